@@ -41,9 +41,27 @@ type GroupVersionKind struct {
 	Kind    string `json:"kind,omitempty" toml:"kind,omitempty"`
 }
 
+const (
+	ScopeCluster    = "cluster"
+	ScopeNamespaced = "namespaced"
+)
+
+// Scopes are the valid scopes of the DeniedScope rules
+var Scopes = []string{ScopeCluster, ScopeNamespaced}
+
+// DeniedScope denies the access to all the resources of a scope, whatever their kind (e.g. no cluster-scoped writes)
+type DeniedScope struct {
+	// Scope is either cluster (e.g. Nodes, ClusterRoles, PersistentVolumes) or namespaced
+	Scope string `json:"scope" toml:"scope"`
+	// WritesOnly only denies the requests changing the resources (create, update, patch, delete), they can still be read
+	WritesOnly bool `json:"writesOnly,omitempty" toml:"writes_only,omitempty"`
+}
+
 type DeniedResourcesProvider interface {
 	// GetDeniedResources returns a list of GroupVersionKinds that are denied.
 	GetDeniedResources() []GroupVersionKind
+	// GetDeniedScopes returns the scopes whose resources are denied, regardless of their kind.
+	GetDeniedScopes() []DeniedScope
 }
 
 type StsConfigProvider interface {
@@ -53,10 +71,27 @@ type StsConfigProvider interface {
 	GetStsScopes() []string
 }
 
+// KubernetesClientConfig tunes how hard the server is allowed to hit the Kubernetes API server
+type KubernetesClientConfig struct {
+	// QPS is the maximum sustained rate of requests per second (Optional, 10 if not provided)
+	QPS float32 `toml:"qps,omitempty"`
+	// Burst is the maximum number of requests above the QPS rate (Optional, 20 if not provided)
+	Burst int `toml:"burst,omitempty"`
+	// UserAgent identifies the requests of the server in the API server audit logs and metrics
+	// (Optional, kubernetes-mcp-server/<version> if not provided)
+	UserAgent string `toml:"user_agent,omitempty"`
+}
+
+type KubernetesClientConfigProvider interface {
+	// GetKubernetesClientConfig returns the rate limits and user agent of the Kubernetes clients
+	GetKubernetesClientConfig() KubernetesClientConfig
+}
+
 type BaseConfig interface {
 	AuthProvider
 	ClusterProvider
 	DeniedResourcesProvider
 	ExtendedConfigProvider
+	KubernetesClientConfigProvider
 	StsConfigProvider
 }
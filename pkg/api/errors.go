@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ErrResourceDenied is returned (wrapped) when a request targets a resource that is denied by the server configuration.
+var ErrResourceDenied = errors.New("resource not allowed")
+
+// ErrorCode is a stable, machine-readable identifier of the cause of a tool call failure.
+type ErrorCode string
+
+const (
+	ErrorCodeResourceDenied  ErrorCode = "RESOURCE_DENIED"
+	ErrorCodeNotFound        ErrorCode = "NOT_FOUND"
+	ErrorCodeRbacForbidden   ErrorCode = "RBAC_FORBIDDEN"
+	ErrorCodeUnauthorized    ErrorCode = "UNAUTHORIZED"
+	ErrorCodeAlreadyExists   ErrorCode = "ALREADY_EXISTS"
+	ErrorCodeConflict        ErrorCode = "CONFLICT"
+	ErrorCodeInvalidArgument ErrorCode = "INVALID_ARGUMENT"
+	ErrorCodeTimeout         ErrorCode = "TIMEOUT"
+	ErrorCodeUnavailable     ErrorCode = "UNAVAILABLE"
+	ErrorCodeRateLimited     ErrorCode = "RATE_LIMITED"
+	// ErrorCodeDisruptionBudget is returned when an eviction would violate a PodDisruptionBudget
+	ErrorCodeDisruptionBudget ErrorCode = "DISRUPTION_BUDGET"
+	// ErrorCodeChangeFreeze is returned when a mutating tool is called during a change freeze window
+	ErrorCodeChangeFreeze ErrorCode = "CHANGE_FREEZE"
+	ErrorCodeUnknown      ErrorCode = "UNKNOWN"
+)
+
+var remediations = map[ErrorCode][]string{
+	ErrorCodeResourceDenied: {
+		"The resource kind, or its scope (cluster-scoped or namespaced), is denied by the server configuration (denied_resources, denied_scopes) and cannot be accessed with this server",
+	},
+	ErrorCodeNotFound: {
+		"Verify the name, namespace, and kind of the resource",
+		"Use a list tool to discover the existing resources",
+	},
+	ErrorCodeRbacForbidden: {
+		"The cluster credentials lack the RBAC permissions required for this operation",
+		"Ask a cluster administrator to grant the required Role/ClusterRole, or use a different namespace",
+	},
+	ErrorCodeUnauthorized: {
+		"The cluster credentials are invalid or expired, refresh the kubeconfig or token",
+	},
+	ErrorCodeAlreadyExists: {
+		"A resource with the same name already exists, use a different name or update the existing resource",
+	},
+	ErrorCodeConflict: {
+		"The resource was modified concurrently, retrieve its latest version and retry",
+	},
+	ErrorCodeInvalidArgument: {
+		"Check the tool arguments and the resource definition",
+	},
+	ErrorCodeTimeout: {
+		"Retry the operation, the cluster may be slow or overloaded",
+		"Narrow the request scope (e.g. namespace or label selector)",
+	},
+	ErrorCodeUnavailable: {
+		"The cluster may be unreachable, retry later",
+	},
+	ErrorCodeRateLimited: {
+		"Too many requests were sent to the cluster, wait before retrying",
+	},
+	ErrorCodeDisruptionBudget: {
+		"The PodDisruptionBudget allows no more disruptions, wait for the other Pods it protects to be ready and retry",
+		"Scale up the workload, or review the PodDisruptionBudget if it can never allow a disruption (e.g. minAvailable equal to the replicas)",
+	},
+	ErrorCodeChangeFreeze: {
+		"Changes are frozen by the server configuration (change_freezes), retry once the freeze ends",
+		"Read-only tools remain available during the freeze",
+	},
+}
+
+// ToolError is the structured payload sent back to the client when a tool call fails.
+type ToolError struct {
+	// Code is the stable error code agents can branch on.
+	Code ErrorCode `json:"code"`
+	// Message is the human-readable error message.
+	Message string `json:"message"`
+	// Remediation are hints on how to resolve the error.
+	Remediation []string `json:"remediation,omitempty"`
+	err         error
+}
+
+func (e *ToolError) Error() string {
+	return e.Message
+}
+
+func (e *ToolError) Unwrap() error {
+	return e.err
+}
+
+// NewToolError wraps the provided error with an explicit error code.
+func NewToolError(code ErrorCode, err error) *ToolError {
+	return &ToolError{Code: code, Message: err.Error(), Remediation: remediations[code], err: err}
+}
+
+// ClassifyError returns the structured ToolError for the provided error.
+// Errors that already carry a ToolError keep their code, otherwise the code is inferred from the error chain.
+func ClassifyError(err error) *ToolError {
+	if err == nil {
+		return nil
+	}
+	var toolError *ToolError
+	if errors.As(err, &toolError) {
+		return &ToolError{Code: toolError.Code, Message: err.Error(), Remediation: toolError.Remediation, err: err}
+	}
+	return NewToolError(errorCode(err), err)
+}
+
+func errorCode(err error) ErrorCode {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrResourceDenied):
+		return ErrorCodeResourceDenied
+	case apierrors.IsNotFound(err):
+		return ErrorCodeNotFound
+	case apierrors.IsForbidden(err):
+		return ErrorCodeRbacForbidden
+	case apierrors.IsUnauthorized(err):
+		return ErrorCodeUnauthorized
+	case apierrors.IsAlreadyExists(err):
+		return ErrorCodeAlreadyExists
+	case apierrors.IsConflict(err):
+		return ErrorCodeConflict
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return ErrorCodeInvalidArgument
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorCodeTimeout
+	case apierrors.IsServiceUnavailable(err), isConnectionFailure(err):
+		return ErrorCodeUnavailable
+	case apierrors.HasStatusCause(err, policyv1.DisruptionBudgetCause):
+		return ErrorCodeDisruptionBudget
+	case apierrors.IsTooManyRequests(err):
+		return ErrorCodeRateLimited
+	}
+	return ErrorCodeUnknown
+}
+
+// isConnectionFailure returns true if the connection to the cluster couldn't be established (connection refused, dial or
+// DNS failure). The other transport errors (e.g. x509, proxy) are wrapped in the same *url.Error by client-go, yet they
+// don't mean the cluster is unreachable.
+func isConnectionFailure(err error) bool {
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	return errors.Is(err, syscall.ECONNREFUSED) || (errors.As(err, &opErr) && opErr.Op == "dial") || errors.As(err, &dnsErr)
+}
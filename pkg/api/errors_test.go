@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type ErrorsSuite struct {
+	suite.Suite
+}
+
+func TestErrorsSuite(t *testing.T) {
+	suite.Run(t, new(ErrorsSuite))
+}
+
+func (s *ErrorsSuite) TestClassifyError() {
+	pods := schema.GroupResource{Resource: "pods"}
+	disruptionBudget := &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status: metav1.StatusFailure, Code: 429, Reason: metav1.StatusReasonTooManyRequests,
+		Message: "Cannot evict pod as it would violate the pod's disruption budget.",
+		Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{{Type: policyv1.DisruptionBudgetCause}}},
+	}}
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected ErrorCode
+	}{
+		{"denied resource", fmt.Errorf("failed to list: %w: /v1, Kind=Secret", ErrResourceDenied), ErrorCodeResourceDenied},
+		{"not found", fmt.Errorf("failed to get pod: %w", apierrors.NewNotFound(pods, "a-pod")), ErrorCodeNotFound},
+		{"forbidden", apierrors.NewForbidden(pods, "a-pod", errors.New("no RBAC")), ErrorCodeRbacForbidden},
+		{"unauthorized", apierrors.NewUnauthorized("expired"), ErrorCodeUnauthorized},
+		{"already exists", apierrors.NewAlreadyExists(pods, "a-pod"), ErrorCodeAlreadyExists},
+		{"conflict", apierrors.NewConflict(pods, "a-pod", errors.New("modified")), ErrorCodeConflict},
+		{"bad request", apierrors.NewBadRequest("invalid"), ErrorCodeInvalidArgument},
+		{"api timeout", apierrors.NewTimeoutError("slow", 1), ErrorCodeTimeout},
+		{"context deadline", fmt.Errorf("failed to exec: %w", context.DeadlineExceeded), ErrorCodeTimeout},
+		{"service unavailable", apierrors.NewServiceUnavailable("down"), ErrorCodeUnavailable},
+		{"connection refused", fmt.Errorf("failed to list pods: %w", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}), ErrorCodeUnavailable},
+		{"dns failure", &url.Error{Op: "Get", URL: "https://cluster.example.com/api", Err: &net.DNSError{Err: "no such host", Name: "cluster.example.com", IsNotFound: true}}, ErrorCodeUnavailable},
+		{"untrusted certificate", &url.Error{Op: "Get", URL: "https://127.0.0.1:6443/api", Err: x509.UnknownAuthorityError{}}, ErrorCodeUnknown},
+		{"proxy failure", &url.Error{Op: "Get", URL: "https://127.0.0.1:6443/api", Err: &net.OpError{Op: "proxyconnect", Net: "tcp", Err: errors.New("proxy denied")}}, ErrorCodeUnknown},
+		{"rate limited", apierrors.NewTooManyRequests("busy", 1), ErrorCodeRateLimited},
+		{"disruption budget", fmt.Errorf("failed to evict pod: %w", disruptionBudget), ErrorCodeDisruptionBudget},
+		{"unknown", errors.New("something else"), ErrorCodeUnknown},
+	} {
+		s.Run(tc.name, func() {
+			toolError := ClassifyError(tc.err)
+			s.Equal(tc.expected, toolError.Code)
+			s.Equal(tc.err.Error(), toolError.Message)
+		})
+	}
+	s.Run("nil error returns nil", func() {
+		s.Nil(ClassifyError(nil))
+	})
+}
+
+func (s *ErrorsSuite) TestToolError() {
+	s.Run("explicit code is preserved when wrapped", func() {
+		toolError := ClassifyError(fmt.Errorf("failed to list: %w", NewToolError(ErrorCodeInvalidArgument, errors.New("invalid output format: xml"))))
+		s.Equal(ErrorCodeInvalidArgument, toolError.Code)
+		s.Equal("failed to list: invalid output format: xml", toolError.Message)
+	})
+	s.Run("includes remediation hints", func() {
+		s.NotEmpty(NewToolError(ErrorCodeRbacForbidden, errors.New("forbidden")).Remediation)
+	})
+	s.Run("unknown errors have no remediation hints", func() {
+		s.Empty(NewToolError(ErrorCodeUnknown, errors.New("unknown")).Remediation)
+	})
+}
@@ -33,6 +33,21 @@ type PodsTopOptions struct {
 	Name          string
 }
 
+// NodesLogOptions contains options for getting node logs through the kubelet log query API.
+// https://kubernetes.io/docs/concepts/cluster-administration/system-logs/#log-query
+type NodesLogOptions struct {
+	// Query is the service (journal unit, e.g. kubelet) or the file (e.g. /kubelet.log) to get the logs from
+	Query     string
+	TailLines int64
+	// SinceTime and UntilTime (RFC 3339) bound the time range of the logs
+	SinceTime string
+	UntilTime string
+	// Pattern is a regular expression filtering the log lines
+	Pattern string
+	// Boot is the boot of the journal to get the service logs from (0 the current boot, -1 the previous boot...)
+	Boot *int64
+}
+
 // NodesTopOptions contains options for getting node metrics.
 type NodesTopOptions struct {
 	metav1.ListOptions
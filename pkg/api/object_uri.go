@@ -0,0 +1,66 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ObjectURITemplate is the (RFC 6570) template of the URIs of the Kubernetes objects served as MCP resources.
+// The group of the core API is "core", the namespace of the cluster-scoped objects is empty, and the target is the
+// cluster (or context) the object belongs to.
+const ObjectURITemplate = "k8s:///{group}/{version}/{kind}/{namespace}/{name}{?target}"
+
+const objectURIScheme = "k8s"
+
+// ObjectReference identifies a Kubernetes object in one of the targets of the server
+type ObjectReference struct {
+	GroupVersionKind
+	Namespace string
+	Name      string
+	Target    string
+}
+
+// URI returns the URI (see ObjectURITemplate) the object can be read from
+func (r ObjectReference) URI() string {
+	group := r.Group
+	if group == "" {
+		group = "core"
+	}
+	uri := url.URL{Scheme: objectURIScheme, Path: "/" + strings.Join([]string{group, r.Version, r.Kind, r.Namespace, r.Name}, "/")}
+	if r.Target != "" {
+		uri.RawQuery = url.Values{"target": {r.Target}}.Encode()
+	}
+	return uri.String()
+}
+
+// APIVersion returns the apiVersion of the object (e.g. v1, apps/v1)
+func (r ObjectReference) APIVersion() string {
+	if r.Group == "" {
+		return r.Version
+	}
+	return r.Group + "/" + r.Version
+}
+
+// ParseObjectURI parses a URI returned by ObjectReference.URI
+func ParseObjectURI(uri string) (*ObjectReference, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object URI %s: %w", uri, err)
+	}
+	segments := strings.Split(strings.TrimPrefix(parsed.Path, "/"), "/")
+	if parsed.Scheme != objectURIScheme || parsed.Host != "" || len(segments) != 5 ||
+		segments[0] == "" || segments[1] == "" || segments[2] == "" || segments[4] == "" {
+		return nil, fmt.Errorf("invalid object URI %s, expected %s", uri, ObjectURITemplate)
+	}
+	ref := &ObjectReference{
+		GroupVersionKind: GroupVersionKind{Group: segments[0], Version: segments[1], Kind: segments[2]},
+		Namespace:        segments[3],
+		Name:             segments[4],
+		Target:           parsed.Query().Get("target"),
+	}
+	if ref.Group == "core" {
+		ref.Group = ""
+	}
+	return ref, nil
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ObjectURISuite struct {
+	suite.Suite
+}
+
+func (s *ObjectURISuite) TestURI() {
+	s.Run("core namespaced object", func() {
+		ref := ObjectReference{GroupVersionKind: GroupVersionKind{Version: "v1", Kind: "Pod"}, Namespace: "default", Name: "nginx"}
+		s.Equal("k8s:///core/v1/Pod/default/nginx", ref.URI())
+	})
+	s.Run("cluster-scoped object in a target", func() {
+		ref := ObjectReference{
+			GroupVersionKind: GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+			Name:             "admin",
+			Target:           "arn:aws:eks:eu-west-1:111122223333:cluster/prod",
+		}
+		s.Equal("k8s:///rbac.authorization.k8s.io/v1/ClusterRole//admin?target=arn%3Aaws%3Aeks%3Aeu-west-1%3A111122223333%3Acluster%2Fprod", ref.URI())
+	})
+}
+
+func (s *ObjectURISuite) TestParseObjectURI() {
+	s.Run("round-trips the references", func() {
+		for _, ref := range []ObjectReference{
+			{GroupVersionKind: GroupVersionKind{Version: "v1", Kind: "Pod"}, Namespace: "default", Name: "nginx"},
+			{GroupVersionKind: GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, Namespace: "web", Name: "frontend", Target: "kind-kind"},
+			{GroupVersionKind: GroupVersionKind{Version: "v1", Kind: "Namespace"}, Name: "default"},
+		} {
+			parsed, err := ParseObjectURI(ref.URI())
+			s.Require().NoError(err)
+			s.Equal(ref, *parsed)
+		}
+	})
+	s.Run("returns the apiVersion", func() {
+		parsed, err := ParseObjectURI("k8s:///apps/v1/Deployment/web/frontend")
+		s.Require().NoError(err)
+		s.Equal("apps/v1", parsed.APIVersion())
+	})
+	s.Run("rejects invalid URIs", func() {
+		for _, uri := range []string{"https://example.com/core/v1/Pod/default/nginx", "k8s:///core/v1/Pod/default", "k8s:///core/v1/Pod/default/", "k8s://host/core/v1/Pod/default/nginx"} {
+			_, err := ParseObjectURI(uri)
+			s.Error(err, uri)
+		}
+	})
+}
+
+func TestObjectURI(t *testing.T) {
+	suite.Run(t, new(ObjectURISuite))
+}
@@ -13,6 +13,52 @@ type ServerTool struct {
 	Handler            ToolHandlerFunc
 	ClusterAware       *bool
 	TargetListProvider *bool
+	LongRunning        *bool
+	// Localized tools render their human-readable summaries in the locale of the call (see locale.FromContext)
+	Localized *bool
+	// TableOutput tools print their list results with the table output (see output.Table)
+	TableOutput *bool
+	// Permissions returns the Kubernetes API permissions required by the tool call with the provided arguments,
+	// checked before calling the handler when the RBAC preflight is enabled (rbac_preflight). Optional, the errors
+	// skip the preflight and are left to the handler to report.
+	Permissions func(params ToolHandlerParams) ([]Permission, error)
+	// APIGroups are the optional API groups the tool works with (e.g. argoproj.io), the tool is only registered if the
+	// cluster serves at least one of them when the API probe is enabled (probe_apis). Optional, the tools without API
+	// groups are always registered.
+	APIGroups []string
+}
+
+// WithAPIGroups sets the optional API groups the provided tools work with, see ServerTool.APIGroups
+func WithAPIGroups(tools []ServerTool, groups ...string) []ServerTool {
+	for i := range tools {
+		tools[i].APIGroups = groups
+	}
+	return tools
+}
+
+// Permission is a Kubernetes API permission, a verb on a resource, required by a tool call.
+type Permission struct {
+	Verb        string
+	Group       string
+	Resource    string
+	Subresource string
+	// Namespace of the namespaced resources, empty for the cluster-scoped ones
+	Namespace string
+}
+
+// String returns the permission as it reads in the errors, e.g. "create deployments.apps in namespace shop"
+func (p Permission) String() string {
+	ret := p.Verb + " " + p.Resource
+	if p.Group != "" {
+		ret += "." + p.Group
+	}
+	if p.Subresource != "" {
+		ret += "/" + p.Subresource
+	}
+	if p.Namespace != "" {
+		ret += " in namespace " + p.Namespace
+	}
+	return ret
 }
 
 // IsClusterAware indicates whether the tool can accept a "cluster" or "context" parameter
@@ -34,6 +80,36 @@ func (s *ServerTool) IsTargetListProvider() bool {
 	return false
 }
 
+// IsLongRunning indicates whether the tool may take long to complete (e.g. waits for the resources to be ready),
+// such tools can be called asynchronously and their result retrieved later with the operations tools.
+// Defaults to false if not explicitly set
+func (s *ServerTool) IsLongRunning() bool {
+	if s.LongRunning != nil {
+		return *s.LongRunning
+	}
+	return false
+}
+
+// IsLocalized indicates whether the tool renders its human-readable summaries in the locale of the call, such tools
+// accept a locale parameter overriding the configured one.
+// Defaults to false if not explicitly set
+func (s *ServerTool) IsLocalized() bool {
+	if s.Localized != nil {
+		return *s.Localized
+	}
+	return false
+}
+
+// IsTableOutput indicates whether the tool prints its list results as a table with the table output, the table output
+// can only be selected in the calls of such tools.
+// Defaults to false if not explicitly set
+func (s *ServerTool) IsTableOutput() bool {
+	if s.TableOutput != nil {
+		return *s.TableOutput
+	}
+	return false
+}
+
 type Toolset interface {
 	// GetName returns the name of the toolset.
 	// Used to identify the toolset in configuration, logs, and command-line arguments.
@@ -55,6 +131,9 @@ type ToolCallRequest interface {
 type ToolCallResult struct {
 	// Raw content returned by the tool.
 	Content string
+	// Header are the comment lines describing the structured content (e.g. "# 3 issue(s) found (YAML format):\n"),
+	// kept apart from the content so that the JSON content remains valid JSON.
+	Header string
 	// Error (non-protocol) to send back to the LLM.
 	Error error
 }
@@ -66,12 +145,48 @@ func NewToolCallResult(content string, err error) *ToolCallResult {
 	}
 }
 
+// NewStructuredToolCallResult returns the result of a tool call whose content was marshalled with
+// ToolHandlerParams.Marshal, described by the header comment lines
+func NewStructuredToolCallResult(header, content string, err error) *ToolCallResult {
+	return &ToolCallResult{
+		Content: content,
+		Header:  header,
+		Error:   err,
+	}
+}
+
 type ToolHandlerParams struct {
 	context.Context
 	ExtendedConfigProvider
 	KubernetesClient
 	ToolCallRequest
 	ListOutput output.Output
+	// PruneFields are the fields to remove from the returned objects (see output.PruneFields)
+	PruneFields []string
+	// ListDetail is the configured level of detail of the listed objects (see output.Details)
+	ListDetail string
+	// Target is the target (cluster or context) the tool call is run against
+	Target string
+	// TargetClient returns the client of another target (e.g. for the tools comparing two clusters in multi-cluster mode)
+	TargetClient func(ctx context.Context, target string) (KubernetesClient, error)
+}
+
+// Marshal marshals the structured result of the tool call with the requested output: compact JSON if requested, YAML
+// otherwise (the table output only applies to the lists of Kubernetes objects)
+func (p ToolHandlerParams) Marshal(v any) (string, error) {
+	if p.ListOutput == output.Json {
+		return output.MarshalJson(v)
+	}
+	return output.MarshalYaml(v)
+}
+
+// FormatName returns the name of the format of the structured result marshalled by Marshal (JSON or YAML), to be stated
+// in its header
+func (p ToolHandlerParams) FormatName() string {
+	if p.ListOutput == output.Json {
+		return "JSON"
+	}
+	return "YAML"
 }
 
 type ToolHandlerFunc func(params ToolHandlerParams) (*ToolCallResult, error)
@@ -42,6 +42,25 @@ func (s *ToolsetsSuite) TestServerTool() {
 	})
 }
 
+func (s *ToolsetsSuite) TestWithAPIGroups() {
+	tools := WithAPIGroups([]ServerTool{{Tool: Tool{Name: "a"}}, {Tool: Tool{Name: "b"}}}, "argoproj.io")
+	s.Require().Len(tools, 2)
+	s.Equal([]string{"argoproj.io"}, tools[0].APIGroups)
+	s.Equal([]string{"argoproj.io"}, tools[1].APIGroups)
+}
+
+func (s *ToolsetsSuite) TestPermission() {
+	s.Run("String of a core namespaced resource", func() {
+		s.Equal("delete pods in namespace shop", Permission{Verb: "delete", Resource: "pods", Namespace: "shop"}.String())
+	})
+	s.Run("String of a subresource of a resource with a group", func() {
+		s.Equal("update deployments.apps/scale in namespace shop", Permission{Verb: "update", Group: "apps", Resource: "deployments", Subresource: "scale", Namespace: "shop"}.String())
+	})
+	s.Run("String of a cluster-scoped resource", func() {
+		s.Equal("get nodes", Permission{Verb: "get", Resource: "nodes"}.String())
+	})
+}
+
 func TestToolsets(t *testing.T) {
 	suite.Run(t, new(ToolsetsSuite))
 }
@@ -0,0 +1,230 @@
+package argocd
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/crdstatus"
+)
+
+const (
+	// DefaultNamespace is the namespace where Argo CD is installed and the Applications are created by default
+	DefaultNamespace = "argocd"
+
+	SyncStatusSynced    = "Synced"
+	SyncStatusOutOfSync = "OutOfSync"
+
+	// initiatedBy is the username reported by Argo CD for the sync operations triggered by this server
+	initiatedBy = "kubernetes-mcp-server"
+)
+
+// ErrOperationInProgress is returned when a sync is requested for an Application with an operation still running
+var ErrOperationInProgress = errors.New("another operation is already in progress")
+
+// ApplicationSummary is the sync and health status of an Argo CD Application.
+type ApplicationSummary struct {
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	Project     string `json:"project"`
+	Source      string `json:"source"`
+	Revision    string `json:"revision,omitempty"`
+	Destination string `json:"destination"`
+	SyncStatus  string `json:"syncStatus"`
+	Health      string `json:"health"`
+	// AutoSync is true when the Application is synced automatically by Argo CD
+	AutoSync bool `json:"autoSync"`
+	// Operation is the phase and message of the last (or current) operation
+	Operation  string   `json:"operation,omitempty"`
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// ApplicationDiff is the summary of the differences between the desired (Git) and live state of an Argo CD Application,
+// as computed by the Argo CD application controller.
+type ApplicationDiff struct {
+	ApplicationSummary
+	ComparedTo string `json:"comparedTo,omitempty"`
+	// OutOfSync are the resources that differ from the desired state (or must be created or pruned)
+	OutOfSync []ResourceDiff `json:"outOfSync"`
+	// Unhealthy are the synced resources that are not healthy
+	Unhealthy []ResourceDiff `json:"unhealthy,omitempty"`
+	// Synced is the number of resources in sync with the desired state
+	Synced int `json:"synced"`
+}
+
+// ResourceDiff is the sync and health status of a resource managed by an Argo CD Application.
+type ResourceDiff struct {
+	Resource string `json:"resource"`
+	Status   string `json:"status"`
+	Health   string `json:"health,omitempty"`
+	Message  string `json:"message,omitempty"`
+	// RequiresPruning is true when the resource is no longer in Git and will be deleted by a sync with prune
+	RequiresPruning bool `json:"requiresPruning,omitempty"`
+}
+
+// ListApplications lists the Argo CD Applications in the provided namespace (all namespaces if empty) with their sync and health status
+func ListApplications(ctx context.Context, client dynamic.Interface, namespace string) ([]ApplicationSummary, error) {
+	applications, err := client.Resource(ApplicationGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]ApplicationSummary, 0, len(applications.Items))
+	for _, application := range applications.Items {
+		summaries = append(summaries, Summarize(&application))
+	}
+	// Out of sync and unhealthy Applications first
+	slices.SortStableFunc(summaries, func(a, b ApplicationSummary) int {
+		return cmp.Or(
+			cmp.Compare(statusRank(a), statusRank(b)),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return summaries, nil
+}
+
+// GetApplicationDiff returns the resources of the Argo CD Application that are out of sync or unhealthy
+func GetApplicationDiff(ctx context.Context, client dynamic.Interface, namespace, name string) (*ApplicationDiff, error) {
+	application, err := client.Resource(ApplicationGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return Diff(application), nil
+}
+
+// SyncApplication requests Argo CD to sync the Application to the provided revision (target revision if empty) by
+// setting its operation field, the same way the Argo CD CLI and UI do.
+func SyncApplication(ctx context.Context, client dynamic.Interface, namespace, name, revision string, prune bool) (*unstructured.Unstructured, error) {
+	application, err := client.Resource(ApplicationGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	phase, _, _ := unstructured.NestedString(application.Object, "status", "operationState", "phase")
+	if _, pending := application.Object["operation"]; pending || phase == "Running" || phase == "Terminating" {
+		return nil, fmt.Errorf("failed to sync Application %s: %w", name, ErrOperationInProgress)
+	}
+	sync := map[string]any{"prune": prune}
+	if revision != "" {
+		sync["revision"] = revision
+	}
+	patch, err := json.Marshal(map[string]any{"operation": map[string]any{
+		"initiatedBy": map[string]any{"username": initiatedBy},
+		"sync":        sync,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	return client.Resource(ApplicationGVR).Namespace(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+}
+
+// Summarize returns the sync and health status of the provided Argo CD Application
+func Summarize(application *unstructured.Unstructured) ApplicationSummary {
+	summary := ApplicationSummary{Namespace: application.GetNamespace(), Name: application.GetName()}
+	summary.Project, _, _ = unstructured.NestedString(application.Object, "spec", "project")
+	summary.Source = source(application.Object, "spec")
+	summary.Revision, _, _ = unstructured.NestedString(application.Object, "status", "sync", "revision")
+	server, _, _ := unstructured.NestedString(application.Object, "spec", "destination", "server")
+	destinationName, _, _ := unstructured.NestedString(application.Object, "spec", "destination", "name")
+	destinationNamespace, _, _ := unstructured.NestedString(application.Object, "spec", "destination", "namespace")
+	summary.Destination = cmp.Or(destinationName, server)
+	if destinationNamespace != "" {
+		summary.Destination += "/" + destinationNamespace
+	}
+	summary.SyncStatus, _, _ = unstructured.NestedString(application.Object, "status", "sync", "status")
+	summary.SyncStatus = cmp.Or(summary.SyncStatus, "Unknown")
+	summary.Health, _, _ = unstructured.NestedString(application.Object, "status", "health", "status")
+	summary.Health = cmp.Or(summary.Health, "Unknown")
+	_, summary.AutoSync, _ = unstructured.NestedMap(application.Object, "spec", "syncPolicy", "automated")
+	if phase, _, _ := unstructured.NestedString(application.Object, "status", "operationState", "phase"); phase != "" {
+		summary.Operation = phase
+		if message, _, _ := unstructured.NestedString(application.Object, "status", "operationState", "message"); message != "" {
+			summary.Operation += ": " + message
+		}
+	}
+	for _, condition := range crdstatus.Conditions(application.Object) {
+		summary.Conditions = append(summary.Conditions, condition.Type+": "+condition.Message)
+	}
+	return summary
+}
+
+// Diff returns the resources of the provided Argo CD Application that are out of sync or unhealthy
+func Diff(application *unstructured.Unstructured) *ApplicationDiff {
+	diff := &ApplicationDiff{ApplicationSummary: Summarize(application), OutOfSync: make([]ResourceDiff, 0)}
+	diff.ComparedTo = source(application.Object, "status", "sync", "comparedTo")
+	resources, _, _ := unstructured.NestedSlice(application.Object, "status", "resources")
+	for _, resource := range resources {
+		resource, _ := resource.(map[string]any)
+		resourceDiff := ResourceDiff{Resource: resourceName(resource)}
+		resourceDiff.Status, _ = resource["status"].(string)
+		resourceDiff.RequiresPruning, _ = resource["requiresPruning"].(bool)
+		if health, ok := resource["health"].(map[string]any); ok {
+			resourceDiff.Health, _ = health["status"].(string)
+			resourceDiff.Message, _ = health["message"].(string)
+		}
+		switch {
+		case resourceDiff.Status == SyncStatusOutOfSync || resourceDiff.RequiresPruning:
+			diff.OutOfSync = append(diff.OutOfSync, resourceDiff)
+		case resourceDiff.Health != "" && resourceDiff.Health != "Healthy":
+			diff.Unhealthy = append(diff.Unhealthy, resourceDiff)
+		default:
+			diff.Synced++
+		}
+	}
+	return diff
+}
+
+// source returns the repository, path or chart, and target revision of the source at the provided path
+func source(object map[string]any, fields ...string) string {
+	repoURL, _, _ := unstructured.NestedString(object, append(fields, "source", "repoURL")...)
+	if repoURL == "" {
+		// Multi-source Applications
+		sources, _, _ := unstructured.NestedSlice(object, append(fields, "sources")...)
+		if len(sources) == 0 {
+			return ""
+		}
+		first, _ := sources[0].(map[string]any)
+		return source(map[string]any{"source": first}) + fmt.Sprintf(" (+%d more)", len(sources)-1)
+	}
+	ret := repoURL
+	if path, _, _ := unstructured.NestedString(object, append(fields, "source", "path")...); path != "" {
+		ret += "/" + path
+	}
+	if chart, _, _ := unstructured.NestedString(object, append(fields, "source", "chart")...); chart != "" {
+		ret += " chart " + chart
+	}
+	if targetRevision, _, _ := unstructured.NestedString(object, append(fields, "source", "targetRevision")...); targetRevision != "" {
+		ret += "@" + targetRevision
+	}
+	return ret
+}
+
+func resourceName(resource map[string]any) string {
+	name := fmt.Sprintf("%v/%v", resource["kind"], resource["name"])
+	if group, _ := resource["group"].(string); group != "" {
+		name = group + "/" + name
+	}
+	if namespace, _ := resource["namespace"].(string); namespace != "" {
+		name = namespace + "/" + name
+	}
+	return name
+}
+
+func statusRank(summary ApplicationSummary) int {
+	switch {
+	case summary.Health == "Degraded" || summary.Health == "Missing":
+		return 0
+	case summary.SyncStatus != SyncStatusSynced:
+		return 1
+	case summary.Health != "Healthy":
+		return 2
+	}
+	return 3
+}
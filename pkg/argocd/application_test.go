@@ -0,0 +1,132 @@
+package argocd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+type ApplicationTestSuite struct {
+	suite.Suite
+}
+
+func application(name, syncStatus, health string, extra map[string]any) *unstructured.Unstructured {
+	app := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata":   map[string]any{"name": name, "namespace": DefaultNamespace},
+		"spec": map[string]any{
+			"project":     "default",
+			"source":      map[string]any{"repoURL": "https://github.com/example/gitops", "path": "apps/" + name, "targetRevision": "main"},
+			"destination": map[string]any{"server": "https://kubernetes.default.svc", "namespace": name},
+		},
+		"status": map[string]any{
+			"sync":   map[string]any{"status": syncStatus, "revision": "abc123"},
+			"health": map[string]any{"status": health},
+		},
+	}}
+	for k, v := range extra {
+		app.Object[k] = v
+	}
+	return app
+}
+
+func (s *ApplicationTestSuite) client(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{ApplicationGVR: "ApplicationList"}, objects...)
+}
+
+func (s *ApplicationTestSuite) TestListApplications() {
+	client := s.client(
+		application("web", SyncStatusSynced, "Healthy", nil),
+		application("api", SyncStatusOutOfSync, "Healthy", nil),
+		application("db", SyncStatusSynced, "Degraded", nil),
+	)
+	summaries, err := ListApplications(s.T().Context(), client, "")
+	s.Require().NoError(err)
+	s.Run("sorts degraded and out of sync applications first", func() {
+		s.Require().Len(summaries, 3)
+		s.Equal("db", summaries[0].Name)
+		s.Equal("api", summaries[1].Name)
+		s.Equal("web", summaries[2].Name)
+	})
+	s.Run("summarizes source and destination", func() {
+		s.Equal("https://github.com/example/gitops/apps/web@main", summaries[2].Source)
+		s.Equal("https://kubernetes.default.svc/web", summaries[2].Destination)
+		s.Equal("abc123", summaries[2].Revision)
+		s.False(summaries[2].AutoSync)
+	})
+}
+
+func (s *ApplicationTestSuite) TestSummarize() {
+	s.Run("multi-source application with automated sync", func() {
+		app := application("web", SyncStatusSynced, "Healthy", nil)
+		spec := app.Object["spec"].(map[string]any)
+		delete(spec, "source")
+		spec["sources"] = []any{
+			map[string]any{"repoURL": "https://charts.example.com", "chart": "web", "targetRevision": "1.2.0"},
+			map[string]any{"repoURL": "https://github.com/example/values"},
+		}
+		spec["syncPolicy"] = map[string]any{"automated": map[string]any{"prune": true}}
+		summary := Summarize(app)
+		s.Equal("https://charts.example.com chart web@1.2.0 (+1 more)", summary.Source)
+		s.True(summary.AutoSync)
+	})
+	s.Run("missing status", func() {
+		summary := Summarize(&unstructured.Unstructured{Object: map[string]any{"metadata": map[string]any{"name": "new"}}})
+		s.Equal("Unknown", summary.SyncStatus)
+		s.Equal("Unknown", summary.Health)
+	})
+}
+
+func (s *ApplicationTestSuite) TestDiff() {
+	app := application("web", SyncStatusOutOfSync, "Progressing", nil)
+	status := app.Object["status"].(map[string]any)
+	status["resources"] = []any{
+		map[string]any{"group": "apps", "kind": "Deployment", "namespace": "web", "name": "web", "status": SyncStatusOutOfSync,
+			"health": map[string]any{"status": "Progressing", "message": "Waiting for rollout to finish"}},
+		map[string]any{"kind": "ConfigMap", "namespace": "web", "name": "legacy", "status": SyncStatusOutOfSync, "requiresPruning": true},
+		map[string]any{"kind": "Service", "namespace": "web", "name": "web", "status": SyncStatusSynced, "health": map[string]any{"status": "Healthy"}},
+		map[string]any{"group": "batch", "kind": "Job", "namespace": "web", "name": "migrate", "status": SyncStatusSynced,
+			"health": map[string]any{"status": "Degraded", "message": "Job has reached the specified backoff limit"}},
+	}
+	status["sync"].(map[string]any)["comparedTo"] = map[string]any{"source": map[string]any{"repoURL": "https://github.com/example/gitops", "path": "apps/web", "targetRevision": "main"}}
+	diff := Diff(app)
+	s.Equal("https://github.com/example/gitops/apps/web@main", diff.ComparedTo)
+	s.Equal([]ResourceDiff{
+		{Resource: "web/apps/Deployment/web", Status: SyncStatusOutOfSync, Health: "Progressing", Message: "Waiting for rollout to finish"},
+		{Resource: "web/ConfigMap/legacy", Status: SyncStatusOutOfSync, RequiresPruning: true},
+	}, diff.OutOfSync)
+	s.Equal([]ResourceDiff{
+		{Resource: "web/batch/Job/migrate", Status: SyncStatusSynced, Health: "Degraded", Message: "Job has reached the specified backoff limit"},
+	}, diff.Unhealthy)
+	s.Equal(1, diff.Synced)
+}
+
+func (s *ApplicationTestSuite) TestSyncApplication() {
+	s.Run("sets the sync operation", func() {
+		client := s.client(application("web", SyncStatusOutOfSync, "Healthy", nil))
+		synced, err := SyncApplication(s.T().Context(), client, DefaultNamespace, "web", "v1.2.0", true)
+		s.Require().NoError(err)
+		operation, _, _ := unstructured.NestedMap(synced.Object, "operation")
+		s.Equal(map[string]any{
+			"initiatedBy": map[string]any{"username": "kubernetes-mcp-server"},
+			"sync":        map[string]any{"revision": "v1.2.0", "prune": true},
+		}, operation)
+	})
+	s.Run("fails when an operation is in progress", func() {
+		app := application("web", SyncStatusOutOfSync, "Healthy", nil)
+		app.Object["status"].(map[string]any)["operationState"] = map[string]any{"phase": "Running"}
+		_, err := SyncApplication(s.T().Context(), s.client(app), DefaultNamespace, "web", "", false)
+		s.True(errors.Is(err, ErrOperationInProgress), "unexpected error %v", err)
+	})
+}
+
+func TestApplication(t *testing.T) {
+	suite.Run(t, new(ApplicationTestSuite))
+}
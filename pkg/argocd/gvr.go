@@ -0,0 +1,15 @@
+package argocd
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Argo CD resources
+var (
+	// ApplicationGVR is the GroupVersionResource for Application resources
+	ApplicationGVR = schema.GroupVersionResource{
+		Group:    "argoproj.io",
+		Version:  "v1alpha1",
+		Resource: "applications",
+	}
+)
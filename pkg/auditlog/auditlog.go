@@ -0,0 +1,352 @@
+package auditlog
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// requestTimeout bounds the duration of the Loki queries
+	requestTimeout = 60 * time.Second
+	// maxLineSize is the maximum size of an audit event of the file (the request and response objects can be large)
+	maxLineSize = 16 * 1024 * 1024
+	// maxLokiLimit bounds the number of lines requested from Loki
+	maxLokiLimit = 5000
+)
+
+// WriteVerbs are the verbs of the requests changing the cluster, the default verbs of the queries
+var WriteVerbs = []string{"create", "update", "patch", "delete", "deletecollection"}
+
+// Filter selects the audit events returned by Query, the empty fields match all the events.
+type Filter struct {
+	Verbs []string
+	// Resource is the plural name of the resource (e.g. deployments), optionally with its group (e.g. deployments.apps)
+	Resource  string
+	Namespace string
+	Name      string
+	// User matches the users whose name contains it (case-insensitive)
+	User  string
+	From  time.Time
+	To    time.Time
+	Limit int
+}
+
+// Event is a request to the Kubernetes API server recorded in the audit log.
+type Event struct {
+	Time             string   `json:"time"`
+	User             string   `json:"user"`
+	ImpersonatedUser string   `json:"impersonatedUser,omitempty"`
+	Verb             string   `json:"verb"`
+	Object           string   `json:"object,omitempty"`
+	Code             int32    `json:"code,omitempty"`
+	SourceIPs        []string `json:"sourceIPs,omitempty"`
+	UserAgent        string   `json:"userAgent,omitempty"`
+	AuditID          string   `json:"auditID"`
+	time             time.Time
+	stage            string
+}
+
+// Result is the result of an audit log query.
+type Result struct {
+	// Events are the most recent events matching the filter, oldest first
+	Events []Event
+	// Truncated is true if more events matched the filter than its limit
+	Truncated bool
+}
+
+// rawEvent holds the fields of an audit.k8s.io/v1 Event
+type rawEvent struct {
+	AuditID string `json:"auditID"`
+	Stage   string `json:"stage"`
+	Verb    string `json:"verb"`
+	User    struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	ImpersonatedUser *struct {
+		Username string `json:"username"`
+	} `json:"impersonatedUser"`
+	SourceIPs []string `json:"sourceIPs"`
+	UserAgent string   `json:"userAgent"`
+	ObjectRef *struct {
+		Resource    string `json:"resource"`
+		Namespace   string `json:"namespace"`
+		Name        string `json:"name"`
+		APIGroup    string `json:"apiGroup"`
+		Subresource string `json:"subresource"`
+	} `json:"objectRef"`
+	ResponseStatus *struct {
+		Code int32 `json:"code"`
+	} `json:"responseStatus"`
+	RequestReceivedTimestamp time.Time `json:"requestReceivedTimestamp"`
+	StageTimestamp           time.Time `json:"stageTimestamp"`
+}
+
+type AuditLog struct {
+	config *Config
+}
+
+func NewAuditLog(config *Config) *AuditLog {
+	return &AuditLog{config: config}
+}
+
+// Query returns the most recent audit events matching the filter from the audit log file or Loki, one per request
+// (the event of its last recorded stage)
+func (a *AuditLog) Query(ctx context.Context, filter Filter) (*Result, error) {
+	if a.config == nil {
+		return nil, errors.New("audit log is not configured")
+	}
+	var events []Event
+	var err error
+	if a.config.File != "" {
+		events, err = a.queryFile(filter)
+	} else {
+		events, err = a.queryLoki(ctx, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	events = lastStages(events)
+	slices.SortStableFunc(events, func(a, b Event) int { return a.time.Compare(b.time) })
+	result := &Result{Events: events}
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		result.Events, result.Truncated = events[len(events)-filter.Limit:], true
+	}
+	return result, nil
+}
+
+func (a *AuditLog) queryFile(filter Filter) ([]Event, error) {
+	file, err := os.Open(a.config.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if event, ok := parseEvent(scanner.Bytes(), filter); ok {
+			events = append(events, event)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return events, nil
+}
+
+func (a *AuditLog) queryLoki(ctx context.Context, filter Filter) ([]Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	params := url.Values{"query": {lokiQuery(a.config.lokiSelector(), filter)}, "direction": {"backward"}}
+	// Several stages of each request might be recorded
+	params.Set("limit", strconv.Itoa(min(max(filter.Limit, 1)*3, maxLokiLimit)))
+	if !filter.From.IsZero() {
+		params.Set("start", strconv.FormatInt(filter.From.UnixNano(), 10))
+	}
+	if !filter.To.IsZero() {
+		params.Set("end", strconv.FormatInt(filter.To.UnixNano(), 10))
+	}
+	apiURL, err := url.JoinPath(a.config.LokiUrl, "loki", "api", "v1", "query_range")
+	if err != nil {
+		return nil, fmt.Errorf("invalid loki url: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if a.config.BearerTokenFile != "" {
+		token, err := os.ReadFile(a.config.BearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read loki bearer token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	if a.config.LokiTenant != "" {
+		req.Header.Set("X-Scope-OrgID", a.config.LokiTenant)
+	}
+	client, err := a.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read loki response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("loki API error: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var response struct {
+		Status string `json:"status"`
+		Data   struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Values [][]string `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse loki response: %w", err)
+	}
+	if response.Status != "success" || response.Data.ResultType != "streams" {
+		return nil, fmt.Errorf("unexpected loki response status %q (result type %q)", response.Status, response.Data.ResultType)
+	}
+	var events []Event
+	for _, stream := range response.Data.Result {
+		for _, value := range stream.Values {
+			if len(value) < 2 {
+				continue
+			}
+			if event, ok := parseEvent([]byte(value[1]), filter); ok {
+				events = append(events, event)
+			}
+		}
+	}
+	return events, nil
+}
+
+func (a *AuditLog) httpClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: a.config.Insecure}
+	if a.config.CertificateAuthority != "" {
+		caPEM, err := os.ReadFile(a.config.CertificateAuthority)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read loki certificate authority: %w", err)
+		}
+		certPool, err := x509.SystemCertPool()
+		if err != nil || certPool == nil {
+			certPool = x509.NewCertPool()
+		}
+		if !certPool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("failed to parse loki certificate authority")
+		}
+		tlsConfig.RootCAs = certPool
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}}, nil
+}
+
+// lokiQuery returns the LogQL query of the audit events matching the filter, the events are filtered again once parsed
+func lokiQuery(selector string, filter Filter) string {
+	query := selector
+	if filter.User != "" {
+		query += " |~ " + strconv.Quote("(?i)"+regexp.QuoteMeta(filter.User))
+	}
+	query += " | json"
+	if len(filter.Verbs) > 0 {
+		verbs := make([]string, 0, len(filter.Verbs))
+		for _, verb := range filter.Verbs {
+			verbs = append(verbs, regexp.QuoteMeta(verb))
+		}
+		query += " | verb=~" + strconv.Quote(strings.Join(verbs, "|"))
+	}
+	if resource, _, _ := strings.Cut(filter.Resource, "."); resource != "" {
+		query += " | objectRef_resource=" + strconv.Quote(resource)
+	}
+	if filter.Namespace != "" {
+		query += " | objectRef_namespace=" + strconv.Quote(filter.Namespace)
+	}
+	if filter.Name != "" {
+		query += " | objectRef_name=" + strconv.Quote(filter.Name)
+	}
+	return query
+}
+
+// parseEvent returns the audit event of the line if it matches the filter
+func parseEvent(line []byte, filter Filter) (Event, bool) {
+	var raw rawEvent
+	if err := json.Unmarshal(line, &raw); err != nil || raw.AuditID == "" {
+		return Event{}, false
+	}
+	at := raw.RequestReceivedTimestamp
+	if at.IsZero() {
+		at = raw.StageTimestamp
+	}
+	if (!filter.From.IsZero() && at.Before(filter.From)) || (!filter.To.IsZero() && at.After(filter.To)) {
+		return Event{}, false
+	}
+	if len(filter.Verbs) > 0 && !slices.Contains(filter.Verbs, raw.Verb) {
+		return Event{}, false
+	}
+	if filter.User != "" && !strings.Contains(strings.ToLower(raw.User.Username), strings.ToLower(filter.User)) {
+		return Event{}, false
+	}
+	objectRef := raw.ObjectRef
+	if objectRef == nil && (filter.Resource != "" || filter.Namespace != "" || filter.Name != "") {
+		return Event{}, false
+	}
+	if objectRef != nil {
+		resource, group, withGroup := strings.Cut(filter.Resource, ".")
+		if (resource != "" && objectRef.Resource != resource) || (withGroup && objectRef.APIGroup != group) ||
+			(filter.Namespace != "" && objectRef.Namespace != filter.Namespace) || (filter.Name != "" && objectRef.Name != filter.Name) {
+			return Event{}, false
+		}
+	}
+	event := Event{
+		Time:      at.UTC().Format(time.RFC3339),
+		User:      raw.User.Username,
+		Verb:      raw.Verb,
+		SourceIPs: raw.SourceIPs,
+		UserAgent: raw.UserAgent,
+		AuditID:   raw.AuditID,
+		time:      at,
+		stage:     raw.Stage,
+	}
+	if raw.ImpersonatedUser != nil {
+		event.ImpersonatedUser = raw.ImpersonatedUser.Username
+	}
+	if raw.ResponseStatus != nil {
+		event.Code = raw.ResponseStatus.Code
+	}
+	if objectRef != nil {
+		event.Object = objectRef.Resource
+		if objectRef.APIGroup != "" {
+			event.Object += "." + objectRef.APIGroup
+		}
+		if objectRef.Subresource != "" {
+			event.Object += "/" + objectRef.Subresource
+		}
+		if name := strings.Trim(objectRef.Namespace+"/"+objectRef.Name, "/"); name != "" {
+			event.Object += " " + name
+		}
+	}
+	return event, true
+}
+
+// stageOrder orders the stages of the requests, the last recorded stage of a request has its response status
+var stageOrder = map[string]int{"RequestReceived": 0, "ResponseStarted": 1, "ResponseComplete": 2, "Panic": 3}
+
+// lastStages keeps the event of the last recorded stage of each request
+func lastStages(events []Event) []Event {
+	last := make(map[string]int, len(events))
+	ret := make([]Event, 0, len(events))
+	for _, event := range events {
+		i, ok := last[event.AuditID]
+		if !ok {
+			last[event.AuditID] = len(ret)
+			ret = append(ret, event)
+			continue
+		}
+		if cmp.Compare(stageOrder[event.stage], stageOrder[ret[i].stage]) > 0 {
+			ret[i] = event
+		}
+	}
+	return ret
+}
@@ -0,0 +1,153 @@
+package auditlog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const auditEvents = `{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","auditID":"a1","stage":"ResponseComplete","verb":"get","user":{"username":"alice@example.com"},"objectRef":{"resource":"deployments","namespace":"shop","name":"web","apiGroup":"apps","apiVersion":"v1"},"responseStatus":{"code":200},"requestReceivedTimestamp":"2026-10-15T09:00:00.000000Z","stageTimestamp":"2026-10-15T09:00:00.010000Z"}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","auditID":"a2","stage":"RequestReceived","verb":"delete","user":{"username":"alice@example.com"},"sourceIPs":["10.0.0.7"],"userAgent":"kubectl/v1.34.0","objectRef":{"resource":"deployments","namespace":"shop","name":"web","apiGroup":"apps","apiVersion":"v1"},"requestReceivedTimestamp":"2026-10-15T10:00:00.000000Z","stageTimestamp":"2026-10-15T10:00:00.000000Z"}
+not an audit event
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","auditID":"a2","stage":"ResponseComplete","verb":"delete","user":{"username":"alice@example.com"},"sourceIPs":["10.0.0.7"],"userAgent":"kubectl/v1.34.0","objectRef":{"resource":"deployments","namespace":"shop","name":"web","apiGroup":"apps","apiVersion":"v1"},"responseStatus":{"code":200},"requestReceivedTimestamp":"2026-10-15T10:00:00.000000Z","stageTimestamp":"2026-10-15T10:00:00.020000Z"}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","auditID":"a3","stage":"ResponseComplete","verb":"patch","user":{"username":"system:serviceaccount:ci:deployer"},"impersonatedUser":{"username":"bob"},"objectRef":{"resource":"deployments","namespace":"shop","name":"web","apiGroup":"apps","apiVersion":"v1","subresource":"scale"},"responseStatus":{"code":200},"requestReceivedTimestamp":"2026-10-15T11:00:00.000000Z","stageTimestamp":"2026-10-15T11:00:00.020000Z"}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","auditID":"a4","stage":"ResponseComplete","verb":"delete","user":{"username":"carol"},"objectRef":{"resource":"pods","namespace":"shop","name":"web-1","apiVersion":"v1"},"responseStatus":{"code":404},"requestReceivedTimestamp":"2026-10-15T12:00:00.000000Z","stageTimestamp":"2026-10-15T12:00:00.020000Z"}
+`
+
+type AuditLogTestSuite struct {
+	suite.Suite
+	file string
+}
+
+func (s *AuditLogTestSuite) SetupTest() {
+	s.file = filepath.Join(s.T().TempDir(), "audit.log")
+	s.Require().NoError(os.WriteFile(s.file, []byte(auditEvents), 0600))
+}
+
+func (s *AuditLogTestSuite) query(filter Filter) *Result {
+	result, err := NewAuditLog(&Config{File: s.file}).Query(context.Background(), filter)
+	s.Require().NoError(err)
+	return result
+}
+
+func (s *AuditLogTestSuite) TestQueryFile() {
+	s.Run("returns the last stage of each request", func() {
+		result := s.query(Filter{Verbs: []string{"delete"}, Resource: "deployments"})
+		s.Require().Len(result.Events, 1)
+		event := result.Events[0]
+		s.Equal("2026-10-15T10:00:00Z", event.Time)
+		s.Equal("alice@example.com", event.User)
+		s.Equal("delete", event.Verb)
+		s.Equal("deployments.apps shop/web", event.Object)
+		s.Equal(int32(200), event.Code)
+		s.Equal([]string{"10.0.0.7"}, event.SourceIPs)
+		s.Equal("kubectl/v1.34.0", event.UserAgent)
+		s.Equal("a2", event.AuditID)
+	})
+	s.Run("filters by verbs, oldest first", func() {
+		result := s.query(Filter{Verbs: WriteVerbs})
+		s.Require().Len(result.Events, 3)
+		s.Equal([]string{"a2", "a3", "a4"}, []string{result.Events[0].AuditID, result.Events[1].AuditID, result.Events[2].AuditID})
+		s.Equal("deployments.apps/scale shop/web", result.Events[1].Object)
+		s.Equal("bob", result.Events[1].ImpersonatedUser)
+	})
+	s.Run("filters by resource with its group", func() {
+		s.Len(s.query(Filter{Resource: "deployments.apps"}).Events, 3)
+		s.Empty(s.query(Filter{Resource: "deployments.extensions"}).Events)
+	})
+	s.Run("filters by namespace and name", func() {
+		s.Len(s.query(Filter{Namespace: "shop", Name: "web-1"}).Events, 1)
+	})
+	s.Run("filters by part of the user name, case-insensitive", func() {
+		result := s.query(Filter{User: "ALICE"})
+		s.Len(result.Events, 2)
+		s.Len(s.query(Filter{User: "serviceaccount:ci"}).Events, 1)
+	})
+	s.Run("filters by time range", func() {
+		result := s.query(Filter{From: time.Date(2026, 10, 15, 10, 30, 0, 0, time.UTC), To: time.Date(2026, 10, 15, 11, 30, 0, 0, time.UTC)})
+		s.Require().Len(result.Events, 1)
+		s.Equal("a3", result.Events[0].AuditID)
+	})
+	s.Run("keeps the most recent events up to the limit", func() {
+		result := s.query(Filter{Limit: 2})
+		s.True(result.Truncated)
+		s.Require().Len(result.Events, 2)
+		s.Equal("a3", result.Events[0].AuditID)
+		s.Equal("a4", result.Events[1].AuditID)
+	})
+}
+
+func (s *AuditLogTestSuite) TestQueryLoki() {
+	var request *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		request = req
+		lines := strings.Split(strings.TrimSpace(auditEvents), "\n")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"streams","result":[{"stream":{"job":"audit"},"values":[` +
+			`["1760522400020000000",` + quote(lines[3]) + `],["1760522400000000000",` + quote(lines[1]) + `]]}]}}`))
+	}))
+	defer server.Close()
+	tokenFile := filepath.Join(s.T().TempDir(), "token")
+	s.Require().NoError(os.WriteFile(tokenFile, []byte("loki-token\n"), 0600))
+	auditLog := NewAuditLog(&Config{LokiUrl: server.URL + "/base", LokiSelector: `{job="audit"}`, LokiTenant: "platform", BearerTokenFile: tokenFile})
+	from, to := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC), time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	result, err := auditLog.Query(context.Background(), Filter{Verbs: []string{"delete"}, Resource: "deployments.apps", Namespace: "shop", Name: "web",
+		User: "alice", From: from, To: to, Limit: 10})
+	s.Require().NoError(err)
+	s.Run("queries the range of the filter", func() {
+		s.Equal("/base/loki/api/v1/query_range", request.URL.Path)
+		s.Equal(`{job="audit"} |~ "(?i)alice" | json | verb=~"delete" | objectRef_resource="deployments" | objectRef_namespace="shop" | objectRef_name="web"`,
+			request.URL.Query().Get("query"))
+		s.Equal(strconv.FormatInt(from.UnixNano(), 10), request.URL.Query().Get("start"))
+		s.Equal(strconv.FormatInt(to.UnixNano(), 10), request.URL.Query().Get("end"))
+		s.Equal("30", request.URL.Query().Get("limit"))
+		s.Equal("backward", request.URL.Query().Get("direction"))
+	})
+	s.Run("sends the credentials and the tenant", func() {
+		s.Equal("Bearer loki-token", request.Header.Get("Authorization"))
+		s.Equal("platform", request.Header.Get("X-Scope-OrgID"))
+	})
+	s.Run("returns the last stage of each request", func() {
+		s.Require().Len(result.Events, 1)
+		s.Equal("a2", result.Events[0].AuditID)
+		s.Equal(int32(200), result.Events[0].Code)
+	})
+}
+
+func (s *AuditLogTestSuite) TestConfigValidate() {
+	s.Run("requires either file or loki_url", func() {
+		s.EqualError((&Config{}).Validate(), "audit_log requires either file or loki_url")
+		s.EqualError((&Config{File: s.file, LokiUrl: "https://loki"}).Validate(), "audit_log requires either file or loki_url")
+	})
+	s.Run("rejects a missing file", func() {
+		s.ErrorContains((&Config{File: s.file + ".missing"}).Validate(), "audit_log file must be a valid file path")
+	})
+	s.Run("rejects an invalid loki_url", func() {
+		s.EqualError((&Config{LokiUrl: "loki"}).Validate(), "audit_log loki_url must be a valid URL")
+	})
+	s.Run("rejects an invalid loki_selector", func() {
+		s.ErrorContains((&Config{LokiUrl: "https://loki", LokiSelector: "job=audit"}).Validate(), "audit_log loki_selector must be a LogQL stream selector")
+	})
+	s.Run("resolves the relative paths", func() {
+		config := &Config{File: "audit.log", CertificateAuthority: "/etc/ca.pem"}
+		config.ResolvePaths("/etc/kubernetes-mcp-server")
+		s.Equal("/etc/kubernetes-mcp-server/audit.log", config.File)
+		s.Equal("/etc/ca.pem", config.CertificateAuthority)
+	})
+}
+
+func quote(line string) string {
+	return `"` + strings.ReplaceAll(line, `"`, `\"`) + `"`
+}
+
+func TestAuditLog(t *testing.T) {
+	suite.Run(t, new(AuditLogTestSuite))
+}
@@ -0,0 +1,75 @@
+package auditlog
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultLokiSelector is the default LogQL stream selector of the audit events stored in Loki
+const defaultLokiSelector = `{job="kube-apiserver-audit"}`
+
+// Config holds the settings of the backend storing the Kubernetes API server audit events (audit.k8s.io/v1 Event,
+// JSON Lines as written by the log backend or forwarded by the webhook backend): a file or a Loki endpoint.
+type Config struct {
+	// File is the path of the audit log file of the API server (e.g. /var/log/kube-apiserver/audit.log)
+	File string `toml:"file,omitempty"`
+	// LokiUrl is the base URL of the Loki storing the audit events (e.g. https://loki.example.com)
+	LokiUrl string `toml:"loki_url,omitempty"`
+	// LokiSelector is the LogQL stream selector of the audit events (Optional, {job="kube-apiserver-audit"} if not provided)
+	LokiSelector string `toml:"loki_selector,omitempty"`
+	// LokiTenant is the tenant of the audit events sent in the X-Scope-OrgID header (Optional)
+	LokiTenant           string `toml:"loki_tenant,omitempty"`
+	Insecure             bool   `toml:"insecure,omitempty"`
+	CertificateAuthority string `toml:"certificate_authority,omitempty"`
+	// BearerTokenFile is the file with the token sent to Loki (Optional)
+	BearerTokenFile string `toml:"bearer_token_file,omitempty"`
+}
+
+func (c *Config) Validate() error {
+	if c == nil {
+		return errors.New("audit_log config is nil")
+	}
+	if (c.File == "") == (c.LokiUrl == "") {
+		return errors.New("audit_log requires either file or loki_url")
+	}
+	if c.LokiUrl != "" {
+		if u, err := url.Parse(c.LokiUrl); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("audit_log loki_url must be a valid URL")
+		}
+	}
+	if c.LokiSelector != "" && (!strings.HasPrefix(c.LokiSelector, "{") || !strings.HasSuffix(c.LokiSelector, "}")) {
+		return fmt.Errorf("audit_log loki_selector must be a LogQL stream selector (e.g. %s)", defaultLokiSelector)
+	}
+	for name, file := range map[string]string{"file": c.File, "certificate_authority": c.CertificateAuthority, "bearer_token_file": c.BearerTokenFile} {
+		if strings.TrimSpace(file) == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("audit_log %s must be a valid file path: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ResolvePaths resolves the relative file, certificate_authority, and bearer_token_file paths against the configuration directory
+func (c *Config) ResolvePaths(configDir string) {
+	if configDir == "" {
+		return
+	}
+	for _, file := range []*string{&c.File, &c.CertificateAuthority, &c.BearerTokenFile} {
+		if *file != "" && !filepath.IsAbs(*file) {
+			*file = filepath.Join(configDir, *file)
+		}
+	}
+}
+
+func (c *Config) lokiSelector() string {
+	if c.LokiSelector == "" {
+		return defaultLokiSelector
+	}
+	return c.LokiSelector
+}
@@ -0,0 +1,167 @@
+package clusterapi
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/crdstatus"
+)
+
+const (
+	// ClusterNameLabel is set by Cluster API on the resources of a Cluster
+	ClusterNameLabel = "cluster.x-k8s.io/cluster-name"
+	// TopologyOwnedLabel is set on the resources managed by the topology (ClusterClass) of their Cluster
+	TopologyOwnedLabel = "topology.cluster.x-k8s.io/owned"
+	// AutoscalerMinSizeAnnotation and AutoscalerMaxSizeAnnotation are the node group size limits of the cluster autoscaler
+	AutoscalerMinSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size"
+	AutoscalerMaxSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
+)
+
+// Resource is the lifecycle summary of a Cluster API Cluster, MachineDeployment, or Machine.
+type Resource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Cluster   string `json:"cluster,omitempty"`
+	// Phase is the lifecycle phase (e.g. Provisioning, Provisioned, ScalingUp, Running, Failed, Deleting)
+	Phase string `json:"phase,omitempty"`
+	// Ready is the status of the Ready condition (True, False, or Unknown)
+	Ready string `json:"ready"`
+	// Version is the Kubernetes version of the Cluster topology, or of the Machines
+	Version string `json:"version,omitempty"`
+	// ControlPlaneReady and InfrastructureReady are the provisioning status of a Cluster
+	ControlPlaneReady   *bool `json:"controlPlaneReady,omitempty"`
+	InfrastructureReady *bool `json:"infrastructureReady,omitempty"`
+	// Replicas is the desired number of Machines of a MachineDeployment, the other counts are observed
+	Replicas          *int64 `json:"replicas,omitempty"`
+	ReadyReplicas     *int64 `json:"readyReplicas,omitempty"`
+	UpdatedReplicas   *int64 `json:"updatedReplicas,omitempty"`
+	AvailableReplicas *int64 `json:"availableReplicas,omitempty"`
+	// Node is the Node of a Machine in the workload cluster
+	Node       string `json:"node,omitempty"`
+	ProviderID string `json:"providerID,omitempty"`
+	// Failure is the terminal failure reason and message (only reported by the v1beta1 resources)
+	Failure string `json:"failure,omitempty"`
+	// Conditions are the conditions that are not True, with their reason and message
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// ListResources lists the Cluster API resources of the provided GroupVersionResource in the namespace (all namespaces if empty),
+// only the ones of the provided Cluster if not empty
+func ListResources(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace, cluster string) ([]Resource, error) {
+	list, err := client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]Resource, 0, len(list.Items))
+	for _, item := range list.Items {
+		resource := Summarize(&item)
+		if cluster != "" && cmp.Or(resource.Cluster, resource.Name) != cluster {
+			continue
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// SortResources sorts the failed and not ready resources first, then by kind (Clusters first), namespace, and name
+func SortResources(resources []Resource) {
+	crdstatus.Sort(resources, func(resource Resource) crdstatus.Key {
+		return crdstatus.Key{Rank: readyRank(resource), KindOrder: slices.Index(Kinds, resource.Kind), Kind: resource.Kind, Namespace: resource.Namespace, Name: resource.Name}
+	})
+}
+
+// Summarize returns the lifecycle summary of the provided Cluster API resource
+func Summarize(obj *unstructured.Unstructured) Resource {
+	resource := Resource{
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Cluster:    cmp.Or(crdstatus.NestedString(obj.Object, "spec", "clusterName"), obj.GetLabels()[ClusterNameLabel]),
+		Phase:      crdstatus.NestedString(obj.Object, "status", "phase"),
+		Ready:      string(metav1.ConditionUnknown),
+		ProviderID: crdstatus.NestedString(obj.Object, "spec", "providerID"),
+		Node:       crdstatus.NestedString(obj.Object, "status", "nodeRef", "name"),
+	}
+	if failureReason := crdstatus.NestedString(obj.Object, "status", "failureReason"); failureReason != "" {
+		resource.Failure = failureReason + ": " + crdstatus.NestedString(obj.Object, "status", "failureMessage")
+	}
+	for _, condition := range crdstatus.Conditions(obj.Object) {
+		if condition.Type == "Ready" {
+			resource.Ready = condition.Status
+		}
+		if condition.Status == string(metav1.ConditionTrue) {
+			continue
+		}
+		summary := condition.Type
+		if condition.Severity != "" {
+			summary += " (" + condition.Severity + ")"
+		}
+		for _, value := range []string{condition.Reason, condition.Message} {
+			if value != "" {
+				summary += ": " + value
+			}
+		}
+		resource.Conditions = append(resource.Conditions, summary)
+	}
+	switch obj.GetKind() {
+	case ClusterGK.Kind:
+		resource.Cluster = ""
+		resource.Version = crdstatus.NestedString(obj.Object, "spec", "topology", "version")
+		// v1beta2 moved the provisioning status to status.initialization
+		resource.ControlPlaneReady = crdstatus.NestedBool(obj.Object, [][]string{{"status", "controlPlaneReady"}, {"status", "initialization", "controlPlaneInitialized"}})
+		resource.InfrastructureReady = crdstatus.NestedBool(obj.Object, [][]string{{"status", "infrastructureReady"}, {"status", "initialization", "infrastructureProvisioned"}})
+	case MachineDeploymentGK.Kind:
+		resource.Version = crdstatus.NestedString(obj.Object, "spec", "template", "spec", "version")
+		resource.Replicas = crdstatus.NestedInt64(obj.Object, [][]string{{"spec", "replicas"}})
+		resource.ReadyReplicas = crdstatus.NestedInt64(obj.Object, [][]string{{"status", "readyReplicas"}})
+		resource.UpdatedReplicas = crdstatus.NestedInt64(obj.Object, [][]string{{"status", "updatedReplicas"}, {"status", "upToDateReplicas"}})
+		resource.AvailableReplicas = crdstatus.NestedInt64(obj.Object, [][]string{{"status", "availableReplicas"}})
+	case MachineGK.Kind:
+		resource.Version = crdstatus.NestedString(obj.Object, "spec", "version")
+	}
+	return resource
+}
+
+// Scale sets the replicas of the MachineDeployment, refusing the MachineDeployments managed by the topology of their
+// Cluster (which would revert the change) and the replicas out of the cluster autoscaler limits
+func Scale(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string, replicas int64) (*unstructured.Unstructured, error) {
+	machineDeployment, err := client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if _, found := machineDeployment.GetLabels()[TopologyOwnedLabel]; found {
+		return nil, fmt.Errorf("MachineDeployment %s/%s is managed by the topology of Cluster %s, scale its replicas in the Cluster spec.topology.workers.machineDeployments instead",
+			namespace, name, machineDeployment.GetLabels()[ClusterNameLabel])
+	}
+	annotations := machineDeployment.GetAnnotations()
+	if limit, err := strconv.ParseInt(annotations[AutoscalerMinSizeAnnotation], 10, 64); err == nil && replicas < limit {
+		return nil, fmt.Errorf("%d replicas is below the cluster autoscaler minimum size %d of MachineDeployment %s/%s", replicas, limit, namespace, name)
+	}
+	if limit, err := strconv.ParseInt(annotations[AutoscalerMaxSizeAnnotation], 10, 64); err == nil && replicas > limit {
+		return nil, fmt.Errorf("%d replicas is above the cluster autoscaler maximum size %d of MachineDeployment %s/%s", replicas, limit, namespace, name)
+	}
+	data, err := json.Marshal(map[string]any{"spec": map[string]any{"replicas": replicas}})
+	if err != nil {
+		return nil, err
+	}
+	return client.Resource(gvr).Namespace(namespace).Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{})
+}
+
+// readyRank ranks the failed resources before the not ready ones
+func readyRank(resource Resource) int {
+	if resource.Failure != "" || resource.Phase == "Failed" {
+		return 0
+	}
+	return 1 + crdstatus.ReadyRank(resource.Ready, false)
+}
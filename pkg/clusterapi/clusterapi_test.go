@@ -0,0 +1,161 @@
+package clusterapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
+)
+
+var (
+	clusterGVR           = schema.GroupVersionResource{Group: "cluster.x-k8s.io", Version: "v1beta1", Resource: "clusters"}
+	machineDeploymentGVR = schema.GroupVersionResource{Group: "cluster.x-k8s.io", Version: "v1beta1", Resource: "machinedeployments"}
+	machineGVR           = schema.GroupVersionResource{Group: "cluster.x-k8s.io", Version: "v1beta1", Resource: "machines"}
+)
+
+type ClusterAPITestSuite struct {
+	suite.Suite
+}
+
+func cluster(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cluster.x-k8s.io/v1beta1",
+		"kind":       "Cluster",
+		"metadata":   map[string]any{"name": name, "namespace": "fleet"},
+		"spec":       map[string]any{"topology": map[string]any{"version": "v1.31.2"}},
+		"status": map[string]any{
+			"phase":               "Provisioned",
+			"controlPlaneReady":   true,
+			"infrastructureReady": true,
+			"conditions":          []any{map[string]any{"type": "Ready", "status": "True"}},
+		},
+	}}
+}
+
+func machineDeployment(name string, labels, annotations map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cluster.x-k8s.io/v1beta1",
+		"kind":       "MachineDeployment",
+		"metadata":   map[string]any{"name": name, "namespace": "fleet", "labels": labels, "annotations": annotations},
+		"spec": map[string]any{
+			"clusterName": "prod-eu",
+			"replicas":    int64(3),
+			"template":    map[string]any{"spec": map[string]any{"version": "v1.31.2"}},
+		},
+		"status": map[string]any{
+			"phase":             "ScalingUp",
+			"readyReplicas":     int64(2),
+			"updatedReplicas":   int64(3),
+			"availableReplicas": int64(2),
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "False", "severity": "Warning", "reason": "WaitingForAvailableMachines", "message": "Minimum availability requires 3 replicas, current 2 available"},
+				map[string]any{"type": "MachineSetReady", "status": "True"},
+			},
+		},
+	}}
+}
+
+func machine(name, phase string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cluster.x-k8s.io/v1beta1",
+		"kind":       "Machine",
+		"metadata":   map[string]any{"name": name, "namespace": "fleet", "labels": map[string]any{ClusterNameLabel: "prod-eu"}},
+		"spec":       map[string]any{"clusterName": "prod-eu", "version": "v1.31.2", "providerID": "aws:///eu-west-1a/i-0abc"},
+		"status": map[string]any{
+			"phase":          phase,
+			"nodeRef":        map[string]any{"name": "ip-10-0-1-12"},
+			"failureReason":  "CreateError",
+			"failureMessage": "failed to create instance: InsufficientInstanceCapacity",
+		},
+	}}
+}
+
+func (s *ClusterAPITestSuite) client(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		clusterGVR:           "ClusterList",
+		machineDeploymentGVR: "MachineDeploymentList",
+		machineGVR:           "MachineList",
+	}, objects...)
+}
+
+func (s *ClusterAPITestSuite) TestSummarize() {
+	s.Run("Cluster", func() {
+		resource := Summarize(cluster("prod-eu"))
+		s.Equal("Provisioned", resource.Phase)
+		s.Equal("True", resource.Ready)
+		s.Equal("v1.31.2", resource.Version)
+		s.Equal(ptr.To(true), resource.ControlPlaneReady)
+		s.Equal(ptr.To(true), resource.InfrastructureReady)
+		s.Empty(resource.Conditions)
+	})
+	s.Run("v1beta2 Cluster", func() {
+		resource := Summarize(&unstructured.Unstructured{Object: map[string]any{
+			"kind":   "Cluster",
+			"status": map[string]any{"initialization": map[string]any{"infrastructureProvisioned": true, "controlPlaneInitialized": false}},
+		}})
+		s.Equal(ptr.To(false), resource.ControlPlaneReady)
+		s.Equal(ptr.To(true), resource.InfrastructureReady)
+	})
+	s.Run("MachineDeployment", func() {
+		resource := Summarize(machineDeployment("workers", nil, nil))
+		s.Equal("prod-eu", resource.Cluster)
+		s.Equal(ptr.To(int64(3)), resource.Replicas)
+		s.Equal(ptr.To(int64(2)), resource.ReadyReplicas)
+		s.Equal(ptr.To(int64(3)), resource.UpdatedReplicas)
+		s.Equal([]string{"Ready (Warning): WaitingForAvailableMachines: Minimum availability requires 3 replicas, current 2 available"}, resource.Conditions)
+	})
+	s.Run("Machine", func() {
+		resource := Summarize(machine("workers-abc", "Failed"))
+		s.Equal("ip-10-0-1-12", resource.Node)
+		s.Equal("aws:///eu-west-1a/i-0abc", resource.ProviderID)
+		s.Equal("CreateError: failed to create instance: InsufficientInstanceCapacity", resource.Failure)
+		s.Equal("Unknown", resource.Ready)
+	})
+}
+
+func (s *ClusterAPITestSuite) TestListResources() {
+	client := s.client(cluster("prod-eu"), cluster("prod-us"), machine("workers-abc", "Failed"))
+	s.Run("filters the resources of the Cluster", func() {
+		clusters, err := ListResources(s.T().Context(), client, clusterGVR, "fleet", "prod-eu")
+		s.Require().NoError(err)
+		s.Require().Len(clusters, 1)
+		s.Equal("prod-eu", clusters[0].Name)
+		machines, err := ListResources(s.T().Context(), client, machineGVR, "", "prod-us")
+		s.Require().NoError(err)
+		s.Empty(machines)
+	})
+	s.Run("sorts the failed resources first", func() {
+		resources := []Resource{Summarize(cluster("prod-eu")), Summarize(machineDeployment("workers", nil, nil)), Summarize(machine("workers-abc", "Failed"))}
+		SortResources(resources)
+		s.Equal([]string{"Machine", "MachineDeployment", "Cluster"}, []string{resources[0].Kind, resources[1].Kind, resources[2].Kind})
+	})
+}
+
+func (s *ClusterAPITestSuite) TestScale() {
+	s.Run("sets the replicas", func() {
+		client := s.client(machineDeployment("workers", nil, map[string]any{AutoscalerMinSizeAnnotation: "1", AutoscalerMaxSizeAnnotation: "5"}))
+		scaled, err := Scale(s.T().Context(), client, machineDeploymentGVR, "fleet", "workers", 5)
+		s.Require().NoError(err)
+		s.Equal(ptr.To(int64(5)), Summarize(scaled).Replicas)
+	})
+	s.Run("refuses the replicas out of the cluster autoscaler limits", func() {
+		client := s.client(machineDeployment("workers", nil, map[string]any{AutoscalerMinSizeAnnotation: "1", AutoscalerMaxSizeAnnotation: "5"}))
+		_, err := Scale(s.T().Context(), client, machineDeploymentGVR, "fleet", "workers", 6)
+		s.EqualError(err, "6 replicas is above the cluster autoscaler maximum size 5 of MachineDeployment fleet/workers")
+		_, err = Scale(s.T().Context(), client, machineDeploymentGVR, "fleet", "workers", 0)
+		s.EqualError(err, "0 replicas is below the cluster autoscaler minimum size 1 of MachineDeployment fleet/workers")
+	})
+	s.Run("refuses the MachineDeployments managed by the topology", func() {
+		client := s.client(machineDeployment("workers", map[string]any{TopologyOwnedLabel: "", ClusterNameLabel: "prod-eu"}, nil))
+		_, err := Scale(s.T().Context(), client, machineDeploymentGVR, "fleet", "workers", 5)
+		s.ErrorContains(err, "is managed by the topology of Cluster prod-eu")
+	})
+}
+
+func TestClusterAPI(t *testing.T) {
+	suite.Run(t, new(ClusterAPITestSuite))
+}
@@ -0,0 +1,44 @@
+package clusterapi
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Cluster API resources
+var (
+	// ClusterGK is the GroupKind for Cluster resources
+	ClusterGK = schema.GroupKind{Group: "cluster.x-k8s.io", Kind: "Cluster"}
+
+	// MachineDeploymentGK is the GroupKind for MachineDeployment resources
+	MachineDeploymentGK = schema.GroupKind{Group: "cluster.x-k8s.io", Kind: "MachineDeployment"}
+
+	// MachineGK is the GroupKind for Machine resources
+	MachineGK = schema.GroupKind{Group: "cluster.x-k8s.io", Kind: "Machine"}
+)
+
+// Kinds are the kinds of the Cluster API resources supported by the clusterapi toolset
+var Kinds = []string{ClusterGK.Kind, MachineDeploymentGK.Kind, MachineGK.Kind}
+
+// ResourceFor returns the GroupVersionResource of the provided Cluster API kind in the preferred version served by the
+// cluster (cluster.x-k8s.io/v1beta1, or v1beta2 for the Cluster API versions starting with 1.11).
+func ResourceFor(mapper meta.RESTMapper, kind string) (schema.GroupVersionResource, error) {
+	var groupKind schema.GroupKind
+	switch kind {
+	case ClusterGK.Kind:
+		groupKind = ClusterGK
+	case MachineDeploymentGK.Kind:
+		groupKind = MachineDeploymentGK
+	case MachineGK.Kind:
+		groupKind = MachineGK
+	default:
+		return schema.GroupVersionResource{}, fmt.Errorf("unsupported Cluster API kind %s, supported kinds are %v", kind, Kinds)
+	}
+	mapping, err := mapper.RESTMapping(groupKind)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return mapping.Resource, nil
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// maxChangeFreezeExtensions bounds the overlapping windows extending a recurring freeze (e.g. a window longer than its period)
+const maxChangeFreezeExtensions = 1000
+
+// ChangeFreezeConfig configures a change freeze window during which the mutating tools are rejected. The window is
+// either a date range (start and end) or recurring (a cron expression of its start and a duration).
+type ChangeFreezeConfig struct {
+	// Name identifies the freeze in the errors of the rejected tool calls (e.g. "year-end").
+	Name string `toml:"name"`
+	// Start is the start of the date range (RFC 3339, e.g. 2026-12-20T00:00:00Z).
+	Start string `toml:"start,omitempty"`
+	// End is the end of the date range (RFC 3339), excluded.
+	End string `toml:"end,omitempty"`
+	// Cron is the standard 5-field cron expression of the starts of the recurring window (e.g. "0 18 * * 5"),
+	// in the server's time zone.
+	Cron string `toml:"cron,omitempty"`
+	// Duration is the duration of the recurring window (e.g. 60h).
+	Duration string `toml:"duration,omitempty"`
+	// Targets restricts the freeze to the tool calls against these targets (clusters or contexts), all if empty.
+	Targets []string `toml:"targets,omitempty"`
+	// BreakGlass are the identities (subject of the OAuth token, e.g. the on-call engineers) allowed to call the mutating
+	// tools during the freeze with require_oauth, their calls are logged and notified to the change_freeze_break_glass
+	// event hooks.
+	BreakGlass []string `toml:"break_glass,omitempty"`
+}
+
+// Validate returns an error if the freeze is neither a valid date range nor a valid recurring window
+func (c *ChangeFreezeConfig) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("change freeze: a name is required")
+	}
+	dateRange, recurring := c.Start != "" || c.End != "", c.Cron != "" || c.Duration != ""
+	switch {
+	case dateRange && recurring, !dateRange && !recurring:
+		return fmt.Errorf("change freeze %s: either start and end, or cron and duration are required", c.Name)
+	case dateRange:
+		start, err := time.Parse(time.RFC3339, c.Start)
+		if err != nil {
+			return fmt.Errorf("change freeze %s: invalid start, RFC 3339 expected (e.g. 2026-12-20T00:00:00Z): %s", c.Name, c.Start)
+		}
+		end, err := time.Parse(time.RFC3339, c.End)
+		if err != nil {
+			return fmt.Errorf("change freeze %s: invalid end, RFC 3339 expected (e.g. 2027-01-05T00:00:00Z): %s", c.Name, c.End)
+		}
+		if !end.After(start) {
+			return fmt.Errorf("change freeze %s: the end must be after the start", c.Name)
+		}
+	default:
+		if _, err := ParseCron(c.Cron); err != nil {
+			return fmt.Errorf("change freeze %s: %w", c.Name, err)
+		}
+		if duration, err := time.ParseDuration(c.Duration); err != nil || duration <= 0 {
+			return fmt.Errorf("change freeze %s: duration must be a positive duration (e.g. 60h): %s", c.Name, c.Duration)
+		}
+	}
+	return nil
+}
+
+// ActiveUntil returns the end of the freeze window containing the provided time for the target,
+// false if the freeze is not active
+func (c *ChangeFreezeConfig) ActiveUntil(now time.Time, target string) (time.Time, bool) {
+	if len(c.Targets) > 0 && !slices.Contains(c.Targets, target) {
+		return time.Time{}, false
+	}
+	if c.Cron == "" {
+		start, startErr := time.Parse(time.RFC3339, c.Start)
+		end, endErr := time.Parse(time.RFC3339, c.End)
+		if startErr != nil || endErr != nil || now.Before(start) || !now.Before(end) {
+			return time.Time{}, false
+		}
+		return end, true
+	}
+	cron, err := ParseCron(c.Cron)
+	duration, durationErr := time.ParseDuration(c.Duration)
+	if err != nil || durationErr != nil {
+		return time.Time{}, false
+	}
+	// the latest start of a window still open, extended by the windows overlapping it
+	var latest time.Time
+	for start := cron.Next(now.Add(-duration)); !start.IsZero() && !start.After(now); start = cron.Next(start) {
+		latest = start
+	}
+	if latest.IsZero() {
+		return time.Time{}, false
+	}
+	until := latest.Add(duration)
+	for i, start := 0, cron.Next(latest); i < maxChangeFreezeExtensions && !start.IsZero() && !start.After(until); i, start = i+1, cron.Next(start) {
+		until = start.Add(duration)
+	}
+	return until, true
+}
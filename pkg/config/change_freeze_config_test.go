@@ -0,0 +1,98 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ChangeFreezeConfigSuite struct {
+	BaseConfigSuite
+}
+
+func (s *ChangeFreezeConfigSuite) at(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	s.Require().NoError(err)
+	return t
+}
+
+func (s *ChangeFreezeConfigSuite) TestReadConfig() {
+	config, err := Read(s.writeConfig(`
+		[[change_freezes]]
+		name = "year-end"
+		start = "2026-12-20T00:00:00Z"
+		end = "2027-01-05T00:00:00Z"
+		[[change_freezes]]
+		name = "weekend"
+		cron = "0 18 * * 5"
+		duration = "60h"
+		targets = ["production"]
+	`), "")
+	s.Require().NoError(err)
+	s.Require().Len(config.ChangeFreezes, 2)
+	s.Equal(ChangeFreezeConfig{Name: "year-end", Start: "2026-12-20T00:00:00Z", End: "2027-01-05T00:00:00Z"}, config.ChangeFreezes[0])
+	s.Equal(ChangeFreezeConfig{Name: "weekend", Cron: "0 18 * * 5", Duration: "60h", Targets: []string{"production"}}, config.ChangeFreezes[1])
+	for _, freeze := range config.ChangeFreezes {
+		s.NoError(freeze.Validate())
+	}
+}
+
+func (s *ChangeFreezeConfigSuite) TestValidate() {
+	for _, tc := range []struct {
+		freeze   ChangeFreezeConfig
+		expected string
+	}{
+		{ChangeFreezeConfig{Start: "2026-12-20T00:00:00Z", End: "2027-01-05T00:00:00Z"}, "change freeze: a name is required"},
+		{ChangeFreezeConfig{Name: "f"}, "change freeze f: either start and end, or cron and duration are required"},
+		{ChangeFreezeConfig{Name: "f", Start: "2026-12-20T00:00:00Z", Cron: "@daily"}, "change freeze f: either start and end, or cron and duration are required"},
+		{ChangeFreezeConfig{Name: "f", Start: "2026-12-20", End: "2027-01-05T00:00:00Z"}, "change freeze f: invalid start, RFC 3339 expected (e.g. 2026-12-20T00:00:00Z): 2026-12-20"},
+		{ChangeFreezeConfig{Name: "f", Start: "2027-01-05T00:00:00Z", End: "2026-12-20T00:00:00Z"}, "change freeze f: the end must be after the start"},
+		{ChangeFreezeConfig{Name: "f", Cron: "@daily", Duration: "0s"}, "change freeze f: duration must be a positive duration (e.g. 60h): 0s"},
+	} {
+		s.Run(tc.expected, func() {
+			s.EqualError(tc.freeze.Validate(), tc.expected)
+		})
+	}
+}
+
+func (s *ChangeFreezeConfigSuite) TestActiveUntil() {
+	s.Run("date range", func() {
+		freeze := ChangeFreezeConfig{Name: "year-end", Start: "2026-12-20T00:00:00Z", End: "2027-01-05T00:00:00Z"}
+		_, active := freeze.ActiveUntil(s.at("2026-12-19T23:59:59Z"), "")
+		s.False(active, "expected the freeze not to be active before its start")
+		until, active := freeze.ActiveUntil(s.at("2026-12-20T00:00:00Z"), "")
+		s.True(active)
+		s.Equal(s.at("2027-01-05T00:00:00Z"), until)
+		_, active = freeze.ActiveUntil(s.at("2027-01-05T00:00:00Z"), "")
+		s.False(active, "expected the freeze not to be active at its end")
+	})
+	s.Run("recurring window", func() {
+		// from Friday 18:00 to Monday 06:00
+		freeze := ChangeFreezeConfig{Name: "weekend", Cron: "0 18 * * 5", Duration: "60h"}
+		_, active := freeze.ActiveUntil(s.at("2026-10-16T17:59:00Z"), "")
+		s.False(active, "expected the freeze not to be active before its start")
+		until, active := freeze.ActiveUntil(s.at("2026-10-18T12:00:00Z"), "")
+		s.True(active)
+		s.Equal(s.at("2026-10-19T06:00:00Z"), until)
+		_, active = freeze.ActiveUntil(s.at("2026-10-19T06:00:00Z"), "")
+		s.False(active, "expected the freeze not to be active at its end")
+	})
+	s.Run("overlapping windows extend the freeze", func() {
+		freeze := ChangeFreezeConfig{Name: "nights", Cron: "0 * * * *", Duration: "90m"}
+		until, active := freeze.ActiveUntil(s.at("2026-10-16T10:30:00Z"), "")
+		s.True(active)
+		s.Equal(s.at("2026-10-16T10:30:00Z").Add(maxChangeFreezeExtensions*time.Hour+time.Hour), until)
+	})
+	s.Run("targets", func() {
+		freeze := ChangeFreezeConfig{Name: "year-end", Start: "2026-12-20T00:00:00Z", End: "2027-01-05T00:00:00Z", Targets: []string{"production"}}
+		_, active := freeze.ActiveUntil(s.at("2026-12-24T00:00:00Z"), "production")
+		s.True(active)
+		_, active = freeze.ActiveUntil(s.at("2026-12-24T00:00:00Z"), "staging")
+		s.False(active, "expected the freeze not to apply to the other targets")
+	})
+}
+
+func TestChangeFreezeConfig(t *testing.T) {
+	suite.Run(t, new(ChangeFreezeConfigSuite))
+}
@@ -0,0 +1,23 @@
+package config
+
+const (
+	CompressionZstd = "zstd"
+	CompressionGzip = "gzip"
+)
+
+// CompressionEncodings are the supported content encodings of the HTTP responses
+var CompressionEncodings = []string{CompressionZstd, CompressionGzip}
+
+// DefaultCompressionMinSize is the size below which the responses of known length are not compressed
+const DefaultCompressionMinSize = 1024
+
+// CompressionConfig configures the compression of the HTTP transport responses (e.g. large manifests and logs),
+// negotiated with each client through its Accept-Encoding header.
+type CompressionConfig struct {
+	// Encodings are the content encodings offered to the clients, in order of preference (zstd, gzip).
+	// If empty, the responses are not compressed.
+	Encodings []string `toml:"encodings,omitempty"`
+	// MinSize is the size in bytes below which the responses of known length are sent uncompressed
+	// (DefaultCompressionMinSize if not set). Streamed responses (SSE) are always compressed.
+	MinSize int `toml:"min_size,omitzero"`
+}
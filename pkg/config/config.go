@@ -22,12 +22,30 @@ const (
 // It allows to configure server specific settings and tools to be enabled or disabled.
 type StaticConfig struct {
 	DeniedResources []api.GroupVersionKind `toml:"denied_resources"`
-
-	LogLevel   int    `toml:"log_level,omitzero"`
-	Port       string `toml:"port,omitempty"`
+	// DeniedScopes deny the access to the cluster-scoped or namespaced resources as a whole, or only their changes
+	DeniedScopes []api.DeniedScope `toml:"denied_scopes,omitempty"`
+
+	LogLevel int    `toml:"log_level,omitzero"`
+	Port     string `toml:"port,omitempty"`
+	// Stdio enables the stdio transport in addition to the HTTP transport when Port is set.
+	// Both transports share the same server (caches and configuration), each client gets its own session.
+	Stdio      bool   `toml:"stdio,omitempty"`
 	SSEBaseURL string `toml:"sse_base_url,omitempty"`
 	KubeConfig string `toml:"kubeconfig,omitempty"`
 	ListOutput string `toml:"list_output,omitempty"`
+	// ListDetail is the level of detail of the objects returned by the list tools (summary or full).
+	// The summaries include the URI of each object so that clients can read the complete object on demand.
+	// Tools accepting a detail parameter allow overriding it on a per-call basis (summary if not set).
+	ListDetail string `toml:"list_detail,omitempty"`
+	// PruneFields are the fields removed from the objects returned by the tools (e.g. last_applied, status).
+	// Tools accepting a prune parameter allow overriding this list on a per-call basis.
+	PruneFields []string `toml:"prune_fields,omitempty"`
+	// Locale is the language of the human-readable summaries of the localized tools (e.g. diagnosis findings and
+	// durations), one of en (default), de, or ja. The localized tools accept a locale parameter overriding it.
+	Locale string `toml:"locale,omitempty"`
+	// MaxOutputBytes is the maximum size of the lists returned by the tools, the items exceeding it are left out
+	// (16MiB if not set).
+	MaxOutputBytes int64 `toml:"max_output_bytes,omitzero"`
 	// Stateless configures the MCP server to operate in stateless mode.
 	// When true, the server will not send notifications to clients (e.g., tools/list_changed, prompts/list_changed).
 	// This is useful for container deployments, load balancing, and serverless environments where
@@ -38,8 +56,11 @@ type StaticConfig struct {
 	// When true, expose only tools annotated with readOnlyHint=true
 	ReadOnly bool `toml:"read_only,omitempty"`
 	// When true, disable tools annotated with destructiveHint=true
-	DisableDestructive bool     `toml:"disable_destructive,omitempty"`
-	Toolsets           []string `toml:"toolsets,omitempty"`
+	DisableDestructive bool `toml:"disable_destructive,omitempty"`
+	// ProbeAPIs registers the tools working with optional APIs (e.g. Argo CD, Flux, KEDA) only if the default target
+	// serves them, probed at startup and on each reload of the targets.
+	ProbeAPIs bool     `toml:"probe_apis,omitempty"`
+	Toolsets  []string `toml:"toolsets,omitempty"`
 	// Tool configuration
 	EnabledTools  []string `toml:"enabled_tools,omitempty"`
 	DisabledTools []string `toml:"disabled_tools,omitempty"`
@@ -90,6 +111,63 @@ type StaticConfig struct {
 	// These can also be configured via OTEL_* environment variables.
 	Telemetry TelemetryConfig `toml:"telemetry,omitempty"`
 
+	// RecordFile is the path of a file where every tool call and its result are recorded (JSON Lines).
+	// The data of the Secrets and the sensitive data of the arguments and of the results are masked.
+	RecordFile string `toml:"record_file,omitempty"`
+	// ReplayFile is the path of a file produced by RecordFile whose results are served instead of calling the tools.
+	// In replay mode, no requests are performed against the cluster.
+	ReplayFile string `toml:"replay_file,omitempty"`
+
+	// EventHooks are webhooks notified on session start/end and on each successful mutating tool call.
+	EventHooks []EventHookConfig `toml:"event_hooks,omitempty"`
+
+	// HTTPCompression configures the compression of the HTTP transport responses.
+	HTTPCompression CompressionConfig `toml:"http_compression,omitempty"`
+
+	// AdminAddress is the listen address (e.g. 127.0.0.1:6060) of the admin endpoint serving the pprof profiles and the
+	// runtime statistics, disabled if empty. The endpoint is not authenticated.
+	AdminAddress string `toml:"admin_address,omitempty"`
+
+	// Redaction removes the sensitive data matching the configured rules from the tool results.
+	Redaction RedactionConfig `toml:"redaction,omitempty"`
+
+	// ChangeFreezes are the windows during which the mutating tools are rejected.
+	ChangeFreezes []ChangeFreezeConfig `toml:"change_freezes,omitempty"`
+
+	// RbacPreflight checks the Kubernetes API permissions required by the mutating tool calls before executing them
+	// (a SelfSubjectAccessReview each) and rejects the calls lacking any, with the Roles granting them.
+	RbacPreflight bool `toml:"rbac_preflight,omitempty"`
+
+	// LabelScopes are the mandatory label selectors of the sessions, the first scope matching the identity of a session
+	// applies to its requests of the namespaced resources.
+	LabelScopes []LabelScopeConfig `toml:"label_scopes,omitempty"`
+
+	// Schedules are the read-only tools called periodically by the server, their latest results are returned by the
+	// schedules_results tool and served as MCP resources. They are disabled with RequireOAuth, the scheduled calls use
+	// the credentials of the server.
+	Schedules []ScheduleConfig `toml:"schedules,omitempty"`
+
+	// Extensions are the sidecars serving custom tools over HTTP, registered with the tools of the enabled toolsets.
+	Extensions []ExtensionConfig `toml:"extensions,omitempty"`
+
+	// ResultBudget bounds the size of each tool result, the results exceeding it are degraded toward it.
+	ResultBudget ResultBudgetConfig `toml:"result_budget,omitempty"`
+
+	// ResultCache caches the results of the read-only tool calls for a short time.
+	ResultCache ResultCacheConfig `toml:"result_cache,omitempty"`
+
+	// SessionLimits caps the resources held on behalf of each MCP session.
+	SessionLimits SessionLimitsConfig `toml:"session_limits,omitempty"`
+
+	// Workspace keeps the files written by each MCP session in a temporary directory.
+	Workspace WorkspaceConfig `toml:"workspace,omitempty"`
+
+	// TransientCleanup periodically deletes the expired transient objects created by the server in the clusters.
+	TransientCleanup TransientCleanupConfig `toml:"transient_cleanup,omitempty"`
+
+	// KubernetesClient holds the client-side rate limits and user agent of the requests to the Kubernetes API server.
+	KubernetesClient api.KubernetesClientConfig `toml:"kubernetes_client,omitempty"`
+
 	// Internal: parsed provider configs (not exposed to TOML package)
 	parsedClusterProviderConfigs map[string]api.ExtendedConfig
 	// Internal: parsed toolset configs (not exposed to TOML package)
@@ -307,6 +385,10 @@ func (c *StaticConfig) GetDeniedResources() []api.GroupVersionKind {
 	return c.DeniedResources
 }
 
+func (c *StaticConfig) GetDeniedScopes() []api.DeniedScope {
+	return c.DeniedScopes
+}
+
 func (c *StaticConfig) GetKubeConfigPath() string {
 	return c.KubeConfig
 }
@@ -341,3 +423,7 @@ func (c *StaticConfig) GetStsAudience() string {
 func (c *StaticConfig) GetStsScopes() []string {
 	return c.StsScopes
 }
+
+func (c *StaticConfig) GetKubernetesClientConfig() api.KubernetesClientConfig {
+	return c.KubernetesClient
+}
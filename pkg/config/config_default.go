@@ -2,14 +2,16 @@ package config
 
 import (
 	"bytes"
+	"reflect"
 
 	"github.com/BurntSushi/toml"
 )
 
 func Default() *StaticConfig {
 	defaultConfig := StaticConfig{
-		ListOutput: "table",
-		Toolsets:   []string{"core", "config", "helm"},
+		ListOutput:  "table",
+		PruneFields: []string{"last_applied"},
+		Toolsets:    []string{"core", "config", "helm"},
 	}
 	overrides := defaultOverrides()
 	mergedConfig := mergeConfig(defaultConfig, overrides)
@@ -18,14 +20,9 @@ func Default() *StaticConfig {
 
 // HasDefaultOverrides indicates whether the internal defaultOverrides function
 // provides any overrides or an empty StaticConfig.
+// The overrides are compared instead of encoded, the empty tables of the structs with slices are still encoded.
 func HasDefaultOverrides() bool {
-	overrides := defaultOverrides()
-	var buf bytes.Buffer
-	if err := toml.NewEncoder(&buf).Encode(overrides); err != nil {
-		// If marshaling fails, assume no overrides
-		return false
-	}
-	return len(bytes.TrimSpace(buf.Bytes())) > 0
+	return !reflect.DeepEqual(defaultOverrides(), StaticConfig{})
 }
 
 // mergeConfig applies non-zero values from override to base using TOML serialization
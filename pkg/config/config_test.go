@@ -7,8 +7,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/sessionbudget"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -71,6 +73,7 @@ func (s *ConfigSuite) TestReadConfigValid() {
 	validConfigPath := s.writeConfig(`
 		log_level = 1
 		port = "9999"
+		stdio = true
 		sse_base_url = "https://example.com"
 		kubeconfig = "./path/to/config"
 		list_output = "yaml"
@@ -114,6 +117,9 @@ func (s *ConfigSuite) TestReadConfigValid() {
 	s.Run("port parsed correctly", func() {
 		s.Equalf("9999", config.Port, "Expected Port to be 9999, got %s", config.Port)
 	})
+	s.Run("stdio parsed correctly", func() {
+		s.Truef(config.Stdio, "Expected Stdio to be true, got %v", config.Stdio)
+	})
 	s.Run("sse_base_url parsed correctly", func() {
 		s.Equalf("https://example.com", config.SSEBaseURL, "Expected SSEBaseURL to be https://example.com, got %s", config.SSEBaseURL)
 	})
@@ -246,6 +252,9 @@ func (s *ConfigSuite) TestReadConfigValidPreservesDefaultsForMissingFields() {
 	s.Run("list_output defaulted correctly", func() {
 		s.Equalf("table", config.ListOutput, "Expected ListOutput to be table, got %s", config.ListOutput)
 	})
+	s.Run("prune_fields defaulted correctly", func() {
+		s.Equal([]string{"last_applied"}, config.PruneFields)
+	})
 	s.Run("toolsets defaulted correctly", func() {
 		s.Require().Lenf(config.Toolsets, 3, "Expected 3 toolsets, got %d", len(config.Toolsets))
 		for _, toolset := range []string{"core", "config", "helm"} {
@@ -1038,6 +1047,199 @@ func (s *ConfigSuite) TestEmptyConfigFile() {
 	})
 }
 
+func (s *ConfigSuite) TestReadConfigEventHooks() {
+	config, err := Read(s.writeConfig(`
+		[[event_hooks]]
+		url = "https://hooks.example.com/all"
+
+		[[event_hooks]]
+		url = "https://hooks.example.com/mutations"
+		events = ["mutating_tool_call"]
+		headers = { Authorization = "Bearer secret" }
+	`), "")
+	s.Require().NoError(err)
+	s.Require().Len(config.EventHooks, 2)
+	s.Run("hook without events accepts all events", func() {
+		s.Equal("https://hooks.example.com/all", config.EventHooks[0].URL)
+		s.True(config.EventHooks[0].Accepts(EventSessionStart))
+		s.True(config.EventHooks[0].Accepts(EventMutatingToolCall))
+	})
+	s.Run("hook with events accepts only subscribed events", func() {
+		s.Equal(map[string]string{"Authorization": "Bearer secret"}, config.EventHooks[1].Headers)
+		s.False(config.EventHooks[1].Accepts(EventSessionStart))
+		s.True(config.EventHooks[1].Accepts(EventMutatingToolCall))
+	})
+}
+
+func (s *ConfigSuite) TestReadConfigKubernetesClient() {
+	config, err := Read(s.writeConfig(`
+		[kubernetes_client]
+		qps = 5.5
+		burst = 12
+		user_agent = "mcp-agent"
+	`), "")
+	s.Require().NoError(err)
+	s.Equal(api.KubernetesClientConfig{QPS: 5.5, Burst: 12, UserAgent: "mcp-agent"}, config.GetKubernetesClientConfig())
+}
+
+func (s *ConfigSuite) TestReadConfigMaxOutputBytes() {
+	config, err := Read(s.writeConfig(`
+		max_output_bytes = 1048576
+	`), "")
+	s.Require().NoError(err)
+	s.Equal(int64(1048576), config.MaxOutputBytes)
+}
+
+func (s *ConfigSuite) TestReadConfigListDetail() {
+	config, err := Read(s.writeConfig(`
+		list_detail = "full"
+	`), "")
+	s.Require().NoError(err)
+	s.Equal("full", config.ListDetail)
+}
+
+func (s *ConfigSuite) TestReadConfigHTTPCompression() {
+	config, err := Read(s.writeConfig(`
+		[http_compression]
+		encodings = ["zstd", "gzip"]
+		min_size = 2048
+	`), "")
+	s.Require().NoError(err)
+	s.Equal(CompressionConfig{Encodings: []string{CompressionZstd, CompressionGzip}, MinSize: 2048}, config.HTTPCompression)
+}
+
+func (s *ConfigSuite) TestReadConfigSessionLimits() {
+	config, err := Read(s.writeConfig(`
+		[session_limits]
+		max_concurrent_tool_calls = 8
+		max_operations = 2
+		max_retained_bytes = 1048576
+		max_log_bytes = 4194304
+		max_goroutines = 16
+	`), "")
+	s.Require().NoError(err)
+	s.Equal(SessionLimitsConfig{MaxConcurrentToolCalls: 8, MaxOperations: 2, MaxRetainedBytes: 1048576,
+		MaxLogBytes: 4194304, MaxGoroutines: 16}, config.SessionLimits)
+	s.Equal(int64(4194304), config.SessionLimits.Limit(sessionbudget.LogBytes))
+}
+
+func (s *ConfigSuite) TestReadConfigAdminAddress() {
+	config, err := Read(s.writeConfig(`
+		admin_address = "127.0.0.1:6060"
+	`), "")
+	s.Require().NoError(err)
+	s.Equal("127.0.0.1:6060", config.AdminAddress)
+}
+
+func (s *ConfigSuite) TestReadConfigDeniedScopes() {
+	config, err := Read(s.writeConfig(`
+		denied_scopes = [
+			{ scope = "cluster", writes_only = true },
+			{ scope = "namespaced" }
+		]
+	`), "")
+	s.Require().NoError(err)
+	s.Equal([]api.DeniedScope{
+		{Scope: api.ScopeCluster, WritesOnly: true},
+		{Scope: api.ScopeNamespaced},
+	}, config.GetDeniedScopes())
+}
+
+func (s *ConfigSuite) TestReadConfigRedaction() {
+	config, err := Read(s.writeConfig(`
+		[redaction]
+		builtin_patterns = true
+		[[redaction.rules]]
+		name = "(?i)PASSWORD|TOKEN"
+		[[redaction.rules]]
+		json_path = ".data"
+		replacement = "***"
+		[[redaction.rules]]
+		pattern = 'ACME-[0-9A-F]{16}'
+	`), "")
+	s.Require().NoError(err)
+	s.True(config.Redaction.BuiltinPatterns)
+	s.Equal([]RedactionRuleConfig{
+		{Name: "(?i)PASSWORD|TOKEN"},
+		{JSONPath: ".data", Replacement: "***"},
+		{Pattern: "ACME-[0-9A-F]{16}"},
+	}, config.Redaction.Rules)
+	_, err = config.Redaction.Redactor()
+	s.NoError(err)
+}
+
+func (s *ConfigSuite) TestReadConfigResultBudget() {
+	config, err := Read(s.writeConfig(`
+		[result_budget]
+		max_bytes = 65536
+		max_tokens = 8000
+	`), "")
+	s.Require().NoError(err)
+	s.Require().NoError(config.ResultBudget.Validate())
+	s.Run("smallest budget applies", func() {
+		s.Equal(32000, config.ResultBudget.GetMaxBytes())
+		s.Equal(1000, (&ResultBudgetConfig{MaxBytes: 1000, MaxTokens: 8000}).GetMaxBytes())
+	})
+	s.Run("no budget by default", func() {
+		s.Equal(0, Default().ResultBudget.GetMaxBytes())
+	})
+	s.Run("rejects negative budgets", func() {
+		s.Error((&ResultBudgetConfig{MaxTokens: -1}).Validate())
+	})
+}
+
+func (s *ConfigSuite) TestReadConfigResultCache() {
+	config, err := Read(s.writeConfig(`
+		[result_cache]
+		max_age = "45s"
+	`), "")
+	s.Require().NoError(err)
+	s.Require().NoError(config.ResultCache.Validate())
+	s.Equal(45*time.Second, config.ResultCache.GetMaxAge())
+	s.Equal(DefaultResultCacheMaxEntries, config.ResultCache.GetMaxEntries())
+	s.Run("disabled by default", func() {
+		s.Equal(time.Duration(0), Default().ResultCache.GetMaxAge())
+	})
+	s.Run("rejects invalid max age", func() {
+		s.EqualError((&ResultCacheConfig{MaxAge: "-1s"}).Validate(), "result_cache max_age must be a positive duration (e.g. 30s): -1s")
+	})
+	s.Run("rejects negative max entries", func() {
+		s.EqualError((&ResultCacheConfig{MaxEntries: -1}).Validate(), "result_cache max_entries must be positive: -1")
+	})
+}
+
+func (s *ConfigSuite) TestReadConfigSchedules() {
+	config, err := Read(s.writeConfig(`
+		[[schedules]]
+		name = "deprecated-apis"
+		cron = "@daily"
+		tool = "resources_list"
+		target = "production"
+		arguments = { apiVersion = "v1", kind = "Pod", limit = 10 }
+	`), "")
+	s.Require().NoError(err)
+	s.Equal([]ScheduleConfig{{
+		Name:      "deprecated-apis",
+		Cron:      "@daily",
+		Tool:      "resources_list",
+		Target:    "production",
+		Arguments: map[string]any{"apiVersion": "v1", "kind": "Pod", "limit": int64(10)},
+	}}, config.Schedules)
+	s.NoError(ValidateSchedules(config.Schedules))
+	s.Run("rejects invalid names", func() {
+		err := ValidateSchedules([]ScheduleConfig{{Name: "Nightly Scan", Cron: "@daily", Tool: "pods_list"}})
+		s.EqualError(err, `schedule 0: invalid name "Nightly Scan", lowercase alphanumeric characters and dashes are expected`)
+	})
+	s.Run("rejects duplicate names", func() {
+		err := ValidateSchedules([]ScheduleConfig{{Name: "scan", Cron: "@daily", Tool: "pods_list"}, {Name: "scan", Cron: "@hourly", Tool: "pods_list"}})
+		s.EqualError(err, "schedule scan: duplicate name")
+	})
+	s.Run("rejects missing tools", func() {
+		err := ValidateSchedules([]ScheduleConfig{{Name: "scan", Cron: "@daily"}})
+		s.EqualError(err, "schedule scan: a tool is required")
+	})
+}
+
 func TestConfig(t *testing.T) {
 	suite.Run(t, new(ConfigSuite))
 }
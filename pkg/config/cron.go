@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros are the supported shorthands of the cron expressions
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSearchLimit bounds the search of the next activation of an expression that can't match (e.g. 0 0 30 2 *)
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// Cron is a parsed standard 5-field cron expression (minute hour day-of-month month day-of-week)
+type Cron struct {
+	minutes, hours, days, months, weekdays uint64
+	// anyDay and anyWeekday are set when the field is *, the day matches both fields otherwise if either matches
+	anyDay, anyWeekday bool
+}
+
+// ParseCron parses a standard 5-field cron expression, supporting the *, lists (1,15), ranges (1-5) and steps (*/10),
+// and the @yearly, @monthly, @weekly, @daily and @hourly shorthands
+func ParseCron(expression string) (*Cron, error) {
+	if macro, ok := cronMacros[strings.TrimSpace(expression)]; ok {
+		expression = macro
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields (minute hour day-of-month month day-of-week), got %d", expression, len(fields))
+	}
+	ret := &Cron{anyDay: fields[2] == "*", anyWeekday: fields[4] == "*"}
+	var err error
+	for i, field := range []struct {
+		bits     *uint64
+		min, max int
+	}{{&ret.minutes, 0, 59}, {&ret.hours, 0, 23}, {&ret.days, 1, 31}, {&ret.months, 1, 12}, {&ret.weekdays, 0, 7}} {
+		if *field.bits, err = parseCronField(fields[i], field.min, field.max); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expression, err)
+		}
+	}
+	// 7 is an alias of Sunday
+	if ret.weekdays&(1<<7) != 0 {
+		ret.weekdays |= 1
+	}
+	return ret, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var ret uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+		}
+		from, to := min, max
+		if rangePart != "*" {
+			fromPart, toPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if from, err = strconv.Atoi(fromPart); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(toPart); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			} else if hasStep {
+				to = max
+			}
+		}
+		if from < min || to > max || from > to {
+			return 0, fmt.Errorf("value %q out of range %d-%d", part, min, max)
+		}
+		for i := from; i <= to; i += step {
+			ret |= 1 << i
+		}
+	}
+	return ret, nil
+}
+
+// Next returns the first activation of the expression strictly after the provided time, in its location,
+// or the zero time if the expression never matches
+func (c *Cron) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	for limit := after.Add(cronSearchLimit); t.Before(limit); {
+		switch {
+		case c.months&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case c.hours&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case c.minutes&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Cron) matchesDay(t time.Time) bool {
+	day := c.days&(1<<t.Day()) != 0
+	weekday := c.weekdays&(1<<int(t.Weekday())) != 0
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return weekday
+	case c.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
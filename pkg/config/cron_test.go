@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CronSuite struct {
+	suite.Suite
+}
+
+func (s *CronSuite) next(expression, after string) string {
+	cron, err := ParseCron(expression)
+	s.Require().NoError(err)
+	t, err := time.Parse(time.RFC3339, after)
+	s.Require().NoError(err)
+	next := cron.Next(t)
+	if next.IsZero() {
+		return ""
+	}
+	return next.Format(time.RFC3339)
+}
+
+func (s *CronSuite) TestNext() {
+	s.Run("every minute", func() {
+		s.Equal("2026-10-16T10:31:00Z", s.next("* * * * *", "2026-10-16T10:30:15Z"))
+	})
+	s.Run("is strictly after the provided time", func() {
+		s.Equal("2026-10-17T02:00:00Z", s.next("0 2 * * *", "2026-10-16T02:00:00Z"))
+	})
+	s.Run("steps", func() {
+		s.Equal("2026-10-16T10:45:00Z", s.next("*/15 * * * *", "2026-10-16T10:30:00Z"))
+	})
+	s.Run("lists and ranges", func() {
+		s.Equal("2026-10-16T13:00:00Z", s.next("0 9-11,13 * * *", "2026-10-16T11:00:00Z"))
+	})
+	s.Run("day of week", func() {
+		// 2026-10-16 is a Friday
+		s.Equal("2026-10-19T06:00:00Z", s.next("0 6 * * 1-5", "2026-10-16T07:00:00Z"))
+		s.Equal("2026-10-18T00:00:00Z", s.next("0 0 * * 7", "2026-10-16T07:00:00Z"))
+	})
+	s.Run("day of month or day of week when both are restricted", func() {
+		s.Equal("2026-10-19T00:00:00Z", s.next("0 0 1 * 1", "2026-10-16T07:00:00Z"))
+		s.Equal("2026-11-01T00:00:00Z", s.next("0 0 1 * 1", "2026-10-26T07:00:00Z"))
+	})
+	s.Run("skips the months without the day", func() {
+		s.Equal("2026-12-31T00:00:00Z", s.next("0 0 31 * *", "2026-10-31T07:00:00Z"))
+	})
+	s.Run("macros", func() {
+		s.Equal("2026-10-17T00:00:00Z", s.next("@daily", "2026-10-16T07:00:00Z"))
+		s.Equal("2026-10-16T08:00:00Z", s.next("@hourly", "2026-10-16T07:00:00Z"))
+		s.Equal("2027-01-01T00:00:00Z", s.next("@yearly", "2026-10-16T07:00:00Z"))
+	})
+	s.Run("never matching", func() {
+		s.Equal("", s.next("0 0 30 2 *", "2026-10-16T07:00:00Z"))
+	})
+}
+
+func (s *CronSuite) TestParseCronErrors() {
+	for expression, expected := range map[string]string{
+		"* * * *":      `invalid cron expression "* * * *": expected 5 fields (minute hour day-of-month month day-of-week), got 4`,
+		"60 * * * *":   `invalid cron expression "60 * * * *": value "60" out of range 0-59`,
+		"* * 0 * *":    `invalid cron expression "* * 0 * *": value "0" out of range 1-31`,
+		"*/0 * * * *":  `invalid cron expression "*/0 * * * *": invalid step "*/0"`,
+		"a * * * *":    `invalid cron expression "a * * * *": invalid value "a"`,
+		"* 5-2 * * *":  `invalid cron expression "* 5-2 * * *": value "5-2" out of range 0-23`,
+		"@fortnightly": `invalid cron expression "@fortnightly": expected 5 fields (minute hour day-of-month month day-of-week), got 1`,
+	} {
+		s.Run(expression, func() {
+			_, err := ParseCron(expression)
+			s.EqualError(err, expected)
+		})
+	}
+}
+
+func TestCron(t *testing.T) {
+	suite.Run(t, new(CronSuite))
+}
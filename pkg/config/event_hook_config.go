@@ -0,0 +1,28 @@
+package config
+
+import "slices"
+
+const (
+	EventSessionStart     = "session_start"
+	EventSessionEnd       = "session_end"
+	EventMutatingToolCall = "mutating_tool_call"
+	// EventChangeFreezeBreakGlass is sent when a break-glass identity calls a mutating tool during a change freeze
+	EventChangeFreezeBreakGlass = "change_freeze_break_glass"
+)
+
+// EventHookConfig configures a webhook that is notified of session lifecycle and cluster mutation events.
+type EventHookConfig struct {
+	// URL is the endpoint that receives the event payload as a JSON POST request.
+	URL string `toml:"url"`
+	// Events restricts the events sent to this hook (session_start, session_end, mutating_tool_call,
+	// change_freeze_break_glass).
+	// If empty, all events are sent.
+	Events []string `toml:"events,omitempty"`
+	// Headers are additional HTTP headers sent with each request (e.g. Authorization).
+	Headers map[string]string `toml:"headers,omitempty"`
+}
+
+// Accepts returns true if the hook is subscribed to the provided event.
+func (c *EventHookConfig) Accepts(event string) bool {
+	return len(c.Events) == 0 || slices.Contains(c.Events, event)
+}
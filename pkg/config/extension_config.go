@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// DefaultExtensionTimeout is the timeout of the tool calls of the extensions if not configured
+const DefaultExtensionTimeout = time.Minute
+
+// extensionNamePattern restricts the extension names to those usable as the prefix of the names of their tools
+var extensionNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ExtensionConfig configures an extension, a sidecar serving custom tools over HTTP (see pkg/extension), so that
+// company-specific tools can be added without forking the server. The tools of the extensions go through the same
+// filters (read_only, enabled_tools...), change freezes, RBAC preflight, and audit layers as the built-in ones.
+type ExtensionConfig struct {
+	// Name of the extension, the prefix of the names of its tools (lowercase alphanumeric characters and underscores).
+	Name string `toml:"name"`
+	// URL is the base URL of the extension (e.g. http://localhost:8090).
+	URL string `toml:"url"`
+	// Headers are additional HTTP headers sent with each request (e.g. a shared secret).
+	Headers map[string]string `toml:"headers,omitempty"`
+	// ForwardAuthorization forwards the Authorization header of the MCP requests (OAuth) to the extension, so that it
+	// can act with the identity of the user.
+	ForwardAuthorization bool `toml:"forward_authorization,omitempty"`
+	// Timeout of the tool calls (e.g. 30s), DefaultExtensionTimeout if not set.
+	Timeout string `toml:"timeout,omitempty"`
+}
+
+// GetTimeout returns the timeout of the tool calls of the extension
+func (c *ExtensionConfig) GetTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(c.Timeout); err == nil && timeout > 0 {
+		return timeout
+	}
+	return DefaultExtensionTimeout
+}
+
+// ValidateExtensions returns an error if an extension is invalid or if several extensions have the same name
+func ValidateExtensions(extensions []ExtensionConfig) error {
+	names := make(map[string]bool, len(extensions))
+	for i, extension := range extensions {
+		if !extensionNamePattern.MatchString(extension.Name) {
+			return fmt.Errorf("extension %d: invalid name %q, lowercase alphanumeric characters and underscores are expected", i, extension.Name)
+		}
+		if names[extension.Name] {
+			return fmt.Errorf("extension %s: duplicate name", extension.Name)
+		}
+		names[extension.Name] = true
+		if u, err := url.Parse(extension.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("extension %s: url must be an http or https URL: %s", extension.Name, extension.URL)
+		}
+		if extension.Timeout != "" {
+			if timeout, err := time.ParseDuration(extension.Timeout); err != nil || timeout <= 0 {
+				return fmt.Errorf("extension %s: timeout must be a positive duration (e.g. 30s): %s", extension.Name, extension.Timeout)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ExtensionConfigSuite struct {
+	BaseConfigSuite
+}
+
+func (s *ExtensionConfigSuite) TestReadConfig() {
+	config, err := Read(s.writeConfig(`
+		[[extensions]]
+		name = "acme"
+		url = "http://localhost:8090"
+		forward_authorization = true
+		timeout = "30s"
+		[extensions.headers]
+		X-Api-Key = "secret"
+	`), "")
+	s.Require().NoError(err)
+	s.Equal([]ExtensionConfig{{
+		Name:                 "acme",
+		URL:                  "http://localhost:8090",
+		Headers:              map[string]string{"X-Api-Key": "secret"},
+		ForwardAuthorization: true,
+		Timeout:              "30s",
+	}}, config.Extensions)
+	s.NoError(ValidateExtensions(config.Extensions))
+	s.Equal(30*time.Second, config.Extensions[0].GetTimeout())
+}
+
+func (s *ExtensionConfigSuite) TestGetTimeout() {
+	s.Equal(DefaultExtensionTimeout, (&ExtensionConfig{}).GetTimeout())
+}
+
+func (s *ExtensionConfigSuite) TestValidateExtensions() {
+	s.Run("rejects an invalid name", func() {
+		s.EqualError(ValidateExtensions([]ExtensionConfig{{Name: "Acme-Tools", URL: "http://localhost"}}),
+			`extension 0: invalid name "Acme-Tools", lowercase alphanumeric characters and underscores are expected`)
+	})
+	s.Run("rejects duplicate names", func() {
+		s.EqualError(ValidateExtensions([]ExtensionConfig{{Name: "acme", URL: "http://a"}, {Name: "acme", URL: "http://b"}}),
+			"extension acme: duplicate name")
+	})
+	s.Run("rejects a URL without scheme", func() {
+		s.EqualError(ValidateExtensions([]ExtensionConfig{{Name: "acme", URL: "localhost:8090"}}),
+			"extension acme: url must be an http or https URL: localhost:8090")
+	})
+	s.Run("rejects an invalid timeout", func() {
+		s.EqualError(ValidateExtensions([]ExtensionConfig{{Name: "acme", URL: "http://localhost", Timeout: "-1s"}}),
+			"extension acme: timeout must be a positive duration (e.g. 30s): -1s")
+	})
+}
+
+func TestExtensionConfig(t *testing.T) {
+	suite.Run(t, new(ExtensionConfigSuite))
+}
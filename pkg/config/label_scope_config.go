@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// LabelScopeConfig scopes the sessions of the matching identities to the objects matching a label selector: the
+// selector is added to every list and watch of the namespaced resources, and the requests of an existing object not
+// matching it (reads and writes) are forbidden. It implements a soft multi-tenancy on shared clusters, the RBAC permissions of the cluster
+// credentials remain the actual boundary.
+type LabelScopeConfig struct {
+	// LabelSelector is the mandatory label selector of the matching sessions (e.g. "team=payments").
+	LabelSelector string `toml:"label_selector"`
+	// Subjects restricts the scope to the sessions of these identities (sub claim of the OAuth token), all the sessions
+	// if empty. The subjects of the tokens are only verified with require_oauth, which the subjects require.
+	Subjects []string `toml:"subjects,omitempty"`
+}
+
+// Validate returns an error if the label selector of the scope is missing or invalid
+func (c *LabelScopeConfig) Validate() error {
+	if c.LabelSelector == "" {
+		return fmt.Errorf("label scope: a label_selector is required")
+	}
+	if _, err := labels.Parse(c.LabelSelector); err != nil {
+		return fmt.Errorf("label scope: invalid label_selector %s: %w", c.LabelSelector, err)
+	}
+	return nil
+}
+
+// ValidateLabelScopes returns an error if a scope is invalid, if a scope restricts the subjects without require_oauth
+// (the subjects of the tokens wouldn't be verified), or if the scopes restricting the subjects miss a default scope
+// (without subjects) for the other sessions, which would be left unscoped
+func ValidateLabelScopes(scopes []LabelScopeConfig, requireOAuth bool) error {
+	withSubjects, withDefault := false, false
+	for _, scope := range scopes {
+		if err := scope.Validate(); err != nil {
+			return err
+		}
+		if len(scope.Subjects) == 0 {
+			withDefault = true
+			continue
+		}
+		if !requireOAuth {
+			return fmt.Errorf("label scope %s: subjects are only valid if require-oauth is enabled", scope.LabelSelector)
+		}
+		withSubjects = true
+	}
+	if withSubjects && !withDefault {
+		return fmt.Errorf("label scopes: a scope without subjects is required for the sessions matching none of the subjects")
+	}
+	return nil
+}
+
+// LabelScopeFor returns the label selector of the first scope matching the subject (empty for the sessions without
+// identity), empty if none of the scopes match
+func LabelScopeFor(scopes []LabelScopeConfig, subject string) string {
+	for _, scope := range scopes {
+		if len(scope.Subjects) == 0 || (subject != "" && slices.Contains(scope.Subjects, subject)) {
+			return scope.LabelSelector
+		}
+	}
+	return ""
+}
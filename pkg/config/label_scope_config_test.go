@@ -0,0 +1,85 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LabelScopeConfigSuite struct {
+	BaseConfigSuite
+}
+
+func (s *LabelScopeConfigSuite) TestReadConfig() {
+	config, err := Read(s.writeConfig(`
+		[[label_scopes]]
+		label_selector = "team=payments"
+		subjects = ["alice", "bob"]
+		[[label_scopes]]
+		label_selector = "team in (shared)"
+	`), "")
+	s.Require().NoError(err)
+	s.Equal([]LabelScopeConfig{
+		{LabelSelector: "team=payments", Subjects: []string{"alice", "bob"}},
+		{LabelSelector: "team in (shared)"},
+	}, config.LabelScopes)
+	for _, scope := range config.LabelScopes {
+		s.NoError(scope.Validate())
+	}
+}
+
+func (s *LabelScopeConfigSuite) TestValidate() {
+	s.Run("requires a label selector", func() {
+		s.EqualError((&LabelScopeConfig{Subjects: []string{"alice"}}).Validate(), "label scope: a label_selector is required")
+	})
+	s.Run("rejects an invalid label selector", func() {
+		s.ErrorContains((&LabelScopeConfig{LabelSelector: "team==="}).Validate(), "label scope: invalid label_selector team===")
+	})
+}
+
+func (s *LabelScopeConfigSuite) TestValidateLabelScopes() {
+	payments := LabelScopeConfig{LabelSelector: "team=payments", Subjects: []string{"alice"}}
+	shared := LabelScopeConfig{LabelSelector: "team=shared"}
+	s.Run("accepts the subjects with require_oauth and a default scope", func() {
+		s.NoError(ValidateLabelScopes([]LabelScopeConfig{payments, shared}, true))
+	})
+	s.Run("accepts the scopes without subjects without require_oauth", func() {
+		s.NoError(ValidateLabelScopes([]LabelScopeConfig{shared}, false))
+		s.NoError(ValidateLabelScopes(nil, false))
+	})
+	s.Run("rejects the subjects without require_oauth", func() {
+		s.EqualError(ValidateLabelScopes([]LabelScopeConfig{payments, shared}, false),
+			"label scope team=payments: subjects are only valid if require-oauth is enabled")
+	})
+	s.Run("rejects the subjects without a default scope", func() {
+		s.EqualError(ValidateLabelScopes([]LabelScopeConfig{payments}, true),
+			"label scopes: a scope without subjects is required for the sessions matching none of the subjects")
+	})
+	s.Run("rejects an invalid scope", func() {
+		s.EqualError(ValidateLabelScopes([]LabelScopeConfig{{Subjects: []string{"alice"}}}, true), "label scope: a label_selector is required")
+	})
+}
+
+func (s *LabelScopeConfigSuite) TestLabelScopeFor() {
+	scopes := []LabelScopeConfig{
+		{LabelSelector: "team=payments", Subjects: []string{"alice"}},
+		{LabelSelector: "team=shared"},
+	}
+	s.Run("returns the scope of the subject", func() {
+		s.Equal("team=payments", LabelScopeFor(scopes, "alice"))
+	})
+	s.Run("returns the first scope matching all the subjects for the others", func() {
+		s.Equal("team=shared", LabelScopeFor(scopes, "bob"))
+	})
+	s.Run("returns the scope matching all the subjects for the sessions without identity", func() {
+		s.Equal("team=shared", LabelScopeFor(scopes, ""))
+	})
+	s.Run("returns no scope if none match", func() {
+		s.Empty(LabelScopeFor(scopes[:1], "bob"))
+		s.Empty(LabelScopeFor(nil, "alice"))
+	})
+}
+
+func TestLabelScopeConfig(t *testing.T) {
+	suite.Run(t, new(LabelScopeConfigSuite))
+}
@@ -0,0 +1,36 @@
+package config
+
+import "github.com/containers/kubernetes-mcp-server/pkg/output"
+
+// RedactionConfig configures the removal of sensitive data (credentials, tokens...) from the results of the tool calls,
+// before they are returned to the MCP client.
+type RedactionConfig struct {
+	// BuiltinPatterns applies the built-in secret patterns of the MCP log notifications (passwords, bearer tokens,
+	// cloud provider keys, private keys...) to the tool results too.
+	BuiltinPatterns bool `toml:"builtin_patterns,omitempty"`
+	// Rules are the redaction rules applied to the tool results, in order.
+	Rules []RedactionRuleConfig `toml:"rules,omitempty"`
+}
+
+// RedactionRuleConfig is either a regular expression replaced in the text of the tool results (pattern), or the fields
+// of the YAML/JSON results to replace (json_path and/or name).
+type RedactionRuleConfig struct {
+	// Pattern is a regular expression whose matches are replaced ($1 in the replacement expands to the first group).
+	Pattern string `toml:"pattern,omitempty"`
+	// Replacement replaces the redacted data, defaults to [REDACTED].
+	Replacement string `toml:"replacement,omitempty"`
+	// JSONPath selects the objects or lists whose values are replaced (e.g. .data), the whole results if empty.
+	JSONPath string `toml:"json_path,omitempty"`
+	// Name is a regular expression matching the names of the fields to replace, and the names of the name/value
+	// entries (e.g. env vars) whose value is replaced.
+	Name string `toml:"name,omitempty"`
+}
+
+// Redactor returns the output.Redactor applying the configured rules
+func (c *RedactionConfig) Redactor() (*output.Redactor, error) {
+	redactions := make([]output.Redaction, 0, len(c.Rules))
+	for _, rule := range c.Rules {
+		redactions = append(redactions, output.Redaction{Pattern: rule.Pattern, Replacement: rule.Replacement, JSONPath: rule.JSONPath, Name: rule.Name})
+	}
+	return output.NewRedactor(redactions...)
+}
@@ -0,0 +1,33 @@
+package config
+
+import "fmt"
+
+// BytesPerToken is the approximate number of bytes of a token of the tool results, used to convert the token budgets
+const BytesPerToken = 4
+
+// ResultBudgetConfig bounds the size of each tool result so that it fits in the context window of the client.
+// The results exceeding the budget are degraded toward it (verbose metadata removed, long values truncated, fewer list
+// items) and state what was omitted. A zero value disables the corresponding budget, the smallest one applies.
+type ResultBudgetConfig struct {
+	// MaxBytes is the maximum size of a tool result in bytes.
+	MaxBytes int `toml:"max_bytes,omitempty"`
+	// MaxTokens is the maximum size of a tool result in tokens, approximated as BytesPerToken bytes per token.
+	MaxTokens int `toml:"max_tokens,omitempty"`
+}
+
+// Validate returns an error if a budget is negative
+func (c *ResultBudgetConfig) Validate() error {
+	if c.MaxBytes < 0 || c.MaxTokens < 0 {
+		return fmt.Errorf("result_budget max_bytes and max_tokens must be positive (0 disables the budget)")
+	}
+	return nil
+}
+
+// GetMaxBytes returns the budget of each tool result in bytes, 0 if there is no budget
+func (c *ResultBudgetConfig) GetMaxBytes() int {
+	ret := c.MaxBytes
+	if tokenBytes := c.MaxTokens * BytesPerToken; tokenBytes > 0 && (ret <= 0 || tokenBytes < ret) {
+		ret = tokenBytes
+	}
+	return max(ret, 0)
+}
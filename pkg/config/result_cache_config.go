@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultResultCacheMaxEntries is the number of results kept by the result cache if not configured
+const DefaultResultCacheMaxEntries = 1000
+
+// ResultCacheConfig configures the short-lived cache of the results of the read-only tool calls, so that the identical
+// calls repeated by an agent in a conversation are not sent to the cluster again, and optionally the fallback to the
+// last cached results when the cluster is unreachable.
+// The cached results are only shared by the calls with the same credentials.
+type ResultCacheConfig struct {
+	// MaxAge is the maximum age of the returned cached results (e.g. 30s), the cache is disabled if not set.
+	// The tool calls can lower it with their max_age parameter.
+	MaxAge string `toml:"max_age,omitempty"`
+	// StaleMaxAge is the maximum age of the cached results (e.g. 1h) returned, marked as stale, by the calls failing
+	// because the cluster is unreachable or times out, the fallback is disabled if not set.
+	StaleMaxAge string `toml:"stale_max_age,omitempty"`
+	// MaxEntries is the number of cached results, the oldest are dropped beyond it (DefaultResultCacheMaxEntries if not set).
+	MaxEntries int `toml:"max_entries,omitempty"`
+}
+
+// Validate returns an error if the max age isn't a valid duration or the max entries is negative
+func (c *ResultCacheConfig) Validate() error {
+	if c.MaxAge != "" {
+		if maxAge, err := time.ParseDuration(c.MaxAge); err != nil || maxAge < 0 {
+			return fmt.Errorf("result_cache max_age must be a positive duration (e.g. 30s): %s", c.MaxAge)
+		}
+	}
+	if c.StaleMaxAge != "" {
+		if staleMaxAge, err := time.ParseDuration(c.StaleMaxAge); err != nil || staleMaxAge < 0 {
+			return fmt.Errorf("result_cache stale_max_age must be a positive duration (e.g. 1h): %s", c.StaleMaxAge)
+		}
+	}
+	if c.MaxEntries < 0 {
+		return fmt.Errorf("result_cache max_entries must be positive: %d", c.MaxEntries)
+	}
+	return nil
+}
+
+// GetMaxAge returns the maximum age of the cached results, 0 if the cache is disabled
+func (c *ResultCacheConfig) GetMaxAge() time.Duration {
+	maxAge, _ := time.ParseDuration(c.MaxAge)
+	return max(0, maxAge)
+}
+
+// GetStaleMaxAge returns the maximum age of the stale cached results returned when the cluster is unreachable,
+// 0 if the fallback is disabled
+func (c *ResultCacheConfig) GetStaleMaxAge() time.Duration {
+	staleMaxAge, _ := time.ParseDuration(c.StaleMaxAge)
+	return max(0, staleMaxAge)
+}
+
+// GetRetention returns the duration the results are cached for, 0 if the cache and the fallback are disabled
+func (c *ResultCacheConfig) GetRetention() time.Duration {
+	return max(c.GetMaxAge(), c.GetStaleMaxAge())
+}
+
+// GetMaxEntries returns the number of cached results
+func (c *ResultCacheConfig) GetMaxEntries() int {
+	if c.MaxEntries > 0 {
+		return c.MaxEntries
+	}
+	return DefaultResultCacheMaxEntries
+}
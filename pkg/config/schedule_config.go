@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// scheduleNamePattern restricts the schedule names to those usable in the URIs of their MCP resources
+var scheduleNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// ScheduleConfig configures a read-only tool called periodically by the server, whose latest result is kept so that it's
+// ready when the user asks for it (e.g. a nightly scan of the deprecated APIs).
+type ScheduleConfig struct {
+	// Name identifies the schedule and its results (lowercase alphanumeric characters and dashes).
+	Name string `toml:"name"`
+	// Cron is the standard 5-field cron expression of the calls (e.g. "0 2 * * *"), in the server's time zone.
+	Cron string `toml:"cron"`
+	// Tool is the name of the called tool, it must be enabled and read-only.
+	Tool string `toml:"tool"`
+	// Arguments are the arguments of the tool calls.
+	Arguments map[string]any `toml:"arguments,omitempty"`
+	// Target is the target (cluster or context) of the tool calls, the default target if empty.
+	Target string `toml:"target,omitempty"`
+}
+
+// ValidateSchedules returns an error if a schedule is invalid or if several schedules have the same name
+func ValidateSchedules(schedules []ScheduleConfig) error {
+	names := make(map[string]bool, len(schedules))
+	for i, schedule := range schedules {
+		if !scheduleNamePattern.MatchString(schedule.Name) {
+			return fmt.Errorf("schedule %d: invalid name %q, lowercase alphanumeric characters and dashes are expected", i, schedule.Name)
+		}
+		if names[schedule.Name] {
+			return fmt.Errorf("schedule %s: duplicate name", schedule.Name)
+		}
+		names[schedule.Name] = true
+		if schedule.Tool == "" {
+			return fmt.Errorf("schedule %s: a tool is required", schedule.Name)
+		}
+		if _, err := ParseCron(schedule.Cron); err != nil {
+			return fmt.Errorf("schedule %s: %w", schedule.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import "github.com/containers/kubernetes-mcp-server/pkg/sessionbudget"
+
+// SessionLimitsConfig caps the resources the server holds on behalf of each MCP session (the tool calls in flight, the
+// background operations with their goroutines, the retained results of the completed operations, and the buffered log
+// bytes), so that a single misbehaving client can't exhaust the memory, the
+// goroutines, or the connections of the server.
+// A zero value disables the corresponding cap.
+type SessionLimitsConfig struct {
+	// MaxConcurrentToolCalls is the number of tool calls of a session handled at the same time,
+	// the calls beyond it are rejected until one of them completes.
+	MaxConcurrentToolCalls int `toml:"max_concurrent_tool_calls,omitempty"`
+	// MaxOperations is the number of background operations (tool calls with async=true) of a session running at the
+	// same time, the oldest running operation is cancelled to start a new one beyond it.
+	MaxOperations int `toml:"max_operations,omitempty"`
+	// MaxRetainedBytes is the size of the results of the completed operations of a session kept for their retrieval,
+	// the largest results are dropped first beyond it.
+	MaxRetainedBytes int64 `toml:"max_retained_bytes,omitempty"`
+	// MaxLogBytes is the size of the logs buffered at the same time by the tool calls of a session,
+	// the largest logs being read are torn down first beyond it (see sessionbudget).
+	MaxLogBytes int64 `toml:"max_log_bytes,omitempty"`
+	// MaxGoroutines is the number of background goroutines (e.g. of the operations) held by a session,
+	// the oldest are torn down first beyond it.
+	MaxGoroutines int `toml:"max_goroutines,omitempty"`
+}
+
+// Limit returns the limit of the resource accounted per session by the sessionbudget package, 0 if unlimited
+func (c SessionLimitsConfig) Limit(resource string) int64 {
+	switch resource {
+	case sessionbudget.LogBytes:
+		return c.MaxLogBytes
+	case sessionbudget.Goroutines:
+		return int64(c.MaxGoroutines)
+	}
+	return 0
+}
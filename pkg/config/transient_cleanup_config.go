@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// TransientCleanupConfig configures the automatic garbage collection of the transient objects the server creates in
+// the clusters to run the tool calls (e.g. the node debug and the network check Pods), so that the objects left behind
+// by a crash or a restart of the server don't accumulate.
+type TransientCleanupConfig struct {
+	// Interval is the interval (e.g. 10m) between the deletions of the expired transient objects in every cluster,
+	// the automatic garbage collection is disabled if not set (the transient_cleanup tool can still be called).
+	Interval string `toml:"interval,omitempty"`
+}
+
+// Validate returns an error if the interval isn't a valid duration
+func (c *TransientCleanupConfig) Validate() error {
+	if c.Interval != "" {
+		if interval, err := time.ParseDuration(c.Interval); err != nil || interval <= 0 {
+			return fmt.Errorf("transient_cleanup interval must be a positive duration (e.g. 10m): %s", c.Interval)
+		}
+	}
+	return nil
+}
+
+// GetInterval returns the interval between the automatic garbage collections, 0 if disabled
+func (c *TransientCleanupConfig) GetInterval() time.Duration {
+	interval, _ := time.ParseDuration(c.Interval)
+	return max(0, interval)
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultWorkspaceIdleTimeout is the duration after which the workspace of an idle session is removed if not configured
+const DefaultWorkspaceIdleTimeout = time.Hour
+
+// WorkspaceConfig configures the workspace of the MCP sessions, a temporary directory per session where the agents
+// write the values files and manifests they build iteratively (workspace_write, workspace_read, and workspace_list tools),
+// referenced by the other tools as workspace:<path>. The directory of a session is removed when the session ends or
+// stays idle for longer than the idle timeout.
+type WorkspaceConfig struct {
+	// MaxBytes is the size of the files of the workspace of each session, the workspace is disabled if not set.
+	MaxBytes int64 `toml:"max_bytes,omitempty"`
+	// Dir is the directory in which the temporary directories of the sessions are created (the system temporary
+	// directory if not set).
+	Dir string `toml:"dir,omitempty"`
+	// IdleTimeout is the duration (e.g. 30m) after which the workspace of a session that hasn't been used is removed,
+	// even if the end of the session wasn't notified (DefaultWorkspaceIdleTimeout if not set).
+	IdleTimeout string `toml:"idle_timeout,omitempty"`
+}
+
+// Enabled returns true if the workspace is enabled
+func (c *WorkspaceConfig) Enabled() bool {
+	return c.MaxBytes > 0
+}
+
+// Validate returns an error if the max bytes is negative, the idle timeout isn't a valid duration, or the workspace is
+// enabled in stateless mode, where the session IDs are chosen by the clients and can't isolate their workspaces
+func (c *WorkspaceConfig) Validate(stateless bool) error {
+	if c.MaxBytes < 0 {
+		return fmt.Errorf("workspace max_bytes must be positive: %d", c.MaxBytes)
+	}
+	if c.IdleTimeout != "" {
+		if idleTimeout, err := time.ParseDuration(c.IdleTimeout); err != nil || idleTimeout <= 0 {
+			return fmt.Errorf("workspace idle_timeout must be a positive duration (e.g. 30m): %s", c.IdleTimeout)
+		}
+	}
+	if stateless && c.Enabled() {
+		return fmt.Errorf("workspace is not supported in stateless mode")
+	}
+	return nil
+}
+
+// GetIdleTimeout returns the duration after which the workspace of an idle session is removed, 0 if disabled
+func (c *WorkspaceConfig) GetIdleTimeout() time.Duration {
+	if !c.Enabled() {
+		return 0
+	}
+	if idleTimeout, _ := time.ParseDuration(c.IdleTimeout); idleTimeout > 0 {
+		return idleTimeout
+	}
+	return DefaultWorkspaceIdleTimeout
+}
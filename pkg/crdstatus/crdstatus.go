@@ -0,0 +1,114 @@
+// Package crdstatus provides the helpers shared by the summaries of the custom resources of the optional APIs
+// (Argo CD, Cluster API, Crossplane, External Secrets, Flux, Gatekeeper, KEDA, Tekton): reading their nested fields and status conditions, and
+// sorting them by readiness.
+package crdstatus
+
+import (
+	"cmp"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Condition is a status condition of a custom resource.
+type Condition struct {
+	Type     string
+	Status   string
+	Reason   string
+	Message  string
+	Severity string
+}
+
+// Conditions returns the status conditions of the object
+func Conditions(object map[string]any) []Condition {
+	conditions, _, _ := unstructured.NestedSlice(object, "status", "conditions")
+	ret := make([]Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		condition, _ := condition.(map[string]any)
+		ret = append(ret, Condition{
+			Type:     NestedString(condition, "type"),
+			Status:   NestedString(condition, "status"),
+			Reason:   NestedString(condition, "reason"),
+			Message:  NestedString(condition, "message"),
+			Severity: NestedString(condition, "severity"),
+		})
+	}
+	return ret
+}
+
+// FindCondition returns the (last) status condition of the object of the provided type, false if not found
+func FindCondition(object map[string]any, conditionType string) (Condition, bool) {
+	conditions := Conditions(object)
+	for i := len(conditions) - 1; i >= 0; i-- {
+		if conditions[i].Type == conditionType {
+			return conditions[i], true
+		}
+	}
+	return Condition{}, false
+}
+
+// NestedString returns the string field of the object, empty if not found
+func NestedString(object map[string]any, fields ...string) string {
+	value, _, _ := unstructured.NestedString(object, fields...)
+	return value
+}
+
+// NestedBool returns the first of the boolean fields found, nil if none
+func NestedBool(object map[string]any, paths [][]string) *bool {
+	for _, path := range paths {
+		if value, found, _ := unstructured.NestedBool(object, path...); found {
+			return &value
+		}
+	}
+	return nil
+}
+
+// NestedInt64 returns the first of the integer fields found, nil if none
+func NestedInt64(object map[string]any, paths [][]string) *int64 {
+	for _, path := range paths {
+		if value, found, _ := unstructured.NestedInt64(object, path...); found {
+			return &value
+		}
+	}
+	return nil
+}
+
+// ReadyRank ranks the resources by the status of their Ready condition, the not ready ones first (False, then Unknown
+// or missing), then the ready but degraded (e.g. suspended or paused) ones, then the ready ones
+func ReadyRank(ready string, degraded bool) int {
+	switch {
+	case ready == string(metav1.ConditionFalse):
+		return 0
+	case ready != string(metav1.ConditionTrue):
+		return 1
+	case degraded:
+		return 2
+	}
+	return 3
+}
+
+// Key is the sort key of a resource
+type Key struct {
+	// Rank of the resource, the lowest first (e.g. ReadyRank)
+	Rank int
+	// KindOrder of the kind of the resource, the lowest first, before the kind names
+	KindOrder int
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Sort sorts the resources by rank, then by kind, namespace, and name
+func Sort[T any](resources []T, key func(T) Key) {
+	slices.SortStableFunc(resources, func(a, b T) int {
+		keyA, keyB := key(a), key(b)
+		return cmp.Or(
+			cmp.Compare(keyA.Rank, keyB.Rank),
+			cmp.Compare(keyA.KindOrder, keyB.KindOrder),
+			cmp.Compare(keyA.Kind, keyB.Kind),
+			cmp.Compare(keyA.Namespace, keyB.Namespace),
+			cmp.Compare(keyA.Name, keyB.Name),
+		)
+	})
+}
@@ -0,0 +1,72 @@
+package crdstatus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CrdStatusSuite struct {
+	suite.Suite
+}
+
+func (s *CrdStatusSuite) TestConditions() {
+	object := map[string]any{"status": map[string]any{"conditions": []any{
+		map[string]any{"type": "Ready", "status": "False", "reason": "Failed", "message": "first"},
+		map[string]any{"type": "Reconciling", "status": "True", "severity": "Info"},
+		map[string]any{"type": "Ready", "status": "False", "reason": "Failed", "message": "last"},
+	}}}
+	s.Run("returns the conditions", func() {
+		conditions := Conditions(object)
+		s.Len(conditions, 3)
+		s.Equal(Condition{Type: "Reconciling", Status: "True", Severity: "Info"}, conditions[1])
+	})
+	s.Run("finds the last condition of the type", func() {
+		ready, found := FindCondition(object, "Ready")
+		s.True(found)
+		s.Equal("last", ready.Message)
+	})
+	s.Run("doesn't find the missing conditions", func() {
+		_, found := FindCondition(object, "Stalled")
+		s.False(found)
+		_, found = FindCondition(map[string]any{}, "Ready")
+		s.False(found)
+	})
+}
+
+func (s *CrdStatusSuite) TestNested() {
+	object := map[string]any{"spec": map[string]any{"name": "web", "replicas": int64(2)}, "status": map[string]any{"ready": true}}
+	s.Equal("web", NestedString(object, "spec", "name"))
+	s.Empty(NestedString(object, "spec", "missing"))
+	s.Equal(true, *NestedBool(object, [][]string{{"status", "missing"}, {"status", "ready"}}))
+	s.Nil(NestedBool(object, [][]string{{"status", "missing"}}))
+	s.Equal(int64(2), *NestedInt64(object, [][]string{{"spec", "replicas"}}))
+	s.Nil(NestedInt64(object, [][]string{{"spec", "name"}}))
+}
+
+func (s *CrdStatusSuite) TestSort() {
+	type resource struct{ kind, name, ready string }
+	resources := []resource{
+		{"Kustomization", "b", "True"},
+		{"HelmRelease", "c", "True"},
+		{"Kustomization", "a", "True"},
+		{"Kustomization", "unknown", ""},
+		{"Kustomization", "failing", "False"},
+	}
+	Sort(resources, func(r resource) Key {
+		return Key{Rank: ReadyRank(r.ready, false), Kind: r.kind, Name: r.name}
+	})
+	var names []string
+	for _, r := range resources {
+		names = append(names, r.name)
+	}
+	s.Equal([]string{"failing", "unknown", "c", "a", "b"}, names)
+	s.Run("ranks the degraded resources between the not ready and the ready ones", func() {
+		s.Less(ReadyRank("Unknown", false), ReadyRank("True", true))
+		s.Less(ReadyRank("True", true), ReadyRank("True", false))
+	})
+}
+
+func TestCrdStatus(t *testing.T) {
+	suite.Run(t, new(CrdStatusSuite))
+}
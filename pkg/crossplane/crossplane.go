@@ -0,0 +1,262 @@
+package crossplane
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/crdstatus"
+)
+
+const (
+	// ExternalNameAnnotation is the name of the resource in the external system (e.g. the cloud provider) of a managed resource
+	ExternalNameAnnotation = "crossplane.io/external-name"
+	// CompositeLabel is set by Crossplane on the composed resources with the name of their composite
+	CompositeLabel = "crossplane.io/composite"
+	// ClaimNameLabel and ClaimNamespaceLabel are set by Crossplane on the composites and the composed resources of a claim
+	ClaimNameLabel      = "crossplane.io/claim-name"
+	ClaimNamespaceLabel = "crossplane.io/claim-namespace"
+)
+
+// maxTreeDepth limits the nesting of the composites resolved by Tree
+const maxTreeDepth = 5
+
+// Resource is the readiness and synchronization summary of a Crossplane claim, composite, or managed resource.
+type Resource struct {
+	Category   string `json:"category,omitempty"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// Ready and Synced are the status of the Ready and Synced conditions (True, False, or Unknown)
+	Ready  string `json:"ready"`
+	Synced string `json:"synced"`
+	// Reason is the reason of the Ready condition (e.g. Available, Creating, Unavailable)
+	Reason string `json:"reason,omitempty"`
+	// Errors are the messages of the conditions that are not True, for the managed resources the errors returned by the provider
+	Errors []string `json:"errors,omitempty"`
+	// ExternalName is the name of a managed resource in the external system
+	ExternalName string `json:"externalName,omitempty"`
+	// Composite is the composite of a claim or of a composed resource
+	Composite string `json:"composite,omitempty"`
+	// Claim is the claim (namespace/name) of a composite or of a composed resource
+	Claim string `json:"claim,omitempty"`
+	// Resources are the composite of a claim or the composed resources of a composite (only returned by Tree)
+	Resources []Resource `json:"resources,omitempty"`
+}
+
+// Healthy returns true if the resource is ready and synced
+func (r *Resource) Healthy() bool {
+	return r.Ready == string(metav1.ConditionTrue) && r.Synced == string(metav1.ConditionTrue)
+}
+
+// ListResources lists the Crossplane resources of the provided type in the namespace (all namespaces if empty).
+// The cluster-scoped resources are filtered by the namespace of their claim.
+func ListResources(ctx context.Context, client dynamic.Interface, apiResource APIResource, namespace string) ([]Resource, error) {
+	options := metav1.ListOptions{}
+	listNamespace := namespace
+	if !apiResource.Namespaced {
+		listNamespace = ""
+		if namespace != "" {
+			options.LabelSelector = ClaimNamespaceLabel + "=" + namespace
+		}
+	}
+	list, err := client.Resource(apiResource.GVR).Namespace(listNamespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]Resource, 0, len(list.Items))
+	for _, item := range list.Items {
+		resource := Summarize(&item)
+		resource.Category = apiResource.Category
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// SortResources sorts the unhealthy resources first, then by category, kind, namespace, and name
+func SortResources(resources []Resource) {
+	slices.SortStableFunc(resources, func(a, b Resource) int {
+		return cmp.Or(
+			cmp.Compare(healthRank(a), healthRank(b)),
+			cmp.Compare(slices.Index(Categories, a.Category), slices.Index(Categories, b.Category)),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+}
+
+// Summarize returns the readiness and synchronization summary of the provided Crossplane resource
+func Summarize(obj *unstructured.Unstructured) Resource {
+	resource := Resource{
+		APIVersion:   obj.GetAPIVersion(),
+		Kind:         obj.GetKind(),
+		Namespace:    obj.GetNamespace(),
+		Name:         obj.GetName(),
+		Ready:        string(metav1.ConditionUnknown),
+		Synced:       string(metav1.ConditionUnknown),
+		ExternalName: obj.GetAnnotations()[ExternalNameAnnotation],
+	}
+	for _, condition := range crdstatus.Conditions(obj.Object) {
+		switch condition.Type {
+		case "Ready":
+			resource.Ready = condition.Status
+			resource.Reason = condition.Reason
+		case "Synced":
+			resource.Synced = condition.Status
+		}
+		// Besides Ready and Synced, the providers report the errors of the asynchronous operations in LastAsyncOperation
+		if condition.Message != "" && condition.Status != string(metav1.ConditionTrue) && !slices.Contains(resource.Errors, condition.Message) {
+			resource.Errors = append(resource.Errors, condition.Message)
+		}
+	}
+	labels := obj.GetLabels()
+	if claimName := labels[ClaimNameLabel]; claimName != "" {
+		resource.Claim = labels[ClaimNamespaceLabel] + "/" + claimName
+	}
+	if composite := labels[CompositeLabel]; composite != "" && composite != obj.GetName() {
+		resource.Composite = composite
+	}
+	// Claims reference their composite, composites their claim (in spec.crossplane for the Crossplane v2 composites)
+	if ref, found, _ := unstructured.NestedStringMap(obj.Object, "spec", "resourceRef"); found {
+		resource.Composite = ref["kind"] + "/" + ref["name"]
+	}
+	for _, path := range [][]string{{"spec", "claimRef"}, {"spec", "crossplane", "claimRef"}} {
+		if ref, found, _ := unstructured.NestedStringMap(obj.Object, path...); found {
+			resource.Claim = ref["namespace"] + "/" + ref["name"]
+		}
+	}
+	return resource
+}
+
+// Tree returns the summary of the provided claim or composite with its composite or composed resources resolved recursively
+func Tree(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, apiVersion, kind, namespace, name string) (*Resource, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := get(ctx, client, mapper, gv.WithKind(kind), namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	resource := tree(ctx, client, mapper, obj, 0)
+	return &resource, nil
+}
+
+func tree(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured, depth int) Resource {
+	resource := Summarize(obj)
+	resource.Category = category(obj)
+	if depth >= maxTreeDepth {
+		return resource
+	}
+	for _, ref := range resourceRefs(obj) {
+		gv, _ := schema.ParseGroupVersion(ref["apiVersion"])
+		// The composed resources of the namespaced composites are in the namespace of the composite
+		child, err := get(ctx, client, mapper, gv.WithKind(ref["kind"]), cmp.Or(ref["namespace"], obj.GetNamespace()), ref["name"])
+		if err != nil {
+			resource.Resources = append(resource.Resources, Resource{
+				APIVersion: ref["apiVersion"], Kind: ref["kind"], Name: ref["name"],
+				Ready: string(metav1.ConditionUnknown), Synced: string(metav1.ConditionUnknown),
+				Errors: []string{fmt.Sprintf("failed to get resource: %v", err)},
+			})
+			continue
+		}
+		resource.Resources = append(resource.Resources, tree(ctx, client, mapper, child, depth+1))
+	}
+	return resource
+}
+
+// RootCauses returns the errors of the deepest unhealthy resources of the tree, prefixed with their kind and name,
+// the errors of their parents usually being a summary of the ones of their children (e.g. "unready resources: bucket")
+func RootCauses(resource *Resource) []string {
+	causes := make([]string, 0)
+	for i := range resource.Resources {
+		causes = append(causes, RootCauses(&resource.Resources[i])...)
+	}
+	if len(causes) > 0 || resource.Healthy() {
+		return causes
+	}
+	for _, err := range resource.Errors {
+		causes = append(causes, fmt.Sprintf("%s/%s: %s", resource.Kind, resource.Name, err))
+	}
+	return causes
+}
+
+// Count returns the number of resources in the tree and the number of unhealthy ones
+func Count(resource *Resource) (total int, unhealthy int) {
+	total = 1
+	if !resource.Healthy() {
+		unhealthy = 1
+	}
+	for i := range resource.Resources {
+		t, u := Count(&resource.Resources[i])
+		total += t
+		unhealthy += u
+	}
+	return total, unhealthy
+}
+
+func get(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		namespace = ""
+	}
+	return client.Resource(mapping.Resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// category returns the category of a Crossplane resource from its references
+func category(obj *unstructured.Unstructured) string {
+	switch {
+	case hasField(obj, "spec", "resourceRef"):
+		return CategoryClaim
+	case hasField(obj, "spec", "resourceRefs"), hasField(obj, "spec", "crossplane"), hasField(obj, "spec", "claimRef"):
+		return CategoryComposite
+	case hasField(obj, "spec", "forProvider"):
+		return CategoryManaged
+	}
+	return ""
+}
+
+// resourceRefs returns the composite of a claim or the composed resources of a composite
+func resourceRefs(obj *unstructured.Unstructured) []map[string]string {
+	if ref, found, _ := unstructured.NestedStringMap(obj.Object, "spec", "resourceRef"); found {
+		return []map[string]string{ref}
+	}
+	refs, found, _ := unstructured.NestedSlice(obj.Object, "spec", "resourceRefs")
+	if !found {
+		refs, _, _ = unstructured.NestedSlice(obj.Object, "spec", "crossplane", "resourceRefs")
+	}
+	ret := make([]map[string]string, 0, len(refs))
+	for _, ref := range refs {
+		ref, _ := ref.(map[string]any)
+		values := make(map[string]string, len(ref))
+		for key, value := range ref {
+			values[key], _ = value.(string)
+		}
+		ret = append(ret, values)
+	}
+	return ret
+}
+
+func hasField(obj *unstructured.Unstructured, fields ...string) bool {
+	_, found, _ := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+	return found
+}
+
+func healthRank(resource Resource) int {
+	if resource.Healthy() {
+		return 1
+	}
+	return 0
+}
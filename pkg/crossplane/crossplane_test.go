@@ -0,0 +1,198 @@
+package crossplane
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+var (
+	claimGVR     = schema.GroupVersionResource{Group: "storage.example.org", Version: "v1alpha1", Resource: "buckets"}
+	compositeGVR = schema.GroupVersionResource{Group: "storage.example.org", Version: "v1alpha1", Resource: "xbuckets"}
+	managedGVR   = schema.GroupVersionResource{Group: "s3.aws.upbound.io", Version: "v1beta1", Resource: "buckets"}
+	policyGVR    = schema.GroupVersionResource{Group: "s3.aws.upbound.io", Version: "v1beta1", Resource: "bucketpolicies"}
+)
+
+type CrossplaneTestSuite struct {
+	suite.Suite
+}
+
+func conditions(ready, synced, message string) []any {
+	return []any{
+		map[string]any{"type": "Ready", "status": ready, "reason": map[string]string{"True": "Available", "False": "Unavailable"}[ready], "message": message},
+		map[string]any{"type": "Synced", "status": synced, "reason": "ReconcileSuccess"},
+	}
+}
+
+func claim() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "storage.example.org/v1alpha1",
+		"kind":       "Bucket",
+		"metadata":   map[string]any{"name": "assets", "namespace": "shop"},
+		"spec":       map[string]any{"resourceRef": map[string]any{"apiVersion": "storage.example.org/v1alpha1", "kind": "XBucket", "name": "assets-x7k2p"}},
+		"status":     map[string]any{"conditions": conditions("False", "True", "Composite resource claim is waiting for composite resource to become Ready")},
+	}}
+}
+
+func composite() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "storage.example.org/v1alpha1",
+		"kind":       "XBucket",
+		"metadata": map[string]any{"name": "assets-x7k2p", "labels": map[string]any{
+			CompositeLabel: "assets-x7k2p", ClaimNameLabel: "assets", ClaimNamespaceLabel: "shop",
+		}},
+		"spec": map[string]any{
+			"claimRef": map[string]any{"namespace": "shop", "name": "assets"},
+			"resourceRefs": []any{
+				map[string]any{"apiVersion": "s3.aws.upbound.io/v1beta1", "kind": "Bucket", "name": "assets-x7k2p-bucket"},
+				map[string]any{"apiVersion": "s3.aws.upbound.io/v1beta1", "kind": "BucketPolicy", "name": "assets-x7k2p-policy"},
+			},
+		},
+		"status": map[string]any{"conditions": conditions("False", "True", "Unready resources: bucket")},
+	}}
+}
+
+func managed(name string, ready, synced, message string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "s3.aws.upbound.io/v1beta1",
+		"kind":       "Bucket",
+		"metadata": map[string]any{
+			"name":        name,
+			"labels":      map[string]any{CompositeLabel: "assets-x7k2p", ClaimNameLabel: "assets", ClaimNamespaceLabel: "shop"},
+			"annotations": map[string]any{ExternalNameAnnotation: name},
+		},
+		"spec": map[string]any{"forProvider": map[string]any{"region": "eu-west-1"}},
+		"status": map[string]any{"conditions": append(conditions(ready, synced, ""),
+			map[string]any{"type": "LastAsyncOperation", "status": "False", "reason": "ApplyFailure", "message": message},
+		)},
+	}}
+	return obj
+}
+
+func (s *CrossplaneTestSuite) client(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		claimGVR:     "BucketList",
+		compositeGVR: "XBucketList",
+		managedGVR:   "BucketList",
+		policyGVR:    "BucketPolicyList",
+	}, objects...)
+}
+
+func (s *CrossplaneTestSuite) mapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.AddSpecific(claimGVR.GroupVersion().WithKind("Bucket"), claimGVR, claimGVR, meta.RESTScopeNamespace)
+	mapper.AddSpecific(compositeGVR.GroupVersion().WithKind("XBucket"), compositeGVR, compositeGVR, meta.RESTScopeRoot)
+	mapper.AddSpecific(managedGVR.GroupVersion().WithKind("Bucket"), managedGVR, managedGVR, meta.RESTScopeRoot)
+	return mapper
+}
+
+func (s *CrossplaneTestSuite) TestAPIResources() {
+	resourceLists := []*metav1.APIResourceList{
+		{GroupVersion: "storage.example.org/v1alpha1", APIResources: []metav1.APIResource{
+			{Name: "buckets", Kind: "Bucket", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}, Categories: []string{"claim"}},
+			{Name: "xbuckets", Kind: "XBucket", Verbs: metav1.Verbs{"get", "list"}, Categories: []string{"composite"}},
+			{Name: "xbuckets/status", Kind: "XBucket", Verbs: metav1.Verbs{"get"}, Categories: []string{"composite"}},
+		}},
+		{GroupVersion: "s3.aws.upbound.io/v1beta1", APIResources: []metav1.APIResource{
+			{Name: "buckets", Kind: "Bucket", Verbs: metav1.Verbs{"get", "list"}, Categories: []string{"crossplane", "managed", "aws"}},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}, Categories: []string{"all"}},
+		}},
+	}
+	s.Run("returns the resources of all the categories", func() {
+		s.Equal([]APIResource{
+			{Category: CategoryClaim, Kind: "Bucket", Namespaced: true, GVR: claimGVR},
+			{Category: CategoryComposite, Kind: "XBucket", GVR: compositeGVR},
+			{Category: CategoryManaged, Kind: "Bucket", GVR: managedGVR},
+		}, apiResources(resourceLists, Categories))
+	})
+	s.Run("returns the resources of the provided category", func() {
+		s.Equal([]APIResource{{Category: CategoryManaged, Kind: "Bucket", GVR: managedGVR}}, apiResources(resourceLists, []string{CategoryManaged}))
+	})
+}
+
+func (s *CrossplaneTestSuite) TestSummarize() {
+	s.Run("managed resource", func() {
+		resource := Summarize(managed("assets-bucket", "False", "False", "creating bucket: AccessDenied: not authorized to perform s3:CreateBucket"))
+		s.Equal("False", resource.Ready)
+		s.Equal("Unavailable", resource.Reason)
+		s.Equal([]string{"creating bucket: AccessDenied: not authorized to perform s3:CreateBucket"}, resource.Errors)
+		s.Equal("assets-bucket", resource.ExternalName)
+		s.Equal("assets-x7k2p", resource.Composite)
+		s.Equal("shop/assets", resource.Claim)
+	})
+	s.Run("claim", func() {
+		resource := Summarize(claim())
+		s.Equal("XBucket/assets-x7k2p", resource.Composite)
+		s.Empty(resource.Claim)
+	})
+	s.Run("composite", func() {
+		resource := Summarize(composite())
+		s.Empty(resource.Composite, "the composite label of a composite is its own name")
+		s.Equal("shop/assets", resource.Claim)
+	})
+	s.Run("resource without conditions", func() {
+		resource := Summarize(&unstructured.Unstructured{Object: map[string]any{"kind": "Bucket", "metadata": map[string]any{"name": "new"}}})
+		s.Equal("Unknown", resource.Ready)
+		s.Equal("Unknown", resource.Synced)
+		s.False(resource.Healthy())
+	})
+}
+
+func (s *CrossplaneTestSuite) TestListResources() {
+	client := s.client(managed("assets-bucket", "True", "True", ""), managed("logs-bucket", "False", "True", "AccessDenied"))
+	apiResource := APIResource{Category: CategoryManaged, Kind: "Bucket", GVR: managedGVR}
+	resources, err := ListResources(s.T().Context(), client, apiResource, "")
+	s.Require().NoError(err)
+	SortResources(resources)
+	s.Require().Len(resources, 2)
+	s.Equal("logs-bucket", resources[0].Name, "unhealthy resources are sorted first")
+	s.Equal(CategoryManaged, resources[0].Category)
+	s.Run("filters the cluster-scoped resources by the namespace of their claim", func() {
+		resources, err := ListResources(s.T().Context(), client, apiResource, "other")
+		s.Require().NoError(err)
+		s.Empty(resources)
+	})
+}
+
+func (s *CrossplaneTestSuite) TestTree() {
+	client := s.client(claim(), composite(), managed("assets-x7k2p-bucket", "False", "False", "creating bucket: AccessDenied"))
+	tree, err := Tree(s.T().Context(), client, s.mapper(), "storage.example.org/v1alpha1", "Bucket", "shop", "assets")
+	s.Require().NoError(err)
+	s.Run("resolves the composite and the composed resources", func() {
+		s.Equal(CategoryClaim, tree.Category)
+		s.Require().Len(tree.Resources, 1)
+		s.Equal(CategoryComposite, tree.Resources[0].Category)
+		s.Require().Len(tree.Resources[0].Resources, 2)
+		s.Equal(CategoryManaged, tree.Resources[0].Resources[0].Category)
+		s.Equal("assets-x7k2p-bucket", tree.Resources[0].Resources[0].ExternalName)
+	})
+	s.Run("reports the composed resources that can't be retrieved", func() {
+		policy := tree.Resources[0].Resources[1]
+		s.Equal("BucketPolicy", policy.Kind)
+		s.Equal("Unknown", policy.Ready)
+		s.Len(policy.Errors, 1)
+	})
+	s.Run("returns the errors of the deepest unhealthy resources as root causes", func() {
+		causes := RootCauses(tree)
+		s.Require().Len(causes, 2)
+		s.Equal("Bucket/assets-x7k2p-bucket: creating bucket: AccessDenied", causes[0])
+		s.Contains(causes[1], "BucketPolicy/assets-x7k2p-policy: failed to get resource:")
+	})
+	s.Run("counts the unhealthy resources", func() {
+		total, unhealthy := Count(tree)
+		s.Equal(4, total)
+		s.Equal(4, unhealthy)
+	})
+}
+
+func TestCrossplane(t *testing.T) {
+	suite.Run(t, new(CrossplaneTestSuite))
+}
@@ -0,0 +1,75 @@
+package crossplane
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// APIGroup is the API group of the Crossplane core resources (e.g. CompositeResourceDefinitions), served once
+// Crossplane is installed
+const APIGroup = "apiextensions.crossplane.io"
+
+// Crossplane resource categories, set by Crossplane on the CRDs of the claims and composites it generates from the
+// CompositeResourceDefinitions, and by the providers on the CRDs of their managed resources
+const (
+	CategoryClaim     = "claim"
+	CategoryComposite = "composite"
+	CategoryManaged   = "managed"
+)
+
+// Categories are the Crossplane resource categories supported by the crossplane toolset
+var Categories = []string{CategoryClaim, CategoryComposite, CategoryManaged}
+
+// APIResource is a Crossplane resource type served by the cluster.
+type APIResource struct {
+	Category   string
+	Kind       string
+	Namespaced bool
+	GVR        schema.GroupVersionResource
+}
+
+// Discover returns the Crossplane resource types of the provided categories (all the categories if empty) served by the cluster,
+// found with the categories of the API resources (as kubectl get managed does)
+func Discover(discoveryClient discovery.DiscoveryInterface, categories ...string) ([]APIResource, error) {
+	resourceLists, err := discoveryClient.ServerPreferredResources()
+	// Partial discovery failures (e.g. unavailable aggregated APIs) are tolerated
+	if err != nil && len(resourceLists) == 0 {
+		return nil, fmt.Errorf("failed to discover API resources: %w", err)
+	}
+	if len(categories) == 0 {
+		categories = Categories
+	}
+	return apiResources(resourceLists, categories), nil
+}
+
+func apiResources(resourceLists []*metav1.APIResourceList, categories []string) []APIResource {
+	ret := make([]APIResource, 0)
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, apiResource := range resourceList.APIResources {
+			if !slices.Contains(apiResource.Verbs, "list") || strings.Contains(apiResource.Name, "/") {
+				continue
+			}
+			// Claims and composites are listed in a single category, managed resources in the crossplane, managed, and provider ones
+			index := slices.IndexFunc(categories, func(category string) bool { return slices.Contains(apiResource.Categories, category) })
+			if index < 0 {
+				continue
+			}
+			ret = append(ret, APIResource{
+				Category:   categories[index],
+				Kind:       apiResource.Kind,
+				Namespaced: apiResource.Namespaced,
+				GVR:        gv.WithResource(apiResource.Name),
+			})
+		}
+	}
+	return ret
+}
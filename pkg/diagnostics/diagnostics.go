@@ -0,0 +1,198 @@
+package diagnostics
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	runtimepprof "runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// maxGoroutineGroups is the number of the largest groups of goroutines reported by Collect
+const maxGoroutineGroups = 10
+
+// startTime is the time the process started (close enough: the time the package was initialized)
+var startTime = time.Now()
+
+// Runtime is the summary of the Go runtime of the server, to investigate its memory growth and goroutine leaks
+type Runtime struct {
+	GoVersion     string `json:"goVersion"`
+	UptimeSeconds int64  `json:"uptimeSeconds"`
+	NumCPU        int    `json:"numCPU"`
+	GOMAXPROCS    int    `json:"gomaxprocs"`
+	Goroutines    int    `json:"goroutines"`
+	Memory        Memory `json:"memory"`
+	GC            GC     `json:"gc"`
+	// GoroutineGroups are the largest groups of goroutines sharing the same stack
+	GoroutineGroups []GoroutineGroup `json:"goroutineGroups,omitempty"`
+}
+
+type Memory struct {
+	// HeapAllocBytes is the size of the allocated heap objects
+	HeapAllocBytes uint64 `json:"heapAllocBytes"`
+	HeapInuseBytes uint64 `json:"heapInuseBytes"`
+	HeapIdleBytes  uint64 `json:"heapIdleBytes"`
+	// HeapReleasedBytes is the size of the heap returned to the operating system
+	HeapReleasedBytes uint64 `json:"heapReleasedBytes"`
+	HeapObjects       uint64 `json:"heapObjects"`
+	StackInuseBytes   uint64 `json:"stackInuseBytes"`
+	// SysBytes is the memory obtained from the operating system
+	SysBytes uint64 `json:"sysBytes"`
+}
+
+type GC struct {
+	NumGC uint32 `json:"numGC"`
+	// LastGC is the time the last garbage collection completed
+	LastGC       *time.Time `json:"lastGC,omitempty"`
+	PauseTotalMs float64    `json:"pauseTotalMs"`
+	LastPauseMs  float64    `json:"lastPauseMs"`
+	CPUFraction  float64    `json:"cpuFraction"`
+	NextGCBytes  uint64     `json:"nextGCBytes"`
+	// GOGC is the value of the GOGC environment variable (100 if not set)
+	GOGC string `json:"gogc,omitempty"`
+	// MemoryLimitBytes is the soft memory limit of the runtime (GOMEMLIMIT), if any
+	MemoryLimitBytes int64 `json:"memoryLimitBytes,omitempty"`
+}
+
+type GoroutineGroup struct {
+	Count int `json:"count"`
+	// Function is the first function of the stack outside the Go runtime
+	Function string `json:"function"`
+	// CreatedBy is the function that started the goroutines
+	CreatedBy string `json:"createdBy,omitempty"`
+}
+
+// Collect returns the summary of the Go runtime, reading the memory statistics stops the world briefly
+func Collect() *Runtime {
+	memStats := &runtime.MemStats{}
+	runtime.ReadMemStats(memStats)
+	ret := &Runtime{
+		GoVersion:     runtime.Version(),
+		UptimeSeconds: int64(time.Since(startTime).Seconds()),
+		NumCPU:        runtime.NumCPU(),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		Goroutines:    runtime.NumGoroutine(),
+		Memory: Memory{
+			HeapAllocBytes:    memStats.HeapAlloc,
+			HeapInuseBytes:    memStats.HeapInuse,
+			HeapIdleBytes:     memStats.HeapIdle,
+			HeapReleasedBytes: memStats.HeapReleased,
+			HeapObjects:       memStats.HeapObjects,
+			StackInuseBytes:   memStats.StackInuse,
+			SysBytes:          memStats.Sys,
+		},
+		GC: GC{
+			NumGC:        memStats.NumGC,
+			PauseTotalMs: float64(memStats.PauseTotalNs) / float64(time.Millisecond),
+			CPUFraction:  memStats.GCCPUFraction,
+			NextGCBytes:  memStats.NextGC,
+			GOGC:         os.Getenv("GOGC"),
+		},
+		GoroutineGroups: goroutineGroups(),
+	}
+	if limit := debug.SetMemoryLimit(-1); limit != math.MaxInt64 {
+		ret.GC.MemoryLimitBytes = limit
+	}
+	if memStats.NumGC > 0 {
+		lastGC := time.Unix(0, int64(memStats.LastGC)).UTC()
+		ret.GC.LastGC = &lastGC
+		ret.GC.LastPauseMs = float64(memStats.PauseNs[(memStats.NumGC+255)%256]) / float64(time.Millisecond)
+	}
+	return ret
+}
+
+// goroutineHeader matches the header of a group of the goroutine profile (e.g. "12 @ 0x43e8d6 0x40650c")
+var goroutineHeader = regexp.MustCompile(`^(\d+) @`)
+
+// goroutineGroups returns the largest groups of goroutines of the goroutine profile
+func goroutineGroups() []GoroutineGroup {
+	profile := runtimepprof.Lookup("goroutine")
+	if profile == nil {
+		return nil
+	}
+	buffer := &bytes.Buffer{}
+	if err := profile.WriteTo(buffer, 1); err != nil {
+		return nil
+	}
+	return parseGoroutineGroups(buffer.Bytes())
+}
+
+// parseGoroutineGroups parses the goroutine profile in the debug=1 format: a header with the count of each group
+// followed by its stack frames ("#	0x4a1b2c	net/http.(*conn).serve+0x5f	/usr/lib/go/src/net/http/server.go:2009")
+func parseGoroutineGroups(profile []byte) []GoroutineGroup {
+	groups := make([]GoroutineGroup, 0)
+	var functions []string
+	count := 0
+	flush := func() {
+		if count > 0 && len(functions) > 0 {
+			group := GoroutineGroup{Count: count, Function: functions[0]}
+			if i := slices.IndexFunc(functions, func(f string) bool { return !isRuntimeFunction(f) }); i >= 0 {
+				group.Function = functions[i]
+			}
+			if len(functions) > 1 {
+				group.CreatedBy = functions[len(functions)-1]
+			}
+			groups = append(groups, group)
+		}
+		count, functions = 0, nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(profile))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := goroutineHeader.FindStringSubmatch(line); match != nil {
+			flush()
+			count, _ = strconv.Atoi(match[1])
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) >= 3 && fields[0] == "#" {
+			function := fields[2]
+			if i := strings.LastIndex(function, "+0x"); i > 0 {
+				function = function[:i]
+			}
+			functions = append(functions, function)
+		}
+	}
+	flush()
+	slices.SortStableFunc(groups, func(a, b GoroutineGroup) int { return cmp.Compare(b.Count, a.Count) })
+	return groups[:min(len(groups), maxGoroutineGroups)]
+}
+
+func isRuntimeFunction(function string) bool {
+	return strings.HasPrefix(function, "runtime.") || strings.HasPrefix(function, "internal/") ||
+		strings.HasPrefix(function, "sync.") || strings.HasPrefix(function, "runtime/")
+}
+
+// Handler serves the pprof profiles (/debug/pprof/) and the summary of the Go runtime as JSON (/debug/runtime)
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/runtime", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(Collect()); err != nil {
+			klog.V(1).Infof("Failed to encode runtime diagnostics: %v", err)
+		}
+	})
+	return mux
+}
@@ -0,0 +1,103 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DiagnosticsSuite struct {
+	suite.Suite
+}
+
+const goroutineProfile = `goroutine profile: total 7
+4 @ 0x43e8d6 0x40650c 0x406077 0x6d2a45 0x471a61
+#	0x6d2a44	github.com/containers/kubernetes-mcp-server/pkg/mcp.(*toolsReloader).request.func1+0x44	/src/pkg/mcp/reload.go:50
+#	0x471a60	github.com/containers/kubernetes-mcp-server/pkg/mcp.(*toolsReloader).request+0x20	/src/pkg/mcp/reload.go:40
+
+2 @ 0x43e8d6 0x44f1e5 0x6d3b12 0x471a61
+#	0x44f1e4	sync.runtime_notifyListWait+0x124	/usr/lib/go/src/runtime/sema.go:597
+#	0x6d3b11	net/http.(*conn).serve+0x5f	/usr/lib/go/src/net/http/server.go:2009
+#	0x471a60	net/http.(*Server).Serve+0x20	/usr/lib/go/src/net/http/server.go:3360
+
+1 @ 0x43e8d6 0x471a61
+#	0x43e8d5	runtime.gopark+0xce	/usr/lib/go/src/runtime/proc.go:424
+`
+
+func (s *DiagnosticsSuite) TestParseGoroutineGroups() {
+	groups := parseGoroutineGroups([]byte(goroutineProfile))
+	s.Require().Len(groups, 3)
+	s.Run("sorts the groups by count", func() {
+		s.Equal([]int{4, 2, 1}, []int{groups[0].Count, groups[1].Count, groups[2].Count})
+	})
+	s.Run("reports the first function outside the Go runtime", func() {
+		s.Equal("github.com/containers/kubernetes-mcp-server/pkg/mcp.(*toolsReloader).request.func1", groups[0].Function)
+		s.Equal("net/http.(*conn).serve", groups[1].Function)
+	})
+	s.Run("reports the function that created the goroutines", func() {
+		s.Equal("net/http.(*Server).Serve", groups[1].CreatedBy)
+	})
+	s.Run("reports runtime goroutines as is", func() {
+		s.Equal("runtime.gopark", groups[2].Function)
+		s.Empty(groups[2].CreatedBy)
+	})
+}
+
+func (s *DiagnosticsSuite) TestCollect() {
+	runtime.GC()
+	ret := Collect()
+	s.Equal(runtime.Version(), ret.GoVersion)
+	s.Equal(runtime.NumCPU(), ret.NumCPU)
+	s.Greater(ret.Goroutines, 0)
+	s.Greater(ret.Memory.HeapAllocBytes, uint64(0))
+	s.Greater(ret.Memory.SysBytes, uint64(0))
+	s.Run("reports the last garbage collection", func() {
+		s.Greater(ret.GC.NumGC, uint32(0))
+		s.NotNil(ret.GC.LastGC)
+	})
+	s.Run("reports the goroutine groups", func() {
+		s.NotEmpty(ret.GoroutineGroups)
+		s.LessOrEqual(len(ret.GoroutineGroups), maxGoroutineGroups)
+	})
+}
+
+func (s *DiagnosticsSuite) TestHandler() {
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+	s.Run("serves the runtime summary", func() {
+		resp, err := http.Get(server.URL + "/debug/runtime")
+		s.Require().NoError(err)
+		defer func() { _ = resp.Body.Close() }()
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Equal("application/json", resp.Header.Get("Content-Type"))
+		ret := &Runtime{}
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(ret))
+		s.Equal(runtime.Version(), ret.GoVersion)
+	})
+	s.Run("rejects other methods for the runtime summary", func() {
+		resp, err := http.Post(server.URL+"/debug/runtime", "application/json", nil)
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		s.Equal(http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+	s.Run("serves the pprof profiles", func() {
+		resp, err := http.Get(server.URL + "/debug/pprof/heap?debug=1")
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		s.Equal(http.StatusOK, resp.StatusCode)
+	})
+	s.Run("does not serve other paths", func() {
+		resp, err := http.Get(server.URL + "/mcp")
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestDiagnostics(t *testing.T) {
+	suite.Run(t, new(DiagnosticsSuite))
+}
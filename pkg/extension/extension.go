@@ -0,0 +1,281 @@
+// Package extension serves the custom tools of the extensions, sidecars implementing the extension protocol over HTTP,
+// so that company-specific tools (e.g. for internal CRDs or paved-road actions) can be added without forking the server.
+//
+// The protocol has two endpoints relative to the URL of the extension:
+//   - GET /tools returns the definitions of the tools: {"tools": [{"name", "description", "inputSchema", "annotations", "permissions"}]}
+//   - POST /tools/<name> calls a tool with {"arguments": {...}, "target": "..."} and returns {"content": "..."} or {"error": "..."}
+//
+// The names of the tools are prefixed with the name of the extension (e.g. acme_tenants_create). The tools not annotated
+// as read-only are treated as mutating and destructive unless annotated otherwise, and their declared Kubernetes API
+// permissions are checked by the RBAC preflight (rbac_preflight). The extensions access the clusters with their own
+// clients, the calls of the tools declaring permissions on denied resources (denied_resources, denied_scopes) are
+// rejected before reaching them, as are the calls of the mutating tools declaring no permissions when resources or
+// scopes are denied.
+package extension
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+// discoveryTimeout is the timeout of the requests of the definitions of the tools
+const discoveryTimeout = 10 * time.Second
+
+// maxErrorBodyBytes bounds the part of the body of the failed responses reported in the errors
+const maxErrorBodyBytes = 1024
+
+// maxResponseBytes bounds the size of the responses of the extensions
+const maxResponseBytes = 16 << 20
+
+// toolNamePattern is the pattern of the names of the MCP tools, the names of the tools of the extensions are prefixed
+// with the name of the extension
+var toolNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// ToolDefinition is the definition of a tool returned by an extension.
+type ToolDefinition struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	InputSchema *jsonschema.Schema  `json:"inputSchema,omitempty"`
+	Annotations api.ToolAnnotations `json:"annotations"`
+	// Permissions are the Kubernetes API permissions required by the calls of the tool
+	Permissions []Permission `json:"permissions,omitempty"`
+}
+
+// Permission is a Kubernetes API permission required by the calls of a tool, in the namespace argument of the call
+// (the configured one if not provided) if namespaced.
+type Permission struct {
+	Verb        string `json:"verb"`
+	Group       string `json:"group,omitempty"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	Namespaced  bool   `json:"namespaced,omitempty"`
+}
+
+type toolsResponse struct {
+	Tools []ToolDefinition `json:"tools"`
+}
+
+type callRequest struct {
+	Arguments map[string]any `json:"arguments"`
+	Target    string         `json:"target,omitempty"`
+}
+
+type callResponse struct {
+	Content string `json:"content"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Toolset serves the tools of an extension.
+type Toolset struct {
+	config config.ExtensionConfig
+	denied api.DeniedResourcesProvider
+	client *http.Client
+}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func NewToolset(config config.ExtensionConfig, denied api.DeniedResourcesProvider) *Toolset {
+	return &Toolset{config: config, denied: denied, client: &http.Client{}}
+}
+
+func (t *Toolset) GetName() string {
+	return t.config.Name
+}
+
+func (t *Toolset) GetDescription() string {
+	return fmt.Sprintf("Custom tools served by the %s extension", t.config.Name)
+}
+
+// GetTools returns the tools currently served by the extension, none if they can't be retrieved (e.g. the extension
+// isn't running yet), they are retrieved again on the next reload of the tools
+func (t *Toolset) GetTools(_ api.Openshift) []api.ServerTool {
+	definitions, err := t.definitions()
+	if err != nil {
+		klog.Errorf("Failed to retrieve the tools of the %s extension: %v", t.config.Name, err)
+		return nil
+	}
+	tools := make([]api.ServerTool, 0, len(definitions))
+	for _, definition := range definitions {
+		tools = append(tools, t.serverTool(definition))
+	}
+	return tools
+}
+
+func (t *Toolset) GetPrompts() []api.ServerPrompt {
+	return nil
+}
+
+func (t *Toolset) definitions() ([]ToolDefinition, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(t.config.URL, "/")+"/tools", nil)
+	if err != nil {
+		return nil, err
+	}
+	var response toolsResponse
+	if err = t.do(req, &response); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(response.Tools))
+	for _, definition := range response.Tools {
+		switch {
+		case definition.Name == "":
+			return nil, errors.New("tool without name")
+		case !toolNamePattern.MatchString(t.config.Name + "_" + definition.Name):
+			return nil, fmt.Errorf("invalid tool name %q, the names must match %s once prefixed with the name of the extension", definition.Name, toolNamePattern)
+		case names[definition.Name]:
+			return nil, fmt.Errorf("duplicate tool name %q", definition.Name)
+		}
+		names[definition.Name] = true
+	}
+	return response.Tools, nil
+}
+
+func (t *Toolset) serverTool(definition ToolDefinition) api.ServerTool {
+	annotations := definition.Annotations
+	readOnly := ptr.Deref(annotations.ReadOnlyHint, false)
+	annotations.ReadOnlyHint = ptr.To(readOnly)
+	if annotations.DestructiveHint == nil {
+		annotations.DestructiveHint = ptr.To(!readOnly)
+	}
+	if annotations.Title == "" {
+		annotations.Title = fmt.Sprintf("%s: %s", t.config.Name, definition.Name)
+	}
+	inputSchema := definition.InputSchema
+	if inputSchema == nil {
+		inputSchema = &jsonschema.Schema{Type: "object"}
+	}
+	tool := api.ServerTool{
+		Tool: api.Tool{
+			Name:        t.config.Name + "_" + definition.Name,
+			Description: definition.Description,
+			InputSchema: inputSchema,
+			Annotations: annotations,
+		},
+		Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+			if err := t.checkDenied(params, readOnly, definition.Permissions); err != nil {
+				return api.NewToolCallResult("", fmt.Errorf("failed to call the %s extension: %w", t.config.Name, err)), nil
+			}
+			return t.call(params, definition.Name)
+		},
+	}
+	if len(definition.Permissions) > 0 {
+		tool.Permissions = func(params api.ToolHandlerParams) ([]api.Permission, error) {
+			return permissions(params, definition.Permissions), nil
+		}
+	}
+	return tool
+}
+
+func (t *Toolset) call(params api.ToolHandlerParams, name string) (*api.ToolCallResult, error) {
+	body, err := json.Marshal(callRequest{Arguments: params.GetArguments(), Target: params.Target})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(params.Context, t.config.GetTimeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(t.config.URL, "/")+"/tools/"+url.PathEscape(name), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorization, ok := params.Value(kubernetes.OAuthAuthorizationHeader).(string); ok && t.config.ForwardAuthorization {
+		req.Header.Set("Authorization", authorization)
+	}
+	var response callResponse
+	if err = t.do(req, &response); err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to call the %s extension: %w", t.config.Name, err)), nil
+	}
+	if response.Error != "" {
+		return api.NewToolCallResult("", errors.New(response.Error)), nil
+	}
+	return api.NewToolCallResult(response.Content, nil), nil
+}
+
+// checkDenied returns an error if any of the declared permissions is on a resource denied by the configuration, with
+// the same rules as the access control of the Kubernetes API requests: by its kind (in any of its versions) or by its
+// scope (only for the writes if the scope only denies them). The mutating tools declaring no permissions might change
+// any resource, they're denied when any resource or scope is.
+func (t *Toolset) checkDenied(params api.ToolHandlerParams, readOnly bool, declared []Permission) error {
+	if t.denied == nil || (len(t.denied.GetDeniedResources()) == 0 && len(t.denied.GetDeniedScopes()) == 0) {
+		return nil
+	}
+	if !readOnly && len(declared) == 0 {
+		return errors.New("the tool declares no permissions, the mutating tools must declare them when resources or scopes are denied")
+	}
+	for _, permission := range declared {
+		resource := schema.GroupResource{Group: permission.Group, Resource: permission.Resource}
+		gvks, err := params.RESTMapper().KindsFor(resource.WithVersion(""))
+		if err != nil {
+			return fmt.Errorf("failed to get the kind of %s: %w", resource, err)
+		}
+		scope := api.ScopeCluster
+		if permission.Namespaced {
+			scope = api.ScopeNamespaced
+		}
+		write := permission.Verb != "get" && permission.Verb != "list" && permission.Verb != "watch"
+		for _, gvk := range gvks {
+			if err = kubernetes.CheckDenied(t.denied, gvk, scope, write); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// do sends the request with the configured headers and decodes the JSON response
+func (t *Toolset) do(req *http.Request, response any) error {
+	for k, v := range t.config.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	body := &io.LimitedReader{R: resp.Body, N: maxResponseBytes + 1}
+	if err = json.NewDecoder(body).Decode(response); err != nil {
+		if body.N == 0 {
+			return fmt.Errorf("response larger than %d bytes", maxResponseBytes)
+		}
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+// permissions returns the permissions declared by the tool, in the namespace of the call for the namespaced ones
+func permissions(params api.ToolHandlerParams, declared []Permission) []api.Permission {
+	ret := make([]api.Permission, 0, len(declared))
+	for _, permission := range declared {
+		p := api.Permission{Verb: permission.Verb, Group: permission.Group, Resource: permission.Resource, Subresource: permission.Subresource}
+		if permission.Namespaced {
+			namespace, _ := params.GetArguments()["namespace"].(string)
+			p.Namespace = kubernetes.NewCore(params).NamespaceOrDefault(namespace)
+		}
+		ret = append(ret, p)
+	}
+	return ret
+}
@@ -0,0 +1,161 @@
+package extension
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+type toolCallRequest map[string]any
+
+func (r toolCallRequest) GetArguments() map[string]any {
+	return r
+}
+
+type ExtensionSuite struct {
+	suite.Suite
+	mockServer *test.MockServer
+	client     api.KubernetesClient
+}
+
+func (s *ExtensionSuite) SetupTest() {
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "authorization.k8s.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "selfsubjectaccessreviews", Kind: "SelfSubjectAccessReview", Namespaced: false, Verbs: metav1.Verbs{"create"}},
+		},
+	}))
+	var err error
+	s.client, err = kubernetes.NewKubernetes(config.Default(), clientcmd.NewDefaultClientConfig(*s.mockServer.Kubeconfig(), nil), s.mockServer.Config())
+	s.Require().NoError(err)
+}
+
+func (s *ExtensionSuite) TearDownTest() {
+	s.mockServer.Close()
+}
+
+func (s *ExtensionSuite) params(arguments map[string]any) api.ToolHandlerParams {
+	return api.ToolHandlerParams{Context: s.T().Context(), KubernetesClient: s.client, ToolCallRequest: toolCallRequest(arguments)}
+}
+
+func (s *ExtensionSuite) TestCheckDenied() {
+	deploy := []Permission{{Verb: "create", Group: "apps", Resource: "deployments", Namespaced: true}}
+	list := []Permission{{Verb: "list", Group: "apps", Resource: "deployments", Namespaced: true}}
+	review := []Permission{{Verb: "create", Group: "authorization.k8s.io", Resource: "selfsubjectaccessreviews"}}
+	s.Run("allows every tool without denied resources nor scopes", func() {
+		toolset := NewToolset(config.ExtensionConfig{Name: "acme"}, &config.StaticConfig{})
+		s.NoError(toolset.checkDenied(s.params(nil), false, nil))
+		s.NoError(toolset.checkDenied(s.params(nil), false, deploy))
+	})
+	s.Run("denied resource", func() {
+		toolset := NewToolset(config.ExtensionConfig{Name: "acme"}, &config.StaticConfig{
+			DeniedResources: []api.GroupVersionKind{{Group: "apps", Version: "v1", Kind: "Deployment"}},
+		})
+		s.Run("rejects the permissions on the denied resource in any of its versions", func() {
+			s.EqualError(toolset.checkDenied(s.params(nil), false, deploy), "resource not allowed: apps/v1, Kind=Deployment")
+			s.Error(toolset.checkDenied(s.params(nil), true, list))
+		})
+		s.Run("allows the permissions on the other resources", func() {
+			s.NoError(toolset.checkDenied(s.params(nil), false, review))
+		})
+		s.Run("rejects the mutating tools declaring no permissions", func() {
+			err := toolset.checkDenied(s.params(nil), false, nil)
+			s.Require().Error(err)
+			s.Contains(err.Error(), "the tool declares no permissions")
+		})
+		s.Run("allows the read-only tools declaring no permissions", func() {
+			s.NoError(toolset.checkDenied(s.params(nil), true, nil))
+		})
+	})
+	s.Run("denied scope", func() {
+		toolset := NewToolset(config.ExtensionConfig{Name: "acme"}, &config.StaticConfig{
+			DeniedScopes: []api.DeniedScope{{Scope: api.ScopeNamespaced, WritesOnly: true}},
+		})
+		s.Run("rejects the writes to the denied scope", func() {
+			s.EqualError(toolset.checkDenied(s.params(nil), false, deploy),
+				"resource not allowed: apps/v1, Kind=Deployment (writes to namespaced resources are denied)")
+		})
+		s.Run("allows the reads of the scope denying the writes", func() {
+			s.NoError(toolset.checkDenied(s.params(nil), true, list))
+		})
+	})
+	s.Run("allows the reviews in a cluster scope denying the writes", func() {
+		toolset := NewToolset(config.ExtensionConfig{Name: "acme"}, &config.StaticConfig{
+			DeniedScopes: []api.DeniedScope{{Scope: api.ScopeCluster, WritesOnly: true}},
+		})
+		s.NoError(toolset.checkDenied(s.params(nil), false, review))
+	})
+	s.Run("fails for the unknown resources", func() {
+		toolset := NewToolset(config.ExtensionConfig{Name: "acme"}, &config.StaticConfig{
+			DeniedResources: []api.GroupVersionKind{{Group: "apps", Version: "v1", Kind: "Deployment"}},
+		})
+		err := toolset.checkDenied(s.params(nil), false, []Permission{{Verb: "create", Group: "acme.io", Resource: "tenants"}})
+		s.Require().Error(err)
+		s.Contains(err.Error(), "failed to get the kind of tenants.acme.io")
+	})
+}
+
+func (s *ExtensionSuite) TestPermissions() {
+	declared := []Permission{
+		{Verb: "create", Group: "apps", Resource: "deployments", Namespaced: true},
+		{Verb: "get", Resource: "nodes", Subresource: "proxy"},
+	}
+	s.Run("in the namespace argument", func() {
+		s.Equal([]api.Permission{
+			{Verb: "create", Group: "apps", Resource: "deployments", Namespace: "shop"},
+			{Verb: "get", Resource: "nodes", Subresource: "proxy"},
+		}, permissions(s.params(map[string]any{"namespace": "shop"}), declared))
+	})
+	s.Run("in the configured namespace without namespace argument", func() {
+		s.Equal("default", permissions(s.params(nil), declared)[0].Namespace)
+	})
+}
+
+func (s *ExtensionSuite) TestDefinitions() {
+	definitions := func(body string) ([]ToolDefinition, error) {
+		extension := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		}))
+		defer extension.Close()
+		return NewToolset(config.ExtensionConfig{Name: "acme", URL: extension.URL}, nil).definitions()
+	}
+	s.Run("returns the definitions", func() {
+		tools, err := definitions(`{"tools": [{"name": "tenants_list"}, {"name": "tenants.create-v2"}]}`)
+		s.Require().NoError(err)
+		s.Len(tools, 2)
+	})
+	s.Run("rejects the tools without name", func() {
+		_, err := definitions(`{"tools": [{"description": "nameless"}]}`)
+		s.EqualError(err, "tool without name")
+	})
+	s.Run("rejects the invalid names", func() {
+		_, err := definitions(`{"tools": [{"name": "tenants list"}]}`)
+		s.Require().Error(err)
+		s.Contains(err.Error(), `invalid tool name "tenants list"`)
+		_, err = definitions(`{"tools": [{"name": "` + strings.Repeat("a", 124) + `"}]}`)
+		s.Error(err, "the prefixed name exceeds 128 characters")
+	})
+	s.Run("rejects the duplicate names", func() {
+		_, err := definitions(`{"tools": [{"name": "tenants_list"}, {"name": "tenants_list"}]}`)
+		s.EqualError(err, `duplicate tool name "tenants_list"`)
+	})
+	s.Run("rejects the responses beyond the size limit", func() {
+		_, err := definitions(`{"tools": [{"name": "tenants_list", "description": "` + strings.Repeat("a", maxResponseBytes) + `"}]}`)
+		s.EqualError(err, "response larger than 16777216 bytes")
+	})
+}
+
+func TestExtension(t *testing.T) {
+	suite.Run(t, new(ExtensionSuite))
+}
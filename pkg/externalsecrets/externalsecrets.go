@@ -0,0 +1,145 @@
+package externalsecrets
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/crdstatus"
+)
+
+// ForceSyncAnnotation requests the operator to synchronize an ExternalSecret out of its refresh interval when its value changes
+const ForceSyncAnnotation = "force-sync"
+
+// Resource is the synchronization status summary of an External Secrets Operator resource.
+type Resource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Ready is the status of the Ready condition (True, False, or Unknown)
+	Ready  string `json:"ready"`
+	Reason string `json:"reason,omitempty"`
+	// Message is the message of the Ready condition, the last synchronization or validation error if not ready
+	Message string `json:"message,omitempty"`
+	// Store is the SecretStore or ClusterSecretStore (kind/name) of an ExternalSecret or ClusterExternalSecret
+	Store string `json:"store,omitempty"`
+	// StoreReady is the status of the Ready condition of the store, if accessible
+	StoreReady string `json:"storeReady,omitempty"`
+	// Target is the name of the Secret created by an ExternalSecret or by a ClusterExternalSecret in each namespace
+	Target          string `json:"target,omitempty"`
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+	// RefreshTime is the last time an ExternalSecret was synchronized with the provider
+	RefreshTime string `json:"refreshTime,omitempty"`
+	// Provider is the secret provider of a store (e.g. aws, vault, gcpsm)
+	Provider string `json:"provider,omitempty"`
+	// FailedNamespaces are the namespaces in which a ClusterExternalSecret failed to create its ExternalSecret, with the reason
+	FailedNamespaces []string `json:"failedNamespaces,omitempty"`
+}
+
+// ClusterScoped returns true for the cluster-scoped kinds (ClusterExternalSecret and ClusterSecretStore)
+func ClusterScoped(kind string) bool {
+	return kind == ClusterExternalSecretGK.Kind || kind == ClusterSecretStoreGK.Kind
+}
+
+// ListResources lists the External Secrets Operator resources of the provided GroupVersionResource in the namespace
+// (all namespaces if empty, must be empty for the cluster-scoped kinds)
+func ListResources(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace string) ([]Resource, error) {
+	list, err := client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]Resource, 0, len(list.Items))
+	for _, item := range list.Items {
+		resources = append(resources, Summarize(&item))
+	}
+	return resources, nil
+}
+
+// SortResources sorts the not ready resources first, then by kind, namespace, and name
+func SortResources(resources []Resource) {
+	crdstatus.Sort(resources, func(resource Resource) crdstatus.Key {
+		return crdstatus.Key{Rank: crdstatus.ReadyRank(resource.Ready, false), Kind: resource.Kind, Namespace: resource.Namespace, Name: resource.Name}
+	})
+}
+
+// LinkStores sets the readiness of their store on the ExternalSecrets and ClusterExternalSecrets, a secret that isn't
+// updating being often caused by a store that can't authenticate to its provider
+func LinkStores(resources []Resource, stores []Resource) {
+	ready := make(map[string]string, len(stores))
+	for _, store := range stores {
+		ready[store.Kind+"/"+store.Namespace+"/"+store.Name] = store.Ready
+	}
+	for i := range resources {
+		if resources[i].Store == "" {
+			continue
+		}
+		kind, name, _ := strings.Cut(resources[i].Store, "/")
+		namespace := resources[i].Namespace
+		if ClusterScoped(kind) {
+			namespace = ""
+		}
+		resources[i].StoreReady = ready[kind+"/"+namespace+"/"+name]
+	}
+}
+
+// Summarize returns the synchronization status summary of the provided External Secrets Operator resource
+func Summarize(obj *unstructured.Unstructured) Resource {
+	resource := Resource{Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName(), Ready: string(metav1.ConditionUnknown)}
+	if ready, found := crdstatus.FindCondition(obj.Object, "Ready"); found {
+		resource.Ready, resource.Reason, resource.Message = ready.Status, ready.Reason, ready.Message
+	}
+	switch obj.GetKind() {
+	case ExternalSecretGK.Kind:
+		summarizeSpec(&resource, obj.Object, "spec")
+		resource.Target = cmp.Or(crdstatus.NestedString(obj.Object, "spec", "target", "name"), obj.GetName())
+		resource.RefreshTime = crdstatus.NestedString(obj.Object, "status", "refreshTime")
+	case ClusterExternalSecretGK.Kind:
+		summarizeSpec(&resource, obj.Object, "spec", "externalSecretSpec")
+		resource.Target = cmp.Or(crdstatus.NestedString(obj.Object, "spec", "externalSecretSpec", "target", "name"),
+			crdstatus.NestedString(obj.Object, "spec", "externalSecretName"), obj.GetName())
+		failed, _, _ := unstructured.NestedSlice(obj.Object, "status", "failedNamespaces")
+		for _, f := range failed {
+			f, _ := f.(map[string]any)
+			resource.FailedNamespaces = append(resource.FailedNamespaces, fmt.Sprintf("%v: %v", f["namespace"], f["reason"]))
+		}
+	case SecretStoreGK.Kind, ClusterSecretStoreGK.Kind:
+		provider, _, _ := unstructured.NestedMap(obj.Object, "spec", "provider")
+		// The provider has a single field, named after the provider
+		if names := slices.Sorted(maps.Keys(provider)); len(names) > 0 {
+			resource.Provider = names[0]
+		}
+	}
+	return resource
+}
+
+// Refresh requests the operator to synchronize the ExternalSecret with its provider by setting the force-sync annotation
+func Refresh(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string, now time.Time) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"annotations": map[string]any{ForceSyncAnnotation: strconv.FormatInt(now.Unix(), 10)}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.Resource(gvr).Namespace(namespace).Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{})
+}
+
+// summarizeSpec sets the store and refresh interval of the ExternalSecret spec at the provided path
+func summarizeSpec(resource *Resource, object map[string]any, fields ...string) {
+	storeName := crdstatus.NestedString(object, append(slices.Clone(fields), "secretStoreRef", "name")...)
+	if storeName != "" {
+		resource.Store = cmp.Or(crdstatus.NestedString(object, append(slices.Clone(fields), "secretStoreRef", "kind")...), SecretStoreGK.Kind) + "/" + storeName
+	}
+	resource.RefreshInterval = crdstatus.NestedString(object, append(slices.Clone(fields), "refreshInterval")...)
+}
@@ -0,0 +1,115 @@
+package externalsecrets
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+var externalSecretGVR = schema.GroupVersionResource{Group: "external-secrets.io", Version: "v1", Resource: "externalsecrets"}
+
+type ExternalSecretsTestSuite struct {
+	suite.Suite
+}
+
+func externalSecret(name, ready, message string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "external-secrets.io/v1",
+		"kind":       "ExternalSecret",
+		"metadata":   map[string]any{"name": name, "namespace": "payments"},
+		"spec": map[string]any{
+			"refreshInterval": "1h",
+			"secretStoreRef":  map[string]any{"name": "vault"},
+			"target":          map[string]any{"name": name + "-credentials"},
+		},
+		"status": map[string]any{
+			"refreshTime": "2025-01-01T14:00:00Z",
+			"conditions":  []any{map[string]any{"type": "Ready", "status": ready, "reason": "SecretSyncedError", "message": message}},
+		},
+	}}
+}
+
+func (s *ExternalSecretsTestSuite) client(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		externalSecretGVR: "ExternalSecretList",
+	}, objects...)
+}
+
+func (s *ExternalSecretsTestSuite) TestListResources() {
+	resources, err := ListResources(s.T().Context(), s.client(
+		externalSecret("database", "True", "Secret was synced"),
+		externalSecret("api", "False", "could not get secret data from provider: permission denied"),
+	), externalSecretGVR, "payments")
+	s.Require().NoError(err)
+	SortResources(resources)
+	s.Require().Len(resources, 2)
+	s.Run("sorts not ready resources first", func() {
+		s.Equal("api", resources[0].Name)
+		s.Equal("could not get secret data from provider: permission denied", resources[0].Message)
+	})
+	s.Run("summarizes the synchronization", func() {
+		s.Equal(Resource{
+			Kind: "ExternalSecret", Namespace: "payments", Name: "database",
+			Ready: "True", Reason: "SecretSyncedError", Message: "Secret was synced",
+			Store: "SecretStore/vault", Target: "database-credentials", RefreshInterval: "1h", RefreshTime: "2025-01-01T14:00:00Z",
+		}, resources[1])
+	})
+}
+
+func (s *ExternalSecretsTestSuite) TestSummarize() {
+	s.Run("ClusterExternalSecret", func() {
+		resource := Summarize(&unstructured.Unstructured{Object: map[string]any{
+			"kind":     "ClusterExternalSecret",
+			"metadata": map[string]any{"name": "registry"},
+			"spec": map[string]any{
+				"externalSecretName": "registry-pull",
+				"externalSecretSpec": map[string]any{"secretStoreRef": map[string]any{"kind": "ClusterSecretStore", "name": "aws"}},
+			},
+			"status": map[string]any{"failedNamespaces": []any{map[string]any{"namespace": "legacy", "reason": "secret already exists"}}},
+		}})
+		s.Equal("ClusterSecretStore/aws", resource.Store)
+		s.Equal("registry-pull", resource.Target)
+		s.Equal("Unknown", resource.Ready)
+		s.Equal([]string{"legacy: secret already exists"}, resource.FailedNamespaces)
+	})
+	s.Run("SecretStore", func() {
+		resource := Summarize(&unstructured.Unstructured{Object: map[string]any{
+			"kind":     "SecretStore",
+			"metadata": map[string]any{"name": "vault", "namespace": "payments"},
+			"spec":     map[string]any{"provider": map[string]any{"vault": map[string]any{"server": "https://vault:8200"}}},
+		}})
+		s.Equal("vault", resource.Provider)
+	})
+}
+
+func (s *ExternalSecretsTestSuite) TestLinkStores() {
+	resources := []Resource{
+		{Kind: "ExternalSecret", Namespace: "payments", Name: "api", Store: "SecretStore/vault"},
+		{Kind: "ExternalSecret", Namespace: "orders", Name: "api", Store: "SecretStore/vault"},
+		{Kind: "ExternalSecret", Namespace: "orders", Name: "registry", Store: "ClusterSecretStore/aws"},
+	}
+	LinkStores(resources, []Resource{
+		{Kind: "SecretStore", Namespace: "payments", Name: "vault", Ready: "False"},
+		{Kind: "ClusterSecretStore", Name: "aws", Ready: "True"},
+	})
+	s.Equal("False", resources[0].StoreReady)
+	s.Empty(resources[1].StoreReady, "the SecretStore is in the namespace of the ExternalSecret")
+	s.Equal("True", resources[2].StoreReady)
+}
+
+func (s *ExternalSecretsTestSuite) TestRefresh() {
+	now := time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+	externalSecret, err := Refresh(s.T().Context(), s.client(externalSecret("api", "False", "")), externalSecretGVR, "payments", "api", now)
+	s.Require().NoError(err)
+	s.Equal(strconv.FormatInt(now.Unix(), 10), externalSecret.GetAnnotations()[ForceSyncAnnotation])
+}
+
+func TestExternalSecrets(t *testing.T) {
+	suite.Run(t, new(ExternalSecretsTestSuite))
+}
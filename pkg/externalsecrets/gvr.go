@@ -0,0 +1,43 @@
+package externalsecrets
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// External Secrets Operator resources
+var (
+	// ExternalSecretGK is the GroupKind for ExternalSecret resources
+	ExternalSecretGK = schema.GroupKind{Group: "external-secrets.io", Kind: "ExternalSecret"}
+
+	// ClusterExternalSecretGK is the GroupKind for ClusterExternalSecret resources
+	ClusterExternalSecretGK = schema.GroupKind{Group: "external-secrets.io", Kind: "ClusterExternalSecret"}
+
+	// SecretStoreGK is the GroupKind for SecretStore resources
+	SecretStoreGK = schema.GroupKind{Group: "external-secrets.io", Kind: "SecretStore"}
+
+	// ClusterSecretStoreGK is the GroupKind for ClusterSecretStore resources
+	ClusterSecretStoreGK = schema.GroupKind{Group: "external-secrets.io", Kind: "ClusterSecretStore"}
+)
+
+// SecretKinds are the kinds of the External Secrets Operator resources synchronizing secrets
+var SecretKinds = []string{ExternalSecretGK.Kind, ClusterExternalSecretGK.Kind}
+
+// StoreKinds are the kinds of the External Secrets Operator resources configuring the access to the secret providers
+var StoreKinds = []string{SecretStoreGK.Kind, ClusterSecretStoreGK.Kind}
+
+// ResourceFor returns the GroupVersionResource of the provided External Secrets Operator kind in the preferred version
+// served by the cluster (external-secrets.io/v1, or v1beta1 for the versions of the operator older than 0.17).
+func ResourceFor(mapper meta.RESTMapper, kind string) (schema.GroupVersionResource, error) {
+	if !slices.Contains(SecretKinds, kind) && !slices.Contains(StoreKinds, kind) {
+		return schema.GroupVersionResource{}, fmt.Errorf("unsupported External Secrets kind %s, supported kinds are %v", kind, slices.Concat(SecretKinds, StoreKinds))
+	}
+	mapping, err := mapper.RESTMapping(schema.GroupKind{Group: ExternalSecretGK.Group, Kind: kind})
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return mapping.Resource, nil
+}
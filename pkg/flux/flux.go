@@ -0,0 +1,139 @@
+package flux
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/crdstatus"
+)
+
+const (
+	// ReconcileRequestedAtAnnotation requests the Flux controllers to reconcile the resource out of its interval
+	ReconcileRequestedAtAnnotation = "reconcile.fluxcd.io/requestedAt"
+	// ReconcileForceAtAnnotation requests the helm-controller to force the upgrade of a HelmRelease (used together with requestedAt)
+	ReconcileForceAtAnnotation = "reconcile.fluxcd.io/forceAt"
+)
+
+// Resource is the readiness and revision summary of a Flux Kustomization or HelmRelease.
+type Resource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Ready is the status of the Ready condition (True, False, or Unknown)
+	Ready     string `json:"ready"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Suspended bool   `json:"suspended"`
+	Source    string `json:"source,omitempty"`
+	// LastAppliedRevision is the revision of the source (or the chart version) last applied successfully
+	LastAppliedRevision   string `json:"lastAppliedRevision,omitempty"`
+	LastAttemptedRevision string `json:"lastAttemptedRevision,omitempty"`
+	// LastHandledReconcileAt is the last reconcile request (requestedAt annotation) handled by the controller
+	LastHandledReconcileAt string `json:"lastHandledReconcileAt,omitempty"`
+}
+
+// ListResources lists the Flux resources of the provided GroupVersionResource in the namespace (all namespaces if empty)
+func ListResources(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace string) ([]Resource, error) {
+	list, err := client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]Resource, 0, len(list.Items))
+	for _, item := range list.Items {
+		resources = append(resources, Summarize(&item))
+	}
+	return resources, nil
+}
+
+// SortResources sorts the not ready resources first, then by kind, namespace, and name
+func SortResources(resources []Resource) {
+	crdstatus.Sort(resources, func(resource Resource) crdstatus.Key {
+		return crdstatus.Key{Rank: crdstatus.ReadyRank(resource.Ready, resource.Suspended), Kind: resource.Kind, Namespace: resource.Namespace, Name: resource.Name}
+	})
+}
+
+// Reconcile requests the Flux controllers to reconcile the resource by setting the reconcile.fluxcd.io/requestedAt
+// annotation, the same way the flux reconcile command does. When force is true, a HelmRelease upgrade is forced.
+func Reconcile(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string, force bool, now time.Time) (*unstructured.Unstructured, error) {
+	requestedAt := now.Format(time.RFC3339Nano)
+	annotations := map[string]any{ReconcileRequestedAtAnnotation: requestedAt}
+	if force {
+		annotations[ReconcileForceAtAnnotation] = requestedAt
+	}
+	return patch(ctx, client, gvr, namespace, name, map[string]any{"metadata": map[string]any{"annotations": annotations}})
+}
+
+// SetSuspended suspends or resumes the reconciliation of the resource, a reconcile is requested when resuming
+func SetSuspended(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string, suspend bool, now time.Time) (*unstructured.Unstructured, error) {
+	mergePatch := map[string]any{"spec": map[string]any{"suspend": suspend}}
+	if !suspend {
+		mergePatch["metadata"] = map[string]any{"annotations": map[string]any{ReconcileRequestedAtAnnotation: now.Format(time.RFC3339Nano)}}
+	}
+	return patch(ctx, client, gvr, namespace, name, mergePatch)
+}
+
+func patch(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string, mergePatch map[string]any) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(mergePatch)
+	if err != nil {
+		return nil, err
+	}
+	return client.Resource(gvr).Namespace(namespace).Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{})
+}
+
+// Summarize returns the readiness and revision summary of the provided Flux Kustomization or HelmRelease
+func Summarize(obj *unstructured.Unstructured) Resource {
+	resource := Resource{Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName(), Ready: string(metav1.ConditionUnknown)}
+	resource.Suspended, _, _ = unstructured.NestedBool(obj.Object, "spec", "suspend")
+	if ready, found := crdstatus.FindCondition(obj.Object, "Ready"); found {
+		resource.Ready, resource.Reason, resource.Message = ready.Status, ready.Reason, ready.Message
+	}
+	resource.LastAppliedRevision = crdstatus.NestedString(obj.Object, "status", "lastAppliedRevision")
+	resource.LastAttemptedRevision = crdstatus.NestedString(obj.Object, "status", "lastAttemptedRevision")
+	resource.LastHandledReconcileAt = crdstatus.NestedString(obj.Object, "status", "lastHandledReconcileAt")
+	switch resource.Kind {
+	case KustomizationGK.Kind:
+		resource.Source = sourceRef(obj.Object, "spec", "sourceRef")
+		if path, _, _ := unstructured.NestedString(obj.Object, "spec", "path"); path != "" {
+			resource.Source += " " + path
+		}
+	case HelmReleaseGK.Kind:
+		if chartRef := sourceRef(obj.Object, "spec", "chartRef"); chartRef != "" {
+			resource.Source = chartRef
+		} else {
+			chart, _, _ := unstructured.NestedString(obj.Object, "spec", "chart", "spec", "chart")
+			version, _, _ := unstructured.NestedString(obj.Object, "spec", "chart", "spec", "version")
+			resource.Source = sourceRef(obj.Object, "spec", "chart", "spec", "sourceRef") + " chart " + chart
+			if version != "" {
+				resource.Source += "@" + version
+			}
+		}
+		// helm.toolkit.fluxcd.io/v2 records the releases in the history instead of lastAppliedRevision
+		if history, _, _ := unstructured.NestedSlice(obj.Object, "status", "history"); resource.LastAppliedRevision == "" && len(history) > 0 {
+			latest, _ := history[0].(map[string]any)
+			if latest["status"] == "deployed" || latest["status"] == "superseded" {
+				resource.LastAppliedRevision = fmt.Sprintf("%v@%v", latest["chartName"], latest["chartVersion"])
+			}
+		}
+	}
+	return resource
+}
+
+func sourceRef(object map[string]any, fields ...string) string {
+	ref, found, _ := unstructured.NestedStringMap(object, fields...)
+	if !found || ref["name"] == "" {
+		return ""
+	}
+	source := ref["kind"] + "/"
+	if ref["namespace"] != "" {
+		source += ref["namespace"] + "/"
+	}
+	return source + ref["name"]
+}
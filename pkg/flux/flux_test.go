@@ -0,0 +1,131 @@
+package flux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+var kustomizations = schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}
+
+type FluxTestSuite struct {
+	suite.Suite
+}
+
+func kustomization(name string, ready string, suspend bool) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+		"kind":       "Kustomization",
+		"metadata":   map[string]any{"name": name, "namespace": "flux-system"},
+		"spec": map[string]any{
+			"path":      "./apps/" + name,
+			"suspend":   suspend,
+			"sourceRef": map[string]any{"kind": "GitRepository", "name": "flux-system"},
+		},
+		"status": map[string]any{
+			"lastAppliedRevision":   "main@sha1:abc123",
+			"lastAttemptedRevision": "main@sha1:def456",
+			"conditions": []any{
+				map[string]any{"type": "Reconciling", "status": "False"},
+				map[string]any{"type": "Ready", "status": ready, "reason": "ReconciliationFailed", "message": "kustomize build failed"},
+			},
+		},
+	}}
+}
+
+func (s *FluxTestSuite) client(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{kustomizations: "KustomizationList"}, objects...)
+}
+
+func (s *FluxTestSuite) TestListResources() {
+	resources, err := ListResources(s.T().Context(), s.client(
+		kustomization("web", "True", false),
+		kustomization("api", "False", false),
+		kustomization("db", "True", true),
+	), kustomizations, "")
+	s.Require().NoError(err)
+	SortResources(resources)
+	s.Run("sorts not ready and suspended resources first", func() {
+		s.Require().Len(resources, 3)
+		s.Equal("api", resources[0].Name)
+		s.Equal("db", resources[1].Name)
+		s.Equal("web", resources[2].Name)
+	})
+	s.Run("summarizes the Ready condition and revisions", func() {
+		s.Equal(Resource{
+			Kind: "Kustomization", Namespace: "flux-system", Name: "api",
+			Ready: "False", Reason: "ReconciliationFailed", Message: "kustomize build failed",
+			Source:              "GitRepository/flux-system ./apps/api",
+			LastAppliedRevision: "main@sha1:abc123", LastAttemptedRevision: "main@sha1:def456",
+		}, resources[0])
+	})
+}
+
+func (s *FluxTestSuite) TestSummarizeHelmRelease() {
+	s.Run("chart template with history (v2)", func() {
+		resource := Summarize(&unstructured.Unstructured{Object: map[string]any{
+			"kind":     "HelmRelease",
+			"metadata": map[string]any{"name": "podinfo", "namespace": "apps"},
+			"spec": map[string]any{"chart": map[string]any{"spec": map[string]any{
+				"chart": "podinfo", "version": "6.x", "sourceRef": map[string]any{"kind": "HelmRepository", "name": "podinfo", "namespace": "flux-system"},
+			}}},
+			"status": map[string]any{"history": []any{
+				map[string]any{"chartName": "podinfo", "chartVersion": "6.5.4", "status": "deployed"},
+				map[string]any{"chartName": "podinfo", "chartVersion": "6.5.3", "status": "superseded"},
+			}},
+		}})
+		s.Equal("HelmRepository/flux-system/podinfo chart podinfo@6.x", resource.Source)
+		s.Equal("podinfo@6.5.4", resource.LastAppliedRevision)
+		s.Equal("Unknown", resource.Ready)
+	})
+	s.Run("chart reference", func() {
+		resource := Summarize(&unstructured.Unstructured{Object: map[string]any{
+			"kind":     "HelmRelease",
+			"metadata": map[string]any{"name": "podinfo", "namespace": "apps"},
+			"spec":     map[string]any{"chartRef": map[string]any{"kind": "OCIRepository", "name": "podinfo"}},
+			"status":   map[string]any{"history": []any{map[string]any{"chartName": "podinfo", "chartVersion": "6.5.4", "status": "failed"}}},
+		}})
+		s.Equal("OCIRepository/podinfo", resource.Source)
+		s.Empty(resource.LastAppliedRevision)
+	})
+}
+
+func (s *FluxTestSuite) TestReconcile() {
+	now := time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC)
+	s.Run("sets the requestedAt annotation", func() {
+		resource, err := Reconcile(s.T().Context(), s.client(kustomization("web", "True", false)), kustomizations, "flux-system", "web", false, now)
+		s.Require().NoError(err)
+		s.Equal(map[string]string{ReconcileRequestedAtAnnotation: "2025-01-01T14:00:00Z"}, resource.GetAnnotations())
+	})
+	s.Run("sets the forceAt annotation when forced", func() {
+		resource, err := Reconcile(s.T().Context(), s.client(kustomization("web", "True", false)), kustomizations, "flux-system", "web", true, now)
+		s.Require().NoError(err)
+		s.Equal("2025-01-01T14:00:00Z", resource.GetAnnotations()[ReconcileForceAtAnnotation])
+	})
+}
+
+func (s *FluxTestSuite) TestSetSuspended() {
+	now := time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC)
+	s.Run("suspends", func() {
+		resource, err := SetSuspended(s.T().Context(), s.client(kustomization("web", "True", false)), kustomizations, "flux-system", "web", true, now)
+		s.Require().NoError(err)
+		s.True(Summarize(resource).Suspended)
+		s.Empty(resource.GetAnnotations())
+	})
+	s.Run("resumes and requests a reconciliation", func() {
+		resource, err := SetSuspended(s.T().Context(), s.client(kustomization("web", "True", true)), kustomizations, "flux-system", "web", false, now)
+		s.Require().NoError(err)
+		s.False(Summarize(resource).Suspended)
+		s.Equal("2025-01-01T14:00:00Z", resource.GetAnnotations()[ReconcileRequestedAtAnnotation])
+	})
+}
+
+func TestFlux(t *testing.T) {
+	suite.Run(t, new(FluxTestSuite))
+}
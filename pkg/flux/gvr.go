@@ -0,0 +1,39 @@
+package flux
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Flux resources
+var (
+	// KustomizationGK is the GroupKind for Kustomization resources
+	KustomizationGK = schema.GroupKind{Group: "kustomize.toolkit.fluxcd.io", Kind: "Kustomization"}
+
+	// HelmReleaseGK is the GroupKind for HelmRelease resources
+	HelmReleaseGK = schema.GroupKind{Group: "helm.toolkit.fluxcd.io", Kind: "HelmRelease"}
+)
+
+// Kinds are the kinds of the Flux resources supported by the flux toolset
+var Kinds = []string{KustomizationGK.Kind, HelmReleaseGK.Kind}
+
+// ResourceFor returns the GroupVersionResource of the provided Flux kind in the preferred version served by the cluster
+// (e.g. helm.toolkit.fluxcd.io/v2 or v2beta2 for HelmReleases, depending on the installed Flux version).
+func ResourceFor(mapper meta.RESTMapper, kind string) (schema.GroupVersionResource, error) {
+	var groupKind schema.GroupKind
+	switch kind {
+	case KustomizationGK.Kind:
+		groupKind = KustomizationGK
+	case HelmReleaseGK.Kind:
+		groupKind = HelmReleaseGK
+	default:
+		return schema.GroupVersionResource{}, fmt.Errorf("unsupported Flux kind %s, supported kinds are %v", kind, Kinds)
+	}
+	mapping, err := mapper.RESTMapping(groupKind)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return mapping.Resource, nil
+}
@@ -0,0 +1,82 @@
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"k8s.io/client-go/rest"
+)
+
+// actionConfigTTL is the duration an action configuration is reused, it bounds the staleness of the capabilities
+// (Kubernetes version and APIs) the configuration caches
+const actionConfigTTL = 5 * time.Minute
+
+// actionConfigs are the action configurations shared by the calls of the same identity and namespace, so that bursts
+// of calls don't rebuild the Kubernetes client and the release storage backend on every call
+var actionConfigs = newActionConfigPool()
+
+type actionConfigPool struct {
+	mu      sync.Mutex
+	configs map[string]*pooledActionConfig
+	now     func() time.Time
+}
+
+type pooledActionConfig struct {
+	once    sync.Once
+	config  *action.Configuration
+	err     error
+	created time.Time
+}
+
+func newActionConfigPool() *actionConfigPool {
+	return &actionConfigPool{configs: make(map[string]*pooledActionConfig), now: time.Now}
+}
+
+// actionConfigKey identifies the configurations of the same credentials and namespace without keeping the credentials as is
+func actionConfigKey(cfg *rest.Config, namespace string) string {
+	hash := sha256.New()
+	for _, part := range []string{
+		cfg.Host, cfg.BearerToken, cfg.BearerTokenFile, cfg.Username, cfg.CertFile, string(cfg.CertData), cfg.UserAgent,
+		cfg.Impersonate.UserName, strings.Join(cfg.Impersonate.Groups, ","), fmt.Sprintf("%p", cfg.ExecProvider),
+		fmt.Sprintf("%p", cfg.AuthProvider), namespace,
+	} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// get returns the configuration of the key, initialized by newConfig on its first use. Concurrent calls of the same key
+// wait for the same initialization, the configurations that failed to initialize are not kept.
+func (p *actionConfigPool) get(key string, newConfig func() (*action.Configuration, error)) (*action.Configuration, error) {
+	p.mu.Lock()
+	now := p.now()
+	for k, pooled := range p.configs {
+		if now.Sub(pooled.created) > actionConfigTTL {
+			delete(p.configs, k)
+		}
+	}
+	pooled, ok := p.configs[key]
+	if !ok {
+		pooled = &pooledActionConfig{created: now}
+		p.configs[key] = pooled
+	}
+	p.mu.Unlock()
+	pooled.once.Do(func() {
+		pooled.config, pooled.err = newConfig()
+	})
+	if pooled.err != nil {
+		p.mu.Lock()
+		if p.configs[key] == pooled {
+			delete(p.configs, key)
+		}
+		p.mu.Unlock()
+		return nil, pooled.err
+	}
+	return pooled.config, nil
+}
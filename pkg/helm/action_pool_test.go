@@ -0,0 +1,90 @@
+package helm
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"helm.sh/helm/v3/pkg/action"
+	"k8s.io/client-go/rest"
+)
+
+type ActionPoolTestSuite struct {
+	suite.Suite
+	now     time.Time
+	pool    *actionConfigPool
+	created atomic.Int32
+}
+
+func (s *ActionPoolTestSuite) SetupTest() {
+	s.now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.pool = &actionConfigPool{configs: map[string]*pooledActionConfig{}, now: func() time.Time { return s.now }}
+	s.created.Store(0)
+}
+
+func (s *ActionPoolTestSuite) newConfig() (*action.Configuration, error) {
+	s.created.Add(1)
+	return new(action.Configuration), nil
+}
+
+func (s *ActionPoolTestSuite) TestGet() {
+	first, err := s.pool.get("identity-a/default", s.newConfig)
+	s.Require().NoError(err)
+	s.Run("reuses the configuration of the same identity and namespace", func() {
+		again, err := s.pool.get("identity-a/default", s.newConfig)
+		s.Require().NoError(err)
+		s.Same(first, again)
+		s.Equal(int32(1), s.created.Load())
+	})
+	s.Run("creates a configuration per identity and namespace", func() {
+		other, err := s.pool.get("identity-a/other", s.newConfig)
+		s.Require().NoError(err)
+		s.NotSame(first, other)
+	})
+	s.Run("recreates the configurations older than the TTL", func() {
+		s.now = s.now.Add(actionConfigTTL + time.Second)
+		again, err := s.pool.get("identity-a/default", s.newConfig)
+		s.Require().NoError(err)
+		s.NotSame(first, again)
+		s.Len(s.pool.configs, 1, "expected the expired configurations to be dropped")
+	})
+	s.Run("does not keep the configurations that failed to initialize", func() {
+		_, err := s.pool.get("identity-b/default", func() (*action.Configuration, error) { return nil, errors.New("boom") })
+		s.Require().Error(err)
+		s.NotContains(s.pool.configs, "identity-b/default")
+	})
+}
+
+func (s *ActionPoolTestSuite) TestConcurrentInitialization() {
+	var wg sync.WaitGroup
+	configs := make([]*action.Configuration, 10)
+	for i := range configs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			configs[i], _ = s.pool.get("identity-a/default", func() (*action.Configuration, error) {
+				time.Sleep(10 * time.Millisecond)
+				return s.newConfig()
+			})
+		}()
+	}
+	wg.Wait()
+	s.Equal(int32(1), s.created.Load(), "expected the configuration to be initialized once")
+	for _, config := range configs {
+		s.Same(configs[0], config)
+	}
+}
+
+func (s *ActionPoolTestSuite) TestKey() {
+	cfg := &rest.Config{Host: "https://cluster", BearerToken: "secret-token"}
+	s.NotContains(actionConfigKey(cfg, "default"), "secret-token")
+	s.NotEqual(actionConfigKey(cfg, "default"), actionConfigKey(cfg, "other"))
+	s.NotEqual(actionConfigKey(cfg, "default"), actionConfigKey(&rest.Config{Host: "https://cluster", BearerToken: "other-token"}, "default"))
+}
+
+func TestActionPool(t *testing.T) {
+	suite.Run(t, new(ActionPoolTestSuite))
+}
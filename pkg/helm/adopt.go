@@ -0,0 +1,99 @@
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// The ownership metadata Helm requires on the existing resources of a chart to install or upgrade its release
+const (
+	ManagedByLabel             = "app.kubernetes.io/managed-by"
+	ManagedByHelm              = "Helm"
+	ReleaseNameAnnotation      = "meta.helm.sh/release-name"
+	ReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+const (
+	adopted        = "adopted"
+	alreadyAdopted = "already adopted"
+)
+
+// AdoptedResource is a resource of the cluster to adopt into a release
+type AdoptedResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	// Status is adopted, or already adopted if the resource had the ownership metadata of the release
+	Status string `json:"status,omitempty"`
+}
+
+// Adopt sets the ownership metadata of the release on the resources (created outside Helm, e.g. with kubectl apply),
+// so that installing or upgrading the release with a chart rendering them takes them over instead of failing because
+// they already exist. Namespaced resources default to the namespace of the release. No resource is modified if any
+// of them doesn't exist or is owned by another release.
+func Adopt(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, release, releaseNamespace string, resources []AdoptedResource) ([]AdoptedResource, error) {
+	if err := ValidateReleaseName(release); err != nil {
+		return nil, err
+	}
+	gvrs := make([]schema.GroupVersionResource, len(resources))
+	ret := make([]AdoptedResource, len(resources))
+	for i, resource := range resources {
+		gv, err := schema.ParseGroupVersion(resource.APIVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid apiVersion %q of %s %s: %w", resource.APIVersion, resource.Kind, resource.Name, err)
+		}
+		mapping, err := mapper.RESTMapping(gv.WithKind(resource.Kind).GroupKind(), gv.Version)
+		if err != nil {
+			return nil, err
+		}
+		gvrs[i] = mapping.Resource
+		ret[i] = resource
+		ret[i].Namespace = ""
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			ret[i].Namespace = resource.Namespace
+			if ret[i].Namespace == "" {
+				ret[i].Namespace = releaseNamespace
+			}
+		}
+		obj, err := client.Resource(gvrs[i]).Namespace(ret[i].Namespace).Get(ctx, resource.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s %s: %w", resource.Kind, resource.Name, err)
+		}
+		annotations := obj.GetAnnotations()
+		owner, ownerNamespace := annotations[ReleaseNameAnnotation], annotations[ReleaseNamespaceAnnotation]
+		switch {
+		case owner == "" && ownerNamespace == "":
+			ret[i].Status = adopted
+		case owner == release && ownerNamespace == releaseNamespace && obj.GetLabels()[ManagedByLabel] == ManagedByHelm:
+			ret[i].Status = alreadyAdopted
+		case owner == release && ownerNamespace == releaseNamespace:
+			ret[i].Status = adopted
+		default:
+			return nil, fmt.Errorf("%s %s is owned by release %s/%s", resource.Kind, resource.Name, ownerNamespace, owner)
+		}
+	}
+	mergePatch, err := json.Marshal(map[string]any{"metadata": map[string]any{
+		"labels":      map[string]any{ManagedByLabel: ManagedByHelm},
+		"annotations": map[string]any{ReleaseNameAnnotation: release, ReleaseNamespaceAnnotation: releaseNamespace},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	for i, resource := range ret {
+		if resource.Status != adopted {
+			continue
+		}
+		if _, err = client.Resource(gvrs[i]).Namespace(resource.Namespace).Patch(ctx, resource.Name, types.MergePatchType, mergePatch, metav1.PatchOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to adopt %s %s: %w", resource.Kind, resource.Name, err)
+		}
+	}
+	return ret, nil
+}
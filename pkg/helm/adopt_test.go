@@ -0,0 +1,120 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+var (
+	configMaps   = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	clusterRoles = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}
+)
+
+type AdoptTestSuite struct {
+	suite.Suite
+	mapper meta.RESTMapper
+}
+
+func (s *AdoptTestSuite) SetupTest() {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+	s.mapper = mapper
+}
+
+func object(apiVersion, kind, namespace, name string, annotations map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func (s *AdoptTestSuite) client(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		configMaps:   "ConfigMapList",
+		clusterRoles: "ClusterRoleList",
+	}, objects...)
+}
+
+func (s *AdoptTestSuite) TestAdopt() {
+	client := s.client(
+		object("v1", "ConfigMap", "apps", "config", nil),
+		object("rbac.authorization.k8s.io/v1", "ClusterRole", "", "reader", nil),
+	)
+	adopted, err := Adopt(s.T().Context(), client, s.mapper, "app", "apps", []AdoptedResource{
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "config"},
+		{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "reader", Namespace: "ignored"},
+	})
+	s.Require().NoError(err)
+	s.Run("returns the adopted resources", func() {
+		s.Equal([]AdoptedResource{
+			{APIVersion: "v1", Kind: "ConfigMap", Name: "config", Namespace: "apps", Status: "adopted"},
+			{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "reader", Status: "adopted"},
+		}, adopted)
+	})
+	s.Run("sets the ownership metadata of the release", func() {
+		cm, err := client.Resource(configMaps).Namespace("apps").Get(s.T().Context(), "config", metav1.GetOptions{})
+		s.Require().NoError(err)
+		s.Equal("Helm", cm.GetLabels()["app.kubernetes.io/managed-by"])
+		s.Equal("app", cm.GetAnnotations()["meta.helm.sh/release-name"])
+		s.Equal("apps", cm.GetAnnotations()["meta.helm.sh/release-namespace"])
+		cr, err := client.Resource(clusterRoles).Get(s.T().Context(), "reader", metav1.GetOptions{})
+		s.Require().NoError(err)
+		s.Equal("app", cr.GetAnnotations()["meta.helm.sh/release-name"])
+	})
+	s.Run("reports the resources already adopted", func() {
+		adopted, err = Adopt(s.T().Context(), client, s.mapper, "app", "apps", []AdoptedResource{
+			{APIVersion: "v1", Kind: "ConfigMap", Name: "config", Namespace: "apps"},
+		})
+		s.Require().NoError(err)
+		s.Equal("already adopted", adopted[0].Status)
+	})
+}
+
+func (s *AdoptTestSuite) TestAdoptOwnedByAnotherRelease() {
+	client := s.client(
+		object("v1", "ConfigMap", "apps", "free", nil),
+		object("v1", "ConfigMap", "apps", "owned", map[string]string{
+			"meta.helm.sh/release-name":      "other",
+			"meta.helm.sh/release-namespace": "apps",
+		}),
+	)
+	_, err := Adopt(s.T().Context(), client, s.mapper, "app", "apps", []AdoptedResource{
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "free"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "owned"},
+	})
+	s.Run("returns the owner", func() {
+		s.EqualError(err, "ConfigMap owned is owned by release apps/other")
+	})
+	s.Run("does not modify any resource", func() {
+		cm, err := client.Resource(configMaps).Namespace("apps").Get(s.T().Context(), "free", metav1.GetOptions{})
+		s.Require().NoError(err)
+		s.Empty(cm.GetAnnotations())
+	})
+}
+
+func (s *AdoptTestSuite) TestAdoptNotFound() {
+	_, err := Adopt(s.T().Context(), s.client(), s.mapper, "app", "apps", []AdoptedResource{
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "missing"},
+	})
+	s.ErrorContains(err, "failed to get ConfigMap missing")
+}
+
+func (s *AdoptTestSuite) TestAdoptInvalidReleaseName() {
+	_, err := Adopt(s.T().Context(), s.client(), s.mapper, "Invalid_Name", "apps", nil)
+	s.ErrorContains(err, `invalid release name "Invalid_Name"`)
+}
+
+func TestAdopt(t *testing.T) {
+	suite.Run(t, new(AdoptTestSuite))
+}
@@ -0,0 +1,192 @@
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+const (
+	// defaultChartCacheChartTTL is the duration a cached chart is served before being downloaded again
+	defaultChartCacheChartTTL = 24 * time.Hour
+	// defaultChartCacheIndexTTL is the duration a cached repository index is served before being downloaded again
+	defaultChartCacheIndexTTL = 10 * time.Minute
+)
+
+// ChartCacheConfig enables the local store of the downloaded chart archives and repository indexes (index.yaml).
+// The cached copies are served while fresher than their TTL, and past it if downloading them again fails.
+type ChartCacheConfig struct {
+	// Dir is the directory of the store (Optional, the helm directory of the user cache directory if not provided)
+	Dir string `toml:"dir,omitempty"`
+	// ChartTTL is the duration a cached chart is served before being downloaded again (Optional, 24h if not provided)
+	ChartTTL string `toml:"chart_ttl,omitempty"`
+	// IndexTTL is the duration a cached repository index is served before being downloaded again (Optional, 10m if not provided)
+	IndexTTL string `toml:"index_ttl,omitempty"`
+	// Offline serves only the cached charts and indexes, whatever their age, and never downloads them (air-gapped deployments)
+	Offline bool `toml:"offline,omitempty"`
+}
+
+func (c *ChartCacheConfig) Validate() error {
+	if c == nil {
+		return errors.New("cache config is nil")
+	}
+	for name, ttl := range map[string]string{"chart_ttl": c.ChartTTL, "index_ttl": c.IndexTTL} {
+		if ttl == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(ttl); err != nil || d <= 0 {
+			return fmt.Errorf("cache %s must be a positive duration (e.g. 24h): %s", name, ttl)
+		}
+	}
+	return nil
+}
+
+// ResolvePaths resolves the relative dir against the configuration directory
+func (c *ChartCacheConfig) ResolvePaths(configDir string) {
+	if configDir != "" && c.Dir != "" && !filepath.IsAbs(c.Dir) {
+		c.Dir = filepath.Join(configDir, c.Dir)
+	}
+}
+
+func (c *ChartCacheConfig) dir() string {
+	if c.Dir != "" {
+		return c.Dir
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, version.BinaryName, "helm")
+}
+
+func (c *ChartCacheConfig) chartTTL() time.Duration {
+	if ttl, err := time.ParseDuration(c.ChartTTL); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultChartCacheChartTTL
+}
+
+func (c *ChartCacheConfig) indexTTL() time.Duration {
+	if ttl, err := time.ParseDuration(c.IndexTTL); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultChartCacheIndexTTL
+}
+
+// chartCache is a content-addressed store: blobs/sha256/<digest> hold the downloaded contents, and refs/<hash> map each
+// reference (chart or index URL) to the digest of its content and the time it was downloaded
+type chartCache struct {
+	config *ChartCacheConfig
+	dir    string
+	now    func() time.Time
+}
+
+type chartCacheRef struct {
+	Reference  string    `json:"reference"`
+	Digest     string    `json:"digest"`
+	Downloaded time.Time `json:"downloaded"`
+}
+
+func newChartCache(config *ChartCacheConfig) *chartCache {
+	return &chartCache{config: config, dir: config.dir(), now: time.Now}
+}
+
+// get returns the cached content of the reference while fresher than the ttl, and downloads it with fetch otherwise.
+// The stale content is served if the download fails, and in offline mode whatever its age.
+func (c *chartCache) get(reference string, ttl time.Duration, fetch func() ([]byte, error)) ([]byte, error) {
+	ref, data, err := c.load(reference)
+	cached := err == nil
+	if cached && (c.config.Offline || c.now().Sub(ref.Downloaded) < ttl) {
+		return data, nil
+	}
+	if c.config.Offline {
+		return nil, fmt.Errorf("%s is not available in the offline helm cache", reference)
+	}
+	fresh, err := fetch()
+	if err != nil {
+		if cached {
+			klog.V(1).Infof("Serving the cached %s (downloaded %s) after failing to download it: %v", reference, ref.Downloaded, err)
+			return data, nil
+		}
+		return nil, err
+	}
+	if err = c.store(reference, fresh); err != nil {
+		klog.V(1).Infof("Failed to cache %s: %v", reference, err)
+	}
+	return fresh, nil
+}
+
+func (c *chartCache) refPath(reference string) string {
+	hash := sha256.Sum256([]byte(reference))
+	return filepath.Join(c.dir, "refs", hex.EncodeToString(hash[:])+".json")
+}
+
+func (c *chartCache) blobPath(digest string) string {
+	return filepath.Join(c.dir, "blobs", "sha256", digest)
+}
+
+// load returns the reference and its content, the content not matching its digest (e.g. partially written) is ignored
+func (c *chartCache) load(reference string) (*chartCacheRef, []byte, error) {
+	raw, err := os.ReadFile(c.refPath(reference))
+	if err != nil {
+		return nil, nil, err
+	}
+	ref := &chartCacheRef{}
+	if err = json.Unmarshal(raw, ref); err != nil {
+		return nil, nil, err
+	}
+	if ref.Reference != reference {
+		return nil, nil, fmt.Errorf("cached reference mismatch: %s", ref.Reference)
+	}
+	data, err := os.ReadFile(c.blobPath(ref.Digest))
+	if err != nil {
+		return nil, nil, err
+	}
+	if digest := sha256.Sum256(data); hex.EncodeToString(digest[:]) != ref.Digest {
+		return nil, nil, fmt.Errorf("cached content of %s doesn't match its digest", reference)
+	}
+	return ref, data, nil
+}
+
+func (c *chartCache) store(reference string, data []byte) error {
+	digest := sha256.Sum256(data)
+	ref := chartCacheRef{Reference: reference, Digest: hex.EncodeToString(digest[:]), Downloaded: c.now()}
+	if err := writeFileAtomically(c.blobPath(ref.Digest), data); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(ref)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(c.refPath(reference), raw)
+}
+
+// writeFileAtomically writes the file through a temporary file renamed into place, so that concurrent readers never
+// see it partially written
+func writeFileAtomically(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
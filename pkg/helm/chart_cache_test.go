@@ -0,0 +1,107 @@
+package helm
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ChartCacheTestSuite struct {
+	suite.Suite
+	now       time.Time
+	cache     *chartCache
+	downloads int
+}
+
+func (s *ChartCacheTestSuite) SetupTest() {
+	s.now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.cache = &chartCache{config: &ChartCacheConfig{}, dir: s.T().TempDir(), now: func() time.Time { return s.now }}
+	s.downloads = 0
+}
+
+func (s *ChartCacheTestSuite) download(content string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		s.downloads++
+		return []byte(content), nil
+	}
+}
+
+func failure() ([]byte, error) {
+	return nil, errors.New("registry unavailable")
+}
+
+func (s *ChartCacheTestSuite) TestGet() {
+	data, err := s.cache.get("oci://registry/app", time.Hour, s.download("v1"))
+	s.Require().NoError(err)
+	s.Equal("v1", string(data))
+	s.Run("serves the cached content while fresh", func() {
+		s.now = s.now.Add(30 * time.Minute)
+		data, err := s.cache.get("oci://registry/app", time.Hour, s.download("v2"))
+		s.Require().NoError(err)
+		s.Equal("v1", string(data))
+		s.Equal(1, s.downloads)
+	})
+	s.Run("downloads the stale content again", func() {
+		s.now = s.now.Add(time.Hour)
+		data, err := s.cache.get("oci://registry/app", time.Hour, s.download("v2"))
+		s.Require().NoError(err)
+		s.Equal("v2", string(data))
+		s.Equal(2, s.downloads)
+	})
+	s.Run("serves the stale content if the download fails", func() {
+		s.now = s.now.Add(2 * time.Hour)
+		data, err := s.cache.get("oci://registry/app", time.Hour, failure)
+		s.Require().NoError(err)
+		s.Equal("v2", string(data))
+	})
+	s.Run("fails if the download of uncached content fails", func() {
+		_, err := s.cache.get("oci://registry/db", time.Hour, failure)
+		s.EqualError(err, "registry unavailable")
+	})
+}
+
+func (s *ChartCacheTestSuite) TestOffline() {
+	_, err := s.cache.get("s3://charts/app-1.0.0.tgz", time.Hour, s.download("app"))
+	s.Require().NoError(err)
+	s.cache.config.Offline = true
+	s.Run("serves the cached content whatever its age", func() {
+		s.now = s.now.Add(365 * 24 * time.Hour)
+		data, err := s.cache.get("s3://charts/app-1.0.0.tgz", time.Hour, s.download("other"))
+		s.Require().NoError(err)
+		s.Equal("app", string(data))
+		s.Equal(1, s.downloads)
+	})
+	s.Run("fails without downloading the uncached content", func() {
+		_, err := s.cache.get("s3://charts/db-1.0.0.tgz", time.Hour, s.download("db"))
+		s.EqualError(err, "s3://charts/db-1.0.0.tgz is not available in the offline helm cache")
+		s.Equal(1, s.downloads)
+	})
+}
+
+func (s *ChartCacheTestSuite) TestContentAddressed() {
+	_, err := s.cache.get("oci://registry/a", time.Hour, s.download("same"))
+	s.Require().NoError(err)
+	_, err = s.cache.get("oci://registry/b", time.Hour, s.download("same"))
+	s.Require().NoError(err)
+	s.Run("stores the same content once", func() {
+		blobs, err := os.ReadDir(s.cache.blobPath(""))
+		s.Require().NoError(err)
+		s.Len(blobs, 1)
+	})
+	s.Run("ignores the content not matching its digest", func() {
+		ref, _, err := s.cache.load("oci://registry/a")
+		s.Require().NoError(err)
+		s.Require().NoError(os.WriteFile(s.cache.blobPath(ref.Digest), []byte("corrupted"), 0o600))
+		data, err := s.cache.get("oci://registry/a", time.Hour, s.download("same"))
+		s.Require().NoError(err)
+		s.Equal("same", string(data))
+		s.Equal(3, s.downloads)
+	})
+}
+
+func TestChartCache(t *testing.T) {
+	suite.Run(t, new(ChartCacheTestSuite))
+}
@@ -0,0 +1,160 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/release"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// maxDriftedFields is the number of modified fields reported for each drifted resource
+const maxDriftedFields = 10
+
+// Drift is a resource of a release modified outside Helm since its last revision was applied
+type Drift struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// Reason is why the resource is drifted: modified, deleted, or created outside Helm
+	Reason string `json:"reason"`
+	// Fields are the paths of the fields of the last revision modified in the cluster
+	Fields []string `json:"fields,omitempty"`
+}
+
+// DriftError is returned when the resources a rollback would overwrite were modified outside Helm
+type DriftError struct {
+	Release  string
+	Revision int
+	Drift    []Drift
+}
+
+func (e *DriftError) Error() string {
+	sb := strings.Builder{}
+	_, _ = fmt.Fprintf(&sb, "%d resources of release %s were modified outside Helm and would be overwritten by the rollback to revision %d, "+
+		"review the changes (e.g. manual hotfixes) and force the rollback to proceed:", len(e.Drift), e.Release, e.Revision)
+	for _, d := range e.Drift {
+		_, _ = fmt.Fprintf(&sb, "\n- %s %s: %s", d.Kind, strings.TrimPrefix(d.Namespace+"/"+d.Name, "/"), d.Reason)
+		if len(d.Fields) > 0 {
+			_, _ = fmt.Fprintf(&sb, " (%s)", strings.Join(d.Fields, ", "))
+		}
+	}
+	return sb.String()
+}
+
+// driftOf returns the resources of the target revision whose live state diverges from the last revision of the
+// release, i.e. the changes made outside Helm that rolling back to the target revision would overwrite
+func driftOf(kubeClient kube.Interface, last, target *release.Release) ([]Drift, error) {
+	applied, err := kubeClient.Build(bytes.NewBufferString(last.Manifest), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the manifest of revision %d: %w", last.Version, err)
+	}
+	appliedObjects := make(map[string]runtime.Object, len(applied))
+	for _, info := range applied {
+		appliedObjects[resourceKey(info)] = info.Object
+	}
+	resources, err := kubeClient.Build(bytes.NewBufferString(target.Manifest), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the manifest of revision %d: %w", target.Version, err)
+	}
+	ret := make([]Drift, 0)
+	for _, info := range resources {
+		d := Drift{Kind: info.Mapping.GroupVersionKind.Kind, Name: info.Name, Namespace: info.Namespace}
+		appliedObject, managed := appliedObjects[resourceKey(info)]
+		err = info.Get()
+		switch {
+		case apierrors.IsNotFound(err):
+			if managed {
+				d.Reason = "deleted outside Helm"
+				ret = append(ret, d)
+			}
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("failed to get %s %s: %w", d.Kind, info.Name, err)
+		case !managed:
+			d.Reason = fmt.Sprintf("created outside Helm, not part of revision %d", last.Version)
+			ret = append(ret, d)
+			continue
+		}
+		expected, err := runtime.DefaultUnstructuredConverter.ToUnstructured(appliedObject)
+		if err != nil {
+			return nil, err
+		}
+		live, err := runtime.DefaultUnstructuredConverter.ToUnstructured(info.Object)
+		if err != nil {
+			return nil, err
+		}
+		delete(expected, "status")
+		if d.Fields = driftedFields(expected, live, ""); len(d.Fields) > 0 {
+			d.Reason = "modified outside Helm"
+			if len(d.Fields) > maxDriftedFields {
+				d.Fields = append(d.Fields[:maxDriftedFields], fmt.Sprintf("and %d more", len(d.Fields)-maxDriftedFields))
+			}
+			ret = append(ret, d)
+		}
+	}
+	return ret, nil
+}
+
+func resourceKey(info *resource.Info) string {
+	return info.Mapping.GroupVersionKind.GroupKind().String() + "/" + info.Namespace + "/" + info.Name
+}
+
+// driftedFields returns the paths of the fields of the expected object that have a different value in the live object,
+// the fields only set by the cluster (defaulted fields, generated metadata) are ignored
+func driftedFields(expected, live map[string]interface{}, path string) []string {
+	ret := make([]string, 0)
+	for _, key := range slices.Sorted(maps.Keys(expected)) {
+		ret = append(ret, driftedValue(expected[key], live[key], joinPath(path, key))...)
+	}
+	return ret
+}
+
+func driftedValue(expected, live interface{}, path string) []string {
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		liveMap, ok := live.(map[string]interface{})
+		if !ok {
+			if len(expected) == 0 && live == nil {
+				return nil
+			}
+			return []string{path}
+		}
+		return driftedFields(expected, liveMap, path)
+	case []interface{}:
+		liveSlice, ok := live.([]interface{})
+		if !ok || len(liveSlice) != len(expected) {
+			if len(expected) == 0 && live == nil {
+				return nil
+			}
+			return []string{path}
+		}
+		ret := make([]string, 0)
+		for i := range expected {
+			ret = append(ret, driftedValue(expected[i], liveSlice[i], path+"["+strconv.Itoa(i)+"]")...)
+		}
+		return ret
+	case nil:
+		return nil
+	default:
+		if !reflect.DeepEqual(expected, live) && fmt.Sprint(expected) != fmt.Sprint(live) {
+			return []string{path}
+		}
+		return nil
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,80 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DriftTestSuite struct {
+	suite.Suite
+}
+
+func (s *DriftTestSuite) TestDriftedFields() {
+	expected := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "app", "labels": map[string]interface{}{"app": "app"}},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "app", "image": "app:1.0"}},
+			}},
+		},
+	}
+	live := func(mutate func(spec map[string]interface{})) map[string]interface{} {
+		spec := map[string]interface{}{
+			"replicas":             int64(2),
+			"revisionHistoryLimit": int64(10),
+			"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "app", "image": "app:1.0", "imagePullPolicy": "IfNotPresent"}},
+			}},
+		}
+		if mutate != nil {
+			mutate(spec)
+		}
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "app", "uid": "1234", "labels": map[string]interface{}{"app": "app"}},
+			"spec":     spec,
+		}
+	}
+	s.Run("ignores the fields set by the cluster", func() {
+		s.Empty(driftedFields(expected, live(nil), ""))
+	})
+	s.Run("reports modified values", func() {
+		s.Equal([]string{"spec.replicas"}, driftedFields(expected, live(func(spec map[string]interface{}) {
+			spec["replicas"] = int64(5)
+		}), ""))
+	})
+	s.Run("reports modified list items", func() {
+		s.Equal([]string{"spec.template.spec.containers[0].image"}, driftedFields(expected, live(func(spec map[string]interface{}) {
+			spec["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})[0].(map[string]interface{})["image"] = "app:1.0-hotfix"
+		}), ""))
+	})
+	s.Run("reports lists with added or removed items", func() {
+		s.Equal([]string{"spec.template.spec.containers"}, driftedFields(expected, live(func(spec map[string]interface{}) {
+			spec["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"] = []interface{}{}
+		}), ""))
+	})
+	s.Run("reports removed fields", func() {
+		s.Equal([]string{"spec.replicas"}, driftedFields(expected, live(func(spec map[string]interface{}) {
+			delete(spec, "replicas")
+		}), ""))
+	})
+	s.Run("compares numbers regardless of their type", func() {
+		s.Empty(driftedFields(map[string]interface{}{"replicas": float64(2)}, map[string]interface{}{"replicas": int64(2)}, ""))
+	})
+}
+
+func (s *DriftTestSuite) TestDriftError() {
+	err := &DriftError{Release: "app", Revision: 1, Drift: []Drift{
+		{Kind: "Deployment", Name: "app", Namespace: "default", Reason: "modified outside Helm", Fields: []string{"spec.replicas"}},
+		{Kind: "ClusterRole", Name: "app", Reason: "deleted outside Helm"},
+	}}
+	s.Equal("2 resources of release app were modified outside Helm and would be overwritten by the rollback to revision 1, "+
+		"review the changes (e.g. manual hotfixes) and force the rollback to proceed:\n"+
+		"- Deployment default/app: modified outside Helm (spec.replicas)\n"+
+		"- ClusterRole app: deleted outside Helm", err.Error())
+}
+
+func TestDrift(t *testing.T) {
+	suite.Run(t, new(DriftTestSuite))
+}
@@ -0,0 +1,185 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+// ExportFormats are the formats of the release exports supported by Export
+var ExportFormats = []string{"values", "kustomize"}
+
+// chartLabel is the label of the resources rendered by the charts following the Helm conventions
+const chartLabel = "helm.sh/chart"
+
+// Export returns the release (its deployed revision if revision is 0) as files to commit to a Git repository, so that
+// a GitOps pipeline can take over a release installed with Helm: the user-supplied values.yaml with the reference of
+// its chart (values format), or a kustomize base with the rendered resources of the release (kustomize format)
+func (h *Helm) Export(_ context.Context, name string, namespace string, revision int, format string) (string, error) {
+	if !slices.Contains(ExportFormats, format) {
+		return "", fmt.Errorf("unsupported format %s, supported formats are %v", format, ExportFormats)
+	}
+	cfg, err := h.newAction(h.kubernetes.NamespaceOrDefault(namespace), false)
+	if err != nil {
+		return "", err
+	}
+	var rel *release.Release
+	if revision == 0 {
+		rel, err = cfg.Releases.Deployed(name)
+	} else {
+		rel, err = cfg.Releases.Get(name, revision)
+	}
+	if err != nil {
+		return "", err
+	}
+	if rel.Chart == nil || rel.Chart.Metadata == nil {
+		return "", fmt.Errorf("release %s has no chart", name)
+	}
+	return exportRelease(rel, format)
+}
+
+// exportRelease returns the files of the export of the release in the format, each preceded by a # File: comment
+func exportRelease(rel *release.Release, format string) (string, error) {
+	sb := strings.Builder{}
+	_, _ = fmt.Fprintf(&sb, "# Helm release %s/%s revision %d (chart %s %s) exported in %s format\n",
+		rel.Namespace, rel.Name, rel.Version, rel.Chart.Metadata.Name, rel.Chart.Metadata.Version, format)
+	var files map[string]string
+	var err error
+	switch format {
+	case "values":
+		sb.WriteString("# The repository of the chart isn't recorded by Helm, reference it in the GitOps tool " +
+			"(e.g. the sourceRef of a Flux HelmRelease or the repoURL of an Argo CD Application)\n")
+		var masked []string
+		files, masked, err = exportValues(rel)
+		for _, valuePath := range masked {
+			_, _ = fmt.Fprintf(&sb, "# Masked: value %s, resolved from Vault or decrypted from SOPS at install time, "+
+				"manage it with a secret management tool of the GitOps pipeline (e.g. SOPS, External Secrets)\n", valuePath)
+		}
+	case "kustomize":
+		var excluded []string
+		files, excluded, err = exportKustomize(rel)
+		for _, resource := range excluded {
+			_, _ = fmt.Fprintf(&sb, "# Not exported: %s\n", resource)
+		}
+	default:
+		err = fmt.Errorf("unsupported format %s, supported formats are %v", format, ExportFormats)
+	}
+	if err != nil {
+		return "", err
+	}
+	for _, file := range slices.Sorted(maps.Keys(files)) {
+		_, _ = fmt.Fprintf(&sb, "---\n# File: %s\n%s", file, files[file])
+	}
+	return sb.String(), nil
+}
+
+// exportValues returns the values.yaml with the user-supplied values of the release (without the chart defaults)
+// and the chart.yaml with the reference of its chart, and the paths of the values masked in the values.yaml: the
+// values resolved from Vault or decrypted from SOPS at install time, not to commit them
+func exportValues(rel *release.Release) (map[string]string, []string, error) {
+	values := "{}\n"
+	masked := make([]string, 0)
+	if len(rel.Config) > 0 {
+		isSecret := func(valuePath string) bool {
+			if isSecretValue(rel.Labels, valuePath) {
+				masked = append(masked, valuePath)
+				return true
+			}
+			return false
+		}
+		var err error
+		if values, err = output.MarshalYaml(maskValues(rel.Config, nil, "", isSecret)); err != nil {
+			return nil, nil, err
+		}
+		slices.Sort(masked)
+	}
+	reference := map[string]any{
+		"chart":   rel.Chart.Metadata.Name,
+		"version": rel.Chart.Metadata.Version,
+		"release": map[string]any{"name": rel.Name, "namespace": rel.Namespace},
+	}
+	if rel.Chart.Metadata.AppVersion != "" {
+		reference["appVersion"] = rel.Chart.Metadata.AppVersion
+	}
+	if rel.Chart.Metadata.Home != "" {
+		reference["home"] = rel.Chart.Metadata.Home
+	}
+	if len(rel.Chart.Metadata.Sources) > 0 {
+		reference["sources"] = rel.Chart.Metadata.Sources
+	}
+	chart, err := output.MarshalYaml(reference)
+	if err != nil {
+		return nil, nil, err
+	}
+	return map[string]string{"values.yaml": values, "chart.yaml": chart}, masked, nil
+}
+
+// exportKustomize returns the kustomization.yaml and the rendered resources of the release without their Helm labels,
+// and the descriptions of the resources left out: the Secrets, not to commit their data, and the hooks, run by Helm only
+func exportKustomize(rel *release.Release) (map[string]string, []string, error) {
+	files := make(map[string]string)
+	resources := make([]string, 0)
+	excluded := make([]string, 0)
+	manifests := releaseutil.SplitManifests(rel.Manifest)
+	keys := slices.Collect(maps.Keys(manifests))
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+	for _, key := range keys {
+		var object map[string]any
+		if err := yaml.Unmarshal([]byte(manifests[key]), &object); err != nil {
+			return nil, nil, fmt.Errorf("failed to read the manifest of release %s: %w", rel.Name, err)
+		}
+		if len(object) == 0 {
+			continue
+		}
+		kind, _ := object["kind"].(string)
+		metadata, _ := object["metadata"].(map[string]any)
+		name, _ := metadata["name"].(string)
+		if kind == "Secret" {
+			excluded = append(excluded, fmt.Sprintf("Secret %s, manage it with a secret management tool of the GitOps pipeline (e.g. SOPS, Sealed Secrets)", name))
+			continue
+		}
+		if labels, ok := metadata["labels"].(map[string]any); ok {
+			if labels[ManagedByLabel] == ManagedByHelm {
+				delete(labels, ManagedByLabel)
+			}
+			delete(labels, chartLabel)
+			if len(labels) == 0 {
+				delete(metadata, "labels")
+			}
+		}
+		file := strings.ToLower(kind) + "-" + name + ".yaml"
+		if _, exists := files[file]; exists {
+			namespace, _ := metadata["namespace"].(string)
+			file = strings.ToLower(kind) + "-" + namespace + "-" + name + ".yaml"
+		}
+		content, err := output.MarshalYaml(object)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[file] = content
+		resources = append(resources, file)
+	}
+	for _, hook := range rel.Hooks {
+		excluded = append(excluded, fmt.Sprintf("hook %s %s (%s), it is run by helm install and upgrade only", hook.Kind, hook.Name, hook.Path))
+	}
+	kustomization, err := output.MarshalYaml(map[string]any{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"namespace":  rel.Namespace,
+		"resources":  resources,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	files["kustomization.yaml"] = kustomization
+	return files, excluded, nil
+}
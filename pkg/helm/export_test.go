@@ -0,0 +1,100 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+type ExportTestSuite struct {
+	suite.Suite
+	release *release.Release
+}
+
+func (s *ExportTestSuite) SetupTest() {
+	s.release = &release.Release{
+		Name:      "app",
+		Namespace: "production",
+		Version:   3,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "app", Version: "1.2.0", AppVersion: "2.0", Home: "https://example.com/app"},
+			Values:   map[string]interface{}{"replicas": 1, "debug": false},
+		},
+		Config: map[string]interface{}{"replicas": 3, "image": map[string]interface{}{"tag": "2.0"}},
+		Manifest: "---\n# Source: app/templates/configmap.yaml\n" +
+			"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n  labels:\n    app.kubernetes.io/managed-by: Helm\n    helm.sh/chart: app-1.2.0\n" +
+			"data:\n  key: value\n" +
+			"---\n# Source: app/templates/secret.yaml\n" +
+			"apiVersion: v1\nkind: Secret\nmetadata:\n  name: app-credentials\nstringData:\n  password: s3cr3t\n" +
+			"---\n# Source: app/templates/deployment.yaml\n" +
+			"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\n  labels:\n    app.kubernetes.io/name: app\n    helm.sh/chart: app-1.2.0\n" +
+			"spec:\n  replicas: 3\n",
+		Hooks: []*release.Hook{{Name: "app-migrate", Kind: "Job", Path: "app/templates/migrate.yaml"}},
+	}
+}
+
+func (s *ExportTestSuite) TestExportValues() {
+	export, err := exportRelease(s.release, "values")
+	s.Require().NoError(err)
+	s.Run("returns the user-supplied values without the chart defaults", func() {
+		s.Contains(export, "---\n# File: values.yaml\nimage:\n  tag: \"2.0\"\nreplicas: 3\n")
+		s.NotContains(export, "debug")
+	})
+	s.Run("returns the chart reference", func() {
+		s.Contains(export, "---\n# File: chart.yaml\nappVersion: \"2.0\"\nchart: app\nhome: https://example.com/app\n"+
+			"release:\n  name: app\n  namespace: production\nversion: 1.2.0\n")
+	})
+	s.Run("describes the export", func() {
+		s.Contains(export, "# Helm release production/app revision 3 (chart app 1.2.0) exported in values format\n")
+		s.Contains(export, "# The repository of the chart isn't recorded by Helm")
+	})
+	s.Run("masks the values resolved from Vault or decrypted from SOPS", func() {
+		s.release.Config["database"] = map[string]interface{}{"host": "db.prod", "password": "s3cr3t-from-vault"}
+		s.release.Labels = secretPaths{"database.password": true}.labels()
+		export, err := exportRelease(s.release, "values")
+		s.Require().NoError(err)
+		s.NotContains(export, "s3cr3t-from-vault")
+		s.Contains(export, "---\n# File: values.yaml\ndatabase:\n  host: db.prod\n  password: '[REDACTED]'\n")
+		s.Contains(export, "# Masked: value database.password, resolved from Vault or decrypted from SOPS at install time")
+	})
+	s.Run("returns empty values if none were supplied", func() {
+		s.release.Config = nil
+		export, err := exportRelease(s.release, "values")
+		s.Require().NoError(err)
+		s.Contains(export, "---\n# File: values.yaml\n{}\n")
+	})
+}
+
+func (s *ExportTestSuite) TestExportKustomize() {
+	export, err := exportRelease(s.release, "kustomize")
+	s.Require().NoError(err)
+	s.Run("returns the kustomization of the resources", func() {
+		s.Contains(export, "---\n# File: kustomization.yaml\napiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\n"+
+			"namespace: production\nresources:\n- configmap-app.yaml\n- deployment-app.yaml\n")
+	})
+	s.Run("returns the resources without the Helm labels", func() {
+		s.Contains(export, "---\n# File: configmap-app.yaml\napiVersion: v1\ndata:\n  key: value\nkind: ConfigMap\nmetadata:\n  name: app\n")
+		s.Contains(export, "---\n# File: deployment-app.yaml\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  labels:\n    app.kubernetes.io/name: app\n  name: app\n")
+		s.NotContains(export, "helm.sh/chart")
+		s.NotContains(export, "managed-by")
+	})
+	s.Run("leaves the Secrets out", func() {
+		s.NotContains(export, "s3cr3t")
+		s.Contains(export, "# Not exported: Secret app-credentials, manage it with a secret management tool")
+	})
+	s.Run("leaves the hooks out", func() {
+		s.NotContains(export, "# File: job-app-migrate.yaml")
+		s.Contains(export, "# Not exported: hook Job app-migrate (app/templates/migrate.yaml), it is run by helm install and upgrade only\n")
+	})
+}
+
+func (s *ExportTestSuite) TestExportUnsupportedFormat() {
+	_, err := exportRelease(s.release, "flux")
+	s.EqualError(err, "unsupported format flux, supported formats are [values kustomize]")
+}
+
+func TestExport(t *testing.T) {
+	suite.Run(t, new(ExportTestSuite))
+}
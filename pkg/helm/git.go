@@ -0,0 +1,176 @@
+package helm
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GitChartPrefix is the prefix of the chart references located in a git repository
+const GitChartPrefix = "git+"
+
+// gitCloneTimeout bounds the shallow clone of a chart repository
+const gitCloneTimeout = 2 * time.Minute
+
+// GitConfig holds the settings of the git repositories the charts are cloned from (git+https chart references)
+type GitConfig struct {
+	// WorkspaceDir is the directory the repositories are cloned into (Optional, the system temporary directory if not provided)
+	WorkspaceDir string `toml:"workspace_dir,omitempty"`
+	// Credentials authenticate the clones, the credential with the longest url prefix matching the repository is used
+	Credentials []GitCredential `toml:"credentials,omitempty"`
+}
+
+// GitCredential is the HTTP basic authentication of the repositories matching the Url prefix
+type GitCredential struct {
+	// Url is the prefix of the repository URLs the credential applies to (e.g. https://github.com/my-org/)
+	Url string `toml:"url"`
+	// Username (Optional, "git" if not provided, most hosts accept any username with an access token)
+	Username string `toml:"username,omitempty"`
+	// PasswordFile is the file with the password or access token
+	PasswordFile string `toml:"password_file"`
+}
+
+func (c *GitConfig) Validate() error {
+	if c == nil {
+		return errors.New("git config is nil")
+	}
+	if c.WorkspaceDir != "" {
+		if info, err := os.Stat(c.WorkspaceDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("git workspace_dir must be an existing directory: %s", c.WorkspaceDir)
+		}
+	}
+	for _, credential := range c.Credentials {
+		if u, err := url.Parse(credential.Url); err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("git credential url must be a valid https URL, got %s", credential.Url)
+		}
+		if _, err := os.Stat(credential.PasswordFile); err != nil {
+			return fmt.Errorf("git credential password_file of %s must be a valid file path: %w", credential.Url, err)
+		}
+	}
+	return nil
+}
+
+// ResolvePaths resolves the relative workspace_dir and password_file paths against the configuration directory
+func (c *GitConfig) ResolvePaths(configDir string) {
+	if configDir == "" {
+		return
+	}
+	files := []*string{&c.WorkspaceDir}
+	for i := range c.Credentials {
+		files = append(files, &c.Credentials[i].PasswordFile)
+	}
+	for _, file := range files {
+		if *file != "" && !filepath.IsAbs(*file) {
+			*file = filepath.Join(configDir, *file)
+		}
+	}
+}
+
+// GitSource is a chart located in a git repository, referenced as git+https://host/org/repo@path/to/chart?ref=v1.0.0
+type GitSource struct {
+	// Repository is the https URL of the repository
+	Repository string
+	// Path is the directory of the chart in the repository (the repository root if empty)
+	Path string
+	// Ref is the branch, tag, or commit to check out (the default branch if empty)
+	Ref string
+}
+
+// IsGitChart returns true if the chart reference is located in a git repository
+func IsGitChart(chart string) bool {
+	return strings.HasPrefix(chart, GitChartPrefix)
+}
+
+// ParseGitSource parses a git+https://host/org/repo@path/to/chart?ref=v1.0.0 chart reference
+func ParseGitSource(chart string) (*GitSource, error) {
+	u, err := url.Parse(strings.TrimPrefix(chart, GitChartPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid git chart reference %s: %w", chart, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid git chart reference %s: only git+https repositories are supported", chart)
+	}
+	if u.User != nil {
+		return nil, fmt.Errorf("invalid git chart reference %s: credentials must be configured in the helm toolset configuration, not in the reference", chart)
+	}
+	repository, chartPath, _ := strings.Cut(u.Path, "@")
+	if strings.Trim(repository, "/") == "" {
+		return nil, fmt.Errorf("invalid git chart reference %s: missing repository", chart)
+	}
+	chartPath = strings.Trim(chartPath, "/")
+	if chartPath != "" && !filepath.IsLocal(chartPath) {
+		return nil, fmt.Errorf("invalid git chart reference %s: the chart path must be relative to the repository root", chart)
+	}
+	return &GitSource{
+		Repository: (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: repository}).String(),
+		Path:       path.Clean("/" + chartPath)[1:],
+		Ref:        u.Query().Get("ref"),
+	}, nil
+}
+
+// Clone shallowly clones the ref of the repository into a new directory of the workspace and returns its path,
+// the caller is responsible for removing it
+func (c *GitConfig) Clone(ctx context.Context, source *GitSource) (string, error) {
+	if c == nil {
+		c = &GitConfig{}
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git is required to install charts from git repositories: %w", err)
+	}
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if credential := c.credentialFor(source.Repository); credential != nil {
+		password, err := os.ReadFile(credential.PasswordFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the git credential of %s: %w", credential.Url, err)
+		}
+		// Passed through the environment so the credential doesn't show up in the process arguments
+		auth := base64.StdEncoding.EncodeToString([]byte(cmp.Or(credential.Username, "git") + ":" + strings.TrimSpace(string(password))))
+		env = append(env, "GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=Authorization: Basic "+auth)
+	}
+	dir, err := os.MkdirTemp(c.WorkspaceDir, "chart-")
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, gitCloneTimeout)
+	defer cancel()
+	// fetch accepts branches, tags, and commits alike, unlike clone --branch
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--depth", "1", "--no-tags", "--", source.Repository, cmp.Or(source.Ref, "HEAD")},
+		{"checkout", "--quiet", "--detach", "FETCH_HEAD"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = env
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err = cmd.Run(); err != nil {
+			_ = os.RemoveAll(dir)
+			return "", fmt.Errorf("failed to clone %s (ref %s): %w: %s",
+				source.Repository, cmp.Or(source.Ref, "default branch"), err, strings.TrimSpace(stderr.String()))
+		}
+	}
+	return dir, nil
+}
+
+// credentialFor returns the credential with the longest url prefix matching the repository, nil if none
+func (c *GitConfig) credentialFor(repository string) *GitCredential {
+	var match *GitCredential
+	for i, credential := range c.Credentials {
+		prefix := strings.TrimSuffix(credential.Url, "/")
+		if (repository == prefix || strings.HasPrefix(repository, prefix+"/")) &&
+			(match == nil || len(credential.Url) > len(match.Url)) {
+			match = &c.Credentials[i]
+		}
+	}
+	return match
+}
@@ -1,18 +1,31 @@
 package helm
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/objectstorage"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+	"github.com/containers/kubernetes-mcp-server/pkg/sops"
+	"github.com/containers/kubernetes-mcp-server/pkg/vault"
 )
 
 type Kubernetes interface {
@@ -21,44 +34,129 @@ type Kubernetes interface {
 }
 
 type Helm struct {
-	kubernetes Kubernetes
+	kubernetes    Kubernetes
+	git           *GitConfig
+	objectStorage *objectstorage.Client
+	cache         *chartCache
+	releaseIndex  bool
+	sops          *sops.Config
+	vault         *vault.Config
 }
 
 // NewHelm creates a new Helm instance
 func NewHelm(kubernetes Kubernetes) *Helm {
-	return &Helm{kubernetes: kubernetes}
+	return &Helm{kubernetes: kubernetes, objectStorage: objectstorage.NewClient(nil, nil), releaseIndex: true}
+}
+
+// WithReleaseIndex enables (the default) or disables listing the releases from the watched release Secrets
+func (h *Helm) WithReleaseIndex(enabled bool) *Helm {
+	h.releaseIndex = enabled
+	return h
+}
+
+// WithGit sets the configuration used to clone the charts referenced by git+https URLs
+func (h *Helm) WithGit(git *GitConfig) *Helm {
+	h.git = git
+	return h
+}
+
+// WithSops sets the keys decrypting the SOPS-encrypted values files
+func (h *Helm) WithSops(sops *sops.Config) *Helm {
+	h.sops = sops
+	return h
 }
 
-func (h *Helm) Install(ctx context.Context, chart string, values map[string]interface{}, name string, namespace string) (string, error) {
+// WithVault sets the Vault the vault:<path>#<key> placeholders of the values are resolved against
+func (h *Helm) WithVault(vault *vault.Config) *Helm {
+	h.vault = vault
+	return h
+}
+
+// WithObjectStorage sets the client downloading the charts referenced by s3:// and gs:// URLs
+func (h *Helm) WithObjectStorage(client *objectstorage.Client) *Helm {
+	h.objectStorage = client
+	return h
+}
+
+// WithChartCache sets the local store of the downloaded charts and repository indexes, nil downloads them on every install
+func (h *Helm) WithChartCache(config *ChartCacheConfig) *Helm {
+	h.cache = nil
+	if config != nil {
+		h.cache = newChartCache(config)
+	}
+	return h
+}
+
+// cached returns the chart archive (or the repository index if index is true) of the reference from the chart cache,
+// or downloads it with fetch if the cache is disabled
+func (h *Helm) cached(reference string, index bool, fetch func() ([]byte, error)) ([]byte, error) {
+	if h.cache == nil {
+		return fetch()
+	}
+	ttl := h.cache.config.chartTTL()
+	if index {
+		ttl = h.cache.config.indexTTL()
+	}
+	return h.cache.get(reference, ttl, fetch)
+}
+
+// maxReleaseNameLength is the maximum length of a release name, leaving room for the suffixes charts usually append to
+// it in the names (63 characters at most) of their resources
+const maxReleaseNameLength = 53
+
+// ValidateReleaseName returns a descriptive error if the name can't be used as the name of a release
+func ValidateReleaseName(name string) error {
+	if len(name) > maxReleaseNameLength {
+		return fmt.Errorf("invalid release name %q: must be no more than %d characters, got %d", name, maxReleaseNameLength, len(name))
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid release name %q: %s", name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// Install installs the chart with the values files of the chart merged in order and overridden by the values,
+// the Vault placeholders of the values are resolved at install time and the paths of the secret values are recorded
+// in the labels of the release (see SecretValueLabelPrefix)
+func (h *Helm) Install(ctx context.Context, chart string, values map[string]interface{}, valuesFiles []string, name string, namespace string) (string, error) {
 	cfg, err := h.newAction(h.kubernetes.NamespaceOrDefault(namespace), false)
 	if err != nil {
 		return "", err
 	}
 	install := action.NewInstall(cfg)
-	if name == "" {
-		install.GenerateName = true
-		install.ReleaseName, _, _ = install.NameAndChart([]string{chart})
-	} else {
-		install.ReleaseName = name
-	}
+	install.ReleaseName = name
 	install.Namespace = h.kubernetes.NamespaceOrDefault(namespace)
 	install.Wait = true
 	install.Timeout = 5 * time.Minute
 	install.DryRun = false
 
-	chartRequested, err := install.LocateChart(chart, cli.New())
+	chartLoaded, generateNameFrom, err := h.loadChart(ctx, install, chart)
 	if err != nil {
 		return "", err
 	}
-	chartLoaded, err := loader.Load(chartRequested)
+	if name == "" {
+		install.GenerateName = true
+		install.ReleaseName, _, _ = install.NameAndChart([]string{cmp.Or(generateNameFrom, chartLoaded.Name())})
+	}
+	values, secrets, err := h.valuesOf(ctx, chartLoaded, valuesFiles, values)
 	if err != nil {
 		return "", err
 	}
+	// the paths of the secret values are recorded in the release, the values shown or exported from it mask them
+	install.Labels = secrets.labels()
+	if vault.HasPlaceholders(values) {
+		if values, err = h.vault.Resolve(ctx, values); err != nil {
+			return "", err
+		}
+	}
 
 	installedRelease, err := install.RunWithContext(ctx, chartLoaded, values)
 	if err != nil {
 		return "", err
 	}
+	if index := h.existingReleaseIndex(); index != nil {
+		index.await(ctx, installedRelease.Namespace, installedRelease.Name, installedRelease.Version)
+	}
 	ret, err := yaml.Marshal(simplify(installedRelease))
 	if err != nil {
 		return "", err
@@ -66,14 +164,98 @@ func (h *Helm) Install(ctx context.Context, chart string, values map[string]inte
 	return string(ret), nil
 }
 
-// List lists all the releases for the specified namespace (or current namespace if). Or allNamespaces is true, it lists all releases across all namespaces.
-func (h *Helm) List(namespace string, allNamespaces bool) (string, error) {
+// loadChart locates the chart reference, cloning or downloading it (or reading it from the chart cache) if needed, and
+// loads it. It returns, in addition to the chart, the reference the names of its releases are generated from.
+func (h *Helm) loadChart(ctx context.Context, install *action.Install, reference string) (*chart.Chart, string, error) {
+	// release names are generated from the chart reference, or from the chart name for the cloned and downloaded charts
+	generateNameFrom := reference
+	switch {
+	case IsGitChart(reference):
+		if h.cache != nil && h.cache.config.Offline {
+			return nil, "", fmt.Errorf("charts in git repositories can't be installed in offline mode: %s", reference)
+		}
+		source, err := ParseGitSource(reference)
+		if err != nil {
+			return nil, "", err
+		}
+		dir, err := h.git.Clone(ctx, source)
+		if err != nil {
+			return nil, "", err
+		}
+		defer func() { _ = os.RemoveAll(dir) }()
+		reference = filepath.Join(dir, source.Path)
+		generateNameFrom = ""
+	case objectstorage.IsObjectURL(reference):
+		dir, err := os.MkdirTemp("", "chart-")
+		if err != nil {
+			return nil, "", err
+		}
+		defer func() { _ = os.RemoveAll(dir) }()
+		if reference, err = h.downloadChart(ctx, reference, dir); err != nil {
+			return nil, "", err
+		}
+		generateNameFrom = ""
+	case h.cache != nil && !isLocalChart(reference):
+		dir, err := os.MkdirTemp("", "chart-")
+		if err != nil {
+			return nil, "", err
+		}
+		defer func() { _ = os.RemoveAll(dir) }()
+		if reference, err = h.cachedRepositoryChart(install, reference, dir); err != nil {
+			return nil, "", err
+		}
+	}
+	chartRequested, err := install.LocateChart(reference, cli.New())
+	if err != nil {
+		return nil, "", err
+	}
+	chartLoaded, err := loader.Load(chartRequested)
+	if err != nil {
+		return nil, "", err
+	}
+	return chartLoaded, generateNameFrom, nil
+}
+
+// isLocalChart returns true if the chart reference is a chart directory or archive of the local file system
+func isLocalChart(chart string) bool {
+	_, err := os.Stat(chart)
+	return err == nil
+}
+
+// cachedRepositoryChart writes the archive of the chart of a Helm (or OCI) repository, served from the chart cache
+// or located by Helm, into the directory and returns its path
+func (h *Helm) cachedRepositoryChart(install *action.Install, chart, dir string) (string, error) {
+	data, err := h.cached(chart, false, func() ([]byte, error) {
+		located, err := install.LocateChart(chart, cli.New())
+		if err != nil {
+			return nil, err
+		}
+		return os.ReadFile(located)
+	})
+	if err != nil {
+		return "", err
+	}
+	archive := filepath.Join(dir, "chart.tgz")
+	return archive, os.WriteFile(archive, data, 0o600)
+}
+
+// List lists the releases in the specified namespace (or the current namespace if empty), or across all namespaces if
+// allNamespaces is true. The releases are sorted by name or, if byDate is true, by their last deployment date, in
+// descending order if descending is true.
+func (h *Helm) List(ctx context.Context, namespace string, allNamespaces, byDate, descending bool) (string, error) {
 	cfg, err := h.newAction(namespace, allNamespaces)
 	if err != nil {
 		return "", err
 	}
+	if releases := h.indexedReleases(ctx, namespace, allNamespaces); releases != nil {
+		indexed := *cfg
+		indexed.Releases = releases
+		cfg = &indexed
+	}
 	list := action.NewList(cfg)
 	list.AllNamespaces = allNamespaces
+	list.ByDate = byDate
+	list.SortReverse = descending
 	releases, err := list.Run()
 	if err != nil {
 		return "", err
@@ -87,7 +269,7 @@ func (h *Helm) List(namespace string, allNamespaces bool) (string, error) {
 	return string(ret), nil
 }
 
-func (h *Helm) Uninstall(name string, namespace string) (string, error) {
+func (h *Helm) Uninstall(ctx context.Context, name string, namespace string) (string, error) {
 	cfg, err := h.newAction(h.kubernetes.NamespaceOrDefault(namespace), false)
 	if err != nil {
 		return "", err
@@ -102,21 +284,124 @@ func (h *Helm) Uninstall(name string, namespace string) (string, error) {
 	} else if err != nil {
 		return "", err
 	}
+	if index := h.existingReleaseIndex(); index != nil {
+		index.await(ctx, h.kubernetes.NamespaceOrDefault(namespace), name, 0)
+	}
 	return fmt.Sprintf("Uninstalled release %s %s", uninstalledRelease.Release.Name, uninstalledRelease.Info), nil
 }
 
+// Rollback rolls the release back to the revision (the previous one if 0). Unless force is true, the rollback is refused
+// with a DriftError if the resources of the revision were modified outside Helm since the last revision was applied
+// (e.g. manual hotfixes), checkDrift false skips the check
+func (h *Helm) Rollback(ctx context.Context, name string, namespace string, revision int, checkDrift, force bool) (string, error) {
+	cfg, err := h.newAction(h.kubernetes.NamespaceOrDefault(namespace), false)
+	if err != nil {
+		return "", err
+	}
+	last, err := cfg.Releases.Last(name)
+	if err != nil {
+		return "", err
+	}
+	if revision == 0 {
+		revision = last.Version - 1
+	}
+	target, err := cfg.Releases.Get(name, revision)
+	if err != nil {
+		return "", fmt.Errorf("release %s has no revision %d: %w", name, revision, err)
+	}
+	drift := make([]Drift, 0)
+	if checkDrift {
+		if drift, err = driftOf(cfg.KubeClient, last, target); err != nil {
+			return "", fmt.Errorf("failed to check the drift of release %s: %w", name, err)
+		}
+		if len(drift) > 0 && !force {
+			return "", &DriftError{Release: name, Revision: revision, Drift: drift}
+		}
+	}
+	rollback := action.NewRollback(cfg)
+	rollback.Version = revision
+	rollback.Wait = true
+	rollback.Timeout = 5 * time.Minute
+	if err = rollback.Run(name); err != nil {
+		return "", err
+	}
+	rolledBack, err := cfg.Releases.Last(name)
+	if err != nil {
+		return "", err
+	}
+	if index := h.existingReleaseIndex(); index != nil {
+		index.await(ctx, rolledBack.Namespace, rolledBack.Name, rolledBack.Version)
+	}
+	ret := map[string]interface{}{"release": simplify(rolledBack)[0]}
+	if len(drift) > 0 {
+		ret["overwrittenDrift"] = drift
+	}
+	out, err := yaml.Marshal(ret)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// indexedReleases returns the release storage served from the index of the release Secrets of the credentials,
+// starting its watch if needed, nil if the index is disabled or unavailable (e.g. Secrets can't be watched cluster-wide)
+func (h *Helm) indexedReleases(ctx context.Context, namespace string, allNamespaces bool) *storage.Storage {
+	if !h.releaseIndex {
+		return nil
+	}
+	restConfig, err := h.kubernetes.ToRESTConfig()
+	if err != nil {
+		return nil
+	}
+	index := releaseIndexes.indexFor(restConfig, func() (kubernetes.Interface, error) {
+		return kubernetes.NewForConfig(restConfig)
+	})
+	if !index.wait(ctx) {
+		return nil
+	}
+	if allNamespaces {
+		namespace = ""
+	} else {
+		namespace = h.kubernetes.NamespaceOrDefault(namespace)
+	}
+	return storage.Init(index.driver(namespace))
+}
+
+// existingReleaseIndex returns the synced index of the release Secrets of the credentials, nil if none was started
+func (h *Helm) existingReleaseIndex() *releaseIndex {
+	if !h.releaseIndex {
+		return nil
+	}
+	restConfig, err := h.kubernetes.ToRESTConfig()
+	if err != nil {
+		return nil
+	}
+	return releaseIndexes.existing(restConfig)
+}
+
+// newAction returns the action configuration of the namespace (all namespaces if allNamespaces is true), shared with the
+// calls of the same identity
 func (h *Helm) newAction(namespace string, allNamespaces bool) (*action.Configuration, error) {
-	cfg := new(action.Configuration)
 	applicableNamespace := ""
 	if !allNamespaces {
 		applicableNamespace = h.kubernetes.NamespaceOrDefault(namespace)
 	}
-	registryClient, err := registry.NewClient()
+	restConfig, err := h.kubernetes.ToRESTConfig()
 	if err != nil {
 		return nil, err
 	}
-	cfg.RegistryClient = registryClient
-	return cfg, cfg.Init(h.kubernetes, applicableNamespace, "", klog.V(5).Infof)
+	return actionConfigs.get(actionConfigKey(restConfig, applicableNamespace), func() (*action.Configuration, error) {
+		cfg := new(action.Configuration)
+		registryClient, err := registry.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		cfg.RegistryClient = registryClient
+		if err = cfg.Init(h.kubernetes, applicableNamespace, "", klog.V(5).Infof); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+	})
 }
 
 func simplify(release ...*release.Release) []map[string]interface{} {
@@ -135,7 +420,8 @@ func simplify(release ...*release.Release) []map[string]interface{} {
 		if r.Info != nil {
 			ret[i]["status"] = r.Info.Status.String()
 			if !r.Info.LastDeployed.IsZero() {
-				ret[i]["lastDeployed"] = r.Info.LastDeployed.Format(time.RFC1123Z)
+				ret[i]["lastDeployed"] = r.Info.LastDeployed.UTC().Format(time.RFC3339)
+				ret[i]["age"] = output.HumanAge(r.Info.LastDeployed.Time)
 			}
 		}
 	}
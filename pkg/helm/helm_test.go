@@ -0,0 +1,36 @@
+package helm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HelmTestSuite struct {
+	suite.Suite
+}
+
+func (s *HelmTestSuite) TestValidateReleaseName() {
+	s.Run("accepts DNS-1123 names", func() {
+		s.NoError(ValidateReleaseName("my-release"))
+		s.NoError(ValidateReleaseName("my.release-1"))
+		s.NoError(ValidateReleaseName(strings.Repeat("a", 53)))
+	})
+	s.Run("rejects names longer than 53 characters", func() {
+		s.EqualError(ValidateReleaseName(strings.Repeat("a", 54)),
+			`invalid release name "`+strings.Repeat("a", 54)+`": must be no more than 53 characters, got 54`)
+	})
+	s.Run("rejects names with invalid characters", func() {
+		err := ValidateReleaseName("My_Release")
+		s.Require().Error(err)
+		s.Contains(err.Error(), `invalid release name "My_Release": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters`)
+	})
+	s.Run("rejects names not ending with an alphanumeric character", func() {
+		s.Error(ValidateReleaseName("my-release-"))
+	})
+}
+
+func TestHelm(t *testing.T) {
+	suite.Run(t, new(HelmTestSuite))
+}
@@ -0,0 +1,45 @@
+package helm
+
+import (
+	"context"
+	"slices"
+
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/yaml"
+)
+
+// History returns the revisions of the release in the specified namespace (or the current namespace if empty), sorted
+// by revision, in descending order if descending is true.
+func (h *Helm) History(_ context.Context, name string, namespace string, descending bool) (string, error) {
+	cfg, err := h.newAction(h.kubernetes.NamespaceOrDefault(namespace), false)
+	if err != nil {
+		return "", err
+	}
+	revisions, err := cfg.Releases.History(name)
+	if err != nil {
+		return "", err
+	}
+	ret, err := yaml.Marshal(history(revisions, descending))
+	if err != nil {
+		return "", err
+	}
+	return string(ret), nil
+}
+
+// history simplifies the revisions of a release, sorted by revision, along with the description of each of them
+// (e.g. "Install complete", "Rollback to 2")
+func history(revisions []*release.Release, descending bool) []map[string]interface{} {
+	revisions = slices.Clone(revisions)
+	releaseutil.SortByRevision(revisions)
+	if descending {
+		slices.Reverse(revisions)
+	}
+	ret := simplify(revisions...)
+	for i, r := range revisions {
+		if r.Info != nil && r.Info.Description != "" {
+			ret[i]["description"] = r.Info.Description
+		}
+	}
+	return ret
+}
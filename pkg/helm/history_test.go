@@ -0,0 +1,50 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+type HistoryTestSuite struct {
+	suite.Suite
+	revisions []*release.Release
+}
+
+func (s *HistoryTestSuite) SetupTest() {
+	s.revisions = []*release.Release{
+		{Name: "app", Namespace: "production", Version: 2, Info: &release.Info{Status: release.StatusSuperseded, Description: "Upgrade complete"}},
+		{Name: "app", Namespace: "production", Version: 3, Info: &release.Info{Status: release.StatusDeployed, Description: "Rollback to 1"}},
+		{Name: "app", Namespace: "production", Version: 1, Info: &release.Info{Status: release.StatusSuperseded, Description: "Install complete"}},
+	}
+}
+
+func (s *HistoryTestSuite) TestHistory() {
+	revisionsOf := func(history []map[string]interface{}) []interface{} {
+		ret := make([]interface{}, len(history))
+		for i, r := range history {
+			ret[i] = r["revision"]
+		}
+		return ret
+	}
+	s.Run("sorts the revisions in ascending order", func() {
+		s.Equal([]interface{}{1, 2, 3}, revisionsOf(history(s.revisions, false)))
+	})
+	s.Run("sorts the revisions in descending order", func() {
+		s.Equal([]interface{}{3, 2, 1}, revisionsOf(history(s.revisions, true)))
+	})
+	s.Run("returns the status and description of the revisions", func() {
+		latest := history(s.revisions, true)[0]
+		s.Equal("deployed", latest["status"])
+		s.Equal("Rollback to 1", latest["description"])
+	})
+	s.Run("doesn't reorder the revisions it is given", func() {
+		history(s.revisions, false)
+		s.Equal(2, s.revisions[0].Version)
+	})
+}
+
+func TestHistory(t *testing.T) {
+	suite.Run(t, new(HistoryTestSuite))
+}
@@ -0,0 +1,168 @@
+package helm
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// ChartInventory is what installing a chart would pull: its subcharts and the container images of its manifests
+type ChartInventory struct {
+	Chart        string            `json:"chart"`
+	Version      string            `json:"version"`
+	AppVersion   string            `json:"appVersion,omitempty"`
+	Dependencies []ChartDependency `json:"dependencies,omitempty"`
+	Images       []ChartImage      `json:"images"`
+}
+
+type ChartDependency struct {
+	// Path is the path of the subchart in the dependency tree (e.g. postgresql/common)
+	Path       string `json:"path"`
+	Version    string `json:"version"`
+	Repository string `json:"repository,omitempty"`
+	// Enabled is false if the subchart is disabled by its condition or tags for the provided values
+	Enabled bool `json:"enabled"`
+}
+
+type ChartImage struct {
+	Image string `json:"image"`
+	// Resources are the rendered resources (e.g. Deployment/web) referencing the image, hooks included
+	Resources []string `json:"resources"`
+}
+
+// Inventory returns the subcharts of the chart and the container images referenced by its manifests, rendered for
+// the namespace without accessing the cluster, with the values files of the chart merged in order and overridden by the values
+func (h *Helm) Inventory(ctx context.Context, reference string, values map[string]interface{}, valuesFiles []string, namespace string) (*ChartInventory, error) {
+	// a dedicated configuration, rendering the manifests replaces its Kubernetes client and release storage
+	cfg := &action.Configuration{Log: klog.V(5).Infof}
+	registryClient, err := registry.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	cfg.RegistryClient = registryClient
+	install := action.NewInstall(cfg)
+	install.DryRun = true
+	install.ClientOnly = true
+	install.IncludeCRDs = true
+	install.Namespace = namespace
+	chartLoaded, _, err := h.loadChart(ctx, install, reference)
+	if err != nil {
+		return nil, err
+	}
+	install.ReleaseName = chartLoaded.Name()
+	if values, _, err = h.valuesOf(ctx, chartLoaded, valuesFiles, values); err != nil {
+		return nil, err
+	}
+	ret := &ChartInventory{
+		Chart:      chartLoaded.Name(),
+		Version:    chartLoaded.Metadata.Version,
+		AppVersion: chartLoaded.Metadata.AppVersion,
+	}
+	declared := dependenciesOf(chartLoaded, "")
+	if err = chartutil.ProcessDependenciesWithMerge(chartLoaded, values); err != nil {
+		return nil, err
+	}
+	enabled := dependenciesOf(chartLoaded, "")
+	for _, dependency := range declared {
+		dependency.Enabled = slices.ContainsFunc(enabled, func(d ChartDependency) bool { return d.Path == dependency.Path })
+		ret.Dependencies = append(ret.Dependencies, dependency)
+	}
+	rendered, err := install.RunWithContext(ctx, chartLoaded, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %s: %w", chartLoaded.Name(), err)
+	}
+	manifests := []string{rendered.Manifest}
+	for _, hook := range rendered.Hooks {
+		manifests = append(manifests, hook.Manifest)
+	}
+	if ret.Images, err = imagesOf(manifests...); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// dependenciesOf returns the subcharts of the chart, and theirs, sorted by path
+func dependenciesOf(c *chart.Chart, parent string) []ChartDependency {
+	ret := make([]ChartDependency, 0)
+	for _, dependency := range c.Dependencies() {
+		d := ChartDependency{Path: path.Join(parent, dependency.Name()), Version: dependency.Metadata.Version}
+		for _, declared := range c.Metadata.Dependencies {
+			if declared.Name == dependency.Name() || declared.Alias == dependency.Name() {
+				d.Repository = declared.Repository
+			}
+		}
+		ret = append(ret, d)
+		ret = append(ret, dependenciesOf(dependency, d.Path)...)
+	}
+	slices.SortFunc(ret, func(a, b ChartDependency) int { return cmp.Compare(a.Path, b.Path) })
+	return ret
+}
+
+// imagesOf returns the container images (containers, init and ephemeral containers of any pod template) referenced by
+// the resources of the manifests, sorted by image
+func imagesOf(manifests ...string) ([]ChartImage, error) {
+	resources := make(map[string][]string)
+	for _, manifest := range manifests {
+		for _, document := range releaseutil.SplitManifests(manifest) {
+			obj := map[string]interface{}{}
+			if err := yaml.Unmarshal([]byte(document), &obj); err != nil {
+				return nil, fmt.Errorf("failed to parse rendered manifest: %w", err)
+			}
+			if len(obj) == 0 {
+				continue
+			}
+			kind, _ := obj["kind"].(string)
+			metadata, _ := obj["metadata"].(map[string]interface{})
+			name, _ := metadata["name"].(string)
+			for _, image := range containerImages(obj) {
+				resource := kind + "/" + name
+				if !slices.Contains(resources[image], resource) {
+					resources[image] = append(resources[image], resource)
+				}
+			}
+		}
+	}
+	ret := make([]ChartImage, 0, len(resources))
+	for image, referencing := range resources {
+		slices.Sort(referencing)
+		ret = append(ret, ChartImage{Image: image, Resources: referencing})
+	}
+	slices.SortFunc(ret, func(a, b ChartImage) int { return strings.Compare(a.Image, b.Image) })
+	return ret, nil
+}
+
+// containerImages returns the images of the container lists found at any depth of the object
+func containerImages(value interface{}) []string {
+	var ret []string
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range value {
+			if containers, ok := nested.([]interface{}); ok && (key == "containers" || key == "initContainers" || key == "ephemeralContainers") {
+				for _, container := range containers {
+					container, _ := container.(map[string]interface{})
+					if image, ok := container["image"].(string); ok && image != "" {
+						ret = append(ret, image)
+					}
+				}
+				continue
+			}
+			ret = append(ret, containerImages(nested)...)
+		}
+	case []interface{}:
+		for _, nested := range value {
+			ret = append(ret, containerImages(nested)...)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,80 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type InventoryTestSuite struct {
+	suite.Suite
+	chart string
+}
+
+// SetupTest writes a chart with an optional subchart, a Deployment, a CronJob and a pre-install hook
+func (s *InventoryTestSuite) SetupTest() {
+	s.chart = s.T().TempDir()
+	files := map[string]string{
+		"Chart.yaml": "apiVersion: v2\nname: app\nversion: 1.2.3\nappVersion: \"4.5\"\n" +
+			"dependencies:\n- name: cache\n  version: 0.1.0\n  repository: https://charts.example.com\n  condition: cache.enabled\n",
+		"values.yaml": "image: registry.example.com/app:4.5\ncache:\n  enabled: false\n",
+		"templates/deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  template:\n    spec:\n" +
+			"      initContainers:\n      - name: migrate\n        image: {{ .Values.image }}\n" +
+			"      containers:\n      - name: web\n        image: {{ .Values.image }}\n      - name: proxy\n        image: envoyproxy/envoy:v1.30\n",
+		"templates/cronjob.yaml": "apiVersion: batch/v1\nkind: CronJob\nmetadata:\n  name: cleanup\nspec:\n  schedule: \"@daily\"\n  jobTemplate:\n    spec:\n      template:\n        spec:\n" +
+			"          containers:\n          - name: cleanup\n            image: busybox:1.36\n",
+		"templates/hook.yaml": "apiVersion: v1\nkind: Pod\nmetadata:\n  name: pre-install\n  annotations:\n    helm.sh/hook: pre-install\nspec:\n" +
+			"  containers:\n  - name: check\n    image: busybox:1.36\n",
+		"charts/cache/Chart.yaml":                "apiVersion: v2\nname: cache\nversion: 0.1.0\n",
+		"charts/cache/templates/deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: cache\nspec:\n  template:\n    spec:\n      containers:\n      - name: redis\n        image: redis:7\n",
+	}
+	for name, content := range files {
+		s.Require().NoError(os.MkdirAll(filepath.Dir(filepath.Join(s.chart, name)), 0o755))
+		s.Require().NoError(os.WriteFile(filepath.Join(s.chart, name), []byte(content), 0o644))
+	}
+}
+
+func (s *InventoryTestSuite) TestInventory() {
+	inventory, err := NewHelm(nil).Inventory(s.T().Context(), s.chart, nil, nil, "default")
+	s.Require().NoError(err)
+	s.Run("returns the chart", func() {
+		s.Equal("app", inventory.Chart)
+		s.Equal("1.2.3", inventory.Version)
+		s.Equal("4.5", inventory.AppVersion)
+	})
+	s.Run("returns the disabled subcharts", func() {
+		s.Equal([]ChartDependency{{Path: "cache", Version: "0.1.0", Repository: "https://charts.example.com", Enabled: false}}, inventory.Dependencies)
+	})
+	s.Run("returns the images of the containers, init containers and hooks", func() {
+		s.Equal([]ChartImage{
+			{Image: "busybox:1.36", Resources: []string{"CronJob/cleanup", "Pod/pre-install"}},
+			{Image: "envoyproxy/envoy:v1.30", Resources: []string{"Deployment/web"}},
+			{Image: "registry.example.com/app:4.5", Resources: []string{"Deployment/web"}},
+		}, inventory.Images)
+	})
+}
+
+func (s *InventoryTestSuite) TestInventoryWithValues() {
+	inventory, err := NewHelm(nil).Inventory(s.T().Context(), s.chart, map[string]interface{}{
+		"image": "registry.example.com/app:4.6",
+		"cache": map[string]interface{}{"enabled": true},
+	}, nil, "default")
+	s.Require().NoError(err)
+	s.Run("returns the enabled subcharts", func() {
+		s.Require().Len(inventory.Dependencies, 1)
+		s.True(inventory.Dependencies[0].Enabled)
+	})
+	s.Run("returns the images rendered with the values", func() {
+		images := make([]string, 0, len(inventory.Images))
+		for _, image := range inventory.Images {
+			images = append(images, image.Image)
+		}
+		s.Equal([]string{"busybox:1.36", "envoyproxy/envoy:v1.30", "redis:7", "registry.example.com/app:4.6"}, images)
+	})
+}
+
+func TestInventory(t *testing.T) {
+	suite.Run(t, new(InventoryTestSuite))
+}
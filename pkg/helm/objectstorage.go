@@ -0,0 +1,63 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// downloadChart downloads the chart archive of the s3:// or gs:// reference into the directory and returns its path.
+// The reference is either a chart archive (s3://bucket/charts/app-1.0.0.tgz) or a chart of the repository indexed in the
+// parent path (s3://bucket/charts/app for the latest version, s3://bucket/charts/app?version=1.0.0 for another one)
+func (h *Helm) downloadChart(ctx context.Context, chart, dir string) (string, error) {
+	u, err := url.Parse(chart)
+	if err != nil {
+		return "", fmt.Errorf("invalid chart reference %s: %w", chart, err)
+	}
+	version := u.Query().Get("version")
+	u.RawQuery = ""
+	archiveURL := u.String()
+	if !strings.HasSuffix(u.Path, ".tgz") {
+		name := path.Base(u.Path)
+		u.Path = path.Dir(u.Path)
+		repositoryURL := strings.TrimSuffix(u.String(), "/")
+		data, err := h.cached(repositoryURL+"/index.yaml", true, func() ([]byte, error) {
+			return h.objectStorage.Get(ctx, repositoryURL+"/index.yaml")
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to download the index of the chart repository %s: %w", repositoryURL, err)
+		}
+		indexFile := filepath.Join(dir, "index.yaml")
+		if err = os.WriteFile(indexFile, data, 0o600); err != nil {
+			return "", err
+		}
+		index, err := repo.LoadIndexFile(indexFile)
+		if err != nil {
+			return "", fmt.Errorf("invalid index of the chart repository %s: %w", repositoryURL, err)
+		}
+		chartVersion, err := index.Get(name, version)
+		if err != nil {
+			return "", fmt.Errorf("chart %s not found in the repository %s: %w", name, repositoryURL, err)
+		}
+		if len(chartVersion.URLs) == 0 {
+			return "", fmt.Errorf("chart %s %s has no URL in the repository %s", name, chartVersion.Version, repositoryURL)
+		}
+		if archiveURL, err = repo.ResolveReferenceURL(repositoryURL, chartVersion.URLs[0]); err != nil {
+			return "", err
+		}
+	}
+	data, err := h.cached(archiveURL, false, func() ([]byte, error) {
+		return h.objectStorage.Get(ctx, archiveURL)
+	})
+	if err != nil {
+		return "", err
+	}
+	archive := filepath.Join(dir, "chart.tgz")
+	return archive, os.WriteFile(archive, data, 0o600)
+}
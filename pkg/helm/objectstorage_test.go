@@ -0,0 +1,91 @@
+package helm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/objectstorage"
+)
+
+type ObjectStorageTestSuite struct {
+	suite.Suite
+	helm *Helm
+}
+
+func (s *ObjectStorageTestSuite) SetupTest() {
+	s.T().Setenv("AWS_ACCESS_KEY_ID", "")
+	s.T().Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+	s.T().Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", "")
+	s.T().Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/charts/stable/index.yaml":
+			_, _ = w.Write([]byte(`apiVersion: v1
+entries:
+  app:
+  - name: app
+    version: 1.0.0
+    urls: [app-1.0.0.tgz]
+  - name: app
+    version: 1.1.0
+    urls: [s3://charts/archive/app-1.1.0.tgz]
+`))
+		case "/charts/stable/app-1.0.0.tgz", "/charts/archive/app-1.1.0.tgz":
+			_, _ = w.Write([]byte(req.URL.Path))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	s.T().Cleanup(server.Close)
+	s.helm = NewHelm(nil).WithObjectStorage(objectstorage.NewClient(&objectstorage.S3Config{Endpoint: server.URL}, nil))
+}
+
+func (s *ObjectStorageTestSuite) download(chart string) string {
+	archive, err := s.helm.downloadChart(s.T().Context(), chart, s.T().TempDir())
+	s.Require().NoError(err)
+	data, err := os.ReadFile(archive)
+	s.Require().NoError(err)
+	return string(data)
+}
+
+func (s *ObjectStorageTestSuite) TestDownloadChart() {
+	s.Run("downloads the chart archive", func() {
+		s.Equal("/charts/archive/app-1.1.0.tgz", s.download("s3://charts/archive/app-1.1.0.tgz"))
+	})
+	s.Run("downloads the latest version of the repository chart", func() {
+		s.Equal("/charts/archive/app-1.1.0.tgz", s.download("s3://charts/stable/app"))
+	})
+	s.Run("downloads the version of the repository chart relative to the index", func() {
+		s.Equal("/charts/stable/app-1.0.0.tgz", s.download("s3://charts/stable/app?version=1.0.0"))
+	})
+	s.Run("fails on the charts missing from the repository", func() {
+		_, err := s.helm.downloadChart(s.T().Context(), "s3://charts/stable/db", s.T().TempDir())
+		s.ErrorContains(err, "chart db not found in the repository s3://charts/stable")
+	})
+	s.Run("fails on the repositories without index", func() {
+		_, err := s.helm.downloadChart(s.T().Context(), "s3://charts/unknown/app", s.T().TempDir())
+		s.EqualError(err, "failed to download the index of the chart repository s3://charts/unknown: object s3://charts/unknown/index.yaml not found")
+	})
+}
+
+func (s *ObjectStorageTestSuite) TestDownloadChartCached() {
+	cacheConfig := &ChartCacheConfig{Dir: s.T().TempDir()}
+	s.helm.WithChartCache(cacheConfig)
+	s.Equal("/charts/stable/app-1.0.0.tgz", s.download("s3://charts/stable/app?version=1.0.0"))
+	s.Run("installs the cached charts in offline mode", func() {
+		cacheConfig.Offline = true
+		s.Equal("/charts/stable/app-1.0.0.tgz", s.download("s3://charts/stable/app?version=1.0.0"))
+	})
+	s.Run("fails on the uncached charts in offline mode", func() {
+		_, err := s.helm.downloadChart(s.T().Context(), "s3://charts/archive/app-1.1.0.tgz", s.T().TempDir())
+		s.EqualError(err, "s3://charts/archive/app-1.1.0.tgz is not available in the offline helm cache")
+	})
+}
+
+func TestObjectStorage(t *testing.T) {
+	suite.Run(t, new(ObjectStorageTestSuite))
+}
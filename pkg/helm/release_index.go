@@ -0,0 +1,318 @@
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// releaseIndexIdleTimeout stops the watch of the release Secrets not listed for the duration
+	releaseIndexIdleTimeout = 10 * time.Minute
+	// releaseIndexSyncTimeout is the maximum duration the first list waits for the initial list of the release Secrets
+	releaseIndexSyncTimeout = 30 * time.Second
+	// releaseIndexRetryInterval is the duration the releases are listed from the API server after the watch failed to sync
+	releaseIndexRetryInterval = time.Minute
+	// releaseIndexObserveTimeout bounds the wait for the index to observe the release installed or uninstalled by a call
+	releaseIndexObserveTimeout = 5 * time.Second
+)
+
+// releaseIndexes are the indexes of the releases per client, shared by the calls of the same identity
+var releaseIndexes = &releaseIndexRegistry{indexes: make(map[string]*releaseIndex)}
+
+type releaseIndexRegistry struct {
+	mu      sync.Mutex
+	indexes map[string]*releaseIndex
+}
+
+// releaseIndex watches the release Secrets (owner=helm) of all the namespaces and keeps their decoded releases, so
+// that listing the releases doesn't list and decode all the release Secrets on every call
+type releaseIndex struct {
+	informer  cache.SharedIndexInformer
+	stop      chan struct{}
+	ready     chan struct{}
+	err       error
+	failedAt  time.Time
+	idleTimer *time.Timer
+	mu        sync.RWMutex
+	// releases are the decoded releases by namespace/name of their Secret, without their chart templates and manifest
+	releases map[string]*release.Release
+}
+
+// existing returns the synced index of the credentials of the config, nil if there's none
+func (r *releaseIndexRegistry) existing(cfg *rest.Config) *releaseIndex {
+	r.mu.Lock()
+	index, ok := r.indexes[releaseIndexKey(cfg)]
+	r.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	select {
+	case <-index.ready:
+		if index.err == nil {
+			return index
+		}
+	default:
+	}
+	return nil
+}
+
+// releaseIndexKey identifies the index by the REST config of the client, which is kept for the lifetime of the client
+// of each identity, so that the index is never shared with a client of other credentials or access control
+func releaseIndexKey(cfg *rest.Config) string {
+	return fmt.Sprintf("%p", cfg)
+}
+
+// indexFor returns the index of the releases the credentials of the config can read, starting its watch if needed
+func (r *releaseIndexRegistry) indexFor(cfg *rest.Config, newClient func() (kubernetes.Interface, error)) *releaseIndex {
+	key := releaseIndexKey(cfg)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if index, ok := r.indexes[key]; ok {
+		select {
+		case <-index.ready:
+			if index.err == nil || time.Since(index.failedAt) < releaseIndexRetryInterval {
+				return index
+			}
+		default:
+			return index
+		}
+	}
+	index := &releaseIndex{stop: make(chan struct{}), ready: make(chan struct{}), releases: make(map[string]*release.Release)}
+	r.indexes[key] = index
+	index.idleTimer = time.AfterFunc(releaseIndexIdleTimeout, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.indexes[key] == index {
+			delete(r.indexes, key)
+		}
+		index.close()
+	})
+	client, err := newClient()
+	if err != nil {
+		index.fail(err)
+		return index
+	}
+	go index.start(client)
+	return index
+}
+
+// start runs the watch and waits for its initial list, an error of the first list or watch (e.g. forbidden) fails it
+func (i *releaseIndex) start(client kubernetes.Interface) {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0, informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.LabelSelector = labels.Set{"owner": "helm"}.String()
+	}))
+	i.informer = factory.Core().V1().Secrets().Informer()
+	failed := make(chan error, 1)
+	_ = i.informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		select {
+		case failed <- err:
+		default:
+		}
+	})
+	_, _ = i.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    i.update,
+		UpdateFunc: func(_, obj any) { i.update(obj) },
+		DeleteFunc: i.delete,
+	})
+	go i.informer.Run(i.stop)
+	ctx, cancel := context.WithTimeout(context.Background(), releaseIndexSyncTimeout)
+	defer cancel()
+	err := wait.PollUntilContextCancel(ctx, 50*time.Millisecond, true, func(context.Context) (bool, error) {
+		select {
+		case err := <-failed:
+			return false, err
+		default:
+			return i.informer.HasSynced(), nil
+		}
+	})
+	if err != nil {
+		i.fail(err)
+		return
+	}
+	close(i.ready)
+}
+
+func (i *releaseIndex) fail(err error) {
+	klog.V(2).Infof("Helm release index unavailable, listing the releases from the API server: %v", err)
+	i.err = err
+	i.failedAt = time.Now()
+	i.close()
+	close(i.ready)
+}
+
+func (i *releaseIndex) update(obj any) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	rls, err := decodeRelease(secret.Data["release"])
+	if err != nil {
+		klog.V(2).Infof("Failed to decode the Helm release of the Secret %s/%s: %v", secret.Namespace, secret.Name, err)
+		return
+	}
+	rls.Labels = secret.Labels
+	// the templates and manifest are the bulk of the release, and are not needed to list the releases
+	if rls.Chart != nil {
+		rls.Chart.Templates, rls.Chart.Files, rls.Chart.Raw = nil, nil, nil
+	}
+	rls.Manifest, rls.Hooks = "", nil
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.releases[secret.Namespace+"/"+secret.Name] = rls
+}
+
+func (i *releaseIndex) delete(obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.releases, secret.Namespace+"/"+secret.Name)
+}
+
+// wait returns true once the index is synced, false if it failed to sync or the context is done first
+func (i *releaseIndex) wait(ctx context.Context) bool {
+	select {
+	case <-i.ready:
+		if i.err != nil {
+			return false
+		}
+		i.idleTimer.Reset(releaseIndexIdleTimeout)
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// await waits for the index to observe the revision of the release in the namespace (its removal if the revision is 0),
+// so that the releases listed after an install or uninstall include its change
+func (i *releaseIndex) await(ctx context.Context, namespace, name string, revision int) {
+	ctx, cancel := context.WithTimeout(ctx, releaseIndexObserveTimeout)
+	defer cancel()
+	_ = wait.PollUntilContextCancel(ctx, 50*time.Millisecond, true, func(context.Context) (bool, error) {
+		releases, _ := i.driver(namespace).List(func(rls *release.Release) bool { return rls.Name == name })
+		if revision == 0 {
+			return len(releases) == 0, nil
+		}
+		return slices.ContainsFunc(releases, func(rls *release.Release) bool { return rls.Version == revision }), nil
+	})
+}
+
+func (i *releaseIndex) close() {
+	select {
+	case <-i.stop:
+	default:
+		close(i.stop)
+	}
+}
+
+// driver returns a read-only storage driver listing the indexed releases of the namespace (all namespaces if empty)
+func (i *releaseIndex) driver(namespace string) driver.Driver {
+	return &releaseIndexDriver{index: i, namespace: namespace}
+}
+
+// releaseIndexDriver serves the queries of the Helm storage from the index, the releases are not modified through it
+type releaseIndexDriver struct {
+	index     *releaseIndex
+	namespace string
+}
+
+var _ driver.Driver = (*releaseIndexDriver)(nil)
+
+var errReleaseIndexReadOnly = errors.New("the helm release index is read-only")
+
+func (d *releaseIndexDriver) Name() string {
+	return "ReleaseIndex"
+}
+
+func (d *releaseIndexDriver) Create(string, *release.Release) error {
+	return errReleaseIndexReadOnly
+}
+
+func (d *releaseIndexDriver) Update(string, *release.Release) error {
+	return errReleaseIndexReadOnly
+}
+
+func (d *releaseIndexDriver) Delete(string) (*release.Release, error) {
+	return nil, errReleaseIndexReadOnly
+}
+
+func (d *releaseIndexDriver) Get(key string) (*release.Release, error) {
+	d.index.mu.RLock()
+	defer d.index.mu.RUnlock()
+	for secretKey, rls := range d.index.releases {
+		namespace, name, _ := strings.Cut(secretKey, "/")
+		if name == key && (d.namespace == "" || d.namespace == namespace) {
+			return rls, nil
+		}
+	}
+	return nil, driver.ErrReleaseNotFound
+}
+
+func (d *releaseIndexDriver) List(filter func(*release.Release) bool) ([]*release.Release, error) {
+	var results []*release.Release
+	d.index.mu.RLock()
+	defer d.index.mu.RUnlock()
+	for secretKey, rls := range d.index.releases {
+		if namespace, _, _ := strings.Cut(secretKey, "/"); (d.namespace == "" || d.namespace == namespace) && filter(rls) {
+			results = append(results, rls)
+		}
+	}
+	return results, nil
+}
+
+func (d *releaseIndexDriver) Query(query map[string]string) ([]*release.Release, error) {
+	selector := labels.SelectorFromSet(query)
+	results, _ := d.List(func(rls *release.Release) bool { return selector.Matches(labels.Set(rls.Labels)) })
+	if len(results) == 0 {
+		return nil, driver.ErrReleaseNotFound
+	}
+	return results, nil
+}
+
+// decodeRelease decodes the release as stored by the Helm storage backend (base64 of the gzipped JSON)
+func decodeRelease(data []byte) (*release.Release, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, err
+	}
+	// the releases stored before the compression was introduced are plain JSON
+	if len(decoded) > 3 && bytes.Equal(decoded[:3], []byte{0x1f, 0x8b, 0x08}) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = reader.Close() }()
+		if decoded, err = io.ReadAll(reader); err != nil {
+			return nil, err
+		}
+	}
+	rls := &release.Release{}
+	return rls, json.Unmarshal(decoded, rls)
+}
@@ -0,0 +1,137 @@
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+type ReleaseIndexTestSuite struct {
+	suite.Suite
+	client *fake.Clientset
+	index  *releaseIndex
+}
+
+func releaseSecret(namespace, name string, revision int, status release.Status) *corev1.Secret {
+	rls := &release.Release{
+		Name:      name,
+		Namespace: namespace,
+		Version:   revision,
+		Info:      &release.Info{Status: status},
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "app", Version: "1.0.0"}, Templates: []*chart.File{{Name: "templates/cm.yaml"}}},
+		Manifest:  "apiVersion: v1\nkind: ConfigMap\n",
+	}
+	raw, _ := json.Marshal(rls)
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, _ = writer.Write(raw)
+	_ = writer.Close()
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, revision),
+			Labels:    map[string]string{"owner": "helm", "name": name, "status": status.String(), "version": fmt.Sprint(revision)},
+		},
+		Type: "helm.sh/release.v1",
+		Data: map[string][]byte{"release": []byte(base64.StdEncoding.EncodeToString(compressed.Bytes()))},
+	}
+}
+
+func (s *ReleaseIndexTestSuite) SetupTest() {
+	s.client = fake.NewClientset(
+		releaseSecret("default", "web", 1, release.StatusSuperseded),
+		releaseSecret("default", "web", 2, release.StatusDeployed),
+		releaseSecret("team-a", "db", 1, release.StatusFailed),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "not-a-release"}},
+	)
+	s.index = releaseIndexes.indexFor(&rest.Config{}, func() (kubernetes.Interface, error) { return s.client, nil })
+	s.T().Cleanup(s.index.close)
+	s.Require().True(s.index.wait(s.T().Context()), "expected the index to sync")
+}
+
+func (s *ReleaseIndexTestSuite) list(namespace string) []*release.Release {
+	releases, err := action.NewList(&action.Configuration{
+		Releases:   storage.Init(s.index.driver(namespace)),
+		KubeClient: &kubefake.PrintingKubeClient{Out: io.Discard},
+	}).Run()
+	s.Require().NoError(err)
+	return releases
+}
+
+func (s *ReleaseIndexTestSuite) TestList() {
+	s.Run("lists the latest revision of the releases of all namespaces", func() {
+		releases := s.list("")
+		s.Require().Len(releases, 2)
+		s.Equal("db", releases[0].Name)
+		s.Equal("web", releases[1].Name)
+		s.Equal(2, releases[1].Version)
+	})
+	s.Run("lists the releases of the namespace", func() {
+		releases := s.list("team-a")
+		s.Require().Len(releases, 1)
+		s.Equal("db", releases[0].Name)
+	})
+	s.Run("keeps the release labels", func() {
+		s.Equal("web", s.list("default")[0].Labels["name"])
+	})
+	s.Run("drops the templates and manifest of the releases", func() {
+		rls := s.list("default")[0]
+		s.Empty(rls.Manifest)
+		s.Empty(rls.Chart.Templates)
+		s.Equal("app", rls.Chart.Metadata.Name)
+	})
+}
+
+func (s *ReleaseIndexTestSuite) TestWatch() {
+	s.Run("observes the installed releases", func() {
+		_, err := s.client.CoreV1().Secrets("team-b").Create(s.T().Context(), releaseSecret("team-b", "cache", 1, release.StatusDeployed), metav1.CreateOptions{})
+		s.Require().NoError(err)
+		s.index.await(s.T().Context(), "team-b", "cache", 1)
+		s.Len(s.list("team-b"), 1)
+	})
+	s.Run("observes the uninstalled releases", func() {
+		s.Require().NoError(s.client.CoreV1().Secrets("team-a").Delete(s.T().Context(), "sh.helm.release.v1.db.v1", metav1.DeleteOptions{}))
+		s.index.await(s.T().Context(), "team-a", "db", 0)
+		s.Empty(s.list("team-a"))
+	})
+}
+
+func (s *ReleaseIndexTestSuite) TestDriver() {
+	s.Run("gets the release by the name of its Secret", func() {
+		rls, err := s.index.driver("default").Get("sh.helm.release.v1.web.v2")
+		s.Require().NoError(err)
+		s.Equal(2, rls.Version)
+	})
+	s.Run("doesn't get the releases of other namespaces", func() {
+		_, err := s.index.driver("team-a").Get("sh.helm.release.v1.web.v2")
+		s.Error(err)
+	})
+	s.Run("queries the releases by label", func() {
+		releases, err := s.index.driver("").Query(map[string]string{"name": "web", "status": "deployed"})
+		s.Require().NoError(err)
+		s.Len(releases, 1)
+	})
+	s.Run("is read-only", func() {
+		s.ErrorIs(s.index.driver("").Create("key", &release.Release{}), errReleaseIndexReadOnly)
+	})
+}
+
+func TestReleaseIndex(t *testing.T) {
+	suite.Run(t, new(ReleaseIndexTestSuite))
+}
@@ -0,0 +1,171 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RepairAuto rolls the pending revision forward if all its resources exist, back otherwise
+	RepairAuto = "auto"
+	// RepairForward marks the pending revision as deployed, its resources must all exist
+	RepairForward = "forward"
+	// RepairBack marks the pending revision as failed and rolls the release back to its last deployed revision
+	RepairBack = "back"
+)
+
+// RepairStrategies are the supported strategies of the repair of a release stuck in a pending status
+var RepairStrategies = []string{RepairAuto, RepairForward, RepairBack}
+
+// repairMinPendingAge is how long a revision must have been pending before being repaired, the operations of the
+// clients still running (e.g. waiting for the resources to be ready) must not be interrupted
+const repairMinPendingAge = 5 * time.Minute
+
+// RepairResult is the outcome of the repair of a release stuck in a pending status
+type RepairResult struct {
+	Release map[string]interface{} `json:"release"`
+	// PendingRevision is the revision that was stuck, and PendingStatus its status (e.g. pending-upgrade)
+	PendingRevision int    `json:"pendingRevision"`
+	PendingStatus   string `json:"pendingStatus"`
+	// Action is what the repair did: rolled-forward, rolled-back, or marked-failed (no revision to roll back to)
+	Action string `json:"action"`
+	// MissingResources are the resources of the pending revision missing from the cluster
+	MissingResources []string `json:"missingResources,omitempty"`
+}
+
+// Repair repairs the release left in a pending status (pending-install, pending-upgrade, or pending-rollback) by a
+// client that crashed or was interrupted, such releases block all further operations. The pending revision is either
+// rolled forward (marked as deployed, when all its resources exist) or back to the last deployed revision, according
+// to the strategy. Unless force is true, the revisions pending for less than 5 minutes are left to their client.
+func (h *Helm) Repair(ctx context.Context, name string, namespace string, strategy string, force bool) (string, error) {
+	cfg, err := h.newAction(h.kubernetes.NamespaceOrDefault(namespace), false)
+	if err != nil {
+		return "", err
+	}
+	result, err := repair(cfg, name, strategy, force, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if index := h.existingReleaseIndex(); index != nil {
+		index.await(ctx, h.kubernetes.NamespaceOrDefault(namespace), name, result.Release["revision"].(int))
+	}
+	out, err := yaml.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func repair(cfg *action.Configuration, name, strategy string, force bool, now time.Time) (*RepairResult, error) {
+	last, err := cfg.Releases.Last(name)
+	if err != nil {
+		return nil, err
+	}
+	if !last.Info.Status.IsPending() {
+		return nil, fmt.Errorf("release %s is not stuck, its last revision %d is %s", name, last.Version, last.Info.Status)
+	}
+	if pendingFor := now.Sub(last.Info.LastDeployed.Time); !force && pendingFor < repairMinPendingAge {
+		return nil, fmt.Errorf("revision %d of release %s has been %s for %s only, its client may still be running, retry later or force the repair",
+			last.Version, name, last.Info.Status, duration.HumanDuration(pendingFor))
+	}
+	result := &RepairResult{PendingRevision: last.Version, PendingStatus: last.Info.Status.String()}
+	if result.MissingResources, err = missingResources(cfg, last); err != nil {
+		return nil, err
+	}
+	if strategy == RepairAuto {
+		strategy = RepairForward
+		if len(result.MissingResources) > 0 {
+			strategy = RepairBack
+		}
+	}
+	switch strategy {
+	case RepairForward:
+		if len(result.MissingResources) > 0 {
+			return nil, fmt.Errorf("failed to roll revision %d of release %s forward, %d of its resources are missing: %s",
+				last.Version, name, len(result.MissingResources), strings.Join(result.MissingResources, ", "))
+		}
+		if err = rollForward(cfg, last); err != nil {
+			return nil, err
+		}
+		result.Action = "rolled-forward"
+	case RepairBack:
+		if result.Action, err = rollBack(cfg, last); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported repair strategy %s, valid strategies are: %s", strategy, strings.Join(RepairStrategies, ", "))
+	}
+	repaired, err := cfg.Releases.Last(name)
+	if err != nil {
+		return nil, err
+	}
+	result.Release = simplify(repaired)[0]
+	return result, nil
+}
+
+// rollForward marks the pending revision as deployed, superseding the previously deployed ones
+func rollForward(cfg *action.Configuration, pending *release.Release) error {
+	deployed, err := cfg.Releases.DeployedAll(pending.Name)
+	if err != nil && !errors.Is(err, driver.ErrNoDeployedReleases) {
+		return err
+	}
+	for _, r := range deployed {
+		r.Info.Status = release.StatusSuperseded
+		if err = cfg.Releases.Update(r); err != nil {
+			return err
+		}
+	}
+	pending.SetStatus(release.StatusDeployed, fmt.Sprintf("Repaired, %s rolled forward", pending.Info.Status))
+	return cfg.Releases.Update(pending)
+}
+
+// rollBack marks the pending revision as failed and rolls the release back to its last deployed revision if any
+func rollBack(cfg *action.Configuration, pending *release.Release) (string, error) {
+	pending.SetStatus(release.StatusFailed, fmt.Sprintf("Repaired, %s interrupted", pending.Info.Status))
+	if err := cfg.Releases.Update(pending); err != nil {
+		return "", err
+	}
+	deployed, err := cfg.Releases.Deployed(pending.Name)
+	if errors.Is(err, driver.ErrNoDeployedReleases) {
+		// e.g. interrupted first install, the failed release can be uninstalled or upgraded
+		return "marked-failed", nil
+	} else if err != nil {
+		return "", err
+	}
+	rollback := action.NewRollback(cfg)
+	rollback.Version = deployed.Version
+	rollback.Wait = true
+	rollback.Timeout = 5 * time.Minute
+	if err = rollback.Run(pending.Name); err != nil {
+		return "", fmt.Errorf("failed to roll release %s back to revision %d: %w", pending.Name, deployed.Version, err)
+	}
+	return "rolled-back", nil
+}
+
+// missingResources returns the resources of the revision that don't exist in the cluster
+func missingResources(cfg *action.Configuration, rel *release.Release) ([]string, error) {
+	resources, err := cfg.KubeClient.Build(bytes.NewBufferString(rel.Manifest), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the manifest of revision %d: %w", rel.Version, err)
+	}
+	var ret []string
+	for _, info := range resources {
+		if err = info.Get(); apierrors.IsNotFound(err) {
+			ret = append(ret, info.Mapping.GroupVersionKind.Kind+" "+strings.TrimPrefix(info.Namespace+"/"+info.Name, "/"))
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get %s %s: %w", info.Mapping.GroupVersionKind.Kind, info.Name, err)
+		}
+	}
+	return ret, nil
+}
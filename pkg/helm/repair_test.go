@@ -0,0 +1,112 @@
+package helm
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	helmtime "helm.sh/helm/v3/pkg/time"
+)
+
+type RepairTestSuite struct {
+	suite.Suite
+	cfg *action.Configuration
+	now time.Time
+}
+
+func (s *RepairTestSuite) SetupTest() {
+	s.now = time.Now()
+	s.cfg = &action.Configuration{
+		Releases:     storage.Init(driver.NewMemory()),
+		KubeClient:   &kubefake.PrintingKubeClient{Out: io.Discard},
+		Capabilities: chartutil.DefaultCapabilities,
+		Log:          func(string, ...interface{}) {},
+	}
+}
+
+func (s *RepairTestSuite) release(version int, status release.Status, deployedAgo time.Duration) {
+	s.Require().NoError(s.cfg.Releases.Create(&release.Release{
+		Name:      "app",
+		Namespace: "default",
+		Version:   version,
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "app", Version: "1.0.0"}},
+		Info:      &release.Info{Status: status, LastDeployed: helmtime.Time{Time: s.now.Add(-deployedAgo)}},
+	}))
+}
+
+func (s *RepairTestSuite) status(version int) release.Status {
+	r, err := s.cfg.Releases.Get("app", version)
+	s.Require().NoError(err)
+	return r.Info.Status
+}
+
+func (s *RepairTestSuite) TestRepairForward() {
+	s.release(1, release.StatusDeployed, time.Hour)
+	s.release(2, release.StatusPendingUpgrade, 10*time.Minute)
+	result, err := repair(s.cfg, "app", RepairAuto, false, s.now)
+	s.Require().NoError(err)
+	s.Run("rolls the pending revision forward if its resources exist", func() {
+		s.Equal("rolled-forward", result.Action)
+		s.Equal(2, result.PendingRevision)
+		s.Equal("pending-upgrade", result.PendingStatus)
+		s.Equal(2, result.Release["revision"])
+		s.Equal(release.StatusDeployed, s.status(2))
+	})
+	s.Run("supersedes the previously deployed revision", func() {
+		s.Equal(release.StatusSuperseded, s.status(1))
+	})
+}
+
+func (s *RepairTestSuite) TestRepairBack() {
+	s.release(1, release.StatusDeployed, time.Hour)
+	s.release(2, release.StatusPendingUpgrade, 10*time.Minute)
+	result, err := repair(s.cfg, "app", RepairBack, false, s.now)
+	s.Require().NoError(err)
+	s.Run("rolls the release back to the last deployed revision", func() {
+		s.Equal("rolled-back", result.Action)
+		s.Equal(3, result.Release["revision"])
+		s.Equal(release.StatusDeployed, s.status(3))
+	})
+	s.Run("marks the pending revision as failed", func() {
+		s.Equal(release.StatusFailed, s.status(2))
+	})
+}
+
+func (s *RepairTestSuite) TestRepairBackWithoutDeployedRevision() {
+	s.release(1, release.StatusPendingInstall, 10*time.Minute)
+	result, err := repair(s.cfg, "app", RepairBack, false, s.now)
+	s.Require().NoError(err)
+	s.Equal("marked-failed", result.Action)
+	s.Equal(release.StatusFailed, s.status(1))
+}
+
+func (s *RepairTestSuite) TestRepairRefused() {
+	s.Run("release not stuck", func() {
+		s.release(1, release.StatusDeployed, time.Hour)
+		_, err := repair(s.cfg, "app", RepairAuto, false, s.now)
+		s.EqualError(err, "release app is not stuck, its last revision 1 is deployed")
+	})
+	s.Run("recently pending revision", func() {
+		s.release(2, release.StatusPendingUpgrade, time.Minute)
+		_, err := repair(s.cfg, "app", RepairAuto, false, s.now)
+		s.EqualError(err, "revision 2 of release app has been pending-upgrade for 60s only, its client may still be running, retry later or force the repair")
+		s.Equal(release.StatusPendingUpgrade, s.status(2))
+	})
+	s.Run("recently pending revision with force", func() {
+		result, err := repair(s.cfg, "app", RepairAuto, true, s.now)
+		s.Require().NoError(err)
+		s.Equal("rolled-forward", result.Action)
+	})
+}
+
+func TestRepair(t *testing.T) {
+	suite.Run(t, new(RepairTestSuite))
+}
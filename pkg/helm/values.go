@@ -0,0 +1,262 @@
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+	"github.com/containers/kubernetes-mcp-server/pkg/sops"
+	"github.com/containers/kubernetes-mcp-server/pkg/vault"
+	"github.com/containers/kubernetes-mcp-server/pkg/workspace"
+)
+
+// SecretValueLabelPrefix prefixes the labels of the Helm releases recording the paths of their values resolved from
+// Vault or decrypted from SOPS at install time, so that the values shown or exported from the release are masked
+const SecretValueLabelPrefix = "kubernetes-mcp-server/secret-value-"
+
+// ValuesPreview returns the values an upgrade of the release with its deployed chart would compute from the values
+// files of the chart and the values, followed by their diff against the deployed values. The values of the deployed
+// revision are kept and overridden (as helm upgrade --reuse-values would), or discarded if reset is true (as helm
+// upgrade --reset-values would). Vault placeholders aren't resolved, and the values resolved from Vault or decrypted from
+// SOPS are masked.
+func (h *Helm) ValuesPreview(ctx context.Context, name string, namespace string, values map[string]interface{}, valuesFiles []string, reset bool) (string, error) {
+	cfg, err := h.newAction(h.kubernetes.NamespaceOrDefault(namespace), false)
+	if err != nil {
+		return "", err
+	}
+	deployed, err := cfg.Releases.Deployed(name)
+	if err != nil {
+		return "", err
+	}
+	if deployed.Chart == nil {
+		return "", fmt.Errorf("release %s has no chart", name)
+	}
+	overrides, secrets, err := h.valuesOf(ctx, deployed.Chart, valuesFiles, values)
+	if err != nil {
+		return "", err
+	}
+	return renderValuesPreview(deployed, overrides, secrets, reset)
+}
+
+// renderValuesPreview returns the computed values and their diff against the deployed values, with the values resolved
+// from Vault or decrypted from SOPS (recorded in the release, or in the secrets of the overrides) masked
+func renderValuesPreview(deployed *release.Release, overrides map[string]interface{}, secrets secretPaths, reset bool) (string, error) {
+	deployedValues, proposedValues, err := previewValues(deployed, overrides, reset)
+	if err != nil {
+		return "", err
+	}
+	isSecret := func(path string) bool { return secrets[path] || isSecretValue(deployed.Labels, path) }
+	deployedYaml, err := output.MarshalYaml(maskValues(deployedValues, nil, "", isSecret))
+	if err != nil {
+		return "", err
+	}
+	proposedYaml, err := output.MarshalYaml(maskValues(proposedValues, deployedValues, "", isSecret))
+	if err != nil {
+		return "", err
+	}
+	source := "chart defaults + values of revision " + fmt.Sprint(deployed.Version) + " + overrides"
+	if reset {
+		source = "chart defaults + overrides"
+	}
+	ret := fmt.Sprintf("# Computed values of release %s (%s)\n%s", deployed.Name, source, proposedYaml)
+	diff, err := output.DiffText(fmt.Sprintf("values of revision %d", deployed.Version), deployedYaml, proposedYaml)
+	if err != nil {
+		return "", err
+	}
+	if diff == "" {
+		return ret + "# No changes to the deployed values\n", nil
+	}
+	return ret + "# Changes to the deployed values (unified diff)\n" + diff, nil
+}
+
+// previewValues returns the computed values (chart defaults coalesced with the user-supplied values) of the deployed
+// release and those an upgrade with the overrides would compute, following the precedence of helm upgrade
+func previewValues(deployed *release.Release, overrides map[string]interface{}, reset bool) (chartutil.Values, chartutil.Values, error) {
+	deployedValues, err := chartutil.CoalesceValues(deployed.Chart, deployed.Config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute the deployed values: %w", err)
+	}
+	// CoalesceTables modifies the nested tables of both its arguments
+	userValues := copyValues(overrides)
+	if !reset {
+		userValues = chartutil.CoalesceTables(userValues, copyValues(deployed.Config))
+	}
+	proposedValues, err := chartutil.CoalesceValues(deployed.Chart, userValues)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute the values: %w", err)
+	}
+	return deployedValues, proposedValues, nil
+}
+
+// valuesOf merges in order the values files of the chart (or of the workspace of the session for the workspace:<path>
+// references), decrypting in memory the SOPS-encrypted ones, and overrides them with the provided values (as
+// helm install -f file --set would). It returns, in addition to the values, the paths of the decrypted values and of
+// the Vault placeholders.
+func (h *Helm) valuesOf(ctx context.Context, chartLoaded *chart.Chart, valuesFiles []string, values map[string]interface{}) (map[string]interface{}, secretPaths, error) {
+	merged := map[string]interface{}{}
+	secrets := secretPaths{}
+	for _, name := range valuesFiles {
+		var data []byte
+		if workspace.IsReference(name) {
+			var err error
+			if data, err = workspace.Resolve(ctx, name); err != nil {
+				return nil, nil, err
+			}
+		} else {
+			for _, file := range chartLoaded.Files {
+				if file.Name == path.Clean(strings.TrimPrefix(name, "/")) {
+					data = file.Data
+					break
+				}
+			}
+		}
+		if data == nil {
+			return nil, nil, fmt.Errorf("values file %s not found in chart %s", name, chartLoaded.Name())
+		}
+		fileValues := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &fileValues); err != nil {
+			return nil, nil, fmt.Errorf("invalid values file %s: %w", name, err)
+		}
+		if sops.IsEncrypted(fileValues) {
+			var err error
+			if fileValues, err = h.sops.Decrypt(fileValues); err != nil {
+				return nil, nil, fmt.Errorf("failed to decrypt values file %s: %w", name, err)
+			}
+			secrets.add(fileValues, "", func(interface{}) bool { return true })
+		}
+		merged = mergeValues(merged, fileValues)
+	}
+	merged = mergeValues(merged, values)
+	secrets.add(merged, "", vault.HasPlaceholders)
+	return merged, secrets, nil
+}
+
+// secretPaths are the paths (the keys joined with dots) of the values resolved from Vault or decrypted from SOPS
+type secretPaths map[string]bool
+
+// add adds the paths of the leaves (the values other than maps) of the values matching
+func (p secretPaths) add(values map[string]interface{}, prefix string, match func(interface{}) bool) {
+	for key, value := range values {
+		if nested, ok := value.(map[string]interface{}); ok {
+			p.add(nested, joinPath(prefix, key), match)
+		} else if match(value) {
+			p[joinPath(prefix, key)] = true
+		}
+	}
+}
+
+// labels returns the release labels recording the paths, hashed as the paths aren't valid label names
+func (p secretPaths) labels() map[string]string {
+	labels := make(map[string]string, len(p))
+	for secretPath := range p {
+		labels[secretValueLabel(secretPath)] = "true"
+	}
+	return labels
+}
+
+func secretValueLabel(secretPath string) string {
+	sum := sha256.Sum256([]byte(secretPath))
+	return SecretValueLabelPrefix + hex.EncodeToString(sum[:8])
+}
+
+// isSecretValue returns true if the labels of the release record the path as a secret value
+func isSecretValue(labels map[string]string, secretPath string) bool {
+	_, ok := labels[secretValueLabel(secretPath)]
+	return ok
+}
+
+// maskValues returns a copy of the values with the secret leaves replaced by output.Redacted, the Vault placeholders
+// are kept. The secret leaves differing from the previous values (if any) are marked as changed.
+func maskValues(values, previous map[string]interface{}, prefix string, isSecret func(string) bool) map[string]interface{} {
+	ret := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		valuePath := joinPath(prefix, key)
+		var previousValue interface{}
+		if previous != nil {
+			previousValue = previous[key]
+		}
+		switch {
+		case isMap(value):
+			previousMap, _ := toMap(previousValue)
+			if previous != nil && previousMap == nil {
+				previousMap = map[string]interface{}{}
+			}
+			valueMap, _ := toMap(value)
+			ret[key] = maskValues(valueMap, previousMap, valuePath, isSecret)
+		case !isSecret(valuePath) || vault.HasPlaceholders(value):
+			ret[key] = value
+		case previous != nil && !reflect.DeepEqual(value, previousValue):
+			ret[key] = output.Redacted + " (changed)"
+		default:
+			ret[key] = output.Redacted
+		}
+	}
+	return ret
+}
+
+func isMap(value interface{}) bool {
+	_, ok := toMap(value)
+	return ok
+}
+
+// toMap returns the map of the values, chartutil.Values included
+func toMap(value interface{}) (map[string]interface{}, bool) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return value, true
+	case chartutil.Values:
+		return value, true
+	}
+	return nil, false
+}
+
+// mergeValues merges the override values into the base ones, the nested maps are merged and the other values replaced
+func mergeValues(base, override map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		if overrideMap, ok := value.(map[string]interface{}); ok {
+			if baseMap, ok := merged[key].(map[string]interface{}); ok {
+				merged[key] = mergeValues(baseMap, overrideMap)
+				continue
+			}
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// copyValues returns a deep copy of the values, their nested maps and lists are copied
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		ret[key] = copyValue(value)
+	}
+	return ret
+}
+
+func copyValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return copyValues(value)
+	case []interface{}:
+		ret := make([]interface{}, len(value))
+		for i := range value {
+			ret[i] = copyValue(value[i])
+		}
+		return ret
+	}
+	return value
+}
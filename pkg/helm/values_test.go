@@ -0,0 +1,162 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/workspace"
+)
+
+type ValuesTestSuite struct {
+	suite.Suite
+	chart *chart.Chart
+}
+
+func (s *ValuesTestSuite) SetupTest() {
+	s.T().Setenv("SOPS_AGE_KEY_FILE", "")
+	s.T().Setenv("SOPS_AGE_KEY", "")
+	s.chart = &chart.Chart{
+		Metadata: &chart.Metadata{Name: "app"},
+		Files: []*chart.File{
+			{Name: "values-production.yaml", Data: []byte("replicas: 3\ndatabase:\n  host: db.prod\n  port: 5432\n")},
+			{Name: "secrets/production.enc.yaml", Data: []byte("database:\n  password: ENC[AES256_GCM,data:AA==,iv:AA==,tag:AA==,type:str]\nsops:\n  age: []\n  kms:\n  - arn: arn:aws:kms:eu-west-1:111122223333:key/app\n")},
+		},
+	}
+}
+
+func (s *ValuesTestSuite) TestValuesOf() {
+	s.Run("merges the values files and the values in order", func() {
+		values, _, err := NewHelm(nil).valuesOf(s.T().Context(), s.chart, []string{"./values-production.yaml"}, map[string]interface{}{
+			"replicas": 5,
+			"database": map[string]interface{}{"host": "db.staging"},
+		})
+		s.Require().NoError(err)
+		s.Equal(map[string]interface{}{
+			"replicas": 5,
+			"database": map[string]interface{}{"host": "db.staging", "port": float64(5432)},
+		}, values)
+	})
+	s.Run("fails on the files missing from the chart", func() {
+		_, _, err := NewHelm(nil).valuesOf(s.T().Context(), s.chart, []string{"values-staging.yaml"}, nil)
+		s.EqualError(err, "values file values-staging.yaml not found in chart app")
+	})
+	s.Run("decrypts the SOPS-encrypted files", func() {
+		_, _, err := NewHelm(nil).valuesOf(s.T().Context(), s.chart, []string{"secrets/production.enc.yaml"}, nil)
+		s.EqualError(err, "failed to decrypt values file secrets/production.enc.yaml: encrypted with kms keys, only age keys are supported")
+	})
+	s.Run("reads the files of the workspace of the session", func() {
+		w := workspace.New(s.T().TempDir())
+		defer w.Close()
+		_, err := w.Write("session", "values.yaml", []byte("replicas: 2\n"), 1024)
+		s.Require().NoError(err)
+		values, _, err := NewHelm(nil).valuesOf(workspace.NewContext(s.T().Context(), w, "session"), s.chart,
+			[]string{"values-production.yaml", "workspace:values.yaml"}, nil)
+		s.Require().NoError(err)
+		s.Equal(float64(2), values["replicas"])
+	})
+	s.Run("returns the paths of the Vault placeholders", func() {
+		_, secrets, err := NewHelm(nil).valuesOf(s.T().Context(), s.chart, []string{"values-production.yaml"}, map[string]interface{}{
+			"database": map[string]interface{}{"password": "vault:secret/data/app#password"},
+		})
+		s.Require().NoError(err)
+		s.Equal(secretPaths{"database.password": true}, secrets)
+	})
+	s.Run("fails on the workspace files without workspace", func() {
+		_, _, err := NewHelm(nil).valuesOf(s.T().Context(), s.chart, []string{"workspace:values.yaml"}, nil)
+		s.EqualError(err, "failed to read workspace:values.yaml, the workspace is not enabled")
+	})
+}
+
+func (s *ValuesTestSuite) TestPreviewValues() {
+	s.chart.Values = map[string]interface{}{
+		"replicas": float64(1),
+		"image":    map[string]interface{}{"repository": "app", "tag": "1.0"},
+		"debug":    false,
+	}
+	deployed := &release.Release{Chart: s.chart, Config: map[string]interface{}{
+		"replicas": float64(3),
+		"image":    map[string]interface{}{"tag": "1.1"},
+	}}
+	s.Run("computes the deployed values", func() {
+		deployedValues, _, err := previewValues(deployed, nil, false)
+		s.Require().NoError(err)
+		s.Equal(chartutil.Values{
+			"replicas": float64(3),
+			"image":    map[string]interface{}{"repository": "app", "tag": "1.1"},
+			"debug":    false,
+		}, deployedValues)
+	})
+	s.Run("overrides the values of the deployed revision", func() {
+		_, proposedValues, err := previewValues(deployed, map[string]interface{}{
+			"image": map[string]interface{}{"tag": "1.2"},
+			"debug": true,
+		}, false)
+		s.Require().NoError(err)
+		s.Equal(chartutil.Values{
+			"replicas": float64(3),
+			"image":    map[string]interface{}{"repository": "app", "tag": "1.2"},
+			"debug":    true,
+		}, proposedValues)
+	})
+	s.Run("discards the values of the deployed revision on reset", func() {
+		_, proposedValues, err := previewValues(deployed, map[string]interface{}{"debug": true}, true)
+		s.Require().NoError(err)
+		s.Equal(chartutil.Values{
+			"replicas": float64(1),
+			"image":    map[string]interface{}{"repository": "app", "tag": "1.0"},
+			"debug":    true,
+		}, proposedValues)
+	})
+	s.Run("reverts the values set to null to the chart defaults", func() {
+		_, proposedValues, err := previewValues(deployed, map[string]interface{}{"image": map[string]interface{}{"tag": nil}}, false)
+		s.Require().NoError(err)
+		s.Equal(map[string]interface{}{"repository": "app", "tag": "1.0"}, proposedValues["image"])
+	})
+	s.Run("removes the values set to null on reset", func() {
+		_, proposedValues, err := previewValues(deployed, map[string]interface{}{"image": map[string]interface{}{"tag": nil}}, true)
+		s.Require().NoError(err)
+		s.Equal(map[string]interface{}{"repository": "app"}, proposedValues["image"])
+	})
+	s.Run("does not modify the deployed release", func() {
+		s.Equal(map[string]interface{}{"replicas": float64(3), "image": map[string]interface{}{"tag": "1.1"}}, deployed.Config)
+	})
+}
+
+func (s *ValuesTestSuite) TestRenderValuesPreview() {
+	deployed := &release.Release{Name: "app", Version: 2, Chart: s.chart,
+		Config: map[string]interface{}{"database": map[string]interface{}{"host": "db.prod", "password": "s3cr3t-from-vault"}},
+		Labels: secretPaths{"database.password": true}.labels(),
+	}
+	s.Run("masks the secret values recorded in the release", func() {
+		preview, err := renderValuesPreview(deployed, map[string]interface{}{"replicas": 2}, secretPaths{}, false)
+		s.Require().NoError(err)
+		s.NotContains(preview, "s3cr3t-from-vault")
+		s.Contains(preview, "  password: '[REDACTED]'\n")
+		s.Contains(preview, "+replicas: 2\n")
+	})
+	s.Run("masks the decrypted values of the overrides", func() {
+		preview, err := renderValuesPreview(deployed, map[string]interface{}{
+			"database": map[string]interface{}{"password": "s3cr3t-from-sops", "user": "s3cr3t-user"},
+		}, secretPaths{"database.password": true, "database.user": true}, false)
+		s.Require().NoError(err)
+		s.NotContains(preview, "s3cr3t")
+		s.Contains(preview, "-  password: '[REDACTED]'\n+  password: '[REDACTED] (changed)'\n")
+		s.Contains(preview, "+  user: '[REDACTED] (changed)'\n")
+	})
+	s.Run("keeps the Vault placeholders of the overrides", func() {
+		preview, err := renderValuesPreview(deployed, map[string]interface{}{
+			"database": map[string]interface{}{"password": "vault:secret/data/app#password"},
+		}, secretPaths{"database.password": true}, false)
+		s.Require().NoError(err)
+		s.NotContains(preview, "s3cr3t-from-vault")
+		s.Contains(preview, "+  password: vault:secret/data/app#password\n")
+	})
+}
+
+func TestValues(t *testing.T) {
+	suite.Run(t, new(ValuesTestSuite))
+}
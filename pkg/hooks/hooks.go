@@ -0,0 +1,88 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+const requestTimeout = 5 * time.Second
+
+// Event is the payload sent to the configured event hooks.
+type Event struct {
+	Event     string    `json:"event"`
+	Timestamp time.Time `json:"timestamp"`
+	Server    string    `json:"server"`
+	SessionID string    `json:"sessionId,omitempty"`
+	Tool      string    `json:"tool,omitempty"`
+	Target    string    `json:"target,omitempty"`
+}
+
+// Dispatcher delivers events to webhooks asynchronously.
+// Delivery failures are logged and never affect the MCP session that triggered the event.
+type Dispatcher struct {
+	client  *http.Client
+	pending sync.WaitGroup
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{client: &http.Client{Timeout: requestTimeout}}
+}
+
+// Fire sends the event to every hook subscribed to it.
+func (d *Dispatcher) Fire(hooks []config.EventHookConfig, event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	for _, hook := range hooks {
+		if !hook.Accepts(event.Event) {
+			continue
+		}
+		d.pending.Add(1)
+		go func(hook config.EventHookConfig) {
+			defer d.pending.Done()
+			if err := d.send(hook, event); err != nil {
+				klog.V(1).Infof("Failed to deliver %s event hook to %s: %v", event.Event, hook.URL, err)
+			}
+		}(hook)
+	}
+}
+
+// Wait blocks until all in-flight deliveries complete.
+func (d *Dispatcher) Wait() {
+	d.pending.Wait()
+}
+
+func (d *Dispatcher) send(hook config.EventHookConfig, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
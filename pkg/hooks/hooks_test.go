@@ -0,0 +1,85 @@
+package hooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type HooksSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	mu       sync.Mutex
+	received []Event
+	headers  []http.Header
+}
+
+func (s *HooksSuite) SetupTest() {
+	s.received = nil
+	s.headers = nil
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		_ = json.NewDecoder(r.Body).Decode(&event)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.received = append(s.received, event)
+		s.headers = append(s.headers, r.Header.Clone())
+	}))
+}
+
+func (s *HooksSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *HooksSuite) TestFireDeliversEventPayload() {
+	d := NewDispatcher()
+	d.Fire([]config.EventHookConfig{{URL: s.server.URL}}, Event{Event: config.EventMutatingToolCall, SessionID: "1337", Tool: "pods_delete", Target: "ctx"})
+	d.Wait()
+	s.Require().Len(s.received, 1)
+	s.Run("payload contains event details", func() {
+		s.Equal(config.EventMutatingToolCall, s.received[0].Event)
+		s.Equal("1337", s.received[0].SessionID)
+		s.Equal("pods_delete", s.received[0].Tool)
+		s.Equal("ctx", s.received[0].Target)
+		s.False(s.received[0].Timestamp.IsZero(), "expected timestamp to be set")
+	})
+	s.Run("payload is JSON", func() {
+		s.Equal("application/json", s.headers[0].Get("Content-Type"))
+	})
+}
+
+func (s *HooksSuite) TestFireSendsConfiguredHeaders() {
+	d := NewDispatcher()
+	d.Fire([]config.EventHookConfig{{URL: s.server.URL, Headers: map[string]string{"Authorization": "Bearer secret"}}}, Event{Event: config.EventSessionStart})
+	d.Wait()
+	s.Require().Len(s.headers, 1)
+	s.Equal("Bearer secret", s.headers[0].Get("Authorization"))
+}
+
+func (s *HooksSuite) TestFireSkipsHooksNotSubscribedToEvent() {
+	d := NewDispatcher()
+	d.Fire([]config.EventHookConfig{
+		{URL: s.server.URL, Events: []string{config.EventSessionStart}},
+		{URL: s.server.URL, Events: []string{config.EventSessionEnd}},
+	}, Event{Event: config.EventSessionEnd})
+	d.Wait()
+	s.Require().Len(s.received, 1)
+	s.Equal(config.EventSessionEnd, s.received[0].Event)
+}
+
+func (s *HooksSuite) TestFireIgnoresUnreachableHooks() {
+	d := NewDispatcher()
+	d.Fire([]config.EventHookConfig{{URL: "http://127.0.0.1:1"}}, Event{Event: config.EventSessionStart})
+	d.Wait()
+	s.Empty(s.received)
+}
+
+func TestHooks(t *testing.T) {
+	suite.Run(t, new(HooksSuite))
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/diagnostics"
+)
+
+// ServeAdmin serves the diagnostics endpoints (/debug/pprof/ and /debug/runtime) on the address until the context is
+// done. The endpoints aren't authenticated, the address is expected to be local or otherwise protected.
+func ServeAdmin(ctx context.Context, address string) error {
+	adminServer := &http.Server{
+		Addr:              address,
+		Handler:           diagnostics.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		klog.V(0).Infof("Admin server starting on %s (endpoints: /debug/pprof/, /debug/runtime)", address)
+		if err := adminServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+	select {
+	case <-ctx.Done():
+	case err := <-serverErr:
+		return err
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return adminServer.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+// compressor is the common interface of the gzip and zstd writers
+type compressor interface {
+	io.Writer
+	Flush() error
+	Close() error
+	Reset(w io.Writer)
+}
+
+// compressors are the pools of the writers of each encoding, the zstd writers in particular are expensive to create
+var compressors = map[string]*sync.Pool{
+	config.CompressionGzip: {New: func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}},
+	config.CompressionZstd: {New: func() any {
+		w, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedDefault))
+		return &zstdCompressor{Encoder: w}
+	}},
+}
+
+type zstdCompressor struct {
+	*zstd.Encoder
+}
+
+func (z *zstdCompressor) Reset(w io.Writer) {
+	z.Encoder.Reset(w)
+}
+
+// CompressionMiddleware compresses the responses with the first of the configured encodings accepted by the client.
+// The streamed responses (SSE) are flushed through the compressor so that each event reaches the client as it's sent.
+func CompressionMiddleware(compression config.CompressionConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(compression.Encodings) == 0 {
+			return next
+		}
+		minSize := compression.MinSize
+		if minSize <= 0 {
+			minSize = config.DefaultCompressionMinSize
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), compression.Encodings)
+			w.Header().Add("Vary", "Accept-Encoding")
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cw := &compressResponseWriter{ResponseWriter: w, encoding: encoding, minSize: minSize}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding returns the first of the offered encodings accepted (with a non-zero quality) by the client
+func negotiateEncoding(acceptEncoding string, offered []string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q > 0
+	}
+	for _, encoding := range offered {
+		if accepted[encoding] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressResponseWriter decides whether to compress once the headers of the response are known: the responses
+// encoded by the handler, without a body, of a binary content type, or smaller than minSize are sent as is
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding   string
+	minSize    int
+	decided    bool
+	compressor compressor
+}
+
+func (cw *compressResponseWriter) WriteHeader(statusCode int) {
+	if !cw.decided {
+		cw.decided = true
+		if cw.compressible(statusCode) {
+			header := cw.Header()
+			header.Set("Content-Encoding", cw.encoding)
+			header.Del("Content-Length")
+			cw.compressor = compressors[cw.encoding].Get().(compressor)
+			cw.compressor.Reset(cw.ResponseWriter)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (cw *compressResponseWriter) compressible(statusCode int) bool {
+	header := cw.Header()
+	if statusCode < http.StatusOK || statusCode == http.StatusNoContent || statusCode == http.StatusNotModified ||
+		header.Get("Content-Encoding") != "" {
+		return false
+	}
+	if length, err := strconv.Atoi(header.Get("Content-Length")); err == nil && length < cw.minSize {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	return strings.HasPrefix(mediaType, "text/") ||
+		slices.Contains([]string{"application/json", "application/yaml", "application/javascript"}, mediaType)
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if !cw.decided {
+		if cw.Header().Get("Content-Type") == "" {
+			cw.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.compressor != nil {
+		return cw.compressor.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+func (cw *compressResponseWriter) Flush() {
+	if cw.compressor != nil {
+		_ = cw.compressor.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (cw *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := cw.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer (e.g. to set deadlines)
+func (cw *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// close completes the compressed stream and returns the compressor to its pool
+func (cw *compressResponseWriter) close() {
+	if cw.compressor == nil {
+		return
+	}
+	_ = cw.compressor.Close()
+	cw.compressor.Reset(io.Discard)
+	compressors[cw.encoding].Put(cw.compressor)
+	cw.compressor = nil
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type CompressionMiddlewareSuite struct {
+	suite.Suite
+	body    string
+	handler http.Handler
+}
+
+func (s *CompressionMiddlewareSuite) SetupTest() {
+	s.body = `{"result":"` + strings.Repeat("apiVersion: v1\nkind: Pod\n", 200) + `"}`
+	s.handler = CompressionMiddleware(config.CompressionConfig{Encodings: []string{config.CompressionZstd, config.CompressionGzip}})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/small":
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Length", "2")
+				_, _ = w.Write([]byte("{}"))
+			case "/encoded":
+				w.Header().Set("Content-Type", "text/plain")
+				w.Header().Set("Content-Encoding", "gzip")
+				_, _ = w.Write([]byte("already encoded"))
+			default:
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(s.body))
+			}
+		}))
+}
+
+func (s *CompressionMiddlewareSuite) serve(path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *CompressionMiddlewareSuite) TestNegotiation() {
+	s.Run("prefers the first configured encoding accepted by the client", func() {
+		rec := s.serve("/mcp", "gzip, deflate, br, zstd")
+		s.Equal("zstd", rec.Header().Get("Content-Encoding"))
+		decoder, err := zstd.NewReader(rec.Body)
+		s.Require().NoError(err)
+		defer decoder.Close()
+		body, err := io.ReadAll(decoder)
+		s.Require().NoError(err)
+		s.Equal(s.body, string(body))
+		s.Less(rec.Body.Len(), len(s.body))
+	})
+	s.Run("falls back to the encodings accepted by the client", func() {
+		rec := s.serve("/mcp", "gzip;q=0.8, zstd;q=0")
+		s.Equal("gzip", rec.Header().Get("Content-Encoding"))
+		reader, err := gzip.NewReader(rec.Body)
+		s.Require().NoError(err)
+		body, err := io.ReadAll(reader)
+		s.Require().NoError(err)
+		s.Equal(s.body, string(body))
+	})
+	s.Run("sends the response as is to the clients not accepting any encoding", func() {
+		rec := s.serve("/mcp", "")
+		s.Empty(rec.Header().Get("Content-Encoding"))
+		s.Equal(s.body, rec.Body.String())
+		s.Equal("Accept-Encoding", rec.Header().Get("Vary"))
+	})
+}
+
+func (s *CompressionMiddlewareSuite) TestUncompressed() {
+	s.Run("responses smaller than the minimum size", func() {
+		rec := s.serve("/small", "zstd")
+		s.Empty(rec.Header().Get("Content-Encoding"))
+		s.Equal("{}", rec.Body.String())
+	})
+	s.Run("responses encoded by the handler", func() {
+		rec := s.serve("/encoded", "zstd")
+		s.Equal("gzip", rec.Header().Get("Content-Encoding"))
+		s.Equal("already encoded", rec.Body.String())
+	})
+	s.Run("disabled compression", func() {
+		rec := httptest.NewRecorder()
+		CompressionMiddleware(config.CompressionConfig{})(s.handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/mcp", nil))
+		s.Empty(rec.Header().Get("Content-Encoding"))
+	})
+}
+
+func (s *CompressionMiddlewareSuite) TestStreaming() {
+	sent := make(chan struct{})
+	done := make(chan struct{})
+	server := httptest.NewServer(CompressionMiddleware(config.CompressionConfig{Encodings: []string{config.CompressionGzip}})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("event: message\ndata: {\"id\":1}\n\n"))
+			w.(http.Flusher).Flush()
+			close(sent)
+			<-done
+		})))
+	defer server.Close()
+	defer close(done)
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/sse", nil)
+	s.Require().NoError(err)
+	// Setting the header explicitly disables the transparent decompression of the client
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer func() { _ = resp.Body.Close() }()
+	s.Equal("gzip", resp.Header.Get("Content-Encoding"))
+	<-sent
+	reader, err := gzip.NewReader(resp.Body)
+	s.Require().NoError(err)
+	line, err := bufio.NewReader(reader).ReadString('\n')
+	s.Require().NoError(err)
+	s.Equal("event: message\n", line, "expected the flushed event to be readable before the stream ends")
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	suite.Run(t, new(CompressionMiddlewareSuite))
+}
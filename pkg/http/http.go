@@ -81,7 +81,9 @@ func Serve(ctx context.Context, mcpServer *mcp.Server, staticConfig *config.Stat
 	mux := http.NewServeMux()
 
 	wrappedMux := RequestMiddleware(
-		AuthorizationMiddleware(staticConfig, oidcProvider)(mux),
+		AuthorizationMiddleware(staticConfig, oidcProvider)(
+			CompressionMiddleware(staticConfig.HTTPCompression)(mux),
+		),
 	)
 
 	// Wrap with metrics middleware
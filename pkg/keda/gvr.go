@@ -0,0 +1,48 @@
+package keda
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// KEDA resources
+var (
+	// ScaledObjectGVR is the GroupVersionResource for ScaledObject resources
+	ScaledObjectGVR = schema.GroupVersionResource{
+		Group:    "keda.sh",
+		Version:  "v1alpha1",
+		Resource: "scaledobjects",
+	}
+
+	// ScaledJobGVR is the GroupVersionResource for ScaledJob resources
+	ScaledJobGVR = schema.GroupVersionResource{
+		Group:    "keda.sh",
+		Version:  "v1alpha1",
+		Resource: "scaledjobs",
+	}
+
+	horizontalPodAutoscalerGVR = schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}
+	eventGVR                   = schema.GroupVersionResource{Version: "v1", Resource: "events"}
+)
+
+const (
+	// ScaledObjectKind is the kind of the ScaledObject resources
+	ScaledObjectKind = "ScaledObject"
+	// ScaledJobKind is the kind of the ScaledJob resources
+	ScaledJobKind = "ScaledJob"
+)
+
+// Kinds are the kinds of the KEDA resources supported by the keda toolset
+var Kinds = []string{ScaledObjectKind, ScaledJobKind}
+
+// ResourceFor returns the GroupVersionResource of the provided KEDA kind
+func ResourceFor(kind string) (schema.GroupVersionResource, error) {
+	switch kind {
+	case ScaledObjectKind:
+		return ScaledObjectGVR, nil
+	case ScaledJobKind:
+		return ScaledJobGVR, nil
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("unsupported KEDA kind %s, supported kinds are %v", kind, Kinds)
+}
@@ -0,0 +1,326 @@
+package keda
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/crdstatus"
+)
+
+const (
+	// PausedAnnotation pauses the autoscaling of a ScaledObject or ScaledJob
+	PausedAnnotation = "autoscaling.keda.sh/paused"
+	// PausedReplicasAnnotation pauses the autoscaling of a ScaledObject at the provided number of replicas
+	PausedReplicasAnnotation = "autoscaling.keda.sh/paused-replicas"
+
+	// defaultMaxReplicaCount is the maxReplicaCount of the ScaledObjects and ScaledJobs if not provided
+	defaultMaxReplicaCount = 100
+	// maxScalingEvents is the maximum number of scaling events returned for a ScaledObject or ScaledJob
+	maxScalingEvents = 20
+)
+
+// ScaledResource is the scaling status of a KEDA ScaledObject or ScaledJob.
+type ScaledResource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Target is the workload scaled by a ScaledObject (Kind/name) or Job for a ScaledJob
+	Target      string `json:"target"`
+	MinReplicas int64  `json:"minReplicas"`
+	MaxReplicas int64  `json:"maxReplicas"`
+	// CurrentReplicas and DesiredReplicas are the replicas of the HorizontalPodAutoscaler managed by a ScaledObject
+	CurrentReplicas *int64 `json:"currentReplicas,omitempty"`
+	DesiredReplicas *int64 `json:"desiredReplicas,omitempty"`
+	// Ready is the status of the Ready condition (True, False, or Unknown)
+	Ready string `json:"ready"`
+	// Active is the status of the Active condition, True when at least one trigger is active (scaling from zero)
+	Active string `json:"active"`
+	Paused bool   `json:"paused,omitempty"`
+	// Fallback is true when the triggers are failing and the fallback replicas are used
+	Fallback       bool      `json:"fallback,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	LastActiveTime string    `json:"lastActiveTime,omitempty"`
+	Triggers       []Trigger `json:"triggers"`
+	// ScalingEvents are the most recent scaling decisions of KEDA and of the HorizontalPodAutoscaler, newest first
+	ScalingEvents []ScalingEvent `json:"scalingEvents,omitempty"`
+}
+
+// Trigger is a scaler of a ScaledObject or ScaledJob with its health and its current and target metric values.
+type Trigger struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	// MetricName is the name of the external metric exposed by KEDA to the HorizontalPodAutoscaler
+	MetricName string `json:"metricName,omitempty"`
+	// Health is the status of the scaler (Happy or Failure) and Failures the number of consecutive failures
+	Health            string            `json:"health,omitempty"`
+	Failures          int64             `json:"failures,omitempty"`
+	Current           string            `json:"current,omitempty"`
+	Target            string            `json:"target,omitempty"`
+	AuthenticationRef string            `json:"authenticationRef,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+}
+
+// ScalingEvent is an event of a ScaledObject, a ScaledJob, or their HorizontalPodAutoscaler.
+type ScalingEvent struct {
+	Time    string `json:"time"`
+	Object  string `json:"object"`
+	Type    string `json:"type"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	Count   int64  `json:"count,omitempty"`
+}
+
+// ListScaledResources lists the KEDA resources of the provided GroupVersionResource in the namespace (all namespaces if empty)
+func ListScaledResources(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace string) ([]ScaledResource, error) {
+	list, err := client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	hpas := map[string]*unstructured.Unstructured{}
+	if gvr == ScaledObjectGVR && len(list.Items) > 0 {
+		// The HorizontalPodAutoscalers are best-effort, they might not be accessible
+		if hpaList, err := client.Resource(horizontalPodAutoscalerGVR).Namespace(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+			for i := range hpaList.Items {
+				hpas[hpaList.Items[i].GetNamespace()+"/"+hpaList.Items[i].GetName()] = &hpaList.Items[i]
+			}
+		}
+	}
+	resources := make([]ScaledResource, 0, len(list.Items))
+	for _, item := range list.Items {
+		resources = append(resources, Summarize(&item, hpas[item.GetNamespace()+"/"+hpaName(&item)], false))
+	}
+	return resources, nil
+}
+
+// GetScaledResource returns the scaling status of a KEDA resource with the metadata of its triggers and its recent scaling events
+func GetScaledResource(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string) (*ScaledResource, error) {
+	obj, err := client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var hpa *unstructured.Unstructured
+	var managedHPA string
+	if gvr == ScaledObjectGVR {
+		managedHPA = hpaName(obj)
+		// The HorizontalPodAutoscaler is best-effort, it might not exist yet or not be accessible
+		hpa, _ = client.Resource(horizontalPodAutoscalerGVR).Namespace(namespace).Get(ctx, managedHPA, metav1.GetOptions{})
+	}
+	resource := Summarize(obj, hpa, true)
+	// The events are best-effort, they might not be accessible
+	if events, err := client.Resource(eventGVR).Namespace(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		resource.ScalingEvents = ScalingEvents(events.Items, obj.GetKind(), obj.GetName(), managedHPA)
+	}
+	return &resource, nil
+}
+
+// SortScaledResources sorts the not ready resources first, then by kind, namespace, and name
+func SortScaledResources(resources []ScaledResource) {
+	crdstatus.Sort(resources, func(resource ScaledResource) crdstatus.Key {
+		return crdstatus.Key{Rank: readyRank(resource), Kind: resource.Kind, Namespace: resource.Namespace, Name: resource.Name}
+	})
+}
+
+// Summarize returns the scaling status of the provided ScaledObject or ScaledJob, with the current and target metric
+// values of the triggers from the HorizontalPodAutoscaler managed by the ScaledObject (if provided).
+func Summarize(obj *unstructured.Unstructured, hpa *unstructured.Unstructured, withMetadata bool) ScaledResource {
+	resource := ScaledResource{
+		Kind:        obj.GetKind(),
+		Namespace:   obj.GetNamespace(),
+		Name:        obj.GetName(),
+		Target:      "Job",
+		MaxReplicas: defaultMaxReplicaCount,
+		Ready:       string(metav1.ConditionUnknown),
+		Active:      string(metav1.ConditionUnknown),
+	}
+	if obj.GetKind() == ScaledObjectKind {
+		targetKind, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "kind")
+		targetName, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "name")
+		resource.Target = cmp.Or(targetKind, "Deployment") + "/" + targetName
+	}
+	if minReplicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "minReplicaCount"); found {
+		resource.MinReplicas = minReplicas
+	}
+	if maxReplicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "maxReplicaCount"); found {
+		resource.MaxReplicas = maxReplicas
+	}
+	for _, condition := range crdstatus.Conditions(obj.Object) {
+		switch condition.Type {
+		case "Ready":
+			resource.Ready = condition.Status
+			if condition.Status != string(metav1.ConditionTrue) {
+				resource.Message = condition.Message
+			}
+		case "Active":
+			resource.Active = condition.Status
+		case "Fallback":
+			resource.Fallback = condition.Status == string(metav1.ConditionTrue)
+		case "Paused":
+			resource.Paused = condition.Status == string(metav1.ConditionTrue)
+		}
+	}
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[PausedReplicasAnnotation]; ok || annotations[PausedAnnotation] == "true" {
+		resource.Paused = true
+	}
+	resource.LastActiveTime, _, _ = unstructured.NestedString(obj.Object, "status", "lastActiveTime")
+	if hpa != nil {
+		if current, found, _ := unstructured.NestedInt64(hpa.Object, "status", "currentReplicas"); found {
+			resource.CurrentReplicas = &current
+		}
+		if desired, found, _ := unstructured.NestedInt64(hpa.Object, "status", "desiredReplicas"); found {
+			resource.DesiredReplicas = &desired
+		}
+	}
+	resource.Triggers = triggers(obj, hpa, withMetadata)
+	return resource
+}
+
+func triggers(obj *unstructured.Unstructured, hpa *unstructured.Unstructured, withMetadata bool) []Trigger {
+	externalMetricNames, _, _ := unstructured.NestedStringSlice(obj.Object, "status", "externalMetricNames")
+	health, _, _ := unstructured.NestedMap(obj.Object, "status", "health")
+	specTriggers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "triggers")
+	ret := make([]Trigger, 0, len(specTriggers))
+	for i, specTrigger := range specTriggers {
+		specTrigger, _ := specTrigger.(map[string]any)
+		trigger := Trigger{}
+		trigger.Type, _ = specTrigger["type"].(string)
+		trigger.Name, _ = specTrigger["name"].(string)
+		trigger.AuthenticationRef, _, _ = unstructured.NestedString(specTrigger, "authenticationRef", "name")
+		if withMetadata {
+			trigger.Metadata, _, _ = unstructured.NestedStringMap(specTrigger, "metadata")
+		}
+		switch trigger.Type {
+		case "cpu", "memory":
+			// The cpu and memory triggers are Resource metrics of the HorizontalPodAutoscaler
+			trigger.MetricName = trigger.Type
+		default:
+			// KEDA names the external metrics of the triggers s<trigger index>-<trigger type>-<...>
+			prefix := fmt.Sprintf("s%d-", i)
+			if index := slices.IndexFunc(externalMetricNames, func(name string) bool { return strings.HasPrefix(name, prefix) }); index >= 0 {
+				trigger.MetricName = externalMetricNames[index]
+			}
+		}
+		if triggerHealth, ok := health[trigger.MetricName].(map[string]any); ok {
+			trigger.Health, _ = triggerHealth["status"].(string)
+			trigger.Failures, _, _ = unstructured.NestedInt64(triggerHealth, "numberOfFailures")
+		}
+		if hpa != nil && trigger.MetricName != "" {
+			trigger.Current, trigger.Target = hpaMetricValues(hpa, trigger.MetricName)
+		}
+		ret = append(ret, trigger)
+	}
+	return ret
+}
+
+// hpaMetricValues returns the current and target values of an External or Resource metric of the HorizontalPodAutoscaler
+func hpaMetricValues(hpa *unstructured.Unstructured, metricName string) (current, target string) {
+	specMetrics, _, _ := unstructured.NestedSlice(hpa.Object, "spec", "metrics")
+	for _, metric := range specMetrics {
+		metric, _ := metric.(map[string]any)
+		if name, values := hpaMetric(metric); name == metricName {
+			target = values
+		}
+	}
+	currentMetrics, _, _ := unstructured.NestedSlice(hpa.Object, "status", "currentMetrics")
+	for _, metric := range currentMetrics {
+		metric, _ := metric.(map[string]any)
+		if name, values := hpaMetric(metric); name == metricName {
+			current = values
+		}
+	}
+	return current, target
+}
+
+// hpaMetric returns the name and the value (target or current) of an External or Resource HorizontalPodAutoscaler metric
+func hpaMetric(metric map[string]any) (string, string) {
+	var name, source string
+	switch metric["type"] {
+	case "External":
+		source = "external"
+		name, _, _ = unstructured.NestedString(metric, source, "metric", "name")
+	case "Resource":
+		source = "resource"
+		name, _, _ = unstructured.NestedString(metric, source, "name")
+	default:
+		return "", ""
+	}
+	// The spec metrics have a target, the status metrics a current value
+	values, found, _ := unstructured.NestedMap(metric, source, "target")
+	if !found {
+		values, _, _ = unstructured.NestedMap(metric, source, "current")
+	}
+	switch {
+	case values["averageValue"] != nil:
+		return name, fmt.Sprintf("%v (average)", values["averageValue"])
+	case values["averageUtilization"] != nil:
+		return name, fmt.Sprintf("%v%%", values["averageUtilization"])
+	case values["value"] != nil:
+		return name, fmt.Sprint(values["value"])
+	}
+	return name, ""
+}
+
+// ScalingEvents returns the most recent events of the ScaledObject or ScaledJob and of its HorizontalPodAutoscaler, newest first
+func ScalingEvents(events []unstructured.Unstructured, kind, name, hpaName string) []ScalingEvent {
+	scalingEvents := make([]ScalingEvent, 0)
+	for _, event := range events {
+		involvedKind, _, _ := unstructured.NestedString(event.Object, "involvedObject", "kind")
+		involvedName, _, _ := unstructured.NestedString(event.Object, "involvedObject", "name")
+		if !(involvedKind == kind && involvedName == name) && !(involvedKind == "HorizontalPodAutoscaler" && involvedName == hpaName) {
+			continue
+		}
+		scalingEvent := ScalingEvent{Time: eventTime(&event), Object: involvedKind + "/" + involvedName}
+		scalingEvent.Type, _, _ = unstructured.NestedString(event.Object, "type")
+		scalingEvent.Reason, _, _ = unstructured.NestedString(event.Object, "reason")
+		scalingEvent.Message, _, _ = unstructured.NestedString(event.Object, "message")
+		scalingEvent.Count, _, _ = unstructured.NestedInt64(event.Object, "count")
+		scalingEvents = append(scalingEvents, scalingEvent)
+	}
+	// The times are RFC3339 timestamps, sorting them as strings sorts them chronologically
+	slices.SortStableFunc(scalingEvents, func(a, b ScalingEvent) int { return strings.Compare(b.Time, a.Time) })
+	if len(scalingEvents) > maxScalingEvents {
+		scalingEvents = scalingEvents[:maxScalingEvents]
+	}
+	return scalingEvents
+}
+
+// eventTime returns the last time the event was observed (lastTimestamp for core events, eventTime for events.k8s.io events)
+func eventTime(event *unstructured.Unstructured) string {
+	for _, field := range []string{"lastTimestamp", "eventTime", "firstTimestamp"} {
+		if timestamp, _, _ := unstructured.NestedString(event.Object, field); timestamp != "" {
+			return timestamp
+		}
+	}
+	return event.GetCreationTimestamp().UTC().Format(time.RFC3339)
+}
+
+// hpaName returns the name of the HorizontalPodAutoscaler managed by the ScaledObject
+func hpaName(obj *unstructured.Unstructured) string {
+	if name, _, _ := unstructured.NestedString(obj.Object, "status", "hpaName"); name != "" {
+		return name
+	}
+	if name, _, _ := unstructured.NestedString(obj.Object, "spec", "advanced", "horizontalPodAutoscalerConfig", "name"); name != "" {
+		return name
+	}
+	return "keda-hpa-" + obj.GetName()
+}
+
+// readyRank ranks the resources falling back to their fallback replicas right after the not ready ones
+func readyRank(resource ScaledResource) int {
+	switch {
+	case resource.Ready == string(metav1.ConditionFalse):
+		return 0
+	case resource.Fallback:
+		return 1
+	}
+	return 1 + crdstatus.ReadyRank(resource.Ready, resource.Paused)
+}
@@ -0,0 +1,163 @@
+package keda
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+type KedaTestSuite struct {
+	suite.Suite
+}
+
+func scaledObject(name string, ready string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "keda.sh/v1alpha1",
+		"kind":       "ScaledObject",
+		"metadata":   map[string]any{"name": name, "namespace": "orders"},
+		"spec": map[string]any{
+			"scaleTargetRef":  map[string]any{"name": name},
+			"minReplicaCount": int64(1),
+			"triggers": []any{
+				map[string]any{"type": "cpu", "metadata": map[string]any{"value": "80"}},
+				map[string]any{"type": "rabbitmq", "metadata": map[string]any{"queueName": "orders", "value": "20"}, "authenticationRef": map[string]any{"name": "rabbitmq-auth"}},
+			},
+		},
+		"status": map[string]any{
+			"hpaName":             "keda-hpa-" + name,
+			"externalMetricNames": []any{"s1-rabbitmq-orders"},
+			"lastActiveTime":      "2025-01-01T14:00:00Z",
+			"health":              map[string]any{"s1-rabbitmq-orders": map[string]any{"numberOfFailures": int64(3), "status": "Failure"}},
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": ready, "message": "error connecting to rabbitmq"},
+				map[string]any{"type": "Active", "status": "True"},
+				map[string]any{"type": "Fallback", "status": "False"},
+			},
+		},
+	}}
+}
+
+func hpa(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "autoscaling/v2",
+		"kind":       "HorizontalPodAutoscaler",
+		"metadata":   map[string]any{"name": "keda-hpa-" + name, "namespace": "orders"},
+		"spec": map[string]any{"metrics": []any{
+			map[string]any{"type": "Resource", "resource": map[string]any{"name": "cpu", "target": map[string]any{"type": "Utilization", "averageUtilization": int64(80)}}},
+			map[string]any{"type": "External", "external": map[string]any{
+				"metric": map[string]any{"name": "s1-rabbitmq-orders"},
+				"target": map[string]any{"type": "AverageValue", "averageValue": "20"},
+			}},
+		}},
+		"status": map[string]any{
+			"currentReplicas": int64(3),
+			"desiredReplicas": int64(5),
+			"currentMetrics": []any{
+				map[string]any{"type": "Resource", "resource": map[string]any{"name": "cpu", "current": map[string]any{"averageUtilization": int64(35)}}},
+				map[string]any{"type": "External", "external": map[string]any{
+					"metric":  map[string]any{"name": "s1-rabbitmq-orders"},
+					"current": map[string]any{"averageValue": "33500m"},
+				}},
+			},
+		},
+	}}
+}
+
+func event(involvedKind, involvedName, reason, lastTimestamp string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion":     "v1",
+		"kind":           "Event",
+		"metadata":       map[string]any{"name": involvedName + "." + reason, "namespace": "orders"},
+		"involvedObject": map[string]any{"kind": involvedKind, "name": involvedName},
+		"type":           "Normal",
+		"reason":         reason,
+		"message":        reason + " message",
+		"lastTimestamp":  lastTimestamp,
+	}}
+}
+
+func (s *KedaTestSuite) client(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		ScaledObjectGVR:            "ScaledObjectList",
+		ScaledJobGVR:               "ScaledJobList",
+		horizontalPodAutoscalerGVR: "HorizontalPodAutoscalerList",
+		eventGVR:                   "EventList",
+	}, objects...)
+}
+
+func (s *KedaTestSuite) TestListScaledResources() {
+	resources, err := ListScaledResources(s.T().Context(), s.client(
+		scaledObject("worker", "True"),
+		scaledObject("consumer", "False"),
+		hpa("worker"),
+	), ScaledObjectGVR, "")
+	s.Require().NoError(err)
+	SortScaledResources(resources)
+	s.Require().Len(resources, 2)
+	s.Run("sorts not ready resources first", func() {
+		s.Equal("consumer", resources[0].Name)
+		s.Equal("error connecting to rabbitmq", resources[0].Message)
+		s.Nil(resources[0].CurrentReplicas, "the HorizontalPodAutoscaler of consumer does not exist")
+	})
+	s.Run("summarizes the replicas and conditions", func() {
+		s.Equal("Deployment/worker", resources[1].Target)
+		s.Equal(int64(1), resources[1].MinReplicas)
+		s.Equal(int64(100), resources[1].MaxReplicas)
+		s.Equal(int64(3), *resources[1].CurrentReplicas)
+		s.Equal(int64(5), *resources[1].DesiredReplicas)
+		s.Equal("True", resources[1].Active)
+		s.Empty(resources[1].Message)
+	})
+	s.Run("returns the current and target values of the triggers", func() {
+		s.Equal([]Trigger{
+			{Type: "cpu", MetricName: "cpu", Current: "35%", Target: "80%"},
+			{Type: "rabbitmq", MetricName: "s1-rabbitmq-orders", Health: "Failure", Failures: 3, Current: "33500m (average)", Target: "20 (average)", AuthenticationRef: "rabbitmq-auth"},
+		}, resources[1].Triggers)
+	})
+}
+
+func (s *KedaTestSuite) TestGetScaledResource() {
+	resource, err := GetScaledResource(s.T().Context(), s.client(
+		scaledObject("worker", "True"),
+		hpa("worker"),
+		event("HorizontalPodAutoscaler", "keda-hpa-worker", "SuccessfulRescale", "2025-01-01T14:05:00Z"),
+		event("ScaledObject", "worker", "KEDAScaleTargetActivated", "2025-01-01T14:00:00Z"),
+		event("Deployment", "worker", "ScalingReplicaSet", "2025-01-01T14:06:00Z"),
+	), ScaledObjectGVR, "orders", "worker")
+	s.Require().NoError(err)
+	s.Run("returns the metadata of the triggers", func() {
+		s.Equal(map[string]string{"queueName": "orders", "value": "20"}, resource.Triggers[1].Metadata)
+	})
+	s.Run("returns the scaling events newest first", func() {
+		s.Require().Len(resource.ScalingEvents, 2)
+		s.Equal(ScalingEvent{
+			Time: "2025-01-01T14:05:00Z", Object: "HorizontalPodAutoscaler/keda-hpa-worker",
+			Type: "Normal", Reason: "SuccessfulRescale", Message: "SuccessfulRescale message",
+		}, resource.ScalingEvents[0])
+		s.Equal("KEDAScaleTargetActivated", resource.ScalingEvents[1].Reason)
+	})
+}
+
+func (s *KedaTestSuite) TestSummarizeScaledJob() {
+	resource := Summarize(&unstructured.Unstructured{Object: map[string]any{
+		"kind":     "ScaledJob",
+		"metadata": map[string]any{"name": "batch", "namespace": "orders", "annotations": map[string]any{PausedAnnotation: "true"}},
+		"spec": map[string]any{
+			"maxReplicaCount": int64(10),
+			"triggers":        []any{map[string]any{"type": "aws-sqs-queue", "name": "sqs"}},
+		},
+	}}, nil, false)
+	s.Equal("Job", resource.Target)
+	s.Equal(int64(10), resource.MaxReplicas)
+	s.True(resource.Paused)
+	s.Equal("Unknown", resource.Ready)
+	s.Equal([]Trigger{{Type: "aws-sqs-queue", Name: "sqs"}}, resource.Triggers)
+}
+
+func TestKeda(t *testing.T) {
+	suite.Run(t, new(KedaTestSuite))
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/containers/kubernetes-mcp-server/pkg/config"
 	internalhttp "github.com/containers/kubernetes-mcp-server/pkg/http"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
 	"github.com/containers/kubernetes-mcp-server/pkg/mcp"
 	"github.com/containers/kubernetes-mcp-server/pkg/output"
 	"github.com/containers/kubernetes-mcp-server/pkg/telemetry"
@@ -52,6 +53,9 @@ kubernetes-mcp-server
 # start a SSE server on port 8080
 kubernetes-mcp-server --port 8080
 
+# start a SSE server on port 8080 and serve STDIO at the same time
+kubernetes-mcp-server --port 8080 --stdio
+
 # start a SSE server on port 8443 with a public HTTPS host of example.com
 kubernetes-mcp-server --port 8443 --sse-base-url https://example.com:8443
 
@@ -63,6 +67,13 @@ kubernetes-mcp-server --cluster-provider kubeconfig
 
 # start with kcp cluster provider for multi-workspace support
 kubernetes-mcp-server --cluster-provider kcp
+
+# record tool calls and their results, then replay them later without a cluster
+kubernetes-mcp-server --record-file session.jsonl
+kubernetes-mcp-server --replay-file session.jsonl
+
+# validate the configuration, cluster connectivity, and RBAC permissions, then quit
+kubernetes-mcp-server --config config.toml --validate
 `))
 )
 
@@ -72,10 +83,12 @@ const (
 	flagConfig               = "config"
 	flagConfigDir            = "config-dir"
 	flagPort                 = "port"
+	flagStdio                = "stdio"
 	flagSSEBaseUrl           = "sse-base-url"
 	flagKubeconfig           = "kubeconfig"
 	flagToolsets             = "toolsets"
 	flagListOutput           = "list-output"
+	flagPruneFields          = "prune-fields"
 	flagReadOnly             = "read-only"
 	flagDisableDestructive   = "disable-destructive"
 	flagStateless            = "stateless"
@@ -86,16 +99,21 @@ const (
 	flagCertificateAuthority = "certificate-authority"
 	flagDisableMultiCluster  = "disable-multi-cluster"
 	flagClusterProvider      = "cluster-provider"
+	flagValidate             = "validate"
+	flagRecordFile           = "record-file"
+	flagReplayFile           = "replay-file"
 )
 
 type MCPServerOptions struct {
 	Version              bool
 	LogLevel             int
 	Port                 string
+	Stdio                bool
 	SSEBaseUrl           string
 	Kubeconfig           string
 	Toolsets             []string
 	ListOutput           string
+	PruneFields          []string
 	ReadOnly             bool
 	DisableDestructive   bool
 	Stateless            bool
@@ -106,6 +124,9 @@ type MCPServerOptions struct {
 	ServerURL            string
 	DisableMultiCluster  bool
 	ClusterProvider      string
+	ValidateOnly         bool
+	RecordFile           string
+	ReplayFile           string
 
 	ConfigPath   string
 	ConfigDir    string
@@ -130,8 +151,14 @@ func NewMCPServer(streams genericiooptions.IOStreams) *cobra.Command {
 		Example: examples,
 		RunE: func(c *cobra.Command, args []string) error {
 			if err := o.Complete(c); err != nil {
+				if o.ValidateOnly {
+					return o.RunValidate(err)
+				}
 				return err
 			}
+			if o.ValidateOnly {
+				return o.RunValidate(o.Validate())
+			}
 			if err := o.Validate(); err != nil {
 				return err
 			}
@@ -148,10 +175,12 @@ func NewMCPServer(streams genericiooptions.IOStreams) *cobra.Command {
 	cmd.Flags().StringVar(&o.ConfigPath, flagConfig, o.ConfigPath, "Path of the config file.")
 	cmd.Flags().StringVar(&o.ConfigDir, flagConfigDir, o.ConfigDir, "Path to drop-in configuration directory (files loaded in lexical order). Defaults to "+config.DefaultDropInConfigDir+" relative to the config file if --config is set.")
 	cmd.Flags().StringVar(&o.Port, flagPort, o.Port, "Start a streamable HTTP and SSE HTTP server on the specified port (e.g. 8080)")
+	cmd.Flags().BoolVar(&o.Stdio, flagStdio, o.Stdio, "If true and --port is set, also serve the STDIO transport alongside the HTTP transports (sessions are kept isolated)")
 	cmd.Flags().StringVar(&o.SSEBaseUrl, flagSSEBaseUrl, o.SSEBaseUrl, "SSE public base URL to use when sending the endpoint message (e.g. https://example.com)")
 	cmd.Flags().StringVar(&o.Kubeconfig, flagKubeconfig, o.Kubeconfig, "Path to the kubeconfig file to use for authentication")
 	cmd.Flags().StringSliceVar(&o.Toolsets, flagToolsets, o.Toolsets, "Comma-separated list of MCP toolsets to use (available toolsets: "+strings.Join(toolsets.ToolsetNames(), ", ")+"). Defaults to "+strings.Join(o.StaticConfig.Toolsets, ", ")+".")
 	cmd.Flags().StringVar(&o.ListOutput, flagListOutput, o.ListOutput, "Output format for resource list operations (one of: "+strings.Join(output.Names, ", ")+"). Defaults to "+o.StaticConfig.ListOutput+".")
+	cmd.Flags().StringSliceVar(&o.PruneFields, flagPruneFields, o.PruneFields, "Comma-separated list of fields to remove from the returned objects (available fields: "+strings.Join(output.PruneFields, ", ")+"). Defaults to "+strings.Join(o.StaticConfig.PruneFields, ", ")+".")
 	cmd.Flags().BoolVar(&o.ReadOnly, flagReadOnly, o.ReadOnly, "If true, only tools annotated with readOnlyHint=true are exposed")
 	cmd.Flags().BoolVar(&o.DisableDestructive, flagDisableDestructive, o.DisableDestructive, "If true, tools annotated with destructiveHint=true are disabled")
 	cmd.Flags().BoolVar(&o.Stateless, flagStateless, o.Stateless, "If true, run the MCP server in stateless mode (disables tool/prompt change notifications). Useful for container deployments and load balancing. Default is false (stateful mode)")
@@ -167,6 +196,9 @@ func NewMCPServer(streams genericiooptions.IOStreams) *cobra.Command {
 	_ = cmd.Flags().MarkHidden(flagCertificateAuthority)
 	cmd.Flags().BoolVar(&o.DisableMultiCluster, flagDisableMultiCluster, o.DisableMultiCluster, "Disable multi cluster tools. Optional. If true, all tools will be run against the default cluster/context.")
 	cmd.Flags().StringVar(&o.ClusterProvider, flagClusterProvider, o.ClusterProvider, "Cluster provider strategy to use (one of: kubeconfig, in-cluster, kcp, disabled). If not set, the server will auto-detect based on the environment.")
+	cmd.Flags().StringVar(&o.RecordFile, flagRecordFile, o.RecordFile, "Path of a file where tool calls and their results are recorded (JSON Lines)")
+	cmd.Flags().StringVar(&o.ReplayFile, flagReplayFile, o.ReplayFile, "Path of a file recorded with --record-file whose results are served instead of contacting the cluster")
+	cmd.Flags().BoolVar(&o.ValidateOnly, flagValidate, o.ValidateOnly, "Validate the configuration, cluster reachability, and RBAC permissions for the enabled toolsets, print a report, and quit")
 
 	return cmd
 }
@@ -199,6 +231,9 @@ func (m *MCPServerOptions) loadFlags(cmd *cobra.Command) {
 	if cmd.Flag(flagPort).Changed {
 		m.StaticConfig.Port = m.Port
 	}
+	if cmd.Flag(flagStdio).Changed {
+		m.StaticConfig.Stdio = m.Stdio
+	}
 	if cmd.Flag(flagSSEBaseUrl).Changed {
 		m.StaticConfig.SSEBaseURL = m.SSEBaseUrl
 	}
@@ -208,6 +243,9 @@ func (m *MCPServerOptions) loadFlags(cmd *cobra.Command) {
 	if cmd.Flag(flagListOutput).Changed {
 		m.StaticConfig.ListOutput = m.ListOutput
 	}
+	if cmd.Flag(flagPruneFields).Changed {
+		m.StaticConfig.PruneFields = m.PruneFields
+	}
 	if cmd.Flag(flagReadOnly).Changed {
 		m.StaticConfig.ReadOnly = m.ReadOnly
 	}
@@ -238,6 +276,12 @@ func (m *MCPServerOptions) loadFlags(cmd *cobra.Command) {
 	if cmd.Flag(flagClusterProvider).Changed {
 		m.StaticConfig.ClusterProviderStrategy = m.ClusterProvider
 	}
+	if cmd.Flag(flagRecordFile).Changed {
+		m.StaticConfig.RecordFile = m.RecordFile
+	}
+	if cmd.Flag(flagReplayFile).Changed {
+		m.StaticConfig.ReplayFile = m.ReplayFile
+	}
 	if cmd.Flag(flagDisableMultiCluster).Changed && m.DisableMultiCluster {
 		m.StaticConfig.ClusterProviderStrategy = api.ClusterProviderDisabled
 	}
@@ -252,7 +296,12 @@ func (m *MCPServerOptions) initializeLogging() {
 		_ = flagSet.Parse([]string{"-logtostderr=false", "-alsologtostderr=false", "-stderrthreshold=FATAL"})
 		return
 	}
-	loggerOptions := []textlogger.ConfigOption{textlogger.Output(m.Out)}
+	logOutput := m.Out
+	if m.StaticConfig.Stdio {
+		// stdout is used by the STDIO transport, log to stderr instead
+		logOutput = m.ErrOut
+	}
+	loggerOptions := []textlogger.ConfigOption{textlogger.Output(logOutput)}
 	if m.StaticConfig.LogLevel >= 0 {
 		loggerOptions = append(loggerOptions, textlogger.Verbosity(m.StaticConfig.LogLevel))
 		_ = flagSet.Parse([]string{"--v", strconv.Itoa(m.StaticConfig.LogLevel)})
@@ -265,6 +314,22 @@ func (m *MCPServerOptions) Validate() error {
 	if output.FromString(m.StaticConfig.ListOutput) == nil {
 		return fmt.Errorf("invalid output name: %s, valid names are: %s", m.StaticConfig.ListOutput, strings.Join(output.Names, ", "))
 	}
+	if m.StaticConfig.ListDetail != "" && !slices.Contains(output.Details, m.StaticConfig.ListDetail) {
+		return fmt.Errorf("invalid list_detail: %s, valid values are: %s", m.StaticConfig.ListDetail, strings.Join(output.Details, ", "))
+	}
+	if !locale.IsSupported(m.StaticConfig.Locale) {
+		return fmt.Errorf("invalid locale: %s, valid locales are: %s", m.StaticConfig.Locale, strings.Join(locale.Names, ", "))
+	}
+	for _, field := range m.StaticConfig.PruneFields {
+		if !slices.Contains(output.PruneFields, field) {
+			return fmt.Errorf("invalid prune field: %s, valid fields are: %s", field, strings.Join(output.PruneFields, ", "))
+		}
+	}
+	for _, denied := range m.StaticConfig.DeniedScopes {
+		if !slices.Contains(api.Scopes, denied.Scope) {
+			return fmt.Errorf("invalid denied_scopes scope: %s, valid values are: %s", denied.Scope, strings.Join(api.Scopes, ", "))
+		}
+	}
 	if err := toolsets.Validate(m.StaticConfig.Toolsets); err != nil {
 		return err
 	}
@@ -275,6 +340,50 @@ func (m *MCPServerOptions) Validate() error {
 			return fmt.Errorf("invalid cluster-provider: %s, valid values are: %s", m.StaticConfig.ClusterProviderStrategy, strings.Join(validStrategies, ", "))
 		}
 	}
+	if m.StaticConfig.KubernetesClient.QPS < 0 || m.StaticConfig.KubernetesClient.Burst < 0 {
+		return fmt.Errorf("kubernetes_client qps and burst must be positive")
+	}
+	for _, encoding := range m.StaticConfig.HTTPCompression.Encodings {
+		if !slices.Contains(config.CompressionEncodings, encoding) {
+			return fmt.Errorf("invalid http_compression encoding: %s, valid encodings are: %s", encoding, strings.Join(config.CompressionEncodings, ", "))
+		}
+	}
+	if limits := m.StaticConfig.SessionLimits; limits.MaxConcurrentToolCalls < 0 || limits.MaxOperations < 0 || limits.MaxRetainedBytes < 0 ||
+		limits.MaxLogBytes < 0 || limits.MaxGoroutines < 0 {
+		return fmt.Errorf("session_limits must be positive (0 disables the limit)")
+	}
+	if _, err := m.StaticConfig.Redaction.Redactor(); err != nil {
+		return fmt.Errorf("invalid redaction: %w", err)
+	}
+	if err := m.StaticConfig.ResultBudget.Validate(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.ResultCache.Validate(); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.Workspace.Validate(m.StaticConfig.Stateless); err != nil {
+		return err
+	}
+	if err := m.StaticConfig.TransientCleanup.Validate(); err != nil {
+		return err
+	}
+	for _, freeze := range m.StaticConfig.ChangeFreezes {
+		if err := freeze.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := config.ValidateLabelScopes(m.StaticConfig.LabelScopes, m.StaticConfig.RequireOAuth); err != nil {
+		return err
+	}
+	if err := config.ValidateSchedules(m.StaticConfig.Schedules); err != nil {
+		return fmt.Errorf("invalid schedules: %w", err)
+	}
+	if err := config.ValidateExtensions(m.StaticConfig.Extensions); err != nil {
+		return fmt.Errorf("invalid extensions: %w", err)
+	}
+	if m.StaticConfig.RecordFile != "" && m.StaticConfig.ReplayFile != "" {
+		return fmt.Errorf("record-file and replay-file are mutually exclusive")
+	}
 	if !m.StaticConfig.RequireOAuth && (m.StaticConfig.OAuthAudience != "" || m.StaticConfig.AuthorizationURL != "" || m.StaticConfig.ServerURL != "" || m.StaticConfig.CertificateAuthority != "") {
 		return fmt.Errorf("oauth-audience, authorization-url, server-url and certificate-authority are only valid if require-oauth is enabled. Missing --port may implicitly set require-oauth to false")
 	}
@@ -308,9 +417,11 @@ func (m *MCPServerOptions) Run() error {
 	klog.V(1).Infof(" - Config: %s", m.ConfigPath)
 	klog.V(1).Infof(" - Toolsets: %s", strings.Join(m.StaticConfig.Toolsets, ", "))
 	klog.V(1).Infof(" - ListOutput: %s", m.StaticConfig.ListOutput)
+	klog.V(1).Infof(" - PruneFields: %s", strings.Join(m.StaticConfig.PruneFields, ", "))
 	klog.V(1).Infof(" - Read-only mode: %t", m.StaticConfig.ReadOnly)
 	klog.V(1).Infof(" - Disable destructive tools: %t", m.StaticConfig.DisableDestructive)
 	klog.V(1).Infof(" - Stateless mode: %t", m.StaticConfig.Stateless)
+	klog.V(1).Infof(" - STDIO alongside HTTP: %t", m.StaticConfig.Port != "" && m.StaticConfig.Stdio)
 	klog.V(1).Infof(" - Telemetry enabled: %t", m.StaticConfig.Telemetry.IsEnabled())
 
 	strategy := m.StaticConfig.ClusterProviderStrategy
@@ -359,10 +470,14 @@ func (m *MCPServerOptions) Run() error {
 		oidcProvider = provider
 	}
 
-	provider, err := kubernetes.NewProvider(m.StaticConfig, kubernetes.WithTokenExchange(oidcProvider, httpClient))
+	provider, err := m.newProvider(oidcProvider, httpClient)
 	if err != nil {
 		return fmt.Errorf("unable to create kubernetes target provider: %w", err)
 	}
+	if !m.StaticConfig.RequireOAuth && m.StaticConfig.ReplayFile == "" {
+		// Startup self-check, problems are logged but don't prevent the server from starting
+		go m.logSelfCheck(provider)
+	}
 
 	mcpServer, err := mcp.NewServer(mcp.Configuration{
 		StaticConfig: m.StaticConfig,
@@ -378,13 +493,33 @@ func (m *MCPServerOptions) Run() error {
 		}
 	}()
 
+	if m.StaticConfig.AdminAddress != "" {
+		adminCtx, cancelAdmin := context.WithCancel(context.Background())
+		defer cancelAdmin()
+		go func() {
+			if err := internalhttp.ServeAdmin(adminCtx, m.StaticConfig.AdminAddress); err != nil {
+				klog.Errorf("Admin server error: %v", err)
+			}
+		}()
+	}
+
 	// Set up SIGHUP handler for configuration reload
 	if m.ConfigPath != "" || m.ConfigDir != "" {
 		m.setupSIGHUPHandler(mcpServer)
 	}
 
 	if m.StaticConfig.Port != "" {
-		ctx := context.Background()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if m.StaticConfig.Stdio {
+			// The STDIO client owns the process lifecycle, shut down the HTTP server once it disconnects
+			go func() {
+				if err := mcpServer.ServeStdio(ctx); err != nil && !errors.Is(err, context.Canceled) {
+					klog.Errorf("STDIO transport error: %v", err)
+				}
+				cancel()
+			}()
+		}
 		return internalhttp.Serve(ctx, mcpServer, m.StaticConfig, oidcProvider, httpClient)
 	}
 
@@ -396,6 +531,15 @@ func (m *MCPServerOptions) Run() error {
 	return nil
 }
 
+// newProvider creates the provider of the cluster targets, in replay mode the results are served from the recording and
+// neither a kubeconfig nor a cluster is required.
+func (m *MCPServerOptions) newProvider(oidcProvider *oidc.Provider, httpClient *http.Client) (kubernetes.Provider, error) {
+	if m.StaticConfig.ReplayFile != "" {
+		return kubernetes.NewReplayProvider(), nil
+	}
+	return kubernetes.NewProvider(m.StaticConfig, kubernetes.WithTokenExchange(oidcProvider, httpClient))
+}
+
 // setupSIGHUPHandler sets up a signal handler to reload configuration on SIGHUP.
 // This is a blocking call that runs in a separate goroutine.
 func (m *MCPServerOptions) setupSIGHUPHandler(mcpServer *mcp.Server) {
@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -328,7 +329,7 @@ func TestListOutput(t *testing.T) {
 		rootCmd := NewMCPServer(ioStreams)
 		rootCmd.SetArgs([]string{"--help"})
 		o, err := captureOutput(rootCmd.Execute) // --help doesn't use logger/klog, cobra prints directly to stdout
-		if !strings.Contains(o, "Output format for resource list operations (one of: yaml, table)") {
+		if !strings.Contains(o, "Output format for resource list operations (one of: yaml, table, json)") {
 			t.Fatalf("Expected all available outputs, got %s %v", o, err)
 		}
 	})
@@ -352,6 +353,36 @@ func TestListOutput(t *testing.T) {
 	})
 }
 
+func TestPruneFields(t *testing.T) {
+	t.Run("defaults to last_applied", func(t *testing.T) {
+		if config.HasDefaultOverrides() {
+			t.Skip("Skipping test because default configuration overrides are present (this is a downstream fork)")
+		}
+		ioStreams, out := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--port=1337", "--log-level=1"})
+		if err := rootCmd.Execute(); !strings.Contains(out.String(), " - PruneFields: last_applied\"") {
+			t.Fatalf("Expected prune-fields 'last_applied', got %s %v", out, err)
+		}
+	})
+	t.Run("set with --prune-fields", func(t *testing.T) {
+		ioStreams, out := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--port=1337", "--log-level=1", "--prune-fields", "status,system_metadata"})
+		if err := rootCmd.Execute(); !strings.Contains(out.String(), " - PruneFields: status, system_metadata\"") {
+			t.Fatalf("Expected prune-fields 'status, system_metadata', got %s %v", out, err)
+		}
+	})
+	t.Run("invalid field", func(t *testing.T) {
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--port=1337", "--prune-fields", "spec"})
+		if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid prune field: spec, valid fields are: last_applied, status, system_metadata, owner_references") {
+			t.Fatalf("Expected invalid prune field error, got %v", err)
+		}
+	})
+}
+
 func TestReadOnly(t *testing.T) {
 	t.Run("defaults to false", func(t *testing.T) {
 		if config.HasDefaultOverrides() {
@@ -482,3 +513,244 @@ func TestStateless(t *testing.T) {
 		}
 	})
 }
+
+func TestStdio(t *testing.T) {
+	t.Run("defaults to false", func(t *testing.T) {
+		ioStreams, out := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--port=1337", "--log-level=1"})
+		if err := rootCmd.Execute(); !strings.Contains(out.String(), " - STDIO alongside HTTP: false") {
+			t.Fatalf("Expected STDIO alongside HTTP false, got %s %v", out, err)
+		}
+	})
+	t.Run("set with --stdio logs to stderr", func(t *testing.T) {
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		rootCmd := NewMCPServer(genericiooptions.IOStreams{In: &bytes.Buffer{}, Out: out, ErrOut: errOut})
+		rootCmd.SetArgs([]string{"--version", "--port=1337", "--log-level=1", "--stdio"})
+		_ = rootCmd.Execute()
+		expected := `(?m)\" - STDIO alongside HTTP\: true\"`
+		if m, err := regexp.MatchString(expected, errOut.String()); !m || err != nil {
+			t.Fatalf("Expected STDIO alongside HTTP to be %s, got %s %v", expected, errOut.String(), err)
+		}
+		assert.Equalf(t, "0.0.0\n", out.String(), "Expected only version output in stdout, got %s", out.String())
+	})
+}
+
+func TestRecordReplay(t *testing.T) {
+	t.Run("record-file and replay-file are mutually exclusive", func(t *testing.T) {
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--record-file", "a.jsonl", "--replay-file", "b.jsonl"})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for mutually exclusive flags")
+		assert.Equal(t, "record-file and replay-file are mutually exclusive", err.Error())
+	})
+	t.Run("replay-file doesn't require a kubeconfig", func(t *testing.T) {
+		ioStreams, _ := testStream()
+		o := NewMCPServerOptions(ioStreams)
+		o.StaticConfig.KubeConfig = filepath.Join(t.TempDir(), "missing-kubeconfig")
+		_, err := o.newProvider(nil, nil)
+		require.Error(t, err, "Expected error creating the provider without kubeconfig")
+		o.StaticConfig.ReplayFile = filepath.Join(t.TempDir(), "recording.jsonl")
+		provider, err := o.newProvider(nil, nil)
+		require.NoError(t, err, "Expected no error creating the provider in replay mode")
+		_, err = provider.GetDerivedKubernetes(t.Context(), provider.GetDefaultTarget())
+		assert.ErrorIs(t, err, kubernetes.ErrorReplayMode)
+	})
+}
+
+func TestHTTPCompression(t *testing.T) {
+	t.Run("invalid encoding", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[http_compression]\nencodings = [\"br\"]\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for invalid encoding")
+		assert.Equal(t, "invalid http_compression encoding: br, valid encodings are: zstd, gzip", err.Error())
+	})
+}
+
+func TestListDetail(t *testing.T) {
+	t.Run("invalid value", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("list_detail = \"verbose\"\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for invalid list detail")
+		assert.Equal(t, "invalid list_detail: verbose, valid values are: summary, full", err.Error())
+	})
+}
+
+func TestLocale(t *testing.T) {
+	t.Run("invalid value", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("locale = \"fr\"\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for invalid locale")
+		assert.Equal(t, "invalid locale: fr, valid locales are: en, de, ja", err.Error())
+	})
+}
+
+func TestSessionLimits(t *testing.T) {
+	t.Run("negative limit", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[session_limits]\nmax_operations = -1\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for negative limit")
+		assert.Equal(t, "session_limits must be positive (0 disables the limit)", err.Error())
+	})
+	t.Run("negative log bytes limit", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[session_limits]\nmax_log_bytes = -1\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for negative limit")
+		assert.Equal(t, "session_limits must be positive (0 disables the limit)", err.Error())
+	})
+}
+
+func TestRedaction(t *testing.T) {
+	t.Run("invalid rule", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[[redaction.rules]]\nreplacement = \"***\"\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for rule without pattern, JSONPath nor name")
+		assert.Equal(t, "invalid redaction: redaction rule 0: either a pattern, a JSONPath or a name is required", err.Error())
+	})
+}
+
+func TestSchedules(t *testing.T) {
+	t.Run("invalid cron", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[[schedules]]\nname = \"nightly\"\ncron = \"0 2 * *\"\ntool = \"pods_list\"\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for invalid cron expression")
+		assert.Equal(t, "invalid schedules: schedule nightly: invalid cron expression \"0 2 * *\": expected 5 fields (minute hour day-of-month month day-of-week), got 4", err.Error())
+	})
+}
+
+func TestExtensions(t *testing.T) {
+	t.Run("invalid url", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[[extensions]]\nname = \"acme\"\nurl = \"localhost:8090\"\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for invalid extension url")
+		assert.Equal(t, "invalid extensions: extension acme: url must be an http or https URL: localhost:8090", err.Error())
+	})
+}
+
+func TestWorkspace(t *testing.T) {
+	t.Run("negative max_bytes", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[workspace]\nmax_bytes = -1\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for negative workspace max_bytes")
+		assert.Equal(t, "workspace max_bytes must be positive: -1", err.Error())
+	})
+	t.Run("invalid idle_timeout", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[workspace]\nmax_bytes = 1024\nidle_timeout = \"soon\"\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for invalid workspace idle_timeout")
+		assert.Equal(t, "workspace idle_timeout must be a positive duration (e.g. 30m): soon", err.Error())
+	})
+	t.Run("stateless mode", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[workspace]\nmax_bytes = 1024\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--stateless", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for the workspace in stateless mode")
+		assert.Equal(t, "workspace is not supported in stateless mode", err.Error())
+	})
+}
+
+func TestTransientCleanup(t *testing.T) {
+	t.Run("invalid interval", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[transient_cleanup]\ninterval = \"often\"\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for invalid transient_cleanup interval")
+		assert.Equal(t, "transient_cleanup interval must be a positive duration (e.g. 10m): often", err.Error())
+	})
+}
+
+func TestChangeFreezes(t *testing.T) {
+	t.Run("invalid date range", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[[change_freezes]]\nname = \"year-end\"\nstart = \"2027-01-05T00:00:00Z\"\nend = \"2026-12-20T00:00:00Z\"\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for invalid change freeze")
+		assert.Equal(t, "change freeze year-end: the end must be after the start", err.Error())
+	})
+}
+
+func TestLabelScopes(t *testing.T) {
+	t.Run("invalid label selector", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[[label_scopes]]\nsubjects = [\"alice\"]\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for invalid label scope")
+		assert.Equal(t, "label scope: a label_selector is required", err.Error())
+	})
+	t.Run("subjects without require-oauth", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[[label_scopes]]\nlabel_selector = \"team=payments\"\nsubjects = [\"alice\"]\n[[label_scopes]]\nlabel_selector = \"team=shared\"\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for label scope subjects without require-oauth")
+		assert.Equal(t, "label scope team=payments: subjects are only valid if require-oauth is enabled", err.Error())
+	})
+}
+
+func TestDeniedScopes(t *testing.T) {
+	t.Run("invalid scope", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("denied_scopes = [ { scope = \"global\" } ]\n"), 0644))
+		ioStreams, _ := testStream()
+		rootCmd := NewMCPServer(ioStreams)
+		rootCmd.SetArgs([]string{"--version", "--config", configPath})
+		err := rootCmd.Execute()
+		require.Error(t, err, "Expected error for invalid scope")
+		assert.Equal(t, "invalid denied_scopes scope: global, valid values are: cluster, namespaced", err.Error())
+	})
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+const (
+	SelfCheckStatusOk      = "ok"
+	SelfCheckStatusWarning = "warning"
+	SelfCheckStatusError   = "error"
+	SelfCheckStatusSkipped = "skipped"
+
+	selfCheckTimeout = 30 * time.Second
+)
+
+// SelfCheckReport is the structured result of the startup self-check and --validate mode.
+type SelfCheckReport struct {
+	Valid  bool             `json:"valid"`
+	Checks []SelfCheckEntry `json:"checks"`
+}
+
+type SelfCheckEntry struct {
+	Name    string `json:"name"`
+	Target  string `json:"target,omitempty"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+func (r *SelfCheckReport) add(name, target, status, message string) {
+	r.Checks = append(r.Checks, SelfCheckEntry{Name: name, Target: target, Status: status, Message: message})
+	if status == SelfCheckStatusError {
+		r.Valid = false
+	}
+}
+
+// selfCheckPermission is a resource access that the tools of a toolset rely on.
+type selfCheckPermission struct {
+	Group    string
+	Resource string
+	Verb     string
+}
+
+// selfCheckPermissions lists, per toolset, the cluster-wide access required by the most common tool operations.
+// Missing permissions are reported as warnings since tools may still work with namespace-scoped access.
+var selfCheckPermissions = map[string][]selfCheckPermission{
+	"core": {
+		{Resource: "namespaces", Verb: "list"},
+		{Resource: "pods", Verb: "list"},
+		{Resource: "pods", Verb: "get"},
+		{Resource: "pods/log", Verb: "get"},
+		{Resource: "events", Verb: "list"},
+		{Resource: "nodes", Verb: "list"},
+	},
+	"helm": {
+		{Resource: "secrets", Verb: "list"},
+		{Resource: "secrets", Verb: "create"},
+	},
+	"kubevirt": {
+		{Group: "kubevirt.io", Resource: "virtualmachines", Verb: "list"},
+	},
+}
+
+// selfCheck verifies the configuration, cluster reachability, and RBAC permissions for the enabled toolsets.
+// configErr is the error (if any) that was returned while loading and validating the configuration.
+func (m *MCPServerOptions) selfCheck(ctx context.Context, configErr error) *SelfCheckReport {
+	report := &SelfCheckReport{Valid: true}
+	if configErr != nil {
+		report.add("config", "", SelfCheckStatusError, configErr.Error())
+		report.add("kubeconfig", "", SelfCheckStatusSkipped, "invalid configuration")
+		return report
+	}
+	report.add("config", "", SelfCheckStatusOk, "")
+
+	provider, err := kubernetes.NewProvider(m.StaticConfig)
+	if err != nil {
+		report.add("kubeconfig", "", SelfCheckStatusError, err.Error())
+		return report
+	}
+	defer provider.Close()
+	report.add("kubeconfig", "", SelfCheckStatusOk, "")
+	m.selfCheckProvider(ctx, provider, report)
+	return report
+}
+
+func (m *MCPServerOptions) selfCheckProvider(ctx context.Context, provider kubernetes.Provider, report *SelfCheckReport) {
+	targets, err := provider.GetTargets(ctx)
+	if err != nil {
+		report.add("targets", "", SelfCheckStatusError, err.Error())
+		return
+	}
+	// The targets are checked concurrently, the report lists them in their original order
+	type reachability struct {
+		k       *kubernetes.Kubernetes
+		version string
+	}
+	reached, errs := kubernetes.FanOut(ctx, kubernetes.DefaultFanOutConcurrency, targets, func(ctx context.Context, target string) (reachability, error) {
+		k, err := provider.GetDerivedKubernetes(ctx, target)
+		if err != nil {
+			return reachability{}, err
+		}
+		serverVersion, err := k.Discovery().ServerVersion()
+		if err != nil {
+			return reachability{}, err
+		}
+		return reachability{k: k, version: serverVersion.GitVersion}, nil
+	})
+	for i, target := range targets {
+		if errs[i] != nil {
+			report.add("reachability", target, SelfCheckStatusError, errs[i].Error())
+			continue
+		}
+		report.add("reachability", target, SelfCheckStatusOk, "Kubernetes "+reached[i].version)
+		// RBAC checks are only performed for the default target, the rest share the same toolsets
+		if target != provider.GetDefaultTarget() {
+			continue
+		}
+		for _, toolset := range m.StaticConfig.Toolsets {
+			for _, permission := range selfCheckPermissions[toolset] {
+				m.selfCheckPermission(ctx, reached[i].k, toolset, target, permission, report)
+			}
+		}
+	}
+}
+
+func (m *MCPServerOptions) selfCheckPermission(ctx context.Context, k *kubernetes.Kubernetes, toolset, target string, permission selfCheckPermission, report *SelfCheckReport) {
+	name := fmt.Sprintf("rbac/%s", toolset)
+	resource := permission.Resource
+	if permission.Group != "" {
+		resource = resource + "." + permission.Group
+	}
+	response, err := k.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &authv1.ResourceAttributes{
+			Verb:     permission.Verb,
+			Group:    permission.Group,
+			Resource: permission.Resource,
+		}},
+	}, metav1.CreateOptions{})
+	switch {
+	case err != nil:
+		report.add(name, target, SelfCheckStatusWarning, fmt.Sprintf("unable to check %s %s: %v", permission.Verb, resource, err))
+	case !response.Status.Allowed:
+		report.add(name, target, SelfCheckStatusWarning, fmt.Sprintf("cannot %s %s", permission.Verb, resource))
+	default:
+		report.add(name, target, SelfCheckStatusOk, fmt.Sprintf("can %s %s", permission.Verb, resource))
+	}
+}
+
+// RunValidate runs the self-check, prints the report, and returns an error if any of the checks failed.
+func (m *MCPServerOptions) RunValidate(configErr error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), selfCheckTimeout)
+	defer cancel()
+	report := m.selfCheck(ctx, configErr)
+	ret, err := output.MarshalYaml(report)
+	if err != nil {
+		return fmt.Errorf("failed to print self-check report: %w", err)
+	}
+	_, _ = fmt.Fprint(m.Out, ret)
+	if !report.Valid {
+		return errors.New("self-check failed")
+	}
+	return nil
+}
+
+// logSelfCheck runs the self-check against the provided provider and logs the problems found, if any.
+func (m *MCPServerOptions) logSelfCheck(provider kubernetes.Provider) {
+	ctx, cancel := context.WithTimeout(context.Background(), selfCheckTimeout)
+	defer cancel()
+	report := &SelfCheckReport{Valid: true}
+	m.selfCheckProvider(ctx, provider, report)
+	for _, check := range report.Checks {
+		if !slices.Contains([]string{SelfCheckStatusWarning, SelfCheckStatusError}, check.Status) {
+			continue
+		}
+		klog.Warningf("Self-check %s (%s) %s: %s", check.Name, check.Target, check.Status, check.Message)
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type ValidateSuite struct {
+	suite.Suite
+	mockServer *test.MockServer
+	// denied contains the resources for which the mock server denies access
+	denied map[string]bool
+}
+
+func (s *ValidateSuite) SetupTest() {
+	s.denied = map[string]bool{}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "authorization.k8s.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "selfsubjectaccessreviews", Kind: "SelfSubjectAccessReview", Verbs: metav1.Verbs{"create"}},
+		},
+	}))
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/version":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(version.Info{GitVersion: "v1.33.0"})
+		case "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews":
+			body, _ := io.ReadAll(req.Body)
+			review := &authv1.SelfSubjectAccessReview{}
+			if _, _, err := scheme.Codecs.UniversalDeserializer().Decode(body, nil, review); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			review.Status.Allowed = !s.denied[review.Spec.ResourceAttributes.Resource]
+			test.WriteObject(w, review)
+		}
+	}))
+}
+
+func (s *ValidateSuite) TearDownTest() {
+	s.mockServer.Close()
+}
+
+func (s *ValidateSuite) validate(args ...string) (*SelfCheckReport, error) {
+	ioStreams, out := testStream()
+	rootCmd := NewMCPServer(ioStreams)
+	rootCmd.SetArgs(append([]string{"--validate"}, args...))
+	err := rootCmd.Execute()
+	report := &SelfCheckReport{}
+	s.Require().NoErrorf(yaml.Unmarshal(out.Bytes(), report), "Expected a YAML report, got %s", out.String())
+	return report, err
+}
+
+func (s *ValidateSuite) TestValidConfiguration() {
+	report, err := s.validate("--kubeconfig", s.mockServer.KubeconfigFile(s.T()), "--toolsets", "core")
+	s.Run("succeeds", func() {
+		s.NoError(err)
+		s.True(report.Valid)
+	})
+	s.Run("reports config check", func() {
+		s.Equal(SelfCheckEntry{Name: "config", Status: SelfCheckStatusOk}, report.Checks[0])
+	})
+	s.Run("reports reachability with server version", func() {
+		s.Contains(report.Checks, SelfCheckEntry{Name: "reachability", Target: "fake-context", Status: SelfCheckStatusOk, Message: "Kubernetes v1.33.0"})
+	})
+	s.Run("reports RBAC checks for enabled toolsets", func() {
+		s.Contains(report.Checks, SelfCheckEntry{Name: "rbac/core", Target: "fake-context", Status: SelfCheckStatusOk, Message: "can list pods"})
+		for _, check := range report.Checks {
+			s.NotEqual("rbac/helm", check.Name, "helm toolset is not enabled")
+		}
+	})
+}
+
+func (s *ValidateSuite) TestMissingPermissions() {
+	s.denied["nodes"] = true
+	report, err := s.validate("--kubeconfig", s.mockServer.KubeconfigFile(s.T()), "--toolsets", "core")
+	s.Run("succeeds with warnings", func() {
+		s.NoError(err)
+		s.True(report.Valid)
+	})
+	s.Run("reports missing permission as warning", func() {
+		s.Contains(report.Checks, SelfCheckEntry{Name: "rbac/core", Target: "fake-context", Status: SelfCheckStatusWarning, Message: "cannot list nodes"})
+	})
+}
+
+func (s *ValidateSuite) TestInvalidConfiguration() {
+	report, err := s.validate("--kubeconfig", s.mockServer.KubeconfigFile(s.T()), "--list-output", "invalid")
+	s.Run("fails", func() {
+		s.EqualError(err, "self-check failed")
+		s.False(report.Valid)
+	})
+	s.Run("reports config error", func() {
+		s.Equal("config", report.Checks[0].Name)
+		s.Equal(SelfCheckStatusError, report.Checks[0].Status)
+		s.Contains(report.Checks[0].Message, "invalid output name: invalid")
+	})
+	s.Run("skips cluster checks", func() {
+		s.Equal(SelfCheckEntry{Name: "kubeconfig", Status: SelfCheckStatusSkipped, Message: "invalid configuration"}, report.Checks[1])
+	})
+}
+
+func (s *ValidateSuite) TestUnreachableCluster() {
+	kubeconfig := s.mockServer.KubeconfigFile(s.T())
+	s.mockServer.Close()
+	report, err := s.validate("--kubeconfig", kubeconfig, "--toolsets", "core")
+	s.Run("fails", func() {
+		s.EqualError(err, "self-check failed")
+		s.False(report.Valid)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	suite.Run(t, new(ValidateSuite))
+}
@@ -35,37 +35,49 @@ func (rt *AccessControlRoundTripper) RoundTrip(req *http.Request) (*http.Respons
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: AccessControlRoundTripper failed to get kind for gvr %v: %w", gvr, err)
 	}
-	if !rt.isAllowed(gvk) {
-		return nil, fmt.Errorf("resource not allowed: %s", gvk.String())
+	// the kinds whose scope can't be determined are considered cluster-scoped
+	scope := api.ScopeCluster
+	if mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil && mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		scope = api.ScopeNamespaced
+	}
+	write := req.Method != http.MethodGet && req.Method != http.MethodHead && req.Method != http.MethodOptions
+	if err = CheckDenied(rt.deniedResourcesProvider, gvk, scope, write); err != nil {
+		return nil, err
 	}
 
 	return rt.delegate.RoundTrip(req)
 }
 
-// isAllowed checks the resource is in denied list or not.
-// If it is in denied list, this function returns false.
-func (rt *AccessControlRoundTripper) isAllowed(
-	gvk schema.GroupVersionKind,
-) bool {
-	if rt.deniedResourcesProvider == nil {
-		return true
+// CheckDenied returns an error if the kind, or its whole group version, is among the denied resources, or if its scope
+// (api.ScopeCluster or api.ScopeNamespaced) is denied, only for the writes if the scope only denies them. The reviews
+// (e.g. SelfSubjectAccessReview) are created without persisting anything, they're not considered as writes.
+func CheckDenied(provider api.DeniedResourcesProvider, gvk schema.GroupVersionKind, scope string, write bool) error {
+	if provider == nil {
+		return nil
 	}
-
-	for _, val := range rt.deniedResourcesProvider.GetDeniedResources() {
-		// If kind is empty, that means Group/Version pair is denied entirely
-		if val.Kind == "" {
-			if gvk.Group == val.Group && gvk.Version == val.Version {
-				return false
-			}
+	for _, denied := range provider.GetDeniedResources() {
+		if gvk.Group == denied.Group && gvk.Version == denied.Version && (denied.Kind == "" || gvk.Kind == denied.Kind) {
+			return fmt.Errorf("%w: %s", api.ErrResourceDenied, gvk.String())
 		}
-		if gvk.Group == val.Group &&
-			gvk.Version == val.Version &&
-			gvk.Kind == val.Kind {
-			return false
+	}
+	write = write && gvk.Group != "authorization.k8s.io" && gvk.Group != "authentication.k8s.io"
+	for _, denied := range provider.GetDeniedScopes() {
+		switch {
+		case denied.Scope != scope:
+		case denied.WritesOnly && write:
+			return fmt.Errorf("%w: %s (writes to %s resources are denied)", api.ErrResourceDenied, gvk.String(), scopeName(scope))
+		case !denied.WritesOnly:
+			return fmt.Errorf("%w: %s (%s resources are denied)", api.ErrResourceDenied, gvk.String(), scopeName(scope))
 		}
 	}
+	return nil
+}
 
-	return true
+func scopeName(scope string) string {
+	if scope == api.ScopeCluster {
+		return "cluster-scoped"
+	}
+	return scope
 }
 
 func parseURLToGVR(path string) (gvr schema.GroupVersionResource, ok bool) {
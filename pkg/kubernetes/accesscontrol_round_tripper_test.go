@@ -291,6 +291,84 @@ func (s *AccessControlRoundTripperTestSuite) TestRoundTripForDeniedAPIResources(
 	})
 }
 
+func (s *AccessControlRoundTripperTestSuite) TestRoundTripForDeniedScopes() {
+	delegateCalled := false
+	mockDelegate := &mockRoundTripper{
+		called: &delegateCalled,
+		onRequest: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		},
+	}
+	rt := &AccessControlRoundTripper{
+		delegate:                mockDelegate,
+		deniedResourcesProvider: config.Default(),
+		restMapperProvider:      func() meta.RESTMapper { return s.restMapper },
+	}
+
+	s.Run("Cluster-scoped writes are denied", func() {
+		s.Require().NoError(toml.Unmarshal([]byte(`
+			denied_scopes = [ { scope = "cluster", writes_only = true } ]
+		`), rt.deniedResourcesProvider), "Expected to parse denied scopes config")
+
+		s.Run("Delete node is denied", func() {
+			delegateCalled = false
+			resp, err := rt.RoundTrip(httptest.NewRequest("DELETE", "/api/v1/nodes/node-1", nil))
+			s.Error(err)
+			s.Nil(resp)
+			s.False(delegateCalled, "Expected delegate not to be called for denied scope")
+			s.Contains(err.Error(), "resource not allowed: /v1, Kind=Node (writes to cluster-scoped resources are denied)")
+		})
+
+		s.Run("Get node is allowed", func() {
+			delegateCalled = false
+			resp, err := rt.RoundTrip(httptest.NewRequest("GET", "/api/v1/nodes/node-1", nil))
+			s.NoError(err)
+			s.NotNil(resp)
+			s.True(delegateCalled)
+		})
+
+		s.Run("Delete pod is allowed", func() {
+			delegateCalled = false
+			resp, err := rt.RoundTrip(httptest.NewRequest("DELETE", "/api/v1/namespaces/default/pods/my-pod", nil))
+			s.NoError(err)
+			s.NotNil(resp)
+			s.True(delegateCalled)
+		})
+	})
+
+	s.Run("Namespaced resources are denied", func() {
+		rt.deniedResourcesProvider = config.Default()
+		s.Require().NoError(toml.Unmarshal([]byte(`
+			denied_scopes = [ { scope = "namespaced" } ]
+		`), rt.deniedResourcesProvider), "Expected to parse denied scopes config")
+
+		s.Run("List pods is denied", func() {
+			delegateCalled = false
+			resp, err := rt.RoundTrip(httptest.NewRequest("GET", "/api/v1/pods", nil))
+			s.Error(err)
+			s.Nil(resp)
+			s.False(delegateCalled)
+			s.Contains(err.Error(), "resource not allowed: /v1, Kind=Pod (namespaced resources are denied)")
+		})
+
+		s.Run("Get deployment is denied", func() {
+			delegateCalled = false
+			resp, err := rt.RoundTrip(httptest.NewRequest("GET", "/apis/apps/v1/namespaces/default/deployments/web", nil))
+			s.Error(err)
+			s.Nil(resp)
+			s.False(delegateCalled)
+		})
+
+		s.Run("List nodes is allowed", func() {
+			delegateCalled = false
+			resp, err := rt.RoundTrip(httptest.NewRequest("GET", "/api/v1/nodes", nil))
+			s.NoError(err)
+			s.NotNil(resp)
+			s.True(delegateCalled)
+		})
+	})
+}
+
 func TestAccessControlRoundTripper(t *testing.T) {
 	suite.Run(t, new(AccessControlRoundTripperTestSuite))
 }
@@ -0,0 +1,153 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	CapacityGroupByNamespace = "namespace"
+
+	// capacityNoLabel is the group of the Pods without the label used to group the requests
+	capacityNoLabel = "(none)"
+)
+
+// CapacityPrices are the hourly prices of a unit of each resource used to estimate the cost of the requests:
+// a CPU core for cpu, a GiB for memory, ephemeral-storage, and hugepages, and a unit for the other (extended) resources.
+type CapacityPrices map[string]float64
+
+// CapacityReport aggregates the resources requested by the running Pods by namespace or label.
+type CapacityReport struct {
+	GroupBy string `json:"groupBy"`
+	Pods    int    `json:"pods"`
+	// Allocatable are the resources allocatable in the cluster (sum of all the Nodes)
+	Allocatable v1.ResourceList `json:"allocatable,omitempty"`
+	Requested   v1.ResourceList `json:"requested"`
+	// RequestedPercent is the percentage of the allocatable resources requested
+	RequestedPercent     map[v1.ResourceName]int64 `json:"requestedPercent,omitempty"`
+	EstimatedCostPerHour string                    `json:"estimatedCostPerHour,omitempty"`
+	Groups               []CapacityGroup           `json:"groups"`
+}
+
+// CapacityGroup are the resources requested by the Pods of a group.
+type CapacityGroup struct {
+	Name     string          `json:"name"`
+	Pods     int             `json:"pods"`
+	Requests v1.ResourceList `json:"requests"`
+	// SharePercent is the percentage of the total requests of each resource
+	SharePercent         map[v1.ResourceName]int64 `json:"sharePercent"`
+	EstimatedCostPerHour string                    `json:"estimatedCostPerHour,omitempty"`
+	// CostSharePercent is the percentage of the total estimated cost
+	CostSharePercent *float64 `json:"costSharePercent,omitempty"`
+	cost             float64
+}
+
+// CapacityUsage aggregates the resources requested by the running Pods in the provided namespace (all namespaces if
+// empty) by namespace, or by the value of the provided label. The cost is estimated when prices are provided.
+func (c *Core) CapacityUsage(ctx context.Context, namespace, groupByLabel string, prices CapacityPrices) (*CapacityReport, error) {
+	pods, err := c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var nodes []v1.Node
+	// The allocatable resources are best-effort, the Nodes might not be accessible
+	if nodeList, err := c.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err == nil {
+		nodes = nodeList.Items
+	}
+	return capacityUsage(pods.Items, nodes, groupByLabel, prices), nil
+}
+
+func capacityUsage(pods []v1.Pod, nodes []v1.Node, groupByLabel string, prices CapacityPrices) *CapacityReport {
+	report := &CapacityReport{GroupBy: CapacityGroupByNamespace, Allocatable: v1.ResourceList{}}
+	if groupByLabel != "" {
+		report.GroupBy = "label " + groupByLabel
+	}
+	groupPods := map[string][]v1.Pod{}
+	var running []v1.Pod
+	for _, pod := range pods {
+		// Completed Pods don't hold their requests
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		group := pod.Namespace
+		if groupByLabel != "" {
+			group = cmp.Or(pod.Labels[groupByLabel], capacityNoLabel)
+		}
+		groupPods[group] = append(groupPods[group], pod)
+		running = append(running, pod)
+	}
+	report.Pods = len(running)
+	report.Requested = podsRequests(running)
+	for _, node := range nodes {
+		for name, quantity := range node.Status.Allocatable {
+			total := report.Allocatable[name]
+			total.Add(quantity)
+			report.Allocatable[name] = total
+		}
+	}
+	if len(report.Allocatable) > 0 {
+		report.RequestedPercent = map[v1.ResourceName]int64{}
+		for name, requested := range report.Requested {
+			if allocatable, ok := report.Allocatable[name]; ok && !allocatable.IsZero() {
+				report.RequestedPercent[name] = requested.MilliValue() * 100 / allocatable.MilliValue()
+			}
+		}
+	} else {
+		report.Allocatable = nil
+	}
+	totalCost := 0.0
+	for name, pods := range groupPods {
+		group := CapacityGroup{Name: name, Pods: len(pods), Requests: podsRequests(pods), SharePercent: map[v1.ResourceName]int64{}}
+		for resourceName, requested := range group.Requests {
+			if total := report.Requested[resourceName]; !total.IsZero() {
+				group.SharePercent[resourceName] = requested.MilliValue() * 100 / total.MilliValue()
+			}
+			if price, ok := prices[string(resourceName)]; ok {
+				group.cost += capacityUnits(resourceName, requested) * price
+			}
+		}
+		totalCost += group.cost
+		report.Groups = append(report.Groups, group)
+	}
+	if len(prices) > 0 {
+		report.EstimatedCostPerHour = fmt.Sprintf("%.2f", totalCost)
+		for i := range report.Groups {
+			group := &report.Groups[i]
+			group.EstimatedCostPerHour = fmt.Sprintf("%.2f", group.cost)
+			costShare := 0.0
+			if totalCost > 0 {
+				costShare = math.Round(group.cost*1000/totalCost) / 10
+			}
+			group.CostSharePercent = &costShare
+		}
+	}
+	slices.SortFunc(report.Groups, func(a, b CapacityGroup) int {
+		return cmp.Or(
+			cmp.Compare(b.cost, a.cost),
+			cmp.Compare(b.SharePercent[v1.ResourceCPU], a.SharePercent[v1.ResourceCPU]),
+			cmp.Compare(b.SharePercent[v1.ResourceMemory], a.SharePercent[v1.ResourceMemory]),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return report
+}
+
+// capacityUnits returns the quantity in the units the resource prices are expressed in (see CapacityPrices)
+func capacityUnits(name v1.ResourceName, quantity resource.Quantity) float64 {
+	switch {
+	case name == v1.ResourceCPU:
+		return float64(quantity.MilliValue()) / 1000
+	case name == v1.ResourceMemory || name == v1.ResourceEphemeralStorage || strings.HasPrefix(string(name), v1.ResourceHugePagesPrefix):
+		return quantity.AsApproximateFloat64() / (1 << 30)
+	default:
+		return quantity.AsApproximateFloat64()
+	}
+}
@@ -0,0 +1,85 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type CapacityTestSuite struct {
+	suite.Suite
+}
+
+func capacityPod(namespace, name, team, cpu, memory string, phase v1.PodPhase) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"team": team}},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		}}}}},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func (s *CapacityTestSuite) TestCapacityUsage() {
+	pods := []v1.Pod{
+		capacityPod("payments", "api-1", "checkout", "1500m", "2Gi", v1.PodRunning),
+		capacityPod("payments", "api-2", "checkout", "500m", "2Gi", v1.PodRunning),
+		capacityPod("search", "indexer", "discovery", "2", "12Gi", v1.PodRunning),
+		capacityPod("search", "job", "", "4", "4Gi", v1.PodSucceeded),
+		capacityPod("search", "web", "", "1", "0", v1.PodPending),
+	}
+	nodes := []v1.Node{
+		{Status: v1.NodeStatus{Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("16Gi")}}},
+		{Status: v1.NodeStatus{Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("16Gi")}}},
+	}
+	s.Run("groups by namespace", func() {
+		report := capacityUsage(pods, nodes, "", nil)
+		s.Equal(CapacityGroupByNamespace, report.GroupBy)
+		s.Equal(4, report.Pods, "completed pods are ignored")
+		s.Equal("5", ptr.To(report.Requested[v1.ResourceCPU]).String())
+		s.Equal("8", ptr.To(report.Allocatable[v1.ResourceCPU]).String())
+		s.Equal(map[v1.ResourceName]int64{v1.ResourceCPU: 62, v1.ResourceMemory: 50}, report.RequestedPercent)
+		s.Require().Len(report.Groups, 2)
+		s.Equal("search", report.Groups[0].Name)
+		s.Equal(2, report.Groups[0].Pods)
+		s.Equal(map[v1.ResourceName]int64{v1.ResourceCPU: 60, v1.ResourceMemory: 75}, report.Groups[0].SharePercent)
+		s.Equal("payments", report.Groups[1].Name)
+		s.Empty(report.EstimatedCostPerHour)
+		s.Nil(report.Groups[1].CostSharePercent)
+	})
+	s.Run("groups by label", func() {
+		report := capacityUsage(pods, nodes, "team", nil)
+		s.Equal("label team", report.GroupBy)
+		s.Require().Len(report.Groups, 3)
+		s.Equal("discovery", report.Groups[0].Name)
+		s.Equal("checkout", report.Groups[1].Name, "same CPU share as discovery, less memory")
+		s.Equal("(none)", report.Groups[2].Name)
+	})
+	s.Run("estimates costs with prices", func() {
+		report := capacityUsage(pods, nodes, "team", CapacityPrices{"cpu": 0.04, "memory": 0.005})
+		s.Equal("0.28", report.EstimatedCostPerHour)
+		s.Require().Len(report.Groups, 3)
+		s.Equal("discovery", report.Groups[0].Name)
+		s.Equal("0.14", report.Groups[0].EstimatedCostPerHour)
+		s.Equal(50.0, *report.Groups[0].CostSharePercent)
+		s.Equal("checkout", report.Groups[1].Name)
+		s.Equal("0.10", report.Groups[1].EstimatedCostPerHour)
+		s.Equal(35.7, *report.Groups[1].CostSharePercent)
+		s.Equal("(none)", report.Groups[2].Name)
+		s.Equal("0.04", report.Groups[2].EstimatedCostPerHour)
+	})
+	s.Run("omits allocatable without nodes", func() {
+		report := capacityUsage(pods, nil, "", nil)
+		s.Nil(report.Allocatable)
+		s.Nil(report.RequestedPercent)
+	})
+}
+
+func TestCapacity(t *testing.T) {
+	suite.Run(t, new(CapacityTestSuite))
+}
@@ -0,0 +1,181 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	CertificateStatusValid    = "valid"
+	CertificateStatusExpiring = "expiring"
+	CertificateStatusExpired  = "expired"
+	CertificateStatusInvalid  = "invalid"
+)
+
+var certManagerCertificates = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+// CertificateAudit is the result of the inspection of a TLS Secret or a cert-manager Certificate.
+type CertificateAudit struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// SecretName is the Secret where the cert-manager Certificate is stored
+	SecretName string   `json:"secretName,omitempty"`
+	Status     string   `json:"status"`
+	Subject    string   `json:"subject,omitempty"`
+	Issuer     string   `json:"issuer,omitempty"`
+	DNSNames   []string `json:"dnsNames,omitempty"`
+	NotBefore  string   `json:"notBefore,omitempty"`
+	NotAfter   string   `json:"notAfter,omitempty"`
+	// ExpiresIn is the time left before the certificate expires (negative if already expired)
+	ExpiresIn string   `json:"expiresIn,omitempty"`
+	Issues    []string `json:"issues,omitempty"`
+}
+
+// CertificatesAudit inspects the TLS Secrets (and cert-manager Certificates, if available) in the provided namespace
+// (all namespaces if empty) and reports their expiry, issuer, and SAN mismatches with the Ingresses using them.
+// Certificates expiring within the provided window are flagged.
+func (c *Core) CertificatesAudit(ctx context.Context, namespace string, window time.Duration) ([]CertificateAudit, error) {
+	secrets, err := c.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{FieldSelector: "type=" + string(v1.SecretTypeTLS)})
+	if err != nil {
+		return nil, err
+	}
+	var ingresses []networkingv1.Ingress
+	if ingressList, err := c.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		ingresses = ingressList.Items
+	}
+	var certificates []unstructured.Unstructured
+	// cert-manager is optional
+	if certificateList, err := c.DynamicClient().Resource(certManagerCertificates).Namespace(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		certificates = certificateList.Items
+	}
+	return auditCertificates(secrets.Items, ingresses, certificates, time.Now(), window), nil
+}
+
+func auditCertificates(secrets []v1.Secret, ingresses []networkingv1.Ingress, certificates []unstructured.Unstructured, now time.Time, window time.Duration) []CertificateAudit {
+	// hosts served with each TLS Secret by the Ingresses
+	ingressHosts := map[string][]string{}
+	for _, ingress := range ingresses {
+		for _, tls := range ingress.Spec.TLS {
+			key := ingress.Namespace + "/" + tls.SecretName
+			ingressHosts[key] = append(ingressHosts[key], tls.Hosts...)
+		}
+	}
+	leafCertificates := map[string]*x509.Certificate{}
+	audits := make([]CertificateAudit, 0, len(secrets)+len(certificates))
+	for _, secret := range secrets {
+		audit := CertificateAudit{Kind: "Secret", Namespace: secret.Namespace, Name: secret.Name}
+		certificate, err := parseLeafCertificate(secret.Data[v1.TLSCertKey])
+		if err != nil {
+			audit.Status = CertificateStatusInvalid
+			audit.Issues = append(audit.Issues, err.Error())
+			audits = append(audits, audit)
+			continue
+		}
+		leafCertificates[secret.Namespace+"/"+secret.Name] = certificate
+		audit.Subject = certificate.Subject.String()
+		audit.Issuer = certificate.Issuer.String()
+		audit.DNSNames = certificate.DNSNames
+		audit.NotBefore = certificate.NotBefore.UTC().Format(time.RFC3339)
+		audit.setExpiry(certificate.NotAfter, now, window)
+		for _, host := range ingressHosts[secret.Namespace+"/"+secret.Name] {
+			if certificate.VerifyHostname(host) != nil {
+				audit.Issues = append(audit.Issues, fmt.Sprintf("host %s served by an Ingress is not covered by the certificate SANs", host))
+			}
+		}
+		audits = append(audits, audit)
+	}
+	for _, certificate := range certificates {
+		audit := CertificateAudit{Kind: "Certificate", Namespace: certificate.GetNamespace(), Name: certificate.GetName()}
+		audit.SecretName, _, _ = unstructured.NestedString(certificate.Object, "spec", "secretName")
+		issuerKind, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind")
+		issuerName, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "name")
+		if issuerName != "" {
+			audit.Issuer = cmp.Or(issuerKind, "Issuer") + "/" + issuerName
+		}
+		audit.DNSNames, _, _ = unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+		audit.Status = CertificateStatusValid
+		if notAfter, _, _ := unstructured.NestedString(certificate.Object, "status", "notAfter"); notAfter != "" {
+			if t, err := time.Parse(time.RFC3339, notAfter); err == nil {
+				audit.setExpiry(t, now, window)
+			}
+		}
+		conditions, _, _ := unstructured.NestedSlice(certificate.Object, "status", "conditions")
+		for _, condition := range conditions {
+			c, _ := condition.(map[string]any)
+			if c["type"] == "Ready" && c["status"] != "True" {
+				audit.Issues = append(audit.Issues, fmt.Sprintf("Certificate is not ready: %v", c["message"]))
+			}
+		}
+		if leaf, ok := leafCertificates[audit.Namespace+"/"+audit.SecretName]; ok {
+			for _, dnsName := range audit.DNSNames {
+				if !slices.Contains(leaf.DNSNames, dnsName) {
+					audit.Issues = append(audit.Issues, fmt.Sprintf("DNS name %s requested by the Certificate is missing from the issued certificate in Secret %s", dnsName, audit.SecretName))
+				}
+			}
+		} else if audit.SecretName != "" {
+			audit.Issues = append(audit.Issues, fmt.Sprintf("Secret %s with the issued certificate was not found", audit.SecretName))
+		}
+		audits = append(audits, audit)
+	}
+	// Most urgent first
+	slices.SortStableFunc(audits, func(a, b CertificateAudit) int {
+		return cmp.Or(
+			cmp.Compare(certificateStatusPriority(a.Status), certificateStatusPriority(b.Status)),
+			cmp.Compare(a.NotAfter, b.NotAfter),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return audits
+}
+
+func (a *CertificateAudit) setExpiry(notAfter, now time.Time, window time.Duration) {
+	a.NotAfter = notAfter.UTC().Format(time.RFC3339)
+	left := notAfter.Sub(now)
+	if left < 0 {
+		a.ExpiresIn = "-" + duration.HumanDuration(-left)
+	} else {
+		a.ExpiresIn = duration.HumanDuration(left)
+	}
+	switch {
+	case left <= 0:
+		a.Status = CertificateStatusExpired
+	case left <= window:
+		a.Status = CertificateStatusExpiring
+	default:
+		a.Status = CertificateStatusValid
+	}
+}
+
+func certificateStatusPriority(status string) int {
+	return slices.Index([]string{CertificateStatusExpired, CertificateStatusInvalid, CertificateStatusExpiring, CertificateStatusValid}, status)
+}
+
+// parseLeafCertificate parses the first (leaf) certificate of a PEM encoded chain
+func parseLeafCertificate(data []byte) (*x509.Certificate, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("secret has no %s", v1.TLSCertKey)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s is not a PEM encoded certificate", v1.TLSCertKey)
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", v1.TLSCertKey, err)
+	}
+	return certificate, nil
+}
@@ -0,0 +1,111 @@
+package kubernetes
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type CertificatesTestSuite struct {
+	suite.Suite
+}
+
+func (s *CertificatesTestSuite) tlsSecret(name string, notAfter time.Time, dnsNames ...string) v1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     dnsNames,
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	s.Require().NoError(err)
+	return v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Type:       v1.SecretTypeTLS,
+		Data:       map[string][]byte{v1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})},
+	}
+}
+
+func (s *CertificatesTestSuite) TestAuditCertificates() {
+	now := time.Now()
+	day := 24 * time.Hour
+	secrets := []v1.Secret{
+		s.tlsSecret("valid", now.Add(60*day), "valid.example.com"),
+		s.tlsSecret("expiring", now.Add(10*day), "*.example.com"),
+		s.tlsSecret("expired", now.Add(-day), "expired.example.com"),
+		{ObjectMeta: metav1.ObjectMeta{Name: "garbage", Namespace: "default"}, Type: v1.SecretTypeTLS, Data: map[string][]byte{v1.TLSCertKey: []byte("not a certificate")}},
+	}
+	ingresses := []networkingv1.Ingress{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: networkingv1.IngressSpec{TLS: []networkingv1.IngressTLS{
+			{SecretName: "valid", Hosts: []string{"valid.example.com", "other.example.org"}},
+			{SecretName: "expiring", Hosts: []string{"app.example.com"}},
+		}},
+	}}
+	certificates := []unstructured.Unstructured{{Object: map[string]any{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata":   map[string]any{"name": "valid-cert", "namespace": "default"},
+		"spec": map[string]any{
+			"secretName": "valid",
+			"dnsNames":   []any{"valid.example.com", "www.example.com"},
+			"issuerRef":  map[string]any{"kind": "ClusterIssuer", "name": "letsencrypt"},
+		},
+		"status": map[string]any{
+			"notAfter":   now.Add(60 * day).UTC().Format(time.RFC3339),
+			"conditions": []any{map[string]any{"type": "Ready", "status": "False", "message": "Issuing certificate"}},
+		},
+	}}}
+	audits := auditCertificates(secrets, ingresses, certificates, now, 30*day)
+	s.Run("returns all certificates sorted by urgency", func() {
+		var found []string
+		for _, audit := range audits {
+			found = append(found, audit.Kind+"/"+audit.Name+":"+audit.Status)
+		}
+		s.Equal([]string{
+			"Secret/expired:expired",
+			"Secret/garbage:invalid",
+			"Secret/expiring:expiring",
+			"Secret/valid:valid",
+			"Certificate/valid-cert:valid",
+		}, found)
+	})
+	s.Run("reports certificate details", func() {
+		s.Equal("CN=expired", audits[0].Subject)
+		s.Equal([]string{"expired.example.com"}, audits[0].DNSNames)
+		s.Contains(audits[0].ExpiresIn, "-")
+		s.Equal([]string{"tls.crt is not a PEM encoded certificate"}, audits[1].Issues)
+	})
+	s.Run("wildcard SANs cover Ingress hosts", func() {
+		s.Empty(audits[2].Issues)
+	})
+	s.Run("reports Ingress hosts not covered by the SANs", func() {
+		s.Equal([]string{"host other.example.org served by an Ingress is not covered by the certificate SANs"}, audits[3].Issues)
+	})
+	s.Run("reports cert-manager Certificate issues", func() {
+		s.Equal("ClusterIssuer/letsencrypt", audits[4].Issuer)
+		s.Equal([]string{
+			"Certificate is not ready: Issuing certificate",
+			"DNS name www.example.com requested by the Certificate is missing from the issued certificate in Secret valid",
+		}, audits[4].Issues)
+	})
+}
+
+func TestCertificates(t *testing.T) {
+	suite.Run(t, new(CertificatesTestSuite))
+}
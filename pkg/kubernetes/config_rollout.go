@@ -0,0 +1,319 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// ConfigRolloutKinds are the kinds of configuration supported by ConfigRollout
+var ConfigRolloutKinds = []string{"ConfigMap", "Secret"}
+
+// restartedAtAnnotation is the Pod template annotation changed to restart a workload (like kubectl rollout restart)
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+const (
+	ConfigRolloutPending   = "Pending"
+	ConfigRolloutRestarted = "Restarted"
+	ConfigRolloutRolledOut = "RolledOut"
+	ConfigRolloutTimedOut  = "TimedOut"
+	ConfigRolloutFailed    = "Failed"
+)
+
+// ConfigRollout is the result of the update of a ConfigMap or Secret and of the restart of the workloads referencing it.
+type ConfigRollout struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// DryRun is true if the configuration and the workloads were left unchanged
+	DryRun      bool     `json:"dryRun,omitempty"`
+	UpdatedKeys []string `json:"updatedKeys,omitempty"`
+	RemovedKeys []string `json:"removedKeys,omitempty"`
+	// Workloads are the workloads whose Pod template references the configuration
+	Workloads []ConfigRolloutWorkload `json:"workloads"`
+}
+
+// ConfigRolloutWorkload is a workload referencing the configuration and the status of its restart.
+type ConfigRolloutWorkload struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// References describe how the Pod template references the configuration (e.g. "volume config", "container app envFrom")
+	References []string `json:"references"`
+	Status     string   `json:"status"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// ConfigRolloutProgress is notified of each completed step of a ConfigRollout out of the total number of steps.
+type ConfigRolloutProgress func(step, total int, message string)
+
+// ConfigRollout updates the keys of a ConfigMap or Secret (the values of the Secret are provided in plain text),
+// then restarts the Deployments, StatefulSets, and DaemonSets of its namespace mounting or referencing it, and
+// waits for their rollouts to complete (not waited for if timeout is not positive).
+// The workloads are only discovered if dryRun is true.
+func (c *Core) ConfigRollout(ctx context.Context, kind, namespace, name string, data map[string]string, removeKeys []string,
+	dryRun bool, timeout time.Duration, progress ConfigRolloutProgress) (*ConfigRollout, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	rollout := &ConfigRollout{Kind: kind, Namespace: namespace, Name: name, DryRun: dryRun,
+		UpdatedKeys: slices.Sorted(maps.Keys(data)), RemovedKeys: removeKeys}
+	var update func() error
+	switch kind {
+	case "ConfigMap":
+		configMap, err := c.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		maps.Copy(configMap.Data, data)
+		for _, key := range removeKeys {
+			delete(configMap.Data, key)
+			delete(configMap.BinaryData, key)
+		}
+		update = func() error {
+			_, err := c.CoreV1().ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+			return err
+		}
+	case "Secret":
+		secret, err := c.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		for key, value := range data {
+			secret.Data[key] = []byte(value)
+		}
+		for _, key := range removeKeys {
+			delete(secret.Data, key)
+			delete(secret.StringData, key)
+		}
+		update = func() error {
+			_, err := c.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+			return err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported configuration kind %s, supported kinds are %v", kind, ConfigRolloutKinds)
+	}
+	workloads, err := c.configWorkloads(ctx, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	rollout.Workloads = make([]ConfigRolloutWorkload, 0, len(workloads))
+	for _, workload := range workloads {
+		rollout.Workloads = append(rollout.Workloads, workload.ConfigRolloutWorkload)
+	}
+	if dryRun {
+		return rollout, nil
+	}
+	// the update, the restart of each workload, and the completion of each rollout if waited for
+	total := 1 + len(workloads)
+	if timeout > 0 {
+		total += len(workloads)
+	}
+	step := 0
+	notify := func(format string, args ...any) {
+		step++
+		if progress != nil {
+			progress(step, total, fmt.Sprintf(format, args...))
+		}
+	}
+	if err = update(); err != nil {
+		return nil, err
+	}
+	notify("%s %s/%s updated", kind, namespace, name)
+	restartedAt := time.Now().UTC().Format(time.RFC3339)
+	for i, workload := range workloads {
+		if err = c.restartWorkload(ctx, workload.Kind, namespace, workload.Name, restartedAt); err != nil {
+			rollout.Workloads[i].Status, rollout.Workloads[i].Error = ConfigRolloutFailed, err.Error()
+			notify("%s %s failed to restart: %v", workload.Kind, workload.Name, err)
+			continue
+		}
+		rollout.Workloads[i].Status = ConfigRolloutRestarted
+		notify("%s %s restarted", workload.Kind, workload.Name)
+	}
+	if timeout <= 0 {
+		return rollout, nil
+	}
+	// the requests use the parent context, the timeout only applies to the wait
+	err = wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(context.Context) (bool, error) {
+		done := true
+		for i := range rollout.Workloads {
+			workload := &rollout.Workloads[i]
+			if workload.Status != ConfigRolloutRestarted {
+				continue
+			}
+			complete, err := c.rolloutComplete(ctx, workload.Kind, namespace, workload.Name)
+			if err != nil {
+				return false, err
+			}
+			if !complete {
+				done = false
+				continue
+			}
+			workload.Status = ConfigRolloutRolledOut
+			notify("%s %s rolled out", workload.Kind, workload.Name)
+		}
+		return done, nil
+	})
+	switch {
+	case wait.Interrupted(err):
+		for i := range rollout.Workloads {
+			if rollout.Workloads[i].Status == ConfigRolloutRestarted {
+				rollout.Workloads[i].Status = ConfigRolloutTimedOut
+				rollout.Workloads[i].Error = fmt.Sprintf("rollout not complete within %s", timeout)
+			}
+		}
+	case err != nil:
+		return nil, err
+	}
+	return rollout, nil
+}
+
+type configWorkload struct {
+	ConfigRolloutWorkload
+	template v1.PodTemplateSpec
+}
+
+// configWorkloads returns the Deployments, StatefulSets, and DaemonSets of the namespace whose Pod template references the configuration
+func (c *Core) configWorkloads(ctx context.Context, kind, namespace, name string) ([]configWorkload, error) {
+	var candidates []configWorkload
+	deployments, err := c.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, deployment := range deployments.Items {
+		candidates = append(candidates, configWorkload{ConfigRolloutWorkload{Kind: "Deployment", Name: deployment.Name}, deployment.Spec.Template})
+	}
+	statefulSets, err := c.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, statefulSet := range statefulSets.Items {
+		candidates = append(candidates, configWorkload{ConfigRolloutWorkload{Kind: "StatefulSet", Name: statefulSet.Name}, statefulSet.Spec.Template})
+	}
+	daemonSets, err := c.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, daemonSet := range daemonSets.Items {
+		candidates = append(candidates, configWorkload{ConfigRolloutWorkload{Kind: "DaemonSet", Name: daemonSet.Name}, daemonSet.Spec.Template})
+	}
+	ret := make([]configWorkload, 0)
+	for _, candidate := range candidates {
+		if candidate.References = configReferences(candidate.template.Spec, kind, name); len(candidate.References) > 0 {
+			candidate.Status = ConfigRolloutPending
+			ret = append(ret, candidate)
+		}
+	}
+	return ret, nil
+}
+
+// configReferences describes how the Pod spec mounts or references the ConfigMap or Secret
+func configReferences(spec v1.PodSpec, kind, name string) []string {
+	var ret []string
+	isConfigMap := kind == "ConfigMap"
+	for _, volume := range spec.Volumes {
+		switch {
+		case isConfigMap && volume.ConfigMap != nil && volume.ConfigMap.Name == name,
+			!isConfigMap && volume.Secret != nil && volume.Secret.SecretName == name:
+			ret = append(ret, "volume "+volume.Name)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if (isConfigMap && source.ConfigMap != nil && source.ConfigMap.Name == name) ||
+					(!isConfigMap && source.Secret != nil && source.Secret.Name == name) {
+					ret = append(ret, "projected volume "+volume.Name)
+					break
+				}
+			}
+		}
+	}
+	for _, container := range slices.Concat(spec.InitContainers, spec.Containers) {
+		for _, envFrom := range container.EnvFrom {
+			if (isConfigMap && envFrom.ConfigMapRef != nil && envFrom.ConfigMapRef.Name == name) ||
+				(!isConfigMap && envFrom.SecretRef != nil && envFrom.SecretRef.Name == name) {
+				ret = append(ret, "container "+container.Name+" envFrom")
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if (isConfigMap && env.ValueFrom.ConfigMapKeyRef != nil && env.ValueFrom.ConfigMapKeyRef.Name == name) ||
+				(!isConfigMap && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == name) {
+				ret = append(ret, "container "+container.Name+" env "+env.Name)
+			}
+		}
+	}
+	return ret
+}
+
+// restartWorkload changes the restartedAt annotation of the Pod template of the workload to roll its Pods out
+func (c *Core) restartWorkload(ctx context.Context, kind, namespace, name, restartedAt string) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, restartedAtAnnotation, restartedAt))
+	var err error
+	switch kind {
+	case "Deployment":
+		_, err = c.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = c.AppsV1().StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "DaemonSet":
+		_, err = c.AppsV1().DaemonSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	}
+	return err
+}
+
+// rolloutComplete returns true once all the replicas of the workload are updated and available
+func (c *Core) rolloutComplete(ctx context.Context, kind, namespace, name string) (bool, error) {
+	switch kind {
+	case "Deployment":
+		deployment, err := c.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return deploymentRolledOut(deployment), nil
+	case "StatefulSet":
+		statefulSet, err := c.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return statefulSetRolledOut(statefulSet), nil
+	case "DaemonSet":
+		daemonSet, err := c.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return daemonSetRolledOut(daemonSet), nil
+	}
+	return false, fmt.Errorf("unsupported workload kind %s", kind)
+}
+
+func deploymentRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := replicasOrDefault(deployment.Spec.Replicas)
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+func statefulSetRolledOut(statefulSet *appsv1.StatefulSet) bool {
+	replicas := replicasOrDefault(statefulSet.Spec.Replicas)
+	return statefulSet.Status.ObservedGeneration >= statefulSet.Generation &&
+		statefulSet.Status.UpdatedReplicas == replicas &&
+		statefulSet.Status.ReadyReplicas == replicas
+}
+
+func daemonSetRolledOut(daemonSet *appsv1.DaemonSet) bool {
+	return daemonSet.Status.ObservedGeneration >= daemonSet.Generation &&
+		daemonSet.Status.UpdatedNumberScheduled == daemonSet.Status.DesiredNumberScheduled &&
+		daemonSet.Status.NumberAvailable == daemonSet.Status.DesiredNumberScheduled
+}
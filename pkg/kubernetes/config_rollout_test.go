@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+type ConfigRolloutTestSuite struct {
+	suite.Suite
+}
+
+func (s *ConfigRolloutTestSuite) TestConfigReferences() {
+	spec := v1.PodSpec{
+		Volumes: []v1.Volume{
+			{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "app"}}}},
+			{Name: "tls", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "app"}}},
+			{Name: "bundle", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{Sources: []v1.VolumeProjection{
+				{ConfigMap: &v1.ConfigMapProjection{LocalObjectReference: v1.LocalObjectReference{Name: "other"}}},
+				{ConfigMap: &v1.ConfigMapProjection{LocalObjectReference: v1.LocalObjectReference{Name: "app"}}},
+			}}}},
+		},
+		InitContainers: []v1.Container{{Name: "init", EnvFrom: []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "app"}}}}}},
+		Containers: []v1.Container{{Name: "app",
+			EnvFrom: []v1.EnvFromSource{{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "app"}}}},
+			Env: []v1.EnvVar{
+				{Name: "LOG_LEVEL", ValueFrom: &v1.EnvVarSource{ConfigMapKeyRef: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "app"}, Key: "log-level"}}},
+				{Name: "PASSWORD", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "app"}, Key: "password"}}},
+				{Name: "PLAIN", Value: "app"},
+			},
+		}},
+	}
+	s.Run("ConfigMap", func() {
+		s.Equal([]string{"volume config", "projected volume bundle", "container app envFrom", "container app env LOG_LEVEL"},
+			configReferences(spec, "ConfigMap", "app"))
+	})
+	s.Run("Secret", func() {
+		s.Equal([]string{"volume tls", "container init envFrom", "container app env PASSWORD"},
+			configReferences(spec, "Secret", "app"))
+	})
+	s.Run("not referenced", func() {
+		s.Empty(configReferences(spec, "ConfigMap", "unknown"))
+	})
+}
+
+func (s *ConfigRolloutTestSuite) TestRolledOut() {
+	s.Run("Deployment with old replicas", func() {
+		deployment := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))}}
+		deployment.Generation = 2
+		deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2}
+		s.False(deploymentRolledOut(deployment))
+		deployment.Status.Replicas = 2
+		s.True(deploymentRolledOut(deployment))
+	})
+	s.Run("Deployment not observed yet", func() {
+		deployment := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(1))}}
+		deployment.Generation = 3
+		deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+		s.False(deploymentRolledOut(deployment))
+	})
+	s.Run("StatefulSet", func() {
+		statefulSet := &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Replicas: ptr.To(int32(3))}}
+		statefulSet.Status = appsv1.StatefulSetStatus{UpdatedReplicas: 2, ReadyReplicas: 3}
+		s.False(statefulSetRolledOut(statefulSet))
+		statefulSet.Status.UpdatedReplicas = 3
+		s.True(statefulSetRolledOut(statefulSet))
+	})
+	s.Run("DaemonSet", func() {
+		daemonSet := &appsv1.DaemonSet{Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2}}
+		s.False(daemonSetRolledOut(daemonSet))
+		daemonSet.Status.NumberAvailable = 3
+		s.True(daemonSetRolledOut(daemonSet))
+	})
+}
+
+func TestConfigRollout(t *testing.T) {
+	suite.Run(t, new(ConfigRolloutTestSuite))
+}
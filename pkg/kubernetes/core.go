@@ -4,6 +4,8 @@ import "github.com/containers/kubernetes-mcp-server/pkg/api"
 
 type Core struct {
 	api.KubernetesClient
+	readCache          *ReadCacheConfig
+	readCacheFreshness *ReadCacheFreshness
 }
 
 func NewCore(client api.KubernetesClient) *Core {
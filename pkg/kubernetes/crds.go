@@ -0,0 +1,201 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var crdResource = apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+
+// CRD is the summary of a CustomResourceDefinition
+type CRD struct {
+	Name       string       `json:"name"`
+	Group      string       `json:"group"`
+	Kind       string       `json:"kind"`
+	Plural     string       `json:"plural"`
+	ShortNames []string     `json:"shortNames,omitempty"`
+	Scope      string       `json:"scope"`
+	Versions   []CRDVersion `json:"versions"`
+	// Established is true once the API server serves the resources of the CRD
+	Established bool `json:"established"`
+	// Issue is the reason of the CRD not being established (e.g. names conflicting with another CRD)
+	Issue string `json:"issue,omitempty"`
+}
+
+type CRDVersion struct {
+	Name       string `json:"name"`
+	Served     bool   `json:"served"`
+	Storage    bool   `json:"storage"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+}
+
+// CRDSchema is the OpenAPI v3 schema of a version of a CustomResourceDefinition
+type CRDSchema struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Version string `json:"version"`
+	// Field is the path of the returned schema in the schema of the resource, empty for the whole resource
+	Field  string                           `json:"field,omitempty"`
+	Schema *apiextensionsv1.JSONSchemaProps `json:"schema"`
+}
+
+// CRDsList returns the CustomResourceDefinitions, of the API group if provided, sorted by name
+func (c *Core) CRDsList(ctx context.Context, group string) ([]CRD, error) {
+	list, err := c.DynamicClient().Resource(crdResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]CRD, 0, len(list.Items))
+	for _, item := range list.Items {
+		crd, err := toCRD(&item)
+		if err != nil {
+			return nil, err
+		}
+		if group != "" && crd.Spec.Group != group {
+			continue
+		}
+		ret = append(ret, crdSummary(crd))
+	}
+	slices.SortFunc(ret, func(a, b CRD) int { return strings.Compare(a.Name, b.Name) })
+	return ret, nil
+}
+
+// CRDSchemaGet returns the schema of the version (the storage version if empty) of the CustomResourceDefinition,
+// or of one of its fields if a dot-separated path is provided (e.g. spec.template, the items of the arrays are traversed)
+func (c *Core) CRDSchemaGet(ctx context.Context, name, version, field string) (*CRDSchema, error) {
+	obj, err := c.DynamicClient().Resource(crdResource).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	crd, err := toCRD(obj)
+	if err != nil {
+		return nil, err
+	}
+	ret := &CRDSchema{Name: crd.Name, Kind: crd.Spec.Names.Kind, Field: field}
+	var versions []string
+	for _, v := range crd.Spec.Versions {
+		versions = append(versions, v.Name)
+		if v.Name == version || (version == "" && v.Storage) {
+			ret.Version = v.Name
+			if v.Schema != nil {
+				ret.Schema = v.Schema.OpenAPIV3Schema
+			}
+		}
+	}
+	if ret.Version == "" {
+		return nil, fmt.Errorf("CustomResourceDefinition %s has no version %s, available versions: %s", name, version, strings.Join(versions, ", "))
+	}
+	if ret.Schema == nil {
+		return nil, fmt.Errorf("version %s of CustomResourceDefinition %s has no schema", ret.Version, name)
+	}
+	if ret.Schema, err = schemaField(ret.Schema, field); err != nil {
+		return nil, fmt.Errorf("version %s of CustomResourceDefinition %s: %w", ret.Version, name, err)
+	}
+	return ret, nil
+}
+
+// CRDWaitEstablished waits for the CustomResourceDefinition to be established, so that its custom resources can be
+// created. A CRD not found yet (e.g. installed by an operator starting up) is waited for too.
+func (c *Core) CRDWaitEstablished(ctx context.Context, name string, timeout time.Duration) (*CRD, error) {
+	var ret *CRD
+	// The requests use the parent context, the timeout only applies to the wait
+	err := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(context.Context) (bool, error) {
+		obj, err := c.DynamicClient().Resource(crdResource).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			ret = nil
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		crd, err := toCRD(obj)
+		if err != nil {
+			return false, err
+		}
+		summary := crdSummary(crd)
+		ret = &summary
+		if ret.Issue != "" && !ret.Established {
+			return false, fmt.Errorf("CustomResourceDefinition %s can't be established: %s", name, ret.Issue)
+		}
+		return ret.Established, nil
+	})
+	switch {
+	case err == nil:
+		// the cached discovery doesn't include the resources of the CRD yet
+		c.RESTMapper().Reset()
+		return ret, nil
+	case wait.Interrupted(err) && ret == nil:
+		return nil, fmt.Errorf("CustomResourceDefinition %s was not found within %s", name, timeout)
+	case wait.Interrupted(err):
+		return nil, fmt.Errorf("CustomResourceDefinition %s was not established within %s", name, timeout)
+	}
+	return nil, err
+}
+
+func toCRD(obj *unstructured.Unstructured) (*apiextensionsv1.CustomResourceDefinition, error) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, crd); err != nil {
+		return nil, fmt.Errorf("failed to read CustomResourceDefinition %s: %w", obj.GetName(), err)
+	}
+	return crd, nil
+}
+
+func crdSummary(crd *apiextensionsv1.CustomResourceDefinition) CRD {
+	ret := CRD{
+		Name:       crd.Name,
+		Group:      crd.Spec.Group,
+		Kind:       crd.Spec.Names.Kind,
+		Plural:     crd.Spec.Names.Plural,
+		ShortNames: crd.Spec.Names.ShortNames,
+		Scope:      string(crd.Spec.Scope),
+	}
+	for _, v := range crd.Spec.Versions {
+		ret.Versions = append(ret.Versions, CRDVersion{Name: v.Name, Served: v.Served, Storage: v.Storage, Deprecated: v.Deprecated})
+	}
+	for _, condition := range crd.Status.Conditions {
+		switch {
+		case condition.Type == apiextensionsv1.Established:
+			ret.Established = condition.Status == apiextensionsv1.ConditionTrue
+		case condition.Type == apiextensionsv1.NamesAccepted && condition.Status == apiextensionsv1.ConditionFalse:
+			ret.Issue = condition.Message
+		case condition.Type == apiextensionsv1.Terminating && condition.Status == apiextensionsv1.ConditionTrue:
+			ret.Issue = "the CustomResourceDefinition is being deleted"
+		}
+	}
+	return ret
+}
+
+// schemaField returns the schema of the field at the dot-separated path, the items of the arrays and the values of
+// the maps (additionalProperties) are traversed implicitly
+func schemaField(schema *apiextensionsv1.JSONSchemaProps, field string) (*apiextensionsv1.JSONSchemaProps, error) {
+	if field == "" {
+		return schema, nil
+	}
+	for _, name := range strings.Split(field, ".") {
+		for {
+			if schema.Items != nil && schema.Items.Schema != nil {
+				schema = schema.Items.Schema
+			} else if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil && len(schema.Properties) == 0 {
+				schema = schema.AdditionalProperties.Schema
+			} else {
+				break
+			}
+		}
+		property, ok := schema.Properties[name]
+		if !ok {
+			return nil, fmt.Errorf("field %s not found in the schema", field)
+		}
+		schema = &property
+	}
+	return schema, nil
+}
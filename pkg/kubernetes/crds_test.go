@@ -0,0 +1,110 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CRDsTestSuite struct {
+	suite.Suite
+}
+
+func (s *CRDsTestSuite) TestCRDSummary() {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Widget", Plural: "widgets", ShortNames: []string{"wd"}},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true, Deprecated: true},
+				{Name: "v1", Served: true, Storage: true},
+			},
+		},
+	}
+	s.Run("reports versions and scope", func() {
+		summary := crdSummary(crd)
+		s.Equal("example.com", summary.Group)
+		s.Equal("Widget", summary.Kind)
+		s.Equal("Namespaced", summary.Scope)
+		s.Equal([]CRDVersion{{Name: "v1alpha1", Served: true, Deprecated: true}, {Name: "v1", Served: true, Storage: true}}, summary.Versions)
+		s.False(summary.Established)
+		s.Empty(summary.Issue)
+	})
+	s.Run("reports established condition", func() {
+		crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{
+			{Type: apiextensionsv1.NamesAccepted, Status: apiextensionsv1.ConditionTrue},
+			{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+		}
+		summary := crdSummary(crd)
+		s.True(summary.Established)
+		s.Empty(summary.Issue)
+	})
+	s.Run("reports names conflict", func() {
+		crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{
+			{Type: apiextensionsv1.NamesAccepted, Status: apiextensionsv1.ConditionFalse, Message: `"wd" is already in use`},
+			{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionFalse},
+		}
+		summary := crdSummary(crd)
+		s.False(summary.Established)
+		s.Equal(`"wd" is already in use`, summary.Issue)
+	})
+}
+
+func (s *CRDsTestSuite) TestSchemaField() {
+	schema := &apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"ports": {
+						Type: "array",
+						Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+							Type:       "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{"port": {Type: "integer", Description: "The port"}},
+						}},
+					},
+					"labels": {
+						Type: "object",
+						AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Schema: &apiextensionsv1.JSONSchemaProps{
+							Type:       "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{"value": {Type: "string"}},
+						}},
+					},
+				},
+			},
+		},
+	}
+	s.Run("returns whole schema without field", func() {
+		field, err := schemaField(schema, "")
+		s.Require().NoError(err)
+		s.Same(schema, field)
+	})
+	s.Run("returns nested field", func() {
+		field, err := schemaField(schema, "spec.ports")
+		s.Require().NoError(err)
+		s.Equal("array", field.Type)
+	})
+	s.Run("traverses array items", func() {
+		field, err := schemaField(schema, "spec.ports.port")
+		s.Require().NoError(err)
+		s.Equal("The port", field.Description)
+	})
+	s.Run("traverses map values", func() {
+		field, err := schemaField(schema, "spec.labels.value")
+		s.Require().NoError(err)
+		s.Equal("string", field.Type)
+	})
+	s.Run("returns error for unknown field", func() {
+		_, err := schemaField(schema, "spec.replicas")
+		s.EqualError(err, "field spec.replicas not found in the schema")
+	})
+}
+
+func TestCRDs(t *testing.T) {
+	suite.Run(t, new(CRDsTestSuite))
+}
@@ -0,0 +1,123 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// deploymentConfigNameLabel is the label set on the ReplicationControllers of a DeploymentConfig rollout
+	deploymentConfigNameLabel = "openshift.io/deployment-config.name"
+	// deploymentConfigVersionAnnotation is the annotation with the version of a DeploymentConfig rollout set on its ReplicationControllers
+	deploymentConfigVersionAnnotation = "openshift.io/deployment-config.latest-version"
+	// deploymentConfigPhaseAnnotation is the annotation with the phase (New, Pending, Running, Complete, Failed) of a DeploymentConfig rollout
+	deploymentConfigPhaseAnnotation = "openshift.io/deployment.phase"
+)
+
+var openShiftDeploymentConfigs = schema.GroupVersionResource{Group: "apps.openshift.io", Version: "v1", Resource: "deploymentconfigs"}
+
+// DeploymentConfigRollout is the rollout status and history of an OpenShift DeploymentConfig.
+type DeploymentConfigRollout struct {
+	Namespace         string   `json:"namespace"`
+	Name              string   `json:"name"`
+	LatestVersion     int64    `json:"latestVersion"`
+	Paused            bool     `json:"paused,omitempty"`
+	Replicas          int64    `json:"replicas"`
+	ReadyReplicas     int64    `json:"readyReplicas"`
+	AvailableReplicas int64    `json:"availableReplicas"`
+	UpdatedReplicas   int64    `json:"updatedReplicas"`
+	Triggers          []string `json:"triggers,omitempty"`
+	Conditions        []string `json:"conditions,omitempty"`
+	// Revisions are the rollouts of the DeploymentConfig, latest first
+	Revisions []DeploymentConfigRevision `json:"revisions"`
+}
+
+// DeploymentConfigRevision is a rollout of a DeploymentConfig and the ReplicationController created for it.
+type DeploymentConfigRevision struct {
+	Version               int64    `json:"version"`
+	ReplicationController string   `json:"replicationController"`
+	Phase                 string   `json:"phase,omitempty"`
+	Replicas              int32    `json:"replicas"`
+	ReadyReplicas         int32    `json:"readyReplicas"`
+	Images                []string `json:"images"`
+	Created               string   `json:"created"`
+}
+
+// DeploymentConfigRollout returns the rollout status of the provided OpenShift DeploymentConfig, its triggers and
+// conditions, and the history of its rollouts from the ReplicationControllers created for each version.
+func (c *Core) DeploymentConfigRollout(ctx context.Context, namespace, name string) (*DeploymentConfigRollout, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	deploymentConfig, err := c.DynamicClient().Resource(openShiftDeploymentConfigs).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	replicationControllers, err := c.CoreV1().ReplicationControllers(namespace).List(ctx, metav1.ListOptions{LabelSelector: deploymentConfigNameLabel + "=" + name})
+	if err != nil {
+		return nil, err
+	}
+	return deploymentConfigRollout(deploymentConfig, replicationControllers.Items), nil
+}
+
+func deploymentConfigRollout(deploymentConfig *unstructured.Unstructured, replicationControllers []v1.ReplicationController) *DeploymentConfigRollout {
+	rollout := &DeploymentConfigRollout{Namespace: deploymentConfig.GetNamespace(), Name: deploymentConfig.GetName()}
+	rollout.Paused, _, _ = unstructured.NestedBool(deploymentConfig.Object, "spec", "paused")
+	rollout.Replicas, _, _ = unstructured.NestedInt64(deploymentConfig.Object, "spec", "replicas")
+	rollout.LatestVersion, _, _ = unstructured.NestedInt64(deploymentConfig.Object, "status", "latestVersion")
+	rollout.ReadyReplicas, _, _ = unstructured.NestedInt64(deploymentConfig.Object, "status", "readyReplicas")
+	rollout.AvailableReplicas, _, _ = unstructured.NestedInt64(deploymentConfig.Object, "status", "availableReplicas")
+	rollout.UpdatedReplicas, _, _ = unstructured.NestedInt64(deploymentConfig.Object, "status", "updatedReplicas")
+	triggers, _, _ := unstructured.NestedSlice(deploymentConfig.Object, "spec", "triggers")
+	for _, trigger := range triggers {
+		trigger, _ := trigger.(map[string]any)
+		description, _ := trigger["type"].(string)
+		if imageChange, ok := trigger["imageChangeParams"].(map[string]any); ok {
+			from, _ := imageChange["from"].(map[string]any)
+			automatic, _ := imageChange["automatic"].(bool)
+			description += fmt.Sprintf(" %v (containers %v, automatic %t)", from["name"], imageChange["containerNames"], automatic)
+		}
+		rollout.Triggers = append(rollout.Triggers, description)
+	}
+	conditions, _, _ := unstructured.NestedSlice(deploymentConfig.Object, "status", "conditions")
+	for _, condition := range conditions {
+		condition, _ := condition.(map[string]any)
+		description := fmt.Sprintf("%v=%v", condition["type"], condition["status"])
+		if reason, _ := condition["reason"].(string); reason != "" {
+			description += " " + reason
+		}
+		if message, _ := condition["message"].(string); message != "" {
+			description += ": " + message
+		}
+		rollout.Conditions = append(rollout.Conditions, description)
+	}
+	rollout.Revisions = make([]DeploymentConfigRevision, 0, len(replicationControllers))
+	for _, replicationController := range replicationControllers {
+		revision := DeploymentConfigRevision{
+			ReplicationController: replicationController.Name,
+			Phase:                 replicationController.Annotations[deploymentConfigPhaseAnnotation],
+			Replicas:              replicasOrDefault(replicationController.Spec.Replicas),
+			ReadyReplicas:         replicationController.Status.ReadyReplicas,
+			Images:                make([]string, 0),
+			Created:               replicationController.CreationTimestamp.UTC().Format(time.RFC3339),
+		}
+		revision.Version, _ = strconv.ParseInt(replicationController.Annotations[deploymentConfigVersionAnnotation], 10, 64)
+		if replicationController.Spec.Template != nil {
+			for _, container := range replicationController.Spec.Template.Spec.Containers {
+				revision.Images = append(revision.Images, container.Name+"="+container.Image)
+			}
+		}
+		rollout.Revisions = append(rollout.Revisions, revision)
+	}
+	slices.SortFunc(rollout.Revisions, func(a, b DeploymentConfigRevision) int {
+		return cmp.Or(cmp.Compare(b.Version, a.Version), cmp.Compare(b.Created, a.Created))
+	})
+	return rollout
+}
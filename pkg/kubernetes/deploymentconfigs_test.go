@@ -0,0 +1,71 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+)
+
+type DeploymentConfigsTestSuite struct {
+	suite.Suite
+}
+
+func (s *DeploymentConfigsTestSuite) TestDeploymentConfigRollout() {
+	base := time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC)
+	deploymentConfig := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps.openshift.io/v1",
+		"kind":       "DeploymentConfig",
+		"metadata":   map[string]any{"name": "web", "namespace": "default"},
+		"spec": map[string]any{
+			"replicas": int64(2),
+			"triggers": []any{
+				map[string]any{"type": "ConfigChange"},
+				map[string]any{"type": "ImageChange", "imageChangeParams": map[string]any{
+					"automatic": true, "containerNames": []any{"web"}, "from": map[string]any{"kind": "ImageStreamTag", "name": "web:latest"},
+				}},
+			},
+		},
+		"status": map[string]any{
+			"latestVersion": int64(3), "readyReplicas": int64(1), "availableReplicas": int64(1), "updatedReplicas": int64(1),
+			"conditions": []any{
+				map[string]any{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded", "message": "replication controller \"web-3\" has failed progressing"},
+			},
+		},
+	}}
+	replicationController := func(version string, phase string, created time.Time, image string) v1.ReplicationController {
+		return v1.ReplicationController{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-" + version, Namespace: "default", CreationTimestamp: metav1.NewTime(created),
+				Annotations: map[string]string{deploymentConfigVersionAnnotation: version, deploymentConfigPhaseAnnotation: phase}},
+			Spec: v1.ReplicationControllerSpec{Replicas: ptr.To(int32(1)), Template: &v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "web", Image: image}}}}},
+		}
+	}
+	rollout := deploymentConfigRollout(deploymentConfig, []v1.ReplicationController{
+		replicationController("2", "Complete", base, "web:1.0"),
+		replicationController("3", "Failed", base.Add(time.Hour), "web:2.0"),
+	})
+	s.Run("summarizes the status", func() {
+		s.Equal(int64(3), rollout.LatestVersion)
+		s.Equal(int64(2), rollout.Replicas)
+		s.Equal(int64(1), rollout.AvailableReplicas)
+	})
+	s.Run("summarizes the triggers and conditions", func() {
+		s.Equal([]string{"ConfigChange", "ImageChange web:latest (containers [web], automatic true)"}, rollout.Triggers)
+		s.Equal([]string{"Progressing=False ProgressDeadlineExceeded: replication controller \"web-3\" has failed progressing"}, rollout.Conditions)
+	})
+	s.Run("returns the revisions latest first", func() {
+		s.Require().Len(rollout.Revisions, 2)
+		s.Equal(DeploymentConfigRevision{
+			Version: 3, ReplicationController: "web-3", Phase: "Failed", Replicas: 1, Images: []string{"web=web:2.0"}, Created: "2025-01-01T15:00:00Z",
+		}, rollout.Revisions[0])
+		s.Equal(int64(2), rollout.Revisions[1].Version)
+	})
+}
+
+func TestDeploymentConfigs(t *testing.T) {
+	suite.Run(t, new(DeploymentConfigsTestSuite))
+}
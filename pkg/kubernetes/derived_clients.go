@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+const (
+	// derivedClientIdleTimeout is the duration a derived client is kept after its last use
+	derivedClientIdleTimeout = 10 * time.Minute
+	// maxDerivedClients is the maximum number of derived clients kept, the least recently used are evicted
+	maxDerivedClients = 256
+)
+
+// derivedClients keeps the clients derived for each identity (bearer token and user agent), so that the tool calls
+// and sessions of the same identity reuse their clientsets and connections instead of building them on every call
+type derivedClients struct {
+	mu      sync.Mutex
+	clients map[string]*derivedClient
+	now     func() time.Time
+}
+
+type derivedClient struct {
+	kubernetes *Kubernetes
+	lastUsed   time.Time
+}
+
+func newDerivedClients() *derivedClients {
+	return &derivedClients{clients: make(map[string]*derivedClient), now: time.Now}
+}
+
+// derivedClientKey identifies the derived client without keeping the bearer token as is
+func derivedClientKey(authorization, userAgent string) string {
+	hash := sha256.Sum256([]byte(authorization + "\x00" + userAgent))
+	return hex.EncodeToString(hash[:])
+}
+
+// get returns the client of the identity, or the one created (and kept) by newClient if there's none
+func (d *derivedClients) get(key string, newClient func() (*Kubernetes, error)) (*Kubernetes, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	for k, client := range d.clients {
+		if now.Sub(client.lastUsed) > derivedClientIdleTimeout {
+			delete(d.clients, k)
+		}
+	}
+	if client, ok := d.clients[key]; ok {
+		client.lastUsed = now
+		return client.kubernetes, nil
+	}
+	k, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	if len(d.clients) >= maxDerivedClients {
+		d.evictLeastRecentlyUsed()
+	}
+	d.clients[key] = &derivedClient{kubernetes: k, lastUsed: now}
+	return k, nil
+}
+
+func (d *derivedClients) evictLeastRecentlyUsed() {
+	var oldestKey string
+	var oldest time.Time
+	for k, client := range d.clients {
+		if oldestKey == "" || client.lastUsed.Before(oldest) {
+			oldestKey, oldest = k, client.lastUsed
+		}
+	}
+	delete(d.clients, oldestKey)
+}
+
+// clear drops all the clients (e.g. the cluster state changed and their discovery is stale)
+func (d *derivedClients) clear() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	clear(d.clients)
+}
@@ -0,0 +1,128 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/stretchr/testify/suite"
+)
+
+type DerivedClientsTestSuite struct {
+	suite.Suite
+	now     time.Time
+	clients *derivedClients
+	created int
+}
+
+func (s *DerivedClientsTestSuite) SetupTest() {
+	s.now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.clients = &derivedClients{clients: map[string]*derivedClient{}, now: func() time.Time { return s.now }}
+	s.created = 0
+}
+
+func (s *DerivedClientsTestSuite) newClient() (*Kubernetes, error) {
+	s.created++
+	return &Kubernetes{}, nil
+}
+
+func (s *DerivedClientsTestSuite) TestGet() {
+	first, err := s.clients.get("identity-a", s.newClient)
+	s.Require().NoError(err)
+	s.Run("reuses the client of the same identity", func() {
+		s.now = s.now.Add(derivedClientIdleTimeout)
+		again, err := s.clients.get("identity-a", s.newClient)
+		s.Require().NoError(err)
+		s.Same(first, again)
+		s.Equal(1, s.created)
+	})
+	s.Run("creates a client per identity", func() {
+		other, err := s.clients.get("identity-b", s.newClient)
+		s.Require().NoError(err)
+		s.NotSame(first, other)
+		s.Equal(2, s.created)
+	})
+	s.Run("drops the clients idle for longer than the timeout", func() {
+		s.now = s.now.Add(derivedClientIdleTimeout + time.Second)
+		again, err := s.clients.get("identity-a", s.newClient)
+		s.Require().NoError(err)
+		s.NotSame(first, again)
+		s.Len(s.clients.clients, 1, "expected the idle identity-b client to be dropped")
+	})
+	s.Run("does not keep the clients that failed to be created", func() {
+		_, err := s.clients.get("identity-c", func() (*Kubernetes, error) { return nil, errors.New("boom") })
+		s.Require().Error(err)
+		s.NotContains(s.clients.clients, "identity-c")
+	})
+}
+
+func (s *DerivedClientsTestSuite) TestEvictLeastRecentlyUsed() {
+	for i := range maxDerivedClients {
+		s.now = s.now.Add(time.Millisecond)
+		_, _ = s.clients.get(fmt.Sprintf("identity-%d", i), s.newClient)
+	}
+	s.now = s.now.Add(time.Millisecond)
+	_, _ = s.clients.get("identity-0", s.newClient)
+	_, _ = s.clients.get("identity-new", s.newClient)
+	s.Len(s.clients.clients, maxDerivedClients)
+	s.Contains(s.clients.clients, "identity-0", "expected the recently used client to be kept")
+	s.NotContains(s.clients.clients, "identity-1", "expected the least recently used client to be evicted")
+}
+
+func (s *DerivedClientsTestSuite) TestKey() {
+	s.NotContains(derivedClientKey("Bearer secret-token", "agent"), "secret-token")
+	s.NotEqual(derivedClientKey("Bearer token", "agent-a"), derivedClientKey("Bearer token", "agent-b"))
+}
+
+func (s *DerivedClientsTestSuite) TestManager() {
+	kubeconfigPath := filepath.Join(s.T().TempDir(), "config")
+	s.Require().NoError(os.WriteFile(kubeconfigPath, []byte(`
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://derived-clients.example.com
+  name: test-cluster
+contexts:
+- context:
+    cluster: test-cluster
+    user: test-user
+  name: test-context
+current-context: test-context
+users:
+- name: test-user
+  user:
+    token: test-token
+`), 0644))
+	testManager, err := NewKubeconfigManager(test.Must(config.ReadToml([]byte(`
+		kubeconfig = "`+strings.ReplaceAll(kubeconfigPath, `\`, `\\`)+`"
+	`))), "")
+	s.Require().NoError(err)
+	derived := func(token string) *Kubernetes {
+		k, err := testManager.Derived(context.WithValue(s.T().Context(), OAuthAuthorizationHeader, "Bearer "+token))
+		s.Require().NoError(err)
+		return k
+	}
+	first := derived("token-a")
+	s.Run("reuses the derived client of the same token", func() {
+		s.Same(first, derived("token-a"))
+	})
+	s.Run("derives a new client for another token", func() {
+		s.NotSame(first, derived("token-b"))
+	})
+	s.Run("derives a new client after the invalidation", func() {
+		testManager.Invalidate()
+		s.NotSame(first, derived("token-a"))
+	})
+}
+
+func TestDerivedClients(t *testing.T) {
+	suite.Run(t, new(DerivedClientsTestSuite))
+}
@@ -0,0 +1,310 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
+)
+
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// diagnoseHighRestartThreshold is the number of container restarts above which a container is reported
+const diagnoseHighRestartThreshold = 5
+
+// diagnoseLogTailLines is the number of lines retrieved from the logs of the last terminated container instance
+const diagnoseLogTailLines = int64(20)
+
+// Finding is an issue detected while diagnosing a resource.
+type Finding struct {
+	Severity string `json:"severity"`
+	Reason   string `json:"reason"`
+	// Container is the name of the affected container (if the finding is container specific)
+	Container string `json:"container,omitempty"`
+	Message   string `json:"message"`
+}
+
+// PodDiagnosis is the result of the analysis of a Pod.
+type PodDiagnosis struct {
+	Name       string               `json:"name"`
+	Namespace  string               `json:"namespace"`
+	Phase      v1.PodPhase          `json:"phase"`
+	Reason     string               `json:"reason,omitempty"`
+	Node       string               `json:"node,omitempty"`
+	Findings   []Finding            `json:"findings"`
+	Containers []ContainerDiagnosis `json:"containers"`
+	Events     []DiagnosisEvent     `json:"events,omitempty"`
+}
+
+// ContainerDiagnosis contains the relevant state of a container of the diagnosed Pod.
+type ContainerDiagnosis struct {
+	Name         string `json:"name"`
+	Image        string `json:"image"`
+	Init         bool   `json:"init,omitempty"`
+	Ready        bool   `json:"ready"`
+	RestartCount int32  `json:"restartCount"`
+	State        string `json:"state"`
+	Reason       string `json:"reason,omitempty"`
+	Message      string `json:"message,omitempty"`
+	ExitCode     *int32 `json:"exitCode,omitempty"`
+	// LastTermination describes the previous (terminated) instance of the container
+	LastTermination *ContainerTermination `json:"lastTermination,omitempty"`
+	Probes          map[string]string     `json:"probes,omitempty"`
+}
+
+// ContainerTermination describes a terminated container instance.
+type ContainerTermination struct {
+	Reason     string `json:"reason,omitempty"`
+	Message    string `json:"message,omitempty"`
+	ExitCode   int32  `json:"exitCode"`
+	FinishedAt string `json:"finishedAt,omitempty"`
+	Logs       string `json:"logs,omitempty"`
+}
+
+// DiagnosisEvent is a condensed representation of an Event related to the diagnosed resource.
+type DiagnosisEvent struct {
+	Type     string `json:"type"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+	Count    int32  `json:"count,omitempty"`
+	LastSeen string `json:"lastSeen,omitempty"`
+}
+
+// PodsDiagnose gathers the status, container states, recent events, and last-termination logs of the Pod
+// and analyzes them to produce a list of findings, in the locale of the context.
+func (c *Core) PodsDiagnose(ctx context.Context, namespace, name string) (*PodDiagnosis, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	raw, err := c.ResourcesGet(ctx, &schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"}, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	pod := &v1.Pod{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(raw.Object, pod); err != nil {
+		return nil, err
+	}
+	// Events are best-effort, the diagnosis is still useful without them
+	events, _ := c.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{"involvedObject.kind": "Pod", "involvedObject.name": name}.String(),
+	})
+	var eventList []v1.Event
+	if events != nil {
+		eventList = events.Items
+	}
+	diagnosis := DiagnosePod(pod, eventList, locale.FromContext(ctx))
+	for i := range diagnosis.Containers {
+		container := &diagnosis.Containers[i]
+		if container.LastTermination == nil {
+			continue
+		}
+		logs, err := c.PodsLog(ctx, namespace, name, container.Name, true, diagnoseLogTailLines)
+		if err != nil {
+			continue
+		}
+		container.LastTermination.Logs = strings.TrimSpace(logs)
+	}
+	return diagnosis, nil
+}
+
+// DiagnosePod analyzes the provided Pod and its related events, the messages of the findings are rendered by the Printer.
+func DiagnosePod(pod *v1.Pod, events []v1.Event, p locale.Printer) *PodDiagnosis {
+	diagnosis := &PodDiagnosis{
+		Name:       pod.Name,
+		Namespace:  pod.Namespace,
+		Phase:      pod.Status.Phase,
+		Reason:     pod.Status.Reason,
+		Node:       pod.Spec.NodeName,
+		Findings:   []Finding{},
+		Containers: []ContainerDiagnosis{},
+	}
+	if pod.Status.Reason == "Evicted" {
+		diagnosis.Findings = append(diagnosis.Findings, Finding{
+			Severity: SeverityCritical, Reason: "Evicted", Message: pod.Status.Message,
+		})
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse {
+			diagnosis.Findings = append(diagnosis.Findings, Finding{
+				Severity: SeverityCritical, Reason: nonEmpty(condition.Reason, "Unschedulable"), Message: condition.Message,
+			})
+		}
+	}
+	diagnosis.diagnoseContainers(pod.Spec.InitContainers, pod.Status.InitContainerStatuses, true, p)
+	diagnosis.diagnoseContainers(pod.Spec.Containers, pod.Status.ContainerStatuses, false, p)
+
+	slices.SortStableFunc(events, func(a, b v1.Event) int {
+		return eventLastSeen(a).Compare(eventLastSeen(b))
+	})
+	reported := map[string]bool{}
+	for _, event := range events {
+		lastSeen := ""
+		if t := eventLastSeen(event); !t.IsZero() {
+			lastSeen = t.UTC().Format(time.RFC3339)
+		}
+		diagnosis.Events = append(diagnosis.Events, DiagnosisEvent{
+			Type: event.Type, Reason: event.Reason, Message: strings.TrimSpace(event.Message), Count: event.Count, LastSeen: lastSeen,
+		})
+		if event.Type != v1.EventTypeWarning || reported[event.Reason] {
+			continue
+		}
+		switch event.Reason {
+		case "Unhealthy":
+			diagnosis.Findings = append(diagnosis.Findings, Finding{
+				Severity: SeverityWarning, Reason: "ProbeFailure", Message: strings.TrimSpace(event.Message),
+			})
+		case "FailedMount", "FailedAttachVolume", "FailedCreatePodSandBox", "FailedScheduling":
+			diagnosis.Findings = append(diagnosis.Findings, Finding{
+				Severity: SeverityCritical, Reason: event.Reason, Message: strings.TrimSpace(event.Message),
+			})
+		default:
+			continue
+		}
+		reported[event.Reason] = true
+	}
+	return diagnosis
+}
+
+func (d *PodDiagnosis) diagnoseContainers(containers []v1.Container, statuses []v1.ContainerStatus, init bool, p locale.Printer) {
+	for _, container := range containers {
+		containerDiagnosis := ContainerDiagnosis{
+			Name:   container.Name,
+			Image:  container.Image,
+			Init:   init,
+			State:  "unknown",
+			Probes: probes(container),
+		}
+		idx := slices.IndexFunc(statuses, func(s v1.ContainerStatus) bool { return s.Name == container.Name })
+		if idx < 0 {
+			d.Containers = append(d.Containers, containerDiagnosis)
+			continue
+		}
+		status := statuses[idx]
+		containerDiagnosis.Ready = status.Ready
+		containerDiagnosis.RestartCount = status.RestartCount
+		switch {
+		case status.State.Waiting != nil:
+			containerDiagnosis.State = "waiting"
+			containerDiagnosis.Reason = status.State.Waiting.Reason
+			containerDiagnosis.Message = status.State.Waiting.Message
+		case status.State.Running != nil:
+			containerDiagnosis.State = "running"
+		case status.State.Terminated != nil:
+			containerDiagnosis.State = "terminated"
+			containerDiagnosis.Reason = status.State.Terminated.Reason
+			containerDiagnosis.Message = status.State.Terminated.Message
+			containerDiagnosis.ExitCode = &status.State.Terminated.ExitCode
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			containerDiagnosis.LastTermination = &ContainerTermination{
+				Reason:   terminated.Reason,
+				Message:  terminated.Message,
+				ExitCode: terminated.ExitCode,
+			}
+			if !terminated.FinishedAt.IsZero() {
+				containerDiagnosis.LastTermination.FinishedAt = terminated.FinishedAt.UTC().Format(time.RFC3339)
+			}
+		}
+		d.Containers = append(d.Containers, containerDiagnosis)
+		d.Findings = append(d.Findings, containerFindings(containerDiagnosis, status, p)...)
+	}
+}
+
+func containerFindings(container ContainerDiagnosis, status v1.ContainerStatus, p locale.Printer) []Finding {
+	var findings []Finding
+	add := func(severity, reason, message string) {
+		findings = append(findings, Finding{Severity: severity, Reason: reason, Container: container.Name, Message: message})
+	}
+	switch container.Reason {
+	case "ImagePullBackOff", "ErrImagePull", "InvalidImageName", "ErrImageNeverPull":
+		add(SeverityCritical, container.Reason, p.Sprintf("failed to pull image %s: %s", container.Image, container.Message))
+	case "CrashLoopBackOff":
+		message := p.Sprintf("container is crash looping (%d restarts)", container.RestartCount)
+		if container.LastTermination != nil {
+			message = p.Sprintf("container is crash looping (%d restarts), last terminated with exit code %d", container.RestartCount, container.LastTermination.ExitCode)
+			if container.LastTermination.Reason != "" {
+				message += fmt.Sprintf(" (%s)", container.LastTermination.Reason)
+			}
+		}
+		add(SeverityCritical, container.Reason, message)
+	case "CreateContainerConfigError", "CreateContainerError", "RunContainerError":
+		add(SeverityCritical, container.Reason, container.Message)
+	}
+	// The OOMKilled containers are reported by the OOMKilled finding rather than by their exit code (137)
+	if container.State == "terminated" && container.ExitCode != nil && *container.ExitCode != 0 && !container.Init && container.Reason != "OOMKilled" {
+		add(SeverityCritical, nonEmpty(container.Reason, "Error"), p.Sprintf("container terminated with exit code %d", *container.ExitCode))
+	}
+	if container.Reason == "OOMKilled" || (container.LastTermination != nil && container.LastTermination.Reason == "OOMKilled") {
+		add(SeverityCritical, "OOMKilled", p.Sprintf("container was killed because it exceeded its memory limit, consider increasing resources.limits.memory"))
+	}
+	if status.RestartCount >= diagnoseHighRestartThreshold && container.Reason != "CrashLoopBackOff" {
+		add(SeverityWarning, "HighRestartCount", p.Sprintf("container has restarted %d times", status.RestartCount))
+	}
+	if container.State == "running" && !status.Ready && !container.Init {
+		add(SeverityWarning, "NotReady", p.Sprintf("container is running but not ready, check the readiness probe"))
+	}
+	return findings
+}
+
+func probes(container v1.Container) map[string]string {
+	ret := map[string]string{}
+	for name, probe := range map[string]*v1.Probe{
+		"liveness": container.LivenessProbe, "readiness": container.ReadinessProbe, "startup": container.StartupProbe,
+	} {
+		if probe != nil {
+			ret[name] = describeProbe(probe)
+		}
+	}
+	if len(ret) == 0 {
+		return nil
+	}
+	return ret
+}
+
+func describeProbe(probe *v1.Probe) string {
+	var handler string
+	switch {
+	case probe.HTTPGet != nil:
+		handler = fmt.Sprintf("http-get %s:%s%s", strings.ToLower(string(probe.HTTPGet.Scheme)), probe.HTTPGet.Port.String(), probe.HTTPGet.Path)
+	case probe.TCPSocket != nil:
+		handler = fmt.Sprintf("tcp-socket :%s", probe.TCPSocket.Port.String())
+	case probe.Exec != nil:
+		handler = fmt.Sprintf("exec %v", probe.Exec.Command)
+	case probe.GRPC != nil:
+		handler = fmt.Sprintf("grpc :%d", probe.GRPC.Port)
+	default:
+		handler = "unknown"
+	}
+	return fmt.Sprintf("%s delay=%ds timeout=%ds period=%ds #failure=%d",
+		handler, probe.InitialDelaySeconds, probe.TimeoutSeconds, probe.PeriodSeconds, probe.FailureThreshold)
+}
+
+func eventLastSeen(event v1.Event) time.Time {
+	switch {
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.FirstTimestamp.Time
+}
+
+func nonEmpty(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
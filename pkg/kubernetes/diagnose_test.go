@@ -0,0 +1,116 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
+)
+
+type DiagnoseTestSuite struct {
+	suite.Suite
+}
+
+func (s *DiagnoseTestSuite) TestDiagnosePod() {
+	s.Run("healthy pod has no findings", func() {
+		diagnosis := DiagnosePod(&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "healthy", Namespace: "default"},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "nginx"}}},
+			Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{
+				Name: "app", Ready: true, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+			}}},
+		}, nil, locale.NewPrinter(locale.English))
+		s.Empty(diagnosis.Findings)
+		s.Require().Len(diagnosis.Containers, 1)
+		s.Equal("running", diagnosis.Containers[0].State)
+	})
+	s.Run("crash looping OOMKilled container", func() {
+		diagnosis := DiagnosePod(&v1.Pod{
+			Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "app"}}},
+			Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{
+				Name:                 "app",
+				RestartCount:         7,
+				State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			}}},
+		}, nil, locale.NewPrinter(locale.English))
+		s.Require().Len(diagnosis.Findings, 2)
+		s.Equal("CrashLoopBackOff", diagnosis.Findings[0].Reason)
+		s.Equal("container is crash looping (7 restarts), last terminated with exit code 137 (OOMKilled)", diagnosis.Findings[0].Message)
+		s.Equal("OOMKilled", diagnosis.Findings[1].Reason)
+		s.Equal(int32(137), diagnosis.Containers[0].LastTermination.ExitCode)
+	})
+	s.Run("terminated OOMKilled container", func() {
+		diagnosis := DiagnosePod(&v1.Pod{
+			Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "app"}}},
+			Status: v1.PodStatus{Phase: v1.PodFailed, ContainerStatuses: []v1.ContainerStatus{{
+				Name:  "app",
+				State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			}}},
+		}, nil, locale.NewPrinter(locale.English))
+		s.Require().Len(diagnosis.Findings, 1)
+		s.Equal("OOMKilled", diagnosis.Findings[0].Reason)
+		s.Equal(int32(137), *diagnosis.Containers[0].ExitCode)
+	})
+	s.Run("terminated container with a non-zero exit code", func() {
+		diagnosis := DiagnosePod(&v1.Pod{
+			Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "app"}}},
+			Status: v1.PodStatus{Phase: v1.PodFailed, ContainerStatuses: []v1.ContainerStatus{{
+				Name:  "app",
+				State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+			}}},
+		}, nil, locale.NewPrinter(locale.English))
+		s.Require().Len(diagnosis.Findings, 1)
+		s.Equal(Finding{Severity: SeverityCritical, Reason: "Error", Container: "app", Message: "container terminated with exit code 1"}, diagnosis.Findings[0])
+	})
+	s.Run("findings in the locale", func() {
+		diagnosis := DiagnosePod(&v1.Pod{
+			Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "app"}}},
+			Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{
+				Name:                 "app",
+				RestartCount:         7,
+				State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+			}}},
+		}, nil, locale.NewPrinter(locale.Japanese))
+		s.Require().Len(diagnosis.Findings, 1)
+		s.Equal("CrashLoopBackOff", diagnosis.Findings[0].Reason)
+		s.Equal("コンテナがクラッシュループしています (再起動 7 回)、前回は終了コード 1 で終了しました (Error)", diagnosis.Findings[0].Message)
+	})
+	s.Run("unschedulable pod", func() {
+		diagnosis := DiagnosePod(&v1.Pod{
+			Status: v1.PodStatus{Phase: v1.PodPending, Conditions: []v1.PodCondition{{
+				Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes are available",
+			}}},
+		}, nil, locale.NewPrinter(locale.English))
+		s.Require().Len(diagnosis.Findings, 1)
+		s.Equal(Finding{Severity: SeverityCritical, Reason: "Unschedulable", Message: "0/3 nodes are available"}, diagnosis.Findings[0])
+	})
+	s.Run("failing readiness probe", func() {
+		diagnosis := DiagnosePod(&v1.Pod{
+			Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "app", ReadinessProbe: &v1.Probe{
+				ProbeHandler:  v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Path: "/ready", Port: intstr.FromInt32(8080), Scheme: v1.URISchemeHTTP}},
+				PeriodSeconds: 10, FailureThreshold: 3, TimeoutSeconds: 1,
+			}}}},
+			Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{
+				Name: "app", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+			}}},
+		}, []v1.Event{
+			{Type: v1.EventTypeWarning, Reason: "Unhealthy", Message: "Readiness probe failed: HTTP probe failed with statuscode: 503"},
+			{Type: v1.EventTypeWarning, Reason: "Unhealthy", Message: "Readiness probe failed: HTTP probe failed with statuscode: 500"},
+		}, locale.NewPrinter(locale.English))
+		s.Require().Len(diagnosis.Findings, 2)
+		s.Equal("NotReady", diagnosis.Findings[0].Reason)
+		s.Equal("ProbeFailure", diagnosis.Findings[1].Reason)
+		s.Equal("http-get http:8080/ready delay=0s timeout=1s period=10s #failure=3", diagnosis.Containers[0].Probes["readiness"])
+		s.Len(diagnosis.Events, 2)
+	})
+}
+
+func TestDiagnose(t *testing.T) {
+	suite.Run(t, new(DiagnoseTestSuite))
+}
@@ -0,0 +1,70 @@
+package kubernetes
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+)
+
+// DefaultDiscoveryCacheTTL is the default duration the discovery and OpenAPI documents are shared by the clients of the same cluster
+const DefaultDiscoveryCacheTTL = 5 * time.Minute
+
+// sharedDiscoveryCache is the discovery cache of the clients derived for each request (OAuth bearer token)
+var sharedDiscoveryCache = newDiscoveryCache()
+
+// discoveryCache shares the cached discovery client, and the RESTMapper built from it, among the clients of the same
+// API server. The derived clients are short-lived (one per tool call), without sharing them each call would run the
+// whole discovery (and OpenAPI download) again, which takes seconds on clusters with many CRDs.
+// The discovery documents are not user specific, they are readable by every authenticated user (system:discovery).
+type discoveryCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]*discoveryCacheEntry
+	now     func() time.Time
+}
+
+type discoveryCacheEntry struct {
+	discoveryClient discovery.CachedDiscoveryInterface
+	restMapper      meta.ResettableRESTMapper
+	expires         time.Time
+}
+
+func newDiscoveryCache() *discoveryCache {
+	ttl := DefaultDiscoveryCacheTTL
+	// Allow override via environment variable (0 disables the cache)
+	if envTTL := os.Getenv("DISCOVERY_CACHE_TTL_MS"); envTTL != "" {
+		if ms, err := strconv.Atoi(envTTL); err == nil && ms >= 0 {
+			ttl = time.Duration(ms) * time.Millisecond
+			klog.V(2).Infof("Using custom discovery cache TTL: %v", ttl)
+		}
+	}
+	return &discoveryCache{ttl: ttl, entries: make(map[string]*discoveryCacheEntry), now: time.Now}
+}
+
+// get returns the discovery client and RESTMapper cached for the API server, or caches the provided ones
+// if there are none or they expired
+func (c *discoveryCache) get(host string, discoveryClient discovery.CachedDiscoveryInterface, restMapper meta.ResettableRESTMapper) (discovery.CachedDiscoveryInterface, meta.ResettableRESTMapper) {
+	if c.ttl <= 0 {
+		return discoveryClient, restMapper
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if entry, ok := c.entries[host]; ok && now.Before(entry.expires) {
+		return entry.discoveryClient, entry.restMapper
+	}
+	c.entries[host] = &discoveryCacheEntry{discoveryClient: discoveryClient, restMapper: restMapper, expires: now.Add(c.ttl)}
+	return discoveryClient, restMapper
+}
+
+// invalidate drops the cached discovery of the API server (e.g. the cluster state changed)
+func (c *discoveryCache) invalidate(host string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, host)
+}
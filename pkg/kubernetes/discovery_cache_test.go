@@ -0,0 +1,130 @@
+package kubernetes
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/restmapper"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+type DiscoveryCacheTestSuite struct {
+	suite.Suite
+	now   time.Time
+	cache *discoveryCache
+}
+
+func (s *DiscoveryCacheTestSuite) SetupTest() {
+	s.now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.cache = &discoveryCache{ttl: time.Minute, entries: map[string]*discoveryCacheEntry{}, now: func() time.Time { return s.now }}
+}
+
+func newCachedDiscovery() (discovery.CachedDiscoveryInterface, *restmapper.DeferredDiscoveryRESTMapper) {
+	discoveryClient := memory.NewMemCacheClient(&fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}})
+	return discoveryClient, restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
+}
+
+func (s *DiscoveryCacheTestSuite) TestGet() {
+	firstDiscovery, firstMapper := newCachedDiscovery()
+	discoveryClient, restMapper := s.cache.get("https://cluster-a", firstDiscovery, firstMapper)
+	s.Run("caches the first clients", func() {
+		s.Same(firstDiscovery, discoveryClient)
+		s.Same(firstMapper, restMapper)
+	})
+	s.Run("returns the cached clients before the TTL", func() {
+		s.now = s.now.Add(59 * time.Second)
+		otherDiscovery, otherMapper := newCachedDiscovery()
+		discoveryClient, restMapper := s.cache.get("https://cluster-a", otherDiscovery, otherMapper)
+		s.Same(firstDiscovery, discoveryClient)
+		s.Same(firstMapper, restMapper)
+	})
+	s.Run("does not share the clients among API servers", func() {
+		otherDiscovery, otherMapper := newCachedDiscovery()
+		discoveryClient, _ := s.cache.get("https://cluster-b", otherDiscovery, otherMapper)
+		s.Same(otherDiscovery, discoveryClient)
+	})
+	s.Run("replaces the cached clients after the TTL", func() {
+		s.now = s.now.Add(2 * time.Second)
+		otherDiscovery, otherMapper := newCachedDiscovery()
+		discoveryClient, restMapper := s.cache.get("https://cluster-a", otherDiscovery, otherMapper)
+		s.Same(otherDiscovery, discoveryClient)
+		s.Same(otherMapper, restMapper)
+	})
+}
+
+func (s *DiscoveryCacheTestSuite) TestInvalidate() {
+	firstDiscovery, firstMapper := newCachedDiscovery()
+	s.cache.get("https://cluster-a", firstDiscovery, firstMapper)
+	s.cache.invalidate("https://cluster-a")
+	otherDiscovery, otherMapper := newCachedDiscovery()
+	discoveryClient, _ := s.cache.get("https://cluster-a", otherDiscovery, otherMapper)
+	s.Same(otherDiscovery, discoveryClient, "expected the clients to be replaced after the invalidation")
+}
+
+func (s *DiscoveryCacheTestSuite) TestDisabled() {
+	s.cache.ttl = 0
+	firstDiscovery, firstMapper := newCachedDiscovery()
+	s.cache.get("https://cluster-a", firstDiscovery, firstMapper)
+	otherDiscovery, otherMapper := newCachedDiscovery()
+	discoveryClient, _ := s.cache.get("https://cluster-a", otherDiscovery, otherMapper)
+	s.Same(otherDiscovery, discoveryClient, "expected no caching with a zero TTL")
+}
+
+func (s *DiscoveryCacheTestSuite) TestDerived() {
+	kubeconfigPath := filepath.Join(s.T().TempDir(), "config")
+	s.Require().NoError(os.WriteFile(kubeconfigPath, []byte(`
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://discovery-cache.example.com
+  name: test-cluster
+contexts:
+- context:
+    cluster: test-cluster
+    user: test-user
+  name: test-context
+current-context: test-context
+users:
+- name: test-user
+  user:
+    token: test-token
+`), 0644))
+	testManager, err := NewKubeconfigManager(test.Must(config.ReadToml([]byte(`
+		kubeconfig = "`+strings.ReplaceAll(kubeconfigPath, `\`, `\\`)+`"
+	`))), "")
+	s.Require().NoError(err)
+	derived := func(token string) *Kubernetes {
+		k, err := testManager.Derived(context.WithValue(s.T().Context(), OAuthAuthorizationHeader, "Bearer "+token))
+		s.Require().NoError(err)
+		return k
+	}
+	first, second := derived("token-a"), derived("token-b")
+	s.Run("derived clients share the discovery client and RESTMapper", func() {
+		s.NotSame(first, second)
+		s.Same(first.DiscoveryClient(), second.DiscoveryClient())
+		s.Same(first.RESTMapper(), second.RESTMapper())
+	})
+	s.Run("derived clients keep their own credentials", func() {
+		s.Equal("token-a", first.RESTConfig().BearerToken)
+		s.Equal("token-b", second.RESTConfig().BearerToken)
+	})
+	s.Run("invalidating the manager drops the shared discovery", func() {
+		testManager.Invalidate()
+		s.NotSame(first.DiscoveryClient(), derived("token-c").DiscoveryClient())
+	})
+}
+
+func TestDiscoveryCache(t *testing.T) {
+	suite.Run(t, new(DiscoveryCacheTestSuite))
+}
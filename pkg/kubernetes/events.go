@@ -2,9 +2,12 @@ package kubernetes
 
 import (
 	"context"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -23,22 +26,26 @@ func (c *Core) EventsList(ctx context.Context, namespace string) ([]map[string]a
 	if len(unstructuredList.Items) == 0 {
 		return eventMap, nil
 	}
+	events := make([]*v1.Event, 0, len(unstructuredList.Items))
 	for _, item := range unstructuredList.Items {
 		event := &v1.Event{}
 		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, event); err != nil {
 			return eventMap, err
 		}
-		timestamp := event.EventTime.Time
-		if timestamp.IsZero() && event.Series != nil {
-			timestamp = event.Series.LastObservedTime.Time
-		} else if timestamp.IsZero() && event.Count > 1 {
-			timestamp = event.LastTimestamp.Time
-		} else if timestamp.IsZero() {
-			timestamp = event.FirstTimestamp.Time
+		events = append(events, event)
+	}
+	// Sort chronologically, events with the same timestamp are sorted by namespace and name for a stable ordering
+	slices.SortStableFunc(events, func(a, b *v1.Event) int {
+		if c := eventTimestamp(a).Compare(eventTimestamp(b)); c != 0 {
+			return c
 		}
-		eventMap = append(eventMap, map[string]any{
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	for _, event := range events {
+		timestamp := eventTimestamp(event)
+		eventEntry := map[string]any{
 			"Namespace": event.Namespace,
-			"Timestamp": timestamp.String(),
+			"Timestamp": timestamp.UTC().Format(time.RFC3339),
 			"Type":      event.Type,
 			"Reason":    event.Reason,
 			"InvolvedObject": map[string]string{
@@ -47,7 +54,23 @@ func (c *Core) EventsList(ctx context.Context, namespace string) ([]map[string]a
 				"Name":       event.InvolvedObject.Name,
 			},
 			"Message": strings.TrimSpace(event.Message),
-		})
+		}
+		if age := locale.FromContext(ctx).Age(timestamp); age != "" {
+			eventEntry["Age"] = age
+		}
+		eventMap = append(eventMap, eventEntry)
 	}
 	return eventMap, nil
 }
+
+func eventTimestamp(event *v1.Event) time.Time {
+	timestamp := event.EventTime.Time
+	if timestamp.IsZero() && event.Series != nil {
+		timestamp = event.Series.LastObservedTime.Time
+	} else if timestamp.IsZero() && event.Count > 1 {
+		timestamp = event.LastTimestamp.Time
+	} else if timestamp.IsZero() {
+		timestamp = event.FirstTimestamp.Time
+	}
+	return timestamp
+}
@@ -0,0 +1,136 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// eventsSpikeThreshold is the number of occurrences within the window above which an event group is reported as a spike
+	eventsSpikeThreshold = 20
+	// eventsSummaryTopObjects is the maximum number of objects listed for each event group
+	eventsSummaryTopObjects = 5
+)
+
+// EventGroup aggregates the events with the same type, reason, and involved object kind.
+type EventGroup struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+	Kind   string `json:"kind"`
+	// Count is the number of occurrences of the events in the group (taking into account the event series and counts)
+	Count int32 `json:"count"`
+	// Spike is true when the number of occurrences within the window exceeds the spike threshold
+	Spike bool `json:"spike,omitempty"`
+	// PerMinute is the rate of occurrences between the first and last time the events of the group were seen
+	PerMinute string `json:"perMinute"`
+	FirstSeen string `json:"firstSeen"`
+	LastSeen  string `json:"lastSeen"`
+	// Objects is the number of distinct objects involved
+	Objects    int                `json:"objects"`
+	TopObjects []EventGroupObject `json:"topObjects"`
+	// Message is the most recent message, DistinctMessages the number of different messages in the group
+	Message          string `json:"message"`
+	DistinctMessages int    `json:"distinctMessages"`
+}
+
+// EventGroupObject is an object involved in the events of a group and its number of occurrences.
+type EventGroupObject struct {
+	Object string `json:"object"`
+	Count  int32  `json:"count"`
+}
+
+// EventsSummary aggregates the events seen within the provided window in the provided namespace (all namespaces if
+// empty) by type, reason, and involved object kind. Groups are sorted by type (warnings first) and occurrences.
+func (c *Core) EventsSummary(ctx context.Context, namespace string, window time.Duration) ([]EventGroup, error) {
+	events, err := c.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return summarizeEvents(events.Items, time.Now(), window), nil
+}
+
+func summarizeEvents(events []v1.Event, now time.Time, window time.Duration) []EventGroup {
+	type accumulator struct {
+		group     *EventGroup
+		firstSeen time.Time
+		lastSeen  time.Time
+		objects   map[string]int32
+		messages  map[string]bool
+	}
+	since := now.Add(-window)
+	accumulators := map[string]*accumulator{}
+	for _, event := range events {
+		lastSeen := eventLastSeen(event)
+		if lastSeen.Before(since) {
+			continue
+		}
+		key := event.Type + "/" + event.Reason + "/" + event.InvolvedObject.Kind
+		acc, ok := accumulators[key]
+		if !ok {
+			acc = &accumulator{
+				group:     &EventGroup{Type: event.Type, Reason: event.Reason, Kind: event.InvolvedObject.Kind},
+				firstSeen: lastSeen,
+				objects:   map[string]int32{},
+				messages:  map[string]bool{},
+			}
+			accumulators[key] = acc
+		}
+		count := max(event.Count, 1)
+		if event.Series != nil {
+			count = max(event.Series.Count, count)
+		}
+		acc.group.Count += count
+		acc.objects[strings.TrimPrefix(event.InvolvedObject.Namespace+"/"+event.InvolvedObject.Name, "/")] += count
+		message := strings.TrimSpace(event.Message)
+		acc.messages[message] = true
+		firstSeen := event.FirstTimestamp.Time
+		if firstSeen.IsZero() {
+			firstSeen = lastSeen
+		} else if firstSeen.Before(since) {
+			firstSeen = since
+		}
+		if firstSeen.Before(acc.firstSeen) {
+			acc.firstSeen = firstSeen
+		}
+		if !lastSeen.Before(acc.lastSeen) {
+			acc.lastSeen = lastSeen
+			acc.group.Message = message
+		}
+	}
+	groups := make([]EventGroup, 0, len(accumulators))
+	for _, acc := range accumulators {
+		group := acc.group
+		group.FirstSeen = acc.firstSeen.UTC().Format(time.RFC3339)
+		group.LastSeen = acc.lastSeen.UTC().Format(time.RFC3339)
+		// Rate over the time the events of the group were active, a burst is not diluted by a long window
+		group.PerMinute = fmt.Sprintf("%.1f", float64(group.Count)/max(acc.lastSeen.Sub(acc.firstSeen).Minutes(), 1))
+		group.Spike = group.Count >= eventsSpikeThreshold
+		group.Objects = len(acc.objects)
+		group.DistinctMessages = len(acc.messages)
+		for object, count := range acc.objects {
+			group.TopObjects = append(group.TopObjects, EventGroupObject{Object: object, Count: count})
+		}
+		slices.SortFunc(group.TopObjects, func(a, b EventGroupObject) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Object, b.Object))
+		})
+		group.TopObjects = group.TopObjects[:min(len(group.TopObjects), eventsSummaryTopObjects)]
+		groups = append(groups, *group)
+	}
+	slices.SortFunc(groups, func(a, b EventGroup) int {
+		return cmp.Or(
+			// Warnings first
+			cmp.Compare(b.Type, a.Type),
+			cmp.Compare(b.Count, a.Count),
+			cmp.Compare(a.Reason, b.Reason),
+			cmp.Compare(a.Kind, b.Kind),
+		)
+	})
+	return groups
+}
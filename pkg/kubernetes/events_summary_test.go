@@ -0,0 +1,62 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type EventsSummaryTestSuite struct {
+	suite.Suite
+}
+
+func (s *EventsSummaryTestSuite) TestSummarizeEvents() {
+	now := time.Now().Truncate(time.Second)
+	event := func(eventType, reason, pod string, count int32, firstSeen, lastSeen time.Duration) v1.Event {
+		return v1.Event{
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "default", Name: pod},
+			Type:           eventType,
+			Reason:         reason,
+			Message:        reason + " for " + pod,
+			Count:          count,
+			FirstTimestamp: metav1.NewTime(now.Add(-firstSeen)),
+			LastTimestamp:  metav1.NewTime(now.Add(-lastSeen)),
+		}
+	}
+	events := []v1.Event{
+		event("Normal", "Pulled", "web-1", 1, 5*time.Minute, 5*time.Minute),
+		event("Warning", "FailedScheduling", "web-2", 300, 10*time.Minute, time.Minute),
+		event("Warning", "FailedScheduling", "web-3", 200, 10*time.Minute, 0),
+		event("Warning", "BackOff", "web-4", 3, 30*time.Minute, 20*time.Minute),
+		// Outside the window
+		event("Warning", "BackOff", "web-5", 50, 3*time.Hour, 2*time.Hour),
+	}
+	groups := summarizeEvents(events, now, time.Hour)
+	s.Require().Len(groups, 3)
+	s.Run("sorts warnings first by occurrences", func() {
+		s.Equal([]string{"FailedScheduling", "BackOff", "Pulled"}, []string{groups[0].Reason, groups[1].Reason, groups[2].Reason})
+	})
+	s.Run("flags spikes", func() {
+		s.True(groups[0].Spike)
+		s.False(groups[1].Spike)
+		s.Equal(int32(500), groups[0].Count)
+		s.Equal("50.0", groups[0].PerMinute)
+	})
+	s.Run("reports the involved objects and the latest message", func() {
+		s.Equal(2, groups[0].Objects)
+		s.Equal([]EventGroupObject{{Object: "default/web-2", Count: 300}, {Object: "default/web-3", Count: 200}}, groups[0].TopObjects)
+		s.Equal("FailedScheduling for web-3", groups[0].Message)
+		s.Equal(2, groups[0].DistinctMessages)
+	})
+	s.Run("excludes events outside the window", func() {
+		s.Equal(int32(3), groups[1].Count)
+		s.Equal(1, groups[1].Objects)
+	})
+}
+
+func TestEventsSummary(t *testing.T) {
+	suite.Run(t, new(EventsSummaryTestSuite))
+}
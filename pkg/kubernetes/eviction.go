@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PodEviction is the outcome of the eviction of a Pod
+type PodEviction struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// DryRun is true if the eviction was only validated, the Pod was not evicted
+	DryRun bool `json:"dryRun,omitempty"`
+	// DisruptionBudgets are the PodDisruptionBudgets protecting the Pod, as they were before the eviction
+	DisruptionBudgets []DisruptionBudget `json:"disruptionBudgets,omitempty"`
+}
+
+type DisruptionBudget struct {
+	Name               string `json:"name"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+	CurrentHealthy     int32  `json:"currentHealthy"`
+	DesiredHealthy     int32  `json:"desiredHealthy"`
+	ExpectedPods       int32  `json:"expectedPods"`
+}
+
+func (b *DisruptionBudget) String() string {
+	return fmt.Sprintf("%s (%d disruptions allowed, %d healthy Pods of the %d desired)", b.Name, b.DisruptionsAllowed, b.CurrentHealthy, b.DesiredHealthy)
+}
+
+// PodsEvict evicts the Pod with the Eviction API, which refuses the evictions violating a PodDisruptionBudget unlike a
+// delete. With dryRun, the eviction is validated (PodDisruptionBudgets included) without evicting the Pod.
+// The error of a refused eviction names the PodDisruptionBudgets of the Pod.
+func (c *Core) PodsEvict(ctx context.Context, namespace, name string, dryRun bool) (*PodEviction, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	pod, err := c.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ret := &PodEviction{Name: name, Namespace: namespace, DryRun: dryRun}
+	// the budgets are only informative, the API server enforces them whether they can be listed or not
+	if pdbList, _ := c.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{}); pdbList != nil {
+		for _, pdb := range pdbList.Items {
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			ret.DisruptionBudgets = append(ret.DisruptionBudgets, DisruptionBudget{
+				Name:               pdb.Name,
+				DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+				CurrentHealthy:     pdb.Status.CurrentHealthy,
+				DesiredHealthy:     pdb.Status.DesiredHealthy,
+				ExpectedPods:       pdb.Status.ExpectedPods,
+			})
+		}
+	}
+	eviction := &policyv1.Eviction{
+		ObjectMeta:    metav1.ObjectMeta{Name: name, Namespace: namespace},
+		DeleteOptions: &metav1.DeleteOptions{},
+	}
+	if dryRun {
+		eviction.DeleteOptions.DryRun = []string{metav1.DryRunAll}
+	}
+	err = c.CoreV1().Pods(namespace).EvictV1(ctx, eviction)
+	if apierrors.HasStatusCause(err, policyv1.DisruptionBudgetCause) && len(ret.DisruptionBudgets) > 0 {
+		budgets := make([]string, 0, len(ret.DisruptionBudgets))
+		for _, budget := range ret.DisruptionBudgets {
+			budgets = append(budgets, budget.String())
+		}
+		return nil, fmt.Errorf("evicting Pod %s would violate its PodDisruptionBudget %s: %w", name, strings.Join(budgets, ", "), err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
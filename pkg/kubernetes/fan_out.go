@@ -0,0 +1,36 @@
+package kubernetes
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultFanOutConcurrency is the maximum number of concurrent requests of a fan-out, the client rate limiter
+// still applies to the requests as a whole
+const DefaultFanOutConcurrency = 8
+
+// FanOut calls fn for each of the items concurrently, with at most concurrency calls in flight (DefaultFanOutConcurrency
+// if not positive), and returns the results and errors in the order of the items so that the merged output is stable.
+// The failure of an item doesn't cancel the rest, the callers decide whether partial results are acceptable.
+func FanOut[T, R any](ctx context.Context, concurrency int, items []T, fn func(ctx context.Context, item T) (R, error)) ([]R, []error) {
+	if concurrency <= 0 {
+		concurrency = DefaultFanOutConcurrency
+	}
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	var group errgroup.Group
+	group.SetLimit(concurrency)
+	for i, item := range items {
+		group.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				return nil
+			}
+			results[i], errs[i] = fn(ctx, item)
+			return nil
+		})
+	}
+	_ = group.Wait()
+	return results, errs
+}
@@ -0,0 +1,70 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FanOutTestSuite struct {
+	suite.Suite
+}
+
+func (s *FanOutTestSuite) TestResultsInOrder() {
+	items := []int{5, 1, 4, 2, 3}
+	results, errs := FanOut(s.T().Context(), 2, items, func(_ context.Context, item int) (string, error) {
+		time.Sleep(time.Duration(item) * time.Millisecond)
+		if item == 4 {
+			return "", errors.New("boom")
+		}
+		return fmt.Sprintf("item-%d", item), nil
+	})
+	s.Run("returns the results in the order of the items", func() {
+		s.Equal([]string{"item-5", "item-1", "", "item-2", "item-3"}, results)
+	})
+	s.Run("returns the error of the failed item without failing the rest", func() {
+		s.Require().Len(errs, len(items))
+		s.EqualError(errs[2], "boom")
+		s.NoError(errors.Join(errs[0], errs[1], errs[3], errs[4]))
+	})
+}
+
+func (s *FanOutTestSuite) TestConcurrencyLimit() {
+	var inFlight, maxInFlight atomic.Int32
+	_, _ = FanOut(s.T().Context(), 3, make([]int, 20), func(_ context.Context, _ int) (any, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			previous := maxInFlight.Load()
+			if current <= previous || maxInFlight.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil, nil
+	})
+	s.LessOrEqual(maxInFlight.Load(), int32(3))
+	s.Greater(maxInFlight.Load(), int32(1), "expected the items to be processed concurrently")
+}
+
+func (s *FanOutTestSuite) TestCanceledContext() {
+	ctx, cancel := context.WithCancel(s.T().Context())
+	cancel()
+	called := false
+	_, errs := FanOut(ctx, 0, []int{1, 2}, func(_ context.Context, _ int) (any, error) {
+		called = true
+		return nil, nil
+	})
+	s.False(called, "expected the items not to be processed once the context is canceled")
+	s.ErrorIs(errs[0], context.Canceled)
+	s.ErrorIs(errs[1], context.Canceled)
+}
+
+func TestFanOut(t *testing.T) {
+	suite.Run(t, new(FanOutTestSuite))
+}
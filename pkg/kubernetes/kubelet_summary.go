@@ -0,0 +1,186 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	metricsv1beta1api "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+// kubeletSummary is the subset of the kubelet Summary API response providing the resource usage of the node and its pods,
+// the same usage the metrics-server collects from the kubelets.
+// https://github.com/kubernetes/kubelet/blob/master/pkg/apis/stats/v1alpha1/types.go
+type kubeletSummary struct {
+	Node struct {
+		CPU    *kubeletCPUStats    `json:"cpu,omitempty"`
+		Memory *kubeletMemoryStats `json:"memory,omitempty"`
+	} `json:"node"`
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Containers []struct {
+			Name   string              `json:"name"`
+			CPU    *kubeletCPUStats    `json:"cpu,omitempty"`
+			Memory *kubeletMemoryStats `json:"memory,omitempty"`
+		} `json:"containers"`
+	} `json:"pods"`
+}
+
+type kubeletCPUStats struct {
+	Time           metav1.Time `json:"time"`
+	UsageNanoCores *uint64     `json:"usageNanoCores,omitempty"`
+}
+
+type kubeletMemoryStats struct {
+	WorkingSetBytes *uint64 `json:"workingSetBytes,omitempty"`
+}
+
+func (c *Core) kubeletSummary(ctx context.Context, nodeName string) (*kubeletSummary, error) {
+	rawData, err := c.CoreV1().RESTClient().
+		Get().
+		AbsPath("api", "v1", "nodes", nodeName, "proxy", "stats", "summary").
+		DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node %s stats summary: %w", nodeName, err)
+	}
+	summary := &kubeletSummary{}
+	if err = json.Unmarshal(rawData, summary); err != nil {
+		return nil, fmt.Errorf("failed to read node %s stats summary response: %w", nodeName, err)
+	}
+	return summary, nil
+}
+
+// kubeletSummaries queries the Summary API of the nodes concurrently, the results are in the order of the nodes
+func (c *Core) kubeletSummaries(ctx context.Context, nodeNames []string) ([]*kubeletSummary, []error) {
+	return FanOut(ctx, DefaultFanOutConcurrency, nodeNames, c.kubeletSummary)
+}
+
+// nodesTopFromKubeletSummary returns the node metrics from the kubelet Summary API of each node, the nodes not reporting
+// their usage (e.g. NotReady) are skipped unless a single node is requested
+func (c *Core) nodesTopFromKubeletSummary(ctx context.Context, options api.NodesTopOptions) (*metricsv1beta1api.NodeMetricsList, error) {
+	nodes := &v1.NodeList{}
+	if options.Name != "" {
+		node, err := c.CoreV1().Nodes().Get(ctx, options.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get node %s: %w", options.Name, err)
+		}
+		nodes.Items = []v1.Node{*node}
+	} else {
+		var err error
+		if nodes, err = c.CoreV1().Nodes().List(ctx, options.ListOptions); err != nil {
+			return nil, fmt.Errorf("failed to list nodes: %w", err)
+		}
+	}
+	ret := &metricsv1beta1api.NodeMetricsList{}
+	nodeNames := make([]string, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		nodeNames = append(nodeNames, node.Name)
+	}
+	summaries, errs := c.kubeletSummaries(ctx, nodeNames)
+	for i, node := range nodes.Items {
+		summary, err := summaries[i], errs[i]
+		if err != nil && options.Name != "" {
+			return nil, err
+		}
+		if err != nil || !hasUsage(summary.Node.CPU, summary.Node.Memory) {
+			continue
+		}
+		ret.Items = append(ret.Items, metricsv1beta1api.NodeMetrics{
+			ObjectMeta: metav1.ObjectMeta{Name: node.Name, Labels: node.Labels},
+			Timestamp:  summary.Node.CPU.Time,
+			Usage:      usage(summary.Node.CPU, summary.Node.Memory),
+		})
+	}
+	if options.Name != "" && len(ret.Items) == 0 {
+		return nil, fmt.Errorf("the kubelet of node %s does not report its resource usage", options.Name)
+	}
+	return ret, nil
+}
+
+// podsTopFromKubeletSummary returns the pod metrics from the kubelet Summary API of the nodes running the pods, the pods of the
+// nodes not reporting their usage are skipped unless a single pod is requested
+func (c *Core) podsTopFromKubeletSummary(ctx context.Context, namespace string, options api.PodsTopOptions) (*metricsv1beta1api.PodMetricsList, error) {
+	pods := &v1.PodList{}
+	if options.Name != "" {
+		pod, err := c.CoreV1().Pods(namespace).Get(ctx, options.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod %s/%s: %w", namespace, options.Name, err)
+		}
+		pods.Items = []v1.Pod{*pod}
+	} else {
+		var err error
+		if pods, err = c.CoreV1().Pods(namespace).List(ctx, options.ListOptions); err != nil {
+			return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+		}
+	}
+	// The Summary API is queried once per node running the selected pods
+	podsByNode := make(map[string]map[types.NamespacedName]v1.Pod)
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		if podsByNode[pod.Spec.NodeName] == nil {
+			podsByNode[pod.Spec.NodeName] = make(map[types.NamespacedName]v1.Pod)
+		}
+		podsByNode[pod.Spec.NodeName][types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}] = pod
+	}
+	ret := &metricsv1beta1api.PodMetricsList{}
+	nodeNames := slices.Sorted(maps.Keys(podsByNode))
+	summaries, errs := c.kubeletSummaries(ctx, nodeNames)
+	for i, nodeName := range nodeNames {
+		summary, err := summaries[i], errs[i]
+		if err != nil && options.Name != "" {
+			return nil, err
+		}
+		if err != nil {
+			continue
+		}
+		for _, podStats := range summary.Pods {
+			pod, found := podsByNode[nodeName][types.NamespacedName{Namespace: podStats.PodRef.Namespace, Name: podStats.PodRef.Name}]
+			if !found {
+				continue
+			}
+			podMetrics := metricsv1beta1api.PodMetrics{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace, Labels: pod.Labels}}
+			for _, container := range podStats.Containers {
+				if !hasUsage(container.CPU, container.Memory) {
+					continue
+				}
+				podMetrics.Timestamp = container.CPU.Time
+				podMetrics.Containers = append(podMetrics.Containers, metricsv1beta1api.ContainerMetrics{
+					Name:  container.Name,
+					Usage: usage(container.CPU, container.Memory),
+				})
+			}
+			if len(podMetrics.Containers) > 0 {
+				ret.Items = append(ret.Items, podMetrics)
+			}
+		}
+	}
+	if options.Name != "" && len(ret.Items) == 0 {
+		return nil, fmt.Errorf("the kubelet does not report the resource usage of pod %s/%s", namespace, options.Name)
+	}
+	return ret, nil
+}
+
+func hasUsage(cpu *kubeletCPUStats, memory *kubeletMemoryStats) bool {
+	return cpu != nil && cpu.UsageNanoCores != nil && memory != nil && memory.WorkingSetBytes != nil
+}
+
+// usage converts the kubelet stats as the metrics-server does: CPU usage in nanocores and memory working set in bytes
+func usage(cpu *kubeletCPUStats, memory *kubeletMemoryStats) v1.ResourceList {
+	return v1.ResourceList{
+		v1.ResourceCPU:    *resource.NewScaledQuantity(int64(*cpu.UsageNanoCores), resource.Nano),
+		v1.ResourceMemory: *resource.NewQuantity(int64(*memory.WorkingSetBytes), resource.BinarySI),
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/flowcontrol"
 	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 )
 
@@ -59,6 +60,10 @@ func NewKubernetes(config api.BaseConfig, clientCmdConfig clientcmd.ClientConfig
 	if k.restConfig.UserAgent == "" {
 		k.restConfig.UserAgent = rest.DefaultKubernetesUserAgent()
 	}
+	// A single rate limiter for all the clients, the QPS and Burst limit the requests of the client as a whole
+	if k.restConfig.RateLimiter == nil && k.restConfig.QPS > 0 {
+		k.restConfig.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(k.restConfig.QPS, k.restConfig.Burst)
+	}
 	k.restConfig.Wrap(func(original http.RoundTripper) http.RoundTripper {
 		return &AccessControlRoundTripper{
 			delegate:                original,
@@ -66,24 +71,35 @@ func NewKubernetes(config api.BaseConfig, clientCmdConfig clientcmd.ClientConfig
 			restMapperProvider:      func() meta.RESTMapper { return k.restMapper },
 		}
 	})
+	k.restConfig.Wrap(func(original http.RoundTripper) http.RoundTripper {
+		return &LabelScopeRoundTripper{
+			delegate:           original,
+			restMapperProvider: func() meta.RESTMapper { return k.restMapper },
+		}
+	})
 	k.restConfig.Wrap(func(original http.RoundTripper) http.RoundTripper {
 		return &UserAgentRoundTripper{delegate: original}
 	})
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(k.restConfig)
+	// A single HTTP client (and transport) for all the clients, the connections are reused among them
+	httpClient, err := rest.HTTPClientFor(k.restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http client: %w", err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfigAndClient(k.restConfig, httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create discovery client: %w", err)
 	}
 	k.discoveryClient = memory.NewMemCacheClient(discoveryClient)
 	k.restMapper = restmapper.NewDeferredDiscoveryRESTMapper(k.discoveryClient)
-	k.Interface, err = kubernetes.NewForConfig(k.restConfig)
+	k.Interface, err = kubernetes.NewForConfigAndClient(k.restConfig, httpClient)
 	if err != nil {
 		return nil, err
 	}
-	k.dynamicClient, err = dynamic.NewForConfig(k.restConfig)
+	k.dynamicClient, err = dynamic.NewForConfigAndClient(k.restConfig, httpClient)
 	if err != nil {
 		return nil, err
 	}
-	k.metricsV1beta1, err = metricsv1beta1.NewForConfig(k.restConfig)
+	k.metricsV1beta1, err = metricsv1beta1.NewForConfigAndClient(k.restConfig, httpClient)
 	if err != nil {
 		return nil, err
 	}
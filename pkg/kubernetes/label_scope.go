@@ -0,0 +1,171 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type labelScopeContextKey struct{}
+
+// WithLabelScope returns a context whose requests of the namespaced resources are scoped to the objects matching the
+// label selector (see LabelScopeRoundTripper), an empty selector leaves the reads unscoped
+func WithLabelScope(ctx context.Context, labelSelector string) context.Context {
+	if labelSelector == "" {
+		return ctx
+	}
+	selector, err := labels.Parse(labelSelector)
+	if err != nil || selector.Empty() {
+		return ctx
+	}
+	return context.WithValue(ctx, labelScopeContextKey{}, selector)
+}
+
+// labelScopeFor returns the label scope of the context applicable to the resource, nil if the requests of the resource
+// aren't scoped: the cluster-scoped resources (e.g. Nodes, Namespaces) and the Events, which don't carry the labels of
+// the objects they are about, are never scoped
+func labelScopeFor(ctx context.Context, restMapper meta.RESTMapper, gvr schema.GroupVersionResource) labels.Selector {
+	selector, ok := ctx.Value(labelScopeContextKey{}).(labels.Selector)
+	if !ok || restMapper == nil || gvr.Resource == "events" {
+		return nil
+	}
+	gvk, err := restMapper.KindFor(gvr)
+	if err != nil {
+		return nil
+	}
+	if mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil || mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return nil
+	}
+	return selector
+}
+
+// LabelScopeRoundTripper enforces the label scope of the context of the requests: the label selector of the scope is
+// added to the lists, watches and collection deletes, and the requests (reads and writes) of the existing objects (and
+// of their subresources, e.g. the logs of a Pod) not matching it are forbidden. The objects are reported as forbidden
+// rather than not found, so that the writes checking whether an object exists (e.g. the transactions) don't mistake
+// an object out of the scope for a missing one.
+type LabelScopeRoundTripper struct {
+	delegate           http.RoundTripper
+	restMapperProvider func() meta.RESTMapper
+}
+
+func (rt *LabelScopeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	gvr, ok := parseURLToGVR(req.URL.Path)
+	if !ok {
+		return rt.delegate.RoundTrip(req)
+	}
+	selector := labelScopeFor(req.Context(), rt.restMapperProvider(), gvr)
+	if selector == nil {
+		return rt.delegate.RoundTrip(req)
+	}
+	collection, name := splitResourcePath(req.URL.Path)
+	if name == "" {
+		// the objects created in a collection don't exist yet
+		if req.Method != http.MethodGet && req.Method != http.MethodDelete {
+			return rt.delegate.RoundTrip(req)
+		}
+		scoped := req.Clone(req.Context())
+		query := scoped.URL.Query()
+		query.Set("labelSelector", strings.Trim(query.Get("labelSelector")+","+selector.String(), ","))
+		scoped.URL.RawQuery = query.Encode()
+		return rt.delegate.RoundTrip(scoped)
+	}
+	inScope, err := rt.matches(req, collection, name, selector)
+	if err != nil {
+		return nil, err
+	}
+	if inScope {
+		return rt.delegate.RoundTrip(req)
+	}
+	// the missing objects are reported as such (or created by the applies and updates)
+	exists, err := rt.matches(req, collection, name, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return rt.delegate.RoundTrip(req)
+	}
+	return forbiddenResponse(req, gvr, name, selector)
+}
+
+// matches lists the object with the label selector to check it matches it
+func (rt *LabelScopeRoundTripper) matches(req *http.Request, collection, name string, selector labels.Selector) (bool, error) {
+	list := req.Clone(req.Context())
+	list.Method = http.MethodGet
+	list.Body, list.GetBody, list.ContentLength = nil, nil, 0
+	list.URL.Path = collection
+	list.URL.RawPath = ""
+	query := url.Values{"fieldSelector": {"metadata.name=" + name}}
+	if !selector.Empty() {
+		query.Set("labelSelector", selector.String())
+	}
+	list.URL.RawQuery = query.Encode()
+	list.Header.Del("Content-Type")
+	list.Header.Set("Accept", "application/json")
+	resp, err := rt.delegate.RoundTrip(list)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("failed to check the label scope %s of %s: %s", selector.String(), name, resp.Status)
+	}
+	items := struct {
+		Items []json.RawMessage `json:"items"`
+	}{}
+	if err = json.Unmarshal(body, &items); err != nil {
+		return false, fmt.Errorf("failed to check the label scope %s of %s: %w", selector.String(), name, err)
+	}
+	return len(items.Items) > 0, nil
+}
+
+// splitResourcePath returns the path of the collection of an API resource path, and the name of the object if the
+// path is the one of an object or one of its subresources
+func splitResourcePath(path string) (string, string) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	// /api/v1/[namespaces/{namespace}/]{resource}[/{name}[/{subresource}]]
+	resource := 2
+	if parts[0] == "apis" {
+		resource = 3
+	}
+	if len(parts) > resource+2 && parts[resource] == "namespaces" {
+		resource += 2
+	}
+	if len(parts) <= resource+1 {
+		return "/" + strings.Join(parts, "/"), ""
+	}
+	return "/" + strings.Join(parts[:resource+1], "/"), parts[resource+1]
+}
+
+func forbiddenResponse(req *http.Request, gvr schema.GroupVersionResource, name string, selector labels.Selector) (*http.Response, error) {
+	status := apierrors.NewForbidden(gvr.GroupResource(), name, fmt.Errorf("the object is out of the label scope %s of the session", selector.String())).Status()
+	status.APIVersion, status.Kind = "v1", "Status"
+	body, err := json.Marshal(status)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", http.StatusForbidden, http.StatusText(http.StatusForbidden)),
+		StatusCode:    http.StatusForbidden,
+		Proto:         req.Proto,
+		ProtoMajor:    req.ProtoMajor,
+		ProtoMinor:    req.ProtoMinor,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(string(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
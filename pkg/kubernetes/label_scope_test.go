@@ -0,0 +1,192 @@
+package kubernetes
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+)
+
+type LabelScopeRoundTripperTestSuite struct {
+	suite.Suite
+	mockServer *test.MockServer
+	restMapper *restmapper.DeferredDiscoveryRESTMapper
+	requests   []*http.Request
+	rt         *LabelScopeRoundTripper
+}
+
+func (s *LabelScopeRoundTripperTestSuite) SetupTest() {
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	clientSet, err := kubernetes.NewForConfig(s.mockServer.Config())
+	s.Require().NoError(err, "Expected no error creating clientset")
+	s.restMapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientSet.Discovery()))
+	s.requests = nil
+	called := false
+	s.rt = &LabelScopeRoundTripper{
+		delegate: &mockRoundTripper{
+			called: &called,
+			onRequest: func(w http.ResponseWriter, r *http.Request) {
+				s.requests = append(s.requests, r)
+				w.Header().Set("Content-Type", "application/json")
+				if (r.URL.Query().Get("labelSelector") == "team=payments" && r.URL.Query().Get("fieldSelector") == "metadata.name=other-team") ||
+					r.URL.Query().Get("fieldSelector") == "metadata.name=missing" {
+					_, _ = w.Write([]byte(`{"kind":"PodList","apiVersion":"v1","items":[]}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"kind":"PodList","apiVersion":"v1","items":[{"metadata":{"name":"a-pod"}}]}`))
+			},
+		},
+		restMapperProvider: func() meta.RESTMapper { return s.restMapper },
+	}
+}
+
+func (s *LabelScopeRoundTripperTestSuite) TearDownTest() {
+	s.mockServer.Close()
+}
+
+func (s *LabelScopeRoundTripperTestSuite) scopedRequest(method, target string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	return req.WithContext(WithLabelScope(context.Background(), "team=payments"))
+}
+
+func (s *LabelScopeRoundTripperTestSuite) TestList() {
+	s.Run("adds the label selector of the scope", func() {
+		resp, err := s.rt.RoundTrip(s.scopedRequest("GET", "/api/v1/namespaces/default/pods"))
+		s.Require().NoError(err)
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Require().Len(s.requests, 1)
+		s.Equal("team=payments", s.requests[0].URL.Query().Get("labelSelector"))
+	})
+	s.Run("merges the label selector of the scope with the one of the request", func() {
+		s.requests = nil
+		_, err := s.rt.RoundTrip(s.scopedRequest("GET", "/apis/apps/v1/deployments?labelSelector=app%3Dweb"))
+		s.Require().NoError(err)
+		s.Require().Len(s.requests, 1)
+		s.Equal("app=web,team=payments", s.requests[0].URL.Query().Get("labelSelector"))
+	})
+}
+
+func (s *LabelScopeRoundTripperTestSuite) TestGet() {
+	s.Run("returns the object in the scope", func() {
+		resp, err := s.rt.RoundTrip(s.scopedRequest("GET", "/api/v1/namespaces/default/pods/a-pod"))
+		s.Require().NoError(err)
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Require().Len(s.requests, 2)
+		s.Equal("/api/v1/namespaces/default/pods", s.requests[0].URL.Path)
+		s.Equal("metadata.name=a-pod", s.requests[0].URL.Query().Get("fieldSelector"))
+		s.Equal("team=payments", s.requests[0].URL.Query().Get("labelSelector"))
+		s.Equal("/api/v1/namespaces/default/pods/a-pod", s.requests[1].URL.Path)
+	})
+	s.Run("forbids the object out of the scope", func() {
+		s.requests = nil
+		resp, err := s.rt.RoundTrip(s.scopedRequest("GET", "/api/v1/namespaces/default/pods/other-team"))
+		s.Require().NoError(err)
+		s.Equal(http.StatusForbidden, resp.StatusCode)
+		s.Len(s.requests, 2, "Expected the object not to be requested")
+		s.Equal("", s.requests[1].URL.Query().Get("labelSelector"))
+		body, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		s.Contains(string(body), `pods \"other-team\" is forbidden: the object is out of the label scope team=payments of the session`)
+	})
+	s.Run("forbids the subresources of the object out of the scope", func() {
+		s.requests = nil
+		resp, err := s.rt.RoundTrip(s.scopedRequest("GET", "/api/v1/namespaces/default/pods/other-team/log"))
+		s.Require().NoError(err)
+		s.Equal(http.StatusForbidden, resp.StatusCode)
+		s.Len(s.requests, 2)
+	})
+	s.Run("passes the requests of the missing objects through", func() {
+		s.requests = nil
+		resp, err := s.rt.RoundTrip(s.scopedRequest("GET", "/api/v1/namespaces/default/pods/missing"))
+		s.Require().NoError(err)
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Require().Len(s.requests, 3)
+		s.Equal("/api/v1/namespaces/default/pods/missing", s.requests[2].URL.Path)
+	})
+}
+
+func (s *LabelScopeRoundTripperTestSuite) TestWrite() {
+	for _, method := range []string{"PUT", "PATCH", "DELETE"} {
+		s.Run(method+" forbids the object out of the scope", func() {
+			s.requests = nil
+			resp, err := s.rt.RoundTrip(s.scopedRequest(method, "/api/v1/namespaces/default/pods/other-team"))
+			s.Require().NoError(err)
+			s.Equal(http.StatusForbidden, resp.StatusCode)
+			s.Require().Len(s.requests, 2, "Expected the object not to be written")
+			s.Equal("GET", s.requests[1].Method)
+		})
+		s.Run(method+" writes the object in the scope", func() {
+			s.requests = nil
+			resp, err := s.rt.RoundTrip(s.scopedRequest(method, "/api/v1/namespaces/default/pods/a-pod"))
+			s.Require().NoError(err)
+			s.Equal(http.StatusOK, resp.StatusCode)
+			s.Require().Len(s.requests, 2)
+			s.Equal(method, s.requests[1].Method)
+		})
+	}
+	s.Run("adds the label selector of the scope to the collection deletes", func() {
+		s.requests = nil
+		_, err := s.rt.RoundTrip(s.scopedRequest("DELETE", "/api/v1/namespaces/default/pods"))
+		s.Require().NoError(err)
+		s.Require().Len(s.requests, 1)
+		s.Equal("team=payments", s.requests[0].URL.Query().Get("labelSelector"))
+	})
+	s.Run("passes the creates through", func() {
+		s.requests = nil
+		_, err := s.rt.RoundTrip(s.scopedRequest("POST", "/api/v1/namespaces/default/pods"))
+		s.Require().NoError(err)
+		s.Require().Len(s.requests, 1)
+		s.Equal("", s.requests[0].URL.Query().Get("labelSelector"))
+	})
+}
+
+func (s *LabelScopeRoundTripperTestSuite) TestUnscoped() {
+	cases := map[string]*http.Request{
+		"requests without scope":      httptest.NewRequest("GET", "/api/v1/namespaces/default/pods/other-team", nil),
+		"events":                      s.scopedRequest("GET", "/api/v1/namespaces/default/events"),
+		"cluster-scoped resources":    s.scopedRequest("GET", "/api/v1/nodes"),
+		"non API resource endpoints":  s.scopedRequest("GET", "/healthz"),
+		"named cluster-scoped object": s.scopedRequest("GET", "/api/v1/namespaces/other-team"),
+	}
+	for name, req := range cases {
+		s.Run(name+" are passed through", func() {
+			s.requests = nil
+			resp, err := s.rt.RoundTrip(req)
+			s.Require().NoError(err)
+			s.Equal(http.StatusOK, resp.StatusCode)
+			s.Require().Len(s.requests, 1)
+			s.Equal(req.URL.String(), s.requests[0].URL.String())
+		})
+	}
+}
+
+func (s *LabelScopeRoundTripperTestSuite) TestSplitResourcePath() {
+	cases := []struct{ path, collection, name string }{
+		{"/api/v1/pods", "/api/v1/pods", ""},
+		{"/api/v1/namespaces/default/pods", "/api/v1/namespaces/default/pods", ""},
+		{"/api/v1/namespaces/default/pods/a-pod", "/api/v1/namespaces/default/pods", "a-pod"},
+		{"/api/v1/namespaces/default/pods/a-pod/log", "/api/v1/namespaces/default/pods", "a-pod"},
+		{"/apis/apps/v1/namespaces/default/deployments/web/scale", "/apis/apps/v1/namespaces/default/deployments", "web"},
+		{"/api/v1/namespaces/default", "/api/v1/namespaces", "default"},
+	}
+	for _, c := range cases {
+		s.Run(c.path, func() {
+			collection, name := splitResourcePath(c.path)
+			s.Equal(c.collection, collection)
+			s.Equal(c.name, name)
+		})
+	}
+}
+
+func TestLabelScopeRoundTripper(t *testing.T) {
+	suite.Run(t, new(LabelScopeRoundTripperTestSuite))
+}
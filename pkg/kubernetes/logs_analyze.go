@@ -0,0 +1,204 @@
+package kubernetes
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	LogLevelError   = "error"
+	LogLevelWarning = "warning"
+	LogLevelInfo    = "info"
+
+	// logsAnalyzeMaxPods is the maximum number of Pods whose logs are fetched by LogsAnalyze
+	logsAnalyzeMaxPods = 50
+	// logsSimilarityThreshold is the minimum fraction of equal tokens for a line to be clustered with a pattern
+	logsSimilarityThreshold = 0.5
+	// logsPatternMaxPods is the maximum number of Pods listed for each pattern
+	logsPatternMaxPods = 5
+	// logsWildcard replaces the variable parts of the log lines in the patterns
+	logsWildcard = "<*>"
+)
+
+var (
+	// logsVariable matches the parts of a log line that are likely variable (UUIDs, hex values, hashes, numbers with units, IPs)
+	logsVariable = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|\b0x[0-9a-fA-F]+\b|\b[0-9a-f]{12,}\b|\b\d+(?:\.\d+)*(?:[a-zA-Zµ]+)?\b`)
+	logsError    = regexp.MustCompile(`(?i)\b(error|err|fatal|panic|exception|failed|failure)\b`)
+	logsWarning  = regexp.MustCompile(`(?i)\b(warn|warning)\b`)
+)
+
+// LogsAnalysis is the result of the clustering of the log lines of a set of Pods.
+type LogsAnalysis struct {
+	// Pods is the number of Pods whose logs were analyzed, MatchingPods the number of Pods matching the selector
+	Pods         int          `json:"pods"`
+	MatchingPods int          `json:"matchingPods"`
+	Containers   int          `json:"containers"`
+	Lines        int          `json:"lines"`
+	Patterns     []LogPattern `json:"patterns"`
+}
+
+// LogPattern is a cluster of similar log lines, the variable parts of the lines are replaced with <*>.
+type LogPattern struct {
+	Pattern   string   `json:"pattern"`
+	Level     string   `json:"level"`
+	Count     int      `json:"count"`
+	FirstSeen string   `json:"firstSeen,omitempty"`
+	LastSeen  string   `json:"lastSeen,omitempty"`
+	Pods      []string `json:"pods"`
+	Example   string   `json:"example"`
+	tokens    []string
+	firstSeen time.Time
+	lastSeen  time.Time
+	pods      map[string]bool
+}
+
+// containerLogs are the logs (with timestamps) of a container of a Pod
+type containerLogs struct {
+	pod, container, logs string
+}
+
+// LogsAnalyze fetches the logs of the Pods matching the provided label selector and clusters the similar lines into
+// patterns (Drain-style templating), sorted by level (errors first) and number of occurrences.
+func (c *Core) LogsAnalyze(ctx context.Context, namespace, labelSelector, container string, tail int64, since time.Duration) (*LogsAnalysis, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	pods, err := c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+	var logs []containerLogs
+	analyzed := pods.Items[:min(len(pods.Items), logsAnalyzeMaxPods)]
+	for _, pod := range analyzed {
+		for _, podContainer := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+			if container != "" && podContainer.Name != container {
+				continue
+			}
+			logOptions := &v1.PodLogOptions{Container: podContainer.Name, Timestamps: true, TailLines: ptr.To(tail)}
+			if since > 0 {
+				logOptions.SinceSeconds = ptr.To(int64(since.Seconds()))
+			}
+			// Best-effort, the containers might not have started yet
+			raw, err := c.CoreV1().Pods(namespace).GetLogs(pod.Name, logOptions).Do(ctx).Raw()
+			if err != nil {
+				continue
+			}
+			logs = append(logs, containerLogs{pod: pod.Name, container: podContainer.Name, logs: string(raw)})
+		}
+	}
+	analysis := analyzeLogs(logs)
+	analysis.Pods, analysis.MatchingPods = len(analyzed), len(pods.Items)
+	return analysis, nil
+}
+
+func analyzeLogs(logs []containerLogs) *LogsAnalysis {
+	analysis := &LogsAnalysis{Containers: len(logs)}
+	// Patterns by number of tokens, only lines with the same number of tokens can be clustered together
+	patterns := map[int][]*LogPattern{}
+	for _, containerLogs := range logs {
+		scanner := bufio.NewScanner(strings.NewReader(containerLogs.logs))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			var timestamp time.Time
+			if prefix, rest, found := strings.Cut(line, " "); found {
+				if parsed, err := time.Parse(time.RFC3339Nano, prefix); err == nil {
+					timestamp, line = parsed, rest
+				}
+			}
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			analysis.Lines++
+			tokens := strings.Fields(logsVariable.ReplaceAllString(line, logsWildcard))
+			pattern := closestLogPattern(patterns[len(tokens)], tokens)
+			if pattern == nil {
+				pattern = &LogPattern{tokens: tokens, Level: LogLevelInfo, Example: line, pods: map[string]bool{}}
+				patterns[len(tokens)] = append(patterns[len(tokens)], pattern)
+			} else {
+				for i, token := range tokens {
+					if pattern.tokens[i] != token {
+						pattern.tokens[i] = logsWildcard
+					}
+				}
+			}
+			pattern.Count++
+			pattern.pods[containerLogs.pod] = true
+			if level := logLevel(line); logLevelRank(level) < logLevelRank(pattern.Level) {
+				pattern.Level, pattern.Example = level, line
+			}
+			if !timestamp.IsZero() {
+				if pattern.firstSeen.IsZero() || timestamp.Before(pattern.firstSeen) {
+					pattern.firstSeen = timestamp
+				}
+				if timestamp.After(pattern.lastSeen) {
+					pattern.lastSeen = timestamp
+				}
+			}
+		}
+	}
+	for _, sameLength := range patterns {
+		for _, pattern := range sameLength {
+			pattern.Pattern = strings.Join(pattern.tokens, " ")
+			if !pattern.firstSeen.IsZero() {
+				pattern.FirstSeen = pattern.firstSeen.UTC().Format(time.RFC3339)
+				pattern.LastSeen = pattern.lastSeen.UTC().Format(time.RFC3339)
+			}
+			for pod := range pattern.pods {
+				pattern.Pods = append(pattern.Pods, pod)
+			}
+			slices.Sort(pattern.Pods)
+			pattern.Pods = pattern.Pods[:min(len(pattern.Pods), logsPatternMaxPods)]
+			analysis.Patterns = append(analysis.Patterns, *pattern)
+		}
+	}
+	slices.SortFunc(analysis.Patterns, func(a, b LogPattern) int {
+		return cmp.Or(
+			cmp.Compare(logLevelRank(a.Level), logLevelRank(b.Level)),
+			cmp.Compare(b.Count, a.Count),
+			cmp.Compare(a.Pattern, b.Pattern),
+		)
+	})
+	return analysis
+}
+
+// closestLogPattern returns the pattern most similar to the tokens, or nil if none is similar enough
+func closestLogPattern(patterns []*LogPattern, tokens []string) *LogPattern {
+	var closest *LogPattern
+	closestSimilarity := 0.0
+	for _, pattern := range patterns {
+		equal := 0
+		for i, token := range tokens {
+			if pattern.tokens[i] == token {
+				equal++
+			}
+		}
+		similarity := float64(equal) / float64(max(len(tokens), 1))
+		if similarity >= logsSimilarityThreshold && similarity > closestSimilarity {
+			closest, closestSimilarity = pattern, similarity
+		}
+	}
+	return closest
+}
+
+func logLevel(line string) string {
+	switch {
+	case logsError.MatchString(line):
+		return LogLevelError
+	case logsWarning.MatchString(line):
+		return LogLevelWarning
+	}
+	return LogLevelInfo
+}
+
+func logLevelRank(level string) int {
+	return slices.Index([]string{LogLevelError, LogLevelWarning, LogLevelInfo}, level)
+}
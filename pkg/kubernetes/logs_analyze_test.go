@@ -0,0 +1,59 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LogsAnalyzeTestSuite struct {
+	suite.Suite
+}
+
+func (s *LogsAnalyzeTestSuite) TestAnalyzeLogs() {
+	analysis := analyzeLogs([]containerLogs{
+		{pod: "api-1", container: "api", logs: "" +
+			"2025-01-01T10:00:00.123456789Z GET /users/42 200 12ms\n" +
+			"2025-01-01T10:00:01.000000000Z GET /users/7 200 3ms\n" +
+			"2025-01-01T10:00:02.000000000Z ERROR connection to 10.0.0.12:5432 refused after 3 retries\n" +
+			"2025-01-01T10:00:03.000000000Z GET /orders/1001 404 1ms\n" +
+			"\n"},
+		{pod: "api-2", container: "api", logs: "" +
+			"2025-01-01T09:59:00.000000000Z ERROR connection to 10.0.0.13:5432 refused after 5 retries\n" +
+			"2025-01-01T10:05:00.000000000Z WARN slow request id=1b4e28ba-2fa1-11d2-883f-0016d3cca427 took 2.5s\n" +
+			"2025-01-01T10:06:00.000000000Z ERROR connection to 10.0.0.14:5432 refused after 1 retries\n"},
+		{pod: "api-3", container: "sidecar", logs: "starting sidecar version 1.2.3\n"},
+	})
+	s.Run("counts the analyzed lines", func() {
+		s.Equal(3, analysis.Containers)
+		s.Equal(8, analysis.Lines)
+	})
+	s.Require().Len(analysis.Patterns, 4)
+	s.Run("clusters similar lines into patterns with errors first", func() {
+		s.Equal("ERROR connection to <*>:<*> refused after <*> retries", analysis.Patterns[0].Pattern)
+		s.Equal(LogLevelError, analysis.Patterns[0].Level)
+		s.Equal(3, analysis.Patterns[0].Count)
+		s.Equal("2025-01-01T09:59:00Z", analysis.Patterns[0].FirstSeen)
+		s.Equal("2025-01-01T10:06:00Z", analysis.Patterns[0].LastSeen)
+		s.Equal([]string{"api-1", "api-2"}, analysis.Patterns[0].Pods)
+		s.Equal("ERROR connection to 10.0.0.12:5432 refused after 3 retries", analysis.Patterns[0].Example)
+	})
+	s.Run("masks UUIDs and durations", func() {
+		s.Equal("WARN slow request id=<*> took <*>", analysis.Patterns[1].Pattern)
+		s.Equal(LogLevelWarning, analysis.Patterns[1].Level)
+	})
+	s.Run("replaces the differing tokens with wildcards", func() {
+		s.Equal("GET <*> <*> <*>", analysis.Patterns[2].Pattern)
+		s.Equal(3, analysis.Patterns[2].Count)
+		s.Equal(LogLevelInfo, analysis.Patterns[2].Level)
+	})
+	s.Run("handles lines without timestamps", func() {
+		s.Equal("starting sidecar version <*>", analysis.Patterns[3].Pattern)
+		s.Empty(analysis.Patterns[3].FirstSeen)
+		s.Equal([]string{"api-3"}, analysis.Patterns[3].Pods)
+	})
+}
+
+func TestLogsAnalyze(t *testing.T) {
+	suite.Run(t, new(LogsAnalyzeTestSuite))
+}
@@ -1,6 +1,7 @@
 package kubernetes
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -15,8 +16,16 @@ import (
 	"k8s.io/klog/v2"
 )
 
+const (
+	// DefaultQPS is the maximum sustained rate of requests per second of the clients if not configured
+	DefaultQPS = 10
+	// DefaultBurst is the maximum number of requests above the QPS rate of the clients if not configured
+	DefaultBurst = 20
+)
+
 type Manager struct {
 	kubernetes *Kubernetes
+	derived    *derivedClients
 
 	config api.BaseConfig
 }
@@ -95,11 +104,13 @@ func NewManager(config api.BaseConfig, restConfig *rest.Config, clientCmdConfig
 		return nil, errors.New("clientCmdConfig cannot be nil")
 	}
 
+	applyClientConfig(restConfig, config.GetKubernetesClientConfig())
 	// Apply QPS and Burst from environment variables if set (primarily for testing)
 	applyRateLimitFromEnv(restConfig)
 
 	k8s := &Manager{
-		config: config,
+		config:  config,
+		derived: newDerivedClients(),
 	}
 	var err error
 	// TODO: Won't work because not all client-go clients use the shared context (e.g. discovery client uses context.TODO())
@@ -145,20 +156,23 @@ func (m *Manager) Derived(ctx context.Context) (*Kubernetes, error) {
 		Timeout:     m.kubernetes.RESTConfig().Timeout,
 		Impersonate: rest.ImpersonationConfig{},
 	}
-	clientCmdApiConfig, err := m.kubernetes.clientCmdConfig.RawConfig()
-	if err != nil {
-		if m.config.IsRequireOAuth() {
-			klog.Errorf("failed to get kubeconfig: %v", err)
+	derived, err := m.derived.get(derivedClientKey(authorization, userAgent), func() (*Kubernetes, error) {
+		clientCmdApiConfig, err := m.kubernetes.clientCmdConfig.RawConfig()
+		if err != nil {
 			return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
 		}
-		return m.kubernetes, nil
-	}
-	clientCmdApiConfig.AuthInfos = make(map[string]*clientcmdapi.AuthInfo)
-	derived, err := NewKubernetes(m.config, clientcmd.NewDefaultClientConfig(clientCmdApiConfig, nil), derivedCfg)
+		clientCmdApiConfig.AuthInfos = make(map[string]*clientcmdapi.AuthInfo)
+		derived, err := NewKubernetes(m.config, clientcmd.NewDefaultClientConfig(clientCmdApiConfig, nil), derivedCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create derived client: %w", err)
+		}
+		derived.discoveryClient, derived.restMapper = sharedDiscoveryCache.get(derivedCfg.Host, derived.discoveryClient, derived.restMapper)
+		return derived, nil
+	})
 	if err != nil {
 		if m.config.IsRequireOAuth() {
-			klog.Errorf("failed to create derived client: %v", err)
-			return nil, fmt.Errorf("failed to create derived client: %w", err)
+			klog.Errorf("%v", err)
+			return nil, err
 		}
 		return m.kubernetes, nil
 	}
@@ -168,6 +182,17 @@ func (m *Manager) Derived(ctx context.Context) (*Kubernetes, error) {
 // Invalidate invalidates the cached discovery information.
 func (m *Manager) Invalidate() {
 	m.kubernetes.DiscoveryClient().Invalidate()
+	sharedDiscoveryCache.invalidate(m.kubernetes.RESTConfig().Host)
+	m.derived.clear()
+}
+
+// applyClientConfig applies the configured rate limits (or the conservative defaults) and user agent
+func applyClientConfig(cfg *rest.Config, clientConfig api.KubernetesClientConfig) {
+	cfg.QPS = cmp.Or(clientConfig.QPS, cfg.QPS, DefaultQPS)
+	cfg.Burst = cmp.Or(clientConfig.Burst, cfg.Burst, DefaultBurst)
+	if clientConfig.UserAgent != "" {
+		cfg.UserAgent = clientConfig.UserAgent
+	}
 }
 
 // applyRateLimitFromEnv applies QPS and Burst rate limits from environment variables if set.
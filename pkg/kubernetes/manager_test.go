@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/containers/kubernetes-mcp-server/pkg/config"
 	"github.com/stretchr/testify/suite"
 	"k8s.io/client-go/rest"
@@ -228,6 +229,28 @@ func (s *ManagerTestSuite) TestNewManager() {
 	})
 }
 
+func (s *ManagerTestSuite) TestApplyClientConfig() {
+	s.Run("applies the conservative defaults", func() {
+		cfg := &rest.Config{}
+		applyClientConfig(cfg, api.KubernetesClientConfig{})
+		s.Equal(float32(DefaultQPS), cfg.QPS)
+		s.Equal(DefaultBurst, cfg.Burst)
+	})
+	s.Run("keeps the rate limits of the kubeconfig", func() {
+		cfg := &rest.Config{QPS: 50, Burst: 100}
+		applyClientConfig(cfg, api.KubernetesClientConfig{})
+		s.Equal(float32(50), cfg.QPS)
+		s.Equal(100, cfg.Burst)
+	})
+	s.Run("applies the configured rate limits and user agent", func() {
+		cfg := &rest.Config{QPS: 50, Burst: 100, UserAgent: "kubectl"}
+		applyClientConfig(cfg, api.KubernetesClientConfig{QPS: 5, Burst: 8, UserAgent: "mcp-agent"})
+		s.Equal(float32(5), cfg.QPS)
+		s.Equal(8, cfg.Burst)
+		s.Equal("mcp-agent", cfg.UserAgent)
+	})
+}
+
 func TestManager(t *testing.T) {
 	suite.Run(t, new(ManagerTestSuite))
 }
@@ -0,0 +1,136 @@
+package kubernetes
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NamespaceDiff is the object-by-object comparison of two namespaces (of the same or of two clusters)
+type NamespaceDiff struct {
+	Namespace      string `json:"namespace"`
+	OtherNamespace string `json:"otherNamespace"`
+	// Identical is the number of objects identical in both namespaces
+	Identical int `json:"identical"`
+	// OnlyInNamespace are the objects (Kind/Name) missing from the other namespace
+	OnlyInNamespace []string `json:"onlyInNamespace,omitempty"`
+	// OnlyInOtherNamespace are the objects (Kind/Name) missing from the namespace
+	OnlyInOtherNamespace []string `json:"onlyInOtherNamespace,omitempty"`
+	// Differences are the objects of both namespaces whose fields differ
+	Differences []ObjectDiff `json:"differences,omitempty"`
+	// Skipped are the resources that could not be compared in either namespace and the reason
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// ObjectDiff are the differing fields of an object present in both namespaces
+type ObjectDiff struct {
+	Object string      `json:"object"`
+	Fields []FieldDiff `json:"fields"`
+}
+
+// FieldDiff is a field whose value differs between the namespaces, the value is omitted if the field is not set
+type FieldDiff struct {
+	Path       string `json:"path"`
+	Value      any    `json:"value,omitempty"`
+	OtherValue any    `json:"otherValue,omitempty"`
+}
+
+// DiffNamespaces compares the exported objects of two namespaces (see NamespaceExport), the objects are matched by
+// group, kind, and name, and their fields (but the name) are compared.
+func DiffNamespaces(export, otherExport *NamespaceExport) *NamespaceDiff {
+	diff := &NamespaceDiff{Namespace: export.Namespace, OtherNamespace: otherExport.Namespace}
+	for _, skipped := range export.Skipped {
+		diff.Skipped = append(diff.Skipped, export.Namespace+": "+skipped)
+	}
+	for _, skipped := range otherExport.Skipped {
+		diff.Skipped = append(diff.Skipped, otherExport.Namespace+": "+skipped)
+	}
+	others := make(map[string]*unstructured.Unstructured, len(otherExport.Objects))
+	for i := range otherExport.Objects {
+		others[diffKey(&otherExport.Objects[i])] = &otherExport.Objects[i]
+	}
+	for i := range export.Objects {
+		obj := &export.Objects[i]
+		other, ok := others[diffKey(obj)]
+		if !ok {
+			diff.OnlyInNamespace = append(diff.OnlyInNamespace, obj.GetKind()+"/"+obj.GetName())
+			continue
+		}
+		delete(others, diffKey(obj))
+		var fields []FieldDiff
+		diffFields("", comparedFields(obj), comparedFields(other), &fields)
+		if len(fields) == 0 {
+			diff.Identical++
+			continue
+		}
+		slices.SortFunc(fields, func(a, b FieldDiff) int { return cmp.Compare(a.Path, b.Path) })
+		diff.Differences = append(diff.Differences, ObjectDiff{Object: obj.GetKind() + "/" + obj.GetName(), Fields: fields})
+	}
+	for i := range otherExport.Objects {
+		if obj := &otherExport.Objects[i]; others[diffKey(obj)] != nil {
+			diff.OnlyInOtherNamespace = append(diff.OnlyInOtherNamespace, obj.GetKind()+"/"+obj.GetName())
+		}
+	}
+	return diff
+}
+
+// diffKey identifies the object in both namespaces, its version may differ (e.g. between two clusters)
+func diffKey(obj *unstructured.Unstructured) string {
+	return schema.FromAPIVersionAndKind(obj.GetAPIVersion(), obj.GetKind()).GroupKind().String() + "/" + obj.GetName()
+}
+
+// comparedFields returns the fields of the object compared between the namespaces
+func comparedFields(obj *unstructured.Unstructured) map[string]any {
+	fields := obj.DeepCopy().Object
+	delete(fields, "apiVersion")
+	unstructured.RemoveNestedField(fields, "metadata", "name")
+	if metadata, ok := fields["metadata"].(map[string]any); ok && len(metadata) == 0 {
+		delete(fields, "metadata")
+	}
+	return fields
+}
+
+// diffFields appends the paths of the fields whose values differ, the nested maps and the lists of the same length
+// are compared field by field
+func diffFields(path string, value, otherValue any, fields *[]FieldDiff) {
+	switch v := value.(type) {
+	case map[string]any:
+		if o, ok := otherValue.(map[string]any); ok {
+			for key, child := range v {
+				diffFields(fieldPath(path, key), child, o[key], fields)
+			}
+			for key, child := range o {
+				if _, ok := v[key]; !ok {
+					diffFields(fieldPath(path, key), nil, child, fields)
+				}
+			}
+			return
+		}
+	case []any:
+		if o, ok := otherValue.([]any); ok && len(v) == len(o) {
+			for i := range v {
+				diffFields(fmt.Sprintf("%s[%d]", path, i), v[i], o[i], fields)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(value, otherValue) {
+		*fields = append(*fields, FieldDiff{Path: path, Value: value, OtherValue: otherValue})
+	}
+}
+
+func fieldPath(path, key string) string {
+	switch {
+	case strings.ContainsAny(key, "./"):
+		return path + "['" + key + "']"
+	case path == "":
+		return key
+	default:
+		return path + "." + key
+	}
+}
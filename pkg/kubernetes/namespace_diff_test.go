@@ -0,0 +1,83 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type NamespaceDiffTestSuite struct {
+	suite.Suite
+}
+
+func diffObject(apiVersion, kind, name string, fields map[string]any) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{"apiVersion": apiVersion, "kind": kind, "metadata": map[string]any{"name": name}}}
+	for k, v := range fields {
+		obj.Object[k] = v
+	}
+	return obj
+}
+
+func (s *NamespaceDiffTestSuite) TestDiffNamespaces() {
+	staging := &NamespaceExport{Namespace: "staging", Objects: []unstructured.Unstructured{
+		diffObject("apps/v1", "Deployment", "web", map[string]any{"spec": map[string]any{"replicas": int64(1), "template": map[string]any{
+			"spec": map[string]any{"containers": []any{map[string]any{"name": "web", "image": "web:1.1"}}},
+		}}}),
+		diffObject("v1", "ConfigMap", "settings", map[string]any{"data": map[string]any{"mode": "debug"}}),
+		diffObject("v1", "Service", "web", map[string]any{"spec": map[string]any{"type": "ClusterIP"}}),
+		diffObject("v1", "ConfigMap", "feature-flags", nil),
+	}, Skipped: []string{"secrets (forbidden)"}}
+	production := &NamespaceExport{Namespace: "production", Objects: []unstructured.Unstructured{
+		diffObject("apps/v1", "Deployment", "web", map[string]any{"spec": map[string]any{"replicas": int64(3), "template": map[string]any{
+			"spec": map[string]any{"containers": []any{map[string]any{"name": "web", "image": "web:1.0"}}},
+		}}}),
+		diffObject("v1", "ConfigMap", "settings", map[string]any{"data": map[string]any{"mode": "debug", "cache": "on"}}),
+		diffObject("v1", "Service", "web", map[string]any{"spec": map[string]any{"type": "ClusterIP"}}),
+		diffObject("policy/v1", "PodDisruptionBudget", "web", nil),
+	}}
+	diff := DiffNamespaces(staging, production)
+	s.Run("counts the identical objects", func() {
+		s.Equal(1, diff.Identical)
+	})
+	s.Run("reports the objects missing from either namespace", func() {
+		s.Equal([]string{"ConfigMap/feature-flags"}, diff.OnlyInNamespace)
+		s.Equal([]string{"PodDisruptionBudget/web"}, diff.OnlyInOtherNamespace)
+	})
+	s.Run("reports the differing fields", func() {
+		s.Equal([]ObjectDiff{
+			{Object: "Deployment/web", Fields: []FieldDiff{
+				{Path: "spec.replicas", Value: int64(1), OtherValue: int64(3)},
+				{Path: "spec.template.spec.containers[0].image", Value: "web:1.1", OtherValue: "web:1.0"},
+			}},
+			{Object: "ConfigMap/settings", Fields: []FieldDiff{{Path: "data.cache", OtherValue: "on"}}},
+		}, diff.Differences)
+	})
+	s.Run("reports the skipped resources of each namespace", func() {
+		s.Equal([]string{"staging: secrets (forbidden)"}, diff.Skipped)
+	})
+}
+
+func (s *NamespaceDiffTestSuite) TestDiffNamespacesMatching() {
+	s.Run("matches the objects of different versions", func() {
+		diff := DiffNamespaces(
+			&NamespaceExport{Namespace: "a", Objects: []unstructured.Unstructured{diffObject("autoscaling/v2", "HorizontalPodAutoscaler", "web", nil)}},
+			&NamespaceExport{Namespace: "b", Objects: []unstructured.Unstructured{diffObject("autoscaling/v1", "HorizontalPodAutoscaler", "web", nil)}},
+		)
+		s.Equal(1, diff.Identical)
+	})
+	s.Run("quotes the keys with dots and slashes", func() {
+		diff := DiffNamespaces(
+			&NamespaceExport{Namespace: "a", Objects: []unstructured.Unstructured{diffObject("v1", "ConfigMap", "web", map[string]any{
+				"metadata": map[string]any{"name": "web", "labels": map[string]any{"app.kubernetes.io/version": "1.1"}}})}},
+			&NamespaceExport{Namespace: "b", Objects: []unstructured.Unstructured{diffObject("v1", "ConfigMap", "web", map[string]any{
+				"metadata": map[string]any{"name": "web", "labels": map[string]any{"app.kubernetes.io/version": "1.0"}}})}},
+		)
+		s.Require().Len(diff.Differences, 1)
+		s.Equal("metadata.labels['app.kubernetes.io/version']", diff.Differences[0].Fields[0].Path)
+	})
+}
+
+func TestNamespaceDiff(t *testing.T) {
+	suite.Run(t, new(NamespaceDiffTestSuite))
+}
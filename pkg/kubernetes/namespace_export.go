@@ -0,0 +1,169 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// namespaceExportSkippedResources are the resources that are not exported since they are transient or generated by the cluster
+	namespaceExportSkippedResources = []schema.GroupResource{
+		{Resource: "events"}, {Group: "events.k8s.io", Resource: "events"},
+		{Resource: "endpoints"}, {Group: "discovery.k8s.io", Resource: "endpointslices"},
+		{Group: "coordination.k8s.io", Resource: "leases"}, {Group: "metrics.k8s.io", Resource: "pods"},
+		{Group: "apps", Resource: "controllerrevisions"},
+	}
+	// namespaceExportKindOrder is the order in which the kinds are exported, so that the export can be applied as is
+	namespaceExportKindOrder = []string{"ServiceAccount", "Role", "RoleBinding", "Secret", "ConfigMap", "PersistentVolumeClaim", "Service"}
+	// namespaceExportSystemObjects are the objects created by the cluster in every namespace (Kind/Name)
+	namespaceExportSystemObjects = []string{"ServiceAccount/default", "ConfigMap/kube-root-ca.crt", "ConfigMap/openshift-service-ca.crt"}
+	// namespaceExportClusterAnnotations are the annotations set by the cluster that must not be exported
+	namespaceExportClusterAnnotations = []string{
+		"kubectl.kubernetes.io/last-applied-configuration",
+		deploymentRevisionAnnotation,
+		"pv.kubernetes.io/bind-completed",
+		"pv.kubernetes.io/bound-by-controller",
+		"volume.beta.kubernetes.io/storage-provisioner",
+		"volume.kubernetes.io/storage-provisioner",
+		"volume.kubernetes.io/selected-node",
+	}
+)
+
+// NamespaceExport are the cleaned objects of a namespace, ready to be applied to the same or another namespace.
+type NamespaceExport struct {
+	Namespace string
+	Objects   []unstructured.Unstructured
+	// Skipped are the resources that could not be exported (e.g. denied or forbidden) and the reason
+	Skipped []string
+}
+
+// NamespaceExport exports all the objects in the provided namespace that can be listed, stripping the fields
+// populated by the cluster (status, uid, resourceVersion, cluster IPs, ...). Objects managed by a controller
+// (e.g. the Pods of a Deployment) and the objects created by the cluster in every namespace are not exported.
+// Secrets are only exported when includeSecrets is true.
+func (c *Core) NamespaceExport(ctx context.Context, namespace string, includeSecrets bool) (*NamespaceExport, error) {
+	export := &NamespaceExport{Namespace: c.NamespaceOrDefault(namespace)}
+	resourceLists, err := c.DiscoveryClient().ServerPreferredNamespacedResources()
+	// The discovery of some groups might fail (e.g. unavailable aggregated APIs), the rest can still be exported
+	if len(resourceLists) == 0 && err != nil {
+		return nil, err
+	}
+	type exportedResource struct {
+		gvr  schema.GroupVersionResource
+		kind string
+	}
+	var resources []exportedResource
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, apiResource := range resourceList.APIResources {
+			gvr := gv.WithResource(apiResource.Name)
+			if strings.Contains(apiResource.Name, "/") || !slices.Contains(apiResource.Verbs, "list") ||
+				slices.Contains(namespaceExportSkippedResources, gvr.GroupResource()) ||
+				(!includeSecrets && gvr.GroupResource() == schema.GroupResource{Resource: "secrets"}) {
+				continue
+			}
+			resources = append(resources, exportedResource{gvr: gvr, kind: apiResource.Kind})
+		}
+	}
+	// The resources are listed concurrently, the lists are merged in the discovery order
+	lists, errs := FanOut(ctx, DefaultFanOutConcurrency, resources, func(ctx context.Context, resource exportedResource) (*unstructured.UnstructuredList, error) {
+		return c.DynamicClient().Resource(resource.gvr).Namespace(export.Namespace).List(ctx, metav1.ListOptions{})
+	})
+	for i, resource := range resources {
+		if err := errs[i]; err != nil {
+			reason := err.Error()
+			if apierrors.IsForbidden(err) {
+				reason = "forbidden"
+			}
+			export.Skipped = append(export.Skipped, fmt.Sprintf("%s (%s)", resource.gvr.GroupResource(), reason))
+			continue
+		}
+		for _, item := range lists[i].Items {
+			if item.GetKind() == "" {
+				item.SetAPIVersion(resource.gvr.GroupVersion().String())
+				item.SetKind(resource.kind)
+			}
+			if exportable(&item) {
+				export.Objects = append(export.Objects, item)
+			}
+		}
+	}
+	for i := range export.Objects {
+		cleanExportedObject(&export.Objects[i])
+	}
+	slices.SortStableFunc(export.Objects, func(a, b unstructured.Unstructured) int {
+		return cmp.Or(
+			cmp.Compare(exportKindRank(a.GetKind()), exportKindRank(b.GetKind())),
+			cmp.Compare(a.GetKind(), b.GetKind()),
+			cmp.Compare(a.GetName(), b.GetName()),
+		)
+	})
+	return export, nil
+}
+
+// exportable returns false for the objects that are recreated by the cluster and must not be exported
+func exportable(obj *unstructured.Unstructured) bool {
+	if metav1.GetControllerOfNoCopy(obj) != nil {
+		return false
+	}
+	if slices.Contains(namespaceExportSystemObjects, obj.GetKind()+"/"+obj.GetName()) {
+		return false
+	}
+	if secretType, _, _ := unstructured.NestedString(obj.Object, "type"); obj.GetKind() == "Secret" && secretType == "kubernetes.io/service-account-token" {
+		return false
+	}
+	return true
+}
+
+// cleanExportedObject removes the fields populated by the cluster so that the object can be applied to another namespace or cluster
+func cleanExportedObject(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "status")
+	for _, field := range []string{"namespace", "uid", "resourceVersion", "generation", "selfLink", "creationTimestamp", "managedFields", "ownerReferences"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	annotations := obj.GetAnnotations()
+	for _, annotation := range namespaceExportClusterAnnotations {
+		delete(annotations, annotation)
+	}
+	obj.SetAnnotations(annotations)
+	switch obj.GetKind() {
+	case "Service":
+		for _, field := range []string{"clusterIP", "clusterIPs", "healthCheckNodePort"} {
+			unstructured.RemoveNestedField(obj.Object, "spec", field)
+		}
+		if ports, found, _ := unstructured.NestedSlice(obj.Object, "spec", "ports"); found {
+			for _, port := range ports {
+				if port, ok := port.(map[string]any); ok {
+					delete(port, "nodePort")
+				}
+			}
+			_ = unstructured.SetNestedSlice(obj.Object, ports, "spec", "ports")
+		}
+	case "PersistentVolumeClaim":
+		unstructured.RemoveNestedField(obj.Object, "spec", "volumeName")
+	case "Job":
+		// The selector and its labels are generated with the uid of the Job
+		unstructured.RemoveNestedField(obj.Object, "spec", "selector")
+		for _, label := range []string{"controller-uid", "batch.kubernetes.io/controller-uid"} {
+			unstructured.RemoveNestedField(obj.Object, "spec", "template", "metadata", "labels", label)
+		}
+	}
+}
+
+func exportKindRank(kind string) int {
+	if rank := slices.Index(namespaceExportKindOrder, kind); rank >= 0 {
+		return rank
+	}
+	return len(namespaceExportKindOrder)
+}
@@ -0,0 +1,109 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type NamespaceExportTestSuite struct {
+	suite.Suite
+}
+
+func (s *NamespaceExportTestSuite) TestExportable() {
+	object := func(kind, name string, extra map[string]any) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]any{"apiVersion": "v1", "kind": kind, "metadata": map[string]any{"name": name}}}
+		for k, v := range extra {
+			obj.Object[k] = v
+		}
+		return obj
+	}
+	s.Run("exports user objects", func() {
+		s.True(exportable(object("ConfigMap", "settings", nil)))
+		s.True(exportable(object("Secret", "credentials", map[string]any{"type": "Opaque"})))
+	})
+	s.Run("skips objects managed by a controller", func() {
+		pod := object("Pod", "web-6c9f-abcde", nil)
+		pod.Object["metadata"].(map[string]any)["ownerReferences"] = []any{
+			map[string]any{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "web-6c9f", "uid": "1234", "controller": true},
+		}
+		s.False(exportable(pod))
+	})
+	s.Run("skips objects created by the cluster", func() {
+		s.False(exportable(object("ServiceAccount", "default", nil)))
+		s.False(exportable(object("ConfigMap", "kube-root-ca.crt", nil)))
+		s.False(exportable(object("Secret", "builder-token", map[string]any{"type": "kubernetes.io/service-account-token"})))
+	})
+}
+
+func (s *NamespaceExportTestSuite) TestCleanExportedObject() {
+	s.Run("strips cluster-specific metadata and status", func() {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name": "web", "namespace": "prod", "uid": "1234", "resourceVersion": "42", "generation": int64(3),
+				"creationTimestamp": "2025-01-01T00:00:00Z", "managedFields": []any{map[string]any{"manager": "kubectl"}},
+				"labels":      map[string]any{"app": "web"},
+				"annotations": map[string]any{deploymentRevisionAnnotation: "3", "kubectl.kubernetes.io/last-applied-configuration": "{}", "team": "payments"},
+			},
+			"spec":   map[string]any{"replicas": int64(2)},
+			"status": map[string]any{"readyReplicas": int64(2)},
+		}}
+		cleanExportedObject(obj)
+		s.Equal(map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name":        "web",
+				"labels":      map[string]any{"app": "web"},
+				"annotations": map[string]any{"team": "payments"},
+			},
+			"spec": map[string]any{"replicas": int64(2)},
+		}, obj.Object)
+	})
+	s.Run("strips allocated Service IPs and node ports", func() {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata":   map[string]any{"name": "web"},
+			"spec": map[string]any{
+				"type": "NodePort", "clusterIP": "10.0.0.1", "clusterIPs": []any{"10.0.0.1"},
+				"ports": []any{map[string]any{"port": int64(80), "nodePort": int64(30080)}},
+			},
+		}}
+		cleanExportedObject(obj)
+		s.Equal(map[string]any{"type": "NodePort", "ports": []any{map[string]any{"port": int64(80)}}}, obj.Object["spec"])
+	})
+	s.Run("strips the bound volume of a PersistentVolumeClaim", func() {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "PersistentVolumeClaim",
+			"metadata":   map[string]any{"name": "data", "annotations": map[string]any{"pv.kubernetes.io/bind-completed": "yes"}},
+			"spec":       map[string]any{"volumeName": "pvc-1234", "storageClassName": "standard"},
+		}}
+		cleanExportedObject(obj)
+		s.Equal(map[string]any{"storageClassName": "standard"}, obj.Object["spec"])
+		s.Empty(obj.GetAnnotations())
+	})
+	s.Run("strips the generated selector of a Job", func() {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "batch/v1",
+			"kind":       "Job",
+			"metadata":   map[string]any{"name": "migrate"},
+			"spec": map[string]any{
+				"selector": map[string]any{"matchLabels": map[string]any{"batch.kubernetes.io/controller-uid": "1234"}},
+				"template": map[string]any{"metadata": map[string]any{"labels": map[string]any{"batch.kubernetes.io/controller-uid": "1234", "app": "migrate"}}},
+			},
+		}}
+		cleanExportedObject(obj)
+		s.Equal(map[string]any{
+			"template": map[string]any{"metadata": map[string]any{"labels": map[string]any{"app": "migrate"}}},
+		}, obj.Object["spec"])
+	})
+}
+
+func TestNamespaceExport(t *testing.T) {
+	suite.Run(t, new(NamespaceExportTestSuite))
+}
@@ -0,0 +1,197 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+const (
+	NetCheckDNS  = "dns"
+	NetCheckTCP  = "tcp"
+	NetCheckHTTP = "http"
+
+	// netCheckMarker prefixes the lines printed by the diagnostic script to delimit the output of each check
+	netCheckMarker = "@@net_check"
+)
+
+// netCheckHost matches the host names and IP addresses accepted as net_check targets
+var netCheckHost = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.:-]*[A-Za-z0-9])?$`)
+
+// NetCheckOptions configures the checks performed by NetCheck.
+type NetCheckOptions struct {
+	// Target is a Service name (resolved in Namespace), a Service DNS name, a host name, or an IP address
+	Target    string
+	Namespace string
+	// Port to check, if not provided the ports of the target Service are checked
+	Port int32
+	// HTTPPath performs an HTTP GET request against each port when provided
+	HTTPPath string
+	Image    string
+	Timeout  time.Duration
+}
+
+// NetCheck is the result of a single connectivity check.
+type NetCheck struct {
+	Type    string `json:"type"`
+	Target  string `json:"target"`
+	Success bool   `json:"success"`
+	Output  string `json:"output,omitempty"`
+}
+
+// NetCheckResult is the result of the connectivity checks run from the diagnostic Pod.
+type NetCheckResult struct {
+	Namespace string     `json:"namespace"`
+	Pod       string     `json:"pod"`
+	Image     string     `json:"image"`
+	Host      string     `json:"host"`
+	Checks    []NetCheck `json:"checks"`
+}
+
+// NetCheck launches a short-lived diagnostic Pod that runs DNS lookups, TCP connects, and (optionally) HTTP requests
+// against the target from inside the cluster. The Pod is deleted once the checks complete (or time out).
+func (c *Core) NetCheck(ctx context.Context, options NetCheckOptions) (*NetCheckResult, error) {
+	if !netCheckHost.MatchString(options.Target) {
+		return nil, fmt.Errorf("invalid target %q, expected a Service name, a host name, or an IP address", options.Target)
+	}
+	namespace := c.NamespaceOrDefault(options.Namespace)
+	host := options.Target
+	var ports []int32
+	var httpPorts []int32
+	if options.Port > 0 {
+		ports = append(ports, options.Port)
+	} else if !strings.ContainsAny(host, ".:") {
+		// A Service in the namespace: check all of its TCP ports
+		service, err := c.CoreV1().Services(namespace).Get(ctx, host, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil {
+			host = fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
+			for _, port := range service.Spec.Ports {
+				if port.Protocol != "" && port.Protocol != v1.ProtocolTCP {
+					continue
+				}
+				ports = append(ports, port.Port)
+				if strings.HasPrefix(port.Name, "http") || strings.HasPrefix(ptr.Deref(port.AppProtocol, ""), "http") {
+					httpPorts = append(httpPorts, port.Port)
+				}
+			}
+		}
+	}
+	if options.HTTPPath != "" {
+		httpPorts = ports
+	}
+	result := &NetCheckResult{
+		Namespace: namespace,
+		Pod:       version.BinaryName + "-net-check-" + rand.String(5),
+		Image:     options.Image,
+		Host:      host,
+	}
+	pod := &v1.Pod{
+		ObjectMeta: transientObjectMeta(namespace, result.Pod, "net-check", options.Timeout),
+		Spec: v1.PodSpec{
+			RestartPolicy:                 v1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: ptr.To(int64(0)),
+			ActiveDeadlineSeconds:         ptr.To(int64(options.Timeout.Seconds()) + 1),
+			Containers: []v1.Container{{
+				Name:    "net-check",
+				Image:   options.Image,
+				Command: []string{"sh", "-c", netCheckScript(host, ports, httpPorts, options.HTTPPath)},
+			}},
+		},
+	}
+	pods := c.CoreV1().Pods(namespace)
+	if _, err := pods.Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to create diagnostic pod: %w", err)
+	}
+	defer func() {
+		// Clean up even if the request was cancelled
+		_ = pods.Delete(context.WithoutCancel(ctx), pod.Name, metav1.DeleteOptions{GracePeriodSeconds: ptr.To(int64(0))})
+	}()
+	phase := v1.PodPending
+	// The requests use the parent context, the timeout only applies to the wait
+	err := wait.PollUntilContextTimeout(ctx, time.Second, options.Timeout, true, func(context.Context) (bool, error) {
+		current, err := pods.Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		phase = current.Status.Phase
+		return phase == v1.PodSucceeded || phase == v1.PodFailed, nil
+	})
+	if err != nil {
+		if wait.Interrupted(err) {
+			return nil, fmt.Errorf("diagnostic pod %s did not complete within %s (phase %s), check that the image %s can be pulled and the pod can be scheduled",
+				pod.Name, options.Timeout, phase, options.Image)
+		}
+		return nil, err
+	}
+	logs, err := pods.GetLogs(pod.Name, &v1.PodLogOptions{}).Do(ctx).Raw()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the logs of diagnostic pod %s: %w", pod.Name, err)
+	}
+	result.Checks = parseNetCheckOutput(string(logs))
+	if len(result.Checks) == 0 {
+		return nil, errors.New("diagnostic pod produced no results, check that the image provides sh, nslookup, nc, and wget: " + strings.TrimSpace(string(logs)))
+	}
+	return result, nil
+}
+
+// netCheckScript returns the shell script that runs the checks, the output of each check is delimited by marker lines
+func netCheckScript(host string, ports, httpPorts []int32, httpPath string) string {
+	var script strings.Builder
+	check := func(checkType, target, command string) {
+		_, _ = fmt.Fprintf(&script, "echo %s %s %s; %s 2>&1; echo %s result $?;\n", netCheckMarker, checkType, shellQuote(target), command, netCheckMarker)
+	}
+	check(NetCheckDNS, host, "nslookup "+shellQuote(host))
+	for _, port := range ports {
+		check(NetCheckTCP, fmt.Sprintf("%s:%d", host, port), fmt.Sprintf("nc -w 5 %s %d </dev/null", shellQuote(host), port))
+	}
+	for _, port := range httpPorts {
+		url := fmt.Sprintf("http://%s:%d/%s", host, port, strings.TrimPrefix(httpPath, "/"))
+		check(NetCheckHTTP, url, "wget -q -S -T 5 -O /dev/null "+shellQuote(url))
+	}
+	return script.String()
+}
+
+func parseNetCheckOutput(logs string) []NetCheck {
+	var checks []NetCheck
+	var current *NetCheck
+	var output []string
+	for _, line := range strings.Split(logs, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != netCheckMarker {
+			if current != nil {
+				output = append(output, line)
+			}
+			continue
+		}
+		if fields[1] == "result" && current != nil {
+			exitCode, _ := strconv.Atoi(fields[2])
+			current.Success = exitCode == 0
+			current.Output = strings.TrimSpace(strings.Join(output, "\n"))
+			checks = append(checks, *current)
+			current, output = nil, nil
+			continue
+		}
+		current = &NetCheck{Type: fields[1], Target: strings.Join(fields[2:], " ")}
+	}
+	return checks
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
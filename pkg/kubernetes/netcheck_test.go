@@ -0,0 +1,46 @@
+package kubernetes
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type NetCheckTestSuite struct {
+	suite.Suite
+}
+
+func (s *NetCheckTestSuite) TestNetCheckScript() {
+	script := netCheckScript("web.default.svc", []int32{80, 443}, []int32{80}, "/healthz")
+	s.Contains(script, "nslookup 'web.default.svc'")
+	s.Contains(script, "nc -w 5 'web.default.svc' 443 </dev/null")
+	s.Contains(script, "wget -q -S -T 5 -O /dev/null 'http://web.default.svc:80/healthz'")
+	s.Run("delimits the output of each check", func() {
+		if _, err := exec.LookPath("sh"); err != nil {
+			s.T().Skip("sh not available")
+		}
+		// Replace the network tools with stubs to run the script locally
+		stubs := "nslookup() { echo \"Name: $1\"; }; nc() { echo 'connection refused'; return 1; }; wget() { echo 'HTTP/1.1 200 OK'; };\n"
+		out, err := exec.Command("sh", "-c", stubs+script).CombinedOutput()
+		s.Require().NoError(err)
+		s.Equal([]NetCheck{
+			{Type: NetCheckDNS, Target: "web.default.svc", Success: true, Output: "Name: web.default.svc"},
+			{Type: NetCheckTCP, Target: "web.default.svc:80", Success: false, Output: "connection refused"},
+			{Type: NetCheckTCP, Target: "web.default.svc:443", Success: false, Output: "connection refused"},
+			{Type: NetCheckHTTP, Target: "http://web.default.svc:80/healthz", Success: true, Output: "HTTP/1.1 200 OK"},
+		}, parseNetCheckOutput(string(out)))
+	})
+}
+
+func (s *NetCheckTestSuite) TestParseNetCheckOutputIgnoresIncompleteChecks() {
+	s.Empty(parseNetCheckOutput("some noise\n@@net_check dns web\nServer: 10.0.0.10\n"))
+}
+
+func (s *NetCheckTestSuite) TestShellQuote() {
+	s.Equal(`'it'\''s'`, shellQuote("it's"))
+}
+
+func TestNetCheck(t *testing.T) {
+	suite.Run(t, new(NetCheckTestSuite))
+}
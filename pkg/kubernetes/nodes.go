@@ -2,22 +2,24 @@ package kubernetes
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/metrics/pkg/apis/metrics"
 	metricsv1beta1api "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
 
-func (c *Core) NodesLog(ctx context.Context, name string, query string, tailLines int64) (string, error) {
+func (c *Core) NodesLog(ctx context.Context, name string, options api.NodesLogOptions) (string, error) {
 	// Use the node proxy API to access logs from the kubelet
 	// https://kubernetes.io/docs/concepts/cluster-administration/system-logs/#log-query
 	// Common log paths:
 	// - /var/log/kubelet.log - kubelet logs
 	// - /var/log/kube-proxy.log - kube-proxy logs
 	// - /var/log/containers/ - container logs
+	// The services (journal units, e.g. kubelet, crio) require the NodeLogQuery feature of the kubelet
 
 	if _, err := c.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{}); err != nil {
 		return "", fmt.Errorf("failed to get node %s: %w", name, err)
@@ -26,10 +28,22 @@ func (c *Core) NodesLog(ctx context.Context, name string, query string, tailLine
 	req := c.CoreV1().RESTClient().
 		Get().
 		AbsPath("api", "v1", "nodes", name, "proxy", "logs")
-	req.Param("query", query)
+	req.Param("query", options.Query)
 	// Query parameters for tail
-	if tailLines > 0 {
-		req.Param("tailLines", fmt.Sprintf("%d", tailLines))
+	if options.TailLines > 0 {
+		req.Param("tailLines", fmt.Sprintf("%d", options.TailLines))
+	}
+	if options.SinceTime != "" {
+		req.Param("sinceTime", options.SinceTime)
+	}
+	if options.UntilTime != "" {
+		req.Param("untilTime", options.UntilTime)
+	}
+	if options.Pattern != "" {
+		req.Param("pattern", options.Pattern)
+	}
+	if options.Boot != nil {
+		req.Param("boot", fmt.Sprintf("%d", *options.Boot))
 	}
 
 	result := req.Do(ctx)
@@ -70,14 +84,46 @@ func (c *Core) NodesStatsSummary(ctx context.Context, name string) (string, erro
 	return string(rawData), nil
 }
 
-func (c *Core) NodesTop(ctx context.Context, options api.NodesTopOptions) (*metrics.NodeMetricsList, error) {
-	// TODO, maybe move to mcp Tools setup and omit in case metrics aren't available in the target cluster
-	if !c.supportsGroupVersion(metrics.GroupName + "/" + metricsv1beta1api.SchemeGroupVersion.Version) {
-		return nil, errors.New("metrics API is not available")
+// NodesKubeletEndpoints are the kubelet introspection endpoints supported by NodesKubelet
+var NodesKubeletEndpoints = []string{"configz", "healthz"}
+
+// NodesKubelet gets the running configuration (configz) or the health checks (healthz) of the kubelet of the node
+// through the node proxy API, to diagnose the node-level problems that the Pod logs can't explain.
+func (c *Core) NodesKubelet(ctx context.Context, name, endpoint string) (string, error) {
+	if !slices.Contains(NodesKubeletEndpoints, endpoint) {
+		return "", fmt.Errorf("unsupported kubelet endpoint %s, supported endpoints are %v", endpoint, NodesKubeletEndpoints)
+	}
+
+	if _, err := c.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{}); err != nil {
+		return "", fmt.Errorf("failed to get node %s: %w", name, err)
+	}
+
+	req := c.CoreV1().RESTClient().
+		Get().
+		AbsPath("api", "v1", "nodes", name, "proxy", endpoint)
+	if endpoint == "healthz" {
+		// list each of the health checks instead of a single ok
+		req.Param("verbose", "")
 	}
+	// The healthz endpoint answers the failing checks with a 500 and their details, which are worth returning
+	rawData, err := req.DoRaw(ctx)
+	if err != nil && (endpoint != "healthz" || !apierrors.IsInternalError(err) || len(rawData) == 0) {
+		return "", fmt.Errorf("failed to get kubelet %s: %w", endpoint, err)
+	}
+
+	return string(rawData), nil
+}
+
+func (c *Core) NodesTop(ctx context.Context, options api.NodesTopOptions) (*metrics.NodeMetricsList, error) {
 	versionedMetrics := &metricsv1beta1api.NodeMetricsList{}
 	var err error
-	if options.Name != "" {
+	if !c.supportsGroupVersion(metrics.GroupName + "/" + metricsv1beta1api.SchemeGroupVersion.Version) {
+		// Minimal clusters may not run the metrics-server, the kubelets report the same usage through their Summary API
+		versionedMetrics, err = c.nodesTopFromKubeletSummary(ctx, options)
+		if err != nil {
+			return nil, fmt.Errorf("metrics API is not available, failed to get the usage from the kubelet Summary API: %w", err)
+		}
+	} else if options.Name != "" {
 		m, err := c.MetricsV1beta1Client().NodeMetricses().Get(ctx, options.Name, metav1.GetOptions{})
 		if err != nil {
 			return nil, fmt.Errorf("failed to get metrics for node %s: %w", options.Name, err)
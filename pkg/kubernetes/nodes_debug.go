@@ -0,0 +1,153 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+const (
+	// DefaultNodeDebugImage is the default image of the node debug Pods
+	DefaultNodeDebugImage = "busybox:1.37"
+	// NodeDebugHostPath is where the root filesystem of the node is mounted in the node debug Pods
+	NodeDebugHostPath = "/host"
+)
+
+// NodeDebugConfig enables the node debug Pods, which run with the host namespaces of the node and its root filesystem mounted.
+type NodeDebugConfig struct {
+	// Image of the node debug Pods (Optional, busybox if not provided)
+	Image string `toml:"image,omitempty"`
+	// Namespace where the node debug Pods run (Optional, the default namespace if not provided)
+	Namespace string `toml:"namespace,omitempty"`
+	// Privileged runs the node debug Pods as privileged containers, required to inspect most of the node (e.g. iptables, crictl)
+	Privileged bool `toml:"privileged,omitempty"`
+}
+
+func (c *NodeDebugConfig) Validate() error {
+	if c == nil {
+		return errors.New("node_debug config is nil")
+	}
+	if strings.ContainsAny(c.Image, " \t\n") {
+		return fmt.Errorf("node_debug image is invalid: %q", c.Image)
+	}
+	return nil
+}
+
+// NodeDebugOptions configures the command run by NodesDebug.
+type NodeDebugOptions struct {
+	Node    string
+	Command []string
+	Timeout time.Duration
+}
+
+// NodeDebugResult is the result of a command run on a node by NodesDebug.
+type NodeDebugResult struct {
+	Node       string `json:"node"`
+	Namespace  string `json:"namespace"`
+	Pod        string `json:"pod"`
+	Image      string `json:"image"`
+	Privileged bool   `json:"privileged"`
+	ExitCode   int32  `json:"exitCode"`
+	Output     string `json:"output"`
+}
+
+// NodesDebug runs the command on the node from a short-lived Pod with the host network, PID, and IPC namespaces of
+// the node and its root filesystem mounted at /host (like kubectl debug node), and returns its output.
+// The Pod is deleted once the command completes (or times out).
+func (c *Core) NodesDebug(ctx context.Context, config *NodeDebugConfig, options NodeDebugOptions) (*NodeDebugResult, error) {
+	if len(options.Command) == 0 {
+		return nil, errors.New("the command is required")
+	}
+	if _, err := c.CoreV1().Nodes().Get(ctx, options.Node, metav1.GetOptions{}); err != nil {
+		return nil, err
+	}
+	namespace := c.NamespaceOrDefault(config.Namespace)
+	image := config.Image
+	if image == "" {
+		image = DefaultNodeDebugImage
+	}
+	result := &NodeDebugResult{
+		Node:       options.Node,
+		Namespace:  namespace,
+		Pod:        version.BinaryName + "-node-debug-" + rand.String(5),
+		Image:      image,
+		Privileged: config.Privileged,
+	}
+	pod := &v1.Pod{
+		ObjectMeta: transientObjectMeta(namespace, result.Pod, "node-debug", options.Timeout),
+		Spec: v1.PodSpec{
+			NodeName:                      options.Node,
+			HostNetwork:                   true,
+			HostPID:                       true,
+			HostIPC:                       true,
+			RestartPolicy:                 v1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: ptr.To(int64(0)),
+			ActiveDeadlineSeconds:         ptr.To(int64(options.Timeout.Seconds()) + 1),
+			// Run on the tainted nodes too (e.g. control plane, NotReady)
+			Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
+			Volumes: []v1.Volume{{Name: "host-root", VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{Path: "/"},
+			}}},
+			Containers: []v1.Container{{
+				Name:            "debugger",
+				Image:           image,
+				Command:         options.Command,
+				VolumeMounts:    []v1.VolumeMount{{Name: "host-root", MountPath: NodeDebugHostPath}},
+				SecurityContext: &v1.SecurityContext{Privileged: ptr.To(config.Privileged)},
+			}},
+		},
+	}
+	pods := c.CoreV1().Pods(namespace)
+	if _, err := pods.Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to create node debug pod: %w", err)
+	}
+	defer func() {
+		// Clean up even if the request was cancelled
+		_ = pods.Delete(context.WithoutCancel(ctx), pod.Name, metav1.DeleteOptions{GracePeriodSeconds: ptr.To(int64(0))})
+	}()
+	var current *v1.Pod
+	// The requests use the parent context, the timeout only applies to the wait
+	err := wait.PollUntilContextTimeout(ctx, time.Second, options.Timeout, true, func(context.Context) (bool, error) {
+		var err error
+		if current, err = pods.Get(ctx, pod.Name, metav1.GetOptions{}); err != nil {
+			return false, err
+		}
+		return current.Status.Phase == v1.PodSucceeded || current.Status.Phase == v1.PodFailed, nil
+	})
+	if err != nil {
+		if wait.Interrupted(err) {
+			phase := v1.PodPending
+			if current != nil {
+				phase = current.Status.Phase
+			}
+			return nil, fmt.Errorf("node debug pod %s did not complete within %s (phase %s), check that the image %s can be pulled and the command terminates",
+				pod.Name, options.Timeout, phase, image)
+		}
+		return nil, err
+	}
+	terminated := false
+	for _, status := range current.Status.ContainerStatuses {
+		if status.State.Terminated != nil {
+			result.ExitCode, terminated = status.State.Terminated.ExitCode, true
+		}
+	}
+	if !terminated && current.Status.Phase == v1.PodFailed {
+		return nil, fmt.Errorf("node debug pod %s failed: %s", pod.Name, strings.TrimSpace(current.Status.Reason+" "+current.Status.Message))
+	}
+	logs, err := pods.GetLogs(pod.Name, &v1.PodLogOptions{}).Do(ctx).Raw()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the output of node debug pod %s: %w", pod.Name, err)
+	}
+	result.Output = string(logs)
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
+)
+
+// nodeRequestsHighPercent is the percentage of the allocatable resources above which the requests of a node are highlighted
+const nodeRequestsHighPercent = 90
+
+// NodeHealth is the health summary of a Node.
+type NodeHealth struct {
+	Name          string `json:"name"`
+	Ready         bool   `json:"ready"`
+	Unschedulable bool   `json:"unschedulable,omitempty"`
+	// Conditions are the abnormal conditions of the Node (not ready, pressure, network unavailable)
+	Conditions              []NodeConditionHealth  `json:"conditions,omitempty"`
+	KubeletVersion          string                 `json:"kubeletVersion,omitempty"`
+	ContainerRuntimeVersion string                 `json:"containerRuntimeVersion,omitempty"`
+	KernelVersion           string                 `json:"kernelVersion,omitempty"`
+	Pods                    NodeResourceAllocation `json:"pods"`
+	CPU                     NodeResourceAllocation `json:"cpu"`
+	Memory                  NodeResourceAllocation `json:"memory"`
+	Issues                  []string               `json:"issues,omitempty"`
+}
+
+// NodeConditionHealth is an abnormal condition of a Node and for how long it has been in that state.
+type NodeConditionHealth struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+	For     string `json:"for,omitempty"`
+}
+
+// NodeResourceAllocation compares the resources requested by the Pods scheduled on a Node with the allocatable ones.
+type NodeResourceAllocation struct {
+	Allocatable      string `json:"allocatable"`
+	Requested        string `json:"requested"`
+	RequestedPercent int64  `json:"requestedPercent"`
+}
+
+// NodesHealth reports the conditions, versions, and allocatable vs requested resources of the Nodes matching the
+// label selector (all Nodes if empty), highlighting the outliers in the locale of the context.
+func (c *Core) NodesHealth(ctx context.Context, labelSelector string) ([]NodeHealth, error) {
+	nodes, err := c.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+	// Terminated Pods don't consume resources (same as kubectl describe node)
+	pods, err := c.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "status.phase!=Succeeded,status.phase!=Failed"})
+	if err != nil {
+		return nil, err
+	}
+	return nodesHealth(nodes.Items, pods.Items, time.Now(), locale.FromContext(ctx)), nil
+}
+
+func nodesHealth(nodes []v1.Node, pods []v1.Pod, now time.Time, p locale.Printer) []NodeHealth {
+	nodePods := map[string][]v1.Pod{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			nodePods[pod.Spec.NodeName] = append(nodePods[pod.Spec.NodeName], pod)
+		}
+	}
+	kubeletVersions, runtimeVersions := map[string]int{}, map[string]int{}
+	for _, node := range nodes {
+		kubeletVersions[node.Status.NodeInfo.KubeletVersion]++
+		runtimeVersions[node.Status.NodeInfo.ContainerRuntimeVersion]++
+	}
+	kubeletVersion, runtimeVersion := mostCommon(kubeletVersions), mostCommon(runtimeVersions)
+	health := make([]NodeHealth, 0, len(nodes))
+	for _, node := range nodes {
+		h := NodeHealth{
+			Name:                    node.Name,
+			Unschedulable:           node.Spec.Unschedulable,
+			KubeletVersion:          node.Status.NodeInfo.KubeletVersion,
+			ContainerRuntimeVersion: node.Status.NodeInfo.ContainerRuntimeVersion,
+			KernelVersion:           node.Status.NodeInfo.KernelVersion,
+		}
+		for _, condition := range node.Status.Conditions {
+			abnormal := condition.Status == v1.ConditionTrue
+			if condition.Type == v1.NodeReady {
+				h.Ready = condition.Status == v1.ConditionTrue
+				abnormal = !h.Ready
+			}
+			if !abnormal {
+				continue
+			}
+			conditionHealth := NodeConditionHealth{
+				Type:    string(condition.Type),
+				Status:  string(condition.Status),
+				Reason:  condition.Reason,
+				Message: condition.Message,
+			}
+			if !condition.LastTransitionTime.IsZero() {
+				conditionHealth.For = p.Duration(now.Sub(condition.LastTransitionTime.Time))
+			}
+			h.Conditions = append(h.Conditions, conditionHealth)
+			if condition.Type == v1.NodeReady {
+				h.Issues = append(h.Issues, p.Sprintf("node is not ready for %s: %s", cmp.Or(conditionHealth.For, p.Sprintf("an unknown time")), cmp.Or(condition.Message, condition.Reason)))
+			} else {
+				h.Issues = append(h.Issues, p.Sprintf("node has condition %s: %s", condition.Type, cmp.Or(condition.Message, condition.Reason)))
+			}
+		}
+		if h.Unschedulable {
+			h.Issues = append(h.Issues, p.Sprintf("node is cordoned (unschedulable)"))
+		}
+		requested := podsRequests(nodePods[node.Name])
+		h.Pods = nodeResourceAllocation(node.Status.Allocatable[v1.ResourcePods], *resource.NewQuantity(int64(len(nodePods[node.Name])), resource.DecimalSI))
+		h.CPU = nodeResourceAllocation(node.Status.Allocatable[v1.ResourceCPU], requested[v1.ResourceCPU])
+		h.Memory = nodeResourceAllocation(node.Status.Allocatable[v1.ResourceMemory], requested[v1.ResourceMemory])
+		for resourceName, allocation := range map[string]NodeResourceAllocation{"pods": h.Pods, "cpu": h.CPU, "memory": h.Memory} {
+			if allocation.RequestedPercent >= nodeRequestsHighPercent {
+				h.Issues = append(h.Issues, p.Sprintf("%s requests are at %d%% of the allocatable %s", resourceName, allocation.RequestedPercent, allocation.Allocatable))
+			}
+		}
+		if h.KubeletVersion != kubeletVersion {
+			h.Issues = append(h.Issues, p.Sprintf("kubelet version %s differs from the most common version %s", h.KubeletVersion, kubeletVersion))
+		}
+		if h.ContainerRuntimeVersion != runtimeVersion {
+			h.Issues = append(h.Issues, p.Sprintf("container runtime version %s differs from the most common version %s", h.ContainerRuntimeVersion, runtimeVersion))
+		}
+		slices.Sort(h.Issues)
+		health = append(health, h)
+	}
+	slices.SortFunc(health, func(a, b NodeHealth) int { return cmp.Compare(a.Name, b.Name) })
+	return health
+}
+
+func nodeResourceAllocation(allocatable, requested resource.Quantity) NodeResourceAllocation {
+	allocation := NodeResourceAllocation{Allocatable: allocatable.String(), Requested: requested.String()}
+	if allocatable.MilliValue() > 0 {
+		allocation.RequestedPercent = requested.MilliValue() * 100 / allocatable.MilliValue()
+	}
+	return allocation
+}
+
+// mostCommon returns the most common value (the lowest one in case of tie)
+func mostCommon(counts map[string]int) string {
+	var ret string
+	for value, count := range counts {
+		if count > counts[ret] || (count == counts[ret] && value < ret) {
+			ret = value
+		}
+	}
+	return ret
+}
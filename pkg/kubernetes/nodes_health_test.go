@@ -0,0 +1,87 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
+)
+
+type NodesHealthTestSuite struct {
+	suite.Suite
+}
+
+func healthNode(name, kubeletVersion string, conditions ...v1.NodeCondition) v1.Node {
+	return v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1.NodeStatus{
+			Conditions: conditions,
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("4Gi"),
+				v1.ResourcePods:   resource.MustParse("110"),
+			},
+			NodeInfo: v1.NodeSystemInfo{KubeletVersion: kubeletVersion, ContainerRuntimeVersion: "containerd://1.7.0"},
+		},
+	}
+}
+
+func (s *NodesHealthTestSuite) TestNodesHealth() {
+	now := time.Now()
+	ready := v1.NodeCondition{Type: v1.NodeReady, Status: v1.ConditionTrue}
+	nodes := []v1.Node{
+		healthNode("node-c", "v1.34.0", ready),
+		healthNode("node-a", "v1.34.0", ready, v1.NodeCondition{Type: v1.NodeMemoryPressure, Status: v1.ConditionTrue, Reason: "KubeletHasInsufficientMemory"}),
+		healthNode("node-b", "v1.33.0", v1.NodeCondition{
+			Type: v1.NodeReady, Status: v1.ConditionUnknown, Message: "Kubelet stopped posting node status.",
+			LastTransitionTime: metav1.NewTime(now.Add(-5 * time.Hour)),
+		}),
+	}
+	nodes[0].Spec.Unschedulable = true
+	pods := []v1.Pod{{Spec: v1.PodSpec{NodeName: "node-c", Containers: []v1.Container{{
+		Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1900m"), v1.ResourceMemory: resource.MustParse("1Gi")}},
+	}}}}}
+	health := nodesHealth(nodes, pods, now, locale.NewPrinter(locale.English))
+	s.Require().Len(health, 3)
+	s.Run("sorts nodes by name", func() {
+		s.Equal([]string{"node-a", "node-b", "node-c"}, []string{health[0].Name, health[1].Name, health[2].Name})
+	})
+	s.Run("reports pressure conditions", func() {
+		s.True(health[0].Ready)
+		s.Require().Len(health[0].Conditions, 1)
+		s.Equal("MemoryPressure", health[0].Conditions[0].Type)
+		s.Equal([]string{"node has condition MemoryPressure: KubeletHasInsufficientMemory"}, health[0].Issues)
+	})
+	s.Run("reports not ready nodes and version skew", func() {
+		s.False(health[1].Ready)
+		s.Equal("5h", health[1].Conditions[0].For)
+		s.Equal([]string{
+			"kubelet version v1.33.0 differs from the most common version v1.34.0",
+			"node is not ready for 5h: Kubelet stopped posting node status.",
+		}, health[1].Issues)
+	})
+	s.Run("reports allocatable vs requested resources", func() {
+		s.Equal(NodeResourceAllocation{Allocatable: "2", Requested: "1900m", RequestedPercent: 95}, health[2].CPU)
+		s.Equal(NodeResourceAllocation{Allocatable: "4Gi", Requested: "1Gi", RequestedPercent: 25}, health[2].Memory)
+		s.Equal(NodeResourceAllocation{Allocatable: "110", Requested: "1", RequestedPercent: 0}, health[2].Pods)
+		s.Equal([]string{"cpu requests are at 95% of the allocatable 2", "node is cordoned (unschedulable)"}, health[2].Issues)
+	})
+	s.Run("renders the issues in the locale", func() {
+		health := nodesHealth(nodes, pods, now, locale.NewPrinter(locale.German))
+		s.Require().Len(health, 3)
+		s.Equal("5 Std.", health[1].Conditions[0].For)
+		s.Equal([]string{
+			"Kubelet-Version v1.33.0 weicht von der häufigsten Version v1.34.0 ab",
+			"Node ist seit 5 Std. nicht bereit: Kubelet stopped posting node status.",
+		}, health[1].Issues)
+	})
+}
+
+func TestNodesHealth(t *testing.T) {
+	suite.Run(t, new(NodesHealthTestSuite))
+}
@@ -0,0 +1,320 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+// OrphanedResource is a resource that is likely unused.
+type OrphanedResource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+	Age       string `json:"age,omitempty"`
+}
+
+// orphansInventory contains the resources analyzed to detect orphaned resources
+type orphansInventory struct {
+	services       []v1.Service
+	endpointSlices []discoveryv1.EndpointSlice
+	configMaps     []v1.ConfigMap
+	secrets        []v1.Secret
+	pvcs           []v1.PersistentVolumeClaim
+	replicaSets    []appsv1.ReplicaSet
+	deployments    []appsv1.Deployment
+	// podSpecs are the specs of the Pods and of the templates of the workloads (which reference resources even when scaled down)
+	podSpecs        []podSpecRef
+	ingresses       []networkingv1.Ingress
+	serviceAccounts []v1.ServiceAccount
+	// failed are the errors of the lists that failed (e.g. forbidden) by kind, the checks depending on them are skipped
+	failed map[string]error
+}
+
+// workloadKinds are the kinds of the workloads whose templates reference ConfigMaps and Secrets
+var workloadKinds = []string{"Deployments", "StatefulSets", "DaemonSets", "CronJobs", "ReplicaSets"}
+
+type podSpecRef struct {
+	namespace string
+	spec      *v1.PodSpec
+	// pod is true for the specs of actual Pods (as opposed to workload templates)
+	pod bool
+}
+
+// Orphans finds likely-orphaned resources in the provided namespace (all namespaces if empty):
+// Services without ready endpoints, ConfigMaps and Secrets not referenced by any Pod (or workload template),
+// PersistentVolumeClaims not mounted by any Pod and older than unusedFor, and ReplicaSets with zero replicas left behind
+// by Deployments that no longer exist.
+// The checks depending on a list that fails (e.g. forbidden by RBAC) are skipped instead of reporting every resource
+// they would have found in use, the skipped checks are returned with the error of the list.
+func (c *Core) Orphans(ctx context.Context, namespace string, unusedFor time.Duration) ([]OrphanedResource, []string, error) {
+	inventory := &orphansInventory{failed: map[string]error{}}
+	services, err := c.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	inventory.services = services.Items
+	if endpointSlices, err := c.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		inventory.endpointSlices = endpointSlices.Items
+	} else {
+		inventory.failed["EndpointSlices"] = err
+	}
+	if configMaps, err := c.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		inventory.configMaps = configMaps.Items
+	} else {
+		inventory.failed["ConfigMaps"] = err
+	}
+	if secrets, err := c.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		inventory.secrets = secrets.Items
+	} else {
+		inventory.failed["Secrets"] = err
+	}
+	if pvcs, err := c.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		inventory.pvcs = pvcs.Items
+	} else {
+		inventory.failed["PersistentVolumeClaims"] = err
+	}
+	if replicaSets, err := c.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		inventory.replicaSets = replicaSets.Items
+	} else {
+		inventory.failed["ReplicaSets"] = err
+	}
+	if deployments, err := c.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		inventory.deployments = deployments.Items
+	} else {
+		inventory.failed["Deployments"] = err
+	}
+	if ingresses, err := c.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		inventory.ingresses = ingresses.Items
+	} else {
+		inventory.failed["Ingresses"] = err
+	}
+	if serviceAccounts, err := c.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		inventory.serviceAccounts = serviceAccounts.Items
+	} else {
+		inventory.failed["ServiceAccounts"] = err
+	}
+	pods, err := c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range pods.Items {
+		inventory.podSpecs = append(inventory.podSpecs, podSpecRef{pods.Items[i].Namespace, &pods.Items[i].Spec, true})
+	}
+	for i := range inventory.deployments {
+		inventory.podSpecs = append(inventory.podSpecs, podSpecRef{inventory.deployments[i].Namespace, &inventory.deployments[i].Spec.Template.Spec, false})
+	}
+	if statefulSets, err := c.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range statefulSets.Items {
+			inventory.podSpecs = append(inventory.podSpecs, podSpecRef{statefulSets.Items[i].Namespace, &statefulSets.Items[i].Spec.Template.Spec, false})
+		}
+	} else {
+		inventory.failed["StatefulSets"] = err
+	}
+	if daemonSets, err := c.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range daemonSets.Items {
+			inventory.podSpecs = append(inventory.podSpecs, podSpecRef{daemonSets.Items[i].Namespace, &daemonSets.Items[i].Spec.Template.Spec, false})
+		}
+	} else {
+		inventory.failed["DaemonSets"] = err
+	}
+	if cronJobs, err := c.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range cronJobs.Items {
+			inventory.podSpecs = append(inventory.podSpecs, podSpecRef{cronJobs.Items[i].Namespace, &cronJobs.Items[i].Spec.JobTemplate.Spec.Template.Spec, false})
+		}
+	} else {
+		inventory.failed["CronJobs"] = err
+	}
+	for i := range inventory.replicaSets {
+		inventory.podSpecs = append(inventory.podSpecs, podSpecRef{inventory.replicaSets[i].Namespace, &inventory.replicaSets[i].Spec.Template.Spec, false})
+	}
+	orphans, skipped := findOrphans(inventory, time.Now().Add(-unusedFor))
+	return orphans, skipped, nil
+}
+
+func findOrphans(inventory *orphansInventory, unusedSince time.Time) (orphans []OrphanedResource, skipped []string) {
+	orphans = make([]OrphanedResource, 0)
+	// skip returns true if any of the lists the check depends on failed, recording the check as skipped
+	skip := func(check string, kinds ...string) bool {
+		for _, kind := range kinds {
+			if err, failed := inventory.failed[kind]; failed {
+				skipped = append(skipped, fmt.Sprintf("%s: failed to list %s: %v", check, kind, err))
+				return true
+			}
+		}
+		return false
+	}
+	services, configMaps, secrets, pvcs, replicaSets := inventory.services, inventory.configMaps, inventory.secrets, inventory.pvcs, inventory.replicaSets
+	if skip("Services without ready endpoints", "EndpointSlices") {
+		services = nil
+	}
+	if skip("ConfigMaps not referenced", append([]string{"ConfigMaps"}, workloadKinds...)...) {
+		configMaps = nil
+	}
+	if skip("Secrets not referenced", append([]string{"Secrets", "Ingresses", "ServiceAccounts"}, workloadKinds...)...) {
+		secrets = nil
+	}
+	if skip("PersistentVolumeClaims not mounted", "PersistentVolumeClaims") {
+		pvcs = nil
+	}
+	if skip("ReplicaSets left behind by deleted Deployments", "ReplicaSets", "Deployments") {
+		replicaSets = nil
+	}
+	add := func(kind string, obj metav1.Object, reason string) {
+		orphans = append(orphans, OrphanedResource{
+			Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), Reason: reason,
+			Age: output.HumanAge(obj.GetCreationTimestamp().Time),
+		})
+	}
+
+	readyEndpoints := map[string]int{}
+	for _, slice := range inventory.endpointSlices {
+		service := slice.Labels[discoveryv1.LabelServiceName]
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				readyEndpoints[slice.Namespace+"/"+service]++
+			}
+		}
+	}
+	for i := range services {
+		service := &services[i]
+		if service.Spec.Type == v1.ServiceTypeExternalName {
+			continue
+		}
+		if readyEndpoints[service.Namespace+"/"+service.Name] == 0 {
+			reason := "Service has no ready endpoints"
+			if len(service.Spec.Selector) > 0 {
+				reason = fmt.Sprintf("Service has no ready endpoints, no ready Pods match its selector %v", service.Spec.Selector)
+			}
+			add("Service", service, reason)
+		}
+	}
+
+	referencedConfigMaps, referencedSecrets, mountedPvcs := referencedResources(inventory)
+	for i := range configMaps {
+		configMap := &configMaps[i]
+		// Published by Kubernetes in every namespace
+		if configMap.Name == "kube-root-ca.crt" || configMap.Name == "openshift-service-ca.crt" {
+			continue
+		}
+		if !referencedConfigMaps[configMap.Namespace+"/"+configMap.Name] {
+			add("ConfigMap", configMap, "ConfigMap is not referenced by any Pod or workload")
+		}
+	}
+	for i := range secrets {
+		secret := &secrets[i]
+		// Secrets managed by Kubernetes or tools (Helm releases, bootstrap tokens) are consumed by means other than Pods
+		switch secret.Type {
+		case v1.SecretTypeServiceAccountToken, v1.SecretTypeBootstrapToken, "helm.sh/release.v1":
+			continue
+		}
+		if !referencedSecrets[secret.Namespace+"/"+secret.Name] {
+			add("Secret", secret, "Secret is not referenced by any Pod, workload, Ingress, or ServiceAccount")
+		}
+	}
+	for i := range pvcs {
+		pvc := &pvcs[i]
+		if !mountedPvcs[pvc.Namespace+"/"+pvc.Name] && pvc.CreationTimestamp.Time.Before(unusedSince) {
+			add("PersistentVolumeClaim", pvc, "PersistentVolumeClaim is not mounted by any Pod")
+		}
+	}
+
+	deployments := map[string]bool{}
+	for _, deployment := range inventory.deployments {
+		deployments[deployment.Namespace+"/"+deployment.Name] = true
+	}
+	for i := range replicaSets {
+		replicaSet := &replicaSets[i]
+		if (replicaSet.Spec.Replicas != nil && *replicaSet.Spec.Replicas > 0) || replicaSet.Status.Replicas > 0 {
+			continue
+		}
+		owner := metav1.GetControllerOf(replicaSet)
+		switch {
+		case owner == nil:
+			add("ReplicaSet", replicaSet, "ReplicaSet has zero replicas and no owner")
+		case owner.Kind == "Deployment" && !deployments[replicaSet.Namespace+"/"+owner.Name]:
+			add("ReplicaSet", replicaSet, fmt.Sprintf("ReplicaSet has zero replicas and its Deployment %s no longer exists", owner.Name))
+		}
+	}
+
+	slices.SortFunc(orphans, func(a, b OrphanedResource) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	return orphans, skipped
+}
+
+// referencedResources returns the sets (namespace/name) of the ConfigMaps, Secrets, and PersistentVolumeClaims referenced in the inventory
+func referencedResources(inventory *orphansInventory) (configMaps, secrets, pvcs map[string]bool) {
+	configMaps, secrets, pvcs = map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for _, ref := range inventory.podSpecs {
+		key := func(name string) string { return ref.namespace + "/" + name }
+		for _, secret := range ref.spec.ImagePullSecrets {
+			secrets[key(secret.Name)] = true
+		}
+		for _, volume := range ref.spec.Volumes {
+			switch {
+			case volume.ConfigMap != nil:
+				configMaps[key(volume.ConfigMap.Name)] = true
+			case volume.Secret != nil:
+				secrets[key(volume.Secret.SecretName)] = true
+			case volume.PersistentVolumeClaim != nil && ref.pod:
+				pvcs[key(volume.PersistentVolumeClaim.ClaimName)] = true
+			case volume.Projected != nil:
+				for _, source := range volume.Projected.Sources {
+					if source.ConfigMap != nil {
+						configMaps[key(source.ConfigMap.Name)] = true
+					}
+					if source.Secret != nil {
+						secrets[key(source.Secret.Name)] = true
+					}
+				}
+			}
+		}
+		for _, container := range slices.Concat(ref.spec.InitContainers, ref.spec.Containers) {
+			for _, envFrom := range container.EnvFrom {
+				if envFrom.ConfigMapRef != nil {
+					configMaps[key(envFrom.ConfigMapRef.Name)] = true
+				}
+				if envFrom.SecretRef != nil {
+					secrets[key(envFrom.SecretRef.Name)] = true
+				}
+			}
+			for _, env := range container.Env {
+				if env.ValueFrom == nil {
+					continue
+				}
+				if env.ValueFrom.ConfigMapKeyRef != nil {
+					configMaps[key(env.ValueFrom.ConfigMapKeyRef.Name)] = true
+				}
+				if env.ValueFrom.SecretKeyRef != nil {
+					secrets[key(env.ValueFrom.SecretKeyRef.Name)] = true
+				}
+			}
+		}
+	}
+	for _, ingress := range inventory.ingresses {
+		for _, tls := range ingress.Spec.TLS {
+			secrets[ingress.Namespace+"/"+tls.SecretName] = true
+		}
+	}
+	for _, serviceAccount := range inventory.serviceAccounts {
+		for _, secret := range serviceAccount.Secrets {
+			secrets[serviceAccount.Namespace+"/"+secret.Name] = true
+		}
+		for _, secret := range serviceAccount.ImagePullSecrets {
+			secrets[serviceAccount.Namespace+"/"+secret.Name] = true
+		}
+	}
+	return configMaps, secrets, pvcs
+}
@@ -0,0 +1,117 @@
+package kubernetes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+type OrphansTestSuite struct {
+	suite.Suite
+}
+
+func objectMeta(name string, age time.Duration) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(time.Now().Add(-age))}
+}
+
+func (s *OrphansTestSuite) TestFindOrphans() {
+	week := 7 * 24 * time.Hour
+	inventory := &orphansInventory{
+		services: []v1.Service{
+			{ObjectMeta: objectMeta("backed", time.Hour), Spec: v1.ServiceSpec{Selector: map[string]string{"app": "web"}}},
+			{ObjectMeta: objectMeta("unbacked", time.Hour), Spec: v1.ServiceSpec{Selector: map[string]string{"app": "gone"}}},
+			{ObjectMeta: objectMeta("external", time.Hour), Spec: v1.ServiceSpec{Type: v1.ServiceTypeExternalName}},
+		},
+		endpointSlices: []discoveryv1.EndpointSlice{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Labels: map[string]string{discoveryv1.LabelServiceName: "backed"}},
+			Endpoints:  []discoveryv1.Endpoint{{Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)}}},
+		}},
+		configMaps: []v1.ConfigMap{{ObjectMeta: objectMeta("used-env", time.Hour)}, {ObjectMeta: objectMeta("unused", time.Hour)}, {ObjectMeta: objectMeta("kube-root-ca.crt", time.Hour)}},
+		secrets: []v1.Secret{
+			{ObjectMeta: objectMeta("used-volume", time.Hour)},
+			{ObjectMeta: objectMeta("tls", time.Hour)},
+			{ObjectMeta: objectMeta("unused", time.Hour)},
+			{ObjectMeta: objectMeta("sh.helm.release.v1.app.v1", time.Hour), Type: "helm.sh/release.v1"},
+		},
+		pvcs: []v1.PersistentVolumeClaim{{ObjectMeta: objectMeta("mounted", 2*week)}, {ObjectMeta: objectMeta("recent", time.Hour)}, {ObjectMeta: objectMeta("stale", 2*week)}},
+		replicaSets: []appsv1.ReplicaSet{
+			{ObjectMeta: objectMeta("web-1", week), Spec: appsv1.ReplicaSetSpec{Replicas: ptr.To(int32(0))}},
+			{ObjectMeta: objectMeta("current-1", week), Spec: appsv1.ReplicaSetSpec{Replicas: ptr.To(int32(0))}},
+			{ObjectMeta: objectMeta("current-2", week), Spec: appsv1.ReplicaSetSpec{Replicas: ptr.To(int32(1))}},
+		},
+		deployments: []appsv1.Deployment{{ObjectMeta: objectMeta("current", week)}},
+		podSpecs: []podSpecRef{{namespace: "default", pod: true, spec: &v1.PodSpec{
+			Containers: []v1.Container{{Env: []v1.EnvVar{{ValueFrom: &v1.EnvVarSource{ConfigMapKeyRef: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "used-env"}}}}}}},
+			Volumes: []v1.Volume{
+				{VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "used-volume"}}},
+				{VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "mounted"}}},
+			},
+		}}},
+		serviceAccounts: []v1.ServiceAccount{{ObjectMeta: objectMeta("default", week), ImagePullSecrets: []v1.LocalObjectReference{{Name: "tls"}}}},
+	}
+	controller := true
+	inventory.replicaSets[0].OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: &controller}}
+	inventory.replicaSets[1].OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: "current", Controller: &controller}}
+	orphans, skipped := findOrphans(inventory, time.Now().Add(-week))
+	s.Empty(skipped)
+	var found []string
+	for _, orphan := range orphans {
+		found = append(found, orphan.Kind+"/"+orphan.Name)
+	}
+	s.Equal([]string{
+		"ConfigMap/unused",
+		"PersistentVolumeClaim/stale",
+		"ReplicaSet/web-1",
+		"Secret/unused",
+		"Service/unbacked",
+	}, found)
+	s.Equal("ReplicaSet has zero replicas and its Deployment web no longer exists", orphans[2].Reason)
+}
+
+func (s *OrphansTestSuite) TestForbiddenDeployments() {
+	controller := true
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: objectMeta("web-1", time.Hour), Spec: appsv1.ReplicaSetSpec{Replicas: ptr.To(int32(0))}}
+	replicaSet.OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: &controller}}
+	clientset := fake.NewClientset(replicaSet,
+		&v1.ConfigMap{ObjectMeta: objectMeta("config", time.Hour)},
+		&v1.ConfigMap{ObjectMeta: objectMeta("unused", time.Hour)},
+	)
+	clientset.PrependReactor("list", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "", errors.New("RBAC denied"))
+	})
+	orphans, skipped, err := NewCore(&Kubernetes{Interface: clientset}).Orphans(s.T().Context(), "default", time.Hour)
+	s.Require().NoError(err)
+	s.Run("doesn't report the ReplicaSets of the Deployments it can't list", func() {
+		for _, orphan := range orphans {
+			s.NotEqual("ReplicaSet", orphan.Kind)
+		}
+	})
+	s.Run("doesn't report the ConfigMaps the Deployments might reference", func() {
+		for _, orphan := range orphans {
+			s.NotEqual("ConfigMap", orphan.Kind)
+		}
+	})
+	s.Run("reports the skipped checks", func() {
+		s.Require().Len(skipped, 3)
+		s.True(strings.HasPrefix(skipped[0], "ConfigMaps not referenced: failed to list Deployments: "), skipped[0])
+		s.True(strings.HasPrefix(skipped[1], "Secrets not referenced: failed to list Deployments: "), skipped[1])
+		s.True(strings.HasPrefix(skipped[2], "ReplicaSets left behind by deleted Deployments: failed to list Deployments: "), skipped[2])
+	})
+}
+
+func TestOrphans(t *testing.T) {
+	suite.Run(t, new(OrphansTestSuite))
+}
@@ -0,0 +1,253 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+// ownersMaxDepth is the maximum number of levels of owners (or dependents) walked from the object
+const ownersMaxDepth = 10
+
+// ownersSkippedResources are the resources whose objects are never owned, not listed when looking for the dependents
+var ownersSkippedResources = []schema.GroupResource{
+	{Resource: "events"}, {Group: "events.k8s.io", Resource: "events"},
+	{Group: "metrics.k8s.io", Resource: "pods"}, {Group: "metrics.k8s.io", Resource: "nodes"},
+}
+
+// OwnerNode is an object of an ownership tree, with its owners (walking up the ownerReferences) or its dependents
+// (the objects whose ownerReferences point to it)
+type OwnerNode struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	// Controller is true if the owner is the controller managing the object that references it
+	Controller bool `json:"controller,omitempty"`
+	// ManagedBy is the tool that deployed the object without being one of its owners (e.g. a Helm release or a Flux
+	// Kustomization), which the garbage collector ignores
+	ManagedBy string `json:"managedBy,omitempty"`
+	// Issue is the problem found with the ownerReference of the owner, e.g. an owner that doesn't exist anymore
+	Issue      string      `json:"issue,omitempty"`
+	Owners     []OwnerNode `json:"owners,omitempty"`
+	Dependents []OwnerNode `json:"dependents,omitempty"`
+}
+
+// OwnersTree is the ownership tree of an object
+type OwnersTree struct {
+	// Roots are the objects at the top of the ownership chains of the object, none if all its owners are gone
+	Roots []string `json:"roots,omitempty"`
+	// Object is the object with its owners up to the roots, and its dependents if requested
+	Object OwnerNode `json:"object"`
+	// Skipped are the resources that could not be listed when looking for the dependents, and the reason
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// OwnersTree walks the ownerReferences of the object up to its roots, the objects that aren't owned (and that the
+// garbage collector won't delete), and optionally down to all the objects that depend on it.
+func (c *Core) OwnersTree(ctx context.Context, gvk *schema.GroupVersionKind, namespace, name string, dependents bool) (*OwnersTree, error) {
+	obj, err := c.ResourcesGet(ctx, gvk, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	ret := &OwnersTree{Object: ownerNodeOf(obj)}
+	ret.Object.Owners = c.ownersOf(ctx, obj, map[types.UID]bool{obj.GetUID(): true}, 1)
+	ret.Roots = ownerRoots(&ret.Object)
+	if dependents {
+		index, skipped, err := c.ownedObjects(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+		ret.Object.Dependents = dependentsOf(index, obj.GetUID(), map[types.UID]bool{obj.GetUID(): true}, 1)
+		ret.Skipped = skipped
+	}
+	return ret, nil
+}
+
+// ownersOf returns the owners of the object, and theirs, the objects already in the chain (ownership cycles) are not walked again
+func (c *Core) ownersOf(ctx context.Context, obj *unstructured.Unstructured, visited map[types.UID]bool, depth int) []OwnerNode {
+	var ret []OwnerNode
+	for _, ref := range obj.GetOwnerReferences() {
+		node := OwnerNode{APIVersion: ref.APIVersion, Kind: ref.Kind, Name: ref.Name, Controller: ptr.Deref(ref.Controller, false)}
+		owner, err := c.ownerOf(ctx, obj, ref)
+		switch {
+		case apierrors.IsNotFound(err):
+			node.Issue = "owner not found, the garbage collector deletes the objects whose owners are all gone"
+		case err != nil:
+			node.Issue = fmt.Sprintf("failed to get owner: %v", err)
+		case owner.GetUID() != ref.UID:
+			node.Namespace = owner.GetNamespace()
+			node.Issue = "owner was recreated (uid mismatch), the garbage collector treats the ownerReference as pointing to a deleted owner"
+		default:
+			node = ownerNodeOf(owner)
+			node.Controller = ptr.Deref(ref.Controller, false)
+			if visited[owner.GetUID()] {
+				node.Issue = "ownership cycle"
+			} else if depth < ownersMaxDepth {
+				visited[owner.GetUID()] = true
+				node.Owners = c.ownersOf(ctx, owner, visited, depth+1)
+				delete(visited, owner.GetUID())
+			}
+		}
+		ret = append(ret, node)
+	}
+	return ret
+}
+
+// ownerOf gets the owner of the ownerReference, a namespaced owner is in the namespace of the object
+func (c *Core) ownerOf(ctx context.Context, obj *unstructured.Unstructured, ref metav1.OwnerReference) (*unstructured.Unstructured, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := c.RESTMapper().RESTMapping(gv.WithKind(ref.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		return nil, err
+	}
+	namespace := ""
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		namespace = obj.GetNamespace()
+	}
+	return c.DynamicClient().Resource(mapping.Resource).Namespace(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+}
+
+// ownedObjects lists the objects that can depend on the object (in its namespace, or in the whole cluster for a
+// cluster-scoped object), indexed by the UID of their owners
+func (c *Core) ownedObjects(ctx context.Context, obj *unstructured.Unstructured) (map[types.UID][]unstructured.Unstructured, []string, error) {
+	discovery := c.DiscoveryClient().ServerPreferredResources
+	if obj.GetNamespace() != "" {
+		discovery = c.DiscoveryClient().ServerPreferredNamespacedResources
+	}
+	resourceLists, err := discovery()
+	// The discovery of some groups might fail (e.g. unavailable aggregated APIs), the rest can still be listed
+	if len(resourceLists) == 0 && err != nil {
+		return nil, nil, err
+	}
+	type ownedResource struct {
+		gvr  schema.GroupVersionResource
+		kind string
+	}
+	var resources []ownedResource
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, apiResource := range resourceList.APIResources {
+			gvr := gv.WithResource(apiResource.Name)
+			if strings.Contains(apiResource.Name, "/") || !slices.Contains(apiResource.Verbs, "list") || slices.Contains(ownersSkippedResources, gvr.GroupResource()) {
+				continue
+			}
+			resources = append(resources, ownedResource{gvr: gvr, kind: apiResource.Kind})
+		}
+	}
+	lists, errs := FanOut(ctx, DefaultFanOutConcurrency, resources, func(ctx context.Context, resource ownedResource) (*unstructured.UnstructuredList, error) {
+		return c.DynamicClient().Resource(resource.gvr).Namespace(obj.GetNamespace()).List(ctx, metav1.ListOptions{})
+	})
+	index := make(map[types.UID][]unstructured.Unstructured)
+	listed := make(map[types.UID]bool)
+	var skipped []string
+	for i, resource := range resources {
+		if err := errs[i]; err != nil {
+			reason := err.Error()
+			if apierrors.IsForbidden(err) {
+				reason = "forbidden"
+			}
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", resource.gvr.GroupResource(), reason))
+			continue
+		}
+		for _, item := range lists[i].Items {
+			if listed[item.GetUID()] {
+				continue
+			}
+			listed[item.GetUID()] = true
+			if item.GetKind() == "" {
+				item.SetAPIVersion(resource.gvr.GroupVersion().String())
+				item.SetKind(resource.kind)
+			}
+			for _, ref := range item.GetOwnerReferences() {
+				index[ref.UID] = append(index[ref.UID], item)
+			}
+		}
+	}
+	return index, skipped, nil
+}
+
+// dependentsOf returns the objects owned by the owner, and theirs, sorted by kind and name
+func dependentsOf(index map[types.UID][]unstructured.Unstructured, owner types.UID, visited map[types.UID]bool, depth int) []OwnerNode {
+	var ret []OwnerNode
+	for _, dependent := range index[owner] {
+		node := ownerNodeOf(&dependent)
+		if visited[dependent.GetUID()] {
+			node.Issue = "ownership cycle"
+		} else if depth < ownersMaxDepth {
+			visited[dependent.GetUID()] = true
+			node.Dependents = dependentsOf(index, dependent.GetUID(), visited, depth+1)
+			delete(visited, dependent.GetUID())
+		}
+		ret = append(ret, node)
+	}
+	slices.SortFunc(ret, func(a, b OwnerNode) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	return ret
+}
+
+// ownerRoots returns the objects without owners at the top of the ownership chains of the node
+func ownerRoots(node *OwnerNode) []string {
+	if len(node.Owners) == 0 {
+		root := node.Kind + " " + strings.TrimPrefix(node.Namespace+"/"+node.Name, "/")
+		if node.ManagedBy != "" {
+			root += " (managed by " + node.ManagedBy + ")"
+		}
+		return []string{root}
+	}
+	var ret []string
+	for i := range node.Owners {
+		// the missing owners are not roots, their dependents are deleted by the garbage collector
+		if node.Owners[i].Issue == "" || len(node.Owners[i].Owners) > 0 {
+			ret = append(ret, ownerRoots(&node.Owners[i])...)
+		}
+	}
+	return slices.Compact(ret)
+}
+
+func ownerNodeOf(obj *unstructured.Unstructured) OwnerNode {
+	return OwnerNode{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+		ManagedBy:  managedBy(obj),
+	}
+}
+
+// managedBy returns the deployment tool (Flux, Argo CD, Helm) that created the object, tracked with labels or
+// annotations instead of ownerReferences
+func managedBy(obj *unstructured.Unstructured) string {
+	labels, annotations := obj.GetLabels(), obj.GetAnnotations()
+	switch {
+	case labels["helm.toolkit.fluxcd.io/name"] != "":
+		return "Flux HelmRelease " + strings.TrimPrefix(labels["helm.toolkit.fluxcd.io/namespace"]+"/"+labels["helm.toolkit.fluxcd.io/name"], "/")
+	case labels["kustomize.toolkit.fluxcd.io/name"] != "":
+		return "Flux Kustomization " + strings.TrimPrefix(labels["kustomize.toolkit.fluxcd.io/namespace"]+"/"+labels["kustomize.toolkit.fluxcd.io/name"], "/")
+	case annotations["argocd.argoproj.io/tracking-id"] != "":
+		application, _, _ := strings.Cut(annotations["argocd.argoproj.io/tracking-id"], ":")
+		return "Argo CD Application " + application
+	case labels["argocd.argoproj.io/instance"] != "":
+		return "Argo CD Application " + labels["argocd.argoproj.io/instance"]
+	case annotations["meta.helm.sh/release-name"] != "":
+		return "Helm release " + strings.TrimPrefix(annotations["meta.helm.sh/release-namespace"]+"/"+annotations["meta.helm.sh/release-name"], "/")
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OwnersTestSuite struct {
+	suite.Suite
+}
+
+func ownedObject(kind, name string, uid types.UID, owners ...types.UID) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind(kind)
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetUID(uid)
+	var refs []metav1.OwnerReference
+	for _, owner := range owners {
+		refs = append(refs, metav1.OwnerReference{UID: owner})
+	}
+	obj.SetOwnerReferences(refs)
+	return obj
+}
+
+func (s *OwnersTestSuite) TestManagedBy() {
+	s.Run("detects Flux HelmRelease before the Helm release it creates", func() {
+		obj := ownedObject("Deployment", "web", "1")
+		obj.SetLabels(map[string]string{"helm.toolkit.fluxcd.io/name": "web", "helm.toolkit.fluxcd.io/namespace": "flux-system"})
+		obj.SetAnnotations(map[string]string{"meta.helm.sh/release-name": "web", "meta.helm.sh/release-namespace": "default"})
+		s.Equal("Flux HelmRelease flux-system/web", managedBy(&obj))
+	})
+	s.Run("detects Flux Kustomization", func() {
+		obj := ownedObject("Deployment", "web", "1")
+		obj.SetLabels(map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps", "kustomize.toolkit.fluxcd.io/namespace": "flux-system"})
+		s.Equal("Flux Kustomization flux-system/apps", managedBy(&obj))
+	})
+	s.Run("detects Argo CD Application from tracking id", func() {
+		obj := ownedObject("Deployment", "web", "1")
+		obj.SetAnnotations(map[string]string{"argocd.argoproj.io/tracking-id": "guestbook:apps/Deployment:default/web"})
+		s.Equal("Argo CD Application guestbook", managedBy(&obj))
+	})
+	s.Run("detects Argo CD Application from instance label", func() {
+		obj := ownedObject("Deployment", "web", "1")
+		obj.SetLabels(map[string]string{"argocd.argoproj.io/instance": "guestbook"})
+		s.Equal("Argo CD Application guestbook", managedBy(&obj))
+	})
+	s.Run("detects Helm release", func() {
+		obj := ownedObject("Deployment", "web", "1")
+		obj.SetAnnotations(map[string]string{"meta.helm.sh/release-name": "web", "meta.helm.sh/release-namespace": "default"})
+		s.Equal("Helm release default/web", managedBy(&obj))
+	})
+	s.Run("returns empty for unmanaged objects", func() {
+		obj := ownedObject("Deployment", "web", "1")
+		s.Empty(managedBy(&obj))
+	})
+}
+
+func (s *OwnersTestSuite) TestOwnerRoots() {
+	s.Run("object without owners is its own root", func() {
+		s.Equal([]string{"Pod default/web"}, ownerRoots(&OwnerNode{Kind: "Pod", Namespace: "default", Name: "web"}))
+	})
+	s.Run("returns the top of the chain with its manager", func() {
+		node := &OwnerNode{Kind: "Pod", Namespace: "default", Name: "web-1-a", Owners: []OwnerNode{
+			{Kind: "ReplicaSet", Namespace: "default", Name: "web-1", Owners: []OwnerNode{
+				{Kind: "Deployment", Namespace: "default", Name: "web", ManagedBy: "Helm release default/web"},
+			}},
+		}}
+		s.Equal([]string{"Deployment default/web (managed by Helm release default/web)"}, ownerRoots(node))
+	})
+	s.Run("ignores missing owners", func() {
+		node := &OwnerNode{Kind: "Pod", Namespace: "default", Name: "web-1-a", Owners: []OwnerNode{
+			{Kind: "ReplicaSet", Name: "web-1", Issue: "owner not found"},
+		}}
+		s.Empty(ownerRoots(node))
+	})
+	s.Run("cluster scoped root has no namespace", func() {
+		s.Equal([]string{"Node worker"}, ownerRoots(&OwnerNode{Kind: "Node", Name: "worker"}))
+	})
+}
+
+func (s *OwnersTestSuite) TestDependentsOf() {
+	index := map[types.UID][]unstructured.Unstructured{
+		"deployment": {ownedObject("ReplicaSet", "web-2", "rs-2", "deployment"), ownedObject("ReplicaSet", "web-1", "rs-1", "deployment")},
+		"rs-1":       {ownedObject("Pod", "web-1-a", "pod", "rs-1")},
+		"pod":        {ownedObject("ReplicaSet", "web-1", "rs-1", "pod")},
+	}
+	dependents := dependentsOf(index, "deployment", map[types.UID]bool{"deployment": true}, 1)
+	s.Run("sorts dependents by kind and name", func() {
+		s.Require().Len(dependents, 2)
+		s.Equal("web-1", dependents[0].Name)
+		s.Equal("web-2", dependents[1].Name)
+	})
+	s.Run("walks dependents recursively", func() {
+		s.Require().Len(dependents[0].Dependents, 1)
+		s.Equal("Pod", dependents[0].Dependents[0].Kind)
+	})
+	s.Run("stops at ownership cycles", func() {
+		s.Require().Len(dependents[0].Dependents[0].Dependents, 1)
+		cycle := dependents[0].Dependents[0].Dependents[0]
+		s.Equal("ownership cycle", cycle.Issue)
+		s.Empty(cycle.Dependents)
+	})
+}
+
+func TestOwners(t *testing.T) {
+	suite.Run(t, new(OwnersTestSuite))
+}
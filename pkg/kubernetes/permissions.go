@@ -0,0 +1,147 @@
+package kubernetes
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	authv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+// MissingPermissions returns the permissions denied to the user among the provided ones, each checked once with a
+// SelfSubjectAccessReview
+func (c *Core) MissingPermissions(ctx context.Context, permissions []api.Permission) ([]api.Permission, error) {
+	var missing []api.Permission
+	checked := make(map[api.Permission]bool, len(permissions))
+	for _, permission := range permissions {
+		if checked[permission] {
+			continue
+		}
+		checked[permission] = true
+		response, err := c.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authv1.SelfSubjectAccessReview{
+			Spec: authv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &authv1.ResourceAttributes{
+				Namespace:   permission.Namespace,
+				Verb:        permission.Verb,
+				Group:       permission.Group,
+				Resource:    permission.Resource,
+				Subresource: permission.Subresource,
+			}},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if !response.Status.Allowed {
+			missing = append(missing, permission)
+		}
+	}
+	return missing, nil
+}
+
+// ResourcePermission returns the permission to use the verb on the resources of the kind, in the namespace (the
+// configured one if not provided) for the namespaced resources
+func (c *Core) ResourcePermission(gvk *schema.GroupVersionKind, namespace, verb, subresource string) (api.Permission, error) {
+	gvr, err := c.resourceFor(gvk)
+	if err != nil {
+		return api.Permission{}, err
+	}
+	namespaced, err := c.isNamespaced(gvk)
+	if err != nil {
+		return api.Permission{}, err
+	}
+	if namespaced {
+		namespace = c.NamespaceOrDefault(namespace)
+	} else {
+		namespace = ""
+	}
+	return api.Permission{Verb: verb, Group: gvr.Group, Resource: gvr.Resource, Subresource: subresource, Namespace: namespace}, nil
+}
+
+// ManifestPermissions returns the permissions required to create or update the resources of the manifest with
+// server-side apply: patch for all of them, and create for the ones that don't exist yet. The resources of the kinds
+// unknown to the cluster (e.g. defined by a CRD of the same manifest) are left out.
+func (c *Core) ManifestPermissions(ctx context.Context, resource string) ([]api.Permission, error) {
+	resources, err := parseResources(resource)
+	if err != nil {
+		return nil, err
+	}
+	var permissions []api.Permission
+	for _, obj := range resources {
+		gvk := obj.GroupVersionKind()
+		permission, err := c.ResourcePermission(&gvk, obj.GetNamespace(), "patch", "")
+		if meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+		gvr := schema.GroupVersionResource{Group: permission.Group, Version: gvk.Version, Resource: permission.Resource}
+		if _, err = c.DynamicClient().Resource(gvr).Namespace(permission.Namespace).Get(ctx, obj.GetName(), metav1.GetOptions{}); apierrors.IsNotFound(err) {
+			permission.Verb = "create"
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions, nil
+}
+
+// PermissionsRoles returns the RBAC objects with the provided name granting the permissions: a Role per namespace
+// for the namespaced resources and a ClusterRole for the cluster-scoped ones
+func PermissionsRoles(name string, permissions []api.Permission) []runtime.Object {
+	type ruleKey struct{ group, resource string }
+	rulesByNamespace := make(map[string]map[ruleKey][]string)
+	for _, permission := range permissions {
+		resource := permission.Resource
+		if permission.Subresource != "" {
+			resource += "/" + permission.Subresource
+		}
+		if rulesByNamespace[permission.Namespace] == nil {
+			rulesByNamespace[permission.Namespace] = make(map[ruleKey][]string)
+		}
+		key := ruleKey{permission.Group, resource}
+		if !slices.Contains(rulesByNamespace[permission.Namespace][key], permission.Verb) {
+			rulesByNamespace[permission.Namespace][key] = append(rulesByNamespace[permission.Namespace][key], permission.Verb)
+		}
+	}
+	namespaces := make([]string, 0, len(rulesByNamespace))
+	for namespace := range rulesByNamespace {
+		namespaces = append(namespaces, namespace)
+	}
+	slices.Sort(namespaces)
+	var ret []runtime.Object
+	for _, namespace := range namespaces {
+		keys := make([]ruleKey, 0, len(rulesByNamespace[namespace]))
+		for key := range rulesByNamespace[namespace] {
+			keys = append(keys, key)
+		}
+		slices.SortFunc(keys, func(a, b ruleKey) int {
+			return strings.Compare(a.group+"/"+a.resource, b.group+"/"+b.resource)
+		})
+		rules := make([]rbacv1.PolicyRule, 0, len(keys))
+		for _, key := range keys {
+			verbs := rulesByNamespace[namespace][key]
+			slices.Sort(verbs)
+			rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{key.group}, Resources: []string{key.resource}, Verbs: verbs})
+		}
+		if namespace == "" {
+			ret = append(ret, &rbacv1.ClusterRole{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Rules:      rules,
+			})
+			continue
+		}
+		ret = append(ret, &rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Rules:      rules,
+		})
+	}
+	return ret
+}
@@ -0,0 +1,53 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+type PermissionsTestSuite struct {
+	suite.Suite
+}
+
+func (s *PermissionsTestSuite) TestPermissionsRoles() {
+	roles := PermissionsRoles("kubernetes-mcp-server-nodes-debug", []api.Permission{
+		{Verb: "get", Resource: "nodes"},
+		{Verb: "delete", Resource: "pods", Namespace: "debug"},
+		{Verb: "create", Resource: "pods", Namespace: "debug"},
+		{Verb: "create", Resource: "pods", Namespace: "debug"},
+		{Verb: "get", Resource: "pods", Subresource: "log", Namespace: "debug"},
+		{Verb: "patch", Group: "apps", Resource: "deployments", Namespace: "debug"},
+		{Verb: "delete", Resource: "pods", Namespace: "shop"},
+	})
+	s.Require().Len(roles, 3)
+	s.Run("returns a ClusterRole for the cluster-scoped resources", func() {
+		clusterRole, ok := roles[0].(*rbacv1.ClusterRole)
+		s.Require().True(ok, "expected a ClusterRole, got %T", roles[0])
+		s.Equal("kubernetes-mcp-server-nodes-debug", clusterRole.Name)
+		s.Equal([]rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get"}}}, clusterRole.Rules)
+	})
+	s.Run("returns a Role per namespace with a rule per resource", func() {
+		debug, ok := roles[1].(*rbacv1.Role)
+		s.Require().True(ok, "expected a Role, got %T", roles[1])
+		s.Equal("rbac.authorization.k8s.io/v1", debug.APIVersion)
+		s.Equal("Role", debug.Kind)
+		s.Equal("debug", debug.Namespace)
+		s.Equal([]rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"create", "delete"}},
+			{APIGroups: []string{""}, Resources: []string{"pods/log"}, Verbs: []string{"get"}},
+			{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"patch"}},
+		}, debug.Rules)
+		shop, ok := roles[2].(*rbacv1.Role)
+		s.Require().True(ok, "expected a Role, got %T", roles[2])
+		s.Equal("shop", shop.Namespace)
+		s.Equal([]rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"delete"}}}, shop.Rules)
+	})
+}
+
+func TestPermissions(t *testing.T) {
+	suite.Run(t, new(PermissionsTestSuite))
+}
@@ -3,7 +3,6 @@ package kubernetes
 import (
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
 
 	v1 "k8s.io/api/core/v1"
@@ -21,6 +20,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/sessionbudget"
 	"github.com/containers/kubernetes-mcp-server/pkg/version"
 )
 
@@ -104,12 +104,13 @@ func (c *Core) PodsLog(ctx context.Context, namespace, name, container string, p
 		logOptions.TailLines = ptr.To(DefaultTailLines)
 	}
 
-	req := pods.GetLogs(name, logOptions)
-	res := req.Do(ctx)
-	if res.Error() != nil {
-		return "", res.Error()
+	// the log is buffered within the log bytes budget of the session
+	stream, err := pods.GetLogs(name, logOptions).Stream(ctx)
+	if err != nil {
+		return "", err
 	}
-	rawData, err := res.Raw()
+	defer func() { _ = stream.Close() }()
+	rawData, err := sessionbudget.ReadAll(ctx, stream)
 	if err != nil {
 		return "", err
 	}
@@ -197,10 +198,6 @@ func (c *Core) PodsRun(ctx context.Context, namespace, name, image string, port
 }
 
 func (c *Core) PodsTop(ctx context.Context, options api.PodsTopOptions) (*metrics.PodMetricsList, error) {
-	// TODO, maybe move to mcp Tools setup and omit in case metrics aren't available in the target cluster
-	if !c.supportsGroupVersion(metrics.GroupName + "/" + metricsv1beta1api.SchemeGroupVersion.Version) {
-		return nil, errors.New("metrics API is not available")
-	}
 	namespace := options.Namespace
 	if options.AllNamespaces && namespace == "" {
 		namespace = ""
@@ -209,7 +206,13 @@ func (c *Core) PodsTop(ctx context.Context, options api.PodsTopOptions) (*metric
 	}
 	var err error
 	versionedMetrics := &metricsv1beta1api.PodMetricsList{}
-	if options.Name != "" {
+	if !c.supportsGroupVersion(metrics.GroupName + "/" + metricsv1beta1api.SchemeGroupVersion.Version) {
+		// Minimal clusters may not run the metrics-server, the kubelets report the same usage through their Summary API
+		versionedMetrics, err = c.podsTopFromKubeletSummary(ctx, namespace, options)
+		if err != nil {
+			return nil, fmt.Errorf("metrics API is not available, failed to get the usage from the kubelet Summary API: %w", err)
+		}
+	} else if options.Name != "" {
 		m, err := c.MetricsV1beta1Client().PodMetricses(namespace).Get(ctx, options.Name, metav1.GetOptions{})
 		if err != nil {
 			return nil, fmt.Errorf("failed to get metrics for pod %s/%s: %w", namespace, options.Name, err)
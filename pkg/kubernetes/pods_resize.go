@@ -0,0 +1,218 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// PodResizeCompleted is the status of a resize applied to the container by the kubelet
+	PodResizeCompleted = "Completed"
+	// PodResizeNotObserved is the status of a resize not observed by the kubelet yet
+	PodResizeNotObserved = "NotObserved"
+	PodResizeInProgress  = "InProgress"
+	// PodResizeDeferred is the status of a feasible resize that can't be granted now (e.g. not enough free resources on the node)
+	PodResizeDeferred = "Deferred"
+	// PodResizeInfeasible is the status of a resize that can't be granted on the node (e.g. above its capacity)
+	PodResizeInfeasible = "Infeasible"
+	PodResizeError      = "Error"
+)
+
+// PodResizeResources are the compute resources of a container (CPU and memory) set by PodsResize, by resource name.
+type PodResizeResources struct {
+	Requests map[string]string `json:"requests,omitempty"`
+	Limits   map[string]string `json:"limits,omitempty"`
+}
+
+// PodResize is the result of an in-place resize of the resources of a container of a Pod.
+type PodResize struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	// Previous are the resources of the container before the resize
+	Previous PodResizeResources `json:"previous"`
+	// Desired are the resources of the container after the resize
+	Desired PodResizeResources `json:"desired"`
+	// Actual are the resources applied to the container by the kubelet
+	Actual  PodResizeResources `json:"actual"`
+	Status  string             `json:"status"`
+	Message string             `json:"message,omitempty"`
+	// Restarted is true if the container restarted to apply the resize (RestartContainer resize policy)
+	Restarted bool `json:"restarted,omitempty"`
+}
+
+// PodsResize changes the CPU and memory requests and limits of the container of the Pod in place through the resize
+// subresource (Kubernetes 1.33+), without recreating the Pod, and waits for the kubelet to apply the resize (not
+// waited for if timeout is not positive). The resources not provided are left unchanged.
+func (c *Core) PodsResize(ctx context.Context, namespace, name, container string, resources PodResizeResources, timeout time.Duration) (*PodResize, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	pods := c.CoreV1().Pods(namespace)
+	pod, err := pods.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if container == "" {
+		container = pod.Spec.Containers[0].Name
+	}
+	index := slices.IndexFunc(pod.Spec.Containers, func(c v1.Container) bool { return c.Name == container })
+	if index < 0 {
+		return nil, fmt.Errorf("container %s not found in pod %s", container, name)
+	}
+	desired, err := podResizeRequirements(resources)
+	if err != nil {
+		return nil, err
+	}
+	result := &PodResize{
+		Namespace: namespace,
+		Pod:       name,
+		Container: container,
+		Previous:  podResizeResources(pod.Spec.Containers[index].Resources),
+		Desired:   resources,
+	}
+	restarts := containerRestarts(pod, container)
+	patch, err := json.Marshal(map[string]any{"spec": map[string]any{"containers": []map[string]any{{"name": container, "resources": desired}}}})
+	if err != nil {
+		return nil, err
+	}
+	if pod, err = pods.Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "resize"); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("the cluster doesn't support the in-place resize of the pods (resize subresource of Kubernetes 1.33+): %w", err)
+		}
+		return nil, err
+	}
+	result.Desired = podResizeResources(pod.Spec.Containers[index].Resources)
+	result.Status, result.Message = podResizeStatus(pod, container, pod.Spec.Containers[index].Resources)
+	if timeout > 0 {
+		// The requests use the parent context, the timeout only applies to the wait
+		err = wait.PollUntilContextTimeout(ctx, time.Second, timeout, false, func(context.Context) (bool, error) {
+			current, err := pods.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			pod = current
+			result.Status, result.Message = podResizeStatus(pod, container, pod.Spec.Containers[index].Resources)
+			return result.Status == PodResizeCompleted || result.Status == PodResizeInfeasible || result.Status == PodResizeError, nil
+		})
+		if err != nil && !wait.Interrupted(err) {
+			return nil, err
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container && status.Resources != nil {
+			result.Actual = podResizeResources(*status.Resources)
+		}
+	}
+	result.Restarted = containerRestarts(pod, container) > restarts
+	return result, nil
+}
+
+// podResizeStatus returns the status of the resize of the container of the Pod from the resize conditions of the Pod
+// and the resources applied to the container by the kubelet
+func podResizeStatus(pod *v1.Pod, container string, desired v1.ResourceRequirements) (string, string) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+		switch {
+		case condition.Type == v1.PodResizePending && condition.Reason == v1.PodReasonInfeasible:
+			return PodResizeInfeasible, condition.Message
+		case condition.Type == v1.PodResizePending:
+			return PodResizeDeferred, condition.Message
+		case condition.Type == v1.PodResizeInProgress && condition.Reason == v1.PodReasonError:
+			return PodResizeError, condition.Message
+		case condition.Type == v1.PodResizeInProgress:
+			return PodResizeInProgress, condition.Message
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != container || status.Resources == nil {
+			continue
+		}
+		if resourceListsEqual(desired.Requests, status.Resources.Requests) && resourceListsEqual(desired.Limits, status.Resources.Limits) {
+			return PodResizeCompleted, ""
+		}
+	}
+	return PodResizeNotObserved, ""
+}
+
+// resourceListsEqual returns true if the actual resource list has the quantities of the desired CPU and memory
+func resourceListsEqual(desired, actual v1.ResourceList) bool {
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		quantity, ok := desired[name]
+		if !ok {
+			continue
+		}
+		if current, ok := actual[name]; !ok || current.Cmp(quantity) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func podResizeRequirements(resources PodResizeResources) (v1.ResourceRequirements, error) {
+	ret := v1.ResourceRequirements{}
+	parse := func(values map[string]string) (v1.ResourceList, error) {
+		if len(values) == 0 {
+			return nil, nil
+		}
+		list := v1.ResourceList{}
+		for name, value := range values {
+			if name != string(v1.ResourceCPU) && name != string(v1.ResourceMemory) {
+				return nil, fmt.Errorf("unsupported resource %s, only cpu and memory can be resized", name)
+			}
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s quantity %q: %w", name, value, err)
+			}
+			list[v1.ResourceName(name)] = quantity
+		}
+		return list, nil
+	}
+	var err error
+	if ret.Requests, err = parse(resources.Requests); err != nil {
+		return ret, err
+	}
+	if ret.Limits, err = parse(resources.Limits); err != nil {
+		return ret, err
+	}
+	if len(ret.Requests) == 0 && len(ret.Limits) == 0 {
+		return ret, errors.New("the cpu or memory requests or limits to set are required")
+	}
+	return ret, nil
+}
+
+func podResizeResources(requirements v1.ResourceRequirements) PodResizeResources {
+	list := func(resources v1.ResourceList) map[string]string {
+		ret := make(map[string]string)
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			if quantity, ok := resources[name]; ok {
+				ret[string(name)] = quantity.String()
+			}
+		}
+		if len(ret) == 0 {
+			return nil
+		}
+		return ret
+	}
+	return PodResizeResources{Requests: list(requirements.Requests), Limits: list(requirements.Limits)}
+}
+
+func containerRestarts(pod *v1.Pod, container string) int32 {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container {
+			return status.RestartCount
+		}
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type PodsResizeTestSuite struct {
+	suite.Suite
+}
+
+func (s *PodsResizeTestSuite) TestPodResizeStatus() {
+	desired := v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+		Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+	}
+	pod := func(conditions []v1.PodCondition, actual *v1.ResourceRequirements) *v1.Pod {
+		return &v1.Pod{Status: v1.PodStatus{
+			Conditions:        conditions,
+			ContainerStatuses: []v1.ContainerStatus{{Name: "app", Resources: actual}},
+		}}
+	}
+	s.Run("Completed once the kubelet applied the resources", func() {
+		status, _ := podResizeStatus(pod(nil, &v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1024Mi"), v1.ResourceCPU: resource.MustParse("100m")},
+			Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+		}), "app", desired)
+		s.Equal(PodResizeCompleted, status)
+	})
+	s.Run("NotObserved until the kubelet applies the resources", func() {
+		status, _ := podResizeStatus(pod(nil, &v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("512Mi")},
+			Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+		}), "app", desired)
+		s.Equal(PodResizeNotObserved, status)
+	})
+	s.Run("Infeasible", func() {
+		status, message := podResizeStatus(pod([]v1.PodCondition{{Type: v1.PodResizePending, Status: v1.ConditionTrue, Reason: v1.PodReasonInfeasible,
+			Message: "Node didn't have enough capacity: memory, requested: 1073741824, capacity: 536870912"}}, nil), "app", desired)
+		s.Equal(PodResizeInfeasible, status)
+		s.Equal("Node didn't have enough capacity: memory, requested: 1073741824, capacity: 536870912", message)
+	})
+	s.Run("Deferred", func() {
+		status, _ := podResizeStatus(pod([]v1.PodCondition{{Type: v1.PodResizePending, Status: v1.ConditionTrue, Reason: v1.PodReasonDeferred}}, nil), "app", desired)
+		s.Equal(PodResizeDeferred, status)
+	})
+	s.Run("InProgress", func() {
+		status, _ := podResizeStatus(pod([]v1.PodCondition{{Type: v1.PodResizeInProgress, Status: v1.ConditionTrue}}, nil), "app", desired)
+		s.Equal(PodResizeInProgress, status)
+	})
+	s.Run("Error", func() {
+		status, _ := podResizeStatus(pod([]v1.PodCondition{{Type: v1.PodResizeInProgress, Status: v1.ConditionTrue, Reason: v1.PodReasonError}}, nil), "app", desired)
+		s.Equal(PodResizeError, status)
+	})
+}
+
+func (s *PodsResizeTestSuite) TestPodResizeRequirements() {
+	s.Run("parses the cpu and memory quantities", func() {
+		requirements, err := podResizeRequirements(PodResizeResources{Requests: map[string]string{"cpu": "500m"}, Limits: map[string]string{"memory": "2Gi"}})
+		s.Require().NoError(err)
+		s.Equal("500m", requirements.Requests.Cpu().String())
+		s.Equal("2Gi", requirements.Limits.Memory().String())
+	})
+	s.Run("rejects the other resources", func() {
+		_, err := podResizeRequirements(PodResizeResources{Limits: map[string]string{"nvidia.com/gpu": "1"}})
+		s.EqualError(err, "unsupported resource nvidia.com/gpu, only cpu and memory can be resized")
+	})
+	s.Run("rejects invalid quantities", func() {
+		_, err := podResizeRequirements(PodResizeResources{Requests: map[string]string{"memory": "lots"}})
+		s.ErrorContains(err, `invalid memory quantity "lots"`)
+	})
+	s.Run("requires a resource", func() {
+		_, err := podResizeRequirements(PodResizeResources{})
+		s.EqualError(err, "the cpu or memory requests or limits to set are required")
+	})
+}
+
+func TestPodsResize(t *testing.T) {
+	suite.Run(t, new(PodsResizeTestSuite))
+}
@@ -132,7 +132,14 @@ func (p *kubeConfigClusterProvider) WatchTargets(reload McpReload) {
 		return reload()
 	}
 	p.kubeconfigWatcher.Watch(reloadWithReset)
-	p.clusterStateWatcher.Watch(reload)
+	// the derived clients share the discovery cache of the watched cluster, drop it as well
+	manager := p.managers[p.defaultContext]
+	p.clusterStateWatcher.Watch(func() error {
+		if manager != nil {
+			manager.Invalidate()
+		}
+		return reload()
+	})
 }
 
 func (p *kubeConfigClusterProvider) Close() {
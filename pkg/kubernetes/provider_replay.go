@@ -0,0 +1,44 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+)
+
+// replayProvider implements Provider for the replay mode, where the tool calls are served from a previous recording
+// and no cluster is contacted (nor needs to be configured).
+type replayProvider struct{}
+
+var _ Provider = &replayProvider{}
+
+// ErrorReplayMode is returned when a Kubernetes client is requested in replay mode
+var ErrorReplayMode = errors.New("no cluster is available in replay mode")
+
+// NewReplayProvider creates a provider that exposes a single target without any cluster behind it.
+func NewReplayProvider() Provider {
+	return &replayProvider{}
+}
+
+func (p *replayProvider) IsOpenShift(_ context.Context) bool {
+	return false
+}
+
+func (p *replayProvider) GetTargets(_ context.Context) ([]string, error) {
+	return []string{""}, nil
+}
+
+func (p *replayProvider) GetDerivedKubernetes(_ context.Context, _ string) (*Kubernetes, error) {
+	return nil, ErrorReplayMode
+}
+
+func (p *replayProvider) GetDefaultTarget() string {
+	return ""
+}
+
+func (p *replayProvider) GetTargetParameterName() string {
+	return ""
+}
+
+func (p *replayProvider) WatchTargets(_ McpReload) {}
+
+func (p *replayProvider) Close() {}
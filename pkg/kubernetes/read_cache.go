@@ -0,0 +1,462 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultReadCacheIdleTimeout is the duration after which the informers of the kinds that are not read are stopped
+	defaultReadCacheIdleTimeout = 10 * time.Minute
+	// readCacheSyncTimeout is the maximum duration the first read waits for the initial list of the informer
+	readCacheSyncTimeout = 30 * time.Second
+	// readCacheRetryInterval is the duration the API server is read directly after an informer failed to sync or to watch
+	readCacheRetryInterval = time.Minute
+)
+
+// defaultReadCacheResources are the kinds cached if none are configured
+var defaultReadCacheResources = []api.GroupVersionKind{
+	{Group: "", Version: "v1", Kind: "Pod"},
+	{Group: "", Version: "v1", Kind: "Event"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+}
+
+// ReadCacheConfig enables the informer-backed cache of the list and get requests of the frequently read kinds.
+// The informers are started on the first read of each kind (per cluster and credentials) and watch the whole cluster,
+// they fall back to the API server if the credentials can't list and watch the kind in all namespaces.
+type ReadCacheConfig struct {
+	// Resources are the cached kinds (Optional, Pods, Events, and Deployments if not provided)
+	Resources []api.GroupVersionKind `toml:"resources,omitempty"`
+	// IdleTimeout stops the informers of the kinds not read for the duration (Optional, 10m if not provided)
+	IdleTimeout string `toml:"idle_timeout,omitempty"`
+}
+
+func (c *ReadCacheConfig) Validate() error {
+	if c == nil {
+		return errors.New("read_cache config is nil")
+	}
+	if c.IdleTimeout != "" {
+		if timeout, err := time.ParseDuration(c.IdleTimeout); err != nil || timeout <= 0 {
+			return fmt.Errorf("read_cache idle_timeout must be a positive duration (e.g. 10m): %s", c.IdleTimeout)
+		}
+	}
+	for _, resource := range c.Resources {
+		if resource.Kind == "" || resource.Version == "" {
+			return fmt.Errorf("read_cache resources require a version and a kind: %+v", resource)
+		}
+	}
+	return nil
+}
+
+func (c *ReadCacheConfig) idleTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(c.IdleTimeout); err == nil && timeout > 0 {
+		return timeout
+	}
+	return defaultReadCacheIdleTimeout
+}
+
+func (c *ReadCacheConfig) caches(gvk *schema.GroupVersionKind) bool {
+	resources := c.Resources
+	if len(resources) == 0 {
+		resources = defaultReadCacheResources
+	}
+	return slices.ContainsFunc(resources, func(resource api.GroupVersionKind) bool {
+		return resource.Group == gvk.Group && resource.Version == gvk.Version && resource.Kind == gvk.Kind
+	})
+}
+
+// ReadCacheFreshness describes the informer a result was served from
+type ReadCacheFreshness struct {
+	Kind string
+	// WatchingSince is the time of the initial list of the informer
+	WatchingSince time.Time
+	// LastChange is the time of the last change received by the informer
+	LastChange time.Time
+}
+
+// String returns a comment line with the freshness of the result, to be prepended to the tool output
+func (f *ReadCacheFreshness) String() string {
+	return fmt.Sprintf("# Served from the %s informer cache (watching since %s, last change %s ago), set bypass_cache to read from the API server\n",
+		f.Kind, output.HumanAge(f.WatchingSince), output.HumanAge(f.LastChange))
+}
+
+// WithReadCache serves the list and get requests of the configured kinds from informers, nil reads from the API server
+func (c *Core) WithReadCache(config *ReadCacheConfig) *Core {
+	c.readCache = config
+	return c
+}
+
+// ReadCacheFreshness returns the freshness of the last result served from the read cache, nil if it was read from the API server
+func (c *Core) ReadCacheFreshness() *ReadCacheFreshness {
+	return c.readCacheFreshness
+}
+
+// cachedList returns the objects of the informer of the kind, false if the request can't be served by the read cache
+func (c *Core) cachedList(ctx context.Context, gvk *schema.GroupVersionKind, gvr *schema.GroupVersionResource, namespace string, options api.ListOptions) (*unstructured.UnstructuredList, bool) {
+	if options.FieldSelector != "" || options.ResourceVersion != "" || options.Limit > 0 || options.Continue != "" {
+		return nil, false
+	}
+	selector, err := labels.Parse(options.LabelSelector)
+	if err != nil {
+		return nil, false
+	}
+	informer := c.cachedInformer(ctx, gvk, gvr)
+	if informer == nil {
+		return nil, false
+	}
+	var objects []any
+	if namespace == "" {
+		objects = informer.informer.GetStore().List()
+	} else if objects, err = informer.informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace); err != nil {
+		return nil, false
+	}
+	// The informers watch all the objects, the label scope of the session is enforced on what they serve
+	scope := labelScopeFor(ctx, c.RESTMapper(), *gvr)
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion(gvk.GroupVersion().String())
+	list.SetKind(gvk.Kind + "List")
+	for _, object := range objects {
+		item, ok := object.(*unstructured.Unstructured)
+		if ok && selector.Matches(labels.Set(item.GetLabels())) && (scope == nil || scope.Matches(labels.Set(item.GetLabels()))) {
+			list.Items = append(list.Items, *item.DeepCopy())
+		}
+	}
+	slices.SortFunc(list.Items, func(a, b unstructured.Unstructured) int {
+		return strings.Compare(a.GetNamespace()+"/"+a.GetName(), b.GetNamespace()+"/"+b.GetName())
+	})
+	c.readCacheFreshness = informer.freshness(gvk.Kind)
+	return list, true
+}
+
+// cachedGet returns the object from the informer of the kind, false if it's not cached (the API server has the final word on missing objects)
+func (c *Core) cachedGet(ctx context.Context, gvk *schema.GroupVersionKind, gvr *schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, bool) {
+	informer := c.cachedInformer(ctx, gvk, gvr)
+	if informer == nil {
+		return nil, false
+	}
+	key := name
+	if namespace != "" {
+		key = namespace + "/" + name
+	}
+	object, exists, err := informer.informer.GetStore().GetByKey(key)
+	if err != nil || !exists {
+		return nil, false
+	}
+	item, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		return nil, false
+	}
+	// The objects out of the label scope of the session are reported as not found by the API server
+	if scope := labelScopeFor(ctx, c.RESTMapper(), *gvr); scope != nil && !scope.Matches(labels.Set(item.GetLabels())) {
+		return nil, false
+	}
+	c.readCacheFreshness = informer.freshness(gvk.Kind)
+	return item.DeepCopy(), true
+}
+
+// cachedInformer returns the synced informer of the kind, nil if the kind is not cached or the informer can't sync or watch
+func (c *Core) cachedInformer(ctx context.Context, gvk *schema.GroupVersionKind, gvr *schema.GroupVersionResource) *readCacheInformer {
+	if c.readCache == nil || !c.readCache.caches(gvk) {
+		return nil
+	}
+	informer := readCaches.informerFor(c.KubernetesClient, *gvr, c.readCache.idleTimeout())
+	select {
+	case <-informer.ready:
+	case <-ctx.Done():
+		return nil
+	}
+	if _, err := informer.failure(); err != nil {
+		return nil
+	}
+	informer.read()
+	return informer
+}
+
+// readCaches holds the informers per cluster and credentials, shared by the (derived) clients of every tool call
+var readCaches = &readCacheRegistry{informers: make(map[string]*readCacheInformer)}
+
+type readCacheRegistry struct {
+	mu        sync.Mutex
+	informers map[string]*readCacheInformer
+}
+
+type readCacheInformer struct {
+	informer      cache.SharedIndexInformer
+	stop          chan struct{}
+	stopOnce      sync.Once
+	ready         chan struct{}
+	mu            sync.Mutex
+	err           error
+	failedAt      time.Time
+	synced        atomic.Bool
+	watchingSince time.Time
+	lastChange    atomic.Int64
+	idleTimer     *time.Timer
+	idleTimeout   time.Duration
+}
+
+// informerFor returns the informer of the resource for the credentials of the client, starting it if needed
+func (r *readCacheRegistry) informerFor(client api.KubernetesClient, gvr schema.GroupVersionResource, idleTimeout time.Duration) *readCacheInformer {
+	key := readCacheKey(client, gvr)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if informer, ok := r.informers[key]; ok {
+		select {
+		case <-informer.ready:
+			if failedAt, err := informer.failure(); err == nil || time.Since(failedAt) < readCacheRetryInterval {
+				return informer
+			}
+		default:
+			return informer
+		}
+	}
+	informer := &readCacheInformer{stop: make(chan struct{}), ready: make(chan struct{}), idleTimeout: idleTimeout}
+	r.informers[key] = informer
+	informer.idleTimer = time.AfterFunc(idleTimeout, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.informers[key] == informer {
+			delete(r.informers, key)
+		}
+		informer.close()
+	})
+	go informer.start(client.DynamicClient(), gvr)
+	return informer
+}
+
+// start runs the informer and waits for its initial list, an error of the first list or watch (e.g. forbidden) fails it,
+// as do the later watch errors (see watchFailed)
+func (i *readCacheInformer) start(client dynamic.Interface, gvr schema.GroupVersionResource) {
+	defer close(i.ready)
+	i.informer = dynamicinformer.NewFilteredDynamicInformer(client, gvr, metav1.NamespaceAll, 0,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, nil).Informer()
+	failed := make(chan error, 1)
+	_ = i.informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		if i.synced.Load() {
+			i.watchFailed(gvr, err)
+			return
+		}
+		select {
+		case failed <- err:
+		default:
+		}
+	})
+	changed := func() { i.lastChange.Store(time.Now().UnixNano()) }
+	_, _ = i.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { changed() },
+		UpdateFunc: func(any, any) { changed() },
+		DeleteFunc: func(any) { changed() },
+	})
+	go i.informer.Run(i.stop)
+	ctx, cancel := context.WithTimeout(context.Background(), readCacheSyncTimeout)
+	defer cancel()
+	err := wait.PollUntilContextCancel(ctx, 50*time.Millisecond, true, func(context.Context) (bool, error) {
+		select {
+		case err := <-failed:
+			return false, err
+		default:
+			return i.informer.HasSynced(), nil
+		}
+	})
+	if err != nil {
+		klog.V(2).Infof("read cache of %s unavailable, reading from the API server: %v", gvr.String(), err)
+		i.fail(err)
+		return
+	}
+	i.watchingSince = time.Now()
+	i.synced.Store(true)
+}
+
+// watchFailed fails the synced informer on the watch errors (e.g. the credentials were revoked, the API server is
+// unreachable), its objects are no longer updated. The closed watches restarted by the informer are ignored.
+func (i *readCacheInformer) watchFailed(gvr schema.GroupVersionResource, err error) {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+		return
+	}
+	klog.V(2).Infof("read cache of %s failed to watch, reading from the API server: %v", gvr.String(), err)
+	i.fail(err)
+}
+
+// fail stops the informer, the reads fall back to the API server until it's restarted after the retry interval
+func (i *readCacheInformer) fail(err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.err, i.failedAt = err, time.Now()
+	i.close()
+}
+
+// failure returns the time and the error the informer was stopped with, nil if it's running
+func (i *readCacheInformer) failure() (time.Time, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.failedAt, i.err
+}
+
+// read postpones the idle timeout of the informer
+func (i *readCacheInformer) read() {
+	i.idleTimer.Reset(i.idleTimeout)
+}
+
+// close stops the informer, it's called both by the idle timeout and by fail
+func (i *readCacheInformer) close() {
+	i.stopOnce.Do(func() { close(i.stop) })
+}
+
+func (i *readCacheInformer) freshness(kind string) *ReadCacheFreshness {
+	freshness := &ReadCacheFreshness{Kind: kind, WatchingSince: i.watchingSince, LastChange: i.watchingSince}
+	if lastChange := i.lastChange.Load(); lastChange > 0 {
+		freshness.LastChange = time.Unix(0, lastChange)
+	}
+	return freshness
+}
+
+// readCacheKey identifies the informers per API server, credentials, and resource.
+// The informers list with the credentials of the client, the results are only shared with the same credentials.
+func readCacheKey(client api.KubernetesClient, gvr schema.GroupVersionResource) string {
+	return clientKey(client, gvr.String())
+}
+
+// CredentialsKey identifies the API server and the credentials of the client, to share the data read with the same
+// credentials only
+func CredentialsKey(client api.KubernetesClient) string {
+	return clientKey(client)
+}
+
+// clientKey identifies the API server and the credentials of the client, and the extra parts
+func clientKey(client api.KubernetesClient, extra ...string) string {
+	cfg := client.RESTConfig()
+	hash := sha256.New()
+	for _, part := range append([]string{
+		cfg.Host, cfg.BearerToken, cfg.BearerTokenFile, cfg.Username, cfg.CertFile, string(cfg.CertData),
+		cfg.Impersonate.UserName, strings.Join(cfg.Impersonate.Groups, ","), fmt.Sprintf("%p", cfg.ExecProvider),
+		fmt.Sprintf("%p", cfg.AuthProvider),
+	}, extra...) {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// readCacheTable renders the cached objects as the Table the API server would return, with the common columns of the kind
+func readCacheTable(gvk *schema.GroupVersionKind, list *unstructured.UnstructuredList) (*metav1.Table, error) {
+	columns := readCacheColumns[gvk.GroupKind()]
+	table := &metav1.Table{ColumnDefinitions: []metav1.TableColumnDefinition{{Name: "Name", Type: "string", Format: "name"}}}
+	for _, column := range columns {
+		table.ColumnDefinitions = append(table.ColumnDefinitions, metav1.TableColumnDefinition{Name: column.name, Type: "string"})
+	}
+	table.ColumnDefinitions = append(table.ColumnDefinitions, metav1.TableColumnDefinition{Name: "Age", Type: "string"})
+	for i := range list.Items {
+		item := &list.Items[i]
+		raw, err := item.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		cells := []any{item.GetName()}
+		for _, column := range columns {
+			cells = append(cells, column.value(item.Object))
+		}
+		cells = append(cells, output.HumanAge(item.GetCreationTimestamp().Time))
+		table.Rows = append(table.Rows, metav1.TableRow{Cells: cells, Object: runtime.RawExtension{Raw: raw}})
+	}
+	return table, nil
+}
+
+type readCacheColumn struct {
+	name  string
+	value func(object map[string]any) any
+}
+
+var readCacheColumns = map[schema.GroupKind][]readCacheColumn{
+	{Group: "", Kind: "Pod"}: {
+		{"Ready", func(object map[string]any) any {
+			statuses, _, _ := unstructured.NestedSlice(object, "status", "containerStatuses")
+			ready := 0
+			for _, status := range statuses {
+				if status, ok := status.(map[string]any); ok && status["ready"] == true {
+					ready++
+				}
+			}
+			containers, _, _ := unstructured.NestedSlice(object, "spec", "containers")
+			return fmt.Sprintf("%d/%d", ready, len(containers))
+		}},
+		{"Status", func(object map[string]any) any {
+			statuses, _, _ := unstructured.NestedSlice(object, "status", "containerStatuses")
+			for _, status := range statuses {
+				status, _ := status.(map[string]any)
+				if reason, _, _ := unstructured.NestedString(status, "state", "waiting", "reason"); reason != "" {
+					return reason
+				}
+			}
+			phase, _, _ := unstructured.NestedString(object, "status", "phase")
+			return phase
+		}},
+		{"Restarts", func(object map[string]any) any {
+			statuses, _, _ := unstructured.NestedSlice(object, "status", "containerStatuses")
+			var restarts int64
+			for _, status := range statuses {
+				status, _ := status.(map[string]any)
+				count, _, _ := unstructured.NestedInt64(status, "restartCount")
+				restarts += count
+			}
+			return fmt.Sprint(restarts)
+		}},
+		{"IP", nestedString("status", "podIP")},
+		{"Node", nestedString("spec", "nodeName")},
+	},
+	{Group: "", Kind: "Event"}: {
+		{"Type", nestedString("type")},
+		{"Reason", nestedString("reason")},
+		{"Object", func(object map[string]any) any {
+			kind, _, _ := unstructured.NestedString(object, "involvedObject", "kind")
+			name, _, _ := unstructured.NestedString(object, "involvedObject", "name")
+			return strings.ToLower(kind) + "/" + name
+		}},
+		{"Message", nestedString("message")},
+	},
+	{Group: "apps", Kind: "Deployment"}: {
+		{"Ready", func(object map[string]any) any {
+			ready, _, _ := unstructured.NestedInt64(object, "status", "readyReplicas")
+			replicas, _, _ := unstructured.NestedInt64(object, "spec", "replicas")
+			return fmt.Sprintf("%d/%d", ready, replicas)
+		}},
+		{"Up-to-date", nestedInt64("status", "updatedReplicas")},
+		{"Available", nestedInt64("status", "availableReplicas")},
+	},
+}
+
+func nestedString(fields ...string) func(object map[string]any) any {
+	return func(object map[string]any) any {
+		value, _, _ := unstructured.NestedString(object, fields...)
+		return value
+	}
+}
+
+func nestedInt64(fields ...string) func(object map[string]any) any {
+	return func(object map[string]any) any {
+		value, _, _ := unstructured.NestedInt64(object, fields...)
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,196 @@
+package kubernetes
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+)
+
+type ReadCacheTestSuite struct {
+	suite.Suite
+	core *Core
+}
+
+var (
+	podGVK = &schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	podGVR = &schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+)
+
+func (s *ReadCacheTestSuite) SetupTest() {
+	pod := func(namespace, name, app string) runtime.Object {
+		return &v1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"app": app}},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app"}}, NodeName: "node-1"},
+			Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", Ready: true, RestartCount: 2},
+			}},
+		}
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(Scheme,
+		map[schema.GroupVersionResource]string{*podGVR: "PodList"},
+		pod("default", "web-1", "web"), pod("default", "db-1", "db"), pod("other", "web-2", "web"))
+	// A distinct host per test, the informers are shared by the clients with the same credentials
+	k := &Kubernetes{restConfig: &rest.Config{Host: "https://" + s.T().Name()}, dynamicClient: client}
+	s.core = NewCore(k).WithReadCache(&ReadCacheConfig{})
+}
+
+func (s *ReadCacheTestSuite) TestList() {
+	s.Run("lists the cached objects of the namespace matching the label selector", func() {
+		list, ok := s.core.cachedList(s.T().Context(), podGVK, podGVR, "default", api.ListOptions{
+			ListOptions: metav1.ListOptions{LabelSelector: "app=web"},
+		})
+		s.Require().True(ok, "expected the list to be served from the cache")
+		s.Require().Len(list.Items, 1)
+		s.Equal("web-1", list.Items[0].GetName())
+		s.Equal("PodList", list.GetKind())
+	})
+	s.Run("lists the cached objects of all namespaces sorted by namespace and name", func() {
+		list, ok := s.core.cachedList(s.T().Context(), podGVK, podGVR, "", api.ListOptions{})
+		s.Require().True(ok, "expected the list to be served from the cache")
+		s.Require().Len(list.Items, 3)
+		s.Equal([]string{"db-1", "web-1", "web-2"}, []string{list.Items[0].GetName(), list.Items[1].GetName(), list.Items[2].GetName()})
+	})
+	s.Run("reports the freshness of the informer", func() {
+		freshness := s.core.ReadCacheFreshness()
+		s.Require().NotNil(freshness)
+		s.Equal("Pod", freshness.Kind)
+		s.WithinDuration(time.Now(), freshness.WatchingSince, time.Minute)
+		s.Contains(freshness.String(), "# Served from the Pod informer cache")
+	})
+	s.Run("field selectors are served by the API server", func() {
+		_, ok := s.core.cachedList(s.T().Context(), podGVK, podGVR, "", api.ListOptions{
+			ListOptions: metav1.ListOptions{FieldSelector: "spec.nodeName=node-1"},
+		})
+		s.False(ok)
+	})
+	s.Run("kinds that are not configured are served by the API server", func() {
+		_, ok := s.core.cachedList(s.T().Context(), &schema.GroupVersionKind{Version: "v1", Kind: "Secret"},
+			&schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, "", api.ListOptions{})
+		s.False(ok)
+	})
+	s.Run("bypassed cache is served by the API server", func() {
+		_, ok := NewCore(s.core.KubernetesClient).cachedList(s.T().Context(), podGVK, podGVR, "", api.ListOptions{})
+		s.False(ok)
+	})
+}
+
+func (s *ReadCacheTestSuite) TestGet() {
+	s.Run("returns the cached object", func() {
+		pod, ok := s.core.cachedGet(s.T().Context(), podGVK, podGVR, "other", "web-2")
+		s.Require().True(ok, "expected the object to be served from the cache")
+		s.Equal("web-2", pod.GetName())
+		s.NotNil(s.core.ReadCacheFreshness())
+	})
+	s.Run("missing objects are served by the API server", func() {
+		_, ok := s.core.cachedGet(s.T().Context(), podGVK, podGVR, "other", "missing")
+		s.False(ok)
+	})
+}
+
+func (s *ReadCacheTestSuite) TestWatchFailure() {
+	_, ok := s.core.cachedList(s.T().Context(), podGVK, podGVR, "", api.ListOptions{})
+	s.Require().True(ok, "expected the list to be served from the cache")
+	informer := readCaches.informerFor(s.core.KubernetesClient, *podGVR, time.Minute)
+	s.Run("closed watches restarted by the informer keep serving from the cache", func() {
+		informer.watchFailed(*podGVR, io.EOF)
+		_, ok := s.core.cachedList(s.T().Context(), podGVK, podGVR, "", api.ListOptions{})
+		s.True(ok, "expected the list to be served from the cache")
+	})
+	s.Run("failed watches are served by the API server", func() {
+		informer.watchFailed(*podGVR, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("revoked")))
+		_, ok := s.core.cachedList(s.T().Context(), podGVK, podGVR, "", api.ListOptions{})
+		s.False(ok)
+		_, ok = s.core.cachedGet(s.T().Context(), podGVK, podGVR, "other", "web-2")
+		s.False(ok)
+	})
+	s.Run("failed informer is stopped", func() {
+		select {
+		case <-informer.stop:
+		default:
+			s.Fail("expected the informer to be stopped")
+		}
+	})
+	s.Run("failed informer is restarted after the retry interval", func() {
+		informer.mu.Lock()
+		informer.failedAt = informer.failedAt.Add(-readCacheRetryInterval)
+		informer.mu.Unlock()
+		_, ok := s.core.cachedList(s.T().Context(), podGVK, podGVR, "", api.ListOptions{})
+		s.True(ok, "expected the list to be served from the cache")
+		s.NotSame(informer, readCaches.informerFor(s.core.KubernetesClient, *podGVR, time.Minute))
+	})
+}
+
+func (s *ReadCacheTestSuite) TestIdleExpiryDuringFailure() {
+	for range 100 {
+		informer := &readCacheInformer{stop: make(chan struct{}), ready: make(chan struct{})}
+		// the idle timeout closes the informer without its lock, while the watch fails
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			informer.fail(errors.New("watch failed"))
+		}()
+		informer.idleTimer = time.AfterFunc(0, func() {
+			defer wg.Done()
+			informer.close()
+		})
+		wg.Wait()
+		select {
+		case <-informer.stop:
+		default:
+			s.Fail("expected the informer to be stopped")
+		}
+	}
+}
+
+func (s *ReadCacheTestSuite) TestTable() {
+	list, ok := s.core.cachedList(s.T().Context(), podGVK, podGVR, "other", api.ListOptions{})
+	s.Require().True(ok)
+	table, err := readCacheTable(podGVK, list)
+	s.Require().NoError(err)
+	s.Run("has the common columns of the kind", func() {
+		var columns []string
+		for _, column := range table.ColumnDefinitions {
+			columns = append(columns, column.Name)
+		}
+		s.Equal([]string{"Name", "Ready", "Status", "Restarts", "IP", "Node", "Age"}, columns)
+	})
+	s.Run("has a row per object with its raw object", func() {
+		s.Require().Len(table.Rows, 1)
+		s.Equal([]any{"web-2", "1/1", "Running", "2", "", "node-1", ""}, table.Rows[0].Cells)
+		s.NotEmpty(table.Rows[0].Object.Raw)
+	})
+}
+
+func (s *ReadCacheTestSuite) TestConfigValidate() {
+	s.Run("rejects invalid idle timeouts", func() {
+		s.Error((&ReadCacheConfig{IdleTimeout: "-1m"}).Validate())
+	})
+	s.Run("rejects resources without kind", func() {
+		s.Error((&ReadCacheConfig{Resources: []api.GroupVersionKind{{Version: "v1"}}}).Validate())
+	})
+	s.Run("caches pods, events, and deployments by default", func() {
+		cfg := &ReadCacheConfig{}
+		s.True(cfg.caches(&schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}))
+		s.True(cfg.caches(&schema.GroupVersionKind{Version: "v1", Kind: "Event"}))
+		s.False(cfg.caches(&schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}))
+		s.Equal(defaultReadCacheIdleTimeout, cfg.idleTimeout())
+	})
+}
+
+func TestReadCache(t *testing.T) {
+	suite.Run(t, new(ReadCacheTestSuite))
+}
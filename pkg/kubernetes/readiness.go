@@ -0,0 +1,185 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// WorkloadReadinessKinds are the kinds of workloads supported by WorkloadReadiness
+var WorkloadReadinessKinds = []string{"Deployment", "StatefulSet", "DaemonSet"}
+
+// WorkloadReadiness is a snapshot of the readiness of a workload and its Pods.
+type WorkloadReadiness struct {
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	CapturedAt string `json:"capturedAt"`
+	Generation int64  `json:"generation"`
+	// Images are the container images of the workload Pod template
+	Images            []string       `json:"images,omitempty"`
+	DesiredReplicas   int32          `json:"desiredReplicas"`
+	ReadyReplicas     int32          `json:"readyReplicas"`
+	UpdatedReplicas   int32          `json:"updatedReplicas"`
+	AvailableReplicas int32          `json:"availableReplicas"`
+	Restarts          int32          `json:"restarts"`
+	Pods              []PodReadiness `json:"pods,omitempty"`
+}
+
+// PodReadiness is the readiness of a Pod of a workload.
+type PodReadiness struct {
+	Name     string `json:"name"`
+	Phase    string `json:"phase"`
+	Ready    bool   `json:"ready"`
+	Restarts int32  `json:"restarts"`
+}
+
+// ReadinessComparison is the result of the comparison of the current readiness of a workload with a baseline.
+type ReadinessComparison struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Regressed is true if the workload is less ready or restarting more than in the baseline
+	Regressed     bool     `json:"regressed"`
+	BaselineAt    string   `json:"baselineAt"`
+	CurrentAt     string   `json:"currentAt"`
+	Ready         string   `json:"ready"`
+	BaselineReady string   `json:"baselineReady"`
+	Changes       []string `json:"changes,omitempty"`
+	Regressions   []string `json:"regressions,omitempty"`
+	Improvements  []string `json:"improvements,omitempty"`
+	// RestartsSinceBaseline are the container restarts that happened after the baseline was captured
+	RestartsSinceBaseline int32 `json:"restartsSinceBaseline"`
+}
+
+// WorkloadReadiness captures a snapshot of the readiness and restarts of the provided workload and its Pods.
+func (c *Core) WorkloadReadiness(ctx context.Context, kind, namespace, name string) (*WorkloadReadiness, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	readiness := &WorkloadReadiness{Kind: kind, Namespace: namespace, Name: name, CapturedAt: time.Now().UTC().Format(time.RFC3339)}
+	var selector *metav1.LabelSelector
+	var template v1.PodTemplateSpec
+	switch kind {
+	case "Deployment":
+		deployment, err := c.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, template, readiness.Generation = deployment.Spec.Selector, deployment.Spec.Template, deployment.Generation
+		readiness.DesiredReplicas = replicasOrDefault(deployment.Spec.Replicas)
+		readiness.ReadyReplicas, readiness.UpdatedReplicas, readiness.AvailableReplicas = deployment.Status.ReadyReplicas, deployment.Status.UpdatedReplicas, deployment.Status.AvailableReplicas
+	case "StatefulSet":
+		statefulSet, err := c.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, template, readiness.Generation = statefulSet.Spec.Selector, statefulSet.Spec.Template, statefulSet.Generation
+		readiness.DesiredReplicas = replicasOrDefault(statefulSet.Spec.Replicas)
+		readiness.ReadyReplicas, readiness.UpdatedReplicas, readiness.AvailableReplicas = statefulSet.Status.ReadyReplicas, statefulSet.Status.UpdatedReplicas, statefulSet.Status.AvailableReplicas
+	case "DaemonSet":
+		daemonSet, err := c.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, template, readiness.Generation = daemonSet.Spec.Selector, daemonSet.Spec.Template, daemonSet.Generation
+		readiness.DesiredReplicas = daemonSet.Status.DesiredNumberScheduled
+		readiness.ReadyReplicas, readiness.UpdatedReplicas, readiness.AvailableReplicas = daemonSet.Status.NumberReady, daemonSet.Status.UpdatedNumberScheduled, daemonSet.Status.NumberAvailable
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %s, supported kinds are %v", kind, WorkloadReadinessKinds)
+	}
+	for _, container := range slices.Concat(template.Spec.InitContainers, template.Spec.Containers) {
+		readiness.Images = append(readiness.Images, container.Name+"="+container.Image)
+	}
+	podSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	if podSelector.Empty() {
+		podSelector = labels.Nothing()
+	}
+	pods, err := c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: podSelector.String()})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		podReadiness := PodReadiness{Name: pod.Name, Phase: string(pod.Status.Phase)}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodReady {
+				podReadiness.Ready = condition.Status == v1.ConditionTrue
+			}
+		}
+		for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+			podReadiness.Restarts += status.RestartCount
+		}
+		readiness.Restarts += podReadiness.Restarts
+		readiness.Pods = append(readiness.Pods, podReadiness)
+	}
+	slices.SortFunc(readiness.Pods, func(a, b PodReadiness) int { return cmp.Compare(a.Name, b.Name) })
+	return readiness, nil
+}
+
+// CompareReadiness compares the current readiness of a workload with a baseline captured earlier to detect regressions.
+func CompareReadiness(baseline, current *WorkloadReadiness) *ReadinessComparison {
+	comparison := &ReadinessComparison{
+		Kind: current.Kind, Namespace: current.Namespace, Name: current.Name,
+		BaselineAt:    baseline.CapturedAt,
+		CurrentAt:     current.CapturedAt,
+		Ready:         fmt.Sprintf("%d/%d", current.ReadyReplicas, current.DesiredReplicas),
+		BaselineReady: fmt.Sprintf("%d/%d", baseline.ReadyReplicas, baseline.DesiredReplicas),
+	}
+	if current.Generation != baseline.Generation {
+		comparison.Changes = append(comparison.Changes, fmt.Sprintf("spec changed (generation %d -> %d)", baseline.Generation, current.Generation))
+	}
+	for _, image := range current.Images {
+		if !slices.Contains(baseline.Images, image) {
+			comparison.Changes = append(comparison.Changes, "image "+image)
+		}
+	}
+	if current.DesiredReplicas != baseline.DesiredReplicas {
+		comparison.Changes = append(comparison.Changes, fmt.Sprintf("desired replicas %d -> %d", baseline.DesiredReplicas, current.DesiredReplicas))
+	}
+
+	currentMissing, baselineMissing := current.DesiredReplicas-current.ReadyReplicas, baseline.DesiredReplicas-baseline.ReadyReplicas
+	switch {
+	case currentMissing > baselineMissing:
+		comparison.Regressions = append(comparison.Regressions, fmt.Sprintf("ready replicas went from %s to %s", comparison.BaselineReady, comparison.Ready))
+	case currentMissing < baselineMissing:
+		comparison.Improvements = append(comparison.Improvements, fmt.Sprintf("ready replicas went from %s to %s", comparison.BaselineReady, comparison.Ready))
+	}
+
+	// Pods replaced since the baseline start from zero restarts, all of their restarts happened after the baseline
+	baselineRestarts := map[string]int32{}
+	for _, pod := range baseline.Pods {
+		baselineRestarts[pod.Name] = pod.Restarts
+	}
+	var restartingPods []string
+	for _, pod := range current.Pods {
+		if restarts := pod.Restarts - baselineRestarts[pod.Name]; restarts > 0 {
+			comparison.RestartsSinceBaseline += restarts
+			restartingPods = append(restartingPods, fmt.Sprintf("%s (+%d)", pod.Name, restarts))
+		}
+		if !pod.Ready && pod.Phase != string(v1.PodSucceeded) {
+			if idx := slices.IndexFunc(baseline.Pods, func(p PodReadiness) bool { return p.Name == pod.Name }); idx >= 0 && baseline.Pods[idx].Ready {
+				comparison.Regressions = append(comparison.Regressions, fmt.Sprintf("pod %s was ready and is no longer ready", pod.Name))
+			}
+		}
+	}
+	if comparison.RestartsSinceBaseline > 0 {
+		comparison.Regressions = append(comparison.Regressions, fmt.Sprintf("%d container restart(s) since the baseline: %s", comparison.RestartsSinceBaseline, strings.Join(restartingPods, ", ")))
+	}
+	comparison.Regressed = len(comparison.Regressions) > 0
+	return comparison
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
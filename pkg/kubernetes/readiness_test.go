@@ -0,0 +1,56 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ReadinessTestSuite struct {
+	suite.Suite
+}
+
+func (s *ReadinessTestSuite) TestCompareReadiness() {
+	baseline := &WorkloadReadiness{
+		Kind: "Deployment", Namespace: "default", Name: "web", CapturedAt: "2026-01-01T00:00:00Z",
+		Generation: 1, Images: []string{"app=web:1.0"},
+		DesiredReplicas: 3, ReadyReplicas: 3,
+		Pods: []PodReadiness{{Name: "web-a", Ready: true, Restarts: 2}, {Name: "web-b", Ready: true}, {Name: "web-c", Ready: true}},
+	}
+	s.Run("no changes", func() {
+		comparison := CompareReadiness(baseline, baseline)
+		s.False(comparison.Regressed)
+		s.Empty(comparison.Changes)
+		s.Empty(comparison.Regressions)
+		s.Equal("3/3", comparison.Ready)
+	})
+	s.Run("regression after a rollout", func() {
+		current := &WorkloadReadiness{
+			Kind: "Deployment", Namespace: "default", Name: "web", CapturedAt: "2026-01-01T00:10:00Z",
+			Generation: 2, Images: []string{"app=web:1.1"},
+			DesiredReplicas: 3, ReadyReplicas: 1,
+			Pods: []PodReadiness{{Name: "web-a", Ready: true, Restarts: 3}, {Name: "web-b", Ready: false}, {Name: "web-d", Ready: false, Restarts: 4}},
+		}
+		comparison := CompareReadiness(baseline, current)
+		s.True(comparison.Regressed)
+		s.Equal([]string{"spec changed (generation 1 -> 2)", "image app=web:1.1"}, comparison.Changes)
+		s.Equal(int32(5), comparison.RestartsSinceBaseline)
+		s.Equal([]string{
+			"ready replicas went from 3/3 to 1/3",
+			"pod web-b was ready and is no longer ready",
+			"5 container restart(s) since the baseline: web-a (+1), web-d (+4)",
+		}, comparison.Regressions)
+	})
+	s.Run("improvement", func() {
+		current := *baseline
+		degraded := *baseline
+		degraded.ReadyReplicas = 2
+		comparison := CompareReadiness(&degraded, &current)
+		s.False(comparison.Regressed)
+		s.Equal([]string{"ready replicas went from 2/3 to 3/3"}, comparison.Improvements)
+	})
+}
+
+func TestReadiness(t *testing.T) {
+	suite.Run(t, new(ReadinessTestSuite))
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/containers/kubernetes-mcp-server/pkg/version"
 	authv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
@@ -21,6 +23,7 @@ import (
 
 const (
 	AppKubernetesComponent = "app.kubernetes.io/component"
+	AppKubernetesInstance  = "app.kubernetes.io/instance"
 	AppKubernetesManagedBy = "app.kubernetes.io/managed-by"
 	AppKubernetesName      = "app.kubernetes.io/name"
 	AppKubernetesPartOf    = "app.kubernetes.io/part-of"
@@ -37,6 +40,16 @@ func (c *Core) ResourcesList(ctx context.Context, gvk *schema.GroupVersionKind,
 	if isNamespaced && !c.canIUse(ctx, gvr, namespace, "list") && namespace == "" {
 		namespace = c.NamespaceOrDefault("")
 	}
+	if cached, ok := c.cachedList(ctx, gvk, gvr, namespace, options); ok {
+		if !options.AsTable {
+			return cached, nil
+		}
+		table, err := readCacheTable(gvk, cached)
+		if err != nil {
+			return nil, err
+		}
+		return tableToUnstructured(gvk, gvr, table)
+	}
 	if options.AsTable {
 		return c.resourcesListAsTable(ctx, gvk, gvr, namespace, options)
 	}
@@ -53,10 +66,41 @@ func (c *Core) ResourcesGet(ctx context.Context, gvk *schema.GroupVersionKind, n
 	if namespaced, nsErr := c.isNamespaced(gvk); nsErr == nil && namespaced {
 		namespace = c.NamespaceOrDefault(namespace)
 	}
+	if cached, ok := c.cachedGet(ctx, gvk, gvr, namespace, name); ok {
+		return cached, nil
+	}
 	return c.DynamicClient().Resource(*gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
 func (c *Core) ResourcesCreateOrUpdate(ctx context.Context, resource string) ([]*unstructured.Unstructured, error) {
+	parsedResources, err := parseResources(resource)
+	if err != nil {
+		return nil, err
+	}
+	return c.resourcesCreateOrUpdate(ctx, parsedResources)
+}
+
+// ResourcesCreateOrUpdateWithPrevious creates or updates the provided resources and returns,
+// in addition to the resulting resources, their state prior to the operation (nil for the resources that were created).
+func (c *Core) ResourcesCreateOrUpdateWithPrevious(ctx context.Context, resource string) (previous, ret []*unstructured.Unstructured, err error) {
+	parsedResources, err := parseResources(resource)
+	if err != nil {
+		return nil, nil, err
+	}
+	previous = make([]*unstructured.Unstructured, len(parsedResources))
+	for i, obj := range parsedResources {
+		gvk := obj.GroupVersionKind()
+		previous[i], err = c.ResourcesGet(ctx, &gvk, obj.GetNamespace(), obj.GetName())
+		// the resource (or its kind, e.g. a CRD created in the same call) doesn't exist yet
+		if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return nil, nil, err
+		}
+	}
+	ret, err = c.resourcesCreateOrUpdate(ctx, parsedResources)
+	return previous, ret, err
+}
+
+func parseResources(resource string) ([]*unstructured.Unstructured, error) {
 	separator := regexp.MustCompile(`\r?\n---\r?\n`)
 	resources := separator.Split(resource, -1)
 	var parsedResources []*unstructured.Unstructured
@@ -67,7 +111,7 @@ func (c *Core) ResourcesCreateOrUpdate(ctx context.Context, resource string) ([]
 		}
 		parsedResources = append(parsedResources, &obj)
 	}
-	return c.resourcesCreateOrUpdate(ctx, parsedResources)
+	return parsedResources, nil
 }
 
 func (c *Core) ResourcesDelete(ctx context.Context, gvk *schema.GroupVersionKind, namespace, name string, gracePeriodSeconds *int64) error {
@@ -156,6 +200,11 @@ func (c *Core) resourcesListAsTable(ctx context.Context, gvk *schema.GroupVersio
 	if err != nil {
 		return nil, err
 	}
+	return tableToUnstructured(gvk, gvr, &table)
+}
+
+// tableToUnstructured converts the Table to an unstructured object, with the apiVersion and kind columns of the rows
+func tableToUnstructured(gvk *schema.GroupVersionKind, gvr *schema.GroupVersionResource, table *metav1.Table) (runtime.Unstructured, error) {
 	// Add metav1.Table apiVersion and kind to the unstructured object (server may not return these fields)
 	table.SetGroupVersionKind(metav1.SchemeGroupVersion.WithKind("Table"))
 	// Add additional columns for fields that aren't returned by the server
@@ -170,7 +219,7 @@ func (c *Core) resourcesListAsTable(ctx context.Context, gvk *schema.GroupVersio
 			gvk.Kind,
 		}, row.Cells...)
 	}
-	unstructuredObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&table)
+	unstructuredObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(table)
 	return &unstructured.Unstructured{Object: unstructuredObject}, err
 }
 
@@ -0,0 +1,125 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+// RestartOffender is a container that was OOMKilled or is crash looping within the analyzed time window.
+type RestartOffender struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Restarts  int32  `json:"restarts"`
+	OOMKilled bool   `json:"oomKilled"`
+	CrashLoop bool   `json:"crashLoop"`
+	// LastTermination describes the previous (terminated) instance of the container
+	LastTermination *ContainerTermination `json:"lastTermination,omitempty"`
+	MemoryRequest   string                `json:"memoryRequest,omitempty"`
+	MemoryLimit     string                `json:"memoryLimit,omitempty"`
+	// MemoryUsage is the current usage reported by the Metrics Server (if available)
+	MemoryUsage string `json:"memoryUsage,omitempty"`
+	// MemoryUsageOfLimit is the current usage as a percentage of the memory limit
+	MemoryUsageOfLimit string `json:"memoryUsageOfLimit,omitempty"`
+}
+
+// PodsRestarts scans the Pods in the provided namespace (all namespaces if empty) matching the label selector for
+// containers that were OOMKilled or are crash looping within the provided time window.
+// The returned offenders are ranked by their number of restarts.
+func (c *Core) PodsRestarts(ctx context.Context, namespace, labelSelector string, window time.Duration) ([]RestartOffender, error) {
+	pods, err := c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+	usage := map[string]resource.Quantity{}
+	// Metrics are best-effort, neither the Metrics Server nor the kubelet Summary API might be available
+	if podMetrics, err := c.PodsTop(ctx, api.PodsTopOptions{
+		ListOptions:   metav1.ListOptions{LabelSelector: labelSelector},
+		AllNamespaces: namespace == "",
+		Namespace:     namespace,
+	}); err == nil {
+		for _, pm := range podMetrics.Items {
+			for _, container := range pm.Containers {
+				usage[pm.Namespace+"/"+pm.Name+"/"+container.Name] = container.Usage[v1.ResourceMemory]
+			}
+		}
+	}
+	return restartOffenders(pods.Items, usage, time.Now().Add(-window)), nil
+}
+
+func restartOffenders(pods []v1.Pod, usage map[string]resource.Quantity, since time.Time) []RestartOffender {
+	offenders := make([]RestartOffender, 0)
+	for _, pod := range pods {
+		for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+			offender := RestartOffender{
+				Namespace: pod.Namespace,
+				Pod:       pod.Name,
+				Container: status.Name,
+				Restarts:  status.RestartCount,
+				CrashLoop: status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff",
+				OOMKilled: status.State.Terminated != nil && status.State.Terminated.Reason == "OOMKilled",
+			}
+			recent := offender.CrashLoop || offender.OOMKilled
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				offender.LastTermination = &ContainerTermination{
+					Reason:   terminated.Reason,
+					Message:  terminated.Message,
+					ExitCode: terminated.ExitCode,
+				}
+				if !terminated.FinishedAt.IsZero() {
+					offender.LastTermination.FinishedAt = terminated.FinishedAt.UTC().Format(time.RFC3339)
+				}
+				terminatedInWindow := !terminated.FinishedAt.Time.Before(since)
+				offender.OOMKilled = offender.OOMKilled || (terminated.Reason == "OOMKilled" && terminatedInWindow)
+				recent = recent || terminatedInWindow
+			}
+			if !recent || (!offender.OOMKilled && !offender.CrashLoop && offender.Restarts == 0) {
+				continue
+			}
+			var limit resource.Quantity
+			if container := findContainer(pod, status.Name); container != nil {
+				if request, ok := container.Resources.Requests[v1.ResourceMemory]; ok {
+					offender.MemoryRequest = request.String()
+				}
+				if l, ok := container.Resources.Limits[v1.ResourceMemory]; ok {
+					limit = l
+					offender.MemoryLimit = limit.String()
+				}
+			}
+			if memoryUsage, ok := usage[pod.Namespace+"/"+pod.Name+"/"+status.Name]; ok {
+				offender.MemoryUsage = memoryUsage.String()
+				if limit.Value() > 0 {
+					offender.MemoryUsageOfLimit = fmt.Sprintf("%d%%", memoryUsage.Value()*100/limit.Value())
+				}
+			}
+			offenders = append(offenders, offender)
+		}
+	}
+	slices.SortStableFunc(offenders, func(a, b RestartOffender) int {
+		return cmp.Or(
+			cmp.Compare(b.Restarts, a.Restarts),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Pod, b.Pod),
+			cmp.Compare(a.Container, b.Container),
+		)
+	})
+	return offenders
+}
+
+func findContainer(pod v1.Pod, name string) *v1.Container {
+	for _, containers := range [][]v1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		if idx := slices.IndexFunc(containers, func(c v1.Container) bool { return c.Name == name }); idx >= 0 {
+			return &containers[idx]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type RestartsTestSuite struct {
+	suite.Suite
+}
+
+func restartingPod(name string, restarts int32, lastReason string, finishedAt time.Time, waitingReason string) v1.Pod {
+	status := v1.ContainerStatus{Name: "app", RestartCount: restarts}
+	if lastReason != "" {
+		status.LastTerminationState.Terminated = &v1.ContainerStateTerminated{Reason: lastReason, ExitCode: 137, FinishedAt: metav1.NewTime(finishedAt)}
+	}
+	if waitingReason != "" {
+		status.State.Waiting = &v1.ContainerStateWaiting{Reason: waitingReason}
+	}
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Resources: v1.ResourceRequirements{
+			Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("100Mi")},
+		}}}},
+		Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{status}},
+	}
+}
+
+func (s *RestartsTestSuite) TestRestartOffenders() {
+	now := time.Now()
+	pods := []v1.Pod{
+		restartingPod("healthy", 0, "", time.Time{}, ""),
+		restartingPod("old-oom", 2, "OOMKilled", now.Add(-48*time.Hour), ""),
+		restartingPod("recent-oom", 3, "OOMKilled", now.Add(-time.Hour), ""),
+		restartingPod("crash-loop", 12, "Error", now.Add(-48*time.Hour), "CrashLoopBackOff"),
+	}
+	usage := map[string]resource.Quantity{"default/recent-oom/app": resource.MustParse("90Mi")}
+	offenders := restartOffenders(pods, usage, now.Add(-24*time.Hour))
+	s.Run("only reports containers OOMKilled or crash looping in the window", func() {
+		s.Require().Len(offenders, 2)
+	})
+	s.Run("ranks offenders by restarts", func() {
+		s.Equal("crash-loop", offenders[0].Pod)
+		s.True(offenders[0].CrashLoop)
+		s.False(offenders[0].OOMKilled)
+		s.Equal("recent-oom", offenders[1].Pod)
+		s.True(offenders[1].OOMKilled)
+	})
+	s.Run("correlates with memory limits and usage", func() {
+		s.Equal("100Mi", offenders[1].MemoryLimit)
+		s.Equal("90Mi", offenders[1].MemoryUsage)
+		s.Equal("90%", offenders[1].MemoryUsageOfLimit)
+		s.Empty(offenders[0].MemoryUsage)
+	})
+}
+
+func TestRestarts(t *testing.T) {
+	suite.Run(t, new(RestartsTestSuite))
+}
@@ -0,0 +1,135 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var openShiftRoutes = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+// RouteSummary is the host, backend, TLS, and admission summary of an OpenShift Route.
+type RouteSummary struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Service   string `json:"service"`
+	// AlternateBackends are the other Services the traffic is split with (name=weight)
+	AlternateBackends []string  `json:"alternateBackends,omitempty"`
+	TargetPort        string    `json:"targetPort,omitempty"`
+	TLS               *RouteTLS `json:"tls,omitempty"`
+	// Admitted is true when at least one router admitted the Route
+	Admitted bool     `json:"admitted"`
+	Issues   []string `json:"issues,omitempty"`
+}
+
+// RouteTLS is the TLS configuration of an OpenShift Route.
+type RouteTLS struct {
+	Termination                   string `json:"termination"`
+	InsecureEdgeTerminationPolicy string `json:"insecureEdgeTerminationPolicy,omitempty"`
+	// NotAfter and ExpiresIn are only available when the certificate is set in the Route
+	NotAfter  string `json:"notAfter,omitempty"`
+	ExpiresIn string `json:"expiresIn,omitempty"`
+}
+
+// RoutesList lists the OpenShift Routes in the provided namespace (all namespaces if empty) with their URL, backend
+// Services, TLS termination, and router admission status. Routes pointing to missing Services are flagged.
+func (c *Core) RoutesList(ctx context.Context, namespace string) ([]RouteSummary, error) {
+	routes, err := c.DynamicClient().Resource(openShiftRoutes).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var services []v1.Service
+	// The Services are best-effort, they might not be accessible
+	if serviceList, err := c.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		services = serviceList.Items
+	}
+	return summarizeRoutes(routes.Items, services, time.Now()), nil
+}
+
+func summarizeRoutes(routes []unstructured.Unstructured, services []v1.Service, now time.Time) []RouteSummary {
+	existingServices := map[string]bool{}
+	for _, service := range services {
+		existingServices[service.Namespace+"/"+service.Name] = true
+	}
+	summaries := make([]RouteSummary, 0, len(routes))
+	for _, route := range routes {
+		summary := RouteSummary{Namespace: route.GetNamespace(), Name: route.GetName()}
+		host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+		path, _, _ := unstructured.NestedString(route.Object, "spec", "path")
+		summary.Service, _, _ = unstructured.NestedString(route.Object, "spec", "to", "name")
+		backends := []string{summary.Service}
+		alternateBackends, _, _ := unstructured.NestedSlice(route.Object, "spec", "alternateBackends")
+		for _, alternateBackend := range alternateBackends {
+			backend, _ := alternateBackend.(map[string]any)
+			name, _ := backend["name"].(string)
+			summary.AlternateBackends = append(summary.AlternateBackends, fmt.Sprintf("%s=%v", name, backend["weight"]))
+			backends = append(backends, name)
+		}
+		if targetPort, found, _ := unstructured.NestedFieldNoCopy(route.Object, "spec", "port", "targetPort"); found {
+			summary.TargetPort = fmt.Sprint(targetPort)
+		}
+		scheme := "http"
+		if tls, found, _ := unstructured.NestedMap(route.Object, "spec", "tls"); found {
+			scheme = "https"
+			summary.TLS = &RouteTLS{}
+			summary.TLS.Termination, _ = tls["termination"].(string)
+			summary.TLS.InsecureEdgeTerminationPolicy, _ = tls["insecureEdgeTerminationPolicy"].(string)
+			if certificate, _ := tls["certificate"].(string); certificate != "" {
+				if leaf, err := parseLeafCertificate([]byte(certificate)); err != nil {
+					summary.Issues = append(summary.Issues, "spec.tls.certificate is not a valid PEM encoded certificate")
+				} else {
+					audit := CertificateAudit{}
+					audit.setExpiry(leaf.NotAfter, now, 0)
+					summary.TLS.NotAfter, summary.TLS.ExpiresIn = audit.NotAfter, audit.ExpiresIn
+					if audit.Status == CertificateStatusExpired {
+						summary.Issues = append(summary.Issues, "certificate expired on "+audit.NotAfter)
+					}
+					if host != "" && leaf.VerifyHostname(host) != nil {
+						summary.Issues = append(summary.Issues, fmt.Sprintf("host %s is not covered by the certificate SANs", host))
+					}
+				}
+			}
+		}
+		if host != "" {
+			summary.URL = scheme + "://" + host + path
+		}
+		if len(services) > 0 {
+			for _, backend := range backends {
+				if backend != "" && !existingServices[summary.Namespace+"/"+backend] {
+					summary.Issues = append(summary.Issues, fmt.Sprintf("Service %s not found", backend))
+				}
+			}
+		}
+		ingresses, _, _ := unstructured.NestedSlice(route.Object, "status", "ingress")
+		for _, ingress := range ingresses {
+			ingress, _ := ingress.(map[string]any)
+			conditions, _ := ingress["conditions"].([]any)
+			for _, condition := range conditions {
+				condition, _ := condition.(map[string]any)
+				if condition["type"] != "Admitted" {
+					continue
+				}
+				if condition["status"] == "True" {
+					summary.Admitted = true
+				} else {
+					message, _ := condition["message"].(string)
+					reason, _ := condition["reason"].(string)
+					summary.Issues = append(summary.Issues, fmt.Sprintf("not admitted by router %v: %s", ingress["routerName"], cmp.Or(message, reason)))
+				}
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	slices.SortFunc(summaries, func(a, b RouteSummary) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	return summaries
+}
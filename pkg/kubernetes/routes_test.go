@@ -0,0 +1,98 @@
+package kubernetes
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type RoutesTestSuite struct {
+	suite.Suite
+}
+
+func (s *RoutesTestSuite) certificate(notAfter time.Time, dnsNames ...string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), DNSNames: dnsNames, NotBefore: notAfter.Add(-90 * 24 * time.Hour), NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	s.Require().NoError(err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func (s *RoutesTestSuite) TestSummarizeRoutes() {
+	now := time.Now()
+	route := func(name string, spec map[string]any, admitted string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "route.openshift.io/v1",
+			"kind":       "Route",
+			"metadata":   map[string]any{"name": name, "namespace": "default"},
+			"spec":       spec,
+			"status": map[string]any{"ingress": []any{map[string]any{
+				"routerName": "default",
+				"conditions": []any{map[string]any{"type": "Admitted", "status": admitted, "reason": "HostAlreadyClaimed"}},
+			}}},
+		}}
+	}
+	routes := []unstructured.Unstructured{
+		route("web", map[string]any{
+			"host": "web.apps.example.com", "path": "/api",
+			"to":                map[string]any{"kind": "Service", "name": "web", "weight": int64(90)},
+			"alternateBackends": []any{map[string]any{"kind": "Service", "name": "web-canary", "weight": int64(10)}},
+			"port":              map[string]any{"targetPort": "http"},
+			"tls": map[string]any{"termination": "edge", "insecureEdgeTerminationPolicy": "Redirect",
+				"certificate": s.certificate(now.Add(-time.Hour), "other.example.com")},
+		}, "True"),
+		route("admin", map[string]any{"host": "admin.apps.example.com", "to": map[string]any{"kind": "Service", "name": "admin"}}, "False"),
+	}
+	services := []v1.Service{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: "default"}},
+	}
+	summaries := summarizeRoutes(routes, services, now)
+	s.Require().Len(summaries, 2)
+	s.Run("sorts by namespace and name", func() {
+		s.Equal("admin", summaries[0].Name)
+		s.Equal("web", summaries[1].Name)
+	})
+	s.Run("summarizes the URL and backends", func() {
+		s.Equal("https://web.apps.example.com/api", summaries[1].URL)
+		s.Equal("web", summaries[1].Service)
+		s.Equal([]string{"web-canary=10"}, summaries[1].AlternateBackends)
+		s.Equal("http", summaries[1].TargetPort)
+		s.Equal("http://admin.apps.example.com", summaries[0].URL)
+		s.Nil(summaries[0].TLS)
+	})
+	s.Run("summarizes the TLS configuration", func() {
+		s.Require().NotNil(summaries[1].TLS)
+		s.Equal("edge", summaries[1].TLS.Termination)
+		s.Equal("Redirect", summaries[1].TLS.InsecureEdgeTerminationPolicy)
+		s.NotEmpty(summaries[1].TLS.NotAfter)
+	})
+	s.Run("flags expired and mismatching certificates and missing services", func() {
+		s.True(summaries[1].Admitted)
+		s.Contains(summaries[1].Issues, "certificate expired on "+summaries[1].TLS.NotAfter)
+		s.Contains(summaries[1].Issues, "host web.apps.example.com is not covered by the certificate SANs")
+		s.Contains(summaries[1].Issues, "Service web-canary not found")
+	})
+	s.Run("flags routes not admitted", func() {
+		s.False(summaries[0].Admitted)
+		s.Equal([]string{"not admitted by router default: HostAlreadyClaimed"}, summaries[0].Issues)
+	})
+	s.Run("skips the service check when the services are not accessible", func() {
+		s.NotContains(summarizeRoutes(routes, nil, now)[1].Issues, "Service web-canary not found")
+	})
+}
+
+func TestRoutes(t *testing.T) {
+	suite.Run(t, new(RoutesTestSuite))
+}
@@ -0,0 +1,299 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/klog/v2"
+	resourceutil "k8s.io/kubectl/pkg/util/resource"
+)
+
+// SchedulingDiagnosis explains why a Pod is (not) scheduled.
+type SchedulingDiagnosis struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Scheduled bool   `json:"scheduled"`
+	Node      string `json:"node,omitempty"`
+	// Reasons reported by the scheduler (PodScheduled condition and FailedScheduling events)
+	Reasons  []string          `json:"reasons,omitempty"`
+	Requests map[string]string `json:"requests,omitempty"`
+	// Nodes contains the per-node fit analysis
+	Nodes        []NodeFit `json:"nodes,omitempty"`
+	Findings     []Finding `json:"findings"`
+	Remediations []string  `json:"remediations,omitempty"`
+}
+
+// NodeFit describes whether the diagnosed Pod fits a node, and the reasons why not.
+type NodeFit struct {
+	Name    string   `json:"name"`
+	Fits    bool     `json:"fits"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// PodsDiagnoseScheduling analyzes why the Pod is Pending: scheduler reasons, per-node fit (taints, node selector and
+// affinity, resource shortfalls), and ResourceQuota and PodDisruptionBudget constraints.
+func (c *Core) PodsDiagnoseScheduling(ctx context.Context, namespace, name string) (*SchedulingDiagnosis, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	raw, err := c.ResourcesGet(ctx, &schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"}, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	pod := &v1.Pod{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(raw.Object, pod); err != nil {
+		return nil, err
+	}
+	diagnosis := &SchedulingDiagnosis{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		Scheduled: pod.Spec.NodeName != "",
+		Node:      pod.Spec.NodeName,
+		Findings:  []Finding{},
+	}
+	if diagnosis.Scheduled {
+		return diagnosis, nil
+	}
+	var events []v1.Event
+	if eventList, _ := c.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{"involvedObject.kind": "Pod", "involvedObject.name": name, "reason": "FailedScheduling"}.String(),
+	}); eventList != nil {
+		events = eventList.Items
+	}
+	nodes, err := c.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	// Pods that consume node resources (the scheduler ignores terminated Pods)
+	pods, err := c.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=" + string(v1.PodSucceeded) + ",status.phase!=" + string(v1.PodFailed),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	var quotas []v1.ResourceQuota
+	if quotaList, _ := c.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{}); quotaList != nil {
+		quotas = quotaList.Items
+	}
+	var pdbs []policyv1.PodDisruptionBudget
+	if pdbList, _ := c.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{}); pdbList != nil {
+		pdbs = pdbList.Items
+	}
+	diagnoseScheduling(diagnosis, pod, events, nodes.Items, pods.Items, quotas, pdbs)
+	return diagnosis, nil
+}
+
+func diagnoseScheduling(diagnosis *SchedulingDiagnosis, pod *v1.Pod, events []v1.Event, nodes []v1.Node, pods []v1.Pod,
+	quotas []v1.ResourceQuota, pdbs []policyv1.PodDisruptionBudget) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse && condition.Message != "" {
+			diagnosis.Reasons = append(diagnosis.Reasons, condition.Message)
+		}
+	}
+	for _, event := range events {
+		if message := strings.TrimSpace(event.Message); !slices.Contains(diagnosis.Reasons, message) {
+			diagnosis.Reasons = append(diagnosis.Reasons, message)
+		}
+	}
+
+	requests, _ := resourceutil.PodRequestsAndLimits(pod)
+	diagnosis.Requests = map[string]string{}
+	for name, quantity := range requests {
+		diagnosis.Requests[string(name)] = quantity.String()
+	}
+	podsByNode := map[string][]v1.Pod{}
+	for _, p := range pods {
+		if p.Spec.NodeName != "" && p.UID != pod.UID {
+			podsByNode[p.Spec.NodeName] = append(podsByNode[p.Spec.NodeName], p)
+		}
+	}
+	remediations := map[string]bool{}
+	fittingNodes := 0
+	for _, node := range nodes {
+		fit := nodeFit(pod, requests, &node, podsByNode[node.Name], remediations)
+		if fit.Fits {
+			fittingNodes++
+		}
+		diagnosis.Nodes = append(diagnosis.Nodes, fit)
+	}
+	switch {
+	case len(nodes) == 0:
+		diagnosis.Findings = append(diagnosis.Findings, Finding{
+			Severity: SeverityCritical, Reason: "NoNodes", Message: "there are no nodes in the cluster",
+		})
+	case fittingNodes == 0:
+		diagnosis.Findings = append(diagnosis.Findings, Finding{
+			Severity: SeverityCritical, Reason: "Unschedulable", Message: fmt.Sprintf("the Pod does not fit any of the %d nodes", len(nodes)),
+		})
+	default:
+		diagnosis.Findings = append(diagnosis.Findings, Finding{
+			Severity: SeverityInfo, Reason: "SchedulableNodes",
+			Message: fmt.Sprintf("the Pod fits %d of %d nodes, it may be waiting for the scheduler or blocked by constraints not evaluated here (e.g. pod (anti-)affinity, topology spread, volumes)", fittingNodes, len(nodes)),
+		})
+	}
+
+	for _, quota := range quotas {
+		for name, hard := range quota.Status.Hard {
+			if used, ok := quota.Status.Used[name]; ok && used.Cmp(hard) >= 0 {
+				diagnosis.Findings = append(diagnosis.Findings, Finding{
+					Severity: SeverityWarning, Reason: "QuotaExhausted",
+					Message: fmt.Sprintf("ResourceQuota %s has exhausted %s (used %s of %s)", quota.Name, name, used.String(), hard.String()),
+				})
+				remediations["Increase the ResourceQuota limits or free resources in the namespace"] = true
+			}
+		}
+	}
+	for _, pdb := range pdbs {
+		if pdb.Status.DisruptionsAllowed > 0 || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		diagnosis.Findings = append(diagnosis.Findings, Finding{
+			Severity: SeverityInfo, Reason: "DisruptionBlocked",
+			Message: fmt.Sprintf("PodDisruptionBudget %s/%s allows no disruptions, the Pods it protects cannot be preempted or evicted to make room", pdb.Namespace, pdb.Name),
+		})
+	}
+	if len(diagnosis.Reasons) == 0 && len(events) == 0 && pod.Spec.SchedulerName != "" && pod.Spec.SchedulerName != v1.DefaultSchedulerName {
+		diagnosis.Findings = append(diagnosis.Findings, Finding{
+			Severity: SeverityWarning, Reason: "CustomScheduler",
+			Message: fmt.Sprintf("the Pod is assigned to scheduler %s which has not reported any scheduling attempt", pod.Spec.SchedulerName),
+		})
+		remediations["Verify that the scheduler "+pod.Spec.SchedulerName+" is running"] = true
+	}
+	for remediation := range remediations {
+		diagnosis.Remediations = append(diagnosis.Remediations, remediation)
+	}
+	slices.Sort(diagnosis.Remediations)
+}
+
+func nodeFit(pod *v1.Pod, requests v1.ResourceList, node *v1.Node, nodePods []v1.Pod, remediations map[string]bool) NodeFit {
+	fit := NodeFit{Name: node.Name}
+	if node.Spec.Unschedulable {
+		fit.Reasons = append(fit.Reasons, "node is cordoned (unschedulable)")
+		remediations["Uncordon the nodes (kubectl uncordon) or add capacity to the cluster"] = true
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady && condition.Status != v1.ConditionTrue {
+			fit.Reasons = append(fit.Reasons, "node is not ready")
+		}
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == v1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !slices.ContainsFunc(pod.Spec.Tolerations, func(toleration v1.Toleration) bool {
+			return toleration.ToleratesTaint(klog.Background(), taint, false)
+		}) {
+			fit.Reasons = append(fit.Reasons, fmt.Sprintf("untolerated taint %s", taint.ToString()))
+			remediations["Add a toleration for the node taints to the Pod, or remove the taints from the nodes"] = true
+		}
+	}
+	if len(pod.Spec.NodeSelector) > 0 && !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		fit.Reasons = append(fit.Reasons, fmt.Sprintf("node labels don't match the node selector %s", labels.Set(pod.Spec.NodeSelector).String()))
+		remediations["Fix the Pod nodeSelector or label the nodes accordingly"] = true
+	}
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil &&
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil &&
+		!matchesNodeSelectorTerms(node, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) {
+		fit.Reasons = append(fit.Reasons, "node doesn't match the required node affinity")
+		remediations["Relax the Pod required node affinity or label the nodes accordingly"] = true
+	}
+	requested := podsRequests(nodePods)
+	for _, name := range slices.Sorted(maps.Keys(requests)) {
+		quantity := requests[name]
+		allocatable, ok := node.Status.Allocatable[name]
+		if !ok {
+			if !quantity.IsZero() {
+				fit.Reasons = append(fit.Reasons, fmt.Sprintf("node has no allocatable %s", name))
+			}
+			continue
+		}
+		available := allocatable.DeepCopy()
+		available.Sub(requested[name])
+		if quantity.Cmp(available) > 0 {
+			fit.Reasons = append(fit.Reasons, fmt.Sprintf("insufficient %s (requested %s, available %s of %s allocatable)",
+				name, quantity.String(), available.String(), allocatable.String()))
+			remediations["Reduce the Pod resource requests, free resources by scaling down other workloads, or add nodes to the cluster"] = true
+		}
+	}
+	if allocatablePods, ok := node.Status.Allocatable[v1.ResourcePods]; ok && int64(len(nodePods)) >= allocatablePods.Value() {
+		fit.Reasons = append(fit.Reasons, fmt.Sprintf("too many pods (%d of %s)", len(nodePods), allocatablePods.String()))
+	}
+	fit.Fits = len(fit.Reasons) == 0
+	return fit
+}
+
+// podsRequests returns the sum of the resource requests of the provided Pods
+func podsRequests(pods []v1.Pod) v1.ResourceList {
+	requested := v1.ResourceList{}
+	for i := range pods {
+		podRequests, _ := resourceutil.PodRequestsAndLimits(&pods[i])
+		for name, quantity := range podRequests {
+			total := requested[name]
+			total.Add(quantity)
+			requested[name] = total
+		}
+	}
+	return requested
+}
+
+func matchesNodeSelectorTerms(node *v1.Node, terms []v1.NodeSelectorTerm) bool {
+	for _, term := range terms {
+		if matchesNodeSelectorTerm(node, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesNodeSelectorTerm(node *v1.Node, term v1.NodeSelectorTerm) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	selector := labels.NewSelector()
+	for _, expression := range term.MatchExpressions {
+		operator, ok := map[v1.NodeSelectorOperator]selection.Operator{
+			v1.NodeSelectorOpIn:           selection.In,
+			v1.NodeSelectorOpNotIn:        selection.NotIn,
+			v1.NodeSelectorOpExists:       selection.Exists,
+			v1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+			v1.NodeSelectorOpGt:           selection.GreaterThan,
+			v1.NodeSelectorOpLt:           selection.LessThan,
+		}[expression.Operator]
+		if !ok {
+			return false
+		}
+		requirement, err := labels.NewRequirement(expression.Key, operator, expression.Values)
+		if err != nil {
+			return false
+		}
+		selector = selector.Add(*requirement)
+	}
+	if !selector.Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	for _, field := range term.MatchFields {
+		// metadata.name is the only supported field
+		if field.Key != "metadata.name" {
+			return false
+		}
+		contains := slices.Contains(field.Values, node.Name)
+		if (field.Operator == v1.NodeSelectorOpIn && !contains) || (field.Operator == v1.NodeSelectorOpNotIn && contains) {
+			return false
+		}
+	}
+	return true
+}
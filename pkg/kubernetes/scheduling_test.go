@@ -0,0 +1,107 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type SchedulingTestSuite struct {
+	suite.Suite
+}
+
+func schedulingNode(name string, cpu string) v1.Node {
+	return v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"kubernetes.io/hostname": name}},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu), v1.ResourcePods: resource.MustParse("110")},
+			Conditions:  []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+}
+
+func pendingPod(cpu string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default", UID: "pending"},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+		}}}},
+	}
+}
+
+func (s *SchedulingTestSuite) TestDiagnoseScheduling() {
+	s.Run("reports resource shortfall considering the pods on the node", func() {
+		diagnosis := &SchedulingDiagnosis{}
+		running := v1.Pod{Spec: v1.PodSpec{NodeName: "node-1", Containers: []v1.Container{{Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1500m")},
+		}}}}}
+		diagnoseScheduling(diagnosis, pendingPod("1"), nil, []v1.Node{schedulingNode("node-1", "2")}, []v1.Pod{running}, nil, nil)
+		s.Require().Len(diagnosis.Nodes, 1)
+		s.False(diagnosis.Nodes[0].Fits)
+		s.Equal([]string{"insufficient cpu (requested 1, available 500m of 2 allocatable)"}, diagnosis.Nodes[0].Reasons)
+		s.Equal("Unschedulable", diagnosis.Findings[0].Reason)
+		s.Equal("1", diagnosis.Requests["cpu"])
+		s.NotEmpty(diagnosis.Remediations)
+	})
+	s.Run("reports untolerated taints and cordoned nodes", func() {
+		diagnosis := &SchedulingDiagnosis{}
+		tainted := schedulingNode("tainted", "4")
+		tainted.Spec.Taints = []v1.Taint{{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}}
+		cordoned := schedulingNode("cordoned", "4")
+		cordoned.Spec.Unschedulable = true
+		diagnoseScheduling(diagnosis, pendingPod("1"), nil, []v1.Node{tainted, cordoned}, nil, nil, nil)
+		s.Equal([]string{"untolerated taint dedicated=gpu:NoSchedule"}, diagnosis.Nodes[0].Reasons)
+		s.Equal([]string{"node is cordoned (unschedulable)"}, diagnosis.Nodes[1].Reasons)
+	})
+	s.Run("tolerated taints fit", func() {
+		diagnosis := &SchedulingDiagnosis{}
+		tainted := schedulingNode("tainted", "4")
+		tainted.Spec.Taints = []v1.Taint{{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}}
+		pod := pendingPod("1")
+		pod.Spec.Tolerations = []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "gpu"}}
+		diagnoseScheduling(diagnosis, pod, nil, []v1.Node{tainted}, nil, nil, nil)
+		s.True(diagnosis.Nodes[0].Fits)
+		s.Equal("SchedulableNodes", diagnosis.Findings[0].Reason)
+	})
+	s.Run("reports node selector and affinity mismatches", func() {
+		diagnosis := &SchedulingDiagnosis{}
+		pod := pendingPod("1")
+		pod.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+		pod.Spec.Affinity = &v1.Affinity{NodeAffinity: &v1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{{MatchExpressions: []v1.NodeSelectorRequirement{{
+				Key: "kubernetes.io/hostname", Operator: v1.NodeSelectorOpIn, Values: []string{"node-2"},
+			}}}},
+		}}}
+		diagnoseScheduling(diagnosis, pod, nil, []v1.Node{schedulingNode("node-1", "4")}, nil, nil, nil)
+		s.Equal([]string{
+			"node labels don't match the node selector disktype=ssd",
+			"node doesn't match the required node affinity",
+		}, diagnosis.Nodes[0].Reasons)
+	})
+	s.Run("reports scheduler reasons, exhausted quotas and blocking PDBs", func() {
+		diagnosis := &SchedulingDiagnosis{}
+		pod := pendingPod("1")
+		pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodScheduled, Status: v1.ConditionFalse, Message: "0/1 nodes are available"}}
+		events := []v1.Event{{Reason: "FailedScheduling", Message: "0/1 nodes are available"}, {Reason: "FailedScheduling", Message: "preemption is not helpful"}}
+		quota := v1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "compute"}, Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{v1.ResourceRequestsCPU: resource.MustParse("2")},
+			Used: v1.ResourceList{v1.ResourceRequestsCPU: resource.MustParse("2")},
+		}}
+		pdb := policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+		}
+		diagnoseScheduling(diagnosis, pod, events, []v1.Node{schedulingNode("node-1", "4")}, nil, []v1.ResourceQuota{quota}, []policyv1.PodDisruptionBudget{pdb})
+		s.Equal([]string{"0/1 nodes are available", "preemption is not helpful"}, diagnosis.Reasons)
+		s.Equal("ResourceQuota compute has exhausted requests.cpu (used 2 of 2)", diagnosis.Findings[1].Message)
+		s.Equal("DisruptionBlocked", diagnosis.Findings[2].Reason)
+	})
+}
+
+func TestScheduling(t *testing.T) {
+	suite.Run(t, new(SchedulingTestSuite))
+}
@@ -0,0 +1,249 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// Pod Security Standards levels https://kubernetes.io/docs/concepts/security/pod-security-standards/
+const (
+	PodSecurityPrivileged = "privileged"
+	PodSecurityBaseline   = "baseline"
+	PodSecurityRestricted = "restricted"
+
+	// podSecurityEnforceLabel is the Namespace label with the level enforced by the Pod Security Admission controller
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+)
+
+var (
+	// podSecurityBaselineCapabilities are the capabilities that can be added under the baseline level
+	podSecurityBaselineCapabilities = []v1.Capability{"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+		"NET_BIND_SERVICE", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT"}
+	// podSecurityRestrictedVolumes are the volume types allowed under the restricted level
+	podSecurityRestrictedVolumes = []string{"configMap", "csi", "downwardAPI", "emptyDir", "ephemeral", "persistentVolumeClaim", "projected", "secret"}
+)
+
+// NamespaceSecurityPosture summarizes the Pod Security Standards evaluation of the Pods of a Namespace.
+type NamespaceSecurityPosture struct {
+	Namespace string `json:"namespace"`
+	// EnforcedLevel is the level enforced by the Pod Security Admission controller (if any)
+	EnforcedLevel string `json:"enforcedLevel,omitempty"`
+	Pods          int    `json:"pods"`
+	// FailBaseline is the number of Pods that fail the baseline level (i.e. only compliant with privileged)
+	FailBaseline int `json:"failBaseline"`
+	// FailRestricted is the number of Pods that pass the baseline level but fail the restricted level
+	FailRestricted int `json:"failRestricted"`
+	// Checks is the number of Pods failing each check
+	Checks     map[string]int      `json:"checks,omitempty"`
+	Violations []PodSecurityReport `json:"violations,omitempty"`
+}
+
+// PodSecurityReport is the Pod Security Standards evaluation of a Pod.
+type PodSecurityReport struct {
+	Pod string `json:"pod"`
+	// Level is the most restrictive level the Pod complies with
+	Level      string                 `json:"level"`
+	Violations []PodSecurityViolation `json:"violations"`
+}
+
+// PodSecurityViolation is a failed Pod Security Standards check.
+type PodSecurityViolation struct {
+	// Level is the level the check belongs to, the Pod fails this level (and the more restrictive ones)
+	Level   string `json:"level"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+// SecurityPosture evaluates the Pods in the provided namespace (all namespaces if empty) against the Pod Security
+// Standards and summarizes the violations per namespace.
+func (c *Core) SecurityPosture(ctx context.Context, namespace string) ([]NamespaceSecurityPosture, error) {
+	pods, err := c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	enforced := map[string]string{}
+	// The enforced levels are best-effort, the Namespaces might not be accessible
+	if namespace != "" {
+		if ns, err := c.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err == nil {
+			enforced[ns.Name] = ns.Labels[podSecurityEnforceLabel]
+		}
+	} else if namespaces, err := c.CoreV1().Namespaces().List(ctx, metav1.ListOptions{}); err == nil {
+		for _, ns := range namespaces.Items {
+			enforced[ns.Name] = ns.Labels[podSecurityEnforceLabel]
+		}
+	}
+	return securityPosture(pods.Items, enforced), nil
+}
+
+func securityPosture(pods []v1.Pod, enforced map[string]string) []NamespaceSecurityPosture {
+	postures := map[string]*NamespaceSecurityPosture{}
+	for i := range pods {
+		pod := &pods[i]
+		posture, ok := postures[pod.Namespace]
+		if !ok {
+			posture = &NamespaceSecurityPosture{Namespace: pod.Namespace, EnforcedLevel: enforced[pod.Namespace], Checks: map[string]int{}}
+			postures[pod.Namespace] = posture
+		}
+		posture.Pods++
+		report := evaluatePodSecurity(pod)
+		switch report.Level {
+		case PodSecurityPrivileged:
+			posture.FailBaseline++
+		case PodSecurityBaseline:
+			posture.FailRestricted++
+		default:
+			continue
+		}
+		checks := map[string]bool{}
+		for _, violation := range report.Violations {
+			checks[violation.Check] = true
+		}
+		for check := range checks {
+			posture.Checks[check]++
+		}
+		posture.Violations = append(posture.Violations, report)
+	}
+	ret := make([]NamespaceSecurityPosture, 0, len(postures))
+	for _, namespace := range slices.Sorted(maps.Keys(postures)) {
+		posture := postures[namespace]
+		slices.SortFunc(posture.Violations, func(a, b PodSecurityReport) int {
+			return cmp.Or(cmp.Compare(podSecurityLevelRank(a.Level), podSecurityLevelRank(b.Level)), cmp.Compare(a.Pod, b.Pod))
+		})
+		ret = append(ret, *posture)
+	}
+	return ret
+}
+
+func podSecurityLevelRank(level string) int {
+	return slices.Index([]string{PodSecurityPrivileged, PodSecurityBaseline, PodSecurityRestricted}, level)
+}
+
+// evaluatePodSecurity evaluates a Pod against the baseline and restricted Pod Security Standards
+func evaluatePodSecurity(pod *v1.Pod) PodSecurityReport {
+	report := PodSecurityReport{Pod: pod.Name, Violations: make([]PodSecurityViolation, 0)}
+	fail := func(level, check, format string, args ...any) {
+		report.Violations = append(report.Violations, PodSecurityViolation{Level: level, Check: check, Message: fmt.Sprintf(format, args...)})
+	}
+	spec := &pod.Spec
+	podContext := ptr.Deref(spec.SecurityContext, v1.PodSecurityContext{})
+
+	// Baseline
+	for name, enabled := range map[string]bool{"hostNetwork": spec.HostNetwork, "hostPID": spec.HostPID, "hostIPC": spec.HostIPC} {
+		if enabled {
+			fail(PodSecurityBaseline, "hostNamespaces", "%s is enabled", name)
+		}
+	}
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			fail(PodSecurityBaseline, "hostPathVolumes", "volume %s mounts the host path %s", volume.Name, volume.HostPath.Path)
+		}
+	}
+	if isSeccompUnconfined(podContext.SeccompProfile) {
+		fail(PodSecurityBaseline, "seccompProfile", "pod seccomp profile is Unconfined")
+	}
+	allContainers := slices.Concat(spec.InitContainers, spec.Containers)
+	for _, ephemeral := range spec.EphemeralContainers {
+		allContainers = append(allContainers, v1.Container(ephemeral.EphemeralContainerCommon))
+	}
+	for _, container := range allContainers {
+		securityContext := ptr.Deref(container.SecurityContext, v1.SecurityContext{})
+		if ptr.Deref(securityContext.Privileged, false) {
+			fail(PodSecurityBaseline, "privileged", "container %s is privileged", container.Name)
+		}
+		if capabilities := securityContext.Capabilities; capabilities != nil {
+			for _, capability := range capabilities.Add {
+				if !slices.Contains(podSecurityBaselineCapabilities, capability) {
+					fail(PodSecurityBaseline, "capabilities", "container %s adds the capability %s", container.Name, capability)
+				}
+			}
+		}
+		for _, port := range container.Ports {
+			if port.HostPort != 0 {
+				fail(PodSecurityBaseline, "hostPorts", "container %s uses the host port %d", container.Name, port.HostPort)
+			}
+		}
+		if procMount := ptr.Deref(securityContext.ProcMount, v1.DefaultProcMount); procMount != v1.DefaultProcMount {
+			fail(PodSecurityBaseline, "procMount", "container %s uses the %s proc mount", container.Name, procMount)
+		}
+		if isSeccompUnconfined(securityContext.SeccompProfile) {
+			fail(PodSecurityBaseline, "seccompProfile", "container %s seccomp profile is Unconfined", container.Name)
+		}
+	}
+
+	// Restricted
+	for _, volume := range spec.Volumes {
+		if volumeType := volumeSourceType(volume.VolumeSource); !slices.Contains(podSecurityRestrictedVolumes, volumeType) {
+			fail(PodSecurityRestricted, "restrictedVolumes", "volume %s uses the %s volume type", volume.Name, volumeType)
+		}
+	}
+	for _, container := range allContainers {
+		securityContext := ptr.Deref(container.SecurityContext, v1.SecurityContext{})
+		if ptr.Deref(securityContext.AllowPrivilegeEscalation, true) {
+			fail(PodSecurityRestricted, "allowPrivilegeEscalation", "container %s doesn't set allowPrivilegeEscalation=false", container.Name)
+		}
+		if runAsNonRoot := cmp.Or(securityContext.RunAsNonRoot, podContext.RunAsNonRoot); !ptr.Deref(runAsNonRoot, false) {
+			fail(PodSecurityRestricted, "runAsNonRoot", "container %s doesn't set runAsNonRoot=true", container.Name)
+		}
+		if runAsUser := cmp.Or(securityContext.RunAsUser, podContext.RunAsUser); runAsUser != nil && *runAsUser == 0 {
+			fail(PodSecurityRestricted, "runAsUser", "container %s runs as the root user (runAsUser=0)", container.Name)
+		}
+		if seccompProfile := cmp.Or(securityContext.SeccompProfile, podContext.SeccompProfile); seccompProfile == nil {
+			fail(PodSecurityRestricted, "seccompProfile", "container %s doesn't set a seccomp profile (RuntimeDefault or Localhost)", container.Name)
+		}
+		capabilities := ptr.Deref(securityContext.Capabilities, v1.Capabilities{})
+		if !slices.Contains(capabilities.Drop, "ALL") {
+			fail(PodSecurityRestricted, "capabilities", "container %s doesn't drop ALL capabilities", container.Name)
+		}
+		for _, capability := range capabilities.Add {
+			if capability != "NET_BIND_SERVICE" && slices.Contains(podSecurityBaselineCapabilities, capability) {
+				fail(PodSecurityRestricted, "capabilities", "container %s adds the capability %s", container.Name, capability)
+			}
+		}
+	}
+
+	report.Level = PodSecurityRestricted
+	for _, violation := range report.Violations {
+		if violation.Level == PodSecurityBaseline {
+			report.Level = PodSecurityPrivileged
+			break
+		}
+		report.Level = PodSecurityBaseline
+	}
+	slices.SortStableFunc(report.Violations, func(a, b PodSecurityViolation) int {
+		return cmp.Or(cmp.Compare(a.Level, b.Level), cmp.Compare(a.Check, b.Check), cmp.Compare(a.Message, b.Message))
+	})
+	return report
+}
+
+func isSeccompUnconfined(profile *v1.SeccompProfile) bool {
+	return profile != nil && profile.Type == v1.SeccompProfileTypeUnconfined
+}
+
+// volumeSourceType returns the name of the (first) volume type set in the VolumeSource (e.g. hostPath, configMap)
+func volumeSourceType(source v1.VolumeSource) string {
+	types := map[string]bool{
+		"hostPath": source.HostPath != nil, "emptyDir": source.EmptyDir != nil, "gcePersistentDisk": source.GCEPersistentDisk != nil,
+		"awsElasticBlockStore": source.AWSElasticBlockStore != nil, "gitRepo": source.GitRepo != nil, "secret": source.Secret != nil,
+		"nfs": source.NFS != nil, "iscsi": source.ISCSI != nil, "glusterfs": source.Glusterfs != nil,
+		"persistentVolumeClaim": source.PersistentVolumeClaim != nil, "rbd": source.RBD != nil, "flexVolume": source.FlexVolume != nil,
+		"cinder": source.Cinder != nil, "cephfs": source.CephFS != nil, "flocker": source.Flocker != nil,
+		"downwardAPI": source.DownwardAPI != nil, "fc": source.FC != nil, "azureFile": source.AzureFile != nil,
+		"configMap": source.ConfigMap != nil, "vsphereVolume": source.VsphereVolume != nil, "quobyte": source.Quobyte != nil,
+		"azureDisk": source.AzureDisk != nil, "photonPersistentDisk": source.PhotonPersistentDisk != nil, "projected": source.Projected != nil,
+		"portworxVolume": source.PortworxVolume != nil, "scaleIO": source.ScaleIO != nil, "storageos": source.StorageOS != nil,
+		"csi": source.CSI != nil, "ephemeral": source.Ephemeral != nil, "image": source.Image != nil,
+	}
+	for _, volumeType := range slices.Sorted(maps.Keys(types)) {
+		if types[volumeType] {
+			return volumeType
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,261 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// SecurityContextKinds are the kinds whose effective security settings can be inspected
+var SecurityContextKinds = []string{"Pod", "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job", "CronJob"}
+
+// Sources of the effective security settings
+const (
+	SecuritySourceContainer = "container"
+	SecuritySourcePod       = "pod"
+	// SecuritySourceAnnotation is the deprecated container.apparmor.security.beta.kubernetes.io/<container> annotation
+	SecuritySourceAnnotation = "annotation"
+	// SecuritySourceDefault is the default of the image, the container runtime, or the kubelet
+	SecuritySourceDefault = "default"
+
+	appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+)
+
+// runtimeDefaultCapabilities are the capabilities granted by default by the container runtimes (containerd and CRI-O)
+var runtimeDefaultCapabilities = []v1.Capability{"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+	"NET_BIND_SERVICE", "NET_RAW", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT"}
+
+// SecuritySetting is the effective value of a security setting and where it is set
+type SecuritySetting struct {
+	Value any `json:"value"`
+	// Source is where the value is set: container, pod, annotation, or default
+	Source string `json:"source"`
+}
+
+// PodSecuritySettings are the effective security settings of a Pod (or of the Pods of a workload), flattened from the
+// Pod and container security contexts and completed with the defaults of the image, the container runtime, and the kubelet.
+type PodSecuritySettings struct {
+	Object string `json:"object"`
+	// PodSecurityLevel is the most restrictive Pod Security Standards level the Pod complies with
+	PodSecurityLevel             string                      `json:"podSecurityLevel"`
+	ServiceAccount               string                      `json:"serviceAccount"`
+	AutomountServiceAccountToken bool                        `json:"automountServiceAccountToken"`
+	HostNetwork                  bool                        `json:"hostNetwork"`
+	HostPID                      bool                        `json:"hostPID"`
+	HostIPC                      bool                        `json:"hostIPC"`
+	HostUsers                    bool                        `json:"hostUsers"`
+	FSGroup                      any                         `json:"fsGroup"`
+	FSGroupChangePolicy          string                      `json:"fsGroupChangePolicy,omitempty"`
+	SupplementalGroups           []int64                     `json:"supplementalGroups,omitempty"`
+	Sysctls                      []string                    `json:"sysctls,omitempty"`
+	Containers                   []ContainerSecuritySettings `json:"containers"`
+}
+
+// ContainerSecuritySettings are the effective security settings of a container
+type ContainerSecuritySettings struct {
+	Name string `json:"name"`
+	// Type is the type of the container: container, init, or ephemeral
+	Type                     string          `json:"type"`
+	RunAsUser                SecuritySetting `json:"runAsUser"`
+	RunAsGroup               SecuritySetting `json:"runAsGroup"`
+	RunAsNonRoot             SecuritySetting `json:"runAsNonRoot"`
+	Privileged               SecuritySetting `json:"privileged"`
+	AllowPrivilegeEscalation SecuritySetting `json:"allowPrivilegeEscalation"`
+	ReadOnlyRootFilesystem   SecuritySetting `json:"readOnlyRootFilesystem"`
+	Capabilities             SecuritySetting `json:"capabilities"`
+	Seccomp                  SecuritySetting `json:"seccomp"`
+	AppArmor                 SecuritySetting `json:"appArmor"`
+	SELinux                  SecuritySetting `json:"seLinux"`
+	ProcMount                SecuritySetting `json:"procMount"`
+}
+
+// SecurityContext returns the effective security settings of the Pod, or of the Pods of the workload (from its Pod template)
+func (c *Core) SecurityContext(ctx context.Context, kind, namespace, name string) (*PodSecuritySettings, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	template, err := c.securityContextTemplate(ctx, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return EffectiveSecuritySettings(kind+"/"+namespace+"/"+name, template), nil
+}
+
+func (c *Core) securityContextTemplate(ctx context.Context, kind, namespace, name string) (*v1.PodTemplateSpec, error) {
+	switch kind {
+	case "Pod":
+		pod, err := c.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &v1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec}, nil
+	case "Deployment", "StatefulSet", "DaemonSet":
+		return c.workloadTemplate(ctx, kind, namespace, name)
+	case "ReplicaSet":
+		replicaSet, err := c.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &replicaSet.Spec.Template, nil
+	case "Job":
+		job, err := c.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &job.Spec.Template, nil
+	case "CronJob":
+		cronJob, err := c.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &cronJob.Spec.JobTemplate.Spec.Template, nil
+	}
+	return nil, fmt.Errorf("unsupported kind %s, supported kinds are %v", kind, SecurityContextKinds)
+}
+
+// EffectiveSecuritySettings flattens the security settings of the Pod template, the container settings take precedence
+// over the Pod settings, and the settings set in neither are reported with the default applied by the image, the
+// container runtime, or the kubelet.
+func EffectiveSecuritySettings(object string, template *v1.PodTemplateSpec) *PodSecuritySettings {
+	spec := &template.Spec
+	podContext := ptr.Deref(spec.SecurityContext, v1.PodSecurityContext{})
+	settings := &PodSecuritySettings{
+		Object:                       object,
+		PodSecurityLevel:             evaluatePodSecurity(&v1.Pod{ObjectMeta: template.ObjectMeta, Spec: *spec}).Level,
+		ServiceAccount:               spec.ServiceAccountName,
+		AutomountServiceAccountToken: ptr.Deref(spec.AutomountServiceAccountToken, true),
+		HostNetwork:                  spec.HostNetwork,
+		HostPID:                      spec.HostPID,
+		HostIPC:                      spec.HostIPC,
+		HostUsers:                    ptr.Deref(spec.HostUsers, true),
+		FSGroup:                      "none, the volumes keep their ownership",
+		SupplementalGroups:           podContext.SupplementalGroups,
+		Containers:                   make([]ContainerSecuritySettings, 0),
+	}
+	if settings.ServiceAccount == "" {
+		settings.ServiceAccount = "default"
+	}
+	if podContext.FSGroup != nil {
+		settings.FSGroup = *podContext.FSGroup
+		settings.FSGroupChangePolicy = string(ptr.Deref(podContext.FSGroupChangePolicy, v1.FSGroupChangeAlways))
+	}
+	for _, sysctl := range podContext.Sysctls {
+		settings.Sysctls = append(settings.Sysctls, sysctl.Name+"="+sysctl.Value)
+	}
+	for _, container := range spec.InitContainers {
+		settings.Containers = append(settings.Containers, containerSecuritySettings("init", container.Name, container.SecurityContext, &podContext, template.Annotations))
+	}
+	for _, container := range spec.Containers {
+		settings.Containers = append(settings.Containers, containerSecuritySettings("container", container.Name, container.SecurityContext, &podContext, template.Annotations))
+	}
+	for _, container := range spec.EphemeralContainers {
+		settings.Containers = append(settings.Containers, containerSecuritySettings("ephemeral", container.Name, container.SecurityContext, &podContext, template.Annotations))
+	}
+	return settings
+}
+
+func containerSecuritySettings(containerType, name string, securityContext *v1.SecurityContext, podContext *v1.PodSecurityContext, annotations map[string]string) ContainerSecuritySettings {
+	sc := ptr.Deref(securityContext, v1.SecurityContext{})
+	privileged := ptr.Deref(sc.Privileged, false)
+	settings := ContainerSecuritySettings{
+		Name:                     name,
+		Type:                     containerType,
+		RunAsUser:                inherited(sc.RunAsUser, podContext.RunAsUser, "the USER of the image, root (0) if not set"),
+		RunAsGroup:               inherited(sc.RunAsGroup, podContext.RunAsGroup, "the group of the USER of the image, root (0) if not set"),
+		RunAsNonRoot:             inherited(sc.RunAsNonRoot, podContext.RunAsNonRoot, false),
+		Privileged:               inherited(sc.Privileged, nil, false),
+		AllowPrivilegeEscalation: inherited(sc.AllowPrivilegeEscalation, nil, true),
+		ReadOnlyRootFilesystem:   inherited(sc.ReadOnlyRootFilesystem, nil, false),
+		Capabilities:             effectiveCapabilities(sc.Capabilities, privileged),
+		ProcMount:                inherited(sc.ProcMount, nil, v1.DefaultProcMount),
+	}
+	switch {
+	case sc.SeccompProfile != nil:
+		settings.Seccomp = SecuritySetting{Value: seccompProfile(sc.SeccompProfile), Source: SecuritySourceContainer}
+	case podContext.SeccompProfile != nil:
+		settings.Seccomp = SecuritySetting{Value: seccompProfile(podContext.SeccompProfile), Source: SecuritySourcePod}
+	case privileged:
+		settings.Seccomp = SecuritySetting{Value: string(v1.SeccompProfileTypeUnconfined), Source: SecuritySourceDefault}
+	default:
+		settings.Seccomp = SecuritySetting{Value: "Unconfined, RuntimeDefault if the kubelet runs with --seccomp-default", Source: SecuritySourceDefault}
+	}
+	switch annotation, ok := annotations[appArmorAnnotationPrefix+name]; {
+	case sc.AppArmorProfile != nil:
+		settings.AppArmor = SecuritySetting{Value: appArmorProfile(sc.AppArmorProfile), Source: SecuritySourceContainer}
+	case podContext.AppArmorProfile != nil:
+		settings.AppArmor = SecuritySetting{Value: appArmorProfile(podContext.AppArmorProfile), Source: SecuritySourcePod}
+	case ok:
+		settings.AppArmor = SecuritySetting{Value: annotation, Source: SecuritySourceAnnotation}
+	case privileged:
+		settings.AppArmor = SecuritySetting{Value: string(v1.AppArmorProfileTypeUnconfined), Source: SecuritySourceDefault}
+	default:
+		settings.AppArmor = SecuritySetting{Value: "RuntimeDefault, if AppArmor is enabled on the node", Source: SecuritySourceDefault}
+	}
+	settings.SELinux = inherited(sc.SELinuxOptions, podContext.SELinuxOptions, "assigned by the container runtime, if SELinux is enabled on the node")
+	// The privilege escalation is always allowed to the privileged containers and to the containers with CAP_SYS_ADMIN
+	if privileged || (sc.Capabilities != nil && slices.Contains(sc.Capabilities.Add, "SYS_ADMIN")) {
+		settings.AllowPrivilegeEscalation.Value = true
+	}
+	return settings
+}
+
+// inherited returns the container value, else the Pod value, else the default value
+func inherited[T any](container, pod *T, defaultValue any) SecuritySetting {
+	switch {
+	case container != nil:
+		return SecuritySetting{Value: *container, Source: SecuritySourceContainer}
+	case pod != nil:
+		return SecuritySetting{Value: *pod, Source: SecuritySourcePod}
+	default:
+		return SecuritySetting{Value: defaultValue, Source: SecuritySourceDefault}
+	}
+}
+
+// effectiveCapabilities returns the runtime default capabilities without the dropped ones and with the added ones
+func effectiveCapabilities(capabilities *v1.Capabilities, privileged bool) SecuritySetting {
+	if privileged {
+		return SecuritySetting{Value: []string{"ALL"}, Source: SecuritySourceContainer}
+	}
+	if capabilities == nil || (len(capabilities.Add) == 0 && len(capabilities.Drop) == 0) {
+		return SecuritySetting{Value: capabilityNames(runtimeDefaultCapabilities), Source: SecuritySourceDefault}
+	}
+	var effective []v1.Capability
+	if !slices.Contains(capabilities.Drop, "ALL") {
+		for _, capability := range runtimeDefaultCapabilities {
+			if !slices.Contains(capabilities.Drop, capability) {
+				effective = append(effective, capability)
+			}
+		}
+	}
+	for _, capability := range capabilities.Add {
+		if !slices.Contains(effective, capability) {
+			effective = append(effective, capability)
+		}
+	}
+	return SecuritySetting{Value: capabilityNames(effective), Source: SecuritySourceContainer}
+}
+
+func capabilityNames(capabilities []v1.Capability) []string {
+	names := make([]string, 0, len(capabilities))
+	for _, capability := range capabilities {
+		names = append(names, string(capability))
+	}
+	slices.Sort(names)
+	return names
+}
+
+func seccompProfile(profile *v1.SeccompProfile) string {
+	if profile.Type == v1.SeccompProfileTypeLocalhost {
+		return string(profile.Type) + "/" + ptr.Deref(profile.LocalhostProfile, "")
+	}
+	return string(profile.Type)
+}
+
+func appArmorProfile(profile *v1.AppArmorProfile) string {
+	if profile.Type == v1.AppArmorProfileTypeLocalhost {
+		return string(profile.Type) + "/" + ptr.Deref(profile.LocalhostProfile, "")
+	}
+	return string(profile.Type)
+}
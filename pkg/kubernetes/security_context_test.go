@@ -0,0 +1,96 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type SecurityContextTestSuite struct {
+	suite.Suite
+}
+
+func (s *SecurityContextTestSuite) TestEffectiveSecuritySettings() {
+	pod := restrictedPod("ns", "restricted")
+	pod.Spec.SecurityContext.RunAsUser = ptr.To(int64(1000))
+	pod.Spec.SecurityContext.FSGroup = ptr.To(int64(2000))
+	pod.Spec.Containers[0].SecurityContext.RunAsUser = ptr.To(int64(1001))
+	settings := EffectiveSecuritySettings("Pod/ns/restricted", &v1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec})
+	s.Run("reports the Pod settings", func() {
+		s.Equal(PodSecurityRestricted, settings.PodSecurityLevel)
+		s.Equal("default", settings.ServiceAccount)
+		s.True(settings.AutomountServiceAccountToken)
+		s.Equal(int64(2000), settings.FSGroup)
+		s.Equal("Always", settings.FSGroupChangePolicy)
+	})
+	s.Require().Len(settings.Containers, 1)
+	container := settings.Containers[0]
+	s.Run("container settings take precedence over the Pod settings", func() {
+		s.Equal(SecuritySetting{Value: int64(1001), Source: SecuritySourceContainer}, container.RunAsUser)
+	})
+	s.Run("inherits the Pod settings", func() {
+		s.Equal(SecuritySetting{Value: true, Source: SecuritySourcePod}, container.RunAsNonRoot)
+		s.Equal(SecuritySetting{Value: "RuntimeDefault", Source: SecuritySourcePod}, container.Seccomp)
+	})
+	s.Run("reports the defaults of the settings set nowhere", func() {
+		s.Equal(SecuritySourceDefault, container.RunAsGroup.Source)
+		s.Equal(SecuritySetting{Value: false, Source: SecuritySourceDefault}, container.ReadOnlyRootFilesystem)
+		s.Equal(SecuritySourceDefault, container.AppArmor.Source)
+		s.Equal(SecuritySourceDefault, container.SELinux.Source)
+	})
+	s.Run("computes the effective capabilities", func() {
+		s.Equal(SecuritySetting{Value: []string{"NET_BIND_SERVICE"}, Source: SecuritySourceContainer}, container.Capabilities)
+	})
+}
+
+func (s *SecurityContextTestSuite) TestEffectiveSecuritySettingsDefaults() {
+	template := &v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{appArmorAnnotationPrefix + "sidecar": "localhost/sidecar"}},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "init"}},
+			Containers: []v1.Container{
+				{Name: "app", SecurityContext: &v1.SecurityContext{Capabilities: &v1.Capabilities{Drop: []v1.Capability{"NET_RAW"}, Add: []v1.Capability{"SYS_ADMIN"}}}},
+				{Name: "sidecar", SecurityContext: &v1.SecurityContext{Privileged: ptr.To(true)}},
+			},
+		},
+	}
+	settings := EffectiveSecuritySettings("Deployment/ns/web", template)
+	s.Require().Len(settings.Containers, 3)
+	s.Run("reports the init containers first", func() {
+		s.Equal("init", settings.Containers[0].Type)
+		s.Equal("container", settings.Containers[1].Type)
+	})
+	s.Run("grants the runtime default capabilities", func() {
+		s.Equal(SecuritySetting{Value: capabilityNames(runtimeDefaultCapabilities), Source: SecuritySourceDefault}, settings.Containers[0].Capabilities)
+		s.Equal(SecuritySetting{Value: true, Source: SecuritySourceDefault}, settings.Containers[0].AllowPrivilegeEscalation)
+		s.Contains(settings.Containers[0].RunAsUser.Value, "root (0) if not set")
+	})
+	s.Run("drops and adds the capabilities to the runtime defaults", func() {
+		capabilities := settings.Containers[1].Capabilities.Value.([]string)
+		s.NotContains(capabilities, "NET_RAW")
+		s.Contains(capabilities, "SYS_ADMIN")
+		s.Contains(capabilities, "CHOWN")
+	})
+	s.Run("privileged containers have all the capabilities and are unconfined", func() {
+		sidecar := settings.Containers[2]
+		s.Equal([]string{"ALL"}, sidecar.Capabilities.Value)
+		s.Equal("Unconfined", sidecar.Seccomp.Value)
+		s.Equal(SecuritySetting{Value: true, Source: SecuritySourceContainer}, sidecar.Privileged)
+	})
+	s.Run("reads the AppArmor profile from the deprecated annotation", func() {
+		s.Equal(SecuritySetting{Value: "localhost/sidecar", Source: SecuritySourceAnnotation}, settings.Containers[2].AppArmor)
+	})
+	s.Run("fsGroup is not set", func() {
+		s.Equal("none, the volumes keep their ownership", settings.FSGroup)
+	})
+	s.Run("the workload fails the baseline level", func() {
+		s.Equal(PodSecurityPrivileged, settings.PodSecurityLevel)
+	})
+}
+
+func TestSecurityContext(t *testing.T) {
+	suite.Run(t, new(SecurityContextTestSuite))
+}
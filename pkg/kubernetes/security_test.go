@@ -0,0 +1,124 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type SecurityTestSuite struct {
+	suite.Suite
+}
+
+func restrictedPod(namespace, name string) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: v1.PodSpec{
+			SecurityContext: &v1.PodSecurityContext{
+				RunAsNonRoot:   ptr.To(true),
+				SeccompProfile: &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault},
+			},
+			Containers: []v1.Container{{
+				Name: "app",
+				SecurityContext: &v1.SecurityContext{
+					AllowPrivilegeEscalation: ptr.To(false),
+					Capabilities:             &v1.Capabilities{Drop: []v1.Capability{"ALL"}, Add: []v1.Capability{"NET_BIND_SERVICE"}},
+				},
+			}},
+			Volumes: []v1.Volume{{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{}}}},
+		},
+	}
+}
+
+func (s *SecurityTestSuite) TestEvaluatePodSecurity() {
+	s.Run("restricted pod has no violations", func() {
+		pod := restrictedPod("ns", "restricted")
+		report := evaluatePodSecurity(&pod)
+		s.Equal(PodSecurityRestricted, report.Level)
+		s.Empty(report.Violations)
+	})
+	s.Run("default pod fails restricted", func() {
+		pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "default"}, Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app"}}}}
+		report := evaluatePodSecurity(&pod)
+		s.Equal(PodSecurityBaseline, report.Level)
+		s.Equal([]PodSecurityViolation{
+			{Level: PodSecurityRestricted, Check: "allowPrivilegeEscalation", Message: "container app doesn't set allowPrivilegeEscalation=false"},
+			{Level: PodSecurityRestricted, Check: "capabilities", Message: "container app doesn't drop ALL capabilities"},
+			{Level: PodSecurityRestricted, Check: "runAsNonRoot", Message: "container app doesn't set runAsNonRoot=true"},
+			{Level: PodSecurityRestricted, Check: "seccompProfile", Message: "container app doesn't set a seccomp profile (RuntimeDefault or Localhost)"},
+		}, report.Violations)
+	})
+	s.Run("privileged pod fails baseline", func() {
+		pod := restrictedPod("ns", "privileged")
+		pod.Spec.HostNetwork = true
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{Name: "root", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/"}}})
+		pod.Spec.Containers[0].Ports = []v1.ContainerPort{{ContainerPort: 80, HostPort: 8080}}
+		pod.Spec.Containers[0].SecurityContext.Privileged = ptr.To(true)
+		pod.Spec.Containers[0].SecurityContext.Capabilities.Add = []v1.Capability{"SYS_ADMIN", "CHOWN"}
+		pod.Spec.Containers[0].SecurityContext.SeccompProfile = &v1.SeccompProfile{Type: v1.SeccompProfileTypeUnconfined}
+		pod.Spec.Containers[0].SecurityContext.RunAsUser = ptr.To(int64(0))
+		report := evaluatePodSecurity(&pod)
+		s.Equal(PodSecurityPrivileged, report.Level)
+		s.Equal([]PodSecurityViolation{
+			{Level: PodSecurityBaseline, Check: "capabilities", Message: "container app adds the capability SYS_ADMIN"},
+			{Level: PodSecurityBaseline, Check: "hostNamespaces", Message: "hostNetwork is enabled"},
+			{Level: PodSecurityBaseline, Check: "hostPathVolumes", Message: "volume root mounts the host path /"},
+			{Level: PodSecurityBaseline, Check: "hostPorts", Message: "container app uses the host port 8080"},
+			{Level: PodSecurityBaseline, Check: "privileged", Message: "container app is privileged"},
+			{Level: PodSecurityBaseline, Check: "seccompProfile", Message: "container app seccomp profile is Unconfined"},
+			{Level: PodSecurityRestricted, Check: "capabilities", Message: "container app adds the capability CHOWN"},
+			{Level: PodSecurityRestricted, Check: "restrictedVolumes", Message: "volume root uses the hostPath volume type"},
+			{Level: PodSecurityRestricted, Check: "runAsUser", Message: "container app runs as the root user (runAsUser=0)"},
+		}, report.Violations)
+	})
+	s.Run("evaluates init containers", func() {
+		pod := restrictedPod("ns", "init")
+		pod.Spec.InitContainers = []v1.Container{{Name: "init", SecurityContext: &v1.SecurityContext{Privileged: ptr.To(true)}}}
+		report := evaluatePodSecurity(&pod)
+		s.Equal(PodSecurityPrivileged, report.Level)
+		s.Contains(report.Violations, PodSecurityViolation{Level: PodSecurityBaseline, Check: "privileged", Message: "container init is privileged"})
+	})
+}
+
+func (s *SecurityTestSuite) TestSecurityPosture() {
+	privileged := restrictedPod("apps", "privileged")
+	privileged.Spec.HostPID = true
+	baseline := restrictedPod("apps", "baseline")
+	baseline.Spec.SecurityContext = nil
+	postures := securityPosture([]v1.Pod{
+		restrictedPod("secure", "restricted"),
+		baseline,
+		privileged,
+		restrictedPod("apps", "restricted"),
+	}, map[string]string{"apps": PodSecurityPrivileged})
+	s.Require().Len(postures, 2)
+	s.Run("summarizes violations per namespace", func() {
+		s.Equal("apps", postures[0].Namespace)
+		s.Equal(PodSecurityPrivileged, postures[0].EnforcedLevel)
+		s.Equal(3, postures[0].Pods)
+		s.Equal(1, postures[0].FailBaseline)
+		s.Equal(1, postures[0].FailRestricted)
+		s.Equal(map[string]int{"hostNamespaces": 1, "runAsNonRoot": 1, "seccompProfile": 1}, postures[0].Checks)
+	})
+	s.Run("lists the pods failing baseline first", func() {
+		s.Require().Len(postures[0].Violations, 2)
+		s.Equal("privileged", postures[0].Violations[0].Pod)
+		s.Equal(PodSecurityPrivileged, postures[0].Violations[0].Level)
+		s.Equal("baseline", postures[0].Violations[1].Pod)
+		s.Equal(PodSecurityBaseline, postures[0].Violations[1].Level)
+	})
+	s.Run("reports compliant namespaces without violations", func() {
+		s.Equal("secure", postures[1].Namespace)
+		s.Equal(1, postures[1].Pods)
+		s.Zero(postures[1].FailBaseline)
+		s.Zero(postures[1].FailRestricted)
+		s.Empty(postures[1].Violations)
+	})
+}
+
+func TestSecurity(t *testing.T) {
+	suite.Run(t, new(SecurityTestSuite))
+}
@@ -0,0 +1,135 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"k8s.io/client-go/rest"
+)
+
+// ServiceProxyKinds are the kinds of the targets supported by ServiceProxyRequest
+var ServiceProxyKinds = []string{"Service", "Pod"}
+
+// ServiceProxyMethods are the HTTP methods supported by ServiceProxyRequest
+var ServiceProxyMethods = []string{http.MethodGet, http.MethodPost}
+
+// serviceProxyDeniedHeaders are the request headers that would replace the credentials of the request to the API server
+var serviceProxyDeniedHeaders = []string{"Authorization", "Impersonate-"}
+
+// ServiceProxyRequest is an HTTP request to a Service or Pod sent through the proxy subresource of the API server.
+type ServiceProxyRequest struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Port is the name or number of the port of the Service or Pod, the first port if empty
+	Port string
+	// Scheme is http or https, http if empty
+	Scheme string
+	Method string
+	// Path is the path of the request, including its query string (e.g. /metrics?format=json)
+	Path    string
+	Headers map[string]string
+	Body    string
+	// MaxBytes is the maximum size of the returned response body, the rest is left out
+	MaxBytes int64
+}
+
+// ServiceProxyResponse is the response of a ServiceProxyRequest.
+type ServiceProxyResponse struct {
+	StatusCode  int
+	Status      string
+	ContentType string
+	Body        string
+	// Truncated is true if the response body exceeded MaxBytes
+	Truncated bool
+}
+
+// ServiceProxyRequest sends the HTTP request to the Service or Pod through the proxy subresource of the API server,
+// so that the in-cluster endpoints can be reached without port-forwarding. The responses of any status are returned.
+func (c *Core) ServiceProxyRequest(ctx context.Context, request ServiceProxyRequest) (*ServiceProxyResponse, error) {
+	var resource string
+	switch request.Kind {
+	case "Service":
+		resource = "services"
+	case "Pod":
+		resource = "pods"
+	default:
+		return nil, fmt.Errorf("unsupported kind %s, supported kinds are %v", request.Kind, ServiceProxyKinds)
+	}
+	method := strings.ToUpper(request.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	if method != http.MethodGet && method != http.MethodPost {
+		return nil, fmt.Errorf("unsupported method %s, supported methods are %v", request.Method, ServiceProxyMethods)
+	}
+	for name := range request.Headers {
+		for _, denied := range serviceProxyDeniedHeaders {
+			if strings.HasPrefix(strings.ToLower(name), strings.ToLower(denied)) {
+				return nil, fmt.Errorf("header %s is not allowed, it would replace the credentials of the request to the API server", name)
+			}
+		}
+	}
+	path, err := url.Parse(request.Path)
+	if err != nil || path.Host != "" {
+		return nil, fmt.Errorf("invalid path %s, expected a path with an optional query string (e.g. /healthz)", request.Path)
+	}
+	// The proxy path is [scheme:]name[:port], the port is required when the scheme is provided
+	target := request.Name
+	switch {
+	case request.Scheme != "" && request.Port == "":
+		return nil, errors.New("the port is required when the scheme is provided")
+	case request.Scheme != "":
+		target = request.Scheme + ":" + target + ":" + request.Port
+	case request.Port != "":
+		target += ":" + request.Port
+	}
+	restClient, ok := c.CoreV1().RESTClient().(*rest.RESTClient)
+	if !ok {
+		return nil, errors.New("the API server proxy is not available for this client")
+	}
+	proxyURL := restClient.Get().
+		AbsPath("api", "v1", "namespaces", c.NamespaceOrDefault(request.Namespace), resource, target, "proxy", path.Path).
+		URL()
+	// the trailing slash of the path is significant for some applications (e.g. /admin/)
+	if strings.HasSuffix(path.Path, "/") && !strings.HasSuffix(proxyURL.Path, "/") {
+		proxyURL.Path += "/"
+	}
+	proxyURL.RawQuery = path.RawQuery
+	var body io.Reader
+	if request.Body != "" {
+		body = strings.NewReader(request.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, proxyURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range request.Headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := restClient.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	// one more byte to detect the truncation
+	content, err := io.ReadAll(io.LimitReader(resp.Body, request.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response: %w", err)
+	}
+	ret := &ServiceProxyResponse{
+		StatusCode:  resp.StatusCode,
+		Status:      resp.Status,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if int64(len(content)) > request.MaxBytes {
+		content, ret.Truncated = content[:request.MaxBytes], true
+	}
+	ret.Body = strings.ToValidUTF8(string(content), "")
+	return ret, nil
+}
@@ -0,0 +1,300 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
+)
+
+// TerminationDiagnosis lists the objects stuck in Terminating, and the state of the controllers owning their finalizers.
+type TerminationDiagnosis struct {
+	Objects      []StuckObject         `json:"objects"`
+	Controllers  []FinalizerController `json:"controllers,omitempty"`
+	Remediations []string              `json:"remediations,omitempty"`
+}
+
+// StuckObject is an object with a deletionTimestamp that hasn't been removed yet.
+type StuckObject struct {
+	APIVersion        string   `json:"apiVersion"`
+	Kind              string   `json:"kind"`
+	Namespace         string   `json:"namespace,omitempty"`
+	Name              string   `json:"name"`
+	DeletionTimestamp string   `json:"deletionTimestamp"`
+	TerminatingFor    string   `json:"terminatingFor,omitempty"`
+	Finalizers        []string `json:"finalizers,omitempty"`
+	// Conditions reported by the namespace controller for Namespaces (e.g. NamespaceFinalizersRemaining)
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// FinalizerController is the (likely) controller responsible for removing a finalizer.
+type FinalizerController struct {
+	Finalizer  string `json:"finalizer"`
+	Controller string `json:"controller"`
+	// Healthy is nil when the health of the controller could not be determined
+	Healthy   *bool            `json:"healthy,omitempty"`
+	Workloads []WorkloadHealth `json:"workloads,omitempty"`
+}
+
+// WorkloadHealth is the readiness of a workload (Deployment, StatefulSet, or DaemonSet).
+type WorkloadHealth struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Ready     string `json:"ready"`
+	Healthy   bool   `json:"healthy"`
+}
+
+// wellKnownFinalizers maps the finalizers handled by Kubernetes components to their controller
+var wellKnownFinalizers = map[string]string{
+	"kubernetes":                   "kube-controller-manager (namespace controller)",
+	"foregroundDeletion":           "kube-controller-manager (garbage collector)",
+	"orphan":                       "kube-controller-manager (garbage collector)",
+	"kubernetes.io/pvc-protection": "kube-controller-manager (pvc-protection controller)",
+	"kubernetes.io/pv-protection":  "kube-controller-manager (pv-protection controller)",
+	"service.kubernetes.io/load-balancer-cleanup": "cloud-controller-manager (service controller)",
+	"customresourcecleanup.apiextensions.k8s.io":  "kube-apiserver (CRD cleanup)",
+}
+
+// genericFinalizerTokens are the domain fragments that can't be used to identify the controller of a finalizer
+var genericFinalizerTokens = []string{"io", "com", "org", "dev", "net", "k8s", "x-k8s", "kubernetes", "finalizer", "finalizers", "resources-finalizer", "protection", "cleanup"}
+
+// TerminationDiagnose finds the objects stuck in Terminating in the provided namespace (or in the whole cluster if empty),
+// the finalizers blocking their deletion, and the health of the controllers owning those finalizers. The durations are
+// rendered in the locale of the context.
+func (c *Core) TerminationDiagnose(ctx context.Context, namespace string) (*TerminationDiagnosis, error) {
+	p := locale.FromContext(ctx)
+	resourceLists, err := c.DiscoveryClient().ServerPreferredResources()
+	// Partial discovery failures (e.g. unavailable aggregated APIs) are tolerated
+	if err != nil && len(resourceLists) == 0 {
+		return nil, fmt.Errorf("failed to discover API resources: %w", err)
+	}
+	diagnosis := &TerminationDiagnosis{Objects: []StuckObject{}}
+	if namespace != "" {
+		ns, err := c.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if ns.DeletionTimestamp != nil {
+			diagnosis.Objects = append(diagnosis.Objects, stuckNamespace(ns, p))
+		}
+	}
+	var gvrs []schema.GroupVersionResource
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, apiResource := range resourceList.APIResources {
+			if !slices.Contains(apiResource.Verbs, "list") || strings.Contains(apiResource.Name, "/") ||
+				(namespace != "" && !apiResource.Namespaced) {
+				continue
+			}
+			if gv.Group == "" && apiResource.Kind == "Namespace" {
+				if namespace == "" {
+					namespaces, err := c.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+					if err != nil {
+						continue
+					}
+					for i := range namespaces.Items {
+						if namespaces.Items[i].DeletionTimestamp != nil {
+							diagnosis.Objects = append(diagnosis.Objects, stuckNamespace(&namespaces.Items[i], p))
+						}
+					}
+				}
+				continue
+			}
+			gvrs = append(gvrs, gv.WithResource(apiResource.Name))
+		}
+	}
+	// The resources are listed concurrently, the objects are sorted afterward
+	stuck, _ := FanOut(ctx, DefaultFanOutConcurrency, gvrs, func(ctx context.Context, gvr schema.GroupVersionResource) ([]StuckObject, error) {
+		list, err := c.DynamicClient().Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		// Objects that can't be listed (RBAC, denied resources) are skipped
+		if err != nil {
+			return nil, err
+		}
+		var ret []StuckObject
+		for _, item := range list.Items {
+			if item.GetDeletionTimestamp() != nil {
+				ret = append(ret, stuckObject(&item, p))
+			}
+		}
+		return ret, nil
+	})
+	for _, objects := range stuck {
+		diagnosis.Objects = append(diagnosis.Objects, objects...)
+	}
+	slices.SortFunc(diagnosis.Objects, func(a, b StuckObject) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	if len(diagnosis.Objects) == 0 {
+		return diagnosis, nil
+	}
+	workloads := c.workloadsHealth(ctx)
+	diagnosis.Controllers = finalizerControllers(diagnosis.Objects, workloads)
+	diagnosis.Remediations = terminationRemediations(diagnosis)
+	return diagnosis, nil
+}
+
+func stuckNamespace(ns *v1.Namespace, p locale.Printer) StuckObject {
+	stuck := StuckObject{
+		APIVersion:        "v1",
+		Kind:              "Namespace",
+		Name:              ns.Name,
+		DeletionTimestamp: ns.DeletionTimestamp.UTC().Format(time.RFC3339),
+		TerminatingFor:    p.Age(ns.DeletionTimestamp.Time),
+		Finalizers:        slices.Clone(ns.Finalizers),
+	}
+	for _, finalizer := range ns.Spec.Finalizers {
+		stuck.Finalizers = append(stuck.Finalizers, string(finalizer))
+	}
+	for _, condition := range ns.Status.Conditions {
+		if condition.Status == v1.ConditionTrue && condition.Message != "" {
+			stuck.Conditions = append(stuck.Conditions, fmt.Sprintf("%s: %s", condition.Type, condition.Message))
+		}
+	}
+	return stuck
+}
+
+func stuckObject(obj *unstructured.Unstructured, p locale.Printer) StuckObject {
+	return StuckObject{
+		APIVersion:        obj.GetAPIVersion(),
+		Kind:              obj.GetKind(),
+		Namespace:         obj.GetNamespace(),
+		Name:              obj.GetName(),
+		DeletionTimestamp: obj.GetDeletionTimestamp().UTC().Format(time.RFC3339),
+		TerminatingFor:    p.Age(obj.GetDeletionTimestamp().Time),
+		Finalizers:        obj.GetFinalizers(),
+	}
+}
+
+// workloadsHealth returns the readiness of all the Deployments, StatefulSets, and DaemonSets in the cluster (best-effort)
+func (c *Core) workloadsHealth(ctx context.Context) []WorkloadHealth {
+	var workloads []WorkloadHealth
+	if deployments, err := c.AppsV1().Deployments("").List(ctx, metav1.ListOptions{}); err == nil {
+		for _, d := range deployments.Items {
+			workloads = append(workloads, deploymentHealth(&d))
+		}
+	}
+	if statefulSets, err := c.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{}); err == nil {
+		for _, s := range statefulSets.Items {
+			replicas := int32(1)
+			if s.Spec.Replicas != nil {
+				replicas = *s.Spec.Replicas
+			}
+			workloads = append(workloads, WorkloadHealth{
+				Kind: "StatefulSet", Namespace: s.Namespace, Name: s.Name,
+				Ready:   fmt.Sprintf("%d/%d", s.Status.ReadyReplicas, replicas),
+				Healthy: replicas > 0 && s.Status.ReadyReplicas >= replicas,
+			})
+		}
+	}
+	if daemonSets, err := c.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{}); err == nil {
+		for _, ds := range daemonSets.Items {
+			workloads = append(workloads, WorkloadHealth{
+				Kind: "DaemonSet", Namespace: ds.Namespace, Name: ds.Name,
+				Ready:   fmt.Sprintf("%d/%d", ds.Status.NumberReady, ds.Status.DesiredNumberScheduled),
+				Healthy: ds.Status.DesiredNumberScheduled > 0 && ds.Status.NumberReady >= ds.Status.DesiredNumberScheduled,
+			})
+		}
+	}
+	return workloads
+}
+
+func deploymentHealth(d *appsv1.Deployment) WorkloadHealth {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return WorkloadHealth{
+		Kind: "Deployment", Namespace: d.Namespace, Name: d.Name,
+		Ready:   fmt.Sprintf("%d/%d", d.Status.ReadyReplicas, replicas),
+		Healthy: replicas > 0 && d.Status.ReadyReplicas >= replicas,
+	}
+}
+
+// finalizerControllers identifies the controllers of the finalizers of the stuck objects.
+// Finalizers that are not handled by Kubernetes components are matched against the names of the workloads in the cluster
+// using the fragments of their domain (e.g. cert-manager.io/finalizer matches the cert-manager Deployment).
+func finalizerControllers(objects []StuckObject, workloads []WorkloadHealth) []FinalizerController {
+	var finalizers []string
+	for _, obj := range objects {
+		for _, finalizer := range obj.Finalizers {
+			if !slices.Contains(finalizers, finalizer) {
+				finalizers = append(finalizers, finalizer)
+			}
+		}
+	}
+	slices.Sort(finalizers)
+	controllers := make([]FinalizerController, 0, len(finalizers))
+	for _, finalizer := range finalizers {
+		if controller, ok := wellKnownFinalizers[finalizer]; ok {
+			controllers = append(controllers, FinalizerController{Finalizer: finalizer, Controller: controller})
+			continue
+		}
+		controller := FinalizerController{Finalizer: finalizer, Controller: "unknown"}
+		tokens := finalizerTokens(finalizer)
+		for _, workload := range workloads {
+			if slices.ContainsFunc(tokens, func(token string) bool { return strings.Contains(workload.Name, token) }) {
+				controller.Workloads = append(controller.Workloads, workload)
+			}
+		}
+		if len(controller.Workloads) > 0 {
+			controller.Controller = fmt.Sprintf("%s/%s", controller.Workloads[0].Namespace, controller.Workloads[0].Name)
+			healthy := !slices.ContainsFunc(controller.Workloads, func(w WorkloadHealth) bool { return !w.Healthy })
+			controller.Healthy = &healthy
+		} else {
+			healthy := false
+			controller.Healthy = &healthy
+		}
+		controllers = append(controllers, controller)
+	}
+	return controllers
+}
+
+func finalizerTokens(finalizer string) []string {
+	domain, _, _ := strings.Cut(finalizer, "/")
+	var tokens []string
+	for _, token := range strings.Split(domain, ".") {
+		if len(token) >= 4 && !slices.Contains(genericFinalizerTokens, token) {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func terminationRemediations(diagnosis *TerminationDiagnosis) []string {
+	var remediations []string
+	for _, controller := range diagnosis.Controllers {
+		switch {
+		case controller.Healthy == nil:
+			continue
+		case !*controller.Healthy && len(controller.Workloads) == 0:
+			remediations = append(remediations, fmt.Sprintf(
+				"No controller was found for finalizer %s, if its operator was uninstalled reinstall it so it can clean up, "+
+					"or remove the finalizer manually (kubectl patch <kind> <name> --type=json -p '[{\"op\":\"remove\",\"path\":\"/metadata/finalizers\"}]'), "+
+					"which may leak the external resources it protects", controller.Finalizer))
+		case !*controller.Healthy:
+			remediations = append(remediations, fmt.Sprintf(
+				"The controller %s of finalizer %s is not healthy, restore it (check its Pods and logs) so it can remove the finalizer", controller.Controller, controller.Finalizer))
+		default:
+			remediations = append(remediations, fmt.Sprintf(
+				"The controller %s of finalizer %s is running, check its logs for errors processing the deletion", controller.Controller, controller.Finalizer))
+		}
+	}
+	if slices.ContainsFunc(diagnosis.Objects, func(o StuckObject) bool { return o.Kind == "Namespace" && len(o.Conditions) > 0 }) {
+		remediations = append(remediations, "Review the Namespace conditions, they list the remaining resources and finalizers blocking its deletion (e.g. unavailable aggregated APIs)")
+	}
+	return remediations
+}
@@ -0,0 +1,60 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TerminationTestSuite struct {
+	suite.Suite
+}
+
+func (s *TerminationTestSuite) TestFinalizerControllers() {
+	objects := []StuckObject{
+		{Kind: "Namespace", Name: "stuck", Finalizers: []string{"kubernetes"}, Conditions: []string{"NamespaceFinalizersRemaining: Some content in the namespace has finalizers remaining"}},
+		{Kind: "Certificate", Namespace: "stuck", Name: "tls", Finalizers: []string{"cert-manager.io/finalizer"}},
+		{Kind: "Application", Namespace: "stuck", Name: "app", Finalizers: []string{"resources-finalizer.argocd.argoproj.io"}},
+		{Kind: "Widget", Namespace: "stuck", Name: "w", Finalizers: []string{"widgets.example.com/cleanup"}},
+	}
+	workloads := []WorkloadHealth{
+		{Kind: "Deployment", Namespace: "cert-manager", Name: "cert-manager", Ready: "1/1", Healthy: true},
+		{Kind: "Deployment", Namespace: "argocd", Name: "argocd-application-controller", Ready: "0/1", Healthy: false},
+		{Kind: "Deployment", Namespace: "default", Name: "nginx", Ready: "1/1", Healthy: true},
+	}
+	controllers := finalizerControllers(objects, workloads)
+	s.Require().Len(controllers, 4)
+	s.Run("sorted by finalizer", func() {
+		s.Equal("cert-manager.io/finalizer", controllers[0].Finalizer)
+		s.Equal("kubernetes", controllers[1].Finalizer)
+	})
+	s.Run("well-known finalizers", func() {
+		s.Equal("kube-controller-manager (namespace controller)", controllers[1].Controller)
+		s.Nil(controllers[1].Healthy)
+	})
+	s.Run("healthy operator", func() {
+		s.Equal("cert-manager/cert-manager", controllers[0].Controller)
+		s.True(*controllers[0].Healthy)
+	})
+	s.Run("unhealthy operator", func() {
+		s.Equal("resources-finalizer.argocd.argoproj.io", controllers[2].Finalizer)
+		s.Equal("argocd/argocd-application-controller", controllers[2].Controller)
+		s.False(*controllers[2].Healthy)
+	})
+	s.Run("missing operator", func() {
+		s.Equal("unknown", controllers[3].Controller)
+		s.False(*controllers[3].Healthy)
+	})
+	s.Run("remediations", func() {
+		remediations := terminationRemediations(&TerminationDiagnosis{Objects: objects, Controllers: controllers})
+		s.Len(remediations, 4)
+		s.Contains(remediations[0], "check its logs")
+		s.Contains(remediations[1], "is not healthy")
+		s.Contains(remediations[2], "remove the finalizer manually")
+		s.Contains(remediations[3], "Namespace conditions")
+	})
+}
+
+func TestTermination(t *testing.T) {
+	suite.Run(t, new(TerminationTestSuite))
+}
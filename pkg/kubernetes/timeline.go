@@ -0,0 +1,324 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	TimelineSourceEvent   = "event"
+	TimelineSourceHelm    = "helm"
+	TimelineSourceRollout = "rollout"
+	TimelineSourceChange  = "change"
+	TimelineSourceRestart = "restart"
+
+	// helmReleaseNameAnnotation is the annotation set by Helm on the objects of a release
+	helmReleaseNameAnnotation = "meta.helm.sh/release-name"
+	// helmReleaseSecretSelector selects the Secrets where Helm stores the release revisions
+	helmReleaseSecretSelector = "owner=helm"
+	// deploymentRevisionAnnotation is the annotation with the revision of a Deployment rollout set on its ReplicaSets
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+)
+
+// TimelineWorkloadKinds are the kinds of workloads supported by ChangeTimeline
+var TimelineWorkloadKinds = []string{"Deployment", "StatefulSet", "DaemonSet"}
+
+// TimelineEntry is something that happened to an object at a point in time.
+type TimelineEntry struct {
+	Time        string `json:"time"`
+	Source      string `json:"source"`
+	Object      string `json:"object"`
+	Description string `json:"description"`
+	time        time.Time
+}
+
+// timelineObject is an object whose creation and updates (managedFields) are part of the timeline
+type timelineObject struct {
+	kind string
+	meta metav1.ObjectMeta
+}
+
+// timelineSources are the objects the timeline is reconstructed from
+type timelineSources struct {
+	events              []v1.Event
+	helmReleases        []v1.Secret
+	replicaSets         []appsv1.ReplicaSet
+	controllerRevisions []appsv1.ControllerRevision
+	objects             []timelineObject
+	// pods are the Pods whose last container terminations (restarts) are part of the timeline
+	pods []v1.Pod
+}
+
+// IncidentTimeline is the chronological timeline of the problems and the changes of a namespace within a time range.
+type IncidentTimeline struct {
+	// Summary is the number of entries of each source
+	Summary map[string]int `json:"summary"`
+	// FirstProblem is the first Warning event or container restart of the time range
+	FirstProblem *TimelineEntry `json:"firstProblem,omitempty"`
+	// PrecedingChanges are the rollouts and Helm revisions of the time range before the first problem, its likely causes
+	PrecedingChanges []TimelineEntry `json:"precedingChanges,omitempty"`
+	Timeline         []TimelineEntry `json:"timeline"`
+}
+
+// ChangeTimeline reconstructs the chronological timeline of what happened between from and to in the provided
+// namespace, or to the provided workload (if kind is not empty), from the events, the Helm release history, the
+// rollout history (ReplicaSets and ControllerRevisions), and the creation and update (managedFields) timestamps.
+func (c *Core) ChangeTimeline(ctx context.Context, namespace, kind, name string, from, to time.Time) ([]TimelineEntry, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	sources := timelineSources{}
+	events, err := c.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sources.events = events.Items
+	// The Helm history is best-effort, the Secrets might not be accessible
+	if helmReleases, err := c.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: helmReleaseSecretSelector}); err == nil {
+		sources.helmReleases = helmReleases.Items
+	}
+	if kind == "" {
+		return c.namespaceTimeline(ctx, namespace, sources, from, to)
+	}
+	var workload metav1.ObjectMeta
+	var selector *metav1.LabelSelector
+	switch kind {
+	case "Deployment":
+		deployment, err := c.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		workload, selector = deployment.ObjectMeta, deployment.Spec.Selector
+		replicaSets, err := c.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, replicaSet := range replicaSets.Items {
+			if owner := metav1.GetControllerOf(&replicaSet); owner != nil && owner.UID == deployment.UID {
+				sources.replicaSets = append(sources.replicaSets, replicaSet)
+			}
+		}
+	case "StatefulSet":
+		statefulSet, err := c.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		workload, selector = statefulSet.ObjectMeta, statefulSet.Spec.Selector
+	case "DaemonSet":
+		daemonSet, err := c.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		workload, selector = daemonSet.ObjectMeta, daemonSet.Spec.Selector
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %s, supported kinds are %v", kind, TimelineWorkloadKinds)
+	}
+	sources.objects = append(sources.objects, timelineObject{kind: kind, meta: workload})
+	if kind != "Deployment" {
+		controllerRevisions, err := c.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, controllerRevision := range controllerRevisions.Items {
+			if owner := metav1.GetControllerOf(&controllerRevision); owner != nil && owner.UID == workload.UID {
+				sources.controllerRevisions = append(sources.controllerRevisions, controllerRevision)
+			}
+		}
+	}
+	// Only the events of the workload, its ReplicaSets, and its Pods
+	involved := map[string]bool{kind + "/" + name: true}
+	for _, replicaSet := range sources.replicaSets {
+		involved["ReplicaSet/"+replicaSet.Name] = true
+	}
+	if podSelector, err := metav1.LabelSelectorAsSelector(selector); err == nil && !podSelector.Empty() {
+		if pods, err := c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: podSelector.String()}); err == nil {
+			for _, pod := range pods.Items {
+				involved["Pod/"+pod.Name] = true
+			}
+		}
+	}
+	sources.events = slices.DeleteFunc(sources.events, func(event v1.Event) bool {
+		return !involved[event.InvolvedObject.Kind+"/"+event.InvolvedObject.Name]
+	})
+	// Only the Helm release the workload belongs to
+	release := cmp.Or(workload.Annotations[helmReleaseNameAnnotation], workload.Labels[AppKubernetesInstance])
+	sources.helmReleases = slices.DeleteFunc(sources.helmReleases, func(secret v1.Secret) bool {
+		return release == "" || secret.Labels["name"] != release
+	})
+	return buildTimeline(sources, from, to), nil
+}
+
+// IncidentTimeline correlates the Warning events, the container restarts, the rollout history (ReplicaSets and
+// ControllerRevisions), and the Helm release history of the provided namespace between from and to into a single
+// chronological timeline, pointing out the first problem and the changes that preceded it.
+func (c *Core) IncidentTimeline(ctx context.Context, namespace string, from, to time.Time) (*IncidentTimeline, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	sources := timelineSources{}
+	events, err := c.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: "type=" + v1.EventTypeWarning})
+	if err != nil {
+		return nil, err
+	}
+	sources.events = events.Items
+	// The Helm history is best-effort, the Secrets might not be accessible
+	if helmReleases, err := c.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: helmReleaseSecretSelector}); err == nil {
+		sources.helmReleases = helmReleases.Items
+	}
+	pods, err := c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sources.pods = pods.Items
+	replicaSets, err := c.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sources.replicaSets = replicaSets.Items
+	controllerRevisions, err := c.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sources.controllerRevisions = controllerRevisions.Items
+	return correlate(buildTimeline(sources, from, to)), nil
+}
+
+// correlate summarizes the timeline and finds its first problem (Warning event or restart) and the changes before it
+func correlate(entries []TimelineEntry) *IncidentTimeline {
+	ret := &IncidentTimeline{Summary: map[string]int{}, Timeline: entries}
+	changes := make([]TimelineEntry, 0)
+	for i := range entries {
+		ret.Summary[entries[i].Source]++
+		switch entries[i].Source {
+		case TimelineSourceEvent, TimelineSourceRestart:
+			if ret.FirstProblem == nil {
+				ret.FirstProblem = &entries[i]
+				ret.PrecedingChanges = changes
+			}
+		case TimelineSourceRollout, TimelineSourceHelm:
+			changes = append(changes, entries[i])
+		}
+	}
+	return ret
+}
+
+func (c *Core) namespaceTimeline(ctx context.Context, namespace string, sources timelineSources, from, to time.Time) ([]TimelineEntry, error) {
+	deployments, err := c.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, deployment := range deployments.Items {
+		sources.objects = append(sources.objects, timelineObject{kind: "Deployment", meta: deployment.ObjectMeta})
+	}
+	statefulSets, err := c.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, statefulSet := range statefulSets.Items {
+		sources.objects = append(sources.objects, timelineObject{kind: "StatefulSet", meta: statefulSet.ObjectMeta})
+	}
+	daemonSets, err := c.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, daemonSet := range daemonSets.Items {
+		sources.objects = append(sources.objects, timelineObject{kind: "DaemonSet", meta: daemonSet.ObjectMeta})
+	}
+	configMaps, err := c.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, configMap := range configMaps.Items {
+		sources.objects = append(sources.objects, timelineObject{kind: "ConfigMap", meta: configMap.ObjectMeta})
+	}
+	services, err := c.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services.Items {
+		sources.objects = append(sources.objects, timelineObject{kind: "Service", meta: service.ObjectMeta})
+	}
+	replicaSets, err := c.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sources.replicaSets = replicaSets.Items
+	controllerRevisions, err := c.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sources.controllerRevisions = controllerRevisions.Items
+	return buildTimeline(sources, from, to), nil
+}
+
+func buildTimeline(sources timelineSources, from, to time.Time) []TimelineEntry {
+	entries := make([]TimelineEntry, 0)
+	add := func(at time.Time, source, object, description string) {
+		if at.Before(from) || at.After(to) {
+			return
+		}
+		entries = append(entries, TimelineEntry{Time: at.UTC().Format(time.RFC3339), Source: source, Object: object, Description: description, time: at})
+	}
+	for _, event := range sources.events {
+		description := fmt.Sprintf("%s %s: %s", event.Type, event.Reason, strings.TrimSpace(event.Message))
+		if count := max(event.Count, 1); count > 1 {
+			description += fmt.Sprintf(" (x%d)", count)
+		}
+		add(eventLastSeen(event), TimelineSourceEvent, event.InvolvedObject.Kind+"/"+event.InvolvedObject.Name, description)
+	}
+	for _, secret := range sources.helmReleases {
+		add(secret.CreationTimestamp.Time, TimelineSourceHelm, "HelmRelease/"+secret.Labels["name"],
+			fmt.Sprintf("revision %s installed (status %s)", secret.Labels["version"], secret.Labels["status"]))
+	}
+	for _, replicaSet := range sources.replicaSets {
+		owner := metav1.GetControllerOf(&replicaSet)
+		if owner == nil {
+			continue
+		}
+		images := make([]string, 0, len(replicaSet.Spec.Template.Spec.Containers))
+		for _, container := range replicaSet.Spec.Template.Spec.Containers {
+			images = append(images, container.Name+"="+container.Image)
+		}
+		add(replicaSet.CreationTimestamp.Time, TimelineSourceRollout, owner.Kind+"/"+owner.Name,
+			fmt.Sprintf("revision %s rolled out with ReplicaSet %s (%s)", replicaSet.Annotations[deploymentRevisionAnnotation], replicaSet.Name, strings.Join(images, ", ")))
+	}
+	for _, controllerRevision := range sources.controllerRevisions {
+		owner := metav1.GetControllerOf(&controllerRevision)
+		if owner == nil {
+			continue
+		}
+		add(controllerRevision.CreationTimestamp.Time, TimelineSourceRollout, owner.Kind+"/"+owner.Name,
+			fmt.Sprintf("revision %d rolled out with ControllerRevision %s", controllerRevision.Revision, controllerRevision.Name))
+	}
+	for _, pod := range sources.pods {
+		for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+			// Only the last termination of a restarted container is known
+			terminated := status.LastTerminationState.Terminated
+			if terminated == nil || terminated.FinishedAt.IsZero() {
+				continue
+			}
+			add(terminated.FinishedAt.Time, TimelineSourceRestart, "Pod/"+pod.Name,
+				fmt.Sprintf("container %s terminated with %s (exit code %d) and restarted (%d restarts)",
+					status.Name, nonEmpty(terminated.Reason, "unknown reason"), terminated.ExitCode, status.RestartCount))
+		}
+	}
+	for _, object := range sources.objects {
+		objectName := object.kind + "/" + object.meta.Name
+		add(object.meta.CreationTimestamp.Time, TimelineSourceChange, objectName, "created")
+		for _, managedFields := range object.meta.ManagedFields {
+			// Status updates are reported by the events and the rollouts
+			if managedFields.Time == nil || managedFields.Subresource != "" || managedFields.Time.Equal(&object.meta.CreationTimestamp) {
+				continue
+			}
+			add(managedFields.Time.Time, TimelineSourceChange, objectName, fmt.Sprintf("last %s by %s", strings.ToLower(string(managedFields.Operation)), managedFields.Manager))
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b TimelineEntry) int {
+		return cmp.Or(a.time.Compare(b.time), cmp.Compare(a.Source, b.Source), cmp.Compare(a.Object, b.Object))
+	})
+	return entries
+}
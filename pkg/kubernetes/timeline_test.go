@@ -0,0 +1,105 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type TimelineTestSuite struct {
+	suite.Suite
+}
+
+func (s *TimelineTestSuite) TestBuildTimeline() {
+	base := time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC)
+	at := func(minutes int) metav1.Time { return metav1.NewTime(base.Add(time.Duration(minutes) * time.Minute)) }
+	owner := []metav1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: ptr.To(true)}}
+	sources := timelineSources{
+		events: []v1.Event{
+			{InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-6c9f-abcde"}, Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", Count: 4, LastTimestamp: at(7)},
+			{InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-5d4f-fghij"}, Type: "Normal", Reason: "Pulled", Message: "Image pulled", LastTimestamp: at(-90)},
+		},
+		helmReleases: []v1.Secret{
+			{ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.web.v3", CreationTimestamp: at(4), Labels: map[string]string{"name": "web", "version": "3", "status": "deployed"}}},
+		},
+		replicaSets: []appsv1.ReplicaSet{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-6c9f", CreationTimestamp: at(5), OwnerReferences: owner, Annotations: map[string]string{deploymentRevisionAnnotation: "7"}},
+				Spec:       appsv1.ReplicaSetSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "web", Image: "web:2.0"}}}}},
+			},
+			{ObjectMeta: metav1.ObjectMeta{Name: "orphan", CreationTimestamp: at(5)}},
+		},
+		controllerRevisions: []appsv1.ControllerRevision{
+			{ObjectMeta: metav1.ObjectMeta{Name: "db-7f8", CreationTimestamp: at(2), OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db", Controller: ptr.To(true)}}}, Revision: 2},
+		},
+		objects: []timelineObject{{kind: "Deployment", meta: metav1.ObjectMeta{Name: "web", CreationTimestamp: at(-600), ManagedFields: []metav1.ManagedFieldsEntry{
+			{Manager: "helm", Operation: metav1.ManagedFieldsOperationUpdate, Time: ptr.To(at(4))},
+			{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status", Time: ptr.To(at(8))},
+		}}}},
+	}
+	entries := buildTimeline(sources, base, base.Add(30*time.Minute))
+	s.Run("merges the sources chronologically within the window", func() {
+		s.Equal([]TimelineEntry{
+			{Time: "2025-01-01T14:02:00Z", Source: TimelineSourceRollout, Object: "StatefulSet/db", Description: "revision 2 rolled out with ControllerRevision db-7f8"},
+			{Time: "2025-01-01T14:04:00Z", Source: TimelineSourceChange, Object: "Deployment/web", Description: "last update by helm"},
+			{Time: "2025-01-01T14:04:00Z", Source: TimelineSourceHelm, Object: "HelmRelease/web", Description: "revision 3 installed (status deployed)"},
+			{Time: "2025-01-01T14:05:00Z", Source: TimelineSourceRollout, Object: "Deployment/web", Description: "revision 7 rolled out with ReplicaSet web-6c9f (web=web:2.0)"},
+			{Time: "2025-01-01T14:07:00Z", Source: TimelineSourceEvent, Object: "Pod/web-6c9f-abcde", Description: "Warning BackOff: Back-off restarting failed container (x4)"},
+		}, stripTimelineTimes(entries))
+	})
+}
+
+func (s *TimelineTestSuite) TestCorrelate() {
+	base := time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC)
+	at := func(minutes int) metav1.Time { return metav1.NewTime(base.Add(time.Duration(minutes) * time.Minute)) }
+	sources := timelineSources{
+		events: []v1.Event{
+			{InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-6c9f-abcde"}, Type: "Warning", Reason: "Unhealthy", Message: "Readiness probe failed", LastTimestamp: at(9)},
+		},
+		helmReleases: []v1.Secret{
+			{ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.web.v3", CreationTimestamp: at(4), Labels: map[string]string{"name": "web", "version": "3", "status": "deployed"}}},
+		},
+		pods: []v1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-6c9f-abcde"},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+				{Name: "web", RestartCount: 2, LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1, FinishedAt: at(6)}}},
+				{Name: "sidecar"},
+			}},
+		}},
+	}
+	timeline := correlate(stripTimelineTimes(buildTimeline(sources, base, base.Add(30*time.Minute))))
+	s.Run("merges the restarts into the timeline", func() {
+		s.Equal(TimelineEntry{Time: "2025-01-01T14:06:00Z", Source: TimelineSourceRestart, Object: "Pod/web-6c9f-abcde",
+			Description: "container web terminated with Error (exit code 1) and restarted (2 restarts)"}, timeline.Timeline[1])
+	})
+	s.Run("summarizes the entries by source", func() {
+		s.Equal(map[string]int{TimelineSourceHelm: 1, TimelineSourceRestart: 1, TimelineSourceEvent: 1}, timeline.Summary)
+	})
+	s.Run("points out the first problem and the changes before it", func() {
+		s.Require().NotNil(timeline.FirstProblem)
+		s.Equal("2025-01-01T14:06:00Z", timeline.FirstProblem.Time)
+		s.Equal([]TimelineEntry{{Time: "2025-01-01T14:04:00Z", Source: TimelineSourceHelm, Object: "HelmRelease/web", Description: "revision 3 installed (status deployed)"}},
+			timeline.PrecedingChanges)
+	})
+	s.Run("has no first problem without Warning events and restarts", func() {
+		timeline := correlate(stripTimelineTimes(buildTimeline(timelineSources{helmReleases: sources.helmReleases}, base, base.Add(30*time.Minute))))
+		s.Nil(timeline.FirstProblem)
+		s.Empty(timeline.PrecedingChanges)
+	})
+}
+
+func stripTimelineTimes(entries []TimelineEntry) []TimelineEntry {
+	for i := range entries {
+		entries[i].time = time.Time{}
+	}
+	return entries
+}
+
+func TestTimeline(t *testing.T) {
+	suite.Run(t, new(TimelineTestSuite))
+}
@@ -0,0 +1,532 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	istioVirtualServices  = schema.GroupKind{Group: "networking.istio.io", Kind: "VirtualService"}
+	istioDestinationRules = schema.GroupKind{Group: "networking.istio.io", Kind: "DestinationRule"}
+	gatewayHTTPRoutes     = schema.GroupKind{Group: "gateway.networking.k8s.io", Kind: "HTTPRoute"}
+
+	// ErrNoTrafficRouting is returned when neither Istio nor the Gateway API is installed in the cluster
+	ErrNoTrafficRouting = errors.New("neither Istio nor the Gateway API is installed in the cluster")
+)
+
+// TrafficRoute is a routing rule of an Istio VirtualService or a Gateway API HTTPRoute with its destinations.
+type TrafficRoute struct {
+	// Source is the VirtualService or HTTPRoute (Kind namespace/name) the rule belongs to
+	Source   string   `json:"source"`
+	Hosts    []string `json:"hosts,omitempty"`
+	Gateways []string `json:"gateways,omitempty"`
+	Rule     string   `json:"rule"`
+	Match    string   `json:"match"`
+	// Conditional is true when the rule also matches on headers, query parameters, or methods: the requests
+	// that don't satisfy them fall through to the next rules
+	Conditional  bool                 `json:"conditional,omitempty"`
+	Destinations []TrafficDestination `json:"destinations,omitempty"`
+	// Actions are the redirects, rewrites, timeouts, retries, fault injections, mirrors, and filters of the rule
+	Actions []string `json:"actions,omitempty"`
+	Issues  []string `json:"issues,omitempty"`
+}
+
+// TrafficDestination is a destination (backend) of a routing rule and the share of the traffic it receives.
+type TrafficDestination struct {
+	Host    string `json:"host"`
+	Subset  string `json:"subset,omitempty"`
+	Port    string `json:"port,omitempty"`
+	Weight  int64  `json:"weight"`
+	Percent int64  `json:"percent"`
+	// SubsetLabels are the labels of the Pods of the subset (from the DestinationRule)
+	SubsetLabels map[string]string `json:"subsetLabels,omitempty"`
+	// TrafficPolicy is the summary of the DestinationRule traffic policy applied to the destination
+	TrafficPolicy []string `json:"trafficPolicy,omitempty"`
+}
+
+// trafficSources are the routing resources the effective routing is computed from
+type trafficSources struct {
+	virtualServices  []unstructured.Unstructured
+	destinationRules []unstructured.Unstructured
+	httpRoutes       []unstructured.Unstructured
+}
+
+// TrafficRoutes summarizes the Istio VirtualServices (with their DestinationRules) and the Gateway API HTTPRoutes in
+// the provided namespace (all namespaces if empty). When a host is provided, only the rules effectively routing the
+// requests for the host and path are returned, in evaluation order, with the subsets and weights of their destinations.
+func (c *Core) TrafficRoutes(ctx context.Context, namespace, host, path string) ([]TrafficRoute, error) {
+	sources := trafficSources{}
+	var installed bool
+	var err error
+	if sources.virtualServices, installed, err = c.listInstalled(ctx, istioVirtualServices, namespace); err != nil {
+		return nil, err
+	}
+	istio := installed
+	if istio {
+		// The DestinationRules are best-effort, they might be in namespaces that are not accessible
+		sources.destinationRules, _, _ = c.listInstalled(ctx, istioDestinationRules, "")
+	}
+	if sources.httpRoutes, installed, err = c.listInstalled(ctx, gatewayHTTPRoutes, namespace); err != nil {
+		return nil, err
+	}
+	if !istio && !installed {
+		return nil, ErrNoTrafficRouting
+	}
+	return trafficRoutes(sources, host, cmp.Or(path, "/")), nil
+}
+
+// listInstalled lists the resources of the provided kind in its preferred version, installed is false if the kind is not served by the cluster
+func (c *Core) listInstalled(ctx context.Context, groupKind schema.GroupKind, namespace string) (items []unstructured.Unstructured, installed bool, err error) {
+	mapping, err := c.RESTMapper().RESTMapping(groupKind)
+	if meta.IsNoMatchError(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	list, err := c.DynamicClient().Resource(mapping.Resource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, true, err
+	}
+	return list.Items, true, nil
+}
+
+func trafficRoutes(sources trafficSources, host, path string) []TrafficRoute {
+	routes := make([]TrafficRoute, 0)
+	for _, virtualService := range sources.virtualServices {
+		routes = append(routes, virtualServiceRoutes(virtualService, sources.destinationRules, host, path)...)
+	}
+	for _, httpRoute := range sources.httpRoutes {
+		routes = append(routes, httpRouteRoutes(httpRoute, host, path)...)
+	}
+	return routes
+}
+
+func virtualServiceRoutes(virtualService unstructured.Unstructured, destinationRules []unstructured.Unstructured, host, path string) []TrafficRoute {
+	namespace := virtualService.GetNamespace()
+	hosts, _, _ := unstructured.NestedStringSlice(virtualService.Object, "spec", "hosts")
+	if host != "" && !slices.ContainsFunc(hosts, func(h string) bool { return trafficHostMatches(h, namespace, host) }) {
+		return nil
+	}
+	gateways, _, _ := unstructured.NestedStringSlice(virtualService.Object, "spec", "gateways")
+	httpRules, _, _ := unstructured.NestedSlice(virtualService.Object, "spec", "http")
+	var routes []TrafficRoute
+	for i, httpRule := range httpRules {
+		rule, _ := httpRule.(map[string]any)
+		matches, _ := rule["match"].([]any)
+		route := TrafficRoute{
+			Source:   "VirtualService " + namespace + "/" + virtualService.GetName(),
+			Hosts:    hosts,
+			Gateways: gateways,
+			Rule:     trafficRuleName(rule, i),
+			Match:    "any",
+		}
+		matched, unconditional := len(matches) == 0, len(matches) == 0
+		var descriptions []string
+		for _, match := range matches {
+			match, _ := match.(map[string]any)
+			description, conditional := istioMatchDescription(match)
+			descriptions = append(descriptions, description)
+			if istioPathMatches(match, path) {
+				matched = true
+				unconditional = unconditional || !conditional
+			}
+		}
+		route.Conditional = matched && !unconditional
+		if len(descriptions) > 0 {
+			route.Match = strings.Join(descriptions, " or ")
+		}
+		if host != "" && !matched {
+			continue
+		}
+		destinations, _ := rule["route"].([]any)
+		total := int64(0)
+		for _, destination := range destinations {
+			destination, _ := destination.(map[string]any)
+			trafficDestination := TrafficDestination{}
+			trafficDestination.Host, _, _ = unstructured.NestedString(destination, "destination", "host")
+			trafficDestination.Subset, _, _ = unstructured.NestedString(destination, "destination", "subset")
+			if port, found, _ := unstructured.NestedFieldNoCopy(destination, "destination", "port", "number"); found {
+				trafficDestination.Port = fmt.Sprint(port)
+			}
+			trafficDestination.Weight, _, _ = unstructured.NestedInt64(destination, "weight")
+			if len(destinations) == 1 && trafficDestination.Weight == 0 {
+				trafficDestination.Weight = 100
+			}
+			total += trafficDestination.Weight
+			route.Issues = append(route.Issues, trafficDestination.applyDestinationRule(destinationRules, namespace)...)
+			route.Destinations = append(route.Destinations, trafficDestination)
+		}
+		trafficPercents(route.Destinations, total)
+		if len(destinations) > 1 && total != 100 {
+			route.Issues = append(route.Issues, fmt.Sprintf("destination weights sum to %d instead of 100", total))
+		}
+		route.Actions = istioActions(rule)
+		routes = append(routes, route)
+		// Istio evaluates the rules in order, the first unconditional match receives all the remaining requests
+		if host != "" && !route.Conditional {
+			break
+		}
+	}
+	return routes
+}
+
+// applyDestinationRule sets the subset labels and traffic policy of the DestinationRule for the destination host
+func (d *TrafficDestination) applyDestinationRule(destinationRules []unstructured.Unstructured, namespace string) []string {
+	for _, destinationRule := range destinationRules {
+		ruleHost, _, _ := unstructured.NestedString(destinationRule.Object, "spec", "host")
+		if !trafficHostMatches(ruleHost, destinationRule.GetNamespace(), trafficFQDN(d.Host, namespace)) {
+			continue
+		}
+		trafficPolicy, _, _ := unstructured.NestedMap(destinationRule.Object, "spec", "trafficPolicy")
+		if d.Subset == "" {
+			d.TrafficPolicy = istioTrafficPolicy(trafficPolicy)
+			return nil
+		}
+		subsets, _, _ := unstructured.NestedSlice(destinationRule.Object, "spec", "subsets")
+		for _, subset := range subsets {
+			subset, _ := subset.(map[string]any)
+			if subset["name"] != d.Subset {
+				continue
+			}
+			d.SubsetLabels, _, _ = unstructured.NestedStringMap(subset, "labels")
+			// The subset traffic policy overrides the one of the DestinationRule
+			if subsetTrafficPolicy, ok := subset["trafficPolicy"].(map[string]any); ok {
+				trafficPolicy = subsetTrafficPolicy
+			}
+			d.TrafficPolicy = istioTrafficPolicy(trafficPolicy)
+			return nil
+		}
+		return []string{fmt.Sprintf("subset %s is not defined in DestinationRule %s/%s", d.Subset, destinationRule.GetNamespace(), destinationRule.GetName())}
+	}
+	if d.Subset != "" {
+		return []string{fmt.Sprintf("subset %s of host %s is not defined in any DestinationRule", d.Subset, d.Host)}
+	}
+	return nil
+}
+
+func httpRouteRoutes(httpRoute unstructured.Unstructured, host, path string) []TrafficRoute {
+	namespace := httpRoute.GetNamespace()
+	hostnames, _, _ := unstructured.NestedStringSlice(httpRoute.Object, "spec", "hostnames")
+	// An HTTPRoute without hostnames matches all the hosts of its Gateway listeners
+	if host != "" && len(hostnames) > 0 && !slices.ContainsFunc(hostnames, func(h string) bool { return trafficHostMatches(h, "", host) }) {
+		return nil
+	}
+	var gateways []string
+	parentRefs, _, _ := unstructured.NestedSlice(httpRoute.Object, "spec", "parentRefs")
+	for _, parentRef := range parentRefs {
+		parentRef, _ := parentRef.(map[string]any)
+		parentNamespace, _ := parentRef["namespace"].(string)
+		gateways = append(gateways, fmt.Sprintf("%s/%v", cmp.Or(parentNamespace, namespace), parentRef["name"]))
+	}
+	type candidate struct {
+		route       TrafficRoute
+		specificity int
+	}
+	var candidates []candidate
+	rules, _, _ := unstructured.NestedSlice(httpRoute.Object, "spec", "rules")
+	for i, rule := range rules {
+		rule, _ := rule.(map[string]any)
+		matches, _ := rule["matches"].([]any)
+		route := TrafficRoute{
+			Source:   "HTTPRoute " + namespace + "/" + httpRoute.GetName(),
+			Hosts:    hostnames,
+			Gateways: gateways,
+			Rule:     trafficRuleName(rule, i),
+			Match:    "path PathPrefix /",
+		}
+		// A rule without matches matches all the paths
+		specificity := -1
+		if len(matches) == 0 {
+			specificity = gatewayPathSpecificity(nil, path)
+		}
+		var descriptions []string
+		for _, match := range matches {
+			match, _ := match.(map[string]any)
+			description, conditional := gatewayMatchDescription(match)
+			descriptions = append(descriptions, description)
+			if matchSpecificity := gatewayPathSpecificity(match, path); matchSpecificity > specificity {
+				specificity = matchSpecificity
+				route.Conditional = conditional
+			}
+		}
+		if len(descriptions) > 0 {
+			route.Match = strings.Join(descriptions, " or ")
+		}
+		if host != "" && specificity < 0 {
+			continue
+		}
+		backendRefs, _ := rule["backendRefs"].([]any)
+		total := int64(0)
+		for _, backendRef := range backendRefs {
+			backendRef, _ := backendRef.(map[string]any)
+			destination := TrafficDestination{Weight: 1}
+			destination.Host = fmt.Sprint(backendRef["name"])
+			if backendNamespace, _ := backendRef["namespace"].(string); backendNamespace != "" && backendNamespace != namespace {
+				destination.Host = backendNamespace + "/" + destination.Host
+			}
+			if port, ok := backendRef["port"]; ok {
+				destination.Port = fmt.Sprint(port)
+			}
+			if weight, found, _ := unstructured.NestedInt64(backendRef, "weight"); found {
+				destination.Weight = weight
+			}
+			total += destination.Weight
+			route.Destinations = append(route.Destinations, destination)
+		}
+		trafficPercents(route.Destinations, total)
+		if len(backendRefs) > 0 && total == 0 {
+			route.Issues = append(route.Issues, "all the backends have weight 0, the requests fail with 500")
+		}
+		route.Actions = gatewayActions(rule)
+		candidates = append(candidates, candidate{route: route, specificity: specificity})
+	}
+	if host != "" {
+		// The most specific match wins, the rules defined first win the ties
+		slices.SortStableFunc(candidates, func(a, b candidate) int { return cmp.Compare(b.specificity, a.specificity) })
+	}
+	var routes []TrafficRoute
+	for _, candidate := range candidates {
+		routes = append(routes, candidate.route)
+		if host != "" && !candidate.route.Conditional {
+			break
+		}
+	}
+	return routes
+}
+
+// trafficHostMatches returns true if the route host (wildcards and short Service names allowed) matches the host
+func trafficHostMatches(routeHost, namespace, host string) bool {
+	if routeHost == "*" || routeHost == host {
+		return true
+	}
+	if strings.HasPrefix(routeHost, "*.") {
+		return strings.HasSuffix(host, routeHost[1:])
+	}
+	return namespace != "" && trafficFQDN(routeHost, namespace) == trafficFQDN(host, namespace)
+}
+
+// trafficFQDN expands the short Service names (e.g. reviews) the way Istio does (reviews.<namespace>.svc.cluster.local)
+func trafficFQDN(host, namespace string) string {
+	if strings.Contains(host, ".") || namespace == "" {
+		return host
+	}
+	return host + "." + namespace + ".svc.cluster.local"
+}
+
+func trafficRuleName(rule map[string]any, index int) string {
+	if name, _ := rule["name"].(string); name != "" {
+		return name
+	}
+	return fmt.Sprintf("#%d", index)
+}
+
+func trafficPercents(destinations []TrafficDestination, total int64) {
+	for i := range destinations {
+		if total > 0 {
+			destinations[i].Percent = destinations[i].Weight * 100 / total
+		}
+	}
+}
+
+func istioMatchDescription(match map[string]any) (string, bool) {
+	var parts []string
+	if uri, ok := match["uri"].(map[string]any); ok {
+		for _, matchType := range []string{"exact", "prefix", "regex"} {
+			if value, ok := uri[matchType]; ok {
+				parts = append(parts, fmt.Sprintf("uri %s %v", matchType, value))
+			}
+		}
+	}
+	conditional := false
+	for _, condition := range []string{"headers", "queryParams", "method", "authority", "port", "sourceLabels", "gateways"} {
+		if value, ok := match[condition]; ok {
+			conditional = true
+			if values, ok := value.(map[string]any); ok && condition != "method" && condition != "authority" {
+				parts = append(parts, fmt.Sprintf("%s %s", condition, strings.Join(slices.Sorted(maps.Keys(values)), ",")))
+			} else {
+				parts = append(parts, fmt.Sprintf("%s %v", condition, value))
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return "any", false
+	}
+	return strings.Join(parts, " and "), conditional
+}
+
+func istioPathMatches(match map[string]any, path string) bool {
+	uri, ok := match["uri"].(map[string]any)
+	if !ok {
+		return true
+	}
+	if exact, ok := uri["exact"].(string); ok {
+		return path == exact
+	}
+	if prefix, ok := uri["prefix"].(string); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	if regex, ok := uri["regex"].(string); ok {
+		matched, err := regexp.MatchString("^(?:"+regex+")$", path)
+		return err == nil && matched
+	}
+	return true
+}
+
+func istioActions(rule map[string]any) []string {
+	var actions []string
+	if redirect, ok := rule["redirect"].(map[string]any); ok {
+		actions = append(actions, fmt.Sprintf("redirect %s", trafficFields(redirect, "scheme", "authority", "uri", "redirectCode")))
+	}
+	if rewrite, ok := rule["rewrite"].(map[string]any); ok {
+		actions = append(actions, fmt.Sprintf("rewrite %s", trafficFields(rewrite, "authority", "uri")))
+	}
+	if timeout, ok := rule["timeout"]; ok {
+		actions = append(actions, fmt.Sprintf("timeout %v", timeout))
+	}
+	if retries, ok := rule["retries"].(map[string]any); ok {
+		actions = append(actions, fmt.Sprintf("retries %s", trafficFields(retries, "attempts", "perTryTimeout", "retryOn")))
+	}
+	if delay, found, _ := unstructured.NestedMap(rule, "fault", "delay"); found {
+		percentage, _, _ := unstructured.NestedFieldNoCopy(delay, "percentage", "value")
+		actions = append(actions, fmt.Sprintf("fault delay %v for %v%% of the requests", delay["fixedDelay"], cmp.Or(percentage, any(100))))
+	}
+	if abort, found, _ := unstructured.NestedMap(rule, "fault", "abort"); found {
+		percentage, _, _ := unstructured.NestedFieldNoCopy(abort, "percentage", "value")
+		actions = append(actions, fmt.Sprintf("fault abort with %v for %v%% of the requests", abort["httpStatus"], cmp.Or(percentage, any(100))))
+	}
+	if mirror, ok := rule["mirror"].(map[string]any); ok {
+		percentage, _, _ := unstructured.NestedFieldNoCopy(rule, "mirrorPercentage", "value")
+		actions = append(actions, fmt.Sprintf("mirror to %s (%v%%)", trafficFields(mirror, "host", "subset"), cmp.Or(percentage, any(100))))
+	}
+	return actions
+}
+
+func istioTrafficPolicy(trafficPolicy map[string]any) []string {
+	var policy []string
+	if simple, found, _ := unstructured.NestedString(trafficPolicy, "loadBalancer", "simple"); found {
+		policy = append(policy, "loadBalancer "+simple)
+	} else if _, found, _ := unstructured.NestedMap(trafficPolicy, "loadBalancer", "consistentHash"); found {
+		policy = append(policy, "loadBalancer consistentHash")
+	}
+	if mode, found, _ := unstructured.NestedString(trafficPolicy, "tls", "mode"); found {
+		policy = append(policy, "tls "+mode)
+	}
+	if connectionPool, ok := trafficPolicy["connectionPool"].(map[string]any); ok {
+		policy = append(policy, "connectionPool "+strings.Join(slices.Sorted(maps.Keys(connectionPool)), ","))
+	}
+	if outlierDetection, ok := trafficPolicy["outlierDetection"].(map[string]any); ok {
+		policy = append(policy, "outlierDetection "+trafficFields(outlierDetection, "consecutive5xxErrors", "consecutiveGatewayErrors", "interval", "baseEjectionTime"))
+	}
+	return policy
+}
+
+func gatewayMatchDescription(match map[string]any) (string, bool) {
+	pathType, _, _ := unstructured.NestedString(match, "path", "type")
+	pathValue, found, _ := unstructured.NestedString(match, "path", "value")
+	parts := []string{"path PathPrefix /"}
+	if found {
+		parts = []string{fmt.Sprintf("path %s %s", cmp.Or(pathType, "PathPrefix"), pathValue)}
+	}
+	conditional := false
+	for _, condition := range []string{"headers", "queryParams", "method"} {
+		value, ok := match[condition]
+		if !ok {
+			continue
+		}
+		conditional = true
+		if values, ok := value.([]any); ok {
+			var names []string
+			for _, v := range values {
+				v, _ := v.(map[string]any)
+				names = append(names, fmt.Sprint(v["name"]))
+			}
+			parts = append(parts, fmt.Sprintf("%s %s", condition, strings.Join(names, ",")))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s %v", condition, value))
+		}
+	}
+	return strings.Join(parts, " and "), conditional
+}
+
+// gatewayPathSpecificity returns how specific the path match of an HTTPRoute rule is for the path (the higher the
+// more specific, exact matches first, then the longest prefixes), or -1 if the path doesn't match
+func gatewayPathSpecificity(match map[string]any, path string) int {
+	pathType, _, _ := unstructured.NestedString(match, "path", "type")
+	value, found, _ := unstructured.NestedString(match, "path", "value")
+	if !found {
+		value = "/"
+	}
+	switch cmp.Or(pathType, "PathPrefix") {
+	case "Exact":
+		if path == value {
+			return 1<<16 + len(value)
+		}
+	case "PathPrefix":
+		prefix := strings.TrimSuffix(value, "/")
+		if prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return len(prefix)
+		}
+	case "RegularExpression":
+		if matched, err := regexp.MatchString("^(?:"+value+")$", path); err == nil && matched {
+			return len(value)
+		}
+	}
+	return -1
+}
+
+func gatewayActions(rule map[string]any) []string {
+	var actions []string
+	filters, _ := rule["filters"].([]any)
+	for _, filter := range filters {
+		filter, _ := filter.(map[string]any)
+		switch filter["type"] {
+		case "RequestRedirect":
+			redirect, _ := filter["requestRedirect"].(map[string]any)
+			actions = append(actions, "redirect "+trafficFields(redirect, "scheme", "hostname", "port", "path", "statusCode"))
+		case "URLRewrite":
+			rewrite, _ := filter["urlRewrite"].(map[string]any)
+			actions = append(actions, "rewrite "+trafficFields(rewrite, "hostname", "path"))
+		case "RequestMirror":
+			backendRef, _, _ := unstructured.NestedFieldNoCopy(filter, "requestMirror", "backendRef", "name")
+			actions = append(actions, fmt.Sprintf("mirror to %v", backendRef))
+		default:
+			actions = append(actions, fmt.Sprint(filter["type"]))
+		}
+	}
+	if timeouts, ok := rule["timeouts"].(map[string]any); ok {
+		actions = append(actions, "timeouts "+trafficFields(timeouts, "request", "backendRequest"))
+	}
+	return actions
+}
+
+// trafficFields returns the provided fields of the object as a compact key=value list
+func trafficFields(object map[string]any, fields ...string) string {
+	var values []string
+	for _, field := range fields {
+		value, ok := object[field]
+		if !ok {
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok {
+			var nestedValues []string
+			for _, key := range slices.Sorted(maps.Keys(nested)) {
+				nestedValues = append(nestedValues, fmt.Sprintf("%s=%v", key, nested[key]))
+			}
+			value = strings.Join(nestedValues, ",")
+		}
+		values = append(values, fmt.Sprintf("%s=%v", field, value))
+	}
+	return strings.Join(values, " ")
+}
@@ -0,0 +1,160 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type TrafficTestSuite struct {
+	suite.Suite
+	sources trafficSources
+}
+
+func (s *TrafficTestSuite) SetupTest() {
+	s.sources = trafficSources{
+		virtualServices: []unstructured.Unstructured{{Object: map[string]any{
+			"apiVersion": "networking.istio.io/v1",
+			"kind":       "VirtualService",
+			"metadata":   map[string]any{"name": "reviews", "namespace": "bookinfo"},
+			"spec": map[string]any{
+				"hosts": []any{"reviews"},
+				"http": []any{
+					map[string]any{
+						"name":  "jason",
+						"match": []any{map[string]any{"headers": map[string]any{"end-user": map[string]any{"exact": "jason"}}}},
+						"route": []any{map[string]any{"destination": map[string]any{"host": "reviews", "subset": "v2"}}},
+					},
+					map[string]any{
+						"match":   []any{map[string]any{"uri": map[string]any{"prefix": "/api"}}},
+						"route":   []any{map[string]any{"destination": map[string]any{"host": "reviews", "subset": "v3"}}},
+						"retries": map[string]any{"attempts": int64(3), "perTryTimeout": "2s"},
+					},
+					map[string]any{
+						"route": []any{
+							map[string]any{"destination": map[string]any{"host": "reviews", "subset": "v1"}, "weight": int64(80)},
+							map[string]any{"destination": map[string]any{"host": "reviews", "subset": "v4"}, "weight": int64(10)},
+						},
+					},
+				},
+			},
+		}}},
+		destinationRules: []unstructured.Unstructured{{Object: map[string]any{
+			"apiVersion": "networking.istio.io/v1",
+			"kind":       "DestinationRule",
+			"metadata":   map[string]any{"name": "reviews", "namespace": "bookinfo"},
+			"spec": map[string]any{
+				"host":          "reviews.bookinfo.svc.cluster.local",
+				"trafficPolicy": map[string]any{"loadBalancer": map[string]any{"simple": "ROUND_ROBIN"}},
+				"subsets": []any{
+					map[string]any{"name": "v1", "labels": map[string]any{"version": "v1"}},
+					map[string]any{"name": "v2", "labels": map[string]any{"version": "v2"}},
+					map[string]any{"name": "v3", "labels": map[string]any{"version": "v3"},
+						"trafficPolicy": map[string]any{"loadBalancer": map[string]any{"simple": "LEAST_REQUEST"}}},
+				},
+			},
+		}}},
+		httpRoutes: []unstructured.Unstructured{{Object: map[string]any{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata":   map[string]any{"name": "store", "namespace": "shop"},
+			"spec": map[string]any{
+				"hostnames":  []any{"*.example.com"},
+				"parentRefs": []any{map[string]any{"name": "public", "namespace": "infra"}},
+				"rules": []any{
+					map[string]any{
+						"backendRefs": []any{
+							map[string]any{"name": "store-v1", "port": int64(8080), "weight": int64(3)},
+							map[string]any{"name": "store-v2", "port": int64(8080)},
+						},
+					},
+					map[string]any{
+						"matches":     []any{map[string]any{"path": map[string]any{"type": "PathPrefix", "value": "/checkout"}}},
+						"backendRefs": []any{map[string]any{"name": "checkout", "port": int64(8080)}},
+						"filters":     []any{map[string]any{"type": "URLRewrite", "urlRewrite": map[string]any{"path": map[string]any{"type": "ReplacePrefixMatch", "replacePrefixMatch": "/"}}}},
+					},
+					map[string]any{
+						"matches":     []any{map[string]any{"path": map[string]any{"type": "Exact", "value": "/checkout/health"}}},
+						"backendRefs": []any{map[string]any{"name": "health", "port": int64(8080), "weight": int64(0)}},
+					},
+				},
+			},
+		}}},
+	}
+}
+
+func (s *TrafficTestSuite) TestVirtualService() {
+	s.Run("conditional rules fall through to the first unconditional match", func() {
+		routes := trafficRoutes(trafficSources{virtualServices: s.sources.virtualServices, destinationRules: s.sources.destinationRules}, "reviews.bookinfo.svc.cluster.local", "/api/ratings")
+		s.Require().Len(routes, 2)
+		s.Equal("jason", routes[0].Rule)
+		s.True(routes[0].Conditional)
+		s.Equal("headers end-user", routes[0].Match)
+		s.Equal("#1", routes[1].Rule)
+		s.False(routes[1].Conditional)
+		s.Equal([]string{"retries attempts=3 perTryTimeout=2s"}, routes[1].Actions)
+	})
+	s.Run("applies the subset labels and the subset traffic policy", func() {
+		routes := trafficRoutes(trafficSources{virtualServices: s.sources.virtualServices, destinationRules: s.sources.destinationRules}, "reviews", "/api")
+		s.Require().Len(routes, 2)
+		s.Equal(TrafficDestination{
+			Host: "reviews", Subset: "v3", Weight: 100, Percent: 100,
+			SubsetLabels:  map[string]string{"version": "v3"},
+			TrafficPolicy: []string{"loadBalancer LEAST_REQUEST"},
+		}, routes[1].Destinations[0])
+	})
+	s.Run("splits the traffic by weight and flags the issues", func() {
+		routes := trafficRoutes(trafficSources{virtualServices: s.sources.virtualServices, destinationRules: s.sources.destinationRules}, "reviews", "/")
+		s.Require().Len(routes, 2)
+		s.Equal("any", routes[1].Match)
+		s.Require().Len(routes[1].Destinations, 2)
+		s.Equal(int64(88), routes[1].Destinations[0].Percent)
+		s.Equal([]string{"loadBalancer ROUND_ROBIN"}, routes[1].Destinations[0].TrafficPolicy)
+		s.Equal([]string{
+			"subset v4 is not defined in DestinationRule bookinfo/reviews",
+			"destination weights sum to 90 instead of 100",
+		}, routes[1].Issues)
+	})
+	s.Run("ignores the other hosts", func() {
+		s.Empty(trafficRoutes(trafficSources{virtualServices: s.sources.virtualServices}, "ratings", "/"))
+		s.Empty(trafficRoutes(trafficSources{virtualServices: s.sources.virtualServices}, "reviews.other.svc.cluster.local", "/"))
+	})
+}
+
+func (s *TrafficTestSuite) TestHTTPRoute() {
+	s.Run("the longest prefix wins", func() {
+		routes := trafficRoutes(trafficSources{httpRoutes: s.sources.httpRoutes}, "www.example.com", "/checkout/cart")
+		s.Require().Len(routes, 1)
+		s.Equal("HTTPRoute shop/store", routes[0].Source)
+		s.Equal([]string{"infra/public"}, routes[0].Gateways)
+		s.Equal("path PathPrefix /checkout", routes[0].Match)
+		s.Equal([]string{"rewrite path=replacePrefixMatch=/,type=ReplacePrefixMatch"}, routes[0].Actions)
+	})
+	s.Run("exact matches win over prefixes", func() {
+		routes := trafficRoutes(trafficSources{httpRoutes: s.sources.httpRoutes}, "www.example.com", "/checkout/health")
+		s.Require().Len(routes, 1)
+		s.Equal("#2", routes[0].Rule)
+		s.Equal([]string{"all the backends have weight 0, the requests fail with 500"}, routes[0].Issues)
+	})
+	s.Run("backend weights default to 1", func() {
+		routes := trafficRoutes(trafficSources{httpRoutes: s.sources.httpRoutes}, "www.example.com", "/")
+		s.Require().Len(routes, 1)
+		s.Equal([]TrafficDestination{
+			{Host: "store-v1", Port: "8080", Weight: 3, Percent: 75},
+			{Host: "store-v2", Port: "8080", Weight: 1, Percent: 25},
+		}, routes[0].Destinations)
+	})
+	s.Run("ignores the other hostnames", func() {
+		s.Empty(trafficRoutes(trafficSources{httpRoutes: s.sources.httpRoutes}, "example.org", "/"))
+	})
+}
+
+func (s *TrafficTestSuite) TestWithoutHost() {
+	routes := trafficRoutes(s.sources, "", "/")
+	s.Len(routes, 6)
+}
+
+func TestTraffic(t *testing.T) {
+	suite.Run(t, new(TrafficTestSuite))
+}
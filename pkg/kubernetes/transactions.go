@@ -0,0 +1,233 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+const (
+	// transactionRetention is the duration a transaction can be rolled back for
+	transactionRetention = 24 * time.Hour
+	// maxTransactions bounds the number of kept transactions, the oldest ones are dropped first
+	maxTransactions = 100
+)
+
+var (
+	// ErrTransactionNotFound is returned when rolling back a transaction that doesn't exist, or expired
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrTransactionRolledBack is returned when rolling back a transaction a second time
+	ErrTransactionRolledBack = errors.New("transaction was already rolled back")
+)
+
+// Transaction records the state of the resources before a create or update, to restore it with TransactionRollback
+type Transaction struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Objects are the resources changed by the transaction, in the order they were applied
+	Objects    []TransactionObject `json:"objects"`
+	RolledBack bool                `json:"rolledBack,omitempty"`
+	// key identifies the API server and credentials of the transaction, only the same client can roll it back
+	key string
+	mu  sync.Mutex
+}
+
+type TransactionObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// Created is true if the resource didn't exist before the transaction, it is deleted on rollback
+	Created bool `json:"created,omitempty"`
+	// Rollback is the outcome of the rollback of the resource
+	Rollback string `json:"rollback,omitempty"`
+	previous *unstructured.Unstructured
+}
+
+func (o *TransactionObject) String() string {
+	if o.Namespace == "" {
+		return o.Kind + " " + o.Name
+	}
+	return o.Kind + " " + o.Namespace + "/" + o.Name
+}
+
+// transactions holds the transactions of the server, shared by the (derived) clients of every tool call
+var transactions = &transactionRegistry{now: time.Now}
+
+type transactionRegistry struct {
+	mu    sync.Mutex
+	items []*Transaction
+	now   func() time.Time
+}
+
+// add keeps the transaction, dropping the expired ones and the oldest ones beyond maxTransactions
+func (r *transactionRegistry) add(transaction *Transaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items = slices.DeleteFunc(r.items, func(t *Transaction) bool {
+		return r.now().Sub(t.CreatedAt) > transactionRetention
+	})
+	r.items = append(r.items, transaction)
+	if len(r.items) > maxTransactions {
+		r.items = r.items[len(r.items)-maxTransactions:]
+	}
+}
+
+func (r *transactionRegistry) get(key, id string) *Transaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, transaction := range r.items {
+		if transaction.ID == id && transaction.key == key && r.now().Sub(transaction.CreatedAt) <= transactionRetention {
+			return transaction
+		}
+	}
+	return nil
+}
+
+// ResourcesCreateOrUpdateInTransaction creates or updates the provided resources, like ResourcesCreateOrUpdateWithPrevious,
+// and records their previous state in a transaction. The transaction is returned (and kept) even if applying one of the
+// resources failed, to roll back the ones applied before the failure.
+func (c *Core) ResourcesCreateOrUpdateInTransaction(ctx context.Context, resource string) (transaction *Transaction, previous, ret []*unstructured.Unstructured, err error) {
+	parsedResources, err := parseResources(resource)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	previous = make([]*unstructured.Unstructured, len(parsedResources))
+	for i, obj := range parsedResources {
+		gvk := obj.GroupVersionKind()
+		var getErr error
+		previous[i], getErr = c.ResourcesGet(ctx, &gvk, obj.GetNamespace(), obj.GetName())
+		// the resource (or its kind, e.g. a CRD created in the same call) doesn't exist yet
+		if getErr != nil && !apierrors.IsNotFound(getErr) && !meta.IsNoMatchError(getErr) {
+			return nil, nil, nil, getErr
+		}
+	}
+	transaction = &Transaction{ID: rand.Text(), CreatedAt: transactions.now(), key: clientKey(c)}
+	for i, obj := range parsedResources {
+		applied, applyErr := c.resourcesCreateOrUpdate(ctx, []*unstructured.Unstructured{obj})
+		if applyErr != nil {
+			err = fmt.Errorf("failed to apply %s %s: %w", obj.GetKind(), obj.GetName(), applyErr)
+			break
+		}
+		ret = append(ret, applied[0])
+		transaction.Objects = append(transaction.Objects, TransactionObject{
+			APIVersion: applied[0].GetAPIVersion(),
+			Kind:       applied[0].GetKind(),
+			Namespace:  applied[0].GetNamespace(),
+			Name:       applied[0].GetName(),
+			Created:    previous[i] == nil,
+			previous:   previous[i],
+		})
+	}
+	if len(transaction.Objects) > 0 {
+		transactions.add(transaction)
+	} else {
+		transaction = nil
+	}
+	return transaction, previous, ret, err
+}
+
+// TransactionRollback restores the resources of the transaction to their state before it, in reverse order: the
+// created resources are deleted, and the updated ones are replaced by their previous state (re-created if deleted since)
+func (c *Core) TransactionRollback(ctx context.Context, id string) (*Transaction, error) {
+	transaction := transactions.get(clientKey(c), id)
+	if transaction == nil {
+		return nil, fmt.Errorf("%w: %s, transactions expire after %s", ErrTransactionNotFound, id, transactionRetention)
+	}
+	transaction.mu.Lock()
+	defer transaction.mu.Unlock()
+	if transaction.RolledBack {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionRolledBack, id)
+	}
+	var errs []error
+	for i := len(transaction.Objects) - 1; i >= 0; i-- {
+		obj := &transaction.Objects[i]
+		if err := c.rollbackObject(ctx, obj); err != nil {
+			obj.Rollback = "failed: " + err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", obj, err))
+		}
+	}
+	if len(errs) > 0 {
+		return transaction, fmt.Errorf("failed to roll back %d of the %d resources: %w", len(errs), len(transaction.Objects), errors.Join(errs...))
+	}
+	transaction.RolledBack = true
+	return transaction, nil
+}
+
+// TransactionPermissions returns the permissions required to roll back the transaction: delete for the resources it
+// created, and update for the ones it updated
+func (c *Core) TransactionPermissions(id string) ([]api.Permission, error) {
+	transaction := transactions.get(clientKey(c), id)
+	if transaction == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, id)
+	}
+	transaction.mu.Lock()
+	defer transaction.mu.Unlock()
+	permissions := make([]api.Permission, 0, len(transaction.Objects))
+	for _, obj := range transaction.Objects {
+		gv, err := schema.ParseGroupVersion(obj.APIVersion)
+		if err != nil {
+			return nil, err
+		}
+		verb := "update"
+		if obj.Created {
+			verb = "delete"
+		}
+		permission, err := c.ResourcePermission(&schema.GroupVersionKind{Group: gv.Group, Version: gv.Version, Kind: obj.Kind}, obj.Namespace, verb, "")
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, nil
+}
+
+func (c *Core) rollbackObject(ctx context.Context, obj *TransactionObject) error {
+	gv, err := schema.ParseGroupVersion(obj.APIVersion)
+	if err != nil {
+		return err
+	}
+	gvr, err := c.resourceFor(&schema.GroupVersionKind{Group: gv.Group, Version: gv.Version, Kind: obj.Kind})
+	if err != nil {
+		return err
+	}
+	client := c.DynamicClient().Resource(*gvr).Namespace(obj.Namespace)
+	if obj.Created {
+		if err = client.Delete(ctx, obj.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		obj.Rollback = "deleted"
+		return nil
+	}
+	previous := obj.previous.DeepCopy()
+	// an unconditional update, the changes made since the transaction are overwritten too
+	previous.SetResourceVersion("")
+	previous.SetManagedFields(nil)
+	_, err = client.Update(ctx, previous, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) {
+		previous.SetUID("")
+		previous.SetCreationTimestamp(metav1.Time{})
+		if _, err = client.Create(ctx, previous, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		obj.Rollback = "re-created"
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	obj.Rollback = "restored"
+	return nil
+}
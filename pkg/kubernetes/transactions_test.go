@@ -0,0 +1,173 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type TransactionsTestSuite struct {
+	suite.Suite
+	now      time.Time
+	registry *transactionRegistry
+}
+
+func (s *TransactionsTestSuite) SetupTest() {
+	s.now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.registry = &transactionRegistry{now: func() time.Time { return s.now }}
+}
+
+func (s *TransactionsTestSuite) TestGet() {
+	s.registry.add(&Transaction{ID: "tx", CreatedAt: s.now, key: "cluster-a"})
+	s.Run("returns the transaction of the same client", func() {
+		s.NotNil(s.registry.get("cluster-a", "tx"))
+	})
+	s.Run("hides the transaction from other clients", func() {
+		s.Nil(s.registry.get("cluster-b", "tx"))
+	})
+	s.Run("hides expired transactions", func() {
+		s.now = s.now.Add(transactionRetention + time.Second)
+		s.Nil(s.registry.get("cluster-a", "tx"))
+	})
+}
+
+func (s *TransactionsTestSuite) TestAdd() {
+	s.Run("drops expired transactions", func() {
+		s.registry.add(&Transaction{ID: "expired", CreatedAt: s.now, key: "cluster"})
+		s.now = s.now.Add(transactionRetention + time.Second)
+		s.registry.add(&Transaction{ID: "recent", CreatedAt: s.now, key: "cluster"})
+		s.Len(s.registry.items, 1)
+		s.Equal("recent", s.registry.items[0].ID)
+	})
+	s.Run("drops the oldest transactions beyond the maximum", func() {
+		for i := range maxTransactions {
+			s.registry.add(&Transaction{ID: fmt.Sprintf("tx-%d", i), CreatedAt: s.now, key: "cluster"})
+		}
+		s.Len(s.registry.items, maxTransactions)
+		s.Nil(s.registry.get("cluster", "recent"))
+		s.NotNil(s.registry.get("cluster", "tx-0"))
+	})
+}
+
+func (s *TransactionsTestSuite) TestTransactionObjectString() {
+	s.Equal("ConfigMap default/cm", (&TransactionObject{Kind: "ConfigMap", Namespace: "default", Name: "cm"}).String())
+	s.Equal("Namespace ns", (&TransactionObject{Kind: "Namespace", Name: "ns"}).String())
+}
+
+func (s *TransactionsTestSuite) TestRollbackInLabelScope() {
+	mockServer := test.NewMockServer()
+	defer mockServer.Close()
+	mockServer.Handle(test.NewDiscoveryClientHandler())
+	store := &podStore{pods: map[string]map[string]any{
+		"payments-pod": scopedPod("payments-pod", "payments", "v1"),
+		"billing-pod":  scopedPod("billing-pod", "billing", "v1"),
+	}}
+	mockServer.Handle(store)
+	k, err := NewKubernetes(config.Default(), nil, mockServer.Config())
+	s.Require().NoError(err)
+	core := NewCore(k)
+	ctx := WithLabelScope(s.T().Context(), "team=payments")
+	manifest := func(name, team, version string) string {
+		return fmt.Sprintf("apiVersion: v1\nkind: Pod\nmetadata:\n  name: %s\n  namespace: default\n  labels:\n    team: %s\n    version: %s\n", name, team, version)
+	}
+	s.Run("refuses to update the existing objects out of the scope", func() {
+		transaction, _, _, err := core.ResourcesCreateOrUpdateInTransaction(ctx, manifest("billing-pod", "payments", "v2"))
+		s.Truef(apierrors.IsForbidden(err), "expected forbidden error, got %v", err)
+		s.Nil(transaction)
+		s.Equal("v1", store.version("billing-pod"))
+	})
+	s.Run("rolls back the objects in the scope", func() {
+		transaction, _, _, err := core.ResourcesCreateOrUpdateInTransaction(ctx, manifest("payments-pod", "payments", "v2")+"---\n"+manifest("new-pod", "payments", "v2"))
+		s.Require().NoError(err)
+		s.Require().Len(transaction.Objects, 2)
+		s.False(transaction.Objects[0].Created)
+		s.True(transaction.Objects[1].Created)
+		s.Equal("v2", store.version("payments-pod"))
+		_, err = core.TransactionRollback(ctx, transaction.ID)
+		s.Require().NoError(err)
+		s.Equal("v1", store.version("payments-pod"))
+		s.Equal("", store.version("new-pod"), "expected the created object to be deleted")
+		s.Equal("v1", store.version("billing-pod"), "expected the object out of the scope to be kept")
+	})
+}
+
+func scopedPod(name, team, version string) map[string]any {
+	return map[string]any{"apiVersion": "v1", "kind": "Pod", "metadata": map[string]any{
+		"name": name, "namespace": "default", "labels": map[string]any{"team": team, "version": version},
+	}}
+}
+
+// podStore serves the Pods of the default namespace from memory, the lists are filtered by name and labels
+type podStore struct {
+	mu   sync.Mutex
+	pods map[string]map[string]any
+}
+
+func (p *podStore) version(name string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pod, ok := p.pods[name]; ok {
+		return (&unstructured.Unstructured{Object: pod}).GetLabels()["version"]
+	}
+	return ""
+}
+
+func (p *podStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutPrefix(r.URL.Path, "/api/v1/namespaces/default/pods")
+	if !ok {
+		return
+	}
+	name = strings.TrimPrefix(name, "/")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case name == "" && r.Method == http.MethodGet:
+		selector, _ := labels.Parse(r.URL.Query().Get("labelSelector"))
+		fieldName := strings.TrimPrefix(r.URL.Query().Get("fieldSelector"), "metadata.name=")
+		items := make([]any, 0)
+		for podName, pod := range p.pods {
+			if (fieldName == "" || podName == fieldName) && selector.Matches(labels.Set((&unstructured.Unstructured{Object: pod}).GetLabels())) {
+				items = append(items, pod)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"apiVersion": "v1", "kind": "PodList", "items": items})
+	case r.Method == http.MethodGet || r.Method == http.MethodDelete:
+		pod, exists := p.pods[name]
+		if !exists {
+			status := apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, name).Status()
+			status.APIVersion, status.Kind = "v1", "Status"
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(status)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(p.pods, name)
+		}
+		_ = json.NewEncoder(w).Encode(pod)
+	case r.Method == http.MethodPatch || r.Method == http.MethodPut || r.Method == http.MethodPost:
+		body, _ := io.ReadAll(r.Body)
+		pod := map[string]any{}
+		_ = json.Unmarshal(body, &pod)
+		p.pods[(&unstructured.Unstructured{Object: pod}).GetName()] = pod
+		_ = json.NewEncoder(w).Encode(pod)
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	suite.Run(t, new(TransactionsTestSuite))
+}
@@ -0,0 +1,106 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+const (
+	// TransientLabel marks the short-lived objects the server creates to run a tool call (the node debug and the
+	// network check Pods), the tool call deletes them once done
+	TransientLabel = "kubernetes-mcp-server/transient"
+	// TransientExpiresAtAnnotation is the time (RFC 3339) after which the transient object can be garbage collected
+	TransientExpiresAtAnnotation = "kubernetes-mcp-server/expires-at"
+	// TransientGracePeriod is added to the expected lifetime of the transient objects before they expire
+	TransientGracePeriod = time.Minute
+)
+
+// TransientObject is a short-lived object created by the server.
+// The labels and annotations of the objects are the registry of the transient objects, so that the objects left
+// behind by a crash or a restart of the server are still garbage collected.
+// Only Pods are transient objects: the registry lists and deletes the labeled Pods, the tools creating other kinds of
+// short-lived objects need to extend it.
+type TransientObject struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Component string    `json:"component"`
+	Created   time.Time `json:"created"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Expired   bool      `json:"expired"`
+}
+
+// transientObjectMeta returns the metadata of a transient object, it expires after the provided lifetime
+// (plus TransientGracePeriod)
+func transientObjectMeta(namespace, name, component string, lifetime time.Duration) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{
+		AppKubernetesName:      name,
+		AppKubernetesComponent: component,
+		AppKubernetesManagedBy: version.BinaryName,
+		AppKubernetesPartOf:    version.BinaryName + "-transient",
+		TransientLabel:         "true",
+	}, Annotations: map[string]string{
+		TransientExpiresAtAnnotation: time.Now().Add(lifetime + TransientGracePeriod).UTC().Format(time.RFC3339),
+	}}
+}
+
+// TransientObjects returns the transient objects (Pods) in the provided namespace (all namespaces if empty),
+// the objects without a valid expiration time are reported as expired
+func (c *Core) TransientObjects(ctx context.Context, namespace string) ([]TransientObject, error) {
+	pods, err := c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: TransientLabel + "=true"})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	objects := make([]TransientObject, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		object := TransientObject{
+			Kind:      "Pod",
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Component: pod.Labels[AppKubernetesComponent],
+			Created:   pod.CreationTimestamp.Time,
+		}
+		object.ExpiresAt, err = time.Parse(time.RFC3339, pod.Annotations[TransientExpiresAtAnnotation])
+		object.Expired = err != nil || now.After(object.ExpiresAt)
+		objects = append(objects, object)
+	}
+	slices.SortFunc(objects, func(a, b TransientObject) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	return objects, nil
+}
+
+// TransientCleanup deletes the expired transient objects (Pods) in the provided namespace (all namespaces if empty), or all
+// of them (including the ones of the tool calls in progress) if all is true, and returns the deleted objects
+func (c *Core) TransientCleanup(ctx context.Context, namespace string, all, dryRun bool) ([]TransientObject, error) {
+	objects, err := c.TransientObjects(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	deleted := make([]TransientObject, 0, len(objects))
+	for _, object := range objects {
+		if !object.Expired && !all {
+			continue
+		}
+		if !dryRun {
+			err = c.CoreV1().Pods(object.Namespace).Delete(ctx, object.Name, metav1.DeleteOptions{GracePeriodSeconds: ptr.To(int64(0))})
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return deleted, err
+			}
+		}
+		deleted = append(deleted, object)
+	}
+	return deleted, nil
+}
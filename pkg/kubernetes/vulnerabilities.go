@@ -0,0 +1,226 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// vulnerabilitiesTopFindings is the maximum number of findings detailed for each image
+	vulnerabilitiesTopFindings = 10
+
+	trivyResourceKindLabel  = "trivy-operator.resource.kind"
+	trivyResourceNameLabel  = "trivy-operator.resource.name"
+	trivyContainerNameLabel = "trivy-operator.container.name"
+)
+
+// VulnerabilityWorkloadKinds are the kinds of workloads whose images can be looked up by ImageVulnerabilities
+var VulnerabilityWorkloadKinds = []string{"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job", "CronJob", "Pod"}
+
+// vulnerabilitySeverities are the severities reported by the scanner, from the most to the least severe
+var vulnerabilitySeverities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "UNKNOWN"}
+
+var trivyVulnerabilityReports = schema.GroupVersionResource{Group: "aquasecurity.github.io", Version: "v1alpha1", Resource: "vulnerabilityreports"}
+
+// ErrNoVulnerabilityScanner is returned when no supported in-cluster vulnerability scanner is installed
+var ErrNoVulnerabilityScanner = errors.New("no in-cluster vulnerability scanner found, install the Trivy Operator (https://github.com/aquasecurity/trivy-operator) to scan the cluster images")
+
+// ImageVulnerabilities are the vulnerabilities found in a container image by the in-cluster scanner.
+type ImageVulnerabilities struct {
+	Image string `json:"image"`
+	// Workloads are the workloads (and containers) running the image
+	Workloads []string            `json:"workloads"`
+	Scanner   string              `json:"scanner,omitempty"`
+	ScannedAt string              `json:"scannedAt,omitempty"`
+	Counts    VulnerabilityCounts `json:"counts"`
+	// Fixable is the number of vulnerabilities with a fixed version available
+	Fixable       int             `json:"fixable"`
+	WorstFindings []Vulnerability `json:"worstFindings,omitempty"`
+}
+
+// VulnerabilityCounts is the number of vulnerabilities by severity.
+type VulnerabilityCounts struct {
+	Critical int64 `json:"critical"`
+	High     int64 `json:"high"`
+	Medium   int64 `json:"medium"`
+	Low      int64 `json:"low"`
+	Unknown  int64 `json:"unknown"`
+}
+
+// Vulnerability is a vulnerability found in a package of a container image.
+type Vulnerability struct {
+	ID               string  `json:"vulnerabilityID"`
+	Severity         string  `json:"severity"`
+	Score            float64 `json:"score,omitempty"`
+	Resource         string  `json:"resource"`
+	InstalledVersion string  `json:"installedVersion"`
+	FixedVersion     string  `json:"fixedVersion,omitempty"`
+	Title            string  `json:"title,omitempty"`
+	PrimaryLink      string  `json:"primaryLink,omitempty"`
+}
+
+// trivyVulnerabilityReport contains the fields of the Trivy Operator VulnerabilityReport used to summarize the vulnerabilities
+type trivyVulnerabilityReport struct {
+	Report struct {
+		UpdateTimestamp string `json:"updateTimestamp"`
+		Scanner         struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"scanner"`
+		Registry struct {
+			Server string `json:"server"`
+		} `json:"registry"`
+		Artifact struct {
+			Repository string `json:"repository"`
+			Tag        string `json:"tag"`
+			Digest     string `json:"digest"`
+		} `json:"artifact"`
+		Summary *struct {
+			CriticalCount int64 `json:"criticalCount"`
+			HighCount     int64 `json:"highCount"`
+			MediumCount   int64 `json:"mediumCount"`
+			LowCount      int64 `json:"lowCount"`
+			UnknownCount  int64 `json:"unknownCount"`
+		} `json:"summary"`
+		Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+	} `json:"report"`
+}
+
+// ImageVulnerabilities returns the vulnerabilities of the images run by the provided workload (or by all the workloads
+// in the namespace if kind is empty), as reported by the in-cluster scanner (Trivy Operator VulnerabilityReports).
+func (c *Core) ImageVulnerabilities(ctx context.Context, namespace, kind, name string) ([]ImageVulnerabilities, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	var workloads []string
+	if kind != "" {
+		if !slices.Contains(VulnerabilityWorkloadKinds, kind) {
+			return nil, fmt.Errorf("unsupported workload kind %s, supported kinds are %v", kind, VulnerabilityWorkloadKinds)
+		}
+		workloads = append(workloads, kind+"/"+name)
+		// The images of Deployments are scanned through their ReplicaSets
+		if kind == "Deployment" {
+			replicaSets, err := c.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			for _, replicaSet := range replicaSets.Items {
+				if owner := metav1.GetControllerOf(&replicaSet); owner != nil && owner.Kind == kind && owner.Name == name {
+					workloads = append(workloads, "ReplicaSet/"+replicaSet.Name)
+				}
+			}
+		}
+	}
+	reports, err := c.DynamicClient().Resource(trivyVulnerabilityReports).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+		return nil, ErrNoVulnerabilityScanner
+	} else if err != nil {
+		return nil, err
+	}
+	return summarizeVulnerabilityReports(reports.Items, workloads)
+}
+
+// summarizeVulnerabilityReports aggregates the reports by image, only the reports of the provided workloads (Kind/Name)
+// are considered unless workloads is nil
+func summarizeVulnerabilityReports(reports []unstructured.Unstructured, workloads []string) ([]ImageVulnerabilities, error) {
+	images := map[string]*ImageVulnerabilities{}
+	for _, item := range reports {
+		labels := item.GetLabels()
+		workload := labels[trivyResourceKindLabel] + "/" + labels[trivyResourceNameLabel]
+		if workloads != nil && !slices.Contains(workloads, workload) {
+			continue
+		}
+		report := trivyVulnerabilityReport{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &report); err != nil {
+			return nil, fmt.Errorf("failed to parse VulnerabilityReport %s/%s: %w", item.GetNamespace(), item.GetName(), err)
+		}
+		artifact := report.Report.Artifact
+		image := strings.TrimPrefix(report.Report.Registry.Server+"/"+artifact.Repository, "/")
+		if artifact.Tag != "" {
+			image += ":" + artifact.Tag
+		}
+		if artifact.Digest != "" {
+			image += "@" + artifact.Digest
+		}
+		if container := labels[trivyContainerNameLabel]; container != "" {
+			workload += " (container " + container + ")"
+		}
+		vulnerabilities, ok := images[image]
+		if ok {
+			// The same image run by several workloads, the findings are the same
+			vulnerabilities.Workloads = append(vulnerabilities.Workloads, workload)
+			continue
+		}
+		vulnerabilities = &ImageVulnerabilities{
+			Image:     image,
+			Workloads: []string{workload},
+			Scanner:   strings.TrimSpace(report.Report.Scanner.Name + " " + report.Report.Scanner.Version),
+			ScannedAt: report.Report.UpdateTimestamp,
+		}
+		images[image] = vulnerabilities
+		findings := report.Report.Vulnerabilities
+		for _, finding := range findings {
+			if finding.FixedVersion != "" {
+				vulnerabilities.Fixable++
+			}
+			if report.Report.Summary == nil {
+				switch strings.ToUpper(finding.Severity) {
+				case "CRITICAL":
+					vulnerabilities.Counts.Critical++
+				case "HIGH":
+					vulnerabilities.Counts.High++
+				case "MEDIUM":
+					vulnerabilities.Counts.Medium++
+				case "LOW":
+					vulnerabilities.Counts.Low++
+				default:
+					vulnerabilities.Counts.Unknown++
+				}
+			}
+		}
+		if summary := report.Report.Summary; summary != nil {
+			vulnerabilities.Counts = VulnerabilityCounts{Critical: summary.CriticalCount, High: summary.HighCount,
+				Medium: summary.MediumCount, Low: summary.LowCount, Unknown: summary.UnknownCount}
+		}
+		slices.SortFunc(findings, func(a, b Vulnerability) int {
+			return cmp.Or(
+				cmp.Compare(vulnerabilitySeverityRank(a.Severity), vulnerabilitySeverityRank(b.Severity)),
+				cmp.Compare(b.Score, a.Score),
+				cmp.Compare(a.ID, b.ID),
+				cmp.Compare(a.Resource, b.Resource),
+			)
+		})
+		vulnerabilities.WorstFindings = findings[:min(len(findings), vulnerabilitiesTopFindings)]
+	}
+	ret := make([]ImageVulnerabilities, 0, len(images))
+	for _, vulnerabilities := range images {
+		slices.Sort(vulnerabilities.Workloads)
+		ret = append(ret, *vulnerabilities)
+	}
+	slices.SortFunc(ret, func(a, b ImageVulnerabilities) int {
+		return cmp.Or(
+			cmp.Compare(b.Counts.Critical, a.Counts.Critical),
+			cmp.Compare(b.Counts.High, a.Counts.High),
+			cmp.Compare(b.Counts.Medium, a.Counts.Medium),
+			cmp.Compare(b.Counts.Low, a.Counts.Low),
+			cmp.Compare(a.Image, b.Image),
+		)
+	})
+	return ret, nil
+}
+
+func vulnerabilitySeverityRank(severity string) int {
+	if rank := slices.Index(vulnerabilitySeverities, strings.ToUpper(severity)); rank >= 0 {
+		return rank
+	}
+	return len(vulnerabilitySeverities)
+}
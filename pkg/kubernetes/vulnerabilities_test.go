@@ -0,0 +1,88 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type VulnerabilitiesTestSuite struct {
+	suite.Suite
+}
+
+func vulnerabilityReport(name, kind, workload, container, repository, tag string, summary map[string]any, vulnerabilities ...any) unstructured.Unstructured {
+	report := map[string]any{
+		"updateTimestamp": "2025-01-01T00:00:00Z",
+		"scanner":         map[string]any{"name": "Trivy", "version": "0.60.0"},
+		"registry":        map[string]any{"server": "index.docker.io"},
+		"artifact":        map[string]any{"repository": repository, "tag": tag},
+		"vulnerabilities": vulnerabilities,
+	}
+	if summary != nil {
+		report["summary"] = summary
+	}
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "aquasecurity.github.io/v1alpha1",
+		"kind":       "VulnerabilityReport",
+		"metadata": map[string]any{"name": name, "namespace": "apps", "labels": map[string]any{
+			trivyResourceKindLabel:  kind,
+			trivyResourceNameLabel:  workload,
+			trivyContainerNameLabel: container,
+		}},
+		"report": report,
+	}}
+}
+
+func (s *VulnerabilitiesTestSuite) TestSummarizeVulnerabilityReports() {
+	reports := []unstructured.Unstructured{
+		vulnerabilityReport("replicaset-web-nginx", "ReplicaSet", "web-5d4f8", "nginx", "library/nginx", "1.25",
+			map[string]any{"criticalCount": int64(1), "highCount": int64(2), "mediumCount": int64(0), "lowCount": int64(7), "unknownCount": int64(0)},
+			map[string]any{"vulnerabilityID": "CVE-2025-0002", "severity": "HIGH", "score": 7.5, "resource": "libssl3", "installedVersion": "3.0.1"},
+			map[string]any{"vulnerabilityID": "CVE-2025-0001", "severity": "CRITICAL", "score": int64(9), "resource": "zlib", "installedVersion": "1.2", "fixedVersion": "1.3"},
+			map[string]any{"vulnerabilityID": "CVE-2025-0003", "severity": "HIGH", "score": 8.1, "resource": "curl", "installedVersion": "7.0", "fixedVersion": "8.0"},
+		),
+		vulnerabilityReport("statefulset-db-nginx", "StatefulSet", "db", "proxy", "library/nginx", "1.25",
+			map[string]any{"criticalCount": int64(1), "highCount": int64(2), "mediumCount": int64(0), "lowCount": int64(7), "unknownCount": int64(0)}),
+		vulnerabilityReport("daemonset-agent", "DaemonSet", "agent", "agent", "example/agent", "2.0", nil,
+			map[string]any{"vulnerabilityID": "CVE-2024-1000", "severity": "MEDIUM", "resource": "busybox", "installedVersion": "1.36"},
+			map[string]any{"vulnerabilityID": "CVE-2024-1001", "severity": "LOW", "resource": "busybox", "installedVersion": "1.36", "fixedVersion": "1.37"},
+		),
+	}
+	s.Run("aggregates the reports by image", func() {
+		images, err := summarizeVulnerabilityReports(reports, nil)
+		s.Require().NoError(err)
+		s.Require().Len(images, 2)
+		s.Equal("index.docker.io/library/nginx:1.25", images[0].Image)
+		s.Equal([]string{"ReplicaSet/web-5d4f8 (container nginx)", "StatefulSet/db (container proxy)"}, images[0].Workloads)
+		s.Equal("Trivy 0.60.0", images[0].Scanner)
+		s.Equal(VulnerabilityCounts{Critical: 1, High: 2, Low: 7}, images[0].Counts)
+		s.Equal(2, images[0].Fixable)
+	})
+	s.Run("sorts the worst findings by severity and score", func() {
+		images, err := summarizeVulnerabilityReports(reports, nil)
+		s.Require().NoError(err)
+		s.Require().Len(images[0].WorstFindings, 3)
+		s.Equal("CVE-2025-0001", images[0].WorstFindings[0].ID)
+		s.Equal(float64(9), images[0].WorstFindings[0].Score)
+		s.Equal("CVE-2025-0003", images[0].WorstFindings[1].ID)
+		s.Equal("CVE-2025-0002", images[0].WorstFindings[2].ID)
+	})
+	s.Run("counts the findings when the summary is missing", func() {
+		images, err := summarizeVulnerabilityReports(reports, nil)
+		s.Require().NoError(err)
+		s.Equal("index.docker.io/example/agent:2.0", images[1].Image)
+		s.Equal(VulnerabilityCounts{Medium: 1, Low: 1}, images[1].Counts)
+		s.Equal(1, images[1].Fixable)
+	})
+	s.Run("filters the reports by workload", func() {
+		images, err := summarizeVulnerabilityReports(reports, []string{"DaemonSet/agent"})
+		s.Require().NoError(err)
+		s.Require().Len(images, 1)
+		s.Equal([]string{"DaemonSet/agent (container agent)"}, images[0].Workloads)
+	})
+}
+
+func TestVulnerabilities(t *testing.T) {
+	suite.Run(t, new(VulnerabilitiesTestSuite))
+}
@@ -0,0 +1,186 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	WebhookBackendHealthy          = "healthy"
+	WebhookBackendNoReadyEndpoints = "no ready endpoints"
+	WebhookBackendServiceNotFound  = "service not found"
+	WebhookBackendExternal         = "external URL (not checked)"
+	WebhookBackendUnknown          = "unknown"
+
+	// webhookHighTimeoutSeconds is the timeout above which a webhook is reported as adding too much latency
+	webhookHighTimeoutSeconds = 15
+)
+
+// webhookCriticalResources are the resources whose operations are critical for the cluster to work
+var webhookCriticalResources = []string{"*", "pods", "nodes", "namespaces", "leases", "configmaps", "secrets", "serviceaccounts", "endpoints", "endpointslices"}
+
+// WebhookAudit is the result of the audit of an admission webhook.
+type WebhookAudit struct {
+	Kind           string   `json:"kind"`
+	Configuration  string   `json:"configuration"`
+	Name           string   `json:"name"`
+	FailurePolicy  string   `json:"failurePolicy"`
+	TimeoutSeconds int32    `json:"timeoutSeconds"`
+	Rules          []string `json:"rules"`
+	Backend        string   `json:"backend"`
+	BackendStatus  string   `json:"backendStatus"`
+	// Blocking is true if the webhook rejects the requests it targets when its backend is down
+	Blocking bool     `json:"blocking"`
+	Issues   []string `json:"issues,omitempty"`
+}
+
+// webhookInfo contains the fields shared by the validating and mutating webhooks
+type webhookInfo struct {
+	kind, configuration, name string
+	clientConfig              admissionregistrationv1.WebhookClientConfig
+	rules                     []admissionregistrationv1.RuleWithOperations
+	failurePolicy             *admissionregistrationv1.FailurePolicyType
+	timeoutSeconds            *int32
+	namespaceSelector         *metav1.LabelSelector
+}
+
+// WebhooksAudit lists the validating and mutating admission webhooks with their failure policy, timeout, targeted
+// resources, and backing Service health, flagging the ones that would block critical operations if their backend is down.
+func (c *Core) WebhooksAudit(ctx context.Context) ([]WebhookAudit, error) {
+	validating, err := c.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	mutating, err := c.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var webhooks []webhookInfo
+	for _, configuration := range validating.Items {
+		for _, webhook := range configuration.Webhooks {
+			webhooks = append(webhooks, webhookInfo{"ValidatingWebhookConfiguration", configuration.Name, webhook.Name, webhook.ClientConfig,
+				webhook.Rules, webhook.FailurePolicy, webhook.TimeoutSeconds, webhook.NamespaceSelector})
+		}
+	}
+	for _, configuration := range mutating.Items {
+		for _, webhook := range configuration.Webhooks {
+			webhooks = append(webhooks, webhookInfo{"MutatingWebhookConfiguration", configuration.Name, webhook.Name, webhook.ClientConfig,
+				webhook.Rules, webhook.FailurePolicy, webhook.TimeoutSeconds, webhook.NamespaceSelector})
+		}
+	}
+	// Health of the backing Services
+	backendStatus := map[string]string{}
+	for _, webhook := range webhooks {
+		service := webhook.clientConfig.Service
+		if service == nil {
+			continue
+		}
+		key := service.Namespace + "/" + service.Name
+		if _, ok := backendStatus[key]; ok {
+			continue
+		}
+		// The health of the backends is best-effort, the Services might not be accessible
+		backendStatus[key] = WebhookBackendUnknown
+		if _, err := c.CoreV1().Services(service.Namespace).Get(ctx, service.Name, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+			backendStatus[key] = WebhookBackendServiceNotFound
+			continue
+		} else if err != nil {
+			continue
+		}
+		endpointSlices, err := c.DiscoveryV1().EndpointSlices(service.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: discoveryv1.LabelServiceName + "=" + service.Name,
+		})
+		if err != nil {
+			continue
+		}
+		backendStatus[key] = WebhookBackendNoReadyEndpoints
+		if readyEndpoints(endpointSlices.Items) > 0 {
+			backendStatus[key] = WebhookBackendHealthy
+		}
+	}
+	return auditWebhooks(webhooks, backendStatus), nil
+}
+
+func auditWebhooks(webhooks []webhookInfo, backendStatus map[string]string) []WebhookAudit {
+	// kube-system namespace labels, to check if the webhooks intercept the requests of the system components
+	kubeSystem := labels.Set{v1.LabelMetadataName: metav1.NamespaceSystem}
+	audits := make([]WebhookAudit, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		audit := WebhookAudit{
+			Kind:           webhook.kind,
+			Configuration:  webhook.configuration,
+			Name:           webhook.name,
+			FailurePolicy:  string(ptr.Deref(webhook.failurePolicy, admissionregistrationv1.Fail)),
+			TimeoutSeconds: ptr.Deref(webhook.timeoutSeconds, 10),
+		}
+		critical := false
+		for _, rule := range webhook.rules {
+			operations := make([]string, 0, len(rule.Operations))
+			for _, operation := range rule.Operations {
+				operations = append(operations, string(operation))
+			}
+			audit.Rules = append(audit.Rules, fmt.Sprintf("%s %s/%s %s", strings.Join(operations, ","),
+				strings.Join(rule.APIGroups, ","), strings.Join(rule.APIVersions, ","), strings.Join(rule.Resources, ",")))
+			for _, resource := range rule.Resources {
+				critical = critical || slices.Contains(webhookCriticalResources, strings.Split(resource, "/")[0])
+			}
+		}
+		if service := webhook.clientConfig.Service; service != nil {
+			audit.Backend = fmt.Sprintf("service %s/%s:%d%s", service.Namespace, service.Name, ptr.Deref(service.Port, 443), ptr.Deref(service.Path, ""))
+			audit.BackendStatus = backendStatus[service.Namespace+"/"+service.Name]
+		} else {
+			audit.Backend = "url " + ptr.Deref(webhook.clientConfig.URL, "")
+			audit.BackendStatus = WebhookBackendExternal
+		}
+		selector, err := metav1.LabelSelectorAsSelector(webhook.namespaceSelector)
+		interceptsKubeSystem := err != nil || webhook.namespaceSelector == nil || selector.Matches(kubeSystem)
+		audit.Blocking = audit.FailurePolicy == string(admissionregistrationv1.Fail)
+		backendDown := audit.BackendStatus == WebhookBackendNoReadyEndpoints || audit.BackendStatus == WebhookBackendServiceNotFound
+		switch {
+		case audit.Blocking && backendDown:
+			audit.Issues = append(audit.Issues, fmt.Sprintf("backend is down (%s) and failurePolicy is Fail: the targeted operations are currently rejected", audit.BackendStatus))
+		case backendDown:
+			audit.Issues = append(audit.Issues, fmt.Sprintf("backend is down (%s), the targeted operations are not being validated or mutated", audit.BackendStatus))
+		}
+		if audit.Blocking && critical && interceptsKubeSystem {
+			audit.Issues = append(audit.Issues, "targets critical resources, including in kube-system, with failurePolicy Fail: an outage of the backend would block critical cluster operations")
+		}
+		if audit.TimeoutSeconds > webhookHighTimeoutSeconds {
+			audit.Issues = append(audit.Issues, fmt.Sprintf("timeout of %ds can add up to %ds of latency to each targeted request", audit.TimeoutSeconds, audit.TimeoutSeconds))
+		}
+		audits = append(audits, audit)
+	}
+	slices.SortStableFunc(audits, func(a, b WebhookAudit) int {
+		return cmp.Or(
+			cmp.Compare(len(b.Issues), len(a.Issues)),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Configuration, b.Configuration),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return audits
+}
+
+// readyEndpoints returns the number of ready endpoints in the provided EndpointSlices
+func readyEndpoints(endpointSlices []discoveryv1.EndpointSlice) int {
+	ready := 0
+	for _, slice := range endpointSlices {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready++
+			}
+		}
+	}
+	return ready
+}
@@ -0,0 +1,69 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type WebhooksTestSuite struct {
+	suite.Suite
+}
+
+func (s *WebhooksTestSuite) TestAuditWebhooks() {
+	serviceConfig := func(name string) admissionregistrationv1.WebhookClientConfig {
+		return admissionregistrationv1.WebhookClientConfig{Service: &admissionregistrationv1.ServiceReference{Namespace: "webhooks", Name: name, Path: ptr.To("/validate")}}
+	}
+	podRules := []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+		Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"pods"}},
+	}}
+	appRules := []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		Rule:       admissionregistrationv1.Rule{APIGroups: []string{"example.com"}, APIVersions: []string{"v1"}, Resources: []string{"widgets"}},
+	}}
+	excludeKubeSystem := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+		Key: "kubernetes.io/metadata.name", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"kube-system"},
+	}}}
+	webhooks := []webhookInfo{
+		{kind: "ValidatingWebhookConfiguration", configuration: "policy", name: "pods.policy.io", clientConfig: serviceConfig("policy"), rules: podRules},
+		{kind: "ValidatingWebhookConfiguration", configuration: "policy", name: "scoped.policy.io", clientConfig: serviceConfig("policy"), rules: podRules, namespaceSelector: excludeKubeSystem},
+		{kind: "MutatingWebhookConfiguration", configuration: "widgets", name: "widgets.example.com", clientConfig: serviceConfig("widgets"), rules: appRules,
+			failurePolicy: ptr.To(admissionregistrationv1.Ignore), timeoutSeconds: ptr.To(int32(30))},
+		{kind: "MutatingWebhookConfiguration", configuration: "external", name: "external.example.com",
+			clientConfig: admissionregistrationv1.WebhookClientConfig{URL: ptr.To("https://hooks.example.com/mutate")}, rules: appRules},
+	}
+	audits := auditWebhooks(webhooks, map[string]string{"webhooks/policy": WebhookBackendHealthy, "webhooks/widgets": WebhookBackendNoReadyEndpoints})
+	s.Require().Len(audits, 4)
+	s.Run("flags critical webhooks intercepting kube-system", func() {
+		s.Equal("pods.policy.io", audits[1].Name)
+		s.True(audits[1].Blocking)
+		s.Equal("Fail", audits[1].FailurePolicy)
+		s.Equal(int32(10), audits[1].TimeoutSeconds)
+		s.Equal([]string{"CREATE,UPDATE /v1 pods"}, audits[1].Rules)
+		s.Equal("service webhooks/policy:443/validate", audits[1].Backend)
+		s.Equal([]string{"targets critical resources, including in kube-system, with failurePolicy Fail: an outage of the backend would block critical cluster operations"}, audits[1].Issues)
+	})
+	s.Run("flags down backends and high timeouts", func() {
+		s.Equal("widgets.example.com", audits[0].Name)
+		s.False(audits[0].Blocking)
+		s.Equal([]string{
+			"backend is down (no ready endpoints), the targeted operations are not being validated or mutated",
+			"timeout of 30s can add up to 30s of latency to each targeted request",
+		}, audits[0].Issues)
+	})
+	s.Run("doesn't flag healthy webhooks", func() {
+		s.Equal("external.example.com", audits[2].Name)
+		s.Equal(WebhookBackendExternal, audits[2].BackendStatus)
+		s.Empty(audits[2].Issues)
+		s.Equal("scoped.policy.io", audits[3].Name)
+		s.Empty(audits[3].Issues)
+	})
+}
+
+func TestWebhooks(t *testing.T) {
+	suite.Run(t, new(WebhooksTestSuite))
+}
@@ -0,0 +1,258 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// WorkloadImageKinds are the kinds of workloads supported by SetImage and ImageHistory
+var WorkloadImageKinds = []string{"Deployment", "StatefulSet", "DaemonSet"}
+
+// changeCauseAnnotation is the annotation recording the cause of a change of a workload (like kubectl --record),
+// copied by the controllers to the ReplicaSets and ControllerRevisions of its revisions
+const changeCauseAnnotation = "kubernetes.io/change-cause"
+
+// WorkloadImageChange is the change of the image of a container of a workload.
+type WorkloadImageChange struct {
+	Container string `json:"container"`
+	Previous  string `json:"previous"`
+	Image     string `json:"image"`
+}
+
+// WorkloadSetImage is the result of SetImage.
+type WorkloadSetImage struct {
+	Kind        string                `json:"kind"`
+	Namespace   string                `json:"namespace"`
+	Name        string                `json:"name"`
+	ChangeCause string                `json:"changeCause"`
+	Changes     []WorkloadImageChange `json:"changes"`
+}
+
+// WorkloadImageRevision is a revision of the rollout history of a workload and the images of its containers.
+type WorkloadImageRevision struct {
+	Revision int64 `json:"revision"`
+	// Source is the ReplicaSet or ControllerRevision of the revision
+	Source      string `json:"source"`
+	Created     string `json:"created,omitempty"`
+	Current     bool   `json:"current,omitempty"`
+	ChangeCause string `json:"changeCause,omitempty"`
+	// Images are the images of the containers (and init containers) of the revision by container name
+	Images map[string]string `json:"images"`
+}
+
+// WorkloadImageHistory is the rollout history of the images of a workload, the oldest revision first.
+type WorkloadImageHistory struct {
+	Kind      string                  `json:"kind"`
+	Namespace string                  `json:"namespace"`
+	Name      string                  `json:"name"`
+	Revisions []WorkloadImageRevision `json:"revisions"`
+}
+
+// SetImage updates the images of the provided containers (by container name) of the Deployment, StatefulSet, or
+// DaemonSet and records the change cause annotation (a description of the change if changeCause is empty), rolling
+// out a new revision without editing the manifest of the workload.
+func (c *Core) SetImage(ctx context.Context, kind, namespace, name string, images map[string]string, changeCause string) (*WorkloadSetImage, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	template, err := c.workloadTemplate(ctx, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	current := templateImages(template.Spec)
+	ret := &WorkloadSetImage{Kind: kind, Namespace: namespace, Name: name, Changes: make([]WorkloadImageChange, 0, len(images))}
+	for _, container := range slices.Sorted(maps.Keys(images)) {
+		previous, ok := current[container]
+		if !ok {
+			return nil, fmt.Errorf("container %s not found in %s %s, its containers are %v", container, kind, name, slices.Sorted(maps.Keys(current)))
+		}
+		if images[container] == "" {
+			return nil, fmt.Errorf("the image of container %s is empty", container)
+		}
+		ret.Changes = append(ret.Changes, WorkloadImageChange{Container: container, Previous: previous, Image: images[container]})
+	}
+	ret.ChangeCause = changeCause
+	if ret.ChangeCause == "" {
+		changes := make([]string, 0, len(ret.Changes))
+		for _, change := range ret.Changes {
+			changes = append(changes, change.Container+"="+change.Image)
+		}
+		ret.ChangeCause = "set image " + strings.Join(changes, " ")
+	}
+	// The containers are merged by name, the init containers are in a list of their own
+	containers := make([]map[string]string, 0)
+	initContainers := make([]map[string]string, 0)
+	for _, change := range ret.Changes {
+		patch := map[string]string{"name": change.Container, "image": change.Image}
+		if slices.ContainsFunc(template.Spec.InitContainers, func(container v1.Container) bool { return container.Name == change.Container }) {
+			initContainers = append(initContainers, patch)
+		} else {
+			containers = append(containers, patch)
+		}
+	}
+	podSpec := map[string]any{}
+	if len(containers) > 0 {
+		podSpec["containers"] = containers
+	}
+	if len(initContainers) > 0 {
+		podSpec["initContainers"] = initContainers
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"annotations": map[string]string{changeCauseAnnotation: ret.ChangeCause}},
+		"spec":     map[string]any{"template": map[string]any{"spec": podSpec}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "Deployment":
+		_, err = c.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = c.AppsV1().StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "DaemonSet":
+		_, err = c.AppsV1().DaemonSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ImageHistory returns the images of the revisions of the rollout history of the Deployment (its ReplicaSets), or of
+// the StatefulSet or DaemonSet (its ControllerRevisions), to find the images to roll back to with SetImage.
+func (c *Core) ImageHistory(ctx context.Context, kind, namespace, name string) (*WorkloadImageHistory, error) {
+	namespace = c.NamespaceOrDefault(namespace)
+	ret := &WorkloadImageHistory{Kind: kind, Namespace: namespace, Name: name, Revisions: make([]WorkloadImageRevision, 0)}
+	var uid types.UID
+	switch kind {
+	case "Deployment":
+		deployment, err := c.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		replicaSets, err := c.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, replicaSet := range replicaSets.Items {
+			if owner := metav1.GetControllerOf(&replicaSet); owner == nil || owner.UID != deployment.UID {
+				continue
+			}
+			revision, err := strconv.ParseInt(replicaSet.Annotations[deploymentRevisionAnnotation], 10, 64)
+			if err != nil {
+				continue
+			}
+			ret.Revisions = append(ret.Revisions, imageRevision(revision, "ReplicaSet/"+replicaSet.Name, replicaSet.ObjectMeta, replicaSet.Spec.Template.Spec))
+		}
+	case "StatefulSet":
+		statefulSet, err := c.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		uid = statefulSet.UID
+	case "DaemonSet":
+		daemonSet, err := c.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		uid = daemonSet.UID
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %s, supported kinds are %v", kind, WorkloadImageKinds)
+	}
+	if kind != "Deployment" {
+		controllerRevisions, err := c.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, controllerRevision := range controllerRevisions.Items {
+			if owner := metav1.GetControllerOf(&controllerRevision); owner == nil || owner.UID != uid {
+				continue
+			}
+			spec, err := controllerRevisionPodSpec(controllerRevision)
+			if err != nil {
+				return nil, err
+			}
+			ret.Revisions = append(ret.Revisions, imageRevision(controllerRevision.Revision, "ControllerRevision/"+controllerRevision.Name, controllerRevision.ObjectMeta, spec))
+		}
+	}
+	slices.SortFunc(ret.Revisions, func(a, b WorkloadImageRevision) int { return cmp.Compare(a.Revision, b.Revision) })
+	// The rollbacks of the Deployments renumber the ReplicaSets, the current revision is always the last one
+	if len(ret.Revisions) > 0 {
+		ret.Revisions[len(ret.Revisions)-1].Current = true
+	}
+	return ret, nil
+}
+
+// workloadTemplate returns the Pod template of the Deployment, StatefulSet, or DaemonSet
+func (c *Core) workloadTemplate(ctx context.Context, kind, namespace, name string) (*v1.PodTemplateSpec, error) {
+	switch kind {
+	case "Deployment":
+		deployment, err := c.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &deployment.Spec.Template, nil
+	case "StatefulSet":
+		statefulSet, err := c.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &statefulSet.Spec.Template, nil
+	case "DaemonSet":
+		daemonSet, err := c.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &daemonSet.Spec.Template, nil
+	}
+	return nil, fmt.Errorf("unsupported workload kind %s, supported kinds are %v", kind, WorkloadImageKinds)
+}
+
+func imageRevision(revision int64, source string, meta metav1.ObjectMeta, spec v1.PodSpec) WorkloadImageRevision {
+	ret := WorkloadImageRevision{
+		Revision:    revision,
+		Source:      source,
+		ChangeCause: meta.Annotations[changeCauseAnnotation],
+		Images:      templateImages(spec),
+	}
+	if !meta.CreationTimestamp.IsZero() {
+		ret.Created = meta.CreationTimestamp.UTC().Format(time.RFC3339)
+	}
+	return ret
+}
+
+// templateImages returns the images of the containers and init containers of the Pod spec by container name
+func templateImages(spec v1.PodSpec) map[string]string {
+	ret := make(map[string]string, len(spec.InitContainers)+len(spec.Containers))
+	for _, container := range slices.Concat(spec.InitContainers, spec.Containers) {
+		ret[container.Name] = container.Image
+	}
+	return ret
+}
+
+// controllerRevisionPodSpec returns the Pod spec of the template stored in the data of the ControllerRevision of a
+// StatefulSet or DaemonSet (a patch replacing the template of the workload)
+func controllerRevisionPodSpec(controllerRevision appsv1.ControllerRevision) (v1.PodSpec, error) {
+	data := struct {
+		Spec struct {
+			Template v1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}{}
+	if len(controllerRevision.Data.Raw) == 0 {
+		return v1.PodSpec{}, nil
+	}
+	if err := json.Unmarshal(controllerRevision.Data.Raw, &data); err != nil {
+		return v1.PodSpec{}, fmt.Errorf("failed to read ControllerRevision %s: %w", controllerRevision.Name, err)
+	}
+	return data.Spec.Template.Spec, nil
+}
@@ -0,0 +1,47 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type WorkloadImagesTestSuite struct {
+	suite.Suite
+}
+
+func (s *WorkloadImagesTestSuite) TestTemplateImages() {
+	s.Equal(map[string]string{"init": "busybox:1.36", "app": "app:1.0", "sidecar": "envoy:1.30"}, templateImages(v1.PodSpec{
+		InitContainers: []v1.Container{{Name: "init", Image: "busybox:1.36"}},
+		Containers:     []v1.Container{{Name: "app", Image: "app:1.0"}, {Name: "sidecar", Image: "envoy:1.30"}},
+	}))
+}
+
+func (s *WorkloadImagesTestSuite) TestControllerRevisionPodSpec() {
+	s.Run("returns the Pod spec of the template patch", func() {
+		spec, err := controllerRevisionPodSpec(appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-5d8c7"},
+			Data: runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"$patch":"replace","metadata":{"labels":{"app":"db"}},` +
+				`"spec":{"containers":[{"name":"db","image":"postgres:16.2"}]}}}}`)},
+		})
+		s.Require().NoError(err)
+		s.Equal(map[string]string{"db": "postgres:16.2"}, templateImages(spec))
+	})
+	s.Run("returns an empty Pod spec without data", func() {
+		spec, err := controllerRevisionPodSpec(appsv1.ControllerRevision{ObjectMeta: metav1.ObjectMeta{Name: "db-5d8c7"}})
+		s.Require().NoError(err)
+		s.Empty(spec.Containers)
+	})
+	s.Run("returns an error for invalid data", func() {
+		_, err := controllerRevisionPodSpec(appsv1.ControllerRevision{ObjectMeta: metav1.ObjectMeta{Name: "db-5d8c7"}, Data: runtime.RawExtension{Raw: []byte(`[`)}})
+		s.ErrorContains(err, "failed to read ControllerRevision db-5d8c7")
+	})
+}
+
+func TestWorkloadImages(t *testing.T) {
+	suite.Run(t, new(WorkloadImagesTestSuite))
+}
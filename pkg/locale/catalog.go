@@ -0,0 +1,81 @@
+package locale
+
+type units struct {
+	names map[string]string
+	// separator between the values and their units
+	separator string
+	// join between the value and unit pairs
+	join string
+}
+
+// durationUnits are the units of the compact durations (see Printer.Duration) of the locales other than English
+var durationUnits = map[string]units{
+	German: {
+		names:     map[string]string{"s": "Sek.", "m": "Min.", "h": "Std.", "d": "Tg.", "y": "J."},
+		separator: " ",
+		join:      " ",
+	},
+	Japanese: {
+		names: map[string]string{"s": "秒", "m": "分", "h": "時間", "d": "日", "y": "年"},
+	},
+}
+
+// catalog maps the (English) formats of the human-readable summaries to their translations in the other locales
+var catalog = map[string]map[string]string{
+	German: {
+		// diagnose_pod
+		"# %d issue(s) found for Pod %s/%s (%s format):\n":                                                       "# %d Problem(e) für Pod %s/%s gefunden (%s-Format):\n",
+		"# No issues found for Pod %s/%s (%s format):\n":                                                         "# Keine Probleme für Pod %s/%s gefunden (%s-Format):\n",
+		"failed to pull image %s: %s":                                                                            "Image %s konnte nicht geladen werden: %s",
+		"container is crash looping (%d restarts)":                                                               "Container stürzt wiederholt ab (%d Neustarts)",
+		"container is crash looping (%d restarts), last terminated with exit code %d":                            "Container stürzt wiederholt ab (%d Neustarts), zuletzt mit Exit-Code %d beendet",
+		"container terminated with exit code %d":                                                                 "Container mit Exit-Code %d beendet",
+		"container was killed because it exceeded its memory limit, consider increasing resources.limits.memory": "Container wurde wegen Überschreitung seines Speicherlimits beendet, resources.limits.memory erhöhen",
+		"container has restarted %d times":                                                                       "Container wurde %d-mal neu gestartet",
+		"container is running but not ready, check the readiness probe":                                          "Container läuft, ist aber nicht bereit, Readiness-Probe prüfen",
+		// diagnose_termination
+		"# %d object(s) stuck in Terminating (%s format):\n": "# %d Objekt(e) hängen in Terminating fest (%s-Format):\n",
+		"# No objects stuck in Terminating found":            "# Keine in Terminating festhängenden Objekte gefunden",
+		// nodes_health
+		"# %d node(s), %d with issues (%s format):\n": "# %d Node(s), %d mit Problemen (%s-Format):\n",
+		"# No nodes found":                                                     "# Keine Nodes gefunden",
+		"node is not ready for %s: %s":                                         "Node ist seit %s nicht bereit: %s",
+		"an unknown time":                                                      "unbekannter Zeit",
+		"node has condition %s: %s":                                            "Node hat die Condition %s: %s",
+		"node is cordoned (unschedulable)":                                     "Node ist gesperrt (unschedulable)",
+		"%s requests are at %d%% of the allocatable %s":                        "%s-Requests liegen bei %d%% der zuweisbaren %s",
+		"kubelet version %s differs from the most common version %s":           "Kubelet-Version %s weicht von der häufigsten Version %s ab",
+		"container runtime version %s differs from the most common version %s": "Container-Runtime-Version %s weicht von der häufigsten Version %s ab",
+		// events_list
+		"# The following events (%s format) were found:\n": "# Die folgenden Events (%s-Format) wurden gefunden:\n",
+		"# No events found": "# Keine Events gefunden",
+	},
+	Japanese: {
+		// diagnose_pod
+		"# %d issue(s) found for Pod %s/%s (%s format):\n":                                                       "# Pod %[2]s/%[3]s で %[1]d 件の問題が見つかりました (%[4]s形式):\n",
+		"# No issues found for Pod %s/%s (%s format):\n":                                                         "# Pod %s/%s で問題は見つかりませんでした (%s形式):\n",
+		"failed to pull image %s: %s":                                                                            "イメージ %s のプルに失敗しました: %s",
+		"container is crash looping (%d restarts)":                                                               "コンテナがクラッシュループしています (再起動 %d 回)",
+		"container is crash looping (%d restarts), last terminated with exit code %d":                            "コンテナがクラッシュループしています (再起動 %d 回)、前回は終了コード %d で終了しました",
+		"container terminated with exit code %d":                                                                 "コンテナは終了コード %d で終了しました",
+		"container was killed because it exceeded its memory limit, consider increasing resources.limits.memory": "コンテナはメモリ制限を超えたため強制終了されました、resources.limits.memory の引き上げを検討してください",
+		"container has restarted %d times":                                                                       "コンテナは %d 回再起動しています",
+		"container is running but not ready, check the readiness probe":                                          "コンテナは実行中ですが準備ができていません、readiness probe を確認してください",
+		// diagnose_termination
+		"# %d object(s) stuck in Terminating (%s format):\n": "# %d 件のオブジェクトが Terminating のまま停止しています (%s形式):\n",
+		"# No objects stuck in Terminating found":            "# Terminating のまま停止しているオブジェクトは見つかりませんでした",
+		// nodes_health
+		"# %d node(s), %d with issues (%s format):\n": "# %d 台のノード、うち %d 台に問題があります (%s形式):\n",
+		"# No nodes found":                                                     "# ノードが見つかりませんでした",
+		"node is not ready for %s: %s":                                         "ノードは %s 前から準備ができていません: %s",
+		"an unknown time":                                                      "不明な時間",
+		"node has condition %s: %s":                                            "ノードにコンディション %s があります: %s",
+		"node is cordoned (unschedulable)":                                     "ノードは cordon されています (unschedulable)",
+		"%s requests are at %d%% of the allocatable %s":                        "%[1]s のリクエストは割り当て可能な %[3]s の %[2]d%% です",
+		"kubelet version %s differs from the most common version %s":           "kubelet バージョン %s は最も一般的なバージョン %s と異なります",
+		"container runtime version %s differs from the most common version %s": "コンテナランタイムのバージョン %s は最も一般的なバージョン %s と異なります",
+		// events_list
+		"# The following events (%s format) were found:\n": "# 以下のイベント (%s形式) が見つかりました:\n",
+		"# No events found": "# イベントは見つかりませんでした",
+	},
+}
@@ -0,0 +1,105 @@
+// Package locale renders the human-readable summaries of the tool results (diagnosis findings, result headers, and
+// durations) in the language the operators interact with the agent in. The structured fields (names, reasons, states)
+// and the messages reported by Kubernetes are left untouched.
+package locale
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	English  = "en"
+	German   = "de"
+	Japanese = "ja"
+)
+
+// Names are the supported locales
+var Names = []string{English, German, Japanese}
+
+// IsSupported returns whether the locale is supported, the empty locale stands for the default one (English)
+func IsSupported(locale string) bool {
+	return locale == "" || slices.Contains(Names, locale)
+}
+
+// Printer renders the human-readable summaries in a locale.
+type Printer struct {
+	locale string
+}
+
+// NewPrinter returns the Printer of the locale, English if the locale is not supported
+func NewPrinter(locale string) Printer {
+	if !slices.Contains(Names, locale) {
+		locale = English
+	}
+	return Printer{locale: locale}
+}
+
+// Locale returns the locale of the Printer
+func (p Printer) Locale() string {
+	if p.locale == "" {
+		return English
+	}
+	return p.locale
+}
+
+// Sprintf formats the translation of the (English) format in the locale of the Printer, the format itself if it has no
+// translation. The translations may reorder the arguments with explicit argument indexes (e.g. %[2]s).
+func (p Printer) Sprintf(format string, args ...any) string {
+	if translation, ok := catalog[p.locale][format]; ok {
+		format = translation
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// durationUnit matches the value and unit pairs of the compact durations used by kubectl (e.g. 3d4h, 45s)
+var durationUnit = regexp.MustCompile(`(\d+)([smhdy])`)
+
+// Duration returns the duration in the compact form used by kubectl (e.g. 45s, 5m, 3d4h) with the units of the
+// locale of the Printer (e.g. 3 Tg. 4 Std., 3日4時間)
+func (p Printer) Duration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	ret := duration.HumanDuration(d)
+	units, ok := durationUnits[p.locale]
+	if !ok {
+		return sign + ret
+	}
+	var parts []string
+	for _, match := range durationUnit.FindAllStringSubmatch(ret, -1) {
+		parts = append(parts, match[1]+units.separator+units.names[match[2]])
+	}
+	if len(parts) == 0 {
+		return sign + ret
+	}
+	return sign + strings.Join(parts, units.join)
+}
+
+// Age returns the time elapsed since t (see Duration), an empty string for zero times
+func (p Printer) Age(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return p.Duration(time.Since(t))
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying the locale of the tool call
+func NewContext(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the Printer of the locale carried by the context, English if none
+func FromContext(ctx context.Context) Printer {
+	locale, _ := ctx.Value(contextKey{}).(string)
+	return NewPrinter(locale)
+}
@@ -0,0 +1,89 @@
+package locale
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSprintf(t *testing.T) {
+	t.Run("translates the format", func(t *testing.T) {
+		assert.Equal(t, "Container wurde 7-mal neu gestartet", NewPrinter(German).Sprintf("container has restarted %d times", 7))
+	})
+	t.Run("reorders the arguments", func(t *testing.T) {
+		assert.Equal(t, "# Pod shop/web で 2 件の問題が見つかりました (JSON形式):\n",
+			NewPrinter(Japanese).Sprintf("# %d issue(s) found for Pod %s/%s (%s format):\n", 2, "shop", "web", "JSON"))
+	})
+	t.Run("falls back to the format without translation", func(t *testing.T) {
+		assert.Equal(t, "unknown 1", NewPrinter(German).Sprintf("unknown %d", 1))
+	})
+	t.Run("unsupported locale renders English", func(t *testing.T) {
+		assert.Equal(t, "container has restarted 7 times", NewPrinter("fr").Sprintf("container has restarted %d times", 7))
+		assert.Equal(t, English, NewPrinter("fr").Locale())
+	})
+}
+
+// TestCatalog checks that the translations use all the arguments of their formats
+func TestCatalog(t *testing.T) {
+	verb := regexp.MustCompile(`%[ds]`)
+	for locale, translations := range catalog {
+		for format := range translations {
+			var args []any
+			for _, v := range verb.FindAllString(format, -1) {
+				if v == "%d" {
+					args = append(args, len(args)+1)
+				} else {
+					args = append(args, "arg"+string(rune('a'+len(args))))
+				}
+			}
+			ret := NewPrinter(locale).Sprintf(format, args...)
+			assert.NotContainsf(t, ret, "%!", "%s translation of %q is invalid: %s", locale, format, ret)
+			for _, arg := range args {
+				if s, ok := arg.(string); ok {
+					assert.Truef(t, strings.Contains(ret, s), "%s translation of %q misses argument %s: %s", locale, format, s, ret)
+				}
+			}
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	for _, tc := range []struct {
+		locale   string
+		duration time.Duration
+		expected string
+	}{
+		{English, 50 * time.Hour, "2d2h"},
+		{German, 50 * time.Hour, "2 Tg. 2 Std."},
+		{German, 45 * time.Second, "45 Sek."},
+		{Japanese, 50 * time.Hour, "2日2時間"},
+		{Japanese, 5 * time.Minute, "5分"},
+		{Japanese, -5 * time.Minute, "-5分"},
+	} {
+		t.Run(tc.locale+" "+tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewPrinter(tc.locale).Duration(tc.duration))
+		})
+	}
+	t.Run("zero time age", func(t *testing.T) {
+		assert.Empty(t, NewPrinter(German).Age(time.Time{}))
+	})
+}
+
+func TestContext(t *testing.T) {
+	t.Run("carries the locale", func(t *testing.T) {
+		assert.Equal(t, Japanese, FromContext(NewContext(context.Background(), Japanese)).Locale())
+	})
+	t.Run("defaults to English", func(t *testing.T) {
+		assert.Equal(t, English, FromContext(context.Background()).Locale())
+	})
+}
+
+func TestIsSupported(t *testing.T) {
+	assert.True(t, IsSupported(""))
+	assert.True(t, IsSupported(German))
+	assert.False(t, IsSupported("fr"))
+}
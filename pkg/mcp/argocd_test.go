@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type ArgoCDSuite struct {
+	BaseMcpSuite
+	mockServer  *test.MockServer
+	application *unstructured.Unstructured
+	patches     []map[string]any
+}
+
+func (s *ArgoCDSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.patches = nil
+	s.application = &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata":   map[string]any{"name": "web", "namespace": "argocd"},
+		"spec": map[string]any{
+			"project":     "default",
+			"source":      map[string]any{"repoURL": "https://github.com/example/gitops", "path": "apps/web", "targetRevision": "main"},
+			"destination": map[string]any{"server": "https://kubernetes.default.svc", "namespace": "web"},
+		},
+		"status": map[string]any{
+			"sync":   map[string]any{"status": "OutOfSync", "revision": "abc123"},
+			"health": map[string]any{"status": "Healthy"},
+			"resources": []any{
+				map[string]any{"group": "apps", "kind": "Deployment", "namespace": "web", "name": "web", "status": "OutOfSync"},
+				map[string]any{"kind": "Service", "namespace": "web", "name": "web", "status": "Synced"},
+			},
+		},
+	}}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "argoproj.io/v1alpha1",
+		APIResources: []metav1.APIResource{{Name: "applications", Kind: "Application", Namespaced: true, Verbs: metav1.Verbs{"get", "list", "patch"}}},
+	}))
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/argoproj.io/v1alpha1/applications", "/apis/argoproj.io/v1alpha1/namespaces/argocd/applications":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "argoproj.io/v1alpha1", "kind": "ApplicationList"},
+				Items:  []unstructured.Unstructured{*s.application},
+			})
+		case "/apis/argoproj.io/v1alpha1/namespaces/argocd/applications/web":
+			if req.Method == http.MethodPatch {
+				body, _ := io.ReadAll(req.Body)
+				patch := map[string]any{}
+				_ = json.Unmarshal(body, &patch)
+				s.patches = append(s.patches, patch)
+				s.application.Object["operation"] = patch["operation"]
+			}
+			test.WriteObject(w, s.application)
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"argocd"}
+}
+
+func (s *ArgoCDSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ArgoCDSuite) TestApplicationsList() {
+	s.InitMcpClient()
+	s.Run("argocd_applications_list", func() {
+		toolResult, err := s.CallTool("argocd_applications_list", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of applications", func() {
+			s.Truef(strings.HasPrefix(text, "# 1 application(s) found, 1 not synced, 0 not healthy (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the sync status", func() {
+			s.Contains(text, "syncStatus: OutOfSync")
+			s.Contains(text, "source: https://github.com/example/gitops/apps/web@main")
+		})
+	})
+}
+
+func (s *ArgoCDSuite) TestApplicationDiff() {
+	s.InitMcpClient()
+	s.Run("argocd_application_diff(name=web)", func() {
+		toolResult, err := s.CallTool("argocd_application_diff", map[string]interface{}{"name": "web"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of out of sync resources", func() {
+			s.Truef(strings.HasPrefix(text, "# Application web is OutOfSync and Healthy, 1 resource(s) out of sync, 0 unhealthy (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the out of sync resources", func() {
+			s.Contains(text, "resource: web/apps/Deployment/web")
+			s.Contains(text, "synced: 1")
+		})
+	})
+}
+
+func (s *ArgoCDSuite) TestApplicationSync() {
+	s.InitMcpClient()
+	s.Run("argocd_application_sync(name=web, prune=true)", func() {
+		toolResult, err := s.CallTool("argocd_application_sync", map[string]interface{}{"name": "web", "prune": true})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("patches the sync operation", func() {
+			s.Require().Len(s.patches, 1)
+			s.Equal(map[string]any{
+				"initiatedBy": map[string]any{"username": "kubernetes-mcp-server"},
+				"sync":        map[string]any{"prune": true},
+			}, s.patches[0]["operation"])
+		})
+		s.Run("returns the requested operation", func() {
+			s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# Sync of application web requested"), "unexpected result %v", toolResult.Content[0])
+		})
+	})
+	s.Run("argocd_application_sync(name=web) with operation in progress", func() {
+		toolResult, err := s.CallTool("argocd_application_sync", map[string]interface{}{"name": "web"})
+		s.Run("has error", func() {
+			s.Nilf(err, "call tool should not return error object")
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "another operation is already in progress")
+		})
+	})
+}
+
+func TestArgoCD(t *testing.T) {
+	suite.Run(t, new(ArgoCDSuite))
+}
@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+const auditLogEvents = `{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","auditID":"a1","stage":"ResponseComplete","verb":"get","user":{"username":"alice@example.com"},"objectRef":{"resource":"deployments","namespace":"shop","name":"web","apiGroup":"apps","apiVersion":"v1"},"responseStatus":{"code":200},"requestReceivedTimestamp":"2026-10-15T09:00:00.000000Z","stageTimestamp":"2026-10-15T09:00:00.010000Z"}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","auditID":"a2","stage":"ResponseComplete","verb":"delete","user":{"username":"alice@example.com"},"sourceIPs":["10.0.0.7"],"userAgent":"kubectl/v1.34.0","objectRef":{"resource":"deployments","namespace":"shop","name":"web","apiGroup":"apps","apiVersion":"v1"},"responseStatus":{"code":200},"requestReceivedTimestamp":"2026-10-15T10:00:00.000000Z","stageTimestamp":"2026-10-15T10:00:00.020000Z"}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","auditID":"a3","stage":"ResponseComplete","verb":"patch","user":{"username":"bob"},"objectRef":{"resource":"configmaps","namespace":"shop","name":"web-config","apiVersion":"v1"},"responseStatus":{"code":200},"requestReceivedTimestamp":"2026-10-15T11:00:00.000000Z","stageTimestamp":"2026-10-15T11:00:00.020000Z"}
+`
+
+type AuditLogSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *AuditLogSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	file := filepath.Join(s.T().TempDir(), "audit.log")
+	s.Require().NoError(os.WriteFile(file, []byte(auditLogEvents), 0600))
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	s.Cfg = test.Must(config.ReadToml([]byte(`
+		[toolset_configs.core.audit_log]
+		file = ` + strconv.Quote(file) + `
+	`)))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *AuditLogSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *AuditLogSuite) TestAuditLogQuery() {
+	s.InitMcpClient()
+	s.Run("audit_log_query returns the write requests by default", func() {
+		toolResult, err := s.CallTool("audit_log_query", map[string]any{"from": "2026-10-15T00:00:00Z", "to": "2026-10-16T00:00:00Z"})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("# 2 audit event(s) between 2026-10-15T00:00:00Z and 2026-10-16T00:00:00Z, oldest first (YAML format):\n"+
+			"- auditID: a2\n"+
+			"  code: 200\n"+
+			"  object: deployments.apps shop/web\n"+
+			"  sourceIPs:\n"+
+			"  - 10.0.0.7\n"+
+			"  time: \"2026-10-15T10:00:00Z\"\n"+
+			"  user: alice@example.com\n"+
+			"  userAgent: kubectl/v1.34.0\n"+
+			"  verb: delete\n"+
+			"- auditID: a3\n"+
+			"  code: 200\n"+
+			"  object: configmaps shop/web-config\n"+
+			"  time: \"2026-10-15T11:00:00Z\"\n"+
+			"  user: bob\n"+
+			"  verb: patch\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("audit_log_query filters by verbs, resource, and name", func() {
+		toolResult, err := s.CallTool("audit_log_query", map[string]any{
+			"verbs": []any{"get", "delete"}, "resource": "deployments", "name": "web", "from": "2026-10-15T00:00:00Z", "to": "2026-10-16T00:00:00Z",
+		})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# 2 audit event(s) between")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "verb: get")
+		s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "web-config")
+	})
+	s.Run("audit_log_query keeps the most recent events up to limit", func() {
+		toolResult, err := s.CallTool("audit_log_query", map[string]any{"limit": 1, "from": "2026-10-15T00:00:00Z", "to": "2026-10-16T00:00:00Z"})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# The 1 most recent audit events between")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "auditID: a3")
+	})
+	s.Run("audit_log_query with no matching events", func() {
+		toolResult, err := s.CallTool("audit_log_query", map[string]any{"user": "carol", "from": "2026-10-15T00:00:00Z", "to": "2026-10-16T00:00:00Z"})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("# No audit events found between 2026-10-15T00:00:00Z and 2026-10-16T00:00:00Z", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("audit_log_query with from after to", func() {
+		toolResult, err := s.CallTool("audit_log_query", map[string]any{"from": "2026-10-16T00:00:00Z", "to": "2026-10-15T00:00:00Z"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("invalid time range, from 2026-10-16T00:00:00Z is not before to 2026-10-15T00:00:00Z", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *AuditLogSuite) TestAuditLogQueryNotConfigured() {
+	s.Cfg = test.Must(config.ReadToml([]byte(``)))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("audit_log_query", map[string]any{})
+	s.Require().NoError(err)
+	s.True(toolResult.IsError)
+	s.Equal("failed to query audit log: the audit log is not configured, set [toolset_configs.core.audit_log] in the server configuration", toolResult.Content[0].(mcp.TextContent).Text)
+}
+
+func TestAuditLog(t *testing.T) {
+	suite.Run(t, new(AuditLogSuite))
+}
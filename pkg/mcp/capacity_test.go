@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type CapacitySuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *CapacitySuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests := func(cpu, memory string) v1.ResourceRequirements {
+			return v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu), v1.ResourceMemory: resource.MustParse(memory)}}
+		}
+		switch req.URL.Path {
+		case "/api/v1/nodes":
+			test.WriteObject(w, &v1.NodeList{Items: []v1.Node{{
+				ObjectMeta: metav1.ObjectMeta{Name: "a-node"},
+				Status:     v1.NodeStatus{Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("8Gi")}},
+			}}})
+		case "/api/v1/pods":
+			test.WriteObject(w, &v1.PodList{Items: []v1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "api", Labels: map[string]string{"team": "checkout"}},
+					Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "api", Resources: requests("1", "2Gi")}}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "search", Name: "indexer", Labels: map[string]string{"team": "discovery"}},
+					Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "indexer", Resources: requests("2", "2Gi")}}},
+				},
+			}})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *CapacitySuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *CapacitySuite) TestCapacityUsage() {
+	s.InitMcpClient()
+	s.Run("capacity_usage()", func() {
+		toolResult, err := s.CallTool("capacity_usage", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment without costs", func() {
+			s.Truef(strings.HasPrefix(text, "# 2 running pod(s) in 2 group(s) by namespace, no prices configured, costs not estimated (YAML format):\n"), "unexpected result %v", text)
+		})
+		var report map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &report))
+		s.Run("reports the percentage of the allocatable resources requested", func() {
+			s.Equal(map[string]any{"cpu": float64(75), "memory": float64(50)}, report["requestedPercent"])
+		})
+		s.Run("reports the groups by namespace", func() {
+			groups := report["groups"].([]any)
+			s.Require().Len(groups, 2)
+			s.Equal("search", groups[0].(map[string]any)["name"])
+			s.Equal(map[string]any{"cpu": float64(66), "memory": float64(50)}, groups[0].(map[string]any)["sharePercent"])
+			s.Equal("payments", groups[1].(map[string]any)["name"])
+		})
+	})
+}
+
+func (s *CapacitySuite) TestCapacityUsageWithPrices() {
+	kubeConfig := s.Cfg.KubeConfig
+	s.Cfg = test.Must(config.ReadToml([]byte(`
+		[toolset_configs.core.prices]
+		cpu = 0.05
+		memory = 0.01
+	`)))
+	s.Cfg.KubeConfig = kubeConfig
+	s.InitMcpClient()
+	s.Run("capacity_usage(group_by_label=team)", func() {
+		toolResult, err := s.CallTool("capacity_usage", map[string]interface{}{"group_by_label": "team"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the estimated cost", func() {
+			s.Truef(strings.HasPrefix(text, "# 2 running pod(s) in 2 group(s) by label team, estimated cost per hour 0.19 (YAML format):\n"), "unexpected result %v", text)
+		})
+		var report map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &report))
+		s.Run("reports the estimated cost of each team", func() {
+			groups := report["groups"].([]any)
+			s.Require().Len(groups, 2)
+			s.Equal("discovery", groups[0].(map[string]any)["name"])
+			s.Equal("0.12", groups[0].(map[string]any)["estimatedCostPerHour"])
+			s.Equal(63.2, groups[0].(map[string]any)["costSharePercent"])
+			s.Equal("checkout", groups[1].(map[string]any)["name"])
+			s.Equal("0.07", groups[1].(map[string]any)["estimatedCostPerHour"])
+		})
+	})
+}
+
+func TestCapacity(t *testing.T) {
+	suite.Run(t, new(CapacitySuite))
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+type CertificatesSuite struct {
+	BaseMcpSuite
+}
+
+func (s *CertificatesSuite) TestCertificatesAudit() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "expiring.example.com"},
+		DNSNames:     []string{"expiring.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(5 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	s.Require().NoError(err)
+	client := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	_, err = client.CoreV1().Secrets("ns-1").Create(s.T().Context(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "an-expiring-certificate"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: []byte("not-a-real-key"),
+		},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = client.CoreV1().Secrets("ns-1").Delete(s.T().Context(), "an-expiring-certificate", metav1.DeleteOptions{})
+	})
+	s.InitMcpClient()
+	s.Run("certificates_audit(namespace=ns-1)", func() {
+		toolResult, err := s.CallTool("certificates_audit", map[string]interface{}{"namespace": "ns-1"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		var audits []map[string]any
+		err = yaml.Unmarshal([]byte(text), &audits)
+		s.Run("has yaml content", func() {
+			s.Nilf(err, "invalid tool result content %v", err)
+			s.Require().Len(audits, 1)
+		})
+		s.Run("flags the certificate as expiring", func() {
+			s.Equal("an-expiring-certificate", audits[0]["name"])
+			s.Equal("expiring", audits[0]["status"])
+			s.Equal("CN=expiring.example.com", audits[0]["subject"])
+		})
+		s.Run("doesn't return the private key", func() {
+			s.NotContains(text, "not-a-real-key")
+		})
+	})
+	s.Run("certificates_audit(namespace=ns-1, expiry_days=1)", func() {
+		toolResult, err := s.CallTool("certificates_audit", map[string]interface{}{"namespace": "ns-1", "expiry_days": 1})
+		s.Nilf(err, "call tool failed %v", err)
+		var audits []map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &audits))
+		s.Require().Len(audits, 1)
+		s.Equal("valid", audits[0]["status"])
+	})
+	s.Run("certificates_audit(expiry_days=-1)", func() {
+		toolResult, _ := s.CallTool("certificates_audit", map[string]interface{}{"expiry_days": -1})
+		s.Truef(toolResult.IsError, "call tool should fail")
+	})
+}
+
+func TestCertificates(t *testing.T) {
+	suite.Run(t, new(CertificatesSuite))
+}
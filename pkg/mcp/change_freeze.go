@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/hooks"
+)
+
+// DryRunParameterName is the name of the parameter of the mutating tools returning what would change without changing it
+const DryRunParameterName = "dry_run"
+
+// checkChangeFreeze rejects the calls of the mutating tools against a target with an active change freeze, except for
+// the break-glass identities of the freeze, whose calls are audited (logged and notified to the event hooks). The identity
+// is the subject of the OAuth token, only trusted with require_oauth.
+func (s *Server) checkChangeFreeze(ctx context.Context, tool api.ServerTool, arguments map[string]any, target, session string) error {
+	if !isMutatingCall(tool, arguments) {
+		return nil
+	}
+	now := time.Now()
+	for _, freeze := range s.configuration.ChangeFreezes {
+		until, active := freeze.ActiveUntil(now, target)
+		if !active {
+			continue
+		}
+		if subject := tokenSubject(ctx); s.configuration.RequireOAuth && subject != "" && slices.Contains(freeze.BreakGlass, subject) {
+			klog.Warningf("Change freeze %s broken by %s: %s called against %s", freeze.Name, subject, tool.Tool.Name, target)
+			s.fireEvent(hooks.Event{Event: config.EventChangeFreezeBreakGlass, SessionID: session, Tool: tool.Tool.Name, Target: target})
+			continue
+		}
+		return api.NewToolError(api.ErrorCodeChangeFreeze,
+			fmt.Errorf("change freeze %s active until %s, %s is rejected", freeze.Name, until.Format(time.RFC3339), tool.Tool.Name))
+	}
+	return nil
+}
+
+// isMutatingCall returns true if the tool call may change the cluster: the tools not running against a cluster
+// (e.g. workspace_write) don't change the clusters, nor the dry runs (dry_run=true) of the tools supporting them
+func isMutatingCall(tool api.ServerTool, arguments map[string]any) bool {
+	if ptr.Deref(tool.Tool.Annotations.ReadOnlyHint, false) || !tool.IsClusterAware() {
+		return false
+	}
+	if dryRun, _ := arguments[DryRunParameterName].(bool); dryRun && tool.Tool.InputSchema != nil {
+		return tool.Tool.InputSchema.Properties[DryRunParameterName] == nil
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/hooks"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChangeFreezeSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	originalToolsets []api.Toolset
+}
+
+func (s *ChangeFreezeSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.originalToolsets = toolsets.Toolsets()
+	toolsets.Clear()
+	// the schedules test toolset provides a read-only tool (scan) and a mutating tool (fix)
+	toolsets.Register(schedulesTestToolset())
+	s.Cfg.Toolsets = []string{"schedules-test"}
+	now := time.Now().UTC()
+	s.Cfg.ChangeFreezes = []config.ChangeFreezeConfig{{
+		Name:  "incident",
+		Start: now.Add(-time.Hour).Format(time.RFC3339),
+		End:   now.Add(time.Hour).Truncate(time.Second).Format(time.RFC3339),
+	}}
+}
+
+func (s *ChangeFreezeSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	toolsets.Clear()
+	for _, toolset := range s.originalToolsets {
+		toolsets.Register(toolset)
+	}
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ChangeFreezeSuite) TestActiveFreeze() {
+	s.InitMcpClient()
+	s.Run("rejects the mutating tools", func() {
+		toolResult, err := s.CallTool("fix", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("change freeze incident active until "+s.Cfg.ChangeFreezes[0].End+", fix is rejected",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("reports the change freeze error code", func() {
+		toolResult, err := s.CallTool("fix", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		structuredError, ok := toolResult.StructuredContent.(map[string]any)["error"].(map[string]any)
+		s.Require().Truef(ok, "expected structured error, got %v", toolResult.StructuredContent)
+		s.Equal("CHANGE_FREEZE", structuredError["code"])
+	})
+	s.Run("allows the dry runs of the mutating tools", func() {
+		toolResult, err := s.CallTool("fix", map[string]any{"dry_run": true})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	})
+	s.Run("rejects the mutating tools called with dry_run=false", func() {
+		toolResult, err := s.CallTool("fix", map[string]any{"dry_run": false})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+	})
+	s.Run("allows the read-only tools", func() {
+		toolResult, err := s.CallTool("scan", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.False(toolResult.IsError, "call tool should succeed")
+	})
+}
+
+func (s *ChangeFreezeSuite) TestBreakGlass() {
+	s.Cfg.RequireOAuth = true
+	s.Cfg.ChangeFreezes[0].BreakGlass = []string{"oncall@example.com"}
+	events := make(chan hooks.Event, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := hooks.Event{}
+		_ = json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer hook.Close()
+	s.Cfg.EventHooks = []config.EventHookConfig{{URL: hook.URL, Events: []string{config.EventChangeFreezeBreakGlass}}}
+	s.Run("allows the mutating tools of the break-glass identities", func() {
+		s.InitMcpClient(test.WithTransport(transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + subjectToken("oncall@example.com")})))
+		toolResult, err := s.CallTool("fix", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Run("notifies the break-glass event hooks", func() {
+			select {
+			case event := <-events:
+				s.Equal(config.EventChangeFreezeBreakGlass, event.Event)
+				s.Equal("fix", event.Tool)
+			case <-time.After(5 * time.Second):
+				s.Fail("expected the break-glass event")
+			}
+		})
+	})
+	s.Run("rejects the mutating tools of the other identities", func() {
+		s.InitMcpClient(test.WithTransport(transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + subjectToken("intern@example.com")})))
+		toolResult, err := s.CallTool("fix", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+	})
+	s.Run("rejects the break-glass identities without require_oauth", func() {
+		s.Cfg.RequireOAuth = false
+		s.InitMcpClient(test.WithTransport(transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + subjectToken("oncall@example.com")})))
+		toolResult, err := s.CallTool("fix", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "the unverified subjects must not break the freeze")
+	})
+}
+
+// subjectToken returns an (unsigned) JWT with the subject, its signature is verified by the HTTP layer with require_oauth
+func subjectToken(subject string) string {
+	encode := func(v any) string {
+		b, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	return encode(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encode(map[string]string{"sub": subject}) + ".c2lnbmF0dXJl"
+}
+
+func (s *ChangeFreezeSuite) TestOtherTargets() {
+	s.Cfg.ChangeFreezes[0].Targets = []string{"production"}
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("fix", map[string]any{})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.False(toolResult.IsError, "call tool should succeed against the targets without freeze")
+	s.Equal("fixed: true", toolResult.Content[0].(mcp.TextContent).Text)
+}
+
+func (s *ChangeFreezeSuite) TestEndedFreeze() {
+	s.Cfg.ChangeFreezes[0].End = s.Cfg.ChangeFreezes[0].Start
+	s.Cfg.ChangeFreezes[0].Start = time.Now().UTC().Add(-2 * time.Hour).Format(time.RFC3339)
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("fix", map[string]any{})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.False(toolResult.IsError, "call tool should succeed once the freeze ended")
+}
+
+func (s *ChangeFreezeSuite) TestReloadInvalidChangeFreeze() {
+	s.InitMcpClient()
+	newConfig := *s.Cfg
+	newConfig.ChangeFreezes = []config.ChangeFreezeConfig{{Name: "weekend", Cron: "0 18 * * 5"}}
+	err := s.mcpServer.ReloadConfiguration(&newConfig)
+	s.EqualError(err, "change freeze weekend: duration must be a positive duration (e.g. 60h): ")
+}
+
+func TestChangeFreeze(t *testing.T) {
+	suite.Run(t, new(ChangeFreezeSuite))
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type ClusterAPISuite struct {
+	BaseMcpSuite
+	mockServer        *test.MockServer
+	machineDeployment unstructured.Unstructured
+	patch             string
+}
+
+func (s *ClusterAPISuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.patch = ""
+	s.machineDeployment = unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cluster.x-k8s.io/v1beta1",
+		"kind":       "MachineDeployment",
+		"metadata":   map[string]any{"name": "prod-eu-workers", "namespace": "fleet"},
+		"spec":       map[string]any{"clusterName": "prod-eu", "replicas": int64(3)},
+		"status": map[string]any{
+			"phase":         "Running",
+			"readyReplicas": int64(3),
+			"conditions":    []any{map[string]any{"type": "Ready", "status": "True"}},
+		},
+	}}
+	discovery := test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "cluster.x-k8s.io/v1beta1",
+		APIResources: []metav1.APIResource{
+			{Name: "clusters", Kind: "Cluster", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+			{Name: "machinedeployments", Kind: "MachineDeployment", Namespaced: true, Verbs: metav1.Verbs{"get", "list", "patch"}},
+		},
+	})
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(discovery)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/cluster.x-k8s.io/v1beta1/clusters":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "cluster.x-k8s.io/v1beta1", "kind": "ClusterList"},
+				Items: []unstructured.Unstructured{{Object: map[string]any{
+					"apiVersion": "cluster.x-k8s.io/v1beta1",
+					"kind":       "Cluster",
+					"metadata":   map[string]any{"name": "prod-eu", "namespace": "fleet"},
+					"status": map[string]any{
+						"phase":               "Provisioning",
+						"infrastructureReady": true,
+						"controlPlaneReady":   false,
+						"conditions": []any{map[string]any{
+							"type": "Ready", "status": "False", "severity": "Info", "reason": "WaitingForControlPlane", "message": "Waiting for control plane provider to indicate the control plane has been initialized",
+						}},
+					},
+				}}},
+			})
+		case "/apis/cluster.x-k8s.io/v1beta1/machinedeployments":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "cluster.x-k8s.io/v1beta1", "kind": "MachineDeploymentList"},
+				Items:  []unstructured.Unstructured{s.machineDeployment},
+			})
+		case "/apis/cluster.x-k8s.io/v1beta1/namespaces/fleet/machinedeployments/prod-eu-workers":
+			if req.Method == http.MethodPatch {
+				body, _ := io.ReadAll(req.Body)
+				s.patch = string(body)
+				s.machineDeployment.Object["spec"].(map[string]any)["replicas"] = int64(5)
+			}
+			test.WriteObject(w, &s.machineDeployment)
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"clusterapi"}
+}
+
+func (s *ClusterAPISuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ClusterAPISuite) TestResourcesList() {
+	s.InitMcpClient()
+	s.Run("clusterapi_resources_list skips the kinds not installed", func() {
+		toolResult, err := s.CallTool("clusterapi_resources_list", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of resources", func() {
+			s.Truef(strings.HasPrefix(text, "# 2 Cluster API resource(s) found, 1 not ready (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the provisioning status and the conditions", func() {
+			s.Contains(text, "controlPlaneReady: false")
+			s.Contains(text, "Ready (Info): WaitingForControlPlane:")
+			s.Contains(text, "readyReplicas: 3")
+		})
+	})
+	s.Run("clusterapi_resources_list(kind=Machine) fails when not installed", func() {
+		toolResult, err := s.CallTool("clusterapi_resources_list", map[string]interface{}{"kind": "Machine"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to list Cluster API resources:")
+	})
+}
+
+func (s *ClusterAPISuite) TestMachineDeploymentScale() {
+	s.InitMcpClient()
+	s.Run("clusterapi_machinedeployment_scale(replicas=5)", func() {
+		toolResult, err := s.CallTool("clusterapi_machinedeployment_scale", map[string]interface{}{"namespace": "fleet", "name": "prod-eu-workers", "replicas": 5})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		s.Run("patches the replicas", func() {
+			s.Equal(`{"spec":{"replicas":5}}`, s.patch)
+		})
+		s.Run("returns the MachineDeployment", func() {
+			text := toolResult.Content[0].(mcp.TextContent).Text
+			s.Truef(strings.HasPrefix(text, "# MachineDeployment fleet/prod-eu-workers scaled to 5 replica(s)"), "unexpected result %v", text)
+			s.Contains(text, "replicas: 5")
+		})
+	})
+	s.Run("clusterapi_machinedeployment_scale(missing replicas)", func() {
+		toolResult, err := s.CallTool("clusterapi_machinedeployment_scale", map[string]interface{}{"namespace": "fleet", "name": "prod-eu-workers"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to scale MachineDeployment: replicas parameter required", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestClusterAPI(t *testing.T) {
+	suite.Run(t, new(ClusterAPISuite))
+}
@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type ConfigRolloutSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	updated    *v1.ConfigMap
+	patches    map[string]string
+}
+
+func (s *ConfigRolloutSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	discovery := test.NewDiscoveryClientHandler()
+	verbs := metav1.Verbs{"get", "list", "watch", "create", "update", "patch", "delete"}
+	// the default core and apps resource lists
+	discovery.APIResourceLists[0].APIResources = append(discovery.APIResourceLists[0].APIResources,
+		metav1.APIResource{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: verbs})
+	discovery.APIResourceLists[1].APIResources = append(discovery.APIResourceLists[1].APIResources,
+		metav1.APIResource{Name: "statefulsets", Kind: "StatefulSet", Namespaced: true, Verbs: verbs},
+		metav1.APIResource{Name: "daemonsets", Kind: "DaemonSet", Namespaced: true, Verbs: verbs})
+	s.mockServer.Handle(discovery)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.updated, s.patches = nil, make(map[string]string)
+	deployment := func(name string, spec v1.PodSpec) appsv1.Deployment {
+		return appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(1)), Template: v1.PodTemplateSpec{Spec: spec}},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+		}
+	}
+	deployments := []appsv1.Deployment{
+		deployment("web", v1.PodSpec{Containers: []v1.Container{{Name: "app",
+			EnvFrom: []v1.EnvFromSource{{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "app"}}}},
+		}}}),
+		deployment("unrelated", v1.PodSpec{Containers: []v1.Container{{Name: "app"}}}),
+	}
+	statefulSet := appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{Replicas: ptr.To(int32(1)), Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+			Volumes:    []v1.Volume{{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "app"}}}}},
+			Containers: []v1.Container{{Name: "db"}},
+		}}},
+		// never rolled out
+		Status: appsv1.StatefulSetStatus{UpdatedReplicas: 0, ReadyReplicas: 1},
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch req.Method + " " + req.URL.Path {
+		case "GET /api/v1/namespaces/default/configmaps/app":
+			test.WriteObject(w, &v1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Data:       map[string]string{"LOG_LEVEL": "info", "OBSOLETE": "true", "PORT": "8080"},
+			})
+		case "PUT /api/v1/namespaces/default/configmaps/app":
+			s.updated = &v1.ConfigMap{}
+			// the built-in types are sent as protobuf
+			body, _ := io.ReadAll(req.Body)
+			_, _, _ = scheme.Codecs.UniversalDeserializer().Decode(body, nil, s.updated)
+			test.WriteObject(w, s.updated)
+		case "GET /apis/apps/v1/namespaces/default/deployments":
+			test.WriteObject(w, &appsv1.DeploymentList{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DeploymentList"}, Items: deployments})
+		case "GET /apis/apps/v1/namespaces/default/statefulsets":
+			test.WriteObject(w, &appsv1.StatefulSetList{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSetList"}, Items: []appsv1.StatefulSet{statefulSet}})
+		case "GET /apis/apps/v1/namespaces/default/daemonsets":
+			test.WriteObject(w, &appsv1.DaemonSetList{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DaemonSetList"}})
+		case "PATCH /apis/apps/v1/namespaces/default/deployments/web", "GET /apis/apps/v1/namespaces/default/deployments/web":
+			if req.Method == http.MethodPatch {
+				patch, _ := io.ReadAll(req.Body)
+				s.patches["deployments/web"] = string(patch)
+			}
+			test.WriteObject(w, &deployments[0])
+		case "PATCH /apis/apps/v1/namespaces/default/statefulsets/db", "GET /apis/apps/v1/namespaces/default/statefulsets/db":
+			if req.Method == http.MethodPatch {
+				patch, _ := io.ReadAll(req.Body)
+				s.patches["statefulsets/db"] = string(patch)
+			}
+			test.WriteObject(w, &statefulSet)
+		}
+	}))
+}
+
+func (s *ConfigRolloutSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ConfigRolloutSuite) TestConfigRollout() {
+	s.InitMcpClient()
+	capture := s.StartCapturingNotifications()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "config_rollout"
+	request.Params.Arguments = map[string]any{
+		"kind": "ConfigMap", "namespace": "default", "name": "app",
+		"data": map[string]any{"LOG_LEVEL": "debug", "PORT": 9090}, "remove_keys": []any{"OBSOLETE"}, "timeout": 1,
+	}
+	request.Params.Meta = &mcp.Meta{ProgressToken: "rollout"}
+	toolResult, err := s.Client.CallTool(s.T().Context(), request)
+	s.Run("no error", func() {
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	})
+	s.Run("updates the ConfigMap", func() {
+		s.Require().NotNil(s.updated)
+		s.Equal(map[string]string{"LOG_LEVEL": "debug", "PORT": "9090"}, s.updated.Data)
+	})
+	s.Run("restarts only the referencing workloads", func() {
+		s.Len(s.patches, 2)
+		s.Contains(s.patches["deployments/web"], `"kubectl.kubernetes.io/restartedAt"`)
+		s.Contains(s.patches["statefulsets/db"], `"kubectl.kubernetes.io/restartedAt"`)
+	})
+	s.Run("reports the workloads", func() {
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Contains(text, "# ConfigMap default/app updated, 1 of 2 workload(s) referencing it failed to restart or roll out, check their status (YAML format):\n")
+		var rollout map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &rollout))
+		s.Equal([]any{
+			map[string]any{"kind": "Deployment", "name": "web", "references": []any{"container app envFrom"}, "status": "RolledOut"},
+			map[string]any{"kind": "StatefulSet", "name": "db", "references": []any{"volume config"}, "status": "TimedOut", "error": "rollout not complete within 1s"},
+		}, rollout["workloads"])
+	})
+	s.Run("notifies the progress", func() {
+		notification := capture.RequireNotification(s.T(), 2*time.Second, "notifications/progress")
+		s.Equal("rollout", notification.Params.AdditionalFields["progressToken"])
+		s.EqualValues(1, notification.Params.AdditionalFields["progress"])
+		s.EqualValues(5, notification.Params.AdditionalFields["total"])
+		s.Equal("ConfigMap default/app updated", notification.Params.AdditionalFields["message"])
+	})
+}
+
+func (s *ConfigRolloutSuite) TestConfigRolloutDryRun() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("config_rollout", map[string]any{"kind": "ConfigMap", "namespace": "default", "name": "app", "dry_run": true})
+	s.Run("no error", func() {
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	})
+	s.Run("returns the workloads that would be restarted", func() {
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# Dry run, 2 workload(s) referencing ConfigMap default/app would be restarted (YAML format):\n")
+	})
+	s.Run("leaves everything unchanged", func() {
+		s.Nil(s.updated)
+		s.Empty(s.patches)
+	})
+}
+
+func (s *ConfigRolloutSuite) TestConfigRolloutInvalidArguments() {
+	s.InitMcpClient()
+	s.Run("missing changes", func() {
+		toolResult, err := s.CallTool("config_rollout", map[string]any{"kind": "ConfigMap", "name": "app"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to roll out configuration, provide the data to set or the keys to remove", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("unsupported kind", func() {
+		toolResult, err := s.CallTool("config_rollout", map[string]any{"kind": "Deployment", "name": "app", "dry_run": true})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to roll out Deployment app: unsupported configuration kind Deployment, supported kinds are [ConfigMap Secret]", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestConfigRollout(t *testing.T) {
+	suite.Run(t, new(ConfigRolloutSuite))
+}
@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	apiextensionsv1spec "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+type CRDsSuite struct {
+	BaseMcpSuite
+}
+
+func (s *CRDsSuite) SetupSuite() {
+	crd := CRD("crds.example.com", "v1", "gadgets", "Gadget", "gadget", true)
+	crd.Spec.Versions[0].Served = true
+	crd.Spec.Versions[0].Schema.OpenAPIV3Schema = &apiextensionsv1spec.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1spec.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]apiextensionsv1spec.JSONSchemaProps{
+					"size": {Type: "integer", Description: "Size of the gadget"},
+				},
+			},
+		},
+	}
+	client := apiextensionsv1.NewForConfigOrDie(envTestRestConfig)
+	_, err := client.CustomResourceDefinitions().Create(s.T().Context(), crd, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.Require().NoError(EnvTestWaitForAPIResourceCondition(s.T().Context(), "crds.example.com", "v1", "gadgets", true))
+}
+
+func (s *CRDsSuite) TearDownSuite() {
+	client := apiextensionsv1.NewForConfigOrDie(envTestRestConfig)
+	_ = client.CustomResourceDefinitions().Delete(s.T().Context(), "gadgets.crds.example.com", metav1.DeleteOptions{})
+}
+
+func (s *CRDsSuite) TestCRDsList() {
+	s.InitMcpClient()
+	s.Run("crds_list(group=crds.example.com)", func() {
+		toolResult, err := s.CallTool("crds_list", map[string]interface{}{"group": "crds.example.com"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of CRDs", func() {
+			s.Truef(strings.HasPrefix(text, "# 1 CustomResourceDefinition(s) (YAML format):\n"), "unexpected result %v", text)
+		})
+		var crds []map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &crds))
+		s.Require().Len(crds, 1)
+		s.Run("returns the CRD with its versions", func() {
+			s.Equal("gadgets.crds.example.com", crds[0]["name"])
+			s.Equal("Gadget", crds[0]["kind"])
+			s.Equal("Namespaced", crds[0]["scope"])
+			s.Equal([]any{map[string]any{"name": "v1", "served": true, "storage": true}}, crds[0]["versions"])
+			s.Equal(true, crds[0]["established"])
+		})
+	})
+	s.Run("crds_list(group=missing.example.com)", func() {
+		toolResult, err := s.CallTool("crds_list", map[string]interface{}{"group": "missing.example.com"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("returns no CRDs", func() {
+			s.Equal("# No CustomResourceDefinitions found", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+}
+
+func (s *CRDsSuite) TestCRDsSchema() {
+	s.InitMcpClient()
+	s.Run("crds_schema(name=gadgets.crds.example.com)", func() {
+		toolResult, err := s.CallTool("crds_schema", map[string]interface{}{"name": "gadgets.crds.example.com"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		var decoded map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded))
+		s.Run("returns the schema of the storage version", func() {
+			s.Equal("v1", decoded["version"])
+			s.Contains(decoded["schema"].(map[string]any)["properties"], "spec")
+		})
+	})
+	s.Run("crds_schema(name=gadgets.crds.example.com, field=spec.size)", func() {
+		toolResult, err := s.CallTool("crds_schema", map[string]interface{}{"name": "gadgets.crds.example.com", "field": "spec.size"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		var decoded map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded))
+		s.Run("returns the schema of the field", func() {
+			s.Equal(map[string]any{"type": "integer", "description": "Size of the gadget"}, decoded["schema"])
+		})
+	})
+	s.Run("crds_schema(name=gadgets.crds.example.com, version=v2)", func() {
+		toolResult, _ := s.CallTool("crds_schema", map[string]interface{}{"name": "gadgets.crds.example.com", "version": "v2"})
+		s.Run("returns error with the available versions", func() {
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Equal("failed to get the schema of CustomResourceDefinition gadgets.crds.example.com: CustomResourceDefinition gadgets.crds.example.com has no version v2, available versions: v1",
+				toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("crds_schema(missing name)", func() {
+		toolResult, _ := s.CallTool("crds_schema", map[string]interface{}{})
+		s.Run("returns error", func() {
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Equal("failed to get CustomResourceDefinition schema, missing argument name", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+}
+
+func (s *CRDsSuite) TestCRDsWaitEstablished() {
+	s.InitMcpClient()
+	s.Run("crds_wait_established(name=gadgets.crds.example.com)", func() {
+		toolResult, err := s.CallTool("crds_wait_established", map[string]interface{}{"name": "gadgets.crds.example.com"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("reports the CRD as established", func() {
+			s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# CustomResourceDefinition gadgets.crds.example.com is established (YAML format):\n"),
+				"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("crds_wait_established(name=missing.crds.example.com, timeout=1)", func() {
+		toolResult, _ := s.CallTool("crds_wait_established", map[string]interface{}{"name": "missing.crds.example.com", "timeout": 1})
+		s.Run("returns error", func() {
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Equal("failed to wait for CustomResourceDefinition missing.crds.example.com: CustomResourceDefinition missing.crds.example.com was not found within 1s",
+				toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("crds_wait_established(timeout=0)", func() {
+		toolResult, _ := s.CallTool("crds_wait_established", map[string]interface{}{"name": "gadgets.crds.example.com", "timeout": 0})
+		s.Run("returns error", func() {
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Equal("failed to parse timeout parameter: 0", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+}
+
+func TestCRDs(t *testing.T) {
+	suite.Run(t, new(CRDsSuite))
+}
@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type CrossplaneSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	claim      unstructured.Unstructured
+	composite  unstructured.Unstructured
+	managed    unstructured.Unstructured
+}
+
+func (s *CrossplaneSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.claim = unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "storage.example.org/v1alpha1",
+		"kind":       "Bucket",
+		"metadata":   map[string]any{"name": "assets", "namespace": "shop"},
+		"spec":       map[string]any{"resourceRef": map[string]any{"apiVersion": "storage.example.org/v1alpha1", "kind": "XBucket", "name": "assets-x7k2p"}},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Ready", "status": "False", "reason": "Waiting", "message": "Claim is waiting for composite resource to become Ready"},
+			map[string]any{"type": "Synced", "status": "True"},
+		}},
+	}}
+	s.composite = unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "storage.example.org/v1alpha1",
+		"kind":       "XBucket",
+		"metadata":   map[string]any{"name": "assets-x7k2p", "labels": map[string]any{"crossplane.io/claim-name": "assets", "crossplane.io/claim-namespace": "shop"}},
+		"spec": map[string]any{"resourceRefs": []any{
+			map[string]any{"apiVersion": "s3.aws.upbound.io/v1beta1", "kind": "Bucket", "name": "assets-x7k2p-bucket"},
+		}},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Ready", "status": "False", "reason": "Unavailable", "message": "Unready resources: bucket"},
+			map[string]any{"type": "Synced", "status": "True"},
+		}},
+	}}
+	s.managed = unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "s3.aws.upbound.io/v1beta1",
+		"kind":       "Bucket",
+		"metadata":   map[string]any{"name": "assets-x7k2p-bucket", "labels": map[string]any{"crossplane.io/claim-name": "assets", "crossplane.io/claim-namespace": "shop"}},
+		"spec":       map[string]any{"forProvider": map[string]any{"region": "eu-west-1"}},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Ready", "status": "False", "reason": "Creating"},
+			map[string]any{"type": "Synced", "status": "False", "reason": "ReconcileError", "message": "create failed: AccessDenied: not authorized to perform s3:CreateBucket"},
+		}},
+	}}
+	discovery := test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "storage.example.org/v1alpha1",
+		APIResources: []metav1.APIResource{
+			{Name: "buckets", Kind: "Bucket", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}, Categories: []string{"claim"}},
+			{Name: "xbuckets", Kind: "XBucket", Verbs: metav1.Verbs{"get", "list"}, Categories: []string{"composite"}},
+		},
+	}, metav1.APIResourceList{
+		GroupVersion: "s3.aws.upbound.io/v1beta1",
+		APIResources: []metav1.APIResource{
+			{Name: "buckets", Kind: "Bucket", Verbs: metav1.Verbs{"get", "list"}, Categories: []string{"crossplane", "managed", "aws"}},
+		},
+	})
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(discovery)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		list := func(apiVersion, kind string, item unstructured.Unstructured) {
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": apiVersion, "kind": kind + "List"},
+				Items:  []unstructured.Unstructured{item},
+			})
+		}
+		switch req.URL.Path {
+		case "/apis/storage.example.org/v1alpha1/buckets":
+			list("storage.example.org/v1alpha1", "Bucket", s.claim)
+		case "/apis/storage.example.org/v1alpha1/xbuckets":
+			list("storage.example.org/v1alpha1", "XBucket", s.composite)
+		case "/apis/s3.aws.upbound.io/v1beta1/buckets":
+			list("s3.aws.upbound.io/v1beta1", "Bucket", s.managed)
+		case "/apis/storage.example.org/v1alpha1/namespaces/shop/buckets/assets":
+			test.WriteObject(w, &s.claim)
+		case "/apis/storage.example.org/v1alpha1/xbuckets/assets-x7k2p":
+			test.WriteObject(w, &s.composite)
+		case "/apis/s3.aws.upbound.io/v1beta1/buckets/assets-x7k2p-bucket":
+			test.WriteObject(w, &s.managed)
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"crossplane"}
+}
+
+func (s *CrossplaneSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *CrossplaneSuite) TestResourcesList() {
+	s.InitMcpClient()
+	s.Run("crossplane_resources_list lists all the categories", func() {
+		toolResult, err := s.CallTool("crossplane_resources_list", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of resources", func() {
+			s.Truef(strings.HasPrefix(text, "# 3 Crossplane resource(s) found, 3 not ready or not synced (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the provider errors", func() {
+			s.Contains(text, "create failed: AccessDenied: not authorized to perform s3:CreateBucket")
+			s.Contains(text, "category: managed")
+		})
+	})
+	s.Run("crossplane_resources_list(category=managed)", func() {
+		toolResult, err := s.CallTool("crossplane_resources_list", map[string]interface{}{"category": "managed"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# 1 Crossplane resource(s) found"),
+			"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *CrossplaneSuite) TestResourceTree() {
+	s.InitMcpClient()
+	s.Run("crossplane_resource_tree(claim)", func() {
+		toolResult, err := s.CallTool("crossplane_resource_tree", map[string]interface{}{
+			"apiVersion": "storage.example.org/v1alpha1", "kind": "Bucket", "namespace": "shop", "name": "assets",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of unhealthy resources", func() {
+			s.Truef(strings.HasPrefix(text, "# Bucket assets: Ready=False, Synced=True, 3 of 3 resource(s) not ready or not synced (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the provider error as root cause", func() {
+			s.Contains(text, "(YAML format):\nrootCauses:\n- 'Bucket/assets-x7k2p-bucket: create failed: AccessDenied")
+		})
+	})
+	s.Run("crossplane_resource_tree(missing apiVersion)", func() {
+		toolResult, err := s.CallTool("crossplane_resource_tree", map[string]interface{}{"kind": "Bucket", "name": "assets"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to get Crossplane resource tree:")
+	})
+}
+
+func TestCrossplane(t *testing.T) {
+	suite.Run(t, new(CrossplaneSuite))
+}
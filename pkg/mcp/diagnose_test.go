@@ -0,0 +1,264 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+type DiagnoseSuite struct {
+	BaseMcpSuite
+}
+
+func (s *DiagnoseSuite) TestDiagnosePod() {
+	client := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	pod, err := client.CoreV1().Pods("default").Create(s.T().Context(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-pod-to-diagnose"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "registry.example.com/app:missing"}}},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = client.CoreV1().Pods("default").Delete(s.T().Context(), pod.Name, metav1.DeleteOptions{})
+	})
+	pod.Status = corev1.PodStatus{
+		Phase: corev1.PodPending,
+		ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "app",
+			Image: "registry.example.com/app:missing",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+				Reason:  "ImagePullBackOff",
+				Message: "Back-off pulling image \"registry.example.com/app:missing\": manifest unknown",
+			}},
+		}},
+	}
+	_, err = client.CoreV1().Pods("default").UpdateStatus(s.T().Context(), pod, metav1.UpdateOptions{})
+	s.Require().NoError(err)
+	s.InitMcpClient()
+	s.Run("diagnose_pod(name=a-pod-to-diagnose)", func() {
+		toolResult, err := s.CallTool("diagnose_pod", map[string]interface{}{"name": "a-pod-to-diagnose"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		var diagnosis map[string]any
+		err = yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &diagnosis)
+		s.Run("has yaml content", func() {
+			s.Nilf(err, "invalid tool result content %v", err)
+		})
+		s.Run("reports ImagePullBackOff finding with failing image and registry error", func() {
+			findings, _ := diagnosis["findings"].([]any)
+			s.Require().Len(findings, 1)
+			finding := findings[0].(map[string]any)
+			s.Equal("critical", finding["severity"])
+			s.Equal("ImagePullBackOff", finding["reason"])
+			s.Equal("app", finding["container"])
+			s.Contains(finding["message"], "registry.example.com/app:missing")
+			s.Contains(finding["message"], "manifest unknown")
+		})
+		s.Run("reports container state", func() {
+			containers, _ := diagnosis["containers"].([]any)
+			s.Require().Len(containers, 1)
+			s.Equal("waiting", containers[0].(map[string]any)["state"])
+		})
+	})
+	s.Run("diagnose_pod(name=not-found)", func() {
+		toolResult, _ := s.CallTool("diagnose_pod", map[string]interface{}{"name": "not-found"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to diagnose pod not-found in namespace : pods \"not-found\" not found", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("diagnose_pod with missing name", func() {
+		toolResult, _ := s.CallTool("diagnose_pod", map[string]interface{}{})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to diagnose pod, missing argument name", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *DiagnoseSuite) TestDiagnoseScheduling() {
+	client := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	pod, err := client.CoreV1().Pods("default").Create(s.T().Context(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-pending-pod"},
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"disktype": "ssd"},
+			Containers:   []corev1.Container{{Name: "app", Image: "nginx"}},
+		},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = client.CoreV1().Pods("default").Delete(s.T().Context(), pod.Name, metav1.DeleteOptions{})
+	})
+	s.InitMcpClient()
+	s.Run("diagnose_scheduling(name=a-pending-pod)", func() {
+		toolResult, err := s.CallTool("diagnose_scheduling", map[string]interface{}{"name": "a-pending-pod"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		var diagnosis map[string]any
+		err = yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &diagnosis)
+		s.Run("has yaml content", func() {
+			s.Nilf(err, "invalid tool result content %v", err)
+		})
+		s.Run("reports the pod as not scheduled", func() {
+			s.Equal("a-pending-pod", diagnosis["name"])
+			s.Equal(false, diagnosis["scheduled"])
+			s.NotEmpty(diagnosis["findings"])
+		})
+	})
+	s.Run("diagnose_scheduling(name=not-found)", func() {
+		toolResult, _ := s.CallTool("diagnose_scheduling", map[string]interface{}{"name": "not-found"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to diagnose scheduling of pod not-found in namespace : pods \"not-found\" not found", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *DiagnoseSuite) TestDiagnoseRestarts() {
+	client := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	pod, err := client.CoreV1().Pods("ns-1").Create(s.T().Context(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "an-oomkilled-pod", Labels: map[string]string{"app": "oom"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "nginx"}}},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = client.CoreV1().Pods("ns-1").Delete(s.T().Context(), pod.Name, metav1.DeleteOptions{})
+	})
+	pod.Status = corev1.PodStatus{
+		Phase: corev1.PodRunning,
+		ContainerStatuses: []corev1.ContainerStatus{{
+			Name:         "app",
+			Image:        "nginx",
+			RestartCount: 4,
+			State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.Now()}},
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				Reason: "OOMKilled", ExitCode: 137, FinishedAt: metav1.Now(),
+			}},
+		}},
+	}
+	_, err = client.CoreV1().Pods("ns-1").UpdateStatus(s.T().Context(), pod, metav1.UpdateOptions{})
+	s.Require().NoError(err)
+	s.InitMcpClient()
+	s.Run("diagnose_restarts(namespace=ns-1)", func() {
+		toolResult, err := s.CallTool("diagnose_restarts", map[string]interface{}{"namespace": "ns-1"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		var offenders []map[string]any
+		err = yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &offenders)
+		s.Run("has yaml content", func() {
+			s.Nilf(err, "invalid tool result content %v", err)
+		})
+		s.Run("reports the OOMKilled container", func() {
+			s.Require().Len(offenders, 1)
+			s.Equal("an-oomkilled-pod", offenders[0]["pod"])
+			s.Equal(true, offenders[0]["oomKilled"])
+			s.EqualValues(4, offenders[0]["restarts"])
+		})
+	})
+	s.Run("diagnose_restarts(labelSelector=app=other)", func() {
+		toolResult, _ := s.CallTool("diagnose_restarts", map[string]interface{}{"namespace": "ns-1", "labelSelector": "app=other"})
+		s.Falsef(toolResult.IsError, "call tool failed")
+		s.Equal("# No OOMKilled or crash looping containers found in the last 24h", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("diagnose_restarts(window=invalid)", func() {
+		toolResult, _ := s.CallTool("diagnose_restarts", map[string]interface{}{"window": "yesterday"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("invalid window yesterday, expected a positive duration (e.g. 30m, 6h)", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *DiagnoseSuite) TestDiagnoseTermination() {
+	client := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	cm, err := client.CoreV1().ConfigMaps("ns-1").Create(s.T().Context(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-stuck-configmap", Finalizers: []string{"widgets.example.com/cleanup"}},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		cm, _ = client.CoreV1().ConfigMaps("ns-1").Get(s.T().Context(), cm.Name, metav1.GetOptions{})
+		if cm != nil {
+			cm.Finalizers = nil
+			_, _ = client.CoreV1().ConfigMaps("ns-1").Update(s.T().Context(), cm, metav1.UpdateOptions{})
+		}
+	})
+	s.Require().NoError(client.CoreV1().ConfigMaps("ns-1").Delete(s.T().Context(), cm.Name, metav1.DeleteOptions{}))
+	s.InitMcpClient()
+	s.Run("diagnose_termination(namespace=ns-1)", func() {
+		toolResult, err := s.CallTool("diagnose_termination", map[string]interface{}{"namespace": "ns-1"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		var diagnosis map[string]any
+		err = yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &diagnosis)
+		s.Run("has yaml content", func() {
+			s.Nilf(err, "invalid tool result content %v", err)
+		})
+		s.Run("reports the stuck object and its finalizer", func() {
+			objects, _ := diagnosis["objects"].([]any)
+			s.Require().Len(objects, 1)
+			s.Equal("a-stuck-configmap", objects[0].(map[string]any)["name"])
+			s.Equal([]any{"widgets.example.com/cleanup"}, objects[0].(map[string]any)["finalizers"])
+		})
+		s.Run("reports the missing controller", func() {
+			controllers, _ := diagnosis["controllers"].([]any)
+			s.Require().Len(controllers, 1)
+			s.Equal("unknown", controllers[0].(map[string]any)["controller"])
+		})
+	})
+	s.Run("diagnose_termination(namespace=ns-2) with no stuck objects", func() {
+		toolResult, _ := s.CallTool("diagnose_termination", map[string]interface{}{"namespace": "ns-2"})
+		s.Falsef(toolResult.IsError, "call tool failed")
+		s.Equal("# No objects stuck in Terminating found", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *DiagnoseSuite) TestDiagnoseOrphans() {
+	client := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	_, err := client.CoreV1().ConfigMaps("ns-2").Create(s.T().Context(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "an-unused-configmap"},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = client.CoreV1().ConfigMaps("ns-2").Delete(s.T().Context(), "an-unused-configmap", metav1.DeleteOptions{})
+	})
+	s.InitMcpClient()
+	s.Run("diagnose_orphans(namespace=ns-2)", func() {
+		toolResult, err := s.CallTool("diagnose_orphans", map[string]interface{}{"namespace": "ns-2"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		var orphans []map[string]any
+		err = yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &orphans)
+		s.Run("has yaml content", func() {
+			s.Nilf(err, "invalid tool result content %v", err)
+		})
+		s.Run("reports the unreferenced ConfigMap", func() {
+			var found map[string]any
+			for _, orphan := range orphans {
+				if orphan["kind"] == "ConfigMap" && orphan["name"] == "an-unused-configmap" {
+					found = orphan
+				}
+			}
+			s.Require().NotNil(found, "expected an-unused-configmap to be reported")
+			s.Equal("ConfigMap is not referenced by any Pod or workload", found["reason"])
+		})
+		s.Run("doesn't report the root CA ConfigMap", func() {
+			for _, orphan := range orphans {
+				s.NotEqual("kube-root-ca.crt", orphan["name"])
+			}
+		})
+	})
+	s.Run("diagnose_orphans(pvc_unused_days=-1)", func() {
+		toolResult, _ := s.CallTool("diagnose_orphans", map[string]interface{}{"pvc_unused_days": -1})
+		s.Truef(toolResult.IsError, "call tool should fail")
+	})
+}
+
+func TestDiagnose(t *testing.T) {
+	suite.Run(t, new(DiagnoseSuite))
+}
@@ -71,7 +71,7 @@ func (s *EventsSuite) TestEventsList() {
 					"  Message: The event message\n"+
 					"  Namespace: default\n"+
 					"  Reason: \"\"\n"+
-					"  Timestamp: 0001-01-01 00:00:00 +0000 UTC\n"+
+					"  Timestamp: \"0001-01-01T00:00:00Z\"\n"+
 					"  Type: Normal\n"+
 					"- InvolvedObject:\n"+
 					"    Kind: Pod\n"+
@@ -80,7 +80,7 @@ func (s *EventsSuite) TestEventsList() {
 					"  Message: The event message\n"+
 					"  Namespace: ns-1\n"+
 					"  Reason: \"\"\n"+
-					"  Timestamp: 0001-01-01 00:00:00 +0000 UTC\n"+
+					"  Timestamp: \"0001-01-01T00:00:00Z\"\n"+
 					"  Type: Normal\n",
 					toolResult.Content[0].(mcp.TextContent).Text,
 					"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
@@ -112,7 +112,7 @@ func (s *EventsSuite) TestEventsList() {
 					"  Message: The event message\n"+
 					"  Namespace: ns-1\n"+
 					"  Reason: \"\"\n"+
-					"  Timestamp: 0001-01-01 00:00:00 +0000 UTC\n"+
+					"  Timestamp: \"0001-01-01T00:00:00Z\"\n"+
 					"  Type: Normal\n",
 					toolResult.Content[0].(mcp.TextContent).Text,
 					"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
@@ -121,6 +121,84 @@ func (s *EventsSuite) TestEventsList() {
 	})
 }
 
+func (s *EventsSuite) TestEventsListAge() {
+	s.InitMcpClient()
+	client := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	firstTimestamp := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	_, err := client.CoreV1().Events("ns-2").Create(s.T().Context(), &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "an-aged-event"},
+		InvolvedObject: v1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: "a-pod", Namespace: "ns-2"},
+		FirstTimestamp: metav1.NewTime(firstTimestamp),
+		Type:           "Warning",
+		Message:        "The aged event message",
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err, "failed to create event")
+	s.T().Cleanup(func() {
+		_ = client.CoreV1().Events("ns-2").Delete(s.T().Context(), "an-aged-event", metav1.DeleteOptions{})
+	})
+	toolResult, err := s.CallTool("events_list", map[string]interface{}{"namespace": "ns-2"})
+	s.Run("no error", func() {
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed")
+	})
+	var decoded []map[string]any
+	s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded))
+	s.Require().Len(decoded, 1)
+	s.Run("returns RFC3339 timestamp", func() {
+		s.Equal(firstTimestamp.UTC().Format(time.RFC3339), decoded[0]["Timestamp"])
+	})
+	s.Run("returns human readable age", func() {
+		s.Equal("120m", decoded[0]["Age"])
+	})
+}
+
+func (s *EventsSuite) TestEventsSummary() {
+	s.InitMcpClient()
+	client := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	now := time.Now().Truncate(time.Second)
+	for i, pod := range []string{"pending-1", "pending-2"} {
+		_, err := client.CoreV1().Events("ns-2").Create(s.T().Context(), &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "a-scheduling-event-" + pod},
+			InvolvedObject: v1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: pod, Namespace: "ns-2"},
+			FirstTimestamp: metav1.NewTime(now.Add(-5 * time.Minute)),
+			LastTimestamp:  metav1.NewTime(now),
+			Count:          int32(15 + i),
+			Type:           "Warning",
+			Reason:         "FailedScheduling",
+			Message:        "0/3 nodes are available",
+		}, metav1.CreateOptions{})
+		s.Require().NoError(err, "failed to create event")
+	}
+	s.T().Cleanup(func() {
+		_ = client.CoreV1().Events("ns-2").DeleteCollection(s.T().Context(), metav1.DeleteOptions{}, metav1.ListOptions{})
+	})
+	s.Run("events_summary(namespace=ns-2)", func() {
+		toolResult, err := s.CallTool("events_summary", map[string]interface{}{"namespace": "ns-2"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("has yaml comment with the totals", func() {
+			s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# 31 event occurrence(s) in 1 group(s) in the last 1h, 1 spike(s) (YAML format):\n"),
+				"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+		var groups []map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &groups))
+		s.Require().Len(groups, 1)
+		s.Run("aggregates the events by reason", func() {
+			s.Equal("FailedScheduling", groups[0]["reason"])
+			s.EqualValues(31, groups[0]["count"])
+			s.Equal(true, groups[0]["spike"])
+			s.Equal("6.2", groups[0]["perMinute"])
+			s.EqualValues(2, groups[0]["objects"])
+		})
+	})
+	s.Run("events_summary(window=invalid)", func() {
+		toolResult, _ := s.CallTool("events_summary", map[string]interface{}{"window": "invalid"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+	})
+}
+
 func (s *EventsSuite) TestEventsListDenied() {
 	s.Require().NoError(toml.Unmarshal([]byte(`
 		denied_resources = [ { version = "v1", kind = "Event" } ]
@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+type ExtensionSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	extension  *httptest.Server
+	// calls are the bodies of the tool calls received by the extension
+	calls []map[string]any
+}
+
+func (s *ExtensionSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "authorization.k8s.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "selfsubjectaccessreviews", Kind: "SelfSubjectAccessReview", Namespaced: false, Verbs: metav1.Verbs{"create"}},
+		},
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.calls = nil
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tools", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"tools": [
+			{"name": "tenants_list", "description": "List the tenants", "annotations": {"readOnlyHint": true}},
+			{"name": "tenants_create", "description": "Create a tenant", "inputSchema": {"type": "object", "properties": {"name": {"type": "string"}}}},
+			{"name": "apps_deploy", "description": "Deploy an app", "permissions": [{"verb": "create", "group": "apps", "resource": "deployments", "namespaced": true}]},
+			{"name": "access_check", "description": "Check an access", "permissions": [{"verb": "create", "group": "authorization.k8s.io", "resource": "selfsubjectaccessreviews"}]}
+		]}`))
+	})
+	mux.HandleFunc("POST /tools/{name}", func(w http.ResponseWriter, r *http.Request) {
+		var call map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&call)
+		s.calls = append(s.calls, call)
+		if r.PathValue("name") == "tenants_create" {
+			_, _ = w.Write([]byte(`{"error": "quota exceeded"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"content": "tenants: [payments]"}`))
+	})
+	s.extension = httptest.NewServer(mux)
+	s.Cfg.Toolsets = []string{}
+	s.Cfg.Extensions = []config.ExtensionConfig{{Name: "acme", URL: s.extension.URL, Headers: map[string]string{"X-Api-Key": "secret"}}}
+}
+
+func (s *ExtensionSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	s.extension.Close()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ExtensionSuite) TestListTools() {
+	s.InitMcpClient()
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err, "call ListTools failed")
+	names := make(map[string]mcp.Tool)
+	for _, tool := range tools.Tools {
+		names[tool.Name] = tool
+	}
+	s.Run("registers the tools of the extension prefixed with its name", func() {
+		s.Contains(names, "acme_tenants_list")
+		s.Contains(names, "acme_tenants_create")
+	})
+	s.Run("treats the tools not annotated as read-only as destructive", func() {
+		s.False(ptr.Deref(names["acme_tenants_create"].Annotations.ReadOnlyHint, false))
+		s.True(ptr.Deref(names["acme_tenants_create"].Annotations.DestructiveHint, false))
+		s.False(ptr.Deref(names["acme_tenants_list"].Annotations.DestructiveHint, false))
+	})
+}
+
+func (s *ExtensionSuite) TestReadOnly() {
+	s.Cfg.ReadOnly = true
+	s.InitMcpClient()
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err, "call ListTools failed")
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	s.Contains(names, "acme_tenants_list")
+	s.NotContains(names, "acme_tenants_create", "the mutating tools of the extensions must be filtered in read-only mode")
+}
+
+func (s *ExtensionSuite) TestCallTool() {
+	s.InitMcpClient()
+	s.Run("returns the content returned by the extension", func() {
+		toolResult, err := s.CallTool("acme_tenants_list", map[string]any{"team": "payments"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.False(toolResult.IsError, "call tool should succeed")
+		s.Equal("tenants: [payments]", toolResult.Content[0].(mcp.TextContent).Text)
+		s.Require().Len(s.calls, 1)
+		s.Equal(map[string]any{"team": "payments"}, s.calls[0]["arguments"])
+	})
+	s.Run("returns the error returned by the extension", func() {
+		toolResult, err := s.CallTool("acme_tenants_create", map[string]any{"name": "shop"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("quota exceeded", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *ExtensionSuite) TestDeniedResource() {
+	s.Cfg.DeniedResources = []api.GroupVersionKind{{Group: "apps", Version: "v1", Kind: "Deployment"}}
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("acme_apps_deploy", map[string]any{"namespace": "shop"})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Run("rejects the call of the tool declaring permissions on the denied resource", func() {
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to call the acme extension: resource not allowed: apps/v1, Kind=Deployment", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("doesn't forward the call to the extension", func() {
+		s.Empty(s.calls)
+	})
+	s.Run("rejects the call of the mutating tool declaring no permissions", func() {
+		toolResult, err := s.CallTool("acme_tenants_create", map[string]any{"name": "shop"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "the tool declares no permissions")
+		s.Empty(s.calls)
+	})
+	s.Run("forwards the call of the read-only tool declaring no permissions", func() {
+		toolResult, err := s.CallTool("acme_tenants_list", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	})
+}
+
+func (s *ExtensionSuite) TestDeniedScope() {
+	s.Cfg.DeniedScopes = []api.DeniedScope{{Scope: api.ScopeNamespaced, WritesOnly: true}}
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("acme_apps_deploy", map[string]any{"namespace": "shop"})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Run("rejects the call of the tool declaring writes to the denied scope", func() {
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to call the acme extension: resource not allowed: apps/v1, Kind=Deployment (writes to namespaced resources are denied)", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("doesn't forward the call to the extension", func() {
+		s.Empty(s.calls)
+	})
+}
+
+func (s *ExtensionSuite) TestDeniedScopeReview() {
+	s.Cfg.DeniedScopes = []api.DeniedScope{{Scope: api.ScopeCluster, WritesOnly: true}}
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("acme_access_check", map[string]any{})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Falsef(toolResult.IsError, "the reviews aren't writes, call tool failed: %v", toolResult.Content)
+	s.Len(s.calls, 1, "the call must be forwarded to the extension")
+}
+
+func (s *ExtensionSuite) TestAllowedScope() {
+	s.Cfg.DeniedScopes = []api.DeniedScope{{Scope: api.ScopeCluster}}
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("acme_apps_deploy", map[string]any{"namespace": "shop"})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	s.Len(s.calls, 1, "the call must be forwarded to the extension")
+}
+
+func (s *ExtensionSuite) TestUnavailableExtension() {
+	s.Cfg.Extensions[0].Headers = nil
+	s.InitMcpClient()
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err, "the server must start even if an extension is unavailable")
+	for _, tool := range tools.Tools {
+		s.NotContains(tool.Name, "acme_")
+	}
+}
+
+func TestExtension(t *testing.T) {
+	suite.Run(t, new(ExtensionSuite))
+}
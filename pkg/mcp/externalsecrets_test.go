@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type ExternalSecretsSuite struct {
+	BaseMcpSuite
+	mockServer     *test.MockServer
+	externalSecret unstructured.Unstructured
+	patch          string
+}
+
+func (s *ExternalSecretsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.patch = ""
+	s.externalSecret = unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "external-secrets.io/v1",
+		"kind":       "ExternalSecret",
+		"metadata":   map[string]any{"name": "api", "namespace": "payments"},
+		"spec":       map[string]any{"refreshInterval": "1h", "secretStoreRef": map[string]any{"name": "vault"}},
+		"status": map[string]any{"conditions": []any{map[string]any{
+			"type": "Ready", "status": "False", "reason": "SecretSyncedError", "message": "could not get secret data from provider",
+		}}},
+	}}
+	discovery := test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "external-secrets.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "externalsecrets", Kind: "ExternalSecret", Namespaced: true, Verbs: metav1.Verbs{"get", "list", "patch"}},
+			{Name: "secretstores", Kind: "SecretStore", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+		},
+	})
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(discovery)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/external-secrets.io/v1/externalsecrets":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "external-secrets.io/v1", "kind": "ExternalSecretList"},
+				Items:  []unstructured.Unstructured{s.externalSecret},
+			})
+		case "/apis/external-secrets.io/v1/secretstores":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "external-secrets.io/v1", "kind": "SecretStoreList"},
+				Items: []unstructured.Unstructured{{Object: map[string]any{
+					"apiVersion": "external-secrets.io/v1",
+					"kind":       "SecretStore",
+					"metadata":   map[string]any{"name": "vault", "namespace": "payments"},
+					"spec":       map[string]any{"provider": map[string]any{"vault": map[string]any{}}},
+					"status": map[string]any{"conditions": []any{map[string]any{
+						"type": "Ready", "status": "False", "reason": "InvalidProviderConfig", "message": "unable to log in to auth method: permission denied",
+					}}},
+				}}},
+			})
+		case "/apis/external-secrets.io/v1/namespaces/payments/externalsecrets/api":
+			if req.Method == http.MethodPatch {
+				body, _ := io.ReadAll(req.Body)
+				s.patch = string(body)
+			}
+			test.WriteObject(w, &s.externalSecret)
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"externalsecrets"}
+}
+
+func (s *ExternalSecretsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ExternalSecretsSuite) TestExternalSecretsList() {
+	s.InitMcpClient()
+	s.Run("externalsecrets_list skips the kinds not installed", func() {
+		toolResult, err := s.CallTool("externalsecrets_list", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of resources", func() {
+			s.Truef(strings.HasPrefix(text, "# 2 External Secrets resource(s) found, 2 not ready (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the last errors and the readiness of the store", func() {
+			s.Contains(text, "message: could not get secret data from provider")
+			s.Contains(text, "message: 'unable to log in to auth method: permission denied'")
+			s.Contains(text, "storeReady: \"False\"")
+		})
+	})
+	s.Run("externalsecrets_list(kind=ExternalSecret) links the stores", func() {
+		toolResult, err := s.CallTool("externalsecrets_list", map[string]interface{}{"kind": "ExternalSecret"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "storeReady: \"False\"")
+	})
+	s.Run("externalsecrets_list(kind=ClusterSecretStore) fails when not installed", func() {
+		toolResult, err := s.CallTool("externalsecrets_list", map[string]interface{}{"kind": "ClusterSecretStore"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to list External Secrets resources:")
+	})
+}
+
+func (s *ExternalSecretsSuite) TestExternalSecretRefresh() {
+	s.InitMcpClient()
+	s.Run("externalsecret_refresh(name=api)", func() {
+		toolResult, err := s.CallTool("externalsecret_refresh", map[string]interface{}{"namespace": "payments", "name": "api"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		s.Run("sets the force-sync annotation", func() {
+			s.Contains(s.patch, `{"metadata":{"annotations":{"force-sync":"`)
+		})
+		s.Run("returns the ExternalSecret", func() {
+			s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# Refresh of ExternalSecret payments/api requested"),
+				"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+}
+
+func TestExternalSecrets(t *testing.T) {
+	suite.Run(t, new(ExternalSecretsSuite))
+}
@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type FluxSuite struct {
+	BaseMcpSuite
+	mockServer    *test.MockServer
+	kustomization *unstructured.Unstructured
+	patches       []map[string]any
+}
+
+func (s *FluxSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.patches = nil
+	s.kustomization = &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+		"kind":       "Kustomization",
+		"metadata":   map[string]any{"name": "apps", "namespace": "flux-system"},
+		"spec":       map[string]any{"path": "./apps", "sourceRef": map[string]any{"kind": "GitRepository", "name": "flux-system"}},
+		"status": map[string]any{
+			"lastAppliedRevision": "main@sha1:abc123",
+			"conditions":          []any{map[string]any{"type": "Ready", "status": "False", "reason": "HealthCheckFailed", "message": "timeout waiting for Deployment/apps/web"}},
+		},
+	}}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "kustomize.toolkit.fluxcd.io/v1",
+		APIResources: []metav1.APIResource{{Name: "kustomizations", Kind: "Kustomization", Namespaced: true, Verbs: metav1.Verbs{"get", "list", "patch"}}},
+	}))
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/kustomize.toolkit.fluxcd.io/v1/kustomizations":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "kustomize.toolkit.fluxcd.io/v1", "kind": "KustomizationList"},
+				Items:  []unstructured.Unstructured{*s.kustomization},
+			})
+		case "/apis/kustomize.toolkit.fluxcd.io/v1/namespaces/flux-system/kustomizations/apps":
+			if req.Method == http.MethodPatch {
+				body, _ := io.ReadAll(req.Body)
+				patch := map[string]any{}
+				_ = json.Unmarshal(body, &patch)
+				s.patches = append(s.patches, patch)
+				if spec, ok := patch["spec"].(map[string]any); ok {
+					s.kustomization.Object["spec"].(map[string]any)["suspend"] = spec["suspend"]
+				}
+			}
+			test.WriteObject(w, s.kustomization)
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"flux"}
+}
+
+func (s *FluxSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *FluxSuite) TestResourcesList() {
+	s.InitMcpClient()
+	s.Run("flux_resources_list skips the kinds not installed", func() {
+		toolResult, err := s.CallTool("flux_resources_list", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of resources", func() {
+			s.Truef(strings.HasPrefix(text, "# 1 Flux resource(s) found, 1 not ready, 0 suspended (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the Ready condition and revision", func() {
+			s.Contains(text, "reason: HealthCheckFailed")
+			s.Contains(text, "lastAppliedRevision: main@sha1:abc123")
+		})
+	})
+	s.Run("flux_resources_list(kind=HelmRelease) fails when not installed", func() {
+		toolResult, err := s.CallTool("flux_resources_list", map[string]interface{}{"kind": "HelmRelease"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to list Flux resources:")
+	})
+}
+
+func (s *FluxSuite) TestReconcile() {
+	s.InitMcpClient()
+	s.Run("flux_reconcile(kind=Kustomization, name=apps)", func() {
+		toolResult, err := s.CallTool("flux_reconcile", map[string]interface{}{"kind": "Kustomization", "namespace": "flux-system", "name": "apps"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		s.Run("sets the requestedAt annotation", func() {
+			s.Require().Len(s.patches, 1)
+			annotations := s.patches[0]["metadata"].(map[string]any)["annotations"].(map[string]any)
+			s.Contains(annotations, "reconcile.fluxcd.io/requestedAt")
+			s.NotContains(annotations, "reconcile.fluxcd.io/forceAt")
+		})
+		s.Run("has yaml comment", func() {
+			s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# Reconciliation of Kustomization flux-system/apps requested"), "unexpected result %v", toolResult.Content)
+		})
+	})
+}
+
+func (s *FluxSuite) TestSuspendResume() {
+	s.InitMcpClient()
+	s.Run("flux_suspend(kind=Kustomization, name=apps)", func() {
+		toolResult, err := s.CallTool("flux_suspend", map[string]interface{}{"kind": "Kustomization", "namespace": "flux-system", "name": "apps"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "suspended: true")
+	})
+	s.Run("flux_resume(kind=Kustomization, name=apps)", func() {
+		toolResult, err := s.CallTool("flux_resume", map[string]interface{}{"kind": "Kustomization", "namespace": "flux-system", "name": "apps"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "suspended: false")
+		s.Require().Len(s.patches, 2)
+		s.Equal(map[string]any{"suspend": false}, s.patches[1]["spec"])
+		s.Contains(s.patches[1]["metadata"].(map[string]any)["annotations"], "reconcile.fluxcd.io/requestedAt")
+	})
+}
+
+func TestFlux(t *testing.T) {
+	suite.Run(t, new(FluxSuite))
+}
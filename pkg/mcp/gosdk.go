@@ -1,12 +1,19 @@
 package mcp
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/hooks"
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+	"github.com/containers/kubernetes-mcp-server/pkg/sessionbudget"
+	"github.com/containers/kubernetes-mcp-server/pkg/workspace"
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"k8s.io/utils/ptr"
@@ -44,21 +51,130 @@ func ServerToolToGoSdkTool(s *Server, tool api.ServerTool) (*mcp.Tool, mcp.ToolH
 			return nil, err
 		}
 
-		result, err := tool.Handler(api.ToolHandlerParams{
+		// the output parameter overrides the configured list output for this tool call only
+		listOutput := s.configuration.ListOutput()
+		switch requestedOutput := toolCallRequest.GetString(OutputParameterName, ""); requestedOutput {
+		case "":
+		case output.Yaml.GetName(), output.Json.GetName():
+			listOutput = output.FromString(requestedOutput)
+		case output.Table.GetName():
+			if !tool.IsTableOutput() {
+				return NewTextResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("output format table is not supported by %s", tool.Tool.Name))), nil
+			}
+			listOutput = output.Table
+		default:
+			return NewTextResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid output format: %s", requestedOutput))), nil
+		}
+		// the locale parameter overrides the configured locale of the localized tools for this tool call only
+		requestedLocale := toolCallRequest.GetString(LocaleParameterName, s.configuration.Locale)
+		if !locale.IsSupported(requestedLocale) {
+			return NewTextResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid locale: %s", requestedLocale))), nil
+		}
+		ctx = locale.NewContext(ctx, requestedLocale)
+		// the tools read the files of the workspace of the session referenced as workspace:<path>
+		if s.configuration.Workspace.Enabled() {
+			ctx = workspace.NewContext(ctx, s.workspace, sessionID(request))
+		}
+		// the logs and background goroutines held by the tools are accounted per session, the
+		// sessionless calls (stateless HTTP) by their credentials
+		ctx = sessionbudget.NewContext(ctx, s.budget, cmp.Or(sessionID(request), callerKey(ctx, "")))
+
+		params := api.ToolHandlerParams{
 			Context:                ctx,
 			ExtendedConfigProvider: s.configuration,
 			KubernetesClient:       k,
 			ToolCallRequest:        toolCallRequest,
-			ListOutput:             s.configuration.ListOutput(),
-		})
+			ListOutput:             listOutput,
+			PruneFields:            s.configuration.PruneFields,
+			ListDetail:             s.configuration.ListDetail,
+			Target:                 cluster,
+			TargetClient:           s.targetClient,
+		}
+		if err = s.checkChangeFreeze(ctx, tool, toolCallRequest.GetArguments(), cluster, sessionID(request)); err != nil {
+			return NewTextResult("", err), nil
+		}
+		preflight, err := s.checkPermissions(tool, params)
+		if err != nil {
+			return NewTextResult("", err), nil
+		}
+		// the long-running tools called with async=true return the id of the operation running them in the background
+		if async, _ := toolCallRequest.GetArguments()[AsyncParameterName].(bool); async && tool.IsLongRunning() {
+			operation, err := s.operations.start(ctx, sessionID(request), tool.Tool.Name, cluster, func(ctx context.Context) (*api.ToolCallResult, error) {
+				params.Context = ctx
+				result, err := s.callTool(tool, params, request)
+				if err == nil && result.Error == nil {
+					addResultHeader(result, preflight, params.ListOutput)
+				}
+				return result, err
+			})
+			if err != nil {
+				return NewTextResult("", err), nil
+			}
+			ret, err := params.Marshal(operation)
+			return NewTextResult(ret, err), nil
+		}
+		result, meta, err := s.cachedCallTool(tool, params, request)
 		if err != nil {
 			return nil, err
 		}
-		return NewTextResult(result.Content, result.Error), nil
+		if result.Error == nil {
+			addResultHeader(result, preflight, params.ListOutput)
+		}
+		ret := NewToolCallResult(result)
+		ret.Meta = meta
+		return ret, nil
 	}
 	return goSdkTool, goSdkHandler, nil
 }
 
+// targetClient returns the client of the target with the credentials of the tool call
+func (s *Server) targetClient(ctx context.Context, target string) (api.KubernetesClient, error) {
+	k, err := s.p.GetDerivedKubernetes(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// callTool calls the handler of the tool, and converts its result to the requested output
+func (s *Server) callTool(tool api.ServerTool, params api.ToolHandlerParams, request *mcp.CallToolRequest) (*api.ToolCallResult, error) {
+	result, err := tool.Handler(params)
+	if err != nil {
+		return nil, err
+	}
+	if result.Error == nil {
+		// the header precedes the YAML and text content, it's only kept apart from the JSON content
+		if params.ListOutput != output.Json {
+			result.Content, result.Header = result.Header+result.Content, ""
+		}
+		result.Header = s.configuration.Redact(result.Header)
+		result.Content = s.configuration.Redact(result.Content)
+		reduced, content := output.FitBudget(result.Content, s.configuration.ResultBudget.GetMaxBytes())
+		result.Content = content
+		addResultHeader(result, reduced, params.ListOutput)
+	}
+	if result.Error == nil && isMutatingCall(tool, params.GetArguments()) {
+		// the cached results may be outdated by the change
+		s.resultCache.clear()
+		s.fireEvent(hooks.Event{
+			Event:     config.EventMutatingToolCall,
+			SessionID: sessionID(request),
+			Tool:      tool.Tool.Name,
+			Target:    params.Target,
+		})
+	}
+	return result, nil
+}
+
+// addResultHeader adds the comment lines to the header of the JSON result, or before the content of the other results
+func addResultHeader(result *api.ToolCallResult, header string, o output.Output) {
+	if o == output.Json {
+		result.Header = header + result.Header
+	} else {
+		result.Content = header + result.Content
+	}
+}
+
 type ToolCallRequest struct {
 	Name      string
 	arguments map[string]any
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"flag"
 	"path/filepath"
 	"runtime"
@@ -89,6 +90,64 @@ func (s *HelmSuite) TestHelmInstall() {
 	})
 }
 
+func (s *HelmSuite) TestHelmInstallName() {
+	_, file, _, _ := runtime.Caller(0)
+	chartPath := filepath.Join(filepath.Dir(file), "testdata", "helm-chart-no-op")
+	s.InitMcpClient()
+	s.Run("helm_install(chart=helm-chart-no-op, name=Invalid_Name)", func() {
+		toolResult, err := s.CallTool("helm_install", map[string]interface{}{
+			"chart": chartPath,
+			"name":  "Invalid_Name",
+		})
+		s.Run("has error", func() {
+			s.Nilf(err, "call tool should not return error object")
+			s.Truef(toolResult.IsError, "call tool should fail")
+		})
+		s.Run("describes the invalid name", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text,
+				`failed to install helm chart, invalid release name "Invalid_Name": a lowercase RFC 1123 subdomain`)
+		})
+		s.Run("returns structured error with INVALID_ARGUMENT code", func() {
+			structuredError, ok := toolResult.StructuredContent.(map[string]any)["error"].(map[string]any)
+			s.Require().Truef(ok, "expected structured error, got %v", toolResult.StructuredContent)
+			s.Equal("INVALID_ARGUMENT", structuredError["code"])
+		})
+	})
+	s.Run("helm_install(chart=helm-chart-no-op, name=release, generate_name=true)", func() {
+		toolResult, _ := s.CallTool("helm_install", map[string]interface{}{
+			"chart":         chartPath,
+			"name":          "release",
+			"generate_name": true,
+		})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to install helm chart, name and generate_name can't be provided together", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("helm_install(chart=helm-chart-no-op, generate_name=false)", func() {
+		toolResult, _ := s.CallTool("helm_install", map[string]interface{}{
+			"chart":         chartPath,
+			"generate_name": false,
+		})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to install helm chart, missing argument name (required if generate_name is false)", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("helm_install(chart=helm-chart-no-op, name=named-release)", func() {
+		toolResult, err := s.CallTool("helm_install", map[string]interface{}{
+			"chart": chartPath,
+			"name":  "named-release",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("installs the named release", func() {
+			var decoded []map[string]interface{}
+			s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded))
+			s.Require().Len(decoded, 1)
+			s.Equal("named-release", decoded[0]["name"])
+		})
+	})
+}
+
 func (s *HelmSuite) TestHelmInstallDenied() {
 	s.Require().NoError(toml.Unmarshal([]byte(`
 		denied_resources = [ { version = "v1", kind = "Secret" } ]
@@ -111,6 +170,11 @@ func (s *HelmSuite) TestHelmInstallDenied() {
 			expectedMessage := ": resource not allowed: /v1, Kind=Secret"
 			s.Truef(strings.HasSuffix(msg, expectedMessage), "expected descriptive error '%s', got %v", expectedMessage, msg)
 		})
+		s.Run("returns structured error with RESOURCE_DENIED code", func() {
+			structuredError, ok := toolResult.StructuredContent.(map[string]any)["error"].(map[string]any)
+			s.Require().Truef(ok, "expected structured error, got %v", toolResult.StructuredContent)
+			s.Equal("RESOURCE_DENIED", structuredError["code"])
+		})
 	})
 }
 
@@ -328,6 +392,207 @@ func (s *HelmSuite) TestHelmUninstallDenied() {
 	})
 }
 
+func (s *HelmSuite) TestHelmRollback() {
+	kc := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	manifest := func(value string) string {
+		return "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config-map-to-roll-back\n  namespace: default\ndata:\n  key: " + value + "\n"
+	}
+	createHelmRelease(s.T().Context(), s.T(), kc, "release-to-roll-back", 1, "superseded", manifest("v1"), nil)
+	createHelmRelease(s.T().Context(), s.T(), kc, "release-to-roll-back", 2, "deployed", manifest("v2"), nil)
+	_, err := kc.CoreV1().ConfigMaps("default").Create(s.T().Context(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config-map-to-roll-back"},
+		Data:       map[string]string{"key": "hotfix"},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = kc.CoreV1().ConfigMaps("default").Delete(context.Background(), "config-map-to-roll-back", metav1.DeleteOptions{})
+	})
+	s.InitMcpClient()
+	s.Run("helm_rollback(name=release-to-roll-back) with resources modified outside Helm", func() {
+		toolResult, err := s.CallTool("helm_rollback", map[string]interface{}{
+			"name": "release-to-roll-back",
+		})
+		s.Run("has error", func() {
+			s.Nilf(err, "call tool should not return error object")
+			s.Truef(toolResult.IsError, "call tool should fail")
+		})
+		s.Run("describes the modified resources", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text,
+				"1 resources of release release-to-roll-back were modified outside Helm and would be overwritten by the rollback to revision 1")
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text,
+				"- ConfigMap default/config-map-to-roll-back: modified outside Helm (data.key)")
+		})
+		s.Run("does not roll back", func() {
+			cm, err := kc.CoreV1().ConfigMaps("default").Get(s.T().Context(), "config-map-to-roll-back", metav1.GetOptions{})
+			s.Require().NoError(err)
+			s.Equal("hotfix", cm.Data["key"])
+		})
+	})
+	s.Run("helm_rollback(name=release-to-roll-back, force=true) with resources modified outside Helm", func() {
+		toolResult, err := s.CallTool("helm_rollback", map[string]interface{}{
+			"name":  "release-to-roll-back",
+			"force": true,
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		var decoded map[string]interface{}
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded))
+		s.Run("returns the new revision", func() {
+			s.Require().IsType(map[string]interface{}{}, decoded["release"])
+			s.EqualValues(3, decoded["release"].(map[string]interface{})["revision"])
+		})
+		s.Run("returns the overwritten resources", func() {
+			s.Len(decoded["overwrittenDrift"], 1)
+		})
+		s.Run("rolls back the resources", func() {
+			cm, err := kc.CoreV1().ConfigMaps("default").Get(s.T().Context(), "config-map-to-roll-back", metav1.GetOptions{})
+			s.Require().NoError(err)
+			s.Equal("v1", cm.Data["key"])
+		})
+	})
+}
+
+func (s *HelmSuite) TestHelmExport() {
+	kc := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	createHelmRelease(s.T().Context(), s.T(), kc, "release-to-export", 1, "deployed",
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config-map-to-export\n  labels:\n    helm.sh/chart: app-1.0.0\ndata:\n  key: value\n",
+		map[string]interface{}{
+			"chart": map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "app", "version": "1.0.0"},
+				"values":   map[string]interface{}{"replicas": 1},
+			},
+			"config": map[string]interface{}{"replicas": 3},
+		})
+	s.InitMcpClient()
+	s.Run("helm_export(name=release-to-export)", func() {
+		toolResult, err := s.CallTool("helm_export", map[string]interface{}{
+			"name": "release-to-export",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns the user-supplied values", func() {
+			s.Contains(text, "# Helm release default/release-to-export revision 1 (chart app 1.0.0) exported in values format\n")
+			s.Contains(text, "---\n# File: values.yaml\nreplicas: 3\n")
+		})
+		s.Run("returns the chart reference", func() {
+			s.Contains(text, "---\n# File: chart.yaml\nchart: app\n")
+		})
+	})
+	s.Run("helm_export(name=release-to-export, format=kustomize)", func() {
+		toolResult, err := s.CallTool("helm_export", map[string]interface{}{
+			"name":   "release-to-export",
+			"format": "kustomize",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns the kustomize base", func() {
+			s.Contains(text, "namespace: default\nresources:\n- configmap-config-map-to-export.yaml\n")
+			s.Contains(text, "---\n# File: configmap-config-map-to-export.yaml\napiVersion: v1\ndata:\n  key: value\nkind: ConfigMap\n")
+			s.NotContains(text, "helm.sh/chart")
+		})
+	})
+	s.Run("helm_export(name=release-to-export, format=flux)", func() {
+		toolResult, err := s.CallTool("helm_export", map[string]interface{}{
+			"name":   "release-to-export",
+			"format": "flux",
+		})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to export helm release, unsupported format flux")
+	})
+	s.Run("helm_export(name=inexistent-release)", func() {
+		toolResult, err := s.CallTool("helm_export", map[string]interface{}{
+			"name": "inexistent-release",
+		})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to export helm release 'inexistent-release'")
+	})
+}
+
+// createHelmRelease stores a revision of the release in the default namespace, fields override the defaults of the release
+func (s *HelmSuite) TestHelmValuesPreview() {
+	kc := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	createHelmRelease(s.T().Context(), s.T(), kc, "release-to-preview", 1, "deployed", "", map[string]interface{}{
+		"chart": map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "app", "version": "1.0.0"},
+			"values":   map[string]interface{}{"replicas": 1, "image": map[string]interface{}{"repository": "app", "tag": "1.0"}},
+		},
+		"config": map[string]interface{}{"replicas": 3},
+	})
+	s.InitMcpClient()
+	s.Run("helm_values_preview(name=release-to-preview, values={image: {tag: 1.1}})", func() {
+		toolResult, err := s.CallTool("helm_values_preview", map[string]interface{}{
+			"name":   "release-to-preview",
+			"values": map[string]interface{}{"image": map[string]interface{}{"tag": "1.1"}},
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns the computed values", func() {
+			s.Contains(text, "# Computed values of release release-to-preview (chart defaults + values of revision 1 + overrides)\n"+
+				"image:\n  repository: app\n  tag: \"1.1\"\nreplicas: 3\n")
+		})
+		s.Run("returns the diff against the deployed values", func() {
+			s.Contains(text, "# Changes to the deployed values (unified diff)\n")
+			s.Contains(text, "-  tag: \"1.0\"\n+  tag: \"1.1\"\n")
+		})
+	})
+	s.Run("helm_values_preview(name=release-to-preview, reset_values=true)", func() {
+		toolResult, err := s.CallTool("helm_values_preview", map[string]interface{}{
+			"name":         "release-to-preview",
+			"reset_values": true,
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("returns the chart defaults", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "-replicas: 3\n+replicas: 1\n")
+		})
+	})
+}
+
+func createHelmRelease(ctx context.Context, t *testing.T, kc *kubernetes.Clientset, name string, version int, status, manifest string, fields map[string]interface{}) {
+	rel := map[string]interface{}{
+		"name":      name,
+		"namespace": "default",
+		"version":   version,
+		"info":      map[string]interface{}{"status": status},
+		"chart":     map[string]interface{}{"metadata": map[string]interface{}{"name": name, "version": "1.0.0"}},
+		"manifest":  manifest,
+	}
+	for key, value := range fields {
+		rel[key] = value
+	}
+	data, err := json.Marshal(rel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = kc.CoreV1().Secrets("default").Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "sh.helm.release.v1." + name + ".v" + strconv.Itoa(version),
+			Labels: map[string]string{
+				"owner": "helm", "name": name, "version": strconv.Itoa(version), "status": status,
+			},
+		},
+		Data: map[string][]byte{"release": []byte(base64.StdEncoding.EncodeToString(data))},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func clearHelmReleases(ctx context.Context, kc *kubernetes.Clientset) {
 	secrets, _ := kc.CoreV1().Secrets("default").List(ctx, metav1.ListOptions{})
 	for _, secret := range secrets.Items {
@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/hooks"
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+// fireEvent notifies the configured event hooks (if any) about a server event
+func (s *Server) fireEvent(event hooks.Event) {
+	if len(s.configuration.EventHooks) == 0 {
+		return
+	}
+	event.Server = version.BinaryName + "/" + version.Version
+	s.hooks.Fire(s.configuration.EventHooks, event)
+}
+
+// sessionInitialized fires the session_start event and waits (in the background) for the session to end,
+// then releases the background operations, the usage, and the workspace of the session
+func (s *Server) sessionInitialized(_ context.Context, req *mcp.InitializedRequest) {
+	if req == nil || req.Session == nil {
+		return
+	}
+	session := req.Session
+	s.fireEvent(hooks.Event{Event: config.EventSessionStart, SessionID: session.ID()})
+	go func() {
+		_ = session.Wait()
+		s.operations.endSession(session.ID())
+		s.toolCalls.endSession(session.ID())
+		s.budget.EndSession(session.ID())
+		s.workspace.EndSession(session.ID())
+		s.fireEvent(hooks.Event{Event: config.EventSessionEnd, SessionID: session.ID()})
+	}()
+}
+
+// sessionID returns the ID of the MCP session that performed the tool call, if any
+func sessionID(req *mcp.CallToolRequest) string {
+	if req != nil && req.Session != nil {
+		return req.Session.ID()
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/hooks"
+)
+
+type EventHooksSuite struct {
+	BaseMcpSuite
+	hookServer *httptest.Server
+	mu         sync.Mutex
+	events     []hooks.Event
+}
+
+func (s *EventHooksSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.events = nil
+	s.hookServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event hooks.Event
+		_ = json.NewDecoder(r.Body).Decode(&event)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.events = append(s.events, event)
+	}))
+	s.Cfg.EventHooks = []config.EventHookConfig{{URL: s.hookServer.URL}}
+}
+
+func (s *EventHooksSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	s.hookServer.Close()
+}
+
+func (s *EventHooksSuite) hasEvent(matches func(hooks.Event) bool) func() bool {
+	return func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return slices.ContainsFunc(s.events, matches)
+	}
+}
+
+func (s *EventHooksSuite) TestSessionStart() {
+	s.InitMcpClient()
+	s.Eventually(s.hasEvent(func(e hooks.Event) bool {
+		return e.Event == config.EventSessionStart && e.SessionID != ""
+	}), 5*time.Second, 50*time.Millisecond, "expected session_start event")
+}
+
+func (s *EventHooksSuite) TestMutatingToolCall() {
+	s.InitMcpClient()
+	s.Run("read-only tool call does not fire event", func() {
+		toolResult, err := s.CallTool("namespaces_list", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError)
+		s.Never(s.hasEvent(func(e hooks.Event) bool {
+			return e.Event == config.EventMutatingToolCall
+		}), 500*time.Millisecond, 50*time.Millisecond, "expected no mutating_tool_call event")
+	})
+	s.Run("mutating tool call fires event", func() {
+		toolResult, err := s.CallTool("resources_create_or_update", map[string]interface{}{
+			"resource": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a-cm-created-with-hooks\n  namespace: default\n",
+		})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError)
+		s.Eventually(s.hasEvent(func(e hooks.Event) bool {
+			return e.Event == config.EventMutatingToolCall && e.Tool == "resources_create_or_update"
+		}), 5*time.Second, 50*time.Millisecond, "expected mutating_tool_call event")
+	})
+}
+
+func TestEventHooks(t *testing.T) {
+	suite.Run(t, new(EventHooksSuite))
+}
@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type KedaSuite struct {
+	BaseMcpSuite
+	mockServer   *test.MockServer
+	scaledObject unstructured.Unstructured
+}
+
+func (s *KedaSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.scaledObject = unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "keda.sh/v1alpha1",
+		"kind":       "ScaledObject",
+		"metadata":   map[string]any{"name": "worker", "namespace": "default"},
+		"spec": map[string]any{
+			"scaleTargetRef": map[string]any{"name": "worker"},
+			"triggers":       []any{map[string]any{"type": "kafka", "metadata": map[string]any{"topic": "orders", "lagThreshold": "50"}}},
+		},
+		"status": map[string]any{
+			"externalMetricNames": []any{"s0-kafka-orders"},
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "True"},
+				map[string]any{"type": "Active", "status": "True"},
+			},
+		},
+	}}
+	discovery := test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "keda.sh/v1alpha1",
+		APIResources: []metav1.APIResource{{Name: "scaledobjects", Kind: "ScaledObject", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}},
+	}, metav1.APIResourceList{
+		GroupVersion: "autoscaling/v2",
+		APIResources: []metav1.APIResource{{Name: "horizontalpodautoscalers", Kind: "HorizontalPodAutoscaler", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}},
+	})
+	discovery.APIResourceLists[0].APIResources = append(discovery.APIResourceLists[0].APIResources,
+		metav1.APIResource{Name: "events", Kind: "Event", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}})
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(discovery)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/keda.sh/v1alpha1/scaledobjects":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "keda.sh/v1alpha1", "kind": "ScaledObjectList"},
+				Items:  []unstructured.Unstructured{s.scaledObject},
+			})
+		case "/apis/keda.sh/v1alpha1/namespaces/default/scaledobjects/worker":
+			test.WriteObject(w, &s.scaledObject)
+		case "/apis/autoscaling/v2/horizontalpodautoscalers":
+			test.WriteObject(w, &unstructured.UnstructuredList{Object: map[string]any{"apiVersion": "autoscaling/v2", "kind": "HorizontalPodAutoscalerList"}})
+		case "/apis/autoscaling/v2/namespaces/default/horizontalpodautoscalers/keda-hpa-worker":
+			test.WriteObject(w, &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "autoscaling/v2",
+				"kind":       "HorizontalPodAutoscaler",
+				"metadata":   map[string]any{"name": "keda-hpa-worker", "namespace": "default"},
+				"status": map[string]any{
+					"currentReplicas": int64(2),
+					"desiredReplicas": int64(4),
+					"currentMetrics": []any{map[string]any{"type": "External", "external": map[string]any{
+						"metric":  map[string]any{"name": "s0-kafka-orders"},
+						"current": map[string]any{"averageValue": "120"},
+					}}},
+				},
+			}})
+		case "/api/v1/namespaces/default/events":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "v1", "kind": "EventList"},
+				Items: []unstructured.Unstructured{{Object: map[string]any{
+					"apiVersion":     "v1",
+					"kind":           "Event",
+					"metadata":       map[string]any{"name": "keda-hpa-worker.1", "namespace": "default"},
+					"involvedObject": map[string]any{"kind": "HorizontalPodAutoscaler", "name": "keda-hpa-worker"},
+					"type":           "Normal",
+					"reason":         "SuccessfulRescale",
+					"message":        "New size: 4; reason: external metric s0-kafka-orders above target",
+					"lastTimestamp":  "2025-01-01T14:05:00Z",
+				}}},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"keda"}
+}
+
+func (s *KedaSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *KedaSuite) TestScaledObjectsList() {
+	s.InitMcpClient()
+	s.Run("keda_scaled_objects_list skips the kinds not installed", func() {
+		toolResult, err := s.CallTool("keda_scaled_objects_list", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of resources", func() {
+			s.Truef(strings.HasPrefix(text, "# 1 KEDA resource(s) found, 0 not ready, 1 active (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the triggers", func() {
+			s.Contains(text, "metricName: s0-kafka-orders")
+			s.Contains(text, "target: Deployment/worker")
+		})
+	})
+	s.Run("keda_scaled_objects_list(kind=ScaledJob) fails when not installed", func() {
+		toolResult, err := s.CallTool("keda_scaled_objects_list", map[string]interface{}{"kind": "ScaledJob"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to list KEDA resources:")
+	})
+}
+
+func (s *KedaSuite) TestScaledObjectGet() {
+	s.InitMcpClient()
+	s.Run("keda_scaled_object_get(name=worker)", func() {
+		toolResult, err := s.CallTool("keda_scaled_object_get", map[string]interface{}{"namespace": "default", "name": "worker"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of scaling events", func() {
+			s.Truef(strings.HasPrefix(text, "# ScaledObject default/worker with 1 recent scaling event(s) (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the current metric value and the replicas", func() {
+			s.Contains(text, "current: 120 (average)")
+			s.Contains(text, "desiredReplicas: 4")
+			s.Contains(text, "lagThreshold: \"50\"")
+		})
+		s.Run("returns the scaling decisions", func() {
+			s.Contains(text, "reason: SuccessfulRescale")
+		})
+	})
+	s.Run("keda_scaled_object_get(missing name)", func() {
+		toolResult, err := s.CallTool("keda_scaled_object_get", map[string]interface{}{"namespace": "default"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to get KEDA resource:")
+	})
+}
+
+func TestKeda(t *testing.T) {
+	suite.Run(t, new(KedaSuite))
+}
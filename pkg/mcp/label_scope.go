@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+// labelScopeMiddleware scopes the requests of the tool call to the label selector of the first label scope matching the
+// identity of the session (see config.LabelScopeConfig).
+// This middleware should be added after authHeaderPropagationMiddleware, the identity is the subject of its token.
+func (s *Server) labelScopeMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if scopes := s.configuration.LabelScopes; len(scopes) > 0 {
+			ctx = internalk8s.WithLabelScope(ctx, config.LabelScopeFor(scopes, tokenSubject(ctx)))
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// tokenSubject returns the subject (sub claim) of the bearer token of the context, empty if there is none or the token
+// isn't a JWT. The signature of the token isn't verified here, it is with require_oauth.
+func tokenSubject(ctx context.Context) string {
+	authorization, _ := ctx.Value(internalk8s.OAuthAuthorizationHeader).(string)
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return ""
+	}
+	parsed, err := jwt.ParseSigned(token, []jose.SignatureAlgorithm{
+		jose.EdDSA, jose.HS256, jose.HS384, jose.HS512, jose.RS256, jose.RS384, jose.RS512,
+		jose.ES256, jose.ES384, jose.ES512, jose.PS256, jose.PS384, jose.PS512,
+	})
+	if err != nil {
+		return ""
+	}
+	claims := jwt.Claims{}
+	if err = parsed.UnsafeClaimsWithoutVerification(&claims); err != nil {
+		return ""
+	}
+	return claims.Subject
+}
@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type LabelScopeSuite struct {
+	BaseMcpSuite
+	mockServer     *test.MockServer
+	labelSelectors []string
+}
+
+func (s *LabelScopeSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.LabelScopes = []config.LabelScopeConfig{{LabelSelector: "team=payments"}}
+	s.labelSelectors = nil
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v1/namespaces/default/pods" && req.URL.Path != "/api/v1/namespaces/default/pods/checkout" {
+			return
+		}
+		labelSelector := req.URL.Query().Get("labelSelector")
+		s.labelSelectors = append(s.labelSelectors, labelSelector)
+		pod := corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default", Labels: map[string]string{"team": "payments"}},
+		}
+		if req.URL.Path == "/api/v1/namespaces/default/pods/checkout" {
+			test.WriteObject(w, &pod)
+			return
+		}
+		list := &corev1.PodList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"}}
+		if labelSelector == "team=payments" && req.URL.Query().Get("fieldSelector") != "metadata.name=ledger" {
+			list.Items = append(list.Items, pod)
+		}
+		// the existence check of the pod out of the scope
+		if labelSelector == "" && req.URL.Query().Get("fieldSelector") == "metadata.name=ledger" {
+			list.Items = append(list.Items, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "ledger", Namespace: "default", Labels: map[string]string{"team": "billing"}}})
+		}
+		test.WriteObject(w, list)
+	}))
+}
+
+func (s *LabelScopeSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *LabelScopeSuite) TestLabelScope() {
+	s.InitMcpClient()
+	s.Run("pods_list_in_namespace lists the pods in the scope", func() {
+		toolResult, err := s.CallTool("pods_list_in_namespace", map[string]any{"namespace": "default"})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal([]string{"team=payments"}, s.labelSelectors)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "checkout")
+	})
+	s.Run("pods_list_in_namespace(labelSelector) narrows the scope down", func() {
+		s.labelSelectors = nil
+		_, err := s.CallTool("pods_list_in_namespace", map[string]any{"namespace": "default", "labelSelector": "app=checkout"})
+		s.Require().NoError(err)
+		s.Equal([]string{"app=checkout,team=payments"}, s.labelSelectors)
+	})
+	s.Run("pods_get returns the pod in the scope", func() {
+		toolResult, err := s.CallTool("pods_get", map[string]any{"namespace": "default", "name": "checkout"})
+		s.Require().NoError(err)
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	})
+	s.Run("pods_delete forbids the pod out of the scope", func() {
+		toolResult, err := s.CallTool("pods_delete", map[string]any{"namespace": "default", "name": "ledger"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, `pods "ledger" is forbidden`)
+	})
+	s.Run("pods_get forbids the pod out of the scope", func() {
+		toolResult, err := s.CallTool("pods_get", map[string]any{"namespace": "default", "name": "ledger"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, `pods "ledger" is forbidden: the object is out of the label scope team=payments of the session`)
+	})
+}
+
+func TestLabelScope(t *testing.T) {
+	suite.Run(t, new(LabelScopeSuite))
+}
@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"sigs.k8s.io/yaml"
+)
+
+type ListDetailSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *ListDetailSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.Cfg.ListDetail = ""
+	s.mockServer = test.NewMockServer()
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	pod := `{
+		"apiVersion": "v1", "kind": "Pod",
+		"metadata": { "name": "pod-1", "namespace": "default", "labels": { "app": "web" }, "annotations": { "description": "verbose" } },
+		"spec": { "nodeName": "node-1", "containers": [{ "name": "app", "image": "nginx" }] },
+		"status": { "phase": "Running", "containerStatuses": [{ "name": "app", "ready": true, "restartCount": 2 }] }
+	}`
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/default/pods":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"apiVersion": "v1", "kind": "PodList", "items": [` + pod + `]}`))
+		case "/api/v1/namespaces/default/pods/pod-1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(pod))
+		case "/api/v1/namespaces/default/pods/missing":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"apiVersion": "v1", "kind": "Status", "status": "Failure", "reason": "NotFound", "code": 404}`))
+		}
+	}))
+}
+
+func (s *ListDetailSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ListDetailSuite) TestListSummary() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("pods_list_in_namespace", map[string]interface{}{"namespace": "default"})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Require().False(toolResult.IsError, "call tool should succeed")
+	var decoded []map[string]any
+	s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded))
+	s.Require().Len(decoded, 1)
+	s.Run("returns the summary of the objects", func() {
+		s.Equal(map[string]any{"name": "pod-1", "namespace": "default", "labels": map[string]any{"app": "web"}}, decoded[0]["metadata"])
+		s.Equal(map[string]any{"phase": "Running", "ready": "1/1", "restarts": float64(2), "node": "node-1"}, decoded[0]["status"])
+		s.NotContains(decoded[0], "spec")
+	})
+	s.Run("returns the URI of the complete objects in the target of the tool call", func() {
+		s.Equal("k8s:///core/v1/Pod/default/pod-1?target=fake-context", decoded[0]["uri"])
+	})
+}
+
+func (s *ListDetailSuite) TestListFull() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("pods_list_in_namespace", map[string]interface{}{"namespace": "default", "detail": "full"})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Require().False(toolResult.IsError, "call tool should succeed")
+	s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "image: nginx", "expected the complete objects")
+	s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "uri:")
+}
+
+func (s *ListDetailSuite) TestListFullWithTableOutput() {
+	s.Cfg.ListOutput = "table"
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("pods_list_in_namespace", map[string]interface{}{"namespace": "default", "detail": "full"})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Require().False(toolResult.IsError, "call tool should succeed")
+	s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "image: nginx", "expected the complete objects as YAML")
+}
+
+func (s *ListDetailSuite) TestReadObjectResource() {
+	s.InitMcpClient()
+	s.Run("lists the object resource template", func() {
+		templates, err := s.ListResourceTemplates(s.T().Context(), mcp.ListResourceTemplatesRequest{})
+		s.Require().NoError(err)
+		s.Require().Len(templates.ResourceTemplates, 1)
+		s.Equal("k8s:///{group}/{version}/{kind}/{namespace}/{name}{?target}", templates.ResourceTemplates[0].URITemplate.Raw())
+	})
+	s.Run("reads the complete object", func() {
+		result, err := s.ReadResource(s.T().Context(), mcp.ReadResourceRequest{Params: mcp.ReadResourceParams{URI: "k8s:///core/v1/Pod/default/pod-1"}})
+		s.Require().NoError(err)
+		s.Require().Len(result.Contents, 1)
+		contents, ok := result.Contents[0].(mcp.TextResourceContents)
+		s.Require().True(ok, "expected text contents")
+		s.Equal("application/yaml", contents.MIMEType)
+		s.Contains(contents.Text, "image: nginx")
+		s.Contains(contents.Text, "description: verbose")
+	})
+	s.Run("reads the complete object of the target", func() {
+		result, err := s.ReadResource(s.T().Context(), mcp.ReadResourceRequest{Params: mcp.ReadResourceParams{URI: "k8s:///core/v1/Pod/default/pod-1?target=fake-context"}})
+		s.Require().NoError(err)
+		s.Len(result.Contents, 1)
+	})
+	s.Run("returns an error for missing objects", func() {
+		_, err := s.ReadResource(s.T().Context(), mcp.ReadResourceRequest{Params: mcp.ReadResourceParams{URI: "k8s:///core/v1/Pod/default/missing"}})
+		s.Error(err)
+	})
+	s.Run("returns an error for invalid URIs", func() {
+		_, err := s.ReadResource(s.T().Context(), mcp.ReadResourceRequest{Params: mcp.ReadResourceParams{URI: "k8s:///core/v1/Pod"}})
+		s.Error(err)
+	})
+}
+
+func TestListDetail(t *testing.T) {
+	suite.Run(t, new(ListDetailSuite))
+}
@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+type LocaleSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *LocaleSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/shop/pods/web-1":
+			test.WriteObject(w, &v1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "app"}}},
+				Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{
+					Name:                 "app",
+					RestartCount:         7,
+					State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+				}}},
+			})
+		case "/api/v1/namespaces/shop/events":
+			test.WriteObject(w, &v1.EventList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"}})
+		case "/api/v1/namespaces/shop/pods/web-1/log":
+			_, _ = w.Write([]byte("panic: boom"))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *LocaleSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *LocaleSuite) TestLocaleParameter() {
+	s.InitMcpClient()
+	s.Run("diagnose_pod(locale=ja)", func() {
+		toolResult, err := s.CallTool("diagnose_pod", map[string]any{"namespace": "shop", "name": "web-1", "locale": "ja"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, "call tool failed")
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("renders the header in the locale", func() {
+			s.Contains(text, "# Pod shop/web-1 で 1 件の問題が見つかりました (YAML形式):\n")
+		})
+		s.Run("renders the findings in the locale", func() {
+			s.Contains(text, "message: コンテナがクラッシュループしています (再起動 7 回)、前回は終了コード 1 で終了しました (Error)")
+		})
+		s.Run("leaves the reasons untouched", func() {
+			s.Contains(text, "reason: CrashLoopBackOff")
+		})
+	})
+	s.Run("diagnose_pod without locale", func() {
+		toolResult, err := s.CallTool("diagnose_pod", map[string]any{"namespace": "shop", "name": "web-1"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, "call tool failed")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "message: container is crash looping (7 restarts), last terminated")
+	})
+	s.Run("diagnose_pod(locale=fr)", func() {
+		toolResult, err := s.CallTool("diagnose_pod", map[string]any{"namespace": "shop", "name": "web-1", "locale": "fr"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("invalid locale: fr", toolResult.Content[0].(mcp.TextContent).Text)
+		s.Equal(string(api.ErrorCodeInvalidArgument), toolResult.StructuredContent.(map[string]any)["error"].(map[string]any)["code"])
+	})
+}
+
+func (s *LocaleSuite) TestConfiguredLocale() {
+	s.Cfg.Locale = "de"
+	s.InitMcpClient()
+	s.Run("diagnose_pod renders the findings in the configured locale", func() {
+		toolResult, err := s.CallTool("diagnose_pod", map[string]any{"namespace": "shop", "name": "web-1"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, "call tool failed")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# 1 Problem(e) für Pod shop/web-1 gefunden (YAML-Format):\n")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "message: Container stürzt wiederholt ab (7 Neustarts), zuletzt mit Exit-Code 1")
+	})
+	s.Run("diagnose_pod(locale=en) overrides the configured locale", func() {
+		toolResult, err := s.CallTool("diagnose_pod", map[string]any{"namespace": "shop", "name": "web-1", "locale": "en"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, "call tool failed")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# 1 issue(s) found for Pod shop/web-1 (YAML format):\n")
+	})
+}
+
+func (s *LocaleSuite) TestConfiguredLocaleJsonOutput() {
+	s.Cfg.Locale = "de"
+	s.Cfg.ListOutput = "json"
+	s.InitMcpClient()
+	s.Run("diagnose_pod states the JSON format in the translated header", func() {
+		toolResult, err := s.CallTool("diagnose_pod", map[string]any{"namespace": "shop", "name": "web-1"})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, "call tool failed")
+		s.Require().Len(toolResult.Content, 2)
+		s.Equal("# 1 Problem(e) für Pod shop/web-1 gefunden (JSON-Format):\n", toolResult.Content[1].(mcp.TextContent).Text)
+	})
+}
+
+func TestLocale(t *testing.T) {
+	suite.Run(t, new(LocaleSuite))
+}
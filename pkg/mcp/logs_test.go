@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type LogsSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *LogsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *LogsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *LogsSuite) TestLogsAnalyze() {
+	var labelSelector string
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/default/pods":
+			labelSelector = req.URL.Query().Get("labelSelector")
+			test.WriteObject(w, &v1.PodList{Items: []v1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default"}, Spec: v1.PodSpec{Containers: []v1.Container{{Name: "api"}}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "api-2", Namespace: "default"}, Spec: v1.PodSpec{Containers: []v1.Container{{Name: "api"}}}},
+			}})
+		case "/api/v1/namespaces/default/pods/api-1/log":
+			_, _ = w.Write([]byte("" +
+				"2025-01-01T10:00:00Z GET /users/42 200 12ms\n" +
+				"2025-01-01T10:00:01Z ERROR failed to connect to 10.0.0.12:5432\n"))
+		case "/api/v1/namespaces/default/pods/api-2/log":
+			_, _ = w.Write([]byte("" +
+				"2025-01-01T10:00:02Z ERROR failed to connect to 10.0.0.13:5432\n" +
+				"2025-01-01T10:00:03Z GET /users/7 200 3ms\n"))
+		}
+	}))
+	s.InitMcpClient()
+	s.Run("logs_analyze(label_selector=app=api)", func() {
+		toolResult, err := s.CallTool("logs_analyze", map[string]interface{}{"label_selector": "app=api"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("lists the pods matching the selector", func() {
+			s.Equal("app=api", labelSelector)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the patterns", func() {
+			s.Truef(strings.HasPrefix(text, "# 4 line(s) clustered into 2 pattern(s), 1 error pattern(s), showing the top 2 (YAML format):\n"), "unexpected result %v", text)
+		})
+		var analysis map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &analysis))
+		s.Run("returns the error patterns first", func() {
+			patterns := analysis["patterns"].([]any)
+			s.Require().Len(patterns, 2)
+			s.Equal("ERROR failed to connect to <*>:<*>", patterns[0].(map[string]any)["pattern"])
+			s.Equal(float64(2), patterns[0].(map[string]any)["count"])
+			s.Equal([]any{"api-1", "api-2"}, patterns[0].(map[string]any)["pods"])
+			s.Equal("2025-01-01T10:00:01Z", patterns[0].(map[string]any)["firstSeen"])
+			s.Equal("2025-01-01T10:00:02Z", patterns[0].(map[string]any)["lastSeen"])
+		})
+	})
+	s.Run("logs_analyze(label_selector=app=api, limit=1)", func() {
+		toolResult, err := s.CallTool("logs_analyze", map[string]interface{}{"label_selector": "app=api", "limit": 1})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# 4 line(s) clustered into 2 pattern(s), 1 error pattern(s), showing the top 1 (YAML format):\n"),
+			"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("logs_analyze(label_selector=app=api, since=invalid)", func() {
+		toolResult, _ := s.CallTool("logs_analyze", map[string]interface{}{"label_selector": "app=api", "since": "invalid"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("invalid since invalid, expected a positive duration (e.g. 10m, 1h)", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestLogs(t *testing.T) {
+	suite.Run(t, new(LogsSuite))
+}
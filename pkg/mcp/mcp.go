@@ -9,23 +9,33 @@ import (
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/extension"
+	"github.com/containers/kubernetes-mcp-server/pkg/hooks"
 	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
 	"github.com/containers/kubernetes-mcp-server/pkg/metrics"
 	"github.com/containers/kubernetes-mcp-server/pkg/output"
 	"github.com/containers/kubernetes-mcp-server/pkg/prompts"
+	"github.com/containers/kubernetes-mcp-server/pkg/recording"
+	"github.com/containers/kubernetes-mcp-server/pkg/sessionbudget"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
 	"github.com/containers/kubernetes-mcp-server/pkg/version"
+	"github.com/containers/kubernetes-mcp-server/pkg/workspace"
 )
 
 type Configuration struct {
 	*config.StaticConfig
 	listOutput output.Output
 	toolsets   []api.Toolset
+	// redactor is built from the redaction rules when the server starts and when the configuration reloads, it's only
+	// read by the (concurrent) tool calls
+	redactor *output.Redactor
 }
 
 func (c *Configuration) Toolsets() []api.Toolset {
@@ -33,6 +43,9 @@ func (c *Configuration) Toolsets() []api.Toolset {
 		for _, toolset := range c.StaticConfig.Toolsets {
 			c.toolsets = append(c.toolsets, toolsets.ToolsetFromString(toolset))
 		}
+		for _, ext := range c.StaticConfig.Extensions {
+			c.toolsets = append(c.toolsets, extension.NewToolset(ext, c.StaticConfig))
+		}
 	}
 	return c.toolsets
 }
@@ -44,6 +57,27 @@ func (c *Configuration) ListOutput() output.Output {
 	return c.listOutput
 }
 
+// Redact removes the sensitive data matching the built-in patterns (if enabled) and the configured rules from the content
+func (c *Configuration) Redact(content string) string {
+	if c.Redaction.BuiltinPatterns {
+		content = mcplog.Sanitize(content)
+	}
+	if len(c.Redaction.Rules) == 0 {
+		return content
+	}
+	redactor := c.redactor
+	if redactor == nil {
+		// configuration not loaded by the server, built for this call only
+		var err error
+		if redactor, err = c.Redaction.Redactor(); err != nil {
+			// validated on startup and reload, the invalid rules must not expose the content they're meant to redact
+			klog.Errorf("Failed to apply the redaction rules: %v", err)
+			return output.Redacted
+		}
+	}
+	return redactor.Redact(content)
+}
+
 func (c *Configuration) isToolApplicable(tool api.ServerTool) bool {
 	if c.ReadOnly && !ptr.Deref(tool.Tool.Annotations.ReadOnlyHint, false) {
 		return false
@@ -61,35 +95,61 @@ func (c *Configuration) isToolApplicable(tool api.ServerTool) bool {
 }
 
 type Server struct {
-	configuration  *Configuration
-	server         *mcp.Server
-	enabledTools   []string
-	enabledPrompts []string
-	p              internalk8s.Provider
-	metrics        *metrics.Metrics // Metrics collection system
+	configuration    *Configuration
+	server           *mcp.Server
+	enabledTools     []string
+	enabledPrompts   []string
+	p                internalk8s.Provider
+	reloader         *toolsReloader
+	operations       *operations
+	schedules        *schedules
+	transientSweeper *transientSweeper
+	resultCache      *resultCache
+	toolCalls        *sessionToolCalls
+	budget           *sessionbudget.Budget
+	workspace        *workspace.Workspace
+	metrics          *metrics.Metrics // Metrics collection system
+	hooks            *hooks.Dispatcher
+	recorder         *recording.Recorder
+	replayer         *recording.Replayer
 }
 
 func NewServer(configuration Configuration, targetProvider internalk8s.Provider) (*Server, error) {
 	s := &Server{
 		configuration: &configuration,
-		server: mcp.NewServer(
-			&mcp.Implementation{
-				Name:       version.BinaryName,
-				Title:      version.BinaryName,
-				Version:    version.Version,
-				WebsiteURL: version.WebsiteURL,
+		hooks:         hooks.NewDispatcher(),
+		toolCalls:     newSessionToolCalls(),
+		workspace:     workspace.New(configuration.Workspace.Dir),
+	}
+	s.operations = newOperations(func() config.SessionLimitsConfig { return s.configuration.SessionLimits })
+	s.budget = sessionbudget.New(func(resource string) int64 { return s.configuration.SessionLimits.Limit(resource) })
+	s.schedules = newSchedules(s.callScheduledTool)
+	s.transientSweeper = newTransientSweeper(s.sweepTransientObjects)
+	s.resultCache = newResultCache(func() config.ResultCacheConfig { return s.configuration.ResultCache })
+	s.server = mcp.NewServer(
+		&mcp.Implementation{
+			Name:       version.BinaryName,
+			Title:      version.BinaryName,
+			Version:    version.Version,
+			WebsiteURL: version.WebsiteURL,
+		},
+		&mcp.ServerOptions{
+			Capabilities: &mcp.ServerCapabilities{
+				Resources: nil,
+				Prompts:   &mcp.PromptCapabilities{ListChanged: !configuration.Stateless},
+				Tools:     &mcp.ToolCapabilities{ListChanged: !configuration.Stateless},
+				Logging:   &mcp.LoggingCapabilities{},
 			},
-			&mcp.ServerOptions{
-				Capabilities: &mcp.ServerCapabilities{
-					Resources: nil,
-					Prompts:   &mcp.PromptCapabilities{ListChanged: !configuration.Stateless},
-					Tools:     &mcp.ToolCapabilities{ListChanged: !configuration.Stateless},
-					Logging:   &mcp.LoggingCapabilities{},
-				},
-				Instructions: configuration.ServerInstructions,
-			}),
-		p: targetProvider,
+			Instructions:       configuration.ServerInstructions,
+			InitializedHandler: s.sessionInitialized,
+		})
+	s.p = targetProvider
+	output.SetMaxBytes(configuration.MaxOutputBytes)
+	redactor, err := configuration.Redaction.Redactor()
+	if err != nil {
+		return nil, fmt.Errorf("invalid redaction: %w", err)
 	}
+	s.configuration.redactor = redactor
 
 	// Initialize metrics system
 	metricsInstance, err := metrics.New(metrics.Config{
@@ -103,18 +163,29 @@ func NewServer(configuration Configuration, targetProvider internalk8s.Provider)
 	}
 	s.metrics = metricsInstance
 
+	if err = s.initRecording(); err != nil {
+		return nil, err
+	}
+
 	s.server.AddReceivingMiddleware(sessionInjectionMiddleware)
 	s.server.AddReceivingMiddleware(traceContextPropagationMiddleware)
 	s.server.AddReceivingMiddleware(tracingMiddleware(version.BinaryName + "/mcp"))
 	s.server.AddReceivingMiddleware(authHeaderPropagationMiddleware)
+	s.server.AddReceivingMiddleware(s.labelScopeMiddleware)
 	s.server.AddReceivingMiddleware(userAgentPropagationMiddleware(version.BinaryName, version.Version))
 	s.server.AddReceivingMiddleware(toolCallLoggingMiddleware)
 	s.server.AddReceivingMiddleware(s.metricsMiddleware())
-	err = s.reloadToolsets()
+	s.server.AddReceivingMiddleware(s.recordingMiddleware)
+	s.server.AddReceivingMiddleware(s.sessionLimitsMiddleware)
+	s.reloader = newToolsReloader(s.reloadToolsets)
+	err = s.reloader.run(nil)
 	if err != nil {
 		return nil, err
 	}
-	s.p.WatchTargets(s.reloadToolsets)
+	s.p.WatchTargets(s.reloader.request)
+	s.reloadSchedules(nil)
+	s.transientSweeper.update(s.configuration.TransientCleanup.GetInterval())
+	s.workspace.SetIdleTimeout(s.configuration.Workspace.GetIdleTimeout())
 
 	return s, nil
 }
@@ -145,6 +216,8 @@ func (s *Server) reloadToolsets() error {
 	if err != nil {
 		return err
 	}
+	s.reloadObjectResources()
+	s.schedules.setTools(applicableTools)
 
 	// Reload prompts, and track the newly enabled prompts so that we can diff on reload to figure out which to remove (if any)
 	s.enabledPrompts, err = reloadItems(
@@ -159,7 +232,7 @@ func (s *Server) reloadToolsets() error {
 	}
 
 	// Start new watch
-	s.p.WatchTargets(s.reloadToolsets)
+	s.p.WatchTargets(s.reloader.request)
 	return nil
 }
 
@@ -203,10 +276,16 @@ func (s *Server) collectApplicableTools(targets []string) []api.ServerTool {
 	filter := CompositeFilter(
 		s.configuration.isToolApplicable,
 		ShouldIncludeTargetListTool(s.p.GetTargetParameterName(), targets),
+		ShouldIncludeServedAPITool(s.servedAPIGroups()),
 	)
 	mutator := ComposeMutators(
 		WithTargetParameter(s.p.GetDefaultTarget(), s.p.GetTargetParameterName(), targets),
 		WithTargetListTool(s.p.GetDefaultTarget(), s.p.GetTargetParameterName(), targets),
+		WithServerStatusTool(s),
+		WithOutputParameter(),
+		WithLocaleParameter(),
+		WithAsyncParameter(),
+		WithMaxAgeParameter(s.configuration.ResultCache),
 	)
 
 	tools := make([]api.ServerTool, 0)
@@ -218,9 +297,61 @@ func (s *Server) collectApplicableTools(targets []string) []api.ServerTool {
 			}
 		}
 	}
+	// the schedules tool returns the results of the configured schedules
+	if len(s.enabledSchedules()) > 0 {
+		for _, tool := range s.schedulesTools() {
+			tool = mutator(tool)
+			if filter(tool) {
+				tools = append(tools, tool)
+			}
+		}
+	}
+	// the workspace tools manage the files of the workspace of the session
+	if s.configuration.Workspace.Enabled() {
+		for _, tool := range s.workspaceTools() {
+			tool = mutator(tool)
+			if filter(tool) {
+				tools = append(tools, tool)
+			}
+		}
+	}
+	// the operations tools follow the long-running tool calls run in the background
+	if slices.ContainsFunc(tools, func(tool api.ServerTool) bool { return tool.IsLongRunning() }) {
+		for _, tool := range s.operationsTools() {
+			tool = mutator(tool)
+			if filter(tool) {
+				tools = append(tools, tool)
+			}
+		}
+	}
 	return tools
 }
 
+// servedAPIGroups returns the API groups served by the default target when the API probe is enabled, nil if disabled
+// or if they can't be discovered (e.g. no credentials outside a request with OAuth)
+func (s *Server) servedAPIGroups() []string {
+	if !s.configuration.ProbeAPIs {
+		return nil
+	}
+	k, err := s.p.GetDerivedKubernetes(context.Background(), s.p.GetDefaultTarget())
+	if err != nil {
+		klog.V(1).Infof("Failed to probe the served APIs, registering all the tools: %v", err)
+		return nil
+	}
+	// the APIs may have changed since the previous probe (e.g. the targets were reloaded after a reconnection)
+	k.DiscoveryClient().Invalidate()
+	groups, err := k.DiscoveryClient().ServerGroups()
+	if err != nil {
+		klog.V(1).Infof("Failed to probe the served APIs, registering all the tools: %v", err)
+		return nil
+	}
+	served := make([]string, 0, len(groups.Groups))
+	for _, group := range groups.Groups {
+		served = append(served, group.Name)
+	}
+	return served
+}
+
 // collectApplicablePrompts returns prompts after merging toolset and config prompts
 func (s *Server) collectApplicablePrompts() []api.ServerPrompt {
 	toolsetPrompts := make([]api.ServerPrompt, 0)
@@ -259,23 +390,47 @@ func (s *Server) metricsMiddleware() func(mcp.MethodHandler) mcp.MethodHandler {
 			result, err := next(ctx, method, req)
 			duration := time.Since(start)
 
-			toolName := method
+			toolName, namespace := method, ""
 			if method == "tools/call" {
 				if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok {
 					if toolReq, _ := GoSdkToolCallParamsToToolCallRequest(params); toolReq != nil {
 						toolName = toolReq.Name
+						namespace = toolReq.GetString("namespace", "")
 					}
 				}
 			}
 
 			// Record to all collectors
 			s.metrics.RecordToolCall(ctx, toolName, duration, err)
+			if callToolResult, ok := result.(*mcp.CallToolResult); ok && callToolResult != nil {
+				// the failed calls (e.g. a mistyped or missing namespace) aren't accounted to the namespace they targeted,
+				// only the namespaces the tools actually worked with end up in the metric attributes
+				if callToolResult.IsError || len(validation.IsDNS1123Label(namespace)) > 0 {
+					namespace = ""
+				}
+				bytes := outputBytes(callToolResult)
+				s.metrics.RecordToolOutput(ctx, toolName, namespace, bytes)
+				if session, ok := req.GetSession().(*mcp.ServerSession); ok && session != nil {
+					s.toolCalls.addOutput(session.ID(), bytes)
+				}
+			}
 
 			return result, err
 		}
 	}
 }
 
+// outputBytes returns the size of the text content of the tool call result
+func outputBytes(result *mcp.CallToolResult) int64 {
+	var ret int64
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			ret += int64(len(text.Text))
+		}
+	}
+	return ret
+}
+
 // GetMetrics returns the metrics system for use by the HTTP server.
 func (s *Server) GetMetrics() *metrics.Metrics {
 	return s.metrics
@@ -328,30 +483,89 @@ func (s *Server) GetEnabledPrompts() []string {
 // configuration changes are detected.
 func (s *Server) ReloadConfiguration(newConfig *config.StaticConfig) error {
 	klog.V(1).Info("Reloading MCP server configuration...")
-
-	// Update the configuration
-	s.configuration.StaticConfig = newConfig
-	// Clear cached values so they get recomputed
-	s.configuration.listOutput = nil
-	s.configuration.toolsets = nil
-
-	// Reload the Kubernetes provider (this will also rebuild tools)
-	if err := s.reloadToolsets(); err != nil {
+	redactor, err := newConfig.Redaction.Redactor()
+	if err != nil {
+		return fmt.Errorf("invalid redaction: %w", err)
+	}
+	if err := newConfig.ResultBudget.Validate(); err != nil {
+		return err
+	}
+	if err := newConfig.ResultCache.Validate(); err != nil {
+		return err
+	}
+	if err := newConfig.Workspace.Validate(newConfig.Stateless); err != nil {
+		return err
+	}
+	if err := newConfig.TransientCleanup.Validate(); err != nil {
+		return err
+	}
+	for _, freeze := range newConfig.ChangeFreezes {
+		if err := freeze.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := config.ValidateLabelScopes(newConfig.LabelScopes, newConfig.RequireOAuth); err != nil {
+		return err
+	}
+	if err := config.ValidateSchedules(newConfig.Schedules); err != nil {
+		return fmt.Errorf("invalid schedules: %w", err)
+	}
+	if err := config.ValidateExtensions(newConfig.Extensions); err != nil {
+		return fmt.Errorf("invalid extensions: %w", err)
+	}
+	previousSchedules := s.enabledSchedules()
+
+	// Reload the Kubernetes provider (this will also rebuild tools), a reload requested by the watchers is superseded
+	err = s.reloader.run(func() {
+		// Update the configuration
+		s.configuration.StaticConfig = newConfig
+		s.configuration.redactor = redactor
+		// Clear cached values so they get recomputed
+		s.configuration.listOutput = nil
+		s.configuration.toolsets = nil
+		s.resultCache.clear()
+		output.SetMaxBytes(newConfig.MaxOutputBytes)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to reload toolsets: %w", err)
 	}
+	s.reloadSchedules(previousSchedules)
+	s.transientSweeper.update(s.configuration.TransientCleanup.GetInterval())
+	s.workspace.SetIdleTimeout(s.configuration.Workspace.GetIdleTimeout())
 
 	klog.V(1).Info("MCP server configuration reloaded successfully")
 	return nil
 }
 
 func (s *Server) Close() {
+	if s.reloader != nil {
+		s.reloader.close()
+	}
+	if s.operations != nil {
+		s.operations.close()
+	}
+	if s.schedules != nil {
+		s.schedules.stop()
+	}
+	if s.transientSweeper != nil {
+		s.transientSweeper.stop()
+	}
+	if s.workspace != nil {
+		s.workspace.Close()
+	}
 	if s.p != nil {
 		s.p.Close()
 	}
+	if s.recorder != nil {
+		_ = s.recorder.Close()
+	}
 }
 
 // Shutdown gracefully shuts down the server, flushing any pending metrics.
 func (s *Server) Shutdown(ctx context.Context) error {
+	if s.hooks != nil {
+		s.hooks.Wait()
+	}
 	if s.metrics != nil {
 		if err := s.metrics.Shutdown(ctx); err != nil {
 			return fmt.Errorf("failed to shutdown metrics: %w", err)
@@ -361,6 +575,16 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// NewToolCallResult returns the MCP result of the tool call, the header of the JSON content (see api.ToolCallResult)
+// follows it in a separate text block so that the JSON content remains valid JSON
+func NewToolCallResult(result *api.ToolCallResult) *mcp.CallToolResult {
+	ret := NewTextResult(result.Content, result.Error)
+	if result.Error == nil && result.Header != "" {
+		ret.Content = append(ret.Content, &mcp.TextContent{Text: result.Header})
+	}
+	return ret
+}
+
 func NewTextResult(content string, err error) *mcp.CallToolResult {
 	if err != nil {
 		return &mcp.CallToolResult{
@@ -370,6 +594,8 @@ func NewTextResult(content string, err error) *mcp.CallToolResult {
 					Text: err.Error(),
 				},
 			},
+			// machine-readable error payload (code, message, remediation) for clients to branch on
+			StructuredContent: map[string]any{"error": api.ClassifyError(err)},
 		}
 	}
 	return &mcp.CallToolResult{
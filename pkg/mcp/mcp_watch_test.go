@@ -27,6 +27,7 @@ type WatchKubeConfigSuite struct {
 func (s *WatchKubeConfigSuite) SetupTest() {
 	s.BaseMcpSuite.SetupTest()
 	s.T().Setenv("KUBECONFIG_DEBOUNCE_WINDOW_MS", "10")
+	s.T().Setenv("TOOLS_RELOAD_DEBOUNCE_WINDOW_MS", "10")
 	s.mockServer = test.NewMockServer()
 	s.Require().NoError(toml.Unmarshal([]byte(`
 		[[prompts]]
@@ -158,6 +159,7 @@ func (s *WatchClusterStateSuite) SetupTest() {
 	// Configure fast polling for tests
 	s.T().Setenv("CLUSTER_STATE_POLL_INTERVAL_MS", "50")
 	s.T().Setenv("CLUSTER_STATE_DEBOUNCE_WINDOW_MS", "10")
+	s.T().Setenv("TOOLS_RELOAD_DEBOUNCE_WINDOW_MS", "10")
 	s.mockServer = test.NewMockServer()
 	s.handler = test.NewDiscoveryClientHandler()
 	s.mockServer.Handle(s.handler)
@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type MetricsSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	queries    []string
+}
+
+func (s *MetricsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.queries = nil
+	s.mockServer = test.NewMockServer()
+	discovery := test.NewDiscoveryClientHandler()
+	discovery.APIResourceLists[0].APIResources = append(discovery.APIResourceLists[0].APIResources,
+		metav1.APIResource{Name: "services", Kind: "Service", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}})
+	s.mockServer.Handle(discovery)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v1/namespaces/monitoring/services/prometheus:9090/proxy/api/v1/query" {
+			return
+		}
+		s.queries = append(s.queries, req.URL.Query().Get("query"))
+		w.Header().Set("Content-Type", "application/json")
+		if req.URL.Query().Get("query") == "sum(" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unclosed left parenthesis"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"namespace":"default","pod":"web-1"},"value":[1735740000,"0.25"]},
+			{"metric":{"namespace":"default","pod":"web-2"},"value":[1735740000,"1.5"]}
+		]}}`))
+	}))
+	s.Cfg = test.Must(config.ReadToml([]byte(`
+		[toolset_configs.core.prometheus]
+		namespace = "monitoring"
+		service = "prometheus"
+		port = "9090"
+	`)))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *MetricsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *MetricsSuite) TestMetricsQuery() {
+	s.InitMcpClient()
+	s.Run("metrics_query(query=sum by (pod))", func() {
+		toolResult, err := s.CallTool("metrics_query", map[string]interface{}{"query": "sum by (namespace, pod) (rate(container_cpu_usage_seconds_total[5m]))"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		s.Run("queries prometheus through the service proxy", func() {
+			s.Equal([]string{"sum by (namespace, pod) (rate(container_cpu_usage_seconds_total[5m]))"}, s.queries)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has comment with the number of series", func() {
+			s.Truef(strings.HasPrefix(text, "# 2 vector series at "), "unexpected result %v", text)
+		})
+		s.Run("returns the compact table", func() {
+			s.Contains(text, "# Common labels: namespace=default\npod    VALUE\nweb-1  0.25\nweb-2  1.5\n")
+		})
+	})
+	s.Run("metrics_query(query=sum() returns the PromQL error", func() {
+		toolResult, err := s.CallTool("metrics_query", map[string]interface{}{"query": "sum("})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to query metrics: prometheus query failed (bad_data): unclosed left parenthesis", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("metrics_query(range=invalid)", func() {
+		toolResult, err := s.CallTool("metrics_query", map[string]interface{}{"query": "up", "range": "invalid"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "invalid range invalid")
+	})
+}
+
+func (s *MetricsSuite) TestMetricsQueryNotConfigured() {
+	s.Cfg = test.Must(config.ReadToml([]byte(``)))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("metrics_query", map[string]interface{}{"query": "up"})
+	s.Nilf(err, "call tool should not return error object")
+	s.Truef(toolResult.IsError, "call tool should fail")
+	s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "Prometheus is not configured")
+}
+
+func TestMetrics(t *testing.T) {
+	suite.Run(t, new(MetricsSuite))
+}
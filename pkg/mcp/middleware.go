@@ -18,7 +18,8 @@ import (
 	"k8s.io/klog/v2"
 )
 
-// sessionInjectionMiddleware injects the MCP session into the context for logging support.
+// sessionInjectionMiddleware injects the MCP session and the progress token of the request into the context for
+// logging and progress notifications support.
 // This middleware should be added first so all subsequent middleware and handlers have access.
 func sessionInjectionMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
 	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
@@ -27,29 +28,35 @@ func sessionInjectionMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
 				ctx = context.WithValue(ctx, mcplog.MCPSessionContextKey, serverSession)
 			}
 		}
+		if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok && params != nil && params.GetProgressToken() != nil {
+			ctx = context.WithValue(ctx, mcplog.MCPProgressTokenContextKey, params.GetProgressToken())
+		}
 		return next(ctx, method, req)
 	}
 }
 
 func authHeaderPropagationMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
 	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
-		if req.GetExtra() != nil && req.GetExtra().Header != nil {
-			// Get the standard Authorization header (OAuth compliant)
-			authHeader := req.GetExtra().Header.Get(string(internalk8s.OAuthAuthorizationHeader))
-			if authHeader != "" {
-				return next(context.WithValue(ctx, internalk8s.OAuthAuthorizationHeader, authHeader), method, req)
-			}
-
-			// Fallback to custom header for backward compatibility
-			customAuthHeader := req.GetExtra().Header.Get(string(internalk8s.CustomAuthorizationHeader))
-			if customAuthHeader != "" {
-				return next(context.WithValue(ctx, internalk8s.OAuthAuthorizationHeader, customAuthHeader), method, req)
-			}
+		if authHeader := authorizationHeader(req); authHeader != "" {
+			return next(context.WithValue(ctx, internalk8s.OAuthAuthorizationHeader, authHeader), method, req)
 		}
 		return next(ctx, method, req)
 	}
 }
 
+// authorizationHeader returns the Authorization header of the request, or the custom one for backward compatibility
+func authorizationHeader(req mcp.Request) string {
+	if req.GetExtra() == nil || req.GetExtra().Header == nil {
+		return ""
+	}
+	// Get the standard Authorization header (OAuth compliant)
+	if authHeader := req.GetExtra().Header.Get(string(internalk8s.OAuthAuthorizationHeader)); authHeader != "" {
+		return authHeader
+	}
+	// Fallback to custom header for backward compatibility
+	return req.GetExtra().Header.Get(string(internalk8s.CustomAuthorizationHeader))
+}
+
 func userAgentPropagationMiddleware(serverName, serverVersion string) func(mcp.MethodHandler) mcp.MethodHandler {
 	return func(next mcp.MethodHandler) mcp.MethodHandler {
 		return func(ctx context.Context, method string, req mcp.Request) (result mcp.Result, err error) {
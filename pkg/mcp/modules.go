@@ -1,10 +1,18 @@
 package mcp
 
 import (
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/argocd"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/clusterapi"
 	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/config"
 	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/core"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/crossplane"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/externalsecrets"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/flux"
 	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/helm"
 	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/kcp"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/keda"
 	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/kiali"
 	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/kubevirt"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/policy"
+	_ "github.com/containers/kubernetes-mcp-server/pkg/toolsets/tekton"
 )
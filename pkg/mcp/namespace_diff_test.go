@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type NamespaceDiffSuite struct {
+	BaseMcpSuite
+	mockServer      *test.MockServer
+	otherMockServer *test.MockServer
+}
+
+// namespaceDiffHandler serves the Deployment web with the provided replicas by namespace,
+// and the ConfigMap settings in the staging namespace only
+func namespaceDiffHandler(replicas map[string]int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		for namespace, r := range replicas {
+			switch req.URL.Path {
+			case "/apis/apps/v1/namespaces/" + namespace + "/deployments":
+				test.WriteObject(w, &appsv1.DeploymentList{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DeploymentList"}, Items: []appsv1.Deployment{{
+					ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace, UID: "web-uid", ResourceVersion: "42"},
+					Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(r)},
+				}}})
+			case "/api/v1/namespaces/" + namespace + "/configmaps":
+				list := &v1.ConfigMapList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMapList"}}
+				if namespace == "staging" {
+					list.Items = append(list.Items, v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: namespace}, Data: map[string]string{"mode": "debug"}})
+				}
+				test.WriteObject(w, list)
+			}
+		}
+	})
+}
+
+func (s *NamespaceDiffSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	discovery := test.NewDiscoveryClientHandler()
+	discovery.APIResourceLists[0].APIResources = append(discovery.APIResourceLists[0].APIResources,
+		metav1.APIResource{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}})
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(discovery)
+	s.mockServer.Handle(namespaceDiffHandler(map[string]int32{"staging": 1, "production": 3}))
+	s.otherMockServer = test.NewMockServer()
+	s.otherMockServer.Handle(discovery)
+	s.otherMockServer.Handle(namespaceDiffHandler(map[string]int32{"staging": 2}))
+	// the other-cluster context targets the other mock server
+	kubeconfig := s.mockServer.Kubeconfig()
+	other := s.otherMockServer.Kubeconfig()
+	kubeconfig.Clusters["other-cluster"] = other.Clusters["fake"]
+	kubeconfig.AuthInfos["other-cluster"] = other.AuthInfos["fake"]
+	kubeconfig.Contexts["other-cluster"] = clientcmdapi.NewContext()
+	kubeconfig.Contexts["other-cluster"].Cluster = "other-cluster"
+	kubeconfig.Contexts["other-cluster"].AuthInfo = "other-cluster"
+	s.Cfg.KubeConfig = test.KubeconfigFile(s.T(), kubeconfig)
+}
+
+func (s *NamespaceDiffSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+	if s.otherMockServer != nil {
+		s.otherMockServer.Close()
+	}
+}
+
+func (s *NamespaceDiffSuite) TestNamespaceDiff() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("namespace_diff", map[string]any{"namespace": "staging", "other_namespace": "production"})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Require().Falsef(toolResult.IsError, "call tool should succeed: %v", toolResult.Content)
+	text := toolResult.Content[0].(mcp.TextContent).Text
+	s.Run("has yaml comment with the summary", func() {
+		s.Contains(text, "# Comparison of namespace staging with namespace production: 0 identical, 1 different, 1 missing object(s) (YAML format):\n")
+	})
+	s.Run("reports the objects missing from the other namespace", func() {
+		s.Contains(text, "onlyInNamespace:\n- ConfigMap/settings\n")
+	})
+	s.Run("reports the differing fields", func() {
+		s.Contains(text, "  object: Deployment/web\n")
+		s.Contains(text, "path: spec.replicas\n")
+		s.Contains(text, "otherValue: 3\n")
+	})
+	s.Run("ignores the fields populated by the cluster", func() {
+		s.NotContains(text, "resourceVersion")
+	})
+}
+
+func (s *NamespaceDiffSuite) TestNamespaceDiffOtherTarget() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("namespace_diff", map[string]any{"namespace": "staging", "other_target": "other-cluster"})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Require().Falsef(toolResult.IsError, "call tool should succeed: %v", toolResult.Content)
+	text := toolResult.Content[0].(mcp.TextContent).Text
+	s.Contains(text, "# Comparison of namespace staging of fake-context with namespace staging of other-cluster: 1 identical, 1 different, 0 missing object(s)")
+	s.Contains(text, "otherValue: 2\n")
+}
+
+func (s *NamespaceDiffSuite) TestNamespaceDiffSameNamespace() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("namespace_diff", map[string]any{"namespace": "staging"})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.True(toolResult.IsError, "call tool should fail")
+	s.Equal("failed to compare namespaces, other_namespace or other_target must differ from the namespace and target of the tool call",
+		toolResult.Content[0].(mcp.TextContent).Text)
+}
+
+func TestNamespaceDiff(t *testing.T) {
+	suite.Run(t, new(NamespaceDiffSuite))
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type NamespaceExportSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *NamespaceExportSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	discovery := test.NewDiscoveryClientHandler()
+	discovery.APIResourceLists[0].APIResources = append(discovery.APIResourceLists[0].APIResources,
+		metav1.APIResource{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+		metav1.APIResource{Name: "events", Kind: "Event", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+		metav1.APIResource{Name: "secrets", Kind: "Secret", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+		metav1.APIResource{Name: "serviceaccounts", Kind: "ServiceAccount", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}})
+	s.mockServer.Handle(discovery)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/apps/v1/namespaces/default/deployments":
+			test.WriteObject(w, &appsv1.DeploymentList{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DeploymentList"}, Items: []appsv1.Deployment{{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid", ResourceVersion: "42",
+					Annotations: map[string]string{"deployment.kubernetes.io/revision": "3"}},
+				Spec:   appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))},
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 2},
+			}}})
+		case "/api/v1/namespaces/default/pods":
+			test.WriteObject(w, &v1.PodList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"}, Items: []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "web-6c9f-abcde", Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-6c9f", UID: "rs-uid", Controller: ptr.To(true)}}}}}})
+		case "/api/v1/namespaces/default/configmaps":
+			test.WriteObject(w, &v1.ConfigMapList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMapList"}, Items: []v1.ConfigMap{
+				{ObjectMeta: metav1.ObjectMeta{Name: "kube-root-ca.crt", Namespace: "default"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"}, Data: map[string]string{"mode": "production"}},
+			}})
+		case "/api/v1/namespaces/default/events":
+			test.WriteObject(w, &v1.EventList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"}, Items: []v1.Event{{ObjectMeta: metav1.ObjectMeta{Name: "web-event", Namespace: "default"}}}})
+		case "/api/v1/namespaces/default/secrets":
+			test.WriteObject(w, &v1.SecretList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "SecretList"}, Items: []v1.Secret{{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "default"}, Type: v1.SecretTypeOpaque}}})
+		case "/api/v1/namespaces/default/serviceaccounts":
+			test.WriteObject(w, &v1.ServiceAccountList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccountList"}, Items: []v1.ServiceAccount{
+				{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+			}})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NamespaceExportSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NamespaceExportSuite) TestNamespaceExport() {
+	s.InitMcpClient()
+	s.Run("namespace_export(namespace=default)", func() {
+		toolResult, err := s.CallTool("namespace_export", map[string]interface{}{"namespace": "default"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of exported objects", func() {
+			s.Truef(strings.HasPrefix(text, "# Exported 3 object(s) from namespace default (YAML format)\n"), "unexpected result %v", text)
+		})
+		s.Run("exports the objects in dependency order", func() {
+			serviceAccount := strings.Index(text, "kind: ServiceAccount")
+			configMap := strings.Index(text, "kind: ConfigMap")
+			deployment := strings.Index(text, "kind: Deployment")
+			s.Truef(serviceAccount >= 0 && serviceAccount < configMap && configMap < deployment, "unexpected order %v", text)
+		})
+		s.Run("strips cluster-specific fields", func() {
+			for _, field := range []string{"uid:", "resourceVersion:", "status:", "namespace:", "deployment.kubernetes.io/revision"} {
+				s.NotContainsf(text, field, "unexpected field %s in %v", field, text)
+			}
+		})
+		s.Run("skips controller managed, system, and transient objects", func() {
+			for _, name := range []string{"web-6c9f-abcde", "kube-root-ca.crt", "name: default", "web-event", "credentials"} {
+				s.NotContainsf(text, name, "unexpected object %s in %v", name, text)
+			}
+		})
+	})
+	s.Run("namespace_export(namespace=default, include_secrets=true)", func() {
+		toolResult, err := s.CallTool("namespace_export", map[string]interface{}{"namespace": "default", "include_secrets": true})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("exports the Secrets", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "name: credentials")
+		})
+	})
+}
+
+func TestNamespaceExport(t *testing.T) {
+	suite.Run(t, new(NamespaceExportSuite))
+}
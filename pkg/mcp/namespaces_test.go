@@ -46,6 +46,40 @@ func (s *NamespacesSuite) TestNamespacesList() {
 	})
 }
 
+func (s *NamespacesSuite) TestNamespacesListSorted() {
+	s.InitMcpClient()
+	for _, order := range []string{"asc", "desc"} {
+		s.Run("namespaces_list(sort_by=name, sort_order="+order+")", func() {
+			toolResult, err := s.CallTool("namespaces_list", map[string]interface{}{"sort_by": "name", "sort_order": order})
+			s.Run("no error", func() {
+				s.Nilf(err, "call tool failed %v", err)
+				s.Falsef(toolResult.IsError, "call tool failed")
+			})
+			var decoded []unstructured.Unstructured
+			s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded))
+			s.Run("returns sorted items", func() {
+				names := make([]string, 0, len(decoded))
+				for _, ns := range decoded {
+					names = append(names, ns.GetName())
+				}
+				expected := slices.Clone(names)
+				slices.Sort(expected)
+				if order == "desc" {
+					slices.Reverse(expected)
+				}
+				s.Equal(expected, names)
+			})
+		})
+	}
+	s.Run("namespaces_list(sort_by=invalid)", func() {
+		toolResult, _ := s.CallTool("namespaces_list", map[string]interface{}{"sort_by": "invalid"})
+		s.Run("returns error", func() {
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "invalid sort field: invalid")
+		})
+	})
+}
+
 func (s *NamespacesSuite) TestNamespacesListDenied() {
 	s.Require().NoError(toml.Unmarshal([]byte(`
 		denied_resources = [ { version = "v1", kind = "Namespace" } ]
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type NetCheckSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *NetCheckSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	discovery := test.NewDiscoveryClientHandler()
+	discovery.APIResourceLists[0].APIResources = append(discovery.APIResourceLists[0].APIResources,
+		metav1.APIResource{Name: "services", Kind: "Service", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}})
+	s.mockServer.Handle(discovery)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NetCheckSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+// diagnosticPodHandler simulates the lifecycle of the diagnostic Pod, which completes with the provided phase and logs
+func (s *NetCheckSuite) diagnosticPodHandler(phase v1.PodPhase, logs string) (created *v1.Pod, deleted *bool) {
+	created, deleted = &v1.Pod{}, new(bool)
+	var mu sync.Mutex
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == "/api/v1/namespaces/default/services/web":
+			test.WriteObject(w, &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec:       v1.ServiceSpec{Ports: []v1.ServicePort{{Name: "http", Port: 8080}}},
+			})
+		case req.Method == http.MethodPost && req.URL.Path == "/api/v1/namespaces/default/pods":
+			body, _ := io.ReadAll(req.Body)
+			_, _, _ = scheme.Codecs.UniversalDeserializer().Decode(body, nil, created)
+			test.WriteObject(w, created)
+		case req.Method == http.MethodGet && created.Name != "" && req.URL.Path == "/api/v1/namespaces/default/pods/"+created.Name:
+			pod := created.DeepCopy()
+			pod.Status.Phase = phase
+			test.WriteObject(w, pod)
+		case req.Method == http.MethodGet && created.Name != "" && req.URL.Path == "/api/v1/namespaces/default/pods/"+created.Name+"/log":
+			_, _ = w.Write([]byte(logs))
+		case req.Method == http.MethodDelete && created.Name != "" && req.URL.Path == "/api/v1/namespaces/default/pods/"+created.Name:
+			*deleted = true
+			test.WriteObject(w, created)
+		}
+	}))
+	return created, deleted
+}
+
+func (s *NetCheckSuite) TestNetCheck() {
+	created, deleted := s.diagnosticPodHandler(v1.PodSucceeded, ""+
+		"@@net_check dns web.default.svc\n"+
+		"Server:\t\t10.96.0.10\n"+
+		"Name:\tweb.default.svc.cluster.local\n"+
+		"@@net_check result 0\n"+
+		"@@net_check tcp web.default.svc:8080\n"+
+		"nc: web.default.svc (10.96.12.1:8080): Connection refused\n"+
+		"@@net_check result 1\n"+
+		"@@net_check http http://web.default.svc:8080/\n"+
+		"wget: can't connect to remote host (10.96.12.1): Connection refused\n"+
+		"@@net_check result 1\n")
+	s.InitMcpClient()
+	s.Run("net_check(target=web)", func() {
+		toolResult, err := s.CallTool("net_check", map[string]interface{}{"target": "web", "namespace": "default"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the failed checks", func() {
+			s.Truef(strings.HasPrefix(text, "# 2 of 3 check(s) failed for web.default.svc (YAML format):\n"), "unexpected result %v", text)
+		})
+		var result map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &result))
+		s.Run("returns the result of each check", func() {
+			checks := result["checks"].([]any)
+			s.Require().Len(checks, 3)
+			s.Equal(map[string]any{"type": "dns", "target": "web.default.svc", "success": true,
+				"output": "Server:\t\t10.96.0.10\nName:\tweb.default.svc.cluster.local"}, checks[0])
+			s.Equal(false, checks[1].(map[string]any)["success"])
+			s.Equal("http://web.default.svc:8080/", checks[2].(map[string]any)["target"])
+		})
+		s.Run("runs the diagnostic pod with the default image", func() {
+			s.Equal("busybox:1.37", created.Spec.Containers[0].Image)
+			s.Equal(v1.RestartPolicyNever, created.Spec.RestartPolicy)
+			s.Contains(created.Spec.Containers[0].Command[2], "nc -w 5 'web.default.svc' 8080")
+		})
+		s.Run("deletes the diagnostic pod", func() {
+			s.True(*deleted)
+		})
+	})
+}
+
+func (s *NetCheckSuite) TestNetCheckTimeout() {
+	_, deleted := s.diagnosticPodHandler(v1.PodPending, "")
+	s.InitMcpClient()
+	s.Run("net_check(target=10.0.0.1, timeout=1)", func() {
+		toolResult, err := s.CallTool("net_check", map[string]interface{}{"target": "10.0.0.1", "namespace": "default", "port": 443, "timeout": 1})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Regexp("failed to check network connectivity to 10.0.0.1: diagnostic pod .+ did not complete within 1s \\(phase Pending\\)", toolResult.Content[0].(mcp.TextContent).Text)
+		s.True(*deleted, "expected the diagnostic pod to be deleted")
+	})
+}
+
+func (s *NetCheckSuite) TestNetCheckInvalidTarget() {
+	s.InitMcpClient()
+	toolResult, _ := s.CallTool("net_check", map[string]interface{}{"target": "web; rm -rf /"})
+	s.Truef(toolResult.IsError, "call tool should fail")
+	s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "invalid target")
+}
+
+func TestNetCheck(t *testing.T) {
+	suite.Run(t, new(NetCheckSuite))
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+)
+
+type NodesDebugSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	created    *v1.Pod
+	deleted    bool
+}
+
+func (s *NodesDebugSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	s.created, s.deleted = &v1.Pod{}, false
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == "/api/v1/nodes/worker-1":
+			test.WriteObject(w, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}})
+		case req.Method == http.MethodPost && req.URL.Path == "/api/v1/namespaces/node-debug/pods":
+			body, _ := io.ReadAll(req.Body)
+			_, _, _ = scheme.Codecs.UniversalDeserializer().Decode(body, nil, s.created)
+			test.WriteObject(w, s.created)
+		case req.Method == http.MethodGet && s.created.Name != "" && req.URL.Path == "/api/v1/namespaces/node-debug/pods/"+s.created.Name:
+			pod := s.created.DeepCopy()
+			pod.Status.Phase = v1.PodFailed
+			pod.Status.ContainerStatuses = []v1.ContainerStatus{{Name: "debugger", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 1}}}}
+			test.WriteObject(w, pod)
+		case req.Method == http.MethodGet && s.created.Name != "" && req.URL.Path == "/api/v1/namespaces/node-debug/pods/"+s.created.Name+"/log":
+			_, _ = w.Write([]byte("Filesystem      Size  Used Avail Use% Mounted on\n/dev/sda1        20G   20G     0 100% /host\n"))
+		case req.Method == http.MethodDelete && s.created.Name != "" && req.URL.Path == "/api/v1/namespaces/node-debug/pods/"+s.created.Name:
+			s.deleted = true
+			test.WriteObject(w, s.created)
+		}
+	}))
+	s.Cfg = test.Must(config.ReadToml([]byte(`
+		[toolset_configs.core.node_debug]
+		namespace = "node-debug"
+		privileged = true
+	`)))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *NodesDebugSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *NodesDebugSuite) TestNodesDebug() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("nodes_debug", map[string]any{"name": "worker-1", "command": []any{"df", "-h", "/host"}})
+	s.Run("returns the output and exit code of the command", func() {
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("# Command exited with code 1 on node worker-1 (privileged debug Pod node-debug/"+s.created.Name+" with image busybox:1.37, deleted)\n"+
+			"Filesystem      Size  Used Avail Use% Mounted on\n/dev/sda1        20G   20G     0 100% /host\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("runs the command on the node with host access", func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		spec := s.created.Spec
+		s.Equal("worker-1", spec.NodeName)
+		s.True(spec.HostNetwork && spec.HostPID && spec.HostIPC, "expected the host namespaces")
+		s.Equal([]v1.Toleration{{Operator: v1.TolerationOpExists}}, spec.Tolerations)
+		s.Equal("/", spec.Volumes[0].HostPath.Path)
+		s.Require().Len(spec.Containers, 1)
+		s.Equal([]string{"df", "-h", "/host"}, spec.Containers[0].Command)
+		s.Equal("/host", spec.Containers[0].VolumeMounts[0].MountPath)
+		s.True(*spec.Containers[0].SecurityContext.Privileged)
+	})
+	s.Run("labels the debug pod as transient", func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.Equal("true", s.created.Labels["kubernetes-mcp-server/transient"])
+		s.NotEmpty(s.created.Annotations["kubernetes-mcp-server/expires-at"])
+	})
+	s.Run("deletes the debug pod", func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.True(s.deleted)
+	})
+}
+
+func (s *NodesDebugSuite) TestNodesDebugNotEnabled() {
+	s.Cfg = test.Must(config.ReadToml([]byte(``)))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("nodes_debug", map[string]any{"name": "worker-1", "command": []any{"ls"}})
+	s.Require().NoError(err)
+	s.True(toolResult.IsError)
+	s.Equal("failed to debug node: node debug is not enabled, set [toolset_configs.core.node_debug] in the server configuration", toolResult.Content[0].(mcp.TextContent).Text)
+	s.Empty(s.created.Name, "expected no debug pod")
+}
+
+func TestNodesDebug(t *testing.T) {
+	suite.Run(t, new(NodesDebugSuite))
+}
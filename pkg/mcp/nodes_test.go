@@ -3,12 +3,16 @@ package mcp
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/BurntSushi/toml"
 	"github.com/containers/kubernetes-mcp-server/internal/test"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type NodesSuite struct {
@@ -30,6 +34,41 @@ func (s *NodesSuite) TearDownTest() {
 	}
 }
 
+func (s *NodesSuite) TestNodesHealth() {
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes":
+			test.WriteObject(w, &v1.NodeList{Items: []v1.Node{{
+				ObjectMeta: metav1.ObjectMeta{Name: "pressured-node"},
+				Status: v1.NodeStatus{
+					Conditions: []v1.NodeCondition{
+						{Type: v1.NodeReady, Status: v1.ConditionTrue},
+						{Type: v1.NodeDiskPressure, Status: v1.ConditionTrue, Message: "disk usage above threshold"},
+					},
+					Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("8Gi")},
+					NodeInfo:    v1.NodeSystemInfo{KubeletVersion: "v1.34.0"},
+				},
+			}}})
+		case "/api/v1/pods":
+			test.WriteObject(w, &v1.PodList{})
+		}
+	}))
+	s.InitMcpClient()
+	s.Run("nodes_health()", func() {
+		toolResult, err := s.CallTool("nodes_health", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("reports the pressure condition", func() {
+			content := toolResult.Content[0].(mcp.TextContent).Text
+			s.Truef(strings.HasPrefix(content, "# 1 node(s), 1 with issues (YAML format):\n"), "unexpected header %v", content)
+			s.Containsf(content, "node has condition DiskPressure: disk usage above threshold", "expected issue, got %v", content)
+			s.Containsf(content, "kubeletVersion: v1.34.0", "expected kubelet version, got %v", content)
+		})
+	})
+}
+
 func (s *NodesSuite) TestNodesLog() {
 	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		// Get Node response
@@ -225,6 +264,209 @@ func (s *NodesSuite) TestNodesLogDenied() {
 	})
 }
 
+func (s *NodesSuite) TestNodesLogQuery() {
+	var query map[string][]string
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v1/nodes/existing-node" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"apiVersion": "v1", "kind": "Node", "metadata": {"name": "existing-node"}}`))
+			return
+		}
+		if req.URL.Path == "/api/v1/nodes/existing-node/proxy/logs" {
+			query = req.URL.Query()
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("Jan 01 10:30:00 existing-node kubelet[1234]: E0101 eviction manager: failed to get summary stats\n"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	s.InitMcpClient()
+	s.Run("nodes_log(name=existing-node, query=kubelet, sinceTime, untilTime, pattern, boot)", func() {
+		toolResult, err := s.CallTool("nodes_log", map[string]interface{}{
+			"name":      "existing-node",
+			"query":     "kubelet",
+			"sinceTime": "2024-01-01T10:00:00Z",
+			"untilTime": "2024-01-01T11:00:00Z",
+			"pattern":   "eviction|failed",
+			"boot":      -1,
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool should not return error object")
+			s.Falsef(toolResult.IsError, "call tool should succeed")
+		})
+		s.Run("returns the journal logs", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "eviction manager: failed to get summary stats")
+		})
+		s.Run("forwards the log query parameters to the kubelet", func() {
+			s.Equal([]string{"kubelet"}, query["query"])
+			s.Equal([]string{"2024-01-01T10:00:00Z"}, query["sinceTime"])
+			s.Equal([]string{"2024-01-01T11:00:00Z"}, query["untilTime"])
+			s.Equal([]string{"eviction|failed"}, query["pattern"])
+			s.Equal([]string{"-1"}, query["boot"])
+		})
+	})
+	s.Run("nodes_log(name=existing-node, query=kubelet) omits the unset parameters", func() {
+		toolResult, err := s.CallTool("nodes_log", map[string]interface{}{
+			"name":  "existing-node",
+			"query": "kubelet",
+		})
+		s.Require().Nil(err)
+		s.Require().False(toolResult.IsError)
+		for _, param := range []string{"sinceTime", "untilTime", "pattern", "boot"} {
+			s.NotContains(query, param)
+		}
+	})
+	s.Run("nodes_log(sinceTime=yesterday)", func() {
+		toolResult, err := s.CallTool("nodes_log", map[string]interface{}{
+			"name":      "existing-node",
+			"query":     "kubelet",
+			"sinceTime": "yesterday",
+		})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to parse sinceTime parameter, expected RFC 3339 format")
+	})
+	s.Run("nodes_log(pattern=invalid regexp)", func() {
+		toolResult, err := s.CallTool("nodes_log", map[string]interface{}{
+			"name":    "existing-node",
+			"query":   "kubelet",
+			"pattern": "error(",
+		})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to parse pattern parameter")
+	})
+	s.Run("nodes_log(boot=1)", func() {
+		toolResult, err := s.CallTool("nodes_log", map[string]interface{}{
+			"name":  "existing-node",
+			"query": "kubelet",
+			"boot":  1,
+		})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to parse boot parameter, expected 0 or a negative integer")
+	})
+}
+
+func (s *NodesSuite) TestNodesKubelet() {
+	healthy := true
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes/existing-node":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"apiVersion": "v1", "kind": "Node", "metadata": {"name": "existing-node"}}`))
+		case "/api/v1/nodes/existing-node/proxy/configz":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kubeletconfig": {"kind": "KubeletConfiguration", "maxPods": 110, "evictionHard": {"memory.available": "100Mi"}}}`))
+		case "/api/v1/nodes/existing-node/proxy/healthz":
+			w.Header().Set("Content-Type", "text/plain")
+			if !req.URL.Query().Has("verbose") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if !healthy {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("[+]ping ok\n[+]log ok\n[-]syncloop failed: reason withheld\nhealthz check failed\n"))
+				return
+			}
+			_, _ = w.Write([]byte("[+]ping ok\n[+]log ok\n[+]syncloop ok\nhealthz check passed\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	s.InitMcpClient()
+	s.Run("nodes_kubelet(name=nil)", func() {
+		toolResult, err := s.CallTool("nodes_kubelet", map[string]interface{}{})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to get node kubelet information, missing argument name", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("nodes_kubelet(name=existing-node, endpoint=pods)", func() {
+		toolResult, err := s.CallTool("nodes_kubelet", map[string]interface{}{
+			"name":     "existing-node",
+			"endpoint": "pods",
+		})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "unsupported endpoint pods")
+	})
+	s.Run("nodes_kubelet(name=inexistent-node)", func() {
+		toolResult, err := s.CallTool("nodes_kubelet", map[string]interface{}{
+			"name": "inexistent-node",
+		})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to get kubelet configz for node inexistent-node: failed to get node inexistent-node")
+	})
+	s.Run("nodes_kubelet(name=existing-node)", func() {
+		toolResult, err := s.CallTool("nodes_kubelet", map[string]interface{}{
+			"name": "existing-node",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool should not return error object")
+			s.Falsef(toolResult.IsError, "call tool should succeed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("returns the running kubelet configuration as YAML", func() {
+			s.Truef(strings.HasPrefix(text, "# Running kubelet configuration of node existing-node (YAML format):\n"), "unexpected header: %s", text)
+			s.Contains(text, "kind: KubeletConfiguration")
+			s.Contains(text, "maxPods: 110")
+			s.Contains(text, "memory.available: 100Mi")
+			s.NotContains(text, "kubeletconfig")
+		})
+	})
+	s.Run("nodes_kubelet(name=existing-node, endpoint=healthz)", func() {
+		toolResult, err := s.CallTool("nodes_kubelet", map[string]interface{}{
+			"name":     "existing-node",
+			"endpoint": "healthz",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool should not return error object")
+			s.Falsef(toolResult.IsError, "call tool should succeed")
+		})
+		s.Run("returns the verbose health checks", func() {
+			s.Equal("# Kubelet health checks of node existing-node:\n[+]ping ok\n[+]log ok\n[+]syncloop ok\nhealthz check passed\n", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("nodes_kubelet(name=existing-node, endpoint=healthz) with failing checks", func() {
+		healthy = false
+		toolResult, err := s.CallTool("nodes_kubelet", map[string]interface{}{
+			"name":     "existing-node",
+			"endpoint": "healthz",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool should not return error object")
+			s.Falsef(toolResult.IsError, "call tool should succeed")
+		})
+		s.Run("returns the failing health checks", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "[-]syncloop failed: reason withheld")
+		})
+	})
+}
+
+func (s *NodesSuite) TestNodesKubeletDenied() {
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		denied_resources = [ { version = "v1", kind = "Node" } ]
+	`), s.Cfg), "Expected to parse denied resources config")
+	s.InitMcpClient()
+	s.Run("nodes_kubelet (denied)", func() {
+		toolResult, err := s.CallTool("nodes_kubelet", map[string]interface{}{
+			"name": "does-not-matter",
+		})
+		s.Require().NotNil(toolResult, "toolResult should not be nil")
+		s.Run("has error", func() {
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Nilf(err, "call tool should not return error object")
+		})
+		s.Run("describes denial", func() {
+			msg := toolResult.Content[0].(mcp.TextContent).Text
+			expectedMessage := "failed to get kubelet configz for node does-not-matter:(.+:)? resource not allowed: /v1, Kind=Node"
+			s.Regexpf(expectedMessage, msg,
+				"expected descriptive error '%s', got %v", expectedMessage, msg)
+		})
+	})
+}
+
 func (s *NodesSuite) TestNodesStatsSummary() {
 	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		// Get Node response
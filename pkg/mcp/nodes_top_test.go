@@ -213,6 +213,61 @@ func (s *NodesTopSuite) TestNodesTopMetricsUnavailable() {
 	})
 }
 
+func (s *NodesTopSuite) TestNodesTopKubeletSummary() {
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/nodes":
+			_, _ = w.Write([]byte(`{
+				"apiVersion": "v1",
+				"kind": "NodeList",
+				"items": [
+					{"metadata": {"name": "node-1"}, "status": {"allocatable": {"cpu": "4", "memory": "16Gi"}}},
+					{"metadata": {"name": "node-2"}, "status": {"allocatable": {"cpu": "4", "memory": "16Gi"}}}
+				]
+			}`))
+		case "/api/v1/nodes/node-2":
+			_, _ = w.Write([]byte(`{"apiVersion": "v1", "kind": "Node", "metadata": {"name": "node-2"}}`))
+		case "/api/v1/nodes/node-1/proxy/stats/summary":
+			_, _ = w.Write([]byte(`{
+				"node": {
+					"nodeName": "node-1",
+					"cpu": {"time": "2025-10-29T09:00:00Z", "usageNanoCores": 500000000},
+					"memory": {"time": "2025-10-29T09:00:00Z", "workingSetBytes": 2147483648}
+				},
+				"pods": []
+			}`))
+		default:
+			// node-2 is NotReady, its kubelet is not reachable
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	s.InitMcpClient()
+
+	s.Run("nodes_top() - kubelet Summary API", func() {
+		toolResult, err := s.CallTool("nodes_top", map[string]interface{}{})
+		s.Require().NotNil(toolResult, "toolResult should not be nil")
+		s.Run("no error", func() {
+			s.Falsef(toolResult.IsError, "call tool should succeed")
+			s.Nilf(err, "call tool should not return error object")
+		})
+		s.Run("returns metrics for the nodes reporting their usage", func() {
+			content := toolResult.Content[0].(mcp.TextContent).Text
+			s.Regexpf(`node-1\s+500m\s+12%\s+2048Mi\s+12%`, content, "expected usage of node-1 in:\n%s", content)
+			s.Regexpf(`node-2\s+<unknown>`, content, "expected unknown usage of unreachable node-2 in:\n%s", content)
+		})
+	})
+
+	s.Run("nodes_top(name=node-2) - kubelet not reachable", func() {
+		toolResult, err := s.CallTool("nodes_top", map[string]interface{}{
+			"name": "node-2",
+		})
+		s.Require().NotNil(toolResult, "toolResult should not be nil")
+		s.Truef(toolResult.IsError, "call tool should fail when the kubelet is not reachable")
+		s.Nilf(err, "call tool should not return error object")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to get node node-2 stats summary")
+	})
+}
+
 func (s *NodesTopSuite) TestNodesTopDenied() {
 	s.Require().NoError(toml.Unmarshal([]byte(`
 		denied_resources = [ { group = "metrics.k8s.io", version = "v1beta1" } ]
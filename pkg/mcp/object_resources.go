@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+// objectResourcesTool is the tool whose availability enables reading the objects through their URIs
+// (the URIs are returned in the summaries of the list tools)
+const objectResourcesTool = "resources_get"
+
+// reloadObjectResources serves the Kubernetes objects as MCP resources while the resources_get tool is enabled
+func (s *Server) reloadObjectResources() {
+	if slices.Contains(s.enabledTools, objectResourcesTool) {
+		s.server.AddResourceTemplate(&mcp.ResourceTemplate{
+			Name:        "kubernetes-object",
+			Title:       "Kubernetes object",
+			Description: "The complete Kubernetes object (YAML) identified by its API group (core for the core API), version, kind, namespace (empty for cluster-scoped objects), and name",
+			MIMEType:    "application/yaml",
+			URITemplate: api.ObjectURITemplate,
+		}, s.readObjectResource)
+	} else {
+		s.server.RemoveResourceTemplates(api.ObjectURITemplate)
+	}
+}
+
+func (s *Server) readObjectResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	ref, err := api.ParseObjectURI(req.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	k, err := s.p.GetDerivedKubernetes(ctx, cmp.Or(ref.Target, s.p.GetDefaultTarget()))
+	if err != nil {
+		return nil, err
+	}
+	gvk := &schema.GroupVersionKind{Group: ref.Group, Version: ref.Version, Kind: ref.Kind}
+	obj, err := internalk8s.NewCore(k).ResourcesGet(ctx, gvk, ref.Namespace, ref.Name)
+	if apierrors.IsNotFound(err) {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", req.Params.URI, err)
+	}
+	output.Prune(obj, s.configuration.PruneFields)
+	content, err := output.MarshalYaml(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", req.Params.URI, err)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: req.Params.URI, MIMEType: "application/yaml", Text: content}},
+	}, nil
+}
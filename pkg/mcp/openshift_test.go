@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type OpenShiftSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *OpenShiftSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *OpenShiftSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *OpenShiftSuite) TestToolsNotRegisteredInKubernetes() {
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	s.InitMcpClient()
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err)
+	for _, tool := range tools.Tools {
+		s.NotContains([]string{"routes_list", "deploymentconfig_rollout_status"}, tool.Name)
+	}
+}
+
+func (s *OpenShiftSuite) TestRoutesList() {
+	discovery := test.NewInOpenShiftHandler(metav1.APIResourceList{
+		GroupVersion: "route.openshift.io/v1",
+		APIResources: []metav1.APIResource{{Name: "routes", Kind: "Route", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}},
+	})
+	discovery.APIResourceLists[0].APIResources = append(discovery.APIResourceLists[0].APIResources,
+		metav1.APIResource{Name: "services", Kind: "Service", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}})
+	s.mockServer.Handle(discovery)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/route.openshift.io/v1/namespaces/default/routes":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "route.openshift.io/v1", "kind": "RouteList"},
+				Items: []unstructured.Unstructured{{Object: map[string]any{
+					"apiVersion": "route.openshift.io/v1",
+					"kind":       "Route",
+					"metadata":   map[string]any{"name": "web", "namespace": "default"},
+					"spec": map[string]any{
+						"host": "web.apps.example.com",
+						"to":   map[string]any{"kind": "Service", "name": "web-missing"},
+						"tls":  map[string]any{"termination": "edge"},
+					},
+				}}},
+			})
+		case "/api/v1/namespaces/default/services":
+			test.WriteObject(w, &v1.ServiceList{Items: []v1.Service{{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}}})
+		}
+	}))
+	s.InitMcpClient()
+	s.Run("routes_list(namespace=default)", func() {
+		toolResult, err := s.CallTool("routes_list", map[string]interface{}{"namespace": "default"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of routes", func() {
+			s.Truef(strings.HasPrefix(text, "# 1 route(s) found, 1 with issues (YAML format):\n"), "unexpected result %v", text)
+		})
+		var routes []map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &routes))
+		s.Require().Len(routes, 1)
+		s.Run("summarizes the route", func() {
+			s.Equal("https://web.apps.example.com", routes[0]["url"])
+			s.Equal(map[string]any{"termination": "edge"}, routes[0]["tls"])
+			s.Equal([]any{"Service web-missing not found"}, routes[0]["issues"])
+		})
+	})
+}
+
+func (s *OpenShiftSuite) TestDeploymentConfigRollout() {
+	discovery := test.NewInOpenShiftHandler(metav1.APIResourceList{
+		GroupVersion: "apps.openshift.io/v1",
+		APIResources: []metav1.APIResource{{Name: "deploymentconfigs", Kind: "DeploymentConfig", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}},
+	})
+	discovery.APIResourceLists[0].APIResources = append(discovery.APIResourceLists[0].APIResources,
+		metav1.APIResource{Name: "replicationcontrollers", Kind: "ReplicationController", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}})
+	s.mockServer.Handle(discovery)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/apps.openshift.io/v1/namespaces/default/deploymentconfigs/web":
+			test.WriteObject(w, &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "apps.openshift.io/v1",
+				"kind":       "DeploymentConfig",
+				"metadata":   map[string]any{"name": "web", "namespace": "default"},
+				"spec":       map[string]any{"replicas": int64(2)},
+				"status":     map[string]any{"latestVersion": int64(2), "availableReplicas": int64(2)},
+			}})
+		case "/api/v1/namespaces/default/replicationcontrollers":
+			if req.URL.Query().Get("labelSelector") != "openshift.io/deployment-config.name=web" {
+				test.WriteObject(w, &v1.ReplicationControllerList{})
+				return
+			}
+			test.WriteObject(w, &v1.ReplicationControllerList{Items: []v1.ReplicationController{{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "default",
+					Annotations: map[string]string{"openshift.io/deployment-config.latest-version": "2", "openshift.io/deployment.phase": "Complete"}},
+				Spec: v1.ReplicationControllerSpec{Replicas: ptr.To(int32(2))},
+			}}})
+		}
+	}))
+	s.InitMcpClient()
+	s.Run("deploymentconfig_rollout_status(name=web)", func() {
+		toolResult, err := s.CallTool("deploymentconfig_rollout_status", map[string]interface{}{"namespace": "default", "name": "web"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the rollout status", func() {
+			s.Truef(strings.HasPrefix(text, "# DeploymentConfig default/web at version 2, 2/2 replica(s) available (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the revisions", func() {
+			s.Contains(text, "replicationController: web-2")
+			s.Contains(text, "phase: Complete")
+		})
+	})
+	s.Run("deploymentconfig_rollout_status(missing name)", func() {
+		toolResult, _ := s.CallTool("deploymentconfig_rollout_status", map[string]interface{}{})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to get deploymentconfig rollout status, missing argument name", toolResult.Content[0].(mcp.TextContent).Text)
+		s.Run("returns structured error with INVALID_ARGUMENT code", func() {
+			structuredError, ok := toolResult.StructuredContent.(map[string]any)["error"].(map[string]any)
+			s.Require().Truef(ok, "expected structured error, got %v", toolResult.StructuredContent)
+			s.Equal("INVALID_ARGUMENT", structuredError["code"])
+		})
+	})
+}
+
+func TestOpenShift(t *testing.T) {
+	suite.Run(t, new(OpenShiftSuite))
+}
@@ -0,0 +1,406 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+	"github.com/containers/kubernetes-mcp-server/pkg/sessionbudget"
+)
+
+const (
+	// AsyncParameterName is the name of the parameter that runs a long-running tool call as a background operation
+	AsyncParameterName = "async"
+	// OperationsStatusToolName is the name of the tool reporting the status of the background operations
+	OperationsStatusToolName = "operations_status"
+	// OperationsResultToolName is the name of the tool returning the result of a completed background operation
+	OperationsResultToolName = "operations_result"
+	// operationRetention is the duration a completed operation is kept for its result to be retrieved
+	operationRetention = time.Hour
+	// maxOperations bounds the number of kept operations of each caller, the oldest completed ones are dropped first
+	maxOperations = 100
+)
+
+const (
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+)
+
+// Operation is the report of a tool call run in the background, returned by the operations_status tool
+type Operation struct {
+	ID          string     `json:"id"`
+	Tool        string     `json:"tool"`
+	Target      string     `json:"target,omitempty"`
+	Status      string     `json:"status"`
+	StartedAt   time.Time  `json:"startedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	// Error is the error of the failed operation, its complete result is returned by the operations_result tool
+	Error   string `json:"error,omitempty"`
+	session string
+	// caller identifies the session and the credentials of the tool call that started the operation, see callerKey
+	caller string
+	result *api.ToolCallResult
+	cancel context.CancelFunc
+}
+
+// operations keeps the background tool calls of the server and their results until they're retrieved or expire
+type operations struct {
+	mu     sync.Mutex
+	items  []*Operation
+	now    func() time.Time
+	limits func() config.SessionLimitsConfig
+}
+
+func newOperations(limits func() config.SessionLimitsConfig) *operations {
+	return &operations{now: time.Now, limits: limits}
+}
+
+// start runs the tool call in the background, detached from the cancellation of the request that started it.
+// The operation is only visible to the caller (session and credentials) that started it, its goroutine is accounted in
+// the budget of the session of the context.
+func (o *operations) start(ctx context.Context, session, tool, target string, call func(ctx context.Context) (*api.ToolCallResult, error)) (Operation, error) {
+	caller := callerKey(ctx, session)
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	// the request completes before the operation, its progress can't be notified anymore
+	ctx = context.WithValue(ctx, mcplog.MCPProgressTokenContextKey, nil)
+	operation := &Operation{
+		ID:        rand.Text(),
+		Tool:      tool,
+		Target:    target,
+		Status:    OperationRunning,
+		StartedAt: o.now(),
+		session:   session,
+		caller:    caller,
+		cancel:    cancel,
+	}
+	goroutine, err := sessionbudget.Acquire(ctx, sessionbudget.Goroutines, 1, func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if operation.CompletedAt == nil {
+			o.teardown(operation, fmt.Sprintf("operation cancelled, the session reached its limit of %d background goroutines", o.limits().MaxGoroutines))
+		}
+	})
+	if err != nil {
+		cancel()
+		return Operation{}, err
+	}
+	o.mu.Lock()
+	o.prune(caller)
+	o.items = append(o.items, operation)
+	o.enforceMaxOperations(caller)
+	ret := *operation
+	o.mu.Unlock()
+	go func() {
+		defer goroutine.Release()
+		defer cancel()
+		result, err := call(ctx)
+		if err != nil {
+			result = api.NewToolCallResult("", err)
+		}
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if operation.CompletedAt != nil {
+			// torn down while running
+			return
+		}
+		operation.Status = OperationSucceeded
+		if result.Error != nil {
+			operation.Status = OperationFailed
+			operation.Error = result.Error.Error()
+		}
+		operation.CompletedAt = ptr.To(o.now())
+		operation.result = result
+		o.enforceMaxRetainedBytes(caller)
+	}()
+	return ret, nil
+}
+
+// enforceMaxOperations cancels the oldest running operations of the caller beyond its limit
+func (o *operations) enforceMaxOperations(caller string) {
+	limit := o.limits().MaxOperations
+	if limit <= 0 {
+		return
+	}
+	running := slices.DeleteFunc(o.callerItems(caller), func(operation *Operation) bool { return operation.CompletedAt != nil })
+	for _, operation := range running[:max(0, len(running)-limit)] {
+		o.teardown(operation, fmt.Sprintf("operation cancelled, the session reached its limit of %d running operations", limit))
+	}
+}
+
+// enforceMaxRetainedBytes drops the largest results of the completed operations of the caller beyond its limit
+func (o *operations) enforceMaxRetainedBytes(caller string) {
+	limit := o.limits().MaxRetainedBytes
+	if limit <= 0 {
+		return
+	}
+	completed := slices.DeleteFunc(o.callerItems(caller), func(operation *Operation) bool { return operation.result == nil })
+	slices.SortStableFunc(completed, func(a, b *Operation) int { return len(b.result.Content) - len(a.result.Content) })
+	retained := int64(0)
+	for _, operation := range completed {
+		retained += int64(len(operation.result.Content))
+	}
+	for _, operation := range completed {
+		if retained <= limit {
+			break
+		}
+		retained -= int64(len(operation.result.Content))
+		o.items = slices.DeleteFunc(o.items, func(item *Operation) bool { return item == operation })
+	}
+}
+
+// teardown cancels the running operation, which completes as failed with the reason
+func (o *operations) teardown(operation *Operation, reason string) {
+	operation.cancel()
+	operation.Status = OperationFailed
+	operation.Error = reason
+	operation.CompletedAt = ptr.To(o.now())
+	operation.result = api.NewToolCallResult("", api.NewToolError(api.ErrorCodeRateLimited, errors.New(reason)))
+}
+
+// callerItems returns the operations of the caller, the oldest first
+func (o *operations) callerItems(caller string) []*Operation {
+	ret := make([]*Operation, 0)
+	for _, operation := range o.items {
+		if operation.caller == caller {
+			ret = append(ret, operation)
+		}
+	}
+	return ret
+}
+
+// usage returns the number of running operations of the session and the size of their retained results
+func (o *operations) usage(session string) (running int, retainedBytes int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, operation := range o.items {
+		if operation.session != session {
+			continue
+		}
+		if operation.CompletedAt == nil {
+			running++
+		} else if operation.result != nil {
+			retainedBytes += int64(len(operation.result.Content))
+		}
+	}
+	return running, retainedBytes
+}
+
+// endSession cancels the running operations of the ended session and drops its results, which can't be retrieved anymore
+func (o *operations) endSession(session string) {
+	if session == "" {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.items = slices.DeleteFunc(o.items, func(operation *Operation) bool {
+		if operation.session == session {
+			operation.cancel()
+			return true
+		}
+		return false
+	})
+}
+
+// prune drops the expired operations, and the oldest completed ones of the caller beyond maxOperations
+func (o *operations) prune(caller string) {
+	o.items = slices.DeleteFunc(o.items, func(operation *Operation) bool {
+		return operation.CompletedAt != nil && o.now().Sub(*operation.CompletedAt) > operationRetention
+	})
+	items := o.callerItems(caller)
+	excess := len(items) - maxOperations + 1
+	completed := slices.DeleteFunc(items, func(operation *Operation) bool { return operation.CompletedAt == nil })
+	for _, operation := range completed[:min(len(completed), max(0, excess))] {
+		o.items = slices.DeleteFunc(o.items, func(item *Operation) bool { return item == operation })
+	}
+}
+
+// get returns a snapshot of the operation of the caller and its result (nil while running)
+func (o *operations) get(caller, id string) (*Operation, *api.ToolCallResult, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.prune(caller)
+	for _, operation := range o.items {
+		if operation.ID == id && operation.caller == caller {
+			ret := *operation
+			return &ret, operation.result, nil
+		}
+	}
+	return nil, nil, api.NewToolError(api.ErrorCodeNotFound, fmt.Errorf("operation %s not found (operations are kept for %s after completing)", id, operationRetention))
+}
+
+// list returns snapshots of the operations of the caller, the most recent first
+func (o *operations) list(caller string) []Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.prune(caller)
+	ret := make([]Operation, 0)
+	for i := len(o.items) - 1; i >= 0; i-- {
+		if o.items[i].caller == caller {
+			ret = append(ret, *o.items[i])
+		}
+	}
+	return ret
+}
+
+// close cancels the running operations
+func (o *operations) close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, operation := range o.items {
+		operation.cancel()
+	}
+}
+
+// WithAsyncParameter adds the async execution parameter to the input schema of the long-running tools
+func WithAsyncParameter() ToolMutator {
+	return func(tool api.ServerTool) api.ServerTool {
+		if !tool.IsLongRunning() {
+			return tool
+		}
+		if tool.Tool.InputSchema == nil {
+			tool.Tool.InputSchema = &jsonschema.Schema{Type: "object"}
+		}
+		if tool.Tool.InputSchema.Properties == nil {
+			tool.Tool.InputSchema.Properties = make(map[string]*jsonschema.Schema)
+		}
+		tool.Tool.InputSchema.Properties[AsyncParameterName] = &jsonschema.Schema{
+			Type: "boolean",
+			Description: "Optional parameter running the tool call in the background, the call returns the id of the operation immediately. " +
+				"Poll its status with " + OperationsStatusToolName + " and retrieve its result with " + OperationsResultToolName + " (Optional, default false)",
+		}
+		return tool
+	}
+}
+
+// operationsTools returns the tools to follow the background operations, their handlers are provided by the Server
+func (s *Server) operationsTools() []api.ServerTool {
+	return []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name: OperationsStatusToolName,
+				Description: "Get the status (running, succeeded, failed) of the operations started by the tool calls with async=true in this session. " +
+					"Returns the operation with the provided id, or all the operations of the session (most recent first) if no id is provided",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"id": {
+							Type:        "string",
+							Description: "Optional id of the operation, as returned by the tool call that started it",
+						},
+					},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Operations: Status",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					IdempotentHint:  ptr.To(true),
+					OpenWorldHint:   ptr.To(false),
+				},
+			},
+			ClusterAware: ptr.To(false),
+			Handler:      s.operationsStatusHandler,
+		},
+		{
+			Tool: api.Tool{
+				Name: OperationsResultToolName,
+				Description: "Get the result of a completed operation started by a tool call with async=true in this session, " +
+					"as the tool call would have returned it. Fails while the operation is still running",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"id": {
+							Type:        "string",
+							Description: "Id of the operation, as returned by the tool call that started it",
+						},
+					},
+					Required: []string{"id"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Operations: Result",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					IdempotentHint:  ptr.To(true),
+					OpenWorldHint:   ptr.To(false),
+				},
+			},
+			ClusterAware: ptr.To(false),
+			Handler:      s.operationsResultHandler,
+		},
+	}
+}
+
+func (s *Server) operationsStatusHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	caller := callerKey(params.Context, sessionIDFromContext(params.Context))
+	var status any
+	if id, ok := params.GetArguments()["id"].(string); ok && id != "" {
+		operation, _, err := s.operations.get(caller, id)
+		if err != nil {
+			return api.NewToolCallResult("", err), nil
+		}
+		status = operation
+	} else {
+		status = s.operations.list(caller)
+	}
+	ret, err := params.Marshal(status)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get the status of the operations: %w", err)), nil
+	}
+	return api.NewToolCallResult(ret, nil), nil
+}
+
+func (s *Server) operationsResultHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	id, ok := params.GetArguments()["id"].(string)
+	if !ok || id == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("missing argument id"))), nil
+	}
+	operation, result, err := s.operations.get(callerKey(params.Context, sessionIDFromContext(params.Context)), id)
+	if err != nil {
+		return api.NewToolCallResult("", err), nil
+	}
+	if result == nil {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeUnavailable,
+			fmt.Errorf("operation %s of %s is still running (started at %s), retry later", id, operation.Tool, operation.StartedAt.Format(time.RFC3339)))), nil
+	}
+	// the result has the output requested by the tool call that started the operation
+	ret := *result
+	return &ret, nil
+}
+
+// sessionIDFromContext returns the ID of the MCP session of the tool call, if any
+func sessionIDFromContext(ctx context.Context) string {
+	if session, ok := ctx.Value(mcplog.MCPSessionContextKey).(*mcp.ServerSession); ok && session != nil {
+		return session.ID()
+	}
+	return ""
+}
+
+// callerKey identifies the caller of a tool call by its session and the credentials (Authorization header) it provided,
+// so that the sessionless tool calls (stateless HTTP) of different identities are kept apart
+func callerKey(ctx context.Context, session string) string {
+	authorization, _ := ctx.Value(internalk8s.OAuthAuthorizationHeader).(string)
+	return credentialsKey(session, authorization)
+}
+
+// credentialsKey identifies the caller by its session and the provided credentials (Authorization header)
+func credentialsKey(session, authorization string) string {
+	if authorization == "" {
+		return session
+	}
+	hash := sha256.Sum256([]byte(authorization))
+	return session + "/" + hex.EncodeToString(hash[:])
+}
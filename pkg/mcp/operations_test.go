@@ -0,0 +1,280 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+type OperationsSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	originalToolsets []api.Toolset
+	release          chan struct{}
+}
+
+func (s *OperationsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.release = make(chan struct{})
+	s.originalToolsets = toolsets.Toolsets()
+	toolsets.Clear()
+	toolsets.Register(operationsTestToolset(s.release))
+	s.Cfg.Toolsets = []string{"operations-test"}
+}
+
+func (s *OperationsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	toolsets.Clear()
+	for _, toolset := range s.originalToolsets {
+		toolsets.Register(toolset)
+	}
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *OperationsSuite) startOperation(args map[string]any) *Operation {
+	toolResult, err := s.CallTool("slow_install", args)
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Require().False(toolResult.IsError, "call tool should succeed")
+	operation := &Operation{}
+	s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), operation))
+	return operation
+}
+
+func (s *OperationsSuite) operationStatus(id string) *Operation {
+	toolResult, err := s.CallTool("operations_status", map[string]any{"id": id})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Require().False(toolResult.IsError, "call tool should succeed")
+	operation := &Operation{}
+	s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), operation))
+	return operation
+}
+
+func (s *OperationsSuite) TestTools() {
+	s.InitMcpClient()
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err)
+	toolsByName := make(map[string]mcp.Tool)
+	for _, tool := range tools.Tools {
+		toolsByName[tool.Name] = tool
+	}
+	s.Run("adds the operations tools", func() {
+		s.Contains(toolsByName, "operations_status")
+		s.Contains(toolsByName, "operations_result")
+	})
+	s.Run("adds the async parameter to the long-running tools", func() {
+		s.Contains(toolsByName["slow_install"].InputSchema.Properties, "async")
+		s.NotContains(toolsByName["quick_list"].InputSchema.Properties, "async")
+	})
+	s.Run("without long-running tools", func() {
+		s.Cfg.DisabledTools = []string{"slow_install"}
+		s.Close()
+		s.mcpServer.Close()
+		s.InitMcpClient()
+		tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+		s.Require().NoError(err)
+		for _, tool := range tools.Tools {
+			s.NotContains([]string{"operations_status", "operations_result"}, tool.Name, "expected no operations tools")
+		}
+	})
+}
+
+func (s *OperationsSuite) TestAsyncOperation() {
+	s.InitMcpClient()
+	operation := s.startOperation(map[string]any{"async": true})
+	s.Run("returns the running operation immediately", func() {
+		s.NotEmpty(operation.ID)
+		s.Equal("slow_install", operation.Tool)
+		s.Equal(OperationRunning, operation.Status)
+	})
+	s.Run("operations_status returns the running operation", func() {
+		s.Equal(OperationRunning, s.operationStatus(operation.ID).Status)
+	})
+	s.Run("operations_result fails while running", func() {
+		toolResult, err := s.CallTool("operations_result", map[string]any{"id": operation.ID})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "is still running")
+	})
+	close(s.release)
+	s.Run("operations_status returns the completed operation", func() {
+		s.Eventually(func() bool { return s.operationStatus(operation.ID).Status == OperationSucceeded }, 5*time.Second, 50*time.Millisecond)
+		s.NotNil(s.operationStatus(operation.ID).CompletedAt)
+	})
+	s.Run("operations_result returns the result of the tool call", func() {
+		toolResult, err := s.CallTool("operations_result", map[string]any{"id": operation.ID})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Require().False(toolResult.IsError, "call tool should succeed")
+		s.Equal("installed: true", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("operations_status lists the operations of the session", func() {
+		toolResult, err := s.CallTool("operations_status", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		var operations []Operation
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &operations))
+		s.Require().Len(operations, 1)
+		s.Equal(operation.ID, operations[0].ID)
+	})
+}
+
+func (s *OperationsSuite) TestFailedOperation() {
+	s.InitMcpClient()
+	operation := s.startOperation(map[string]any{"async": true, "fail": true})
+	close(s.release)
+	s.Eventually(func() bool { return s.operationStatus(operation.ID).Status == OperationFailed }, 5*time.Second, 50*time.Millisecond)
+	s.Run("operations_status returns the error", func() {
+		s.Equal("timed out waiting for the release", s.operationStatus(operation.ID).Error)
+	})
+	s.Run("operations_result returns the error of the tool call", func() {
+		toolResult, err := s.CallTool("operations_result", map[string]any{"id": operation.ID})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("timed out waiting for the release", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *OperationsSuite) TestSyncCall() {
+	s.InitMcpClient()
+	close(s.release)
+	toolResult, err := s.CallTool("slow_install", map[string]any{})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Require().False(toolResult.IsError, "call tool should succeed")
+	s.Equal("installed: true", toolResult.Content[0].(mcp.TextContent).Text)
+}
+
+func (s *OperationsSuite) TestOperationNotFound() {
+	s.InitMcpClient()
+	s.Run("unknown operation", func() {
+		toolResult, err := s.CallTool("operations_result", map[string]any{"id": "unknown"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "operation unknown not found")
+	})
+	s.Run("operation of another session", func() {
+		operation := s.startOperation(map[string]any{"async": true})
+		other := test.NewMcpClient(s.T(), s.mcpServer.ServeHTTP())
+		defer other.Close()
+		toolResult, err := other.CallTool("operations_status", map[string]any{"id": operation.ID})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "not found")
+	})
+}
+
+func (s *OperationsSuite) TestOperationOfAnotherIdentity() {
+	s.Cfg.Stateless = true
+	s.InitMcpClient(test.WithTransport(transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer a-token"})))
+	operation := s.startOperation(map[string]any{"async": true})
+	other := test.NewMcpClient(s.T(), s.mcpServer.ServeHTTP(), test.WithTransport(transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer another-token"})))
+	defer other.Close()
+	s.Run("operations_status doesn't return the operation of another identity", func() {
+		toolResult, err := other.CallTool("operations_status", map[string]any{"id": operation.ID})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "not found")
+	})
+	s.Run("operations_status doesn't list the operations of another identity", func() {
+		toolResult, err := other.CallTool("operations_status", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		var operations []Operation
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &operations))
+		s.Empty(operations)
+	})
+	s.Run("operations_result doesn't return the result of another identity", func() {
+		close(s.release)
+		toolResult, err := other.CallTool("operations_result", map[string]any{"id": operation.ID})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "not found")
+	})
+}
+
+func (s *OperationsSuite) TestMaxOperationsPerCaller() {
+	o := newOperations(func() config.SessionLimitsConfig { return config.SessionLimitsConfig{} })
+	now := time.Now()
+	for i := 0; i < maxOperations; i++ {
+		o.items = append(o.items, &Operation{ID: fmt.Sprintf("a-%d", i), caller: "a", CompletedAt: &now, cancel: func() {}})
+	}
+	call := func(context.Context) (*api.ToolCallResult, error) { return api.NewToolCallResult("", nil), nil }
+	s.Run("operations of another caller don't evict the completed operations of the caller", func() {
+		o.start(s.T().Context(), "b", "slow_install", "", call)
+		s.Len(o.callerItems("a"), maxOperations)
+	})
+	s.Run("operations of the caller evict its oldest completed operation", func() {
+		o.start(s.T().Context(), "a", "slow_install", "", call)
+		s.Len(o.callerItems("a"), maxOperations)
+		s.Equal("a-1", o.callerItems("a")[0].ID)
+	})
+}
+
+func TestOperations(t *testing.T) {
+	suite.Run(t, new(OperationsSuite))
+}
+
+// operationsTestToolset provides a long-running tool completing once release is closed, and a quick tool
+func operationsTestToolset(release chan struct{}) api.Toolset {
+	return &mockToolsetWithTools{name: "operations-test", tools: []api.ServerTool{
+		{
+			Tool: api.Tool{Name: "slow_install", InputSchema: &jsonschema.Schema{Type: "object"}},
+			Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+				select {
+				case <-release:
+				case <-params.Done():
+					return api.NewToolCallResult("", params.Err()), nil
+				}
+				if fail, _ := params.GetArguments()["fail"].(bool); fail {
+					return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeTimeout, errors.New("timed out waiting for the release"))), nil
+				}
+				if size, ok := params.GetArguments()["size"].(float64); ok {
+					return api.NewToolCallResult(strings.Repeat("x", int(size)), nil), nil
+				}
+				return api.NewToolCallResult("installed: true", nil), nil
+			},
+			LongRunning: ptr.To(true),
+		},
+		{
+			Tool: api.Tool{Name: "quick_list", InputSchema: &jsonschema.Schema{Type: "object"}},
+			Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+				return api.NewToolCallResult("listed: true", nil), nil
+			},
+		},
+	}}
+}
+
+type mockToolsetWithTools struct {
+	name  string
+	tools []api.ServerTool
+}
+
+func (m *mockToolsetWithTools) GetName() string {
+	return m.name
+}
+
+func (m *mockToolsetWithTools) GetDescription() string {
+	return "Toolset for testing"
+}
+
+func (m *mockToolsetWithTools) GetTools(_ api.Openshift) []api.ServerTool {
+	return m.tools
+}
+
+func (m *mockToolsetWithTools) GetPrompts() []api.ServerPrompt {
+	return nil
+}
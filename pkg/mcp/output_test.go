@@ -0,0 +1,325 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+type OutputSuite struct {
+	BaseMcpSuite
+}
+
+func (s *OutputSuite) TestJsonOutput() {
+	s.Cfg.ListOutput = "table"
+	s.InitMcpClient()
+	s.Run("namespaces_list(output=json)", func() {
+		toolResult, err := s.CallTool("namespaces_list", map[string]interface{}{"output": "json"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		var decoded []unstructured.Unstructured
+		s.Run("has compact json content", func() {
+			s.NoErrorf(json.Unmarshal([]byte(text), &decoded), "invalid json content %s", text)
+			s.NotContains(text, "\n")
+			s.GreaterOrEqual(len(decoded), 3)
+		})
+	})
+	s.Run("resources_get(output=json)", func() {
+		toolResult, err := s.CallTool("resources_get", map[string]interface{}{
+			"apiVersion": "v1", "kind": "Namespace", "name": "default", "output": "json",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		decoded := &unstructured.Unstructured{}
+		s.Run("has json content", func() {
+			s.NoErrorf(json.Unmarshal([]byte(text), &decoded.Object), "invalid json content %s", text)
+			s.Equal("default", decoded.GetName())
+			s.Nil(decoded.Object["metadata"].(map[string]interface{})["managedFields"])
+		})
+	})
+	s.Run("events_list(output=json)", func() {
+		client := kubernetes.NewForConfigOrDie(envTestRestConfig)
+		_, err := client.CoreV1().Events("default").Create(s.T().Context(), &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "an-event-for-json-output"},
+			InvolvedObject: v1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: "a-pod", Namespace: "default"},
+			Type:           "Normal",
+			Message:        "The event message",
+		}, metav1.CreateOptions{})
+		s.Require().NoError(err, "failed to create event")
+		s.T().Cleanup(func() {
+			_ = client.CoreV1().Events("default").Delete(s.T().Context(), "an-event-for-json-output", metav1.DeleteOptions{})
+		})
+		toolResult, err := s.CallTool("events_list", map[string]interface{}{"output": "json"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("has valid json content", func() {
+			text := toolResult.Content[0].(mcp.TextContent).Text
+			var decoded any
+			s.NoErrorf(json.Unmarshal([]byte(text), &decoded), "invalid json content %s", text)
+		})
+		s.Run("has header in a separate content block", func() {
+			s.Require().Len(toolResult.Content, 2)
+			header := toolResult.Content[1].(mcp.TextContent).Text
+			s.Truef(strings.HasPrefix(header, "# "), "unexpected header %s", header)
+			s.Contains(header, "(JSON format)")
+		})
+	})
+	s.Run("namespaces_list(output=yaml) overrides configured table output", func() {
+		toolResult, err := s.CallTool("namespaces_list", map[string]interface{}{"output": "yaml"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("has yaml content", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "apiVersion: v1")
+		})
+	})
+	s.Run("namespaces_list(output=invalid)", func() {
+		toolResult, _ := s.CallTool("namespaces_list", map[string]interface{}{"output": "invalid"})
+		s.Run("returns error", func() {
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Equal("invalid output format: invalid", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+}
+
+func (s *OutputSuite) TestTableOutput() {
+	s.InitMcpClient()
+	s.Run("namespaces_list(output=table) overrides configured yaml output", func() {
+		toolResult, err := s.CallTool("namespaces_list", map[string]interface{}{"output": "table"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has server-side table columns", func() {
+			s.Regexp("APIVERSION\\s+KIND\\s+NAME\\s+STATUS\\s+AGE\\s+LABELS", text)
+			s.Regexp("v1\\s+Namespace\\s+default\\s+Active", text)
+		})
+	})
+	s.Run("resources_list(output=table)", func() {
+		toolResult, err := s.CallTool("resources_list", map[string]interface{}{
+			"apiVersion": "v1", "kind": "Namespace", "output": "table",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("has server-side table columns", func() {
+			s.Regexp("APIVERSION\\s+KIND\\s+NAME\\s+STATUS\\s+AGE\\s+LABELS", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("events_list(output=table)", func() {
+		toolResult, _ := s.CallTool("events_list", map[string]interface{}{"output": "table"})
+		s.Run("returns error", func() {
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Equal("output format table is not supported by events_list", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+}
+
+func (s *OutputSuite) TestJsonPath() {
+	s.InitMcpClient()
+	s.Run("resources_get(jsonpath=.metadata.name)", func() {
+		toolResult, err := s.CallTool("resources_get", map[string]interface{}{
+			"apiVersion": "v1", "kind": "Namespace", "name": "default", "jsonpath": ".metadata.name",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("returns only the projected field", func() {
+			s.Equal("default\n", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("namespaces_list(jsonpath=.metadata.name) with table output", func() {
+		toolResult, err := s.CallTool("namespaces_list", map[string]interface{}{"jsonpath": ".metadata.name", "output": "table"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		var decoded []output.Projection
+		s.Run("returns projection for each item", func() {
+			s.NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded))
+			s.Contains(decoded, output.Projection{Name: "default", Value: "default"})
+		})
+	})
+	s.Run("resources_get(jsonpath=.spec.missing)", func() {
+		toolResult, _ := s.CallTool("resources_get", map[string]interface{}{
+			"apiVersion": "v1", "kind": "Namespace", "name": "default", "jsonpath": ".spec.missing",
+		})
+		s.Run("returns error", func() {
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Equal("no fields matched the jsonpath expression .spec.missing", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+}
+
+func (s *OutputSuite) TestPrune() {
+	s.Cfg.PruneFields = []string{"system_metadata"}
+	s.InitMcpClient()
+	s.Run("resources_get() with server prune_fields", func() {
+		toolResult, err := s.CallTool("resources_get", map[string]interface{}{
+			"apiVersion": "v1", "kind": "Namespace", "name": "default",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		decoded := &unstructured.Unstructured{}
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded.Object))
+		s.Run("removes configured fields", func() {
+			s.Empty(decoded.GetUID())
+			s.Empty(decoded.GetResourceVersion())
+		})
+		s.Run("keeps other fields", func() {
+			s.Contains(decoded.Object, "status")
+		})
+	})
+	s.Run("resources_get(prune=[status]) overrides server prune_fields", func() {
+		toolResult, err := s.CallTool("resources_get", map[string]interface{}{
+			"apiVersion": "v1", "kind": "Namespace", "name": "default", "prune": []interface{}{"status"},
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		decoded := &unstructured.Unstructured{}
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded.Object))
+		s.Run("removes requested fields", func() {
+			s.NotContains(decoded.Object, "status")
+		})
+		s.Run("keeps server pruned fields", func() {
+			s.NotEmpty(decoded.GetUID())
+		})
+	})
+	s.Run("namespaces_list(prune=[status]) with yaml output", func() {
+		toolResult, err := s.CallTool("namespaces_list", map[string]interface{}{"prune": []interface{}{"status"}})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("removes requested fields from every item", func() {
+			s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "phase: Active")
+		})
+	})
+}
+
+func (s *OutputSuite) TestOutputTemplate() {
+	s.InitMcpClient()
+	s.Run("resources_get(output_template)", func() {
+		toolResult, err := s.CallTool("resources_get", map[string]interface{}{
+			"apiVersion": "v1", "kind": "Namespace", "name": "default", "output_template": "{{.metadata.name}} is {{.status.phase}}",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("returns rendered template", func() {
+			s.Equal("default is Active", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("namespaces_list(output_template) with table output", func() {
+		toolResult, err := s.CallTool("namespaces_list", map[string]interface{}{
+			"output": "table", "sort_by": "name", "output_template": "{{range .items}}[{{.metadata.name}}]{{end}}",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("returns rendered template for sorted items", func() {
+			s.Regexp(`\[default\].*\[ns-1\].*\[ns-2\]`, toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("resources_get(output_template, jsonpath)", func() {
+		toolResult, _ := s.CallTool("resources_get", map[string]interface{}{
+			"apiVersion": "v1", "kind": "Namespace", "name": "default", "output_template": "{{.metadata.name}}", "jsonpath": ".metadata.name",
+		})
+		s.Run("returns error", func() {
+			s.Truef(toolResult.IsError, "call tool should fail")
+			s.Equal("jsonpath and output_template are mutually exclusive", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+}
+
+func (s *OutputSuite) TestRedaction() {
+	s.Cfg.Redaction = config.RedactionConfig{Rules: []config.RedactionRuleConfig{
+		{Name: "^phase$", Replacement: "***"},
+		{Pattern: `(kubernetes\.io/metadata\.name: )\S+`, Replacement: "${1}<namespace>"},
+	}}
+	s.InitMcpClient()
+	s.Run("resources_get() with redaction rules", func() {
+		toolResult, err := s.CallTool("resources_get", map[string]interface{}{
+			"apiVersion": "v1", "kind": "Namespace", "name": "default",
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("redacts fields with matching name", func() {
+			s.Contains(text, "phase: '***'")
+			s.NotContains(text, "Active")
+		})
+		s.Run("redacts pattern matches", func() {
+			s.Contains(text, "kubernetes.io/metadata.name: <namespace>")
+		})
+	})
+	s.Run("namespaces_list(output=json) with redaction rules", func() {
+		toolResult, err := s.CallTool("namespaces_list", map[string]interface{}{"output": "json"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("redacts every item", func() {
+			text := toolResult.Content[0].(mcp.TextContent).Text
+			s.Contains(text, `"phase":"***"`)
+			s.NotContains(text, "Active")
+		})
+	})
+	s.Run("concurrent calls with redaction rules", func() {
+		texts := make([]string, 8)
+		var wg sync.WaitGroup
+		for i := range texts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				toolResult, err := s.CallTool("namespaces_list", map[string]interface{}{"output": "json"})
+				if err == nil && !toolResult.IsError {
+					texts[i] = toolResult.Content[0].(mcp.TextContent).Text
+				}
+			}()
+		}
+		wg.Wait()
+		s.Run("redacts the result of every call", func() {
+			for _, text := range texts {
+				s.Contains(text, `"phase":"***"`)
+				s.NotContains(text, "Active")
+			}
+		})
+	})
+}
+
+func TestOutput(t *testing.T) {
+	suite.Run(t, new(OutputSuite))
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type OwnersSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *OwnersSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	discovery := test.NewDiscoveryClientHandler()
+	discovery.APIResourceLists[1].APIResources = append(discovery.APIResourceLists[1].APIResources,
+		metav1.APIResource{Name: "replicasets", Kind: "ReplicaSet", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}})
+	s.mockServer.Handle(discovery)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *OwnersSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *OwnersSuite) TestOwnersTree() {
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "deployment-uid",
+			Annotations: map[string]string{"meta.helm.sh/release-name": "web", "meta.helm.sh/release-namespace": "default"}},
+	}
+	replicaSet := appsv1.ReplicaSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web-6c9f", Namespace: "default", UID: "replicaset-uid",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "deployment-uid", Controller: ptr.To(true)}}},
+	}
+	pod := v1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web-6c9f-abcde", Namespace: "default", UID: "pod-uid",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-6c9f", UID: "replicaset-uid", Controller: ptr.To(true)}}},
+	}
+	orphan := v1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "orphan", Namespace: "default", UID: "orphan-uid",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-6c9f", UID: "previous-replicaset-uid", Controller: ptr.To(true)}}},
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/apps/v1/namespaces/default/deployments/web":
+			test.WriteObject(w, deployment)
+		case "/apis/apps/v1/namespaces/default/deployments":
+			test.WriteObject(w, &appsv1.DeploymentList{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DeploymentList"}, Items: []appsv1.Deployment{*deployment}})
+		case "/apis/apps/v1/namespaces/default/replicasets/web-6c9f":
+			test.WriteObject(w, &replicaSet)
+		case "/apis/apps/v1/namespaces/default/replicasets":
+			test.WriteObject(w, &appsv1.ReplicaSetList{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSetList"}, Items: []appsv1.ReplicaSet{replicaSet}})
+		case "/api/v1/namespaces/default/pods/web-6c9f-abcde":
+			test.WriteObject(w, &pod)
+		case "/api/v1/namespaces/default/pods/orphan":
+			test.WriteObject(w, &orphan)
+		case "/api/v1/namespaces/default/pods":
+			test.WriteObject(w, &v1.PodList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"}, Items: []v1.Pod{pod, orphan}})
+		}
+	}))
+	s.InitMcpClient()
+	s.Run("owners_tree(Pod)", func() {
+		toolResult, err := s.CallTool("owners_tree", map[string]interface{}{"apiVersion": "v1", "kind": "Pod", "name": "web-6c9f-abcde"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment", func() {
+			s.Truef(strings.HasPrefix(text, "# Ownership tree of Pod web-6c9f-abcde (YAML format):\n"), "unexpected result %v", text)
+		})
+		var tree map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &tree))
+		s.Run("returns the root with its manager", func() {
+			s.Equal([]any{"Deployment default/web (managed by Helm release default/web)"}, tree["roots"])
+		})
+		s.Run("walks the owners up to the root", func() {
+			owners := tree["object"].(map[string]any)["owners"].([]any)
+			s.Require().Len(owners, 1)
+			s.Equal("ReplicaSet", owners[0].(map[string]any)["kind"])
+			s.Equal(true, owners[0].(map[string]any)["controller"])
+			owners = owners[0].(map[string]any)["owners"].([]any)
+			s.Require().Len(owners, 1)
+			s.Equal("Deployment", owners[0].(map[string]any)["kind"])
+		})
+		s.Run("omits dependents by default", func() {
+			s.NotContains(tree["object"], "dependents")
+		})
+	})
+	s.Run("owners_tree(Pod) with a recreated owner", func() {
+		toolResult, err := s.CallTool("owners_tree", map[string]interface{}{"apiVersion": "v1", "kind": "Pod", "name": "orphan"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "owner was recreated (uid mismatch)")
+	})
+	s.Run("owners_tree(Deployment, dependents=true)", func() {
+		toolResult, err := s.CallTool("owners_tree", map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web", "dependents": true})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		var tree map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(strings.SplitN(toolResult.Content[0].(mcp.TextContent).Text, "\n", 2)[1]), &tree))
+		s.Run("walks the dependents down to the pods", func() {
+			dependents := tree["object"].(map[string]any)["dependents"].([]any)
+			s.Require().Len(dependents, 1)
+			s.Equal("web-6c9f", dependents[0].(map[string]any)["name"])
+			dependents = dependents[0].(map[string]any)["dependents"].([]any)
+			s.Require().Len(dependents, 1)
+			s.Equal("web-6c9f-abcde", dependents[0].(map[string]any)["name"])
+		})
+		s.Run("lists all the resources", func() {
+			s.NotContains(tree, "skipped")
+		})
+	})
+	s.Run("owners_tree(missing name)", func() {
+		toolResult, _ := s.CallTool("owners_tree", map[string]interface{}{"apiVersion": "v1", "kind": "Pod"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "missing argument name")
+	})
+}
+
+func TestOwners(t *testing.T) {
+	suite.Run(t, new(OwnersSuite))
+}
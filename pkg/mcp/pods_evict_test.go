@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type PodsEvictSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	evictions  []*policyv1.Eviction
+}
+
+func (s *PodsEvictSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "policy/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget", Namespaced: true, Verbs: metav1.Verbs{"get", "list", "watch"}},
+		},
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.evictions = nil
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/default/pods/web-1", "/api/v1/namespaces/default/pods/db-1":
+			name := strings.TrimPrefix(req.URL.Path, "/api/v1/namespaces/default/pods/")
+			test.WriteObject(w, &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": strings.TrimSuffix(name, "-1")}}})
+		case "/apis/policy/v1/namespaces/default/poddisruptionbudgets":
+			test.WriteObject(w, &policyv1.PodDisruptionBudgetList{TypeMeta: metav1.TypeMeta{APIVersion: "policy/v1", Kind: "PodDisruptionBudgetList"}, Items: []policyv1.PodDisruptionBudget{{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+				Spec:       policyv1.PodDisruptionBudgetSpec{MinAvailable: ptrIntOrString(1), Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+				Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0, CurrentHealthy: 1, DesiredHealthy: 1, ExpectedPods: 1},
+			}}})
+		case "/api/v1/namespaces/default/pods/web-1/eviction":
+			eviction := &policyv1.Eviction{}
+			_ = json.NewDecoder(req.Body).Decode(eviction)
+			s.evictions = append(s.evictions, eviction)
+			w.WriteHeader(http.StatusCreated)
+			test.WriteObject(w, &metav1.Status{Status: metav1.StatusSuccess})
+		case "/api/v1/namespaces/default/pods/db-1/eviction":
+			w.WriteHeader(http.StatusTooManyRequests)
+			test.WriteObject(w, &metav1.Status{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Status"},
+				Status:   metav1.StatusFailure, Code: http.StatusTooManyRequests, Reason: metav1.StatusReasonTooManyRequests,
+				Message: "Cannot evict pod as it would violate the pod's disruption budget.",
+				Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{{Type: policyv1.DisruptionBudgetCause, Message: "The disruption budget db needs 1 healthy pods and has 1 currently"}}},
+			})
+		}
+	}))
+}
+
+func (s *PodsEvictSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PodsEvictSuite) TestPodsEvict() {
+	s.InitMcpClient()
+	s.Run("pods_evict(name=web-1)", func() {
+		toolResult, err := s.CallTool("pods_evict", map[string]interface{}{"namespace": "default", "name": "web-1"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("returns eviction", func() {
+			s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# Pod default/web-1 evicted successfully (YAML format):\n"),
+				"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+		s.Run("uses the Eviction API", func() {
+			s.Require().Len(s.evictions, 1)
+			s.Equal("web-1", s.evictions[0].Name)
+			s.Empty(s.evictions[0].DeleteOptions.DryRun)
+		})
+	})
+	s.Run("pods_evict(name=web-1, dry_run=true)", func() {
+		s.evictions = nil
+		toolResult, err := s.CallTool("pods_evict", map[string]interface{}{"namespace": "default", "name": "web-1", "dry_run": true})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("returns dry run result", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "(dry run, the Pod was not evicted)")
+		})
+		s.Run("requests a dry run eviction", func() {
+			s.Require().Len(s.evictions, 1)
+			s.Equal([]string{metav1.DryRunAll}, s.evictions[0].DeleteOptions.DryRun)
+		})
+	})
+	s.Run("pods_evict(name=db-1) violating PodDisruptionBudget", func() {
+		toolResult, err := s.CallTool("pods_evict", map[string]interface{}{"namespace": "default", "name": "db-1"})
+		s.Run("returns error", func() {
+			s.Nilf(err, "call tool should not return error object")
+			s.Truef(toolResult.IsError, "call tool should fail")
+		})
+		s.Run("names the PodDisruptionBudget", func() {
+			s.Contains(toolResult.Content[0].(mcp.TextContent).Text,
+				"evicting Pod db-1 would violate its PodDisruptionBudget db (0 disruptions allowed, 1 healthy Pods of the 1 desired)")
+		})
+		s.Run("returns disruption budget error code", func() {
+			structuredError, ok := toolResult.StructuredContent.(map[string]any)["error"].(map[string]any)
+			s.Require().Truef(ok, "expected structured error, got %v", toolResult.StructuredContent)
+			s.Equal("DISRUPTION_BUDGET", structuredError["code"])
+		})
+	})
+	s.Run("pods_evict(missing name)", func() {
+		toolResult, _ := s.CallTool("pods_evict", map[string]interface{}{})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "missing argument name")
+	})
+}
+
+func ptrIntOrString(value int) *intstr.IntOrString {
+	ret := intstr.FromInt32(int32(value))
+	return &ret
+}
+
+func TestPodsEvict(t *testing.T) {
+	suite.Run(t, new(PodsEvictSuite))
+}
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type PodsResizeSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	patch      string
+	pod        *v1.Pod
+}
+
+func (s *PodsResizeSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.patch = ""
+	resources := v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("256Mi")},
+		Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("512Mi")},
+	}
+	s.pod = &v1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app", Resources: resources}}},
+		Status:     v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{Name: "app", Resources: resources.DeepCopy()}}},
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch req.Method + " " + req.URL.Path {
+		case "GET /api/v1/namespaces/default/pods/web":
+			test.WriteObject(w, s.pod)
+		case "PATCH /api/v1/namespaces/default/pods/web/resize":
+			body, _ := io.ReadAll(req.Body)
+			s.patch = string(body)
+			// the kubelet applies the resize right away
+			s.pod.Spec.Containers[0].Resources.Limits[v1.ResourceMemory] = resource.MustParse("1Gi")
+			s.pod.Status.ContainerStatuses[0].Resources.Limits[v1.ResourceMemory] = resource.MustParse("1Gi")
+			test.WriteObject(w, s.pod)
+		case "PATCH /api/v1/namespaces/default/pods/old/resize":
+			w.WriteHeader(http.StatusNotFound)
+			test.WriteObject(w, &metav1.Status{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Status"}, Status: metav1.StatusFailure,
+				Reason: metav1.StatusReasonNotFound, Code: http.StatusNotFound, Message: "the server could not find the requested resource"})
+		case "GET /api/v1/namespaces/default/pods/old":
+			pod := s.pod.DeepCopy()
+			pod.Name = "old"
+			test.WriteObject(w, pod)
+		}
+	}))
+}
+
+func (s *PodsResizeSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PodsResizeSuite) TestPodsResize() {
+	s.InitMcpClient()
+	s.Run("pods_resize(limits={memory: 1Gi}) patches the resize subresource", func() {
+		toolResult, err := s.CallTool("pods_resize", map[string]any{"namespace": "default", "name": "web", "limits": map[string]any{"memory": "1Gi"}})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.JSONEq(`{"spec":{"containers":[{"name":"app","resources":{"limits":{"memory":"1Gi"}}}]}}`, s.patch)
+		s.Equal("# Resize of container app of pod default/web: Completed (YAML format):\n"+
+			"actual:\n  limits:\n    memory: 1Gi\n  requests:\n    cpu: 100m\n    memory: 256Mi\n"+
+			"container: app\n"+
+			"desired:\n  limits:\n    memory: 1Gi\n  requests:\n    cpu: 100m\n    memory: 256Mi\n"+
+			"namespace: default\npod: web\n"+
+			"previous:\n  limits:\n    memory: 512Mi\n  requests:\n    cpu: 100m\n    memory: 256Mi\n"+
+			"status: Completed\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("pods_resize on a cluster without the resize subresource is rejected", func() {
+		toolResult, err := s.CallTool("pods_resize", map[string]any{"namespace": "default", "name": "old", "requests": map[string]any{"cpu": "200m"}, "timeout": 0})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "the cluster doesn't support the in-place resize of the pods (resize subresource of Kubernetes 1.33+)")
+	})
+	s.Run("pods_resize with an unknown container is rejected", func() {
+		toolResult, err := s.CallTool("pods_resize", map[string]any{"namespace": "default", "name": "web", "container": "sidecar", "requests": map[string]any{"cpu": "200m"}})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to resize pod web in namespace default: container sidecar not found in pod web", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("pods_resize without resources is rejected", func() {
+		toolResult, err := s.CallTool("pods_resize", map[string]any{"namespace": "default", "name": "web"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to resize pod, provide the requests or limits to set", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestPodsResize(t *testing.T) {
+	suite.Run(t, new(PodsResizeSuite))
+}
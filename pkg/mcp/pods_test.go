@@ -322,6 +322,13 @@ func (s *PodsSuite) TestPodsGet() {
 			s.Truef(toolResult.IsError, "call tool should fail")
 			s.Equalf("failed to get pod not-found in namespace : pods \"not-found\" not found", toolResult.Content[0].(mcp.TextContent).Text, "invalid error message, got %v", toolResult.Content[0].(mcp.TextContent).Text)
 		})
+		s.Run("returns structured error with NOT_FOUND code", func() {
+			structuredError, ok := toolResult.StructuredContent.(map[string]any)["error"].(map[string]any)
+			s.Require().Truef(ok, "expected structured error, got %v", toolResult.StructuredContent)
+			s.Equal("NOT_FOUND", structuredError["code"])
+			s.Equal(toolResult.Content[0].(mcp.TextContent).Text, structuredError["message"])
+			s.NotEmpty(structuredError["remediation"])
+		})
 		s.Run("sends log notification", func() {
 			logNotification := capture.RequireLogNotification(s.T(), 2*time.Second)
 			s.Equal("info", logNotification.Level, "not found errors should log at info level")
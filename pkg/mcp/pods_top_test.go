@@ -3,6 +3,7 @@ package mcp
 import (
 	"net/http"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/BurntSushi/toml"
@@ -42,11 +43,71 @@ func (s *PodsTopSuite) TestPodsTopMetricsUnavailable() {
 		s.NoError(err, "call tool failed %v", err)
 		s.Require().NoError(err)
 		s.True(result.IsError, "call tool should have returned an error")
-		s.Equalf("failed to get pods top: metrics API is not available", result.Content[0].(mcp.TextContent).Text,
+		s.Truef(strings.HasPrefix(result.Content[0].(mcp.TextContent).Text, "failed to get pods top: metrics API is not available, failed to get the usage from the kubelet Summary API:"),
 			"call tool returned unexpected content: %s", result.Content[0].(mcp.TextContent).Text)
 	})
 }
 
+func (s *PodsTopSuite) TestPodsTopKubeletSummary() {
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/api/v1/pods":
+			_, _ = w.Write([]byte(`{"kind":"PodList","apiVersion":"v1","items":[` +
+				`{"metadata":{"name":"pod-1","namespace":"default"},"spec":{"nodeName":"node-1"}},` +
+				`{"metadata":{"name":"pod-2","namespace":"ns-1"},"spec":{"nodeName":"node-2"}},` +
+				`{"metadata":{"name":"pod-pending","namespace":"ns-1"}}` +
+				`]}`))
+		case "/api/v1/namespaces/default/pods/pod-1":
+			_, _ = w.Write([]byte(`{"kind":"Pod","apiVersion":"v1","metadata":{"name":"pod-1","namespace":"default"},"spec":{"nodeName":"node-1"}}`))
+		case "/api/v1/nodes/node-1/proxy/stats/summary":
+			_, _ = w.Write([]byte(`{"node":{"nodeName":"node-1"},"pods":[` +
+				`{"podRef":{"name":"pod-1","namespace":"default"},"containers":[` +
+				`{"name":"container-1","cpu":{"time":"2025-10-29T09:00:00Z","usageNanoCores":100000000},"memory":{"workingSetBytes":209715200}},` +
+				`{"name":"container-2","cpu":{"time":"2025-10-29T09:00:00Z","usageNanoCores":200000000},"memory":{"workingSetBytes":314572800}}` +
+				`]},` +
+				`{"podRef":{"name":"kube-proxy","namespace":"kube-system"},"containers":[` +
+				`{"name":"kube-proxy","cpu":{"time":"2025-10-29T09:00:00Z","usageNanoCores":1000000},"memory":{"workingSetBytes":1048576}}` +
+				`]}` +
+				`]}`))
+		default:
+			// node-2 kubelet is not reachable
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	s.InitMcpClient()
+
+	s.Run("pods_top(defaults) returns pod metrics from the kubelet Summary API", func() {
+		result, err := s.CallTool("pods_top", map[string]interface{}{})
+		s.Require().NotNil(result)
+		s.NoErrorf(err, "call tool failed %v", err)
+		textContent := result.Content[0].(mcp.TextContent).Text
+		s.Falsef(result.IsError, "call tool failed %v", textContent)
+
+		expectedRows := []string{
+			"default\\s+pod-1\\s+container-1\\s+100m\\s+200Mi",
+			"default\\s+pod-1\\s+container-2\\s+200m\\s+300Mi",
+		}
+		for _, row := range expectedRows {
+			s.Regexpf(row, textContent, "expected row '%s' not found in output:\n%s", row, textContent)
+		}
+		s.NotContainsf(textContent, "kube-proxy", "expected pods not selected to be excluded:\n%s", textContent)
+		s.NotContainsf(textContent, "pod-2", "expected pods of unreachable kubelets to be skipped:\n%s", textContent)
+	})
+
+	s.Run("pods_top(name=pod-1) returns pod metrics from the kubelet Summary API", func() {
+		result, err := s.CallTool("pods_top", map[string]interface{}{
+			"namespace": "default",
+			"name":      "pod-1",
+		})
+		s.Require().NotNil(result)
+		s.NoErrorf(err, "call tool failed %v", err)
+		textContent := result.Content[0].(mcp.TextContent).Text
+		s.Falsef(result.IsError, "call tool failed %v", textContent)
+		s.Regexpf("default\\s+pod-1\\s+container-2\\s+200m\\s+300Mi", textContent, "expected row not found in output:\n%s", textContent)
+	})
+}
+
 func (s *PodsTopSuite) TestPodsTopMetricsAvailable() {
 	s.discoveryHandler.AddAPIResourceList(metav1.APIResourceList{
 		GroupVersion: "metrics.k8s.io/v1beta1",
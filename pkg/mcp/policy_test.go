@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type PolicySuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *PolicySuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler(
+		metav1.APIResourceList{
+			GroupVersion: "constraints.gatekeeper.sh/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "k8sblocknodeport", Kind: "K8sBlockNodePort", Verbs: metav1.Verbs{"get", "list"}},
+			},
+		},
+		metav1.APIResourceList{
+			GroupVersion: "wgpolicyk8s.io/v1alpha2",
+			APIResources: []metav1.APIResource{
+				{Name: "policyreports", Kind: "PolicyReport", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+				{Name: "clusterpolicyreports", Kind: "ClusterPolicyReport", Verbs: metav1.Verbs{"get", "list"}},
+			},
+		},
+	))
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/constraints.gatekeeper.sh/v1beta1/k8sblocknodeport":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "constraints.gatekeeper.sh/v1beta1", "kind": "K8sBlockNodePortList"},
+				Items: []unstructured.Unstructured{{Object: map[string]any{
+					"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+					"kind":       "K8sBlockNodePort",
+					"metadata":   map[string]any{"name": "block-node-port"},
+					"status": map[string]any{"totalViolations": int64(1), "violations": []any{map[string]any{
+						"enforcementAction": "deny", "kind": "Service", "name": "api", "namespace": "shop", "message": "User is not allowed to create service of type NodePort",
+					}}},
+				}}},
+			})
+		case "/apis/wgpolicyk8s.io/v1alpha2/policyreports", "/apis/wgpolicyk8s.io/v1alpha2/namespaces/shop/policyreports":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "wgpolicyk8s.io/v1alpha2", "kind": "PolicyReportList"},
+				Items: []unstructured.Unstructured{{Object: map[string]any{
+					"apiVersion": "wgpolicyk8s.io/v1alpha2",
+					"kind":       "PolicyReport",
+					"metadata":   map[string]any{"name": "a1b2c3", "namespace": "shop"},
+					"scope":      map[string]any{"apiVersion": "apps/v1", "kind": "Deployment", "name": "api", "namespace": "shop"},
+					"results": []any{map[string]any{
+						"source": "kyverno", "policy": "disallow-latest-tag", "rule": "validate-image-tag", "result": "fail", "message": "Using a mutable image tag e.g. 'latest' is not allowed.",
+					}},
+				}}},
+			})
+		case "/apis/wgpolicyk8s.io/v1alpha2/clusterpolicyreports":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "wgpolicyk8s.io/v1alpha2", "kind": "ClusterPolicyReportList"},
+				Items: []unstructured.Unstructured{{Object: map[string]any{
+					"apiVersion": "wgpolicyk8s.io/v1alpha2",
+					"kind":       "ClusterPolicyReport",
+					"metadata":   map[string]any{"name": "cpol-require-ns-labels"},
+					"results": []any{map[string]any{
+						"source": "kyverno", "policy": "require-ns-labels", "rule": "check-team", "result": "warn", "message": "The label `team` is required.",
+						"resources": []any{map[string]any{"apiVersion": "v1", "kind": "Namespace", "name": "sandbox"}},
+					}},
+				}}},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"policy"}
+}
+
+func (s *PolicySuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *PolicySuite) TestViolationsList() {
+	s.InitMcpClient()
+	s.Run("policy_violations_list returns the Gatekeeper and Kyverno violations", func() {
+		toolResult, err := s.CallTool("policy_violations_list", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of violations", func() {
+			s.Truef(strings.HasPrefix(text, "# 3 policy violation(s) found in 3 policy and namespace group(s) (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the messages and resources", func() {
+			s.Contains(text, "policy: K8sBlockNodePort/block-node-port")
+			s.Contains(text, "- User is not allowed to create service of type NodePort")
+			s.Contains(text, "- Deployment/api")
+			s.Contains(text, "- Namespace/sandbox")
+		})
+	})
+	s.Run("policy_violations_list(namespace=shop) skips the cluster policy reports", func() {
+		toolResult, err := s.CallTool("policy_violations_list", map[string]interface{}{"namespace": "shop"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "require-ns-labels")
+	})
+	s.Run("policy_violations_list(policy=block-node-port) matches the constraint name", func() {
+		toolResult, err := s.CallTool("policy_violations_list", map[string]interface{}{"policy": "block-node-port"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# 1 policy violation(s) found in 1 policy and namespace group(s)"),
+			"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("policy_violations_list(policy=missing) returns no violations", func() {
+		toolResult, err := s.CallTool("policy_violations_list", map[string]interface{}{"policy": "missing"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Equal("# No policy violations found", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	suite.Run(t, new(PolicySuite))
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type ProbeAPIsSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	discovery  *test.DiscoveryClientHandler
+}
+
+func (s *ProbeAPIsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.discovery = test.NewDiscoveryClientHandler()
+	s.mockServer.Handle(s.discovery)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"core", "argocd", "flux"}
+	s.Cfg.ProbeAPIs = true
+}
+
+func (s *ProbeAPIsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ProbeAPIsSuite) toolNames() []string {
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err)
+	names := make([]string, 0, len(tools.Tools))
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func (s *ProbeAPIsSuite) TestUnservedAPIs() {
+	s.discovery.APIResourceLists = append(s.discovery.APIResourceLists, metav1.APIResourceList{
+		GroupVersion: "helm.toolkit.fluxcd.io/v2",
+		APIResources: []metav1.APIResource{{Name: "helmreleases", Kind: "HelmRelease", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}},
+	})
+	s.InitMcpClient()
+	names := s.toolNames()
+	s.Run("leaves out the tools of the APIs not served", func() {
+		s.NotContains(names, "argocd_applications_list")
+		s.NotContains(names, "argocd_application_sync")
+	})
+	s.Run("registers the tools of the APIs served", func() {
+		s.Contains(names, "flux_resources_list")
+	})
+	s.Run("registers the tools without optional APIs", func() {
+		s.Contains(names, "pods_list")
+	})
+}
+
+func (s *ProbeAPIsSuite) TestDisabled() {
+	s.Cfg.ProbeAPIs = false
+	s.InitMcpClient()
+	names := s.toolNames()
+	s.Contains(names, "argocd_applications_list")
+	s.Contains(names, "flux_resources_list")
+}
+
+func TestProbeAPIs(t *testing.T) {
+	suite.Run(t, new(ProbeAPIsSuite))
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+// checkPermissions rejects the calls of the mutating tools lacking any of the Kubernetes API permissions they require,
+// with all the missing permissions and the Roles granting them, instead of failing midway through the changes.
+// The calls whose permissions can't be checked aren't rejected, the returned header states the preflight was skipped.
+func (s *Server) checkPermissions(tool api.ServerTool, params api.ToolHandlerParams) (string, error) {
+	if !s.configuration.RbacPreflight || !tool.IsClusterAware() || ptr.Deref(tool.Tool.Annotations.ReadOnlyHint, false) {
+		return "", nil
+	}
+	if tool.Permissions == nil {
+		return fmt.Sprintf("# The RBAC preflight was skipped, %s doesn't declare the permissions it requires\n", tool.Tool.Name), nil
+	}
+	permissions, err := tool.Permissions(params)
+	if err != nil {
+		klog.V(3).Infof("Skipping the RBAC preflight of %s: %v", tool.Tool.Name, err)
+		return fmt.Sprintf("# The RBAC preflight was skipped, the permissions required by %s couldn't be determined: %v\n", tool.Tool.Name, err), nil
+	}
+	missing, err := internalk8s.NewCore(params).MissingPermissions(params.Context, permissions)
+	if err != nil {
+		return "", fmt.Errorf("failed to check the permissions of %s: %w", tool.Tool.Name, err)
+	}
+	if len(missing) == 0 {
+		return "", nil
+	}
+	descriptions := make([]string, 0, len(missing))
+	for _, permission := range missing {
+		descriptions = append(descriptions, permission.String())
+	}
+	var roles []string
+	for _, role := range internalk8s.PermissionsRoles(version.BinaryName+"-"+strings.ReplaceAll(tool.Tool.Name, "_", "-"), missing) {
+		yaml, err := output.MarshalYaml(role)
+		if err != nil {
+			return "", err
+		}
+		roles = append(roles, yaml)
+	}
+	return "", api.NewToolError(api.ErrorCodeRbacForbidden, fmt.Errorf("missing permissions for %s: %s\n# Grant them with (YAML format):\n%s",
+		tool.Tool.Name, strings.Join(descriptions, ", "), strings.Join(roles, "---\n")))
+}
@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	authv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type RbacPreflightSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	denied     map[string]bool
+	reviews    []authv1.ResourceAttributes
+	mutations  []string
+}
+
+func (s *RbacPreflightSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "authorization.k8s.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "selfsubjectaccessreviews", Kind: "SelfSubjectAccessReview", Namespaced: false, Verbs: metav1.Verbs{"create"}},
+		},
+	}))
+	s.denied, s.reviews, s.mutations = map[string]bool{}, nil, nil
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case req.Method == http.MethodPost && req.URL.Path == "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews":
+			review := &authv1.SelfSubjectAccessReview{}
+			body, _ := io.ReadAll(req.Body)
+			_, _, _ = scheme.Codecs.UniversalDeserializer().Decode(body, nil, review)
+			attributes := *review.Spec.ResourceAttributes
+			s.reviews = append(s.reviews, attributes)
+			review.Status.Allowed = !s.denied[api.Permission{Verb: attributes.Verb, Group: attributes.Group, Resource: attributes.Resource,
+				Subresource: attributes.Subresource, Namespace: attributes.Namespace}.String()]
+			test.WriteObject(w, review)
+		case req.Method == http.MethodGet && req.URL.Path == "/api/v1/namespaces/shop/pods/web-1":
+			test.WriteObject(w, &v1.Pod{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}, ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"}})
+		case req.Method == http.MethodGet && req.URL.Path == "/apis/apps/v1/namespaces/shop/deployments/web":
+			w.WriteHeader(http.StatusNotFound)
+		case req.Method == http.MethodGet:
+		default:
+			s.mutations = append(s.mutations, req.Method+" "+req.URL.Path)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.RbacPreflight = true
+}
+
+func (s *RbacPreflightSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *RbacPreflightSuite) TestMissingPermissions() {
+	s.denied["delete pods in namespace shop"] = true
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("pods_delete", map[string]any{"namespace": "shop", "name": "web-1"})
+	s.Run("rejects the call with the missing permissions and the Role granting them", func() {
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("missing permissions for pods_delete: delete pods in namespace shop\n"+
+			"# Grant them with (YAML format):\n"+
+			"apiVersion: rbac.authorization.k8s.io/v1\n"+
+			"kind: Role\n"+
+			"metadata:\n"+
+			"  name: kubernetes-mcp-server-pods-delete\n"+
+			"  namespace: shop\n"+
+			"rules:\n"+
+			"- apiGroups:\n"+
+			"  - \"\"\n"+
+			"  resources:\n"+
+			"  - pods\n"+
+			"  verbs:\n"+
+			"  - delete\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("reports the RBAC error code", func() {
+		structuredError, ok := toolResult.StructuredContent.(map[string]any)["error"].(map[string]any)
+		s.Require().Truef(ok, "expected structured error, got %v", toolResult.StructuredContent)
+		s.Equal(string(api.ErrorCodeRbacForbidden), structuredError["code"])
+	})
+	s.Run("checks the permissions of the tool", func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.Equal([]authv1.ResourceAttributes{
+			{Namespace: "shop", Verb: "get", Resource: "pods"},
+			{Namespace: "shop", Verb: "delete", Resource: "pods"},
+		}, s.reviews)
+	})
+	s.Run("doesn't call the tool", func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.Empty(s.mutations)
+	})
+}
+
+func (s *RbacPreflightSuite) TestMissingPermissionsOfManifest() {
+	s.denied["create deployments.apps in namespace shop"] = true
+	s.denied["create pods/exec in namespace shop"] = true
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("resources_create_or_update", map[string]any{
+		"resource": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  namespace: shop\n",
+	})
+	s.Require().NoError(err)
+	s.True(toolResult.IsError)
+	s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "missing permissions for resources_create_or_update: create deployments.apps in namespace shop\n")
+	s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "- apiGroups:\n  - apps\n  resources:\n  - deployments\n  verbs:\n  - create\n")
+}
+
+func (s *RbacPreflightSuite) TestGrantedPermissions() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("pods_delete", map[string]any{"namespace": "shop", "name": "web-1"})
+	s.Require().NoError(err)
+	s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Contains(s.mutations, "DELETE /api/v1/namespaces/shop/pods/web-1")
+}
+
+func (s *RbacPreflightSuite) TestConfigRolloutDryRun() {
+	s.InitMcpClient()
+	_, err := s.CallTool("config_rollout", map[string]any{"kind": "ConfigMap", "namespace": "shop", "name": "web", "dry_run": true})
+	s.Require().NoError(err)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Equal([]authv1.ResourceAttributes{
+		{Namespace: "shop", Verb: "get", Resource: "configmaps"},
+		{Namespace: "shop", Verb: "list", Group: "apps", Resource: "deployments"},
+		{Namespace: "shop", Verb: "list", Group: "apps", Resource: "statefulsets"},
+		{Namespace: "shop", Verb: "list", Group: "apps", Resource: "daemonsets"},
+	}, s.reviews, "expected only the permissions to find the workloads to restart")
+}
+
+func (s *RbacPreflightSuite) TestServiceProxyRequest() {
+	s.denied["create services/proxy in namespace shop"] = true
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("service_proxy_request", map[string]any{"namespace": "shop", "name": "web", "method": "POST", "path": "/refresh"})
+	s.Require().NoError(err)
+	s.Run("rejects the call with the missing proxy permission", func() {
+		s.True(toolResult.IsError)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "missing permissions for service_proxy_request: create services/proxy in namespace shop\n")
+	})
+	s.Run("doesn't send the request", func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.Empty(s.mutations)
+	})
+}
+
+func (s *RbacPreflightSuite) TestUndeclaredPermissions() {
+	originalToolsets := toolsets.Toolsets()
+	defer func() {
+		toolsets.Clear()
+		for _, toolset := range originalToolsets {
+			toolsets.Register(toolset)
+		}
+	}()
+	toolsets.Clear()
+	toolsets.Register(&mockToolsetWithTools{name: "rbac-preflight-test", tools: []api.ServerTool{
+		{
+			Tool: api.Tool{Name: "undeclared_change", InputSchema: &jsonschema.Schema{Type: "object"}},
+			Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+				return api.NewToolCallResult("changed: true", nil), nil
+			},
+		},
+	}})
+	s.Cfg.Toolsets = []string{"rbac-preflight-test"}
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("undeclared_change", map[string]any{})
+	s.Require().NoError(err)
+	s.Run("calls the tool", func() {
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	})
+	s.Run("reports the preflight was skipped", func() {
+		s.Equal("# The RBAC preflight was skipped, undeclared_change doesn't declare the permissions it requires\n"+
+			"changed: true", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *RbacPreflightSuite) TestDisabled() {
+	s.Cfg.RbacPreflight = false
+	s.denied["delete pods in namespace shop"] = true
+	s.InitMcpClient()
+	_, err := s.CallTool("pods_delete", map[string]any{"namespace": "shop", "name": "web-1"})
+	s.Require().NoError(err)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Empty(s.reviews, "expected no access reviews")
+	s.Contains(s.mutations, "DELETE /api/v1/namespaces/shop/pods/web-1")
+}
+
+func TestRbacPreflight(t *testing.T) {
+	suite.Run(t, new(RbacPreflightSuite))
+}
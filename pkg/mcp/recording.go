@@ -0,0 +1,253 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+	"github.com/containers/kubernetes-mcp-server/pkg/recording"
+)
+
+// initRecording sets up the recorder or the replayer based on the server configuration
+func (s *Server) initRecording() error {
+	if s.configuration.ReplayFile != "" {
+		replayer, err := recording.NewReplayer(s.configuration.ReplayFile)
+		if err != nil {
+			return err
+		}
+		s.replayer = replayer
+		klog.V(1).Infof("Replaying tool calls from %s", s.configuration.ReplayFile)
+	} else if s.configuration.RecordFile != "" {
+		recorder, err := recording.NewRecorder(s.configuration.RecordFile)
+		if err != nil {
+			return err
+		}
+		s.recorder = recorder
+		klog.V(1).Infof("Recording tool calls to %s", s.configuration.RecordFile)
+	}
+	return nil
+}
+
+// recordingMiddleware records tool calls and their results, or serves them from a previous recording in replay mode.
+// In replay mode, tool calls never reach the handlers and no cluster is contacted.
+// The arguments are recorded redacted (see redactArguments), and redacted the same way to look up their replay, as
+// are the results (see redactResult).
+func (s *Server) recordingMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method != "tools/call" || (s.recorder == nil && s.replayer == nil) {
+			return next(ctx, method, req)
+		}
+		params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+		if !ok {
+			return next(ctx, method, req)
+		}
+		toolCallRequest, err := GoSdkToolCallParamsToToolCallRequest(params)
+		if err != nil {
+			return next(ctx, method, req)
+		}
+		if s.replayer != nil {
+			entry, err := s.replayer.Replay(toolCallRequest.Name, s.redactArguments(toolCallRequest.GetArguments()))
+			if err != nil {
+				return NewTextResult("", err), nil
+			}
+			// the response is replayed as recorded, with its content blocks and its structured content (e.g. the error code)
+			replayed := &mcp.CallToolResult{IsError: entry.IsError, StructuredContent: entry.StructuredContent}
+			for _, text := range entry.TextBlocks() {
+				replayed.Content = append(replayed.Content, &mcp.TextContent{Text: text})
+			}
+			return replayed, nil
+		}
+		result, err := next(ctx, method, req)
+		if callToolResult, ok := result.(*mcp.CallToolResult); ok && err == nil {
+			entry := recording.Entry{
+				Tool:              toolCallRequest.Name,
+				Arguments:         s.redactArguments(toolCallRequest.GetArguments()),
+				StructuredContent: s.redactStructuredContent(callToolResult.StructuredContent),
+				IsError:           callToolResult.IsError,
+			}
+			for _, content := range callToolResult.Content {
+				if text, ok := content.(*mcp.TextContent); ok {
+					entry.Blocks = append(entry.Blocks, s.redactResult(text.Text))
+				}
+			}
+			if len(entry.Blocks) == 1 {
+				entry.Content, entry.Blocks = entry.Blocks[0], nil
+			}
+			if recordErr := s.recorder.Record(entry); recordErr != nil {
+				klog.Errorf("Failed to record tool call %s: %v", toolCallRequest.Name, recordErr)
+			}
+		}
+		return result, err
+	}
+}
+
+// redactArguments returns the arguments of a tool call as written to the recording file: the data of the Secrets
+// (the Secret manifests, e.g. of resources_create_or_update, and the Secret data of config_rollout) is masked, then
+// the built-in patterns (e.g. the passwords and tokens of the Helm values) and the configured redaction rules are
+// applied to the JSON of the arguments
+func (s *Server) redactArguments(arguments map[string]any) map[string]any {
+	if len(arguments) == 0 {
+		return arguments
+	}
+	masked := make(map[string]any, len(arguments))
+	for key, value := range arguments {
+		masked[key] = maskSecretManifests(value)
+	}
+	if masked["kind"] == "Secret" {
+		masked = maskSecretData(masked)
+	}
+	data, err := json.Marshal(masked)
+	if err != nil {
+		return map[string]any{"arguments": output.Redacted}
+	}
+	redacted := map[string]any{}
+	if err = json.Unmarshal([]byte(s.configuration.Redact(mcplog.Sanitize(string(data)))), &redacted); err != nil {
+		// the redaction rules must not expose the arguments they're meant to redact
+		return map[string]any{"arguments": output.Redacted}
+	}
+	return redacted
+}
+
+// redactResult returns the text of a tool result as written to the recording file: the data of the Secrets of its
+// YAML or JSON (e.g. of resources_get or resources_list) is masked, then the sensitive data is redacted the same way as
+// the arguments (see redactArguments)
+func (s *Server) redactResult(text string) string {
+	return s.configuration.Redact(mcplog.Sanitize(maskSecretText(text)))
+}
+
+// redactStructuredContent returns the structured content of a tool result as written to the recording file, redacted
+// as its text (see redactResult)
+func (s *Server) redactStructuredContent(content any) any {
+	if content == nil {
+		return nil
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return map[string]any{"content": output.Redacted}
+	}
+	var redacted any
+	if err = json.Unmarshal([]byte(s.redactResult(string(data))), &redacted); err != nil {
+		return map[string]any{"content": output.Redacted}
+	}
+	return redacted
+}
+
+// maskSecretManifests returns the value with the data of the Secrets it holds masked: the Secret objects (also the
+// nested ones, e.g. the items of a List), and the YAML or JSON strings of (multi-document) manifests
+func maskSecretManifests(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		if value["kind"] == "Secret" {
+			return maskSecretData(value)
+		}
+		masked := make(map[string]any, len(value))
+		for key := range value {
+			masked[key] = maskSecretManifests(value[key])
+		}
+		return masked
+	case []any:
+		masked := make([]any, len(value))
+		for i := range value {
+			masked[i] = maskSecretManifests(value[i])
+		}
+		return masked
+	case string:
+		return maskSecretText(value)
+	}
+	return value
+}
+
+// maskSecretText returns the YAML (multi-document) or JSON text with the data of the Secrets it holds masked, its
+// leading comments (e.g. the header of a tool result) are kept
+func maskSecretText(text string) string {
+	if !strings.Contains(text, "Secret") {
+		return text
+	}
+	header, body := commentHeader(text)
+	if trimmed := strings.TrimSpace(body); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var value any
+		if err := json.Unmarshal([]byte(trimmed), &value); err != nil {
+			return text
+		}
+		masked := maskSecretManifests(value)
+		if reflect.DeepEqual(value, masked) {
+			return text
+		}
+		data, err := json.Marshal(masked)
+		if err != nil {
+			return output.Redacted
+		}
+		return header + string(data)
+	}
+	documents := strings.Split(body, "\n---")
+	changed := false
+	for i, document := range documents {
+		var value any
+		if err := yaml.Unmarshal([]byte(document), &value); err != nil || !isManifest(value) {
+			continue
+		}
+		masked := maskSecretManifests(value)
+		if reflect.DeepEqual(value, masked) {
+			continue
+		}
+		data, err := yaml.Marshal(masked)
+		if err != nil {
+			return output.Redacted
+		}
+		documentHeader, _ := commentHeader(strings.TrimPrefix(document, "\n"))
+		documents[i], changed = "\n"+documentHeader+string(data), true
+	}
+	if !changed {
+		return text
+	}
+	return header + strings.TrimPrefix(strings.Join(documents, "\n---"), "\n")
+}
+
+// isManifest returns true if the parsed YAML or JSON is an object or a list (not a plain scalar text)
+func isManifest(value any) bool {
+	switch value.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+// commentHeader splits the leading comment lines of the text from the rest of it
+func commentHeader(text string) (string, string) {
+	header := ""
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		header += line
+	}
+	return header, text[len(header):]
+}
+
+// maskSecretData returns a copy of the Secret with the values of its data and stringData replaced by output.Redacted,
+// and the Secrets of its other fields (e.g. the last-applied-configuration annotation) masked
+func maskSecretData(secret map[string]any) map[string]any {
+	masked := make(map[string]any, len(secret))
+	for key, value := range secret {
+		masked[key] = maskSecretManifests(value)
+	}
+	for _, field := range []string{"data", "stringData"} {
+		data, ok := secret[field].(map[string]any)
+		if !ok {
+			continue
+		}
+		maskedData := make(map[string]any, len(data))
+		for key := range data {
+			maskedData[key] = output.Redacted
+		}
+		masked[field] = maskedData
+	}
+	return masked
+}
@@ -0,0 +1,192 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type RecordingSuite struct {
+	BaseMcpSuite
+}
+
+func (s *RecordingSuite) TestRecordAndReplay() {
+	recordFile := filepath.Join(s.T().TempDir(), "recording.jsonl")
+	s.Cfg.RecordFile = recordFile
+	s.InitMcpClient()
+	recorded, err := s.CallTool("namespaces_list", map[string]interface{}{})
+	s.Require().NoError(err)
+	s.Require().False(recorded.IsError, "call tool failed")
+	s.Close()
+	s.mcpServer.Close()
+
+	s.Cfg.RecordFile = ""
+	s.Cfg.ReplayFile = recordFile
+	s.InitMcpClient()
+	s.Run("replays recorded tool call", func() {
+		replayed, err := s.CallTool("namespaces_list", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.False(replayed.IsError, "replayed call failed")
+		s.Equal(recorded.Content[0].(mcp.TextContent).Text, replayed.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("returns error for tool call not recorded", func() {
+		replayed, err := s.CallTool("pods_list", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(replayed.IsError, "expected replay error")
+		s.Contains(replayed.Content[0].(mcp.TextContent).Text, "no recorded response for tool call")
+	})
+	s.Close()
+	s.mcpServer.Close()
+
+	s.Run("replays without kubeconfig", func() {
+		s.Cfg.KubeConfig = filepath.Join(s.T().TempDir(), "missing-kubeconfig")
+		var err error
+		s.mcpServer, err = NewServer(Configuration{StaticConfig: s.Cfg}, internalk8s.NewReplayProvider())
+		s.Require().NoError(err, "Expected no error creating MCP server")
+		s.McpClient = test.NewMcpClient(s.T(), s.mcpServer.ServeHTTP())
+		replayed, err := s.CallTool("namespaces_list", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.False(replayed.IsError, "replayed call failed")
+		s.Equal(recorded.Content[0].(mcp.TextContent).Text, replayed.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *RecordingSuite) TestReplayAsRecorded() {
+	mockServer := test.NewMockServer()
+	defer mockServer.Close()
+	originalToolsets := toolsets.Toolsets()
+	defer func() {
+		toolsets.Clear()
+		for _, toolset := range originalToolsets {
+			toolsets.Register(toolset)
+		}
+	}()
+	toolsets.Clear()
+	toolsets.Register(&mockToolsetWithTools{name: "recording-test", tools: []api.ServerTool{
+		{
+			Tool: api.Tool{Name: "structured", InputSchema: &jsonschema.Schema{Type: "object"}, Annotations: api.ToolAnnotations{ReadOnlyHint: ptr.To(true)}},
+			Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+				return api.NewStructuredToolCallResult("# 1 item(s) found:\n", `[{"name":"a"}]`, nil), nil
+			},
+		},
+		{
+			Tool: api.Tool{Name: "failing", InputSchema: &jsonschema.Schema{Type: "object"}, Annotations: api.ToolAnnotations{ReadOnlyHint: ptr.To(true)}},
+			Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+				return api.NewToolCallResult("", fmt.Errorf("failed to get: %w", apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "missing"))), nil
+			},
+		},
+	}})
+	s.Cfg.KubeConfig = mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"recording-test"}
+	s.Cfg.ListOutput = "json"
+	s.Cfg.RecordFile = filepath.Join(s.T().TempDir(), "recording.jsonl")
+	s.InitMcpClient()
+	recordedStructured, err := s.CallTool("structured", map[string]interface{}{})
+	s.Require().NoError(err)
+	s.Require().Len(recordedStructured.Content, 2, "expected the JSON content and its header")
+	recordedFailing, err := s.CallTool("failing", map[string]interface{}{})
+	s.Require().NoError(err)
+	s.Require().True(recordedFailing.IsError)
+	s.Close()
+	s.mcpServer.Close()
+
+	s.Cfg.ReplayFile, s.Cfg.RecordFile = s.Cfg.RecordFile, ""
+	s.InitMcpClient()
+	s.Run("replays the content blocks as recorded", func() {
+		replayed, err := s.CallTool("structured", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.Equal(recordedStructured.Content, replayed.Content)
+		s.JSONEq(`[{"name":"a"}]`, replayed.Content[0].(mcp.TextContent).Text, "expected the JSON content to remain valid JSON")
+	})
+	s.Run("replays the structured error as recorded", func() {
+		replayed, err := s.CallTool("failing", map[string]interface{}{})
+		s.Require().NoError(err)
+		s.True(replayed.IsError)
+		s.Equal(recordedFailing.Content, replayed.Content)
+		s.Require().NotNil(replayed.StructuredContent)
+		s.Equal(recordedFailing.StructuredContent, replayed.StructuredContent)
+		s.Equal("NOT_FOUND", replayed.StructuredContent.(map[string]any)["error"].(map[string]any)["code"])
+	})
+}
+
+func (s *RecordingSuite) TestRecordRedactsArguments() {
+	recordFile := filepath.Join(s.T().TempDir(), "recording.jsonl")
+	s.Cfg.RecordFile = recordFile
+	s.InitMcpClient()
+	resource := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: recorded-secret\n  namespace: default\nstringData:\n  password: hunter2-recorded\n"
+	toolResult, err := s.CallTool("resources_create_or_update", map[string]interface{}{"resource": resource})
+	s.Require().NoError(err)
+	s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	s.Close()
+	s.mcpServer.Close()
+	recorded, err := os.ReadFile(recordFile)
+	s.Require().NoError(err)
+	s.Run("masks the data of the Secret manifest", func() {
+		s.NotContains(string(recorded), "hunter2-recorded")
+		s.Contains(string(recorded), `stringData:\n  password: '[REDACTED]'\n`)
+	})
+	s.Run("keeps the rest of the manifest", func() {
+		s.Contains(string(recorded), `name: recorded-secret`)
+	})
+
+	s.Cfg.RecordFile, s.Cfg.ReplayFile = "", recordFile
+	s.InitMcpClient()
+	s.Run("replays the tool call with the Secret manifest", func() {
+		replayed, err := s.CallTool("resources_create_or_update", map[string]interface{}{"resource": resource})
+		s.Require().NoError(err)
+		s.Falsef(replayed.IsError, "replayed call failed: %v", replayed.Content)
+	})
+}
+
+func (s *RecordingSuite) TestRecordRedactsResults() {
+	s.InitMcpClient()
+	created, err := s.CallTool("resources_create_or_update", map[string]interface{}{
+		"resource": "apiVersion: v1\nkind: Secret\nmetadata:\n  name: recorded-result\n  namespace: default\nstringData:\n  password: hunter2-results\n",
+	})
+	s.Require().NoError(err)
+	s.Require().Falsef(created.IsError, "call tool failed: %v", created.Content)
+	s.Close()
+	s.mcpServer.Close()
+
+	recordFile := filepath.Join(s.T().TempDir(), "recording.jsonl")
+	s.Cfg.RecordFile = recordFile
+	s.InitMcpClient()
+	got, err := s.CallTool("resources_get", map[string]interface{}{"apiVersion": "v1", "kind": "Secret", "namespace": "default", "name": "recorded-result"})
+	s.Require().NoError(err)
+	s.Require().Falsef(got.IsError, "call tool failed: %v", got.Content)
+	listed, err := s.CallTool("resources_list", map[string]interface{}{"apiVersion": "v1", "kind": "Secret", "namespace": "default", "output": "json"})
+	s.Require().NoError(err)
+	s.Require().Falsef(listed.IsError, "call tool failed: %v", listed.Content)
+	s.Close()
+	s.mcpServer.Close()
+	recorded, err := os.ReadFile(recordFile)
+	s.Require().NoError(err)
+	s.Run("returns the Secret data to the client", func() {
+		s.Contains(got.Content[0].(mcp.TextContent).Text, "aHVudGVyMi1yZXN1bHRz")
+	})
+	s.Run("masks the Secret data of the recorded results", func() {
+		s.NotContains(string(recorded), "aHVudGVyMi1yZXN1bHRz")
+		s.NotContains(string(recorded), "hunter2-results")
+		s.Contains(string(recorded), `password: '[REDACTED]'`)
+	})
+	s.Run("keeps the rest of the recorded results", func() {
+		s.Contains(string(recorded), "name: recorded-result")
+	})
+}
+
+func TestRecording(t *testing.T) {
+	suite.Run(t, new(RecordingSuite))
+}
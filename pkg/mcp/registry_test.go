@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type RegistrySuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	registry   *httptest.Server
+}
+
+func (s *RegistrySuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	// Private registry reached with plain HTTP on the loopback interface, requiring Basic authentication
+	s.registry = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if username, password, ok := req.BasicAuth(); !ok || username != "robot" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch req.URL.Path {
+		case "/v2/shop/api/manifests/v2.0.0":
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+			w.Header().Set("Docker-Content-Digest", "sha256:4f2a9c1")
+			_, _ = w.Write([]byte(`{"schemaVersion":2,"config":{"digest":"sha256:config"},"layers":[{"size":2048}]}`))
+		case "/v2/shop/api/blobs/sha256:config":
+			_, _ = w.Write([]byte(`{"created":"2025-10-29T09:00:00Z","os":"linux","architecture":"amd64","config":{"Labels":{"version":"2.0.0"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`))
+		}
+	}))
+	registryHost := strings.TrimPrefix(s.registry.URL, "http://")
+	s.mockServer = test.NewMockServer()
+	discovery := test.NewDiscoveryClientHandler()
+	discovery.APIResourceLists[0].APIResources = append(discovery.APIResourceLists[0].APIResources,
+		metav1.APIResource{Name: "secrets", Kind: "Secret", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}})
+	s.mockServer.Handle(discovery)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v1/namespaces/shop/secrets" {
+			return
+		}
+		secrets := &v1.SecretList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "SecretList"}}
+		if req.URL.Query().Get("fieldSelector") == "type="+string(v1.SecretTypeDockerConfigJson) {
+			secrets.Items = append(secrets.Items, v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "shop"},
+				Type:       v1.SecretTypeDockerConfigJson,
+				Data:       map[string][]byte{v1.DockerConfigJsonKey: []byte(`{"auths":{"` + registryHost + `":{"username":"robot","password":"secret"}}}`)},
+			})
+		}
+		test.WriteObject(w, secrets)
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *RegistrySuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+	if s.registry != nil {
+		s.registry.Close()
+	}
+}
+
+func (s *RegistrySuite) TestRegistryImageInspect() {
+	s.InitMcpClient()
+	image := strings.TrimPrefix(s.registry.URL, "http://") + "/shop/api:v2.0.0"
+	s.Run("registry_image_inspect(namespace=shop) authenticates with the pull secrets", func() {
+		toolResult, err := s.CallTool("registry_image_inspect", map[string]interface{}{"image": image, "namespace": "shop"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the image", func() {
+			s.Truef(strings.HasPrefix(text, "# Image "+image+" (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the digest, creation date, and labels", func() {
+			s.Contains(text, "digest: sha256:4f2a9c1")
+			s.Contains(text, "created: \"2025-10-29T09:00:00Z\"")
+			s.Contains(text, "version: 2.0.0")
+			s.Contains(text, "platform: linux/amd64")
+			s.Contains(text, "authentication: pull secret shop/registry")
+		})
+	})
+	s.Run("registry_image_inspect(image=v9.9.9) reports the image is not published", func() {
+		toolResult, err := s.CallTool("registry_image_inspect", map[string]interface{}{
+			"image": strings.TrimPrefix(s.registry.URL, "http://") + "/shop/api:v9.9.9", "namespace": "shop",
+		})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "/shop/api:v9.9.9 is not published in the registry")
+	})
+	s.Run("registry_image_inspect without namespace requires credentials", func() {
+		toolResult, err := s.CallTool("registry_image_inspect", map[string]interface{}{"image": image})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "the registry requires credentials")
+	})
+	s.Run("registry_image_inspect(image=Invalid)", func() {
+		toolResult, err := s.CallTool("registry_image_inspect", map[string]interface{}{"image": "ghcr.io/Org/App"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to inspect image: invalid image reference")
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	suite.Run(t, new(RegistrySuite))
+}
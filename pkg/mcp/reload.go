@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultToolsReloadDebounceWindow is the default window the reloads requested by the watchers are coalesced within
+	DefaultToolsReloadDebounceWindow = 100 * time.Millisecond
+)
+
+// toolsReloader recomputes the tools, prompts, and resources of the server when the targets or their capabilities
+// change. The bursts of changes reported by the watchers (e.g. a kubeconfig rewritten context by context, an operator
+// installing its CRDs one API group at a time) are coalesced into a single recomputation, so that the clients are
+// notified once instead of once per change. The recomputations never overlap with each other nor with a configuration
+// reload.
+type toolsReloader struct {
+	reload         func() error
+	debounceWindow time.Duration
+	mu             sync.Mutex
+	debounceTimer  *time.Timer
+	closed         bool
+	// running serializes the recomputations and the configuration reloads
+	running sync.Mutex
+}
+
+func newToolsReloader(reload func() error) *toolsReloader {
+	debounceWindow := DefaultToolsReloadDebounceWindow
+
+	// Allow override via environment variable for testing
+	if envDebounce := os.Getenv("TOOLS_RELOAD_DEBOUNCE_WINDOW_MS"); envDebounce != "" {
+		if ms, err := strconv.Atoi(envDebounce); err == nil && ms > 0 {
+			debounceWindow = time.Duration(ms) * time.Millisecond
+			klog.V(2).Infof("Using custom tools reload debounce window: %v", debounceWindow)
+		}
+	}
+
+	return &toolsReloader{reload: reload, debounceWindow: debounceWindow}
+}
+
+// request schedules a recomputation once no other is requested within the debounce window.
+// It has the signature of the internalk8s.McpReload callback of the watchers, the errors of the recomputation are logged.
+func (r *toolsReloader) request() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	if r.debounceTimer != nil {
+		r.debounceTimer.Stop()
+	}
+	r.debounceTimer = time.AfterFunc(r.debounceWindow, func() {
+		r.mu.Lock()
+		if r.closed {
+			r.mu.Unlock()
+			return
+		}
+		r.debounceTimer = nil
+		r.mu.Unlock()
+		klog.V(2).Info("Reloading tools after a change of the targets or their capabilities")
+		if err := r.run(nil); err != nil {
+			klog.Errorf("Failed to reload tools: %v", err)
+		}
+	})
+	return nil
+}
+
+// run applies the change (e.g. a new configuration) and recomputes immediately, the pending request is superseded by it
+func (r *toolsReloader) run(change func()) error {
+	r.mu.Lock()
+	if r.debounceTimer != nil {
+		r.debounceTimer.Stop()
+		r.debounceTimer = nil
+	}
+	r.mu.Unlock()
+	r.running.Lock()
+	defer r.running.Unlock()
+	if change != nil {
+		change()
+	}
+	return r.reload()
+}
+
+// close cancels the pending request, the requests made after closing are ignored
+func (r *toolsReloader) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	if r.debounceTimer != nil {
+		r.debounceTimer.Stop()
+		r.debounceTimer = nil
+	}
+}
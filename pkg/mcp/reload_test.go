@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ToolsReloaderSuite struct {
+	suite.Suite
+	reloads  atomic.Int32
+	reloader *toolsReloader
+}
+
+func (s *ToolsReloaderSuite) SetupTest() {
+	s.T().Setenv("TOOLS_RELOAD_DEBOUNCE_WINDOW_MS", "50")
+	s.reloads.Store(0)
+	s.reloader = newToolsReloader(func() error {
+		s.reloads.Add(1)
+		return nil
+	})
+}
+
+func (s *ToolsReloaderSuite) TearDownTest() {
+	s.reloader.close()
+}
+
+func (s *ToolsReloaderSuite) TestRequest() {
+	s.Run("coalesces the requests within the debounce window", func() {
+		for i := 0; i < 5; i++ {
+			s.NoError(s.reloader.request())
+			time.Sleep(10 * time.Millisecond)
+		}
+		s.Eventually(func() bool { return s.reloads.Load() == 1 }, time.Second, 10*time.Millisecond)
+		time.Sleep(100 * time.Millisecond)
+		s.Equal(int32(1), s.reloads.Load(), "expected a single reload for the burst of requests")
+	})
+	s.Run("reloads again for a later request", func() {
+		s.NoError(s.reloader.request())
+		s.Eventually(func() bool { return s.reloads.Load() == 2 }, time.Second, 10*time.Millisecond)
+	})
+	s.Run("logs the errors of the reload", func() {
+		s.reloader.reload = func() error { return errors.New("targets unavailable") }
+		s.NoError(s.reloader.request(), "expected the watchers not to get the errors of the debounced reload")
+	})
+}
+
+func (s *ToolsReloaderSuite) TestRun() {
+	s.Run("applies the change and reloads immediately", func() {
+		var changed bool
+		s.NoError(s.reloader.run(func() { changed = true }))
+		s.True(changed)
+		s.Equal(int32(1), s.reloads.Load())
+	})
+	s.Run("supersedes the pending request", func() {
+		s.NoError(s.reloader.request())
+		s.NoError(s.reloader.run(nil))
+		time.Sleep(100 * time.Millisecond)
+		s.Equal(int32(2), s.reloads.Load(), "expected the pending request to be cancelled")
+	})
+	s.Run("returns the errors of the reload", func() {
+		s.reloader.reload = func() error { return errors.New("targets unavailable") }
+		s.EqualError(s.reloader.run(nil), "targets unavailable")
+	})
+}
+
+func (s *ToolsReloaderSuite) TestClose() {
+	s.NoError(s.reloader.request())
+	s.reloader.close()
+	s.NoError(s.reloader.request())
+	time.Sleep(100 * time.Millisecond)
+	s.Equal(int32(0), s.reloads.Load(), "expected no reload after closing")
+}
+
+func TestToolsReloader(t *testing.T) {
+	suite.Run(t, new(ToolsReloaderSuite))
+}
@@ -13,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/rbac/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -535,6 +536,108 @@ func (s *ResourcesSuite) TestResourcesCreateOrUpdate() {
 	})
 }
 
+func (s *ResourcesSuite) TestResourcesCreateOrUpdateDiff() {
+	s.InitMcpClient()
+	configMapYaml := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a-cm-diff\n  namespace: default\ndata:\n  key: value\n"
+	s.Run("resources_create_or_update(diff=true) for new resource", func() {
+		toolResult, err := s.CallTool("resources_create_or_update", map[string]interface{}{"resource": configMapYaml, "diff": true})
+		s.Run("returns success", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("returns whole resource as added", func() {
+			content := toolResult.Content[0].(mcp.TextContent).Text
+			s.Truef(strings.HasPrefix(content, "# The following changes (unified diff) have been applied successfully\n"), "Expected diff message, got %v", content)
+			s.Contains(content, "+kind: ConfigMap\n")
+			s.Contains(content, "+  key: value\n")
+		})
+	})
+	s.Run("resources_create_or_update(diff=true) for updated resource", func() {
+		toolResult, err := s.CallTool("resources_create_or_update", map[string]interface{}{
+			"resource": strings.Replace(configMapYaml, "key: value", "key: updated", 1), "diff": true,
+		})
+		s.Run("returns success", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("returns only changed fields", func() {
+			content := toolResult.Content[0].(mcp.TextContent).Text
+			s.Contains(content, "--- ConfigMap default/a-cm-diff (before)\n+++ ConfigMap default/a-cm-diff (after)\n")
+			s.Contains(content, "-  key: value\n+  key: updated\n")
+			s.NotContains(content, "+kind: ConfigMap")
+		})
+	})
+	s.Run("resources_create_or_update(diff=true) for unchanged resource", func() {
+		toolResult, err := s.CallTool("resources_create_or_update", map[string]interface{}{
+			"resource": strings.Replace(configMapYaml, "key: value", "key: updated", 1), "diff": true,
+		})
+		s.Run("returns success", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("returns no changes message", func() {
+			s.Equal("# No changes were applied to the resources", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+}
+
+func (s *ResourcesSuite) TestResourcesCreateOrUpdateTransaction() {
+	s.InitMcpClient()
+	kc := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	_, _ = kc.CoreV1().ConfigMaps("default").Create(s.T().Context(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-cm-transaction-updated"},
+		Data:       map[string]string{"key": "value"},
+	}, metav1.CreateOptions{})
+	resources := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a-cm-transaction-updated\n  namespace: default\ndata:\n  key: updated\n" +
+		"---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a-cm-transaction-created\n  namespace: default\ndata:\n  key: created\n"
+	var id string
+	s.Run("resources_create_or_update(transaction=true)", func() {
+		toolResult, err := s.CallTool("resources_create_or_update", map[string]interface{}{"resource": resources, "transaction": true})
+		s.Run("returns success", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("returns the transaction ID", func() {
+			content := toolResult.Content[0].(mcp.TextContent).Text
+			matches := regexp.MustCompile(`^# Transaction (\S+) recorded the previous state of the resources`).FindStringSubmatch(content)
+			s.Require().Lenf(matches, 2, "Expected transaction header, got %v", content)
+			id = matches[1]
+			s.Contains(content, "# The following resources (YAML) have been created or updated successfully\n")
+		})
+		s.Run("applies the resources", func() {
+			updated, _ := kc.CoreV1().ConfigMaps("default").Get(s.T().Context(), "a-cm-transaction-updated", metav1.GetOptions{})
+			s.Equal("updated", updated.Data["key"])
+		})
+	})
+	s.Run("transactions_rollback(id)", func() {
+		toolResult, err := s.CallTool("transactions_rollback", map[string]interface{}{"id": id})
+		s.Run("returns success", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+			s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# Transaction "+id+" has been rolled back successfully"),
+				"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+		s.Run("restores the updated resource", func() {
+			updated, _ := kc.CoreV1().ConfigMaps("default").Get(s.T().Context(), "a-cm-transaction-updated", metav1.GetOptions{})
+			s.Equal("value", updated.Data["key"])
+		})
+		s.Run("deletes the created resource", func() {
+			_, err := kc.CoreV1().ConfigMaps("default").Get(s.T().Context(), "a-cm-transaction-created", metav1.GetOptions{})
+			s.Truef(apierrors.IsNotFound(err), "Expected created resource to be deleted, got %v", err)
+		})
+	})
+	s.Run("transactions_rollback(id) already rolled back", func() {
+		toolResult, _ := s.CallTool("transactions_rollback", map[string]interface{}{"id": id})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "transaction was already rolled back: "+id)
+	})
+	s.Run("transactions_rollback(id=unknown)", func() {
+		toolResult, _ := s.CallTool("transactions_rollback", map[string]interface{}{"id": "unknown"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "transaction not found: unknown")
+	})
+}
+
 func (s *ResourcesSuite) TestResourcesCreateOrUpdateDenied() {
 	s.Require().NoError(toml.Unmarshal([]byte(`
 		denied_resources = [
@@ -831,6 +934,27 @@ func (s *ResourcesSuite) TestResourcesScale() {
 			s.Equalf(int32(5), *deployment.Spec.Replicas, "expected 5 replicas in deployment, got %d", *deployment.Spec.Replicas)
 		})
 	})
+	s.Run("resources_scale update with diff returns changed fields", func() {
+		result, err := s.CallTool("resources_scale", map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"namespace":  "default",
+			"name":       deploymentName,
+			"scale":      3,
+			"diff":       true,
+		})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(result.IsError, "call tool failed: %v", result.Content)
+		})
+		s.Run("returns diff of replicas", func() {
+			content := result.Content[0].(mcp.TextContent).Text
+			s.Truef(strings.HasPrefix(content, "# The following changes (unified diff) have been applied successfully\n"),
+				"Expected diff message, got %v", content)
+			s.Contains(content, "--- Scale default/"+deploymentName+" (before)\n")
+			s.Contains(content, "-  replicas: 5\n+  replicas: 3\n")
+		})
+	})
 	s.Run("resources_scale with nonexistent resource", func() {
 		capture := s.StartCapturingLogNotifications()
 		toolResult, _ := s.CallTool("resources_scale", map[string]interface{}{
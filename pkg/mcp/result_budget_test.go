@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/utils/ptr"
+)
+
+type ResultBudgetSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	originalToolsets []api.Toolset
+}
+
+func (s *ResultBudgetSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.originalToolsets = toolsets.Toolsets()
+	toolsets.Clear()
+	toolsets.Register(resultBudgetTestToolset())
+	s.Cfg.Toolsets = []string{"result-budget-test"}
+	s.Cfg.ResultBudget.MaxTokens = 50
+}
+
+func (s *ResultBudgetSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	toolsets.Clear()
+	for _, toolset := range s.originalToolsets {
+		toolsets.Register(toolset)
+	}
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ResultBudgetSuite) TestResultBudget() {
+	s.InitMcpClient()
+	s.Run("reduces the results exceeding the budget", func() {
+		toolResult, err := s.CallTool("list_items", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Require().False(toolResult.IsError, "call tool should succeed")
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.True(strings.HasPrefix(text, "# The result was reduced to fit its 200 bytes budget (first 11 of 100 items kept)"), "unexpected result: %s", text)
+	})
+	s.Run("reduces the results before converting them to JSON", func() {
+		toolResult, err := s.CallTool("list_items", map[string]any{"output": "json"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Require().False(toolResult.IsError, "call tool should succeed")
+		_, body, _ := strings.Cut(toolResult.Content[0].(mcp.TextContent).Text, "\n")
+		s.True(strings.HasPrefix(body, `[{"name":"item-0000"},`), "unexpected result: %s", body)
+		s.True(strings.HasSuffix(body, `{"name":"item-0010"}]`), "unexpected result: %s", body)
+	})
+	s.Run("leaves the results within the budget unchanged", func() {
+		newConfig := *s.Cfg
+		newConfig.ResultBudget.MaxTokens = 0
+		s.Require().NoError(s.mcpServer.ReloadConfiguration(&newConfig))
+		toolResult, err := s.CallTool("list_items", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Equal(100, strings.Count(toolResult.Content[0].(mcp.TextContent).Text, "- name: "))
+	})
+}
+
+func TestResultBudget(t *testing.T) {
+	suite.Run(t, new(ResultBudgetSuite))
+}
+
+// resultBudgetTestToolset provides a read-only tool listing 100 items
+func resultBudgetTestToolset() api.Toolset {
+	return &mockToolsetWithTools{name: "result-budget-test", tools: []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name:        "list_items",
+				InputSchema: &jsonschema.Schema{Type: "object"},
+				Annotations: api.ToolAnnotations{ReadOnlyHint: ptr.To(true)},
+			},
+			Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+				sb := &strings.Builder{}
+				for i := range 100 {
+					fmt.Fprintf(sb, "- name: item-%04d\n", i)
+				}
+				return api.NewToolCallResult(sb.String(), nil), nil
+			},
+		},
+	}}
+}
@@ -0,0 +1,224 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/workspace"
+)
+
+// MaxAgeParameterName is the name of the parameter bounding the age of the cached result returned by a tool call
+const MaxAgeParameterName = "max_age"
+
+// resultCacheMetaKey is the key of the cache metadata in the _meta of the tool call results
+const resultCacheMetaKey = "cache"
+
+// resultCacheEntry is a cached tool call result
+type resultCacheEntry struct {
+	result   api.ToolCallResult
+	storedAt time.Time
+}
+
+// resultCache keeps the results of the read-only tool calls for a short time, keyed on the tool, its arguments,
+// its target, and the credentials of the call.
+// The results are also returned, marked as stale, by the calls failing because the cluster is unreachable.
+type resultCache struct {
+	mu      sync.Mutex
+	entries map[string]*resultCacheEntry
+	now     func() time.Time
+	config  func() config.ResultCacheConfig
+}
+
+func newResultCache(config func() config.ResultCacheConfig) *resultCache {
+	return &resultCache{entries: make(map[string]*resultCacheEntry), now: time.Now, config: config}
+}
+
+// resultCacheKey identifies the tool call, the arguments controlling the call itself (async, max_age) are left out
+func resultCacheKey(tool string, params api.ToolHandlerParams) (string, error) {
+	arguments := maps.Clone(params.GetArguments())
+	delete(arguments, AsyncParameterName)
+	delete(arguments, MaxAgeParameterName)
+	// maps are marshalled with their keys sorted
+	rawArguments, err := json.Marshal(arguments)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	for _, part := range []string{tool, string(rawArguments), params.Target, params.ListOutput.GetName(), internalk8s.CredentialsKey(params.KubernetesClient)} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	// the workspace references resolve to the files of the session, which may be rewritten between identical calls
+	for _, reference := range workspaceReferences(arguments) {
+		content, err := workspace.Resolve(params.Context, reference)
+		if err != nil {
+			return "", err
+		}
+		hash.Write(content)
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// workspaceReferences returns the workspace references found in the (nested) arguments, sorted
+func workspaceReferences(value any) []string {
+	var references []string
+	switch v := value.(type) {
+	case string:
+		if workspace.IsReference(v) {
+			references = append(references, v)
+		}
+	case []any:
+		for _, item := range v {
+			references = append(references, workspaceReferences(item)...)
+		}
+	case map[string]any:
+		for _, item := range v {
+			references = append(references, workspaceReferences(item)...)
+		}
+	}
+	slices.Sort(references)
+	return references
+}
+
+// get returns the cached result of the call if it's not older than maxAge
+func (c *resultCache) get(key string, maxAge time.Duration) (*resultCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || c.now().Sub(entry.storedAt) > maxAge {
+		return nil, false
+	}
+	return entry, true
+}
+
+// put caches the result of the call, dropping the results older than the retention and the oldest ones beyond the max entries
+func (c *resultCache) put(key string, result api.ToolCallResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cfg := c.config()
+	now := c.now()
+	for k, entry := range c.entries {
+		if now.Sub(entry.storedAt) > cfg.GetRetention() {
+			delete(c.entries, k)
+		}
+	}
+	for len(c.entries) >= cfg.GetMaxEntries() {
+		var oldestKey string
+		for k, entry := range c.entries {
+			if oldestKey == "" || entry.storedAt.Before(c.entries[oldestKey].storedAt) {
+				oldestKey = k
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+	c.entries[key] = &resultCacheEntry{result: result, storedAt: now}
+}
+
+// clear drops the cached results, which may be outdated by a mutating tool call
+func (c *resultCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// isResultCacheable returns true if the results of the tool can be cached, the tools reading the state of the server
+// (e.g. the operations) are never cached
+func isResultCacheable(tool api.ServerTool) bool {
+	return ptr.Deref(tool.Tool.Annotations.ReadOnlyHint, false) && tool.IsClusterAware() && !tool.IsTargetListProvider()
+}
+
+// WithMaxAgeParameter adds the max_age parameter to the input schema of the cacheable tools if the result cache is enabled
+func WithMaxAgeParameter(cacheConfig config.ResultCacheConfig) ToolMutator {
+	return func(tool api.ServerTool) api.ServerTool {
+		if cacheConfig.GetMaxAge() <= 0 || !isResultCacheable(tool) {
+			return tool
+		}
+		if tool.Tool.InputSchema == nil {
+			tool.Tool.InputSchema = &jsonschema.Schema{Type: "object"}
+		}
+		if tool.Tool.InputSchema.Properties == nil {
+			tool.Tool.InputSchema.Properties = make(map[string]*jsonschema.Schema)
+		}
+		tool.Tool.InputSchema.Properties[MaxAgeParameterName] = &jsonschema.Schema{
+			Type: "integer",
+			Description: fmt.Sprintf("Optional maximum age (seconds) of the cached result of an identical previous call returned instead of calling the tool again, "+
+				"0 for a fresh result (Optional, default and maximum %d)", int(cacheConfig.GetMaxAge().Seconds())),
+			Minimum: ptr.To(float64(0)),
+		}
+		return tool
+	}
+}
+
+// cachedCallTool returns the cached result of an identical call not older than the requested max age,
+// or calls the tool and caches its successful result.
+// If the call fails because the cluster is unreachable, the cached result not older than the stale max age is returned
+// instead, marked as stale.
+func (s *Server) cachedCallTool(tool api.ServerTool, params api.ToolHandlerParams, request *mcp.CallToolRequest) (*api.ToolCallResult, mcp.Meta, error) {
+	maxAge, staleMaxAge := s.configuration.ResultCache.GetMaxAge(), s.configuration.ResultCache.GetStaleMaxAge()
+	if (maxAge <= 0 && staleMaxAge <= 0) || !isResultCacheable(tool) {
+		result, err := s.callTool(tool, params, request)
+		return result, nil, err
+	}
+	if requested, ok := params.GetArguments()[MaxAgeParameterName].(float64); ok {
+		maxAge = min(maxAge, time.Duration(max(0, requested))*time.Second)
+	}
+	key, err := resultCacheKey(tool.Tool.Name, params)
+	if err != nil {
+		result, err := s.callTool(tool, params, request)
+		return result, nil, err
+	}
+	if entry, ok := s.resultCache.get(key, maxAge); ok && maxAge > 0 {
+		result := entry.result
+		age := s.resultCache.now().Sub(entry.storedAt)
+		addResultHeader(&result, fmt.Sprintf("# Cached result of an identical call %s ago, set %s=0 for a fresh result\n",
+			age.Truncate(time.Second), MaxAgeParameterName), params.ListOutput)
+		return &result, mcp.Meta{resultCacheMetaKey: map[string]any{
+			"hit":        true,
+			"storedAt":   entry.storedAt.UTC().Format(time.RFC3339),
+			"ageSeconds": int(age.Seconds()),
+		}}, nil
+	}
+	result, err := s.callTool(tool, params, request)
+	if err == nil && result.Error == nil {
+		s.resultCache.put(key, *result)
+		return result, mcp.Meta{resultCacheMetaKey: map[string]any{"hit": false}}, nil
+	}
+	callErr := err
+	if callErr == nil {
+		callErr = result.Error
+	}
+	if entry, ok := s.resultCache.get(key, staleMaxAge); ok && staleMaxAge > 0 && isClusterUnreachable(callErr) {
+		stale := entry.result
+		age := s.resultCache.now().Sub(entry.storedAt)
+		addResultHeader(&stale, fmt.Sprintf("# STALE: the cluster is unreachable (%s), returning the cached result of an identical call %s ago, "+
+			"it may not reflect the current state of the cluster\n", callErr.Error(), age.Truncate(time.Second)), params.ListOutput)
+		return &stale, mcp.Meta{resultCacheMetaKey: map[string]any{
+			"hit":        true,
+			"stale":      true,
+			"storedAt":   entry.storedAt.UTC().Format(time.RFC3339),
+			"ageSeconds": int(age.Seconds()),
+			"error":      callErr.Error(),
+		}}, nil
+	}
+	return result, nil, err
+}
+
+// isClusterUnreachable returns true if the call failed because the cluster couldn't be reached or didn't respond
+func isClusterUnreachable(err error) bool {
+	code := api.ClassifyError(err).Code
+	return code == api.ErrorCodeUnavailable || code == api.ErrorCodeTimeout
+}
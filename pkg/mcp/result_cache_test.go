@@ -0,0 +1,273 @@
+package mcp
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/utils/ptr"
+)
+
+type ResultCacheSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	originalToolsets []api.Toolset
+	calls            *atomic.Int32
+	unreachable      *atomic.Bool
+	untrusted        *atomic.Bool
+}
+
+func (s *ResultCacheSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.calls = &atomic.Int32{}
+	s.unreachable = &atomic.Bool{}
+	s.untrusted = &atomic.Bool{}
+	s.originalToolsets = toolsets.Toolsets()
+	toolsets.Clear()
+	toolsets.Register(resultCacheTestToolset(s.calls, s.unreachable, s.untrusted))
+	s.Cfg.Toolsets = []string{"result-cache-test"}
+	s.Cfg.ResultCache.MaxAge = "30s"
+}
+
+func (s *ResultCacheSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	toolsets.Clear()
+	for _, toolset := range s.originalToolsets {
+		toolsets.Register(toolset)
+	}
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ResultCacheSuite) callCount(args map[string]any) *mcp.CallToolResult {
+	toolResult, err := s.CallTool("count", args)
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Require().False(toolResult.IsError, "call tool should succeed")
+	return toolResult
+}
+
+func (s *ResultCacheSuite) TestTools() {
+	s.Run("adds the max_age parameter to the cacheable tools", func() {
+		s.InitMcpClient()
+		tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+		s.Require().NoError(err)
+		toolsByName := make(map[string]mcp.Tool)
+		for _, tool := range tools.Tools {
+			toolsByName[tool.Name] = tool
+		}
+		s.Contains(toolsByName["count"].InputSchema.Properties, "max_age")
+		s.NotContains(toolsByName["bump"].InputSchema.Properties, "max_age")
+	})
+	s.Run("without result cache", func() {
+		s.Cfg.ResultCache.MaxAge = ""
+		toolsets.Clear()
+		toolsets.Register(resultCacheTestToolset(s.calls, s.unreachable, s.untrusted))
+		s.Close()
+		s.mcpServer.Close()
+		s.InitMcpClient()
+		tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+		s.Require().NoError(err)
+		for _, tool := range tools.Tools {
+			s.NotContains(tool.InputSchema.Properties, "max_age")
+		}
+	})
+}
+
+func (s *ResultCacheSuite) TestCache() {
+	s.InitMcpClient()
+	now := time.Now()
+	s.mcpServer.resultCache.now = func() time.Time { return now }
+	first := s.callCount(map[string]any{"namespace": "default"})
+	s.Run("first call is not cached", func() {
+		s.Equal("calls: 1", first.Content[0].(mcp.TextContent).Text)
+		s.Require().NotNil(first.Meta)
+		s.Equal(map[string]any{"hit": false}, first.Meta.AdditionalFields["cache"])
+	})
+	s.Run("identical call returns the cached result", func() {
+		now = now.Add(10 * time.Second)
+		toolResult := s.callCount(map[string]any{"namespace": "default"})
+		s.Equal("# Cached result of an identical call 10s ago, set max_age=0 for a fresh result\ncalls: 1", toolResult.Content[0].(mcp.TextContent).Text)
+		s.Require().NotNil(toolResult.Meta)
+		cache := toolResult.Meta.AdditionalFields["cache"].(map[string]any)
+		s.Equal(true, cache["hit"])
+		s.Equal(float64(10), cache["ageSeconds"])
+		s.Equal(int32(1), s.calls.Load())
+	})
+	s.Run("call with different arguments is not cached", func() {
+		toolResult := s.callCount(map[string]any{"namespace": "kube-system"})
+		s.Equal("calls: 2", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("max_age lower than the age of the cached result calls the tool", func() {
+		toolResult := s.callCount(map[string]any{"namespace": "default", "max_age": 5})
+		s.Equal("calls: 3", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("max_age=0 calls the tool", func() {
+		toolResult := s.callCount(map[string]any{"namespace": "default", "max_age": 0})
+		s.Equal("calls: 4", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("expired results are not returned", func() {
+		now = now.Add(31 * time.Second)
+		toolResult := s.callCount(map[string]any{"namespace": "default", "max_age": 3600})
+		s.Equal("calls: 5", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("dry runs of mutating calls do not clear the cache", func() {
+		toolResult, err := s.CallTool("bump", map[string]any{"dry_run": true})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, "call tool should succeed: %v", toolResult.Content)
+		toolResult = s.callCount(map[string]any{"namespace": "default"})
+		s.Equal("# Cached result of an identical call 0s ago, set max_age=0 for a fresh result\ncalls: 5", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("mutating calls clear the cache", func() {
+		toolResult, err := s.CallTool("bump", map[string]any{})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, "call tool should succeed: %v", toolResult.Content)
+		toolResult = s.callCount(map[string]any{"namespace": "default"})
+		s.Equal("calls: 6", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *ResultCacheSuite) TestWorkspace() {
+	s.Cfg.Workspace.MaxBytes = 64
+	s.Cfg.Workspace.Dir = s.T().TempDir()
+	s.InitMcpClient()
+	writeFile := func(content string) {
+		toolResult, err := s.CallTool("workspace_write", map[string]any{"path": "values.yaml", "content": content})
+		s.Require().NoError(err)
+		s.Require().False(toolResult.IsError, "call tool should succeed: %v", toolResult.Content)
+	}
+	writeFile("replicas: 1\n")
+	s.callCount(map[string]any{"values": []any{"workspace:values.yaml"}})
+	s.Run("workspace_write does not clear the cache", func() {
+		writeFile("replicas: 1\n")
+		toolResult := s.callCount(map[string]any{"values": []any{"workspace:values.yaml"}})
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "calls: 1")
+	})
+	s.Run("call with a rewritten workspace file is not cached", func() {
+		writeFile("replicas: 2\n")
+		toolResult := s.callCount(map[string]any{"values": []any{"workspace:values.yaml"}})
+		s.Equal("calls: 2", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("call with a missing workspace file is not cached", func() {
+		s.callCount(map[string]any{"values": []any{"workspace:missing.yaml"}})
+		toolResult := s.callCount(map[string]any{"values": []any{"workspace:missing.yaml"}})
+		s.Equal("calls: 4", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *ResultCacheSuite) TestMaxEntries() {
+	s.Cfg.ResultCache.MaxEntries = 1
+	s.InitMcpClient()
+	s.callCount(map[string]any{"namespace": "default"})
+	s.callCount(map[string]any{"namespace": "kube-system"})
+	toolResult := s.callCount(map[string]any{"namespace": "default"})
+	s.Equal("calls: 3", toolResult.Content[0].(mcp.TextContent).Text, "expected the oldest result to be dropped")
+}
+
+func (s *ResultCacheSuite) TestStaleFallback() {
+	s.Cfg.ResultCache.MaxAge = ""
+	s.Cfg.ResultCache.StaleMaxAge = "1h"
+	s.InitMcpClient()
+	now := time.Now()
+	s.mcpServer.resultCache.now = func() time.Time { return now }
+	s.callCount(map[string]any{"namespace": "default"})
+	s.Run("identical call calls the tool while the cluster is reachable", func() {
+		toolResult := s.callCount(map[string]any{"namespace": "default"})
+		s.Equal("calls: 2", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.unreachable.Store(true)
+	s.Run("returns the cached result marked as stale when the cluster is unreachable", func() {
+		now = now.Add(10 * time.Minute)
+		toolResult := s.callCount(map[string]any{"namespace": "default"})
+		s.Equal("# STALE: the cluster is unreachable (failed to count: dial tcp: connection refused), "+
+			"returning the cached result of an identical call 10m0s ago, it may not reflect the current state of the cluster\ncalls: 2",
+			toolResult.Content[0].(mcp.TextContent).Text)
+		s.Require().NotNil(toolResult.Meta)
+		cache := toolResult.Meta.AdditionalFields["cache"].(map[string]any)
+		s.Equal(true, cache["stale"])
+		s.Equal(float64(600), cache["ageSeconds"])
+	})
+	s.Run("calls without a cached result fail", func() {
+		toolResult, err := s.CallTool("count", map[string]any{"namespace": "kube-system"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+	})
+	s.Run("results older than stale_max_age are not returned", func() {
+		now = now.Add(time.Hour)
+		toolResult, err := s.CallTool("count", map[string]any{"namespace": "default"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "connection refused")
+	})
+}
+
+func (s *ResultCacheSuite) TestStaleFallbackUntrustedCertificate() {
+	s.Cfg.ResultCache.MaxAge = ""
+	s.Cfg.ResultCache.StaleMaxAge = "1h"
+	s.InitMcpClient()
+	s.callCount(map[string]any{"namespace": "default"})
+	s.untrusted.Store(true)
+	s.Run("x509 errors are returned instead of the cached result", func() {
+		toolResult, err := s.CallTool("count", map[string]any{"namespace": "default"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "x509: certificate signed by unknown authority")
+		s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "STALE")
+	})
+}
+
+func (s *ResultCacheSuite) TestReloadInvalidResultCache() {
+	s.InitMcpClient()
+	newConfig := *s.Cfg
+	newConfig.ResultCache.MaxAge = "soon"
+	err := s.mcpServer.ReloadConfiguration(&newConfig)
+	s.EqualError(err, "result_cache max_age must be a positive duration (e.g. 30s): soon")
+}
+
+func TestResultCache(t *testing.T) {
+	suite.Run(t, new(ResultCacheSuite))
+}
+
+// resultCacheTestToolset provides a read-only tool returning the number of its calls (or failing to connect to the cluster
+// if unreachable, or to verify its certificate if untrusted), and a mutating tool
+func resultCacheTestToolset(calls *atomic.Int32, unreachable, untrusted *atomic.Bool) api.Toolset {
+	return &mockToolsetWithTools{name: "result-cache-test", tools: []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name:        "count",
+				InputSchema: &jsonschema.Schema{Type: "object"},
+				Annotations: api.ToolAnnotations{ReadOnlyHint: ptr.To(true)},
+			},
+			Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+				if unreachable.Load() {
+					return api.NewToolCallResult("", fmt.Errorf("failed to count: %w", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})), nil
+				}
+				if untrusted.Load() {
+					return api.NewToolCallResult("", fmt.Errorf("failed to count: %w", &url.Error{Op: "Get", URL: "https://127.0.0.1:6443/api", Err: x509.UnknownAuthorityError{}})), nil
+				}
+				return api.NewToolCallResult(fmt.Sprintf("calls: %d", calls.Add(1)), nil), nil
+			},
+		},
+		{
+			Tool: api.Tool{Name: "bump", InputSchema: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{
+				"dry_run": {Type: "boolean"},
+			}}},
+			Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+				return api.NewToolCallResult("bumped: true", nil), nil
+			},
+		},
+	}}
+}
@@ -0,0 +1,350 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
+)
+
+const (
+	// SchedulesResultsToolName is the name of the tool returning the results of the scheduled tool calls
+	SchedulesResultsToolName = "schedules_results"
+	// scheduleURIPrefix is the prefix of the URIs of the MCP resources serving the latest result of each schedule
+	scheduleURIPrefix = "schedule://"
+)
+
+// ScheduleStatus is the report of a configured schedule and its latest run, returned by the schedules_results tool
+type ScheduleStatus struct {
+	Name    string       `json:"name"`
+	Cron    string       `json:"cron"`
+	Tool    string       `json:"tool"`
+	Target  string       `json:"target,omitempty"`
+	URI     string       `json:"uri"`
+	NextRun *time.Time   `json:"nextRun,omitempty"`
+	LastRun *ScheduleRun `json:"lastRun,omitempty"`
+}
+
+// ScheduleRun is the report of a scheduled tool call, its complete result is returned by the schedules_results tool
+type ScheduleRun struct {
+	Status      string    `json:"status"`
+	StartedAt   time.Time `json:"startedAt"`
+	CompletedAt time.Time `json:"completedAt"`
+	Error       string    `json:"error,omitempty"`
+	result      *api.ToolCallResult
+}
+
+// schedules calls the configured tools periodically and keeps the result of their latest run
+type schedules struct {
+	mu      sync.Mutex
+	configs []config.ScheduleConfig
+	tools   map[string]api.ServerTool
+	next    map[string]time.Time
+	runs    map[string]*ScheduleRun
+	call    func(ctx context.Context, tool api.ServerTool, schedule config.ScheduleConfig) (*api.ToolCallResult, error)
+	now     func() time.Time
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func newSchedules(call func(ctx context.Context, tool api.ServerTool, schedule config.ScheduleConfig) (*api.ToolCallResult, error)) *schedules {
+	return &schedules{
+		tools: make(map[string]api.ServerTool),
+		next:  make(map[string]time.Time),
+		runs:  make(map[string]*ScheduleRun),
+		call:  call,
+		now:   time.Now,
+	}
+}
+
+// update replaces the running schedules with the (validated) configured ones.
+// The results of the schedules still configured with the same tool are kept.
+func (sc *schedules) update(configs []config.ScheduleConfig) {
+	sc.stop()
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	previous := sc.configs
+	sc.configs = slices.Clone(configs)
+	sc.next = make(map[string]time.Time)
+	for name := range sc.runs {
+		i := slices.IndexFunc(sc.configs, func(schedule config.ScheduleConfig) bool { return schedule.Name == name })
+		j := slices.IndexFunc(previous, func(schedule config.ScheduleConfig) bool { return schedule.Name == name })
+		if i < 0 || j < 0 || sc.configs[i].Tool != previous[j].Tool {
+			delete(sc.runs, name)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	sc.cancel = cancel
+	for _, schedule := range sc.configs {
+		cron, err := config.ParseCron(schedule.Cron)
+		if err != nil {
+			// validated on startup and reload
+			klog.Errorf("Failed to start schedule %s: %v", schedule.Name, err)
+			continue
+		}
+		sc.wg.Add(1)
+		go sc.loop(ctx, schedule, cron)
+	}
+}
+
+// setTools updates the enabled tools the schedules can call
+func (sc *schedules) setTools(tools []api.ServerTool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.tools = make(map[string]api.ServerTool, len(tools))
+	for _, tool := range tools {
+		sc.tools[tool.Tool.Name] = tool
+	}
+}
+
+func (sc *schedules) loop(ctx context.Context, schedule config.ScheduleConfig, cron *config.Cron) {
+	defer sc.wg.Done()
+	for {
+		next := cron.Next(sc.now())
+		if next.IsZero() {
+			klog.Warningf("Schedule %s never runs, its cron expression %q doesn't match any time", schedule.Name, schedule.Cron)
+			return
+		}
+		sc.mu.Lock()
+		sc.next[schedule.Name] = next
+		sc.mu.Unlock()
+		timer := time.NewTimer(next.Sub(sc.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		sc.run(ctx, schedule)
+	}
+}
+
+// run calls the tool of the schedule and keeps its result, the runs of a schedule never overlap
+func (sc *schedules) run(ctx context.Context, schedule config.ScheduleConfig) {
+	run := &ScheduleRun{StartedAt: sc.now()}
+	sc.mu.Lock()
+	tool, ok := sc.tools[schedule.Tool]
+	sc.mu.Unlock()
+	var result *api.ToolCallResult
+	switch {
+	case !ok:
+		result = api.NewToolCallResult("", api.NewToolError(api.ErrorCodeNotFound, fmt.Errorf("tool %s is not enabled", schedule.Tool)))
+	case !ptr.Deref(tool.Tool.Annotations.ReadOnlyHint, false):
+		result = api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("tool %s is not read-only, only read-only tools can be scheduled", schedule.Tool)))
+	default:
+		var err error
+		if result, err = sc.call(ctx, tool, schedule); err != nil {
+			result = api.NewToolCallResult("", err)
+		}
+	}
+	if ctx.Err() != nil {
+		// stopped while running, the schedule was removed or updated
+		return
+	}
+	run.CompletedAt = sc.now()
+	run.Status = OperationSucceeded
+	if result.Error != nil {
+		run.Status = OperationFailed
+		run.Error = result.Error.Error()
+		klog.V(1).Infof("Scheduled call of %s (%s) failed: %v", schedule.Tool, schedule.Name, result.Error)
+	}
+	run.result = result
+	sc.mu.Lock()
+	sc.runs[schedule.Name] = run
+	sc.mu.Unlock()
+}
+
+// stop cancels the schedules and waits for their running calls to return
+func (sc *schedules) stop() {
+	sc.mu.Lock()
+	cancel := sc.cancel
+	sc.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	sc.wg.Wait()
+}
+
+// status returns the report of the configured schedules, in the order of the configuration
+func (sc *schedules) status() []ScheduleStatus {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	ret := make([]ScheduleStatus, 0, len(sc.configs))
+	for _, schedule := range sc.configs {
+		status := ScheduleStatus{
+			Name:   schedule.Name,
+			Cron:   schedule.Cron,
+			Tool:   schedule.Tool,
+			Target: schedule.Target,
+			URI:    scheduleURIPrefix + schedule.Name,
+		}
+		if next, ok := sc.next[schedule.Name]; ok {
+			status.NextRun = &next
+		}
+		if run, ok := sc.runs[schedule.Name]; ok {
+			status.LastRun = ptr.To(*run)
+		}
+		ret = append(ret, status)
+	}
+	return ret
+}
+
+// result returns the result of the latest run of the schedule
+func (sc *schedules) result(name string) (*ScheduleRun, *api.ToolCallResult, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if !slices.ContainsFunc(sc.configs, func(schedule config.ScheduleConfig) bool { return schedule.Name == name }) {
+		return nil, nil, api.NewToolError(api.ErrorCodeNotFound, fmt.Errorf("schedule %s not found", name))
+	}
+	run, ok := sc.runs[name]
+	if !ok {
+		next := "never"
+		if t, ok := sc.next[name]; ok {
+			next = t.Format(time.RFC3339)
+		}
+		return nil, nil, api.NewToolError(api.ErrorCodeUnavailable, fmt.Errorf("schedule %s has not run yet, its next run is at %s", name, next))
+	}
+	ret := *run
+	return &ret, run.result, nil
+}
+
+// callScheduledTool calls the tool of the schedule against its target, outside any MCP session
+func (s *Server) callScheduledTool(ctx context.Context, tool api.ServerTool, schedule config.ScheduleConfig) (*api.ToolCallResult, error) {
+	// the arguments are decoded the way the arguments of the tool calls are (e.g. numbers as float64)
+	arguments := make(map[string]any)
+	if raw, err := json.Marshal(schedule.Arguments); err != nil {
+		return nil, api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid arguments: %w", err))
+	} else if err = json.Unmarshal(raw, &arguments); err != nil {
+		return nil, api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid arguments: %w", err))
+	}
+	target := cmp.Or(schedule.Target, s.p.GetDefaultTarget())
+	if schedule.Target != "" && s.p.GetTargetParameterName() != "" {
+		arguments[s.p.GetTargetParameterName()] = schedule.Target
+	}
+	k, err := s.p.GetDerivedKubernetes(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	params := api.ToolHandlerParams{
+		Context:                locale.NewContext(ctx, s.configuration.Locale),
+		ExtendedConfigProvider: s.configuration,
+		KubernetesClient:       k,
+		ToolCallRequest:        &ToolCallRequest{Name: tool.Tool.Name, arguments: arguments},
+		ListOutput:             s.configuration.ListOutput(),
+		PruneFields:            s.configuration.PruneFields,
+		ListDetail:             s.configuration.ListDetail,
+		Target:                 target,
+		TargetClient:           s.targetClient,
+	}
+	return s.callTool(tool, params, nil)
+}
+
+// enabledSchedules returns the configured schedules, none with require_oauth: the scheduled calls use the credentials
+// of the server, their results can't be served to the sessions with their own (possibly narrower) credentials
+func (s *Server) enabledSchedules() []config.ScheduleConfig {
+	if s.configuration.RequireOAuth {
+		return nil
+	}
+	return s.configuration.Schedules
+}
+
+// reloadSchedules restarts the configured schedules and serves their latest results as MCP resources
+func (s *Server) reloadSchedules(previous []config.ScheduleConfig) {
+	uris := make([]string, 0, len(previous))
+	for _, schedule := range previous {
+		uris = append(uris, scheduleURIPrefix+schedule.Name)
+	}
+	s.server.RemoveResources(uris...)
+	if s.configuration.RequireOAuth && len(s.configuration.Schedules) > 0 {
+		klog.Warningf("The %d configured schedule(s) are disabled, the scheduled calls use the credentials of the server and require_oauth is set", len(s.configuration.Schedules))
+	}
+	s.schedules.update(s.enabledSchedules())
+	for _, schedule := range s.enabledSchedules() {
+		s.server.AddResource(&mcp.Resource{
+			Name:        "schedule-" + schedule.Name,
+			Title:       "Schedule " + schedule.Name,
+			Description: fmt.Sprintf("The result of the latest scheduled call of %s (%s)", schedule.Tool, schedule.Cron),
+			MIMEType:    "text/plain",
+			URI:         scheduleURIPrefix + schedule.Name,
+		}, s.readScheduleResource)
+	}
+}
+
+func (s *Server) readScheduleResource(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	run, result, err := s.schedules.result(req.Params.URI[len(scheduleURIPrefix):])
+	var toolError *api.ToolError
+	if errors.As(err, &toolError) && toolError.Code == api.ErrorCodeNotFound {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("the latest run of %s failed at %s: %w", req.Params.URI, run.CompletedAt.Format(time.RFC3339), result.Error)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: req.Params.URI, MIMEType: "text/plain", Text: result.Content}},
+	}, nil
+}
+
+// schedulesTools returns the tool returning the results of the schedules, its handler is provided by the Server
+func (s *Server) schedulesTools() []api.ServerTool {
+	return []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name: SchedulesResultsToolName,
+				Description: "Get the results of the tools called periodically by the server (e.g. nightly scans), which are ready without waiting for the tool calls. " +
+					"Returns the result of the latest run of the schedule with the provided name as the tool call returned it, " +
+					"or the status of all the schedules (last and next runs) if no name is provided",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"name": {
+							Type:        "string",
+							Description: "Optional name of the schedule, as returned when no name is provided",
+						},
+					},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Schedules: Results",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					IdempotentHint:  ptr.To(true),
+					OpenWorldHint:   ptr.To(false),
+				},
+			},
+			ClusterAware: ptr.To(false),
+			Handler:      s.schedulesResultsHandler,
+		},
+	}
+}
+
+func (s *Server) schedulesResultsHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, ok := params.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		ret, err := params.Marshal(s.schedules.status())
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to get the status of the schedules: %w", err)), nil
+		}
+		return api.NewToolCallResult(ret, nil), nil
+	}
+	_, result, err := s.schedules.result(name)
+	if err != nil {
+		return api.NewToolCallResult("", err), nil
+	}
+	ret := *result
+	return &ret, nil
+}
@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+type SchedulesSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	originalToolsets []api.Toolset
+}
+
+func (s *SchedulesSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.originalToolsets = toolsets.Toolsets()
+	toolsets.Clear()
+	toolsets.Register(schedulesTestToolset())
+	s.Cfg.Toolsets = []string{"schedules-test"}
+	s.Cfg.Schedules = []config.ScheduleConfig{
+		{Name: "nightly-scan", Cron: "0 2 * * *", Tool: "scan", Arguments: map[string]any{"depth": int64(3)}},
+		{Name: "nightly-fix", Cron: "0 3 * * *", Tool: "fix"},
+	}
+}
+
+func (s *SchedulesSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	toolsets.Clear()
+	for _, toolset := range s.originalToolsets {
+		toolsets.Register(toolset)
+	}
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *SchedulesSuite) status() []ScheduleStatus {
+	toolResult, err := s.CallTool("schedules_results", map[string]any{})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Require().False(toolResult.IsError, "call tool should succeed")
+	var ret []ScheduleStatus
+	s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &ret))
+	return ret
+}
+
+func (s *SchedulesSuite) TestTools() {
+	s.Run("adds the schedules tool", func() {
+		s.InitMcpClient()
+		tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+		s.Require().NoError(err)
+		s.True(containsTool(tools.Tools, "schedules_results"))
+	})
+	s.Run("without schedules", func() {
+		s.Cfg.Schedules = nil
+		s.Close()
+		s.mcpServer.Close()
+		s.InitMcpClient()
+		tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+		s.Require().NoError(err)
+		s.False(containsTool(tools.Tools, "schedules_results"))
+	})
+	s.Run("disables the schedules with require_oauth", func() {
+		s.Cfg.RequireOAuth = true
+		server := &Server{configuration: &Configuration{StaticConfig: s.Cfg}}
+		s.Empty(server.enabledSchedules(), "the results of the calls with the server credentials must not be served to the sessions")
+	})
+}
+
+func (s *SchedulesSuite) TestBeforeFirstRun() {
+	s.InitMcpClient()
+	s.Run("reports the schedules and their next run", func() {
+		s.Eventually(func() bool {
+			status := s.status()
+			return len(status) == 2 && status[0].NextRun != nil && status[1].NextRun != nil
+		}, 5*time.Second, 50*time.Millisecond)
+		status := s.status()
+		s.Equal("nightly-scan", status[0].Name)
+		s.Equal("schedule://nightly-scan", status[0].URI)
+		s.Nil(status[0].LastRun)
+	})
+	s.Run("result is unavailable", func() {
+		toolResult, err := s.CallTool("schedules_results", map[string]any{"name": "nightly-scan"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "schedule nightly-scan has not run yet")
+	})
+	s.Run("unknown schedule", func() {
+		toolResult, err := s.CallTool("schedules_results", map[string]any{"name": "unknown"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("schedule unknown not found", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *SchedulesSuite) TestRun() {
+	s.InitMcpClient()
+	s.mcpServer.schedules.run(s.T().Context(), s.Cfg.Schedules[0])
+	s.mcpServer.schedules.run(s.T().Context(), s.Cfg.Schedules[1])
+	s.Run("returns the result of the latest run", func() {
+		toolResult, err := s.CallTool("schedules_results", map[string]any{"name": "nightly-scan"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Require().False(toolResult.IsError, "call tool should succeed")
+		s.Equal("scanned: depth 3", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("reports the last run", func() {
+		status := s.status()
+		s.Require().NotNil(status[0].LastRun)
+		s.Equal(OperationSucceeded, status[0].LastRun.Status)
+	})
+	s.Run("refuses to run tools that aren't read-only", func() {
+		status := s.status()
+		s.Require().NotNil(status[1].LastRun)
+		s.Equal(OperationFailed, status[1].LastRun.Status)
+		s.Equal("tool fix is not read-only, only read-only tools can be scheduled", status[1].LastRun.Error)
+	})
+	s.Run("serves the result as a resource", func() {
+		result, err := s.ReadResource(s.T().Context(), mcp.ReadResourceRequest{Params: mcp.ReadResourceParams{URI: "schedule://nightly-scan"}})
+		s.Require().NoError(err)
+		s.Equal("scanned: depth 3", result.Contents[0].(mcp.TextResourceContents).Text)
+	})
+	s.Run("resource of a failed run", func() {
+		_, err := s.ReadResource(s.T().Context(), mcp.ReadResourceRequest{Params: mcp.ReadResourceParams{URI: "schedule://nightly-fix"}})
+		s.ErrorContains(err, "the latest run of schedule://nightly-fix failed")
+	})
+	s.Run("keeps the results of the schedules kept on reload", func() {
+		newConfig := *s.Cfg
+		newConfig.Schedules = []config.ScheduleConfig{s.Cfg.Schedules[0]}
+		s.Require().NoError(s.mcpServer.ReloadConfiguration(&newConfig))
+		status := s.status()
+		s.Require().Len(status, 1)
+		s.NotNil(status[0].LastRun)
+		_, err := s.ReadResource(s.T().Context(), mcp.ReadResourceRequest{Params: mcp.ReadResourceParams{URI: "schedule://nightly-fix"}})
+		s.Error(err, "expected the resource of the removed schedule to be removed")
+	})
+}
+
+func (s *SchedulesSuite) TestReloadInvalidSchedules() {
+	s.InitMcpClient()
+	newConfig := *s.Cfg
+	newConfig.Schedules = []config.ScheduleConfig{{Name: "scan", Cron: "daily", Tool: "scan"}}
+	err := s.mcpServer.ReloadConfiguration(&newConfig)
+	s.ErrorContains(err, "invalid schedules: schedule scan: invalid cron expression")
+}
+
+func TestSchedules(t *testing.T) {
+	suite.Run(t, new(SchedulesSuite))
+}
+
+func containsTool(tools []mcp.Tool, name string) bool {
+	for _, tool := range tools {
+		if tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// schedulesTestToolset provides a read-only tool echoing its depth argument, and a tool that isn't read-only
+func schedulesTestToolset() api.Toolset {
+	return &mockToolsetWithTools{name: "schedules-test", tools: []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name:        "scan",
+				InputSchema: &jsonschema.Schema{Type: "object"},
+				Annotations: api.ToolAnnotations{ReadOnlyHint: ptr.To(true)},
+			},
+			Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+				return api.NewToolCallResult(fmt.Sprintf("scanned: depth %v", params.GetArguments()["depth"]), nil), nil
+			},
+		},
+		{
+			Tool: api.Tool{Name: "fix", InputSchema: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{
+				"dry_run": {Type: "boolean"},
+			}}},
+			Handler: func(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+				return api.NewToolCallResult("fixed: true", nil), nil
+			},
+		},
+	}}
+}
@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type SecuritySuite struct {
+	BaseMcpSuite
+}
+
+func (s *SecuritySuite) TestSecurityPosture() {
+	client := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	_, err := client.CoreV1().Namespaces().Create(s.T().Context(), &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "security-posture",
+		Labels: map[string]string{"pod-security.kubernetes.io/enforce": "privileged"},
+	}}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = client.CoreV1().Namespaces().Delete(s.T().Context(), "security-posture", metav1.DeleteOptions{})
+	})
+	_, err = client.CoreV1().Pods("security-posture").Create(s.T().Context(), &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-privileged-pod"},
+		Spec: v1.PodSpec{
+			HostNetwork: true,
+			Containers: []v1.Container{{
+				Name:            "app",
+				Image:           "nginx",
+				SecurityContext: &v1.SecurityContext{Privileged: ptr.To(true)},
+			}},
+		},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.InitMcpClient()
+	s.Run("security_posture(namespace=security-posture)", func() {
+		toolResult, err := s.CallTool("security_posture", map[string]interface{}{"namespace": "security-posture"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the evaluated pods", func() {
+			s.Truef(strings.HasPrefix(text, "# 1 pod(s) evaluated, 1 failing baseline, 0 failing restricted (YAML format):\n"), "unexpected result %v", text)
+		})
+		var postures []map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &postures))
+		s.Require().Len(postures, 1)
+		s.Run("reports the namespace with its enforced level", func() {
+			s.Equal("security-posture", postures[0]["namespace"])
+			s.Equal("privileged", postures[0]["enforcedLevel"])
+		})
+		s.Run("reports the privileged pod violations", func() {
+			violations := postures[0]["violations"].([]any)
+			s.Require().Len(violations, 1)
+			pod := violations[0].(map[string]any)
+			s.Equal("a-privileged-pod", pod["pod"])
+			s.Equal("privileged", pod["level"])
+			s.Contains(pod["violations"], map[string]any{"level": "baseline", "check": "privileged", "message": "container app is privileged"})
+			s.Contains(pod["violations"], map[string]any{"level": "baseline", "check": "hostNamespaces", "message": "hostNetwork is enabled"})
+		})
+	})
+	s.Run("security_posture(namespace=non-existent)", func() {
+		toolResult, err := s.CallTool("security_posture", map[string]interface{}{"namespace": "non-existent"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Equal("# No pods found", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *SecuritySuite) TestSecurityContext() {
+	mockServer := test.NewMockServer()
+	s.T().Cleanup(mockServer.Close)
+	mockServer.Handle(test.NewDiscoveryClientHandler())
+	mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/apis/apps/v1/namespaces/shop/deployments/web" {
+			test.WriteObject(w, &appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec: appsv1.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+					SecurityContext: &v1.PodSecurityContext{RunAsUser: ptr.To(int64(1000)), FSGroup: ptr.To(int64(2000))},
+					Containers: []v1.Container{{
+						Name:            "web",
+						SecurityContext: &v1.SecurityContext{Capabilities: &v1.Capabilities{Drop: []v1.Capability{"ALL"}}},
+					}},
+				}}},
+			})
+		}
+	}))
+	s.Cfg.KubeConfig = mockServer.KubeconfigFile(s.T())
+	s.InitMcpClient()
+	s.Run("security_context(kind=Deployment, namespace=shop, name=web)", func() {
+		toolResult, err := s.CallTool("security_context", map[string]interface{}{"kind": "Deployment", "namespace": "shop", "name": "web"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Require().Falsef(toolResult.IsError, "call tool should succeed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the object and level", func() {
+			s.Truef(strings.HasPrefix(text, "# Effective security settings of Deployment/shop/web, 1 container(s), Pod Security Standards level baseline (YAML format):\n"),
+				"unexpected result %v", text)
+		})
+		var settings map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &settings))
+		s.Run("reports the Pod settings", func() {
+			s.Equal(float64(2000), settings["fsGroup"])
+		})
+		s.Run("reports the effective container settings with their source", func() {
+			container := settings["containers"].([]any)[0].(map[string]any)
+			s.Equal(map[string]any{"value": float64(1000), "source": "pod"}, container["runAsUser"])
+			s.Equal(map[string]any{"value": []any{}, "source": "container"}, container["capabilities"])
+			s.Equal("default", container["seccomp"].(map[string]any)["source"])
+		})
+	})
+	s.Run("security_context(kind=Deployment, name=missing)", func() {
+		toolResult, err := s.CallTool("security_context", map[string]interface{}{"kind": "Deployment", "namespace": "shop", "name": "missing"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to get security context: ")
+	})
+}
+
+func TestSecurity(t *testing.T) {
+	suite.Run(t, new(SecuritySuite))
+}
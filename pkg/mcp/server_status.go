@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/sessionbudget"
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+// ServerStatusToolName is the name of the server introspection tool whose handler is provided by the Server
+const ServerStatusToolName = "server_status"
+
+// ServerStatus is the report returned by the server_status tool
+type ServerStatus struct {
+	Version       ServerStatusVersion       `json:"version"`
+	Configuration ServerStatusConfiguration `json:"configuration"`
+	Cluster       ServerStatusCluster       `json:"cluster"`
+	Cache         ServerStatusCache         `json:"cache"`
+	Sessions      ServerStatusSessions      `json:"sessions"`
+	Output        ServerStatusOutput        `json:"output"`
+}
+
+type ServerStatusVersion struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	CommitHash string `json:"commitHash"`
+	BuildTime  string `json:"buildTime"`
+}
+
+type ServerStatusConfiguration struct {
+	Toolsets           []string `json:"toolsets"`
+	EnabledTools       int      `json:"enabledTools"`
+	EnabledPrompts     int      `json:"enabledPrompts"`
+	ReadOnly           bool     `json:"readOnly"`
+	DisableDestructive bool     `json:"disableDestructive"`
+	Stateless          bool     `json:"stateless"`
+	ListOutput         string   `json:"listOutput"`
+	DeniedResources    int      `json:"deniedResources"`
+	// DeniedScopes are the scopes (cluster-scoped or namespaced) whose resources, or their changes, are denied
+	DeniedScopes            []api.DeniedScope `json:"deniedScopes,omitempty"`
+	ClusterProviderStrategy string            `json:"clusterProviderStrategy,omitempty"`
+}
+
+type ServerStatusCluster struct {
+	TargetParameterName string `json:"targetParameterName"`
+	DefaultTarget       string `json:"defaultTarget"`
+	Targets             int    `json:"targets"`
+	Server              string `json:"server,omitempty"`
+	OpenShift           bool   `json:"openShift"`
+}
+
+type ServerStatusCache struct {
+	// DiscoveryFresh reports whether the cached discovery information was fetched during the lifetime of the client
+	DiscoveryFresh bool  `json:"discoveryFresh"`
+	TotalToolCalls int64 `json:"totalToolCalls"`
+	ToolCallErrors int64 `json:"toolCallErrors"`
+	UptimeSeconds  int64 `json:"uptimeSeconds"`
+}
+
+type ServerStatusSessions struct {
+	Active int `json:"active"`
+	// Usage are the resources held on behalf of each active session (see config.SessionLimitsConfig), the session IDs
+	// are not reported since they are the only credential tying the requests of the HTTP transport to a session
+	Usage []ServerStatusSessionUsage `json:"usage,omitempty"`
+}
+
+type ServerStatusSessionUsage struct {
+	ToolCalls         int   `json:"toolCalls"`
+	RunningOperations int   `json:"runningOperations"`
+	RetainedBytes     int64 `json:"retainedBytes"`
+	// OutputBytes is the size of the tool call results returned to the session
+	OutputBytes int64 `json:"outputBytes"`
+	// LogBytes and Goroutines are the subscriptions held by the tools (see sessionbudget)
+	LogBytes   int64 `json:"logBytes"`
+	Goroutines int64 `json:"goroutines"`
+}
+
+// ServerStatusOutput is the size of the tool call results returned since the server started, the calls without a
+// namespace argument (cluster-scoped or all namespaces) are only accounted in the total
+type ServerStatusOutput struct {
+	TotalBytes       int64            `json:"totalBytes"`
+	BytesByNamespace map[string]int64 `json:"bytesByNamespace,omitempty"`
+}
+
+// WithServerStatusTool sets the handler of the server_status tool so that it can report the state of the provided Server.
+func WithServerStatusTool(s *Server) ToolMutator {
+	return func(tool api.ServerTool) api.ServerTool {
+		if tool.Tool.Name != ServerStatusToolName {
+			return tool
+		}
+		tool.Handler = s.serverStatusHandler
+		return tool
+	}
+}
+
+func (s *Server) serverStatusHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	status := s.serverStatus(params)
+	ret, err := params.Marshal(status)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get server status: %w", err)), nil
+	}
+	return api.NewToolCallResult(ret, nil), nil
+}
+
+func (s *Server) serverStatus(params api.ToolHandlerParams) *ServerStatus {
+	status := &ServerStatus{
+		Version: ServerStatusVersion{
+			Name:       version.BinaryName,
+			Version:    version.Version,
+			CommitHash: version.CommitHash,
+			BuildTime:  version.BuildTime,
+		},
+		Configuration: ServerStatusConfiguration{
+			Toolsets:                s.configuration.StaticConfig.Toolsets,
+			EnabledTools:            len(s.enabledTools),
+			EnabledPrompts:          len(s.enabledPrompts),
+			ReadOnly:                s.configuration.ReadOnly,
+			DisableDestructive:      s.configuration.DisableDestructive,
+			Stateless:               s.configuration.Stateless,
+			ListOutput:              s.configuration.ListOutput().GetName(),
+			DeniedResources:         len(s.configuration.DeniedResources),
+			DeniedScopes:            s.configuration.DeniedScopes,
+			ClusterProviderStrategy: s.configuration.ClusterProviderStrategy,
+		},
+		Cluster: ServerStatusCluster{
+			TargetParameterName: s.p.GetTargetParameterName(),
+			DefaultTarget:       s.p.GetDefaultTarget(),
+			OpenShift:           s.p.IsOpenShift(params.Context),
+		},
+	}
+	if targets, err := s.p.GetTargets(params.Context); err == nil {
+		status.Cluster.Targets = len(targets)
+	}
+	if params.KubernetesClient != nil {
+		if restConfig := params.RESTConfig(); restConfig != nil {
+			status.Cluster.Server = restConfig.Host
+		}
+		if discoveryClient := params.DiscoveryClient(); discoveryClient != nil {
+			status.Cache.DiscoveryFresh = discoveryClient.Fresh()
+		}
+	}
+	if s.metrics != nil {
+		stats := s.metrics.GetStats()
+		status.Cache.TotalToolCalls = stats.TotalToolCalls
+		status.Cache.ToolCallErrors = stats.ToolCallErrors
+		status.Cache.UptimeSeconds = stats.UptimeSeconds
+		status.Output.TotalBytes = stats.ToolOutputBytes
+		if len(stats.ToolOutputBytesByNamespace) > 0 {
+			status.Output.BytesByNamespace = stats.ToolOutputBytesByNamespace
+		}
+	}
+	for session := range s.server.Sessions() {
+		status.Sessions.Active++
+		if id := session.ID(); id != "" {
+			usage := ServerStatusSessionUsage{ToolCalls: s.toolCalls.count(id), OutputBytes: s.toolCalls.output(id)}
+			usage.RunningOperations, usage.RetainedBytes = s.operations.usage(id)
+			budget := s.budget.Usage(id)
+			usage.LogBytes, usage.Goroutines = budget[sessionbudget.LogBytes], budget[sessionbudget.Goroutines]
+			status.Sessions.Usage = append(status.Sessions.Usage, usage)
+		}
+	}
+	slices.SortFunc(status.Sessions.Usage, func(a, b ServerStatusSessionUsage) int {
+		return cmp.Or(cmp.Compare(b.ToolCalls, a.ToolCalls), cmp.Compare(b.OutputBytes, a.OutputBytes))
+	})
+	return status
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/diagnostics"
+	"github.com/containers/kubernetes-mcp-server/pkg/version"
+)
+
+type ServerStatusSuite struct {
+	BaseMcpSuite
+}
+
+func (s *ServerStatusSuite) TestServerStatus() {
+	s.Require().NoError(toml.Unmarshal([]byte(`
+		read_only = true
+		denied_resources = [
+			{ version = "v1", kind = "Secret" },
+			{ group = "rbac.authorization.k8s.io", version = "v1" }
+		]
+		denied_scopes = [ { scope = "cluster", writes_only = true } ]
+	`), s.Cfg), "Expected to parse config")
+	s.InitMcpClient()
+	s.Run("server_status", func() {
+		toolResult, err := s.CallTool("server_status", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Require().NotNil(toolResult, "Expected tool result from call")
+		var decoded ServerStatus
+		err = yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded)
+		s.Run("has yaml content", func() {
+			s.Nilf(err, "invalid tool result content %v", err)
+		})
+		s.Run("returns version", func() {
+			s.Equal(version.BinaryName, decoded.Version.Name)
+			s.Equal(version.Version, decoded.Version.Version)
+		})
+		s.Run("returns configuration summary", func() {
+			s.Equal([]string{"core", "config", "helm"}, decoded.Configuration.Toolsets)
+			s.True(decoded.Configuration.ReadOnly, "expected read-only mode to be reported")
+			s.Equal(2, decoded.Configuration.DeniedResources)
+			s.Equal([]api.DeniedScope{{Scope: api.ScopeCluster, WritesOnly: true}}, decoded.Configuration.DeniedScopes)
+			s.Greater(decoded.Configuration.EnabledTools, 0)
+		})
+		s.Run("returns connected cluster", func() {
+			s.Equal("context", decoded.Cluster.TargetParameterName)
+			s.Equal(envTestRestConfig.Host, decoded.Cluster.Server)
+			s.Equal(1, decoded.Cluster.Targets)
+		})
+		s.Run("returns active sessions", func() {
+			s.GreaterOrEqual(decoded.Sessions.Active, 1)
+		})
+		s.Run("does not return the session IDs", func() {
+			s.NotContains(toolResult.Content[0].(mcp.TextContent).Text, "ids:")
+		})
+		s.Run("returns the usage of the sessions", func() {
+			s.Require().Len(decoded.Sessions.Usage, 1)
+			s.Equal(1, decoded.Sessions.Usage[0].ToolCalls, "expected the server_status call to be in flight")
+			s.Equal(0, decoded.Sessions.Usage[0].RunningOperations)
+		})
+	})
+}
+
+func (s *ServerStatusSuite) TestServerStatusOutput() {
+	s.InitMcpClient()
+	listResult, err := s.CallTool("pods_list_in_namespace", map[string]interface{}{"namespace": "ns-1"})
+	s.Require().NoError(err)
+	listBytes := int64(len(listResult.Content[0].(mcp.TextContent).Text))
+	failedResult, err := s.CallTool("pods_get", map[string]interface{}{"namespace": "ns-missing", "name": "missing"})
+	s.Require().NoError(err)
+	s.Require().True(failedResult.IsError, "expected pods_get of a missing pod to fail")
+	toolResult, err := s.CallTool("server_status", map[string]interface{}{})
+	s.Run("no error", func() {
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed")
+	})
+	var decoded ServerStatus
+	s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded))
+	s.Run("returns the output bytes by namespace", func() {
+		s.Equal(listBytes, decoded.Output.BytesByNamespace["ns-1"])
+		s.GreaterOrEqual(decoded.Output.TotalBytes, listBytes)
+	})
+	s.Run("doesn't account the failed calls to their namespace", func() {
+		s.NotContains(decoded.Output.BytesByNamespace, "ns-missing")
+	})
+	s.Run("returns the output bytes of the session", func() {
+		s.Require().Len(decoded.Sessions.Usage, 1)
+		s.Greater(decoded.Sessions.Usage[0].OutputBytes, listBytes, "expected the pods_list_in_namespace and pods_get results to be accounted")
+	})
+}
+
+func (s *ServerStatusSuite) TestServerDiagnostics() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("server_diagnostics", map[string]interface{}{})
+	s.Run("no error", func() {
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed")
+	})
+	var decoded diagnostics.Runtime
+	err = yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), &decoded)
+	s.Run("has yaml content", func() {
+		s.Nilf(err, "invalid tool result content %v", err)
+	})
+	s.Run("returns the runtime statistics", func() {
+		s.Equal(runtime.Version(), decoded.GoVersion)
+		s.Greater(decoded.Goroutines, 0)
+		s.Greater(decoded.Memory.HeapAllocBytes, uint64(0))
+		s.NotEmpty(decoded.GoroutineGroups)
+	})
+}
+
+func TestServerStatus(t *testing.T) {
+	suite.Run(t, new(ServerStatusSuite))
+}
@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type ServiceProxySuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *ServiceProxySuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	discovery := test.NewDiscoveryClientHandler()
+	// the default core resource list
+	discovery.APIResourceLists[0].APIResources = append(discovery.APIResourceLists[0].APIResources,
+		metav1.APIResource{Name: "services", Kind: "Service", Namespaced: true, Verbs: metav1.Verbs{"get", "list", "watch"}})
+	s.mockServer.Handle(discovery)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method + " " + req.URL.Path {
+		case "GET /api/v1/namespaces/default/services/web:8080/proxy/healthz":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("ok"))
+		case "GET /api/v1/namespaces/default/services/https:web:8443/proxy/ready":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("database unreachable"))
+		case "POST /api/v1/namespaces/default/services/web/proxy/admin/":
+			body, _ := io.ReadAll(req.Body)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"query":"` + req.URL.RawQuery + `","contentType":"` + req.Header.Get("Content-Type") + `","body":` + string(body) + `}`))
+		case "GET /api/v1/namespaces/default/pods/web-1/proxy/metrics":
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			_, _ = w.Write([]byte(strings.Repeat("metric 1\n", 100)))
+		}
+	}))
+}
+
+func (s *ServiceProxySuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *ServiceProxySuite) TestServiceProxyRequest() {
+	s.InitMcpClient()
+	s.Run("service_proxy_request(name=web, port=8080, path=/healthz)", func() {
+		toolResult, err := s.CallTool("service_proxy_request", map[string]any{"namespace": "default", "name": "web", "port": "8080", "path": "/healthz"})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("# HTTP 200 OK from Service web GET /healthz (text/plain)\nok", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("service_proxy_request(name=web, scheme=https, port=8443, path=/ready) returns the error responses", func() {
+		toolResult, err := s.CallTool("service_proxy_request", map[string]any{"namespace": "default", "name": "web", "scheme": "https", "port": "8443", "path": "/ready"})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# HTTP 503 Service Unavailable from Service web GET /ready")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "\ndatabase unreachable")
+	})
+	s.Run("service_proxy_request(method=POST) sends the query, headers, and body", func() {
+		toolResult, err := s.CallTool("service_proxy_request", map[string]any{
+			"namespace": "default", "name": "web", "method": "POST", "path": "/admin/?dry=true",
+			"headers": map[string]any{"Content-Type": "application/json"}, "body": `{"level":"debug"}`,
+		})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("# HTTP 200 OK from Service web POST /admin/?dry=true (application/json)\n"+
+			`{"query":"dry=true","contentType":"application/json","body":{"level":"debug"}}`, toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("service_proxy_request(kind=Pod, max_bytes=18) truncates the body", func() {
+		toolResult, err := s.CallTool("service_proxy_request", map[string]any{"namespace": "default", "kind": "Pod", "name": "web-1", "path": "/metrics", "max_bytes": 18})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("# HTTP 200 OK from Pod web-1 GET /metrics (text/plain; version=0.0.4)\n"+
+			"# The response body was truncated to 18 bytes, increase max_bytes or narrow the request down to get the rest\n"+
+			"metric 1\nmetric 1\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("service_proxy_request(headers=Authorization) is rejected", func() {
+		toolResult, err := s.CallTool("service_proxy_request", map[string]any{"namespace": "default", "name": "web", "headers": map[string]any{"authorization": "Bearer token"}})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to send proxy request to Service web: header authorization is not allowed, it would replace the credentials of the request to the API server",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("service_proxy_request(scheme=https) without port is rejected", func() {
+		toolResult, err := s.CallTool("service_proxy_request", map[string]any{"namespace": "default", "name": "web", "scheme": "https"})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to send proxy request to Service web: the port is required when the scheme is provided", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestServiceProxy(t *testing.T) {
+	suite.Run(t, new(ServiceProxySuite))
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+// sessionToolCalls counts the tool calls in flight of each session, and the size of the results returned to it
+type sessionToolCalls struct {
+	mu          sync.Mutex
+	inFlight    map[string]int
+	outputBytes map[string]int64
+}
+
+func newSessionToolCalls() *sessionToolCalls {
+	return &sessionToolCalls{inFlight: make(map[string]int), outputBytes: make(map[string]int64)}
+}
+
+// acquire counts a new tool call of the session, false if the session already has limit calls in flight (0 is unlimited)
+func (c *sessionToolCalls) acquire(session string, limit int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if limit > 0 && c.inFlight[session] >= limit {
+		return false
+	}
+	c.inFlight[session]++
+	return true
+}
+
+func (c *sessionToolCalls) release(session string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.inFlight[session]--; c.inFlight[session] <= 0 {
+		delete(c.inFlight, session)
+	}
+}
+
+func (c *sessionToolCalls) count(session string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.inFlight[session]
+}
+
+func (c *sessionToolCalls) addOutput(session string, bytes int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.outputBytes[session] += bytes
+}
+
+func (c *sessionToolCalls) output(session string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.outputBytes[session]
+}
+
+func (c *sessionToolCalls) endSession(session string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.outputBytes, session)
+}
+
+// sessionLimitsMiddleware rejects the tool calls of a session beyond its limit of concurrent tool calls.
+// The sessionless calls (stateless HTTP) are limited by the credentials (Authorization header) they provide, so that
+// the callers of different identities don't share the same limit.
+func (s *Server) sessionLimitsMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method != "tools/call" {
+			return next(ctx, method, req)
+		}
+		session := ""
+		if serverSession, ok := req.GetSession().(*mcp.ServerSession); ok && serverSession != nil {
+			session = serverSession.ID()
+		}
+		// the middleware runs before the Authorization header is propagated to the context, it's read from the request
+		if session == "" {
+			session = credentialsKey(session, authorizationHeader(req))
+		}
+		limit := s.configuration.SessionLimits.MaxConcurrentToolCalls
+		if !s.toolCalls.acquire(session, limit) {
+			return NewTextResult("", api.NewToolError(api.ErrorCodeRateLimited,
+				fmt.Errorf("the session reached its limit of %d concurrent tool calls, retry once the running calls complete", limit))), nil
+		}
+		defer s.toolCalls.release(session)
+		return next(ctx, method, req)
+	}
+}
@@ -0,0 +1,219 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/sessionbudget"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	"sigs.k8s.io/yaml"
+)
+
+type SessionLimitsSuite struct {
+	BaseMcpSuite
+	mockServer       *test.MockServer
+	originalToolsets []api.Toolset
+	release          chan struct{}
+}
+
+func (s *SessionLimitsSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.release = make(chan struct{})
+	s.originalToolsets = toolsets.Toolsets()
+	toolsets.Clear()
+	toolsets.Register(operationsTestToolset(s.release))
+	s.Cfg.Toolsets = []string{"operations-test"}
+}
+
+func (s *SessionLimitsSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	toolsets.Clear()
+	for _, toolset := range s.originalToolsets {
+		toolsets.Register(toolset)
+	}
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *SessionLimitsSuite) startOperation(args map[string]any) *Operation {
+	toolResult, err := s.CallTool("slow_install", args)
+	s.Require().NoError(err, "call tool should not return error object")
+	s.Require().False(toolResult.IsError, "call tool should succeed")
+	operation := &Operation{}
+	s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), operation))
+	return operation
+}
+
+func (s *SessionLimitsSuite) operationStatus(id string) (*Operation, *mcp.CallToolResult) {
+	toolResult, err := s.CallTool("operations_status", map[string]any{"id": id})
+	s.Require().NoError(err, "call tool should not return error object")
+	operation := &Operation{}
+	if !toolResult.IsError {
+		s.Require().NoError(yaml.Unmarshal([]byte(toolResult.Content[0].(mcp.TextContent).Text), operation))
+	}
+	return operation, toolResult
+}
+
+func (s *SessionLimitsSuite) TestMaxConcurrentToolCalls() {
+	s.Cfg.SessionLimits.MaxConcurrentToolCalls = 1
+	s.InitMcpClient()
+	done := make(chan *mcp.CallToolResult)
+	go func() {
+		toolResult, _ := s.CallTool("slow_install", map[string]any{})
+		done <- toolResult
+	}()
+	s.Eventually(func() bool {
+		s.mcpServer.toolCalls.mu.Lock()
+		defer s.mcpServer.toolCalls.mu.Unlock()
+		return len(s.mcpServer.toolCalls.inFlight) == 1
+	}, 5*time.Second, 10*time.Millisecond, "expected the slow tool call to be in flight")
+	s.Run("rejects the tool calls beyond the limit", func() {
+		toolResult, err := s.CallTool("quick_list", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Equal("the session reached its limit of 1 concurrent tool calls, retry once the running calls complete",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	close(s.release)
+	s.Require().False((<-done).IsError, "expected the slow tool call to succeed")
+	s.Run("accepts the tool calls once the running calls complete", func() {
+		toolResult, err := s.CallTool("quick_list", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.False(toolResult.IsError, "call tool should succeed")
+	})
+}
+
+func (s *SessionLimitsSuite) TestMaxConcurrentToolCallsStateless() {
+	s.Cfg.Stateless = true
+	s.Cfg.SessionLimits.MaxConcurrentToolCalls = 1
+	s.InitMcpClient(test.WithTransport(transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer a-token"})))
+	done := make(chan *mcp.CallToolResult)
+	go func() {
+		toolResult, _ := s.CallTool("slow_install", map[string]any{})
+		done <- toolResult
+	}()
+	s.Eventually(func() bool {
+		s.mcpServer.toolCalls.mu.Lock()
+		defer s.mcpServer.toolCalls.mu.Unlock()
+		return len(s.mcpServer.toolCalls.inFlight) == 1
+	}, 5*time.Second, 10*time.Millisecond, "expected the slow tool call to be in flight")
+	s.Run("rejects the tool calls of the same identity beyond the limit", func() {
+		toolResult, err := s.CallTool("quick_list", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+	})
+	s.Run("accepts the tool calls of another identity", func() {
+		other := test.NewMcpClient(s.T(), s.mcpServer.ServeHTTP(), test.WithTransport(transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer another-token"})))
+		defer other.Close()
+		toolResult, err := other.CallTool("quick_list", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+	})
+	close(s.release)
+	s.Require().False((<-done).IsError, "expected the slow tool call to succeed")
+}
+
+func (s *SessionLimitsSuite) TestMaxOperations() {
+	s.Cfg.SessionLimits.MaxOperations = 1
+	s.InitMcpClient()
+	first := s.startOperation(map[string]any{"async": true})
+	second := s.startOperation(map[string]any{"async": true})
+	s.Run("cancels the oldest running operation", func() {
+		operation, _ := s.operationStatus(first.ID)
+		s.Equal(OperationFailed, operation.Status)
+		s.Equal("operation cancelled, the session reached its limit of 1 running operations", operation.Error)
+	})
+	s.Run("keeps the new operation running", func() {
+		operation, _ := s.operationStatus(second.ID)
+		s.Equal(OperationRunning, operation.Status)
+	})
+	s.Run("completes the new operation", func() {
+		close(s.release)
+		s.Eventually(func() bool {
+			operation, _ := s.operationStatus(second.ID)
+			return operation.Status == OperationSucceeded
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+}
+
+func (s *SessionLimitsSuite) TestMaxGoroutines() {
+	s.Cfg.SessionLimits.MaxGoroutines = 1
+	s.InitMcpClient()
+	first := s.startOperation(map[string]any{"async": true})
+	second := s.startOperation(map[string]any{"async": true})
+	goroutines := func() int64 {
+		for session := range s.mcpServer.server.Sessions() {
+			return s.mcpServer.budget.Usage(session.ID())[sessionbudget.Goroutines]
+		}
+		return 0
+	}
+	s.Run("tears down the oldest background goroutine", func() {
+		operation, _ := s.operationStatus(first.ID)
+		s.Equal(OperationFailed, operation.Status)
+		s.Equal("operation cancelled, the session reached its limit of 1 background goroutines", operation.Error)
+	})
+	s.Run("accounts the goroutine of the new operation", func() {
+		s.Equal(int64(1), goroutines())
+		operation, _ := s.operationStatus(second.ID)
+		s.Equal(OperationRunning, operation.Status)
+	})
+	s.Run("releases the goroutine of the completed operation", func() {
+		close(s.release)
+		s.Eventually(func() bool {
+			operation, _ := s.operationStatus(second.ID)
+			return operation.Status == OperationSucceeded
+		}, 5*time.Second, 50*time.Millisecond)
+		s.Eventually(func() bool { return goroutines() == 0 }, 5*time.Second, 50*time.Millisecond)
+	})
+}
+
+func (s *SessionLimitsSuite) TestMaxRetainedBytes() {
+	s.Cfg.SessionLimits.MaxRetainedBytes = 100
+	s.InitMcpClient()
+	close(s.release)
+	large := s.startOperation(map[string]any{"async": true, "size": 80})
+	s.Eventually(func() bool {
+		operation, _ := s.operationStatus(large.ID)
+		return operation.Status == OperationSucceeded
+	}, 5*time.Second, 50*time.Millisecond)
+	small := s.startOperation(map[string]any{"async": true, "size": 30})
+	s.Eventually(func() bool {
+		operation, _ := s.operationStatus(small.ID)
+		return operation.Status == OperationSucceeded
+	}, 5*time.Second, 50*time.Millisecond)
+	s.Run("drops the largest result beyond the limit", func() {
+		_, toolResult := s.operationStatus(large.ID)
+		s.True(toolResult.IsError, "expected the large result to be dropped")
+	})
+	s.Run("keeps the results within the limit", func() {
+		toolResult, err := s.CallTool("operations_result", map[string]any{"id": small.ID})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Require().False(toolResult.IsError, "call tool should succeed")
+		s.Len(toolResult.Content[0].(mcp.TextContent).Text, 30)
+	})
+}
+
+func (s *SessionLimitsSuite) TestSessionEnd() {
+	s.InitMcpClient()
+	s.startOperation(map[string]any{"async": true})
+	s.Require().Len(s.mcpServer.operations.items, 1)
+	s.Close()
+	s.McpClient = nil
+	s.Eventually(func() bool {
+		s.mcpServer.operations.mu.Lock()
+		defer s.mcpServer.operations.mu.Unlock()
+		return len(s.mcpServer.operations.items) == 0
+	}, 5*time.Second, 50*time.Millisecond, "expected the operations of the ended session to be released")
+}
+
+func TestSessionLimits(t *testing.T) {
+	suite.Run(t, new(SessionLimitsSuite))
+}
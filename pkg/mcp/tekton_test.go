@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type TektonSuite struct {
+	BaseMcpSuite
+	mockServer  *test.MockServer
+	pipelineRun unstructured.Unstructured
+	taskRuns    []unstructured.Unstructured
+	created     *unstructured.Unstructured
+}
+
+func (s *TektonSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.created = nil
+	s.pipelineRun = unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "tekton.dev/v1",
+		"kind":       "PipelineRun",
+		"metadata":   map[string]any{"name": "build-1", "namespace": "ci", "labels": map[string]any{"tekton.dev/pipeline": "build"}},
+		"spec":       map[string]any{"pipelineRef": map[string]any{"name": "build"}},
+		"status": map[string]any{
+			"startTime":      "2025-01-01T14:00:00Z",
+			"completionTime": "2025-01-01T14:02:00Z",
+			"conditions":     []any{map[string]any{"type": "Succeeded", "status": "False", "reason": "Failed", "message": "Tasks Completed: 2 (Failed: 1, Cancelled 0), Skipped: 0"}},
+		},
+	}}
+	taskRun := func(name string, exitCode int64, reason, succeeded string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "tekton.dev/v1",
+			"kind":       "TaskRun",
+			"metadata": map[string]any{"name": "build-1-" + name, "namespace": "ci", "labels": map[string]any{
+				"tekton.dev/pipeline": "build", "tekton.dev/pipelineRun": "build-1", "tekton.dev/pipelineTask": name,
+			}},
+			"spec": map[string]any{"taskRef": map[string]any{"name": name}},
+			"status": map[string]any{
+				"podName":    "build-1-" + name + "-pod",
+				"conditions": []any{map[string]any{"type": "Succeeded", "status": succeeded}},
+				"steps":      []any{map[string]any{"name": name, "container": "step-" + name, "terminated": map[string]any{"exitCode": exitCode, "reason": reason}}},
+			},
+		}}
+	}
+	s.taskRuns = []unstructured.Unstructured{taskRun("clone", 0, "Completed", "True"), taskRun("test", 1, "Error", "False")}
+	discovery := test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "tekton.dev/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "pipelineruns", Kind: "PipelineRun", Namespaced: true, Verbs: metav1.Verbs{"get", "list", "create"}},
+			{Name: "taskruns", Kind: "TaskRun", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+		},
+	})
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(discovery)
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/tekton.dev/v1/pipelineruns":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "tekton.dev/v1", "kind": "PipelineRunList"},
+				Items:  []unstructured.Unstructured{s.pipelineRun},
+			})
+		case "/apis/tekton.dev/v1/taskruns", "/apis/tekton.dev/v1/namespaces/ci/taskruns":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "tekton.dev/v1", "kind": "TaskRunList"},
+				Items:  s.taskRuns,
+			})
+		case "/apis/tekton.dev/v1/namespaces/ci/taskruns/build-1-clone":
+			test.WriteObject(w, &s.taskRuns[0])
+		case "/apis/tekton.dev/v1/namespaces/ci/pipelineruns/build-1":
+			test.WriteObject(w, &s.pipelineRun)
+		case "/apis/tekton.dev/v1/namespaces/ci/pipelineruns":
+			if req.Method != http.MethodPost {
+				return
+			}
+			s.created = &unstructured.Unstructured{}
+			if err := json.NewDecoder(req.Body).Decode(&s.created.Object); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			s.created.SetName(s.created.GetGenerateName() + "x7k2p")
+			w.WriteHeader(http.StatusCreated)
+			test.WriteObject(w, s.created)
+		case "/api/v1/namespaces/ci/pods/build-1-clone-pod/log":
+			_, _ = w.Write([]byte("Cloned main\n"))
+		case "/api/v1/namespaces/ci/pods/build-1-test-pod/log":
+			_, _ = w.Write([]byte("--- FAIL: TestOrders\n"))
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Toolsets = []string{"tekton"}
+}
+
+func (s *TektonSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *TektonSuite) TestRunsList() {
+	s.InitMcpClient()
+	s.Run("tekton_runs_list lists all the kinds", func() {
+		toolResult, err := s.CallTool("tekton_runs_list", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of runs", func() {
+			s.Truef(strings.HasPrefix(text, "# 3 Tekton run(s) found, 2 failed, 0 running (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the duration and the failed steps", func() {
+			s.Contains(text, "duration: 2m0s")
+			s.Contains(text, "failedSteps:\n  - test")
+		})
+	})
+	s.Run("tekton_runs_list(pipelinerun=build-1) lists the TaskRuns", func() {
+		toolResult, err := s.CallTool("tekton_runs_list", map[string]interface{}{"namespace": "ci", "pipelinerun": "build-1"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# 2 Tekton run(s) found, 1 failed, 0 running (YAML format):\n"),
+			"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *TektonSuite) TestRunLogs() {
+	s.InitMcpClient()
+	s.Run("tekton_run_logs(kind=PipelineRun) returns the logs of the failed TaskRuns", func() {
+		toolResult, err := s.CallTool("tekton_run_logs", map[string]interface{}{"kind": "PipelineRun", "namespace": "ci", "name": "build-1"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		s.Equal("# TaskRun build-1-test step test (exit code 1, Error):\n--- FAIL: TestOrders\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("tekton_run_logs(name=build-1-clone) returns the logs of the TaskRun", func() {
+		toolResult, err := s.CallTool("tekton_run_logs", map[string]interface{}{"namespace": "ci", "name": "build-1-clone"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		s.Equal("# TaskRun build-1-clone step clone (exit code 0, Completed):\nCloned main\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("tekton_run_logs(missing name)", func() {
+		toolResult, err := s.CallTool("tekton_run_logs", map[string]interface{}{"namespace": "ci"})
+		s.Nilf(err, "call tool should not return error object")
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "failed to get Tekton run logs:")
+	})
+}
+
+func (s *TektonSuite) TestPipelineRunRerun() {
+	s.InitMcpClient()
+	s.Run("tekton_pipelinerun_rerun(name=build-1)", func() {
+		toolResult, err := s.CallTool("tekton_pipelinerun_rerun", map[string]interface{}{"namespace": "ci", "name": "build-1"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		s.Run("creates a PipelineRun without the managed labels", func() {
+			s.Require().NotNil(s.created)
+			s.Equal("build-1-r-", s.created.GetGenerateName())
+			s.Empty(s.created.GetLabels())
+		})
+		s.Run("returns the summary of the new PipelineRun", func() {
+			s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# PipelineRun ci/build-1 rerun as build-1-r-x7k2p (YAML format):\n"),
+				"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+}
+
+func TestTekton(t *testing.T) {
+	suite.Run(t, new(TektonSuite))
+}
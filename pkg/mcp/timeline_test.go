@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type TimelineSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *TimelineSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	discovery := test.NewDiscoveryClientHandler()
+	discovery.APIResourceLists[0].APIResources = append(discovery.APIResourceLists[0].APIResources,
+		metav1.APIResource{Name: "events", Kind: "Event", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+		metav1.APIResource{Name: "secrets", Kind: "Secret", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}})
+	discovery.APIResourceLists[1].APIResources = append(discovery.APIResourceLists[1].APIResources,
+		metav1.APIResource{Name: "replicasets", Kind: "ReplicaSet", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+		metav1.APIResource{Name: "controllerrevisions", Kind: "ControllerRevision", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}})
+	s.mockServer.Handle(discovery)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *TimelineSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *TimelineSuite) TestChangeTimeline() {
+	recent := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid", CreationTimestamp: metav1.NewTime(time.Now().Add(-48 * time.Hour)),
+			Labels: map[string]string{"app.kubernetes.io/instance": "web"}},
+		Spec: appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/apps/v1/namespaces/default/deployments/web":
+			test.WriteObject(w, deployment)
+		case "/apis/apps/v1/namespaces/default/replicasets":
+			test.WriteObject(w, &appsv1.ReplicaSetList{Items: []appsv1.ReplicaSet{{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-6c9f", Namespace: "default", CreationTimestamp: recent,
+					Annotations:     map[string]string{"deployment.kubernetes.io/revision": "2"},
+					OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web", UID: "web-uid", Controller: ptr.To(true)}}},
+				Spec: appsv1.ReplicaSetSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "web", Image: "web:2.0"}}}}},
+			}}})
+		case "/api/v1/namespaces/default/pods":
+			test.WriteObject(w, &v1.PodList{Items: []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "web-6c9f-abcde", Namespace: "default"}}}})
+		case "/api/v1/namespaces/default/events":
+			test.WriteObject(w, &v1.EventList{Items: []v1.Event{
+				{ObjectMeta: metav1.ObjectMeta{Name: "web-event", Namespace: "default"}, InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-6c9f-abcde"},
+					Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", LastTimestamp: recent},
+				{ObjectMeta: metav1.ObjectMeta{Name: "other-event", Namespace: "default"}, InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "other"},
+					Type: "Normal", Reason: "Pulled", LastTimestamp: recent},
+			}})
+		case "/api/v1/namespaces/default/secrets":
+			test.WriteObject(w, &v1.SecretList{Items: []v1.Secret{
+				{ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.web.v2", Namespace: "default", CreationTimestamp: recent,
+					Labels: map[string]string{"owner": "helm", "name": "web", "version": "2", "status": "deployed"}}},
+			}})
+		}
+	}))
+	s.InitMcpClient()
+	s.Run("change_timeline(kind=Deployment, name=web)", func() {
+		toolResult, err := s.CallTool("change_timeline", map[string]interface{}{"kind": "Deployment", "name": "web"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of changes", func() {
+			s.Truef(strings.HasPrefix(text, "# 3 change(s) between "), "unexpected result %v", text)
+		})
+		var entries []map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &entries))
+		s.Run("merges the workload events, rollouts, and Helm releases", func() {
+			s.Require().Len(entries, 3)
+			s.Equal("event", entries[0]["source"])
+			s.Equal("Pod/web-6c9f-abcde", entries[0]["object"])
+			s.Equal("helm", entries[1]["source"])
+			s.Equal("revision 2 installed (status deployed)", entries[1]["description"])
+			s.Equal("rollout", entries[2]["source"])
+			s.Equal("revision 2 rolled out with ReplicaSet web-6c9f (web=web:2.0)", entries[2]["description"])
+		})
+	})
+	s.Run("change_timeline(kind=Deployment, name=web, around=2000-01-01T00:00:00Z)", func() {
+		toolResult, err := s.CallTool("change_timeline", map[string]interface{}{"kind": "Deployment", "name": "web", "around": "2000-01-01T00:00:00Z"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Equal("# No changes found between 1999-12-31T23:00:00Z and 2000-01-01T01:00:00Z", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("change_timeline(around=invalid)", func() {
+		toolResult, _ := s.CallTool("change_timeline", map[string]interface{}{"around": "invalid"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "invalid around invalid")
+	})
+}
+
+func (s *TimelineSuite) TestIncidentTimeline() {
+	at := func(d time.Duration) metav1.Time { return metav1.NewTime(time.Now().Add(-d).Truncate(time.Second)) }
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/apps/v1/namespaces/default/replicasets":
+			test.WriteObject(w, &appsv1.ReplicaSetList{Items: []appsv1.ReplicaSet{{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-6c9f", Namespace: "default", CreationTimestamp: at(20 * time.Minute),
+					Annotations:     map[string]string{"deployment.kubernetes.io/revision": "2"},
+					OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web", UID: "web-uid", Controller: ptr.To(true)}}},
+				Spec: appsv1.ReplicaSetSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "web", Image: "web:2.0"}}}}},
+			}}})
+		case "/apis/apps/v1/namespaces/default/controllerrevisions":
+			test.WriteObject(w, &appsv1.ControllerRevisionList{})
+		case "/api/v1/namespaces/default/pods":
+			test.WriteObject(w, &v1.PodList{Items: []v1.Pod{{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-6c9f-abcde", Namespace: "default"},
+				Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{Name: "web", RestartCount: 3,
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137, FinishedAt: at(5 * time.Minute)}}}}},
+			}}})
+		case "/api/v1/namespaces/default/events":
+			events := []v1.Event{
+				{ObjectMeta: metav1.ObjectMeta{Name: "web-event", Namespace: "default"}, InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-6c9f-abcde"},
+					Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", LastTimestamp: at(10 * time.Minute)},
+				{ObjectMeta: metav1.ObjectMeta{Name: "other-event", Namespace: "default"}, InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "other"},
+					Type: "Normal", Reason: "Pulled", LastTimestamp: at(10 * time.Minute)},
+			}
+			if req.URL.Query().Get("fieldSelector") == "type=Warning" {
+				events = events[:1]
+			}
+			test.WriteObject(w, &v1.EventList{Items: events})
+		case "/api/v1/namespaces/default/secrets":
+			test.WriteObject(w, &v1.SecretList{Items: []v1.Secret{
+				{ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.web.v2", Namespace: "default", CreationTimestamp: at(21 * time.Minute),
+					Labels: map[string]string{"owner": "helm", "name": "web", "version": "2", "status": "deployed"}}},
+			}})
+		}
+	}))
+	s.InitMcpClient()
+	s.Run("incident_timeline()", func() {
+		toolResult, err := s.CallTool("incident_timeline", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the time range", func() {
+			s.Truef(strings.HasPrefix(text, "# Incident timeline between "), "unexpected result %v", text)
+		})
+		var timeline map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &timeline))
+		s.Run("merges the Warning events, restarts, rollouts, and Helm revisions chronologically", func() {
+			s.Require().IsType([]any{}, timeline["timeline"])
+			entries := timeline["timeline"].([]any)
+			s.Require().Len(entries, 4)
+			s.Equal("helm", entries[0].(map[string]any)["source"])
+			s.Equal("rollout", entries[1].(map[string]any)["source"])
+			s.Equal("event", entries[2].(map[string]any)["source"])
+			s.Equal("Warning BackOff: Back-off restarting failed container", entries[2].(map[string]any)["description"])
+			s.Equal("restart", entries[3].(map[string]any)["source"])
+			s.Equal("container web terminated with OOMKilled (exit code 137) and restarted (3 restarts)", entries[3].(map[string]any)["description"])
+		})
+		s.Run("summarizes the entries by source", func() {
+			s.Equal(map[string]any{"event": float64(1), "helm": float64(1), "restart": float64(1), "rollout": float64(1)}, timeline["summary"])
+		})
+		s.Run("points out the first problem and the changes before it", func() {
+			s.Require().IsType(map[string]any{}, timeline["firstProblem"])
+			s.Equal("Pod/web-6c9f-abcde", timeline["firstProblem"].(map[string]any)["object"])
+			s.Equal("event", timeline["firstProblem"].(map[string]any)["source"])
+			s.Len(timeline["precedingChanges"], 2)
+		})
+	})
+	s.Run("incident_timeline(from=2000-01-01T00:00:00Z, to=2000-01-01T01:00:00Z)", func() {
+		toolResult, err := s.CallTool("incident_timeline", map[string]interface{}{"from": "2000-01-01T00:00:00Z", "to": "2000-01-01T01:00:00Z"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Equal("# No Warning events, restarts, rollouts, or Helm revisions found between 2000-01-01T00:00:00Z and 2000-01-01T01:00:00Z", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("incident_timeline(from after to)", func() {
+		toolResult, _ := s.CallTool("incident_timeline", map[string]interface{}{"from": "2000-01-01T02:00:00Z", "to": "2000-01-01T01:00:00Z"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "invalid time range, from 2000-01-01T02:00:00Z is not before to 2000-01-01T01:00:00Z")
+	})
+	s.Run("incident_timeline(from=invalid)", func() {
+		toolResult, _ := s.CallTool("incident_timeline", map[string]interface{}{"from": "invalid"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "invalid from: invalid around invalid")
+	})
+}
+
+func TestTimeline(t *testing.T) {
+	suite.Run(t, new(TimelineSuite))
+}
@@ -1,6 +1,8 @@
 package mcp
 
 import (
+	"slices"
+
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 )
@@ -46,3 +48,14 @@ func ShouldIncludeTargetListTool(targetName string, targets []string) ToolFilter
 		return true
 	}
 }
+
+// ShouldIncludeServedAPITool filters out the tools working with optional API groups none of which is served by the
+// cluster, all the tools are included if the served API groups are unknown (nil)
+func ShouldIncludeServedAPITool(servedGroups []string) ToolFilter {
+	return func(tool api.ServerTool) bool {
+		if servedGroups == nil || len(tool.APIGroups) == 0 {
+			return true
+		}
+		return slices.ContainsFunc(tool.APIGroups, func(group string) bool { return slices.Contains(servedGroups, group) })
+	}
+}
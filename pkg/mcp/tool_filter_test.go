@@ -79,6 +79,22 @@ func (s *ToolFilterSuite) TestShouldIncludeTargetListTool() {
 	})
 }
 
+func (s *ToolFilterSuite) TestShouldIncludeServedAPITool() {
+	tool := api.ServerTool{Tool: api.Tool{Name: "test"}, APIGroups: []string{"kustomize.toolkit.fluxcd.io", "helm.toolkit.fluxcd.io"}}
+	s.Run("tools without API groups: returns true", func() {
+		s.True(ShouldIncludeServedAPITool([]string{"apps"})(api.ServerTool{Tool: api.Tool{Name: "test"}}))
+	})
+	s.Run("served API groups unknown: returns true", func() {
+		s.True(ShouldIncludeServedAPITool(nil)(tool))
+	})
+	s.Run("one of the API groups served: returns true", func() {
+		s.True(ShouldIncludeServedAPITool([]string{"apps", "helm.toolkit.fluxcd.io"})(tool))
+	})
+	s.Run("none of the API groups served: returns false", func() {
+		s.False(ShouldIncludeServedAPITool([]string{"apps", "argoproj.io"})(tool))
+	})
+}
+
 func TestToolFilter(t *testing.T) {
 	suite.Run(t, new(ToolFilterSuite))
 }
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
 	"github.com/google/jsonschema-go/jsonschema"
 )
 
@@ -133,3 +135,64 @@ func capitalizeFirst(s string) string {
 	}
 	return strings.ToUpper(s[:1]) + s[1:]
 }
+
+// LocaleParameterName is the name of the parameter that selects the locale of the human-readable summaries of a tool call
+const LocaleParameterName = "locale"
+
+// WithLocaleParameter adds the locale selection parameter to the tool's input schema if the tool is localized
+func WithLocaleParameter() ToolMutator {
+	return func(tool api.ServerTool) api.ServerTool {
+		if !tool.IsLocalized() {
+			return tool
+		}
+		if tool.Tool.InputSchema == nil {
+			tool.Tool.InputSchema = &jsonschema.Schema{Type: "object"}
+		}
+		if tool.Tool.InputSchema.Properties == nil {
+			tool.Tool.InputSchema.Properties = make(map[string]*jsonschema.Schema)
+		}
+		enum := make([]any, 0, len(locale.Names))
+		for _, name := range locale.Names {
+			enum = append(enum, name)
+		}
+		tool.Tool.InputSchema.Properties[LocaleParameterName] = &jsonschema.Schema{
+			Type:        "string",
+			Description: "Optional parameter selecting the language of the human-readable summaries (e.g. findings, durations) of the result, the names and reasons reported by Kubernetes are left untouched (Optional, defaults to the configured locale)",
+			Enum:        enum,
+		}
+		return tool
+	}
+}
+
+// OutputParameterName is the name of the parameter that selects the output format of a tool call
+const OutputParameterName = "output"
+
+// WithOutputParameter adds the output format selection parameter to the tool's input schema
+func WithOutputParameter() ToolMutator {
+	return func(tool api.ServerTool) api.ServerTool {
+		if tool.Tool.InputSchema == nil {
+			tool.Tool.InputSchema = &jsonschema.Schema{Type: "object"}
+		}
+		if tool.Tool.InputSchema.Properties == nil {
+			tool.Tool.InputSchema.Properties = make(map[string]*jsonschema.Schema)
+		}
+		if _, ok := tool.Tool.InputSchema.Properties[OutputParameterName]; ok {
+			return tool
+		}
+		// the table output is only offered to the tools printing their list results as a table
+		if tool.IsTableOutput() {
+			tool.Tool.InputSchema.Properties[OutputParameterName] = &jsonschema.Schema{
+				Type:        "string",
+				Description: "Optional parameter selecting the output format of structured results (json is compact and single-line, table uses the columns printed by the Kubernetes API server for list results). Plain text results such as logs are returned unchanged",
+				Enum:        []any{output.Yaml.GetName(), output.Json.GetName(), output.Table.GetName()},
+			}
+			return tool
+		}
+		tool.Tool.InputSchema.Properties[OutputParameterName] = &jsonschema.Schema{
+			Type:        "string",
+			Description: "Optional parameter selecting the output format of structured results (json is compact and single-line). Plain text results such as logs are returned unchanged",
+			Enum:        []any{output.Yaml.GetName(), output.Json.GetName()},
+		}
+		return tool
+	}
+}
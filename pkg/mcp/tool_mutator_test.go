@@ -392,3 +392,17 @@ func (s *TargetListToolMutatorSuite) TestHandlerWithEmptyTargets() {
 func TestTargetListToolMutator(t *testing.T) {
 	suite.Run(t, new(TargetListToolMutatorSuite))
 }
+
+func TestWithLocaleParameter(t *testing.T) {
+	t.Run("adds the locale parameter to the localized tools", func(t *testing.T) {
+		tool := createTestTool("localized-tool")
+		tool.Localized = ptr.To(true)
+		result := WithLocaleParameter()(tool)
+		require.Contains(t, result.Tool.InputSchema.Properties, LocaleParameterName)
+		assert.Equal(t, []any{"en", "de", "ja"}, result.Tool.InputSchema.Properties[LocaleParameterName].Enum)
+	})
+	t.Run("leaves the other tools untouched", func(t *testing.T) {
+		result := WithLocaleParameter()(createTestTool("other-tool"))
+		assert.NotContains(t, result.Tool.InputSchema.Properties, LocaleParameterName)
+	})
+}
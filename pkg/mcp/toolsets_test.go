@@ -13,12 +13,20 @@ import (
 	configuration "github.com/containers/kubernetes-mcp-server/pkg/config"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/argocd"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/clusterapi"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/config"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/core"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/crossplane"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/externalsecrets"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/flux"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/helm"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/kcp"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/keda"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/kiali"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/kubevirt"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/policy"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets/tekton"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/suite"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
@@ -156,11 +164,19 @@ func (s *ToolsetsSuite) TestDefaultToolsetsToolsInMultiClusterEnum() {
 
 func (s *ToolsetsSuite) TestGranularToolsetsTools() {
 	testCases := []api.Toolset{
+		&argocd.Toolset{},
+		&clusterapi.Toolset{},
 		&core.Toolset{},
+		&crossplane.Toolset{},
 		&config.Toolset{},
+		&externalsecrets.Toolset{},
+		&flux.Toolset{},
 		&helm.Toolset{},
+		&keda.Toolset{},
 		&kiali.Toolset{},
 		&kubevirt.Toolset{},
+		&policy.Toolset{},
+		&tekton.Toolset{},
 	}
 	for _, testCase := range testCases {
 		s.Run("Toolset "+testCase.GetName(), func() {
@@ -189,16 +205,18 @@ func (s *ToolsetsSuite) TestInputSchemaEdgeCases() {
 			s.NotNil(tools, "Expected tools from ListTools")
 			s.NoError(err, "Expected no error from ListTools")
 		})
-		var namespacesList *mcp.Tool
+		var serverStatus *mcp.Tool
 		for _, tool := range tools.Tools {
-			if tool.Name == "namespaces_list" {
-				namespacesList = &tool
+			if tool.Name == "server_status" {
+				serverStatus = &tool
 				break
 			}
 		}
-		s.Require().NotNil(namespacesList, "Expected namespaces_list from ListTools")
-		s.NotNil(namespacesList.InputSchema.Properties, "Expected namespaces_list.InputSchema.Properties not to be nil")
-		s.Empty(namespacesList.InputSchema.Properties, "Expected namespaces_list.InputSchema.Properties to be empty")
+		s.Require().NotNil(serverStatus, "Expected server_status from ListTools")
+		s.NotNil(serverStatus.InputSchema.Properties, "Expected server_status.InputSchema.Properties not to be nil")
+		// the output parameter is added to every tool
+		s.Len(serverStatus.InputSchema.Properties, 1, "Expected server_status.InputSchema.Properties to only contain the output parameter")
+		s.Contains(serverStatus.InputSchema.Properties, OutputParameterName, "Expected server_status.InputSchema.Properties to only contain the output parameter")
 	})
 	// https://github.com/containers/kubernetes-mcp-server/issues/717
 	// Verifies ALL tools have Properties initialized (not just cluster-aware ones)
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type TrafficSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *TrafficSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *TrafficSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *TrafficSuite) TestTrafficRoutesNotInstalled() {
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("traffic_routes", map[string]interface{}{})
+	s.Nilf(err, "call tool should not return error object")
+	s.Truef(toolResult.IsError, "call tool should fail")
+	s.Equal("failed to list traffic routes: neither Istio nor the Gateway API is installed in the cluster", toolResult.Content[0].(mcp.TextContent).Text)
+}
+
+func (s *TrafficSuite) TestTrafficRoutes() {
+	s.mockServer.Handle(test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "networking.istio.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "virtualservices", Kind: "VirtualService", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+			{Name: "destinationrules", Kind: "DestinationRule", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+		},
+	}))
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/networking.istio.io/v1/namespaces/bookinfo/virtualservices":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "networking.istio.io/v1", "kind": "VirtualServiceList"},
+				Items: []unstructured.Unstructured{{Object: map[string]any{
+					"apiVersion": "networking.istio.io/v1",
+					"kind":       "VirtualService",
+					"metadata":   map[string]any{"name": "reviews", "namespace": "bookinfo"},
+					"spec": map[string]any{
+						"hosts": []any{"reviews"},
+						"http": []any{map[string]any{"route": []any{
+							map[string]any{"destination": map[string]any{"host": "reviews", "subset": "v1"}, "weight": int64(90)},
+							map[string]any{"destination": map[string]any{"host": "reviews", "subset": "v2"}, "weight": int64(10)},
+						}}},
+					},
+				}}},
+			})
+		case "/apis/networking.istio.io/v1/destinationrules":
+			test.WriteObject(w, &unstructured.UnstructuredList{
+				Object: map[string]any{"apiVersion": "networking.istio.io/v1", "kind": "DestinationRuleList"},
+				Items: []unstructured.Unstructured{{Object: map[string]any{
+					"apiVersion": "networking.istio.io/v1",
+					"kind":       "DestinationRule",
+					"metadata":   map[string]any{"name": "reviews", "namespace": "bookinfo"},
+					"spec": map[string]any{
+						"host":    "reviews",
+						"subsets": []any{map[string]any{"name": "v1", "labels": map[string]any{"version": "v1"}}},
+					},
+				}}},
+			})
+		}
+	}))
+	s.InitMcpClient()
+	s.Run("traffic_routes(host=reviews, namespace=bookinfo)", func() {
+		toolResult, err := s.CallTool("traffic_routes", map[string]interface{}{"host": "reviews", "namespace": "bookinfo"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the number of rules", func() {
+			s.Truef(strings.HasPrefix(text, "# 1 traffic route rule(s) for host reviews and path /, in evaluation order (YAML format):\n"), "unexpected result %v", text)
+		})
+		s.Run("returns the weights and subsets", func() {
+			s.Contains(text, "percent: 90")
+			s.Contains(text, "version: v1")
+			s.Contains(text, "subset v2 is not defined in DestinationRule bookinfo/reviews")
+		})
+	})
+	s.Run("traffic_routes(host=ratings, namespace=bookinfo)", func() {
+		toolResult, err := s.CallTool("traffic_routes", map[string]interface{}{"host": "ratings", "namespace": "bookinfo"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Falsef(toolResult.IsError, "call tool failed %v", toolResult.Content)
+		s.Equal("# No traffic routes found for host ratings and path /", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func TestTraffic(t *testing.T) {
+	suite.Run(t, new(TrafficSuite))
+}
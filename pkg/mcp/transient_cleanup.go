@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	internalk8s "github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+// transientSweeper periodically deletes the expired transient objects (see internalk8s.TransientLabel) left in the
+// clusters by the tool calls interrupted by a crash or a restart of the server
+type transientSweeper struct {
+	mu     sync.Mutex
+	sweep  func(ctx context.Context)
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newTransientSweeper(sweep func(ctx context.Context)) *transientSweeper {
+	return &transientSweeper{sweep: sweep}
+}
+
+// update restarts the sweeper with the provided interval, the sweeper is stopped if the interval is 0.
+// The first sweep runs immediately to collect the objects left behind by the previous runs of the server.
+func (ts *transientSweeper) update(interval time.Duration) {
+	ts.stop()
+	if interval <= 0 {
+		return
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	ts.cancel = cancel
+	ts.wg.Add(1)
+	go func() {
+		defer ts.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			ts.sweep(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (ts *transientSweeper) stop() {
+	ts.mu.Lock()
+	cancel := ts.cancel
+	ts.cancel = nil
+	ts.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	ts.wg.Wait()
+}
+
+// sweepTransientObjects deletes the expired transient objects in all the namespaces of every cluster target
+func (s *Server) sweepTransientObjects(ctx context.Context) {
+	targets, err := s.p.GetTargets(ctx)
+	if err != nil {
+		klog.Warningf("Failed to get the targets to clean up the transient objects from: %v", err)
+		return
+	}
+	for _, target := range targets {
+		k, err := s.p.GetDerivedKubernetes(ctx, target)
+		if err == nil {
+			var deleted []internalk8s.TransientObject
+			deleted, err = internalk8s.NewCore(k).TransientCleanup(ctx, "", false, false)
+			for _, object := range deleted {
+				klog.V(1).Infof("Deleted expired transient %s %s/%s of target %q", object.Kind, object.Namespace, object.Name, target)
+			}
+		}
+		if err != nil && ctx.Err() == nil {
+			klog.Warningf("Failed to clean up the transient objects of target %q: %v", target, err)
+		}
+	}
+}
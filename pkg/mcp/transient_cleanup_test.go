@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type TransientCleanupSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	deleted    []string
+}
+
+func transientPod(namespace, name string, expiresAt time.Time) v1.Pod {
+	return v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   namespace,
+		Labels:      map[string]string{"kubernetes-mcp-server/transient": "true", "app.kubernetes.io/component": "net-check"},
+		Annotations: map[string]string{"kubernetes-mcp-server/expires-at": expiresAt.UTC().Format(time.RFC3339)},
+	}}
+}
+
+func (s *TransientCleanupSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.deleted = nil
+	pods := []v1.Pod{
+		transientPod("default", "kubernetes-mcp-server-net-check-expired", time.Now().Add(-time.Hour)),
+		transientPod("node-debug", "kubernetes-mcp-server-node-debug-running", time.Now().Add(time.Hour)),
+	}
+	s.mockServer = test.NewMockServer()
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == "/api/v1/pods":
+			if req.URL.Query().Get("labelSelector") != "kubernetes-mcp-server/transient=true" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			test.WriteObject(w, &v1.PodList{Items: pods})
+		case req.Method == http.MethodDelete:
+			for _, pod := range pods {
+				if req.URL.Path == "/api/v1/namespaces/"+pod.Namespace+"/pods/"+pod.Name {
+					s.deleted = append(s.deleted, pod.Namespace+"/"+pod.Name)
+					test.WriteObject(w, &pod)
+				}
+			}
+		}
+	}))
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *TransientCleanupSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *TransientCleanupSuite) deletedPods() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.deleted)
+}
+
+func (s *TransientCleanupSuite) TestTransientCleanup() {
+	s.InitMcpClient()
+	s.Run("dry_run lists the expired objects", func() {
+		toolResult, err := s.CallTool("transient_cleanup", map[string]any{"dry_run": true})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Require().Falsef(toolResult.IsError, "call tool should succeed: %v", toolResult.Content)
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Contains(text, "# 1 transient object(s) would be deleted (dry run, the objects were not deleted) (YAML format):\n")
+		s.Contains(text, "name: kubernetes-mcp-server-net-check-expired")
+		s.Empty(s.deletedPods())
+	})
+	s.Run("deletes the expired objects only", func() {
+		toolResult, err := s.CallTool("transient_cleanup", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Require().Falsef(toolResult.IsError, "call tool should succeed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "# 1 transient object(s) deleted (YAML format):\n")
+		s.Equal([]string{"default/kubernetes-mcp-server-net-check-expired"}, s.deletedPods())
+	})
+	s.Run("all deletes the objects that haven't expired", func() {
+		toolResult, err := s.CallTool("transient_cleanup", map[string]any{"all": true})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Require().Falsef(toolResult.IsError, "call tool should succeed: %v", toolResult.Content)
+		s.Contains(s.deletedPods(), "node-debug/kubernetes-mcp-server-node-debug-running")
+	})
+}
+
+func (s *TransientCleanupSuite) TestSweeper() {
+	s.Cfg.TransientCleanup.Interval = "1h"
+	s.InitMcpClient()
+	s.Run("deletes the expired objects on startup", func() {
+		s.Eventually(func() bool {
+			return slices.Contains(s.deletedPods(), "default/kubernetes-mcp-server-net-check-expired")
+		}, 5*time.Second, 50*time.Millisecond)
+		s.NotContains(s.deletedPods(), "node-debug/kubernetes-mcp-server-node-debug-running")
+	})
+}
+
+func (s *TransientCleanupSuite) TestSweeperDisabled() {
+	s.InitMcpClient()
+	s.Never(func() bool { return len(s.deletedPods()) > 0 }, 200*time.Millisecond, 50*time.Millisecond)
+}
+
+func TestTransientCleanup(t *testing.T) {
+	suite.Run(t, new(TransientCleanupSuite))
+}
@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type VulnerabilitiesSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *VulnerabilitiesSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+}
+
+func (s *VulnerabilitiesSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *VulnerabilitiesSuite) TestImagesVulnerabilities() {
+	s.mockServer.Handle(test.NewDiscoveryClientHandler(metav1.APIResourceList{
+		GroupVersion: "aquasecurity.github.io/v1alpha1",
+		APIResources: []metav1.APIResource{{Name: "vulnerabilityreports", Kind: "VulnerabilityReport", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}},
+	}))
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/apis/aquasecurity.github.io/v1alpha1/namespaces/default/vulnerabilityreports" {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"apiVersion":"aquasecurity.github.io/v1alpha1","kind":"VulnerabilityReportList","items":[` +
+			`{"apiVersion":"aquasecurity.github.io/v1alpha1","kind":"VulnerabilityReport","metadata":{"name":"replicaset-web-nginx","namespace":"default",` +
+			`"labels":{"trivy-operator.resource.kind":"ReplicaSet","trivy-operator.resource.name":"web-5d4f8","trivy-operator.container.name":"nginx"}},` +
+			`"report":{"registry":{"server":"index.docker.io"},"artifact":{"repository":"library/nginx","tag":"1.25"},` +
+			`"summary":{"criticalCount":1,"highCount":3,"mediumCount":5,"lowCount":0,"unknownCount":0},` +
+			`"vulnerabilities":[{"vulnerabilityID":"CVE-2025-0001","severity":"CRITICAL","score":9.8,"resource":"zlib","installedVersion":"1.2","fixedVersion":"1.3"}]}}]}`))
+	}))
+	s.InitMcpClient()
+	s.Run("images_vulnerabilities()", func() {
+		toolResult, err := s.CallTool("images_vulnerabilities", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the vulnerability totals", func() {
+			s.Truef(strings.HasPrefix(text, "# 1 image(s), 1 critical and 3 high vulnerabilities (YAML format):\n"), "unexpected result %v", text)
+		})
+		var images []map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &images))
+		s.Require().Len(images, 1)
+		s.Run("reports the image with its workloads and worst findings", func() {
+			s.Equal("index.docker.io/library/nginx:1.25", images[0]["image"])
+			s.Equal([]any{"ReplicaSet/web-5d4f8 (container nginx)"}, images[0]["workloads"])
+			s.Equal(map[string]any{"critical": float64(1), "high": float64(3), "medium": float64(5), "low": float64(0), "unknown": float64(0)}, images[0]["counts"])
+			s.Len(images[0]["worstFindings"], 1)
+		})
+	})
+	s.Run("images_vulnerabilities(kind=Pod, name=unscanned)", func() {
+		toolResult, err := s.CallTool("images_vulnerabilities", map[string]interface{}{"kind": "Pod", "name": "unscanned"})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Equal("# No vulnerability reports found", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("images_vulnerabilities(kind=Pod)", func() {
+		toolResult, _ := s.CallTool("images_vulnerabilities", map[string]interface{}{"kind": "Pod"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Equal("failed to get image vulnerabilities, missing argument name", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *VulnerabilitiesSuite) TestImagesVulnerabilitiesNoScanner() {
+	s.mockServer.Handle(test.NewDiscoveryClientHandler())
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasPrefix(req.URL.Path, "/apis/aquasecurity.github.io/") {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	s.InitMcpClient()
+	s.Run("images_vulnerabilities() without scanner", func() {
+		toolResult, err := s.CallTool("images_vulnerabilities", map[string]interface{}{})
+		s.Nilf(err, "call tool failed %v", err)
+		s.Truef(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "no in-cluster vulnerability scanner found, install the Trivy Operator")
+	})
+}
+
+func TestVulnerabilities(t *testing.T) {
+	suite.Run(t, new(VulnerabilitiesSuite))
+}
@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+type WebhooksSuite struct {
+	BaseMcpSuite
+}
+
+func (s *WebhooksSuite) TestWebhooksAudit() {
+	client := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	_, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Create(s.T().Context(), &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-webhook-configuration"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name:                    "widgets.example.com",
+			ClientConfig:            admissionregistrationv1.WebhookClientConfig{Service: &admissionregistrationv1.ServiceReference{Namespace: "default", Name: "a-missing-service"}},
+			FailurePolicy:           ptr.To(admissionregistrationv1.Ignore),
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+			AdmissionReviewVersions: []string{"v1"},
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+				Rule:       admissionregistrationv1.Rule{APIGroups: []string{"example.com"}, APIVersions: []string{"v1"}, Resources: []string{"widgets"}},
+			}},
+		}},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(s.T().Context(), "a-webhook-configuration", metav1.DeleteOptions{})
+	})
+	s.InitMcpClient()
+	s.Run("webhooks_audit()", func() {
+		toolResult, err := s.CallTool("webhooks_audit", map[string]interface{}{})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Run("has yaml comment with the webhooks with issues", func() {
+			s.Truef(strings.HasPrefix(text, "# 1 admission webhook(s), 1 with issues (YAML format):\n"), "unexpected result %v", text)
+		})
+		var audits []map[string]any
+		s.Require().NoError(yaml.Unmarshal([]byte(text), &audits))
+		s.Require().Len(audits, 1)
+		s.Run("reports the webhook with its missing backend", func() {
+			s.Equal("widgets.example.com", audits[0]["name"])
+			s.Equal("Ignore", audits[0]["failurePolicy"])
+			s.Equal("service not found", audits[0]["backendStatus"])
+			s.Equal([]any{"backend is down (service not found), the targeted operations are not being validated or mutated"}, audits[0]["issues"])
+		})
+	})
+}
+
+func TestWebhooks(t *testing.T) {
+	suite.Run(t, new(WebhooksSuite))
+}
@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+)
+
+type WorkloadImagesSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+	mu         sync.Mutex
+	patch      string
+}
+
+func (s *WorkloadImagesSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	discovery := test.NewDiscoveryClientHandler()
+	verbs := metav1.Verbs{"get", "list", "watch", "create", "update", "patch", "delete"}
+	// the default apps resource list
+	discovery.APIResourceLists[1].APIResources = append(discovery.APIResourceLists[1].APIResources,
+		metav1.APIResource{Name: "replicasets", Kind: "ReplicaSet", Namespaced: true, Verbs: verbs},
+		metav1.APIResource{Name: "statefulsets", Kind: "StatefulSet", Namespaced: true, Verbs: verbs},
+		metav1.APIResource{Name: "controllerrevisions", Kind: "ControllerRevision", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}})
+	s.mockServer.Handle(discovery)
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.patch = ""
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid"},
+		Spec: appsv1.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "migrate", Image: "app-migrate:1.1"}},
+			Containers:     []v1.Container{{Name: "app", Image: "app:1.1"}, {Name: "proxy", Image: "envoy:1.30"}},
+		}}},
+	}
+	replicaSet := func(name, revision, image, changeCause string, owner types.UID) appsv1.ReplicaSet {
+		return appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default",
+				Annotations:     map[string]string{"deployment.kubernetes.io/revision": revision, "kubernetes.io/change-cause": changeCause},
+				OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: owner, Controller: ptr.To(true)}},
+			},
+			Spec: appsv1.ReplicaSetSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: image}}}}},
+		}
+	}
+	statefulSet := &appsv1.StatefulSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", UID: "db-uid"},
+	}
+	s.mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch req.Method + " " + req.URL.Path {
+		case "GET /apis/apps/v1/namespaces/default/deployments/web":
+			test.WriteObject(w, deployment)
+		case "PATCH /apis/apps/v1/namespaces/default/deployments/web":
+			body, _ := io.ReadAll(req.Body)
+			s.patch = string(body)
+			test.WriteObject(w, deployment)
+		case "GET /apis/apps/v1/namespaces/default/replicasets":
+			test.WriteObject(w, &appsv1.ReplicaSetList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSetList"},
+				Items: []appsv1.ReplicaSet{
+					replicaSet("web-3", "3", "app:1.1", "set image app=app:1.1", "web-uid"),
+					replicaSet("web-1", "1", "app:1.0", "", "web-uid"),
+					replicaSet("other-1", "1", "other:1.0", "", "other-uid"),
+				},
+			})
+		case "GET /apis/apps/v1/namespaces/default/statefulsets/db":
+			test.WriteObject(w, statefulSet)
+		case "GET /apis/apps/v1/namespaces/default/controllerrevisions":
+			test.WriteObject(w, &appsv1.ControllerRevisionList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ControllerRevisionList"},
+				Items: []appsv1.ControllerRevision{{
+					ObjectMeta: metav1.ObjectMeta{Name: "db-5d8c7", Namespace: "default",
+						OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "db", UID: "db-uid", Controller: ptr.To(true)}},
+					},
+					Revision: 1,
+					Data:     runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"$patch":"replace","spec":{"containers":[{"name":"db","image":"postgres:16.2"}]}}}}`)},
+				}},
+			})
+		}
+	}))
+}
+
+func (s *WorkloadImagesSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *WorkloadImagesSuite) TestWorkloadsSetImage() {
+	s.InitMcpClient()
+	s.Run("workloads_set_image(images={app, migrate}) patches the containers and records the change cause", func() {
+		toolResult, err := s.CallTool("workloads_set_image", map[string]any{"kind": "Deployment", "namespace": "default", "name": "web",
+			"images": map[string]any{"app": "app:1.0", "migrate": "app-migrate:1.0"}})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.JSONEq(`{"metadata":{"annotations":{"kubernetes.io/change-cause":"set image app=app:1.0 migrate=app-migrate:1.0"}},`+
+			`"spec":{"template":{"spec":{"containers":[{"image":"app:1.0","name":"app"}],"initContainers":[{"image":"app-migrate:1.0","name":"migrate"}]}}}}`, s.patch)
+		s.Equal("# Images of Deployment default/web updated, a new revision is rolling out, check it with workload_readiness (YAML format):\n"+
+			"changeCause: set image app=app:1.0 migrate=app-migrate:1.0\nchanges:\n"+
+			"- container: app\n  image: app:1.0\n  previous: app:1.1\n"+
+			"- container: migrate\n  image: app-migrate:1.0\n  previous: app-migrate:1.1\n"+
+			"kind: Deployment\nname: web\nnamespace: default\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("workloads_set_image(change_cause) records the provided change cause", func() {
+		toolResult, err := s.CallTool("workloads_set_image", map[string]any{"kind": "Deployment", "namespace": "default", "name": "web",
+			"images": map[string]any{"proxy": "envoy:1.29"}, "change_cause": "roll back the proxy (INC-42)"})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.JSONEq(`{"metadata":{"annotations":{"kubernetes.io/change-cause":"roll back the proxy (INC-42)"}},`+
+			`"spec":{"template":{"spec":{"containers":[{"image":"envoy:1.29","name":"proxy"}]}}}}`, s.patch)
+	})
+	s.Run("workloads_set_image with an unknown container is rejected", func() {
+		s.patch = ""
+		toolResult, err := s.CallTool("workloads_set_image", map[string]any{"kind": "Deployment", "namespace": "default", "name": "web",
+			"images": map[string]any{"api": "app:1.0"}})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to set image of Deployment web: container api not found in Deployment web, its containers are [app migrate proxy]",
+			toolResult.Content[0].(mcp.TextContent).Text)
+		s.Empty(s.patch)
+	})
+	s.Run("workloads_set_image without images is rejected", func() {
+		toolResult, err := s.CallTool("workloads_set_image", map[string]any{"kind": "Deployment", "namespace": "default", "name": "web", "images": map[string]any{}})
+		s.Require().NoError(err)
+		s.True(toolResult.IsError)
+		s.Equal("failed to set image, provide the images to set by container name", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+}
+
+func (s *WorkloadImagesSuite) TestWorkloadsImageHistory() {
+	s.InitMcpClient()
+	s.Run("workloads_image_history(kind=Deployment) returns the images of its ReplicaSets", func() {
+		toolResult, err := s.CallTool("workloads_image_history", map[string]any{"kind": "Deployment", "namespace": "default", "name": "web"})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Equal("# Image history of Deployment default/web, 2 revision(s) (YAML format):\n"+
+			"kind: Deployment\nname: web\nnamespace: default\nrevisions:\n"+
+			"- images:\n    app: app:1.0\n  revision: 1\n  source: ReplicaSet/web-1\n"+
+			"- changeCause: set image app=app:1.1\n  current: true\n  images:\n    app: app:1.1\n  revision: 3\n  source: ReplicaSet/web-3\n",
+			toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("workloads_image_history(kind=StatefulSet) returns the images of its ControllerRevisions", func() {
+		toolResult, err := s.CallTool("workloads_image_history", map[string]any{"kind": "StatefulSet", "namespace": "default", "name": "db"})
+		s.Require().NoError(err)
+		s.Require().Falsef(toolResult.IsError, "call tool failed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text,
+			"- current: true\n  images:\n    db: postgres:16.2\n  revision: 1\n  source: ControllerRevision/db-5d8c7\n")
+	})
+}
+
+func TestWorkloadImages(t *testing.T) {
+	suite.Run(t, new(WorkloadImagesSuite))
+}
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+type WorkloadsSuite struct {
+	BaseMcpSuite
+}
+
+func (s *WorkloadsSuite) TestWorkloadReadiness() {
+	client := kubernetes.NewForConfigOrDie(envTestRestConfig)
+	labels := map[string]string{"app": "readiness"}
+	_, err := client.AppsV1().Deployments("default").Create(s.T().Context(), &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "readiness"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(2)),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "nginx"}}},
+			},
+		},
+	}, metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = client.AppsV1().Deployments("default").Delete(s.T().Context(), "readiness", metav1.DeleteOptions{})
+	})
+	s.InitMcpClient()
+	var snapshot string
+	s.Run("workload_readiness(kind=Deployment, name=readiness)", func() {
+		toolResult, err := s.CallTool("workload_readiness", map[string]interface{}{"kind": "Deployment", "namespace": "default", "name": "readiness"})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		snapshot = toolResult.Content[0].(mcp.TextContent).Text
+		var readiness map[string]any
+		s.Run("returns the snapshot", func() {
+			s.Require().NoError(yaml.Unmarshal([]byte(snapshot), &readiness))
+			s.Equal("readiness", readiness["name"])
+			s.EqualValues(2, readiness["desiredReplicas"])
+			s.Equal([]any{"app=nginx"}, readiness["images"])
+		})
+	})
+	s.Run("workload_readiness(kind=Deployment, name=readiness, baseline=snapshot)", func() {
+		toolResult, err := s.CallTool("workload_readiness", map[string]interface{}{"kind": "Deployment", "namespace": "default", "name": "readiness", "baseline": snapshot})
+		s.Run("no error", func() {
+			s.Nilf(err, "call tool failed %v", err)
+			s.Falsef(toolResult.IsError, "call tool failed")
+		})
+		s.Run("reports no regressions", func() {
+			s.Truef(strings.HasPrefix(toolResult.Content[0].(mcp.TextContent).Text, "# No readiness regressions for Deployment default/readiness"),
+				"unexpected result %v", toolResult.Content[0].(mcp.TextContent).Text)
+		})
+	})
+	s.Run("workload_readiness(kind=Deployment, name=readiness, baseline=invalid)", func() {
+		toolResult, _ := s.CallTool("workload_readiness", map[string]interface{}{"kind": "Deployment", "namespace": "default", "name": "readiness", "baseline": "[not a snapshot"})
+		s.Truef(toolResult.IsError, "call tool should fail")
+	})
+	s.Run("workload_readiness(kind=Deployment, name=other, baseline=snapshot)", func() {
+		toolResult, _ := s.CallTool("workload_readiness", map[string]interface{}{"kind": "Deployment", "namespace": "default", "name": "other", "baseline": snapshot})
+		s.Truef(toolResult.IsError, "call tool should fail")
+	})
+}
+
+func TestWorkloads(t *testing.T) {
+	suite.Run(t, new(WorkloadsSuite))
+}
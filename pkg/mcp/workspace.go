@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/workspace"
+)
+
+const (
+	// WorkspaceWriteToolName is the name of the tool writing a file of the workspace of the session
+	WorkspaceWriteToolName = "workspace_write"
+	// WorkspaceReadToolName is the name of the tool reading a file of the workspace of the session
+	WorkspaceReadToolName = "workspace_read"
+	// WorkspaceListToolName is the name of the tool listing the files of the workspace of the session
+	WorkspaceListToolName = "workspace_list"
+)
+
+// workspaceTools returns the tools managing the files of the workspace of the session
+func (s *Server) workspaceTools() []api.ServerTool {
+	pathProperty := &jsonschema.Schema{
+		Type:        "string",
+		Description: "Relative path of the file in the workspace (for example: values/production.yaml)",
+	}
+	return []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name: WorkspaceWriteToolName,
+				Description: "Write a file (for example a Helm values file or a manifest) to the workspace of this session, replacing it if it exists. " +
+					"The file can then be passed to the other tools as workspace:<path> instead of its content " +
+					"(for example in the values_files of helm_install or as the resource of resources_create_or_update). " +
+					"The workspace is size-capped and removed when the session ends or stays idle",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"path": pathProperty,
+						"content": {
+							Type:        "string",
+							Description: "Content of the file",
+						},
+					},
+					Required: []string{"path", "content"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Workspace: Write",
+					ReadOnlyHint:    ptr.To(false),
+					DestructiveHint: ptr.To(false),
+					IdempotentHint:  ptr.To(true),
+					OpenWorldHint:   ptr.To(false),
+				},
+			},
+			ClusterAware: ptr.To(false),
+			Handler:      s.workspaceWriteHandler,
+		},
+		{
+			Tool: api.Tool{
+				Name:        WorkspaceReadToolName,
+				Description: "Read a file of the workspace of this session",
+				InputSchema: &jsonschema.Schema{
+					Type:       "object",
+					Properties: map[string]*jsonschema.Schema{"path": pathProperty},
+					Required:   []string{"path"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Workspace: Read",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					IdempotentHint:  ptr.To(true),
+					OpenWorldHint:   ptr.To(false),
+				},
+			},
+			ClusterAware: ptr.To(false),
+			Handler:      s.workspaceReadHandler,
+		},
+		{
+			Tool: api.Tool{
+				Name:        WorkspaceListToolName,
+				Description: "List the files of the workspace of this session with their size, and the used and maximum size of the workspace",
+				InputSchema: &jsonschema.Schema{
+					Type:       "object",
+					Properties: map[string]*jsonschema.Schema{},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Workspace: List",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					IdempotentHint:  ptr.To(true),
+					OpenWorldHint:   ptr.To(false),
+				},
+			},
+			ClusterAware: ptr.To(false),
+			Handler:      s.workspaceListHandler,
+		},
+	}
+}
+
+func (s *Server) workspaceWriteHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	path, _ := params.GetArguments()["path"].(string)
+	if path == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("missing argument path"))), nil
+	}
+	content, ok := params.GetArguments()["content"].(string)
+	if !ok {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("missing argument content"))), nil
+	}
+	file, err := s.workspace.Write(sessionIDFromContext(params.Context), path, []byte(content), s.configuration.Workspace.MaxBytes)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to write to the workspace: %w", err)), nil
+	}
+	ret, err := params.Marshal(map[string]any{
+		"path":      file.Path,
+		"size":      file.Size,
+		"reference": workspace.Prefix + file.Path,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to write to the workspace: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# The file has been written to the workspace (%s format):\n", params.FormatName()), ret, nil), nil
+}
+
+func (s *Server) workspaceReadHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	path, _ := params.GetArguments()["path"].(string)
+	if path == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("missing argument path"))), nil
+	}
+	content, err := s.workspace.Read(sessionIDFromContext(params.Context), path)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to read from the workspace: %w", err)), nil
+	}
+	return api.NewToolCallResult(string(content), nil), nil
+}
+
+func (s *Server) workspaceListHandler(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	session := sessionIDFromContext(params.Context)
+	files, err := s.workspace.List(session)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list the files of the workspace: %w", err)), nil
+	}
+	var used int64
+	for _, file := range files {
+		used += file.Size
+	}
+	ret, err := params.Marshal(map[string]any{
+		"files":     files,
+		"usedBytes": used,
+		"maxBytes":  s.configuration.Workspace.MaxBytes,
+	})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list the files of the workspace: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Files of the workspace of the session (%s format):\n", params.FormatName()), ret, nil), nil
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+type WorkspaceSuite struct {
+	BaseMcpSuite
+	mockServer *test.MockServer
+}
+
+func (s *WorkspaceSuite) SetupTest() {
+	s.BaseMcpSuite.SetupTest()
+	s.mockServer = test.NewMockServer()
+	s.Cfg.KubeConfig = s.mockServer.KubeconfigFile(s.T())
+	s.Cfg.Workspace.MaxBytes = 64
+	s.Cfg.Workspace.Dir = s.T().TempDir()
+}
+
+func (s *WorkspaceSuite) TearDownTest() {
+	s.BaseMcpSuite.TearDownTest()
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+}
+
+func (s *WorkspaceSuite) TestWriteReadList() {
+	s.InitMcpClient()
+	s.Run("workspace_write writes the file", func() {
+		toolResult, err := s.CallTool("workspace_write", map[string]any{"path": "values/app.yaml", "content": "replicas: 3\n"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.Falsef(toolResult.IsError, "call tool should succeed: %v", toolResult.Content)
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "reference: workspace:values/app.yaml")
+	})
+	s.Run("workspace_read returns the content of the file", func() {
+		toolResult, err := s.CallTool("workspace_read", map[string]any{"path": "values/app.yaml"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.False(toolResult.IsError, "call tool should succeed")
+		s.Equal("replicas: 3\n", toolResult.Content[0].(mcp.TextContent).Text)
+	})
+	s.Run("workspace_list returns the files and the usage", func() {
+		toolResult, err := s.CallTool("workspace_list", map[string]any{})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.False(toolResult.IsError, "call tool should succeed")
+		text := toolResult.Content[0].(mcp.TextContent).Text
+		s.Contains(text, "path: values/app.yaml")
+		s.Contains(text, "usedBytes: 12")
+		s.Contains(text, "maxBytes: 64")
+	})
+	s.Run("workspace_write rejects the files exceeding the size limit", func() {
+		toolResult, err := s.CallTool("workspace_write", map[string]any{"path": "big.yaml", "content": string(make([]byte, 60))})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "would exceed the workspace size limit")
+	})
+	s.Run("workspace_write rejects the paths out of the workspace", func() {
+		toolResult, err := s.CallTool("workspace_write", map[string]any{"path": "../escape.yaml", "content": "x"})
+		s.Require().NoError(err, "call tool should not return error object")
+		s.True(toolResult.IsError, "call tool should fail")
+		s.Contains(toolResult.Content[0].(mcp.TextContent).Text, "invalid path")
+	})
+}
+
+func (s *WorkspaceSuite) TestReferenceMissingFile() {
+	s.InitMcpClient()
+	toolResult, err := s.CallTool("resources_create_or_update", map[string]any{"resource": "workspace:missing.yaml"})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.True(toolResult.IsError, "call tool should fail")
+	s.Equal("failed to create or update resources: file missing.yaml not found in the workspace", toolResult.Content[0].(mcp.TextContent).Text)
+}
+
+func (s *WorkspaceSuite) TestDisabled() {
+	s.Cfg.Workspace.MaxBytes = 0
+	s.InitMcpClient()
+	tools, err := s.ListTools(s.T().Context(), mcp.ListToolsRequest{})
+	s.Require().NoError(err, "call ListTools failed")
+	for _, tool := range tools.Tools {
+		s.NotContains(tool.Name, "workspace_", "the workspace tools must not be registered if the workspace is disabled")
+	}
+	toolResult, err := s.CallTool("resources_create_or_update", map[string]any{"resource": "workspace:app.yaml"})
+	s.Require().NoError(err, "call tool should not return error object")
+	s.True(toolResult.IsError, "call tool should fail")
+	s.Equal("failed to create or update resources: failed to read workspace:app.yaml, the workspace is not enabled", toolResult.Content[0].(mcp.TextContent).Text)
+}
+
+func TestWorkspace(t *testing.T) {
+	suite.Run(t, new(WorkspaceSuite))
+}
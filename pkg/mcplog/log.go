@@ -108,7 +108,8 @@ var (
 	}
 )
 
-func sanitizeMessage(msg string) string {
+// Sanitize returns the message with the secrets matching the built-in patterns (passwords, tokens, keys...) redacted
+func Sanitize(msg string) string {
 	// JSON/YAML field patterns (indices 0-6) - preserve field name
 	for i := 0; i < 7 && i < len(sensitivePatterns); i++ {
 		msg = sensitivePatterns[i].ReplaceAllString(msg, `$1"[REDACTED]"`)
@@ -155,7 +156,7 @@ func SendMCPLog(ctx context.Context, level Level, message string) {
 		return
 	}
 
-	message = sanitizeMessage(message)
+	message = Sanitize(message)
 
 	if err := session.Log(ctx, &mcp.LoggingMessageParams{
 		Level:  mcp.LoggingLevel(level.String()),
@@ -0,0 +1,31 @@
+package mcplog
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MCPProgressTokenContextKey is the context key for storing the progress token of the MCP request
+const MCPProgressTokenContextKey = ContextKey("mcp_progress_token")
+
+// SendMCPProgress sends a progress notification to the MCP client if it requested them (provided a progress token).
+// total is the number of steps of the operation, 0 if unknown. Message is automatically sanitized.
+func SendMCPProgress(ctx context.Context, progress, total float64, message string) {
+	mcpLogger.V(2).Info(message, "progress", progress, "total", total)
+
+	token := ctx.Value(MCPProgressTokenContextKey)
+	session, ok := ctx.Value(MCPSessionContextKey).(*mcp.ServerSession)
+	if token == nil || !ok || session == nil {
+		return
+	}
+
+	if err := session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+		ProgressToken: token,
+		Progress:      progress,
+		Total:         total,
+		Message:       Sanitize(message),
+	}); err != nil {
+		mcpLogger.V(3).Info("failed to send progress to MCP client", "error", err)
+	}
+}
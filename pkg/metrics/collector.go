@@ -11,6 +11,10 @@ type Collector interface {
 	// RecordToolCall records metrics for an MCP tool call execution.
 	RecordToolCall(ctx context.Context, name string, duration time.Duration, err error)
 
+	// RecordToolOutput records the size of the result of an MCP tool call, for the namespace targeted by the call
+	// (empty for the cluster-scoped and all-namespaces calls).
+	RecordToolOutput(ctx context.Context, name, namespace string, bytes int64)
+
 	// RecordHTTPRequest records metrics for an HTTP request.
 	RecordHTTPRequest(ctx context.Context, method, path string, statusCode int, duration time.Duration)
 }
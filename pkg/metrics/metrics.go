@@ -60,6 +60,14 @@ func (m *Metrics) RecordToolCall(ctx context.Context, name string, duration time
 	}
 }
 
+// RecordToolOutput implements the Collector interface.
+// It fans out the call to all registered collectors.
+func (m *Metrics) RecordToolOutput(ctx context.Context, name, namespace string, bytes int64) {
+	for _, c := range m.collectors {
+		c.RecordToolOutput(ctx, name, namespace, bytes)
+	}
+}
+
 // RecordHTTPRequest implements the Collector interface.
 // It fans out the call to all registered collectors.
 func (m *Metrics) RecordHTTPRequest(ctx context.Context, method, path string, statusCode int, duration time.Duration) {
@@ -7,6 +7,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/config"
@@ -32,6 +33,10 @@ type Statistics struct {
 	ToolCallsByName  map[string]int64 `json:"tool_calls_by_name"`
 	ToolErrorsByName map[string]int64 `json:"tool_errors_by_name"`
 
+	// Tool output metrics, the size of the tool results (bytes) by namespace of the tool calls
+	ToolOutputBytes            int64            `json:"tool_output_bytes"`
+	ToolOutputBytesByNamespace map[string]int64 `json:"tool_output_bytes_by_namespace"`
+
 	// HTTP request metrics
 	TotalHTTPRequests    int64            `json:"total_http_requests"`
 	HTTPRequestsByPath   map[string]int64 `json:"http_requests_by_path"`
@@ -51,6 +56,7 @@ type OtelStatsCollector struct {
 	toolCallCounter       metric.Int64Counter
 	toolCallErrorCounter  metric.Int64Counter
 	toolDurationHistogram metric.Float64Histogram
+	toolOutputCounter     metric.Int64Counter
 	httpRequestCounter    metric.Int64Counter
 	serverInfoGauge       metric.Int64Gauge
 
@@ -65,8 +71,17 @@ type OtelStatsCollector struct {
 
 	// Server start time for uptime calculation
 	startTime time.Time
+
+	// outputNamespaces are the namespaces recorded in the attributes of the tool output metric, bounded to
+	// maxOutputNamespaces to keep the cardinality of the metric under control
+	outputNamespacesMu sync.Mutex
+	outputNamespaces   map[string]struct{}
 }
 
+// maxOutputNamespaces is the maximum number of namespaces the tool output is accounted to, the output of the tool
+// calls in any other namespace is only accounted in the total
+const maxOutputNamespaces = 100
+
 // CollectorConfig contains configuration for the OtelStatsCollector.
 type CollectorConfig struct {
 	MeterName      string
@@ -224,6 +239,15 @@ func NewOtelStatsCollectorWithConfig(cfg CollectorConfig) (*OtelStatsCollector,
 		return nil, fmt.Errorf("failed to create tool duration histogram: %w", err)
 	}
 
+	toolOutputCounter, err := meter.Int64Counter(
+		"k8s_mcp.tool.output",
+		metric.WithDescription("Size of the MCP tool call results"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tool output counter: %w", err)
+	}
+
 	httpRequestCounter, err := meter.Int64Counter(
 		"k8s_mcp.http.requests",
 		metric.WithDescription("Total number of HTTP requests to the MCP server"),
@@ -244,12 +268,14 @@ func NewOtelStatsCollectorWithConfig(cfg CollectorConfig) (*OtelStatsCollector,
 		toolCallCounter:       toolCallCounter,
 		toolCallErrorCounter:  toolCallErrorCounter,
 		toolDurationHistogram: toolDurationHistogram,
+		toolOutputCounter:     toolOutputCounter,
 		httpRequestCounter:    httpRequestCounter,
 		serverInfoGauge:       serverInfoGauge,
 		provider:              provider,
 		reader:                reader,
 		prometheusHandler:     prometheusHandler,
 		startTime:             time.Now(),
+		outputNamespaces:      make(map[string]struct{}),
 	}
 
 	// Record server info gauge with version attributes
@@ -290,6 +316,30 @@ func (c *OtelStatsCollector) RecordToolCall(ctx context.Context, name string, du
 	}
 }
 
+// RecordToolOutput implements the Collector interface.
+func (c *OtelStatsCollector) RecordToolOutput(ctx context.Context, name, namespace string, bytes int64) {
+	attrs := []attribute.KeyValue{attribute.String("tool.name", name)}
+	if namespace != "" && c.isOutputNamespace(namespace) {
+		attrs = append(attrs, attribute.String("k8s.namespace.name", namespace))
+	}
+	c.toolOutputCounter.Add(ctx, bytes, metric.WithAttributes(attrs...))
+}
+
+// isOutputNamespace returns true if the namespace is (or can still be) one of the namespaces recorded in the attributes
+// of the tool output metric
+func (c *OtelStatsCollector) isOutputNamespace(namespace string) bool {
+	c.outputNamespacesMu.Lock()
+	defer c.outputNamespacesMu.Unlock()
+	if _, ok := c.outputNamespaces[namespace]; ok {
+		return true
+	}
+	if len(c.outputNamespaces) >= maxOutputNamespaces {
+		return false
+	}
+	c.outputNamespaces[namespace] = struct{}{}
+	return true
+}
+
 // RecordHTTPRequest implements the Collector interface.
 func (c *OtelStatsCollector) RecordHTTPRequest(ctx context.Context, method, path string, statusCode int, duration time.Duration) {
 	// Determine status class (2xx, 3xx, 4xx, 5xx)
@@ -322,24 +372,26 @@ func (c *OtelStatsCollector) GetStats() *Statistics {
 	if err := c.reader.Collect(context.Background(), &rm); err != nil {
 		klog.V(1).Infof("Failed to collect metrics for stats endpoint: %v", err)
 		return &Statistics{
-			ToolCallsByName:      make(map[string]int64),
-			ToolErrorsByName:     make(map[string]int64),
-			HTTPRequestsByPath:   make(map[string]int64),
-			HTTPRequestsByStatus: make(map[string]int64),
-			HTTPRequestsByMethod: make(map[string]int64),
-			UptimeSeconds:        int64(time.Since(c.startTime).Seconds()),
-			StartTime:            c.startTime.Unix(),
+			ToolCallsByName:            make(map[string]int64),
+			ToolErrorsByName:           make(map[string]int64),
+			ToolOutputBytesByNamespace: make(map[string]int64),
+			HTTPRequestsByPath:         make(map[string]int64),
+			HTTPRequestsByStatus:       make(map[string]int64),
+			HTTPRequestsByMethod:       make(map[string]int64),
+			UptimeSeconds:              int64(time.Since(c.startTime).Seconds()),
+			StartTime:                  c.startTime.Unix(),
 		}
 	}
 
 	stats := &Statistics{
-		ToolCallsByName:      make(map[string]int64),
-		ToolErrorsByName:     make(map[string]int64),
-		HTTPRequestsByPath:   make(map[string]int64),
-		HTTPRequestsByStatus: make(map[string]int64),
-		HTTPRequestsByMethod: make(map[string]int64),
-		UptimeSeconds:        int64(time.Since(c.startTime).Seconds()),
-		StartTime:            c.startTime.Unix(),
+		ToolCallsByName:            make(map[string]int64),
+		ToolErrorsByName:           make(map[string]int64),
+		ToolOutputBytesByNamespace: make(map[string]int64),
+		HTTPRequestsByPath:         make(map[string]int64),
+		HTTPRequestsByStatus:       make(map[string]int64),
+		HTTPRequestsByMethod:       make(map[string]int64),
+		UptimeSeconds:              int64(time.Since(c.startTime).Seconds()),
+		StartTime:                  c.startTime.Unix(),
 	}
 
 	// Process collected metrics
@@ -383,6 +435,16 @@ func (c *OtelStatsCollector) processMetric(m metricdata.Metrics, stats *Statisti
 			}
 		}
 
+	case "k8s_mcp.tool.output":
+		if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+			for _, dp := range sum.DataPoints {
+				stats.ToolOutputBytes += dp.Value
+				if namespace := c.getAttributeValue(dp.Attributes, "k8s.namespace.name"); namespace != "" {
+					stats.ToolOutputBytesByNamespace[namespace] += dp.Value
+				}
+			}
+		}
+
 	case "k8s_mcp.http.requests":
 		if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
 			for _, dp := range sum.DataPoints {
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"runtime"
@@ -58,6 +59,36 @@ func (s *OtelStatsCollectorSuite) TestRecordToolCall() {
 	})
 }
 
+func (s *OtelStatsCollectorSuite) TestRecordToolOutput() {
+	s.Run("records output bytes by namespace", func() {
+		ctx := context.Background()
+		s.collector.RecordToolOutput(ctx, "pods_list_in_namespace", "team-a", 1024)
+		s.collector.RecordToolOutput(ctx, "pods_log", "team-a", 512)
+		s.collector.RecordToolOutput(ctx, "pods_list_in_namespace", "team-b", 256)
+		s.collector.RecordToolOutput(ctx, "namespaces_list", "", 128)
+
+		stats := s.collector.GetStats()
+		s.Equal(int64(1920), stats.ToolOutputBytes, "Should account the output of all the tool calls")
+		s.Equal(map[string]int64{"team-a": 1536, "team-b": 256}, stats.ToolOutputBytesByNamespace)
+	})
+
+	s.Run("bounds the number of namespaces", func() {
+		ctx := context.Background()
+		collector, err := NewOtelStatsCollector("test-meter-output")
+		s.Require().NoError(err)
+		for i := range maxOutputNamespaces + 10 {
+			collector.RecordToolOutput(ctx, "pods_list_in_namespace", fmt.Sprintf("ns-%d", i), 10)
+		}
+		collector.RecordToolOutput(ctx, "pods_list_in_namespace", "ns-0", 10)
+
+		stats := collector.GetStats()
+		s.Equal(int64((maxOutputNamespaces+11)*10), stats.ToolOutputBytes, "Should account the output of all the tool calls")
+		s.Len(stats.ToolOutputBytesByNamespace, maxOutputNamespaces)
+		s.Equal(int64(20), stats.ToolOutputBytesByNamespace["ns-0"], "Should keep accounting the output of the recorded namespaces")
+		s.NotContains(stats.ToolOutputBytesByNamespace, fmt.Sprintf("ns-%d", maxOutputNamespaces))
+	})
+}
+
 func (s *OtelStatsCollectorSuite) TestRecordHTTPRequest() {
 	s.Run("records HTTP requests by status class", func() {
 		ctx := context.Background()
@@ -114,6 +145,7 @@ func (s *OtelStatsCollectorSuite) TestGetStats() {
 		stats := s.collector.GetStats()
 		s.NotNil(stats.ToolCallsByName, "ToolCallsByName should be initialized")
 		s.NotNil(stats.ToolErrorsByName, "ToolErrorsByName should be initialized")
+		s.NotNil(stats.ToolOutputBytesByNamespace, "ToolOutputBytesByNamespace should be initialized")
 		s.NotNil(stats.HTTPRequestsByPath, "HTTPRequestsByPath should be initialized")
 		s.NotNil(stats.HTTPRequestsByStatus, "HTTPRequestsByStatus should be initialized")
 		s.NotNil(stats.HTTPRequestsByMethod, "HTTPRequestsByMethod should be initialized")
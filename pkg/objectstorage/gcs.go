@@ -0,0 +1,157 @@
+package objectstorage
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	gcsReadOnlyScope = "https://www.googleapis.com/auth/devstorage.read_only"
+	googleTokenURL   = "https://oauth2.googleapis.com/token"
+)
+
+// GCSConfig holds the settings of the Google Cloud Storage buckets
+type GCSConfig struct {
+	// CredentialsFile is the service account key (Optional, GOOGLE_APPLICATION_CREDENTIALS or the metadata server,
+	// e.g. GKE Workload Identity, if not provided)
+	CredentialsFile string `toml:"credentials_file,omitempty"`
+	// Endpoint of the JSON API (Optional, https://storage.googleapis.com if not provided)
+	Endpoint string `toml:"endpoint,omitempty"`
+}
+
+func (c *GCSConfig) Validate() error {
+	if c == nil {
+		return errors.New("gcs config is nil")
+	}
+	if c.Endpoint != "" {
+		if u, err := url.Parse(c.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("gcs endpoint must be a valid http(s) URL")
+		}
+	}
+	if c.CredentialsFile != "" {
+		if _, err := os.Stat(c.CredentialsFile); err != nil {
+			return fmt.Errorf("gcs credentials_file must be a valid file path: %w", err)
+		}
+	}
+	return nil
+}
+
+// ResolvePaths resolves the relative credentials_file path against the configuration directory
+func (c *GCSConfig) ResolvePaths(configDir string) {
+	if configDir != "" && c.CredentialsFile != "" && !filepath.IsAbs(c.CredentialsFile) {
+		c.CredentialsFile = filepath.Join(configDir, c.CredentialsFile)
+	}
+}
+
+func (c *Client) getGCS(ctx context.Context, bucket, object string) ([]byte, error) {
+	token, err := c.gcsToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the Google Cloud credentials: %w", err)
+	}
+	endpoint := strings.TrimSuffix(cmp.Or(c.gcs.Endpoint, "https://storage.googleapis.com"), "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		endpoint+"/storage/v1/b/"+url.PathEscape(bucket)+"/o/"+url.PathEscape(object)+"?alt=media", nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.do(req, "gs://"+bucket+"/"+object)
+}
+
+// gcsToken returns the access token of the configured service account key, of the application default credentials
+// file, or of the service account of the metadata server. It returns an empty token, to send anonymous requests,
+// if none is available
+func (c *Client) gcsToken(ctx context.Context) (string, error) {
+	if credentialsFile := cmp.Or(c.gcs.CredentialsFile, os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")); credentialsFile != "" {
+		return c.credentialsFileToken(ctx, credentialsFile)
+	}
+	return c.metadataServerToken(ctx), nil
+}
+
+// credentialsFileToken exchanges the service account key, or the refresh token of the user credentials
+// (gcloud auth application-default login), for an access token
+func (c *Client) credentialsFileToken(ctx context.Context, credentialsFile string) (string, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return "", err
+	}
+	var credentials struct {
+		Type         string `json:"type"`
+		ClientEmail  string `json:"client_email"`
+		PrivateKeyID string `json:"private_key_id"`
+		PrivateKey   string `json:"private_key"`
+		TokenURI     string `json:"token_uri"`
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err = json.Unmarshal(data, &credentials); err != nil {
+		return "", fmt.Errorf("invalid credentials file %s: %w", credentialsFile, err)
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, c.httpClient)
+	var tokenSource oauth2.TokenSource
+	switch credentials.Type {
+	case "service_account":
+		tokenSource = (&jwt.Config{
+			Email:        credentials.ClientEmail,
+			PrivateKey:   []byte(credentials.PrivateKey),
+			PrivateKeyID: credentials.PrivateKeyID,
+			Scopes:       []string{gcsReadOnlyScope},
+			TokenURL:     cmp.Or(credentials.TokenURI, googleTokenURL),
+		}).TokenSource(ctx)
+	case "authorized_user":
+		tokenSource = (&oauth2.Config{
+			ClientID:     credentials.ClientID,
+			ClientSecret: credentials.ClientSecret,
+			Endpoint:     oauth2.Endpoint{TokenURL: cmp.Or(credentials.TokenURI, googleTokenURL)},
+			Scopes:       []string{gcsReadOnlyScope},
+		}).TokenSource(ctx, &oauth2.Token{RefreshToken: credentials.RefreshToken})
+	default:
+		return "", fmt.Errorf("unsupported credentials type %q in %s, expected service_account or authorized_user", credentials.Type, credentialsFile)
+	}
+	token, err := tokenSource.Token()
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// metadataServerToken returns the access token of the service account of the metadata server, empty if the metadata
+// server is not reachable (outside of Google Cloud)
+func (c *Client) metadataServerToken(ctx context.Context) string {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+	host := cmp.Or(os.Getenv("GCE_METADATA_HOST"), "metadata.google.internal")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"http://"+host+"/computeMetadata/v1/instance/service-accounts/default/token?scopes="+url.QueryEscape(gcsReadOnlyScope), nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token) != nil {
+		return ""
+	}
+	return token.AccessToken
+}
@@ -0,0 +1,130 @@
+package objectstorage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// requestTimeout is the maximum duration of a download, including the credential resolution
+	requestTimeout = 2 * time.Minute
+	// maxObjectSize is the maximum size of the downloaded objects (chart archives and repository indexes)
+	maxObjectSize = 100 << 20
+	// metadataTimeout bounds the probes of the cloud metadata services, which don't answer outside the cloud
+	metadataTimeout = 2 * time.Second
+)
+
+// Client downloads objects from Amazon S3 (s3://bucket/key) and Google Cloud Storage (gs://bucket/object).
+// The credentials are the configured ones, or the ambient credentials of the environment (variables, workload identity,
+// metadata services), or none for the public buckets.
+type Client struct {
+	s3         *S3Config
+	gcs        *GCSConfig
+	httpClient *http.Client
+}
+
+// NewClient creates a Client, the configurations are optional
+func NewClient(s3 *S3Config, gcs *GCSConfig) *Client {
+	if s3 == nil {
+		s3 = &S3Config{}
+	}
+	if gcs == nil {
+		gcs = &GCSConfig{}
+	}
+	return &Client{s3: s3, gcs: gcs, httpClient: http.DefaultClient}
+}
+
+// IsObjectURL returns true if the reference is an s3:// or gs:// URL
+func IsObjectURL(reference string) bool {
+	return strings.HasPrefix(reference, "s3://") || strings.HasPrefix(reference, "gs://")
+}
+
+// Get downloads the object of the s3://, gs://, or http(s):// URL
+func (c *Client) Get(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	bucket, key := u.Host, strings.TrimPrefix(u.Path, "/")
+	switch u.Scheme {
+	case "s3":
+		if bucket == "" || key == "" {
+			return nil, fmt.Errorf("invalid object URL %s, expected s3://bucket/key", rawURL)
+		}
+		return c.getS3(ctx, bucket, key)
+	case "gs":
+		if bucket == "" || key == "" {
+			return nil, fmt.Errorf("invalid object URL %s, expected gs://bucket/object", rawURL)
+		}
+		return c.getGCS(ctx, bucket, key)
+	case "http", "https":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		return c.do(req, rawURL)
+	}
+	return nil, fmt.Errorf("unsupported object URL %s", rawURL)
+}
+
+// do sends the request and returns the body, failing on the non 2xx responses
+func (c *Client) do(req *http.Request, object string) ([]byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxObjectSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, &Error{Object: object, StatusCode: resp.StatusCode, Message: errorMessage(body), bucketRegion: resp.Header.Get("X-Amz-Bucket-Region")}
+	}
+	if len(body) > maxObjectSize {
+		return nil, fmt.Errorf("object %s exceeds the maximum size of %d bytes", object, maxObjectSize)
+	}
+	return body, nil
+}
+
+// Error is a failed download
+type Error struct {
+	Object     string
+	StatusCode int
+	Message    string
+	// bucketRegion is the region of the S3 bucket, when requested in the wrong region
+	bucketRegion string
+}
+
+func (e *Error) Error() string {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return fmt.Sprintf("object %s not found", e.Object)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Sprintf("access to object %s denied (%d), check the credentials: %s", e.Object, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("failed to download object %s (%d): %s", e.Object, e.StatusCode, e.Message)
+}
+
+// errorMessage extracts the message of the S3 (XML) and GCS (JSON) error documents, or returns the body as is
+func errorMessage(body []byte) string {
+	message := strings.TrimSpace(string(body))
+	for _, delimiters := range [][2]string{{"<Message>", "</Message>"}, {`"message": "`, `"`}, {`"message":"`, `"`}} {
+		if _, after, found := strings.Cut(message, delimiters[0]); found {
+			if value, _, found := strings.Cut(after, delimiters[1]); found {
+				return value
+			}
+		}
+	}
+	if len(message) > 256 {
+		return message[:256]
+	}
+	return message
+}
@@ -0,0 +1,156 @@
+package objectstorage
+
+import (
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// emptyPayloadHash is the SHA-256 of the empty body of the GET requests
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Config holds the settings of the Amazon S3 (or S3 compatible) buckets
+type S3Config struct {
+	// Region of the buckets (Optional, AWS_REGION, AWS_DEFAULT_REGION, or us-east-1 if not provided)
+	Region string `toml:"region,omitempty"`
+	// Endpoint of an S3 compatible storage (e.g. https://minio.example.com), the buckets are addressed in the path
+	Endpoint string `toml:"endpoint,omitempty"`
+	// AccessKeyID and SecretAccessKeyFile are the static credentials (Optional, the ambient credentials if not provided)
+	AccessKeyID         string `toml:"access_key_id,omitempty"`
+	SecretAccessKeyFile string `toml:"secret_access_key_file,omitempty"`
+}
+
+func (c *S3Config) Validate() error {
+	if c == nil {
+		return errors.New("s3 config is nil")
+	}
+	if c.Endpoint != "" {
+		if u, err := url.Parse(c.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("s3 endpoint must be a valid http(s) URL")
+		}
+	}
+	if (c.AccessKeyID == "") != (c.SecretAccessKeyFile == "") {
+		return errors.New("s3 access_key_id and secret_access_key_file must be provided together")
+	}
+	if c.SecretAccessKeyFile != "" {
+		if _, err := os.Stat(c.SecretAccessKeyFile); err != nil {
+			return fmt.Errorf("s3 secret_access_key_file must be a valid file path: %w", err)
+		}
+	}
+	return nil
+}
+
+// ResolvePaths resolves the relative secret_access_key_file path against the configuration directory
+func (c *S3Config) ResolvePaths(configDir string) {
+	if configDir != "" && c.SecretAccessKeyFile != "" && !filepath.IsAbs(c.SecretAccessKeyFile) {
+		c.SecretAccessKeyFile = filepath.Join(configDir, c.SecretAccessKeyFile)
+	}
+}
+
+func (c *S3Config) region() string {
+	return cmp.Or(c.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), "us-east-1")
+}
+
+func (c *Client) getS3(ctx context.Context, bucket, key string) ([]byte, error) {
+	credentials, err := c.awsCredentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the AWS credentials: %w", err)
+	}
+	object := "s3://" + bucket + "/" + key
+	region := c.s3.region()
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.s3ObjectURL(region, bucket, key), nil)
+		if err != nil {
+			return nil, err
+		}
+		if credentials != nil {
+			signV4(req, credentials, region, "s3", time.Now())
+		}
+		body, err := c.do(req, object)
+		// S3 answers with the region of the bucket when requested in another one
+		var downloadErr *Error
+		if errors.As(err, &downloadErr) && attempt == 0 && downloadErr.bucketRegion != "" && downloadErr.bucketRegion != region {
+			region = downloadErr.bucketRegion
+			continue
+		}
+		return body, err
+	}
+}
+
+// s3ObjectURL returns the virtual-hosted URL of the object, or the path-style one for the custom endpoints and the
+// bucket names with dots (which don't match the wildcard certificate of S3)
+func (c *Client) s3ObjectURL(region, bucket, key string) string {
+	if c.s3.Endpoint != "" {
+		return strings.TrimSuffix(c.s3.Endpoint, "/") + "/" + bucket + "/" + escapePath(key)
+	}
+	if strings.Contains(bucket, ".") {
+		return "https://s3." + region + ".amazonaws.com/" + bucket + "/" + escapePath(key)
+	}
+	return "https://" + bucket + ".s3." + region + ".amazonaws.com/" + escapePath(key)
+}
+
+// signV4 signs the request with the AWS Signature Version 4, covering the host and the headers already set
+func signV4(req *http.Request, credentials *awsCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	canonicalHeaders := strings.Builder{}
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method, req.URL.EscapedPath(), req.URL.Query().Encode(), canonicalHeaders.String(), signedHeaders, emptyPayloadHash,
+	}, "\n")
+	scope := amzDate[:8] + "/" + region + "/" + service + "/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+	key := []byte("AWS4" + credentials.SecretAccessKey)
+	for _, part := range []string{amzDate[:8], region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		credentials.AccessKeyID, scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign))))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath escapes every byte of the key but the unreserved characters and the slashes, as expected by the signature
+func escapePath(key string) string {
+	escaped := strings.Builder{}
+	for _, b := range []byte(key) {
+		if ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9') || strings.IndexByte("-_.~/", b) >= 0 {
+			escaped.WriteByte(b)
+		} else {
+			_, _ = fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
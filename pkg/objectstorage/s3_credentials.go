@@ -0,0 +1,161 @@
+package objectstorage
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// awsCredentials are the (temporary if SessionToken is set) credentials signing the S3 requests
+type awsCredentials struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	SessionToken    string `json:"Token"`
+}
+
+// awsCredentials resolves the credentials in the order of the AWS SDKs: the configured static credentials, the
+// environment variables, the web identity of the Pod (IRSA), the container credentials endpoint (EKS Pod Identity, ECS),
+// and the instance profile (IMDSv2). It returns nil, to send anonymous requests, if none is available
+func (c *Client) awsCredentials(ctx context.Context) (*awsCredentials, error) {
+	if c.s3.AccessKeyID != "" {
+		secret, err := os.ReadFile(c.s3.SecretAccessKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return &awsCredentials{AccessKeyID: c.s3.AccessKeyID, SecretAccessKey: strings.TrimSpace(string(secret))}, nil
+	}
+	if accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID"); accessKeyID != "" {
+		return &awsCredentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+	if tokenFile, roleArn := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"), os.Getenv("AWS_ROLE_ARN"); tokenFile != "" && roleArn != "" {
+		return c.assumeRoleWithWebIdentity(ctx, tokenFile, roleArn)
+	}
+	if endpoint := containerCredentialsEndpoint(); endpoint != "" {
+		return c.containerCredentials(ctx, endpoint)
+	}
+	if os.Getenv("AWS_EC2_METADATA_DISABLED") != "true" {
+		return c.instanceProfileCredentials(ctx), nil
+	}
+	return nil, nil
+}
+
+func (c *Client) assumeRoleWithWebIdentity(ctx context.Context, tokenFile, roleArn string) (*awsCredentials, error) {
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := cmp.Or(os.Getenv("AWS_ENDPOINT_URL_STS"), "https://sts."+c.s3.region()+".amazonaws.com")
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {roleArn},
+		"RoleSessionName":  {cmp.Or(os.Getenv("AWS_ROLE_SESSION_NAME"), fmt.Sprintf("kubernetes-mcp-server-%d", time.Now().Unix()))},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	body, err := c.do(req, "AssumeRoleWithWebIdentity "+roleArn)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Credentials struct {
+			AccessKeyID     string `xml:"AccessKeyId"`
+			SecretAccessKey string `xml:"SecretAccessKey"`
+			SessionToken    string `xml:"SessionToken"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err = xml.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	return &awsCredentials{
+		AccessKeyID:     response.Credentials.AccessKeyID,
+		SecretAccessKey: response.Credentials.SecretAccessKey,
+		SessionToken:    response.Credentials.SessionToken,
+	}, nil
+}
+
+func containerCredentialsEndpoint() string {
+	if relativeURI := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); relativeURI != "" {
+		return "http://169.254.170.2" + relativeURI
+	}
+	return os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+}
+
+func (c *Client) containerCredentials(ctx context.Context, endpoint string) (*awsCredentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	authorization := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	if tokenFile := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); tokenFile != "" {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		authorization = strings.TrimSpace(string(token))
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	body, err := c.do(req, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	credentials := &awsCredentials{}
+	return credentials, json.Unmarshal(body, credentials)
+}
+
+// instanceProfileCredentials returns the credentials of the EC2 instance profile, nil if the instance metadata
+// service is not reachable (outside of EC2) or has no instance profile
+func (c *Client) instanceProfileCredentials(ctx context.Context) *awsCredentials {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+	endpoint := strings.TrimSuffix(cmp.Or(os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT"), "http://169.254.169.254"), "/")
+	metadata := func(method, path string, header http.Header) string {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint+path, nil)
+		if err != nil {
+			return ""
+		}
+		req.Header = header
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return ""
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if resp.StatusCode != http.StatusOK {
+			return ""
+		}
+		return strings.TrimSpace(string(body))
+	}
+	token := metadata(http.MethodPut, "/latest/api/token", http.Header{"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": {"300"}})
+	if token == "" {
+		return nil
+	}
+	header := http.Header{"X-Aws-Ec2-Metadata-Token": {token}}
+	role, _, _ := strings.Cut(metadata(http.MethodGet, "/latest/meta-data/iam/security-credentials/", header), "\n")
+	if role == "" {
+		return nil
+	}
+	credentials := &awsCredentials{}
+	if err := json.Unmarshal([]byte(metadata(http.MethodGet, "/latest/meta-data/iam/security-credentials/"+role, header)), credentials); err != nil || credentials.AccessKeyID == "" {
+		return nil
+	}
+	return credentials
+}
@@ -0,0 +1,16 @@
+package output
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// HumanAge returns the time elapsed since t in the compact form used by kubectl (e.g. 45s, 5m, 2d).
+// An empty string is returned for zero times.
+func HumanAge(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return duration.HumanDuration(time.Since(t))
+}
@@ -0,0 +1,29 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHumanAge(t *testing.T) {
+	for _, tc := range []struct {
+		ago      time.Duration
+		expected string
+	}{
+		{30 * time.Second, "30s"},
+		{5 * time.Minute, "5m"},
+		{90 * time.Minute, "90m"},
+		{5 * time.Hour, "5h"},
+		{50 * time.Hour, "2d2h"},
+		{20 * 24 * time.Hour, "20d"},
+	} {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, HumanAge(time.Now().Add(-tc.ago)))
+		})
+	}
+	t.Run("zero time", func(t *testing.T) {
+		assert.Empty(t, HumanAge(time.Time{}))
+	})
+}
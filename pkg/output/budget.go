@@ -0,0 +1,300 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	yml "sigs.k8s.io/yaml"
+)
+
+// budgetValueLength is the length the long string values (e.g. embedded configurations, certificates) are truncated to
+// when a result exceeds its budget
+const budgetValueLength = 256
+
+// budgetReduction reports what was omitted from a result to fit its budget
+type budgetReduction struct {
+	verboseFields   int
+	truncatedValues int
+	listName        string
+	keptItems       int
+	totalItems      int
+	documents       bool
+	keptLines       int
+	totalLines      int
+}
+
+func (r *budgetReduction) String() string {
+	omitted := make([]string, 0)
+	if r.verboseFields > 0 {
+		omitted = append(omitted, fmt.Sprintf("%d managed fields and last-applied-configuration annotations removed", r.verboseFields))
+	}
+	if r.truncatedValues > 0 {
+		omitted = append(omitted, fmt.Sprintf("%d values longer than %d characters truncated", r.truncatedValues, budgetValueLength))
+	}
+	if r.totalItems > 0 && r.documents {
+		omitted = append(omitted, fmt.Sprintf("first %d of %d YAML documents kept", r.keptItems, r.totalItems))
+	} else if r.totalItems > 0 {
+		omitted = append(omitted, fmt.Sprintf("first %d of %d items%s kept", r.keptItems, r.totalItems, r.listName))
+	}
+	if r.totalLines > 0 {
+		omitted = append(omitted, fmt.Sprintf("first %d of %d lines kept", r.keptLines, r.totalLines))
+	}
+	return strings.Join(omitted, ", ")
+}
+
+// FitBudget degrades the tool result toward the maximum size (disabled if not positive): the verbose metadata of the
+// structured (YAML or JSON) results is removed first, then their long values are truncated, and then the items of their
+// largest list (or the YAML documents of a multi-document result) are left out (at least one is kept). Text results (and the structured results without lists that still
+// exceed the budget) keep their first lines. The structured results are re-encoded in their format (JSON or YAML).
+// The returned comment line states what was omitted from reduced results, it's kept apart from the content so that the
+// JSON results remain valid JSON.
+func FitBudget(content string, limit int) (string, string) {
+	if limit <= 0 || len(content) <= limit {
+		return "", content
+	}
+	header, body := splitCommentHeader(content)
+	reduction := &budgetReduction{}
+	if documents, ok := decodeDocuments(body); ok {
+		reduction.documents = true
+		body = fitStructured(documents, limit-len(header), encodeDocuments, reduction)
+	} else if v, ok := decodeStructured(body); ok {
+		encode := encodeYaml
+		if isJson(body) {
+			encode = encodeJson
+		}
+		body = fitStructured(v, limit-len(header), encode, reduction)
+	}
+	// the items of the lists are kept whole, like the lists capped by SetMaxBytes
+	if len(header)+len(body) > limit && reduction.totalItems == 0 {
+		body = fitLines(body, limit-len(header), reduction)
+	}
+	if omitted := reduction.String(); omitted != "" {
+		return fmt.Sprintf("# The result was reduced to fit its %d bytes budget (%s), narrow the request down to get the rest\n", limit, omitted), header + body
+	}
+	return "", header + body
+}
+
+// isJson returns true if the structured content is a JSON object or list
+func isJson(content string) bool {
+	trimmed := strings.TrimSpace(content)
+	return strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")
+}
+
+// splitCommentHeader splits the leading comment lines (e.g. "# The following resources...") from the content
+func splitCommentHeader(content string) (string, string) {
+	header, body := "", content
+	for strings.HasPrefix(body, "#") {
+		line, rest, _ := strings.Cut(body, "\n")
+		header += line + "\n"
+		body = rest
+	}
+	return header, body
+}
+
+// decodeStructured decodes the YAML or JSON objects and lists, numbers are kept as is
+func decodeStructured(content string) (any, bool) {
+	raw, err := yml.YAMLToJSON([]byte(content))
+	if err != nil {
+		return nil, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var v any
+	if err = decoder.Decode(&v); err != nil {
+		return nil, false
+	}
+	switch v.(type) {
+	case map[string]any, []any:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+// decodeDocuments decodes the documents of a multi-document YAML content (e.g. "---" separated resources) as a list,
+// returns false if the content has a single document or if any of its documents isn't structured
+func decodeDocuments(content string) ([]any, bool) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(content)))
+	var documents []any
+	for {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false
+		}
+		if len(bytes.TrimSpace(document)) == 0 {
+			continue
+		}
+		v, ok := decodeStructured(string(document))
+		if !ok {
+			return nil, false
+		}
+		documents = append(documents, v)
+	}
+	return documents, len(documents) > 1
+}
+
+func encodeJson(v any) string {
+	ret, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(ret)
+}
+
+func encodeYaml(v any) string {
+	ret, err := yml.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(ret)
+}
+
+// encodeDocuments encodes the list of documents as a multi-document YAML
+func encodeDocuments(v any) string {
+	documents := make([]string, 0)
+	for _, document := range v.([]any) {
+		documents = append(documents, encodeYaml(document))
+	}
+	return strings.Join(documents, "---\n")
+}
+
+func fitStructured(v any, limit int, encodeValue func(any) string, reduction *budgetReduction) string {
+	encode := func() string {
+		return encodeValue(v)
+	}
+	reduction.verboseFields = removeVerboseFields(v)
+	if ret := encode(); len(ret) <= limit {
+		return ret
+	}
+	reduction.truncatedValues = truncateValues(v)
+	if ret := encode(); len(ret) <= limit {
+		return ret
+	}
+	var list []any
+	var set func([]any)
+	switch t := v.(type) {
+	case []any:
+		list, set = t, func(items []any) { v = items }
+	case map[string]any:
+		if key := largestListField(t); key != "" {
+			list, set = t[key].([]any), func(items []any) { t[key] = items }
+			reduction.listName = " of " + key
+		}
+	}
+	if len(list) == 0 {
+		return encode()
+	}
+	// the largest number of items fitting in the budget, at least one item is kept
+	kept := sort.Search(len(list), func(n int) bool {
+		set(list[:n+1])
+		return len(encode()) > limit
+	})
+	kept = max(kept, 1)
+	set(list[:kept])
+	reduction.keptItems, reduction.totalItems = kept, len(list)
+	return encode()
+}
+
+// removeVerboseFields removes the managed fields and the last-applied-configuration annotations of the objects,
+// returns the number of removed fields
+func removeVerboseFields(v any) int {
+	removed := 0
+	switch t := v.(type) {
+	case map[string]any:
+		if metadata, ok := t["metadata"].(map[string]any); ok {
+			if _, ok := metadata["managedFields"]; ok {
+				delete(metadata, "managedFields")
+				removed++
+			}
+			if annotations, ok := metadata["annotations"].(map[string]any); ok {
+				if _, ok := annotations[lastAppliedAnnotation]; ok {
+					delete(annotations, lastAppliedAnnotation)
+					removed++
+				}
+			}
+		}
+		for _, value := range t {
+			removed += removeVerboseFields(value)
+		}
+	case []any:
+		for _, item := range t {
+			removed += removeVerboseFields(item)
+		}
+	}
+	return removed
+}
+
+// truncateValues truncates the long string values, returns the number of truncated values
+func truncateValues(v any) int {
+	truncated := 0
+	truncate := func(s string) string {
+		if len(s) <= budgetValueLength {
+			return s
+		}
+		truncated++
+		return strings.ToValidUTF8(s[:budgetValueLength], "") + fmt.Sprintf("...(%d characters omitted)", len(s)-budgetValueLength)
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		for key, value := range t {
+			if s, ok := value.(string); ok {
+				t[key] = truncate(s)
+			} else {
+				truncated += truncateValues(value)
+			}
+		}
+	case []any:
+		for i, item := range t {
+			if s, ok := item.(string); ok {
+				t[i] = truncate(s)
+			} else {
+				truncated += truncateValues(item)
+			}
+		}
+	}
+	return truncated
+}
+
+// largestListField returns the name of the largest list field of the object, empty if it has none
+func largestListField(obj map[string]any) string {
+	ret := ""
+	for key, value := range obj {
+		if list, ok := value.([]any); ok && len(list) > 0 && (ret == "" || len(list) > len(obj[ret].([]any)) || (len(list) == len(obj[ret].([]any)) && key < ret)) {
+			ret = key
+		}
+	}
+	return ret
+}
+
+// fitLines keeps the first lines of the text fitting in the budget, at least the first line (e.g. the header of a table)
+func fitLines(text string, limit int, reduction *budgetReduction) string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	size, kept := 0, 0
+	for _, line := range lines {
+		if kept > 0 && size+len(line) > limit {
+			break
+		}
+		size += len(line)
+		kept++
+	}
+	reduction.keptLines, reduction.totalLines = kept, len(lines)
+	ret := strings.Join(lines[:kept], "")
+	if len(ret) > limit {
+		// a single line exceeding the budget
+		ret = strings.ToValidUTF8(ret[:max(limit, 0)], "") + "\n"
+	}
+	return ret
+}
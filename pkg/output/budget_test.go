@@ -0,0 +1,103 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func budgetList(n int) string {
+	sb := &strings.Builder{}
+	sb.WriteString("# The following pods were found:\n")
+	for i := range n {
+		fmt.Fprintf(sb, "- metadata:\n    name: pod-%d\n    namespace: default\n  status:\n    phase: Running\n", i)
+	}
+	return sb.String()
+}
+
+func TestFitBudget(t *testing.T) {
+	t.Run("disabled budget leaves the result unchanged", func(t *testing.T) {
+		content := budgetList(100)
+		reduced, result := FitBudget(content, 0)
+		assert.Empty(t, reduced)
+		assert.Equal(t, content, result)
+	})
+	t.Run("result within the budget is unchanged", func(t *testing.T) {
+		content := budgetList(2)
+		reduced, result := FitBudget(content, len(content))
+		assert.Empty(t, reduced)
+		assert.Equal(t, content, result)
+	})
+	t.Run("list keeps the items fitting in the budget", func(t *testing.T) {
+		item := len(budgetList(1)) - len(budgetList(0))
+		reduced, result := FitBudget(budgetList(100), len(budgetList(0))+10*item+item/2)
+		assert.True(t, strings.HasPrefix(reduced,
+			"# The result was reduced to fit its "), "expected a reduction header, got %s", reduced)
+		assert.Contains(t, reduced, "(first 10 of 100 items kept)")
+		assert.True(t, strings.HasPrefix(result, "# The following pods were found:\n- metadata:\n    name: pod-0\n"), "expected the header of the result to be kept, got %s", result)
+		assert.Contains(t, result, "name: pod-9\n")
+		assert.NotContains(t, result, "name: pod-10\n")
+	})
+	t.Run("list keeps at least one item", func(t *testing.T) {
+		reduced, result := FitBudget(budgetList(100), 10)
+		assert.Contains(t, reduced, "first 1 of 100 items kept")
+		assert.Contains(t, result, "name: pod-0")
+	})
+	t.Run("JSON list is re-encoded as JSON", func(t *testing.T) {
+		reduced, result := FitBudget(`[{"name":"a","replicas":3},{"name":"b","replicas":3},{"name":"c","replicas":3}]`, 60)
+		assert.Equal(t, "# The result was reduced to fit its 60 bytes budget (first 2 of 3 items kept), narrow the request down to get the rest\n", reduced)
+		assert.Equal(t, `[{"name":"a","replicas":3},{"name":"b","replicas":3}]`, result)
+	})
+	t.Run("removes the verbose metadata first", func(t *testing.T) {
+		content := "metadata:\n  annotations:\n    kubectl.kubernetes.io/last-applied-configuration: '" + strings.Repeat("x", 200) + "'\n" +
+			"  managedFields:\n  - manager: kubectl\n  name: pod-1\n"
+		reduced, result := FitBudget(content, 100)
+		assert.Equal(t, "# The result was reduced to fit its 100 bytes budget (2 managed fields and last-applied-configuration annotations removed), narrow the request down to get the rest\n", reduced)
+		assert.Equal(t, "metadata:\n  annotations: {}\n  name: pod-1\n", result)
+	})
+	t.Run("truncates the long values", func(t *testing.T) {
+		content := "data:\n  ca.crt: " + strings.Repeat("x", 1000) + "\nkind: ConfigMap\n"
+		reduced, result := FitBudget(content, 400)
+		assert.Contains(t, reduced, "(1 values longer than 256 characters truncated)")
+		assert.Contains(t, result, "ca.crt: "+strings.Repeat("x", 256)+"...(744")
+		assert.Contains(t, result, "kind: ConfigMap\n")
+	})
+	t.Run("object keeps the items of its largest list", func(t *testing.T) {
+		content := "findings:\n" + strings.Repeat("- image outdated\n", 50) + "summary: 50 findings\n"
+		reduced, result := FitBudget(content, 100)
+		assert.Contains(t, reduced, "(first 4 of 50 items of findings kept)")
+		assert.Contains(t, result, "summary: 50 findings\n")
+	})
+	t.Run("multi-document YAML keeps the documents fitting in the budget", func(t *testing.T) {
+		configMap := func(name string) string {
+			return "apiVersion: v1\ndata:\n  a.yaml: " + strings.Repeat("a", 200) + "\n  b.yaml: " + strings.Repeat("b", 200) +
+				"\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n"
+		}
+		reduced, result := FitBudget(configMap("first")+"---\n"+configMap("second"), 700)
+		assert.Equal(t, "# The result was reduced to fit its 700 bytes budget (first 1 of 2 YAML documents kept), narrow the request down to get the rest\n", reduced)
+		assert.Equal(t, configMap("first"), result)
+	})
+	t.Run("multi-document YAML keeps all the documents when their values are truncated", func(t *testing.T) {
+		configMap := func(name string) string {
+			return "apiVersion: v1\ndata:\n  config.yaml: " + strings.Repeat("x", 400) + "\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n"
+		}
+		reduced, result := FitBudget(configMap("first")+"---\n"+configMap("second"), 800)
+		assert.Contains(t, reduced, "(2 values longer than 256 characters truncated)")
+		assert.NotContains(t, reduced, "YAML documents")
+		assert.Contains(t, result, "name: first\n---\n")
+		assert.Contains(t, result, "name: second\n")
+	})
+	t.Run("text keeps the first lines", func(t *testing.T) {
+		content := "NAME    READY\n" + strings.Repeat("pod-1   1/1\n", 10)
+		reduced, result := FitBudget(content, 40)
+		assert.Equal(t, "# The result was reduced to fit its 40 bytes budget (first 3 of 11 lines kept), narrow the request down to get the rest\n", reduced)
+		assert.Equal(t, "NAME    READY\npod-1   1/1\npod-1   1/1\n", result)
+	})
+	t.Run("single line exceeding the budget is cut", func(t *testing.T) {
+		reduced, result := FitBudget(strings.Repeat("é", 20), 11)
+		assert.Equal(t, "# The result was reduced to fit its 11 bytes budget (first 1 of 1 lines kept), narrow the request down to get the rest\n", reduced)
+		assert.Equal(t, "ééééé\n", result)
+	})
+}
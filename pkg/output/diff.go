@@ -0,0 +1,44 @@
+package output
+
+import (
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Diff returns a unified diff between the YAML representations of the object before and after a change.
+// Fields that change on every update (managedFields, resourceVersion, generation...) are ignored.
+// A nil before object represents a created resource. An empty string is returned if nothing changed.
+func Diff(name string, before, after *unstructured.Unstructured) (string, error) {
+	beforeYaml, err := diffYaml(before)
+	if err != nil {
+		return "", err
+	}
+	afterYaml, err := diffYaml(after)
+	if err != nil {
+		return "", err
+	}
+	return DiffText(name, beforeYaml, afterYaml)
+}
+
+// DiffText returns a unified diff between the before and after texts, an empty string if they are equal.
+func DiffText(name, before, after string) (string, error) {
+	if before == after {
+		return "", nil
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(after),
+		FromFile: name + " (before)",
+		ToFile:   name + " (after)",
+		Context:  3,
+	})
+}
+
+func diffYaml(obj *unstructured.Unstructured) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	obj = obj.DeepCopy()
+	Prune(obj, []string{PruneLastApplied, PruneSystemMetadata})
+	return MarshalYaml(obj)
+}
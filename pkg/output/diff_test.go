@@ -0,0 +1,55 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("returns changed fields", func(t *testing.T) {
+		before := prunablePod()
+		after := prunablePod()
+		_ = unstructured.SetNestedField(after.Object, "Succeeded", "status", "phase")
+		ret, err := Diff("Pod default/pod-1", before, after)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(ret, "--- Pod default/pod-1 (before)\n+++ Pod default/pod-1 (after)\n"), "unexpected diff header %s", ret)
+		assert.Contains(t, ret, "-  phase: Running\n+  phase: Succeeded\n")
+	})
+	t.Run("ignores system metadata", func(t *testing.T) {
+		before := prunablePod()
+		after := prunablePod()
+		after.SetResourceVersion("2")
+		after.SetGeneration(2)
+		ret, err := Diff("Pod default/pod-1", before, after)
+		assert.NoError(t, err)
+		assert.Empty(t, ret)
+	})
+	t.Run("does not modify the provided objects", func(t *testing.T) {
+		before := prunablePod()
+		_, _ = Diff("Pod default/pod-1", before, prunablePod())
+		assert.Equal(t, prunablePod(), before)
+	})
+	t.Run("returns all fields for created objects", func(t *testing.T) {
+		ret, err := Diff("Pod default/pod-1", nil, prunablePod())
+		assert.NoError(t, err)
+		assert.Contains(t, ret, "+kind: Pod\n")
+		assert.NotContains(t, ret, "\n-")
+	})
+}
+
+func TestDiffText(t *testing.T) {
+	t.Run("returns changed lines", func(t *testing.T) {
+		ret, err := DiffText("values", "replicas: 1\nimage: app:1.0\n", "replicas: 3\nimage: app:1.0\n")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(ret, "--- values (before)\n+++ values (after)\n"), "unexpected diff header %s", ret)
+		assert.Contains(t, ret, "-replicas: 1\n+replicas: 3\n image: app:1.0\n")
+	})
+	t.Run("returns empty for equal texts", func(t *testing.T) {
+		ret, err := DiffText("values", "replicas: 1\n", "replicas: 1\n")
+		assert.NoError(t, err)
+		assert.Empty(t, ret)
+	})
+}
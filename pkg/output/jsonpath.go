@@ -0,0 +1,69 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Projection is the result of applying a JSONPath expression to an item of a list.
+type Projection struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Value     any    `json:"value"`
+}
+
+// Project applies the JSONPath expression to the provided object.
+// For lists, the expression is applied to each of the items, and the result is a list of Projection.
+// Both kubectl ({.status.conditions}) and jq-style (.spec.containers[].image) expressions are accepted.
+func Project(obj runtime.Unstructured, expression string) (any, error) {
+	jp := jsonpath.New("projection").AllowMissingKeys(true)
+	if err := jp.Parse(normalizeJsonPath(expression)); err != nil {
+		return nil, fmt.Errorf("invalid jsonpath expression %s: %w", expression, err)
+	}
+	if list, ok := obj.(*unstructured.UnstructuredList); ok {
+		ret := make([]Projection, 0, len(list.Items))
+		for _, item := range list.Items {
+			value, err := project(jp, item.Object)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, Projection{Name: item.GetName(), Namespace: item.GetNamespace(), Value: value})
+		}
+		return ret, nil
+	}
+	return project(jp, obj.UnstructuredContent())
+}
+
+func project(jp *jsonpath.JSONPath, obj map[string]any) (any, error) {
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate jsonpath expression: %w", err)
+	}
+	values := make([]any, 0)
+	for _, result := range results {
+		for _, value := range result {
+			values = append(values, value.Interface())
+		}
+	}
+	switch len(values) {
+	case 0:
+		return nil, nil
+	case 1:
+		return values[0], nil
+	default:
+		return values, nil
+	}
+}
+
+func normalizeJsonPath(expression string) string {
+	expression = strings.TrimSpace(expression)
+	if !strings.HasPrefix(expression, "{") {
+		expression = "{" + expression + "}"
+	}
+	// jq-style array iteration
+	return strings.ReplaceAll(expression, "[]", "[*]")
+}
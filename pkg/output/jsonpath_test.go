@@ -0,0 +1,48 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestProject(t *testing.T) {
+	var pod unstructured.Unstructured
+	_ = json.Unmarshal([]byte(`{
+		"apiVersion": "v1", "kind": "Pod", "metadata": { "name": "pod-1", "namespace": "default" },
+		"spec": { "containers": [{ "name": "app", "image": "nginx" }, { "name": "sidecar", "image": "envoy" }] },
+		"status": { "conditions": [{ "type": "Ready", "status": "True" }] }
+	}`), &pod)
+	t.Run("projects object field", func(t *testing.T) {
+		ret, err := Project(&pod, ".status.conditions")
+		assert.NoError(t, err)
+		assert.Equal(t, []any{map[string]any{"type": "Ready", "status": "True"}}, ret)
+	})
+	t.Run("projects jq-style array iteration", func(t *testing.T) {
+		ret, err := Project(&pod, ".spec.containers[].image")
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"nginx", "envoy"}, ret)
+	})
+	t.Run("projects kubectl-style expression", func(t *testing.T) {
+		ret, err := Project(&pod, "{.spec.containers[0].name}")
+		assert.NoError(t, err)
+		assert.Equal(t, "app", ret)
+	})
+	t.Run("returns nil for missing fields", func(t *testing.T) {
+		ret, err := Project(&pod, ".status.podIP")
+		assert.NoError(t, err)
+		assert.Nil(t, ret)
+	})
+	t.Run("projects each list item", func(t *testing.T) {
+		list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{pod}}
+		ret, err := Project(list, ".spec.containers[0].image")
+		assert.NoError(t, err)
+		assert.Equal(t, []Projection{{Name: "pod-1", Namespace: "default", Value: "nginx"}}, ret)
+	})
+	t.Run("fails for invalid expressions", func(t *testing.T) {
+		_, err := Project(&pod, ".spec.containers[")
+		assert.ErrorContains(t, err, "invalid jsonpath expression .spec.containers[")
+	})
+}
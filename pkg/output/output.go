@@ -2,6 +2,10 @@ package output
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -14,6 +18,8 @@ var Yaml = &yaml{}
 
 var Table = &table{}
 
+var Json = &jsonOutput{}
+
 type Output interface {
 	// GetName returns the name of the output format, will be used by the CLI to identify the output format.
 	GetName() string
@@ -26,6 +32,7 @@ type Output interface {
 var Outputs = []Output{
 	Yaml,
 	Table,
+	Json,
 }
 
 var Names []string
@@ -51,6 +58,18 @@ func (p *yaml) PrintObj(obj runtime.Unstructured) (string, error) {
 	return MarshalYaml(obj)
 }
 
+type jsonOutput struct{}
+
+func (p *jsonOutput) GetName() string {
+	return "json"
+}
+func (p *jsonOutput) AsTable() bool {
+	return false
+}
+func (p *jsonOutput) PrintObj(obj runtime.Unstructured) (string, error) {
+	return MarshalJson(obj)
+}
+
 type table struct{}
 
 func (p *table) GetName() string {
@@ -95,7 +114,100 @@ func (p *table) PrintObj(obj runtime.Unstructured) (string, error) {
 	return buf.String(), err
 }
 
+// MarshalYaml marshals the provided value to YAML.
+// Map keys are always emitted in sorted order so that successive calls produce identical output.
+// Lists are encoded item by item and capped to the maximum output size (see SetMaxBytes).
 func MarshalYaml(v any) (string, error) {
+	if list, ok := v.(*unstructured.UnstructuredList); ok && len(list.Items) > 0 {
+		return encodeList(list.Items, "", "", func(sb *strings.Builder, first bool, item []byte) {
+			// The item is indented as a sequence entry, the empty lines (of literal blocks) are kept as is
+			for i, line := range strings.SplitAfter(string(item), "\n") {
+				switch {
+				case line == "":
+				case i == 0:
+					sb.WriteString("- ")
+				case line != "\n":
+					sb.WriteString("  ")
+				}
+				sb.WriteString(line)
+			}
+		}, yml.Marshal, func(body, note string) string {
+			return "# " + note + "\n" + body
+		})
+	}
+	ret, err := yml.Marshal(stripManagedFields(v))
+	if err != nil {
+		return "", err
+	}
+	return string(ret), nil
+}
+
+// MarshalJson marshals the provided value to compact (single-line) JSON.
+// Lists are encoded item by item and capped to the maximum output size (see SetMaxBytes), truncated lists are wrapped
+// in an object ({"items":[...],"truncated":"..."}) so that the output remains a valid JSON document.
+func MarshalJson(v any) (string, error) {
+	if list, ok := v.(*unstructured.UnstructuredList); ok && len(list.Items) > 0 {
+		return encodeList(list.Items, "[", "]", func(sb *strings.Builder, first bool, item []byte) {
+			if !first {
+				sb.WriteByte(',')
+			}
+			sb.Write(item)
+		}, func(v any) ([]byte, error) { return json.Marshal(v) }, func(body, note string) string {
+			encodedNote, _ := json.Marshal(note)
+			return `{"items":` + body + `,"truncated":` + string(encodedNote) + "}"
+		})
+	}
+	ret, err := json.Marshal(stripManagedFields(v))
+	if err != nil {
+		return "", err
+	}
+	return string(ret), nil
+}
+
+// encodeList encodes the items one at a time into the output, instead of building the intermediate representations
+// (JSON, YAML node tree) of the whole list, and stops once the output exceeds the maximum size. At least one item is
+// always encoded, the output of truncated lists is passed to truncated along with a note stating how many items were
+// left out.
+func encodeList(items []unstructured.Unstructured, prefix, suffix string, write func(sb *strings.Builder, first bool, item []byte), marshal func(any) ([]byte, error), truncated func(body, note string) string) (string, error) {
+	limit := int(maxBytes.Load())
+	sb := &strings.Builder{}
+	sb.WriteString(prefix)
+	encoded := 0
+	for i := range items {
+		items[i].SetManagedFields(nil)
+		item, err := marshal(&items[i])
+		if err != nil {
+			return "", err
+		}
+		if encoded > 0 && limit > 0 && sb.Len()+len(item) > limit {
+			break
+		}
+		write(sb, encoded == 0, item)
+		encoded++
+	}
+	sb.WriteString(suffix)
+	if encoded < len(items) {
+		return truncated(sb.String(), fmt.Sprintf("The output was truncated to the first %d of %d items (%d bytes limit), "+
+			"narrow the request down (e.g. namespace, label selector, field selector) to get the rest", encoded, len(items), limit)), nil
+	}
+	return sb.String(), nil
+}
+
+// DefaultMaxBytes is the default maximum size of the encoded lists
+const DefaultMaxBytes = 16 * 1024 * 1024
+
+var maxBytes atomic.Int64
+
+// SetMaxBytes sets the maximum size of the encoded lists (DefaultMaxBytes if not positive), so that listing thousands of
+// objects doesn't exhaust the memory of the server (nor the context of the client)
+func SetMaxBytes(n int64) {
+	if n <= 0 {
+		n = DefaultMaxBytes
+	}
+	maxBytes.Store(n)
+}
+
+func stripManagedFields(v any) any {
 	switch t := v.(type) {
 	//case unstructured.UnstructuredList:
 	//	for i := range t.Items {
@@ -112,14 +224,11 @@ func MarshalYaml(v any) (string, error) {
 	case *unstructured.Unstructured:
 		t.SetManagedFields(nil)
 	}
-	ret, err := yml.Marshal(v)
-	if err != nil {
-		return "", err
-	}
-	return string(ret), nil
+	return v
 }
 
 func init() {
+	maxBytes.Store(DefaultMaxBytes)
 	Names = make([]string, 0)
 	for _, output := range Outputs {
 		Names = append(Names, output.GetName())
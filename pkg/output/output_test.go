@@ -2,10 +2,13 @@ package output
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	yml "sigs.k8s.io/yaml"
 )
 
 func TestPlainTextUnstructuredList(t *testing.T) {
@@ -31,3 +34,111 @@ func TestPlainTextUnstructuredList(t *testing.T) {
 		}
 	})
 }
+
+func TestJsonUnstructuredList(t *testing.T) {
+	var podList unstructured.UnstructuredList
+	_ = json.Unmarshal([]byte(`
+			{ "apiVersion": "v1", "kind": "PodList", "items": [{
+			  "apiVersion": "v1", "kind": "Pod",
+			  "metadata": { "name": "pod-1", "namespace": "default", "managedFields": [{ "manager": "kubectl" }] }
+			}]}`), &podList)
+	out, err := Json.PrintObj(&podList)
+	t.Run("processes the list", func(t *testing.T) {
+		if err != nil {
+			t.Fatalf("Error printing pod list: %v", err)
+		}
+	})
+	t.Run("prints compact JSON array of items without managedFields", func(t *testing.T) {
+		expected := `[{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod-1","namespace":"default"}}]`
+		if out != expected {
+			t.Errorf("Expected %s, got %s", expected, out)
+		}
+	})
+}
+
+func TestStreamedUnstructuredList(t *testing.T) {
+	newList := func(n int) *unstructured.UnstructuredList {
+		list := &unstructured.UnstructuredList{}
+		for i := range n {
+			list.Items = append(list.Items, unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "v1", "kind": "ConfigMap",
+				"metadata": map[string]any{"name": fmt.Sprintf("cm-%d", i), "managedFields": []any{map[string]any{"manager": "kubectl"}}},
+				"data": map[string]any{
+					"script.sh": "#!/bin/sh\n\necho \"indented\"\n  trailing  \n",
+					"empty":     map[string]any{},
+					"list":      []any{"a", map[string]any{"b": []any{1, 2}}},
+				},
+			}})
+		}
+		return list
+	}
+	t.Run("YAML is the same as the YAML of the whole list", func(t *testing.T) {
+		out, err := MarshalYaml(newList(3))
+		if err != nil {
+			t.Fatalf("Error marshalling list: %v", err)
+		}
+		list := newList(3)
+		for i := range list.Items {
+			list.Items[i].SetManagedFields(nil)
+		}
+		expected, _ := yml.Marshal(list.Items)
+		if out != string(expected) {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, out)
+		}
+	})
+	t.Run("JSON is the same as the JSON of the whole list", func(t *testing.T) {
+		out, err := MarshalJson(newList(3))
+		if err != nil {
+			t.Fatalf("Error marshalling list: %v", err)
+		}
+		list := newList(3)
+		for i := range list.Items {
+			list.Items[i].SetManagedFields(nil)
+		}
+		expected, _ := json.Marshal(list.Items)
+		if out != string(expected) {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, out)
+		}
+	})
+	t.Run("lists exceeding the maximum size are truncated", func(t *testing.T) {
+		SetMaxBytes(1024)
+		t.Cleanup(func() { SetMaxBytes(0) })
+		yamlOut, err := MarshalYaml(newList(100))
+		if err != nil || len(yamlOut) > 1024+256 || !strings.HasPrefix(yamlOut, "# The output was truncated to the first ") {
+			t.Errorf("Expected truncated YAML (%v): %s", err, yamlOut)
+		}
+		jsonOut, _ := MarshalJson(newList(100))
+		var wrapper struct {
+			Items     []any  `json:"items"`
+			Truncated string `json:"truncated"`
+		}
+		if err = json.Unmarshal([]byte(jsonOut), &wrapper); err != nil || len(wrapper.Items) == 0 || len(wrapper.Items) == 100 {
+			t.Errorf("Expected a valid JSON document with the truncated items (%v): %s", err, jsonOut)
+		}
+		if !strings.HasPrefix(wrapper.Truncated, "The output was truncated to the first ") {
+			t.Errorf("Expected the truncation note in the JSON document: %s", jsonOut)
+		}
+	})
+	t.Run("lists with a single item exceeding the maximum size are not truncated", func(t *testing.T) {
+		SetMaxBytes(1)
+		t.Cleanup(func() { SetMaxBytes(0) })
+		out, err := MarshalYaml(newList(1))
+		if err != nil || strings.HasPrefix(out, "#") || !strings.HasPrefix(out, "- apiVersion: v1") {
+			t.Errorf("Expected the single item (%v): %s", err, out)
+		}
+	})
+}
+
+func TestMarshalDeterministicKeyOrder(t *testing.T) {
+	v := map[string]any{"zeta": 1, "alpha": map[string]any{"b": 2, "a": 1}, "mu": []any{"z", "a"}}
+	for i := 0; i < 10; i++ {
+		yamlOut, err := MarshalYaml(v)
+		if err != nil || yamlOut != "alpha:\n  a: 1\n  b: 2\nmu:\n- z\n- a\nzeta: 1\n" {
+			t.Fatalf("Unexpected YAML key order (%v): %s", err, yamlOut)
+		}
+		jsonOut, err := MarshalJson(v)
+		if err != nil || jsonOut != `{"alpha":{"a":1,"b":2},"mu":["z","a"],"zeta":1}` {
+			t.Fatalf("Unexpected JSON key order (%v): %s", err, jsonOut)
+		}
+	}
+}
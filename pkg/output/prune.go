@@ -0,0 +1,61 @@
+package output
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// PruneLastApplied removes the kubectl.kubernetes.io/last-applied-configuration annotation
+	PruneLastApplied = "last_applied"
+	// PruneStatus removes the status of the object
+	PruneStatus = "status"
+	// PruneSystemMetadata removes the metadata fields populated by the system (uid, resourceVersion, generation, selfLink)
+	PruneSystemMetadata = "system_metadata"
+	// PruneOwnerReferences removes the metadata.ownerReferences of the object
+	PruneOwnerReferences = "owner_references"
+
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// PruneFields are the supported names of the fields that can be pruned from the returned objects.
+// Managed fields are not listed since they are always removed when printing.
+var PruneFields = []string{PruneLastApplied, PruneStatus, PruneSystemMetadata, PruneOwnerReferences}
+
+// Prune removes the provided fields from the object or from each of the list items.
+func Prune(obj runtime.Unstructured, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	switch t := obj.(type) {
+	case *unstructured.UnstructuredList:
+		for i := range t.Items {
+			prune(&t.Items[i], fields)
+		}
+	case *unstructured.Unstructured:
+		if t.GetKind() == "Table" {
+			return
+		}
+		prune(t, fields)
+	}
+}
+
+func prune(obj *unstructured.Unstructured, fields []string) {
+	for _, field := range fields {
+		switch field {
+		case PruneLastApplied:
+			unstructured.RemoveNestedField(obj.Object, "metadata", "annotations", lastAppliedAnnotation)
+			if len(obj.GetAnnotations()) == 0 {
+				unstructured.RemoveNestedField(obj.Object, "metadata", "annotations")
+			}
+		case PruneStatus:
+			unstructured.RemoveNestedField(obj.Object, "status")
+		case PruneSystemMetadata:
+			for _, f := range []string{"uid", "resourceVersion", "generation", "selfLink"} {
+				unstructured.RemoveNestedField(obj.Object, "metadata", f)
+			}
+		case PruneOwnerReferences:
+			unstructured.RemoveNestedField(obj.Object, "metadata", "ownerReferences")
+		}
+	}
+}
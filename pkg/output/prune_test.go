@@ -0,0 +1,65 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func prunablePod() *unstructured.Unstructured {
+	pod := &unstructured.Unstructured{}
+	_ = json.Unmarshal([]byte(`{
+		"apiVersion": "v1", "kind": "Pod",
+		"metadata": {
+			"name": "pod-1", "namespace": "default", "uid": "1234", "resourceVersion": "1", "generation": 1,
+			"annotations": { "kubectl.kubernetes.io/last-applied-configuration": "{}" },
+			"ownerReferences": [{ "kind": "ReplicaSet", "name": "rs-1" }]
+		},
+		"spec": { "containers": [{ "name": "app", "image": "nginx" }] },
+		"status": { "phase": "Running" }
+	}`), &pod.Object)
+	return pod
+}
+
+func TestPrune(t *testing.T) {
+	t.Run("no fields leaves object unchanged", func(t *testing.T) {
+		pod := prunablePod()
+		Prune(pod, nil)
+		assert.Equal(t, prunablePod(), pod)
+	})
+	t.Run("last_applied removes annotation", func(t *testing.T) {
+		pod := prunablePod()
+		Prune(pod, []string{PruneLastApplied})
+		_, found, _ := unstructured.NestedFieldNoCopy(pod.Object, "metadata", "annotations")
+		assert.False(t, found)
+	})
+	t.Run("status removes status", func(t *testing.T) {
+		pod := prunablePod()
+		Prune(pod, []string{PruneStatus})
+		_, found, _ := unstructured.NestedFieldNoCopy(pod.Object, "status")
+		assert.False(t, found)
+		assert.Equal(t, "pod-1", pod.GetName())
+	})
+	t.Run("system_metadata removes system populated metadata", func(t *testing.T) {
+		pod := prunablePod()
+		Prune(pod, []string{PruneSystemMetadata})
+		assert.Empty(t, pod.GetUID())
+		assert.Empty(t, pod.GetResourceVersion())
+		assert.Zero(t, pod.GetGeneration())
+	})
+	t.Run("owner_references removes owner references", func(t *testing.T) {
+		pod := prunablePod()
+		Prune(pod, []string{PruneOwnerReferences})
+		assert.Empty(t, pod.GetOwnerReferences())
+	})
+	t.Run("list prunes every item", func(t *testing.T) {
+		list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*prunablePod(), *prunablePod()}}
+		Prune(list, []string{PruneStatus})
+		for _, item := range list.Items {
+			_, found, _ := unstructured.NestedFieldNoCopy(item.Object, "status")
+			assert.False(t, found)
+		}
+	})
+}
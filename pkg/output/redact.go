@@ -0,0 +1,229 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+	yml "sigs.k8s.io/yaml"
+)
+
+// Redacted is the default replacement of the sensitive data removed from the tool results
+const Redacted = "[REDACTED]"
+
+// Redaction is a rule removing sensitive data from the tool results, either:
+//   - a Pattern, the regular expression whose matches are replaced in the text of the results
+//   - a JSONPath and/or a Name, the fields of the YAML/JSON results whose values are replaced
+type Redaction struct {
+	// Pattern is a regular expression, its matches are replaced by the Replacement ($1 expands to the first group)
+	Pattern string
+	// Replacement replaces the redacted data, Redacted if empty
+	Replacement string
+	// JSONPath selects the objects or lists of the results whose values are replaced, e.g. .data (kubectl and jq-style
+	// expressions are accepted), the whole results if empty
+	JSONPath string
+	// Name is a regular expression, only the fields with a matching name are replaced (at any depth of the selected
+	// values), and the value of the name/value entries with a matching name, e.g. PASSWORD|TOKEN for the env vars
+	Name string
+}
+
+// Redactor applies the redaction rules to the tool results
+type Redactor struct {
+	patterns []patternRedaction
+	fields   []fieldRedaction
+}
+
+type patternRedaction struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+type fieldRedaction struct {
+	jsonPath    *jsonpath.JSONPath
+	name        *regexp.Regexp
+	replacement string
+}
+
+// NewRedactor returns a Redactor applying the rules in order, the field rules before the pattern rules
+func NewRedactor(redactions ...Redaction) (*Redactor, error) {
+	r := &Redactor{}
+	for i, redaction := range redactions {
+		replacement := redaction.Replacement
+		if replacement == "" {
+			replacement = Redacted
+		}
+		switch {
+		case redaction.Pattern != "" && (redaction.JSONPath != "" || redaction.Name != ""):
+			return nil, fmt.Errorf("redaction rule %d: pattern can't be combined with a JSONPath or a name", i)
+		case redaction.Pattern != "":
+			pattern, err := regexp.Compile(redaction.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("redaction rule %d: invalid pattern: %w", i, err)
+			}
+			r.patterns = append(r.patterns, patternRedaction{pattern: pattern, replacement: replacement})
+		case redaction.JSONPath != "" || redaction.Name != "":
+			field := fieldRedaction{replacement: replacement}
+			if redaction.JSONPath != "" {
+				field.jsonPath = jsonpath.New("redaction").AllowMissingKeys(true)
+				if err := field.jsonPath.Parse(normalizeJsonPath(redaction.JSONPath)); err != nil {
+					return nil, fmt.Errorf("redaction rule %d: invalid jsonpath expression %s: %w", i, redaction.JSONPath, err)
+				}
+			}
+			if redaction.Name != "" {
+				name, err := regexp.Compile(redaction.Name)
+				if err != nil {
+					return nil, fmt.Errorf("redaction rule %d: invalid name: %w", i, err)
+				}
+				field.name = name
+			}
+			r.fields = append(r.fields, field)
+		default:
+			return nil, fmt.Errorf("redaction rule %d: either a pattern, a JSONPath or a name is required", i)
+		}
+	}
+	return r, nil
+}
+
+// Redact returns the content with the sensitive data removed.
+// The field rules only apply to structured (YAML or JSON) content, which is re-encoded if any field was redacted.
+func (r *Redactor) Redact(content string) string {
+	if r == nil {
+		return content
+	}
+	if len(r.fields) > 0 {
+		documents := strings.Split(content, "\n---\n")
+		for i, document := range documents {
+			documents[i] = r.redactFields(document)
+		}
+		content = strings.Join(documents, "\n---\n")
+	}
+	for _, p := range r.patterns {
+		content = p.pattern.ReplaceAllString(content, p.replacement)
+	}
+	return content
+}
+
+func (r *Redactor) redactFields(content string) string {
+	// Leading comment lines (e.g. "# The following resources...") are kept as is
+	header, body := "", content
+	for strings.HasPrefix(body, "#") {
+		line, rest, _ := strings.Cut(body, "\n")
+		header += line + "\n"
+		body = rest
+	}
+	var v any
+	if err := yml.Unmarshal([]byte(body), &v); err != nil {
+		return content
+	}
+	switch v.(type) {
+	case map[string]any, []any:
+	default:
+		return content
+	}
+	redacted := false
+	for _, field := range r.fields {
+		redacted = field.apply(v) || redacted
+	}
+	if !redacted {
+		return content
+	}
+	if trimmed := strings.TrimSpace(body); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		ret := &bytes.Buffer{}
+		encoder := json.NewEncoder(ret)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(v); err != nil {
+			return content
+		}
+		return header + strings.TrimSuffix(ret.String(), "\n")
+	}
+	ret, err := yml.Marshal(v)
+	if err != nil {
+		return content
+	}
+	if !strings.HasSuffix(body, "\n") {
+		// the documents split from multi-document content
+		return header + strings.TrimSuffix(string(ret), "\n")
+	}
+	return header + string(ret)
+}
+
+// apply redacts the fields of the document, the JSONPath is evaluated against the document and, for lists, against
+// each of the items too
+func (f *fieldRedaction) apply(document any) bool {
+	if f.jsonPath == nil {
+		return f.redactNamed(document)
+	}
+	targets := []any{document}
+	switch document := document.(type) {
+	case []any:
+		targets = append(targets, document...)
+	case map[string]any:
+		if items, ok := document["items"].([]any); ok {
+			targets = append(targets, items...)
+		}
+	}
+	redacted := false
+	for _, target := range targets {
+		results, err := f.jsonPath.FindResults(target)
+		if err != nil {
+			continue
+		}
+		for _, result := range results {
+			for _, value := range result {
+				redacted = f.redactSelected(value.Interface()) || redacted
+			}
+		}
+	}
+	return redacted
+}
+
+// redactSelected redacts the values of the object or list selected by the JSONPath, the selected scalars can't be
+// replaced in place and are ignored
+func (f *fieldRedaction) redactSelected(selected any) bool {
+	if f.name != nil {
+		return f.redactNamed(selected)
+	}
+	switch selected := selected.(type) {
+	case map[string]any:
+		for key := range selected {
+			selected[key] = f.replacement
+		}
+		return len(selected) > 0
+	case []any:
+		for i := range selected {
+			selected[i] = f.replacement
+		}
+		return len(selected) > 0
+	}
+	return false
+}
+
+// redactNamed redacts the fields with a matching name, and the value of the name/value entries with a matching name
+func (f *fieldRedaction) redactNamed(value any) bool {
+	redacted := false
+	switch value := value.(type) {
+	case map[string]any:
+		if name, ok := value["name"].(string); ok && f.name.MatchString(name) {
+			if _, ok = value["value"]; ok {
+				value["value"] = f.replacement
+				redacted = true
+			}
+		}
+		for key, nested := range value {
+			if f.name.MatchString(key) {
+				value[key] = f.replacement
+				redacted = true
+				continue
+			}
+			redacted = f.redactNamed(nested) || redacted
+		}
+	case []any:
+		for _, nested := range value {
+			redacted = f.redactNamed(nested) || redacted
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,105 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redactPod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: pod-1
+spec:
+  containers:
+  - env:
+    - name: DB_PASSWORD
+      value: s3cr3t
+    - name: LOG_LEVEL
+      value: debug
+    image: nginx
+    name: app
+`
+
+func TestNewRedactor(t *testing.T) {
+	t.Run("rejects rule without pattern, JSONPath nor name", func(t *testing.T) {
+		_, err := NewRedactor(Redaction{Replacement: "***"})
+		assert.EqualError(t, err, "redaction rule 0: either a pattern, a JSONPath or a name is required")
+	})
+	t.Run("rejects pattern combined with name", func(t *testing.T) {
+		_, err := NewRedactor(Redaction{Pattern: "secret", Name: "PASSWORD"})
+		assert.ErrorContains(t, err, "pattern can't be combined with a JSONPath or a name")
+	})
+	t.Run("rejects invalid pattern", func(t *testing.T) {
+		_, err := NewRedactor(Redaction{Name: "PASSWORD"}, Redaction{Pattern: "("})
+		assert.ErrorContains(t, err, "redaction rule 1: invalid pattern")
+	})
+	t.Run("rejects invalid JSONPath", func(t *testing.T) {
+		_, err := NewRedactor(Redaction{JSONPath: "{.data"})
+		assert.ErrorContains(t, err, "invalid jsonpath expression")
+	})
+}
+
+func TestRedact(t *testing.T) {
+	t.Run("nil redactor returns content unchanged", func(t *testing.T) {
+		var r *Redactor
+		assert.Equal(t, redactPod, r.Redact(redactPod))
+	})
+	t.Run("pattern replaces matches with groups", func(t *testing.T) {
+		r, err := NewRedactor(Redaction{Pattern: `(password=)\S+`, Replacement: "${1}***"})
+		require.NoError(t, err)
+		assert.Equal(t, "connecting with password=*** to db", r.Redact("connecting with password=hunter2 to db"))
+	})
+	t.Run("name redacts env var values with matching name", func(t *testing.T) {
+		r, err := NewRedactor(Redaction{Name: "PASSWORD|TOKEN"})
+		require.NoError(t, err)
+		ret := r.Redact(redactPod)
+		assert.Contains(t, ret, "- name: DB_PASSWORD\n      value: '[REDACTED]'")
+		assert.Contains(t, ret, "- name: LOG_LEVEL\n      value: debug")
+	})
+	t.Run("name redacts fields with matching name", func(t *testing.T) {
+		r, err := NewRedactor(Redaction{Name: "(?i)^password$"})
+		require.NoError(t, err)
+		assert.Equal(t, "spec:\n  password: '[REDACTED]'\n  user: admin\n", r.Redact("spec:\n  password: hunter2\n  user: admin\n"))
+	})
+	t.Run("JSONPath redacts all values of selected object", func(t *testing.T) {
+		r, err := NewRedactor(Redaction{JSONPath: ".data", Replacement: "***"})
+		require.NoError(t, err)
+		assert.Equal(t, "data:\n  password: '***'\n  user: '***'\nkind: Secret\n", r.Redact("kind: Secret\ndata:\n  user: YWRtaW4=\n  password: aHVudGVyMg==\n"))
+	})
+	t.Run("JSONPath with name only redacts matching entries of selection", func(t *testing.T) {
+		r, err := NewRedactor(Redaction{JSONPath: ".spec.containers[].env", Name: "LOG_LEVEL"})
+		require.NoError(t, err)
+		ret := r.Redact(redactPod)
+		assert.Contains(t, ret, "- name: DB_PASSWORD\n      value: s3cr3t")
+		assert.Contains(t, ret, "- name: LOG_LEVEL\n      value: '[REDACTED]'")
+	})
+	t.Run("JSONPath applies to the items of lists", func(t *testing.T) {
+		r, err := NewRedactor(Redaction{JSONPath: ".data"})
+		require.NoError(t, err)
+		ret := r.Redact("- kind: Secret\n  data:\n    token: dG9rZW4=\n- kind: Secret\n  data:\n    key: a2V5\n")
+		assert.Equal(t, "- data:\n    token: '[REDACTED]'\n  kind: Secret\n- data:\n    key: '[REDACTED]'\n  kind: Secret\n", ret)
+	})
+	t.Run("JSON content is kept as JSON", func(t *testing.T) {
+		r, err := NewRedactor(Redaction{Name: "token"})
+		require.NoError(t, err)
+		assert.Equal(t, `{"token":"[REDACTED]","user":"admin"}`, r.Redact(`{"user":"admin","token":"abc"}`))
+	})
+	t.Run("header comments and documents are preserved", func(t *testing.T) {
+		r, err := NewRedactor(Redaction{Name: "token"})
+		require.NoError(t, err)
+		ret := r.Redact("# The following resources were found\ntoken: abc\n---\nuser: admin\n")
+		assert.Equal(t, "# The following resources were found\ntoken: '[REDACTED]'\n---\nuser: admin\n", ret)
+	})
+	t.Run("unstructured content is unchanged by field rules", func(t *testing.T) {
+		r, err := NewRedactor(Redaction{Name: "token"})
+		require.NoError(t, err)
+		assert.Equal(t, "token rotated at 12:00", r.Redact("token rotated at 12:00"))
+	})
+	t.Run("unmatched content is unchanged", func(t *testing.T) {
+		r, err := NewRedactor(Redaction{Name: "TOKEN"})
+		require.NoError(t, err)
+		assert.Equal(t, redactPod, r.Redact(redactPod))
+	})
+}
@@ -0,0 +1,119 @@
+package output
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	SortByName              = "name"
+	SortByNamespace         = "namespace"
+	SortByCreationTimestamp = "creationTimestamp"
+	// SortByRestarts sorts Pods by the sum of their container restarts
+	SortByRestarts = "restarts"
+)
+
+// SortFields are the supported names of the fields that lists can be sorted by.
+var SortFields = []string{SortByName, SortByNamespace, SortByCreationTimestamp, SortByRestarts}
+
+// Sort sorts the items of the list (or the rows of a Table) in place by the provided field.
+// Items with equal values keep their relative order.
+func Sort(obj runtime.Unstructured, sortBy string, descending bool) error {
+	if !slices.Contains(SortFields, sortBy) {
+		return fmt.Errorf("invalid sort field: %s, valid fields are: %s", sortBy, strings.Join(SortFields, ", "))
+	}
+	switch t := obj.(type) {
+	case *unstructured.UnstructuredList:
+		sortStable(t.Items, descending, func(item unstructured.Unstructured) sortKey {
+			return itemSortKey(item.Object, sortBy)
+		})
+	case *unstructured.Unstructured:
+		if t.GetKind() != "Table" {
+			return nil
+		}
+		rows, _, _ := unstructured.NestedSlice(t.Object, "rows")
+		restartsColumn := tableColumn(t.Object, "Restarts")
+		sortStable(rows, descending, func(row any) sortKey {
+			r, _ := row.(map[string]any)
+			if sortBy == SortByRestarts {
+				cells, _ := r["cells"].([]any)
+				if restartsColumn < 0 || restartsColumn >= len(cells) {
+					return sortKey{}
+				}
+				// e.g. 3 or "3 (5m ago)"
+				restarts, _ := strconv.ParseInt(strings.Fields(fmt.Sprint(cells[restartsColumn]) + " ")[0], 10, 64)
+				return sortKey{number: restarts}
+			}
+			object, _ := r["object"].(map[string]any)
+			return itemSortKey(object, sortBy)
+		})
+		return unstructured.SetNestedSlice(t.Object, rows, "rows")
+	}
+	return nil
+}
+
+type sortKey struct {
+	text   string
+	number int64
+}
+
+func (k sortKey) compare(other sortKey) int {
+	if k.number != other.number {
+		if k.number < other.number {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(k.text, other.text)
+}
+
+func sortStable[T any](items []T, descending bool, key func(T) sortKey) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		if descending {
+			return key(b).compare(key(a))
+		}
+		return key(a).compare(key(b))
+	})
+}
+
+func itemSortKey(obj map[string]any, sortBy string) sortKey {
+	switch sortBy {
+	case SortByName:
+		name, _, _ := unstructured.NestedString(obj, "metadata", "name")
+		return sortKey{text: name}
+	case SortByNamespace:
+		namespace, _, _ := unstructured.NestedString(obj, "metadata", "namespace")
+		name, _, _ := unstructured.NestedString(obj, "metadata", "name")
+		return sortKey{text: namespace + "/" + name}
+	case SortByCreationTimestamp:
+		// RFC 3339 timestamps sort lexicographically
+		creationTimestamp, _, _ := unstructured.NestedString(obj, "metadata", "creationTimestamp")
+		return sortKey{text: creationTimestamp}
+	case SortByRestarts:
+		containerStatuses, _, _ := unstructured.NestedSlice(obj, "status", "containerStatuses")
+		var restarts int64
+		for _, containerStatus := range containerStatuses {
+			if cs, ok := containerStatus.(map[string]any); ok {
+				restartCount, _, _ := unstructured.NestedInt64(cs, "restartCount")
+				restarts += restartCount
+			}
+		}
+		return sortKey{number: restarts}
+	}
+	return sortKey{}
+}
+
+func tableColumn(table map[string]any, name string) int {
+	columns, _, _ := unstructured.NestedSlice(table, "columnDefinitions")
+	for i, column := range columns {
+		if c, ok := column.(map[string]any); ok && strings.EqualFold(fmt.Sprint(c["name"]), name) {
+			return i
+		}
+	}
+	return -1
+}
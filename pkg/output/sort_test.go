@@ -0,0 +1,88 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func sortablePods() *unstructured.UnstructuredList {
+	var podList unstructured.UnstructuredList
+	_ = json.Unmarshal([]byte(`{ "apiVersion": "v1", "kind": "PodList", "items": [
+		{ "apiVersion": "v1", "kind": "Pod",
+		  "metadata": { "name": "b", "namespace": "ns-1", "creationTimestamp": "2024-01-01T00:00:00Z" },
+		  "status": { "containerStatuses": [{ "restartCount": 1 }, { "restartCount": 1 }] } },
+		{ "apiVersion": "v1", "kind": "Pod",
+		  "metadata": { "name": "c", "namespace": "ns-0", "creationTimestamp": "2023-01-01T00:00:00Z" },
+		  "status": { "containerStatuses": [{ "restartCount": 5 }] } },
+		{ "apiVersion": "v1", "kind": "Pod",
+		  "metadata": { "name": "a", "namespace": "ns-1", "creationTimestamp": "2025-01-01T00:00:00Z" } }
+	]}`), &podList)
+	return &podList
+}
+
+func names(list *unstructured.UnstructuredList) []string {
+	ret := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		ret = append(ret, item.GetName())
+	}
+	return ret
+}
+
+func TestSort(t *testing.T) {
+	for _, tc := range []struct {
+		sortBy     string
+		descending bool
+		expected   []string
+	}{
+		{SortByName, false, []string{"a", "b", "c"}},
+		{SortByName, true, []string{"c", "b", "a"}},
+		{SortByNamespace, false, []string{"c", "a", "b"}},
+		{SortByCreationTimestamp, false, []string{"c", "b", "a"}},
+		{SortByCreationTimestamp, true, []string{"a", "b", "c"}},
+		{SortByRestarts, true, []string{"c", "b", "a"}},
+	} {
+		t.Run(tc.sortBy, func(t *testing.T) {
+			list := sortablePods()
+			assert.NoError(t, Sort(list, tc.sortBy, tc.descending))
+			assert.Equal(t, tc.expected, names(list))
+		})
+	}
+	t.Run("invalid field", func(t *testing.T) {
+		assert.EqualError(t, Sort(sortablePods(), "size", false),
+			"invalid sort field: size, valid fields are: name, namespace, creationTimestamp, restarts")
+	})
+}
+
+func TestSortTable(t *testing.T) {
+	table := &unstructured.Unstructured{}
+	_ = json.Unmarshal([]byte(`{ "apiVersion": "meta.k8s.io/v1", "kind": "Table",
+		"columnDefinitions": [{ "name": "Name" }, { "name": "Restarts" }],
+		"rows": [
+			{ "cells": ["b", "2 (5m ago)"], "object": { "metadata": { "name": "b", "creationTimestamp": "2024-01-01T00:00:00Z" } } },
+			{ "cells": ["a", 0], "object": { "metadata": { "name": "a", "creationTimestamp": "2025-01-01T00:00:00Z" } } },
+			{ "cells": ["c", "7"], "object": { "metadata": { "name": "c", "creationTimestamp": "2023-01-01T00:00:00Z" } } }
+		]}`), &table.Object)
+	rowNames := func() []string {
+		rows, _, _ := unstructured.NestedSlice(table.Object, "rows")
+		ret := make([]string, 0, len(rows))
+		for _, row := range rows {
+			ret = append(ret, row.(map[string]any)["cells"].([]any)[0].(string))
+		}
+		return ret
+	}
+	t.Run("by name", func(t *testing.T) {
+		assert.NoError(t, Sort(table, SortByName, false))
+		assert.Equal(t, []string{"a", "b", "c"}, rowNames())
+	})
+	t.Run("by creationTimestamp", func(t *testing.T) {
+		assert.NoError(t, Sort(table, SortByCreationTimestamp, false))
+		assert.Equal(t, []string{"c", "b", "a"}, rowNames())
+	})
+	t.Run("by restarts column", func(t *testing.T) {
+		assert.NoError(t, Sort(table, SortByRestarts, true))
+		assert.Equal(t, []string{"c", "b", "a"}, rowNames())
+	})
+}
@@ -0,0 +1,100 @@
+package output
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// DetailSummary lists the objects as slim summaries (name, namespace, labels, creation, status summary, and URI)
+	DetailSummary = "summary"
+	// DetailFull lists the complete objects
+	DetailFull = "full"
+)
+
+// Details are the supported levels of detail of the listed objects
+var Details = []string{DetailSummary, DetailFull}
+
+// Summarize replaces the items of the list with their summaries: their identity, labels, creation timestamp, the
+// summary of their status (phase, readiness, replicas, and conditions), and the URI the complete object can be read from
+func Summarize(obj runtime.Unstructured, uri func(item *unstructured.Unstructured) string) {
+	list, ok := obj.(*unstructured.UnstructuredList)
+	if !ok {
+		return
+	}
+	for i := range list.Items {
+		list.Items[i] = unstructured.Unstructured{Object: summarize(&list.Items[i], uri(&list.Items[i]))}
+	}
+}
+
+func summarize(item *unstructured.Unstructured, uri string) map[string]any {
+	metadata := map[string]any{"name": item.GetName()}
+	if namespace := item.GetNamespace(); namespace != "" {
+		metadata["namespace"] = namespace
+	}
+	if labels := item.GetLabels(); len(labels) > 0 {
+		summaryLabels := make(map[string]any, len(labels))
+		for k, v := range labels {
+			summaryLabels[k] = v
+		}
+		metadata["labels"] = summaryLabels
+	}
+	if creationTimestamp, ok, _ := unstructured.NestedString(item.Object, "metadata", "creationTimestamp"); ok {
+		metadata["creationTimestamp"] = creationTimestamp
+	}
+	if deletionTimestamp, ok, _ := unstructured.NestedString(item.Object, "metadata", "deletionTimestamp"); ok {
+		metadata["deletionTimestamp"] = deletionTimestamp
+	}
+	ret := map[string]any{"apiVersion": item.GetAPIVersion(), "kind": item.GetKind(), "metadata": metadata}
+	if status := statusSummary(item); len(status) > 0 {
+		ret["status"] = status
+	}
+	ret["uri"] = uri
+	return ret
+}
+
+// statusSummary returns the fields of the status common to most kinds, the rest is available in the complete object
+func statusSummary(item *unstructured.Unstructured) map[string]any {
+	status := map[string]any{}
+	if phase, ok, _ := unstructured.NestedString(item.Object, "status", "phase"); ok {
+		status["phase"] = phase
+	}
+	if containerStatuses, ok, _ := unstructured.NestedSlice(item.Object, "status", "containerStatuses"); ok {
+		ready, restarts := 0, int64(0)
+		for _, cs := range containerStatuses {
+			if containerStatus, ok := cs.(map[string]any); ok {
+				if isReady, _, _ := unstructured.NestedBool(containerStatus, "ready"); isReady {
+					ready++
+				}
+				count, _, _ := unstructured.NestedInt64(containerStatus, "restartCount")
+				restarts += count
+			}
+		}
+		status["ready"] = fmt.Sprintf("%d/%d", ready, len(containerStatuses))
+		status["restarts"] = restarts
+	}
+	if nodeName, ok, _ := unstructured.NestedString(item.Object, "spec", "nodeName"); ok && nodeName != "" {
+		status["node"] = nodeName
+	}
+	if desired, ok, _ := unstructured.NestedInt64(item.Object, "spec", "replicas"); ok {
+		status["replicas"] = desired
+		for _, field := range []string{"readyReplicas", "availableReplicas", "updatedReplicas"} {
+			count, _, _ := unstructured.NestedInt64(item.Object, "status", field)
+			status[field] = count
+		}
+	}
+	if conditions, ok, _ := unstructured.NestedSlice(item.Object, "status", "conditions"); ok && len(conditions) > 0 {
+		summaryConditions := map[string]any{}
+		for _, c := range conditions {
+			if condition, ok := c.(map[string]any); ok {
+				if conditionType, ok := condition["type"].(string); ok {
+					summaryConditions[conditionType] = condition["status"]
+				}
+			}
+		}
+		status["conditions"] = summaryConditions
+	}
+	return status
+}
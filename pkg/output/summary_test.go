@@ -0,0 +1,64 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSummarize(t *testing.T) {
+	list := &unstructured.UnstructuredList{}
+	require.NoError(t, list.UnmarshalJSON([]byte(`{"apiVersion": "v1", "kind": "List", "items": [{
+		"apiVersion": "v1", "kind": "Pod",
+		"metadata": {
+			"name": "pod-1", "namespace": "default", "uid": "1234", "labels": { "app": "web" },
+			"creationTimestamp": "2025-01-01T00:00:00Z", "annotations": { "description": "verbose" }
+		},
+		"spec": { "nodeName": "node-1", "containers": [{ "name": "app", "image": "nginx" }, { "name": "sidecar", "image": "envoy" }] },
+		"status": {
+			"phase": "Running",
+			"conditions": [{ "type": "Ready", "status": "False" }, { "type": "PodScheduled", "status": "True" }],
+			"containerStatuses": [{ "name": "app", "ready": true, "restartCount": 3 }, { "name": "sidecar", "ready": false, "restartCount": 1 }]
+		}
+	}, {
+		"apiVersion": "apps/v1", "kind": "Deployment",
+		"metadata": { "name": "web", "namespace": "default" },
+		"spec": { "replicas": 3 },
+		"status": { "readyReplicas": 2, "availableReplicas": 2 }
+	}, {
+		"apiVersion": "v1", "kind": "ConfigMap",
+		"metadata": { "name": "config", "namespace": "default" },
+		"data": { "key": "value" }
+	}]}`)))
+	Summarize(list, func(item *unstructured.Unstructured) string { return "k8s:///" + item.GetKind() + "/" + item.GetName() })
+	t.Run("keeps the identity, labels, and creation of the objects", func(t *testing.T) {
+		assert.Equal(t, map[string]any{
+			"name": "pod-1", "namespace": "default", "labels": map[string]any{"app": "web"}, "creationTimestamp": "2025-01-01T00:00:00Z",
+		}, list.Items[0].Object["metadata"])
+		assert.Equal(t, "v1", list.Items[0].GetAPIVersion())
+		assert.Equal(t, "Pod", list.Items[0].GetKind())
+	})
+	t.Run("drops the spec and the rest of the status", func(t *testing.T) {
+		assert.NotContains(t, list.Items[0].Object, "spec")
+		assert.NotContains(t, list.Items[2].Object, "data")
+	})
+	t.Run("summarizes the status of Pods", func(t *testing.T) {
+		assert.Equal(t, map[string]any{
+			"phase": "Running", "ready": "1/2", "restarts": int64(4), "node": "node-1",
+			"conditions": map[string]any{"Ready": "False", "PodScheduled": "True"},
+		}, list.Items[0].Object["status"])
+	})
+	t.Run("summarizes the replicas of workloads", func(t *testing.T) {
+		assert.Equal(t, map[string]any{
+			"replicas": int64(3), "readyReplicas": int64(2), "availableReplicas": int64(2), "updatedReplicas": int64(0),
+		}, list.Items[1].Object["status"])
+	})
+	t.Run("omits the status of objects without status", func(t *testing.T) {
+		assert.NotContains(t, list.Items[2].Object, "status")
+	})
+	t.Run("adds the URI of the complete object", func(t *testing.T) {
+		assert.Equal(t, "k8s:///Pod/pod-1", list.Items[0].Object["uri"])
+	})
+}
@@ -0,0 +1,23 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// RenderTemplate renders the provided Go template (same syntax and functions as kubectl -o go-template) with the object.
+// For lists, the template is applied to the list object (e.g. {{range .items}}{{.metadata.name}}{{"\n"}}{{end}}).
+func RenderTemplate(obj runtime.Unstructured, template string) (string, error) {
+	printer, err := printers.NewGoTemplatePrinter([]byte(template))
+	if err != nil {
+		return "", fmt.Errorf("invalid output template: %w", err)
+	}
+	buf := new(bytes.Buffer)
+	if err = printer.PrintObj(obj, buf); err != nil {
+		return "", fmt.Errorf("failed to render output template: %w", err)
+	}
+	return buf.String(), nil
+}
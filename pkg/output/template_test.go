@@ -0,0 +1,28 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	t.Run("renders object", func(t *testing.T) {
+		ret, err := RenderTemplate(prunablePod(), `{{.metadata.namespace}}/{{.metadata.name}}: {{.status.phase}}`)
+		assert.NoError(t, err)
+		assert.Equal(t, "default/pod-1: Running", ret)
+	})
+	t.Run("renders list", func(t *testing.T) {
+		ret, err := RenderTemplate(sortablePods(), `{{range .items}}{{.metadata.name}} {{end}}`)
+		assert.NoError(t, err)
+		assert.Equal(t, "b c a ", ret)
+	})
+	t.Run("fails for invalid templates", func(t *testing.T) {
+		_, err := RenderTemplate(prunablePod(), `{{.metadata.name`)
+		assert.ErrorContains(t, err, "invalid output template")
+	})
+	t.Run("fails for templates that can't be executed", func(t *testing.T) {
+		_, err := RenderTemplate(prunablePod(), `{{index .spec.containers 5}}`)
+		assert.ErrorContains(t, err, "failed to render output template")
+	})
+}
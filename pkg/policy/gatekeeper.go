@@ -0,0 +1,75 @@
+package policy
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/crdstatus"
+)
+
+// ConstraintResources returns the Gatekeeper constraint resource types served by the cluster in the preferred version,
+// none if Gatekeeper is not installed or has no ConstraintTemplates
+func ConstraintResources(discoveryClient discovery.DiscoveryInterface) ([]schema.GroupVersionResource, error) {
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+	index := slices.IndexFunc(groups.Groups, func(group metav1.APIGroup) bool { return group.Name == ConstraintsGroup })
+	if index < 0 {
+		return nil, nil
+	}
+	gv, err := schema.ParseGroupVersion(groups.Groups[index].PreferredVersion.GroupVersion)
+	if err != nil {
+		return nil, err
+	}
+	resourceList, err := discoveryClient.ServerResourcesForGroupVersion(gv.String())
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]schema.GroupVersionResource, 0, len(resourceList.APIResources))
+	for _, apiResource := range resourceList.APIResources {
+		if slices.Contains(apiResource.Verbs, "list") && !strings.Contains(apiResource.Name, "/") {
+			ret = append(ret, gv.WithResource(apiResource.Name))
+		}
+	}
+	return ret, nil
+}
+
+// ConstraintViolations returns the violations of the Gatekeeper constraints of the provided resource type found by
+// the last audit, only the ones in the provided namespace if not empty
+func ConstraintViolations(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace string) ([]Violation, error) {
+	list, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Violation, 0)
+	for _, constraint := range list.Items {
+		enforcementAction := cmp.Or(crdstatus.NestedString(constraint.Object, "spec", "enforcementAction"), "deny")
+		violations, _, _ := unstructured.NestedSlice(constraint.Object, "status", "violations")
+		totalViolations, _, _ := unstructured.NestedInt64(constraint.Object, "status", "totalViolations")
+		for _, violation := range violations {
+			violation, _ := violation.(map[string]any)
+			if namespace != "" && crdstatus.NestedString(violation, "namespace") != namespace {
+				continue
+			}
+			ret = append(ret, Violation{
+				Engine:    EngineGatekeeper,
+				Policy:    constraint.GetKind() + "/" + constraint.GetName(),
+				Namespace: crdstatus.NestedString(violation, "namespace"),
+				Action:    cmp.Or(crdstatus.NestedString(violation, "enforcementAction"), enforcementAction),
+				Message:   crdstatus.NestedString(violation, "message"),
+				Resource:  crdstatus.NestedString(violation, "kind") + "/" + crdstatus.NestedString(violation, "name"),
+				Truncated: totalViolations > int64(len(violations)),
+			})
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,42 @@
+package policy
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Policy engines reporting the violations
+const (
+	EngineGatekeeper = "Gatekeeper"
+	EngineKyverno    = "Kyverno"
+)
+
+// ConstraintsGroup is the API group of the Gatekeeper constraints, each ConstraintTemplate generates a constraint kind in it
+const ConstraintsGroup = "constraints.gatekeeper.sh"
+
+// Policy Reports (wgpolicyk8s.io) resources, produced by the Kyverno background scans and admission reports
+var (
+	// PolicyReportGK is the GroupKind for PolicyReport resources
+	PolicyReportGK = schema.GroupKind{Group: "wgpolicyk8s.io", Kind: "PolicyReport"}
+
+	// ClusterPolicyReportGK is the GroupKind for ClusterPolicyReport resources
+	ClusterPolicyReportGK = schema.GroupKind{Group: "wgpolicyk8s.io", Kind: "ClusterPolicyReport"}
+)
+
+// ReportKinds are the kinds of the Policy Reports resources
+var ReportKinds = []string{PolicyReportGK.Kind, ClusterPolicyReportGK.Kind}
+
+// ResourceFor returns the GroupVersionResource of the provided Policy Reports kind in the preferred version served by the cluster.
+func ResourceFor(mapper meta.RESTMapper, kind string) (schema.GroupVersionResource, error) {
+	if !slices.Contains(ReportKinds, kind) {
+		return schema.GroupVersionResource{}, fmt.Errorf("unsupported Policy Reports kind %s, supported kinds are %v", kind, ReportKinds)
+	}
+	mapping, err := mapper.RESTMapping(schema.GroupKind{Group: PolicyReportGK.Group, Kind: kind})
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return mapping.Resource, nil
+}
@@ -0,0 +1,102 @@
+package policy
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+const (
+	// maxMessages and maxResources limit the distinct messages and resources reported for a policy in a namespace
+	maxMessages  = 5
+	maxResources = 10
+)
+
+// Violation is a resource violating a policy, as reported by the Gatekeeper audit or a Policy Report.
+type Violation struct {
+	Engine    string
+	Policy    string
+	Rule      string
+	Namespace string
+	// Action is the Gatekeeper enforcement action (deny, warn, dryrun) or the Policy Report result (fail, warn, error)
+	Action   string
+	Message  string
+	Resource string
+	// Truncated is set when the Gatekeeper audit reported only part of the violations of the constraint
+	Truncated bool
+}
+
+// IsOf returns true if the violation is of the provided policy, a Gatekeeper constraint is matched by its name or its kind and name
+func (v Violation) IsOf(policy string) bool {
+	return v.Policy == policy || (v.Engine == EngineGatekeeper && strings.HasSuffix(v.Policy, "/"+policy))
+}
+
+// Summary is the aggregation of the violations of a policy in a namespace.
+type Summary struct {
+	Engine string `json:"engine"`
+	// Policy is the kind and name of a Gatekeeper constraint, or the name of a Kyverno policy
+	Policy     string `json:"policy"`
+	Namespace  string `json:"namespace,omitempty"`
+	Action     string `json:"action"`
+	Violations int    `json:"violations"`
+	// Truncated is set when the Gatekeeper audit violations limit (constraintViolationsLimit) was reached
+	Truncated bool     `json:"truncated,omitempty"`
+	Rules     []string `json:"rules,omitempty"`
+	// Messages are the distinct messages explaining the violations, they usually describe what to fix
+	Messages  []string `json:"messages,omitempty"`
+	Resources []string `json:"resources,omitempty"`
+}
+
+// Aggregate aggregates the violations by engine, policy, namespace, and action, the enforced ones and
+// the policies with the most violations first
+func Aggregate(violations []Violation) []Summary {
+	summaries := make([]Summary, 0)
+	index := make(map[[4]string]int)
+	for _, violation := range violations {
+		key := [4]string{violation.Engine, violation.Policy, violation.Namespace, violation.Action}
+		i, found := index[key]
+		if !found {
+			i = len(summaries)
+			index[key] = i
+			summaries = append(summaries, Summary{Engine: violation.Engine, Policy: violation.Policy, Namespace: violation.Namespace, Action: violation.Action})
+		}
+		summary := &summaries[i]
+		summary.Violations++
+		summary.Truncated = summary.Truncated || violation.Truncated
+		summary.Rules = appendDistinct(summary.Rules, violation.Rule, -1)
+		summary.Messages = appendDistinct(summary.Messages, violation.Message, maxMessages)
+		summary.Resources = appendDistinct(summary.Resources, violation.Resource, maxResources)
+	}
+	for i := range summaries {
+		slices.Sort(summaries[i].Rules)
+	}
+	slices.SortStableFunc(summaries, func(a, b Summary) int {
+		return cmp.Or(
+			cmp.Compare(actionRank(a.Action), actionRank(b.Action)),
+			cmp.Compare(b.Violations, a.Violations),
+			cmp.Compare(a.Engine, b.Engine),
+			cmp.Compare(a.Policy, b.Policy),
+			cmp.Compare(a.Namespace, b.Namespace),
+		)
+	})
+	return summaries
+}
+
+// appendDistinct appends the value if not empty nor already present, up to limit values (no limit if negative)
+func appendDistinct(values []string, value string, limit int) []string {
+	if value == "" || slices.Contains(values, value) || (limit >= 0 && len(values) >= limit) {
+		return values
+	}
+	return append(values, value)
+}
+
+// actionRank ranks the violations rejecting the requests first, then the warnings, then the audited ones
+func actionRank(action string) int {
+	switch action {
+	case "deny", "fail", "error":
+		return 0
+	case "warn":
+		return 1
+	}
+	return 2
+}
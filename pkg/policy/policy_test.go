@@ -0,0 +1,173 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	blockNodePortGVR       = schema.GroupVersionResource{Group: ConstraintsGroup, Version: "v1beta1", Resource: "k8sblocknodeport"}
+	policyReportGVR        = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2", Resource: "policyreports"}
+	clusterPolicyReportGVR = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2", Resource: "clusterpolicyreports"}
+)
+
+type PolicyTestSuite struct {
+	suite.Suite
+}
+
+func constraint() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+		"kind":       "K8sBlockNodePort",
+		"metadata":   map[string]any{"name": "block-node-port"},
+		"spec":       map[string]any{"enforcementAction": "deny"},
+		"status": map[string]any{
+			"totalViolations": int64(3),
+			"violations": []any{
+				map[string]any{"enforcementAction": "deny", "kind": "Service", "name": "api", "namespace": "shop", "message": "User is not allowed to create service of type NodePort"},
+				map[string]any{"enforcementAction": "deny", "kind": "Service", "name": "web", "namespace": "shop", "message": "User is not allowed to create service of type NodePort"},
+			},
+		},
+	}}
+}
+
+func policyReport() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "wgpolicyk8s.io/v1alpha2",
+		"kind":       "PolicyReport",
+		"metadata":   map[string]any{"name": "a1b2c3", "namespace": "shop"},
+		"scope":      map[string]any{"apiVersion": "apps/v1", "kind": "Deployment", "name": "api", "namespace": "shop"},
+		"results": []any{
+			map[string]any{"source": "kyverno", "policy": "disallow-latest-tag", "rule": "require-image-tag", "result": "fail", "message": "An image tag is required."},
+			map[string]any{"source": "kyverno", "policy": "disallow-latest-tag", "rule": "validate-image-tag", "result": "fail", "message": "Using a mutable image tag e.g. 'latest' is not allowed."},
+			map[string]any{"source": "kyverno", "policy": "require-requests", "rule": "validate-resources", "result": "pass"},
+		},
+	}}
+}
+
+func clusterPolicyReport() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "wgpolicyk8s.io/v1alpha2",
+		"kind":       "ClusterPolicyReport",
+		"metadata":   map[string]any{"name": "cpol-require-ns-labels"},
+		"results": []any{
+			map[string]any{
+				"source": "kyverno", "policy": "require-ns-labels", "rule": "check-team", "result": "warn", "message": "The label `team` is required.",
+				"resources": []any{map[string]any{"apiVersion": "v1", "kind": "Namespace", "name": "sandbox"}},
+			},
+		},
+	}}
+}
+
+func (s *PolicyTestSuite) client(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		blockNodePortGVR:       "K8sBlockNodePortList",
+		policyReportGVR:        "PolicyReportList",
+		clusterPolicyReportGVR: "ClusterPolicyReportList",
+	}, objects...)
+}
+
+func (s *PolicyTestSuite) TestConstraintResources() {
+	s.Run("returns the constraint kinds", func() {
+		discoveryClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
+			{GroupVersion: "constraints.gatekeeper.sh/v1beta1", APIResources: []metav1.APIResource{
+				{Name: "k8sblocknodeport", Kind: "K8sBlockNodePort", Verbs: metav1.Verbs{"get", "list"}},
+				{Name: "k8sblocknodeport/status", Kind: "K8sBlockNodePort", Verbs: metav1.Verbs{"get"}},
+			}},
+			{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment", Verbs: metav1.Verbs{"list"}}}},
+		}}}
+		resources, err := ConstraintResources(discoveryClient)
+		s.Require().NoError(err)
+		s.Equal([]schema.GroupVersionResource{blockNodePortGVR}, resources)
+	})
+	s.Run("returns none when Gatekeeper is not installed", func() {
+		resources, err := ConstraintResources(&fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}})
+		s.Require().NoError(err)
+		s.Empty(resources)
+	})
+}
+
+func (s *PolicyTestSuite) TestConstraintViolations() {
+	// Gatekeeper names the constraint resources after their lowercase kind, which the fake client would not guess
+	client := s.client()
+	_, err := client.Resource(blockNodePortGVR).Create(s.T().Context(), constraint(), metav1.CreateOptions{})
+	s.Require().NoError(err)
+	s.Run("returns the audit violations", func() {
+		violations, err := ConstraintViolations(s.T().Context(), client, blockNodePortGVR, "")
+		s.Require().NoError(err)
+		s.Require().Len(violations, 2)
+		s.Equal(Violation{
+			Engine: EngineGatekeeper, Policy: "K8sBlockNodePort/block-node-port", Namespace: "shop", Action: "deny",
+			Message: "User is not allowed to create service of type NodePort", Resource: "Service/api", Truncated: true,
+		}, violations[0])
+	})
+	s.Run("filters the violations by namespace", func() {
+		violations, err := ConstraintViolations(s.T().Context(), client, blockNodePortGVR, "payments")
+		s.Require().NoError(err)
+		s.Empty(violations)
+	})
+}
+
+func (s *PolicyTestSuite) TestReportViolations() {
+	client := s.client(policyReport(), clusterPolicyReport())
+	s.Run("returns the failed results of the report scope", func() {
+		violations, err := ReportViolations(s.T().Context(), client, policyReportGVR, "shop")
+		s.Require().NoError(err)
+		s.Require().Len(violations, 2)
+		s.Equal(Violation{
+			Engine: EngineKyverno, Policy: "disallow-latest-tag", Rule: "require-image-tag", Namespace: "shop", Action: "fail",
+			Message: "An image tag is required.", Resource: "Deployment/api",
+		}, violations[0])
+	})
+	s.Run("returns the results of the listed resources", func() {
+		violations, err := ReportViolations(s.T().Context(), client, clusterPolicyReportGVR, "")
+		s.Require().NoError(err)
+		s.Require().Len(violations, 1)
+		s.Equal("Namespace/sandbox", violations[0].Resource)
+		s.Equal("warn", violations[0].Action)
+		s.Empty(violations[0].Namespace)
+	})
+}
+
+func (s *PolicyTestSuite) TestAggregate() {
+	violations := []Violation{
+		{Engine: EngineKyverno, Policy: "require-ns-labels", Rule: "check-team", Action: "warn", Message: "The label `team` is required.", Resource: "Namespace/sandbox"},
+		{Engine: EngineKyverno, Policy: "disallow-latest-tag", Rule: "validate-image-tag", Namespace: "shop", Action: "fail", Message: "latest", Resource: "Deployment/api"},
+		{Engine: EngineKyverno, Policy: "disallow-latest-tag", Rule: "require-image-tag", Namespace: "shop", Action: "fail", Message: "required", Resource: "Deployment/api"},
+		{Engine: EngineGatekeeper, Policy: "K8sRequiredLabels/must-have-owner", Namespace: "shop", Action: "deny", Message: "owner", Resource: "Deployment/api"},
+		{Engine: EngineGatekeeper, Policy: "K8sRequiredLabels/must-have-owner", Namespace: "shop", Action: "deny", Message: "owner", Resource: "Deployment/web", Truncated: true},
+	}
+	summaries := Aggregate(violations)
+	s.Require().Len(summaries, 3)
+	s.Run("aggregates by policy and namespace", func() {
+		s.Equal(Summary{
+			Engine: EngineGatekeeper, Policy: "K8sRequiredLabels/must-have-owner", Namespace: "shop", Action: "deny", Violations: 2, Truncated: true,
+			Messages: []string{"owner"}, Resources: []string{"Deployment/api", "Deployment/web"},
+		}, summaries[0])
+		s.Equal([]string{"require-image-tag", "validate-image-tag"}, summaries[1].Rules)
+		s.Equal([]string{"latest", "required"}, summaries[1].Messages)
+		s.Equal([]string{"Deployment/api"}, summaries[1].Resources)
+	})
+	s.Run("sorts the warnings last", func() {
+		s.Equal("require-ns-labels", summaries[2].Policy)
+	})
+}
+
+func (s *PolicyTestSuite) TestIsOf() {
+	s.True(Violation{Engine: EngineGatekeeper, Policy: "K8sRequiredLabels/must-have-owner"}.IsOf("must-have-owner"))
+	s.True(Violation{Engine: EngineGatekeeper, Policy: "K8sRequiredLabels/must-have-owner"}.IsOf("K8sRequiredLabels/must-have-owner"))
+	s.True(Violation{Engine: EngineKyverno, Policy: "disallow-latest-tag"}.IsOf("disallow-latest-tag"))
+	s.False(Violation{Engine: EngineKyverno, Policy: "shop/disallow-latest-tag"}.IsOf("disallow-latest-tag"))
+}
+
+func TestPolicy(t *testing.T) {
+	suite.Run(t, new(PolicyTestSuite))
+}
@@ -0,0 +1,64 @@
+package policy
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/crdstatus"
+)
+
+// violationResults are the Policy Report results reporting a violation, pass and skip are ignored
+var violationResults = []string{"fail", "error", "warn"}
+
+// ReportViolations returns the violations of the Policy Reports of the provided resource type in the namespace
+// (all namespaces if empty)
+func ReportViolations(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace string) ([]Violation, error) {
+	list, err := client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Violation, 0)
+	for _, report := range list.Items {
+		// Kyverno 1.10+ reports the results of a single resource (scope), older versions list the resources of each result
+		scope, hasScope, _ := unstructured.NestedMap(report.Object, "scope")
+		results, _, _ := unstructured.NestedSlice(report.Object, "results")
+		for _, result := range results {
+			result, _ := result.(map[string]any)
+			if !slices.Contains(violationResults, crdstatus.NestedString(result, "result")) {
+				continue
+			}
+			resources, _, _ := unstructured.NestedSlice(result, "resources")
+			if len(resources) == 0 && hasScope {
+				resources = []any{scope}
+			}
+			for _, resource := range resources {
+				resource, _ := resource.(map[string]any)
+				ret = append(ret, Violation{
+					Engine:    engine(crdstatus.NestedString(result, "source")),
+					Policy:    crdstatus.NestedString(result, "policy"),
+					Rule:      crdstatus.NestedString(result, "rule"),
+					Namespace: cmp.Or(crdstatus.NestedString(resource, "namespace"), report.GetNamespace()),
+					Action:    crdstatus.NestedString(result, "result"),
+					Message:   crdstatus.NestedString(result, "message"),
+					Resource:  crdstatus.NestedString(resource, "kind") + "/" + crdstatus.NestedString(resource, "name"),
+				})
+			}
+		}
+	}
+	return ret, nil
+}
+
+// engine returns the policy engine of the Policy Report source, other tools (e.g. Trivy, kube-bench) produce Policy Reports too
+func engine(source string) string {
+	if source == "" || strings.EqualFold(source, EngineKyverno) {
+		return EngineKyverno
+	}
+	return source
+}
@@ -0,0 +1,69 @@
+package prometheus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config holds the connection settings of the Prometheus (or Thanos querier) queried by the metrics tools.
+// Prometheus is either reached directly (Url) or through the Kubernetes API server Service proxy (Namespace and Service).
+type Config struct {
+	// Url is the base URL of Prometheus (e.g. https://thanos-querier.openshift-monitoring.svc:9091)
+	Url                  string `toml:"url,omitempty"`
+	Insecure             bool   `toml:"insecure,omitempty"`
+	CertificateAuthority string `toml:"certificate_authority,omitempty"`
+	// BearerTokenFile is the file with the token sent to Prometheus, the Kubernetes token is sent if not provided
+	BearerTokenFile string `toml:"bearer_token_file,omitempty"`
+	// Namespace and Service of Prometheus, reached through the Service proxy of the Kubernetes API server
+	Namespace string `toml:"namespace,omitempty"`
+	Service   string `toml:"service,omitempty"`
+	// Port is the name or number of the Service port (Optional, the first port of the Service if not provided)
+	Port string `toml:"port,omitempty"`
+	// Scheme is the scheme of the Service proxy requests, http (default) or https
+	Scheme string `toml:"scheme,omitempty"`
+}
+
+func (c *Config) Validate() error {
+	if c == nil {
+		return errors.New("prometheus config is nil")
+	}
+	if (c.Url == "") == (c.Service == "") {
+		return errors.New("prometheus requires either url or service")
+	}
+	if c.Url != "" {
+		if u, err := url.Parse(c.Url); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("prometheus url must be a valid URL")
+		}
+	}
+	if c.Service != "" && c.Namespace == "" {
+		return errors.New("prometheus namespace is required with service")
+	}
+	if c.Scheme != "" && c.Scheme != "http" && c.Scheme != "https" {
+		return fmt.Errorf("prometheus scheme must be http or https, got %s", c.Scheme)
+	}
+	for name, file := range map[string]string{"certificate_authority": c.CertificateAuthority, "bearer_token_file": c.BearerTokenFile} {
+		if strings.TrimSpace(file) == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("prometheus %s must be a valid file path: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ResolvePaths resolves the relative certificate_authority and bearer_token_file paths against the configuration directory
+func (c *Config) ResolvePaths(configDir string) {
+	if configDir == "" {
+		return
+	}
+	for _, file := range []*string{&c.CertificateAuthority, &c.BearerTokenFile} {
+		if *file != "" && !filepath.IsAbs(*file) {
+			*file = filepath.Join(configDir, *file)
+		}
+	}
+}
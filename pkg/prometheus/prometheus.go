@@ -0,0 +1,280 @@
+package prometheus
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// requestTimeout is the maximum duration of a Prometheus query
+const requestTimeout = 30 * time.Second
+
+// Prometheus queries the Prometheus HTTP API (https://prometheus.io/docs/prometheus/latest/querying/api/)
+type Prometheus struct {
+	config     *Config
+	restClient rest.Interface
+	restConfig *rest.Config
+}
+
+// Result is the result of a PromQL query
+type Result struct {
+	// Type is the type of the result: vector, matrix, scalar, or string
+	Type     string
+	Series   []Series
+	Warnings []string
+}
+
+// Series is a time series (or the single value of a scalar or string result) with its samples
+type Series struct {
+	Labels  map[string]string
+	Samples []Sample
+}
+
+// Sample is a value of a time series at a point in time
+type Sample struct {
+	Time  time.Time
+	Value string
+}
+
+// apiError is an error returned by the Prometheus API (e.g. bad_data for an invalid PromQL query)
+type apiError struct {
+	errorType string
+	message   string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("prometheus query failed (%s): %s", e.errorType, e.message)
+}
+
+type response struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+	ErrorType string   `json:"errorType"`
+	Error     string   `json:"error"`
+	Warnings  []string `json:"warnings"`
+}
+
+// NewPrometheus returns a Prometheus client for the provided configuration. The Kubernetes REST client is used to
+// reach Prometheus through the Service proxy, the Kubernetes bearer token to authenticate to a Prometheus URL.
+func NewPrometheus(cfg *Config, restClient rest.Interface, restConfig *rest.Config) *Prometheus {
+	return &Prometheus{config: cfg, restClient: restClient, restConfig: restConfig}
+}
+
+// Query evaluates an instant query at the provided time (now if zero)
+func (p *Prometheus) Query(ctx context.Context, query string, at time.Time) (*Result, error) {
+	params := url.Values{"query": {query}}
+	if !at.IsZero() {
+		params.Set("time", formatTime(at))
+	}
+	return p.query(ctx, "query", params)
+}
+
+// QueryRange evaluates a range query between start and end with the provided resolution step
+func (p *Prometheus) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (*Result, error) {
+	params := url.Values{
+		"query": {query},
+		"start": {formatTime(start)},
+		"end":   {formatTime(end)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}
+	return p.query(ctx, "query_range", params)
+}
+
+func (p *Prometheus) query(ctx context.Context, endpoint string, params url.Values) (*Result, error) {
+	if p.config == nil {
+		return nil, errors.New("prometheus is not configured")
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	var body []byte
+	var err error
+	if p.config.Url != "" {
+		body, err = p.getURL(ctx, endpoint, params)
+	} else {
+		body, err = p.getServiceProxy(ctx, endpoint, params)
+	}
+	// The error responses of the Prometheus API have a body with the details of the error (e.g. a PromQL syntax error)
+	result, parseErr := parseResponse(body)
+	if queryErr := (*apiError)(nil); errors.As(parseErr, &queryErr) {
+		return nil, queryErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, parseErr
+}
+
+func (p *Prometheus) getServiceProxy(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	// The Service proxy path is [scheme:]name[:port], the port is required when the scheme is provided
+	service := p.config.Service
+	if p.config.Scheme != "" {
+		service = p.config.Scheme + ":" + service + ":" + p.config.Port
+	} else if p.config.Port != "" {
+		service += ":" + p.config.Port
+	}
+	req := p.restClient.Get().AbsPath("api", "v1", "namespaces", p.config.Namespace, "services", service, "proxy", "api", "v1", endpoint)
+	for name, values := range params {
+		for _, value := range values {
+			req = req.Param(name, value)
+		}
+	}
+	return req.DoRaw(ctx)
+}
+
+func (p *Prometheus) getURL(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	apiURL, err := url.JoinPath(p.config.Url, "api", "v1", endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prometheus url: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	token, err := p.bearerToken()
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client, err := p.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prometheus response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return body, fmt.Errorf("prometheus API error: status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+func (p *Prometheus) bearerToken() (string, error) {
+	if p.config.BearerTokenFile != "" {
+		token, err := os.ReadFile(p.config.BearerTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read prometheus bearer token: %w", err)
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+	if p.restConfig == nil {
+		return "", nil
+	}
+	if p.restConfig.BearerToken == "" && p.restConfig.BearerTokenFile != "" {
+		token, err := os.ReadFile(p.restConfig.BearerTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read kubernetes bearer token: %w", err)
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+	return strings.TrimPrefix(p.restConfig.BearerToken, "Bearer "), nil
+}
+
+func (p *Prometheus) httpClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: p.config.Insecure}
+	if p.config.CertificateAuthority != "" {
+		caPEM, err := os.ReadFile(p.config.CertificateAuthority)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prometheus certificate authority: %w", err)
+		}
+		certPool, err := x509.SystemCertPool()
+		if err != nil || certPool == nil {
+			certPool = x509.NewCertPool()
+		}
+		if !certPool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("failed to parse prometheus certificate authority")
+		}
+		tlsConfig.RootCAs = certPool
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}}, nil
+}
+
+func parseResponse(body []byte) (*Result, error) {
+	var resp response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse prometheus response: %w", err)
+	}
+	if resp.Status == "error" {
+		return nil, &apiError{errorType: resp.ErrorType, message: resp.Error}
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("unexpected prometheus response status %q", resp.Status)
+	}
+	result := &Result{Type: resp.Data.ResultType, Warnings: resp.Warnings}
+	switch resp.Data.ResultType {
+	case "vector":
+		var vector []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []any             `json:"value"`
+		}
+		if err := json.Unmarshal(resp.Data.Result, &vector); err != nil {
+			return nil, fmt.Errorf("failed to parse prometheus vector: %w", err)
+		}
+		for _, series := range vector {
+			result.Series = append(result.Series, Series{Labels: series.Metric, Samples: []Sample{parseSample(series.Value)}})
+		}
+	case "matrix":
+		var matrix []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][]any           `json:"values"`
+		}
+		if err := json.Unmarshal(resp.Data.Result, &matrix); err != nil {
+			return nil, fmt.Errorf("failed to parse prometheus matrix: %w", err)
+		}
+		for _, series := range matrix {
+			samples := make([]Sample, 0, len(series.Values))
+			for _, value := range series.Values {
+				samples = append(samples, parseSample(value))
+			}
+			result.Series = append(result.Series, Series{Labels: series.Metric, Samples: samples})
+		}
+	case "scalar", "string":
+		var value []any
+		if err := json.Unmarshal(resp.Data.Result, &value); err != nil {
+			return nil, fmt.Errorf("failed to parse prometheus %s: %w", resp.Data.ResultType, err)
+		}
+		result.Series = []Series{{Labels: map[string]string{}, Samples: []Sample{parseSample(value)}}}
+	default:
+		return nil, fmt.Errorf("unsupported prometheus result type %s", resp.Data.ResultType)
+	}
+	return result, nil
+}
+
+// parseSample parses a [<unix time>, "<value>"] sample
+func parseSample(value []any) Sample {
+	sample := Sample{}
+	if len(value) != 2 {
+		return sample
+	}
+	if timestamp, ok := value[0].(float64); ok {
+		sample.Time = time.UnixMilli(int64(timestamp * 1000)).UTC()
+	}
+	sample.Value, _ = value[1].(string)
+	return sample
+}
+
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', -1, 64)
+}
@@ -0,0 +1,122 @@
+package prometheus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/client-go/rest"
+)
+
+type PrometheusTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	requests []*http.Request
+	response string
+	status   int
+}
+
+func (s *PrometheusTestSuite) SetupTest() {
+	s.requests = nil
+	s.status = http.StatusOK
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.requests = append(s.requests, req)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(s.status)
+		_, _ = w.Write([]byte(s.response))
+	}))
+}
+
+func (s *PrometheusTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *PrometheusTestSuite) client() *Prometheus {
+	return NewPrometheus(&Config{Url: s.server.URL + "/prometheus"}, nil, &rest.Config{BearerToken: "kube-token"})
+}
+
+func (s *PrometheusTestSuite) TestQuery() {
+	s.response = `{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"__name__":"up","job":"kubelet","instance":"node-1"},"value":[1735740000.5,"1"]},
+		{"metric":{"__name__":"up","job":"kubelet","instance":"node-2"},"value":[1735740000.5,"0"]}
+	]},"warnings":["query is slow"]}`
+	result, err := s.client().Query(s.T().Context(), "up", time.Unix(1735740000, 0))
+	s.Require().NoError(err)
+	s.Run("sends the query with the kubernetes bearer token", func() {
+		s.Require().Len(s.requests, 1)
+		s.Equal("/prometheus/api/v1/query", s.requests[0].URL.Path)
+		s.Equal(url.Values{"query": {"up"}, "time": {"1735740000"}}, s.requests[0].URL.Query())
+		s.Equal("Bearer kube-token", s.requests[0].Header.Get("Authorization"))
+	})
+	s.Run("parses the vector", func() {
+		s.Equal("vector", result.Type)
+		s.Equal([]string{"query is slow"}, result.Warnings)
+		s.Require().Len(result.Series, 2)
+		s.Equal(map[string]string{"__name__": "up", "job": "kubelet", "instance": "node-2"}, result.Series[1].Labels)
+		s.Equal([]Sample{{Time: time.UnixMilli(1735740000500).UTC(), Value: "0"}}, result.Series[1].Samples)
+	})
+	s.Run("renders the table with the common labels", func() {
+		s.Equal("# Common labels: __name__=up, job=kubelet\n"+
+			"instance  VALUE\n"+
+			"node-1    1\n"+
+			"node-2    0\n", Table(result))
+	})
+}
+
+func (s *PrometheusTestSuite) TestQueryRange() {
+	s.response = `{"status":"success","data":{"resultType":"matrix","result":[
+		{"metric":{"pod":"web-1"},"values":[[1735740000,"0.123456"],[1735740060,"0.5"],[1735740120,"0.25"]]}
+	]}}`
+	start := time.Unix(1735740000, 0)
+	result, err := s.client().QueryRange(s.T().Context(), "rate(x[5m])", start, start.Add(2*time.Minute), time.Minute)
+	s.Require().NoError(err)
+	s.Run("sends the range", func() {
+		s.Require().Len(s.requests, 1)
+		s.Equal("/prometheus/api/v1/query_range", s.requests[0].URL.Path)
+		s.Equal(url.Values{"query": {"rate(x[5m])"}, "start": {"1735740000"}, "end": {"1735740120"}, "step": {"60"}}, s.requests[0].URL.Query())
+	})
+	s.Run("renders the minimum, maximum, and last values", func() {
+		s.Equal("pod    MIN     MAX  LAST  VALUES\n"+
+			"web-1  0.1235  0.5  0.25  0.1235 0.5 0.25\n", Table(result))
+	})
+}
+
+func (s *PrometheusTestSuite) TestQueryError() {
+	s.status = http.StatusBadRequest
+	s.response = `{"status":"error","errorType":"bad_data","error":"1:4: parse error: unclosed left parenthesis"}`
+	_, err := s.client().Query(s.T().Context(), "sum(", time.Time{})
+	s.Require().Error(err)
+	s.Equal("prometheus query failed (bad_data): 1:4: parse error: unclosed left parenthesis", err.Error())
+	s.NotContains(s.requests[0].URL.Query(), "time")
+}
+
+func (s *PrometheusTestSuite) TestRangeValuesSampling() {
+	samples := make([]Sample, 100)
+	for i := range samples {
+		samples[i] = Sample{Value: "1"}
+	}
+	samples[99].Value = "2"
+	columns := rangeColumns(samples)
+	s.Equal([]string{"1", "2", "2"}, columns[:3])
+	s.Equal("1 1 1 1 1 1 1 1 1 1 1 1 1 1 1 1 1 1 1 2", columns[3])
+}
+
+func (s *PrometheusTestSuite) TestConfigValidate() {
+	s.Run("requires either url or service", func() {
+		s.ErrorContains((&Config{}).Validate(), "either url or service")
+		s.ErrorContains((&Config{Url: "http://prometheus:9090", Service: "prometheus", Namespace: "monitoring"}).Validate(), "either url or service")
+	})
+	s.Run("requires the namespace of the service", func() {
+		s.ErrorContains((&Config{Service: "prometheus"}).Validate(), "namespace is required")
+	})
+	s.Run("accepts a service", func() {
+		s.NoError((&Config{Service: "prometheus-k8s", Namespace: "monitoring", Port: "web", Scheme: "https"}).Validate())
+	})
+}
+
+func TestPrometheus(t *testing.T) {
+	suite.Run(t, new(PrometheusTestSuite))
+}
@@ -0,0 +1,133 @@
+package prometheus
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// maxRangeValues is the maximum number of values of a range query series shown in the table
+const maxRangeValues = 20
+
+// Table renders the result as a compact text table, one row per series. The labels shared by all the series are
+// listed once before the table. The rows of a range query show the minimum, maximum, and last value of each series
+// and up to maxRangeValues values evenly picked among its samples.
+func Table(result *Result) string {
+	sb := strings.Builder{}
+	common, columns := splitLabels(result.Series)
+	if len(common) > 0 {
+		pairs := make([]string, 0, len(common))
+		for _, name := range sortedLabelNames(common) {
+			pairs = append(pairs, name+"="+common[name])
+		}
+		sb.WriteString("# Common labels: " + strings.Join(pairs, ", ") + "\n")
+	}
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	header := slices.Clone(columns)
+	if result.Type == "matrix" {
+		header = append(header, "MIN", "MAX", "LAST", "VALUES")
+	} else {
+		header = append(header, "VALUE")
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, series := range result.Series {
+		row := make([]string, 0, len(header))
+		for _, column := range columns {
+			row = append(row, series.Labels[column])
+		}
+		if result.Type == "matrix" {
+			row = append(row, rangeColumns(series.Samples)...)
+		} else if len(series.Samples) > 0 {
+			row = append(row, formatValue(series.Samples[0].Value))
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	_ = w.Flush()
+	return sb.String()
+}
+
+// splitLabels returns the labels with the same value in all the series (when there are several) and the names of the other labels
+func splitLabels(series []Series) (map[string]string, []string) {
+	common := map[string]string{}
+	if len(series) > 1 {
+		maps.Copy(common, series[0].Labels)
+		for _, s := range series[1:] {
+			for name, value := range common {
+				if v, ok := s.Labels[name]; !ok || v != value {
+					delete(common, name)
+				}
+			}
+		}
+	}
+	names := map[string]string{}
+	for _, s := range series {
+		for name := range s.Labels {
+			if _, ok := common[name]; !ok {
+				names[name] = ""
+			}
+		}
+	}
+	return common, sortedLabelNames(names)
+}
+
+// sortedLabelNames returns the label names sorted alphabetically, with the metric name first
+func sortedLabelNames(labels map[string]string) []string {
+	return slices.SortedFunc(maps.Keys(labels), func(a, b string) int {
+		switch {
+		case a == "__name__":
+			return -1
+		case b == "__name__":
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+}
+
+func rangeColumns(samples []Sample) []string {
+	if len(samples) == 0 {
+		return []string{"", "", "", ""}
+	}
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+	for _, sample := range samples {
+		if value, err := strconv.ParseFloat(sample.Value, 64); err == nil && !math.IsNaN(value) {
+			minValue, maxValue = math.Min(minValue, value), math.Max(maxValue, value)
+		}
+	}
+	values := make([]string, 0, maxRangeValues)
+	for i := range min(len(samples), maxRangeValues) {
+		// Evenly pick the values, always including the first and the last ones
+		index := i
+		if len(samples) > maxRangeValues {
+			index = i * (len(samples) - 1) / (maxRangeValues - 1)
+		}
+		values = append(values, formatValue(samples[index].Value))
+	}
+	minMax := []string{"NaN", "NaN"}
+	if !math.IsInf(minValue, 1) {
+		minMax = []string{formatFloat(minValue), formatFloat(maxValue)}
+	}
+	return append(minMax, formatValue(samples[len(samples)-1].Value), strings.Join(values, " "))
+}
+
+// formatValue formats a sample value with at most 4 decimals (or 4 significant digits for very large or small values)
+func formatValue(value string) string {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	return formatFloat(f)
+}
+
+func formatFloat(f float64) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	if abs := math.Abs(f); abs >= 1e9 || (abs > 0 && abs < 1e-3) {
+		return strconv.FormatFloat(f, 'g', 4, 64)
+	}
+	return strconv.FormatFloat(math.Round(f*1e4)/1e4, 'f', -1, 64)
+}
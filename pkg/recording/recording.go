@@ -0,0 +1,131 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry is a single recorded tool call and its response.
+// Entries are stored as JSON Lines, one entry per line.
+type Entry struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+	// Content is the text of the response with a single content block
+	Content string `json:"content,omitempty"`
+	// Blocks are the texts of the response with several content blocks (e.g. a JSON result followed by its header)
+	Blocks []string `json:"blocks,omitempty"`
+	// StructuredContent is the structured content of the response (e.g. the machine-readable error)
+	StructuredContent any  `json:"structuredContent,omitempty"`
+	IsError           bool `json:"isError,omitempty"`
+}
+
+// TextBlocks returns the texts of the content blocks of the response, in the recorded order
+func (e *Entry) TextBlocks() []string {
+	if len(e.Blocks) > 0 {
+		return e.Blocks
+	}
+	return []string{e.Content}
+}
+
+// Key returns the lookup key for the tool call with the provided name and arguments.
+// Arguments are marshalled to JSON which sorts map keys, so the key is independent of the argument order.
+// Missing and empty arguments (which are not recorded) have the same key.
+func Key(tool string, arguments map[string]any) string {
+	if len(arguments) == 0 {
+		return tool + " {}"
+	}
+	args, _ := json.Marshal(arguments)
+	return tool + " " + string(args)
+}
+
+// Recorder appends tool calls and their responses to a file.
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording file %s: %w", path, err)
+	}
+	return &Recorder{file: file}, nil
+}
+
+func (r *Recorder) Record(entry Entry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.file.Write(append(line, '\n'))
+	return err
+}
+
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// Replayer serves recorded responses for tool calls.
+// When the same tool call was recorded several times, the responses are served in the recorded order,
+// and the last one is repeated once all of them have been served.
+type Replayer struct {
+	mu      sync.Mutex
+	entries map[string][]Entry
+	served  map[string]int
+}
+
+func NewReplayer(path string) (*Replayer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording file %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+	r := &Replayer{entries: map[string][]Entry{}, served: map[string]int{}}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse recording file %s line %d: %w", path, line, err)
+		}
+		key := Key(entry.Tool, entry.Arguments)
+		r.entries[key] = append(r.entries[key], entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording file %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// ErrNotRecorded is returned when there is no recorded response for a tool call.
+var ErrNotRecorded = errors.New("no recorded response for tool call")
+
+// Replay returns the next recorded response for the provided tool call.
+func (r *Replayer) Replay(tool string, arguments map[string]any) (*Entry, error) {
+	key := Key(tool, arguments)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := r.entries[key]
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotRecorded, key)
+	}
+	idx := min(r.served[key], len(entries)-1)
+	r.served[key]++
+	return &entries[idx], nil
+}
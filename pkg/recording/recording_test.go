@@ -0,0 +1,111 @@
+package recording
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RecordingSuite struct {
+	suite.Suite
+	path string
+}
+
+func (s *RecordingSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "recording.jsonl")
+}
+
+func (s *RecordingSuite) record(entries ...Entry) {
+	recorder, err := NewRecorder(s.path)
+	s.Require().NoError(err)
+	for _, entry := range entries {
+		s.Require().NoError(recorder.Record(entry))
+	}
+	s.Require().NoError(recorder.Close())
+}
+
+func (s *RecordingSuite) TestRecordAndReplay() {
+	s.record(
+		Entry{Tool: "pods_list", Arguments: map[string]any{"namespace": "default", "labelSelector": "app=nginx"}, Content: "first"},
+		Entry{Tool: "pods_list", Arguments: map[string]any{"namespace": "default", "labelSelector": "app=nginx"}, Content: "second"},
+		Entry{Tool: "pods_get", Arguments: map[string]any{"name": "missing"}, Content: "not found", IsError: true},
+	)
+	replayer, err := NewReplayer(s.path)
+	s.Require().NoError(err)
+	s.Run("replays recorded responses in order regardless of argument order", func() {
+		entry, err := replayer.Replay("pods_list", map[string]any{"labelSelector": "app=nginx", "namespace": "default"})
+		s.Require().NoError(err)
+		s.Equal("first", entry.Content)
+		entry, err = replayer.Replay("pods_list", map[string]any{"namespace": "default", "labelSelector": "app=nginx"})
+		s.Require().NoError(err)
+		s.Equal("second", entry.Content)
+	})
+	s.Run("repeats last response once exhausted", func() {
+		entry, err := replayer.Replay("pods_list", map[string]any{"namespace": "default", "labelSelector": "app=nginx"})
+		s.Require().NoError(err)
+		s.Equal("second", entry.Content)
+	})
+	s.Run("replays errors", func() {
+		entry, err := replayer.Replay("pods_get", map[string]any{"name": "missing"})
+		s.Require().NoError(err)
+		s.True(entry.IsError)
+		s.Equal("not found", entry.Content)
+	})
+	s.Run("returns error for calls not recorded", func() {
+		_, err := replayer.Replay("pods_get", map[string]any{"name": "other"})
+		s.ErrorIs(err, ErrNotRecorded)
+	})
+}
+
+func (s *RecordingSuite) TestReplayBlocksAndStructuredContent() {
+	s.record(
+		Entry{Tool: "pods_list", Blocks: []string{`[{"name":"a"}]`, "# 1 pod(s) found:\n"}},
+		Entry{Tool: "pods_get", Content: "not found", StructuredContent: map[string]any{"error": map[string]any{"code": "NOT_FOUND"}}, IsError: true},
+	)
+	replayer, err := NewReplayer(s.path)
+	s.Require().NoError(err)
+	s.Run("replays the content blocks in the recorded order", func() {
+		entry, err := replayer.Replay("pods_list", map[string]any{})
+		s.Require().NoError(err)
+		s.Equal([]string{`[{"name":"a"}]`, "# 1 pod(s) found:\n"}, entry.TextBlocks())
+	})
+	s.Run("replays the single content block", func() {
+		entry, err := replayer.Replay("pods_get", nil)
+		s.Require().NoError(err)
+		s.Equal([]string{"not found"}, entry.TextBlocks())
+	})
+	s.Run("replays the structured content", func() {
+		entry, err := replayer.Replay("pods_get", nil)
+		s.Require().NoError(err)
+		s.Equal(map[string]any{"error": map[string]any{"code": "NOT_FOUND"}}, entry.StructuredContent)
+	})
+}
+
+func (s *RecordingSuite) TestRecorderAppends() {
+	s.record(Entry{Tool: "namespaces_list", Content: "first"})
+	s.record(Entry{Tool: "events_list", Content: "second"})
+	replayer, err := NewReplayer(s.path)
+	s.Require().NoError(err)
+	_, err = replayer.Replay("namespaces_list", nil)
+	s.NoError(err, "expected entry from first recording session")
+	_, err = replayer.Replay("events_list", nil)
+	s.NoError(err, "expected entry from second recording session")
+}
+
+func (s *RecordingSuite) TestReplayerInvalidFile() {
+	s.Run("missing file", func() {
+		_, err := NewReplayer(filepath.Join(s.T().TempDir(), "missing.jsonl"))
+		s.ErrorContains(err, "failed to open recording file")
+	})
+	s.Run("invalid content", func() {
+		s.Require().NoError(os.WriteFile(s.path, []byte("{\"tool\":\"ok\"}\nnot json\n"), 0600))
+		_, err := NewReplayer(s.path)
+		s.ErrorContains(err, "line 2")
+	})
+}
+
+func TestRecording(t *testing.T) {
+	suite.Run(t, new(RecordingSuite))
+}
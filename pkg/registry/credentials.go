@@ -0,0 +1,109 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// Credential is a registry credential of an image pull secret.
+type Credential struct {
+	// Registry is the registry host, optionally followed by a repository path prefix
+	Registry string
+	Username string
+	Password string
+	// Source is the namespace and name of the pull secret
+	Source string
+}
+
+// Keyring is the set of registry credentials available to pull the images.
+type Keyring []Credential
+
+// dockerConfigEntry is an entry of the auths of a .dockerconfigjson (or of a legacy .dockercfg) pull secret
+type dockerConfigEntry struct {
+	Auth     string `json:"auth,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// PullSecrets returns the registry credentials of the image pull secrets (kubernetes.io/dockerconfigjson and
+// kubernetes.io/dockercfg Secrets) of the namespace
+func PullSecrets(ctx context.Context, client corev1client.SecretsGetter, namespace string) (Keyring, error) {
+	keyring := make(Keyring, 0)
+	for _, secretType := range []v1.SecretType{v1.SecretTypeDockerConfigJson, v1.SecretTypeDockercfg} {
+		secrets, err := client.Secrets(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("type", string(secretType)).String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range secrets.Items {
+			if secret.Type != secretType {
+				continue
+			}
+			entries := map[string]dockerConfigEntry{}
+			if secretType == v1.SecretTypeDockerConfigJson {
+				dockerConfig := struct {
+					Auths map[string]dockerConfigEntry `json:"auths"`
+				}{}
+				if err = json.Unmarshal(secret.Data[v1.DockerConfigJsonKey], &dockerConfig); err == nil {
+					entries = dockerConfig.Auths
+				}
+			} else {
+				err = json.Unmarshal(secret.Data[v1.DockerConfigKey], &entries)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read pull secret %s/%s: %w", secret.Namespace, secret.Name, err)
+			}
+			for registry, entry := range entries {
+				credential := Credential{
+					Registry: normalizeRegistry(registry),
+					Username: entry.Username,
+					Password: entry.Password,
+					Source:   secret.Namespace + "/" + secret.Name,
+				}
+				if decoded, err := base64.StdEncoding.DecodeString(entry.Auth); err == nil && entry.Auth != "" {
+					credential.Username, credential.Password, _ = strings.Cut(string(decoded), ":")
+				}
+				keyring = append(keyring, credential)
+			}
+		}
+	}
+	return keyring, nil
+}
+
+// Lookup returns the credential of the most specific registry (and repository path prefix) matching the reference,
+// nil if none
+func (k Keyring) Lookup(ref Reference) *Credential {
+	name := ref.Registry + "/" + ref.Repository
+	var ret *Credential
+	for i, credential := range k {
+		if name != credential.Registry && !strings.HasPrefix(name, credential.Registry+"/") {
+			continue
+		}
+		if ret == nil || len(credential.Registry) > len(ret.Registry) {
+			ret = &k[i]
+		}
+	}
+	return ret
+}
+
+// normalizeRegistry removes the scheme and the API path of the docker config registry keys
+// (e.g. https://index.docker.io/v1/ is docker.io)
+func normalizeRegistry(registry string) string {
+	registry = strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://")
+	registry = strings.TrimSuffix(registry, "/")
+	registry = strings.TrimSuffix(strings.TrimSuffix(registry, "/v1"), "/v2")
+	switch registry {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return dockerHub
+	}
+	return registry
+}
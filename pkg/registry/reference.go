@@ -0,0 +1,84 @@
+package registry
+
+import (
+	"cmp"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// dockerHub is the registry of the images without a registry host (e.g. nginx, bitnami/redis)
+const dockerHub = "docker.io"
+
+// Reference is a fully qualified container image reference.
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses a container image reference with the normalization rules of the container runtimes:
+// the registry defaults to Docker Hub (and its official images to the library repository), and the tag to latest
+func ParseReference(image string) (Reference, error) {
+	ref := Reference{Registry: dockerHub}
+	name := strings.TrimSpace(image)
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.Digest = name[:i], name[i+1:]
+		if algorithm, hex, found := strings.Cut(ref.Digest, ":"); !found || algorithm == "" || hex == "" {
+			return Reference{}, fmt.Errorf("invalid image reference %s: invalid digest %s", image, ref.Digest)
+		}
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+	ref.Repository = name
+	if i := strings.Index(name, "/"); i >= 0 && (strings.ContainsAny(name[:i], ".:") || name[:i] == "localhost") {
+		ref.Registry, ref.Repository = name[:i], name[i+1:]
+	}
+	if ref.Registry == "index.docker.io" {
+		ref.Registry = dockerHub
+	}
+	if ref.Registry == dockerHub && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Repository == "" || ref.Repository != strings.ToLower(ref.Repository) {
+		return Reference{}, fmt.Errorf("invalid image reference %s: the repository must be a non-empty lowercase name", image)
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+func (r Reference) String() string {
+	ret := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		ret += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		ret += "@" + r.Digest
+	}
+	return ret
+}
+
+// manifestReference is the digest (which takes precedence) or the tag of the manifest
+func (r Reference) manifestReference() string {
+	return cmp.Or(r.Digest, r.Tag)
+}
+
+// baseURL returns the URL of the registry API, the Docker Hub API is not served by docker.io and the loopback registries
+// are reached with plain HTTP as the container runtimes do
+func (r Reference) baseURL() string {
+	if r.Registry == dockerHub {
+		return "https://registry-1.docker.io"
+	}
+	host := r.Registry
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		return "http://" + r.Registry
+	}
+	return "https://" + r.Registry
+}
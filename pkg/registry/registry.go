@@ -0,0 +1,331 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// requestTimeout is the maximum duration of an image inspection
+	requestTimeout = 30 * time.Second
+	// maxDocumentSize is the maximum size of the manifests and image configurations read from the registry
+	maxDocumentSize = 4 << 20
+	// defaultPlatform is the platform inspected in a multi-platform image when none is provided (if available)
+	defaultPlatform = "linux/amd64"
+)
+
+// Manifest media types, the OCI ones and their Docker equivalents
+const (
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+var manifestMediaTypes = []string{mediaTypeOCIIndex, mediaTypeDockerManifestList, mediaTypeOCIManifest, mediaTypeDockerManifest}
+
+var challengeParameter = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// Image is the summary of a container image published in a registry.
+type Image struct {
+	Reference string `json:"reference"`
+	// Digest is the digest of the image the reference resolves to, the one the container runtimes pull
+	Digest    string `json:"digest"`
+	MediaType string `json:"mediaType"`
+	// Platforms are the platforms of a multi-platform image (image index or manifest list)
+	Platforms []string `json:"platforms,omitempty"`
+	// Platform is the platform of the inspected image configuration
+	Platform string `json:"platform,omitempty"`
+	// ManifestDigest is the digest of the manifest of the inspected platform of a multi-platform image
+	ManifestDigest string            `json:"manifestDigest,omitempty"`
+	Created        string            `json:"created,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	Layers         int               `json:"layers"`
+	// Size is the compressed size of the layers in bytes
+	Size int64 `json:"size"`
+	// Authentication is anonymous or the pull secret used to authenticate to the registry
+	Authentication string `json:"authentication"`
+}
+
+// Client inspects the images of the registries implementing the OCI distribution API
+// (https://github.com/opencontainers/distribution-spec/blob/main/spec.md).
+type Client struct {
+	httpClient *http.Client
+	keyring    Keyring
+}
+
+// NewClient returns a Client authenticating to the registries with the credentials of the keyring, anonymously otherwise
+func NewClient(httpClient *http.Client, keyring Keyring) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: requestTimeout}
+	}
+	return &Client{httpClient: httpClient, keyring: keyring}
+}
+
+// session is the inspection of an image, it keeps the authorization obtained from the registry for the following requests
+type session struct {
+	*Client
+	ref           Reference
+	credential    *Credential
+	authorization string
+}
+
+// RegistryError is an error returned by the registry API (e.g. MANIFEST_UNKNOWN when the tag is not published)
+type RegistryError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *RegistryError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("registry returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("registry returned %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// IsNotFound returns true if the error is a manifest (or repository) not found in the registry
+func IsNotFound(err error) bool {
+	var registryError *RegistryError
+	return errors.As(err, &registryError) && registryError.StatusCode == http.StatusNotFound
+}
+
+// Inspect resolves the image reference in its registry and returns the summary of its manifest and configuration,
+// the provided platform (e.g. linux/arm64) or linux/amd64 (or the first one) of a multi-platform image
+func (c *Client) Inspect(ctx context.Context, ref Reference, platform string) (*Image, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	s := &session{Client: c, ref: ref, credential: c.keyring.Lookup(ref)}
+	image := &Image{Reference: ref.String(), Authentication: "anonymous"}
+	if s.credential != nil {
+		image.Authentication = "pull secret " + s.credential.Source
+	}
+	body, mediaType, digest, err := s.manifest(ctx, ref.manifestReference())
+	if err != nil {
+		return nil, err
+	}
+	image.Digest, image.MediaType = digest, mediaType
+	if mediaType == mediaTypeOCIIndex || mediaType == mediaTypeDockerManifestList {
+		index := struct {
+			Manifests []struct {
+				Digest   string `json:"digest"`
+				Platform *struct {
+					OS           string `json:"os"`
+					Architecture string `json:"architecture"`
+					Variant      string `json:"variant,omitempty"`
+				} `json:"platform,omitempty"`
+			} `json:"manifests"`
+		}{}
+		if err = json.Unmarshal(body, &index); err != nil {
+			return nil, fmt.Errorf("failed to read the image index: %w", err)
+		}
+		digests := make(map[string]string)
+		for _, manifest := range index.Manifests {
+			// Attestations and signatures are stored as unknown/unknown manifests
+			if manifest.Platform == nil || manifest.Platform.OS == "unknown" {
+				continue
+			}
+			p := strings.TrimSuffix(manifest.Platform.OS+"/"+manifest.Platform.Architecture+"/"+manifest.Platform.Variant, "/")
+			image.Platforms = append(image.Platforms, p)
+			digests[p] = manifest.Digest
+		}
+		if len(image.Platforms) == 0 {
+			return image, nil
+		}
+		image.Platform = platform
+		switch {
+		case platform == "" && slices.Contains(image.Platforms, defaultPlatform):
+			image.Platform = defaultPlatform
+		case platform == "":
+			image.Platform = image.Platforms[0]
+		case digests[platform] == "":
+			return nil, fmt.Errorf("platform %s not found in image %s, available platforms are %v", platform, ref, image.Platforms)
+		}
+		image.ManifestDigest = digests[image.Platform]
+		if body, mediaType, _, err = s.manifest(ctx, image.ManifestDigest); err != nil {
+			return nil, err
+		}
+	}
+	if mediaType != mediaTypeOCIManifest && mediaType != mediaTypeDockerManifest {
+		return nil, fmt.Errorf("unsupported manifest media type %s of image %s", mediaType, ref)
+	}
+	manifest := struct {
+		Config struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+		Layers []struct {
+			Size int64 `json:"size"`
+		} `json:"layers"`
+	}{}
+	if err = json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to read the image manifest: %w", err)
+	}
+	image.Layers = len(manifest.Layers)
+	for _, layer := range manifest.Layers {
+		image.Size += layer.Size
+	}
+	if body, _, err = s.get(ctx, "blobs/"+manifest.Config.Digest, nil); err != nil {
+		return nil, err
+	}
+	config := struct {
+		Created      string `json:"created,omitempty"`
+		OS           string `json:"os"`
+		Architecture string `json:"architecture"`
+		Variant      string `json:"variant,omitempty"`
+		Config       struct {
+			Labels map[string]string `json:"Labels,omitempty"`
+		} `json:"config"`
+	}{}
+	if err = json.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("failed to read the image configuration: %w", err)
+	}
+	image.Created, image.Labels = config.Created, config.Config.Labels
+	if image.Platform == "" && config.OS != "" {
+		image.Platform = strings.TrimSuffix(config.OS+"/"+config.Architecture+"/"+config.Variant, "/")
+	}
+	return image, nil
+}
+
+// manifest returns the manifest of the provided tag or digest, with its media type and digest
+func (s *session) manifest(ctx context.Context, reference string) ([]byte, string, string, error) {
+	body, header, err := s.get(ctx, "manifests/"+reference, manifestMediaTypes)
+	if err != nil {
+		return nil, "", "", err
+	}
+	mediaType := strings.TrimSpace(strings.Split(header.Get("Content-Type"), ";")[0])
+	// Registries may omit the content type, the OCI manifests declare it
+	if !slices.Contains(manifestMediaTypes, mediaType) {
+		declared := struct {
+			MediaType string `json:"mediaType"`
+		}{}
+		_ = json.Unmarshal(body, &declared)
+		mediaType = declared.MediaType
+	}
+	digest := header.Get("Docker-Content-Digest")
+	if digest == "" {
+		sum := sha256.Sum256(body)
+		digest = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return body, mediaType, digest, nil
+}
+
+// get requests the provided path of the repository, authenticating as requested by the registry challenge
+func (s *session) get(ctx context.Context, path string, accept []string) ([]byte, http.Header, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.ref.baseURL()+"/v2/"+s.ref.Repository+"/"+path, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+		if s.authorization != "" {
+			req.Header.Set("Authorization", s.authorization)
+		}
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to reach registry %s: %w", s.ref.Registry, err)
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentSize))
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read the registry response: %w", err)
+		}
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			if s.authorization, err = s.authorize(ctx, resp.Header.Get("WWW-Authenticate")); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, nil, registryError(resp.StatusCode, body)
+		}
+		return body, resp.Header, nil
+	}
+}
+
+// authorize returns the authorization requested by the registry challenge: the credential for the Basic scheme,
+// or a token obtained from the authorization service for the Bearer scheme (anonymously if no credential)
+func (s *session) authorize(ctx context.Context, challenge string) (string, error) {
+	scheme, _, _ := strings.Cut(challenge, " ")
+	parameters := make(map[string]string)
+	for _, match := range challengeParameter.FindAllStringSubmatch(challenge, -1) {
+		parameters[strings.ToLower(match[1])] = match[2]
+	}
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if s.credential == nil {
+			return "", &RegistryError{StatusCode: http.StatusUnauthorized, Code: "UNAUTHORIZED", Message: "the registry requires credentials, provide the namespace of an image pull secret"}
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(s.credential.Username+":"+s.credential.Password)), nil
+	case "bearer":
+		realm, err := url.Parse(parameters["realm"])
+		if err != nil || realm.Host == "" {
+			return "", fmt.Errorf("invalid registry authorization challenge: %s", challenge)
+		}
+		query := realm.Query()
+		if parameters["service"] != "" {
+			query.Set("service", parameters["service"])
+		}
+		query.Set("scope", "repository:"+s.ref.Repository+":pull")
+		realm.RawQuery = query.Encode()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if s.credential != nil {
+			req.SetBasicAuth(s.credential.Username, s.credential.Password)
+		}
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to reach the authorization service of registry %s: %w", s.ref.Registry, err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentSize))
+		if err != nil {
+			return "", fmt.Errorf("failed to read the authorization service response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", registryError(resp.StatusCode, body)
+		}
+		token := struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}{}
+		if err = json.Unmarshal(body, &token); err != nil || (token.Token == "" && token.AccessToken == "") {
+			return "", errors.New("the authorization service of the registry did not return a token")
+		}
+		if token.Token != "" {
+			return "Bearer " + token.Token, nil
+		}
+		return "Bearer " + token.AccessToken, nil
+	}
+	return "", fmt.Errorf("unsupported registry authorization challenge: %s", challenge)
+}
+
+// registryError returns the first error of the registry error response
+func registryError(statusCode int, body []byte) error {
+	response := struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}{}
+	ret := &RegistryError{StatusCode: statusCode}
+	if err := json.Unmarshal(body, &response); err == nil && len(response.Errors) > 0 {
+		ret.Code, ret.Message = response.Errors[0].Code, response.Errors[0].Message
+	}
+	return ret
+}
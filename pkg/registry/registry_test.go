@@ -0,0 +1,173 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type RegistryTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+	// authorizations are the Authorization headers received by the token service
+	authorizations []string
+}
+
+func (s *RegistryTestSuite) SetupTest() {
+	s.authorizations = nil
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		s.authorizations = append(s.authorizations, r.Header.Get("Authorization"))
+		if r.URL.Query().Get("scope") != "repository:org/app:pull" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token":"pull-token"}`))
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer pull-token" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+s.server.URL+`/token",service="registry.test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/org/app/manifests/v1.2.3":
+			w.Header().Set("Content-Type", mediaTypeOCIIndex)
+			w.Header().Set("Docker-Content-Digest", "sha256:index")
+			_, _ = w.Write([]byte(`{"schemaVersion":2,"mediaType":"` + mediaTypeOCIIndex + `","manifests":[` +
+				`{"digest":"sha256:arm64","platform":{"os":"linux","architecture":"arm64","variant":"v8"}},` +
+				`{"digest":"sha256:amd64","platform":{"os":"linux","architecture":"amd64"}},` +
+				`{"digest":"sha256:attestation","platform":{"os":"unknown","architecture":"unknown"}}` +
+				`]}`))
+		case "/v2/org/app/manifests/sha256:amd64", "/v2/org/app/manifests/sha256:arm64":
+			w.Header().Set("Content-Type", mediaTypeOCIManifest)
+			_, _ = w.Write([]byte(`{"schemaVersion":2,"mediaType":"` + mediaTypeOCIManifest + `",` +
+				`"config":{"digest":"sha256:config"},"layers":[{"size":1000},{"size":234}]}`))
+		case "/v2/org/app/blobs/sha256:config":
+			_, _ = w.Write([]byte(`{"created":"2025-10-29T09:00:00Z","os":"linux","architecture":"amd64",` +
+				`"config":{"Labels":{"org.opencontainers.image.revision":"4f2a9c1"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`))
+		}
+	})
+	s.server = httptest.NewServer(mux)
+}
+
+func (s *RegistryTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *RegistryTestSuite) reference(tag string) Reference {
+	ref, err := ParseReference(strings.TrimPrefix(s.server.URL, "http://") + "/org/app:" + tag)
+	s.Require().NoError(err)
+	return ref
+}
+
+func (s *RegistryTestSuite) TestParseReference() {
+	for image, expected := range map[string]Reference{
+		"nginx":                                              {Registry: "docker.io", Repository: "library/nginx", Tag: "latest"},
+		"bitnami/redis:7.4":                                  {Registry: "docker.io", Repository: "bitnami/redis", Tag: "7.4"},
+		"index.docker.io/library/nginx:1.27":                 {Registry: "docker.io", Repository: "library/nginx", Tag: "1.27"},
+		"ghcr.io/org/app:v1.2.3":                             {Registry: "ghcr.io", Repository: "org/app", Tag: "v1.2.3"},
+		"localhost:5000/app":                                 {Registry: "localhost:5000", Repository: "app", Tag: "latest"},
+		"quay.io/org/app:v1@sha256:0123abcd":                 {Registry: "quay.io", Repository: "org/app", Tag: "v1", Digest: "sha256:0123abcd"},
+		"registry.example.com:8443/team/app@sha256:0123abcd": {Registry: "registry.example.com:8443", Repository: "team/app", Digest: "sha256:0123abcd"},
+	} {
+		s.Run(image, func() {
+			ref, err := ParseReference(image)
+			s.Require().NoError(err)
+			s.Equal(expected, ref)
+		})
+	}
+	s.Run("invalid", func() {
+		_, err := ParseReference("ghcr.io/Org/App")
+		s.ErrorContains(err, "must be a non-empty lowercase name")
+		_, err = ParseReference("nginx@sha256")
+		s.ErrorContains(err, "invalid digest")
+	})
+	s.Run("reaches Docker Hub and the loopback registries", func() {
+		s.Equal("https://registry-1.docker.io", Reference{Registry: "docker.io"}.baseURL())
+		s.Equal("http://127.0.0.1:5000", Reference{Registry: "127.0.0.1:5000"}.baseURL())
+		s.Equal("https://ghcr.io", Reference{Registry: "ghcr.io"}.baseURL())
+	})
+}
+
+func (s *RegistryTestSuite) TestPullSecrets() {
+	client := fake.NewClientset(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "dockerhub", Namespace: "shop"},
+			Type:       v1.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{v1.DockerConfigJsonKey: []byte(`{"auths":{"https://index.docker.io/v1/":{"auth":"dXNlcjpwYXNz"}}}`)},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "shop"},
+			Type:       v1.SecretTypeDockercfg,
+			Data:       map[string][]byte{v1.DockerConfigKey: []byte(`{"registry.example.com/team":{"username":"robot","password":"secret"}}`)},
+		},
+		&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "shop"}, Type: v1.SecretTypeTLS},
+	)
+	keyring, err := PullSecrets(s.T().Context(), client.CoreV1(), "shop")
+	s.Require().NoError(err)
+	s.Len(keyring, 2)
+	s.Run("decodes the auth of the docker config", func() {
+		credential := keyring.Lookup(Reference{Registry: "docker.io", Repository: "library/nginx"})
+		s.Require().NotNil(credential)
+		s.Equal(Credential{Registry: "docker.io", Username: "user", Password: "pass", Source: "shop/dockerhub"}, *credential)
+	})
+	s.Run("matches the repository path prefix", func() {
+		s.NotNil(keyring.Lookup(Reference{Registry: "registry.example.com", Repository: "team/app"}))
+		s.Nil(keyring.Lookup(Reference{Registry: "registry.example.com", Repository: "other/app"}))
+		s.Nil(keyring.Lookup(Reference{Registry: "ghcr.io", Repository: "org/app"}))
+	})
+}
+
+func (s *RegistryTestSuite) TestInspect() {
+	s.Run("returns the default platform of a multi-platform image", func() {
+		image, err := NewClient(nil, nil).Inspect(s.T().Context(), s.reference("v1.2.3"), "")
+		s.Require().NoError(err)
+		s.Equal(&Image{
+			Reference:      strings.TrimPrefix(s.server.URL, "http://") + "/org/app:v1.2.3",
+			Digest:         "sha256:index",
+			MediaType:      mediaTypeOCIIndex,
+			Platforms:      []string{"linux/arm64/v8", "linux/amd64"},
+			Platform:       "linux/amd64",
+			ManifestDigest: "sha256:amd64",
+			Created:        "2025-10-29T09:00:00Z",
+			Labels:         map[string]string{"org.opencontainers.image.revision": "4f2a9c1"},
+			Layers:         2,
+			Size:           1234,
+			Authentication: "anonymous",
+		}, image)
+	})
+	s.Run("returns the provided platform", func() {
+		image, err := NewClient(nil, nil).Inspect(s.T().Context(), s.reference("v1.2.3"), "linux/arm64/v8")
+		s.Require().NoError(err)
+		s.Equal("sha256:arm64", image.ManifestDigest)
+		_, err = NewClient(nil, nil).Inspect(s.T().Context(), s.reference("v1.2.3"), "windows/amd64")
+		s.ErrorContains(err, "platform windows/amd64 not found in image")
+	})
+	s.Run("authenticates with the pull secret", func() {
+		ref := s.reference("v1.2.3")
+		keyring := Keyring{{Registry: ref.Registry, Username: "user", Password: "pass", Source: "shop/registry"}}
+		image, err := NewClient(nil, keyring).Inspect(s.T().Context(), ref, "")
+		s.Require().NoError(err)
+		s.Equal("pull secret shop/registry", image.Authentication)
+		s.Contains(s.authorizations, "Basic dXNlcjpwYXNz")
+	})
+	s.Run("returns not found for the tags not published", func() {
+		_, err := NewClient(nil, nil).Inspect(s.T().Context(), s.reference("v9.9.9"), "")
+		s.True(IsNotFound(err))
+		s.EqualError(err, "registry returned 404 MANIFEST_UNKNOWN: manifest unknown")
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	suite.Run(t, new(RegistryTestSuite))
+}
@@ -0,0 +1,239 @@
+// Package sessionbudget accounts the resources the tools hold on behalf of each MCP session beyond the handling of a
+// request (buffered log bytes, background goroutines), and caps them per session.
+// Beyond the limit of a resource, the most expensive subscriptions of the session holding it are torn down first.
+package sessionbudget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+// The resources accounted per session
+const (
+	LogBytes   = "log_bytes"
+	Goroutines = "goroutines"
+)
+
+// Resources are the accounted resources, in the order they're reported
+var Resources = []string{LogBytes, Goroutines}
+
+// ErrLimitExceeded is returned when a subscription can't be held within the limit of the session
+var ErrLimitExceeded = api.NewToolError(api.ErrorCodeRateLimited, errors.New("session limit exceeded"))
+
+// Budget accounts the subscriptions of the sessions
+type Budget struct {
+	mu sync.Mutex
+	// limit returns the limit of the resource per session, 0 is unlimited
+	limit func(resource string) int64
+	// sessions are the subscriptions of the sessions by session ID, the oldest first
+	sessions map[string][]*Subscription
+}
+
+// Subscription is an amount of a resource held by a session until released, or torn down (cancel) by the budget
+type Subscription struct {
+	budget   *Budget
+	session  string
+	resource string
+	amount   int64
+	// cancel tears down the subscription, the subscriptions without it can't be evicted
+	cancel func()
+	// evicted is set once the subscription is torn down by the budget
+	evicted bool
+}
+
+// New returns a budget capping each resource to its limit per session (0 is unlimited)
+func New(limit func(resource string) int64) *Budget {
+	return &Budget{limit: limit, sessions: make(map[string][]*Subscription)}
+}
+
+// Acquire holds the amount of the resource for the session until the subscription is released.
+// Beyond the limit of the resource, the more expensive subscriptions of the session (the oldest first among the equally
+// expensive ones) are torn down with their cancel function, the acquisition fails with ErrLimitExceeded if the new
+// subscription is the most expensive one.
+func (b *Budget) Acquire(session, resource string, amount int64, cancel func()) (*Subscription, error) {
+	subscription := &Subscription{budget: b, session: session, resource: resource, cancel: cancel}
+	if err := subscription.Add(amount); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// Add grows the amount held by the subscription, evicting the more expensive subscriptions of the session beyond the
+// limit of the resource (see Acquire). The amount is unchanged if it fails with ErrLimitExceeded.
+func (s *Subscription) Add(amount int64) error {
+	if s == nil || s.budget == nil {
+		return nil
+	}
+	b := s.budget
+	b.mu.Lock()
+	if s.evicted {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: torn down as the most expensive %s of the session beyond its limit", ErrLimitExceeded, s.resource)
+	}
+	subscriptions := slices.Clone(b.sessions[s.session])
+	if !slices.Contains(subscriptions, s) {
+		subscriptions = append(subscriptions, s)
+	}
+	s.amount += amount
+	var evicted []*Subscription
+	limit := b.limit(s.resource)
+	for limit > 0 && usage(subscriptions, s.resource) > limit {
+		costliest := costliest(subscriptions, s)
+		if costliest == nil {
+			s.amount -= amount
+			b.mu.Unlock()
+			return fmt.Errorf("%w: the session reached its limit of %d %s", ErrLimitExceeded, limit, s.resource)
+		}
+		subscriptions = slices.DeleteFunc(subscriptions, func(item *Subscription) bool { return item == costliest })
+		evicted = append(evicted, costliest)
+	}
+	for _, subscription := range evicted {
+		subscription.evicted = true
+	}
+	b.sessions[s.session] = subscriptions
+	b.mu.Unlock()
+	// the subscriptions are torn down outside the lock, their cancel functions might release other subscriptions
+	for _, subscription := range evicted {
+		subscription.cancel()
+	}
+	return nil
+}
+
+// Evicted returns true if the subscription was torn down by the budget
+func (s *Subscription) Evicted() bool {
+	if s == nil || s.budget == nil {
+		return false
+	}
+	s.budget.mu.Lock()
+	defer s.budget.mu.Unlock()
+	return s.evicted
+}
+
+// Release stops accounting the subscription
+func (s *Subscription) Release() {
+	if s == nil || s.budget == nil {
+		return
+	}
+	b := s.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subscriptions := slices.DeleteFunc(b.sessions[s.session], func(item *Subscription) bool { return item == s })
+	if len(subscriptions) == 0 {
+		delete(b.sessions, s.session)
+	} else {
+		b.sessions[s.session] = subscriptions
+	}
+}
+
+// Usage returns the amount of each resource held by the session
+func (b *Budget) Usage(session string) map[string]int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ret := make(map[string]int64, len(Resources))
+	for _, resource := range Resources {
+		ret[resource] = usage(b.sessions[session], resource)
+	}
+	return ret
+}
+
+// EndSession tears down the subscriptions of the ended session
+func (b *Budget) EndSession(session string) {
+	b.mu.Lock()
+	subscriptions := b.sessions[session]
+	delete(b.sessions, session)
+	b.mu.Unlock()
+	for _, subscription := range subscriptions {
+		if subscription.cancel != nil {
+			subscription.cancel()
+		}
+	}
+}
+
+// usage returns the amount of the resource held by the subscriptions
+func usage(subscriptions []*Subscription, resource string) int64 {
+	ret := int64(0)
+	for _, subscription := range subscriptions {
+		if subscription.resource == resource {
+			ret += subscription.amount
+		}
+	}
+	return ret
+}
+
+// costliest returns the most expensive subscription of the resource of the growing subscription that can be evicted
+// (the oldest first among the equally expensive ones), nil if it's the growing subscription itself
+func costliest(subscriptions []*Subscription, growing *Subscription) *Subscription {
+	var ret *Subscription
+	for _, subscription := range subscriptions {
+		if subscription.resource != growing.resource || (subscription.cancel == nil && subscription != growing) {
+			continue
+		}
+		if ret == nil || subscription.amount > ret.amount {
+			ret = subscription
+		}
+	}
+	if ret == growing {
+		return nil
+	}
+	return ret
+}
+
+type contextKey struct{}
+
+// sessionBudget is the budget of a session
+type sessionBudget struct {
+	budget  *Budget
+	session string
+}
+
+// NewContext returns a context carrying the budget of the session, acquired by Acquire and ReadAll
+func NewContext(ctx context.Context, budget *Budget, session string) context.Context {
+	return context.WithValue(ctx, contextKey{}, sessionBudget{budget: budget, session: session})
+}
+
+// Acquire holds the amount of the resource for the session of the context (see Budget.Acquire), the subscription is
+// not accounted if the context carries no budget
+func Acquire(ctx context.Context, resource string, amount int64, cancel func()) (*Subscription, error) {
+	budget, ok := ctx.Value(contextKey{}).(sessionBudget)
+	if !ok || budget.budget == nil {
+		return &Subscription{}, nil
+	}
+	return budget.budget.Acquire(budget.session, resource, amount, cancel)
+}
+
+// ReadAll reads the log stream, accounting the buffered bytes in the log bytes of the session of the context while
+// they're read. The read fails with ErrLimitExceeded if the log is the most expensive one beyond the limit of the
+// session, or once it's torn down as the most expensive one by another log of the session.
+func ReadAll(ctx context.Context, stream io.ReadCloser) ([]byte, error) {
+	subscription, err := Acquire(ctx, LogBytes, 0, func() { _ = stream.Close() })
+	if err != nil {
+		return nil, err
+	}
+	defer subscription.Release()
+	var ret []byte
+	buffer := make([]byte, 32*1024)
+	for {
+		n, err := stream.Read(buffer)
+		if n > 0 {
+			if err := subscription.Add(int64(n)); err != nil {
+				return nil, err
+			}
+			ret = append(ret, buffer[:n]...)
+		}
+		switch {
+		case subscription.Evicted():
+			// the read of the closed stream fails
+			return nil, subscription.Add(0)
+		case errors.Is(err, io.EOF):
+			return ret, nil
+		case err != nil:
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package sessionbudget
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+)
+
+type SessionBudgetSuite struct {
+	suite.Suite
+	limits map[string]int64
+	budget *Budget
+}
+
+func (s *SessionBudgetSuite) SetupTest() {
+	s.limits = map[string]int64{}
+	s.budget = New(func(resource string) int64 { return s.limits[resource] })
+}
+
+func (s *SessionBudgetSuite) TestAcquire() {
+	s.limits[Goroutines] = 2
+	first, err := s.budget.Acquire("a", Goroutines, 1, func() {})
+	s.Require().NoError(err)
+	_, err = s.budget.Acquire("a", Goroutines, 1, func() {})
+	s.Require().NoError(err)
+	s.Run("accounts the subscriptions of the session", func() {
+		s.Equal(int64(2), s.budget.Usage("a")[Goroutines])
+		s.Equal(int64(0), s.budget.Usage("a")[LogBytes])
+	})
+	s.Run("isolates the sessions", func() {
+		_, err := s.budget.Acquire("b", Goroutines, 2, func() {})
+		s.NoError(err)
+		s.Equal(int64(2), s.budget.Usage("b")[Goroutines])
+	})
+	s.Run("releases the subscriptions", func() {
+		first.Release()
+		s.Equal(int64(1), s.budget.Usage("a")[Goroutines])
+	})
+}
+
+func (s *SessionBudgetSuite) TestUnlimited() {
+	_, err := s.budget.Acquire("a", LogBytes, 1000, nil)
+	s.NoError(err)
+	s.Equal(int64(1000), s.budget.Usage("a")[LogBytes])
+}
+
+func (s *SessionBudgetSuite) TestEviction() {
+	s.limits[LogBytes] = 100
+	var torndown []string
+	small, err := s.budget.Acquire("a", LogBytes, 20, func() { torndown = append(torndown, "small") })
+	s.Require().NoError(err)
+	large, err := s.budget.Acquire("a", LogBytes, 60, func() { torndown = append(torndown, "large") })
+	s.Require().NoError(err)
+	s.Run("tears down the most expensive subscription beyond the limit", func() {
+		_, err := s.budget.Acquire("a", LogBytes, 30, nil)
+		s.NoError(err)
+		s.Equal([]string{"large"}, torndown)
+		s.Equal(int64(50), s.budget.Usage("a")[LogBytes])
+	})
+	s.Run("rejects the subscription if it's the most expensive one", func() {
+		_, err := s.budget.Acquire("a", LogBytes, 80, func() {})
+		s.ErrorIs(err, ErrLimitExceeded)
+		s.EqualError(err, "session limit exceeded: the session reached its limit of 100 log_bytes")
+		s.Equal(api.ErrorCodeRateLimited, api.ClassifyError(err).Code)
+		s.Equal([]string{"large"}, torndown)
+		s.Equal(int64(50), s.budget.Usage("a")[LogBytes])
+	})
+	s.Run("rejects the growth of a torn down subscription", func() {
+		s.True(large.Evicted())
+		s.False(small.Evicted())
+		s.ErrorIs(large.Add(1), ErrLimitExceeded)
+		s.Equal(int64(50), s.budget.Usage("a")[LogBytes])
+	})
+}
+
+func (s *SessionBudgetSuite) TestEvictionOldestFirst() {
+	s.limits[Goroutines] = 2
+	var torndown []string
+	for _, name := range []string{"first", "second", "third"} {
+		_, err := s.budget.Acquire("a", Goroutines, 1, func() { torndown = append(torndown, name) })
+		s.Require().NoError(err)
+	}
+	s.Equal([]string{"first"}, torndown)
+	s.Equal(int64(2), s.budget.Usage("a")[Goroutines])
+}
+
+func (s *SessionBudgetSuite) TestEndSession() {
+	torndown := 0
+	_, err := s.budget.Acquire("a", Goroutines, 1, func() { torndown++ })
+	s.Require().NoError(err)
+	s.budget.EndSession("a")
+	s.Equal(1, torndown)
+	s.Equal(int64(0), s.budget.Usage("a")[Goroutines])
+}
+
+func (s *SessionBudgetSuite) TestReadAll() {
+	s.limits[LogBytes] = 16
+	ctx := NewContext(context.Background(), s.budget, "a")
+	s.Run("reads the log within the limit", func() {
+		log, err := ReadAll(ctx, io.NopCloser(strings.NewReader("line 1\nline 2\n")))
+		s.NoError(err)
+		s.Equal("line 1\nline 2\n", string(log))
+		s.Equal(int64(0), s.budget.Usage("a")[LogBytes], "the bytes are released once read")
+	})
+	s.Run("rejects the log beyond the limit", func() {
+		_, err := ReadAll(ctx, io.NopCloser(strings.NewReader("line 1\nline 2\nline 3\n")))
+		s.ErrorIs(err, ErrLimitExceeded)
+	})
+	s.Run("doesn't account the logs without budget", func() {
+		log, err := ReadAll(context.Background(), io.NopCloser(strings.NewReader("line 1\nline 2\nline 3\n")))
+		s.NoError(err)
+		s.Len(log, 21)
+	})
+}
+
+func TestSessionBudget(t *testing.T) {
+	suite.Run(t, new(SessionBudgetSuite))
+}
@@ -0,0 +1,263 @@
+package sops
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	ageIntro             = "age-encryption.org/v1"
+	ageArmorHeader       = "-----BEGIN AGE ENCRYPTED FILE-----"
+	ageArmorFooter       = "-----END AGE ENCRYPTED FILE-----"
+	ageX25519Label       = "age-encryption.org/v1/X25519"
+	ageSecretKeyHRP      = "age-secret-key-"
+	ageStanzaColumns     = 64
+	agePayloadNonceSize  = 16
+	agePayloadChunkSize  = 64 * 1024
+	bech32Charset        = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32ChecksumLength = 6
+)
+
+// ageIdentity is an age X25519 identity (AGE-SECRET-KEY-1...)
+type ageIdentity struct {
+	key *ecdh.PrivateKey
+}
+
+// parseAgeIdentity decodes the Bech32 AGE-SECRET-KEY-1... identity
+func parseAgeIdentity(identity string) (*ageIdentity, error) {
+	hrp, data, err := bech32Decode(identity)
+	if err != nil {
+		return nil, fmt.Errorf("invalid age identity: %w", err)
+	}
+	if hrp != ageSecretKeyHRP {
+		return nil, fmt.Errorf("invalid age identity: unexpected type %s", hrp)
+	}
+	key, err := ecdh.X25519().NewPrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid age identity: %w", err)
+	}
+	return &ageIdentity{key: key}, nil
+}
+
+// ageDecrypt decrypts the (armored) age file with the first of the identities matching one of its X25519 recipients
+func ageDecrypt(file string, identities []*ageIdentity) ([]byte, error) {
+	data := []byte(file)
+	if trimmed := strings.TrimSpace(file); strings.HasPrefix(trimmed, ageArmorHeader) {
+		body, found := strings.CutSuffix(strings.TrimPrefix(trimmed, ageArmorHeader), ageArmorFooter)
+		if !found {
+			return nil, errors.New("invalid armored age file")
+		}
+		var err error
+		if data, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), "")); err != nil {
+			return nil, fmt.Errorf("invalid armored age file: %w", err)
+		}
+	}
+	header, payload, stanzas, mac, err := parseAgeHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, stanza := range stanzas {
+		if len(stanza.args) != 2 || stanza.args[0] != "X25519" {
+			continue
+		}
+		for _, identity := range identities {
+			fileKey, err := identity.unwrap(stanza)
+			if err != nil {
+				continue
+			}
+			headerKey, err := hkdf.Key(sha256.New, fileKey, nil, "header", 32)
+			if err != nil {
+				return nil, err
+			}
+			headerMAC := hmac.New(sha256.New, headerKey)
+			headerMAC.Write(header)
+			if !hmac.Equal(headerMAC.Sum(nil), mac) {
+				return nil, errors.New("invalid age file: bad header MAC")
+			}
+			return ageDecryptPayload(fileKey, payload)
+		}
+	}
+	return nil, errors.New("no age identity matches the recipients")
+}
+
+type ageStanza struct {
+	args []string
+	body []byte
+}
+
+// parseAgeHeader returns the header up to the MAC (which it authenticates), the payload, the recipient stanzas, and the MAC
+func parseAgeHeader(data []byte) (header, payload []byte, stanzas []ageStanza, mac []byte, err error) {
+	rest := data
+	nextLine := func() (string, bool) {
+		line, after, found := bytes.Cut(rest, []byte("\n"))
+		rest = after
+		return string(line), found
+	}
+	if line, _ := nextLine(); line != ageIntro {
+		return nil, nil, nil, nil, errors.New("invalid age file: unsupported version")
+	}
+	for {
+		line, found := nextLine()
+		if !found {
+			return nil, nil, nil, nil, errors.New("invalid age file: truncated header")
+		}
+		if encodedMAC, isMAC := strings.CutPrefix(line, "--- "); isMAC {
+			header = data[:len(data)-len(rest)-len(line)-1+len("---")]
+			if mac, err = base64.RawStdEncoding.DecodeString(encodedMAC); err != nil {
+				return nil, nil, nil, nil, fmt.Errorf("invalid age file: %w", err)
+			}
+			return header, rest, stanzas, mac, nil
+		}
+		args, isStanza := strings.CutPrefix(line, "-> ")
+		if !isStanza {
+			return nil, nil, nil, nil, errors.New("invalid age file: malformed header")
+		}
+		stanza := ageStanza{args: strings.Fields(args)}
+		// the body is wrapped at 64 columns, the last line is shorter (possibly empty)
+		for {
+			bodyLine, found := nextLine()
+			if !found {
+				return nil, nil, nil, nil, errors.New("invalid age file: truncated stanza")
+			}
+			decoded, err := base64.RawStdEncoding.DecodeString(bodyLine)
+			if err != nil {
+				return nil, nil, nil, nil, fmt.Errorf("invalid age file: %w", err)
+			}
+			stanza.body = append(stanza.body, decoded...)
+			if len(bodyLine) < ageStanzaColumns {
+				break
+			}
+		}
+		stanzas = append(stanzas, stanza)
+	}
+}
+
+// unwrap returns the file key of the X25519 stanza if the identity is its recipient
+func (i *ageIdentity) unwrap(stanza ageStanza) ([]byte, error) {
+	share, err := base64.RawStdEncoding.DecodeString(stanza.args[1])
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(share)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := i.key.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	salt := append(append([]byte{}, share...), i.key.PublicKey().Bytes()...)
+	wrappingKey, err := hkdf.Key(sha256.New, shared, salt, ageX25519Label, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.New(wrappingKey)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, make([]byte, chacha20poly1305.NonceSize), stanza.body, nil)
+}
+
+// ageDecryptPayload decrypts the STREAM payload, chunks of 64 KiB sealed with a counter nonce flagging the last one
+func ageDecryptPayload(fileKey, payload []byte) ([]byte, error) {
+	if len(payload) < agePayloadNonceSize {
+		return nil, errors.New("invalid age file: truncated payload")
+	}
+	payloadKey, err := hkdf.Key(sha256.New, fileKey, payload[:agePayloadNonceSize], "payload", chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.New(payloadKey)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := payload[agePayloadNonceSize:]
+	var plaintext []byte
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	for counter := 0; ; counter++ {
+		chunk := ciphertext[:min(len(ciphertext), agePayloadChunkSize+aead.Overhead())]
+		ciphertext = ciphertext[len(chunk):]
+		for i, n := len(nonce)-2, counter; i >= 0; i, n = i-1, n>>8 {
+			nonce[i] = byte(n)
+		}
+		if len(ciphertext) == 0 {
+			nonce[len(nonce)-1] = 1
+		}
+		decrypted, err := aead.Open(nil, nonce, chunk, nil)
+		if err != nil {
+			return nil, errors.New("invalid age file: payload authentication failed")
+		}
+		plaintext = append(plaintext, decrypted...)
+		if len(ciphertext) == 0 {
+			return plaintext, nil
+		}
+	}
+}
+
+// bech32Decode decodes the Bech32 string (case-insensitive) into its human-readable part and data
+func bech32Decode(s string) (string, []byte, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	separator := strings.LastIndexByte(s, '1')
+	if separator < 1 || separator+bech32ChecksumLength+1 > len(s) {
+		return "", nil, errors.New("malformed bech32 string")
+	}
+	hrp := s[:separator]
+	values := make([]byte, 0, len(s)-separator-1)
+	for _, c := range s[separator+1:] {
+		value := strings.IndexRune(bech32Charset, c)
+		if value < 0 {
+			return "", nil, fmt.Errorf("invalid bech32 character %q", c)
+		}
+		values = append(values, byte(value))
+	}
+	checked := make([]byte, 0, len(hrp)*2+1+len(values))
+	for _, c := range hrp {
+		checked = append(checked, byte(c>>5))
+	}
+	checked = append(checked, 0)
+	for _, c := range hrp {
+		checked = append(checked, byte(c&31))
+	}
+	if bech32Polymod(append(checked, values...)) != 1 {
+		return "", nil, errors.New("invalid bech32 checksum")
+	}
+	// regroup the 5-bit values into bytes, the remaining bits are padding
+	var data []byte
+	accumulator, bits := 0, 0
+	for _, value := range values[:len(values)-bech32ChecksumLength] {
+		accumulator = accumulator<<5 | int(value)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			data = append(data, byte(accumulator>>bits))
+		}
+	}
+	if bits >= 5 || accumulator&(1<<bits-1) != 0 {
+		return "", nil, errors.New("invalid bech32 padding")
+	}
+	return hrp, data, nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	checksum := uint32(1)
+	for _, value := range values {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ uint32(value)
+		for i := range generator {
+			if (top>>i)&1 == 1 {
+				checksum ^= generator[i]
+			}
+		}
+	}
+	return checksum
+}
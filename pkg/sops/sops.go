@@ -0,0 +1,205 @@
+package sops
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MetadataKey is the key of the SOPS metadata in the encrypted documents
+const MetadataKey = "sops"
+
+// encryptedValue matches the values encrypted by SOPS
+var encryptedValue = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.*),iv:(.*),tag:(.*),type:(.*)]$`)
+
+// Config holds the keys decrypting the SOPS-encrypted documents
+type Config struct {
+	// AgeKeyFile is the file with the age identities, one AGE-SECRET-KEY-1... per line
+	// (Optional, SOPS_AGE_KEY_FILE and SOPS_AGE_KEY are used if not provided)
+	AgeKeyFile string `toml:"age_key_file,omitempty"`
+}
+
+func (c *Config) Validate() error {
+	if c == nil {
+		return errors.New("sops config is nil")
+	}
+	if c.AgeKeyFile != "" {
+		if _, err := c.identities(); err != nil {
+			return fmt.Errorf("sops age_key_file is invalid: %w", err)
+		}
+	}
+	return nil
+}
+
+// ResolvePaths resolves the relative age_key_file path against the configuration directory
+func (c *Config) ResolvePaths(configDir string) {
+	if configDir != "" && c.AgeKeyFile != "" && !filepath.IsAbs(c.AgeKeyFile) {
+		c.AgeKeyFile = filepath.Join(configDir, c.AgeKeyFile)
+	}
+}
+
+// IsEncrypted returns true if the document has the SOPS metadata
+func IsEncrypted(document map[string]any) bool {
+	_, ok := document[MetadataKey].(map[string]any)
+	return ok
+}
+
+// Decrypt decrypts in memory the values of the SOPS-encrypted document and returns it without the SOPS metadata.
+// The data key is decrypted with the configured age identities, the other key services (KMS, PGP, Vault) are not supported.
+// Each value is authenticated by AES-GCM with its path, the MAC of the whole document is not verified.
+func (c *Config) Decrypt(document map[string]any) (map[string]any, error) {
+	if c == nil {
+		c = &Config{}
+	}
+	metadata, ok := document[MetadataKey].(map[string]any)
+	if !ok {
+		return nil, errors.New("missing SOPS metadata")
+	}
+	dataKey, err := c.dataKey(metadata)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	// SOPS uses 32 bytes IVs
+	aead, err := cipher.NewGCMWithNonceSize(block, 32)
+	if err != nil {
+		return nil, err
+	}
+	decrypted := make(map[string]any, len(document))
+	for key, value := range document {
+		if key == MetadataKey {
+			continue
+		}
+		if decrypted[key], err = decryptValue(aead, value, []string{key}); err != nil {
+			return nil, err
+		}
+	}
+	return decrypted, nil
+}
+
+// dataKey decrypts the data key of the document with the first age recipient matching the identities
+func (c *Config) dataKey(metadata map[string]any) ([]byte, error) {
+	recipients, _ := metadata["age"].([]any)
+	if len(recipients) == 0 {
+		var services []string
+		for _, service := range []string{"kms", "gcp_kms", "azure_kv", "hc_vault", "pgp"} {
+			if entries, _ := metadata[service].([]any); len(entries) > 0 {
+				services = append(services, service)
+			}
+		}
+		return nil, fmt.Errorf("encrypted with %s keys, only age keys are supported", strings.Join(services, ", "))
+	}
+	identities, err := c.identities()
+	if err != nil {
+		return nil, err
+	}
+	if len(identities) == 0 {
+		return nil, errors.New("no age key configured to decrypt the data key")
+	}
+	for _, recipient := range recipients {
+		recipient, _ := recipient.(map[string]any)
+		enc, _ := recipient["enc"].(string)
+		dataKey, err := ageDecrypt(enc, identities)
+		if err == nil && len(dataKey) == 32 {
+			return dataKey, nil
+		}
+	}
+	return nil, errors.New("none of the configured age keys is a recipient of the data key")
+}
+
+// identities returns the age identities of the configured key file, and of the SOPS_AGE_KEY_FILE and SOPS_AGE_KEY variables
+func (c *Config) identities() ([]*ageIdentity, error) {
+	var keys []byte
+	for _, file := range []string{c.AgeKeyFile, os.Getenv("SOPS_AGE_KEY_FILE")} {
+		if file == "" {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(append(keys, data...), '\n')
+	}
+	keys = append(keys, os.Getenv("SOPS_AGE_KEY")...)
+	var identities []*ageIdentity
+	scanner := bufio.NewScanner(bytes.NewReader(keys))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		identity, err := parseAgeIdentity(line)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	return identities, scanner.Err()
+}
+
+// decryptValue decrypts the encrypted strings of the value, authenticated with the path of their keys (the list indexes are not part of the path)
+func decryptValue(aead cipher.AEAD, value any, path []string) (any, error) {
+	switch value := value.(type) {
+	case map[string]any:
+		decrypted := make(map[string]any, len(value))
+		for key, item := range value {
+			var err error
+			if decrypted[key], err = decryptValue(aead, item, append(slices.Clip(path), key)); err != nil {
+				return nil, err
+			}
+		}
+		return decrypted, nil
+	case []any:
+		decrypted := make([]any, len(value))
+		for i, item := range value {
+			var err error
+			if decrypted[i], err = decryptValue(aead, item, path); err != nil {
+				return nil, err
+			}
+		}
+		return decrypted, nil
+	case string:
+		match := encryptedValue.FindStringSubmatch(value)
+		if match == nil {
+			return value, nil
+		}
+		var parts [3][]byte
+		for i := range parts {
+			var err error
+			if parts[i], err = base64.StdEncoding.DecodeString(match[i+1]); err != nil {
+				return nil, fmt.Errorf("invalid encrypted value of %s: %w", strings.Join(path, "."), err)
+			}
+		}
+		data, iv, tag := parts[0], parts[1], parts[2]
+		if len(iv) != aead.NonceSize() {
+			return nil, fmt.Errorf("invalid encrypted value of %s: unexpected IV size", strings.Join(path, "."))
+		}
+		plaintext, err := aead.Open(nil, iv, append(data, tag...), []byte(strings.Join(path, ":")+":"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt the value of %s: %w", strings.Join(path, "."), err)
+		}
+		switch match[4] {
+		case "int":
+			return strconv.Atoi(string(plaintext))
+		case "float":
+			return strconv.ParseFloat(string(plaintext), 64)
+		case "bool":
+			return strconv.ParseBool(string(plaintext))
+		}
+		return string(plaintext), nil
+	}
+	return value, nil
+}
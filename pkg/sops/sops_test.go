@@ -0,0 +1,171 @@
+package sops
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+type SopsTestSuite struct {
+	suite.Suite
+	identity string
+	key      *ecdh.PrivateKey
+	dataKey  []byte
+}
+
+func (s *SopsTestSuite) SetupTest() {
+	s.T().Setenv("SOPS_AGE_KEY_FILE", "")
+	s.T().Setenv("SOPS_AGE_KEY", "")
+	var err error
+	s.key, err = ecdh.X25519().GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	s.identity = strings.ToUpper(bech32Encode(ageSecretKeyHRP, s.key.Bytes()))
+	s.dataKey = make([]byte, 32)
+	_, _ = rand.Read(s.dataKey)
+}
+
+// bech32Encode is the inverse of bech32Decode, to generate the identities
+func bech32Encode(hrp string, data []byte) string {
+	var values []byte
+	accumulator, bits := 0, 0
+	for _, b := range data {
+		accumulator = accumulator<<8 | int(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			values = append(values, byte(accumulator>>bits)&31)
+		}
+	}
+	if bits > 0 {
+		values = append(values, byte(accumulator<<(5-bits))&31)
+	}
+	checked := []byte{}
+	for _, c := range hrp {
+		checked = append(checked, byte(c>>5))
+	}
+	checked = append(checked, 0)
+	for _, c := range hrp {
+		checked = append(checked, byte(c&31))
+	}
+	polymod := bech32Polymod(append(append(checked, values...), 0, 0, 0, 0, 0, 0)) ^ 1
+	for i := 0; i < bech32ChecksumLength; i++ {
+		values = append(values, byte(polymod>>(5*(5-i)))&31)
+	}
+	encoded := hrp + "1"
+	for _, value := range values {
+		encoded += string(bech32Charset[value])
+	}
+	return encoded
+}
+
+// ageEncrypt encrypts the plaintext for the recipient into an armored age file
+func (s *SopsTestSuite) ageEncrypt(recipient *ecdh.PublicKey, plaintext []byte) string {
+	fileKey := make([]byte, 16)
+	_, _ = rand.Read(fileKey)
+	ephemeral, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	shared, _ := ephemeral.ECDH(recipient)
+	share := ephemeral.PublicKey().Bytes()
+	wrappingKey, _ := hkdf.Key(sha256.New, shared, append(append([]byte{}, share...), recipient.Bytes()...), ageX25519Label, 32)
+	wrapping, _ := chacha20poly1305.New(wrappingKey)
+	header := fmt.Sprintf("%s\n-> X25519 %s\n%s\n---", ageIntro, base64.RawStdEncoding.EncodeToString(share),
+		base64.RawStdEncoding.EncodeToString(wrapping.Seal(nil, make([]byte, 12), fileKey, nil)))
+	headerKey, _ := hkdf.Key(sha256.New, fileKey, nil, "header", 32)
+	mac := hmac.New(sha256.New, headerKey)
+	mac.Write([]byte(header))
+	nonce := make([]byte, agePayloadNonceSize)
+	_, _ = rand.Read(nonce)
+	payloadKey, _ := hkdf.Key(sha256.New, fileKey, nonce, "payload", 32)
+	payload, _ := chacha20poly1305.New(payloadKey)
+	file := header + " " + base64.RawStdEncoding.EncodeToString(mac.Sum(nil)) + "\n" +
+		string(nonce) + string(payload.Seal(nil, append(make([]byte, 11), 1), plaintext, nil))
+	return ageArmorHeader + "\n" + base64.StdEncoding.EncodeToString([]byte(file)) + "\n" + ageArmorFooter + "\n"
+}
+
+// encrypt encrypts the value as SOPS does for the path
+func (s *SopsTestSuite) encrypt(value, valueType string, path ...string) string {
+	block, _ := aes.NewCipher(s.dataKey)
+	aead, _ := cipher.NewGCMWithNonceSize(block, 32)
+	iv := make([]byte, 32)
+	_, _ = rand.Read(iv)
+	sealed := aead.Seal(nil, iv, []byte(value), []byte(strings.Join(path, ":")+":"))
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:%s]", base64.StdEncoding.EncodeToString(sealed[:len(sealed)-16]),
+		base64.StdEncoding.EncodeToString(iv), base64.StdEncoding.EncodeToString(sealed[len(sealed)-16:]), valueType)
+}
+
+func (s *SopsTestSuite) document() map[string]any {
+	return map[string]any{
+		"database": map[string]any{
+			"password": s.encrypt("s3cr3t", "str", "database", "password"),
+			"port":     s.encrypt("5432", "int", "database", "port"),
+			"tls":      s.encrypt("True", "bool", "database", "tls"),
+			"hosts":    []any{s.encrypt("db-0", "str", "database", "hosts")},
+		},
+		"replicas_unencrypted": float64(3),
+		MetadataKey: map[string]any{
+			"age": []any{map[string]any{"recipient": "age1...", "enc": s.ageEncrypt(s.key.PublicKey(), s.dataKey)}},
+		},
+	}
+}
+
+func (s *SopsTestSuite) TestBech32Decode() {
+	// BIP-173 test vector
+	hrp, data, err := bech32Decode("abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw")
+	s.Require().NoError(err)
+	s.Equal("abcdef", hrp)
+	s.Equal("00443214c74254b635cf84653a56d7c675be77df", hex.EncodeToString(data))
+	_, _, err = bech32Decode("abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxx")
+	s.EqualError(err, "invalid bech32 checksum")
+}
+
+func (s *SopsTestSuite) TestDecrypt() {
+	s.Run("decrypts the values with the configured age key", func() {
+		keyFile := filepath.Join(s.T().TempDir(), "keys.txt")
+		s.Require().NoError(os.WriteFile(keyFile, []byte("# created: 2026-01-01\n# public key: age1...\n"+s.identity+"\n"), 0o600))
+		values, err := (&Config{AgeKeyFile: keyFile}).Decrypt(s.document())
+		s.Require().NoError(err)
+		s.Equal(map[string]any{
+			"database":             map[string]any{"password": "s3cr3t", "port": 5432, "tls": true, "hosts": []any{"db-0"}},
+			"replicas_unencrypted": float64(3),
+		}, values)
+	})
+	s.Run("decrypts the values with the SOPS_AGE_KEY key", func() {
+		s.T().Setenv("SOPS_AGE_KEY", s.identity)
+		_, err := (*Config)(nil).Decrypt(s.document())
+		s.NoError(err)
+	})
+	s.Run("fails with other age keys", func() {
+		other, _ := ecdh.X25519().GenerateKey(rand.Reader)
+		s.T().Setenv("SOPS_AGE_KEY", bech32Encode(ageSecretKeyHRP, other.Bytes()))
+		_, err := (&Config{}).Decrypt(s.document())
+		s.EqualError(err, "none of the configured age keys is a recipient of the data key")
+	})
+	s.Run("fails on the values moved to another path", func() {
+		s.T().Setenv("SOPS_AGE_KEY", s.identity)
+		document := s.document()
+		document["password"] = document["database"].(map[string]any)["password"]
+		_, err := (&Config{}).Decrypt(document)
+		s.ErrorContains(err, "failed to decrypt the value of password")
+	})
+	s.Run("fails on the documents encrypted with KMS", func() {
+		_, err := (&Config{}).Decrypt(map[string]any{MetadataKey: map[string]any{"kms": []any{map[string]any{"arn": "arn:aws:kms:..."}}}})
+		s.EqualError(err, "encrypted with kms keys, only age keys are supported")
+	})
+}
+
+func TestSops(t *testing.T) {
+	suite.Run(t, new(SopsTestSuite))
+}
@@ -0,0 +1,39 @@
+package tekton
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Tekton resources
+var (
+	// PipelineRunGK is the GroupKind for PipelineRun resources
+	PipelineRunGK = schema.GroupKind{Group: "tekton.dev", Kind: "PipelineRun"}
+
+	// TaskRunGK is the GroupKind for TaskRun resources
+	TaskRunGK = schema.GroupKind{Group: "tekton.dev", Kind: "TaskRun"}
+)
+
+// Kinds are the kinds of the Tekton resources supported by the tekton toolset
+var Kinds = []string{PipelineRunGK.Kind, TaskRunGK.Kind}
+
+// ResourceFor returns the GroupVersionResource of the provided Tekton kind in the preferred version served by the cluster
+// (tekton.dev/v1, or v1beta1 for the Tekton Pipelines versions older than 0.44).
+func ResourceFor(mapper meta.RESTMapper, kind string) (schema.GroupVersionResource, error) {
+	var groupKind schema.GroupKind
+	switch kind {
+	case PipelineRunGK.Kind:
+		groupKind = PipelineRunGK
+	case TaskRunGK.Kind:
+		groupKind = TaskRunGK
+	default:
+		return schema.GroupVersionResource{}, fmt.Errorf("unsupported Tekton kind %s, supported kinds are %v", kind, Kinds)
+	}
+	mapping, err := mapper.RESTMapping(groupKind)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return mapping.Resource, nil
+}
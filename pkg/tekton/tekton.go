@@ -0,0 +1,271 @@
+package tekton
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/crdstatus"
+	"github.com/containers/kubernetes-mcp-server/pkg/sessionbudget"
+)
+
+const (
+	// PipelineLabel is set by the Tekton controller on the PipelineRuns and TaskRuns of a Pipeline
+	PipelineLabel = "tekton.dev/pipeline"
+	// PipelineRunLabel is set by the Tekton controller on the TaskRuns of a PipelineRun
+	PipelineRunLabel = "tekton.dev/pipelineRun"
+	// PipelineTaskLabel is set by the Tekton controller on the TaskRuns with the name of the task in the Pipeline
+	PipelineTaskLabel = "tekton.dev/pipelineTask"
+)
+
+// Run statuses, derived from the Succeeded condition
+const (
+	StatusSucceeded = "Succeeded"
+	StatusFailed    = "Failed"
+	StatusCancelled = "Cancelled"
+	StatusRunning   = "Running"
+	StatusPending   = "Pending"
+)
+
+// rerunSuffix matches the suffix of the names generated for the reruns, stripped when a rerun is rerun
+var rerunSuffix = regexp.MustCompile(`-r-[a-z0-9]{5}$`)
+
+// Run is the status and duration summary of a Tekton PipelineRun or TaskRun.
+type Run struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Pipeline is the referenced Pipeline (or "embedded" for the inline specs) of a PipelineRun, or the Pipeline of the PipelineRun of a TaskRun
+	Pipeline string `json:"pipeline,omitempty"`
+	// PipelineRun and PipelineTask are the PipelineRun and the task in the Pipeline that created a TaskRun
+	PipelineRun  string `json:"pipelineRun,omitempty"`
+	PipelineTask string `json:"pipelineTask,omitempty"`
+	// Task is the referenced Task (or "embedded" for the inline specs) of a TaskRun
+	Task           string `json:"task,omitempty"`
+	Status         string `json:"status"`
+	Reason         string `json:"reason,omitempty"`
+	Message        string `json:"message,omitempty"`
+	StartTime      string `json:"startTime,omitempty"`
+	CompletionTime string `json:"completionTime,omitempty"`
+	// Duration is the duration of the run, or the time elapsed since it started if still running
+	Duration string `json:"duration,omitempty"`
+	// FailedSteps are the steps of a TaskRun that terminated with a non-zero exit code
+	FailedSteps []string `json:"failedSteps,omitempty"`
+}
+
+// StepLog is the log of a step of a TaskRun.
+type StepLog struct {
+	TaskRun   string `json:"taskRun"`
+	Step      string `json:"step"`
+	Container string `json:"container"`
+	// ExitCode and Reason are the termination state of the step container, if terminated
+	ExitCode *int64 `json:"exitCode,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Log      string `json:"log"`
+}
+
+// ListRuns lists the Tekton runs of the provided GroupVersionResource in the namespace (all namespaces if empty) matching
+// the label selector, the most recent first
+func ListRuns(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace, labelSelector string, now time.Time) ([]Run, error) {
+	list, err := client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+	items := list.Items
+	slices.SortStableFunc(items, func(a, b unstructured.Unstructured) int {
+		return b.GetCreationTimestamp().Compare(a.GetCreationTimestamp().Time)
+	})
+	runs := make([]Run, 0, len(items))
+	for _, item := range items {
+		runs = append(runs, Summarize(&item, now))
+	}
+	return runs, nil
+}
+
+// Summarize returns the status and duration summary of the provided PipelineRun or TaskRun
+func Summarize(obj *unstructured.Unstructured, now time.Time) Run {
+	run := Run{Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName(), Status: StatusPending}
+	labels := obj.GetLabels()
+	if obj.GetKind() == PipelineRunGK.Kind {
+		run.Pipeline = reference(obj, "pipelineRef", "pipelineSpec", labels[PipelineLabel])
+	} else {
+		run.Pipeline = labels[PipelineLabel]
+		run.PipelineRun = labels[PipelineRunLabel]
+		run.PipelineTask = labels[PipelineTaskLabel]
+		run.Task = reference(obj, "taskRef", "taskSpec", labels["tekton.dev/task"])
+	}
+	if succeeded, found := crdstatus.FindCondition(obj.Object, "Succeeded"); found {
+		run.Reason = succeeded.Reason
+		run.Status = runStatus(succeeded.Status, run.Reason)
+		if run.Status != StatusSucceeded {
+			run.Message = succeeded.Message
+		}
+	}
+	run.StartTime, _, _ = unstructured.NestedString(obj.Object, "status", "startTime")
+	run.CompletionTime, _, _ = unstructured.NestedString(obj.Object, "status", "completionTime")
+	if start, err := time.Parse(time.RFC3339, run.StartTime); err == nil {
+		end := now
+		if completion, err := time.Parse(time.RFC3339, run.CompletionTime); err == nil {
+			end = completion
+		}
+		run.Duration = end.Sub(start).Round(time.Second).String()
+	}
+	steps, _, _ := unstructured.NestedSlice(obj.Object, "status", "steps")
+	for _, step := range steps {
+		step, _ := step.(map[string]any)
+		if exitCode, found, _ := unstructured.NestedInt64(step, "terminated", "exitCode"); found && exitCode != 0 {
+			run.FailedSteps = append(run.FailedSteps, fmt.Sprint(step["name"]))
+		}
+	}
+	return run
+}
+
+// TaskRuns returns the TaskRuns of the provided PipelineRun
+func TaskRuns(ctx context.Context, client dynamic.Interface, taskRunGVR schema.GroupVersionResource, namespace, pipelineRun string) ([]unstructured.Unstructured, error) {
+	list, err := client.Resource(taskRunGVR).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: PipelineRunLabel + "=" + pipelineRun})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(list.Items, func(a, b unstructured.Unstructured) int {
+		return a.GetCreationTimestamp().Compare(b.GetCreationTimestamp().Time)
+	})
+	return list.Items, nil
+}
+
+// StepLogs returns the logs of the steps of the TaskRun (only of the provided step if not empty), limited to the last tail lines
+func StepLogs(ctx context.Context, pods corev1client.PodsGetter, taskRun *unstructured.Unstructured, step string, tail int64) ([]StepLog, error) {
+	podName, _, _ := unstructured.NestedString(taskRun.Object, "status", "podName")
+	if podName == "" {
+		return nil, fmt.Errorf("TaskRun %s has no Pod yet", taskRun.GetName())
+	}
+	steps, _, _ := unstructured.NestedSlice(taskRun.Object, "status", "steps")
+	logs := make([]StepLog, 0, len(steps))
+	for _, s := range steps {
+		s, _ := s.(map[string]any)
+		stepLog := StepLog{TaskRun: taskRun.GetName()}
+		stepLog.Step, _ = s["name"].(string)
+		if step != "" && stepLog.Step != step {
+			continue
+		}
+		stepLog.Container, _ = s["container"].(string)
+		if stepLog.Container == "" {
+			stepLog.Container = "step-" + stepLog.Step
+		}
+		if exitCode, found, _ := unstructured.NestedInt64(s, "terminated", "exitCode"); found {
+			stepLog.ExitCode = &exitCode
+			stepLog.Reason, _, _ = unstructured.NestedString(s, "terminated", "reason")
+		}
+		log, err := podLog(ctx, pods, taskRun.GetNamespace(), podName, stepLog.Container, tail)
+		if err != nil {
+			// The Pods of the completed TaskRuns might have been pruned
+			stepLog.Log = fmt.Sprintf("failed to get log: %v", err)
+		} else {
+			stepLog.Log = log
+		}
+		logs = append(logs, stepLog)
+	}
+	if step != "" && len(logs) == 0 {
+		return nil, fmt.Errorf("TaskRun %s has no step %s", taskRun.GetName(), step)
+	}
+	return logs, nil
+}
+
+func podLog(ctx context.Context, pods corev1client.PodsGetter, namespace, name, container string, tail int64) (string, error) {
+	options := &v1.PodLogOptions{Container: container}
+	if tail > 0 {
+		options.TailLines = &tail
+	}
+	stream, err := pods.Pods(namespace).GetLogs(name, options).Stream(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = stream.Close() }()
+	log, err := sessionbudget.ReadAll(ctx, stream)
+	return string(log), err
+}
+
+// Rerun creates a new PipelineRun with the spec, labels, and annotations of the provided completed PipelineRun
+func Rerun(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	pipelineRun, err := client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if status := Summarize(pipelineRun, time.Now()).Status; status == StatusRunning || status == StatusPending {
+		return nil, fmt.Errorf("PipelineRun %s/%s is %s, only completed PipelineRuns can be rerun", namespace, name, strings.ToLower(status))
+	}
+	return client.Resource(gvr).Namespace(namespace).Create(ctx, rerunPipelineRun(pipelineRun), metav1.CreateOptions{})
+}
+
+// rerunPipelineRun returns a copy of the PipelineRun without its status and the metadata managed by the Tekton controllers
+func rerunPipelineRun(pipelineRun *unstructured.Unstructured) *unstructured.Unstructured {
+	spec, _, _ := unstructured.NestedMap(pipelineRun.Object, "spec")
+	// The status field of the spec cancels or holds the run (e.g. Cancelled, PipelineRunPending)
+	delete(spec, "status")
+	rerun := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	rerun.SetAPIVersion(pipelineRun.GetAPIVersion())
+	rerun.SetKind(pipelineRun.GetKind())
+	rerun.SetNamespace(pipelineRun.GetNamespace())
+	rerun.SetGenerateName(rerunSuffix.ReplaceAllString(pipelineRun.GetName(), "") + "-r-")
+	rerun.SetLabels(withoutManagedKeys(pipelineRun.GetLabels()))
+	rerun.SetAnnotations(withoutManagedKeys(pipelineRun.GetAnnotations()))
+	return rerun
+}
+
+// withoutManagedKeys returns the labels or annotations without the ones set by the Tekton controllers (Pipelines, Chains, and Results) and kubectl
+func withoutManagedKeys(values map[string]string) map[string]string {
+	ret := make(map[string]string, len(values))
+	for key, value := range values {
+		if strings.HasPrefix(key, "tekton.dev/") || strings.HasPrefix(key, "chains.tekton.dev/") ||
+			strings.HasPrefix(key, "results.tekton.dev/") || strings.HasPrefix(key, "kubectl.kubernetes.io/") {
+			continue
+		}
+		ret[key] = value
+	}
+	if len(ret) == 0 {
+		return nil
+	}
+	return ret
+}
+
+// reference returns the name of the Pipeline or Task run (labelled by the controller for the remote ones), "remote", or "embedded"
+func reference(obj *unstructured.Unstructured, ref, spec, label string) string {
+	if name, _, _ := unstructured.NestedString(obj.Object, "spec", ref, "name"); name != "" {
+		return name
+	}
+	if label != "" {
+		return label
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", ref, "resolver"); found {
+		return "remote"
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", spec); found {
+		return "embedded"
+	}
+	return ""
+}
+
+func runStatus(succeeded, reason string) string {
+	switch succeeded {
+	case string(metav1.ConditionTrue):
+		return StatusSucceeded
+	case string(metav1.ConditionFalse):
+		if strings.Contains(reason, "Cancelled") || strings.HasPrefix(reason, "Stopped") {
+			return StatusCancelled
+		}
+		return StatusFailed
+	}
+	if strings.Contains(reason, "Pending") {
+		return StatusPending
+	}
+	return StatusRunning
+}
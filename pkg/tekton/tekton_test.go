@@ -0,0 +1,193 @@
+package tekton
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	pipelineRunGVR = schema.GroupVersionResource{Group: "tekton.dev", Version: "v1", Resource: "pipelineruns"}
+	taskRunGVR     = schema.GroupVersionResource{Group: "tekton.dev", Version: "v1", Resource: "taskruns"}
+)
+
+type TektonTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func (s *TektonTestSuite) SetupTest() {
+	s.now = time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+}
+
+func run(kind, name, created, succeeded, reason string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "tekton.dev/v1",
+		"kind":       kind,
+		"metadata": map[string]any{
+			"name":              name,
+			"namespace":         "ci",
+			"creationTimestamp": created,
+			"labels":            map[string]any{PipelineLabel: "build", "team": "payments"},
+			"annotations":       map[string]any{"kubectl.kubernetes.io/last-applied-configuration": "{}", "owner": "payments"},
+		},
+		"spec": map[string]any{"pipelineRef": map[string]any{"name": "build"}, "params": []any{map[string]any{"name": "revision", "value": "main"}}},
+		"status": map[string]any{
+			"startTime":  created,
+			"conditions": []any{map[string]any{"type": "Succeeded", "status": succeeded, "reason": reason, "message": reason + " message"}},
+		},
+	}}
+	if succeeded != "Unknown" {
+		_ = unstructured.SetNestedField(obj.Object, "2025-01-01T14:05:30Z", "status", "completionTime")
+	}
+	return obj
+}
+
+func taskRun(name, pipelineRun, created string, exitCode int64) *unstructured.Unstructured {
+	obj := run("TaskRun", name, created, "True", "Succeeded")
+	obj.SetLabels(map[string]string{PipelineRunLabel: pipelineRun, PipelineTaskLabel: name})
+	_ = unstructured.SetNestedField(obj.Object, map[string]any{"taskRef": map[string]any{"name": "golang-build"}}, "spec")
+	_ = unstructured.SetNestedField(obj.Object, name+"-pod", "status", "podName")
+	_ = unstructured.SetNestedSlice(obj.Object, []any{
+		map[string]any{"name": "clone", "container": "step-clone", "terminated": map[string]any{"exitCode": int64(0), "reason": "Completed"}},
+		map[string]any{"name": "build", "terminated": map[string]any{"exitCode": exitCode, "reason": "Error"}},
+	}, "status", "steps")
+	return obj
+}
+
+func (s *TektonTestSuite) client(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		pipelineRunGVR: "PipelineRunList",
+		taskRunGVR:     "TaskRunList",
+	}, objects...)
+}
+
+func (s *TektonTestSuite) TestSummarize() {
+	s.Run("completed run", func() {
+		summary := Summarize(run("PipelineRun", "build-1", "2025-01-01T14:00:00Z", "False", "Failed"), s.now)
+		s.Equal(StatusFailed, summary.Status)
+		s.Equal("build", summary.Pipeline)
+		s.Equal("Failed message", summary.Message)
+		s.Equal("5m30s", summary.Duration)
+	})
+	s.Run("running run", func() {
+		summary := Summarize(run("PipelineRun", "build-2", "2025-01-01T14:50:00Z", "Unknown", "Running"), s.now)
+		s.Equal(StatusRunning, summary.Status)
+		s.Equal("10m0s", summary.Duration, "the duration of a running run is the time elapsed since it started")
+	})
+	s.Run("cancelled run", func() {
+		s.Equal(StatusCancelled, Summarize(run("PipelineRun", "build-3", "2025-01-01T14:00:00Z", "False", "Cancelled"), s.now).Status)
+		s.Equal(StatusCancelled, Summarize(run("PipelineRun", "build-3", "2025-01-01T14:00:00Z", "False", "StoppedRunFinally"), s.now).Status)
+	})
+	s.Run("pending run", func() {
+		summary := Summarize(&unstructured.Unstructured{Object: map[string]any{"kind": "PipelineRun", "metadata": map[string]any{"name": "build-4"}}}, s.now)
+		s.Equal(StatusPending, summary.Status)
+		s.Empty(summary.Duration)
+	})
+	s.Run("TaskRun with a failed step", func() {
+		summary := Summarize(taskRun("compile", "build-1", "2025-01-01T14:01:00Z", 2), s.now)
+		s.Equal("build-1", summary.PipelineRun)
+		s.Equal("compile", summary.PipelineTask)
+		s.Equal("golang-build", summary.Task)
+		s.Equal([]string{"build"}, summary.FailedSteps)
+	})
+	s.Run("embedded and remote references", func() {
+		obj := run("PipelineRun", "build-5", "2025-01-01T14:00:00Z", "True", "Succeeded")
+		obj.SetLabels(nil)
+		_ = unstructured.SetNestedField(obj.Object, map[string]any{"pipelineSpec": map[string]any{}}, "spec")
+		s.Equal("embedded", Summarize(obj, s.now).Pipeline)
+		_ = unstructured.SetNestedField(obj.Object, map[string]any{"pipelineRef": map[string]any{"resolver": "git"}}, "spec")
+		s.Equal("remote", Summarize(obj, s.now).Pipeline)
+	})
+}
+
+func (s *TektonTestSuite) TestListRuns() {
+	runs, err := ListRuns(s.T().Context(), s.client(
+		run("PipelineRun", "build-1", "2025-01-01T14:00:00Z", "False", "Failed"),
+		run("PipelineRun", "build-2", "2025-01-01T14:50:00Z", "Unknown", "Running"),
+	), pipelineRunGVR, "ci", "", s.now)
+	s.Require().NoError(err)
+	s.Require().Len(runs, 2)
+	s.Equal("build-2", runs[0].Name, "the most recent run is listed first")
+	s.Equal("build-1", runs[1].Name)
+}
+
+func (s *TektonTestSuite) TestTaskRuns() {
+	taskRuns, err := TaskRuns(s.T().Context(), s.client(
+		taskRun("test", "build-1", "2025-01-01T14:03:00Z", 0),
+		taskRun("compile", "build-1", "2025-01-01T14:01:00Z", 0),
+		taskRun("compile-other", "build-2", "2025-01-01T14:01:00Z", 0),
+	), taskRunGVR, "ci", "build-1")
+	s.Require().NoError(err)
+	s.Require().Len(taskRuns, 2)
+	s.Equal("compile", taskRuns[0].GetName(), "the TaskRuns are returned in the order they were created")
+	s.Equal("test", taskRuns[1].GetName())
+}
+
+func (s *TektonTestSuite) TestStepLogs() {
+	pods := fake.NewClientset(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "compile-pod", Namespace: "ci"}}).CoreV1()
+	s.Run("returns the logs of all the steps", func() {
+		logs, err := StepLogs(s.T().Context(), pods, taskRun("compile", "build-1", "2025-01-01T14:01:00Z", 2), "", 10)
+		s.Require().NoError(err)
+		s.Require().Len(logs, 2)
+		s.Equal("step-clone", logs[0].Container)
+		s.Equal("step-build", logs[1].Container, "the container name defaults to the step name prefixed by step-")
+		s.Equal(int64(2), *logs[1].ExitCode)
+		s.Equal("Error", logs[1].Reason)
+		s.Equal("fake logs", logs[1].Log)
+	})
+	s.Run("returns the logs of the provided step", func() {
+		logs, err := StepLogs(s.T().Context(), pods, taskRun("compile", "build-1", "2025-01-01T14:01:00Z", 2), "build", 10)
+		s.Require().NoError(err)
+		s.Require().Len(logs, 1)
+		s.Equal("build", logs[0].Step)
+	})
+	s.Run("fails for a missing step", func() {
+		_, err := StepLogs(s.T().Context(), pods, taskRun("compile", "build-1", "2025-01-01T14:01:00Z", 2), "deploy", 10)
+		s.EqualError(err, "TaskRun compile has no step deploy")
+	})
+	s.Run("fails for a TaskRun without Pod", func() {
+		_, err := StepLogs(s.T().Context(), pods, run("TaskRun", "pending", "2025-01-01T14:01:00Z", "Unknown", "Pending"), "", 10)
+		s.EqualError(err, "TaskRun pending has no Pod yet")
+	})
+}
+
+func (s *TektonTestSuite) TestRerun() {
+	s.Run("creates a copy of a completed PipelineRun", func() {
+		client := s.client(run("PipelineRun", "build-1-r-x7k2p", "2025-01-01T14:00:00Z", "False", "Failed"))
+		rerun, err := Rerun(s.T().Context(), client, pipelineRunGVR, "ci", "build-1-r-x7k2p")
+		s.Require().NoError(err)
+		s.Equal("build-1-r-", rerun.GetGenerateName(), "the suffix of a previous rerun is not repeated")
+		s.Equal(map[string]string{"team": "payments"}, rerun.GetLabels())
+		s.Equal(map[string]string{"owner": "payments"}, rerun.GetAnnotations())
+		s.Equal("build", Summarize(rerun, s.now).Pipeline)
+		_, found, _ := unstructured.NestedFieldNoCopy(rerun.Object, "status")
+		s.False(found)
+	})
+	s.Run("removes the status of the spec", func() {
+		pipelineRun := run("PipelineRun", "build-1", "2025-01-01T14:00:00Z", "False", "Cancelled")
+		_ = unstructured.SetNestedField(pipelineRun.Object, "Cancelled", "spec", "status")
+		rerun := rerunPipelineRun(pipelineRun)
+		_, found, _ := unstructured.NestedFieldNoCopy(rerun.Object, "spec", "status")
+		s.False(found)
+		_, found, _ = unstructured.NestedFieldNoCopy(pipelineRun.Object, "spec", "status")
+		s.True(found, "the original PipelineRun is not modified")
+	})
+	s.Run("refuses a running PipelineRun", func() {
+		client := s.client(run("PipelineRun", "build-2", "2025-01-01T14:50:00Z", "Unknown", "Running"))
+		_, err := Rerun(s.T().Context(), client, pipelineRunGVR, "ci", "build-2")
+		s.EqualError(err, "PipelineRun ci/build-2 is running, only completed PipelineRuns can be rerun")
+	})
+}
+
+func TestTekton(t *testing.T) {
+	suite.Run(t, new(TektonTestSuite))
+}
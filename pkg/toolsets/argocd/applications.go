@@ -0,0 +1,185 @@
+package argocd
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/argocd"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+func initApplications() []api.ServerTool {
+	return []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name: "argocd_applications_list",
+				Description: "List the Argo CD Applications with their source, destination, sync status, health status, and last operation. " +
+					"Degraded and out of sync Applications are listed first",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace to list the Applications from (Optional, all namespaces if not provided)",
+						},
+					},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Argo CD: Applications List",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler: applicationsList,
+		},
+		{
+			Tool: api.Tool{
+				Name: "argocd_application_diff",
+				Description: "Get the diff summary of an Argo CD Application as computed by the Argo CD application controller: " +
+					"the resources that are out of sync with the desired state in Git (including the ones that require pruning) and the unhealthy resources",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace of the Application (Optional, default: " + argocd.DefaultNamespace + ")",
+						},
+						"name": {
+							Type:        "string",
+							Description: "Name of the Application",
+						},
+					},
+					Required: []string{"name"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Argo CD: Application Diff",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler: applicationDiff,
+		},
+		{
+			Tool: api.Tool{
+				Name: "argocd_application_sync",
+				Description: "Trigger the sync of an Argo CD Application to its target revision (or to the provided revision). " +
+					"The resources of the Application are updated in the destination cluster, and deleted when prune is true. " +
+					"Fails if another operation is already in progress for the Application",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace of the Application (Optional, default: " + argocd.DefaultNamespace + ")",
+						},
+						"name": {
+							Type:        "string",
+							Description: "Name of the Application",
+						},
+						"revision": {
+							Type:        "string",
+							Description: "Revision (commit, tag, branch, or chart version) to sync to (Optional, the target revision of the Application if not provided)",
+						},
+						"prune": {
+							Type:        "boolean",
+							Description: "Delete the resources that are no longer defined in Git (Optional)",
+							Default:     api.ToRawMessage(false),
+						},
+					},
+					Required: []string{"name"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Argo CD: Application Sync",
+					ReadOnlyHint:    ptr.To(false),
+					DestructiveHint: ptr.To(true),
+					IdempotentHint:  ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler:     applicationSync,
+			Permissions: applicationSyncPermissions,
+		},
+	}
+}
+
+func applicationsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace := api.OptionalString(params, "namespace", "")
+	applications, err := argocd.ListApplications(params.Context, params.DynamicClient(), namespace)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "Argo CD application listing")
+		return api.NewToolCallResult("", fmt.Errorf("failed to list Argo CD applications: %w", err)), nil
+	}
+	if len(applications) == 0 {
+		return api.NewToolCallResult("# No Argo CD applications found", nil), nil
+	}
+	outOfSync, unhealthy := 0, 0
+	for _, application := range applications {
+		if application.SyncStatus != argocd.SyncStatusSynced {
+			outOfSync++
+		}
+		if application.Health != "Healthy" {
+			unhealthy++
+		}
+	}
+	ret, err := params.Marshal(applications)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list Argo CD applications: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d application(s) found, %d not synced, %d not healthy (%s format):\n",
+		len(applications), outOfSync, unhealthy, params.FormatName()), ret, nil), nil
+}
+
+func applicationDiff(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace := api.OptionalString(params, "namespace", argocd.DefaultNamespace)
+	name, err := api.RequiredString(params, "name")
+	if err != nil {
+		return api.NewToolCallResult("", err), nil
+	}
+	diff, err := argocd.GetApplicationDiff(params.Context, params.DynamicClient(), namespace, name)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "Argo CD application diff")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get Argo CD application diff: %w", err)), nil
+	}
+	ret, err := params.Marshal(diff)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get Argo CD application diff: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Application %s is %s and %s, %d resource(s) out of sync, %d unhealthy (%s format):\n",
+		name, diff.SyncStatus, diff.Health, len(diff.OutOfSync), len(diff.Unhealthy), params.FormatName()), ret, nil), nil
+}
+
+// applicationSyncPermissions returns the permissions to get the Application and to patch its operation
+func applicationSyncPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	namespace := api.OptionalString(params, "namespace", argocd.DefaultNamespace)
+	return []api.Permission{
+		{Verb: "get", Group: argocd.ApplicationGVR.Group, Resource: argocd.ApplicationGVR.Resource, Namespace: namespace},
+		{Verb: "patch", Group: argocd.ApplicationGVR.Group, Resource: argocd.ApplicationGVR.Resource, Namespace: namespace},
+	}, nil
+}
+
+func applicationSync(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace := api.OptionalString(params, "namespace", argocd.DefaultNamespace)
+	name, err := api.RequiredString(params, "name")
+	if err != nil {
+		return api.NewToolCallResult("", err), nil
+	}
+	revision := api.OptionalString(params, "revision", "")
+	prune := api.OptionalBool(params, "prune", false)
+	application, err := argocd.SyncApplication(params.Context, params.DynamicClient(), namespace, name, revision, prune)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "Argo CD application sync")
+		return api.NewToolCallResult("", fmt.Errorf("failed to sync Argo CD application: %w", err)), nil
+	}
+	operation, _, _ := unstructured.NestedMap(application.Object, "operation")
+	ret, err := params.Marshal(map[string]any{"application": argocd.Summarize(application), "operation": operation})
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to sync Argo CD application: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Sync of application %s requested, use argocd_applications_list to follow its progress (%s format):\n", name, params.FormatName()), ret, nil), nil
+}
@@ -0,0 +1,38 @@
+package argocd
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/argocd"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return "argocd"
+}
+
+func (t *Toolset) GetDescription() string {
+	return "Argo CD GitOps tools to inspect and sync Applications"
+}
+
+func (t *Toolset) GetTools(_ api.Openshift) []api.ServerTool {
+	return api.WithAPIGroups(
+		slices.Concat(
+			initApplications(),
+		),
+		argocd.ApplicationGVR.Group,
+	)
+}
+
+func (t *Toolset) GetPrompts() []api.ServerPrompt {
+	return nil
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
@@ -0,0 +1,181 @@
+package clusterapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/clusterapi"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+func initResources() []api.ServerTool {
+	kinds := make([]any, 0, len(clusterapi.Kinds))
+	for _, kind := range clusterapi.Kinds {
+		kinds = append(kinds, kind)
+	}
+	return []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name: "clusterapi_resources_list",
+				Description: "List the Cluster API Clusters, MachineDeployments, and Machines with their phase, Ready condition, Kubernetes version, " +
+					"the provisioning status of the Clusters, the replicas of the MachineDeployments, the Nodes of the Machines, " +
+					"and their failures and conditions that are not True. Failed and not ready resources are listed first",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"kind": {
+							Type:        "string",
+							Description: "Kind of the Cluster API resources to list (Optional, all the supported kinds if not provided)",
+							Enum:        kinds,
+						},
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace to list the Cluster API resources from (Optional, all namespaces if not provided)",
+						},
+						"cluster": {
+							Type:        "string",
+							Description: "Name of the Cluster to list the resources of (Optional, all the Clusters if not provided)",
+						},
+					},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Cluster API: Resources List",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler: resourcesList,
+		},
+		{
+			Tool: api.Tool{
+				Name: "clusterapi_machinedeployment_scale",
+				Description: "Scale a Cluster API MachineDeployment to the provided number of Machines. " +
+					"MachineDeployments managed by the topology of their Cluster and replicas out of the cluster autoscaler limits are refused",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace of the MachineDeployment (Optional, current namespace if not provided)",
+						},
+						"name": {
+							Type:        "string",
+							Description: "Name of the MachineDeployment",
+						},
+						"replicas": {
+							Type:        "integer",
+							Description: "Desired number of Machines",
+							Minimum:     ptr.To(float64(0)),
+						},
+					},
+					Required: []string{"name", "replicas"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Cluster API: MachineDeployment Scale",
+					ReadOnlyHint:    ptr.To(false),
+					DestructiveHint: ptr.To(true),
+					IdempotentHint:  ptr.To(true),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler:     machineDeploymentScale,
+			Permissions: machineDeploymentScalePermissions,
+		},
+	}
+}
+
+func resourcesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace := api.OptionalString(params, "namespace", "")
+	cluster := api.OptionalString(params, "cluster", "")
+	kinds := clusterapi.Kinds
+	if kind := api.OptionalString(params, "kind", ""); kind != "" {
+		kinds = []string{kind}
+	}
+	resources := make([]clusterapi.Resource, 0)
+	installed := 0
+	for _, kind := range kinds {
+		gvr, err := clusterapi.ResourceFor(params.RESTMapper(), kind)
+		if meta.IsNoMatchError(err) && len(kinds) > 1 {
+			continue
+		}
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to list Cluster API resources: %w", err)), nil
+		}
+		installed++
+		list, err := clusterapi.ListResources(params.Context, params.DynamicClient(), gvr, namespace, cluster)
+		if err != nil {
+			mcplog.HandleK8sError(params.Context, err, "Cluster API resource listing")
+			return api.NewToolCallResult("", fmt.Errorf("failed to list Cluster API resources: %w", err)), nil
+		}
+		resources = append(resources, list...)
+	}
+	if installed == 0 {
+		return api.NewToolCallResult("", errors.New("failed to list Cluster API resources: Cluster API is not installed in the cluster")), nil
+	}
+	if len(resources) == 0 {
+		return api.NewToolCallResult("# No Cluster API resources found", nil), nil
+	}
+	clusterapi.SortResources(resources)
+	notReady := 0
+	for _, resource := range resources {
+		if resource.Ready != "True" {
+			notReady++
+		}
+	}
+	ret, err := params.Marshal(resources)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list Cluster API resources: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d Cluster API resource(s) found, %d not ready (%s format):\n",
+		len(resources), notReady, params.FormatName()), ret, nil), nil
+}
+
+// machineDeploymentScalePermissions returns the permissions to get the MachineDeployment and to patch its replicas
+func machineDeploymentScalePermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	gvr, err := clusterapi.ResourceFor(params.RESTMapper(), clusterapi.MachineDeploymentGK.Kind)
+	if err != nil {
+		return nil, err
+	}
+	namespace := params.NamespaceOrDefault(api.OptionalString(params, "namespace", ""))
+	return []api.Permission{
+		{Verb: "get", Group: gvr.Group, Resource: gvr.Resource, Namespace: namespace},
+		{Verb: "patch", Group: gvr.Group, Resource: gvr.Resource, Namespace: namespace},
+	}, nil
+}
+
+func machineDeploymentScale(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, err := api.RequiredString(params, "name")
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scale MachineDeployment: %w", err)), nil
+	}
+	value, ok := params.GetArguments()["replicas"]
+	if !ok || value == nil {
+		return api.NewToolCallResult("", errors.New("failed to scale MachineDeployment: replicas parameter required")), nil
+	}
+	replicas, err := api.ParseInt64(value)
+	if err != nil || replicas < 0 {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scale MachineDeployment: invalid replicas %v", value)), nil
+	}
+	namespace := params.NamespaceOrDefault(api.OptionalString(params, "namespace", ""))
+	gvr, err := clusterapi.ResourceFor(params.RESTMapper(), clusterapi.MachineDeploymentGK.Kind)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scale MachineDeployment: %w", err)), nil
+	}
+	machineDeployment, err := clusterapi.Scale(params.Context, params.DynamicClient(), gvr, namespace, name, replicas)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "MachineDeployment scaling")
+		return api.NewToolCallResult("", fmt.Errorf("failed to scale MachineDeployment: %w", err)), nil
+	}
+	ret, err := params.Marshal(clusterapi.Summarize(machineDeployment))
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to scale MachineDeployment: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# MachineDeployment %s/%s scaled to %d replica(s), use clusterapi_resources_list to follow the Machines (%s format):\n",
+		namespace, name, replicas, params.FormatName()), ret, nil), nil
+}
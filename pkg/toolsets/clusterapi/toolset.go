@@ -0,0 +1,38 @@
+package clusterapi
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/clusterapi"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return "clusterapi"
+}
+
+func (t *Toolset) GetDescription() string {
+	return "Cluster API tools to inspect the lifecycle of Clusters, MachineDeployments, and Machines, and to scale MachineDeployments"
+}
+
+func (t *Toolset) GetTools(_ api.Openshift) []api.ServerTool {
+	return api.WithAPIGroups(
+		slices.Concat(
+			initResources(),
+		),
+		clusterapi.ClusterGK.Group,
+	)
+}
+
+func (t *Toolset) GetPrompts() []api.ServerPrompt {
+	return nil
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
@@ -2,13 +2,15 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"k8s.io/utils/ptr"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/diagnostics"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
-	"github.com/containers/kubernetes-mcp-server/pkg/output"
 )
 
 func initConfiguration() []api.ServerTool {
@@ -82,6 +84,49 @@ func initConfiguration() []api.ServerTool {
 			ClusterAware: ptr.To(false),
 			Handler:      configurationView,
 		},
+		// Server status tool, the handler is provided by the MCP server.
+		// The WithServerStatusTool mutator sets the handler since the reported data
+		// (enabled toolsets, sessions, statistics) is only known to the server instance.
+		{
+			Tool: api.Tool{
+				Name: "server_status",
+				Description: "Get the status of this MCP server: version, active configuration summary " +
+					"(enabled toolsets, read-only mode, denied resources count and scopes), connected cluster, cache statistics, " +
+					"size of the tool results returned by namespace, and active sessions with their usage",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Server: Status",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					IdempotentHint:  ptr.To(true),
+					OpenWorldHint:   ptr.To(false),
+				},
+			},
+			ClusterAware: ptr.To(false),
+			Handler:      nil,
+		},
+		{
+			Tool: api.Tool{
+				Name: "server_diagnostics",
+				Description: "Get the runtime diagnostics of this MCP server process: uptime, goroutines (count and largest groups), " +
+					"heap and stack memory, and garbage collector statistics. " +
+					"Useful to investigate memory growth or goroutine leaks of long-running servers",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Server: Diagnostics",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					IdempotentHint:  ptr.To(true),
+					OpenWorldHint:   ptr.To(false),
+				},
+			},
+			ClusterAware: ptr.To(false),
+			Handler:      serverDiagnostics,
+		},
 	}
 	return tools
 }
@@ -105,13 +150,13 @@ func contextsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	result += "Format: [*] CONTEXT_NAME -> SERVER_URL\n"
 	result += " (* indicates the default context used in tools if context is not set)\n\n"
 	result += "Contexts:\n---------\n"
-	for context, server := range contexts {
+	for _, context := range slices.Sorted(maps.Keys(contexts)) {
 		marker := " "
 		if context == defaultContext {
 			marker = "*"
 		}
 
-		result += fmt.Sprintf("%s%s -> %s\n", marker, context, server)
+		result += fmt.Sprintf("%s%s -> %s\n", marker, context, contexts[context])
 	}
 	result += "---------\n\n"
 
@@ -131,9 +176,17 @@ func configurationView(params api.ToolHandlerParams) (*api.ToolCallResult, error
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("failed to get configuration: %w", err)), nil
 	}
-	configurationYaml, err := output.MarshalYaml(ret)
+	configurationYaml, err := params.Marshal(ret)
 	if err != nil {
 		err = fmt.Errorf("failed to get configuration: %w", err)
 	}
 	return api.NewToolCallResult(configurationYaml, err), nil
 }
+
+func serverDiagnostics(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	diagnosticsYaml, err := params.Marshal(diagnostics.Collect())
+	if err != nil {
+		err = fmt.Errorf("failed to get server diagnostics: %w", err)
+	}
+	return api.NewToolCallResult(diagnosticsYaml, err), nil
+}
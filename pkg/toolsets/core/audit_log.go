@@ -0,0 +1,145 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/auditlog"
+)
+
+const (
+	// defaultAuditLogQueryLimit is the default number of audit events returned by the audit_log_query tool
+	defaultAuditLogQueryLimit = 50
+	// maxAuditLogQueryLimit bounds the number of audit events returned by the audit_log_query tool
+	maxAuditLogQueryLimit = 500
+)
+
+func initAuditLog() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "audit_log_query",
+			Description: "Query the Kubernetes API server audit log configured in the server ([toolset_configs.core.audit_log], an audit log file or a Loki endpoint) " +
+				"to find out who changed or deleted what and when, e.g. \"who deleted this deployment yesterday\". " +
+				"Returns the most recent matching requests, oldest first, with their user, verb, object, response code, source IPs, and user agent. " +
+				"Only the requests changing the cluster (create, update, patch, delete, deletecollection) are returned unless verbs are provided",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"verbs": {
+						Type:        "array",
+						Description: "Verbs of the requests (e.g. [\"delete\"]) (Optional, default: [" + strings.Join(auditlog.WriteVerbs, ", ") + "])",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+					"resource": {
+						Type:        "string",
+						Description: "Plural name of the resource of the requests, optionally with its API group (e.g. deployments, deployments.apps, secrets) (Optional)",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the objects of the requests (Optional)",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the object of the requests (Optional)",
+					},
+					"user": {
+						Type:        "string",
+						Description: "Part of the name of the user of the requests, case-insensitive (e.g. alice, system:serviceaccount:ci) (Optional)",
+					},
+					"from": {
+						Type:        "string",
+						Description: "Start of the time range, an RFC3339 timestamp or a time of the day in UTC (Optional, default: 24 hours before to)",
+					},
+					"to": {
+						Type:        "string",
+						Description: "End of the time range, an RFC3339 timestamp or a time of the day in UTC (Optional, default: now)",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Maximum number of returned requests, the most recent ones (Optional, default: %d, maximum: %d)", defaultAuditLogQueryLimit, maxAuditLogQueryLimit),
+						Default:     api.ToRawMessage(defaultAuditLogQueryLimit),
+						Minimum:     ptr.To(float64(1)),
+						Maximum:     ptr.To(float64(maxAuditLogQueryLimit)),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Audit Log: Query",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: auditLogQuery},
+	}
+}
+
+func auditLogQuery(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	filter := auditlog.Filter{Verbs: auditlog.WriteVerbs, Limit: defaultAuditLogQueryLimit}
+	if v, ok := params.GetArguments()["verbs"].([]any); ok && len(v) > 0 {
+		filter.Verbs = make([]string, 0, len(v))
+		for _, verb := range v {
+			s, ok := verb.(string)
+			if !ok || s == "" {
+				return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to query audit log, verbs must be an array of strings"))), nil
+			}
+			filter.Verbs = append(filter.Verbs, strings.ToLower(s))
+		}
+	}
+	filter.Resource, _ = params.GetArguments()["resource"].(string)
+	filter.Namespace, _ = params.GetArguments()["namespace"].(string)
+	filter.Name, _ = params.GetArguments()["name"].(string)
+	filter.User, _ = params.GetArguments()["user"].(string)
+	now := time.Now()
+	filter.To = now
+	if toArg, _ := params.GetArguments()["to"].(string); toArg != "" {
+		var err error
+		if filter.To, err = parseAround(toArg, now); err != nil {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid to: %w", err))), nil
+		}
+	}
+	filter.From = filter.To.Add(-24 * time.Hour)
+	if fromArg, _ := params.GetArguments()["from"].(string); fromArg != "" {
+		var err error
+		if filter.From, err = parseAround(fromArg, now); err != nil {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid from: %w", err))), nil
+		}
+	}
+	if !filter.From.Before(filter.To) {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid time range, from %s is not before to %s",
+			filter.From.UTC().Format(time.RFC3339), filter.To.UTC().Format(time.RFC3339)))), nil
+	}
+	if v, ok := params.GetArguments()["limit"]; ok && v != nil {
+		limit, err := api.ParseInt64(v)
+		if err != nil || limit < 1 || limit > maxAuditLogQueryLimit {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse limit parameter, expected 1 to %d: %v", maxAuditLogQueryLimit, v))), nil
+		}
+		filter.Limit = int(limit)
+	}
+	auditLogConfig := toolsetConfig(params).AuditLog
+	if auditLogConfig == nil {
+		return api.NewToolCallResult("", errors.New("failed to query audit log: the audit log is not configured, set [toolset_configs.core.audit_log] in the server configuration")), nil
+	}
+	result, err := auditlog.NewAuditLog(auditLogConfig).Query(params.Context, filter)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to query audit log: %w", err)), nil
+	}
+	period := fmt.Sprintf("between %s and %s", filter.From.UTC().Format(time.RFC3339), filter.To.UTC().Format(time.RFC3339))
+	if len(result.Events) == 0 {
+		return api.NewToolCallResult("# No audit events found "+period, nil), nil
+	}
+	ret, err := params.Marshal(result.Events)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to query audit log: %w", err)), nil
+	}
+	header := fmt.Sprintf("# %d audit event(s) %s, oldest first (%s format):\n", len(result.Events), period, params.FormatName())
+	if result.Truncated {
+		header = fmt.Sprintf("# The %d most recent audit events %s, oldest first, narrow the query down or increase limit to get the others (%s format):\n", len(result.Events), period, params.FormatName())
+	}
+	return api.NewStructuredToolCallResult(header, ret, nil), nil
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+func initCapacity() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "capacity_usage",
+			Description: "Aggregate the resources (CPU, memory, and extended resources) requested by the running Kubernetes Pods per namespace or per label value (e.g. team), " +
+				"with the share of the total requests of each group and the percentage of the cluster allocatable resources requested. " +
+				"When resource prices are configured ([toolset_configs.core.prices]), also estimates the hourly cost of each group and its share of the total cost. " +
+				"Useful to answer questions like \"which team is consuming the cluster\"",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace to aggregate the Pod requests from. If not provided, will aggregate the Pods in all namespaces",
+					},
+					"group_by_label": {
+						Type:        "string",
+						Description: "Optional Pod label key to group the requests by (e.g. team or app.kubernetes.io/part-of). If not provided, will group the requests by namespace",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Capacity: Usage",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: capacityUsage},
+	}
+}
+
+func capacityUsage(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	groupByLabel, _ := params.GetArguments()["group_by_label"].(string)
+	report, err := kubernetes.NewCore(params).CapacityUsage(params, namespace, groupByLabel, toolsetConfig(params).Prices)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "capacity usage aggregation")
+		return api.NewToolCallResult("", fmt.Errorf("failed to aggregate capacity usage: %w", err)), nil
+	}
+	if report.Pods == 0 {
+		return api.NewToolCallResult("# No running pods found", nil), nil
+	}
+	ret, err := params.Marshal(report)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to aggregate capacity usage: %w", err)), nil
+	}
+	costs := "no prices configured, costs not estimated"
+	if report.EstimatedCostPerHour != "" {
+		costs = "estimated cost per hour " + report.EstimatedCostPerHour
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d running pod(s) in %d group(s) by %s, %s (%s format):\n",
+		report.Pods, len(report.Groups), report.GroupBy, costs, params.FormatName()), ret, nil), nil
+}
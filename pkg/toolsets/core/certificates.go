@@ -0,0 +1,78 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+// defaultCertificatesExpiryDays is the default window in days of the certificates_audit tool
+const defaultCertificatesExpiryDays = 30
+
+func initCertificates() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "certificates_audit",
+			Description: "Audit the TLS certificates stored in Kubernetes TLS Secrets (and cert-manager Certificates, when installed). " +
+				"Reports the expiry date, subject, issuer, and DNS SANs of each certificate, flags certificates that are expired or expiring within a configurable window, " +
+				"and detects SAN mismatches with the hosts of the Ingresses using them. Private keys are never read or returned",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace to audit. If not provided, will audit all namespaces",
+					},
+					"expiry_days": {
+						Type:        "integer",
+						Description: "Certificates expiring within this number of days are flagged as expiring (Optional, default: 30)",
+						Default:     api.ToRawMessage(defaultCertificatesExpiryDays),
+						Minimum:     ptr.To(float64(0)),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Certificates: Audit",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: certificatesAudit},
+	}
+}
+
+func certificatesAudit(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	expiryDays := int64(defaultCertificatesExpiryDays)
+	if v, ok := params.GetArguments()["expiry_days"]; ok && v != nil {
+		var err error
+		if expiryDays, err = api.ParseInt64(v); err != nil || expiryDays < 0 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse expiry_days parameter: %v", v))), nil
+		}
+	}
+	audits, err := kubernetes.NewCore(params).CertificatesAudit(params, namespace, time.Duration(expiryDays)*24*time.Hour)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "certificates audit")
+		return api.NewToolCallResult("", fmt.Errorf("failed to audit certificates: %w", err)), nil
+	}
+	if len(audits) == 0 {
+		return api.NewToolCallResult("# No TLS certificates found", nil), nil
+	}
+	flagged := 0
+	for _, audit := range audits {
+		if audit.Status != kubernetes.CertificateStatusValid || len(audit.Issues) > 0 {
+			flagged++
+		}
+	}
+	ret, err := params.Marshal(audits)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to audit certificates: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d certificate(s) audited, %d need attention (expiring within %d days, expired, invalid, or with issues) (%s format):\n", len(audits), flagged, expiryDays, params.FormatName()), ret, nil), nil
+}
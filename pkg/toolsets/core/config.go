@@ -0,0 +1,86 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/auditlog"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/prometheus"
+)
+
+// Config holds the core toolset configuration
+type Config struct {
+	// Prices are the hourly prices of a unit of each resource (CPU core, GiB of memory, or unit of extended resources),
+	// used to estimate the cost of the requested resources
+	Prices kubernetes.CapacityPrices `toml:"prices,omitempty"`
+	// Prometheus is the Prometheus queried by the metrics_query tool
+	Prometheus *prometheus.Config `toml:"prometheus,omitempty"`
+	// AuditLog is the backend of the API server audit events queried by the audit_log_query tool
+	AuditLog *auditlog.Config `toml:"audit_log,omitempty"`
+	// ReadCache serves the list and get requests of the frequently read kinds from informers (Optional, disabled if not provided)
+	ReadCache *kubernetes.ReadCacheConfig `toml:"read_cache,omitempty"`
+	// NodeDebug enables the nodes_debug tool running commands on the nodes from Pods with host access (Optional, disabled if not provided)
+	NodeDebug *kubernetes.NodeDebugConfig `toml:"node_debug,omitempty"`
+}
+
+var _ api.ExtendedConfig = (*Config)(nil)
+
+func (c *Config) Validate() error {
+	for name, price := range c.Prices {
+		if price < 0 {
+			return fmt.Errorf("price of %s must be positive", name)
+		}
+	}
+	if c.Prometheus != nil {
+		if err := c.Prometheus.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.AuditLog != nil {
+		if err := c.AuditLog.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.ReadCache != nil {
+		if err := c.ReadCache.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.NodeDebug != nil {
+		return c.NodeDebug.Validate()
+	}
+	return nil
+}
+
+func coreToolsetParser(ctx context.Context, primitive toml.Primitive, md toml.MetaData) (api.ExtendedConfig, error) {
+	var cfg Config
+	if err := md.PrimitiveDecode(primitive, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Prometheus != nil {
+		cfg.Prometheus.ResolvePaths(config.ConfigDirPathFromContext(ctx))
+	}
+	if cfg.AuditLog != nil {
+		cfg.AuditLog.ResolvePaths(config.ConfigDirPathFromContext(ctx))
+	}
+	return &cfg, nil
+}
+
+// toolsetConfig returns the core toolset configuration, or an empty one if not configured
+func toolsetConfig(params api.ToolHandlerParams) *Config {
+	if cfg, ok := params.GetToolsetConfig("core"); ok {
+		if coreConfig, ok := cfg.(*Config); ok && coreConfig != nil {
+			return coreConfig
+		}
+	}
+	return &Config{}
+}
+
+func init() {
+	config.RegisterToolsetConfig("core", coreToolsetParser)
+}
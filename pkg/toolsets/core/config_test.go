@@ -0,0 +1,132 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/auditlog"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/prometheus"
+)
+
+type ConfigSuite struct {
+	suite.Suite
+}
+
+func (s *ConfigSuite) TestConfigParser_Prices() {
+	cfg := test.Must(config.ReadToml([]byte(`
+		[toolset_configs.core.prices]
+		cpu = 0.03
+		memory = 0.004
+		"nvidia.com/gpu" = 2.5
+	`)))
+	coreCfg, ok := cfg.GetToolsetConfig("core")
+	s.Require().True(ok, "core config should be present")
+	ccfg, ok := coreCfg.(*Config)
+	s.Require().True(ok, "core config should be of type *Config")
+	s.Equal(kubernetes.CapacityPrices{"cpu": 0.03, "memory": 0.004, "nvidia.com/gpu": 2.5}, ccfg.Prices)
+}
+
+func (s *ConfigSuite) TestConfigParser_NegativePrice() {
+	_, err := config.ReadToml([]byte(`
+		[toolset_configs.core.prices]
+		cpu = -1
+	`))
+	s.Require().Error(err, "negative prices should be rejected")
+	s.Contains(err.Error(), "price of cpu must be positive")
+}
+
+func (s *ConfigSuite) TestConfigParser_Prometheus() {
+	cfg := test.Must(config.ReadToml([]byte(`
+		[toolset_configs.core.prometheus]
+		namespace = "openshift-monitoring"
+		service = "thanos-querier"
+		port = "web"
+		scheme = "https"
+	`)))
+	coreCfg, ok := cfg.GetToolsetConfig("core")
+	s.Require().True(ok, "core config should be present")
+	s.Equal(&prometheus.Config{Namespace: "openshift-monitoring", Service: "thanos-querier", Port: "web", Scheme: "https"}, coreCfg.(*Config).Prometheus)
+}
+
+func (s *ConfigSuite) TestConfigParser_PrometheusWithoutTarget() {
+	_, err := config.ReadToml([]byte(`
+		[toolset_configs.core.prometheus]
+		insecure = true
+	`))
+	s.Require().Error(err, "prometheus without url or service should be rejected")
+	s.Contains(err.Error(), "prometheus requires either url or service")
+}
+
+func (s *ConfigSuite) TestConfigParser_AuditLog() {
+	cfg := test.Must(config.ReadToml([]byte(`
+		[toolset_configs.core.audit_log]
+		loki_url = "https://loki.example.com"
+		loki_selector = '{job="audit"}'
+		loki_tenant = "platform"
+	`)))
+	coreCfg, ok := cfg.GetToolsetConfig("core")
+	s.Require().True(ok, "core config should be present")
+	s.Equal(&auditlog.Config{LokiUrl: "https://loki.example.com", LokiSelector: `{job="audit"}`, LokiTenant: "platform"}, coreCfg.(*Config).AuditLog)
+}
+
+func (s *ConfigSuite) TestConfigParser_AuditLogWithoutBackend() {
+	_, err := config.ReadToml([]byte(`
+		[toolset_configs.core.audit_log]
+		loki_tenant = "platform"
+	`))
+	s.Require().Error(err, "audit_log without file or loki_url should be rejected")
+	s.Contains(err.Error(), "audit_log requires either file or loki_url")
+}
+
+func (s *ConfigSuite) TestConfigParser_ReadCache() {
+	cfg := test.Must(config.ReadToml([]byte(`
+		[toolset_configs.core.read_cache]
+		idle_timeout = "5m"
+		resources = [{group = "", version = "v1", kind = "ConfigMap"}]
+	`)))
+	coreCfg, ok := cfg.GetToolsetConfig("core")
+	s.Require().True(ok, "core config should be present")
+	s.Equal(&kubernetes.ReadCacheConfig{
+		IdleTimeout: "5m",
+		Resources:   []api.GroupVersionKind{{Group: "", Version: "v1", Kind: "ConfigMap"}},
+	}, coreCfg.(*Config).ReadCache)
+}
+
+func (s *ConfigSuite) TestConfigParser_ReadCacheInvalidIdleTimeout() {
+	_, err := config.ReadToml([]byte(`
+		[toolset_configs.core.read_cache]
+		idle_timeout = "soon"
+	`))
+	s.Require().Error(err, "invalid idle timeout should be rejected")
+	s.Contains(err.Error(), "read_cache idle_timeout must be a positive duration")
+}
+
+func (s *ConfigSuite) TestConfigParser_NodeDebug() {
+	cfg := test.Must(config.ReadToml([]byte(`
+		[toolset_configs.core.node_debug]
+		image = "registry.example.com/debug:1.0"
+		namespace = "node-debug"
+		privileged = true
+	`)))
+	coreCfg, ok := cfg.GetToolsetConfig("core")
+	s.Require().True(ok, "core config should be present")
+	s.Equal(&kubernetes.NodeDebugConfig{Image: "registry.example.com/debug:1.0", Namespace: "node-debug", Privileged: true}, coreCfg.(*Config).NodeDebug)
+}
+
+func (s *ConfigSuite) TestConfigParser_NodeDebugInvalidImage() {
+	_, err := config.ReadToml([]byte(`
+		[toolset_configs.core.node_debug]
+		image = "busybox latest"
+	`))
+	s.Require().Error(err, "invalid image should be rejected")
+	s.Contains(err.Error(), "node_debug image is invalid")
+}
+
+func TestConfig(t *testing.T) {
+	suite.Run(t, new(ConfigSuite))
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+// defaultCRDWaitTimeout is the default time (seconds) to wait for a CustomResourceDefinition to be established
+const defaultCRDWaitTimeout = 60
+
+func initCRDs() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "crds_list",
+			Description: "List the CustomResourceDefinitions of the cluster with their API group, kind, scope, versions (served, storage, deprecated), " +
+				"and whether they are established (their custom resources can be created)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"group": {
+						Type:        "string",
+						Description: "API group of the CustomResourceDefinitions to list (e.g. cert-manager.io) (Optional, all groups if not provided)",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "CRDs: List",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: crdsList},
+		{Tool: api.Tool{
+			Name: "crds_schema",
+			Description: "Get the OpenAPI v3 schema of a version of a CustomResourceDefinition, to write valid custom resources. " +
+				"Provide a field path (e.g. spec.issuerRef) to only get the schema of that field of large CRDs",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the CustomResourceDefinition (e.g. certificates.cert-manager.io)",
+					},
+					"version": {
+						Type:        "string",
+						Description: "Version of the CustomResourceDefinition (Optional, the storage version if not provided)",
+					},
+					"field": {
+						Type:        "string",
+						Description: "Dot-separated path of the field whose schema to get (e.g. spec.template), the items of the arrays are traversed implicitly (Optional, the whole resource if not provided)",
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "CRDs: Schema",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: crdsSchema},
+		{Tool: api.Tool{
+			Name: "crds_wait_established",
+			Description: "Wait for a CustomResourceDefinition to be established, i.e. for its custom resources to be accepted by the API server. " +
+				"Use it after installing a chart or an operator providing CRDs, before creating their custom resources. " +
+				"The CustomResourceDefinition is waited for if it doesn't exist yet (e.g. created by an operator starting up)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the CustomResourceDefinition (e.g. certificates.cert-manager.io)",
+					},
+					"timeout": {
+						Type:        "integer",
+						Description: "Maximum time in seconds to wait for the CustomResourceDefinition to be established (Optional, default: 60)",
+						Default:     api.ToRawMessage(defaultCRDWaitTimeout),
+						Minimum:     ptr.To(float64(1)),
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "CRDs: Wait Established",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: crdsWaitEstablished, LongRunning: ptr.To(true)},
+	}
+}
+
+func crdsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	group, _ := params.GetArguments()["group"].(string)
+	crds, err := kubernetes.NewCore(params).CRDsList(params, group)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "CRDs listing")
+		return api.NewToolCallResult("", fmt.Errorf("failed to list CustomResourceDefinitions: %w", err)), nil
+	}
+	if len(crds) == 0 {
+		return api.NewToolCallResult("# No CustomResourceDefinitions found", nil), nil
+	}
+	ret, err := params.Marshal(crds)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list CustomResourceDefinitions: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d CustomResourceDefinition(s) (%s format):\n", len(crds), params.FormatName()), ret, nil), nil
+}
+
+func crdsSchema(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, _ := params.GetArguments()["name"].(string)
+	if name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to get CustomResourceDefinition schema, missing argument name"))), nil
+	}
+	version, _ := params.GetArguments()["version"].(string)
+	field, _ := params.GetArguments()["field"].(string)
+	schema, err := kubernetes.NewCore(params).CRDSchemaGet(params, name, version, field)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "CRD schema retrieval")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get the schema of CustomResourceDefinition %s: %w", name, err)), nil
+	}
+	ret, err := params.Marshal(schema)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get the schema of CustomResourceDefinition %s: %w", name, err)), nil
+	}
+	return api.NewToolCallResult(ret, nil), nil
+}
+
+func crdsWaitEstablished(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, _ := params.GetArguments()["name"].(string)
+	if name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to wait for CustomResourceDefinition, missing argument name"))), nil
+	}
+	timeout := defaultCRDWaitTimeout * time.Second
+	if v, ok := params.GetArguments()["timeout"]; ok && v != nil {
+		seconds, err := api.ParseInt64(v)
+		if err != nil || seconds < 1 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse timeout parameter: %v", v))), nil
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	crd, err := kubernetes.NewCore(params).CRDWaitEstablished(params, name, timeout)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "CRD wait")
+		return api.NewToolCallResult("", fmt.Errorf("failed to wait for CustomResourceDefinition %s: %w", name, err)), nil
+	}
+	ret, err := params.Marshal(crd)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to wait for CustomResourceDefinition %s: %w", name, err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# CustomResourceDefinition %s is established (%s format):\n", name, params.FormatName()), ret, nil), nil
+}
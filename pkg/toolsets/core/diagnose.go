@@ -0,0 +1,279 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+// defaultRestartsWindow is the default time window of the diagnose_restarts tool
+const defaultRestartsWindow = "24h"
+
+// defaultPvcUnusedDays is the default minimum age of the unmounted PersistentVolumeClaims reported by the diagnose_orphans tool
+const defaultPvcUnusedDays = 7
+
+func initDiagnose() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "diagnose_pod",
+			Description: "Diagnose why a Kubernetes Pod is failing or not ready. " +
+				"Gathers the Pod status, container states and exit codes, restarts, logs of the last terminated containers, related events, and probe configuration, " +
+				"and returns a list of findings (e.g. ImagePullBackOff with the failing image and registry error, CrashLoopBackOff, OOMKilled, failing probes)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pod to diagnose",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Pod to diagnose",
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Diagnose: Pod",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: diagnosePod, Localized: ptr.To(true)},
+		{Tool: api.Tool{
+			Name: "diagnose_scheduling",
+			Description: "Explain why a Kubernetes Pod is Pending (not scheduled). " +
+				"Reports the unschedulable reasons from the scheduler and events, a per-node fit analysis (cordoned or not ready nodes, untolerated taints, node selector and affinity mismatches, CPU/memory shortfalls), " +
+				"ResourceQuota and PodDisruptionBudget constraints, and suggested remediations",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pending Pod",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Pending Pod",
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Diagnose: Pod Scheduling",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: diagnoseScheduling},
+		{Tool: api.Tool{
+			Name: "diagnose_restarts",
+			Description: "Scan the Kubernetes Pods in a namespace (or a workload selected by labels) for containers that were OOMKilled or are crash looping within a time window. " +
+				"Offenders are ranked by their number of restarts and correlated with their memory requests, limits, and current usage (when the Metrics Server is available)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace to scan. If not provided, will scan all namespaces",
+					},
+					"labelSelector": {
+						Type:        "string",
+						Description: "Optional Kubernetes label selector (e.g. 'app=myapp') to restrict the scan to the Pods of a workload",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+					"window": {
+						Type:        "string",
+						Description: "Optional time window (Go duration, e.g. '30m', '6h') in which terminations are considered. Defaults to 24h",
+						Default:     api.ToRawMessage(defaultRestartsWindow),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Diagnose: OOMKills and Restarts",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: diagnoseRestarts},
+		{Tool: api.Tool{
+			Name: "diagnose_termination",
+			Description: "Find the Kubernetes objects (including Namespaces) stuck in Terminating, identify the finalizers blocking their deletion and the controllers that own them, " +
+				"check whether those controllers are healthy, and suggest how to unblock the deletion",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace to diagnose (the Namespace itself and the objects it contains). If not provided, will scan the whole cluster",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Diagnose: Stuck Terminating Objects",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: diagnoseTermination, Localized: ptr.To(true)},
+		{Tool: api.Tool{
+			Name: "diagnose_orphans",
+			Description: "Find likely-orphaned Kubernetes resources: Services without ready endpoints, ConfigMaps and Secrets not referenced by any Pod or workload, " +
+				"PersistentVolumeClaims not mounted by any Pod, and ReplicaSets with zero replicas left behind by deleted Deployments. " +
+				"Results are candidates for cleanup and should be reviewed before deleting anything",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace to scan. If not provided, will scan all namespaces",
+					},
+					"pvc_unused_days": {
+						Type:        "integer",
+						Description: "Minimum age in days of the unmounted PersistentVolumeClaims to report (Optional, default: 7)",
+						Default:     api.ToRawMessage(defaultPvcUnusedDays),
+						Minimum:     ptr.To(float64(0)),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Diagnose: Orphaned Resources",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: diagnoseOrphans},
+	}
+}
+
+func diagnosePod(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	ns := params.GetArguments()["namespace"]
+	if ns == nil {
+		ns = ""
+	}
+	name := params.GetArguments()["name"]
+	if name == nil {
+		return api.NewToolCallResult("", errors.New("failed to diagnose pod, missing argument name")), nil
+	}
+	diagnosis, err := kubernetes.NewCore(params).PodsDiagnose(params, ns.(string), name.(string))
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "pod diagnosis")
+		return api.NewToolCallResult("", fmt.Errorf("failed to diagnose pod %s in namespace %s: %w", name, ns, err)), nil
+	}
+	ret, err := params.Marshal(diagnosis)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to diagnose pod %s in namespace %s: %w", name, ns, err)), nil
+	}
+	p := locale.FromContext(params)
+	header := p.Sprintf("# %d issue(s) found for Pod %s/%s (%s format):\n", len(diagnosis.Findings), diagnosis.Namespace, diagnosis.Name, params.FormatName())
+	if len(diagnosis.Findings) == 0 {
+		header = p.Sprintf("# No issues found for Pod %s/%s (%s format):\n", diagnosis.Namespace, diagnosis.Name, params.FormatName())
+	}
+	return api.NewStructuredToolCallResult(header, ret, nil), nil
+}
+
+func diagnoseScheduling(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	ns := params.GetArguments()["namespace"]
+	if ns == nil {
+		ns = ""
+	}
+	name := params.GetArguments()["name"]
+	if name == nil {
+		return api.NewToolCallResult("", errors.New("failed to diagnose pod scheduling, missing argument name")), nil
+	}
+	diagnosis, err := kubernetes.NewCore(params).PodsDiagnoseScheduling(params, ns.(string), name.(string))
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "pod scheduling diagnosis")
+		return api.NewToolCallResult("", fmt.Errorf("failed to diagnose scheduling of pod %s in namespace %s: %w", name, ns, err)), nil
+	}
+	if diagnosis.Scheduled {
+		return api.NewToolCallResult(fmt.Sprintf("# Pod %s/%s is already scheduled on node %s", diagnosis.Namespace, diagnosis.Name, diagnosis.Node), nil), nil
+	}
+	ret, err := params.Marshal(diagnosis)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to diagnose scheduling of pod %s in namespace %s: %w", name, ns, err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Scheduling analysis for Pod %s/%s (%s format):\n", diagnosis.Namespace, diagnosis.Name, params.FormatName()), ret, nil), nil
+}
+
+func diagnoseRestarts(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	labelSelector, _ := params.GetArguments()["labelSelector"].(string)
+	windowArg, ok := params.GetArguments()["window"].(string)
+	if !ok || windowArg == "" {
+		windowArg = defaultRestartsWindow
+	}
+	window, err := time.ParseDuration(windowArg)
+	if err != nil || window <= 0 {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid window %s, expected a positive duration (e.g. 30m, 6h)", windowArg))), nil
+	}
+	offenders, err := kubernetes.NewCore(params).PodsRestarts(params, namespace, labelSelector, window)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "pod restarts analysis")
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze pod restarts: %w", err)), nil
+	}
+	if len(offenders) == 0 {
+		return api.NewToolCallResult(fmt.Sprintf("# No OOMKilled or crash looping containers found in the last %s", windowArg), nil), nil
+	}
+	ret, err := params.Marshal(offenders)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze pod restarts: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# The following containers (%s format) were OOMKilled or crash looping in the last %s, ranked by restarts:\n", params.FormatName(), windowArg), ret, nil), nil
+}
+
+func diagnoseTermination(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	diagnosis, err := kubernetes.NewCore(params).TerminationDiagnose(params, namespace)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "termination diagnosis")
+		return api.NewToolCallResult("", fmt.Errorf("failed to diagnose stuck terminating objects: %w", err)), nil
+	}
+	p := locale.FromContext(params)
+	if len(diagnosis.Objects) == 0 {
+		return api.NewToolCallResult(p.Sprintf("# No objects stuck in Terminating found"), nil), nil
+	}
+	ret, err := params.Marshal(diagnosis)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to diagnose stuck terminating objects: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(p.Sprintf("# %d object(s) stuck in Terminating (%s format):\n", len(diagnosis.Objects), params.FormatName()), ret, nil), nil
+}
+
+func diagnoseOrphans(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	unusedDays := int64(defaultPvcUnusedDays)
+	if v, ok := params.GetArguments()["pvc_unused_days"]; ok && v != nil {
+		var err error
+		if unusedDays, err = api.ParseInt64(v); err != nil || unusedDays < 0 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse pvc_unused_days parameter: %v", v))), nil
+		}
+	}
+	orphans, skipped, err := kubernetes.NewCore(params).Orphans(params, namespace, time.Duration(unusedDays)*24*time.Hour)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "orphaned resources detection")
+		return api.NewToolCallResult("", fmt.Errorf("failed to find orphaned resources: %w", err)), nil
+	}
+	// the checks skipped because of the lists that failed (e.g. forbidden) are stated, their resources aren't reported
+	skippedChecks := ""
+	for _, check := range skipped {
+		skippedChecks += "# Skipped " + check + "\n"
+	}
+	if len(orphans) == 0 {
+		return api.NewToolCallResult(strings.TrimSuffix("# No orphaned resources found\n"+skippedChecks, "\n"), nil), nil
+	}
+	ret, err := params.Marshal(orphans)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to find orphaned resources: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(skippedChecks+fmt.Sprintf("# The following resources (%s format) are likely orphaned:\n", params.FormatName()), ret, nil), nil
+}
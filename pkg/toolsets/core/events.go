@@ -2,16 +2,20 @@ package core
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"k8s.io/utils/ptr"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
 	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
-	"github.com/containers/kubernetes-mcp-server/pkg/output"
 )
 
+// defaultEventsSummaryWindow is the default time window of the events_summary tool
+const defaultEventsSummaryWindow = "1h"
+
 func initEvents() []api.ServerTool {
 	return []api.ServerTool{
 		{Tool: api.Tool{
@@ -20,6 +24,7 @@ func initEvents() []api.ServerTool {
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					"bypass_cache": bypassCacheProperty(),
 					"namespace": {
 						Type:        "string",
 						Description: "Optional Namespace to retrieve the events from. If not provided, will list events from all namespaces",
@@ -32,7 +37,33 @@ func initEvents() []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: eventsList},
+		}, Handler: eventsList, Localized: ptr.To(true)},
+		{Tool: api.Tool{
+			Name: "events_summary",
+			Description: "Summarize the Kubernetes events seen within a time window instead of listing them. " +
+				"Events are aggregated by type, reason, and involved object kind, with their number of occurrences, rate, the most affected objects, and the latest message. " +
+				"Bursts (e.g. hundreds of FailedScheduling events in a few minutes) are flagged as spikes. Warnings are listed first",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace to summarize the events from. If not provided, will summarize events from all namespaces",
+					},
+					"window": {
+						Type:        "string",
+						Description: "Time window to summarize, as a duration (e.g. 10m, 1h, 24h) (Optional, default: 1h)",
+						Default:     api.ToRawMessage(defaultEventsSummaryWindow),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Events: Summary",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: eventsSummary},
 	}
 }
 
@@ -41,17 +72,52 @@ func eventsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	if namespace == nil {
 		namespace = ""
 	}
-	eventMap, err := kubernetes.NewCore(params).EventsList(params, namespace.(string))
+	core := newCore(params)
+	eventMap, err := core.EventsList(params, namespace.(string))
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "events listing")
 		return api.NewToolCallResult("", fmt.Errorf("failed to list events in all namespaces: %w", err)), nil
 	}
+	p := locale.FromContext(params)
 	if len(eventMap) == 0 {
-		return api.NewToolCallResult("# No events found", nil), nil
+		return api.NewToolCallResult(p.Sprintf("# No events found"), nil), nil
 	}
-	yamlEvents, err := output.MarshalYaml(eventMap)
+	yamlEvents, err := params.Marshal(eventMap)
 	if err != nil {
 		err = fmt.Errorf("failed to list events in all namespaces: %w", err)
 	}
-	return api.NewToolCallResult(fmt.Sprintf("# The following events (YAML format) were found:\n%s", yamlEvents), err), nil
+	return withFreshness(core, p.Sprintf("# The following events (%s format) were found:\n", params.FormatName()), yamlEvents, err), nil
+}
+
+func eventsSummary(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	windowArg, ok := params.GetArguments()["window"].(string)
+	if !ok || windowArg == "" {
+		windowArg = defaultEventsSummaryWindow
+	}
+	window, err := time.ParseDuration(windowArg)
+	if err != nil || window <= 0 {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid window %s, expected a positive duration (e.g. 10m, 1h)", windowArg))), nil
+	}
+	groups, err := kubernetes.NewCore(params).EventsSummary(params, namespace, window)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "events summary")
+		return api.NewToolCallResult("", fmt.Errorf("failed to summarize events: %w", err)), nil
+	}
+	if len(groups) == 0 {
+		return api.NewToolCallResult(fmt.Sprintf("# No events found in the last %s", windowArg), nil), nil
+	}
+	var total int32
+	spikes := 0
+	for _, group := range groups {
+		total += group.Count
+		if group.Spike {
+			spikes++
+		}
+	}
+	ret, err := params.Marshal(groups)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to summarize events: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d event occurrence(s) in %d group(s) in the last %s, %d spike(s) (%s format):\n", total, len(groups), windowArg, spikes, params.FormatName()), ret, nil), nil
 }
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -564,13 +565,17 @@ func gatherEventDiagnostics(params api.PromptHandlerParams, namespace string) (s
 	totalErrors := 0
 	var recentEvents []string
 
-	for _, ns := range namespaces {
-		eventList, err := params.CoreV1().Events(ns).List(params.Context, metav1.ListOptions{})
-		if err != nil {
+	// The namespaces are listed concurrently, the events are merged in the order of the namespaces
+	eventLists, errs := kubernetes.FanOut(params.Context, kubernetes.DefaultFanOutConcurrency, namespaces,
+		func(ctx context.Context, ns string) (*v1.EventList, error) {
+			return params.CoreV1().Events(ns).List(ctx, metav1.ListOptions{})
+		})
+	for i, ns := range namespaces {
+		if errs[i] != nil {
 			continue
 		}
 
-		for _, event := range eventList.Items {
+		for _, event := range eventLists[i].Items {
 			// Only include Warning and Error events
 			if event.Type != v1.EventTypeWarning && event.Type != "Error" {
 				continue
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+const (
+	// defaultLogsAnalyzeTail is the default number of lines fetched per container by the logs_analyze tool
+	defaultLogsAnalyzeTail = 1000
+	// defaultLogsAnalyzeLimit is the default number of patterns returned by the logs_analyze tool
+	defaultLogsAnalyzeLimit = 20
+)
+
+func initLogs() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "logs_analyze",
+			Description: "Analyze the logs of the Kubernetes Pods matching a label selector instead of dumping them: " +
+				"similar lines are clustered into patterns (the variable parts such as numbers, IDs, and IPs are replaced with <*>), " +
+				"returning the top patterns, errors first, with their number of occurrences, first and last seen timestamps, the Pods emitting them, and an example line",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace of the Pods. If not provided, will use the configured namespace",
+					},
+					"label_selector": {
+						Type:        "string",
+						Description: "Kubernetes label selector of the Pods to analyze the logs of (e.g. 'app=myapp,env=prod' or 'app in (myapp,yourapp)')",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+					"container": {
+						Type:        "string",
+						Description: "Optional name of the container to analyze the logs of. If not provided, will analyze the logs of all the containers",
+					},
+					"tail": {
+						Type:        "integer",
+						Description: "Number of lines to fetch from the end of the logs of each container (Optional, default: 1000)",
+						Default:     api.ToRawMessage(defaultLogsAnalyzeTail),
+						Minimum:     ptr.To(float64(1)),
+					},
+					"since": {
+						Type:        "string",
+						Description: "Optional duration (e.g. 10m, 1h) to only analyze the lines logged within. If not provided, the last lines are analyzed regardless of their age",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of patterns to return (Optional, default: 20)",
+						Default:     api.ToRawMessage(defaultLogsAnalyzeLimit),
+						Minimum:     ptr.To(float64(1)),
+					},
+				},
+				Required: []string{"label_selector"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Logs: Analyze",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: logsAnalyze},
+	}
+}
+
+func logsAnalyze(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	labelSelector, _ := params.GetArguments()["label_selector"].(string)
+	if labelSelector == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to analyze logs, missing argument label_selector"))), nil
+	}
+	container, _ := params.GetArguments()["container"].(string)
+	tail := int64(defaultLogsAnalyzeTail)
+	if v, ok := params.GetArguments()["tail"]; ok && v != nil {
+		var err error
+		if tail, err = api.ParseInt64(v); err != nil || tail < 1 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse tail parameter: %v", v))), nil
+		}
+	}
+	limit := int64(defaultLogsAnalyzeLimit)
+	if v, ok := params.GetArguments()["limit"]; ok && v != nil {
+		var err error
+		if limit, err = api.ParseInt64(v); err != nil || limit < 1 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse limit parameter: %v", v))), nil
+		}
+	}
+	var since time.Duration
+	if sinceArg, _ := params.GetArguments()["since"].(string); sinceArg != "" {
+		var err error
+		if since, err = time.ParseDuration(sinceArg); err != nil || since <= 0 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid since %s, expected a positive duration (e.g. 10m, 1h)", sinceArg))), nil
+		}
+	}
+	analysis, err := kubernetes.NewCore(params).LogsAnalyze(params, namespace, labelSelector, container, tail, since)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "logs analysis")
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze logs: %w", err)), nil
+	}
+	if analysis.MatchingPods == 0 {
+		return api.NewToolCallResult("# No pods found matching "+labelSelector, nil), nil
+	}
+	if analysis.Lines == 0 {
+		return api.NewToolCallResult(fmt.Sprintf("# No log lines found in %d pod(s) matching %s", analysis.Pods, labelSelector), nil), nil
+	}
+	patterns, errorPatterns := len(analysis.Patterns), 0
+	for _, pattern := range analysis.Patterns {
+		if pattern.Level == kubernetes.LogLevelError {
+			errorPatterns++
+		}
+	}
+	analysis.Patterns = analysis.Patterns[:min(len(analysis.Patterns), int(limit))]
+	ret, err := params.Marshal(analysis)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to analyze logs: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d line(s) clustered into %d pattern(s), %d error pattern(s), showing the top %d (%s format):\n",
+		analysis.Lines, patterns, errorPatterns, len(analysis.Patterns), params.FormatName()), ret, nil), nil
+}
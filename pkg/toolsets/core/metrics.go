@@ -0,0 +1,121 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/prometheus"
+)
+
+const (
+	// metricsQueryMaxSeries is the maximum number of series returned by the metrics_query tool
+	metricsQueryMaxSeries = 100
+	// metricsQueryRangePoints is the number of points of a range query when no step is provided
+	metricsQueryRangePoints = 60
+)
+
+func initMetrics() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "metrics_query",
+			Description: "Execute a PromQL query against the configured Prometheus ([toolset_configs.core.prometheus], a Prometheus URL or an in-cluster Service reached through the Kubernetes API server proxy) " +
+				"and return the result as a compact table, one row per series. Instant queries return the value of each series, " +
+				"range queries (when range is provided) the minimum, maximum, and last value of each series with a sample of its values. " +
+				"Useful to back diagnoses with real metrics (e.g. CPU throttling, request rates, error ratios)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"query": {
+						Type:        "string",
+						Description: "PromQL query to execute (e.g. sum by (pod) (rate(container_cpu_usage_seconds_total{namespace=\"default\"}[5m])))",
+					},
+					"range": {
+						Type:        "string",
+						Description: "Optional duration of a range query ending at time (e.g. 30m, 1h, 24h). If not provided, will execute an instant query",
+					},
+					"step": {
+						Type:        "string",
+						Description: "Optional resolution step of a range query (e.g. 30s, 5m). If not provided, will return 60 points",
+					},
+					"time": {
+						Type:        "string",
+						Description: "Optional evaluation time of an instant query or end of a range query, as an RFC3339 timestamp (e.g. 2025-01-01T14:05:00Z). If not provided, will use now",
+					},
+				},
+				Required: []string{"query"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Metrics: Query",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: metricsQuery},
+	}
+}
+
+func metricsQuery(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	query, ok := params.GetArguments()["query"].(string)
+	if !ok || query == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to query metrics, missing argument query"))), nil
+	}
+	at := time.Now()
+	if timeArg, _ := params.GetArguments()["time"].(string); timeArg != "" {
+		parsed, err := time.Parse(time.RFC3339, timeArg)
+		if err != nil {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid time %s, expected an RFC3339 timestamp (e.g. 2025-01-01T14:05:00Z)", timeArg))), nil
+		}
+		at = parsed
+	}
+	var queryRange, step time.Duration
+	var err error
+	if rangeArg, _ := params.GetArguments()["range"].(string); rangeArg != "" {
+		if queryRange, err = time.ParseDuration(rangeArg); err != nil || queryRange <= 0 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid range %s, expected a positive duration (e.g. 30m, 1h)", rangeArg))), nil
+		}
+		step = max((queryRange / metricsQueryRangePoints).Round(time.Second), time.Second)
+		if stepArg, _ := params.GetArguments()["step"].(string); stepArg != "" {
+			if step, err = time.ParseDuration(stepArg); err != nil || step <= 0 {
+				return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid step %s, expected a positive duration (e.g. 30s, 5m)", stepArg))), nil
+			}
+		}
+	}
+	prometheusConfig := toolsetConfig(params).Prometheus
+	if prometheusConfig == nil {
+		return api.NewToolCallResult("", errors.New("failed to query metrics: Prometheus is not configured, set [toolset_configs.core.prometheus] in the server configuration")), nil
+	}
+	client := prometheus.NewPrometheus(prometheusConfig, params.CoreV1().RESTClient(), params.RESTConfig())
+	var result *prometheus.Result
+	var period string
+	if queryRange > 0 {
+		result, err = client.QueryRange(params.Context, query, at.Add(-queryRange), at, step)
+		period = fmt.Sprintf("from %s to %s every %s", at.Add(-queryRange).UTC().Format(time.RFC3339), at.UTC().Format(time.RFC3339), step)
+	} else {
+		result, err = client.Query(params.Context, query, at)
+		period = "at " + at.UTC().Format(time.RFC3339)
+	}
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to query metrics: %w", err)), nil
+	}
+	sb := strings.Builder{}
+	for _, warning := range result.Warnings {
+		sb.WriteString("# Warning: " + warning + "\n")
+	}
+	if len(result.Series) == 0 {
+		return api.NewToolCallResult(sb.String()+"# No series found "+period, nil), nil
+	}
+	total := len(result.Series)
+	if total > metricsQueryMaxSeries {
+		result.Series = result.Series[:metricsQueryMaxSeries]
+		sb.WriteString(fmt.Sprintf("# Showing the first %d of %d series, aggregate the query (e.g. with sum by or topk) to reduce them\n", metricsQueryMaxSeries, total))
+	}
+	sb.WriteString(fmt.Sprintf("# %d %s series %s (table format):\n", total, result.Type, period))
+	sb.WriteString(prometheus.Table(result))
+	return api.NewToolCallResult(sb.String(), nil), nil
+}
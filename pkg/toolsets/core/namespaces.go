@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"k8s.io/utils/ptr"
@@ -10,6 +11,7 @@ import (
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
 )
 
 func initNamespaces(o api.Openshift) []api.ServerTool {
@@ -20,6 +22,14 @@ func initNamespaces(o api.Openshift) []api.ServerTool {
 			Description: "List all the Kubernetes namespaces in the current cluster",
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"jsonpath":        jsonPathProperty(),
+					"output_template": outputTemplateProperty(),
+					"prune":           pruneProperty(),
+					"detail":          detailProperty(),
+					"sort_by":         sortByProperty(output.SortByName, output.SortByCreationTimestamp),
+					"sort_order":      sortOrderProperty(),
+				},
 			},
 			Annotations: api.ToolAnnotations{
 				Title:           "Namespaces: List",
@@ -27,7 +37,74 @@ func initNamespaces(o api.Openshift) []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: namespacesList,
+		}, Handler: namespacesList, TableOutput: ptr.To(true),
+	})
+	ret = append(ret, api.ServerTool{
+		Tool: api.Tool{
+			Name: "namespace_export",
+			Description: "Export all the objects in a Kubernetes namespace as a multi-document YAML stream, with the fields populated by the cluster stripped " +
+				"(status, uid, resourceVersion, managedFields, cluster IPs, ...), useful to back up a namespace before a change or to clone it into another environment. " +
+				"Objects managed by a controller (e.g. the Pods of a Deployment), the objects created by the cluster in every namespace, and transient resources (Events, Endpoints, Leases) are not exported. " +
+				"Resources that can't be listed (e.g. denied or forbidden) are skipped and reported",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace to export (Optional, current namespace if not provided)",
+					},
+					"include_secrets": {
+						Type:        "boolean",
+						Description: "Include the Secrets (their data is exported as is) in the export (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Namespace: Export",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: namespaceExport,
+	})
+	ret = append(ret, api.ServerTool{
+		Tool: api.Tool{
+			Name: "namespace_diff",
+			Description: "Compare two Kubernetes namespaces (e.g. staging and production), or the same namespace on two clusters in multi-cluster mode, object by object, " +
+				"to answer drift questions. Reports the objects missing from either namespace and the differing fields (path and both values) of the objects present in both. " +
+				"The objects are compared as exported by namespace_export: the fields populated by the cluster, the objects managed by a controller, and transient resources are ignored. " +
+				"Resources that can't be listed (e.g. denied or forbidden) are skipped and reported",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace to compare (Optional, current namespace if not provided)",
+					},
+					"other_namespace": {
+						Type:        "string",
+						Description: "Namespace to compare with (Optional, the same namespace if not provided, other_target is then required)",
+					},
+					"other_target": {
+						Type:        "string",
+						Description: "Cluster or context of the namespace to compare with, in multi-cluster mode (Optional, the target of the tool call if not provided)",
+					},
+					"include_secrets": {
+						Type:        "boolean",
+						Description: "Include the Secrets in the comparison, the differing values of their data are reported as is (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Namespace: Diff",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: namespaceDiff,
 	})
 	if o.IsOpenShift(context.Background()) {
 		ret = append(ret, api.ServerTool{
@@ -36,6 +113,14 @@ func initNamespaces(o api.Openshift) []api.ServerTool {
 				Description: "List all the OpenShift projects in the current cluster",
 				InputSchema: &jsonschema.Schema{
 					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"jsonpath":        jsonPathProperty(),
+						"output_template": outputTemplateProperty(),
+						"prune":           pruneProperty(),
+						"detail":          detailProperty(),
+						"sort_by":         sortByProperty(output.SortByName, output.SortByCreationTimestamp),
+						"sort_order":      sortOrderProperty(),
+					},
 				},
 				Annotations: api.ToolAnnotations{
 					Title:           "Projects: List",
@@ -43,26 +128,97 @@ func initNamespaces(o api.Openshift) []api.ServerTool {
 					DestructiveHint: ptr.To(false),
 					OpenWorldHint:   ptr.To(true),
 				},
-			}, Handler: projectsList,
+			}, Handler: projectsList, TableOutput: ptr.To(true),
 		})
 	}
 	return ret
 }
 
 func namespacesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
-	ret, err := kubernetes.NewCore(params).NamespacesList(params, api.ListOptions{AsTable: params.ListOutput.AsTable()})
+	ret, err := kubernetes.NewCore(params).NamespacesList(params, api.ListOptions{AsTable: listAsTable(params)})
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "namespace listing")
 		return api.NewToolCallResult("", fmt.Errorf("failed to list namespaces: %w", err)), nil
 	}
-	return api.NewToolCallResult(params.ListOutput.PrintObj(ret)), nil
+	return api.NewToolCallResult(printList(params, ret)), nil
+}
+
+func namespaceExport(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	includeSecrets, _ := params.GetArguments()["include_secrets"].(bool)
+	export, err := kubernetes.NewCore(params).NamespaceExport(params, namespace, includeSecrets)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "namespace export")
+		return api.NewToolCallResult("", fmt.Errorf("failed to export namespace: %w", err)), nil
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Exported %d object(s) from namespace %s (YAML format)\n", len(export.Objects), export.Namespace))
+	if len(export.Skipped) > 0 {
+		sb.WriteString("# Skipped resources: " + strings.Join(export.Skipped, ", ") + "\n")
+	}
+	for _, obj := range export.Objects {
+		yaml, err := output.MarshalYaml(obj.Object)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to export namespace: %w", err)), nil
+		}
+		sb.WriteString("---\n" + yaml)
+	}
+	return api.NewToolCallResult(sb.String(), nil), nil
+}
+
+func namespaceDiff(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	core := kubernetes.NewCore(params)
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	namespace = core.NamespaceOrDefault(namespace)
+	otherNamespace, _ := params.GetArguments()["other_namespace"].(string)
+	otherTarget, _ := params.GetArguments()["other_target"].(string)
+	includeSecrets, _ := params.GetArguments()["include_secrets"].(bool)
+	otherCore := core
+	if otherTarget != "" && otherTarget != params.Target {
+		if params.TargetClient == nil {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument,
+				fmt.Errorf("failed to compare namespaces, other_target is not supported"))), nil
+		}
+		client, err := params.TargetClient(params, otherTarget)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to compare namespaces, invalid other_target %s: %w", otherTarget, err)), nil
+		}
+		otherCore = kubernetes.NewCore(client)
+	} else if otherNamespace == "" || otherNamespace == namespace {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument,
+			fmt.Errorf("failed to compare namespaces, other_namespace or other_target must differ from the namespace and target of the tool call"))), nil
+	}
+	if otherNamespace == "" {
+		otherNamespace = namespace
+	}
+	export, err := core.NamespaceExport(params, namespace, includeSecrets)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "namespace diff")
+		return api.NewToolCallResult("", fmt.Errorf("failed to compare namespaces, failed to export namespace %s: %w", namespace, err)), nil
+	}
+	otherExport, err := otherCore.NamespaceExport(params, otherNamespace, includeSecrets)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "namespace diff")
+		return api.NewToolCallResult("", fmt.Errorf("failed to compare namespaces, failed to export namespace %s: %w", otherNamespace, err)), nil
+	}
+	diff := kubernetes.DiffNamespaces(export, otherExport)
+	ret, err := params.Marshal(diff)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to compare namespaces: %w", err)), nil
+	}
+	compared := fmt.Sprintf("namespace %s with namespace %s", diff.Namespace, diff.OtherNamespace)
+	if otherCore != core {
+		compared = fmt.Sprintf("namespace %s of %s with namespace %s of %s", diff.Namespace, params.Target, diff.OtherNamespace, otherTarget)
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Comparison of %s: %d identical, %d different, %d missing object(s) (%s format):\n",
+		compared, diff.Identical, len(diff.Differences), len(diff.OnlyInNamespace)+len(diff.OnlyInOtherNamespace), params.FormatName()), ret, nil), nil
 }
 
 func projectsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
-	ret, err := kubernetes.NewCore(params).ProjectsList(params, api.ListOptions{AsTable: params.ListOutput.AsTable()})
+	ret, err := kubernetes.NewCore(params).ProjectsList(params, api.ListOptions{AsTable: listAsTable(params)})
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "project listing")
 		return api.NewToolCallResult("", fmt.Errorf("failed to list projects: %w", err)), nil
 	}
-	return api.NewToolCallResult(params.ListOutput.PrintObj(ret)), nil
+	return api.NewToolCallResult(printList(params, ret)), nil
 }
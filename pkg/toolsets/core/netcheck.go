@@ -0,0 +1,115 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+const (
+	// defaultNetCheckImage is the default image of the net_check diagnostic Pod, it must provide sh, nslookup, nc, and wget
+	defaultNetCheckImage = "busybox:1.37"
+	// defaultNetCheckTimeout is the default time in seconds the net_check tool waits for the diagnostic Pod to complete
+	defaultNetCheckTimeout = 60
+)
+
+func initNetCheck() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "net_check",
+			Description: "Test DNS resolution and connectivity to a target from inside the Kubernetes cluster. " +
+				"Launches a short-lived diagnostic Pod that runs a DNS lookup, TCP connects to the target ports, and HTTP requests, returns the result of each check, and deletes the Pod once done. " +
+				"When the target is a Service and no port is provided, all of its TCP ports are checked (and HTTP requests are sent to the ports named http*)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"target": {
+						Type:        "string",
+						Description: "Target to check: a Service name (in the provided namespace), a DNS name (e.g. my-service.other-namespace.svc), a host name, or an IP address",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace where the diagnostic Pod runs and where the target Service is looked up (Optional, current namespace if not provided)",
+					},
+					"port": {
+						Type:        "integer",
+						Description: "Port to check (Optional, all the TCP ports of the target Service if not provided)",
+						Minimum:     ptr.To(float64(1)),
+						Maximum:     ptr.To(float64(65535)),
+					},
+					"http_path": {
+						Type:        "string",
+						Description: "Path of the HTTP GET request to send to each checked port (e.g. /healthz) (Optional)",
+					},
+					"image": {
+						Type:        "string",
+						Description: "Image of the diagnostic Pod, it must provide sh, nslookup, nc, and wget (Optional, default: " + defaultNetCheckImage + ")",
+						Default:     api.ToRawMessage(defaultNetCheckImage),
+					},
+					"timeout": {
+						Type:        "integer",
+						Description: "Maximum time in seconds to wait for the checks to complete (Optional, default: 60)",
+						Default:     api.ToRawMessage(defaultNetCheckTimeout),
+						Minimum:     ptr.To(float64(1)),
+					},
+				},
+				Required: []string{"target"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Network: Check",
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: netCheck, Permissions: netCheckPermissions, LongRunning: ptr.To(true)},
+	}
+}
+
+func netCheck(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	options := kubernetes.NetCheckOptions{Image: defaultNetCheckImage, Timeout: defaultNetCheckTimeout * time.Second}
+	options.Target, _ = params.GetArguments()["target"].(string)
+	if options.Target == "" {
+		return api.NewToolCallResult("", errors.New("failed to check network, missing argument target")), nil
+	}
+	options.Namespace, _ = params.GetArguments()["namespace"].(string)
+	options.HTTPPath, _ = params.GetArguments()["http_path"].(string)
+	if image, _ := params.GetArguments()["image"].(string); image != "" {
+		options.Image = image
+	}
+	if v, ok := params.GetArguments()["port"]; ok && v != nil {
+		port, err := api.ParseInt64(v)
+		if err != nil || port < 1 || port > 65535 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse port parameter: %v", v))), nil
+		}
+		options.Port = int32(port)
+	}
+	if v, ok := params.GetArguments()["timeout"]; ok && v != nil {
+		timeout, err := api.ParseInt64(v)
+		if err != nil || timeout < 1 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse timeout parameter: %v", v))), nil
+		}
+		options.Timeout = time.Duration(timeout) * time.Second
+	}
+	result, err := kubernetes.NewCore(params).NetCheck(params, options)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "network check")
+		return api.NewToolCallResult("", fmt.Errorf("failed to check network connectivity to %s: %w", options.Target, err)), nil
+	}
+	failed := 0
+	for _, check := range result.Checks {
+		if !check.Success {
+			failed++
+		}
+	}
+	ret, err := params.Marshal(result)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to check network connectivity to %s: %w", options.Target, err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d of %d check(s) failed for %s (%s format):\n", failed, len(result.Checks), result.Host, params.FormatName()), ret, nil), nil
+}
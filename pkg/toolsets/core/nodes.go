@@ -2,8 +2,12 @@ package core
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	v1 "k8s.io/api/core/v1"
@@ -14,14 +18,42 @@ import (
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/locale"
 	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
 )
 
 func initNodes() []api.ServerTool {
+	kubeletEndpoints := make([]any, 0, len(kubernetes.NodesKubeletEndpoints))
+	for _, endpoint := range kubernetes.NodesKubeletEndpoints {
+		kubeletEndpoints = append(kubeletEndpoints, endpoint)
+	}
 	return []api.ServerTool{
 		{Tool: api.Tool{
-			Name:        "nodes_log",
-			Description: "Get logs from a Kubernetes node (kubelet, kube-proxy, or other system logs). This accesses node logs through the Kubernetes API proxy to the kubelet",
+			Name: "nodes_health",
+			Description: "Summarize the health of the Kubernetes Nodes: conditions (NotReady, MemoryPressure, DiskPressure, PIDPressure) and for how long they have been present, " +
+				"kubelet and container runtime versions, and allocatable vs requested resources (CPU, memory, pods). " +
+				"Outliers (not ready or cordoned nodes, pressure conditions, requests above 90% of the allocatable resources, version skew) are reported as issues",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"label_selector": {
+						Type:        "string",
+						Description: "Kubernetes label selector (e.g. 'node-role.kubernetes.io/worker=') to filter nodes by label (Optional, all Nodes if not provided)",
+						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Nodes: Health",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesHealth, Localized: ptr.To(true)},
+		{Tool: api.Tool{
+			Name: "nodes_log",
+			Description: "Get logs from a Kubernetes node (kubelet, kube-proxy, or other system logs). This accesses node logs through the Kubernetes API proxy to the kubelet. " +
+				"The logs of the services (journal units such as kubelet, crio, or containerd) require the NodeLogQuery feature to be enabled on the kubelet",
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
@@ -39,6 +71,25 @@ func initNodes() []api.ServerTool {
 						Default:     api.ToRawMessage(100),
 						Minimum:     ptr.To(float64(0)),
 					},
+					"sinceTime": {
+						Type:        "string",
+						Description: "Only return the logs after this time in RFC 3339 format (e.g. 2024-01-01T10:00:00Z) (Optional)",
+						Format:      "date-time",
+					},
+					"untilTime": {
+						Type:        "string",
+						Description: "Only return the logs before this time in RFC 3339 format (e.g. 2024-01-01T11:00:00Z) (Optional)",
+						Format:      "date-time",
+					},
+					"pattern": {
+						Type:        "string",
+						Description: "Only return the log lines matching this regular expression (e.g. 'error|failed') (Optional)",
+					},
+					"boot": {
+						Type:        "integer",
+						Description: "Boot of the journal to return the service logs from, 0 for the current boot, -1 for the previous boot (Optional, only applicable to services)",
+						Maximum:     ptr.To(float64(0)),
+					},
 				},
 				Required: []string{"name", "query"},
 			},
@@ -69,9 +120,37 @@ func initNodes() []api.ServerTool {
 				OpenWorldHint:   ptr.To(true),
 			},
 		}, Handler: nodesStatsSummary},
+		{Tool: api.Tool{
+			Name: "nodes_kubelet",
+			Description: "Get the running configuration (configz) or the health checks (healthz) of the kubelet of a Kubernetes node through the Kubernetes API proxy to the kubelet, " +
+				"to diagnose the node-level problems (eviction thresholds, resource reservations, runtime endpoint, failing health checks) that the Pod logs can't explain",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the node to get the kubelet information from",
+					},
+					"endpoint": {
+						Type:        "string",
+						Description: "Kubelet endpoint to get, configz for the running kubelet configuration, healthz for the kubelet health checks (Optional, default: configz)",
+						Enum:        kubeletEndpoints,
+						Default:     api.ToRawMessage("configz"),
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Node: Kubelet",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesKubelet},
 		{Tool: api.Tool{
 			Name:        "nodes_top",
-			Description: "List the resource consumption (CPU and memory) as recorded by the Kubernetes Metrics Server for the specified Kubernetes Nodes or all nodes in the cluster",
+			Description: "List the resource consumption (CPU and memory) as recorded by the Kubernetes Metrics Server (or by the kubelet Summary API when the Metrics Server is not available) for the specified Kubernetes Nodes or all nodes in the cluster",
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
@@ -84,6 +163,11 @@ func initNodes() []api.ServerTool {
 						Description: "Kubernetes label selector (e.g. 'node-role.kubernetes.io/worker=') to filter nodes by label (Optional, only applicable when name is not provided)",
 						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
 					},
+					"sort_by": {
+						Type:        "string",
+						Description: "Sort the Nodes by their resource consumption, highest first (Optional, sorted by name if not provided)",
+						Enum:        []any{"cpu", "memory"},
+					},
 				},
 			},
 			Annotations: api.ToolAnnotations{
@@ -97,6 +181,30 @@ func initNodes() []api.ServerTool {
 	}
 }
 
+func nodesHealth(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	labelSelector, _ := params.GetArguments()["label_selector"].(string)
+	health, err := kubernetes.NewCore(params).NodesHealth(params, labelSelector)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "node health summary")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes health: %w", err)), nil
+	}
+	p := locale.FromContext(params)
+	if len(health) == 0 {
+		return api.NewToolCallResult(p.Sprintf("# No nodes found"), nil), nil
+	}
+	withIssues := 0
+	for _, node := range health {
+		if len(node.Issues) > 0 {
+			withIssues++
+		}
+	}
+	ret, err := params.Marshal(health)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get nodes health: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(p.Sprintf("# %d node(s), %d with issues (%s format):\n", len(health), withIssues, params.FormatName()), ret, nil), nil
+}
+
 func nodesLog(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	name, ok := params.GetArguments()["name"].(string)
 	if !ok || name == "" {
@@ -106,16 +214,36 @@ func nodesLog(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	if !ok || query == "" {
 		return api.NewToolCallResult("", errors.New("failed to get node log, missing argument query")), nil
 	}
-	tailLines := params.GetArguments()["tailLines"]
-	var tailInt int64
-	if tailLines != nil {
+	options := api.NodesLogOptions{Query: query}
+	if tailLines := params.GetArguments()["tailLines"]; tailLines != nil {
 		var err error
-		tailInt, err = api.ParseInt64(tailLines)
+		options.TailLines, err = api.ParseInt64(tailLines)
 		if err != nil {
 			return api.NewToolCallResult("", fmt.Errorf("failed to parse tailLines parameter: %w", err)), nil
 		}
 	}
-	ret, err := kubernetes.NewCore(params).NodesLog(params, name, query, tailInt)
+	for argument, value := range map[string]*string{"sinceTime": &options.SinceTime, "untilTime": &options.UntilTime} {
+		if v, _ := params.GetArguments()[argument].(string); v != "" {
+			if _, err := time.Parse(time.RFC3339, v); err != nil {
+				return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse %s parameter, expected RFC 3339 format: %w", argument, err))), nil
+			}
+			*value = v
+		}
+	}
+	if v, _ := params.GetArguments()["pattern"].(string); v != "" {
+		if _, err := regexp.Compile(v); err != nil {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse pattern parameter: %w", err))), nil
+		}
+		options.Pattern = v
+	}
+	if boot := params.GetArguments()["boot"]; boot != nil {
+		b, err := api.ParseInt64(boot)
+		if err != nil || b > 0 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse boot parameter, expected 0 or a negative integer: %v", boot))), nil
+		}
+		options.Boot = &b
+	}
+	ret, err := kubernetes.NewCore(params).NodesLog(params, name, options)
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "node log access")
 		return api.NewToolCallResult("", fmt.Errorf("failed to get node log for %s: %w", name, err)), nil
@@ -138,6 +266,39 @@ func nodesStatsSummary(params api.ToolHandlerParams) (*api.ToolCallResult, error
 	return api.NewToolCallResult(ret, nil), nil
 }
 
+func nodesKubelet(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, ok := params.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		return api.NewToolCallResult("", errors.New("failed to get node kubelet information, missing argument name")), nil
+	}
+	endpoint, _ := params.GetArguments()["endpoint"].(string)
+	if endpoint == "" {
+		endpoint = "configz"
+	}
+	if !slices.Contains(kubernetes.NodesKubeletEndpoints, endpoint) {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to get node kubelet information, unsupported endpoint %s, expected one of %v", endpoint, kubernetes.NodesKubeletEndpoints))), nil
+	}
+	ret, err := kubernetes.NewCore(params).NodesKubelet(params, name, endpoint)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "node kubelet access")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get kubelet %s for node %s: %w", endpoint, name, err)), nil
+	}
+	if endpoint == "configz" {
+		// the configz endpoint returns the KubeletConfiguration wrapped in a kubeletconfig JSON field
+		var configz map[string]any
+		if err = json.Unmarshal([]byte(ret), &configz); err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to parse kubelet configz for node %s: %w", name, err)), nil
+		}
+		if config, ok := configz["kubeletconfig"]; ok {
+			if ret, err = params.Marshal(config); err != nil {
+				return api.NewToolCallResult("", fmt.Errorf("failed to get kubelet configz for node %s: %w", name, err)), nil
+			}
+		}
+		return api.NewStructuredToolCallResult(fmt.Sprintf("# Running kubelet configuration of node %s (%s format):\n", name, params.FormatName()), ret, nil), nil
+	}
+	return api.NewToolCallResult(fmt.Sprintf("# Kubelet health checks of node %s:\n%s", name, ret), nil), nil
+}
+
 func nodesTop(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	nodesTopOptions := api.NodesTopOptions{}
 	if v, ok := params.GetArguments()["name"].(string); ok {
@@ -146,6 +307,7 @@ func nodesTop(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	if v, ok := params.GetArguments()["label_selector"].(string); ok {
 		nodesTopOptions.LabelSelector = v
 	}
+	sortBy, _ := params.GetArguments()["sort_by"].(string)
 
 	nodeMetrics, err := kubernetes.NewCore(params).NodesTop(params, nodesTopOptions)
 	if err != nil {
@@ -177,7 +339,7 @@ func nodesTop(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	// Print the metrics
 	buf := new(bytes.Buffer)
 	printer := metricsutil.NewTopCmdPrinter(buf, true)
-	err = printer.PrintNodeMetrics(nodeMetrics.Items, availableResources, false, "")
+	err = printer.PrintNodeMetrics(nodeMetrics.Items, availableResources, false, sortBy)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("failed to print node metrics: %w", err)), nil
 	}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+// defaultNodesDebugTimeout is the default time in seconds the nodes_debug tool waits for the command to complete
+const defaultNodesDebugTimeout = 60
+
+func initNodesDebug() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "nodes_debug",
+			Description: "Run a command on a Kubernetes Node without SSH, from a short-lived debug Pod scheduled on the Node with its host network, PID, and IPC namespaces " +
+				"and its root filesystem mounted at " + kubernetes.NodeDebugHostPath + " (like kubectl debug node), return the output of the command, and delete the Pod once done. " +
+				"Use it to inspect the filesystem (e.g. [\"cat\", \"/host/etc/resolv.conf\"]), the network (e.g. [\"ip\", \"route\"]), or the processes of the Node, " +
+				"run the binaries of the Node with chroot (e.g. [\"chroot\", \"/host\", \"crictl\", \"ps\"]). " +
+				"Only available if enabled in the server configuration ([toolset_configs.core.node_debug])",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the Node",
+					},
+					"command": {
+						Type:        "array",
+						Description: "Command to run and its arguments, not interpreted by a shell unless run with sh -c (e.g. [\"sh\", \"-c\", \"df -h /host | tail -1\"])",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+					"timeout": {
+						Type:        "integer",
+						Description: "Maximum time in seconds to wait for the command to complete (Optional, default: 60)",
+						Default:     api.ToRawMessage(defaultNodesDebugTimeout),
+						Minimum:     ptr.To(float64(1)),
+					},
+				},
+				Required: []string{"name", "command"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Node: Debug",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: nodesDebug, Permissions: nodesDebugPermissions, LongRunning: ptr.To(true)},
+	}
+}
+
+func nodesDebug(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	cfg := toolsetConfig(params).NodeDebug
+	if cfg == nil {
+		return api.NewToolCallResult("", errors.New("failed to debug node: node debug is not enabled, set [toolset_configs.core.node_debug] in the server configuration")), nil
+	}
+	options := kubernetes.NodeDebugOptions{Timeout: defaultNodesDebugTimeout * time.Second}
+	options.Node, _ = params.GetArguments()["name"].(string)
+	if options.Node == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to debug node, missing argument name"))), nil
+	}
+	if v, ok := params.GetArguments()["command"].([]any); ok {
+		for _, arg := range v {
+			s, ok := arg.(string)
+			if !ok {
+				return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to debug node, the command must be an array of strings"))), nil
+			}
+			options.Command = append(options.Command, s)
+		}
+	}
+	if len(options.Command) == 0 {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to debug node, missing argument command"))), nil
+	}
+	if v, ok := params.GetArguments()["timeout"]; ok && v != nil {
+		timeout, err := api.ParseInt64(v)
+		if err != nil || timeout < 1 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse timeout parameter: %v", v))), nil
+		}
+		options.Timeout = time.Duration(timeout) * time.Second
+	}
+	result, err := kubernetes.NewCore(params).NodesDebug(params, cfg, options)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "node debug")
+		return api.NewToolCallResult("", fmt.Errorf("failed to debug node %s: %w", options.Node, err)), nil
+	}
+	privileged := ""
+	if result.Privileged {
+		privileged = "privileged "
+	}
+	return api.NewToolCallResult(fmt.Sprintf("# Command exited with code %d on node %s (%sdebug Pod %s/%s with image %s, deleted)\n%s",
+		result.ExitCode, result.Node, privileged, result.Namespace, result.Pod, result.Image, result.Output), nil), nil
+}
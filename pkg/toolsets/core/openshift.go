@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+// initOpenShift returns the tools for the OpenShift specific resources (Routes, DeploymentConfigs), only registered
+// when the cluster is OpenShift (Projects are listed by the projects_list tool).
+func initOpenShift(o api.Openshift) []api.ServerTool {
+	if !o.IsOpenShift(context.Background()) {
+		return nil
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "routes_list",
+			Description: "List the OpenShift Routes with their URL, target Services (and traffic split), target port, TLS termination and certificate expiry, " +
+				"and whether they were admitted by the routers. Routes pointing to missing Services, not admitted, or with an expired or mismatching certificate are flagged",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace to list the Routes from (Optional, all namespaces if not provided)",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Routes: List",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: routesList},
+		{Tool: api.Tool{
+			Name: "deploymentconfig_rollout_status",
+			Description: "Get the rollout status of an OpenShift DeploymentConfig (read-only, it doesn't start a rollout): latest version, replicas, triggers, and conditions, " +
+				"together with the history of its rollouts (version, phase, ReplicationController, and images of each one, latest first)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the DeploymentConfig (Optional, current namespace if not provided)",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the DeploymentConfig",
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "DeploymentConfig: Rollout Status",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: deploymentConfigRollout},
+	}
+}
+
+func routesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	routes, err := kubernetes.NewCore(params).RoutesList(params, namespace)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "route listing")
+		return api.NewToolCallResult("", fmt.Errorf("failed to list routes: %w", err)), nil
+	}
+	if len(routes) == 0 {
+		return api.NewToolCallResult("# No routes found", nil), nil
+	}
+	withIssues := 0
+	for _, route := range routes {
+		if len(route.Issues) > 0 {
+			withIssues++
+		}
+	}
+	ret, err := params.Marshal(routes)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list routes: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d route(s) found, %d with issues (%s format):\n", len(routes), withIssues, params.FormatName()), ret, nil), nil
+}
+
+func deploymentConfigRollout(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	name, ok := params.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to get deploymentconfig rollout status, missing argument name"))), nil
+	}
+	rollout, err := kubernetes.NewCore(params).DeploymentConfigRollout(params, namespace, name)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "deploymentconfig rollout status")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get deploymentconfig rollout status: %w", err)), nil
+	}
+	ret, err := params.Marshal(rollout)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get deploymentconfig rollout status: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# DeploymentConfig %s/%s at version %d, %d/%d replica(s) available (%s format):\n",
+		rollout.Namespace, rollout.Name, rollout.LatestVersion, rollout.AvailableReplicas, rollout.Replicas, params.FormatName()), ret, nil), nil
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+func initOwners() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "owners_tree",
+			Description: "Get the ownership tree of a Kubernetes resource, walking its ownerReferences up to the roots that actually control it " +
+				"(e.g. Pod → ReplicaSet → Deployment → HelmRelease), as used by the garbage collector. " +
+				"The roots deployed by Helm, Flux or Argo CD are reported with the release or application managing them, " +
+				"and broken ownerReferences (owner deleted or recreated) are flagged. " +
+				"Optionally lists all the dependents of the resource too (the objects deleted with it)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"apiVersion": {
+						Type:        "string",
+						Description: "apiVersion of the resource (examples of valid apiVersion are: v1, apps/v1, networking.k8s.io/v1)",
+					},
+					"kind": {
+						Type:        "string",
+						Description: "kind of the resource (examples of valid kind are: Pod, Service, Deployment, Ingress)",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace of the resource (ignored in case of cluster scoped resources). If not provided, will use the configured namespace",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the resource",
+					},
+					"dependents": {
+						Type:        "boolean",
+						Description: "Include the dependents of the resource, looked up in all the resources of its namespace (or of the cluster for cluster scoped resources) (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+				Required: []string{"apiVersion", "kind", "name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Owners: Tree",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: ownersTree},
+	}
+}
+
+func ownersTree(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	gvk, err := parseGroupVersionKind(params.GetArguments())
+	if err != nil {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to get ownership tree, %s", err))), nil
+	}
+	name, _ := params.GetArguments()["name"].(string)
+	if name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to get ownership tree, missing argument name"))), nil
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	dependents, _ := params.GetArguments()["dependents"].(bool)
+	tree, err := newCore(params).OwnersTree(params, gvk, namespace, name, dependents)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "ownership tree retrieval")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get ownership tree of %s %s: %w", gvk.Kind, name, err)), nil
+	}
+	ret, err := params.Marshal(tree)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get ownership tree of %s %s: %w", gvk.Kind, name, err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Ownership tree of %s %s (%s format):\n", gvk.Kind, name, params.FormatName()), ret, nil), nil
+}
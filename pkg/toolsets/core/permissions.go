@@ -0,0 +1,211 @@
+package core
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+// The functions of this file return the Kubernetes API permissions required by the calls of the mutating tools,
+// checked by the RBAC preflight (rbac_preflight) before calling their handlers.
+
+// inNamespace sets the namespace (the configured one if not provided) of the permissions
+func inNamespace(params api.ToolHandlerParams, namespace string, permissions ...api.Permission) []api.Permission {
+	namespace = kubernetes.NewCore(params).NamespaceOrDefault(namespace)
+	for i := range permissions {
+		permissions[i].Namespace = namespace
+	}
+	return permissions
+}
+
+func podsDeletePermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	return inNamespace(params, namespace,
+		api.Permission{Verb: "get", Resource: "pods"},
+		api.Permission{Verb: "delete", Resource: "pods"},
+	), nil
+}
+
+// transientCleanupPermissions returns the permissions to list and delete the transient Pods, cluster-wide if no namespace is provided
+func transientCleanupPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	return []api.Permission{
+		{Verb: "list", Resource: "pods", Namespace: namespace},
+		{Verb: "delete", Resource: "pods", Namespace: namespace},
+	}, nil
+}
+
+func podsEvictPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	return inNamespace(params, namespace,
+		api.Permission{Verb: "get", Resource: "pods"},
+		api.Permission{Verb: "create", Resource: "pods", Subresource: "eviction"},
+	), nil
+}
+
+func podsExecPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	return inNamespace(params, namespace,
+		api.Permission{Verb: "get", Resource: "pods"},
+		api.Permission{Verb: "create", Resource: "pods", Subresource: "exec"},
+	), nil
+}
+
+func podsResizePermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	return inNamespace(params, namespace,
+		api.Permission{Verb: "get", Resource: "pods"},
+		api.Permission{Verb: "patch", Resource: "pods", Subresource: "resize"},
+	), nil
+}
+
+// podsRunPermissions returns the permissions to create the Pod, and its Service if a port is exposed, with server-side apply
+func podsRunPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	permissions := []api.Permission{{Verb: "create", Resource: "pods"}, {Verb: "patch", Resource: "pods"}}
+	if port, _ := params.GetArguments()["port"].(float64); port > 0 {
+		permissions = append(permissions, api.Permission{Verb: "create", Resource: "services"}, api.Permission{Verb: "patch", Resource: "services"})
+	}
+	return inNamespace(params, namespace, permissions...), nil
+}
+
+// netCheckPermissions returns the permissions to look up the target Service, run the diagnostic Pod, read its logs,
+// and delete it once done
+func netCheckPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	return inNamespace(params, namespace,
+		api.Permission{Verb: "get", Resource: "services"},
+		api.Permission{Verb: "create", Resource: "pods"},
+		api.Permission{Verb: "get", Resource: "pods"},
+		api.Permission{Verb: "get", Resource: "pods", Subresource: "log"},
+		api.Permission{Verb: "delete", Resource: "pods"},
+	), nil
+}
+
+// serviceProxyRequestPermissions returns the permission of the request method on the proxy subresource of the
+// Service or Pod (get for GET, create for POST)
+func serviceProxyRequestPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	kind, _ := params.GetArguments()["kind"].(string)
+	kind = cmp.Or(kind, "Service")
+	if !slices.Contains(kubernetes.ServiceProxyKinds, kind) {
+		return nil, fmt.Errorf("unsupported kind %s", kind)
+	}
+	verb := "get"
+	if method, _ := params.GetArguments()["method"].(string); strings.EqualFold(method, "POST") {
+		verb = "create"
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	return inNamespace(params, namespace,
+		api.Permission{Verb: verb, Resource: strings.ToLower(kind) + "s", Subresource: "proxy"},
+	), nil
+}
+
+func resourcesCreateOrUpdatePermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	resource, _ := params.GetArguments()["resource"].(string)
+	if resource == "" {
+		return nil, errors.New("missing argument resource")
+	}
+	resource, err := resolveWorkspaceReference(params, resource)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewCore(params).ManifestPermissions(params, resource)
+}
+
+func resourcesDeletePermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	gvk, err := parseGroupVersionKind(params.GetArguments())
+	if err != nil {
+		return nil, err
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	permission, err := kubernetes.NewCore(params).ResourcePermission(gvk, namespace, "delete", "")
+	if err != nil {
+		return nil, err
+	}
+	return []api.Permission{permission}, nil
+}
+
+// resourcesScalePermissions returns the permissions to get the scale of the resource, and to update it if a scale is provided
+func resourcesScalePermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	gvk, err := parseGroupVersionKind(params.GetArguments())
+	if err != nil {
+		return nil, err
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	permission, err := kubernetes.NewCore(params).ResourcePermission(gvk, namespace, "get", "scale")
+	if err != nil {
+		return nil, err
+	}
+	permissions := []api.Permission{permission}
+	if _, ok := params.GetArguments()["scale"]; ok {
+		permission.Verb = "update"
+		permissions = append(permissions, permission)
+	}
+	return permissions, nil
+}
+
+func workloadsSetImagePermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	kind, _ := params.GetArguments()["kind"].(string)
+	if !slices.Contains(kubernetes.WorkloadImageKinds, kind) {
+		return nil, fmt.Errorf("unsupported workload kind %s", kind)
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	resource := strings.ToLower(kind) + "s"
+	return inNamespace(params, namespace,
+		api.Permission{Verb: "get", Group: "apps", Resource: resource},
+		api.Permission{Verb: "patch", Group: "apps", Resource: resource},
+	), nil
+}
+
+// nodesDebugPermissions returns the permissions to run the debug Pod, read its logs, and delete it once done
+func nodesDebugPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	cfg := toolsetConfig(params).NodeDebug
+	if cfg == nil {
+		return nil, errors.New("node debug is not enabled")
+	}
+	return append([]api.Permission{{Verb: "get", Resource: "nodes"}}, inNamespace(params, cfg.Namespace,
+		api.Permission{Verb: "create", Resource: "pods"},
+		api.Permission{Verb: "get", Resource: "pods"},
+		api.Permission{Verb: "get", Resource: "pods", Subresource: "log"},
+		api.Permission{Verb: "delete", Resource: "pods"},
+	)...), nil
+}
+
+// configRolloutPermissions returns the permissions to read the configuration and find the workloads referencing it,
+// and unless dry_run is true, to update the configuration and restart the workloads (and follow their rollouts)
+func configRolloutPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	kind, _ := params.GetArguments()["kind"].(string)
+	if !slices.Contains(kubernetes.ConfigRolloutKinds, kind) {
+		return nil, fmt.Errorf("unsupported configuration kind %s", kind)
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	resource := strings.ToLower(kind) + "s"
+	workloads := []string{"deployments", "statefulsets", "daemonsets"}
+	permissions := []api.Permission{{Verb: "get", Resource: resource}}
+	for _, workload := range workloads {
+		permissions = append(permissions, api.Permission{Verb: "list", Group: "apps", Resource: workload})
+	}
+	if dryRun, _ := params.GetArguments()["dry_run"].(bool); !dryRun {
+		permissions = append(permissions, api.Permission{Verb: "update", Resource: resource})
+		for _, workload := range workloads {
+			permissions = append(permissions, api.Permission{Verb: "get", Group: "apps", Resource: workload},
+				api.Permission{Verb: "patch", Group: "apps", Resource: workload})
+		}
+	}
+	return inNamespace(params, namespace, permissions...), nil
+}
+
+// transactionsRollbackPermissions returns the permissions to delete the resources created by the transaction, and
+// to restore the ones it updated
+func transactionsRollbackPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	id, _ := params.GetArguments()["id"].(string)
+	if id == "" {
+		return nil, errors.New("missing argument id")
+	}
+	return kubernetes.NewCore(params).TransactionPermissions(id)
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"k8s.io/kubectl/pkg/metricsutil"
@@ -15,6 +17,9 @@ import (
 	"github.com/containers/kubernetes-mcp-server/pkg/output"
 )
 
+// defaultPodsResizeTimeout is the default time in seconds the pods_resize tool waits for the kubelet to apply the resize
+const defaultPodsResizeTimeout = 30
+
 func initPods() []api.ServerTool {
 	return []api.ServerTool{
 		{Tool: api.Tool{
@@ -23,6 +28,7 @@ func initPods() []api.ServerTool {
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					"bypass_cache": bypassCacheProperty(),
 					"labelSelector": {
 						Type:        "string",
 						Description: "Optional Kubernetes label selector (e.g. 'app=myapp,env=prod' or 'app in (myapp,yourapp)'), use this option when you want to filter the pods by label",
@@ -33,6 +39,12 @@ func initPods() []api.ServerTool {
 						Description: "Optional Kubernetes field selector to filter pods by field values (e.g. 'status.phase=Running', 'spec.nodeName=node1'). Supported fields: metadata.name, metadata.namespace, spec.nodeName, spec.restartPolicy, spec.schedulerName, spec.serviceAccountName, status.phase (Pending/Running/Succeeded/Failed/Unknown), status.podIP, status.nominatedNodeName. Note: CrashLoopBackOff is a container state, not a pod phase, so it cannot be filtered directly. See https://kubernetes.io/docs/concepts/overview/working-with-objects/field-selectors/",
 						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
 					},
+					"jsonpath":        jsonPathProperty(),
+					"output_template": outputTemplateProperty(),
+					"prune":           pruneProperty(),
+					"detail":          detailProperty(),
+					"sort_by":         sortByProperty(output.SortFields...),
+					"sort_order":      sortOrderProperty(),
 				},
 			},
 			Annotations: api.ToolAnnotations{
@@ -41,13 +53,14 @@ func initPods() []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsListInAllNamespaces},
+		}, Handler: podsListInAllNamespaces, TableOutput: ptr.To(true)},
 		{Tool: api.Tool{
 			Name:        "pods_list_in_namespace",
 			Description: "List all the Kubernetes pods in the specified namespace in the current cluster",
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					"bypass_cache": bypassCacheProperty(),
 					"namespace": {
 						Type:        "string",
 						Description: "Namespace to list pods from",
@@ -62,6 +75,12 @@ func initPods() []api.ServerTool {
 						Description: "Optional Kubernetes field selector to filter pods by field values (e.g. 'status.phase=Running', 'spec.nodeName=node1'). Supported fields: metadata.name, metadata.namespace, spec.nodeName, spec.restartPolicy, spec.schedulerName, spec.serviceAccountName, status.phase (Pending/Running/Succeeded/Failed/Unknown), status.podIP, status.nominatedNodeName. Note: CrashLoopBackOff is a container state, not a pod phase, so it cannot be filtered directly. See https://kubernetes.io/docs/concepts/overview/working-with-objects/field-selectors/",
 						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
 					},
+					"jsonpath":        jsonPathProperty(),
+					"output_template": outputTemplateProperty(),
+					"prune":           pruneProperty(),
+					"detail":          detailProperty(),
+					"sort_by":         sortByProperty(output.SortFields...),
+					"sort_order":      sortOrderProperty(),
 				},
 				Required: []string{"namespace"},
 			},
@@ -71,13 +90,14 @@ func initPods() []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsListInNamespace},
+		}, Handler: podsListInNamespace, TableOutput: ptr.To(true)},
 		{Tool: api.Tool{
 			Name:        "pods_get",
 			Description: "Get a Kubernetes Pod in the current or provided namespace with the provided name",
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					"bypass_cache": bypassCacheProperty(),
 					"namespace": {
 						Type:        "string",
 						Description: "Namespace to get the Pod from",
@@ -86,6 +106,9 @@ func initPods() []api.ServerTool {
 						Type:        "string",
 						Description: "Name of the Pod",
 					},
+					"jsonpath":        jsonPathProperty(),
+					"output_template": outputTemplateProperty(),
+					"prune":           pruneProperty(),
 				},
 				Required: []string{"name"},
 			},
@@ -119,10 +142,41 @@ func initPods() []api.ServerTool {
 				IdempotentHint:  ptr.To(true),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsDelete},
+		}, Handler: podsDelete, Permissions: podsDeletePermissions},
+		{Tool: api.Tool{
+			Name: "pods_evict",
+			Description: "Evict a Kubernetes Pod in the current or provided namespace with the Eviction API, like a node drain: " +
+				"unlike pods_delete, the eviction is refused if it would violate a PodDisruptionBudget protecting the Pod. " +
+				"Use it for targeted restarts of the Pods of a workload (its controller re-creates them) or to rebalance them. " +
+				"Use dry_run to check whether the Pod can be evicted without evicting it",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pod to evict",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Pod to evict",
+					},
+					"dry_run": {
+						Type:        "boolean",
+						Description: "If true, only validates the eviction (PodDisruptionBudgets included) without evicting the Pod (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Pods: Evict",
+				DestructiveHint: ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: podsEvict, Permissions: podsEvictPermissions},
 		{Tool: api.Tool{
 			Name:        "pods_top",
-			Description: "List the resource consumption (CPU and memory) as recorded by the Kubernetes Metrics Server for the specified Kubernetes Pods in the all namespaces, the provided namespace, or the current namespace",
+			Description: "List the resource consumption (CPU and memory) as recorded by the Kubernetes Metrics Server (or by the kubelet Summary API when the Metrics Server is not available) for the specified Kubernetes Pods in the all namespaces, the provided namespace, or the current namespace",
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
@@ -144,6 +198,11 @@ func initPods() []api.ServerTool {
 						Description: "Kubernetes label selector (e.g. 'app=myapp,env=prod' or 'app in (myapp,yourapp)'), use this option when you want to filter the pods by label (Optional, only applicable when name is not provided)",
 						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
 					},
+					"sort_by": {
+						Type:        "string",
+						Description: "Sort the Pods by their resource consumption, highest first (Optional, sorted by namespace and name if not provided)",
+						Enum:        []any{"cpu", "memory"},
+					},
 				},
 			},
 			Annotations: api.ToolAnnotations{
@@ -187,7 +246,48 @@ func initPods() []api.ServerTool {
 				DestructiveHint: ptr.To(true), // Depending on the Pod's entrypoint, executing certain commands may kill the Pod
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsExec},
+		}, Handler: podsExec, Permissions: podsExecPermissions},
+		{Tool: api.Tool{
+			Name: "pods_resize",
+			Description: "Resize the CPU and memory requests and limits of a container of a Kubernetes Pod in place (resize subresource, Kubernetes 1.33+), " +
+				"without recreating the Pod nor restarting the container (unless its resize policy requires it), e.g. to bump the memory of a Pod close to its limit. " +
+				"Waits for the kubelet to apply the resize and reports its status: Completed, InProgress, Deferred (not enough free resources on the node yet), " +
+				"Infeasible (more than the node can provide), Error, or NotObserved. " +
+				"The Pods managed by a workload get the resources of its template back when recreated, update the workload too to keep the change",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Pod",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Pod",
+					},
+					"container": {
+						Type:        "string",
+						Description: "Name of the container to resize (Optional, the first container if not provided)",
+					},
+					"requests": podResizeResourcesProperty("Requests to set (Optional)"),
+					"limits":   podResizeResourcesProperty("Limits to set (Optional)"),
+					"timeout": {
+						Type:        "integer",
+						Description: "Maximum time in seconds to wait for the kubelet to apply the resize, 0 to not wait (Optional, default: 30)",
+						Default:     api.ToRawMessage(defaultPodsResizeTimeout),
+						Minimum:     ptr.To(float64(0)),
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Pods: Resize",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: podsResize, Permissions: podsResizePermissions},
 		{Tool: api.Tool{
 			Name:        "pods_log",
 			Description: "Get the logs of a Kubernetes Pod in the current or provided namespace with the provided name",
@@ -256,7 +356,7 @@ func initPods() []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: podsRun},
+		}, Handler: podsRun, Permissions: podsRunPermissions},
 	}
 }
 
@@ -264,7 +364,7 @@ func podsListInAllNamespaces(params api.ToolHandlerParams) (*api.ToolCallResult,
 	labelSelector := params.GetArguments()["labelSelector"]
 	fieldSelector := params.GetArguments()["fieldSelector"]
 	resourceListOptions := api.ListOptions{
-		AsTable: params.ListOutput.AsTable(),
+		AsTable: listAsTable(params),
 	}
 	if labelSelector != nil {
 		resourceListOptions.LabelSelector = labelSelector.(string)
@@ -272,12 +372,14 @@ func podsListInAllNamespaces(params api.ToolHandlerParams) (*api.ToolCallResult,
 	if fieldSelector != nil {
 		resourceListOptions.FieldSelector = fieldSelector.(string)
 	}
-	ret, err := kubernetes.NewCore(params).PodsListInAllNamespaces(params, resourceListOptions)
+	core := newCore(params)
+	ret, err := core.PodsListInAllNamespaces(params, resourceListOptions)
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "pod listing")
 		return api.NewToolCallResult("", fmt.Errorf("failed to list pods in all namespaces: %w", err)), nil
 	}
-	return api.NewToolCallResult(params.ListOutput.PrintObj(ret)), nil
+	content, err := printList(params, ret)
+	return withFreshness(core, "", content, err), nil
 }
 
 func podsListInNamespace(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
@@ -286,7 +388,7 @@ func podsListInNamespace(params api.ToolHandlerParams) (*api.ToolCallResult, err
 		return api.NewToolCallResult("", errors.New("failed to list pods in namespace, missing argument namespace")), nil
 	}
 	resourceListOptions := api.ListOptions{
-		AsTable: params.ListOutput.AsTable(),
+		AsTable: listAsTable(params),
 	}
 	labelSelector := params.GetArguments()["labelSelector"]
 	if labelSelector != nil {
@@ -296,12 +398,14 @@ func podsListInNamespace(params api.ToolHandlerParams) (*api.ToolCallResult, err
 	if fieldSelector != nil {
 		resourceListOptions.FieldSelector = fieldSelector.(string)
 	}
-	ret, err := kubernetes.NewCore(params).PodsListInNamespace(params, ns.(string), resourceListOptions)
+	core := newCore(params)
+	ret, err := core.PodsListInNamespace(params, ns.(string), resourceListOptions)
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "pod listing")
 		return api.NewToolCallResult("", fmt.Errorf("failed to list pods in namespace %s: %w", ns, err)), nil
 	}
-	return api.NewToolCallResult(params.ListOutput.PrintObj(ret)), nil
+	content, err := printList(params, ret)
+	return withFreshness(core, "", content, err), nil
 }
 
 func podsGet(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
@@ -313,12 +417,14 @@ func podsGet(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	if name == nil {
 		return api.NewToolCallResult("", errors.New("failed to get pod, missing argument name")), nil
 	}
-	ret, err := kubernetes.NewCore(params).PodsGet(params, ns.(string), name.(string))
+	core := newCore(params)
+	ret, err := core.PodsGet(params, ns.(string), name.(string))
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "pod access")
 		return api.NewToolCallResult("", fmt.Errorf("failed to get pod %s in namespace %s: %w", name, ns, err)), nil
 	}
-	return api.NewToolCallResult(output.MarshalYaml(ret)), nil
+	content, err := printObject(params, ret)
+	return withFreshness(core, "", content, err), nil
 }
 
 func podsDelete(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
@@ -338,6 +444,28 @@ func podsDelete(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	return api.NewToolCallResult(ret, err), nil
 }
 
+func podsEvict(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, _ := params.GetArguments()["name"].(string)
+	if name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to evict pod, missing argument name"))), nil
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	dryRun, _ := params.GetArguments()["dry_run"].(bool)
+	eviction, err := kubernetes.NewCore(params).PodsEvict(params, namespace, name, dryRun)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "pod eviction")
+		return api.NewToolCallResult("", fmt.Errorf("failed to evict pod %s: %w", name, err)), nil
+	}
+	ret, err := params.Marshal(eviction)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to evict pod %s: %w", name, err)), nil
+	}
+	if dryRun {
+		return api.NewStructuredToolCallResult(fmt.Sprintf("# Pod %s/%s can be evicted (dry run, the Pod was not evicted) (%s format):\n", eviction.Namespace, name, params.FormatName()), ret, nil), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Pod %s/%s evicted successfully (%s format):\n", eviction.Namespace, name, params.FormatName()), ret, nil), nil
+}
+
 func podsTop(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	podsTopOptions := api.PodsTopOptions{AllNamespaces: true}
 	if v, ok := params.GetArguments()["namespace"].(string); ok {
@@ -352,13 +480,14 @@ func podsTop(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	if v, ok := params.GetArguments()["label_selector"].(string); ok {
 		podsTopOptions.LabelSelector = v
 	}
+	sortBy, _ := params.GetArguments()["sort_by"].(string)
 	ret, err := kubernetes.NewCore(params).PodsTop(params, podsTopOptions)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("failed to get pods top: %w", err)), nil
 	}
 	buf := new(bytes.Buffer)
 	printer := metricsutil.NewTopCmdPrinter(buf, true)
-	err = printer.PrintPodMetrics(ret.Items, true, true, false, "", true)
+	err = printer.PrintPodMetrics(ret.Items, true, true, false, sortBy, true)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("failed to get pods top: %w", err)), nil
 	}
@@ -398,6 +527,72 @@ func podsExec(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	return api.NewToolCallResult(ret, err), nil
 }
 
+func podsResize(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, _ := params.GetArguments()["name"].(string)
+	if name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to resize pod, missing argument name"))), nil
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	container, _ := params.GetArguments()["container"].(string)
+	resources := kubernetes.PodResizeResources{}
+	for argument, target := range map[string]*map[string]string{"requests": &resources.Requests, "limits": &resources.Limits} {
+		v, ok := params.GetArguments()[argument].(map[string]any)
+		if !ok {
+			continue
+		}
+		*target = make(map[string]string, len(v))
+		for resource, quantity := range v {
+			switch q := quantity.(type) {
+			case string:
+				(*target)[resource] = q
+			case float64:
+				(*target)[resource] = strconv.FormatFloat(q, 'f', -1, 64)
+			default:
+				return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to resize pod, the %s %s is not a quantity", resource, argument))), nil
+			}
+		}
+	}
+	if len(resources.Requests) == 0 && len(resources.Limits) == 0 {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to resize pod, provide the requests or limits to set"))), nil
+	}
+	timeout := defaultPodsResizeTimeout * time.Second
+	if v, ok := params.GetArguments()["timeout"]; ok && v != nil {
+		seconds, err := api.ParseInt64(v)
+		if err != nil || seconds < 0 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse timeout parameter: %v", v))), nil
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	resize, err := kubernetes.NewCore(params).PodsResize(params, namespace, name, container, resources, timeout)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "pod resize")
+		return api.NewToolCallResult("", fmt.Errorf("failed to resize pod %s in namespace %s: %w", name, namespace, err)), nil
+	}
+	ret, err := params.Marshal(resize)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to resize pod %s in namespace %s: %w", name, namespace, err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Resize of container %s of pod %s/%s: %s (%s format):\n",
+		resize.Container, resize.Namespace, resize.Pod, resize.Status, params.FormatName()), ret, nil), nil
+}
+
+func podResizeResourcesProperty(description string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "object",
+		Description: description,
+		Properties: map[string]*jsonschema.Schema{
+			"cpu": {
+				Type:        "string",
+				Description: "CPU quantity (e.g. 500m, 2)",
+			},
+			"memory": {
+				Type:        "string",
+				Description: "Memory quantity (e.g. 512Mi, 2Gi)",
+			},
+		},
+	}
+}
+
 func podsLog(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	ns := params.GetArguments()["namespace"]
 	if ns == nil {
@@ -457,9 +652,9 @@ func podsRun(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("failed to run pod %s in namespace %s: %w", name, ns, err)), nil
 	}
-	marshalledYaml, err := output.MarshalYaml(resources)
+	marshalledYaml, err := params.Marshal(resources)
 	if err != nil {
 		err = fmt.Errorf("failed to run pod: %w", err)
 	}
-	return api.NewToolCallResult("# The following resources (YAML) have been created or updated successfully\n"+marshalledYaml, err), nil
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# The following resources (%s) have been created or updated successfully\n", params.FormatName()), marshalledYaml, err), nil
 }
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func jsonPathProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Optional JSONPath expression to return only the matching fields instead of the full objects (e.g. '.status.conditions' or '.spec.containers[].image'). For lists, the expression is applied to each item",
+	}
+}
+
+func outputTemplateProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Optional Go template applied to the result to shape the output exactly (same syntax as kubectl -o go-template, e.g. '{{range .items}}{{.metadata.name}}{{\"\\n\"}}{{end}}' for lists or '{{.status.phase}}' for single objects)",
+	}
+}
+
+func pruneProperty() *jsonschema.Schema {
+	enum := make([]any, 0, len(output.PruneFields))
+	for _, field := range output.PruneFields {
+		enum = append(enum, field)
+	}
+	return &jsonschema.Schema{
+		Type:        "array",
+		Description: "Optional list of fields to remove from the returned objects to reduce the response size (overrides the server defaults, provide an empty list to return the complete objects)",
+		Items:       &jsonschema.Schema{Type: "string", Enum: enum},
+	}
+}
+
+func detailProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Optional level of detail of the listed objects. 'summary' returns their name, namespace, labels, creation, status summary, and the URI to read the complete object from, 'full' returns the complete objects. Defaults to the server configuration (summary if not configured)",
+		Enum:        []any{output.DetailSummary, output.DetailFull},
+	}
+}
+
+func sortByProperty(fields ...string) *jsonschema.Schema {
+	enum := make([]any, 0, len(fields))
+	for _, field := range fields {
+		enum = append(enum, field)
+	}
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Optional field to sort the results by",
+		Enum:        enum,
+	}
+}
+
+func sortOrderProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Optional order in which the results are sorted when sort_by is provided. Defaults to asc",
+		Enum:        []any{"asc", "desc"},
+	}
+}
+
+func jsonPath(params api.ToolHandlerParams) string {
+	if jp, ok := params.GetArguments()["jsonpath"].(string); ok {
+		return jp
+	}
+	return ""
+}
+
+func outputTemplate(params api.ToolHandlerParams) string {
+	if t, ok := params.GetArguments()["output_template"].(string); ok {
+		return t
+	}
+	return ""
+}
+
+// pruneFields returns the fields to prune provided in the tool call, or the server defaults if not provided
+func pruneFields(params api.ToolHandlerParams) []string {
+	fields, ok := params.GetArguments()["prune"].([]any)
+	if !ok {
+		return params.PruneFields
+	}
+	ret := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if f, ok := field.(string); ok {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
+
+// listDetail returns the level of detail provided in the tool call, or the server default (summary if not configured)
+func listDetail(params api.ToolHandlerParams) string {
+	if detail, ok := params.GetArguments()["detail"].(string); ok && detail != "" {
+		return detail
+	}
+	if params.ListDetail != "" {
+		return params.ListDetail
+	}
+	return output.DetailSummary
+}
+
+// fullDetailRequested returns true if the complete objects were explicitly requested in the tool call
+func fullDetailRequested(params api.ToolHandlerParams) bool {
+	return params.GetArguments()["detail"] == output.DetailFull
+}
+
+// listAsTable returns true if the list should be requested as a Table
+// (projections, templates, and the explicitly requested full details need the full objects)
+func listAsTable(params api.ToolHandlerParams) bool {
+	return params.ListOutput.AsTable() && jsonPath(params) == "" && outputTemplate(params) == "" &&
+		!fullDetailRequested(params)
+}
+
+// printList prints the sorted and pruned list with the configured list output (the complete objects as YAML if the
+// full detail was requested with the table output), or its projection or rendered template if provided in the tool call.
+// The YAML and JSON outputs contain the summaries of the objects unless the full detail is requested or configured.
+func printList(params api.ToolHandlerParams, ret runtime.Unstructured) (string, error) {
+	if sortBy, ok := params.GetArguments()["sort_by"].(string); ok && sortBy != "" {
+		descending := params.GetArguments()["sort_order"] == "desc"
+		if err := output.Sort(ret, sortBy, descending); err != nil {
+			return "", err
+		}
+	}
+	if content, ok, err := printCustom(params, ret); ok {
+		return content, err
+	}
+	output.Prune(ret, pruneFields(params))
+	switch {
+	case params.ListOutput.AsTable() && fullDetailRequested(params):
+		return output.Yaml.PrintObj(ret)
+	case !params.ListOutput.AsTable() && listDetail(params) != output.DetailFull:
+		output.Summarize(ret, func(item *unstructured.Unstructured) string {
+			gvk := item.GroupVersionKind()
+			return api.ObjectReference{
+				GroupVersionKind: api.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+				Namespace:        item.GetNamespace(),
+				Name:             item.GetName(),
+				Target:           params.Target,
+			}.URI()
+		})
+	}
+	return params.ListOutput.PrintObj(ret)
+}
+
+// printObject prints the pruned object as YAML, or its projection or rendered template if provided in the tool call
+func printObject(params api.ToolHandlerParams, ret runtime.Unstructured) (string, error) {
+	if content, ok, err := printCustom(params, ret); ok {
+		return content, err
+	}
+	output.Prune(ret, pruneFields(params))
+	return params.Marshal(ret)
+}
+
+// printCustom prints the JSONPath projection or the rendered output template provided in the tool call.
+// The boolean return value is false if none of them were provided.
+func printCustom(params api.ToolHandlerParams, ret runtime.Unstructured) (string, bool, error) {
+	jp, template := jsonPath(params), outputTemplate(params)
+	switch {
+	case jp != "" && template != "":
+		return "", true, errors.New("jsonpath and output_template are mutually exclusive")
+	case jp != "":
+		content, err := printProjection(params, ret, jp)
+		return content, true, err
+	case template != "":
+		content, err := output.RenderTemplate(ret, template)
+		return content, true, err
+	}
+	return "", false, nil
+}
+
+func printProjection(params api.ToolHandlerParams, ret runtime.Unstructured, jp string) (string, error) {
+	projection, err := output.Project(ret, jp)
+	if err != nil {
+		return "", err
+	}
+	if projection == nil {
+		return "", fmt.Errorf("no fields matched the jsonpath expression %s", jp)
+	}
+	return params.Marshal(projection)
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+)
+
+func bypassCacheProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Optional, if true, reads from the API server instead of the informer cache (only relevant if the read cache is enabled in the server configuration)",
+	}
+}
+
+// newCore returns the Core of the tool call, serving the reads from the informer cache unless disabled or bypassed
+func newCore(params api.ToolHandlerParams) *kubernetes.Core {
+	core := kubernetes.NewCore(params)
+	if bypass, _ := params.GetArguments()["bypass_cache"].(bool); !bypass {
+		core.WithReadCache(toolsetConfig(params).ReadCache)
+	}
+	return core
+}
+
+// withFreshness returns the structured result with the freshness of the informer cache it was served from (if any)
+// in its header
+func withFreshness(core *kubernetes.Core, header, content string, err error) *api.ToolCallResult {
+	if freshness := core.ReadCacheFreshness(); freshness != nil && err == nil {
+		header = freshness.String() + header
+	}
+	return api.NewStructuredToolCallResult(header, content, err)
+}
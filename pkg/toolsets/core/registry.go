@@ -0,0 +1,87 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+	"github.com/containers/kubernetes-mcp-server/pkg/registry"
+)
+
+func initRegistry() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "registry_image_inspect",
+			Description: "Inspect a container image in its registry (Docker Hub, GHCR, Quay, or any OCI registry) and return the digest the reference resolves to, " +
+				"the platforms of a multi-platform image, the creation date, the labels, and the size of the image. " +
+				"Useful to check whether a new image (tag) is actually published, or which image a tag points to. " +
+				"When a namespace is provided, its image pull secrets are used to authenticate to private registries (if reading them is allowed)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"image": {
+						Type:        "string",
+						Description: "Container image reference, as in a Pod spec (e.g. nginx:1.27, ghcr.io/org/app:v1.2.3, quay.io/org/app@sha256:...)",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace whose image pull secrets are used to authenticate to the registry. If not provided, will access the registry anonymously",
+					},
+					"platform": {
+						Type:        "string",
+						Description: "Optional platform to inspect in a multi-platform image (e.g. linux/arm64). If not provided, will inspect linux/amd64 or the first available platform",
+					},
+				},
+				Required: []string{"image"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Registry: Image Inspect",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: registryImageInspect},
+	}
+}
+
+func registryImageInspect(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	image, _ := params.GetArguments()["image"].(string)
+	if image == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to inspect image, missing argument image"))), nil
+	}
+	ref, err := registry.ParseReference(image)
+	if err != nil {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to inspect image: %w", err))), nil
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	platform, _ := params.GetArguments()["platform"].(string)
+	var keyring registry.Keyring
+	var pullSecretsErr error
+	if namespace != "" {
+		// Pull secrets are best-effort, reading Secrets might be denied (RBAC or access control) and is not needed for public images
+		if keyring, pullSecretsErr = registry.PullSecrets(params, params.CoreV1(), namespace); pullSecretsErr != nil {
+			mcplog.HandleK8sError(params.Context, pullSecretsErr, "image pull secrets access")
+			pullSecretsErr = fmt.Errorf("image pull secrets of namespace %s not used: %w", namespace, pullSecretsErr)
+		}
+	}
+	inspected, err := registry.NewClient(nil, keyring).Inspect(params, ref, platform)
+	if registry.IsNotFound(err) {
+		err = fmt.Errorf("image %s is not published in the registry (or not visible with the provided credentials): %w", ref, err)
+	}
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to inspect image: %w", errors.Join(err, pullSecretsErr))), nil
+	}
+	ret, err := params.Marshal(inspected)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to inspect image: %w", err)), nil
+	}
+	notes := ""
+	if pullSecretsErr != nil {
+		notes = "# Warning: " + pullSecretsErr.Error() + "\n"
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("%s# Image %s (%s format):\n", notes, ref, params.FormatName()), ret, nil), nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/ptr"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
 	"github.com/containers/kubernetes-mcp-server/pkg/output"
+	"github.com/containers/kubernetes-mcp-server/pkg/workspace"
 )
 
 func initResources(o api.Openshift) []api.ServerTool {
@@ -28,6 +31,7 @@ func initResources(o api.Openshift) []api.ServerTool {
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					"bypass_cache": bypassCacheProperty(),
 					"apiVersion": {
 						Type:        "string",
 						Description: "apiVersion of the resources (examples of valid apiVersion are: v1, apps/v1, networking.k8s.io/v1)",
@@ -50,6 +54,12 @@ func initResources(o api.Openshift) []api.ServerTool {
 						Description: "Optional Kubernetes field selector to filter resources by field values (e.g. 'status.phase=Running', 'metadata.name=myresource'). Supported fields vary by resource type. For Pods: metadata.name, metadata.namespace, spec.nodeName, spec.restartPolicy, spec.schedulerName, spec.serviceAccountName, status.phase (Pending/Running/Succeeded/Failed/Unknown), status.podIP, status.nominatedNodeName. See https://kubernetes.io/docs/concepts/overview/working-with-objects/field-selectors/",
 						Pattern:     "([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]",
 					},
+					"jsonpath":        jsonPathProperty(),
+					"output_template": outputTemplateProperty(),
+					"prune":           pruneProperty(),
+					"detail":          detailProperty(),
+					"sort_by":         sortByProperty(output.SortFields...),
+					"sort_order":      sortOrderProperty(),
 				},
 				Required: []string{"apiVersion", "kind"},
 			},
@@ -59,13 +69,14 @@ func initResources(o api.Openshift) []api.ServerTool {
 				DestructiveHint: ptr.To(false),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: resourcesList},
+		}, Handler: resourcesList, TableOutput: ptr.To(true)},
 		{Tool: api.Tool{
 			Name:        "resources_get",
 			Description: "Get a Kubernetes resource in the current cluster by providing its apiVersion, kind, optionally the namespace, and its name\n" + commonApiVersion,
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
+					"bypass_cache": bypassCacheProperty(),
 					"apiVersion": {
 						Type:        "string",
 						Description: "apiVersion of the resource (examples of valid apiVersion are: v1, apps/v1, networking.k8s.io/v1)",
@@ -82,6 +93,9 @@ func initResources(o api.Openshift) []api.ServerTool {
 						Type:        "string",
 						Description: "Name of the resource",
 					},
+					"jsonpath":        jsonPathProperty(),
+					"output_template": outputTemplateProperty(),
+					"prune":           pruneProperty(),
 				},
 				Required: []string{"apiVersion", "kind", "name"},
 			},
@@ -99,8 +113,14 @@ func initResources(o api.Openshift) []api.ServerTool {
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
 					"resource": {
-						Type:        "string",
-						Description: "A JSON or YAML containing a representation of the Kubernetes resource. Should include top-level fields such as apiVersion,kind,metadata, and spec",
+						Type: "string",
+						Description: "A JSON or YAML containing a representation of the Kubernetes resource. Should include top-level fields such as apiVersion,kind,metadata, and spec. " +
+							"A manifest written to the workspace of the session can be referenced as workspace:<path> instead",
+					},
+					"diff": diffProperty(),
+					"transaction": {
+						Type:        "boolean",
+						Description: "Optional, if true, records the state of the resources before the change in a transaction, whose ID is returned, to undo the change with transactions_rollback",
 					},
 				},
 				Required: []string{"resource"},
@@ -111,7 +131,7 @@ func initResources(o api.Openshift) []api.ServerTool {
 				IdempotentHint:  ptr.To(true),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: resourcesCreateOrUpdate},
+		}, Handler: resourcesCreateOrUpdate, Permissions: resourcesCreateOrUpdatePermissions},
 		{Tool: api.Tool{
 			Name:        "resources_delete",
 			Description: "Delete a Kubernetes resource in the current cluster by providing its apiVersion, kind, optionally the namespace, and its name\n" + commonApiVersion,
@@ -147,7 +167,7 @@ func initResources(o api.Openshift) []api.ServerTool {
 				IdempotentHint:  ptr.To(true),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: resourcesDelete},
+		}, Handler: resourcesDelete, Permissions: resourcesDeletePermissions},
 		{Tool: api.Tool{
 			Name:        "resources_scale",
 			Description: "Get or update the scale of a Kubernetes resource in the current cluster by providing its apiVersion, kind, name, and optionally the namespace. If the scale is set in the tool call, the scale will be updated to that value. Always returns the current scale of the resource",
@@ -174,6 +194,7 @@ func initResources(o api.Openshift) []api.ServerTool {
 						Type:        "integer",
 						Description: "Optional scale to update the resources scale to. If not provided, will return the current scale of the resource, and not update it",
 					},
+					"diff": diffProperty(),
 				},
 				Required: []string{"apiVersion", "kind", "name"},
 			},
@@ -183,7 +204,7 @@ func initResources(o api.Openshift) []api.ServerTool {
 				IdempotentHint:  ptr.To(true),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: resourcesScale},
+		}, Handler: resourcesScale, Permissions: resourcesScalePermissions},
 	}
 }
 
@@ -194,7 +215,7 @@ func resourcesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 	labelSelector := params.GetArguments()["labelSelector"]
 	resourceListOptions := api.ListOptions{
-		AsTable: params.ListOutput.AsTable(),
+		AsTable: listAsTable(params),
 	}
 
 	if labelSelector != nil {
@@ -222,12 +243,14 @@ func resourcesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 		return api.NewToolCallResult("", fmt.Errorf("namespace is not a string")), nil
 	}
 
-	ret, err := kubernetes.NewCore(params).ResourcesList(params, gvk, ns, resourceListOptions)
+	core := newCore(params)
+	ret, err := core.ResourcesList(params, gvk, ns, resourceListOptions)
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "resource listing")
 		return api.NewToolCallResult("", fmt.Errorf("failed to list resources: %w", err)), nil
 	}
-	return api.NewToolCallResult(params.ListOutput.PrintObj(ret)), nil
+	content, err := printList(params, ret)
+	return withFreshness(core, "", content, err), nil
 }
 
 func resourcesGet(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
@@ -254,12 +277,14 @@ func resourcesGet(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 		return api.NewToolCallResult("", fmt.Errorf("name is not a string")), nil
 	}
 
-	ret, err := kubernetes.NewCore(params).ResourcesGet(params, gvk, ns, n)
+	core := newCore(params)
+	ret, err := core.ResourcesGet(params, gvk, ns, n)
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "resource access")
 		return api.NewToolCallResult("", fmt.Errorf("failed to get resource: %w", err)), nil
 	}
-	return api.NewToolCallResult(output.MarshalYaml(ret)), nil
+	content, err := printObject(params, ret)
+	return withFreshness(core, "", content, err), nil
 }
 
 func resourcesCreateOrUpdate(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
@@ -272,17 +297,45 @@ func resourcesCreateOrUpdate(params api.ToolHandlerParams) (*api.ToolCallResult,
 	if !ok {
 		return api.NewToolCallResult("", fmt.Errorf("resource is not a string")), nil
 	}
+	r, err := resolveWorkspaceReference(params, r)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to create or update resources: %w", err)), nil
+	}
 
+	if params.GetArguments()["transaction"] == true {
+		return resourcesCreateOrUpdateInTransaction(params, r)
+	}
+	if params.GetArguments()["diff"] == true {
+		previous, resources, err := kubernetes.NewCore(params).ResourcesCreateOrUpdateWithPrevious(params, r)
+		if err != nil {
+			mcplog.HandleK8sError(params.Context, err, "resource creation or update")
+			return api.NewToolCallResult("", fmt.Errorf("failed to create or update resources: %w", err)), nil
+		}
+		return api.NewToolCallResult(printDiff(previous, resources)), nil
+	}
 	resources, err := kubernetes.NewCore(params).ResourcesCreateOrUpdate(params, r)
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "resource creation or update")
 		return api.NewToolCallResult("", fmt.Errorf("failed to create or update resources: %w", err)), nil
 	}
-	marshalledYaml, err := output.MarshalYaml(resources)
+	for _, resource := range resources {
+		output.Prune(resource, params.PruneFields)
+	}
+	marshalledYaml, err := params.Marshal(resources)
 	if err != nil {
 		err = fmt.Errorf("failed to create or update resources: %w", err)
 	}
-	return api.NewToolCallResult("# The following resources (YAML) have been created or updated successfully\n"+marshalledYaml, err), nil
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# The following resources (%s) have been created or updated successfully\n", params.FormatName()), marshalledYaml, err), nil
+}
+
+// resolveWorkspaceReference returns the content of the file of the workspace of the session referenced by the
+// manifest (workspace:<path>), or the manifest itself
+func resolveWorkspaceReference(params api.ToolHandlerParams, manifest string) (string, error) {
+	if !workspace.IsReference(manifest) {
+		return manifest, nil
+	}
+	content, err := workspace.Resolve(params.Context, manifest)
+	return string(content), err
 }
 
 func resourcesDelete(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
@@ -361,18 +414,58 @@ func resourcesScale(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 		}
 	}
 
+	var previous *unstructured.Unstructured
+	if shouldScale && params.GetArguments()["diff"] == true {
+		previous, err = kubernetes.NewCore(params).ResourcesScale(params.Context, gvk, ns, n, 0, false)
+		if err != nil {
+			mcplog.HandleK8sError(params.Context, err, "resource scaling")
+			return api.NewToolCallResult("", fmt.Errorf("failed to get/update resource scale: %w", err)), nil
+		}
+	}
 	scale, err := kubernetes.NewCore(params).ResourcesScale(params.Context, gvk, ns, n, desiredScale, shouldScale)
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "resource scaling")
 		return api.NewToolCallResult("", fmt.Errorf("failed to get/update resource scale: %w", err)), nil
 	}
+	if previous != nil {
+		return api.NewToolCallResult(printDiff([]*unstructured.Unstructured{previous}, []*unstructured.Unstructured{scale})), nil
+	}
 
-	marshalled, err := output.MarshalYaml(scale)
+	marshalled, err := params.Marshal(scale)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("failed to marshall scale to yaml format: %v", scale)), nil
 	}
 
-	return api.NewToolCallResult("# Current resource scale (YAML) is below\n"+marshalled, err), nil
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Current resource scale (%s) is below\n", params.FormatName()), marshalled, err), nil
+}
+
+func diffProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Optional, if true, returns a diff of the changed fields (before/after) instead of the whole resulting resource",
+	}
+}
+
+// printDiff prints the unified diff between the previous and the resulting state of each of the resources
+func printDiff(previous, resources []*unstructured.Unstructured) (string, error) {
+	diffs := make([]string, 0, len(resources))
+	for i, resource := range resources {
+		name := resource.GetKind() + " " + resource.GetName()
+		if resource.GetNamespace() != "" {
+			name = resource.GetKind() + " " + resource.GetNamespace() + "/" + resource.GetName()
+		}
+		diff, err := output.Diff(name, previous[i], resource)
+		if err != nil {
+			return "", fmt.Errorf("failed to compute diff for %s: %w", name, err)
+		}
+		if diff != "" {
+			diffs = append(diffs, diff)
+		}
+	}
+	if len(diffs) == 0 {
+		return "# No changes were applied to the resources", nil
+	}
+	return "# The following changes (unified diff) have been applied successfully\n" + strings.Join(diffs, ""), nil
 }
 
 func parseScaleValue(desiredScale interface{}) (int64, error) {
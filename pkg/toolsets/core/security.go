@@ -0,0 +1,125 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+func initSecurity() []api.ServerTool {
+	kinds := make([]any, 0, len(kubernetes.SecurityContextKinds))
+	for _, kind := range kubernetes.SecurityContextKinds {
+		kinds = append(kinds, kind)
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "security_posture",
+			Description: "Evaluate the Kubernetes Pods against the Pod Security Standards (baseline and restricted levels) and summarize the violations per namespace. " +
+				"Reports privileged containers, host namespaces (hostNetwork, hostPID, hostIPC), hostPath volumes, host ports, added capabilities, " +
+				"missing runAsNonRoot, absent or unconfined seccomp profiles, and privilege escalation, together with the level each Pod would fail " +
+				"and the level enforced in its namespace (pod-security.kubernetes.io/enforce label)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace to evaluate the Pods from (Optional, all namespaces if not provided)",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Security: Posture",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: securityPosture},
+		{Tool: api.Tool{
+			Name: "security_context",
+			Description: "Get the effective security settings of a Kubernetes Pod, or of the Pods of a workload, flattened from the Pod and container security contexts. " +
+				"For each container reports the user and group, runAsNonRoot, privileged, privilege escalation, read-only root filesystem, " +
+				"the effective capabilities (runtime defaults minus dropped plus added), and the seccomp, AppArmor, and SELinux profiles, " +
+				"together with the Pod settings (service account, host namespaces, fsGroup, supplemental groups, sysctls). " +
+				"Each setting tells whether it is set on the container, on the Pod, through the deprecated AppArmor annotation, " +
+				"or inherited from the defaults of the image, the container runtime, or the kubelet",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the Pod or workload to get the security settings of",
+						Enum:        kinds,
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Pod or workload",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace of the Pod or workload. If not provided, will use the configured namespace",
+					},
+				},
+				Required: []string{"kind", "name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Security: Context",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: securityContext},
+	}
+}
+
+func securityPosture(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	postures, err := kubernetes.NewCore(params).SecurityPosture(params, namespace)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "security posture evaluation")
+		return api.NewToolCallResult("", fmt.Errorf("failed to evaluate security posture: %w", err)), nil
+	}
+	if len(postures) == 0 {
+		return api.NewToolCallResult("# No pods found", nil), nil
+	}
+	pods, failBaseline, failRestricted := 0, 0, 0
+	for _, posture := range postures {
+		pods += posture.Pods
+		failBaseline += posture.FailBaseline
+		failRestricted += posture.FailRestricted
+	}
+	ret, err := params.Marshal(postures)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to evaluate security posture: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d pod(s) evaluated, %d failing baseline, %d failing restricted (%s format):\n",
+		pods, failBaseline, failRestricted, params.FormatName()), ret, nil), nil
+}
+
+func securityContext(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	kind, _ := params.GetArguments()["kind"].(string)
+	if kind == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to get security context, missing argument kind"))), nil
+	}
+	name, _ := params.GetArguments()["name"].(string)
+	if name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to get security context, missing argument name"))), nil
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	settings, err := kubernetes.NewCore(params).SecurityContext(params, kind, namespace, name)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "security context retrieval")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get security context: %w", err)), nil
+	}
+	ret, err := params.Marshal(settings)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get security context: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Effective security settings of %s, %d container(s), Pod Security Standards level %s (%s format):\n",
+		settings.Object, len(settings.Containers), settings.PodSecurityLevel, params.FormatName()), ret, nil), nil
+}
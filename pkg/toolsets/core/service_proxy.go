@@ -0,0 +1,153 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+const (
+	// defaultServiceProxyMaxBytes is the default maximum size of the returned response body
+	defaultServiceProxyMaxBytes = 64 * 1024
+	// maxServiceProxyMaxBytes bounds the maximum size of the returned response body
+	maxServiceProxyMaxBytes = 1024 * 1024
+)
+
+func initServiceProxy() []api.ServerTool {
+	kinds := make([]any, 0, len(kubernetes.ServiceProxyKinds))
+	for _, kind := range kubernetes.ServiceProxyKinds {
+		kinds = append(kinds, kind)
+	}
+	methods := make([]any, 0, len(kubernetes.ServiceProxyMethods))
+	for _, method := range kubernetes.ServiceProxyMethods {
+		methods = append(methods, method)
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "service_proxy_request",
+			Description: "Send an HTTP GET or POST request to a Service or Pod through the proxy subresource of the Kubernetes API server, " +
+				"to reach the health endpoints, metrics, and admin APIs of the in-cluster applications without port-forwarding. " +
+				"The response is returned whatever its status code, its body is truncated to max_bytes",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the target of the request (Optional, default: Service)",
+						Enum:        kinds,
+						Default:     api.ToRawMessage("Service"),
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Service or Pod",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the Service or Pod",
+					},
+					"port": {
+						Type:        "string",
+						Description: "Name or number of the port of the Service or Pod (Optional, the first port if not provided, required with scheme)",
+					},
+					"scheme": {
+						Type:        "string",
+						Description: "Scheme of the request to the Service or Pod (Optional, default: http)",
+						Enum:        []any{"http", "https"},
+					},
+					"method": {
+						Type:        "string",
+						Description: "HTTP method of the request (Optional, default: GET)",
+						Enum:        methods,
+						Default:     api.ToRawMessage("GET"),
+					},
+					"path": {
+						Type:        "string",
+						Description: "Path of the request including its query string (e.g. /healthz, /metrics?format=json) (Optional, default: /)",
+					},
+					"headers": {
+						Type:        "object",
+						Description: "Headers of the request (e.g. {\"Content-Type\": \"application/json\"}), the Authorization and Impersonate-* headers are not allowed (Optional)",
+						Properties:  make(map[string]*jsonschema.Schema),
+					},
+					"body": {
+						Type:        "string",
+						Description: "Body of the POST request (Optional)",
+					},
+					"max_bytes": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Maximum size in bytes of the returned response body, the rest is left out (Optional, default: %d, maximum: %d)", defaultServiceProxyMaxBytes, maxServiceProxyMaxBytes),
+						Default:     api.ToRawMessage(defaultServiceProxyMaxBytes),
+						Minimum:     ptr.To(float64(1)),
+						Maximum:     ptr.To(float64(maxServiceProxyMaxBytes)),
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Service: Proxy Request",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: serviceProxyRequest, Permissions: serviceProxyRequestPermissions},
+	}
+}
+
+func serviceProxyRequest(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	request := kubernetes.ServiceProxyRequest{Kind: "Service", Method: "GET", Path: "/", MaxBytes: defaultServiceProxyMaxBytes}
+	request.Name, _ = params.GetArguments()["name"].(string)
+	if request.Name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to send proxy request, missing argument name"))), nil
+	}
+	if v, _ := params.GetArguments()["kind"].(string); v != "" {
+		request.Kind = v
+	}
+	if v, _ := params.GetArguments()["method"].(string); v != "" {
+		request.Method = v
+	}
+	if v, _ := params.GetArguments()["path"].(string); v != "" {
+		request.Path = v
+	}
+	request.Namespace, _ = params.GetArguments()["namespace"].(string)
+	request.Port, _ = params.GetArguments()["port"].(string)
+	request.Scheme, _ = params.GetArguments()["scheme"].(string)
+	request.Body, _ = params.GetArguments()["body"].(string)
+	if v, ok := params.GetArguments()["headers"].(map[string]any); ok {
+		request.Headers = make(map[string]string, len(v))
+		for name, value := range v {
+			s, ok := value.(string)
+			if !ok {
+				return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to send proxy request, the value of header %s is not a string", name))), nil
+			}
+			request.Headers[name] = s
+		}
+	}
+	if v, ok := params.GetArguments()["max_bytes"]; ok && v != nil {
+		maxBytes, err := api.ParseInt64(v)
+		if err != nil || maxBytes < 1 || maxBytes > maxServiceProxyMaxBytes {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse max_bytes parameter, expected 1 to %d: %v", maxServiceProxyMaxBytes, v))), nil
+		}
+		request.MaxBytes = maxBytes
+	}
+	response, err := kubernetes.NewCore(params).ServiceProxyRequest(params, request)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "service proxy request")
+		return api.NewToolCallResult("", fmt.Errorf("failed to send proxy request to %s %s: %w", request.Kind, request.Name, err)), nil
+	}
+	header := fmt.Sprintf("# HTTP %s from %s %s %s %s", response.Status, request.Kind, request.Name, request.Method, request.Path)
+	if response.ContentType != "" {
+		header += " (" + response.ContentType + ")"
+	}
+	header += "\n"
+	if response.Truncated {
+		header += fmt.Sprintf("# The response body was truncated to %d bytes, increase max_bytes or narrow the request down to get the rest\n", request.MaxBytes)
+	}
+	return api.NewToolCallResult(header+response.Body, nil), nil
+}
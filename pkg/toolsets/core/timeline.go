@@ -0,0 +1,192 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+// defaultChangeTimelineWindow is the default time window of the change_timeline tool
+const defaultChangeTimelineWindow = "1h"
+
+func initTimeline() []api.ServerTool {
+	kinds := make([]any, 0, len(kubernetes.TimelineWorkloadKinds))
+	for _, kind := range kubernetes.TimelineWorkloadKinds {
+		kinds = append(kinds, kind)
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "change_timeline",
+			Description: "Reconstruct the chronological timeline of what happened in a Kubernetes namespace or to a workload, " +
+				"merging the events, the Helm release history, the rollout history (ReplicaSets and ControllerRevisions), " +
+				"and the creation and last update (managedFields) timestamps of the objects. " +
+				"Useful to answer questions like \"what changed around 14:05\"",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace to reconstruct the timeline for. If not provided, will use the configured namespace",
+					},
+					"kind": {
+						Type:        "string",
+						Description: "Optional kind of the workload to reconstruct the timeline for (requires name). If not provided, will reconstruct the timeline of the whole namespace",
+						Enum:        kinds,
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the workload (required if kind is provided)",
+					},
+					"around": {
+						Type:        "string",
+						Description: "Optional point in time to center the timeline on, as an RFC3339 timestamp (e.g. 2025-01-01T14:05:00Z) or a time of the day in UTC (e.g. 14:05, within the last 24h). If not provided, the timeline ends now",
+					},
+					"window": {
+						Type:        "string",
+						Description: "Time window of the timeline, as a duration (e.g. 10m, 1h, 24h), before and after around if provided, or before now (Optional, default: 1h)",
+						Default:     api.ToRawMessage(defaultChangeTimelineWindow),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Timeline: Changes",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: changeTimeline},
+		{Tool: api.Tool{
+			Name: "incident_timeline",
+			Description: "Correlate what went wrong in a Kubernetes namespace within a time range into a single chronological incident timeline, " +
+				"merging the Warning events, the container restarts (last terminations), the rollout revisions (ReplicaSets and ControllerRevisions), and the Helm release history. " +
+				"The first problem (Warning event or restart) is pointed out along with the rollouts and Helm revisions that preceded it, its likely causes",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace to correlate the incident timeline for. If not provided, will use the configured namespace",
+					},
+					"from": {
+						Type:        "string",
+						Description: "Start of the time range, as an RFC3339 timestamp (e.g. 2025-01-01T14:00:00Z) or a time of the day in UTC (e.g. 14:00, within the last 24h) (Optional, default: 1h before to)",
+					},
+					"to": {
+						Type:        "string",
+						Description: "End of the time range, as an RFC3339 timestamp (e.g. 2025-01-01T15:00:00Z) or a time of the day in UTC (e.g. 15:00, within the last 24h) (Optional, default: now)",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Timeline: Incident",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: incidentTimeline},
+	}
+}
+
+func changeTimeline(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	kind, _ := params.GetArguments()["kind"].(string)
+	name, _ := params.GetArguments()["name"].(string)
+	if kind != "" && name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to reconstruct change timeline, missing argument name"))), nil
+	}
+	windowArg, ok := params.GetArguments()["window"].(string)
+	if !ok || windowArg == "" {
+		windowArg = defaultChangeTimelineWindow
+	}
+	window, err := time.ParseDuration(windowArg)
+	if err != nil || window <= 0 {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid window %s, expected a positive duration (e.g. 10m, 1h)", windowArg))), nil
+	}
+	now := time.Now()
+	from, to := now.Add(-window), now
+	if aroundArg, _ := params.GetArguments()["around"].(string); aroundArg != "" {
+		around, err := parseAround(aroundArg, now)
+		if err != nil {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, err)), nil
+		}
+		from, to = around.Add(-window), around.Add(window)
+	}
+	entries, err := kubernetes.NewCore(params).ChangeTimeline(params, namespace, kind, name, from, to)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "change timeline reconstruction")
+		return api.NewToolCallResult("", fmt.Errorf("failed to reconstruct change timeline: %w", err)), nil
+	}
+	period := fmt.Sprintf("between %s and %s", from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	if len(entries) == 0 {
+		return api.NewToolCallResult("# No changes found "+period, nil), nil
+	}
+	ret, err := params.Marshal(entries)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to reconstruct change timeline: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d change(s) %s, oldest first (%s format):\n", len(entries), period, params.FormatName()), ret, nil), nil
+}
+
+func incidentTimeline(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	now := time.Now()
+	to := now
+	if toArg, _ := params.GetArguments()["to"].(string); toArg != "" {
+		var err error
+		if to, err = parseAround(toArg, now); err != nil {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid to: %w", err))), nil
+		}
+	}
+	from := to.Add(-time.Hour)
+	if fromArg, _ := params.GetArguments()["from"].(string); fromArg != "" {
+		var err error
+		if from, err = parseAround(fromArg, now); err != nil {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid from: %w", err))), nil
+		}
+	}
+	if !from.Before(to) {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("invalid time range, from %s is not before to %s",
+			from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339)))), nil
+	}
+	timeline, err := kubernetes.NewCore(params).IncidentTimeline(params, namespace, from, to)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "incident timeline correlation")
+		return api.NewToolCallResult("", fmt.Errorf("failed to correlate incident timeline: %w", err)), nil
+	}
+	period := fmt.Sprintf("between %s and %s", from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	if len(timeline.Timeline) == 0 {
+		return api.NewToolCallResult("# No Warning events, restarts, rollouts, or Helm revisions found "+period, nil), nil
+	}
+	ret, err := params.Marshal(timeline)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to correlate incident timeline: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Incident timeline %s, oldest first (%s format):\n", period, params.FormatName()), ret, nil), nil
+}
+
+// parseAround parses an RFC3339 timestamp or a time of the day in UTC (the most recent occurrence before now)
+func parseAround(value string, now time.Time) (time.Time, error) {
+	if around, err := time.Parse(time.RFC3339, value); err == nil {
+		return around, nil
+	}
+	for _, layout := range []string{time.TimeOnly, "15:04"} {
+		timeOfDay, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		now = now.UTC()
+		around := time.Date(now.Year(), now.Month(), now.Day(), timeOfDay.Hour(), timeOfDay.Minute(), timeOfDay.Second(), 0, time.UTC)
+		if around.After(now) {
+			around = around.AddDate(0, 0, -1)
+		}
+		return around, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid around %s, expected an RFC3339 timestamp (e.g. 2025-01-01T14:05:00Z) or a time of the day (e.g. 14:05)", value)
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TimelineSuite struct {
+	suite.Suite
+}
+
+func (s *TimelineSuite) TestParseAround() {
+	now := time.Date(2025, 1, 2, 10, 30, 0, 0, time.UTC)
+	s.Run("parses RFC3339 timestamps", func() {
+		around, err := parseAround("2025-01-01T14:05:00+02:00", now)
+		s.Require().NoError(err)
+		s.Equal(time.Date(2025, 1, 1, 12, 5, 0, 0, time.UTC), around.UTC())
+	})
+	s.Run("parses times of the day earlier today", func() {
+		around, err := parseAround("09:15", now)
+		s.Require().NoError(err)
+		s.Equal(time.Date(2025, 1, 2, 9, 15, 0, 0, time.UTC), around)
+	})
+	s.Run("parses times of the day later than now as yesterday", func() {
+		around, err := parseAround("14:05:30", now)
+		s.Require().NoError(err)
+		s.Equal(time.Date(2025, 1, 1, 14, 5, 30, 0, time.UTC), around)
+	})
+	s.Run("rejects invalid values", func() {
+		_, err := parseAround("yesterday", now)
+		s.ErrorContains(err, "invalid around yesterday")
+	})
+}
+
+func TestTimeline(t *testing.T) {
+	suite.Run(t, new(TimelineSuite))
+}
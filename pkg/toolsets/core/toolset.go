@@ -21,11 +21,32 @@ func (t *Toolset) GetDescription() string {
 
 func (t *Toolset) GetTools(o api.Openshift) []api.ServerTool {
 	return slices.Concat(
+		initAuditLog(),
+		initCapacity(),
+		initCertificates(),
+		initCRDs(),
+		initDiagnose(),
 		initEvents(),
+		initLogs(),
+		initMetrics(),
 		initNamespaces(o),
+		initNetCheck(),
 		initNodes(),
+		initNodesDebug(),
+		initOpenShift(o),
+		initOwners(),
 		initPods(),
+		initRegistry(),
 		initResources(o),
+		initSecurity(),
+		initServiceProxy(),
+		initTimeline(),
+		initTraffic(),
+		initTransactions(),
+		initTransient(),
+		initVulnerabilities(),
+		initWebhooks(),
+		initWorkloads(),
 	)
 }
 
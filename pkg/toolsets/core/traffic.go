@@ -0,0 +1,76 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+func initTraffic() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "traffic_routes",
+			Description: "Summarize the Istio VirtualServices (with the subsets and traffic policies of their DestinationRules) and the Gateway API HTTPRoutes. " +
+				"When a host is provided, show the effective routing of the requests for the host and path: the matching rules in evaluation order " +
+				"with their destinations, subsets, and weights (percent of the traffic), redirects, rewrites, retries, and fault injections. " +
+				"Flags undefined subsets and weights not adding up. Useful to debug traffic splitting (canary, A/B testing) setups",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"host": {
+						Type:        "string",
+						Description: "Optional host of the requests to show the effective routing for (e.g. reviews, reviews.bookinfo.svc.cluster.local, www.example.com). If not provided, will summarize all the rules",
+					},
+					"path": {
+						Type:        "string",
+						Description: "Path of the requests to show the effective routing for (Optional, only used with host, default: /)",
+						Default:     api.ToRawMessage("/"),
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace of the VirtualServices and HTTPRoutes. If not provided, will use all namespaces",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Traffic: Routes",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: trafficRoutes},
+	}
+}
+
+func trafficRoutes(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	host, _ := params.GetArguments()["host"].(string)
+	path, ok := params.GetArguments()["path"].(string)
+	if !ok || path == "" {
+		path = "/"
+	}
+	routes, err := kubernetes.NewCore(params).TrafficRoutes(params, namespace, host, path)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "traffic routes listing")
+		return api.NewToolCallResult("", fmt.Errorf("failed to list traffic routes: %w", err)), nil
+	}
+	if len(routes) == 0 {
+		if host != "" {
+			return api.NewToolCallResult(fmt.Sprintf("# No traffic routes found for host %s and path %s", host, path), nil), nil
+		}
+		return api.NewToolCallResult("# No traffic routes found", nil), nil
+	}
+	ret, err := params.Marshal(routes)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list traffic routes: %w", err)), nil
+	}
+	if host != "" {
+		return api.NewStructuredToolCallResult(fmt.Sprintf("# %d traffic route rule(s) for host %s and path %s, in evaluation order (%s format):\n", len(routes), host, path, params.FormatName()), ret, nil), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d traffic route rule(s) found (%s format):\n", len(routes), params.FormatName()), ret, nil), nil
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initTransactions() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "transactions_rollback",
+			Description: "Roll back a transaction of resources_create_or_update (called with transaction=true), restoring the resources it changed to their state before it: " +
+				"the created resources are deleted, and the updated ones are replaced by their previous state, overwriting any change made since. " +
+				"Transactions can be rolled back for 24 hours, once",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "string",
+						Description: "ID of the transaction, returned by resources_create_or_update",
+					},
+				},
+				Required: []string{"id"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Transactions: Rollback",
+				DestructiveHint: ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: transactionsRollback, Permissions: transactionsRollbackPermissions},
+	}
+}
+
+func transactionsRollback(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	id, _ := params.GetArguments()["id"].(string)
+	if id == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to roll back transaction, missing argument id"))), nil
+	}
+	transaction, err := kubernetes.NewCore(params).TransactionRollback(params, id)
+	switch {
+	case errors.Is(err, kubernetes.ErrTransactionNotFound):
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeNotFound, err)), nil
+	case errors.Is(err, kubernetes.ErrTransactionRolledBack):
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeConflict, err)), nil
+	}
+	ret, marshalErr := params.Marshal(transaction)
+	if marshalErr != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to roll back transaction %s: %w", id, marshalErr)), nil
+	}
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "transaction rollback")
+		return api.NewToolCallResult("", fmt.Errorf("failed to roll back transaction %s, call transactions_rollback again to retry: %w\n%s", id, err, ret)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Transaction %s has been rolled back successfully (%s format):\n", id, params.FormatName()), ret, nil), nil
+}
+
+// resourcesCreateOrUpdateInTransaction creates or updates the resources recording their previous state, the ID of the
+// transaction is returned with the result, or with the error if some of the resources were applied
+func resourcesCreateOrUpdateInTransaction(params api.ToolHandlerParams, resource string) (*api.ToolCallResult, error) {
+	transaction, previous, resources, err := kubernetes.NewCore(params).ResourcesCreateOrUpdateInTransaction(params, resource)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "resource creation or update")
+		if transaction != nil {
+			err = fmt.Errorf("%w, the %d resource(s) applied before the failure can be rolled back with transaction %s", err, len(transaction.Objects), transaction.ID)
+		}
+		return api.NewToolCallResult("", fmt.Errorf("failed to create or update resources: %w", err)), nil
+	}
+	header := fmt.Sprintf("# Transaction %s recorded the previous state of the resources, call transactions_rollback to undo the changes\n", transaction.ID)
+	if params.GetArguments()["diff"] == true {
+		diff, err := printDiff(previous, resources)
+		return api.NewToolCallResult(header+diff, err), nil
+	}
+	for _, resource := range resources {
+		output.Prune(resource, params.PruneFields)
+	}
+	marshalledYaml, err := params.Marshal(resources)
+	if err != nil {
+		err = fmt.Errorf("failed to create or update resources: %w", err)
+	}
+	return api.NewStructuredToolCallResult(header+fmt.Sprintf("# The following resources (%s) have been created or updated successfully\n", params.FormatName()), marshalledYaml, err), nil
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+func initTransient() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "transient_cleanup",
+			Description: "Delete the transient objects created in the cluster by this server to run its tools (node debug and network check Pods) " +
+				"whose tool call is over, e.g. the objects left behind by a crash or a restart of the server. " +
+				"The transient objects are labeled " + kubernetes.TransientLabel + "=true and expire after the timeout of their tool call, " +
+				"only the expired objects are deleted unless all is true. Use dry_run to list the transient objects without deleting them",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace to delete the transient objects from (Optional, all namespaces if not provided)",
+					},
+					"all": {
+						Type:        "boolean",
+						Description: "If true, also deletes the transient objects that haven't expired yet, interrupting the tool calls in progress (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+					"dry_run": {
+						Type:        "boolean",
+						Description: "If true, only lists the transient objects that would be deleted (Optional)",
+						Default:     api.ToRawMessage(false),
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Transient Objects: Cleanup",
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: transientCleanup, Permissions: transientCleanupPermissions},
+	}
+}
+
+func transientCleanup(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	all, _ := params.GetArguments()["all"].(bool)
+	dryRun, _ := params.GetArguments()["dry_run"].(bool)
+	deleted, err := kubernetes.NewCore(params).TransientCleanup(params, namespace, all, dryRun)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "transient objects cleanup")
+		return api.NewToolCallResult("", fmt.Errorf("failed to clean up transient objects: %w", err)), nil
+	}
+	if len(deleted) == 0 {
+		return api.NewToolCallResult("# No transient objects to delete", nil), nil
+	}
+	ret, err := params.Marshal(deleted)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to clean up transient objects: %w", err)), nil
+	}
+	if dryRun {
+		return api.NewStructuredToolCallResult(fmt.Sprintf("# %d transient object(s) would be deleted (dry run, the objects were not deleted) (%s format):\n", len(deleted), params.FormatName()), ret, nil), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d transient object(s) deleted (%s format):\n", len(deleted), params.FormatName()), ret, nil), nil
+}
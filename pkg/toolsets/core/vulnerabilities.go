@@ -0,0 +1,83 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+func initVulnerabilities() []api.ServerTool {
+	kinds := make([]any, 0, len(kubernetes.VulnerabilityWorkloadKinds))
+	for _, kind := range kubernetes.VulnerabilityWorkloadKinds {
+		kinds = append(kinds, kind)
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "images_vulnerabilities",
+			Description: "Get the vulnerabilities (CVEs) of the container images run by a Kubernetes workload or by all the workloads in a namespace, " +
+				"as reported by the in-cluster vulnerability scanner (requires the Trivy Operator and its VulnerabilityReports). " +
+				"Returns the number of vulnerabilities by severity for each image, the workloads running it, and the details of the worst findings",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"namespace": {
+						Type:        "string",
+						Description: "Optional Namespace of the workloads. If not provided, will use the configured namespace",
+					},
+					"kind": {
+						Type:        "string",
+						Description: "Optional kind of the workload to get the image vulnerabilities for (requires name). If not provided, will report all the images in the namespace",
+						Enum:        kinds,
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the workload (required if kind is provided)",
+					},
+				},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Images: Vulnerabilities",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: imagesVulnerabilities},
+	}
+}
+
+func imagesVulnerabilities(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	kind, _ := params.GetArguments()["kind"].(string)
+	name, _ := params.GetArguments()["name"].(string)
+	if kind != "" && name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to get image vulnerabilities, missing argument name"))), nil
+	}
+	images, err := kubernetes.NewCore(params).ImageVulnerabilities(params, namespace, kind, name)
+	if errors.Is(err, kubernetes.ErrNoVulnerabilityScanner) {
+		return api.NewToolCallResult("", err), nil
+	}
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "image vulnerabilities retrieval")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get image vulnerabilities: %w", err)), nil
+	}
+	if len(images) == 0 {
+		return api.NewToolCallResult("# No vulnerability reports found", nil), nil
+	}
+	total := kubernetes.VulnerabilityCounts{}
+	for _, image := range images {
+		total.Critical += image.Counts.Critical
+		total.High += image.Counts.High
+	}
+	ret, err := params.Marshal(images)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get image vulnerabilities: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d image(s), %d critical and %d high vulnerabilities (%s format):\n",
+		len(images), total.Critical, total.High, params.FormatName()), ret, nil), nil
+}
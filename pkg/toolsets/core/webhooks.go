@@ -0,0 +1,54 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+func initWebhooks() []api.ServerTool {
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "webhooks_audit",
+			Description: "Audit the Kubernetes admission webhooks (ValidatingWebhookConfigurations and MutatingWebhookConfigurations). " +
+				"Reports the failurePolicy, timeout, targeted operations and resources, and the health of the backing Service of each webhook, " +
+				"and flags the webhooks whose backend is down, that would block critical cluster operations if their backend went down, or with high timeouts",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Webhooks: Audit",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: webhooksAudit},
+	}
+}
+
+func webhooksAudit(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	audits, err := kubernetes.NewCore(params).WebhooksAudit(params)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "admission webhooks audit")
+		return api.NewToolCallResult("", fmt.Errorf("failed to audit admission webhooks: %w", err)), nil
+	}
+	if len(audits) == 0 {
+		return api.NewToolCallResult("# No admission webhooks found", nil), nil
+	}
+	withIssues := 0
+	for _, audit := range audits {
+		if len(audit.Issues) > 0 {
+			withIssues++
+		}
+	}
+	ret, err := params.Marshal(audits)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to audit admission webhooks: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d admission webhook(s), %d with issues (%s format):\n", len(audits), withIssues, params.FormatName()), ret, nil), nil
+}
@@ -0,0 +1,376 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+// defaultConfigRolloutTimeout is the default time (seconds) to wait for the rollouts of the restarted workloads
+const defaultConfigRolloutTimeout = 300
+
+func initWorkloads() []api.ServerTool {
+	kinds := make([]any, 0, len(kubernetes.WorkloadReadinessKinds))
+	for _, kind := range kubernetes.WorkloadReadinessKinds {
+		kinds = append(kinds, kind)
+	}
+	imageKinds := make([]any, 0, len(kubernetes.WorkloadImageKinds))
+	for _, kind := range kubernetes.WorkloadImageKinds {
+		imageKinds = append(imageKinds, kind)
+	}
+	configKinds := make([]any, 0, len(kubernetes.ConfigRolloutKinds))
+	for _, kind := range kubernetes.ConfigRolloutKinds {
+		configKinds = append(configKinds, kind)
+	}
+	return []api.ServerTool{
+		{Tool: api.Tool{
+			Name: "workload_readiness",
+			Description: "Capture a snapshot of the readiness of a Kubernetes workload (ready, updated, and available replicas, and the readiness and restarts of its Pods), " +
+				"or compare the current readiness with a snapshot captured earlier to answer \"did my last change make things worse\". " +
+				"Call it without a baseline before making a change, then call it again with the returned snapshot as the baseline to detect regressions " +
+				"(fewer ready replicas, Pods no longer ready, new container restarts)",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the workload",
+						Enum:        kinds,
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the workload",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the workload",
+					},
+					"baseline": {
+						Type:        "string",
+						Description: "Snapshot previously returned by this tool (YAML). If provided, the current readiness is compared with it and the regressions are reported (Optional)",
+					},
+				},
+				Required: []string{"kind", "name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Workload: Readiness",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: workloadReadiness},
+		{Tool: api.Tool{
+			Name: "config_rollout",
+			Description: "Update the keys of a ConfigMap or Secret, then restart exactly the workloads (Deployments, StatefulSets, DaemonSets) of its namespace " +
+				"mounting it as a volume or referencing it in their environment, and wait for their rollouts to complete, as a single operation. " +
+				"The Pods don't pick up the changes of the environment variables (and of the subPath mounts) without a restart. " +
+				"Progress notifications are sent as each step completes. Use dry_run to list the workloads that would be restarted",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the configuration",
+						Enum:        configKinds,
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the configuration",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the configuration",
+					},
+					"data": {
+						Type:        "object",
+						Description: "Keys to set and their values, in plain text for the Secrets too (Optional)",
+						Properties:  make(map[string]*jsonschema.Schema),
+					},
+					"remove_keys": {
+						Type:        "array",
+						Description: "Keys to remove (Optional)",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+					"dry_run": {
+						Type:        "boolean",
+						Description: "If true, the workloads that would be restarted are returned without changing anything (Optional, default: false)",
+					},
+					"timeout": {
+						Type:        "integer",
+						Description: "Maximum time in seconds to wait for the rollouts of the restarted workloads to complete, 0 to not wait (Optional, default: 300)",
+						Default:     api.ToRawMessage(defaultConfigRolloutTimeout),
+						Minimum:     ptr.To(float64(0)),
+					},
+				},
+				Required: []string{"kind", "name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Configuration: Rollout",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: configRollout, Permissions: configRolloutPermissions, LongRunning: ptr.To(true)},
+		{Tool: api.Tool{
+			Name: "workloads_set_image",
+			Description: "Update the images of containers of a Kubernetes Deployment, StatefulSet, or DaemonSet, rolling out a new revision without editing its manifest. " +
+				"The change cause is recorded in the kubernetes.io/change-cause annotation and shows up in the rollout history. " +
+				"To roll back an image, get the images of the previous revisions with workloads_image_history and set them again",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the workload",
+						Enum:        imageKinds,
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the workload",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the workload",
+					},
+					"images": {
+						Type:        "object",
+						Description: "Images to set by container name, init containers included (e.g. {\"app\": \"registry.example.com/app:1.2.3\"})",
+						Properties:  make(map[string]*jsonschema.Schema),
+					},
+					"change_cause": {
+						Type:        "string",
+						Description: "Cause of the change recorded in the kubernetes.io/change-cause annotation (Optional, default: a description of the image changes)",
+					},
+				},
+				Required: []string{"kind", "name", "images"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Workload: Set Image",
+				ReadOnlyHint:    ptr.To(false),
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: workloadsSetImage, Permissions: workloadsSetImagePermissions},
+		{Tool: api.Tool{
+			Name: "workloads_image_history",
+			Description: "List the images of the containers of each revision of the rollout history of a Kubernetes Deployment (its ReplicaSets), " +
+				"StatefulSet, or DaemonSet (its ControllerRevisions), the oldest revision first, with their change causes. " +
+				"Use it to find the images to roll back to with workloads_set_image",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the workload",
+						Enum:        imageKinds,
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the workload",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the workload",
+					},
+				},
+				Required: []string{"kind", "name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Workload: Image History",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: workloadsImageHistory},
+	}
+}
+
+func workloadReadiness(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	kind, _ := params.GetArguments()["kind"].(string)
+	if kind == "" {
+		return api.NewToolCallResult("", errors.New("failed to get workload readiness, missing argument kind")), nil
+	}
+	name, _ := params.GetArguments()["name"].(string)
+	if name == "" {
+		return api.NewToolCallResult("", errors.New("failed to get workload readiness, missing argument name")), nil
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	var baseline *kubernetes.WorkloadReadiness
+	if v, _ := params.GetArguments()["baseline"].(string); v != "" {
+		if err := yaml.Unmarshal([]byte(v), &baseline); err != nil || baseline == nil {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse baseline, expected a snapshot previously returned by this tool: %v", err))), nil
+		}
+	}
+	current, err := kubernetes.NewCore(params).WorkloadReadiness(params, kind, namespace, name)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "workload readiness")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get readiness of %s %s: %w", kind, name, err)), nil
+	}
+	if baseline == nil {
+		ret, err := params.Marshal(current)
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to get readiness of %s %s: %w", kind, name, err)), nil
+		}
+		return api.NewStructuredToolCallResult(fmt.Sprintf("# Readiness snapshot of %s %s/%s (%s format), provide it as the baseline to compare after a change:\n",
+			current.Kind, current.Namespace, current.Name, params.FormatName()), ret, nil), nil
+	}
+	if baseline.Kind != current.Kind || baseline.Namespace != current.Namespace || baseline.Name != current.Name {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("the baseline was captured for %s %s/%s, not for %s %s/%s",
+			baseline.Kind, baseline.Namespace, baseline.Name, current.Kind, current.Namespace, current.Name))), nil
+	}
+	comparison := kubernetes.CompareReadiness(baseline, current)
+	ret, err := params.Marshal(comparison)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to compare readiness of %s %s: %w", kind, name, err)), nil
+	}
+	header := fmt.Sprintf("# %s %s/%s has regressed since the baseline (%s format):\n", current.Kind, current.Namespace, current.Name, params.FormatName())
+	if !comparison.Regressed {
+		header = fmt.Sprintf("# No readiness regressions for %s %s/%s since the baseline (%s format):\n", current.Kind, current.Namespace, current.Name, params.FormatName())
+	}
+	return api.NewStructuredToolCallResult(header, ret, nil), nil
+}
+
+func configRollout(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	kind, _ := params.GetArguments()["kind"].(string)
+	if kind == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to roll out configuration, missing argument kind"))), nil
+	}
+	name, _ := params.GetArguments()["name"].(string)
+	if name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to roll out configuration, missing argument name"))), nil
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	data := make(map[string]string)
+	if v, ok := params.GetArguments()["data"].(map[string]any); ok {
+		for key, value := range v {
+			switch value.(type) {
+			case string, float64, bool:
+				data[key] = fmt.Sprint(value)
+			default:
+				return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to roll out configuration, the value of key %s is not a string", key))), nil
+			}
+		}
+	}
+	var removeKeys []string
+	if v, ok := params.GetArguments()["remove_keys"].([]any); ok {
+		for _, key := range v {
+			if k, ok := key.(string); ok {
+				removeKeys = append(removeKeys, k)
+			}
+		}
+	}
+	dryRun, _ := params.GetArguments()["dry_run"].(bool)
+	if len(data) == 0 && len(removeKeys) == 0 && !dryRun {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to roll out configuration, provide the data to set or the keys to remove"))), nil
+	}
+	timeout := defaultConfigRolloutTimeout * time.Second
+	if v, ok := params.GetArguments()["timeout"]; ok && v != nil {
+		seconds, err := api.ParseInt64(v)
+		if err != nil || seconds < 0 {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to parse timeout parameter: %v", v))), nil
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	rollout, err := kubernetes.NewCore(params).ConfigRollout(params, kind, namespace, name, data, removeKeys, dryRun, timeout,
+		func(step, total int, message string) {
+			mcplog.SendMCPProgress(params, float64(step), float64(total), message)
+		})
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "configuration rollout")
+		return api.NewToolCallResult("", fmt.Errorf("failed to roll out %s %s: %w", kind, name, err)), nil
+	}
+	ret, err := params.Marshal(rollout)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to roll out %s %s: %w", kind, name, err)), nil
+	}
+	pending := 0
+	for _, workload := range rollout.Workloads {
+		if workload.Status == kubernetes.ConfigRolloutFailed || workload.Status == kubernetes.ConfigRolloutTimedOut {
+			pending++
+		}
+	}
+	var header string
+	switch {
+	case rollout.DryRun:
+		header = fmt.Sprintf("# Dry run, %d workload(s) referencing %s %s/%s would be restarted (%s format):\n", len(rollout.Workloads), rollout.Kind, rollout.Namespace, rollout.Name, params.FormatName())
+	case pending > 0:
+		header = fmt.Sprintf("# %s %s/%s updated, %d of %d workload(s) referencing it failed to restart or roll out, check their status (%s format):\n",
+			rollout.Kind, rollout.Namespace, rollout.Name, pending, len(rollout.Workloads), params.FormatName())
+	default:
+		header = fmt.Sprintf("# %s %s/%s updated and %d workload(s) referencing it restarted (%s format):\n", rollout.Kind, rollout.Namespace, rollout.Name, len(rollout.Workloads), params.FormatName())
+	}
+	return api.NewStructuredToolCallResult(header, ret, nil), nil
+}
+
+func workloadsSetImage(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	kind, _ := params.GetArguments()["kind"].(string)
+	if kind == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to set image, missing argument kind"))), nil
+	}
+	name, _ := params.GetArguments()["name"].(string)
+	if name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to set image, missing argument name"))), nil
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	images := make(map[string]string)
+	if v, ok := params.GetArguments()["images"].(map[string]any); ok {
+		for container, image := range v {
+			s, ok := image.(string)
+			if !ok || s == "" {
+				return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to set image, the image of container %s is not a string", container))), nil
+			}
+			images[container] = s
+		}
+	}
+	if len(images) == 0 {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to set image, provide the images to set by container name"))), nil
+	}
+	changeCause, _ := params.GetArguments()["change_cause"].(string)
+	result, err := kubernetes.NewCore(params).SetImage(params, kind, namespace, name, images, changeCause)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "workload image update")
+		return api.NewToolCallResult("", fmt.Errorf("failed to set image of %s %s: %w", kind, name, err)), nil
+	}
+	ret, err := params.Marshal(result)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to set image of %s %s: %w", kind, name, err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Images of %s %s/%s updated, a new revision is rolling out, check it with workload_readiness (%s format):\n",
+		result.Kind, result.Namespace, result.Name, params.FormatName()), ret, nil), nil
+}
+
+func workloadsImageHistory(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	kind, _ := params.GetArguments()["kind"].(string)
+	if kind == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to get image history, missing argument kind"))), nil
+	}
+	name, _ := params.GetArguments()["name"].(string)
+	if name == "" {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, errors.New("failed to get image history, missing argument name"))), nil
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	history, err := kubernetes.NewCore(params).ImageHistory(params, kind, namespace, name)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "workload image history")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get image history of %s %s: %w", kind, name, err)), nil
+	}
+	if len(history.Revisions) == 0 {
+		return api.NewToolCallResult(fmt.Sprintf("# No rollout history found for %s %s/%s", history.Kind, history.Namespace, history.Name), nil), nil
+	}
+	ret, err := params.Marshal(history)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get image history of %s %s: %w", kind, name, err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Image history of %s %s/%s, %d revision(s) (%s format):\n",
+		history.Kind, history.Namespace, history.Name, len(history.Revisions), params.FormatName()), ret, nil), nil
+}
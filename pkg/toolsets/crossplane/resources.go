@@ -0,0 +1,187 @@
+package crossplane
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/crossplane"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+	"github.com/containers/kubernetes-mcp-server/pkg/output"
+)
+
+func initResources() []api.ServerTool {
+	categories := make([]any, 0, len(crossplane.Categories))
+	for _, category := range crossplane.Categories {
+		categories = append(categories, category)
+	}
+	return []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name: "crossplane_resources_list",
+				Description: "List the Crossplane claims, composites, and managed resources with their Ready and Synced conditions, " +
+					"the error messages of the conditions that are not True (for the managed resources the errors returned by the provider), " +
+					"their external name, and their claim and composite. Unhealthy resources are listed first",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"category": {
+							Type:        "string",
+							Description: "Category of the Crossplane resources to list (Optional, all the categories if not provided)",
+							Enum:        categories,
+						},
+						"kind": {
+							Type:        "string",
+							Description: "Kind of the Crossplane resources to list, e.g. Bucket or XPostgreSQLInstance (Optional, all the kinds if not provided)",
+						},
+						"namespace": {
+							Type: "string",
+							Description: "Namespace to list the Crossplane resources from, the cluster-scoped resources are filtered by the namespace of their claim " +
+								"(Optional, all namespaces if not provided)",
+						},
+					},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Crossplane: Resources List",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler: resourcesList,
+		},
+		{
+			Tool: api.Tool{
+				Name: "crossplane_resource_tree",
+				Description: "Get the tree of a Crossplane claim or composite: its composite and composed resources (recursively) with their Ready and Synced conditions " +
+					"and error messages, and the root causes of its unhealthiness (the errors of the deepest unhealthy resources, usually the provider errors of the managed resources)",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"apiVersion": {
+							Type:        "string",
+							Description: "apiVersion of the claim or composite (e.g. database.example.org/v1alpha1)",
+						},
+						"kind": {
+							Type:        "string",
+							Description: "kind of the claim or composite (e.g. PostgreSQLInstance)",
+						},
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace of the claim (Optional, current namespace if not provided, ignored for the cluster-scoped composites)",
+						},
+						"name": {
+							Type:        "string",
+							Description: "Name of the claim or composite",
+						},
+					},
+					Required: []string{"apiVersion", "kind", "name"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Crossplane: Resource Tree",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler: resourceTree,
+		},
+	}
+}
+
+func resourcesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace := api.OptionalString(params, "namespace", "")
+	kind := api.OptionalString(params, "kind", "")
+	var categories []string
+	if category := api.OptionalString(params, "category", ""); category != "" {
+		categories = []string{category}
+	}
+	apiResources, err := crossplane.Discover(params.DiscoveryClient(), categories...)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list Crossplane resources: %w", err)), nil
+	}
+	if len(apiResources) == 0 {
+		return api.NewToolCallResult("", errors.New("failed to list Crossplane resources: Crossplane is not installed in the cluster")), nil
+	}
+	resources := make([]crossplane.Resource, 0)
+	for _, apiResource := range apiResources {
+		if kind != "" && apiResource.Kind != kind {
+			continue
+		}
+		list, err := crossplane.ListResources(params.Context, params.DynamicClient(), apiResource, namespace)
+		if err != nil {
+			mcplog.HandleK8sError(params.Context, err, "Crossplane resource listing")
+			return api.NewToolCallResult("", fmt.Errorf("failed to list Crossplane resources: %w", err)), nil
+		}
+		resources = append(resources, list...)
+	}
+	if len(resources) == 0 {
+		return api.NewToolCallResult("# No Crossplane resources found", nil), nil
+	}
+	crossplane.SortResources(resources)
+	unhealthy := 0
+	for _, resource := range resources {
+		if !resource.Healthy() {
+			unhealthy++
+		}
+	}
+	ret, err := params.Marshal(resources)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list Crossplane resources: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d Crossplane resource(s) found, %d not ready or not synced (%s format):\n",
+		len(resources), unhealthy, params.FormatName()), ret, nil), nil
+}
+
+func resourceTree(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	apiVersion, err := api.RequiredString(params, "apiVersion")
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get Crossplane resource tree: %w", err)), nil
+	}
+	kind, err := api.RequiredString(params, "kind")
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get Crossplane resource tree: %w", err)), nil
+	}
+	name, err := api.RequiredString(params, "name")
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get Crossplane resource tree: %w", err)), nil
+	}
+	namespace := params.NamespaceOrDefault(api.OptionalString(params, "namespace", ""))
+	tree, err := crossplane.Tree(params.Context, params.DynamicClient(), params.RESTMapper(), apiVersion, kind, namespace, name)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "Crossplane resource access")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get Crossplane resource tree: %w", err)), nil
+	}
+	total, unhealthy := crossplane.Count(tree)
+	// The root causes are marshalled first so that they are not buried below the tree
+	ret, err := marshalTree(params, crossplane.RootCauses(tree), tree)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get Crossplane resource tree: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %s %s: Ready=%s, Synced=%s, %d of %d resource(s) not ready or not synced (%s format):\n",
+		kind, name, tree.Ready, tree.Synced, unhealthy, total, params.FormatName()), ret, nil), nil
+}
+
+// marshalTree marshals the root causes (if any) followed by the tree.
+// The keys of the YAML mappings are sorted, so the YAML root causes and tree are marshalled one after the other.
+func marshalTree(params api.ToolHandlerParams, rootCauses []string, tree *crossplane.Resource) (string, error) {
+	if params.ListOutput == output.Json {
+		return params.Marshal(struct {
+			RootCauses []string `json:"rootCauses,omitempty"`
+			Resource   any      `json:"resource"`
+		}{RootCauses: rootCauses, Resource: tree})
+	}
+	ret := ""
+	if len(rootCauses) > 0 {
+		causes, err := params.Marshal(map[string]any{"rootCauses": rootCauses})
+		if err != nil {
+			return "", err
+		}
+		ret = causes
+	}
+	resource, err := params.Marshal(map[string]any{"resource": tree})
+	return ret + resource, err
+}
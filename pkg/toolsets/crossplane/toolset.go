@@ -0,0 +1,38 @@
+package crossplane
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/crossplane"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return "crossplane"
+}
+
+func (t *Toolset) GetDescription() string {
+	return "Crossplane tools to inspect claims, composites, and managed resources, their Ready and Synced conditions, and the errors of their providers"
+}
+
+func (t *Toolset) GetTools(_ api.Openshift) []api.ServerTool {
+	return api.WithAPIGroups(
+		slices.Concat(
+			initResources(),
+		),
+		crossplane.APIGroup,
+	)
+}
+
+func (t *Toolset) GetPrompts() []api.ServerPrompt {
+	return nil
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
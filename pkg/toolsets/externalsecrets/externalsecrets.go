@@ -0,0 +1,192 @@
+package externalsecrets
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/externalsecrets"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+func initExternalSecrets() []api.ServerTool {
+	kinds := make([]any, 0, len(externalsecrets.SecretKinds)+len(externalsecrets.StoreKinds))
+	for _, kind := range slices.Concat(externalsecrets.SecretKinds, externalsecrets.StoreKinds) {
+		kinds = append(kinds, kind)
+	}
+	return []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name: "externalsecrets_list",
+				Description: "List the External Secrets Operator ExternalSecrets, ClusterExternalSecrets, SecretStores, and ClusterSecretStores with their Ready condition " +
+					"and last synchronization or validation error, the target Secret, refresh interval, and last refresh time of the ExternalSecrets, " +
+					"and the readiness of their store. Not ready resources are listed first",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"kind": {
+							Type:        "string",
+							Description: "Kind of the External Secrets Operator resources to list (Optional, all the supported kinds if not provided)",
+							Enum:        kinds,
+						},
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace to list the namespaced resources from (Optional, all namespaces if not provided)",
+						},
+					},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "External Secrets: List",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler: externalSecretsList,
+		},
+		{
+			Tool: api.Tool{
+				Name: "externalsecret_refresh",
+				Description: "Request the External Secrets Operator to synchronize an ExternalSecret with its provider immediately, out of its refresh interval " +
+					"(sets the force-sync annotation). Use externalsecrets_list to follow the synchronization",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace of the ExternalSecret (Optional, current namespace if not provided)",
+						},
+						"name": {
+							Type:        "string",
+							Description: "Name of the ExternalSecret",
+						},
+					},
+					Required: []string{"name"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "External Secrets: Refresh",
+					ReadOnlyHint:    ptr.To(false),
+					DestructiveHint: ptr.To(false),
+					IdempotentHint:  ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler:     externalSecretRefresh,
+			Permissions: externalSecretRefreshPermissions,
+		},
+	}
+}
+
+func externalSecretsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace := api.OptionalString(params, "namespace", "")
+	kinds := slices.Concat(externalsecrets.SecretKinds, externalsecrets.StoreKinds)
+	if kind := api.OptionalString(params, "kind", ""); kind != "" {
+		kinds = []string{kind}
+	}
+	resources := make([]externalsecrets.Resource, 0)
+	installed := 0
+	for _, kind := range kinds {
+		gvr, err := externalsecrets.ResourceFor(params.RESTMapper(), kind)
+		if meta.IsNoMatchError(err) && len(kinds) > 1 {
+			continue
+		}
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to list External Secrets resources: %w", err)), nil
+		}
+		installed++
+		listNamespace := namespace
+		if externalsecrets.ClusterScoped(kind) {
+			listNamespace = ""
+		}
+		list, err := externalsecrets.ListResources(params.Context, params.DynamicClient(), gvr, listNamespace)
+		if err != nil {
+			mcplog.HandleK8sError(params.Context, err, "External Secrets resource listing")
+			return api.NewToolCallResult("", fmt.Errorf("failed to list External Secrets resources: %w", err)), nil
+		}
+		resources = append(resources, list...)
+	}
+	if installed == 0 {
+		return api.NewToolCallResult("", errors.New("failed to list External Secrets resources: External Secrets Operator is not installed in the cluster")), nil
+	}
+	if len(resources) == 0 {
+		return api.NewToolCallResult("# No External Secrets resources found", nil), nil
+	}
+	externalsecrets.LinkStores(resources, stores(params, namespace, resources))
+	externalsecrets.SortResources(resources)
+	notReady := 0
+	for _, resource := range resources {
+		if resource.Ready != "True" {
+			notReady++
+		}
+	}
+	ret, err := params.Marshal(resources)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list External Secrets resources: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d External Secrets resource(s) found, %d not ready (%s format):\n",
+		len(resources), notReady, params.FormatName()), ret, nil), nil
+}
+
+// stores returns the stores to link to the listed ExternalSecrets, listing them if they were not listed
+func stores(params api.ToolHandlerParams, namespace string, resources []externalsecrets.Resource) []externalsecrets.Resource {
+	ret := slices.DeleteFunc(slices.Clone(resources), func(resource externalsecrets.Resource) bool {
+		return !slices.Contains(externalsecrets.StoreKinds, resource.Kind)
+	})
+	if len(ret) > 0 {
+		return ret
+	}
+	// The stores are best-effort, they might not be accessible
+	for _, kind := range externalsecrets.StoreKinds {
+		gvr, err := externalsecrets.ResourceFor(params.RESTMapper(), kind)
+		if err != nil {
+			continue
+		}
+		listNamespace := namespace
+		if externalsecrets.ClusterScoped(kind) {
+			listNamespace = ""
+		}
+		if list, err := externalsecrets.ListResources(params.Context, params.DynamicClient(), gvr, listNamespace); err == nil {
+			ret = append(ret, list...)
+		}
+	}
+	return ret
+}
+
+// externalSecretRefreshPermissions returns the permission to patch the force-sync annotation of the ExternalSecret
+func externalSecretRefreshPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	gvr, err := externalsecrets.ResourceFor(params.RESTMapper(), externalsecrets.ExternalSecretGK.Kind)
+	if err != nil {
+		return nil, err
+	}
+	namespace := params.NamespaceOrDefault(api.OptionalString(params, "namespace", ""))
+	return []api.Permission{{Verb: "patch", Group: gvr.Group, Resource: gvr.Resource, Namespace: namespace}}, nil
+}
+
+func externalSecretRefresh(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, err := api.RequiredString(params, "name")
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to refresh ExternalSecret: %w", err)), nil
+	}
+	namespace := params.NamespaceOrDefault(api.OptionalString(params, "namespace", ""))
+	gvr, err := externalsecrets.ResourceFor(params.RESTMapper(), externalsecrets.ExternalSecretGK.Kind)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to refresh ExternalSecret: %w", err)), nil
+	}
+	externalSecret, err := externalsecrets.Refresh(params.Context, params.DynamicClient(), gvr, namespace, name, time.Now())
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "ExternalSecret refresh")
+		return api.NewToolCallResult("", fmt.Errorf("failed to refresh ExternalSecret: %w", err)), nil
+	}
+	ret, err := params.Marshal(externalsecrets.Summarize(externalSecret))
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to refresh ExternalSecret: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# Refresh of ExternalSecret %s/%s requested, use externalsecrets_list to follow its progress (%s format):\n",
+		namespace, name, params.FormatName()), ret, nil), nil
+}
@@ -0,0 +1,38 @@
+package externalsecrets
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/externalsecrets"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return "externalsecrets"
+}
+
+func (t *Toolset) GetDescription() string {
+	return "External Secrets Operator tools to inspect the synchronization of ExternalSecrets and the status of SecretStores, and to refresh ExternalSecrets"
+}
+
+func (t *Toolset) GetTools(_ api.Openshift) []api.ServerTool {
+	return api.WithAPIGroups(
+		slices.Concat(
+			initExternalSecrets(),
+		),
+		externalsecrets.ExternalSecretGK.Group,
+	)
+}
+
+func (t *Toolset) GetPrompts() []api.ServerPrompt {
+	return nil
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
@@ -0,0 +1,261 @@
+package flux
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/flux"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+func initResources() []api.ServerTool {
+	kinds := make([]any, 0, len(flux.Kinds))
+	for _, kind := range flux.Kinds {
+		kinds = append(kinds, kind)
+	}
+	resourceProperties := func(action string) map[string]*jsonschema.Schema {
+		return map[string]*jsonschema.Schema{
+			"kind": {
+				Type:        "string",
+				Description: "Kind of the Flux resource to " + action,
+				Enum:        kinds,
+			},
+			"namespace": {
+				Type:        "string",
+				Description: "Namespace of the Flux resource (Optional, current namespace if not provided)",
+			},
+			"name": {
+				Type:        "string",
+				Description: "Name of the Flux resource to " + action,
+			},
+		}
+	}
+	reconcileProperties := resourceProperties("reconcile")
+	reconcileProperties["force"] = &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Force the upgrade of a HelmRelease even if its values and chart did not change (Optional, only for HelmReleases)",
+		Default:     api.ToRawMessage(false),
+	}
+	return []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name: "flux_resources_list",
+				Description: "List the Flux Kustomizations and HelmReleases with their Ready condition (status, reason, and message), source, " +
+					"last applied and last attempted revision, and whether they are suspended. Not ready resources are listed first",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"kind": {
+							Type:        "string",
+							Description: "Kind of the Flux resources to list (Optional, all the supported kinds if not provided)",
+							Enum:        kinds,
+						},
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace to list the Flux resources from (Optional, all namespaces if not provided)",
+						},
+					},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Flux: Resources List",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler: resourcesList,
+		},
+		{
+			Tool: api.Tool{
+				Name: "flux_reconcile",
+				Description: "Trigger the reconciliation of a Flux Kustomization or HelmRelease out of its interval by setting the reconcile.fluxcd.io/requestedAt annotation " +
+					"(same as the flux reconcile command). The changes in the source are applied to the cluster",
+				InputSchema: &jsonschema.Schema{
+					Type:       "object",
+					Properties: reconcileProperties,
+					Required:   []string{"kind", "name"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Flux: Reconcile",
+					ReadOnlyHint:    ptr.To(false),
+					DestructiveHint: ptr.To(true),
+					IdempotentHint:  ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler:     reconcile,
+			Permissions: patchPermissions,
+		},
+		{
+			Tool: api.Tool{
+				Name:        "flux_suspend",
+				Description: "Suspend the reconciliation of a Flux Kustomization or HelmRelease, the changes in its source are no longer applied until it is resumed",
+				InputSchema: &jsonschema.Schema{
+					Type:       "object",
+					Properties: resourceProperties("suspend"),
+					Required:   []string{"kind", "name"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Flux: Suspend",
+					ReadOnlyHint:    ptr.To(false),
+					DestructiveHint: ptr.To(false),
+					IdempotentHint:  ptr.To(true),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler:     suspend,
+			Permissions: patchPermissions,
+		},
+		{
+			Tool: api.Tool{
+				Name:        "flux_resume",
+				Description: "Resume the reconciliation of a suspended Flux Kustomization or HelmRelease and trigger its reconciliation. The changes in the source are applied to the cluster",
+				InputSchema: &jsonschema.Schema{
+					Type:       "object",
+					Properties: resourceProperties("resume"),
+					Required:   []string{"kind", "name"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Flux: Resume",
+					ReadOnlyHint:    ptr.To(false),
+					DestructiveHint: ptr.To(true),
+					IdempotentHint:  ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler:     resume,
+			Permissions: patchPermissions,
+		},
+	}
+}
+
+func resourcesList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace := api.OptionalString(params, "namespace", "")
+	kinds := flux.Kinds
+	if kind := api.OptionalString(params, "kind", ""); kind != "" {
+		kinds = []string{kind}
+	}
+	resources := make([]flux.Resource, 0)
+	installed := 0
+	for _, kind := range kinds {
+		gvr, err := flux.ResourceFor(params.RESTMapper(), kind)
+		// The Flux controllers are optional, e.g. the helm-controller might not be installed
+		if meta.IsNoMatchError(err) && len(kinds) > 1 {
+			continue
+		}
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to list Flux resources: %w", err)), nil
+		}
+		installed++
+		list, err := flux.ListResources(params.Context, params.DynamicClient(), gvr, namespace)
+		if err != nil {
+			mcplog.HandleK8sError(params.Context, err, "Flux resource listing")
+			return api.NewToolCallResult("", fmt.Errorf("failed to list Flux resources: %w", err)), nil
+		}
+		resources = append(resources, list...)
+	}
+	if installed == 0 {
+		return api.NewToolCallResult("", errors.New("failed to list Flux resources: Flux is not installed in the cluster")), nil
+	}
+	if len(resources) == 0 {
+		return api.NewToolCallResult("# No Flux resources found", nil), nil
+	}
+	flux.SortResources(resources)
+	notReady, suspended := 0, 0
+	for _, resource := range resources {
+		if resource.Ready != "True" {
+			notReady++
+		}
+		if resource.Suspended {
+			suspended++
+		}
+	}
+	ret, err := params.Marshal(resources)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list Flux resources: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d Flux resource(s) found, %d not ready, %d suspended (%s format):\n",
+		len(resources), notReady, suspended, params.FormatName()), ret, nil), nil
+}
+
+func reconcile(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	gvr, namespace, name, err := resourceArguments(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to reconcile Flux resource: %w", err)), nil
+	}
+	force := api.OptionalBool(params, "force", false)
+	resource, err := flux.Reconcile(params.Context, params.DynamicClient(), gvr, namespace, name, force, time.Now())
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "Flux resource reconcile")
+		return api.NewToolCallResult("", fmt.Errorf("failed to reconcile Flux resource: %w", err)), nil
+	}
+	return result(params, resource, "# Reconciliation of %s %s/%s requested, use flux_resources_list to follow its progress (%s format):\n")
+}
+
+func suspend(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	gvr, namespace, name, err := resourceArguments(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to suspend Flux resource: %w", err)), nil
+	}
+	resource, err := flux.SetSuspended(params.Context, params.DynamicClient(), gvr, namespace, name, true, time.Now())
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "Flux resource suspend")
+		return api.NewToolCallResult("", fmt.Errorf("failed to suspend Flux resource: %w", err)), nil
+	}
+	return result(params, resource, "# %s %s/%s suspended (%s format):\n")
+}
+
+func resume(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	gvr, namespace, name, err := resourceArguments(params)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to resume Flux resource: %w", err)), nil
+	}
+	resource, err := flux.SetSuspended(params.Context, params.DynamicClient(), gvr, namespace, name, false, time.Now())
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "Flux resource resume")
+		return api.NewToolCallResult("", fmt.Errorf("failed to resume Flux resource: %w", err)), nil
+	}
+	return result(params, resource, "# %s %s/%s resumed and reconciliation requested (%s format):\n")
+}
+
+// patchPermissions returns the permission to patch the Flux resource, the only request of reconcile, suspend, and resume
+func patchPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	gvr, namespace, _, err := resourceArguments(params)
+	if err != nil {
+		return nil, err
+	}
+	return []api.Permission{{Verb: "patch", Group: gvr.Group, Resource: gvr.Resource, Namespace: namespace}}, nil
+}
+
+// resourceArguments returns the GroupVersionResource, namespace, and name of the Flux resource in the tool arguments
+func resourceArguments(params api.ToolHandlerParams) (schema.GroupVersionResource, string, string, error) {
+	kind, err := api.RequiredString(params, "kind")
+	if err != nil {
+		return schema.GroupVersionResource{}, "", "", err
+	}
+	name, err := api.RequiredString(params, "name")
+	if err != nil {
+		return schema.GroupVersionResource{}, "", "", err
+	}
+	gvr, err := flux.ResourceFor(params.RESTMapper(), kind)
+	if err != nil {
+		return schema.GroupVersionResource{}, "", "", err
+	}
+	return gvr, params.NamespaceOrDefault(api.OptionalString(params, "namespace", "")), name, nil
+}
+
+func result(params api.ToolHandlerParams, resource *unstructured.Unstructured, header string) (*api.ToolCallResult, error) {
+	ret, err := params.Marshal(flux.Summarize(resource))
+	if err != nil {
+		return api.NewToolCallResult("", err), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf(header, resource.GetKind(), resource.GetNamespace(), resource.GetName(), params.FormatName()), ret, nil), nil
+}
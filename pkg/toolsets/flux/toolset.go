@@ -0,0 +1,38 @@
+package flux
+
+import (
+	"slices"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/flux"
+	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
+)
+
+type Toolset struct{}
+
+var _ api.Toolset = (*Toolset)(nil)
+
+func (t *Toolset) GetName() string {
+	return "flux"
+}
+
+func (t *Toolset) GetDescription() string {
+	return "Flux GitOps tools to inspect, reconcile, suspend, and resume Kustomizations and HelmReleases"
+}
+
+func (t *Toolset) GetTools(_ api.Openshift) []api.ServerTool {
+	return api.WithAPIGroups(
+		slices.Concat(
+			initResources(),
+		),
+		flux.KustomizationGK.Group, flux.HelmReleaseGK.Group,
+	)
+}
+
+func (t *Toolset) GetPrompts() []api.ServerPrompt {
+	return nil
+}
+
+func init() {
+	toolsets.Register(&Toolset{})
+}
@@ -0,0 +1,107 @@
+package helm
+
+import (
+	"context"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/helm"
+	"github.com/containers/kubernetes-mcp-server/pkg/objectstorage"
+	"github.com/containers/kubernetes-mcp-server/pkg/sops"
+	"github.com/containers/kubernetes-mcp-server/pkg/vault"
+)
+
+// Config holds the helm toolset configuration
+type Config struct {
+	// Git configures the clones of the charts referenced by git+https URLs
+	Git *helm.GitConfig `toml:"git,omitempty"`
+	// S3 and GCS authenticate the downloads of the charts referenced by s3:// and gs:// URLs,
+	// the ambient credentials of the environment are used if not provided
+	S3  *objectstorage.S3Config  `toml:"s3,omitempty"`
+	GCS *objectstorage.GCSConfig `toml:"gcs,omitempty"`
+	// Cache keeps the downloaded charts and repository indexes in a local store, and serves only them in offline mode
+	Cache *helm.ChartCacheConfig `toml:"cache,omitempty"`
+	// DisableReleaseIndex lists the releases from the API server on every call instead of from the watched release Secrets
+	DisableReleaseIndex bool `toml:"disable_release_index,omitempty"`
+	// Sops holds the keys decrypting the SOPS-encrypted values files (Optional, SOPS_AGE_KEY_FILE and SOPS_AGE_KEY are used if not provided)
+	Sops *sops.Config `toml:"sops,omitempty"`
+	// Vault resolves the vault:<path>#<key> placeholders of the values
+	Vault *vault.Config `toml:"vault,omitempty"`
+}
+
+var _ api.ExtendedConfig = (*Config)(nil)
+
+func (c *Config) Validate() error {
+	if c.Git != nil {
+		if err := c.Git.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.S3 != nil {
+		if err := c.S3.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.GCS != nil {
+		if err := c.GCS.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Cache != nil {
+		if err := c.Cache.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Sops != nil {
+		if err := c.Sops.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Vault != nil {
+		return c.Vault.Validate()
+	}
+	return nil
+}
+
+func helmToolsetParser(ctx context.Context, primitive toml.Primitive, md toml.MetaData) (api.ExtendedConfig, error) {
+	var cfg Config
+	if err := md.PrimitiveDecode(primitive, &cfg); err != nil {
+		return nil, err
+	}
+	configDir := config.ConfigDirPathFromContext(ctx)
+	if cfg.Git != nil {
+		cfg.Git.ResolvePaths(configDir)
+	}
+	if cfg.S3 != nil {
+		cfg.S3.ResolvePaths(configDir)
+	}
+	if cfg.GCS != nil {
+		cfg.GCS.ResolvePaths(configDir)
+	}
+	if cfg.Cache != nil {
+		cfg.Cache.ResolvePaths(configDir)
+	}
+	if cfg.Sops != nil {
+		cfg.Sops.ResolvePaths(configDir)
+	}
+	if cfg.Vault != nil {
+		cfg.Vault.ResolvePaths(configDir)
+	}
+	return &cfg, nil
+}
+
+// toolsetConfig returns the helm toolset configuration, or an empty one if not configured
+func toolsetConfig(params api.ToolHandlerParams) *Config {
+	if cfg, ok := params.GetToolsetConfig("helm"); ok {
+		if helmConfig, ok := cfg.(*Config); ok && helmConfig != nil {
+			return helmConfig
+		}
+	}
+	return &Config{}
+}
+
+func init() {
+	config.RegisterToolsetConfig("helm", helmToolsetParser)
+}
@@ -0,0 +1,101 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/containers/kubernetes-mcp-server/internal/test"
+	"github.com/containers/kubernetes-mcp-server/pkg/config"
+	"github.com/containers/kubernetes-mcp-server/pkg/helm"
+	"github.com/containers/kubernetes-mcp-server/pkg/objectstorage"
+)
+
+type ConfigSuite struct {
+	suite.Suite
+}
+
+func (s *ConfigSuite) TestConfigParser_Git() {
+	passwordFile := filepath.Join(s.T().TempDir(), "token")
+	s.Require().NoError(os.WriteFile(passwordFile, []byte("s3cr3t"), 0o600))
+	cfg := test.Must(config.ReadToml([]byte(`
+		[toolset_configs.helm.git]
+		[[toolset_configs.helm.git.credentials]]
+		url = "https://github.com/my-org/"
+		username = "bot"
+		password_file = "` + passwordFile + `"
+	`)))
+	helmCfg, ok := cfg.GetToolsetConfig("helm")
+	s.Require().True(ok, "helm config should be present")
+	s.Equal(&helm.GitConfig{Credentials: []helm.GitCredential{{Url: "https://github.com/my-org/", Username: "bot", PasswordFile: passwordFile}}},
+		helmCfg.(*Config).Git)
+}
+
+func (s *ConfigSuite) TestConfigParser_GitCredentialWithoutHttps() {
+	_, err := config.ReadToml([]byte(`
+		[[toolset_configs.helm.git.credentials]]
+		url = "http://github.com/my-org/"
+		password_file = "/dev/null"
+	`))
+	s.Require().Error(err, "credentials of insecure repositories should be rejected")
+	s.Contains(err.Error(), "git credential url must be a valid https URL")
+}
+
+func (s *ConfigSuite) TestConfigParser_ObjectStorage() {
+	cfg := test.Must(config.ReadToml([]byte(`
+		[toolset_configs.helm.s3]
+		region = "eu-west-1"
+		endpoint = "https://minio.example.com"
+		[toolset_configs.helm.gcs]
+	`)))
+	helmCfg, ok := cfg.GetToolsetConfig("helm")
+	s.Require().True(ok, "helm config should be present")
+	s.Equal(&objectstorage.S3Config{Region: "eu-west-1", Endpoint: "https://minio.example.com"}, helmCfg.(*Config).S3)
+	s.Equal(&objectstorage.GCSConfig{}, helmCfg.(*Config).GCS)
+}
+
+func (s *ConfigSuite) TestConfigParser_S3AccessKeyWithoutSecret() {
+	_, err := config.ReadToml([]byte(`
+		[toolset_configs.helm.s3]
+		access_key_id = "AKIAEXAMPLE"
+	`))
+	s.Require().Error(err, "access key without secret should be rejected")
+	s.Contains(err.Error(), "s3 access_key_id and secret_access_key_file must be provided together")
+}
+
+func (s *ConfigSuite) TestConfigParser_Cache() {
+	cfg := test.Must(config.ReadToml([]byte(`
+		[toolset_configs.helm.cache]
+		dir = "/var/cache/charts"
+		chart_ttl = "1h"
+		offline = true
+	`)))
+	helmCfg, ok := cfg.GetToolsetConfig("helm")
+	s.Require().True(ok, "helm config should be present")
+	s.Equal(&helm.ChartCacheConfig{Dir: "/var/cache/charts", ChartTTL: "1h", Offline: true}, helmCfg.(*Config).Cache)
+}
+
+func (s *ConfigSuite) TestConfigParser_CacheInvalidTTL() {
+	_, err := config.ReadToml([]byte(`
+		[toolset_configs.helm.cache]
+		index_ttl = "soon"
+	`))
+	s.Require().Error(err, "invalid durations should be rejected")
+	s.Contains(err.Error(), "cache index_ttl must be a positive duration (e.g. 24h): soon")
+}
+
+func (s *ConfigSuite) TestConfigParser_DisableReleaseIndex() {
+	cfg := test.Must(config.ReadToml([]byte(`
+		[toolset_configs.helm]
+		disable_release_index = true
+	`)))
+	helmCfg, ok := cfg.GetToolsetConfig("helm")
+	s.Require().True(ok, "helm config should be present")
+	s.True(helmCfg.(*Config).DisableReleaseIndex)
+}
+
+func TestConfig(t *testing.T) {
+	suite.Run(t, new(ConfigSuite))
+}
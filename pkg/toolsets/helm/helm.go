@@ -1,10 +1,18 @@
 package helm
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/helm"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+	"github.com/containers/kubernetes-mcp-server/pkg/objectstorage"
 	"github.com/google/jsonschema-go/jsonschema"
 	"k8s.io/utils/ptr"
 
@@ -14,23 +22,41 @@ import (
 func initHelm() []api.ServerTool {
 	return []api.ServerTool{
 		{Tool: api.Tool{
-			Name:        "helm_install",
-			Description: "Install (deploy) a Helm chart to create a release in the current or provided namespace",
+			Name: "helm_install",
+			Description: "Install (deploy) a Helm chart to create a release in the current or provided namespace. " +
+				"Charts in git repositories (git+https URLs) and object storage (s3:// and gs:// URLs) are fetched by the server, no need to download them first",
 			InputSchema: &jsonschema.Schema{
 				Type: "object",
 				Properties: map[string]*jsonschema.Schema{
 					"chart": {
-						Type:        "string",
-						Description: "Chart reference to install (for example: stable/grafana, oci://ghcr.io/nginxinc/charts/nginx-ingress)",
+						Type: "string",
+						Description: "Chart reference to install (for example: stable/grafana, oci://ghcr.io/nginxinc/charts/nginx-ingress, " +
+							"git+https://github.com/my-org/my-repo@charts/my-chart?ref=v1.0.0 for the chart directory of a git repository branch, tag, or commit, " +
+							"s3://my-bucket/charts/my-chart-1.0.0.tgz or gs://my-bucket/charts/my-chart-1.0.0.tgz for a chart archive, " +
+							"s3://my-bucket/charts/my-chart?version=1.0.0 for a chart of the repository indexed in s3://my-bucket/charts, the latest version if not provided)",
 					},
 					"values": {
-						Type:        "object",
-						Description: "Values to pass to the Helm chart (Optional)",
-						Properties:  make(map[string]*jsonschema.Schema),
+						Type: "object",
+						Description: "Values to pass to the Helm chart (Optional). " +
+							"Secret values can be referenced as vault:<path>#<key> placeholders (e.g. vault:secret/data/app#password), resolved by the server at install time",
+						Properties: make(map[string]*jsonschema.Schema),
+					},
+					"values_files": {
+						Type: "array",
+						Description: "Values files of the chart to merge in order before the values (Optional, for example: [\"values-production.yaml\", \"secrets.enc.yaml\"]). " +
+							"SOPS-encrypted files are decrypted in memory by the server with its configured keys, pass their name instead of their content. " +
+							"Files written to the workspace of the session are referenced as workspace:<path>",
+						Items: &jsonschema.Schema{Type: "string"},
 					},
 					"name": {
-						Type:        "string",
-						Description: "Name of the Helm release (Optional, random name if not provided)",
+						Type: "string",
+						Description: "Name of the Helm release (Optional, generated if not provided). " +
+							"Must be a lowercase DNS-1123 name (lowercase alphanumeric characters, '-' or '.') of at most 53 characters",
+					},
+					"generate_name": {
+						Type: "boolean",
+						Description: "Generate the name of the Helm release from the chart name (e.g. grafana-1712345678) " +
+							"(Optional, defaults to true if name is not provided, can't be true if name is provided)",
 					},
 					"namespace": {
 						Type:        "string",
@@ -45,7 +71,7 @@ func initHelm() []api.ServerTool {
 				IdempotentHint:  nil, // TODO: consider replacing implementation with equivalent to: helm upgrade --install
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: helmInstall},
+		}, Handler: helmInstall, LongRunning: ptr.To(true)},
 		{Tool: api.Tool{
 			Name:        "helm_list",
 			Description: "List all the Helm releases in the current or provided namespace (or in all namespaces if specified)",
@@ -60,6 +86,16 @@ func initHelm() []api.ServerTool {
 						Type:        "boolean",
 						Description: "If true, lists all Helm releases in all namespaces ignoring the namespace argument (Optional)",
 					},
+					"sort_by": {
+						Type:        "string",
+						Description: "Field to sort the Helm releases by (Optional, defaults to name)",
+						Enum:        []any{"name", "date"},
+					},
+					"sort_order": {
+						Type:        "string",
+						Description: "Order in which the Helm releases are sorted (Optional, defaults to asc)",
+						Enum:        []any{"asc", "desc"},
+					},
 				},
 			},
 			Annotations: api.ToolAnnotations{
@@ -69,6 +105,40 @@ func initHelm() []api.ServerTool {
 				OpenWorldHint:   ptr.To(true),
 			},
 		}, Handler: helmList},
+		{Tool: api.Tool{
+			Name:        "helm_history",
+			Description: "Get the history of the revisions of a Helm release in the current or provided namespace, with the status and description of each revision",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the Helm release to get the history of",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Helm release (Optional, current namespace if not provided)",
+					},
+					"sort_by": {
+						Type:        "string",
+						Description: "Field to sort the revisions by (Optional, defaults to revision)",
+						Enum:        []any{"revision"},
+					},
+					"sort_order": {
+						Type:        "string",
+						Description: "Order in which the revisions are sorted (Optional, defaults to asc)",
+						Enum:        []any{"asc", "desc"},
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Helm: History",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: helmHistory},
 		{Tool: api.Tool{
 			Name:        "helm_uninstall",
 			Description: "Uninstall a Helm release in the current or provided namespace",
@@ -92,7 +162,258 @@ func initHelm() []api.ServerTool {
 				IdempotentHint:  ptr.To(true),
 				OpenWorldHint:   ptr.To(true),
 			},
-		}, Handler: helmUninstall},
+		}, Handler: helmUninstall, LongRunning: ptr.To(true)},
+		{Tool: api.Tool{
+			Name: "helm_rollback",
+			Description: "Roll back a Helm release in the current or provided namespace to a previous revision. " +
+				"The live resources are first compared with the last revision of the release, " +
+				"the rollback is refused if they were modified outside Helm (e.g. manual hotfixes) unless force is set",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the Helm release to roll back",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Helm release (Optional, current namespace if not provided)",
+					},
+					"revision": {
+						Type:        "integer",
+						Description: "Revision to roll back to (Optional, previous revision if not provided)",
+						Minimum:     ptr.To(float64(1)),
+					},
+					"check_drift": {
+						Type:        "boolean",
+						Description: "Compare the live resources with the last revision before rolling back (Optional, defaults to true)",
+					},
+					"force": {
+						Type:        "boolean",
+						Description: "Roll back even if resources were modified outside Helm, overwriting the changes (Optional, defaults to false)",
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Helm: Rollback",
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: helmRollback, LongRunning: ptr.To(true)},
+		{Tool: api.Tool{
+			Name: "helm_repair",
+			Description: "Repair a Helm release in the current or provided namespace stuck in pending-install, pending-upgrade, or pending-rollback " +
+				"(e.g. after a crashed or interrupted client), such releases block all further install, upgrade, and rollback operations. " +
+				"The pending revision is rolled forward (marked as deployed) if all its resources exist in the cluster, " +
+				"or back (marked as failed, and the release rolled back to its last deployed revision if any) otherwise. " +
+				"Revisions pending for less than 5 minutes are left to their client unless force is set",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the Helm release to repair",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Helm release (Optional, current namespace if not provided)",
+					},
+					"strategy": {
+						Type: "string",
+						Description: "How to repair the release: auto rolls forward if all the resources of the pending revision exist and back otherwise, " +
+							"forward marks the pending revision as deployed, back marks it as failed and rolls back to the last deployed revision (Optional, defaults to auto)",
+						Enum: []any{helm.RepairAuto, helm.RepairForward, helm.RepairBack},
+					},
+					"force": {
+						Type:        "boolean",
+						Description: "Repair the release even if its revision has been pending for less than 5 minutes (Optional, defaults to false)",
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Helm: Repair",
+				DestructiveHint: ptr.To(true),
+				IdempotentHint:  ptr.To(false),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: helmRepair, LongRunning: ptr.To(true)},
+		{Tool: api.Tool{
+			Name: "helm_adopt",
+			Description: "Adopt existing resources created outside Helm (e.g. with kubectl apply) into a Helm release by setting the Helm ownership label and annotations, " +
+				"so that a subsequent installation or upgrade of the release with a chart rendering them takes ownership instead of failing because they already exist. " +
+				"No resource is modified if any of them doesn't exist or is owned by another release",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the Helm release adopting the resources",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Helm release (Optional, current namespace if not provided)",
+					},
+					"resources": {
+						Type:        "array",
+						Description: "Resources to adopt into the release",
+						MinItems:    ptr.To(1),
+						Items: &jsonschema.Schema{
+							Type: "object",
+							Properties: map[string]*jsonschema.Schema{
+								"apiVersion": {
+									Type:        "string",
+									Description: "apiVersion of the resource (examples of valid apiVersion are: v1, apps/v1, networking.k8s.io/v1)",
+								},
+								"kind": {
+									Type:        "string",
+									Description: "kind of the resource (examples of valid kind are: ConfigMap, Deployment, Ingress)",
+								},
+								"name": {
+									Type:        "string",
+									Description: "Name of the resource",
+								},
+								"namespace": {
+									Type:        "string",
+									Description: "Namespace of the resource (Optional, namespace of the release if not provided, ignored for cluster-scoped resources)",
+								},
+							},
+							Required: []string{"apiVersion", "kind", "name"},
+						},
+					},
+				},
+				Required: []string{"name", "resources"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Helm: Adopt",
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: helmAdopt, Permissions: helmAdoptPermissions},
+		{Tool: api.Tool{
+			Name: "helm_values_preview",
+			Description: "Preview the values an upgrade of a Helm release in the current or provided namespace would compute " +
+				"(chart defaults + values of the deployed revision + proposed overrides), " +
+				"followed by their diff (unified diff) against the deployed values. Nothing is changed in the cluster",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the Helm release",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Helm release (Optional, current namespace if not provided)",
+					},
+					"values": {
+						Type: "object",
+						Description: "Proposed values overriding the values of the deployed revision (Optional). " +
+							"Set a value to null to revert it to the chart default (or to remove it if reset_values is true)",
+						Properties: make(map[string]*jsonschema.Schema),
+					},
+					"values_files": {
+						Type: "array",
+						Description: "Values files of the chart to merge in order before the values (Optional, for example: [\"values-production.yaml\"]). " +
+							"Files written to the workspace of the session are referenced as workspace:<path>",
+						Items: &jsonschema.Schema{Type: "string"},
+					},
+					"reset_values": {
+						Type: "boolean",
+						Description: "Discard the values of the deployed revision, computing the values from the chart defaults and the proposed values only " +
+							"(Optional, defaults to false, as helm upgrade --reset-values)",
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Helm: Values Preview",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: helmValuesPreview},
+		{Tool: api.Tool{
+			Name: "helm_chart_inventory",
+			Description: "List what installing a Helm chart would pull, for a supply-chain review before approving the installation: " +
+				"its subcharts (dependencies) with their versions and repositories, and the container images referenced by its rendered manifests (hooks included). " +
+				"The chart is rendered by the server without installing anything in the cluster",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"chart": {
+						Type:        "string",
+						Description: "Chart reference, with the same formats as helm_install (for example: stable/grafana, oci://ghcr.io/nginxinc/charts/nginx-ingress)",
+					},
+					"values": {
+						Type:        "object",
+						Description: "Values to render the Helm chart with (Optional), the enabled subcharts and the images usually depend on them",
+						Properties:  make(map[string]*jsonschema.Schema),
+					},
+					"values_files": {
+						Type: "array",
+						Description: "Values files of the chart to merge in order before the values (Optional, for example: [\"values-production.yaml\"]). " +
+							"Files written to the workspace of the session are referenced as workspace:<path>",
+						Items: &jsonschema.Schema{Type: "string"},
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace to render the Helm chart for (Optional, current namespace if not provided)",
+					},
+				},
+				Required: []string{"chart"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Helm: Chart Inventory",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: helmChartInventory},
+		{Tool: api.Tool{
+			Name: "helm_export",
+			Description: "Export a Helm release in the current or provided namespace as files to commit to a Git repository, to migrate the release to a GitOps pipeline: " +
+				"its user-supplied values (values.yaml, without the chart defaults and with the values resolved from Vault or decrypted from SOPS masked) and the reference of its chart (chart.yaml) in the values format, " +
+				"or a kustomize base (kustomization.yaml and the rendered resources without the Helm labels, Secrets and hooks left out) in the kustomize format. " +
+				"Nothing is changed in the cluster",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name": {
+						Type:        "string",
+						Description: "Name of the Helm release to export",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the Helm release (Optional, current namespace if not provided)",
+					},
+					"revision": {
+						Type:        "integer",
+						Description: "Revision of the Helm release to export (Optional, deployed revision if not provided)",
+						Minimum:     ptr.To(float64(1)),
+					},
+					"format": {
+						Type:        "string",
+						Description: "Format of the export, values for a values.yaml and a chart reference, kustomize for a kustomize base with the rendered resources (Optional, defaults to values)",
+						Enum:        []any{"values", "kustomize"},
+						Default:     api.ToRawMessage("values"),
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: api.ToolAnnotations{
+				Title:           "Helm: Export",
+				ReadOnlyHint:    ptr.To(true),
+				DestructiveHint: ptr.To(false),
+				IdempotentHint:  ptr.To(true),
+				OpenWorldHint:   ptr.To(true),
+			},
+		}, Handler: helmExport},
 	}
 }
 
@@ -106,15 +427,48 @@ func helmInstall(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	if v, ok := params.GetArguments()["values"].(map[string]interface{}); ok {
 		values = v
 	}
+	var valuesFiles []string
+	if v, ok := params.GetArguments()["values_files"].([]interface{}); ok {
+		for _, file := range v {
+			if file, ok := file.(string); ok {
+				valuesFiles = append(valuesFiles, file)
+			}
+		}
+	}
 	name := ""
 	if v, ok := params.GetArguments()["name"].(string); ok {
 		name = v
 	}
+	generateName := name == ""
+	if v, ok := params.GetArguments()["generate_name"].(bool); ok {
+		generateName = v
+	}
+	switch {
+	case generateName && name != "":
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument,
+			fmt.Errorf("failed to install helm chart, name and generate_name can't be provided together"))), nil
+	case !generateName && name == "":
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument,
+			fmt.Errorf("failed to install helm chart, missing argument name (required if generate_name is false)"))), nil
+	case name != "":
+		if err := helm.ValidateReleaseName(name); err != nil {
+			return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument,
+				fmt.Errorf("failed to install helm chart, %w", err))), nil
+		}
+	}
 	namespace := ""
 	if v, ok := params.GetArguments()["namespace"].(string); ok {
 		namespace = v
 	}
-	ret, err := helm.NewHelm(params).Install(params, chart, values, name, namespace)
+	cfg := toolsetConfig(params)
+	ret, err := helm.NewHelm(params).
+		WithGit(cfg.Git).
+		WithObjectStorage(objectstorage.NewClient(cfg.S3, cfg.GCS)).
+		WithChartCache(cfg.Cache).
+		WithReleaseIndex(!cfg.DisableReleaseIndex).
+		WithSops(cfg.Sops).
+		WithVault(cfg.Vault).
+		Install(params, chart, values, valuesFiles, name, namespace)
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "helm install")
 		return api.NewToolCallResult("", fmt.Errorf("failed to install helm chart '%s': %w", chart, err)), nil
@@ -131,7 +485,11 @@ func helmList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	if v, ok := params.GetArguments()["namespace"].(string); ok {
 		namespace = v
 	}
-	ret, err := helm.NewHelm(params).List(namespace, allNamespaces)
+	byDate := params.GetArguments()["sort_by"] == "date"
+	descending := params.GetArguments()["sort_order"] == "desc"
+	ret, err := helm.NewHelm(params).
+		WithReleaseIndex(!toolsetConfig(params).DisableReleaseIndex).
+		List(params, namespace, allNamespaces, byDate, descending)
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "helm list")
 		return api.NewToolCallResult("", fmt.Errorf("failed to list helm releases in namespace '%s': %w", namespace, err)), nil
@@ -139,6 +497,25 @@ func helmList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	return api.NewToolCallResult(ret, err), nil
 }
 
+func helmHistory(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	var name string
+	ok := false
+	if name, ok = params.GetArguments()["name"].(string); !ok {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get helm release history, missing argument name")), nil
+	}
+	namespace := ""
+	if v, ok := params.GetArguments()["namespace"].(string); ok {
+		namespace = v
+	}
+	descending := params.GetArguments()["sort_order"] == "desc"
+	ret, err := helm.NewHelm(params).History(params, name, namespace, descending)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "helm history")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get helm release history '%s': %w", name, err)), nil
+	}
+	return api.NewToolCallResult(ret, err), nil
+}
+
 func helmUninstall(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	var name string
 	ok := false
@@ -149,10 +526,252 @@ func helmUninstall(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	if v, ok := params.GetArguments()["namespace"].(string); ok {
 		namespace = v
 	}
-	ret, err := helm.NewHelm(params).Uninstall(name, namespace)
+	ret, err := helm.NewHelm(params).
+		WithReleaseIndex(!toolsetConfig(params).DisableReleaseIndex).
+		Uninstall(params, name, namespace)
 	if err != nil {
 		mcplog.HandleK8sError(params.Context, err, "helm uninstall")
 		return api.NewToolCallResult("", fmt.Errorf("failed to uninstall helm chart '%s': %w", name, err)), nil
 	}
 	return api.NewToolCallResult(ret, err), nil
 }
+
+func helmRollback(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	var name string
+	ok := false
+	if name, ok = params.GetArguments()["name"].(string); !ok {
+		return api.NewToolCallResult("", fmt.Errorf("failed to roll back helm release, missing argument name")), nil
+	}
+	namespace := ""
+	if v, ok := params.GetArguments()["namespace"].(string); ok {
+		namespace = v
+	}
+	revision := int64(0)
+	if v, ok := params.GetArguments()["revision"]; ok {
+		var err error
+		if revision, err = api.ParseInt64(v); err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to roll back helm release, invalid argument revision: %w", err)), nil
+		}
+	}
+	checkDrift := true
+	if v, ok := params.GetArguments()["check_drift"].(bool); ok {
+		checkDrift = v
+	}
+	force := false
+	if v, ok := params.GetArguments()["force"].(bool); ok {
+		force = v
+	}
+	ret, err := helm.NewHelm(params).
+		WithReleaseIndex(!toolsetConfig(params).DisableReleaseIndex).
+		Rollback(params, name, namespace, int(revision), checkDrift, force)
+	var driftErr *helm.DriftError
+	if errors.As(err, &driftErr) {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeConflict, err)), nil
+	} else if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "helm rollback")
+		return api.NewToolCallResult("", fmt.Errorf("failed to roll back helm release '%s': %w", name, err)), nil
+	}
+	return api.NewToolCallResult(ret, err), nil
+}
+
+func helmRepair(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, ok := params.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		return api.NewToolCallResult("", fmt.Errorf("failed to repair helm release, missing argument name")), nil
+	}
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	strategy, _ := params.GetArguments()["strategy"].(string)
+	if strategy == "" {
+		strategy = helm.RepairAuto
+	} else if !slices.Contains(helm.RepairStrategies, strategy) {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to repair helm release, invalid strategy %s, valid strategies are: %s",
+			strategy, strings.Join(helm.RepairStrategies, ", ")))), nil
+	}
+	force, _ := params.GetArguments()["force"].(bool)
+	ret, err := helm.NewHelm(params).
+		WithReleaseIndex(!toolsetConfig(params).DisableReleaseIndex).
+		Repair(params, name, namespace, strategy, force)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "helm repair")
+		return api.NewToolCallResult("", fmt.Errorf("failed to repair helm release '%s': %w", name, err)), nil
+	}
+	return api.NewToolCallResult(ret, nil), nil
+}
+
+// helmAdoptPermissions returns the permissions to get and patch each of the resources to adopt
+func helmAdoptPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	namespace, _ := params.GetArguments()["namespace"].(string)
+	resources, _ := params.GetArguments()["resources"].([]interface{})
+	var permissions []api.Permission
+	for _, r := range resources {
+		r, _ := r.(map[string]interface{})
+		apiVersion, _ := r["apiVersion"].(string)
+		kind, _ := r["kind"].(string)
+		resourceNamespace, _ := r["namespace"].(string)
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return nil, err
+		}
+		for _, verb := range []string{"get", "patch"} {
+			permission, err := kubernetes.NewCore(params).ResourcePermission(&schema.GroupVersionKind{Group: gv.Group, Version: gv.Version, Kind: kind},
+				cmp.Or(resourceNamespace, namespace), verb, "")
+			if err != nil {
+				return nil, err
+			}
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions, nil
+}
+
+func helmAdopt(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	var name string
+	ok := false
+	if name, ok = params.GetArguments()["name"].(string); !ok {
+		return api.NewToolCallResult("", fmt.Errorf("failed to adopt resources, missing argument name")), nil
+	}
+	if err := helm.ValidateReleaseName(name); err != nil {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument, fmt.Errorf("failed to adopt resources, %w", err))), nil
+	}
+	namespace := ""
+	if v, ok := params.GetArguments()["namespace"].(string); ok {
+		namespace = v
+	}
+	var resources []helm.AdoptedResource
+	if v, ok := params.GetArguments()["resources"].([]interface{}); ok {
+		for _, r := range v {
+			r, _ := r.(map[string]interface{})
+			resource := helm.AdoptedResource{}
+			resource.APIVersion, _ = r["apiVersion"].(string)
+			resource.Kind, _ = r["kind"].(string)
+			resource.Name, _ = r["name"].(string)
+			resource.Namespace, _ = r["namespace"].(string)
+			if resource.APIVersion == "" || resource.Kind == "" || resource.Name == "" {
+				return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument,
+					fmt.Errorf("failed to adopt resources, apiVersion, kind and name are required for each resource"))), nil
+			}
+			resources = append(resources, resource)
+		}
+	}
+	if len(resources) == 0 {
+		return api.NewToolCallResult("", fmt.Errorf("failed to adopt resources, missing argument resources")), nil
+	}
+	adopted, err := helm.Adopt(params, params.DynamicClient(), params.RESTMapper(), name, params.NamespaceOrDefault(namespace), resources)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "helm adopt")
+		return api.NewToolCallResult("", fmt.Errorf("failed to adopt resources into helm release '%s': %w", name, err)), nil
+	}
+	ret, err := params.Marshal(adopted)
+	if err != nil {
+		err = fmt.Errorf("failed to adopt resources into helm release '%s': %w", name, err)
+	}
+	return api.NewToolCallResult(ret, err), nil
+}
+
+func helmValuesPreview(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	var name string
+	ok := false
+	if name, ok = params.GetArguments()["name"].(string); !ok {
+		return api.NewToolCallResult("", fmt.Errorf("failed to preview helm values, missing argument name")), nil
+	}
+	namespace := ""
+	if v, ok := params.GetArguments()["namespace"].(string); ok {
+		namespace = v
+	}
+	values := map[string]interface{}{}
+	if v, ok := params.GetArguments()["values"].(map[string]interface{}); ok {
+		values = v
+	}
+	var valuesFiles []string
+	if v, ok := params.GetArguments()["values_files"].([]interface{}); ok {
+		for _, file := range v {
+			if file, ok := file.(string); ok {
+				valuesFiles = append(valuesFiles, file)
+			}
+		}
+	}
+	reset := false
+	if v, ok := params.GetArguments()["reset_values"].(bool); ok {
+		reset = v
+	}
+	ret, err := helm.NewHelm(params).
+		WithSops(toolsetConfig(params).Sops).
+		ValuesPreview(params, name, namespace, values, valuesFiles, reset)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "helm values preview")
+		return api.NewToolCallResult("", fmt.Errorf("failed to preview values of helm release '%s': %w", name, err)), nil
+	}
+	return api.NewToolCallResult(ret, err), nil
+}
+
+func helmChartInventory(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	var chart string
+	ok := false
+	if chart, ok = params.GetArguments()["chart"].(string); !ok {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list helm chart inventory, missing argument chart")), nil
+	}
+	values := map[string]interface{}{}
+	if v, ok := params.GetArguments()["values"].(map[string]interface{}); ok {
+		values = v
+	}
+	var valuesFiles []string
+	if v, ok := params.GetArguments()["values_files"].([]interface{}); ok {
+		for _, file := range v {
+			if file, ok := file.(string); ok {
+				valuesFiles = append(valuesFiles, file)
+			}
+		}
+	}
+	namespace := ""
+	if v, ok := params.GetArguments()["namespace"].(string); ok {
+		namespace = v
+	}
+	cfg := toolsetConfig(params)
+	inventory, err := helm.NewHelm(params).
+		WithGit(cfg.Git).
+		WithObjectStorage(objectstorage.NewClient(cfg.S3, cfg.GCS)).
+		WithChartCache(cfg.Cache).
+		WithSops(cfg.Sops).
+		Inventory(params, chart, values, valuesFiles, params.NamespaceOrDefault(namespace))
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list helm chart inventory of '%s': %w", chart, err)), nil
+	}
+	ret, err := params.Marshal(inventory)
+	if err != nil {
+		err = fmt.Errorf("failed to list helm chart inventory of '%s': %w", chart, err)
+	}
+	return api.NewToolCallResult(ret, err), nil
+}
+
+func helmExport(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	var name string
+	ok := false
+	if name, ok = params.GetArguments()["name"].(string); !ok {
+		return api.NewToolCallResult("", fmt.Errorf("failed to export helm release, missing argument name")), nil
+	}
+	namespace := ""
+	if v, ok := params.GetArguments()["namespace"].(string); ok {
+		namespace = v
+	}
+	revision := int64(0)
+	if v, ok := params.GetArguments()["revision"]; ok {
+		var err error
+		if revision, err = api.ParseInt64(v); err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to export helm release, invalid argument revision: %w", err)), nil
+		}
+	}
+	format := "values"
+	if v, ok := params.GetArguments()["format"].(string); ok && v != "" {
+		format = v
+	}
+	if !slices.Contains(helm.ExportFormats, format) {
+		return api.NewToolCallResult("", api.NewToolError(api.ErrorCodeInvalidArgument,
+			fmt.Errorf("failed to export helm release, unsupported format %s, expected one of %v", format, helm.ExportFormats))), nil
+	}
+	ret, err := helm.NewHelm(params).Export(params, name, namespace, int(revision), format)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "helm export")
+		return api.NewToolCallResult("", fmt.Errorf("failed to export helm release '%s': %w", name, err)), nil
+	}
+	return api.NewToolCallResult(ret, err), nil
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	kcppkg "github.com/containers/kubernetes-mcp-server/pkg/kcp"
-	"github.com/containers/kubernetes-mcp-server/pkg/output"
 )
 
 func initWorkspaceTools() []api.ServerTool {
@@ -93,7 +92,7 @@ func workspaceDescribe(params api.ToolHandlerParams) (*api.ToolCallResult, error
 	}
 
 	// Format workspace details as YAML
-	yamlData, err := output.MarshalYaml(workspaceObj)
+	yamlData, err := params.Marshal(workspaceObj)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("failed to marshal workspace: %w", err)), nil
 	}
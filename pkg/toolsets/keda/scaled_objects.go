@@ -0,0 +1,171 @@
+package keda
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/keda"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+)
+
+func initScaledObjects() []api.ServerTool {
+	kinds := make([]any, 0, len(keda.Kinds))
+	for _, kind := range keda.Kinds {
+		kinds = append(kinds, kind)
+	}
+	return []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name: "keda_scaled_objects_list",
+				Description: "List the KEDA ScaledObjects and ScaledJobs with their Ready and Active conditions, scale target, min and max replicas, " +
+					"current and desired replicas, and the health and current/target metric values of their triggers. " +
+					"Not ready and fallback resources are listed first",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"kind": {
+							Type:        "string",
+							Description: "Kind of the KEDA resources to list (Optional, all the supported kinds if not provided)",
+							Enum:        kinds,
+						},
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace to list the KEDA resources from (Optional, all namespaces if not provided)",
+						},
+					},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "KEDA: Scaled Objects List",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler: scaledObjectsList,
+		},
+		{
+			Tool: api.Tool{
+				Name: "keda_scaled_object_get",
+				Description: "Get the scaling status of a KEDA ScaledObject or ScaledJob: its conditions, replicas, triggers (type, metadata, health, " +
+					"and current/target metric values), and its recent scaling decisions (events of KEDA and of the HorizontalPodAutoscaler, newest first)",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"kind": {
+							Type:        "string",
+							Description: "Kind of the KEDA resource (Optional, default: ScaledObject)",
+							Enum:        kinds,
+							Default:     api.ToRawMessage(keda.ScaledObjectKind),
+						},
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace of the KEDA resource (Optional, current namespace if not provided)",
+						},
+						"name": {
+							Type:        "string",
+							Description: "Name of the KEDA resource",
+						},
+					},
+					Required: []string{"name"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "KEDA: Scaled Object Get",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler: scaledObjectGet,
+		},
+	}
+}
+
+func scaledObjectsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace := api.OptionalString(params, "namespace", "")
+	kinds := keda.Kinds
+	if kind := api.OptionalString(params, "kind", ""); kind != "" {
+		kinds = []string{kind}
+	}
+	resources := make([]keda.ScaledResource, 0)
+	installed := 0
+	for _, kind := range kinds {
+		gvr, err := resourceFor(params, kind)
+		if meta.IsNoMatchError(err) && len(kinds) > 1 {
+			continue
+		}
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to list KEDA resources: %w", err)), nil
+		}
+		installed++
+		list, err := keda.ListScaledResources(params.Context, params.DynamicClient(), gvr, namespace)
+		if err != nil {
+			mcplog.HandleK8sError(params.Context, err, "KEDA resource listing")
+			return api.NewToolCallResult("", fmt.Errorf("failed to list KEDA resources: %w", err)), nil
+		}
+		resources = append(resources, list...)
+	}
+	if installed == 0 {
+		return api.NewToolCallResult("", errors.New("failed to list KEDA resources: KEDA is not installed in the cluster")), nil
+	}
+	if len(resources) == 0 {
+		return api.NewToolCallResult("# No KEDA resources found", nil), nil
+	}
+	keda.SortScaledResources(resources)
+	notReady, active := 0, 0
+	for _, resource := range resources {
+		if resource.Ready != "True" {
+			notReady++
+		}
+		if resource.Active == "True" {
+			active++
+		}
+	}
+	ret, err := params.Marshal(resources)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list KEDA resources: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d KEDA resource(s) found, %d not ready, %d active (%s format):\n",
+		len(resources), notReady, active, params.FormatName()), ret, nil), nil
+}
+
+func scaledObjectGet(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, err := api.RequiredString(params, "name")
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get KEDA resource: %w", err)), nil
+	}
+	kind := api.OptionalString(params, "kind", keda.ScaledObjectKind)
+	gvr, err := resourceFor(params, kind)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get KEDA resource: %w", err)), nil
+	}
+	namespace := params.NamespaceOrDefault(api.OptionalString(params, "namespace", ""))
+	resource, err := keda.GetScaledResource(params.Context, params.DynamicClient(), gvr, namespace, name)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "KEDA resource access")
+		return api.NewToolCallResult("", fmt.Errorf("failed to get KEDA resource: %w", err)), nil
+	}
+	ret, err := params.Marshal(resource)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get KEDA resource: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %s %s/%s with %d recent scaling event(s) (%s format):\n",
+		kind, namespace, name, len(resource.ScalingEvents), params.FormatName()), ret, nil), nil
+}
+
+// resourceFor returns the GroupVersionResource of the KEDA kind, or a NoMatch error if KEDA is not installed
+func resourceFor(params api.ToolHandlerParams, kind string) (schema.GroupVersionResource, error) {
+	gvr, err := keda.ResourceFor(kind)
+	if err != nil {
+		return gvr, err
+	}
+	if _, err = params.RESTMapper().RESTMapping(schema.GroupKind{Group: gvr.Group, Kind: kind}, gvr.Version); err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return gvr, nil
+}
@@ -4,6 +4,7 @@ import (
 	"slices"
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubevirt"
 	"github.com/containers/kubernetes-mcp-server/pkg/toolsets"
 	vm_create "github.com/containers/kubernetes-mcp-server/pkg/toolsets/kubevirt/vm/create"
 	vm_lifecycle "github.com/containers/kubernetes-mcp-server/pkg/toolsets/kubevirt/vm/lifecycle"
@@ -22,9 +23,12 @@ func (t *Toolset) GetDescription() string {
 }
 
 func (t *Toolset) GetTools(_ api.Openshift) []api.ServerTool {
-	return slices.Concat(
-		vm_create.Tools(),
-		vm_lifecycle.Tools(),
+	return api.WithAPIGroups(
+		slices.Concat(
+			vm_create.Tools(),
+			vm_lifecycle.Tools(),
+		),
+		kubevirt.VirtualMachineGVR.Group,
 	)
 }
 
@@ -9,7 +9,6 @@ import (
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubevirt"
-	"github.com/containers/kubernetes-mcp-server/pkg/output"
 	"github.com/google/jsonschema-go/jsonschema"
 	"k8s.io/utils/ptr"
 )
@@ -177,12 +176,12 @@ func create(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 
 	// Format the output
-	marshalledYaml, err := output.MarshalYaml(resources)
+	marshalledYaml, err := params.Marshal(resources)
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("failed to marshal created VirtualMachine: %w", err)), nil
 	}
 
-	return api.NewToolCallResult("# VirtualMachine created successfully\n"+marshalledYaml, nil), nil
+	return api.NewStructuredToolCallResult("# VirtualMachine created successfully\n", marshalledYaml, nil), nil
 }
 
 // createParameters holds parsed input parameters for VM creation
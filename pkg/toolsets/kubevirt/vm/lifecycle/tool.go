@@ -5,7 +5,6 @@ import (
 
 	"github.com/containers/kubernetes-mcp-server/pkg/api"
 	"github.com/containers/kubernetes-mcp-server/pkg/kubevirt"
-	"github.com/containers/kubernetes-mcp-server/pkg/output"
 	"github.com/google/jsonschema-go/jsonschema"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/ptr"
@@ -117,10 +116,10 @@ func lifecycle(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
 	}
 
 	// Format the output
-	marshalledYaml, err := output.MarshalYaml([]*unstructured.Unstructured{vm})
+	marshalledYaml, err := params.Marshal([]*unstructured.Unstructured{vm})
 	if err != nil {
 		return api.NewToolCallResult("", fmt.Errorf("failed to marshal VirtualMachine: %w", err)), nil
 	}
 
-	return api.NewToolCallResult(message+marshalledYaml, nil), nil
+	return api.NewStructuredToolCallResult(message, marshalledYaml, nil), nil
 }
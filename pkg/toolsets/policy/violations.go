@@ -0,0 +1,111 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+	"github.com/containers/kubernetes-mcp-server/pkg/policy"
+)
+
+func initViolations() []api.ServerTool {
+	return []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name: "policy_violations_list",
+				Description: "List the admission policy violations found by the Gatekeeper audit (constraints status) and reported by Kyverno (PolicyReports and ClusterPolicyReports), " +
+					"aggregated by policy and namespace with their messages and violating resources. " +
+					"Use it to explain why a request was rejected by an admission policy and what to fix: " +
+					"the deny (Gatekeeper) and fail (Kyverno) violations are listed first, the messages usually describe the expected configuration",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace to list the policy violations from (Optional, all namespaces and cluster-scoped resources if not provided)",
+						},
+						"policy": {
+							Type:        "string",
+							Description: "Name of the Kyverno policy or of the Gatekeeper constraint to list the violations of (Optional, all the policies if not provided)",
+						},
+					},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Policy: Violations List",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler: violationsList,
+		},
+	}
+}
+
+func violationsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace := api.OptionalString(params, "namespace", "")
+	policyName := api.OptionalString(params, "policy", "")
+	constraints, err := policy.ConstraintResources(params.DiscoveryClient())
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list policy violations: %w", err)), nil
+	}
+	violations := make([]policy.Violation, 0)
+	for _, gvr := range constraints {
+		list, err := policy.ConstraintViolations(params.Context, params.DynamicClient(), gvr, namespace)
+		if err != nil {
+			mcplog.HandleK8sError(params.Context, err, "Gatekeeper constraint listing")
+			return api.NewToolCallResult("", fmt.Errorf("failed to list policy violations: %w", err)), nil
+		}
+		violations = append(violations, list...)
+	}
+	reports := make([]schema.GroupVersionResource, 0, len(policy.ReportKinds))
+	for _, kind := range policy.ReportKinds {
+		// The ClusterPolicyReports are about cluster-scoped resources only
+		if namespace != "" && kind == policy.ClusterPolicyReportGK.Kind {
+			continue
+		}
+		gvr, err := policy.ResourceFor(params.RESTMapper(), kind)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to list policy violations: %w", err)), nil
+		}
+		reports = append(reports, gvr)
+	}
+	for _, gvr := range reports {
+		list, err := policy.ReportViolations(params.Context, params.DynamicClient(), gvr, namespace)
+		if err != nil {
+			mcplog.HandleK8sError(params.Context, err, "policy report listing")
+			return api.NewToolCallResult("", fmt.Errorf("failed to list policy violations: %w", err)), nil
+		}
+		violations = append(violations, list...)
+	}
+	if len(constraints) == 0 && len(reports) == 0 {
+		return api.NewToolCallResult("", errors.New("failed to list policy violations: neither Gatekeeper constraints nor Policy Reports (Kyverno) are installed in the cluster")), nil
+	}
+	if policyName != "" {
+		violations = slices.DeleteFunc(violations, func(violation policy.Violation) bool { return !violation.IsOf(policyName) })
+	}
+	summaries := policy.Aggregate(violations)
+	if len(summaries) == 0 {
+		return api.NewToolCallResult("# No policy violations found", nil), nil
+	}
+	total := 0
+	for _, summary := range summaries {
+		total += summary.Violations
+	}
+	ret, err := params.Marshal(summaries)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list policy violations: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d policy violation(s) found in %d policy and namespace group(s) (%s format):\n",
+		total, len(summaries), params.FormatName()), ret, nil), nil
+}
@@ -0,0 +1,309 @@
+package tekton
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+
+	"github.com/containers/kubernetes-mcp-server/pkg/api"
+	"github.com/containers/kubernetes-mcp-server/pkg/kubernetes"
+	"github.com/containers/kubernetes-mcp-server/pkg/mcplog"
+	"github.com/containers/kubernetes-mcp-server/pkg/tekton"
+)
+
+func initRuns() []api.ServerTool {
+	kinds := make([]any, 0, len(tekton.Kinds))
+	for _, kind := range tekton.Kinds {
+		kinds = append(kinds, kind)
+	}
+	return []api.ServerTool{
+		{
+			Tool: api.Tool{
+				Name: "tekton_runs_list",
+				Description: "List the Tekton PipelineRuns and TaskRuns, the most recent first, with their status (Succeeded, Failed, Cancelled, Running, or Pending), " +
+					"the reason and message of the failures, their duration, and the failed steps of the TaskRuns",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"kind": {
+							Type:        "string",
+							Description: "Kind of the Tekton runs to list (Optional, all the supported kinds if not provided)",
+							Enum:        kinds,
+						},
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace to list the Tekton runs from (Optional, all namespaces if not provided)",
+						},
+						"pipeline": {
+							Type:        "string",
+							Description: "Name of the Pipeline to list the runs of (Optional)",
+						},
+						"pipelinerun": {
+							Type:        "string",
+							Description: "Name of the PipelineRun to list the TaskRuns of (Optional, only TaskRuns are listed if provided)",
+						},
+					},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Tekton: Runs List",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler: runsList,
+		},
+		{
+			Tool: api.Tool{
+				Name: "tekton_run_logs",
+				Description: "Get the logs of the steps of a Tekton TaskRun, with the exit code of each terminated step. " +
+					"For a PipelineRun, the logs of its failed TaskRuns are returned (all its TaskRuns if none failed)",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"kind": {
+							Type:        "string",
+							Description: "Kind of the Tekton run (Optional, default: TaskRun)",
+							Enum:        kinds,
+							Default:     api.ToRawMessage(tekton.TaskRunGK.Kind),
+						},
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace of the Tekton run (Optional, current namespace if not provided)",
+						},
+						"name": {
+							Type:        "string",
+							Description: "Name of the Tekton run",
+						},
+						"step": {
+							Type:        "string",
+							Description: "Name of the step to get the logs of (Optional, all the steps if not provided)",
+						},
+						"tail": {
+							Type:        "integer",
+							Description: "Number of lines to retrieve from the end of the logs of each step (Optional, default: 100)",
+							Default:     api.ToRawMessage(kubernetes.DefaultTailLines),
+							Minimum:     ptr.To(float64(0)),
+						},
+					},
+					Required: []string{"name"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Tekton: Run Logs",
+					ReadOnlyHint:    ptr.To(true),
+					DestructiveHint: ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler: runLogs,
+		},
+		{
+			Tool: api.Tool{
+				Name: "tekton_pipelinerun_rerun",
+				Description: "Rerun a completed (succeeded, failed, or cancelled) Tekton PipelineRun by creating a new PipelineRun with the same spec, " +
+					"labels, and annotations. Returns the summary of the new PipelineRun",
+				InputSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"namespace": {
+							Type:        "string",
+							Description: "Namespace of the PipelineRun (Optional, current namespace if not provided)",
+						},
+						"name": {
+							Type:        "string",
+							Description: "Name of the PipelineRun to rerun",
+						},
+					},
+					Required: []string{"name"},
+				},
+				Annotations: api.ToolAnnotations{
+					Title:           "Tekton: PipelineRun Rerun",
+					ReadOnlyHint:    ptr.To(false),
+					DestructiveHint: ptr.To(true),
+					IdempotentHint:  ptr.To(false),
+					OpenWorldHint:   ptr.To(true),
+				},
+			},
+			Handler:     pipelineRunRerun,
+			Permissions: pipelineRunRerunPermissions,
+		},
+	}
+}
+
+func runsList(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	namespace := api.OptionalString(params, "namespace", "")
+	selector := labels.Set{}
+	if pipeline := api.OptionalString(params, "pipeline", ""); pipeline != "" {
+		selector[tekton.PipelineLabel] = pipeline
+	}
+	kinds := tekton.Kinds
+	if pipelineRun := api.OptionalString(params, "pipelinerun", ""); pipelineRun != "" {
+		selector[tekton.PipelineRunLabel] = pipelineRun
+		kinds = []string{tekton.TaskRunGK.Kind}
+	}
+	if kind := api.OptionalString(params, "kind", ""); kind != "" {
+		kinds = []string{kind}
+	}
+	runs := make([]tekton.Run, 0)
+	installed := 0
+	now := time.Now()
+	for _, kind := range kinds {
+		gvr, err := tekton.ResourceFor(params.RESTMapper(), kind)
+		if meta.IsNoMatchError(err) && len(kinds) > 1 {
+			continue
+		}
+		if err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to list Tekton runs: %w", err)), nil
+		}
+		installed++
+		list, err := tekton.ListRuns(params.Context, params.DynamicClient(), gvr, namespace, selector.String(), now)
+		if err != nil {
+			mcplog.HandleK8sError(params.Context, err, "Tekton run listing")
+			return api.NewToolCallResult("", fmt.Errorf("failed to list Tekton runs: %w", err)), nil
+		}
+		runs = append(runs, list...)
+	}
+	if installed == 0 {
+		return api.NewToolCallResult("", errors.New("failed to list Tekton runs: Tekton Pipelines is not installed in the cluster")), nil
+	}
+	if len(runs) == 0 {
+		return api.NewToolCallResult("# No Tekton runs found", nil), nil
+	}
+	failed, running := 0, 0
+	for _, run := range runs {
+		switch run.Status {
+		case tekton.StatusFailed:
+			failed++
+		case tekton.StatusRunning:
+			running++
+		}
+	}
+	ret, err := params.Marshal(runs)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to list Tekton runs: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# %d Tekton run(s) found, %d failed, %d running (%s format):\n",
+		len(runs), failed, running, params.FormatName()), ret, nil), nil
+}
+
+func runLogs(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, err := api.RequiredString(params, "name")
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get Tekton run logs: %w", err)), nil
+	}
+	kind := api.OptionalString(params, "kind", tekton.TaskRunGK.Kind)
+	namespace := params.NamespaceOrDefault(api.OptionalString(params, "namespace", ""))
+	step := api.OptionalString(params, "step", "")
+	tail := kubernetes.DefaultTailLines
+	if v, ok := params.GetArguments()["tail"]; ok && v != nil {
+		if tail, err = api.ParseInt64(v); err != nil {
+			return api.NewToolCallResult("", fmt.Errorf("failed to parse tail parameter: %w", err)), nil
+		}
+	}
+	gvr, err := tekton.ResourceFor(params.RESTMapper(), tekton.TaskRunGK.Kind)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to get Tekton run logs: %w", err)), nil
+	}
+	var taskRuns []unstructured.Unstructured
+	switch kind {
+	case tekton.TaskRunGK.Kind:
+		taskRun, err := params.DynamicClient().Resource(gvr).Namespace(namespace).Get(params.Context, name, metav1.GetOptions{})
+		if err != nil {
+			mcplog.HandleK8sError(params.Context, err, "Tekton run access")
+			return api.NewToolCallResult("", fmt.Errorf("failed to get Tekton run logs: %w", err)), nil
+		}
+		taskRuns = []unstructured.Unstructured{*taskRun}
+	case tekton.PipelineRunGK.Kind:
+		taskRuns, err = tekton.TaskRuns(params.Context, params.DynamicClient(), gvr, namespace, name)
+		if err != nil {
+			mcplog.HandleK8sError(params.Context, err, "Tekton run access")
+			return api.NewToolCallResult("", fmt.Errorf("failed to get Tekton run logs: %w", err)), nil
+		}
+		if len(taskRuns) == 0 {
+			return api.NewToolCallResult(fmt.Sprintf("The PipelineRun %s in namespace %s has no TaskRuns", name, namespace), nil), nil
+		}
+		failed := slices.DeleteFunc(slices.Clone(taskRuns), func(taskRun unstructured.Unstructured) bool {
+			return tekton.Summarize(&taskRun, time.Now()).Status != tekton.StatusFailed
+		})
+		if len(failed) > 0 {
+			taskRuns = failed
+		}
+	default:
+		return api.NewToolCallResult("", fmt.Errorf("failed to get Tekton run logs: unsupported Tekton kind %s, supported kinds are %v", kind, tekton.Kinds)), nil
+	}
+	ret := strings.Builder{}
+	for _, taskRun := range taskRuns {
+		logs, err := tekton.StepLogs(params.Context, params.CoreV1(), &taskRun, step, tail)
+		if err != nil && len(taskRuns) == 1 {
+			return api.NewToolCallResult("", fmt.Errorf("failed to get Tekton run logs: %w", err)), nil
+		}
+		if err != nil {
+			// The TaskRuns of a PipelineRun that are not started yet or that don't have the step are reported and skipped
+			ret.WriteString(fmt.Sprintf("# %v\n", err))
+			continue
+		}
+		for _, log := range logs {
+			ret.WriteString(fmt.Sprintf("# TaskRun %s step %s%s:\n%s", log.TaskRun, log.Step, termination(log), log.Log))
+			if !strings.HasSuffix(log.Log, "\n") {
+				ret.WriteString("\n")
+			}
+		}
+	}
+	return api.NewToolCallResult(ret.String(), nil), nil
+}
+
+// pipelineRunRerunPermissions returns the permissions to get the PipelineRun and to create its copy
+func pipelineRunRerunPermissions(params api.ToolHandlerParams) ([]api.Permission, error) {
+	gvr, err := tekton.ResourceFor(params.RESTMapper(), tekton.PipelineRunGK.Kind)
+	if err != nil {
+		return nil, err
+	}
+	namespace := params.NamespaceOrDefault(api.OptionalString(params, "namespace", ""))
+	return []api.Permission{
+		{Verb: "get", Group: gvr.Group, Resource: gvr.Resource, Namespace: namespace},
+		{Verb: "create", Group: gvr.Group, Resource: gvr.Resource, Namespace: namespace},
+	}, nil
+}
+
+func pipelineRunRerun(params api.ToolHandlerParams) (*api.ToolCallResult, error) {
+	name, err := api.RequiredString(params, "name")
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to rerun PipelineRun: %w", err)), nil
+	}
+	namespace := params.NamespaceOrDefault(api.OptionalString(params, "namespace", ""))
+	gvr, err := tekton.ResourceFor(params.RESTMapper(), tekton.PipelineRunGK.Kind)
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to rerun PipelineRun: %w", err)), nil
+	}
+	rerun, err := tekton.Rerun(params.Context, params.DynamicClient(), gvr, namespace, name)
+	if err != nil {
+		mcplog.HandleK8sError(params.Context, err, "Tekton PipelineRun rerun")
+		return api.NewToolCallResult("", fmt.Errorf("failed to rerun PipelineRun: %w", err)), nil
+	}
+	ret, err := params.Marshal(tekton.Summarize(rerun, time.Now()))
+	if err != nil {
+		return api.NewToolCallResult("", fmt.Errorf("failed to rerun PipelineRun: %w", err)), nil
+	}
+	return api.NewStructuredToolCallResult(fmt.Sprintf("# PipelineRun %s/%s rerun as %s (%s format):\n",
+		namespace, name, rerun.GetName(), params.FormatName()), ret, nil), nil
+}
+
+// termination returns the exit code and reason of a terminated step, formatted for the log header
+func termination(log tekton.StepLog) string {
+	if log.ExitCode == nil {
+		return ""
+	}
+	if log.Reason == "" {
+		return fmt.Sprintf(" (exit code %d)", *log.ExitCode)
+	}
+	return fmt.Sprintf(" (exit code %d, %s)", *log.ExitCode, log.Reason)
+}
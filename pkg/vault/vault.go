@@ -0,0 +1,273 @@
+package vault
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// requestTimeout is the maximum duration of a Vault request
+	requestTimeout = 30 * time.Second
+	// defaultKubernetesTokenFile is the token of the service account of the server Pod
+	defaultKubernetesTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// placeholder matches the vault:<path>#<key> references to the key of a Vault secret
+var placeholder = regexp.MustCompile(`^vault:([^#]+)#(.+)$`)
+
+// Config holds the connection settings of the Vault the placeholders are resolved against.
+// The server authenticates with a token (TokenFile) or with its Kubernetes service account (KubernetesRole).
+type Config struct {
+	// Address of Vault (e.g. https://vault.example.com:8200)
+	Address string `toml:"address"`
+	// Namespace of Vault Enterprise (Optional)
+	Namespace            string `toml:"namespace,omitempty"`
+	CertificateAuthority string `toml:"certificate_authority,omitempty"`
+	Insecure             bool   `toml:"insecure,omitempty"`
+	// TokenFile is the file with the Vault token
+	TokenFile string `toml:"token_file,omitempty"`
+	// KubernetesRole is the role of the Kubernetes auth method the service account token is exchanged for
+	KubernetesRole string `toml:"kubernetes_role,omitempty"`
+	// KubernetesMount is the path of the Kubernetes auth method (Optional, kubernetes if not provided)
+	KubernetesMount string `toml:"kubernetes_mount,omitempty"`
+	// KubernetesTokenFile is the service account token (Optional, the token of the server Pod if not provided)
+	KubernetesTokenFile string `toml:"kubernetes_token_file,omitempty"`
+}
+
+func (c *Config) Validate() error {
+	if c == nil {
+		return errors.New("vault config is nil")
+	}
+	if u, err := url.Parse(c.Address); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("vault address must be a valid http(s) URL")
+	}
+	if (c.TokenFile == "") == (c.KubernetesRole == "") {
+		return errors.New("vault requires either token_file or kubernetes_role")
+	}
+	for name, file := range map[string]string{"certificate_authority": c.CertificateAuthority, "token_file": c.TokenFile, "kubernetes_token_file": c.KubernetesTokenFile} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("vault %s must be a valid file path: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ResolvePaths resolves the relative file paths against the configuration directory
+func (c *Config) ResolvePaths(configDir string) {
+	if configDir == "" {
+		return
+	}
+	for _, file := range []*string{&c.CertificateAuthority, &c.TokenFile, &c.KubernetesTokenFile} {
+		if *file != "" && !filepath.IsAbs(*file) {
+			*file = filepath.Join(configDir, *file)
+		}
+	}
+}
+
+// HasPlaceholders returns true if any of the string values is a vault:<path>#<key> placeholder
+func HasPlaceholders(values any) bool {
+	switch values := values.(type) {
+	case map[string]any:
+		for _, value := range values {
+			if HasPlaceholders(value) {
+				return true
+			}
+		}
+	case []any:
+		for _, value := range values {
+			if HasPlaceholders(value) {
+				return true
+			}
+		}
+	case string:
+		return placeholder.MatchString(values)
+	}
+	return false
+}
+
+// Resolve returns a copy of the values with the vault:<path>#<key> placeholders replaced by the keys of the secrets,
+// read once per path from the KV (version 1 or 2) secrets engine
+func (c *Config) Resolve(ctx context.Context, values map[string]any) (map[string]any, error) {
+	if c == nil {
+		return nil, errors.New("the values have Vault placeholders but Vault is not configured in the server")
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	client, err := c.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	r := &resolver{config: c, client: client, secrets: map[string]map[string]any{}}
+	if r.token, err = c.login(ctx, client); err != nil {
+		return nil, fmt.Errorf("failed to authenticate to Vault: %w", err)
+	}
+	resolved, err := r.resolve(ctx, values)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(map[string]any), nil
+}
+
+type resolver struct {
+	config  *Config
+	client  *http.Client
+	token   string
+	secrets map[string]map[string]any
+}
+
+func (r *resolver) resolve(ctx context.Context, value any) (any, error) {
+	switch value := value.(type) {
+	case map[string]any:
+		resolved := make(map[string]any, len(value))
+		for key, item := range value {
+			var err error
+			if resolved[key], err = r.resolve(ctx, item); err != nil {
+				return nil, err
+			}
+		}
+		return resolved, nil
+	case []any:
+		resolved := make([]any, len(value))
+		for i, item := range value {
+			var err error
+			if resolved[i], err = r.resolve(ctx, item); err != nil {
+				return nil, err
+			}
+		}
+		return resolved, nil
+	case string:
+		match := placeholder.FindStringSubmatch(value)
+		if match == nil {
+			return value, nil
+		}
+		path, key := strings.Trim(match[1], "/"), match[2]
+		secret, ok := r.secrets[path]
+		if !ok {
+			var err error
+			if secret, err = r.read(ctx, path); err != nil {
+				return nil, fmt.Errorf("failed to read Vault secret %s: %w", path, err)
+			}
+			r.secrets[path] = secret
+		}
+		resolved, found := secret[key]
+		if !found {
+			// the secret values are not part of the error, only the names of the keys
+			return nil, fmt.Errorf("key %s not found in Vault secret %s", key, path)
+		}
+		return resolved, nil
+	}
+	return value, nil
+}
+
+// read returns the data of the secret, unwrapping the data and metadata of the KV version 2 secrets
+func (r *resolver) read(ctx context.Context, path string) (map[string]any, error) {
+	var response struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := r.config.do(ctx, r.client, http.MethodGet, path, r.token, nil, &response); err != nil {
+		return nil, err
+	}
+	if data, ok := response.Data["data"].(map[string]any); ok {
+		if _, ok := response.Data["metadata"]; ok {
+			return data, nil
+		}
+	}
+	return response.Data, nil
+}
+
+// login returns the configured token, or the token the Kubernetes service account token is exchanged for
+func (c *Config) login(ctx context.Context, client *http.Client) (string, error) {
+	if c.TokenFile != "" {
+		token, err := os.ReadFile(c.TokenFile)
+		return strings.TrimSpace(string(token)), err
+	}
+	jwt, err := os.ReadFile(cmp.Or(c.KubernetesTokenFile, defaultKubernetesTokenFile))
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	body := map[string]string{"role": c.KubernetesRole, "jwt": strings.TrimSpace(string(jwt))}
+	if err = c.do(ctx, client, http.MethodPost, "auth/"+strings.Trim(cmp.Or(c.KubernetesMount, "kubernetes"), "/")+"/login", "", body, &response); err != nil {
+		return "", err
+	}
+	return response.Auth.ClientToken, nil
+}
+
+func (c *Config) do(ctx context.Context, client *http.Client, method, path, token string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.Address, "/")+"/v1/"+path, reader)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.Namespace)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(data, &vaultErr)
+		if len(vaultErr.Errors) == 0 {
+			return fmt.Errorf("%s", http.StatusText(resp.StatusCode))
+		}
+		return fmt.Errorf("%s: %s", http.StatusText(resp.StatusCode), strings.Join(vaultErr.Errors, ", "))
+	}
+	return json.Unmarshal(data, result)
+}
+
+func (c *Config) httpClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.Insecure}
+	if c.CertificateAuthority != "" {
+		ca, err := os.ReadFile(c.CertificateAuthority)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("invalid vault certificate_authority %s", c.CertificateAuthority)
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
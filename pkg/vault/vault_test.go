@@ -0,0 +1,93 @@
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type VaultTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+	login  map[string]string
+}
+
+func (s *VaultTestSuite) SetupTest() {
+	s.login = nil
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/v1/auth/k8s/login" {
+			_ = json.NewDecoder(req.Body).Decode(&s.login)
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"hvs.kubernetes"}}`))
+			return
+		}
+		if token := req.Header.Get("X-Vault-Token"); token != "hvs.static" && token != "hvs.kubernetes" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch req.URL.Path {
+		case "/v1/secret/data/app":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"s3cr3t","port":5432},"metadata":{"version":3}}}`))
+		case "/v1/kv/app":
+			_, _ = w.Write([]byte(`{"data":{"apiKey":"k3y"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	s.T().Cleanup(s.server.Close)
+}
+
+func (s *VaultTestSuite) file(content string) string {
+	file := filepath.Join(s.T().TempDir(), "token")
+	s.Require().NoError(os.WriteFile(file, []byte(content), 0o600))
+	return file
+}
+
+func (s *VaultTestSuite) TestHasPlaceholders() {
+	s.True(HasPlaceholders(map[string]any{"auth": map[string]any{"passwords": []any{"vault:secret/data/app#password"}}}))
+	s.False(HasPlaceholders(map[string]any{"image": "vault:1.18", "url": "https://vault.example.com#anchor"}))
+}
+
+func (s *VaultTestSuite) TestResolve() {
+	values := map[string]any{
+		"database": map[string]any{"password": "vault:secret/data/app#password", "port": "vault:/secret/data/app#port", "host": "db"},
+		"apiKeys":  []any{"vault:kv/app#apiKey"},
+	}
+	s.Run("resolves the KV version 1 and 2 secrets with a token", func() {
+		resolved, err := (&Config{Address: s.server.URL, TokenFile: s.file("hvs.static\n")}).Resolve(s.T().Context(), values)
+		s.Require().NoError(err)
+		s.Equal(map[string]any{
+			"database": map[string]any{"password": "s3cr3t", "port": float64(5432), "host": "db"},
+			"apiKeys":  []any{"k3y"},
+		}, resolved)
+		s.Equal("vault:secret/data/app#password", values["database"].(map[string]any)["password"], "the values should not be modified")
+	})
+	s.Run("logs in with the Kubernetes service account", func() {
+		cfg := &Config{Address: s.server.URL, KubernetesRole: "mcp", KubernetesMount: "k8s", KubernetesTokenFile: s.file("eyJhbGciOi")}
+		_, err := cfg.Resolve(s.T().Context(), values)
+		s.Require().NoError(err)
+		s.Equal(map[string]string{"role": "mcp", "jwt": "eyJhbGciOi"}, s.login)
+	})
+	s.Run("fails on the missing keys without disclosing the secret", func() {
+		_, err := (&Config{Address: s.server.URL, TokenFile: s.file("hvs.static")}).Resolve(s.T().Context(), map[string]any{"user": "vault:secret/data/app#user"})
+		s.EqualError(err, "key user not found in Vault secret secret/data/app")
+	})
+	s.Run("fails on the denied secrets", func() {
+		_, err := (&Config{Address: s.server.URL, TokenFile: s.file("hvs.other")}).Resolve(s.T().Context(), values)
+		s.ErrorContains(err, "Forbidden: permission denied")
+	})
+	s.Run("fails when Vault is not configured", func() {
+		_, err := (*Config)(nil).Resolve(s.T().Context(), values)
+		s.EqualError(err, "the values have Vault placeholders but Vault is not configured in the server")
+	})
+}
+
+func TestVault(t *testing.T) {
+	suite.Run(t, new(VaultTestSuite))
+}
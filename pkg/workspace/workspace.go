@@ -0,0 +1,270 @@
+// Package workspace keeps the files written by the MCP sessions (e.g. the values files and the manifests an agent builds
+// iteratively) in a temporary directory per session, removed when the session ends or stays idle.
+// The other tools reference the files of the workspace of their session as workspace:<path>.
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prefix is the prefix of the references to the files of the workspace (e.g. workspace:values/production.yaml)
+const Prefix = "workspace:"
+
+// File is a file of the workspace of a session
+type File struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+// Workspace manages the directories of the sessions, created on their first write under a temporary directory
+type Workspace struct {
+	parent string
+	mu     sync.Mutex
+	root   string
+	// sessions are the directories of the sessions by session ID
+	sessions map[string]*sessionWorkspace
+	// stopSweeper stops the removal of the directories of the idle sessions, if started
+	stopSweeper context.CancelFunc
+}
+
+// sessionWorkspace is the directory of a session and the last time it was used
+type sessionWorkspace struct {
+	dir  string
+	used time.Time
+}
+
+// New returns a workspace creating its temporary directory in parent (the default temporary directory if empty)
+func New(parent string) *Workspace {
+	return &Workspace{parent: parent, sessions: make(map[string]*sessionWorkspace)}
+}
+
+// Write writes the file of the session, replacing it if it exists, unless the files of the session would exceed maxBytes
+func (w *Workspace) Write(session, path string, content []byte, maxBytes int64) (*File, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	dir, err := w.sessionDir(session, true)
+	if err != nil {
+		return nil, err
+	}
+	name, err := resolve(dir, path)
+	if err != nil {
+		return nil, err
+	}
+	used, err := usage(dir)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(name); err == nil {
+		if info.IsDir() {
+			return nil, fmt.Errorf("%s is a directory", path)
+		}
+		used -= info.Size()
+	}
+	if used+int64(len(content)) > maxBytes {
+		return nil, fmt.Errorf("writing %s (%d bytes) would exceed the workspace size limit (%d of %d bytes used)", path, len(content), used, maxBytes)
+	}
+	if err = os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(name, content, 0o600); err != nil {
+		return nil, err
+	}
+	return &File{Path: filepath.ToSlash(filepath.Clean(path)), Size: int64(len(content)), Modified: time.Now()}, nil
+}
+
+// Read returns the content of the file of the session
+func (w *Workspace) Read(session, path string) ([]byte, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	dir, _ := w.sessionDir(session, false)
+	if dir == "" {
+		return nil, fmt.Errorf("file %s not found in the workspace", path)
+	}
+	name, err := resolve(dir, path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("file %s not found in the workspace", path)
+	}
+	return content, err
+}
+
+// List returns the files of the session sorted by path
+func (w *Workspace) List(session string) ([]File, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	dir, _ := w.sessionDir(session, false)
+	files := make([]File, 0)
+	if dir == "" {
+		return files, nil
+	}
+	err := filepath.WalkDir(dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, name)
+		files = append(files, File{Path: filepath.ToSlash(rel), Size: info.Size(), Modified: info.ModTime()})
+		return nil
+	})
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, err
+}
+
+// EndSession removes the files of the session
+func (w *Workspace) EndSession(session string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if dir, ok := w.sessions[session]; ok {
+		_ = os.RemoveAll(dir.dir)
+		delete(w.sessions, session)
+	}
+}
+
+// Sweep removes the files of the sessions that haven't been used for longer than idleTimeout, so that the workspaces
+// of the sessions whose end isn't notified don't accumulate
+func (w *Workspace) Sweep(idleTimeout time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for session, dir := range w.sessions {
+		if time.Since(dir.used) >= idleTimeout {
+			_ = os.RemoveAll(dir.dir)
+			delete(w.sessions, session)
+		}
+	}
+}
+
+// SetIdleTimeout restarts the periodic removal of the files of the sessions idle for longer than idleTimeout,
+// the removal is stopped if idleTimeout is 0
+func (w *Workspace) SetIdleTimeout(idleTimeout time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopSweeper != nil {
+		w.stopSweeper()
+		w.stopSweeper = nil
+	}
+	if idleTimeout <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w.stopSweeper = cancel
+	go func() {
+		ticker := time.NewTicker(min(idleTimeout, time.Minute))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.Sweep(idleTimeout)
+			}
+		}
+	}()
+}
+
+// Close stops the removal of the idle sessions and removes the files of all the sessions
+func (w *Workspace) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopSweeper != nil {
+		w.stopSweeper()
+		w.stopSweeper = nil
+	}
+	if w.root != "" {
+		_ = os.RemoveAll(w.root)
+	}
+	w.root = ""
+	w.sessions = make(map[string]*sessionWorkspace)
+}
+
+// sessionDir returns the directory of the session, created if create is true, empty if it doesn't exist.
+// The session is marked as used.
+func (w *Workspace) sessionDir(session string, create bool) (string, error) {
+	if dir, ok := w.sessions[session]; ok {
+		dir.used = time.Now()
+		return dir.dir, nil
+	}
+	if !create {
+		return "", nil
+	}
+	if w.root == "" {
+		root, err := os.MkdirTemp(w.parent, "kubernetes-mcp-server-workspace-")
+		if err != nil {
+			return "", fmt.Errorf("failed to create the workspace: %w", err)
+		}
+		w.root = root
+	}
+	// the session IDs are not used as directory names, they are chosen by the transports
+	dir, err := os.MkdirTemp(w.root, "session-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create the workspace of the session: %w", err)
+	}
+	w.sessions[session] = &sessionWorkspace{dir: dir, used: time.Now()}
+	return dir, nil
+}
+
+// resolve returns the file name of the path in the directory, the path must be relative and remain in the directory
+func resolve(dir, path string) (string, error) {
+	if path == "" || !filepath.IsLocal(filepath.FromSlash(path)) {
+		return "", fmt.Errorf("invalid path %q, a relative path without .. is expected (e.g. values/production.yaml)", path)
+	}
+	return filepath.Join(dir, filepath.FromSlash(path)), nil
+}
+
+func usage(dir string) (int64, error) {
+	var ret int64
+	err := filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		info, err := entry.Info()
+		if err == nil {
+			ret += info.Size()
+		}
+		return err
+	})
+	return ret, err
+}
+
+type contextKey struct{}
+
+// sessionFiles are the files of the workspace of a session
+type sessionFiles struct {
+	workspace *Workspace
+	session   string
+}
+
+// NewContext returns a context carrying the workspace of the session, whose files are read by Resolve
+func NewContext(ctx context.Context, workspace *Workspace, session string) context.Context {
+	return context.WithValue(ctx, contextKey{}, sessionFiles{workspace: workspace, session: session})
+}
+
+// IsReference returns true if the value references a file of the workspace (workspace:<path>)
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Resolve returns the content of the file of the workspace of the session of the context referenced by the
+// workspace:<path> reference
+func Resolve(ctx context.Context, reference string) ([]byte, error) {
+	files, ok := ctx.Value(contextKey{}).(sessionFiles)
+	if !ok {
+		return nil, fmt.Errorf("failed to read %s, the workspace is not enabled", reference)
+	}
+	return files.workspace.Read(files.session, strings.TrimPrefix(reference, Prefix))
+}
@@ -0,0 +1,134 @@
+package workspace
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type WorkspaceSuite struct {
+	suite.Suite
+	workspace *Workspace
+}
+
+func (s *WorkspaceSuite) SetupTest() {
+	s.workspace = New(s.T().TempDir())
+}
+
+func (s *WorkspaceSuite) TearDownTest() {
+	s.workspace.Close()
+}
+
+func (s *WorkspaceSuite) TestWriteRead() {
+	file, err := s.workspace.Write("a", "values/production.yaml", []byte("replicas: 3\n"), 1024)
+	s.Require().NoError(err)
+	s.Run("returns the written file", func() {
+		s.Equal("values/production.yaml", file.Path)
+		s.Equal(int64(12), file.Size)
+	})
+	s.Run("reads the file of the session", func() {
+		content, err := s.workspace.Read("a", "values/production.yaml")
+		s.Require().NoError(err)
+		s.Equal("replicas: 3\n", string(content))
+	})
+	s.Run("isolates the sessions", func() {
+		_, err := s.workspace.Read("b", "values/production.yaml")
+		s.EqualError(err, "file values/production.yaml not found in the workspace")
+	})
+	s.Run("lists the files of the session", func() {
+		_, err := s.workspace.Write("a", "app.yaml", []byte("kind: Pod\n"), 1024)
+		s.Require().NoError(err)
+		files, err := s.workspace.List("a")
+		s.Require().NoError(err)
+		s.Require().Len(files, 2)
+		s.Equal("app.yaml", files[0].Path)
+		s.Equal("values/production.yaml", files[1].Path)
+	})
+}
+
+func (s *WorkspaceSuite) TestWriteSizeLimit() {
+	_, err := s.workspace.Write("a", "a.yaml", []byte("0123456789"), 16)
+	s.Require().NoError(err)
+	s.Run("rejects the writes exceeding the limit", func() {
+		_, err := s.workspace.Write("a", "b.yaml", []byte("0123456789"), 16)
+		s.EqualError(err, "writing b.yaml (10 bytes) would exceed the workspace size limit (10 of 16 bytes used)")
+	})
+	s.Run("replaced files don't count", func() {
+		_, err := s.workspace.Write("a", "a.yaml", []byte("0123456789abcdef"), 16)
+		s.NoError(err)
+	})
+}
+
+func (s *WorkspaceSuite) TestInvalidPath() {
+	for _, path := range []string{"", "../escape.yaml", "/etc/passwd", "values/../../escape.yaml"} {
+		s.Run(path, func() {
+			_, err := s.workspace.Write("a", path, []byte("x"), 16)
+			s.ErrorContains(err, "invalid path")
+		})
+	}
+}
+
+func (s *WorkspaceSuite) TestEndSession() {
+	_, err := s.workspace.Write("a", "a.yaml", []byte("x"), 16)
+	s.Require().NoError(err)
+	dir := s.workspace.sessions["a"].dir
+	s.workspace.EndSession("a")
+	_, err = os.Stat(dir)
+	s.True(os.IsNotExist(err), "the directory of the session must be removed")
+	files, err := s.workspace.List("a")
+	s.NoError(err)
+	s.Empty(files)
+}
+
+func (s *WorkspaceSuite) TestSweep() {
+	_, err := s.workspace.Write("idle", "a.yaml", []byte("x"), 16)
+	s.Require().NoError(err)
+	_, err = s.workspace.Write("active", "a.yaml", []byte("x"), 16)
+	s.Require().NoError(err)
+	idleDir := s.workspace.sessions["idle"].dir
+	s.workspace.sessions["idle"].used = time.Now().Add(-2 * time.Hour)
+	s.workspace.Sweep(time.Hour)
+	s.Run("removes the files of the idle sessions", func() {
+		_, err := os.Stat(idleDir)
+		s.True(os.IsNotExist(err), "the directory of the idle session must be removed")
+		files, err := s.workspace.List("idle")
+		s.NoError(err)
+		s.Empty(files)
+	})
+	s.Run("keeps the files of the active sessions", func() {
+		files, err := s.workspace.List("active")
+		s.NoError(err)
+		s.Len(files, 1)
+	})
+}
+
+func (s *WorkspaceSuite) TestSetIdleTimeout() {
+	_, err := s.workspace.Write("a", "a.yaml", []byte("x"), 16)
+	s.Require().NoError(err)
+	dir := s.workspace.sessions["a"].dir
+	s.workspace.SetIdleTimeout(10 * time.Millisecond)
+	s.Eventually(func() bool {
+		_, err := os.Stat(dir)
+		return os.IsNotExist(err)
+	}, time.Second, 5*time.Millisecond, "the files of the idle session must be removed periodically")
+}
+
+func (s *WorkspaceSuite) TestResolve() {
+	_, err := s.workspace.Write("a", "app.yaml", []byte("kind: Pod\n"), 1024)
+	s.Require().NoError(err)
+	s.Run("reads the referenced file of the session of the context", func() {
+		content, err := Resolve(NewContext(s.T().Context(), s.workspace, "a"), "workspace:app.yaml")
+		s.Require().NoError(err)
+		s.Equal("kind: Pod\n", string(content))
+	})
+	s.Run("fails without workspace", func() {
+		_, err := Resolve(s.T().Context(), "workspace:app.yaml")
+		s.EqualError(err, "failed to read workspace:app.yaml, the workspace is not enabled")
+	})
+}
+
+func TestWorkspace(t *testing.T) {
+	suite.Run(t, new(WorkspaceSuite))
+}